@@ -0,0 +1,126 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"log"
+	"os"
+
+	"github.com/billiford/go-clouddriver/pkg/backup"
+	"github.com/billiford/go-clouddriver/pkg/sql"
+)
+
+const usage = `Backs up and restores clouddriver's providers and permissions.
+
+Usage:
+  clouddriver-backup backup -f FILE
+  clouddriver-backup restore -f FILE [-overwrite]
+
+The database is configured with the same environment variables as clouddriver
+(DB_HOST, DB_NAME, DB_PASS and DB_USER). Backups are encrypted with the base64
+encoded 32 byte key in BACKUP_ENCRYPTION_KEY, which can be generated with
+'openssl rand -base64 32'.
+`
+
+func main() {
+	if len(os.Args) < 2 {
+		fmt.Fprint(os.Stderr, usage)
+		os.Exit(2)
+	}
+
+	fs := flag.NewFlagSet(os.Args[1], flag.ExitOnError)
+	file := fs.String("f", "", "backup file to write or read")
+	overwrite := fs.Bool("overwrite", false, "replace providers that already exist when restoring")
+	fs.Usage = func() { fmt.Fprint(os.Stderr, usage) }
+	fs.Parse(os.Args[2:])
+
+	if *file == "" {
+		fs.Usage()
+		os.Exit(2)
+	}
+
+	key, err := backup.ParseKey(os.Getenv("BACKUP_ENCRYPTION_KEY"))
+	if err != nil {
+		log.Fatal("error reading BACKUP_ENCRYPTION_KEY: ", err.Error())
+	}
+
+	sqlConfig := sql.Config{
+		User:     os.Getenv("DB_USER"),
+		Password: os.Getenv("DB_PASS"),
+		Host:     os.Getenv("DB_HOST"),
+		Name:     os.Getenv("DB_NAME"),
+	}
+
+	driver, connection := sql.Connection(sqlConfig)
+
+	// A DSN such as sqlite::memory: overrides the DB_* variables.
+	if dsn := os.Getenv("DB_DSN"); dsn != "" {
+		driver, connection, err = sql.ParseDSN(dsn)
+		if err != nil {
+			log.Fatal(err.Error())
+		}
+	}
+
+	db, err := sql.Connect(driver, connection)
+	if err != nil {
+		log.Fatal(err.Error())
+	}
+	defer db.Close()
+
+	sc := sql.NewClient(db)
+
+	switch os.Args[1] {
+	case "backup":
+		err = runBackup(sc, *file, key)
+	case "restore":
+		err = runRestore(sc, *file, key, *overwrite)
+	default:
+		fs.Usage()
+		os.Exit(2)
+	}
+
+	if err != nil {
+		log.Fatal(err.Error())
+	}
+}
+
+func runBackup(sc sql.Client, file string, key []byte) error {
+	a, err := backup.Dump(sc)
+	if err != nil {
+		return err
+	}
+
+	f, err := os.OpenFile(file, os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0600)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	err = backup.Write(f, a, key)
+	if err != nil {
+		return err
+	}
+
+	log.Printf("backed up %d providers to %s\n", len(a.Providers), file)
+
+	return f.Sync()
+}
+
+func runRestore(sc sql.Client, file string, key []byte, overwrite bool) error {
+	f, err := os.Open(file)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	a, err := backup.Read(f, key)
+	if err != nil {
+		return err
+	}
+
+	restored, err := backup.Restore(sc, a, overwrite)
+	log.Printf("restored %d of %d providers from backup created at %s\n",
+		len(restored), len(a.Providers), a.CreatedAt)
+
+	return err
+}
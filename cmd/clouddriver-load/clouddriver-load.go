@@ -0,0 +1,178 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"log"
+	"net/http"
+	"os"
+	"os/signal"
+	"sort"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/billiford/go-clouddriver/pkg/client"
+	"github.com/billiford/go-clouddriver/pkg/kubernetes"
+	"github.com/billiford/go-clouddriver/pkg/loadtest"
+)
+
+const usage = `Generates the load of Gate pollers and pipelines deploying against
+clouddriver and reports the latency of its requests.
+
+Usage:
+  clouddriver-load [flags]
+
+By default clouddriver is run in this process, with a SQLite database and
+-fake-accounts fake accounts, so runs are reproducible on any machine. Set
+-url to load a running clouddriver instead, deploying to the fake accounts
+in -accounts, such as accounts created with clouddriverctl accounts add.
+CLOUDDRIVER_USER and CLOUDDRIVER_SHARED_SECRET are sent as they are by
+clouddriverctl.
+
+With -history, the report is compared with the last report in the file of
+a load test with the same flags, and appended to it. The exit status is 1
+if the mean or p99 latency of an operation rose by more than -tolerance, or
+its error rate rose by more than a percentage point.
+
+Flags:
+`
+
+func main() {
+	fs := flag.NewFlagSet("clouddriver-load", flag.ExitOnError)
+	url := fs.String("url", "", "URL of a running clouddriver to load, instead of one in this process")
+	accounts := fs.String("accounts", "", "comma-separated accounts to deploy to, with -url")
+	fakeAccounts := fs.Int("fake-accounts", 10, "fake accounts of the clouddriver in this process")
+	fakeLatency := fs.String("fake-latency", "", "latency of the clusters of the fake accounts, such as 50ms")
+	fakeFailureRate := fs.Float64("fake-failure-rate", 0, "fraction of requests to fake clusters that fail")
+	pollers := fs.Int("pollers", 4, "Gate instances polling /credentials?expand=true")
+	pollInterval := fs.Duration("poll-interval", 30*time.Second, "how often each Gate instance polls")
+	deployers := fs.Int("deployers", 4, "pipelines deploying at once")
+	duration := fs.Duration("duration", time.Minute, "how long to generate load for")
+	history := fs.String("history", "", "file of earlier reports to compare with and append to")
+	tolerance := fs.Float64("tolerance", 0.2, "fraction latency may rise by before it is a regression")
+	fs.Usage = func() {
+		fmt.Fprint(os.Stderr, usage)
+		fs.PrintDefaults()
+	}
+	fs.Parse(os.Args[1:])
+
+	c := loadtest.Config{
+		Pollers:      *pollers,
+		PollInterval: *pollInterval,
+		Deployers:    *deployers,
+		Duration:     *duration,
+	}
+
+	cc := client.Config{
+		URL:          *url,
+		User:         os.Getenv("CLOUDDRIVER_USER"),
+		SharedSecret: os.Getenv("CLOUDDRIVER_SHARED_SECRET"),
+		// Latencies are measured per request, so failed requests are
+		// recorded instead of retried.
+		MaxRetries: -1,
+		HTTPClient: &http.Client{
+			Transport: &http.Transport{
+				MaxIdleConnsPerHost: *pollers + *deployers,
+			},
+		},
+	}
+
+	regressions, err := run(c, cc, *accounts, *fakeAccounts, kubernetes.ProviderFake{
+		Latency:     *fakeLatency,
+		FailureRate: *fakeFailureRate,
+	}, *history, *tolerance)
+	if err != nil {
+		log.Fatal(err.Error())
+	}
+
+	for _, regression := range regressions {
+		fmt.Fprintln(os.Stderr, "regression:", regression)
+	}
+
+	if len(regressions) > 0 {
+		os.Exit(1)
+	}
+}
+
+// run runs the load test c, against the clouddriver in cc or against one
+// started in this process if it has no URL, and returns its regressions
+// from the last comparable report in history.
+func run(c loadtest.Config, cc client.Config, accounts string, fakeAccounts int,
+	fake kubernetes.ProviderFake, history string, tolerance float64) ([]string, error) {
+	if cc.URL == "" {
+		s, err := loadtest.NewServer(fakeAccounts, fake)
+		if err != nil {
+			return nil, fmt.Errorf("error starting clouddriver: %w", err)
+		}
+		defer s.Close()
+
+		cc.URL = s.URL
+		c.Accounts = s.Accounts
+	} else if accounts != "" {
+		c.Accounts = strings.Split(accounts, ",")
+	}
+
+	// Stop generating load on an interrupt, still reporting what was measured.
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	interrupt := make(chan os.Signal, 1)
+	signal.Notify(interrupt, os.Interrupt)
+
+	go func() {
+		<-interrupt
+		cancel()
+	}()
+
+	log.Printf("generating load of %d pollers and %d deployers for %s\n", c.Pollers, c.Deployers, c.Duration)
+
+	r, err := loadtest.Run(ctx, client.New(cc), c)
+	if err != nil {
+		return nil, err
+	}
+
+	printReport(r)
+
+	if history == "" {
+		return nil, nil
+	}
+
+	reports, err := loadtest.ReadHistory(history)
+	if err != nil {
+		return nil, err
+	}
+
+	err = loadtest.AppendHistory(history, r)
+	if err != nil {
+		return nil, err
+	}
+
+	baseline, ok := loadtest.LastComparable(reports, r.Config)
+	if !ok {
+		return nil, nil
+	}
+
+	return loadtest.Compare(baseline, r, tolerance), nil
+}
+
+func printReport(r loadtest.Report) {
+	operations := []string{}
+	for operation := range r.Operations {
+		operations = append(operations, operation)
+	}
+
+	sort.Strings(operations)
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "OPERATION\tREQUESTS\tERRORS\tPER SEC\tMEAN\tP50\tP90\tP99\tMAX")
+
+	for _, operation := range operations {
+		s := r.Operations[operation]
+		fmt.Fprintf(w, "%s\t%d\t%d\t%.1f\t%.1fms\t%.1fms\t%.1fms\t%.1fms\t%.1fms\n", operation,
+			s.Requests, s.Errors, s.PerSec, s.MeanMs, s.P50Ms, s.P90Ms, s.P99Ms, s.MaxMs)
+	}
+
+	w.Flush()
+}
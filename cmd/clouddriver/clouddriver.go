@@ -1,30 +1,142 @@
 package main
 
 import (
+	"context"
+	"crypto/tls"
 	"log"
+	"net"
+	"net/http"
 	"os"
+	"os/signal"
+	"strconv"
 	"strings"
+	"syscall"
+	"time"
 
 	"github.com/billiford/go-clouddriver/pkg/arcade"
 	"github.com/billiford/go-clouddriver/pkg/artifact"
+	"github.com/billiford/go-clouddriver/pkg/docker"
+	"github.com/billiford/go-clouddriver/pkg/events"
 	"github.com/billiford/go-clouddriver/pkg/fiat"
+	"github.com/billiford/go-clouddriver/pkg/freeze"
+	"github.com/billiford/go-clouddriver/pkg/hook"
+	"github.com/billiford/go-clouddriver/pkg/http/core"
 	kube "github.com/billiford/go-clouddriver/pkg/http/core/kubernetes"
+	"github.com/billiford/go-clouddriver/pkg/janitor"
 	"github.com/billiford/go-clouddriver/pkg/kubernetes"
+	"github.com/billiford/go-clouddriver/pkg/kubernetes/decrypt"
+	"github.com/billiford/go-clouddriver/pkg/kubernetes/lint"
+	"github.com/billiford/go-clouddriver/pkg/kubernetes/schedule"
+	"github.com/billiford/go-clouddriver/pkg/kubernetes/verify"
+	"github.com/billiford/go-clouddriver/pkg/lock"
+	"github.com/billiford/go-clouddriver/pkg/middleware"
+	"github.com/billiford/go-clouddriver/pkg/notify"
+	"github.com/billiford/go-clouddriver/pkg/project"
+	"github.com/billiford/go-clouddriver/pkg/queue"
+	"github.com/billiford/go-clouddriver/pkg/reaper"
+	"github.com/billiford/go-clouddriver/pkg/recorder"
+	"github.com/billiford/go-clouddriver/pkg/routine"
+	"github.com/billiford/go-clouddriver/pkg/rpc"
 	"github.com/billiford/go-clouddriver/pkg/server"
+	"github.com/billiford/go-clouddriver/pkg/snapshot"
 	"github.com/billiford/go-clouddriver/pkg/sql"
+	"github.com/billiford/go-clouddriver/pkg/version"
+	"github.com/billiford/go-clouddriver/pkg/webhook"
 	"github.com/gin-gonic/gin"
 	ginprometheus "github.com/mcuadros/go-gin-prometheus"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials"
+)
+
+const (
+	// Namespaces rarely change, so cache them for a minute.
+	namespaceCacheTTL = time.Minute
+	// Permissions are invalidated when accounts change, the TTL
+	// only bounds how stale other clouddriver instances can be.
+	permissionsCacheTTL = 5 * time.Minute
 )
 
 var (
 	r = gin.New()
+	// Set when the APIs are served over TLS.
+	tlsConfig *tls.Config
+	// Cancelled when clouddriver is asked to stop, so background work stops.
+	background, stopBackground = context.WithCancel(context.Background())
 )
 
+// Background work is given this long to stop before clouddriver exits.
+const backgroundStopTimeout = 10 * time.Second
+
 func main() {
-	r.Run(":7002")
+	go stopOnSignal()
+
+	// Serve the gRPC API alongside the REST API, if configured.
+	if port := os.Getenv("GRPC_PORT"); port != "" {
+		go serveGRPC(":" + port)
+	}
+
+	if tlsConfig == nil {
+		r.Run(":7002")
+		return
+	}
+
+	s := &http.Server{
+		Addr:      ":7002",
+		Handler:   r,
+		TLSConfig: tlsConfig,
+	}
+
+	log.Println("[CLOUDDRIVER] serving over TLS")
+
+	// The certificate is already loaded in the TLS config.
+	if err := s.ListenAndServeTLS("", ""); err != nil {
+		log.Fatal("error serving: ", err.Error())
+	}
+}
+
+// stopOnSignal cancels background work when clouddriver receives SIGINT or
+// SIGTERM and waits for it to stop, then exits.
+func stopOnSignal() {
+	ch := make(chan os.Signal, 1)
+	signal.Notify(ch, syscall.SIGINT, syscall.SIGTERM)
+
+	sig := <-ch
+	log.Println("[CLOUDDRIVER] received", sig.String()+", stopping background work")
+
+	stopBackground()
+
+	if !routine.Wait(backgroundStopTimeout) {
+		log.Println("[CLOUDDRIVER] background work still running after", backgroundStopTimeout.String()+":", routine.Running())
+	}
+
+	os.Exit(0)
+}
+
+// serveGRPC serves the gRPC API on addr, handling each call with the REST API.
+func serveGRPC(addr string) {
+	lis, err := net.Listen("tcp", addr)
+	if err != nil {
+		log.Fatal("error listening for gRPC: ", err.Error())
+	}
+
+	opts := []grpc.ServerOption{}
+	if tlsConfig != nil {
+		opts = append(opts, grpc.Creds(credentials.NewTLS(tlsConfig)))
+	}
+
+	s := grpc.NewServer(opts...)
+	rpc.RegisterClouddriverServer(s, rpc.NewServer(r))
+
+	log.Println("[CLOUDDRIVER] serving gRPC on", addr)
+
+	if err := s.Serve(lis); err != nil {
+		log.Fatal("error serving gRPC: ", err.Error())
+	}
 }
 
 func init() {
+	log.Println("[CLOUDDRIVER] starting clouddriver", version.Get())
+
 	// Setup metrics.
 	p := ginprometheus.NewPrometheus("gin")
 	p.MetricsPath = "/metrics"
@@ -37,7 +149,7 @@ func init() {
 	gin.ForceConsoleColor()
 	// Ignore logging of certain endpoints.
 	r.Use(gin.LoggerWithConfig(gin.LoggerConfig{SkipPaths: []string{"/health"}}))
-	r.Use(gin.Recovery())
+	r.Use(middleware.Recovery())
 
 	sqlConfig := sql.Config{
 		User:     os.Getenv("DB_USER"),
@@ -45,23 +157,160 @@ func init() {
 		Host:     os.Getenv("DB_HOST"),
 		Name:     os.Getenv("DB_NAME"),
 	}
+	sqlConfig.MaxOpenConns, _ = strconv.Atoi(os.Getenv("DB_MAX_OPEN_CONNS"))
+	sqlConfig.MaxIdleConns, _ = strconv.Atoi(os.Getenv("DB_MAX_IDLE_CONNS"))
+	sqlConfig.ConnMaxLifetime, _ = time.ParseDuration(os.Getenv("DB_CONN_MAX_LIFETIME"))
+	sqlConfig.SlowQueryThreshold, _ = time.ParseDuration(os.Getenv("DB_SLOW_QUERY_THRESHOLD"))
+	sqlConfig.PrepareStatements = os.Getenv("DB_PREPARE_STATEMENTS") == "true"
 
-	db, err := sql.Connect(sql.Connection(sqlConfig))
+	driver, connection := sql.Connection(sqlConfig)
+
+	// A DSN such as sqlite::memory: overrides the DB_* variables.
+	if dsn := os.Getenv("DB_DSN"); dsn != "" {
+		var err error
+
+		driver, connection, err = sql.ParseDSN(dsn)
+		if err != nil {
+			log.Fatal(err.Error())
+		}
+	}
+
+	db, err := sql.Connect(driver, connection)
 	if err != nil {
 		log.Fatal(err.Error())
 	}
 
+	sql.Configure(db, sqlConfig)
+
+	sqlClient := sql.NewClient(db)
+	sqlReadOnlyClient := sqlClient
+
+	// Send read-heavy requests to a read replica, if configured.
+	if readHost := os.Getenv("DB_READ_HOST"); readHost != "" {
+		readConfig := sqlConfig
+		readConfig.Host = readHost
+
+		readDB, err := sql.ConnectReadReplica(sql.Connection(readConfig))
+		if err != nil {
+			log.Fatal(err.Error())
+		}
+
+		sql.Configure(readDB, readConfig)
+
+		sqlReadOnlyClient = sql.NewClient(readDB)
+	}
+
+	// Clean up old data, if configured.
+	janitorConfig := janitor.Config{}
+	janitorConfig.Interval, _ = time.ParseDuration(os.Getenv("JANITOR_INTERVAL"))
+	janitorConfig.TaskHistoryRetention, _ = time.ParseDuration(os.Getenv("RETENTION_TASK_HISTORY"))
+	janitorConfig.FailedOperationRetention, _ = time.ParseDuration(os.Getenv("RETENTION_FAILED_OPERATIONS"))
+	janitorConfig.CacheSnapshotRetention, _ = time.ParseDuration(os.Getenv("RETENTION_CACHE_SNAPSHOTS"))
+	janitorConfig.DeleteOrphans = os.Getenv("RETENTION_DELETE_ORPHANS") == "true"
+
+	if janitorConfig.Enabled() {
+		routine.Go(background, "janitor", func(ctx context.Context) {
+			janitor.Run(ctx, sqlClient, janitorConfig)
+		})
+	}
+
 	// Grab our artifact credentials from /opt/spinnaker/artifacts/config.
 	artifactCredentialsController, err := artifact.NewDefaultCredentialsController()
 	if err != nil {
 		log.Println("[CLOUDDRIVER] error setting up artifact credentials controller:", err.Error())
 	}
 
-	sqlClient := sql.NewClient(db)
+	// Grab our docker registry credentials from /opt/spinnaker/docker/config.
+	// Docker registry accounts are listed in /credentials, so fail fast on bad config.
+	dockerCredentialsController, err := docker.NewDefaultCredentialsController()
+	if err != nil {
+		log.Fatal("error setting up docker registry credentials controller: ", err.Error())
+	}
+
+	// Grab our project definitions from /opt/spinnaker/projects/config.
+	projectController, err := project.NewDefaultController()
+	if err != nil {
+		log.Fatal("error reading project config: ", err.Error())
+	}
+
+	// Grab our change freezes from /opt/spinnaker/kubernetes/freezes.json.
+	freezeController, err := freeze.NewDefaultController()
+	if err != nil {
+		log.Fatal("error reading freeze config: ", err.Error())
+	}
+
+	// Grab our Slack and Teams notification sinks from /opt/spinnaker/kubernetes/notifications.json.
+	notifier, err := notify.NewDefaultNotifier()
+	if err != nil {
+		log.Fatal("error reading notification config: ", err.Error())
+	}
+
+	// Grab our operation hooks from /opt/spinnaker/kubernetes/hooks.json.
+	hookRunner, err := hook.NewDefaultRunner()
+	if err != nil {
+		log.Fatal("error reading hook config: ", err.Error())
+	}
+
+	// Grab our per account manifest mutation webhooks from /opt/spinnaker/kubernetes/mutations.json.
+	mutator, err := hook.NewDefaultMutator()
+	if err != nil {
+		log.Fatal("error reading mutation webhook config: ", err.Error())
+	}
+
+	// Grab our per account stability webhooks from /opt/spinnaker/kubernetes/stability.json.
+	stabilityChecker, err := hook.NewDefaultStabilityChecker()
+	if err != nil {
+		log.Fatal("error reading stability webhook config: ", err.Error())
+	}
+
+	// Grab our per kind and account cache intervals from /opt/spinnaker/kubernetes/cache.json.
+	cacheConfig, err := kubernetes.NewDefaultCacheConfig()
+	if err != nil {
+		log.Fatal("error reading kubernetes cache config: ", err.Error())
+	}
+
+	// Grab the default labels and annotations of deployed manifests from /opt/spinnaker/kubernetes/metadata.json.
+	metadataConfig, err := kubernetes.NewDefaultMetadataConfig()
+	if err != nil {
+		log.Fatal("error reading kubernetes metadata config: ", err.Error())
+	}
+
+	// Grab which accounts substitute placeholders in deployed manifests from /opt/spinnaker/kubernetes/templating.json.
+	templateConfig, err := kubernetes.NewDefaultTemplateConfig()
+	if err != nil {
+		log.Fatal("error reading kubernetes templating config: ", err.Error())
+	}
+
+	// Grab the clusters guarded from losing their last healthy server group from /opt/spinnaker/kubernetes/traffic-guards.json.
+	trafficGuardConfig, err := kubernetes.NewDefaultTrafficGuardConfig()
+	if err != nil {
+		log.Fatal("error reading kubernetes traffic guard config: ", err.Error())
+	}
+
 	fiatClient := fiat.NewDefaultClient()
-	kubeController := kubernetes.NewController()
+
+	// Read users and their groups from their forwarded OIDC tokens instead of Fiat, if configured.
+	var groupResolver fiat.GroupResolver
+	if jwksURL := os.Getenv("OIDC_JWKS_URL"); jwksURL != "" {
+		groupResolver = fiat.NewOIDCGroupResolver(fiat.OIDCConfig{
+			JWKSURL:   jwksURL,
+			Header:    os.Getenv("OIDC_TOKEN_HEADER"),
+			Claim:     os.Getenv("OIDC_GROUPS_CLAIM"),
+			UserClaim: os.Getenv("OIDC_USER_CLAIM"),
+			Issuer:    os.Getenv("OIDC_ISSUER"),
+			Audience:  os.Getenv("OIDC_AUDIENCE"),
+		})
+	}
+
+	kubeController := kubernetes.NewControllerWithCacheConfig(cacheConfig)
 	arcadeClient := arcade.NewDefaultClient()
 
+	// Forget the minted tokens, transports and request counts of clusters not used for an hour, or KUBERNETES_IDLE_TIMEOUT.
+	kubeIdleTimeout, _ := time.ParseDuration(os.Getenv("KUBERNETES_IDLE_TIMEOUT"))
+	routine.Go(background, "kubernetes-idle-eviction", func(ctx context.Context) {
+		kubernetes.RunIdleEviction(ctx, kubeController, kubeIdleTimeout)
+	})
+
 	arcadeAPIKey := os.Getenv("ARCADE_API_KEY")
 	if arcadeAPIKey == "" {
 		log.Println("[CLOUDDRIVER] WARNING: ARCADE_API_KEY not set")
@@ -69,21 +318,241 @@ func init() {
 
 	arcadeClient.WithAPIKey(arcadeAPIKey)
 
+	// Rate-limit operations against each account, admitting rollbacks first, if configured.
+	queueConfig := queue.Config{}
+	queueConfig.Rate, _ = strconv.ParseFloat(os.Getenv("OPERATION_QUEUE_RATE"), 64)
+	queueConfig.Burst, _ = strconv.Atoi(os.Getenv("OPERATION_QUEUE_BURST"))
+	queueConfig.MaxWait, _ = time.ParseDuration(os.Getenv("OPERATION_QUEUE_MAX_WAIT"))
+
+	var operationQueue queue.Queue
+	if queueConfig.Enabled() {
+		operationQueue = queue.New(queueConfig)
+	}
+
+	// Lock the clusters operations change until they are done, if configured.
+	// Locks are held in SQL so every instance sees them.
+	var locker lock.Locker
+	if os.Getenv("OPERATION_LOCK_ENABLED") == "true" {
+		lockConfig := lock.Config{Policy: os.Getenv("OPERATION_LOCK_POLICY"), Store: sqlClient}
+		lockConfig.MaxWait, _ = time.ParseDuration(os.Getenv("OPERATION_LOCK_MAX_WAIT"))
+
+		locker, err = lock.New(lockConfig)
+		if err != nil {
+			log.Fatal(err.Error())
+		}
+	}
+
+	// Report or delete orphaned resources deployed by spinnaker, if configured.
+	reaperPolicy, err := reaper.NewPolicy(os.Getenv("REAPER_POLICY"))
+	if err != nil {
+		log.Fatal(err.Error())
+	}
+
+	reaperConfig := reaper.Config{Policy: reaperPolicy, Queue: operationQueue}
+	reaperConfig.Interval, _ = time.ParseDuration(os.Getenv("REAPER_INTERVAL"))
+	reaperConfig.OrphanedApplications = os.Getenv("REAPER_ORPHANED_APPLICATIONS") == "true"
+	reaperConfig.MaxVersionHistory, _ = strconv.Atoi(os.Getenv("REAPER_MAX_VERSION_HISTORY"))
+	reaperConfig.FinishedJobs = os.Getenv("REAPER_FINISHED_JOBS") == "true"
+
+	if kinds := os.Getenv("REAPER_KINDS"); kinds != "" {
+		reaperConfig.Kinds = strings.Split(kinds, ",")
+	}
+
+	if reaperConfig.Enabled() {
+		routine.Go(background, "reaper", func(ctx context.Context) {
+			reaper.Run(ctx, sqlClient, arcadeClient, kubeController, reaperConfig)
+		})
+	}
+
+	// Snapshot cluster and server group reads to serve them as of a past time, if configured.
+	var snapshotter snapshot.Snapshotter
+	if os.Getenv("CACHE_SNAPSHOTS") == "true" {
+		interval, _ := time.ParseDuration(os.Getenv("CACHE_SNAPSHOT_INTERVAL"))
+		snapshotter = snapshot.New(interval)
+	}
+
+	namespaceCache := kubernetes.NewNamespaceCacheWithConfig(namespaceCacheTTL, cacheConfig)
+
+	// Refresh the namespaces of every account every thirty seconds, or NAMESPACE_CACHE_REFRESH_INTERVAL,
+	// so Gate's polling of /credentials?expand=true is served from the cache.
+	namespaceRefreshInterval, _ := time.ParseDuration(os.Getenv("NAMESPACE_CACHE_REFRESH_INTERVAL"))
+	routine.Go(background, "namespace-refresh", func(ctx context.Context) {
+		core.RefreshNamespaces(ctx, sqlClient, arcadeClient, kubeController, namespaceCache, namespaceRefreshInterval)
+	})
+
+	actionHandlerConfig := kube.ActionHandlerConfig{
+		MigrateRemovedAPIVersions: os.Getenv("MIGRATE_REMOVED_API_VERSIONS") == "true",
+		ValidateScheduling:        os.Getenv("VALIDATE_SCHEDULING") == "true",
+		DefaultMetadata:           metadataConfig,
+		Templating:                templateConfig,
+		Mutator:                   mutator,
+		TrafficGuards:             trafficGuardConfig,
+	}
+
+	// Limit the size of manifest artifacts and cache them by checksum.
+	manifestArtifactMaxBytes, _ := strconv.ParseInt(os.Getenv("MANIFEST_ARTIFACT_MAX_BYTES"), 10, 64)
+	manifestArtifactCacheSize, _ := strconv.Atoi(os.Getenv("MANIFEST_ARTIFACT_CACHE_SIZE"))
+	actionHandlerConfig.URLFetcher = artifact.NewURLFetcher(manifestArtifactMaxBytes, manifestArtifactCacheSize)
+
+	// Delete jobs run by runJob after they finish, if configured.
+	actionHandlerConfig.JobTTL, _ = time.ParseDuration(os.Getenv("RUN_JOB_TTL"))
+
+	// Lint manifests before deploying them, if configured.
+	if os.Getenv("LINT_MANIFESTS") == "true" {
+		linter, err := lint.NewLinter(os.Getenv("LINT_FAIL_SEVERITY"))
+		if err != nil {
+			log.Fatal(err.Error())
+		}
+
+		actionHandlerConfig.Linter = linter
+	}
+
+	// Warn about or fail deploys that exceed the schedulable capacity of the cluster, if configured.
+	capacityCheck, err := schedule.NewCapacityCheck(os.Getenv("CAPACITY_CHECK"))
+	if err != nil {
+		log.Fatal(err.Error())
+	}
+
+	actionHandlerConfig.CapacityCheck = capacityCheck
+
+	// Decrypt SOPS encrypted manifests and unseal sealed secrets, if configured.
+	if os.Getenv("DECRYPT_SOPS") == "true" {
+		actionHandlerConfig.Decrypters = append(actionHandlerConfig.Decrypters, decrypt.NewSOPSDecrypter("sops"))
+	}
+
+	if key := os.Getenv("SEALED_SECRETS_PRIVATE_KEY_PATH"); key != "" {
+		actionHandlerConfig.Decrypters = append(actionHandlerConfig.Decrypters,
+			decrypt.NewSealedSecretsDecrypter("kubeseal", key))
+	}
+
+	// Verify the signatures of deployed images per /opt/spinnaker/kubernetes/image-verification.json, if configured.
+	verifyConfig, err := verify.NewDefaultConfig()
+	if err != nil {
+		log.Fatal("error reading image verification config: ", err.Error())
+	}
+
+	if verifyConfig.Enabled() {
+		actionHandlerConfig.Verifier = verify.NewCosignVerifier("cosign", verifyConfig)
+	}
+
+	actionHandler := kube.NewActionHandlerWithConfig(actionHandlerConfig)
+
+	// Consume cluster change events from pub/sub, if configured.
+	if consumer := eventsConsumer(); consumer != nil {
+		routine.Go(background, "events", func(ctx context.Context) {
+			events.Run(ctx, consumer, namespaceCache, os.Getenv("EVENTS_DEFAULT_ACCOUNT"))
+		})
+	}
+
 	c := &server.Config{
 		ArcadeClient:                  arcadeClient,
 		ArtifactCredentialsController: artifactCredentialsController,
+		DockerCredentialsController:   dockerCredentialsController,
 		SQLClient:                     sqlClient,
+		SQLReadOnlyClient:             sqlReadOnlyClient,
 		FiatClient:                    fiatClient,
+		GroupResolver:                 groupResolver,
+		FreezeController:              freezeController,
 		KubeController:                kubeController,
-		KubeActionHandler:             kube.NewActionHandler(),
+		KubeActionHandler:             actionHandler,
+		KubeNamespaceCache:            namespaceCache,
+		KubePermissionsCache:          kubernetes.NewPermissionsCache(permissionsCacheTTL),
+		ProjectController:             projectController,
+		Notifier:                      notifier,
+		HookRunner:                    hookRunner,
+		StabilityChecker:              stabilityChecker,
+		Queue:                         operationQueue,
+		Locker:                        locker,
+		Snapshotter:                   snapshotter,
+		// Watch the rollout of every deploy to measure its time to stable, if configured.
+		WatchRollouts: os.Getenv("DEPLOY_STATS_WATCH_ROLLOUTS") == "true",
 	}
 	if os.Getenv("VERBOSE_REQUEST_LOGGING") == "true" {
 		c.VerboseRequestLogging = true
 	}
 
+	// Serve over TLS and verify client certificates, if configured.
+	tc := server.TLSConfig{
+		CertFile:          os.Getenv("TLS_CERT_FILE"),
+		KeyFile:           os.Getenv("TLS_KEY_FILE"),
+		ClientCAFile:      os.Getenv("TLS_CLIENT_CA_FILE"),
+		RequireClientCert: os.Getenv("TLS_REQUIRE_CLIENT_CERT") == "true",
+	}
+
+	if tc.Enabled() {
+		tlsConfig, err = tc.Config()
+		if err != nil {
+			log.Fatal(err.Error())
+		}
+	}
+
+	// Require callers to send a shared secret, if configured.
+	c.SharedSecret = os.Getenv("SHARED_SECRET")
+
+	// Authenticate cache invalidation webhooks with the secrets of their
+	// accounts in /opt/spinnaker/webhooks/secrets.json.
+	c.WebhookSecrets, err = webhook.NewDefaultSecrets()
+	if err != nil {
+		log.Fatal("error reading webhook secrets: ", err.Error())
+	}
+
+	// Only accept requests from allowed networks, if configured.
+	if ips := os.Getenv("ALLOWED_SOURCE_IPS"); ips != "" {
+		c.AllowedIPs, err = middleware.ParseCIDRs(ips)
+		if err != nil {
+			log.Fatal("error parsing ALLOWED_SOURCE_IPS: ", err.Error())
+		}
+	}
+
+	// Limit request bodies and the rate of requests of each client, if configured.
+	c.MaxRequestBodySize, _ = strconv.ParseInt(os.Getenv("MAX_REQUEST_BODY_SIZE"), 10, 64)
+	c.ClientRateLimit, _ = strconv.ParseFloat(os.Getenv("CLIENT_RATE_LIMIT"), 64)
+	c.ClientRateBurst, _ = strconv.Atoi(os.Getenv("CLIENT_RATE_BURST"))
+
+	// Shed new operations when too many are in flight or memory runs low, if configured.
+	c.MaxInFlightOperations, _ = strconv.Atoi(os.Getenv("MAX_IN_FLIGHT_OPERATIONS"))
+	c.MaxHeapSize, _ = strconv.ParseUint(os.Getenv("MAX_HEAP_SIZE"), 10, 64)
+
+	// Give up on requests hung on a dependency after the request timeout.
+	c.RequestTimeout, _ = time.ParseDuration(os.Getenv("REQUEST_TIMEOUT"))
+
+	// Record requests made on behalf of pipeline executions for debugging.
+	if os.Getenv("RECORD_REQUESTS") == "true" {
+		size, _ := strconv.Atoi(os.Getenv("RECORD_REQUESTS_BUFFER_SIZE"))
+		c.Recorder = recorder.New(size)
+	}
+
 	server.Setup(r, c)
 }
 
+// eventsConsumer returns the pub/sub consumer configured by environment
+// variables, or nil if none is configured.
+func eventsConsumer() events.Consumer {
+	if subscription := os.Getenv("EVENTS_PUBSUB_SUBSCRIPTION"); subscription != "" {
+		consumer, err := events.NewGooglePubSubConsumer(context.Background(), subscription)
+		if err != nil {
+			log.Fatal("error setting up google pub/sub events consumer: ", err.Error())
+		}
+
+		return consumer
+	}
+
+	if queueURL := os.Getenv("EVENTS_SQS_QUEUE_URL"); queueURL != "" {
+		return events.NewSQSConsumer(queueURL, os.Getenv("AWS_REGION"))
+	}
+
+	if restURL := os.Getenv("EVENTS_KAFKA_REST_URL"); restURL != "" {
+		topic := os.Getenv("EVENTS_KAFKA_TOPIC")
+		if topic == "" {
+			log.Fatal("EVENTS_KAFKA_TOPIC must be set to consume events from kafka")
+		}
+
+		return events.NewKafkaConsumer(restURL, topic, os.Getenv("EVENTS_KAFKA_GROUP"))
+	}
+
+	return nil
+}
+
 func reqCntURLLabelMappingFn(c *gin.Context) string {
 	// Setting the url to the path will remove query params, which sometimes have a GUID.
 	url := c.Request.URL.Path
@@ -0,0 +1,450 @@
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"log"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	clouddriver "github.com/billiford/go-clouddriver/pkg"
+	"github.com/billiford/go-clouddriver/pkg/http/core"
+)
+
+const usage = `Operates clouddriver through its admin API.
+
+Usage:
+  clouddriverctl accounts list
+  clouddriverctl accounts validate ACCOUNT
+  clouddriverctl accounts add -f FILE
+  clouddriverctl accounts report [-format json|csv]
+  clouddriverctl cache refresh ACCOUNT
+  clouddriverctl tasks tail [-timeout DURATION] TASK_ID
+  clouddriverctl tasks replay [-f FILE] TASK_ID
+  clouddriverctl permissions probe [-namespace NAMESPACE] ACCOUNT
+
+'accounts add' creates the provider in FILE, in the JSON accepted by
+POST /v1/kubernetes/providers. 'accounts validate' exits non-zero if the
+cluster of the account cannot be reached with its credentials. 'tasks replay'
+runs a failed task again, after applying the JSON patch in FILE if set, and
+prints the new task ID.
+
+clouddriver is reached at CLOUDDRIVER_URL (default http://localhost:7002) as
+the Fiat admin in CLOUDDRIVER_USER. Set CLOUDDRIVER_SHARED_SECRET if clouddriver
+requires a shared secret, CLOUDDRIVER_CA_FILE to trust a private CA and
+CLOUDDRIVER_CERT_FILE and CLOUDDRIVER_KEY_FILE to present a client certificate.
+`
+
+const defaultURL = "http://localhost:7002"
+
+func main() {
+	if len(os.Args) < 3 {
+		fmt.Fprint(os.Stderr, usage)
+		os.Exit(2)
+	}
+
+	command := os.Args[1] + " " + os.Args[2]
+
+	fs := flag.NewFlagSet(command, flag.ExitOnError)
+	file := fs.String("f", "", "provider file to create, or patch file to apply to a replayed task")
+	namespace := fs.String("namespace", "", "namespace to probe, instead of all namespaces")
+	format := fs.String("format", "json", "format of the account report, json or csv")
+	timeout := fs.Duration("timeout", 10*time.Minute, "how long to tail the task for")
+	fs.Usage = func() { fmt.Fprint(os.Stderr, usage) }
+	fs.Parse(os.Args[3:])
+
+	cc, err := newClient()
+	if err != nil {
+		log.Fatal(err.Error())
+	}
+
+	switch command {
+	case "accounts list":
+		err = listAccounts(cc)
+	case "accounts validate":
+		err = validateAccount(cc, arg(fs))
+	case "accounts add":
+		if *file == "" {
+			fs.Usage()
+			os.Exit(2)
+		}
+
+		err = addAccount(cc, *file)
+	case "accounts report":
+		err = reportAccounts(cc, *format)
+	case "cache refresh":
+		err = refreshCache(cc, arg(fs))
+	case "tasks tail":
+		err = tailTask(cc, arg(fs), *timeout)
+	case "tasks replay":
+		err = replayTask(cc, arg(fs), *file)
+	case "permissions probe":
+		err = probePermissions(cc, arg(fs), *namespace)
+	default:
+		fs.Usage()
+		os.Exit(2)
+	}
+
+	if err != nil {
+		log.Fatal(err.Error())
+	}
+}
+
+// arg returns the single argument of a command, exiting with the usage if
+// there is not exactly one.
+func arg(fs *flag.FlagSet) string {
+	if fs.NArg() != 1 {
+		fs.Usage()
+		os.Exit(2)
+	}
+
+	return fs.Arg(0)
+}
+
+type client struct {
+	url    string
+	user   string
+	secret string
+	http   *http.Client
+}
+
+func newClient() (*client, error) {
+	u := os.Getenv("CLOUDDRIVER_URL")
+	if u == "" {
+		u = defaultURL
+	}
+
+	tlsConfig := &tls.Config{MinVersion: tls.VersionTLS12}
+
+	if caFile := os.Getenv("CLOUDDRIVER_CA_FILE"); caFile != "" {
+		b, err := ioutil.ReadFile(caFile)
+		if err != nil {
+			return nil, fmt.Errorf("error reading CLOUDDRIVER_CA_FILE: %w", err)
+		}
+
+		pool := x509.NewCertPool()
+		if !pool.AppendCertsFromPEM(b) {
+			return nil, fmt.Errorf("no certificates found in CLOUDDRIVER_CA_FILE %s", caFile)
+		}
+
+		tlsConfig.RootCAs = pool
+	}
+
+	certFile, keyFile := os.Getenv("CLOUDDRIVER_CERT_FILE"), os.Getenv("CLOUDDRIVER_KEY_FILE")
+	if certFile != "" || keyFile != "" {
+		cert, err := tls.LoadX509KeyPair(certFile, keyFile)
+		if err != nil {
+			return nil, fmt.Errorf("error loading client certificate: %w", err)
+		}
+
+		tlsConfig.Certificates = []tls.Certificate{cert}
+	}
+
+	return &client{
+		url:    strings.TrimSuffix(u, "/"),
+		user:   os.Getenv("CLOUDDRIVER_USER"),
+		secret: os.Getenv("CLOUDDRIVER_SHARED_SECRET"),
+		http: &http.Client{
+			Transport: &http.Transport{
+				Proxy:           http.ProxyFromEnvironment,
+				TLSClientConfig: tlsConfig,
+			},
+		},
+	}, nil
+}
+
+// do sends a request to clouddriver, returning the message of the
+// clouddriver error as an error if the request fails.
+func (cc *client) do(method, path string, body io.Reader) (*http.Response, error) {
+	req, err := http.NewRequest(method, cc.url+path, body)
+	if err != nil {
+		return nil, err
+	}
+
+	req.Header.Set("Content-Type", "application/json")
+
+	if cc.user != "" {
+		req.Header.Set("X-Spinnaker-User", cc.user)
+	}
+
+	if cc.secret != "" {
+		req.Header.Set("X-Spinnaker-Shared-Secret", cc.secret)
+	}
+
+	res, err := cc.http.Do(req)
+	if err != nil {
+		return nil, err
+	}
+
+	if res.StatusCode >= http.StatusBadRequest {
+		defer res.Body.Close()
+
+		// The /v1 endpoints only set the error field.
+		ce := clouddriver.Error{}
+
+		b, _ := ioutil.ReadAll(res.Body)
+		if json.Unmarshal(b, &ce) == nil {
+			if ce.Message == "" {
+				ce.Message = ce.Error
+			}
+
+			if ce.Message != "" {
+				return nil, fmt.Errorf("%s %s: %d %s", method, path, res.StatusCode, ce.Message)
+			}
+		}
+
+		return nil, fmt.Errorf("%s %s: %s", method, path, res.Status)
+	}
+
+	return res, nil
+}
+
+// getJSON gets path and decodes the response into v.
+func (cc *client) getJSON(path string, v interface{}) error {
+	res, err := cc.do(http.MethodGet, path, nil)
+	if err != nil {
+		return err
+	}
+	defer res.Body.Close()
+
+	return json.NewDecoder(res.Body).Decode(v)
+}
+
+func listAccounts(cc *client) error {
+	accounts := []core.AdminAccount{}
+
+	err := cc.getJSON("/admin/accounts", &accounts)
+	if err != nil {
+		return err
+	}
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 8, 2, ' ', 0)
+	fmt.Fprintln(w, "NAME\tHOST\tHEALTHY\tMAINTENANCE\tLATENCY\tERROR")
+
+	for _, a := range accounts {
+		fmt.Fprintf(w, "%s\t%s\t%t\t%t\t%dms\t%s\n",
+			a.Name, a.Host, a.Healthy, a.Maintenance, a.LatencyMillis, a.Error)
+	}
+
+	return w.Flush()
+}
+
+func validateAccount(cc *client, account string) error {
+	accounts := []core.AdminAccount{}
+
+	err := cc.getJSON("/admin/accounts", &accounts)
+	if err != nil {
+		return err
+	}
+
+	for _, a := range accounts {
+		if a.Name != account {
+			continue
+		}
+
+		if !a.Healthy {
+			return fmt.Errorf("account %s is unhealthy: %s", account, a.Error)
+		}
+
+		fmt.Printf("account %s is healthy (%dms)\n", account, a.LatencyMillis)
+
+		return nil
+	}
+
+	return fmt.Errorf("account %s not found", account)
+}
+
+func addAccount(cc *client, file string) error {
+	b, err := ioutil.ReadFile(file)
+	if err != nil {
+		return err
+	}
+
+	if !json.Valid(b) {
+		return fmt.Errorf("%s is not valid JSON", file)
+	}
+
+	res, err := cc.do(http.MethodPost, "/v1/kubernetes/providers", bytes.NewReader(b))
+	if err != nil {
+		return err
+	}
+	defer res.Body.Close()
+
+	provider := struct {
+		Name string `json:"name"`
+	}{}
+
+	err = json.NewDecoder(res.Body).Decode(&provider)
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("created account %s\n", provider.Name)
+
+	return validateAccount(cc, provider.Name)
+}
+
+// reportAccounts writes the account health report to stdout as it is
+// returned by clouddriver.
+func reportAccounts(cc *client, format string) error {
+	res, err := cc.do(http.MethodGet, "/admin/reports/accounts?format="+url.QueryEscape(format), nil)
+	if err != nil {
+		return err
+	}
+	defer res.Body.Close()
+
+	_, err = io.Copy(os.Stdout, res.Body)
+
+	return err
+}
+
+func refreshCache(cc *client, account string) error {
+	res, err := cc.do(http.MethodPost, "/admin/accounts/"+url.PathEscape(account)+"/cache/refresh", nil)
+	if err != nil {
+		return err
+	}
+	res.Body.Close()
+
+	fmt.Printf("refreshed caches of account %s\n", account)
+
+	return nil
+}
+
+var errTaskNotStable = errors.New("task did not become stable")
+
+// tailTask prints the events of a task's stream until its rollout ends,
+// returning an error if it does not end stable.
+func tailTask(cc *client, id string, timeout time.Duration) error {
+	path := fmt.Sprintf("/task/%s/stream?timeout=%s", url.PathEscape(id), url.QueryEscape(timeout.String()))
+
+	res, err := cc.do(http.MethodGet, path, nil)
+	if err != nil {
+		return err
+	}
+	defer res.Body.Close()
+
+	event := ""
+	scanner := bufio.NewScanner(res.Body)
+
+	for scanner.Scan() {
+		line := scanner.Text()
+
+		switch {
+		case strings.HasPrefix(line, "event:"):
+			event = strings.TrimSpace(strings.TrimPrefix(line, "event:"))
+		case strings.HasPrefix(line, "data:"):
+			data := strings.TrimSpace(strings.TrimPrefix(line, "data:"))
+			fmt.Printf("%s %-9s %s\n", time.Now().Format("15:04:05"), event, data)
+
+			if event != "phase" {
+				continue
+			}
+
+			phase := struct {
+				Phase string `json:"phase"`
+			}{}
+
+			_ = json.Unmarshal([]byte(data), &phase)
+
+			switch phase.Phase {
+			case "STABLE":
+				return nil
+			case "FAILED", "TIMED_OUT":
+				return fmt.Errorf("%w: %s", errTaskNotStable, phase.Phase)
+			}
+		}
+	}
+
+	if err := scanner.Err(); err != nil {
+		return err
+	}
+
+	return fmt.Errorf("%w: stream of task %s ended", errTaskNotStable, id)
+}
+
+func replayTask(cc *client, id, file string) error {
+	var body io.Reader
+
+	if file != "" {
+		b, err := ioutil.ReadFile(file)
+		if err != nil {
+			return err
+		}
+
+		body = bytes.NewReader(b)
+	}
+
+	res, err := cc.do(http.MethodPost, "/admin/tasks/"+url.PathEscape(id)+"/replay", body)
+	if err != nil {
+		return err
+	}
+	defer res.Body.Close()
+
+	task := struct {
+		ID string `json:"id"`
+	}{}
+
+	err = json.NewDecoder(res.Body).Decode(&task)
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("replayed task %s as %s\n", id, task.ID)
+
+	return nil
+}
+
+func probePermissions(cc *client, account, namespace string) error {
+	path := "/admin/accounts/" + url.PathEscape(account) + "/permissions"
+	if namespace != "" {
+		path += "?namespace=" + url.QueryEscape(namespace)
+	}
+
+	permissions := []core.AdminPermission{}
+
+	err := cc.getJSON(path, &permissions)
+	if err != nil {
+		return err
+	}
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 8, 2, ' ', 0)
+	fmt.Fprintln(w, "VERB\tRESOURCE\tALLOWED\tERROR")
+
+	denied := 0
+
+	for _, p := range permissions {
+		resource := p.Resource
+		if p.Group != "" {
+			resource += "." + p.Group
+		}
+
+		if !p.Allowed {
+			denied++
+		}
+
+		fmt.Fprintf(w, "%s\t%s\t%t\t%s\n", p.Verb, resource, p.Allowed, p.Error)
+	}
+
+	err = w.Flush()
+	if err != nil {
+		return err
+	}
+
+	if denied > 0 {
+		return fmt.Errorf("account %s is denied %d of %d permissions", account, denied, len(permissions))
+	}
+
+	return nil
+}
@@ -4,7 +4,10 @@ set -o errexit
 
 # Builds the base image including the solver dependencies
 build_and_publish_image(){
-    GOOS=linux GOARCH=amd64 go build cmd/clouddriver/clouddriver.go
+    LDFLAGS="-X github.com/billiford/go-clouddriver/pkg/version.Version=${TAG_VERSION}"
+    LDFLAGS="${LDFLAGS} -X github.com/billiford/go-clouddriver/pkg/version.GitCommit=$(git rev-parse HEAD)"
+    LDFLAGS="${LDFLAGS} -X github.com/billiford/go-clouddriver/pkg/version.BuildDate=$(date -u +%Y-%m-%dT%H:%M:%SZ)"
+    GOOS=linux GOARCH=amd64 go build -ldflags "${LDFLAGS}" cmd/clouddriver/clouddriver.go
     GCR_TAG="billiford/go-clouddriver:${TAG_VERSION}"
     docker build . -f docker/Dockerfile -t ${GCR_TAG}
     docker push ${GCR_TAG}
@@ -0,0 +1,34 @@
+#!/usr/bin/env bash
+# Creates a kind cluster and a service account for clouddriver, then runs
+# the e2e tests against it. Set E2E_KUBE_HOST, E2E_KUBE_CA_DATA and
+# E2E_KUBE_TOKEN to run against an existing control plane instead.
+set -euo pipefail
+
+CLUSTER=${E2E_CLUSTER:-clouddriver-e2e}
+NAMESPACE=${E2E_NAMESPACE:-clouddriver-e2e}
+KEEP_CLUSTER=${E2E_KEEP_CLUSTER:-false}
+
+if [ -z "${E2E_KUBE_HOST:-}" ]; then
+  if ! kind get clusters | grep -qx "$CLUSTER"; then
+    kind create cluster --name "$CLUSTER" --wait 120s
+  fi
+
+  if [ "$KEEP_CLUSTER" != "true" ]; then
+    trap 'kind delete cluster --name "$CLUSTER"' EXIT
+  fi
+
+  CONTEXT="kind-$CLUSTER"
+
+  kubectl --context "$CONTEXT" create namespace "$NAMESPACE" --dry-run=client -o yaml | kubectl --context "$CONTEXT" apply -f -
+  kubectl --context "$CONTEXT" -n "$NAMESPACE" create serviceaccount clouddriver --dry-run=client -o yaml | kubectl --context "$CONTEXT" apply -f -
+  kubectl --context "$CONTEXT" create clusterrolebinding clouddriver-e2e \
+    --clusterrole cluster-admin --serviceaccount "$NAMESPACE:clouddriver" --dry-run=client -o yaml | kubectl --context "$CONTEXT" apply -f -
+
+  export E2E_KUBE_HOST=$(kubectl config view --raw -o jsonpath="{.clusters[?(@.name==\"$CONTEXT\")].cluster.server}")
+  export E2E_KUBE_CA_DATA=$(kubectl config view --raw -o jsonpath="{.clusters[?(@.name==\"$CONTEXT\")].cluster.certificate-authority-data}")
+  export E2E_KUBE_TOKEN=$(kubectl --context "$CONTEXT" -n "$NAMESPACE" create token clouddriver --duration 1h)
+fi
+
+export E2E_NAMESPACE=$NAMESPACE
+
+ginkgo -tags e2e test/e2e
@@ -0,0 +1,27 @@
+package clouddriver
+
+import "github.com/gin-gonic/gin"
+
+const (
+	// APIVersionV1 is the version of the API served under /v1, and at the
+	// unversioned paths Gate and Orca call.
+	APIVersionV1 = "v1"
+	// APIVersionInstanceKey is the key of the version of the API a request
+	// is served by.
+	APIVersionInstanceKey = `APIVersion`
+)
+
+// APIVersions are the versions of the API, which are the first segment of
+// the paths of their routes.
+var APIVersions = []string{APIVersionV1}
+
+// APIVersion returns the version of the API a request is served by, so a
+// handler serving several versions can shape its response by it.
+func APIVersion(c *gin.Context) string {
+	version := c.GetString(APIVersionInstanceKey)
+	if version == "" {
+		return APIVersionV1
+	}
+
+	return version
+}
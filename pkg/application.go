@@ -0,0 +1,41 @@
+package clouddriver
+
+import "time"
+
+// Application is the metadata of a Spinnaker application, stored by the
+// createApplication operation so applications exist before anything is deployed.
+type Application struct {
+	Name        string      `json:"name" gorm:"primary_key"`
+	Email       string      `json:"email,omitempty"`
+	Description string      `json:"description,omitempty" gorm:"size:2048"`
+	Permissions Permissions `json:"permissions" gorm:"-"`
+}
+
+func (Application) TableName() string {
+	return "applications"
+}
+
+// DeletedApplication records an application removed by the deleteApplication
+// operation, so its resources can be told apart from those of applications
+// that were never created.
+type DeletedApplication struct {
+	Name      string `gorm:"primary_key"`
+	CreatedAt time.Time
+}
+
+func (DeletedApplication) TableName() string {
+	return "deleted_applications"
+}
+
+// ApplicationPermission is a group with an authorization, such as READ or
+// WRITE, on an application.
+type ApplicationPermission struct {
+	ID              string `json:"-" gorm:"primary_key"`
+	ApplicationName string `json:"applicationName"`
+	Authorization   string `json:"authorization"`
+	GroupName       string `json:"groupName"`
+}
+
+func (ApplicationPermission) TableName() string {
+	return "application_permissions"
+}
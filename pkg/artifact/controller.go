@@ -34,6 +34,15 @@ const (
 	TypeGithubFile                   Type = "github/file"
 )
 
+// FetchableTypes lists the artifact types that can be fetched
+// from /artifacts/fetch.
+var FetchableTypes = []Type{
+	TypeEmbeddedBase64,
+	TypeGithubFile,
+	TypeHelmChart,
+	TypeHTTPFile,
+}
+
 //go:generate counterfeiter . CredentialsController
 type CredentialsController interface {
 	ListArtifactCredentialsNamesAndTypes() []Credentials
@@ -0,0 +1,131 @@
+package artifact
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"strings"
+)
+
+// Artifact is a reference to the contents of an artifact to fetch.
+type Artifact struct {
+	Type            Type   `json:"type"`
+	Name            string `json:"name"`
+	Version         string `json:"version"`
+	Reference       string `json:"reference"`
+	ArtifactAccount string `json:"artifactAccount"`
+}
+
+// InvalidArtifactError is returned when an artifact cannot be fetched because
+// of the artifact itself, such as an unknown artifact account or a reference
+// that does not belong to the account.
+type InvalidArtifactError struct {
+	Err error
+}
+
+func (e InvalidArtifactError) Error() string {
+	return e.Err.Error()
+}
+
+func (e InvalidArtifactError) Unwrap() error {
+	return e.Err
+}
+
+// UnsupportedTypeError is returned when fetching artifacts of a type is not implemented.
+type UnsupportedTypeError struct {
+	Type Type
+}
+
+func (e UnsupportedTypeError) Error() string {
+	return fmt.Sprintf("getting artifact of type %s not implemented", e.Type)
+}
+
+// Fetch returns the contents of an artifact using the credentials of its artifact account.
+func Fetch(cc CredentialsController, a Artifact) ([]byte, error) {
+	switch a.Type {
+	case TypeHTTPFile:
+		hc, err := cc.HTTPClientForAccountName(a.ArtifactAccount)
+		if err != nil {
+			return nil, InvalidArtifactError{Err: err}
+		}
+
+		resp, err := hc.Get(a.Reference)
+		if err != nil {
+			return nil, err
+		}
+		defer resp.Body.Close()
+
+		return ioutil.ReadAll(resp.Body)
+
+	case TypeHelmChart:
+		hc, err := cc.HelmClientForAccountName(a.ArtifactAccount)
+		if err != nil {
+			return nil, InvalidArtifactError{Err: err}
+		}
+
+		return hc.GetChart(a.Name, a.Version)
+
+	case TypeEmbeddedBase64:
+		// TODO when a base64 encoded helm templated manifest makes its way here, it sometimes starts
+		// with "WARNING":"This chart is deprecated" - we should either handle that here by removing
+		// the prefix, or handle it in the deploy manifest operation.
+		return base64.StdEncoding.DecodeString(a.Reference)
+
+	case TypeGithubFile:
+		return fetchGithubFile(cc, a)
+
+	default:
+		return nil, UnsupportedTypeError{Type: a.Type}
+	}
+}
+
+func fetchGithubFile(cc CredentialsController, a Artifact) ([]byte, error) {
+	gc, err := cc.GitClientForAccountName(a.ArtifactAccount)
+	if err != nil {
+		return nil, InvalidArtifactError{Err: err}
+	}
+
+	if !strings.HasPrefix(a.Reference, gc.BaseURL.String()) {
+		return nil, InvalidArtifactError{Err: fmt.Errorf("content URL %s should have base URL %s",
+			a.Reference, gc.BaseURL.String())}
+	}
+
+	urlStr := strings.TrimPrefix(a.Reference, gc.BaseURL.String())
+
+	req, err := gc.NewRequest(http.MethodGet, urlStr, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	branch := "master"
+	if a.Version != "" {
+		branch = a.Version
+	}
+
+	q := req.URL.Query()
+	q.Set("ref", branch)
+	req.URL.RawQuery = q.Encode()
+
+	var buf bytes.Buffer
+
+	_, err = gc.Do(context.TODO(), req, &buf)
+	if err != nil {
+		return nil, err
+	}
+
+	var response struct {
+		Content  string `json:"content"`
+		Encoding string `json:"encoding"`
+	}
+	json.Unmarshal(buf.Bytes(), &response)
+
+	if strings.EqualFold(response.Encoding, "base64") {
+		return base64.StdEncoding.DecodeString(response.Content)
+	}
+
+	return []byte(response.Content), nil
+}
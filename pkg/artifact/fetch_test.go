@@ -0,0 +1,75 @@
+package artifact_test
+
+import (
+	"errors"
+
+	. "github.com/billiford/go-clouddriver/pkg/artifact"
+	"github.com/billiford/go-clouddriver/pkg/artifact/artifactfakes"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Fetch", func() {
+	var (
+		fakeCredentialsController *artifactfakes.FakeCredentialsController
+		a                         Artifact
+		b                         []byte
+		err                       error
+	)
+
+	BeforeEach(func() {
+		fakeCredentialsController = &artifactfakes.FakeCredentialsController{}
+		a = Artifact{
+			Type:      TypeEmbeddedBase64,
+			Name:      "config.yaml",
+			Reference: "a2V5OiB2YWx1ZQ==",
+		}
+	})
+
+	JustBeforeEach(func() {
+		b, err = Fetch(fakeCredentialsController, a)
+	})
+
+	When("the type is not supported", func() {
+		BeforeEach(func() {
+			a.Type = "unknown/type"
+		})
+
+		It("returns an unsupported type error", func() {
+			Expect(err).ToNot(BeNil())
+			Expect(errors.As(err, &UnsupportedTypeError{})).To(BeTrue())
+			Expect(err.Error()).To(Equal("getting artifact of type unknown/type not implemented"))
+		})
+	})
+
+	When("the artifact account does not exist", func() {
+		BeforeEach(func() {
+			a.Type = TypeHTTPFile
+			fakeCredentialsController.HTTPClientForAccountNameReturns(nil, errors.New("error getting http client"))
+		})
+
+		It("returns an invalid artifact error", func() {
+			Expect(err).ToNot(BeNil())
+			Expect(errors.As(err, &InvalidArtifactError{})).To(BeTrue())
+			Expect(err.Error()).To(Equal("error getting http client"))
+		})
+	})
+
+	When("the reference is not base64 encoded", func() {
+		BeforeEach(func() {
+			a.Reference = "{}"
+		})
+
+		It("returns an error", func() {
+			Expect(err).ToNot(BeNil())
+			Expect(errors.As(err, &InvalidArtifactError{})).To(BeFalse())
+		})
+	})
+
+	When("it succeeds", func() {
+		It("returns the contents", func() {
+			Expect(err).To(BeNil())
+			Expect(string(b)).To(Equal("key: value"))
+		})
+	})
+})
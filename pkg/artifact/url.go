@@ -0,0 +1,149 @@
+package artifact
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+)
+
+const (
+	// DefaultMaxURLBytes is the size limit of the contents of URL artifacts.
+	DefaultMaxURLBytes int64 = 10 << 20
+	// DefaultURLCacheSize is how many contents of URL artifacts are cached.
+	DefaultURLCacheSize = 100
+)
+
+// URLFetcher fetches the contents of http/file artifacts, such as GitHub raw
+// links, failing if they are larger than a limit or do not have the SHA256
+// checksum they are expected to. Contents with an expected checksum are
+// cached by it, as they cannot change.
+type URLFetcher struct {
+	maxBytes  int64
+	cacheSize int
+
+	mux   sync.Mutex
+	cache map[string][]byte
+	// Checksums of the cache, oldest first.
+	order []string
+}
+
+// NewURLFetcher returns a URLFetcher of contents up to maxBytes, caching the
+// contents of up to cacheSize checksums. Zero values use the defaults and a
+// negative cacheSize disables the cache.
+func NewURLFetcher(maxBytes int64, cacheSize int) *URLFetcher {
+	if maxBytes <= 0 {
+		maxBytes = DefaultMaxURLBytes
+	}
+
+	if cacheSize == 0 {
+		cacheSize = DefaultURLCacheSize
+	}
+
+	return &URLFetcher{
+		maxBytes:  maxBytes,
+		cacheSize: cacheSize,
+		cache:     map[string][]byte{},
+	}
+}
+
+// Fetch returns the contents of an http/file artifact using the credentials
+// of its artifact account. If checksum is set, the hex SHA256 of the
+// contents, optionally prefixed with "sha256:", must equal it.
+func (f *URLFetcher) Fetch(cc CredentialsController, a Artifact, checksum string) ([]byte, error) {
+	if a.Type != TypeHTTPFile {
+		return nil, UnsupportedTypeError{Type: a.Type}
+	}
+
+	checksum = strings.ToLower(strings.TrimPrefix(strings.TrimSpace(checksum), "sha256:"))
+	if checksum != "" {
+		if _, err := hex.DecodeString(checksum); err != nil || len(checksum) != sha256.Size*2 {
+			return nil, InvalidArtifactError{Err: fmt.Errorf("checksum %s is not a SHA256 checksum", checksum)}
+		}
+
+		if b, ok := f.cached(checksum); ok {
+			return b, nil
+		}
+	}
+
+	u, err := url.Parse(a.Reference)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") {
+		return nil, InvalidArtifactError{Err: fmt.Errorf("reference %s is not an HTTP(S) URL", a.Reference)}
+	}
+
+	hc, err := cc.HTTPClientForAccountName(a.ArtifactAccount)
+	if err != nil {
+		return nil, InvalidArtifactError{Err: err}
+	}
+
+	resp, err := hc.Get(a.Reference)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < http.StatusOK || resp.StatusCode > 299 {
+		return nil, fmt.Errorf("error fetching %s: %s", a.Reference, resp.Status)
+	}
+
+	// Read one more byte than the limit to know if the contents exceed it.
+	b, err := ioutil.ReadAll(io.LimitReader(resp.Body, f.maxBytes+1))
+	if err != nil {
+		return nil, err
+	}
+
+	if int64(len(b)) > f.maxBytes {
+		return nil, InvalidArtifactError{Err: fmt.Errorf("contents of %s are larger than %d bytes", a.Reference, f.maxBytes)}
+	}
+
+	if checksum == "" {
+		return b, nil
+	}
+
+	sum := sha256.Sum256(b)
+	if actual := hex.EncodeToString(sum[:]); actual != checksum {
+		return nil, InvalidArtifactError{Err: fmt.Errorf("contents of %s have SHA256 checksum %s, expected %s",
+			a.Reference, actual, checksum)}
+	}
+
+	f.store(checksum, b)
+
+	return b, nil
+}
+
+func (f *URLFetcher) cached(checksum string) ([]byte, bool) {
+	f.mux.Lock()
+	defer f.mux.Unlock()
+
+	b, ok := f.cache[checksum]
+
+	return b, ok
+}
+
+// store caches the contents of a checksum, forgetting the oldest contents
+// if the cache is full.
+func (f *URLFetcher) store(checksum string, b []byte) {
+	if f.cacheSize < 0 {
+		return
+	}
+
+	f.mux.Lock()
+	defer f.mux.Unlock()
+
+	if _, ok := f.cache[checksum]; ok {
+		return
+	}
+
+	for len(f.order) >= f.cacheSize {
+		delete(f.cache, f.order[0])
+		f.order = f.order[1:]
+	}
+
+	f.cache[checksum] = b
+	f.order = append(f.order, checksum)
+}
@@ -0,0 +1,139 @@
+package artifact_test
+
+import (
+	"errors"
+	"net/http"
+
+	. "github.com/billiford/go-clouddriver/pkg/artifact"
+	"github.com/billiford/go-clouddriver/pkg/artifact/artifactfakes"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"github.com/onsi/gomega/ghttp"
+)
+
+var _ = Describe("URLFetcher", func() {
+	const (
+		// SHA256 of "key: value".
+		checksum = "sha256:b701870861d6ff0565b7078ee799ae7362323298a814d7af4d2dce6cb8d8b674"
+	)
+
+	var (
+		fakeServer                *ghttp.Server
+		fakeCredentialsController *artifactfakes.FakeCredentialsController
+		f                         *URLFetcher
+		a                         Artifact
+		sum                       string
+		b                         []byte
+		err                       error
+	)
+
+	BeforeEach(func() {
+		fakeServer = ghttp.NewServer()
+		fakeServer.RouteToHandler(http.MethodGet, "/config.yaml", ghttp.RespondWith(http.StatusOK, "key: value"))
+		fakeCredentialsController = &artifactfakes.FakeCredentialsController{}
+		fakeCredentialsController.HTTPClientForAccountNameReturns(http.DefaultClient, nil)
+		f = NewURLFetcher(0, 0)
+		a = Artifact{
+			Type:      TypeHTTPFile,
+			Reference: fakeServer.URL() + "/config.yaml",
+		}
+		sum = ""
+	})
+
+	AfterEach(func() {
+		fakeServer.Close()
+	})
+
+	JustBeforeEach(func() {
+		b, err = f.Fetch(fakeCredentialsController, a, sum)
+	})
+
+	When("the artifact is not an http/file", func() {
+		BeforeEach(func() {
+			a.Type = TypeEmbeddedBase64
+		})
+
+		It("returns an unsupported type error", func() {
+			Expect(errors.As(err, &UnsupportedTypeError{})).To(BeTrue())
+		})
+	})
+
+	When("the reference is not an HTTP(S) URL", func() {
+		BeforeEach(func() {
+			a.Reference = "file:///etc/passwd"
+		})
+
+		It("returns an invalid artifact error", func() {
+			Expect(errors.As(err, &InvalidArtifactError{})).To(BeTrue())
+			Expect(err.Error()).To(Equal("reference file:///etc/passwd is not an HTTP(S) URL"))
+			Expect(fakeServer.ReceivedRequests()).To(BeEmpty())
+		})
+	})
+
+	When("the server responds with an error", func() {
+		BeforeEach(func() {
+			fakeServer.RouteToHandler(http.MethodGet, "/config.yaml", ghttp.RespondWith(http.StatusNotFound, nil))
+		})
+
+		It("returns an error", func() {
+			Expect(err).ToNot(BeNil())
+			Expect(err.Error()).To(Equal("error fetching " + a.Reference + ": 404 Not Found"))
+		})
+	})
+
+	When("the contents are larger than the limit", func() {
+		BeforeEach(func() {
+			f = NewURLFetcher(4, 0)
+		})
+
+		It("returns an invalid artifact error", func() {
+			Expect(errors.As(err, &InvalidArtifactError{})).To(BeTrue())
+			Expect(err.Error()).To(Equal("contents of " + a.Reference + " are larger than 4 bytes"))
+		})
+	})
+
+	When("the checksum is not a SHA256 checksum", func() {
+		BeforeEach(func() {
+			sum = "abc"
+		})
+
+		It("returns an invalid artifact error", func() {
+			Expect(errors.As(err, &InvalidArtifactError{})).To(BeTrue())
+			Expect(err.Error()).To(Equal("checksum abc is not a SHA256 checksum"))
+		})
+	})
+
+	When("the checksum does not match", func() {
+		BeforeEach(func() {
+			sum = "B701870861D6FF0565B7078EE799AE7362323298A814D7AF4D2DCE6CB8D8B675"
+		})
+
+		It("returns an invalid artifact error", func() {
+			Expect(errors.As(err, &InvalidArtifactError{})).To(BeTrue())
+			Expect(err.Error()).To(Equal("contents of " + a.Reference + " have SHA256 checksum " +
+				"b701870861d6ff0565b7078ee799ae7362323298a814d7af4d2dce6cb8d8b674, expected " +
+				"b701870861d6ff0565b7078ee799ae7362323298a814d7af4d2dce6cb8d8b675"))
+		})
+	})
+
+	When("the checksum matches", func() {
+		BeforeEach(func() {
+			sum = checksum
+		})
+
+		It("caches the contents by checksum", func() {
+			Expect(err).To(BeNil())
+			Expect(string(b)).To(Equal("key: value"))
+
+			b, err = f.Fetch(fakeCredentialsController, a, sum)
+			Expect(err).To(BeNil())
+			Expect(string(b)).To(Equal("key: value"))
+			Expect(fakeServer.ReceivedRequests()).To(HaveLen(1))
+		})
+	})
+
+	It("returns the contents", func() {
+		Expect(err).To(BeNil())
+		Expect(string(b)).To(Equal("key: value"))
+	})
+})
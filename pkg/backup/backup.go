@@ -0,0 +1,269 @@
+package backup
+
+import (
+	"bytes"
+	"compress/gzip"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"time"
+
+	clouddriver "github.com/billiford/go-clouddriver/pkg"
+	"github.com/billiford/go-clouddriver/pkg/kubernetes"
+	"github.com/billiford/go-clouddriver/pkg/sql"
+	"github.com/google/uuid"
+)
+
+const (
+	// SchemaVersion is the version of the archive format. Bump it when
+	// the archive changes in a way older versions cannot restore.
+	SchemaVersion = 1
+	// Written at the start of every archive to identify the file.
+	magic = "CLOUDDRIVER-BACKUP"
+)
+
+var (
+	ErrInvalidArchive = errors.New("file is not a clouddriver backup")
+	ErrDecrypt        = errors.New("error decrypting backup: wrong key or corrupted file")
+)
+
+// Archive holds everything needed to restore clouddriver's accounts.
+// Deployed resources are not backed up, they are rebuilt as pipelines run.
+type Archive struct {
+	SchemaVersion int                   `json:"schemaVersion"`
+	CreatedAt     time.Time             `json:"createdAt"`
+	Providers     []kubernetes.Provider `json:"providers"`
+}
+
+// Dump reads all providers, including their credentials and permissions.
+func Dump(sc sql.Client) (Archive, error) {
+	a := Archive{
+		SchemaVersion: SchemaVersion,
+		CreatedAt:     time.Now().UTC(),
+		Providers:     []kubernetes.Provider{},
+	}
+
+	providers, err := sc.ListKubernetesProvidersAndPermissions()
+	if err != nil {
+		return a, err
+	}
+
+	for _, provider := range providers {
+		// Listing providers does not return the bearer token.
+		p, err := sc.GetKubernetesProvider(provider.Name)
+		if err != nil {
+			return a, fmt.Errorf("error getting provider %s: %w", provider.Name, err)
+		}
+
+		provider.BearerToken = p.BearerToken
+		a.Providers = append(a.Providers, provider)
+	}
+
+	return a, nil
+}
+
+// Restore creates the providers of the archive. Providers that already exist
+// are skipped unless overwrite is true, in which case they are replaced.
+// Returns the names of the providers that were restored.
+func Restore(sc sql.Client, a Archive, overwrite bool) ([]string, error) {
+	restored := []string{}
+
+	if a.SchemaVersion < 1 || a.SchemaVersion > SchemaVersion {
+		return restored, fmt.Errorf("unsupported backup schema version %d, this version of clouddriver supports up to %d",
+			a.SchemaVersion, SchemaVersion)
+	}
+
+	for _, p := range a.Providers {
+		_, err := sc.GetKubernetesProvider(p.Name)
+		if err == nil {
+			if !overwrite {
+				continue
+			}
+
+			err = sc.DeleteKubernetesProvider(p.Name)
+			if err != nil {
+				return restored, fmt.Errorf("error deleting provider %s: %w", p.Name, err)
+			}
+		}
+
+		err = sc.CreateKubernetesProvider(p)
+		if err != nil {
+			return restored, fmt.Errorf("error creating provider %s: %w", p.Name, err)
+		}
+
+		for _, group := range p.Permissions.Read {
+			rp := clouddriver.ReadPermission{
+				ID:          uuid.New().String(),
+				AccountName: p.Name,
+				ReadGroup:   group,
+			}
+
+			err = sc.CreateReadPermission(rp)
+			if err != nil {
+				return restored, fmt.Errorf("error creating read permission for provider %s: %w", p.Name, err)
+			}
+		}
+
+		for _, group := range p.Permissions.Write {
+			wp := clouddriver.WritePermission{
+				ID:          uuid.New().String(),
+				AccountName: p.Name,
+				WriteGroup:  group,
+			}
+
+			err = sc.CreateWritePermission(wp)
+			if err != nil {
+				return restored, fmt.Errorf("error creating write permission for provider %s: %w", p.Name, err)
+			}
+		}
+
+		for _, group := range p.Permissions.Execute {
+			ep := clouddriver.ExecutePermission{
+				ID:           uuid.New().String(),
+				AccountName:  p.Name,
+				ExecuteGroup: group,
+			}
+
+			err = sc.CreateExecutePermission(ep)
+			if err != nil {
+				return restored, fmt.Errorf("error creating execute permission for provider %s: %w", p.Name, err)
+			}
+		}
+
+		for namespace, np := range p.Permissions.Namespaces {
+			for _, authorization := range []string{"READ", "WRITE", "EXECUTE"} {
+				for _, group := range np.Groups(authorization) {
+					nsp := clouddriver.NamespacePermission{
+						ID:            uuid.New().String(),
+						AccountName:   p.Name,
+						Namespace:     namespace,
+						Authorization: authorization,
+						GroupName:     group,
+					}
+
+					err = sc.CreateNamespacePermission(nsp)
+					if err != nil {
+						return restored, fmt.Errorf("error creating permission of namespace %s for provider %s: %w",
+							namespace, p.Name, err)
+					}
+				}
+			}
+		}
+
+		restored = append(restored, p.Name)
+	}
+
+	return restored, nil
+}
+
+// ParseKey decodes a base64 encoded 32 byte key, such as one generated
+// with `openssl rand -base64 32`.
+func ParseKey(s string) ([]byte, error) {
+	key, err := base64.StdEncoding.DecodeString(s)
+	if err != nil {
+		return nil, fmt.Errorf("error decoding key: %w", err)
+	}
+
+	if len(key) != 32 {
+		return nil, fmt.Errorf("key must be 32 bytes, got %d", len(key))
+	}
+
+	return key, nil
+}
+
+// Write gzips the archive, encrypts it with AES-256-GCM and writes it to w.
+func Write(w io.Writer, a Archive, key []byte) error {
+	buf := &bytes.Buffer{}
+	gw := gzip.NewWriter(buf)
+
+	err := json.NewEncoder(gw).Encode(a)
+	if err != nil {
+		return err
+	}
+
+	err = gw.Close()
+	if err != nil {
+		return err
+	}
+
+	gcm, err := newGCM(key)
+	if err != nil {
+		return err
+	}
+
+	nonce := make([]byte, gcm.NonceSize())
+
+	_, err = rand.Read(nonce)
+	if err != nil {
+		return err
+	}
+
+	// The header is authenticated so it cannot be swapped.
+	header := []byte(magic)
+	ciphertext := gcm.Seal(nil, nonce, buf.Bytes(), header)
+
+	for _, b := range [][]byte{header, nonce, ciphertext} {
+		_, err = w.Write(b)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// Read decrypts and decompresses an archive written by Write.
+func Read(r io.Reader, key []byte) (Archive, error) {
+	a := Archive{}
+
+	b, err := ioutil.ReadAll(r)
+	if err != nil {
+		return a, err
+	}
+
+	gcm, err := newGCM(key)
+	if err != nil {
+		return a, err
+	}
+
+	header := []byte(magic)
+	if len(b) < len(header)+gcm.NonceSize() || !bytes.Equal(b[:len(header)], header) {
+		return a, ErrInvalidArchive
+	}
+
+	b = b[len(header):]
+	nonce, ciphertext := b[:gcm.NonceSize()], b[gcm.NonceSize():]
+
+	plaintext, err := gcm.Open(nil, nonce, ciphertext, header)
+	if err != nil {
+		return a, ErrDecrypt
+	}
+
+	gr, err := gzip.NewReader(bytes.NewReader(plaintext))
+	if err != nil {
+		return a, err
+	}
+	defer gr.Close()
+
+	err = json.NewDecoder(gr).Decode(&a)
+	if err != nil {
+		return a, err
+	}
+
+	return a, nil
+}
+
+func newGCM(key []byte) (cipher.AEAD, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+
+	return cipher.NewGCM(block)
+}
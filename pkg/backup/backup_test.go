@@ -0,0 +1,234 @@
+package backup_test
+
+import (
+	"bytes"
+	"errors"
+
+	. "github.com/billiford/go-clouddriver/pkg/backup"
+	"github.com/billiford/go-clouddriver/pkg/kubernetes"
+	"github.com/billiford/go-clouddriver/pkg/sql/sqlfakes"
+	"github.com/jinzhu/gorm"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Backup", func() {
+	var (
+		err           error
+		fakeSQLClient *sqlfakes.FakeClient
+		archive       Archive
+		key           []byte
+	)
+
+	BeforeEach(func() {
+		fakeSQLClient = &sqlfakes.FakeClient{}
+		archive = Archive{
+			SchemaVersion: SchemaVersion,
+			Providers: []kubernetes.Provider{
+				{
+					Name:        "provider1",
+					Host:        "host1",
+					CAData:      "ca-data1",
+					BearerToken: "token1",
+					Permissions: kubernetes.ProviderPermissions{
+						Read:    []string{"read-group"},
+						Write:   []string{"write-group1", "write-group2"},
+						Execute: []string{"execute-group"},
+						Namespaces: map[string]kubernetes.ProviderNamespacePermissions{
+							"namespace1": {Execute: []string{"namespace-group"}},
+						},
+					},
+				},
+			},
+		}
+		key, _ = ParseKey("MDEyMzQ1Njc4OWFiY2RlZjAxMjM0NTY3ODlhYmNkZWY=")
+	})
+
+	Describe("#Dump", func() {
+		BeforeEach(func() {
+			fakeSQLClient.ListKubernetesProvidersAndPermissionsReturns([]kubernetes.Provider{
+				{
+					Name:   "provider1",
+					Host:   "host1",
+					CAData: "ca-data1",
+				},
+			}, nil)
+			fakeSQLClient.GetKubernetesProviderReturns(kubernetes.Provider{
+				Host:        "host1",
+				CAData:      "ca-data1",
+				BearerToken: "token1",
+			}, nil)
+		})
+
+		JustBeforeEach(func() {
+			archive, err = Dump(fakeSQLClient)
+		})
+
+		When("listing providers returns an error", func() {
+			BeforeEach(func() {
+				fakeSQLClient.ListKubernetesProvidersAndPermissionsReturns(nil, errors.New("error listing providers"))
+			})
+
+			It("returns an error", func() {
+				Expect(err).ToNot(BeNil())
+				Expect(err.Error()).To(Equal("error listing providers"))
+			})
+		})
+
+		When("getting a provider returns an error", func() {
+			BeforeEach(func() {
+				fakeSQLClient.GetKubernetesProviderReturns(kubernetes.Provider{}, errors.New("error getting provider"))
+			})
+
+			It("returns an error", func() {
+				Expect(err).ToNot(BeNil())
+				Expect(err.Error()).To(Equal("error getting provider provider1: error getting provider"))
+			})
+		})
+
+		When("it succeeds", func() {
+			It("includes the bearer token", func() {
+				Expect(err).To(BeNil())
+				Expect(archive.SchemaVersion).To(Equal(SchemaVersion))
+				Expect(archive.Providers).To(HaveLen(1))
+				Expect(archive.Providers[0].Name).To(Equal("provider1"))
+				Expect(archive.Providers[0].BearerToken).To(Equal("token1"))
+			})
+		})
+	})
+
+	Describe("#Restore", func() {
+		var (
+			overwrite bool
+			restored  []string
+		)
+
+		BeforeEach(func() {
+			overwrite = false
+			fakeSQLClient.GetKubernetesProviderReturns(kubernetes.Provider{}, gorm.ErrRecordNotFound)
+		})
+
+		JustBeforeEach(func() {
+			restored, err = Restore(fakeSQLClient, archive, overwrite)
+		})
+
+		When("the schema version is not supported", func() {
+			BeforeEach(func() {
+				archive.SchemaVersion = SchemaVersion + 1
+			})
+
+			It("returns an error", func() {
+				Expect(err).ToNot(BeNil())
+				Expect(err.Error()).To(Equal("unsupported backup schema version 2, this version of clouddriver supports up to 1"))
+				Expect(fakeSQLClient.CreateKubernetesProviderCallCount()).To(BeZero())
+			})
+		})
+
+		When("the provider exists", func() {
+			BeforeEach(func() {
+				fakeSQLClient.GetKubernetesProviderReturns(kubernetes.Provider{}, nil)
+			})
+
+			It("skips it", func() {
+				Expect(err).To(BeNil())
+				Expect(restored).To(BeEmpty())
+				Expect(fakeSQLClient.CreateKubernetesProviderCallCount()).To(BeZero())
+			})
+
+			When("overwrite is true", func() {
+				BeforeEach(func() {
+					overwrite = true
+				})
+
+				It("replaces it", func() {
+					Expect(err).To(BeNil())
+					Expect(restored).To(Equal([]string{"provider1"}))
+					Expect(fakeSQLClient.DeleteKubernetesProviderCallCount()).To(Equal(1))
+					Expect(fakeSQLClient.CreateKubernetesProviderCallCount()).To(Equal(1))
+				})
+			})
+		})
+
+		When("creating the provider returns an error", func() {
+			BeforeEach(func() {
+				fakeSQLClient.CreateKubernetesProviderReturns(errors.New("error creating provider"))
+			})
+
+			It("returns an error", func() {
+				Expect(err).ToNot(BeNil())
+				Expect(err.Error()).To(Equal("error creating provider provider1: error creating provider"))
+			})
+		})
+
+		When("it succeeds", func() {
+			It("creates the provider and its permissions", func() {
+				Expect(err).To(BeNil())
+				Expect(restored).To(Equal([]string{"provider1"}))
+				Expect(fakeSQLClient.CreateKubernetesProviderArgsForCall(0).BearerToken).To(Equal("token1"))
+				Expect(fakeSQLClient.CreateReadPermissionCallCount()).To(Equal(1))
+				Expect(fakeSQLClient.CreateWritePermissionCallCount()).To(Equal(2))
+				Expect(fakeSQLClient.CreateWritePermissionArgsForCall(1).WriteGroup).To(Equal("write-group2"))
+				Expect(fakeSQLClient.CreateExecutePermissionCallCount()).To(Equal(1))
+				Expect(fakeSQLClient.CreateNamespacePermissionCallCount()).To(Equal(1))
+				np := fakeSQLClient.CreateNamespacePermissionArgsForCall(0)
+				Expect(np.Namespace).To(Equal("namespace1"))
+				Expect(np.Authorization).To(Equal("EXECUTE"))
+				Expect(np.GroupName).To(Equal("namespace-group"))
+			})
+		})
+	})
+
+	Describe("#ParseKey", func() {
+		When("the key is not base64", func() {
+			It("returns an error", func() {
+				_, err = ParseKey("!")
+				Expect(err).ToNot(BeNil())
+			})
+		})
+
+		When("the key is the wrong length", func() {
+			It("returns an error", func() {
+				_, err = ParseKey("dGVzdA==")
+				Expect(err).ToNot(BeNil())
+				Expect(err.Error()).To(Equal("key must be 32 bytes, got 4"))
+			})
+		})
+	})
+
+	Describe("#Write and #Read", func() {
+		var (
+			buf  *bytes.Buffer
+			read Archive
+		)
+
+		BeforeEach(func() {
+			buf = &bytes.Buffer{}
+			err = Write(buf, archive, key)
+			Expect(err).To(BeNil())
+		})
+
+		When("the file is not a backup", func() {
+			It("returns an error", func() {
+				_, err = Read(bytes.NewBufferString("hello"), key)
+				Expect(err).To(Equal(ErrInvalidArchive))
+			})
+		})
+
+		When("the key is wrong", func() {
+			It("returns an error", func() {
+				wrongKey, _ := ParseKey("ZmVkY2JhOTg3NjU0MzIxMGZlZGNiYTk4NzY1NDMyMTA=")
+				_, err = Read(buf, wrongKey)
+				Expect(err).To(Equal(ErrDecrypt))
+			})
+		})
+
+		When("it succeeds", func() {
+			It("round trips the archive", func() {
+				Expect(buf.String()).ToNot(ContainSubstring("token1"))
+				read, err = Read(buf, key)
+				Expect(err).To(BeNil())
+				Expect(read).To(Equal(archive))
+			})
+		})
+	})
+})
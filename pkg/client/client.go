@@ -0,0 +1,300 @@
+// Package client is a typed client of the go-clouddriver API, for services
+// and test suites that call clouddriver directly instead of through Gate or Orca.
+package client
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	clouddriver "github.com/billiford/go-clouddriver/pkg"
+	ops "github.com/billiford/go-clouddriver/pkg/http/core/kubernetes"
+)
+
+const (
+	defaultURL          = "http://localhost:7002"
+	defaultMaxRetries   = 3
+	defaultRetryWait    = 500 * time.Millisecond
+	defaultMaxRetryWait = 10 * time.Second
+)
+
+// Config configures how a Client reaches clouddriver.
+type Config struct {
+	// URL of clouddriver. Defaults to http://localhost:7002.
+	URL string
+	// User is sent as X-Spinnaker-User, which Fiat authorizes.
+	User string
+	// SharedSecret is sent as X-Spinnaker-Shared-Secret, if clouddriver requires one.
+	SharedSecret string
+	// HTTPClient sends the requests. Defaults to http.DefaultClient.
+	HTTPClient *http.Client
+	// MaxRetries is how many times a failed request is retried, three by
+	// default. A negative value disables retries.
+	MaxRetries int
+	// RetryWait is the wait before the first retry, doubled for each retry
+	// after it up to MaxRetryWait. Defaults to 500ms and ten seconds.
+	RetryWait    time.Duration
+	MaxRetryWait time.Duration
+}
+
+// Client calls the go-clouddriver API.
+//
+// Reads are retried on connection errors, 429 Too Many Requests and 502, 503
+// and 504 responses. Operations are only retried on 429 Too Many Requests,
+// which clouddriver returns before running them, so they are never run twice.
+//
+//go:generate counterfeiter . Client
+type Client interface {
+	// ListCredentials lists the accounts of clouddriver, with their
+	// namespaces and kind map if expand is true.
+	ListCredentials(ctx context.Context, expand bool) ([]clouddriver.Credential, error)
+	// Deploy creates a deployManifest operation, returning the task that runs it.
+	Deploy(ctx context.Context, dm ops.DeployManifestRequest) (ops.OperationsResponse, error)
+	// GetTask returns the status and results of a task.
+	GetTask(ctx context.Context, id string) (clouddriver.Task, error)
+	// GetManifest returns a manifest of an account by its kind and name.
+	GetManifest(ctx context.Context, account, namespace, kind, name string) (ops.ManifestResponse, error)
+}
+
+// Error is returned when clouddriver responds with an error status.
+type Error struct {
+	Method     string
+	Path       string
+	StatusCode int
+	// Message is the message of the clouddriver error, if the response had one.
+	Message string
+}
+
+func (e *Error) Error() string {
+	if e.Message == "" {
+		return fmt.Sprintf("%s %s: %d %s", e.Method, e.Path, e.StatusCode, http.StatusText(e.StatusCode))
+	}
+
+	return fmt.Sprintf("%s %s: %d %s", e.Method, e.Path, e.StatusCode, e.Message)
+}
+
+// NewDefaultClient returns a Client of clouddriver at http://localhost:7002.
+func NewDefaultClient() Client {
+	return New(Config{})
+}
+
+// New returns a Client configured by c.
+func New(c Config) Client {
+	if c.URL == "" {
+		c.URL = defaultURL
+	}
+
+	if c.HTTPClient == nil {
+		c.HTTPClient = http.DefaultClient
+	}
+
+	if c.MaxRetries == 0 {
+		c.MaxRetries = defaultMaxRetries
+	}
+
+	if c.RetryWait <= 0 {
+		c.RetryWait = defaultRetryWait
+	}
+
+	if c.MaxRetryWait <= 0 {
+		c.MaxRetryWait = defaultMaxRetryWait
+	}
+
+	c.URL = strings.TrimSuffix(c.URL, "/")
+
+	return &client{config: c}
+}
+
+type client struct {
+	config Config
+}
+
+func (c *client) ListCredentials(ctx context.Context, expand bool) ([]clouddriver.Credential, error) {
+	credentials := []clouddriver.Credential{}
+
+	err := c.do(ctx, http.MethodGet, "/credentials?expand="+strconv.FormatBool(expand), nil, nil, &credentials)
+	if err != nil {
+		return nil, err
+	}
+
+	return credentials, nil
+}
+
+func (c *client) Deploy(ctx context.Context, dm ops.DeployManifestRequest) (ops.OperationsResponse, error) {
+	or := ops.OperationsResponse{}
+	header := http.Header{}
+
+	if dm.Moniker.App != "" {
+		header.Set("X-Spinnaker-Application", dm.Moniker.App)
+	}
+
+	err := c.do(ctx, http.MethodPost, "/kubernetes/ops", header, ops.Operations{{DeployManifest: &dm}}, &or)
+
+	return or, err
+}
+
+func (c *client) GetTask(ctx context.Context, id string) (clouddriver.Task, error) {
+	task := clouddriver.Task{}
+	err := c.do(ctx, http.MethodGet, "/task/"+url.PathEscape(id), nil, nil, &task)
+
+	return task, err
+}
+
+func (c *client) GetManifest(ctx context.Context, account, namespace, kind, name string) (ops.ManifestResponse, error) {
+	mr := ops.ManifestResponse{}
+	path := fmt.Sprintf("/manifests/%s/%s/%s", url.PathEscape(account), url.PathEscape(namespace),
+		url.PathEscape(kind+" "+name))
+	err := c.do(ctx, http.MethodGet, path, nil, nil, &mr)
+
+	return mr, err
+}
+
+// do sends a request to clouddriver, retrying it as described by Client, and
+// decodes the response into v.
+func (c *client) do(ctx context.Context, method, path string, header http.Header, body, v interface{}) error {
+	var b []byte
+
+	if body != nil {
+		var err error
+
+		b, err = json.Marshal(body)
+		if err != nil {
+			return err
+		}
+	}
+
+	for attempt := 0; ; attempt++ {
+		res, err := c.send(ctx, method, path, header, b)
+		if err == nil && res.StatusCode < http.StatusBadRequest {
+			defer res.Body.Close()
+
+			return json.NewDecoder(res.Body).Decode(v)
+		}
+
+		if err == nil {
+			err = responseError(method, path, res)
+		}
+
+		if attempt >= c.config.MaxRetries || !retryable(method, res) || ctx.Err() != nil {
+			return err
+		}
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(c.wait(attempt, res)):
+		}
+	}
+}
+
+func (c *client) send(ctx context.Context, method, path string, header http.Header, b []byte) (*http.Response, error) {
+	var body io.Reader
+	if b != nil {
+		body = bytes.NewReader(b)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, method, c.config.URL+path, body)
+	if err != nil {
+		return nil, err
+	}
+
+	for k, v := range header {
+		req.Header[k] = v
+	}
+
+	req.Header.Set("Accept", "application/json")
+
+	if b != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+
+	if c.config.User != "" {
+		req.Header.Set("X-Spinnaker-User", c.config.User)
+	}
+
+	if c.config.SharedSecret != "" {
+		req.Header.Set("X-Spinnaker-Shared-Secret", c.config.SharedSecret)
+	}
+
+	return c.config.HTTPClient.Do(req)
+}
+
+// responseError reads the clouddriver error of a response and closes its body.
+func responseError(method, path string, res *http.Response) error {
+	defer res.Body.Close()
+
+	e := &Error{
+		Method:     method,
+		Path:       path,
+		StatusCode: res.StatusCode,
+	}
+
+	// The /v1 endpoints only set the error field.
+	ce := clouddriver.Error{}
+
+	b, _ := ioutil.ReadAll(res.Body)
+	if json.Unmarshal(b, &ce) == nil {
+		e.Message = ce.Message
+		if e.Message == "" {
+			e.Message = ce.Error
+		}
+	}
+
+	return e
+}
+
+// retryable returns true if a request that failed with the response, or
+// without one if res is nil, can be sent again.
+func retryable(method string, res *http.Response) bool {
+	if res != nil && res.StatusCode == http.StatusTooManyRequests {
+		return true
+	}
+
+	if method != http.MethodGet {
+		return false
+	}
+
+	if res == nil {
+		return true
+	}
+
+	switch res.StatusCode {
+	case http.StatusBadGateway, http.StatusServiceUnavailable, http.StatusGatewayTimeout:
+		return true
+	}
+
+	return false
+}
+
+// wait returns how long to wait before retrying a request, which is the
+// Retry-After of the response if it has one.
+func (c *client) wait(attempt int, res *http.Response) time.Duration {
+	wait := c.config.MaxRetryWait
+
+	if res != nil {
+		if seconds, err := strconv.Atoi(res.Header.Get("Retry-After")); err == nil && seconds >= 0 {
+			if retryAfter := time.Duration(seconds) * time.Second; retryAfter < wait {
+				wait = retryAfter
+			}
+
+			return wait
+		}
+	}
+
+	// Stop doubling before it overflows.
+	if attempt < 32 {
+		if backoff := c.config.RetryWait << uint(attempt); backoff > 0 && backoff < wait {
+			wait = backoff
+		}
+	}
+
+	return wait
+}
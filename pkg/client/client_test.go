@@ -0,0 +1,255 @@
+package client_test
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"time"
+
+	clouddriver "github.com/billiford/go-clouddriver/pkg"
+	. "github.com/billiford/go-clouddriver/pkg/client"
+	ops "github.com/billiford/go-clouddriver/pkg/http/core/kubernetes"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"github.com/onsi/gomega/ghttp"
+)
+
+var _ = Describe("Client", func() {
+	var (
+		server *ghttp.Server
+		config Config
+		client Client
+		err    error
+	)
+
+	BeforeEach(func() {
+		server = ghttp.NewServer()
+		config = Config{
+			URL:          server.URL(),
+			User:         "test-user",
+			SharedSecret: "test-secret",
+			RetryWait:    time.Millisecond,
+		}
+	})
+
+	AfterEach(func() {
+		server.Close()
+	})
+
+	JustBeforeEach(func() {
+		client = New(config)
+	})
+
+	Describe("#ListCredentials", func() {
+		var credentials []clouddriver.Credential
+
+		BeforeEach(func() {
+			server.AppendHandlers(ghttp.CombineHandlers(
+				ghttp.VerifyRequest(http.MethodGet, "/credentials", "expand=true"),
+				ghttp.VerifyHeaderKV("X-Spinnaker-User", "test-user"),
+				ghttp.VerifyHeaderKV("X-Spinnaker-Shared-Secret", "test-secret"),
+				ghttp.RespondWith(http.StatusOK, `[{"name":"test-account","namespaces":["default"]}]`),
+			))
+		})
+
+		JustBeforeEach(func() {
+			credentials, err = client.ListCredentials(context.Background(), true)
+		})
+
+		When("clouddriver is unavailable", func() {
+			BeforeEach(func() {
+				server.SetHandler(0, ghttp.RespondWith(http.StatusServiceUnavailable, nil))
+				server.AppendHandlers(ghttp.RespondWith(http.StatusOK, `[{"name":"test-account"}]`))
+			})
+
+			It("retries", func() {
+				Expect(err).To(BeNil())
+				Expect(server.ReceivedRequests()).To(HaveLen(2))
+				Expect(credentials[0].Name).To(Equal("test-account"))
+			})
+		})
+
+		When("clouddriver stays unavailable", func() {
+			BeforeEach(func() {
+				config.MaxRetries = 1
+				server.SetHandler(0, ghttp.RespondWith(http.StatusServiceUnavailable, nil))
+				server.AppendHandlers(ghttp.RespondWith(http.StatusServiceUnavailable, nil))
+			})
+
+			It("returns the error after the last retry", func() {
+				Expect(err).ToNot(BeNil())
+				Expect(err.Error()).To(Equal("GET /credentials?expand=true: 503 Service Unavailable"))
+				Expect(server.ReceivedRequests()).To(HaveLen(2))
+			})
+		})
+
+		When("retries are disabled", func() {
+			BeforeEach(func() {
+				config.MaxRetries = -1
+				server.SetHandler(0, ghttp.RespondWith(http.StatusBadGateway, nil))
+			})
+
+			It("returns the error", func() {
+				Expect(err).ToNot(BeNil())
+				Expect(server.ReceivedRequests()).To(HaveLen(1))
+			})
+		})
+
+		It("returns the credentials", func() {
+			Expect(err).To(BeNil())
+			Expect(credentials).To(HaveLen(1))
+			Expect(credentials[0].Namespaces).To(Equal([]string{"default"}))
+		})
+	})
+
+	Describe("#Deploy", func() {
+		var (
+			dm ops.DeployManifestRequest
+			or ops.OperationsResponse
+		)
+
+		BeforeEach(func() {
+			dm = ops.DeployManifestRequest{
+				Account:   "test-account",
+				Manifests: []map[string]interface{}{{"kind": "ConfigMap"}},
+			}
+			dm.Moniker.App = "test-app"
+			server.AppendHandlers(ghttp.CombineHandlers(
+				ghttp.VerifyRequest(http.MethodPost, "/kubernetes/ops"),
+				ghttp.VerifyHeaderKV("X-Spinnaker-Application", "test-app"),
+				ghttp.VerifyContentType("application/json"),
+				ghttp.VerifyJSONRepresenting(ops.Operations{{DeployManifest: &dm}}),
+				ghttp.RespondWith(http.StatusOK, `{"id":"test-task","resourceUri":"/task/test-task"}`),
+			))
+		})
+
+		JustBeforeEach(func() {
+			or, err = client.Deploy(context.Background(), dm)
+		})
+
+		When("the operation is rate limited", func() {
+			BeforeEach(func() {
+				server.Reset()
+				server.AppendHandlers(
+					ghttp.RespondWith(http.StatusTooManyRequests, `{"message":"client test-user exceeded the rate limit"}`,
+						http.Header{"Retry-After": []string{"0"}}),
+					ghttp.RespondWith(http.StatusOK, `{"id":"test-task"}`),
+				)
+			})
+
+			It("retries", func() {
+				Expect(err).To(BeNil())
+				Expect(server.ReceivedRequests()).To(HaveLen(2))
+				Expect(or.ID).To(Equal("test-task"))
+			})
+		})
+
+		When("clouddriver is unavailable", func() {
+			BeforeEach(func() {
+				server.SetHandler(0, ghttp.RespondWith(http.StatusServiceUnavailable, nil))
+			})
+
+			It("does not retry, as the operation may have run", func() {
+				Expect(err).ToNot(BeNil())
+				Expect(server.ReceivedRequests()).To(HaveLen(1))
+			})
+		})
+
+		When("the operation is rejected", func() {
+			BeforeEach(func() {
+				server.SetHandler(0, ghttp.RespondWith(http.StatusBadRequest, `{"error":"Bad Request","message":"account is in maintenance"}`))
+			})
+
+			It("returns the clouddriver error", func() {
+				Expect(err).ToNot(BeNil())
+				var ce *Error
+				Expect(errors.As(err, &ce)).To(BeTrue())
+				Expect(ce.StatusCode).To(Equal(http.StatusBadRequest))
+				Expect(ce.Message).To(Equal("account is in maintenance"))
+			})
+		})
+
+		It("returns the task", func() {
+			Expect(err).To(BeNil())
+			Expect(or.ResourceURI).To(Equal("/task/test-task"))
+		})
+	})
+
+	Describe("#GetTask", func() {
+		var (
+			ctx    context.Context
+			cancel context.CancelFunc
+			task   clouddriver.Task
+		)
+
+		BeforeEach(func() {
+			ctx, cancel = context.WithCancel(context.Background())
+			server.AppendHandlers(ghttp.CombineHandlers(
+				ghttp.VerifyRequest(http.MethodGet, "/task/test-task"),
+				ghttp.RespondWith(http.StatusOK, `{"id":"test-task","status":{"completed":true,"failed":false}}`),
+			))
+		})
+
+		AfterEach(func() {
+			cancel()
+		})
+
+		JustBeforeEach(func() {
+			task, err = client.GetTask(ctx, "test-task")
+		})
+
+		When("the context is done while waiting to retry", func() {
+			BeforeEach(func() {
+				server.SetHandler(0, ghttp.RespondWith(http.StatusServiceUnavailable, nil))
+				config.RetryWait = time.Hour
+				config.MaxRetryWait = time.Hour
+				cancel()
+				ctx, cancel = context.WithTimeout(context.Background(), 10*time.Millisecond)
+			})
+
+			It("stops retrying", func() {
+				Expect(err).To(Equal(context.DeadlineExceeded))
+				Expect(server.ReceivedRequests()).To(HaveLen(1))
+			})
+		})
+
+		It("returns the task", func() {
+			Expect(err).To(BeNil())
+			Expect(task.ID).To(Equal("test-task"))
+			Expect(task.Status.Completed).To(BeTrue())
+		})
+	})
+
+	Describe("#GetManifest", func() {
+		var mr ops.ManifestResponse
+
+		BeforeEach(func() {
+			server.AppendHandlers(ghttp.CombineHandlers(
+				ghttp.VerifyRequest(http.MethodGet, "/manifests/test-account/default/deployment test-deployment"),
+				ghttp.RespondWith(http.StatusOK, `{"account":"test-account","name":"deployment test-deployment"}`),
+			))
+		})
+
+		JustBeforeEach(func() {
+			mr, err = client.GetManifest(context.Background(), "test-account", "default", "deployment", "test-deployment")
+		})
+
+		When("the manifest does not exist", func() {
+			BeforeEach(func() {
+				server.SetHandler(0, ghttp.RespondWith(http.StatusNotFound, `{"error":"Not Found","message":"deployments.apps \"test-deployment\" not found"}`))
+			})
+
+			It("returns the error without retrying", func() {
+				Expect(err).ToNot(BeNil())
+				Expect(err.Error()).To(Equal("GET /manifests/test-account/default/deployment%20test-deployment: " +
+					`404 deployments.apps "test-deployment" not found`))
+				Expect(server.ReceivedRequests()).To(HaveLen(1))
+			})
+		})
+
+		It("returns the manifest", func() {
+			Expect(err).To(BeNil())
+			Expect(mr.Name).To(Equal("deployment test-deployment"))
+		})
+	})
+})
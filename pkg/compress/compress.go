@@ -0,0 +1,58 @@
+// Package compress compresses large values stored in SQL, such as manifests,
+// with zstd and hashes their content, so unchanged values can be stored once.
+package compress
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"time"
+
+	"github.com/klauspost/compress/zstd"
+)
+
+var (
+	// The magic number every zstd frame starts with.
+	magic = []byte{0x28, 0xb5, 0x2f, 0xfd}
+
+	// Encoders and decoders without options never fail to be created, and
+	// are safe for concurrent use with EncodeAll and DecodeAll.
+	encoder, _ = zstd.NewWriter(nil)
+	decoder, _ = zstd.NewReader(nil)
+)
+
+// Blob is a compressed value, stored once by the hash of its content and
+// referenced by that hash from the rows that hold it.
+type Blob struct {
+	Hash string `gorm:"primary_key"`
+	Data []byte
+	// When the value was last stored, so values that are being stored are not
+	// deleted before the rows referencing them are.
+	UsedAt time.Time `gorm:"index"`
+}
+
+func (Blob) TableName() string {
+	return "compressed_blobs"
+}
+
+// Encode returns b compressed with zstd.
+func Encode(b []byte) []byte {
+	return encoder.EncodeAll(b, make([]byte, 0, len(b)/2))
+}
+
+// Decode returns b decompressed. Values that are not zstd compressed, such as
+// those stored before compression, are returned as they are.
+func Decode(b []byte) ([]byte, error) {
+	if !bytes.HasPrefix(b, magic) {
+		return b, nil
+	}
+
+	return decoder.DecodeAll(b, nil)
+}
+
+// Hash returns the hex encoded SHA-256 of b, which identifies values with the
+// same content.
+func Hash(b []byte) string {
+	sum := sha256.Sum256(b)
+	return hex.EncodeToString(sum[:])
+}
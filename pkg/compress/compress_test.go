@@ -0,0 +1,54 @@
+package compress_test
+
+import (
+	"bytes"
+
+	. "github.com/billiford/go-clouddriver/pkg/compress"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Compress", func() {
+	var value []byte
+
+	BeforeEach(func() {
+		value = bytes.Repeat([]byte(`{"kind":"Deployment","metadata":{"name":"test-deployment"}}`), 100)
+	})
+
+	Describe("#Encode", func() {
+		It("compresses the value", func() {
+			encoded := Encode(value)
+			Expect(len(encoded)).To(BeNumerically("<", len(value)))
+
+			decoded, err := Decode(encoded)
+			Expect(err).To(BeNil())
+			Expect(decoded).To(Equal(value))
+		})
+	})
+
+	Describe("#Decode", func() {
+		When("the value is not compressed", func() {
+			It("returns it as it is", func() {
+				decoded, err := Decode([]byte(`{"kind":"Deployment"}`))
+				Expect(err).To(BeNil())
+				Expect(decoded).To(Equal([]byte(`{"kind":"Deployment"}`)))
+			})
+		})
+
+		When("the compressed value is corrupt", func() {
+			It("returns an error", func() {
+				encoded := Encode(value)
+				_, err := Decode(encoded[:len(encoded)/2])
+				Expect(err).ToNot(BeNil())
+			})
+		})
+	})
+
+	Describe("#Hash", func() {
+		It("is the same for the same content", func() {
+			Expect(Hash(value)).To(Equal(Hash(append([]byte{}, value...))))
+			Expect(Hash(value)).ToNot(Equal(Hash([]byte("other"))))
+			Expect(Hash(nil)).To(Equal("e3b0c44298fc1c149afbf4c8996fb92427ae41e4649b934ca495991b7852b855"))
+		})
+	})
+})
@@ -5,17 +5,20 @@ type Credential struct {
 	CacheThreads                int           `json:"cacheThreads"`
 	ChallengeDestructiveActions bool          `json:"challengeDestructiveActions"`
 	CloudProvider               string        `json:"cloudProvider"`
+	Deleted                     bool          `json:"deleted,omitempty"`
 	DockerRegistries            []interface{} `json:"dockerRegistries"`
 	Enabled                     bool          `json:"enabled"`
 	Environment                 string        `json:"environment"`
-	Name                        string        `json:"name"`
-	Namespaces                  []string      `json:"namespaces"`
-	Permissions                 struct {
-		READ  []string `json:"READ"`
-		WRITE []string `json:"WRITE"`
-	} `json:"permissions"`
+	// Set while the account is in maintenance, when operations are rejected.
+	Maintenance             bool              `json:"maintenance,omitempty"`
+	MaintenanceMessage      string            `json:"maintenanceMessage,omitempty"`
+	Name                    string            `json:"name"`
+	Namespaces              []string          `json:"namespaces"`
+	Permissions             Permissions       `json:"permissions"`
 	PrimaryAccount          bool              `json:"primaryAccount"`
 	ProviderVersion         string            `json:"providerVersion"`
+	ReadOnly                bool              `json:"readOnly,omitempty"`
+	Registry                string            `json:"registry,omitempty"`
 	RequiredGroupMembership []interface{}     `json:"requiredGroupMembership"`
 	Skin                    string            `json:"skin"`
 	SpinnakerKindMap        map[string]string `json:"spinnakerKindMap"`
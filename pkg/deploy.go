@@ -0,0 +1,23 @@
+package clouddriver
+
+import "time"
+
+// Deploy is a deployManifest operation to a namespace, kept for deploy
+// stats. Deploys to several namespaces are kept once for each.
+type Deploy struct {
+	TaskID      string `json:"taskId" gorm:"primary_key"`
+	Namespace   string `json:"namespace" gorm:"primary_key"`
+	Account     string `json:"account"`
+	Application string `json:"application" gorm:"index"`
+	// Error is set when the operation failed.
+	Error string `json:"error,omitempty" gorm:"type:text"`
+	// Phase is the outcome of the rollout, such as STABLE, and RolledOutAt
+	// the time it was known, when the rollout was watched.
+	Phase       string     `json:"phase,omitempty"`
+	RolledOutAt *time.Time `json:"rolledOutAt,omitempty"`
+	CreatedAt   time.Time  `json:"createdAt" gorm:"index"`
+}
+
+func (Deploy) TableName() string {
+	return "deploys"
+}
@@ -0,0 +1,530 @@
+package docker
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"regexp"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	// Number of results to ask for per page when listing the catalog or tags
+	// when the account does not define it.
+	defaultPageSize = 1000
+	// How long to cache catalog and tag listings when the account does not define it.
+	defaultCacheInterval = 5 * time.Minute
+	// How long to use a registry token when the token server does not define it.
+	defaultTokenExpiration = 60 * time.Second
+	// How long to stop calling a registry that rate limited us without sending a Retry-After header.
+	defaultRetryAfter = time.Minute
+	// How long to wait for a registry or its token server to respond, so a hung registry does not hang its callers.
+	defaultTimeout = 30 * time.Second
+
+	mediaTypeManifestV2 = "application/vnd.docker.distribution.manifest.v2+json"
+)
+
+var (
+	linkNextRegexp  = regexp.MustCompile(`<([^>]+)>;\s*rel="next"`)
+	challengeRegexp = regexp.MustCompile(`(\w+)="([^"]*)"`)
+	// Path components of a repository name, as defined by the distribution spec.
+	repositoryRegexp = regexp.MustCompile(`^[a-z0-9]+([._-][a-z0-9]+)*(/[a-z0-9]+([._-][a-z0-9]+)*)*$`)
+)
+
+// ErrInvalidRepository is returned when a repository is not a valid repository
+// name, so a repository can never change the path or query of a registry request.
+var ErrInvalidRepository = errors.New("invalid docker repository name")
+
+// Client lists repositories and tags of a docker registry using the
+// registry HTTP API V2. See https://docs.docker.com/registry/spec/api/.
+//
+//go:generate counterfeiter . Client
+type Client interface {
+	Catalog() ([]string, error)
+	Created(string, string) (time.Time, error)
+	Invalidate(string)
+	Tags(string) ([]string, error)
+}
+
+// RateLimitError is returned when the registry is rate limiting requests.
+// No requests are made to the registry until RetryAfter has passed.
+type RateLimitError struct {
+	RetryAfter time.Duration
+}
+
+func (e *RateLimitError) Error() string {
+	return fmt.Sprintf("docker registry rate limit exceeded, retry after %s", e.RetryAfter)
+}
+
+// NewClient returns a client for the registry defined in the credentials.
+// If the credentials list repositories, those are returned as the catalog
+// instead of calling the registry, as many registries (like Docker Hub)
+// do not support the catalog endpoint.
+func NewClient(c Credentials) Client {
+	address := strings.TrimSuffix(c.Address, "/")
+	if !strings.HasPrefix(address, "http://") && !strings.HasPrefix(address, "https://") {
+		address = "https://" + address
+	}
+
+	pageSize := defaultPageSize
+	if c.PageSize > 0 {
+		pageSize = c.PageSize
+	}
+
+	cacheInterval := defaultCacheInterval
+	if c.CacheIntervalSeconds > 0 {
+		cacheInterval = time.Duration(c.CacheIntervalSeconds) * time.Second
+	}
+
+	host := ""
+	if u, err := url.Parse(address); err == nil {
+		host = u.Host
+	}
+
+	return &client{
+		address:       address,
+		host:          host,
+		username:      c.Username,
+		password:      c.Password,
+		repositories:  c.Repositories,
+		pageSize:      pageSize,
+		cacheInterval: cacheInterval,
+		httpClient:    &http.Client{Timeout: defaultTimeout},
+		cache:         map[string]cacheEntry{},
+		created:       map[string]createdEntry{},
+		tokens:        map[string]token{},
+	}
+}
+
+type client struct {
+	address       string
+	host          string
+	username      string
+	password      string
+	repositories  []string
+	pageSize      int
+	cacheInterval time.Duration
+	httpClient    *http.Client
+
+	mux              sync.Mutex
+	cache            map[string]cacheEntry
+	created          map[string]createdEntry
+	tokens           map[string]token
+	rateLimitedUntil time.Time
+}
+
+type cacheEntry struct {
+	values    []string
+	expiresAt time.Time
+}
+
+type createdEntry struct {
+	created   time.Time
+	expiresAt time.Time
+}
+
+type token struct {
+	value     string
+	expiresAt time.Time
+}
+
+type catalogResponse struct {
+	Repositories []string `json:"repositories"`
+}
+
+type tagsResponse struct {
+	Name string   `json:"name"`
+	Tags []string `json:"tags"`
+}
+
+type manifestResponse struct {
+	Config struct {
+		Digest string `json:"digest"`
+	} `json:"config"`
+}
+
+type imageConfigResponse struct {
+	Created time.Time `json:"created"`
+}
+
+type tokenResponse struct {
+	Token       string `json:"token"`
+	AccessToken string `json:"access_token"`
+	ExpiresIn   int    `json:"expires_in"`
+}
+
+// Catalog lists all repositories in the registry.
+func (c *client) Catalog() ([]string, error) {
+	if len(c.repositories) > 0 {
+		return c.repositories, nil
+	}
+
+	entry, ok := c.cached("catalog")
+	if ok && time.Now().Before(entry.expiresAt) {
+		return entry.values, nil
+	}
+
+	repositories := []string{}
+	path := fmt.Sprintf("/v2/_catalog?n=%d", c.pageSize)
+
+	for path != "" {
+		cr := catalogResponse{}
+
+		next, err := c.get(path, "application/json", &cr)
+		if err != nil {
+			// Serve stale results while the registry is rate limiting us.
+			if _, rateLimited := err.(*RateLimitError); rateLimited && ok {
+				return entry.values, nil
+			}
+
+			return nil, fmt.Errorf("error listing docker registry catalog: %w", err)
+		}
+
+		repositories = append(repositories, cr.Repositories...)
+		path = next
+	}
+
+	c.setCached("catalog", repositories)
+
+	return repositories, nil
+}
+
+// Tags lists all tags of a given repository.
+func (c *client) Tags(repository string) ([]string, error) {
+	err := validateRepository(repository)
+	if err != nil {
+		return nil, err
+	}
+
+	key := "tags/" + repository
+
+	entry, ok := c.cached(key)
+	if ok && time.Now().Before(entry.expiresAt) {
+		return entry.values, nil
+	}
+
+	tags := []string{}
+	path := fmt.Sprintf("/v2/%s/tags/list?n=%d", repository, c.pageSize)
+
+	for path != "" {
+		tr := tagsResponse{}
+
+		next, err := c.get(path, "application/json", &tr)
+		if err != nil {
+			// Serve stale results while the registry is rate limiting us.
+			if _, rateLimited := err.(*RateLimitError); rateLimited && ok {
+				return entry.values, nil
+			}
+
+			return nil, fmt.Errorf("error listing docker registry tags for repository %s: %w", repository, err)
+		}
+
+		tags = append(tags, tr.Tags...)
+		path = next
+	}
+
+	c.setCached(key, tags)
+
+	return tags, nil
+}
+
+// Created returns the creation date of the image of a given tag, read from the image's config.
+// Only V2 schema 2 manifests are supported.
+func (c *client) Created(repository, tag string) (time.Time, error) {
+	err := validateRepository(repository)
+	if err != nil {
+		return time.Time{}, err
+	}
+
+	key := repository + ":" + tag
+
+	c.mux.Lock()
+	entry, ok := c.created[key]
+	c.mux.Unlock()
+
+	if ok && time.Now().Before(entry.expiresAt) {
+		return entry.created, nil
+	}
+
+	mr := manifestResponse{}
+
+	_, err = c.get(fmt.Sprintf("/v2/%s/manifests/%s", repository, tag), mediaTypeManifestV2, &mr)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("error getting docker registry manifest for %s: %w", key, err)
+	}
+
+	if mr.Config.Digest == "" {
+		return time.Time{}, fmt.Errorf("no config digest found in docker registry manifest for %s", key)
+	}
+
+	icr := imageConfigResponse{}
+
+	_, err = c.get(fmt.Sprintf("/v2/%s/blobs/%s", repository, mr.Config.Digest), "application/json", &icr)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("error getting docker registry image config for %s: %w", key, err)
+	}
+
+	c.mux.Lock()
+	c.created[key] = createdEntry{
+		created:   icr.Created,
+		expiresAt: time.Now().Add(c.cacheInterval),
+	}
+	c.mux.Unlock()
+
+	return icr.Created, nil
+}
+
+// Invalidate removes the cached catalog and all cached tags and creation
+// dates of a repository, for example after an image has been pushed to it.
+func (c *client) Invalidate(repository string) {
+	c.mux.Lock()
+	defer c.mux.Unlock()
+
+	delete(c.cache, "catalog")
+	delete(c.cache, "tags/"+repository)
+
+	for key := range c.created {
+		if strings.HasPrefix(key, repository+":") {
+			delete(c.created, key)
+		}
+	}
+}
+
+// validateRepository returns ErrInvalidRepository if repository is not a valid repository name.
+func validateRepository(repository string) error {
+	if !repositoryRegexp.MatchString(repository) {
+		return fmt.Errorf("%w %q", ErrInvalidRepository, repository)
+	}
+
+	return nil
+}
+
+// cached returns the cache entry for a key, even if it has expired.
+func (c *client) cached(key string) (cacheEntry, bool) {
+	c.mux.Lock()
+	defer c.mux.Unlock()
+
+	entry, ok := c.cache[key]
+
+	return entry, ok
+}
+
+func (c *client) setCached(key string, values []string) {
+	c.mux.Lock()
+	defer c.mux.Unlock()
+
+	c.cache[key] = cacheEntry{
+		values:    values,
+		expiresAt: time.Now().Add(c.cacheInterval),
+	}
+}
+
+// get requests the path from the registry and unmarshals the response into v,
+// returning the path of the next page if there is one.
+//
+// If the registry responds with a bearer challenge, a token is requested
+// from the registry's token server and the request is retried.
+//
+// If the registry responds with status too many requests, no more requests
+// are made until the time defined by the Retry-After header has passed.
+func (c *client) get(path, accept string, v interface{}) (string, error) {
+	c.mux.Lock()
+	rateLimitedUntil := c.rateLimitedUntil
+	c.mux.Unlock()
+
+	if wait := time.Until(rateLimitedUntil); wait > 0 {
+		return "", &RateLimitError{RetryAfter: wait}
+	}
+
+	res, err := c.do(path, accept, "")
+	if err != nil {
+		return "", err
+	}
+
+	if res.StatusCode == http.StatusUnauthorized {
+		challenge := res.Header.Get("WWW-Authenticate")
+		res.Body.Close()
+
+		if !strings.HasPrefix(strings.ToLower(challenge), "bearer ") {
+			return "", errors.New("unauthorized")
+		}
+
+		t, err := c.token(challenge)
+		if err != nil {
+			return "", err
+		}
+
+		res, err = c.do(path, accept, t)
+		if err != nil {
+			return "", err
+		}
+	}
+	defer res.Body.Close()
+
+	if res.StatusCode == http.StatusTooManyRequests {
+		retryAfter := parseRetryAfter(res.Header.Get("Retry-After"))
+
+		c.mux.Lock()
+		c.rateLimitedUntil = time.Now().Add(retryAfter)
+		c.mux.Unlock()
+
+		return "", &RateLimitError{RetryAfter: retryAfter}
+	}
+
+	if res.StatusCode < 200 || res.StatusCode > 399 {
+		return "", errors.New(res.Status)
+	}
+
+	b, err := ioutil.ReadAll(res.Body)
+	if err != nil {
+		return "", err
+	}
+
+	err = json.Unmarshal(b, v)
+	if err != nil {
+		return "", err
+	}
+
+	next := ""
+	if m := linkNextRegexp.FindStringSubmatch(res.Header.Get("Link")); m != nil {
+		next = m[1]
+	}
+
+	return next, nil
+}
+
+func (c *client) do(path, accept, bearerToken string) (*http.Response, error) {
+	u := path
+	// Some registries return an absolute URL in the Link header.
+	if !strings.HasPrefix(u, "http://") && !strings.HasPrefix(u, "https://") {
+		u = c.address + path
+	}
+
+	req, err := http.NewRequest(http.MethodGet, u, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	req.Header.Set("Accept", accept)
+
+	// Credentials are only sent to the registry, not to other hosts its Link headers point to.
+	if !strings.EqualFold(req.URL.Host, c.host) {
+		return c.httpClient.Do(req)
+	}
+
+	if bearerToken != "" {
+		req.Header.Set("Authorization", "Bearer "+bearerToken)
+	} else if c.username != "" {
+		req.SetBasicAuth(c.username, c.password)
+	}
+
+	return c.httpClient.Do(req)
+}
+
+// token returns a bearer token for the challenge sent back by the registry, for example
+//
+//	Bearer realm="https://auth.docker.io/token",service="registry.docker.io",scope="repository:library/nginx:pull"
+//
+// Tokens are cached per scope until they expire.
+func (c *client) token(challenge string) (string, error) {
+	params := map[string]string{}
+	for _, m := range challengeRegexp.FindAllStringSubmatch(challenge, -1) {
+		params[m[1]] = m[2]
+	}
+
+	realm := params["realm"]
+	if realm == "" {
+		return "", errors.New("no realm found in docker registry auth challenge")
+	}
+
+	key := params["service"] + "|" + params["scope"]
+
+	c.mux.Lock()
+	t, ok := c.tokens[key]
+	c.mux.Unlock()
+
+	if ok && time.Now().Before(t.expiresAt) {
+		return t.value, nil
+	}
+
+	q := url.Values{}
+	if params["service"] != "" {
+		q.Set("service", params["service"])
+	}
+
+	if params["scope"] != "" {
+		q.Set("scope", params["scope"])
+	}
+
+	req, err := http.NewRequest(http.MethodGet, realm+"?"+q.Encode(), nil)
+	if err != nil {
+		return "", err
+	}
+
+	if c.username != "" {
+		req.SetBasicAuth(c.username, c.password)
+	}
+
+	res, err := c.httpClient.Do(req)
+	if err != nil {
+		return "", err
+	}
+	defer res.Body.Close()
+
+	if res.StatusCode < 200 || res.StatusCode > 399 {
+		return "", errors.New("error getting docker registry token: " + res.Status)
+	}
+
+	b, err := ioutil.ReadAll(res.Body)
+	if err != nil {
+		return "", err
+	}
+
+	tr := tokenResponse{}
+
+	err = json.Unmarshal(b, &tr)
+	if err != nil {
+		return "", err
+	}
+
+	t = token{
+		value:     tr.Token,
+		expiresAt: time.Now().Add(defaultTokenExpiration),
+	}
+	// Some token servers only return "access_token" (OAuth 2.0 compatibility).
+	if t.value == "" {
+		t.value = tr.AccessToken
+	}
+
+	if tr.ExpiresIn > 0 {
+		t.expiresAt = time.Now().Add(time.Duration(tr.ExpiresIn) * time.Second)
+	}
+
+	c.mux.Lock()
+	c.tokens[key] = t
+	c.mux.Unlock()
+
+	return t.value, nil
+}
+
+// parseRetryAfter parses the Retry-After header, which is either a number
+// of seconds or an HTTP date.
+func parseRetryAfter(retryAfter string) time.Duration {
+	if seconds, err := strconv.Atoi(retryAfter); err == nil && seconds > 0 {
+		return time.Duration(seconds) * time.Second
+	}
+
+	if t, err := http.ParseTime(retryAfter); err == nil {
+		if wait := time.Until(t); wait > 0 {
+			return wait
+		}
+	}
+
+	return defaultRetryAfter
+}
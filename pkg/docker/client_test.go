@@ -0,0 +1,419 @@
+package docker_test
+
+import (
+	"errors"
+	"net/http"
+	"time"
+
+	. "github.com/billiford/go-clouddriver/pkg/docker"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"github.com/onsi/gomega/ghttp"
+)
+
+var _ = Describe("Client", func() {
+	var (
+		server      *ghttp.Server
+		client      Client
+		credentials Credentials
+		err         error
+		values      []string
+	)
+
+	BeforeEach(func() {
+		server = ghttp.NewServer()
+		credentials = Credentials{
+			Name:    "test-registry",
+			Address: server.URL(),
+		}
+	})
+
+	AfterEach(func() {
+		server.Close()
+	})
+
+	JustBeforeEach(func() {
+		client = NewClient(credentials)
+	})
+
+	Describe("#Catalog", func() {
+		JustBeforeEach(func() {
+			values, err = client.Catalog()
+		})
+
+		When("the credentials define repositories", func() {
+			BeforeEach(func() {
+				credentials.Repositories = []string{"library/nginx"}
+			})
+
+			It("does not call the registry", func() {
+				Expect(err).To(BeNil())
+				Expect(values).To(Equal([]string{"library/nginx"}))
+				Expect(server.ReceivedRequests()).To(HaveLen(0))
+			})
+		})
+
+		When("the server is not reachable", func() {
+			BeforeEach(func() {
+				server.Close()
+			})
+
+			It("returns an error", func() {
+				Expect(err).ToNot(BeNil())
+			})
+		})
+
+		When("the response is not 2XX", func() {
+			BeforeEach(func() {
+				server.AppendHandlers(
+					ghttp.RespondWith(http.StatusInternalServerError, nil),
+				)
+			})
+
+			It("returns an error", func() {
+				Expect(err).ToNot(BeNil())
+				Expect(err.Error()).To(Equal("error listing docker registry catalog: 500 Internal Server Error"))
+			})
+		})
+
+		When("the registry requires an unsupported auth scheme", func() {
+			BeforeEach(func() {
+				server.AppendHandlers(
+					ghttp.RespondWith(http.StatusUnauthorized, nil, http.Header{
+						"WWW-Authenticate": []string{`Basic realm="registry"`},
+					}),
+				)
+			})
+
+			It("returns an error", func() {
+				Expect(err).ToNot(BeNil())
+				Expect(err.Error()).To(Equal("error listing docker registry catalog: unauthorized"))
+			})
+		})
+
+		When("the response is paginated", func() {
+			BeforeEach(func() {
+				server.AppendHandlers(
+					ghttp.CombineHandlers(
+						ghttp.VerifyRequest(http.MethodGet, "/v2/_catalog", "n=1000"),
+						ghttp.RespondWith(http.StatusOK, `{"repositories":["library/nginx"]}`, http.Header{
+							"Link": []string{`</v2/_catalog?last=library%2Fnginx&n=1000>; rel="next"`},
+						}),
+					),
+					ghttp.CombineHandlers(
+						ghttp.VerifyRequest(http.MethodGet, "/v2/_catalog", "last=library%2Fnginx&n=1000"),
+						ghttp.RespondWith(http.StatusOK, `{"repositories":["library/redis"]}`),
+					),
+				)
+			})
+
+			It("lists all pages", func() {
+				Expect(err).To(BeNil())
+				Expect(values).To(Equal([]string{"library/nginx", "library/redis"}))
+			})
+		})
+
+		When("the next page is on another host", func() {
+			var other *ghttp.Server
+
+			BeforeEach(func() {
+				other = ghttp.NewServer()
+				credentials.Username = "test-user"
+				credentials.Password = "test-password"
+				server.AppendHandlers(
+					ghttp.CombineHandlers(
+						ghttp.VerifyBasicAuth("test-user", "test-password"),
+						ghttp.RespondWith(http.StatusOK, `{"repositories":["library/nginx"]}`, http.Header{
+							"Link": []string{`<` + other.URL() + `/v2/_catalog?last=library%2Fnginx&n=1000>; rel="next"`},
+						}),
+					),
+				)
+				other.AppendHandlers(
+					ghttp.CombineHandlers(
+						ghttp.VerifyRequest(http.MethodGet, "/v2/_catalog", "last=library%2Fnginx&n=1000"),
+						ghttp.RespondWith(http.StatusOK, `{"repositories":["library/redis"]}`),
+					),
+				)
+			})
+
+			AfterEach(func() {
+				other.Close()
+			})
+
+			It("lists the page without sending the credentials", func() {
+				Expect(err).To(BeNil())
+				Expect(values).To(Equal([]string{"library/nginx", "library/redis"}))
+				Expect(other.ReceivedRequests()).To(HaveLen(1))
+				Expect(other.ReceivedRequests()[0].Header.Get("Authorization")).To(BeEmpty())
+			})
+		})
+
+		When("the catalog is cached", func() {
+			BeforeEach(func() {
+				server.AppendHandlers(
+					ghttp.RespondWith(http.StatusOK, `{"repositories":["library/nginx"]}`),
+				)
+			})
+
+			It("only calls the registry once", func() {
+				Expect(err).To(BeNil())
+				values, err = client.Catalog()
+				Expect(err).To(BeNil())
+				Expect(values).To(Equal([]string{"library/nginx"}))
+				Expect(server.ReceivedRequests()).To(HaveLen(1))
+			})
+		})
+
+		When("basic auth is configured", func() {
+			BeforeEach(func() {
+				credentials.Username = "test-user"
+				credentials.Password = "test-password"
+				server.AppendHandlers(
+					ghttp.CombineHandlers(
+						ghttp.VerifyBasicAuth("test-user", "test-password"),
+						ghttp.RespondWith(http.StatusOK, `{"repositories":["library/nginx"]}`),
+					),
+				)
+			})
+
+			It("succeeds", func() {
+				Expect(err).To(BeNil())
+				Expect(values).To(Equal([]string{"library/nginx"}))
+			})
+		})
+	})
+
+	Describe("#Tags", func() {
+		var repository string
+
+		BeforeEach(func() {
+			repository = "library/nginx"
+		})
+
+		JustBeforeEach(func() {
+			values, err = client.Tags(repository)
+		})
+
+		When("the repository is not a valid repository name", func() {
+			BeforeEach(func() {
+				repository = "library/nginx/../../catalog?n=1"
+			})
+
+			It("returns an error and does not call the registry", func() {
+				Expect(err).ToNot(BeNil())
+				Expect(errors.Is(err, ErrInvalidRepository)).To(BeTrue())
+				Expect(err.Error()).To(Equal(`invalid docker repository name "library/nginx/../../catalog?n=1"`))
+				Expect(server.ReceivedRequests()).To(HaveLen(0))
+			})
+		})
+
+		When("the response is not 2XX", func() {
+			BeforeEach(func() {
+				server.AppendHandlers(
+					ghttp.RespondWith(http.StatusNotFound, nil),
+				)
+			})
+
+			It("returns an error", func() {
+				Expect(err).ToNot(BeNil())
+				Expect(err.Error()).To(Equal("error listing docker registry tags for repository library/nginx: 404 Not Found"))
+			})
+		})
+
+		When("the response is invalid json", func() {
+			BeforeEach(func() {
+				server.AppendHandlers(
+					ghttp.RespondWith(http.StatusOK, `{`),
+				)
+			})
+
+			It("returns an error", func() {
+				Expect(err).ToNot(BeNil())
+				Expect(err.Error()).To(Equal("error listing docker registry tags for repository library/nginx: unexpected end of JSON input"))
+			})
+		})
+
+		When("getting a token returns an error", func() {
+			BeforeEach(func() {
+				server.AppendHandlers(
+					ghttp.RespondWith(http.StatusUnauthorized, nil, http.Header{
+						"WWW-Authenticate": []string{`Bearer realm="` + server.URL() + `/token",service="test-registry",scope="repository:library/nginx:pull"`},
+					}),
+					ghttp.RespondWith(http.StatusForbidden, nil),
+				)
+			})
+
+			It("returns an error", func() {
+				Expect(err).ToNot(BeNil())
+				Expect(err.Error()).To(Equal("error listing docker registry tags for repository library/nginx: error getting docker registry token: 403 Forbidden"))
+			})
+		})
+
+		When("the registry rate limits the request", func() {
+			BeforeEach(func() {
+				server.AppendHandlers(
+					ghttp.RespondWith(http.StatusTooManyRequests, nil, http.Header{
+						"Retry-After": []string{"120"},
+					}),
+				)
+			})
+
+			It("returns a rate limit error and stops calling the registry", func() {
+				Expect(err).ToNot(BeNil())
+				var rle *RateLimitError
+				Expect(errors.As(err, &rle)).To(BeTrue())
+				Expect(rle.RetryAfter).To(Equal(120 * time.Second))
+
+				_, err = client.Tags("library/redis")
+				Expect(errors.As(err, &rle)).To(BeTrue())
+				Expect(server.ReceivedRequests()).To(HaveLen(1))
+			})
+		})
+
+		When("the registry rate limits the request after tags have been cached", func() {
+			BeforeEach(func() {
+				credentials.CacheIntervalSeconds = 1
+				server.AppendHandlers(
+					ghttp.RespondWith(http.StatusOK, `{"name":"library/nginx","tags":["1.19"]}`),
+					ghttp.RespondWith(http.StatusTooManyRequests, nil),
+				)
+			})
+
+			It("returns the stale tags", func() {
+				Expect(err).To(BeNil())
+				time.Sleep(1100 * time.Millisecond)
+				values, err = client.Tags("library/nginx")
+				Expect(err).To(BeNil())
+				Expect(values).To(Equal([]string{"1.19"}))
+				Expect(server.ReceivedRequests()).To(HaveLen(2))
+			})
+		})
+
+		When("the registry uses token auth", func() {
+			BeforeEach(func() {
+				server.AppendHandlers(
+					ghttp.CombineHandlers(
+						ghttp.VerifyRequest(http.MethodGet, "/v2/library/nginx/tags/list"),
+						ghttp.RespondWith(http.StatusUnauthorized, nil, http.Header{
+							"WWW-Authenticate": []string{`Bearer realm="` + server.URL() + `/token",service="test-registry",scope="repository:library/nginx:pull"`},
+						}),
+					),
+					ghttp.CombineHandlers(
+						ghttp.VerifyRequest(http.MethodGet, "/token", "scope=repository%3Alibrary%2Fnginx%3Apull&service=test-registry"),
+						ghttp.RespondWith(http.StatusOK, `{"token":"test-token","expires_in":300}`),
+					),
+					ghttp.CombineHandlers(
+						ghttp.VerifyRequest(http.MethodGet, "/v2/library/nginx/tags/list"),
+						ghttp.VerifyHeaderKV("Authorization", "Bearer test-token"),
+						ghttp.RespondWith(http.StatusOK, `{"name":"library/nginx","tags":["1.18","1.19","latest"]}`),
+					),
+				)
+			})
+
+			It("succeeds", func() {
+				Expect(err).To(BeNil())
+				Expect(values).To(Equal([]string{"1.18", "1.19", "latest"}))
+			})
+		})
+	})
+
+	Describe("#Created", func() {
+		var (
+			created    time.Time
+			repository string
+		)
+
+		BeforeEach(func() {
+			repository = "library/nginx"
+		})
+
+		JustBeforeEach(func() {
+			created, err = client.Created(repository, "1.19")
+		})
+
+		When("the repository is not a valid repository name", func() {
+			BeforeEach(func() {
+				repository = "Library/Nginx"
+			})
+
+			It("returns an error and does not call the registry", func() {
+				Expect(err).ToNot(BeNil())
+				Expect(errors.Is(err, ErrInvalidRepository)).To(BeTrue())
+				Expect(server.ReceivedRequests()).To(HaveLen(0))
+			})
+		})
+
+		When("getting the manifest returns an error", func() {
+			BeforeEach(func() {
+				server.AppendHandlers(
+					ghttp.RespondWith(http.StatusNotFound, nil),
+				)
+			})
+
+			It("returns an error", func() {
+				Expect(err).ToNot(BeNil())
+				Expect(err.Error()).To(Equal("error getting docker registry manifest for library/nginx:1.19: 404 Not Found"))
+			})
+		})
+
+		When("the manifest has no config", func() {
+			BeforeEach(func() {
+				server.AppendHandlers(
+					ghttp.RespondWith(http.StatusOK, `{"schemaVersion":1}`),
+				)
+			})
+
+			It("returns an error", func() {
+				Expect(err).ToNot(BeNil())
+				Expect(err.Error()).To(Equal("no config digest found in docker registry manifest for library/nginx:1.19"))
+			})
+		})
+
+		When("it succeeds", func() {
+			BeforeEach(func() {
+				server.AppendHandlers(
+					ghttp.CombineHandlers(
+						ghttp.VerifyRequest(http.MethodGet, "/v2/library/nginx/manifests/1.19"),
+						ghttp.VerifyHeaderKV("Accept", "application/vnd.docker.distribution.manifest.v2+json"),
+						ghttp.RespondWith(http.StatusOK, `{"schemaVersion":2,"config":{"digest":"sha256:abc"}}`),
+					),
+					ghttp.CombineHandlers(
+						ghttp.VerifyRequest(http.MethodGet, "/v2/library/nginx/blobs/sha256:abc"),
+						ghttp.RespondWith(http.StatusOK, `{"created":"2020-10-01T12:00:00Z"}`),
+					),
+				)
+			})
+
+			It("returns the image creation date and caches it", func() {
+				Expect(err).To(BeNil())
+				Expect(created).To(Equal(time.Date(2020, 10, 1, 12, 0, 0, 0, time.UTC)))
+
+				created, err = client.Created("library/nginx", "1.19")
+				Expect(err).To(BeNil())
+				Expect(server.ReceivedRequests()).To(HaveLen(2))
+			})
+		})
+	})
+
+	Describe("#Invalidate", func() {
+		BeforeEach(func() {
+			server.AppendHandlers(
+				ghttp.RespondWith(http.StatusOK, `{"name":"library/nginx","tags":["1.19"]}`),
+				ghttp.RespondWith(http.StatusOK, `{"name":"library/nginx","tags":["1.19","1.20"]}`),
+			)
+		})
+
+		It("removes the cached tags of the repository", func() {
+			values, err = client.Tags("library/nginx")
+			Expect(err).To(BeNil())
+			Expect(values).To(Equal([]string{"1.19"}))
+
+			client.Invalidate("library/nginx")
+
+			values, err = client.Tags("library/nginx")
+			Expect(err).To(BeNil())
+			Expect(values).To(Equal([]string{"1.19", "1.20"}))
+			Expect(server.ReceivedRequests()).To(HaveLen(2))
+		})
+	})
+})
@@ -0,0 +1,152 @@
+package docker
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+const (
+	CredentialsControllerInstanceKey = "DockerCredentialsController"
+)
+
+//go:generate counterfeiter . CredentialsController
+type CredentialsController interface {
+	ListCredentials() []Credentials
+	ClientForAccountName(string) (Client, error)
+}
+
+// Credentials define a docker registry account.
+type Credentials struct {
+	Name string `json:"name"`
+	// Address of the registry, for example "https://index.docker.io".
+	Address string `json:"address"`
+	// Optional basic auth, also used when requesting tokens from the registry's token server.
+	Username string `json:"username,omitempty"`
+	Password string `json:"password,omitempty"`
+	// Optional list of repositories to use instead of the registry's catalog.
+	Repositories []string `json:"repositories,omitempty"`
+	// How long to cache the catalog and tags of this registry.
+	CacheIntervalSeconds int `json:"cacheIntervalSeconds,omitempty"`
+	// Number of results to ask the registry for per page when listing the catalog or tags.
+	PageSize int `json:"pageSize,omitempty"`
+	// Sort tags by image creation date instead of by semantic version. This requires
+	// two extra calls to the registry per tag, so use it with care on rate limited registries.
+	SortTagsByDate bool `json:"sortTagsByDate,omitempty"`
+}
+
+var (
+	defaultConfigDir = "/opt/spinnaker/docker/config"
+)
+
+// NewDefaultCredentialsController reads docker registry accounts from
+// /opt/spinnaker/docker/config. Docker registry accounts are optional, so
+// if the directory does not exist no accounts are configured.
+func NewDefaultCredentialsController() (CredentialsController, error) {
+	if _, err := os.Stat(defaultConfigDir); os.IsNotExist(err) {
+		return &credentialsController{
+			credentials: []Credentials{},
+			clients:     map[string]Client{},
+		}, nil
+	}
+
+	return NewCredentialsController(defaultConfigDir)
+}
+
+func NewCredentialsController(dir string) (CredentialsController, error) {
+	cc := credentialsController{
+		credentials: []Credentials{},
+		clients:     map[string]Client{},
+	}
+
+	files, err := ioutil.ReadDir(dir)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, f := range files {
+		if !f.IsDir() {
+			path := filepath.Join(dir, f.Name())
+
+			// Handle symlinks for ConfigMaps.
+			ln, err := filepath.EvalSymlinks(path)
+			if err == nil {
+				path = ln
+			}
+
+			b, err := ioutil.ReadFile(path)
+			if err != nil {
+				// Symlinks to dirs when using kubernetes ConfigMaps, see the artifact credentials controller.
+				continue
+			}
+
+			dc := Credentials{}
+
+			err = json.Unmarshal(b, &dc)
+			if err != nil {
+				return nil, err
+			}
+
+			if dc.Name == "" {
+				return nil, fmt.Errorf("no \"name\" found in docker registry config file %s", path)
+			}
+
+			if dc.Address == "" {
+				return nil, fmt.Errorf("docker registry %s missing required \"address\" attribute", dc.Name)
+			}
+
+			for _, c := range cc.credentials {
+				if strings.EqualFold(dc.Name, c.Name) {
+					return nil, fmt.Errorf("duplicate docker registry credential listed: %s", dc.Name)
+				}
+			}
+
+			cc.clients[dc.Name] = NewClient(dc)
+			cc.credentials = append(cc.credentials, dc)
+		}
+	}
+
+	return &cc, nil
+}
+
+type credentialsController struct {
+	credentials []Credentials
+	clients     map[string]Client
+}
+
+// ListCredentials lists the docker registry accounts without their
+// username and password.
+func (cc *credentialsController) ListCredentials() []Credentials {
+	dc := []Credentials{}
+
+	for _, c := range cc.credentials {
+		d := Credentials{
+			Name:                 c.Name,
+			Address:              c.Address,
+			Repositories:         c.Repositories,
+			CacheIntervalSeconds: c.CacheIntervalSeconds,
+			PageSize:             c.PageSize,
+			SortTagsByDate:       c.SortTagsByDate,
+		}
+		dc = append(dc, d)
+	}
+
+	return dc
+}
+
+func (cc *credentialsController) ClientForAccountName(accountName string) (Client, error) {
+	if _, ok := cc.clients[accountName]; !ok {
+		return nil, fmt.Errorf("docker registry account %s not found", accountName)
+	}
+
+	return cc.clients[accountName], nil
+}
+
+func CredentialsControllerInstance(c *gin.Context) CredentialsController {
+	return c.MustGet(CredentialsControllerInstanceKey).(CredentialsController)
+}
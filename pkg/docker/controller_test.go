@@ -0,0 +1,188 @@
+package docker_test
+
+import (
+	"io/ioutil"
+	"os"
+
+	. "github.com/billiford/go-clouddriver/pkg/docker"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Controller", func() {
+	var (
+		cc  CredentialsController
+		err error
+		dir string
+	)
+
+	BeforeEach(func() {
+		dir = "test"
+	})
+
+	Describe("#NewCredentialsController", func() {
+		JustBeforeEach(func() {
+			cc, err = NewCredentialsController(dir)
+		})
+
+		When("the directory does not exist", func() {
+			BeforeEach(func() {
+				dir = "i-dont-exist"
+			})
+
+			It("returns an error", func() {
+				Expect(err).ToNot(BeNil())
+				Expect(err.Error()).To(Equal("open i-dont-exist: no such file or directory"))
+			})
+		})
+
+		When("a file exists with bad json", func() {
+			var tmpFile *os.File
+
+			BeforeEach(func() {
+				tmpFile, err = ioutil.TempFile("test", "cred*.json")
+			})
+
+			AfterEach(func() {
+				os.Remove(tmpFile.Name())
+			})
+
+			It("returns an error", func() {
+				Expect(err).ToNot(BeNil())
+				Expect(err.Error()).To(Equal("unexpected end of JSON input"))
+			})
+		})
+
+		When("a file exists without specifying a credential name", func() {
+			var tmpFile *os.File
+
+			BeforeEach(func() {
+				tmpFile, err = ioutil.TempFile("test", "cred*.json")
+				_, err = tmpFile.WriteString("{}")
+				Expect(err).To(BeNil())
+			})
+
+			AfterEach(func() {
+				os.Remove(tmpFile.Name())
+			})
+
+			It("returns an error", func() {
+				Expect(err).ToNot(BeNil())
+				Expect(err.Error()).To(HavePrefix("no \"name\" found in docker registry config file test/cred"))
+			})
+		})
+
+		When("a file exists without specifying an address", func() {
+			var tmpFile *os.File
+
+			BeforeEach(func() {
+				tmpFile, err = ioutil.TempFile("test", "cred*.json")
+				_, err = tmpFile.WriteString(`{
+					"name": "no-address"
+				}`)
+				Expect(err).To(BeNil())
+			})
+
+			AfterEach(func() {
+				os.Remove(tmpFile.Name())
+			})
+
+			It("returns an error", func() {
+				Expect(err).ToNot(BeNil())
+				Expect(err.Error()).To(Equal(`docker registry no-address missing required "address" attribute`))
+			})
+		})
+
+		When("a duplicate credential exists", func() {
+			var tmpFile *os.File
+
+			BeforeEach(func() {
+				tmpFile, err = ioutil.TempFile("test", "cred*.json")
+				_, err = tmpFile.WriteString(`{
+					"name": "docker-hub",
+					"address": "https://index.docker.io"
+				}`)
+				Expect(err).To(BeNil())
+			})
+
+			AfterEach(func() {
+				os.Remove(tmpFile.Name())
+			})
+
+			It("returns an error", func() {
+				Expect(err).ToNot(BeNil())
+				Expect(err.Error()).To(Equal("duplicate docker registry credential listed: docker-hub"))
+			})
+		})
+
+		When("it succeeds", func() {
+			It("succeeds", func() {
+				Expect(err).To(BeNil())
+			})
+		})
+	})
+
+	Describe("#ListCredentials", func() {
+		var credentials []Credentials
+
+		BeforeEach(func() {
+			cc, err = NewCredentialsController(dir)
+			Expect(err).To(BeNil())
+		})
+
+		JustBeforeEach(func() {
+			credentials = cc.ListCredentials()
+		})
+
+		When("it succeeds", func() {
+			It("does not list usernames or passwords", func() {
+				Expect(credentials).To(HaveLen(2))
+				Expect(credentials[0].Name).To(Equal("docker-hub"))
+				Expect(credentials[0].Address).To(Equal("https://index.docker.io"))
+				Expect(credentials[0].Repositories).To(Equal([]string{"library/nginx"}))
+				Expect(credentials[1].Name).To(Equal("private-registry"))
+				Expect(credentials[1].CacheIntervalSeconds).To(Equal(60))
+				for _, c := range credentials {
+					Expect(c.Username).To(BeEmpty())
+					Expect(c.Password).To(BeEmpty())
+				}
+			})
+		})
+	})
+
+	Describe("#ClientForAccountName", func() {
+		var (
+			client      Client
+			accountName string
+		)
+
+		BeforeEach(func() {
+			accountName = "docker-hub"
+			cc, err = NewCredentialsController(dir)
+			Expect(err).To(BeNil())
+		})
+
+		JustBeforeEach(func() {
+			client, err = cc.ClientForAccountName(accountName)
+		})
+
+		When("the account does not exist", func() {
+			BeforeEach(func() {
+				accountName = "i-dont-exist"
+			})
+
+			It("returns an error", func() {
+				Expect(err).ToNot(BeNil())
+				Expect(err.Error()).To(Equal("docker registry account i-dont-exist not found"))
+				Expect(client).To(BeNil())
+			})
+		})
+
+		When("it succeeds", func() {
+			It("succeeds", func() {
+				Expect(err).To(BeNil())
+				Expect(client).ToNot(BeNil())
+			})
+		})
+	})
+})
@@ -0,0 +1,56 @@
+package docker
+
+import (
+	"sort"
+	"time"
+
+	"k8s.io/apimachinery/pkg/util/version"
+)
+
+// SortTagsBySemver sorts tags newest version first. Tags that are not
+// versions (like "latest") are sorted alphabetically after all versions.
+func SortTagsBySemver(tags []string) {
+	sort.SliceStable(tags, func(i, j int) bool {
+		vi, erri := version.ParseGeneric(tags[i])
+		vj, errj := version.ParseGeneric(tags[j])
+
+		switch {
+		case erri == nil && errj == nil:
+			if vj.LessThan(vi) {
+				return true
+			}
+
+			if vi.LessThan(vj) {
+				return false
+			}
+
+			return tags[i] < tags[j]
+		case erri == nil:
+			return true
+		case errj == nil:
+			return false
+		default:
+			return tags[i] < tags[j]
+		}
+	})
+}
+
+// SortTagsByCreated sorts tags of a repository newest image first.
+func SortTagsByCreated(client Client, repository string, tags []string) error {
+	created := map[string]time.Time{}
+
+	for _, tag := range tags {
+		t, err := client.Created(repository, tag)
+		if err != nil {
+			return err
+		}
+
+		created[tag] = t
+	}
+
+	sort.SliceStable(tags, func(i, j int) bool {
+		return created[tags[i]].After(created[tags[j]])
+	})
+
+	return nil
+}
@@ -0,0 +1,76 @@
+package docker_test
+
+import (
+	"errors"
+	"time"
+
+	. "github.com/billiford/go-clouddriver/pkg/docker"
+	"github.com/billiford/go-clouddriver/pkg/docker/dockerfakes"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Sort", func() {
+	var tags []string
+
+	Describe("#SortTagsBySemver", func() {
+		BeforeEach(func() {
+			tags = []string{"latest", "1.9.0", "v1.10.0", "stable", "1.10.0-alpine", "2.0"}
+		})
+
+		JustBeforeEach(func() {
+			SortTagsBySemver(tags)
+		})
+
+		It("sorts versions newest first and other tags alphabetically last", func() {
+			Expect(tags).To(Equal([]string{"2.0", "1.10.0-alpine", "v1.10.0", "1.9.0", "latest", "stable"}))
+		})
+	})
+
+	Describe("#SortTagsByCreated", func() {
+		var (
+			fakeClient *dockerfakes.FakeClient
+			err        error
+		)
+
+		BeforeEach(func() {
+			tags = []string{"1.18", "1.19", "latest"}
+			fakeClient = &dockerfakes.FakeClient{}
+			fakeClient.CreatedStub = func(repository, tag string) (time.Time, error) {
+				created := map[string]time.Time{
+					"1.18":   time.Date(2020, 10, 2, 0, 0, 0, 0, time.UTC),
+					"1.19":   time.Date(2020, 10, 1, 0, 0, 0, 0, time.UTC),
+					"latest": time.Date(2020, 10, 3, 0, 0, 0, 0, time.UTC),
+				}
+
+				return created[tag], nil
+			}
+		})
+
+		JustBeforeEach(func() {
+			err = SortTagsByCreated(fakeClient, "library/nginx", tags)
+		})
+
+		When("getting the creation date returns an error", func() {
+			BeforeEach(func() {
+				fakeClient.CreatedStub = nil
+				fakeClient.CreatedReturns(time.Time{}, errors.New("error getting manifest"))
+			})
+
+			It("returns an error", func() {
+				Expect(err).ToNot(BeNil())
+				Expect(err.Error()).To(Equal("error getting manifest"))
+			})
+		})
+
+		When("it succeeds", func() {
+			It("sorts tags newest image first", func() {
+				Expect(err).To(BeNil())
+				Expect(fakeClient.CreatedCallCount()).To(Equal(3))
+				repository, _ := fakeClient.CreatedArgsForCall(0)
+				Expect(repository).To(Equal("library/nginx"))
+				Expect(tags).To(Equal([]string{"latest", "1.18", "1.19"}))
+			})
+		})
+	})
+})
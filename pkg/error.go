@@ -1,6 +1,7 @@
 package clouddriver
 
 import (
+	"fmt"
 	"time"
 
 	"github.com/gin-gonic/gin"
@@ -11,6 +12,9 @@ type Error struct {
 	Message   string `json:"message"`
 	Status    int    `json:"status"`
 	Timestamp int64  `json:"timestamp"`
+	// Set when access is denied, see AccessDeniedError.
+	RequiredAuthorization string   `json:"requiredAuthorization,omitempty"`
+	RequiredGroups        []string `json:"requiredGroups,omitempty"`
 }
 
 // Example.
@@ -33,3 +37,18 @@ func WriteError(c *gin.Context, status int, err error) {
 	c.Status(status)
 	c.Error(err).SetType(gin.ErrorTypePublic)
 }
+
+// AccessDeniedError is returned when Fiat denies a user access to an account
+// or application. RequiredGroups lists the groups that have the required
+// authorization, when known, so users know which group to request.
+type AccessDeniedError struct {
+	ResourceType          string
+	Resource              string
+	RequiredAuthorization string
+	RequiredGroups        []string
+}
+
+func (e *AccessDeniedError) Error() string {
+	return fmt.Sprintf("Access denied to %s %s - required authorization: %s",
+		e.ResourceType, e.Resource, e.RequiredAuthorization)
+}
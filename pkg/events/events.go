@@ -0,0 +1,158 @@
+package events
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"log"
+	"strings"
+	"time"
+
+	"github.com/billiford/go-clouddriver/pkg/kubernetes"
+)
+
+const (
+	// Message attribute that defines the account a message is for.
+	AttributeAccount = "account"
+	// How long to wait before receiving again after an error.
+	receiveErrorBackoff = 5 * time.Second
+)
+
+// AuditEvent is the subset of an audit.k8s.io/v1 Event needed to invalidate caches.
+type AuditEvent struct {
+	Verb      string `json:"verb"`
+	ObjectRef struct {
+		Resource  string `json:"resource"`
+		Namespace string `json:"namespace"`
+		Name      string `json:"name"`
+		APIGroup  string `json:"apiGroup"`
+	} `json:"objectRef"`
+}
+
+// AuditEventList is the subset of an audit.k8s.io/v1 EventList, which is
+// what audit webhook backends send.
+type AuditEventList struct {
+	Items []AuditEvent `json:"items"`
+}
+
+// IsChange returns true if the event created, updated or deleted a resource.
+func (e AuditEvent) IsChange() bool {
+	switch e.Verb {
+	case "create", "update", "patch", "delete", "deletecollection":
+		return true
+	default:
+		return false
+	}
+}
+
+// Message is a message received from a pub/sub system.
+type Message struct {
+	ID string
+	// Handle used to acknowledge the message (an ack ID or receipt handle).
+	AckID   string
+	Account string
+	Data    []byte
+}
+
+// Consumer receives messages carrying cluster change events from a pub/sub system.
+//
+//go:generate counterfeiter . Consumer
+type Consumer interface {
+	Receive(context.Context) ([]Message, error)
+	Ack(context.Context, []Message) error
+}
+
+// Invalidate removes the cached data of an account that is affected
+// by a change to the given resource (for example, "namespaces").
+func Invalidate(nc kubernetes.NamespaceCache, account, resource string) {
+	if strings.EqualFold(resource, "namespaces") {
+		nc.Delete(account)
+	}
+}
+
+// Process invalidates caches for the audit events in data, which is either
+// an audit EventList or a single audit Event.
+func Process(nc kubernetes.NamespaceCache, account string, data []byte) error {
+	el := AuditEventList{}
+
+	err := json.Unmarshal(data, &el)
+	if err != nil {
+		return err
+	}
+
+	if len(el.Items) == 0 {
+		e := AuditEvent{}
+
+		err = json.Unmarshal(data, &e)
+		if err != nil {
+			return err
+		}
+
+		if e.Verb == "" {
+			return errors.New("message is not an audit event")
+		}
+
+		el.Items = []AuditEvent{e}
+	}
+
+	for _, e := range el.Items {
+		if e.IsChange() {
+			Invalidate(nc, account, e.ObjectRef.Resource)
+		}
+	}
+
+	return nil
+}
+
+// Run receives and processes messages from the consumer until the context is done.
+// Messages without an account attribute are processed for the default account.
+// All received messages are acknowledged, even if they fail to process, so bad
+// messages are not redelivered forever.
+func Run(ctx context.Context, c Consumer, nc kubernetes.NamespaceCache, defaultAccount string) {
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		default:
+		}
+
+		messages, err := c.Receive(ctx)
+		if err != nil {
+			log.Println("[EVENTS] error receiving messages:", err.Error())
+
+			select {
+			case <-ctx.Done():
+				return
+			case <-time.After(receiveErrorBackoff):
+			}
+
+			continue
+		}
+
+		if len(messages) == 0 {
+			continue
+		}
+
+		for _, m := range messages {
+			account := m.Account
+			if account == "" {
+				account = defaultAccount
+			}
+
+			if account == "" {
+				log.Println("[EVENTS] skipping message", m.ID, "with no account")
+				continue
+			}
+
+			err = Process(nc, account, m.Data)
+			if err != nil {
+				log.Println("[EVENTS] error processing message", m.ID+":", err.Error())
+			}
+		}
+
+		err = c.Ack(ctx, messages)
+		if err != nil {
+			log.Println("[EVENTS] error acknowledging messages:", err.Error())
+		}
+	}
+}
@@ -0,0 +1,171 @@
+package events_test
+
+import (
+	"context"
+
+	. "github.com/billiford/go-clouddriver/pkg/events"
+	"github.com/billiford/go-clouddriver/pkg/events/eventsfakes"
+	"github.com/billiford/go-clouddriver/pkg/kubernetes/kubernetesfakes"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+const (
+	auditEvent = `{
+		"kind": "Event",
+		"apiVersion": "audit.k8s.io/v1",
+		"verb": "create",
+		"objectRef": {
+			"resource": "namespaces",
+			"name": "test-namespace"
+		}
+	}`
+	auditEventList = `{
+		"kind": "EventList",
+		"apiVersion": "audit.k8s.io/v1",
+		"items": [
+			{
+				"verb": "get",
+				"objectRef": {
+					"resource": "namespaces",
+					"name": "test-namespace"
+				}
+			},
+			{
+				"verb": "delete",
+				"objectRef": {
+					"resource": "namespaces",
+					"name": "test-namespace"
+				}
+			},
+			{
+				"verb": "patch",
+				"objectRef": {
+					"resource": "deployments",
+					"namespace": "test-namespace",
+					"name": "test-deployment",
+					"apiGroup": "apps"
+				}
+			}
+		]
+	}`
+)
+
+var _ = Describe("Events", func() {
+	var (
+		err                error
+		fakeNamespaceCache *kubernetesfakes.FakeNamespaceCache
+	)
+
+	BeforeEach(func() {
+		fakeNamespaceCache = &kubernetesfakes.FakeNamespaceCache{}
+	})
+
+	Describe("#Process", func() {
+		var data string
+
+		JustBeforeEach(func() {
+			err = Process(fakeNamespaceCache, "test-account", []byte(data))
+		})
+
+		When("the data is not json", func() {
+			BeforeEach(func() {
+				data = "{"
+			})
+
+			It("returns an error", func() {
+				Expect(err).ToNot(BeNil())
+			})
+		})
+
+		When("the data is not an audit event", func() {
+			BeforeEach(func() {
+				data = `{"hello":"world"}`
+			})
+
+			It("returns an error", func() {
+				Expect(err).ToNot(BeNil())
+				Expect(err.Error()).To(Equal("message is not an audit event"))
+			})
+		})
+
+		When("the data is a single event", func() {
+			BeforeEach(func() {
+				data = auditEvent
+			})
+
+			It("invalidates the account's namespaces", func() {
+				Expect(err).To(BeNil())
+				Expect(fakeNamespaceCache.DeleteCallCount()).To(Equal(1))
+				Expect(fakeNamespaceCache.DeleteArgsForCall(0)).To(Equal("test-account"))
+			})
+		})
+
+		When("the data is an event list", func() {
+			BeforeEach(func() {
+				data = auditEventList
+			})
+
+			It("invalidates the account's namespaces for namespace changes", func() {
+				Expect(err).To(BeNil())
+				Expect(fakeNamespaceCache.DeleteCallCount()).To(Equal(1))
+				Expect(fakeNamespaceCache.DeleteArgsForCall(0)).To(Equal("test-account"))
+			})
+		})
+	})
+
+	Describe("#Run", func() {
+		var (
+			ctx          context.Context
+			cancel       context.CancelFunc
+			fakeConsumer *eventsfakes.FakeConsumer
+			messages     []Message
+		)
+
+		BeforeEach(func() {
+			ctx, cancel = context.WithCancel(context.Background())
+			messages = []Message{
+				{
+					ID:      "1",
+					AckID:   "ack-1",
+					Account: "test-account",
+					Data:    []byte(auditEvent),
+				},
+				{
+					ID:    "2",
+					AckID: "ack-2",
+					Data:  []byte(auditEvent),
+				},
+				{
+					ID:    "3",
+					AckID: "ack-3",
+					Data:  []byte("bad"),
+				},
+			}
+			fakeConsumer = &eventsfakes.FakeConsumer{}
+			fakeConsumer.ReceiveStub = func(context.Context) ([]Message, error) {
+				if fakeConsumer.ReceiveCallCount() == 1 {
+					return messages, nil
+				}
+
+				cancel()
+
+				return nil, nil
+			}
+		})
+
+		JustBeforeEach(func() {
+			Run(ctx, fakeConsumer, fakeNamespaceCache, "default-account")
+		})
+
+		It("processes and acknowledges all messages", func() {
+			Expect(fakeConsumer.ReceiveCallCount()).To(Equal(2))
+			Expect(fakeNamespaceCache.DeleteCallCount()).To(Equal(2))
+			Expect(fakeNamespaceCache.DeleteArgsForCall(0)).To(Equal("test-account"))
+			Expect(fakeNamespaceCache.DeleteArgsForCall(1)).To(Equal("default-account"))
+			Expect(fakeConsumer.AckCallCount()).To(Equal(1))
+			_, acked := fakeConsumer.AckArgsForCall(0)
+			Expect(acked).To(Equal(messages))
+		})
+	})
+})
@@ -0,0 +1,259 @@
+package events
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	kafkaContentType = "application/vnd.kafka.v2+json"
+	// Keys and values of records are read as base64, so any bytes can be sent.
+	kafkaBinaryContentType = "application/vnd.kafka.binary.v2+json"
+	// Default consumer group of the consumer.
+	defaultKafkaGroup = "clouddriver"
+	// How long the REST proxy waits for records before returning none.
+	kafkaPollTimeout = 10 * time.Second
+)
+
+// NewKafkaConsumer returns a consumer that reads the records of a Kafka topic
+// through a Confluent REST Proxy, for example "http://kafka-rest:8082", as a
+// member of a consumer group, clouddriver if group is empty. The key of a
+// record is the account it is for and its value is the message. The consumer
+// instance is created on the first receive, and created again if the proxy
+// has dropped it.
+func NewKafkaConsumer(url, topic, group string) Consumer {
+	if group == "" {
+		group = defaultKafkaGroup
+	}
+
+	return &kafkaConsumer{
+		url:   strings.TrimSuffix(url, "/"),
+		topic: topic,
+		group: group,
+		// Allow the poll to finish before timing out.
+		httpClient: &http.Client{Timeout: kafkaPollTimeout + 10*time.Second},
+	}
+}
+
+type kafkaConsumer struct {
+	url        string
+	topic      string
+	group      string
+	httpClient *http.Client
+	// instance is the base URI of the consumer instance, or empty if it
+	// has not been created.
+	instance string
+}
+
+type kafkaInstance struct {
+	InstanceID string `json:"instance_id"`
+	BaseURI    string `json:"base_uri"`
+}
+
+type kafkaRecord struct {
+	Topic     string `json:"topic"`
+	Key       string `json:"key"`
+	Value     string `json:"value"`
+	Partition int32  `json:"partition"`
+	Offset    int64  `json:"offset"`
+}
+
+type kafkaOffset struct {
+	Topic     string `json:"topic"`
+	Partition int32  `json:"partition"`
+	Offset    int64  `json:"offset"`
+}
+
+func (c *kafkaConsumer) Receive(ctx context.Context) ([]Message, error) {
+	if c.instance == "" {
+		err := c.subscribe(ctx)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	u := fmt.Sprintf("%s/records?timeout=%d", c.instance, kafkaPollTimeout.Milliseconds())
+
+	b, err := c.do(ctx, http.MethodGet, u, nil, kafkaBinaryContentType)
+	if err != nil {
+		return nil, err
+	}
+
+	records := []kafkaRecord{}
+
+	err = json.Unmarshal(b, &records)
+	if err != nil {
+		return nil, err
+	}
+
+	messages := []Message{}
+
+	for _, r := range records {
+		// Records that fail to decode are still returned (and committed),
+		// they just fail to process.
+		key, _ := base64.StdEncoding.DecodeString(r.Key)
+		value, _ := base64.StdEncoding.DecodeString(r.Value)
+		id := fmt.Sprintf("%s/%d/%d", r.Topic, r.Partition, r.Offset)
+
+		messages = append(messages, Message{
+			ID:      id,
+			AckID:   id,
+			Account: string(key),
+			Data:    value,
+		})
+	}
+
+	return messages, nil
+}
+
+// Ack commits the offsets of the messages, the latest of each partition.
+func (c *kafkaConsumer) Ack(ctx context.Context, messages []Message) error {
+	latest := map[string]kafkaOffset{}
+	partitions := []string{}
+
+	for _, m := range messages {
+		o, err := parseKafkaOffset(m.AckID)
+		if err != nil {
+			return err
+		}
+
+		key := fmt.Sprintf("%s/%d", o.Topic, o.Partition)
+
+		l, ok := latest[key]
+		if !ok {
+			partitions = append(partitions, key)
+		}
+
+		if !ok || o.Offset > l.Offset {
+			latest[key] = o
+		}
+	}
+
+	if len(partitions) == 0 {
+		return nil
+	}
+
+	offsets := []kafkaOffset{}
+	for _, key := range partitions {
+		offsets = append(offsets, latest[key])
+	}
+
+	_, err := c.do(ctx, http.MethodPost, c.instance+"/offsets", map[string]interface{}{"offsets": offsets}, "")
+
+	return err
+}
+
+// parseKafkaOffset parses the topic, partition and offset of the ID of a message.
+func parseKafkaOffset(id string) (kafkaOffset, error) {
+	// Topics cannot have slashes, so the ID is split from the end.
+	parts := strings.Split(id, "/")
+	if len(parts) != 3 {
+		return kafkaOffset{}, fmt.Errorf("invalid kafka message ID %q", id)
+	}
+
+	partition, err := strconv.ParseInt(parts[1], 10, 32)
+	if err != nil {
+		return kafkaOffset{}, fmt.Errorf("invalid kafka message ID %q", id)
+	}
+
+	offset, err := strconv.ParseInt(parts[2], 10, 64)
+	if err != nil {
+		return kafkaOffset{}, fmt.Errorf("invalid kafka message ID %q", id)
+	}
+
+	return kafkaOffset{Topic: parts[0], Partition: int32(partition), Offset: offset}, nil
+}
+
+// subscribe creates a consumer instance in the group and subscribes it to
+// the topic. Offsets are only committed by Ack, and a new group starts at
+// the latest records, as older changes are already reflected in clusters.
+func (c *kafkaConsumer) subscribe(ctx context.Context) error {
+	b, err := c.do(ctx, http.MethodPost, c.url+"/consumers/"+c.group, map[string]interface{}{
+		"format":             "binary",
+		"auto.offset.reset":  "latest",
+		"auto.commit.enable": "false",
+	}, "")
+	if err != nil {
+		return err
+	}
+
+	ki := kafkaInstance{}
+
+	err = json.Unmarshal(b, &ki)
+	if err != nil {
+		return err
+	}
+
+	_, err = c.do(ctx, http.MethodPost, ki.BaseURI+"/subscription", map[string]interface{}{
+		"topics": []string{c.topic},
+	}, "")
+	if err != nil {
+		return err
+	}
+
+	c.instance = ki.BaseURI
+
+	return nil
+}
+
+// do makes a request to the REST proxy and returns the response body. The
+// consumer instance is forgotten if the proxy no longer knows it, such as
+// after it was idle too long, so it is created again on the next receive.
+func (c *kafkaConsumer) do(ctx context.Context, method, url string, body interface{}, accept string) ([]byte, error) {
+	var rb []byte
+
+	if body != nil {
+		var err error
+
+		rb, err = json.Marshal(body)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	req, err := http.NewRequest(method, url, bytes.NewReader(rb))
+	if err != nil {
+		return nil, err
+	}
+
+	req = req.WithContext(ctx)
+
+	if body != nil {
+		req.Header.Set("Content-Type", kafkaContentType)
+	}
+
+	if accept == "" {
+		accept = kafkaContentType
+	}
+
+	req.Header.Set("Accept", accept)
+
+	res, err := c.httpClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer res.Body.Close()
+
+	b, err := ioutil.ReadAll(res.Body)
+	if err != nil {
+		return nil, err
+	}
+
+	if res.StatusCode == http.StatusNotFound && c.instance != "" && strings.HasPrefix(url, c.instance) {
+		c.instance = ""
+	}
+
+	if res.StatusCode < 200 || res.StatusCode > 399 {
+		return nil, fmt.Errorf("error calling Kafka REST proxy: %s: %s", res.Status, string(b))
+	}
+
+	return b, nil
+}
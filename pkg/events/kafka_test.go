@@ -0,0 +1,182 @@
+package events_test
+
+import (
+	"context"
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+
+	. "github.com/billiford/go-clouddriver/pkg/events"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"github.com/onsi/gomega/ghttp"
+)
+
+// The key is "test-account" and the value is an audit event, in base64.
+const kafkaRecordsResponse = `[
+  {
+    "topic": "test-topic",
+    "key": "dGVzdC1hY2NvdW50",
+    "value": "eyJ2ZXJiIjoiY3JlYXRlIn0=",
+    "partition": 1,
+    "offset": 42
+  }
+]`
+
+var _ = Describe("KafkaConsumer", func() {
+	var (
+		server   *ghttp.Server
+		consumer Consumer
+		err      error
+		messages []Message
+	)
+
+	BeforeEach(func() {
+		server = ghttp.NewServer()
+	})
+
+	AfterEach(func() {
+		server.Close()
+	})
+
+	JustBeforeEach(func() {
+		consumer = NewKafkaConsumer(server.URL(), "test-topic", "")
+	})
+
+	verifyJSON := func(expected string) http.HandlerFunc {
+		return func(w http.ResponseWriter, r *http.Request) {
+			b, _ := ioutil.ReadAll(r.Body)
+			Expect(b).To(MatchJSON(expected))
+		}
+	}
+
+	subscribeHandlers := func() []http.HandlerFunc {
+		return []http.HandlerFunc{
+			ghttp.CombineHandlers(
+				ghttp.VerifyRequest(http.MethodPost, "/consumers/clouddriver"),
+				ghttp.VerifyContentType("application/vnd.kafka.v2+json"),
+				verifyJSON(`{"format":"binary","auto.offset.reset":"latest","auto.commit.enable":"false"}`),
+				ghttp.RespondWith(http.StatusOK, `{"instance_id":"test-instance","base_uri":"`+server.URL()+`/consumers/clouddriver/instances/test-instance"}`),
+			),
+			ghttp.CombineHandlers(
+				ghttp.VerifyRequest(http.MethodPost, "/consumers/clouddriver/instances/test-instance/subscription"),
+				verifyJSON(`{"topics":["test-topic"]}`),
+				ghttp.RespondWith(http.StatusNoContent, nil),
+			),
+		}
+	}
+
+	Describe("#Receive", func() {
+		JustBeforeEach(func() {
+			messages, err = consumer.Receive(context.Background())
+		})
+
+		When("creating the consumer instance returns an error", func() {
+			BeforeEach(func() {
+				server.AppendHandlers(ghttp.RespondWith(http.StatusConflict, "exists"))
+			})
+
+			It("returns an error", func() {
+				Expect(err).ToNot(BeNil())
+				Expect(err.Error()).To(Equal("error calling Kafka REST proxy: 409 Conflict: exists"))
+			})
+		})
+
+		When("reading records returns an error", func() {
+			BeforeEach(func() {
+				server.AppendHandlers(subscribeHandlers()...)
+				server.AppendHandlers(ghttp.RespondWith(http.StatusInternalServerError, "error"))
+			})
+
+			It("returns an error", func() {
+				Expect(err).ToNot(BeNil())
+				Expect(err.Error()).To(Equal("error calling Kafka REST proxy: 500 Internal Server Error: error"))
+			})
+		})
+
+		When("the consumer instance no longer exists", func() {
+			BeforeEach(func() {
+				server.AppendHandlers(subscribeHandlers()...)
+				server.AppendHandlers(ghttp.RespondWith(http.StatusNotFound, "not found"))
+				server.AppendHandlers(subscribeHandlers()...)
+				server.AppendHandlers(ghttp.RespondWith(http.StatusOK, "[]"))
+			})
+
+			It("creates it again on the next receive", func() {
+				Expect(err).ToNot(BeNil())
+
+				messages, err = consumer.Receive(context.Background())
+				Expect(err).To(BeNil())
+				Expect(messages).To(BeEmpty())
+				Expect(server.ReceivedRequests()).To(HaveLen(6))
+			})
+		})
+
+		When("it succeeds", func() {
+			BeforeEach(func() {
+				server.AppendHandlers(subscribeHandlers()...)
+				server.AppendHandlers(ghttp.CombineHandlers(
+					ghttp.VerifyRequest(http.MethodGet, "/consumers/clouddriver/instances/test-instance/records", "timeout=10000"),
+					ghttp.VerifyHeaderKV("Accept", "application/vnd.kafka.binary.v2+json"),
+					ghttp.RespondWith(http.StatusOK, kafkaRecordsResponse),
+				))
+			})
+
+			It("returns the messages", func() {
+				Expect(err).To(BeNil())
+				Expect(messages).To(HaveLen(1))
+				Expect(messages[0].ID).To(Equal("test-topic/1/42"))
+				Expect(messages[0].AckID).To(Equal("test-topic/1/42"))
+				Expect(messages[0].Account).To(Equal("test-account"))
+				Expect(string(messages[0].Data)).To(Equal(`{"verb":"create"}`))
+			})
+		})
+	})
+
+	Describe("#Ack", func() {
+		var offsets map[string][]map[string]interface{}
+
+		BeforeEach(func() {
+			server.AppendHandlers(subscribeHandlers()...)
+			server.AppendHandlers(ghttp.RespondWith(http.StatusOK, "[]"))
+			server.AppendHandlers(ghttp.CombineHandlers(
+				ghttp.VerifyRequest(http.MethodPost, "/consumers/clouddriver/instances/test-instance/offsets"),
+				func(w http.ResponseWriter, r *http.Request) {
+					b, _ := ioutil.ReadAll(r.Body)
+					Expect(json.Unmarshal(b, &offsets)).To(Succeed())
+				},
+				ghttp.RespondWith(http.StatusOK, nil),
+			))
+		})
+
+		JustBeforeEach(func() {
+			_, err = consumer.Receive(context.Background())
+			Expect(err).To(BeNil())
+
+			err = consumer.Ack(context.Background(), []Message{
+				{AckID: "test-topic/1/42"},
+				{AckID: "test-topic/1/43"},
+				{AckID: "test-topic/0/7"},
+			})
+		})
+
+		It("commits the latest offset of each partition", func() {
+			Expect(err).To(BeNil())
+			Expect(offsets["offsets"]).To(ConsistOf(
+				map[string]interface{}{"topic": "test-topic", "partition": float64(1), "offset": float64(43)},
+				map[string]interface{}{"topic": "test-topic", "partition": float64(0), "offset": float64(7)},
+			))
+		})
+
+		When("the ack ID is invalid", func() {
+			JustBeforeEach(func() {
+				err = consumer.Ack(context.Background(), []Message{{AckID: "receipt-1"}})
+			})
+
+			It("returns an error", func() {
+				Expect(err).ToNot(BeNil())
+				Expect(err.Error()).To(Equal(`invalid kafka message ID "receipt-1"`))
+			})
+		})
+	})
+})
@@ -0,0 +1,77 @@
+package events
+
+import (
+	"context"
+	"encoding/base64"
+
+	"google.golang.org/api/option"
+	pubsub "google.golang.org/api/pubsub/v1"
+)
+
+const (
+	// Max number of messages to pull from a Google Pub/Sub subscription at once.
+	pubSubMaxMessages = 100
+)
+
+// NewGooglePubSubConsumer returns a consumer that pulls messages from a Google
+// Pub/Sub subscription, for example "projects/my-project/subscriptions/my-subscription".
+// Application default credentials are used unless overridden by opts.
+func NewGooglePubSubConsumer(ctx context.Context, subscription string, opts ...option.ClientOption) (Consumer, error) {
+	svc, err := pubsub.NewService(ctx, opts...)
+	if err != nil {
+		return nil, err
+	}
+
+	return &googlePubSubConsumer{
+		svc:          svc,
+		subscription: subscription,
+	}, nil
+}
+
+type googlePubSubConsumer struct {
+	svc          *pubsub.Service
+	subscription string
+}
+
+func (c *googlePubSubConsumer) Receive(ctx context.Context) ([]Message, error) {
+	res, err := c.svc.Projects.Subscriptions.Pull(c.subscription, &pubsub.PullRequest{
+		MaxMessages: pubSubMaxMessages,
+	}).Context(ctx).Do()
+	if err != nil {
+		return nil, err
+	}
+
+	messages := []Message{}
+
+	for _, rm := range res.ReceivedMessages {
+		if rm.Message == nil {
+			continue
+		}
+
+		// Messages that fail to decode are still returned (and acknowledged),
+		// they just fail to process.
+		data, _ := base64.StdEncoding.DecodeString(rm.Message.Data)
+
+		messages = append(messages, Message{
+			ID:      rm.Message.MessageId,
+			AckID:   rm.AckId,
+			Account: rm.Message.Attributes[AttributeAccount],
+			Data:    data,
+		})
+	}
+
+	return messages, nil
+}
+
+func (c *googlePubSubConsumer) Ack(ctx context.Context, messages []Message) error {
+	ackIDs := []string{}
+	for _, m := range messages {
+		ackIDs = append(ackIDs, m.AckID)
+	}
+
+	_, err := c.svc.Projects.Subscriptions.Acknowledge(c.subscription, &pubsub.AcknowledgeRequest{
+		AckIds: ackIDs,
+	}).Context(ctx).Do()
+
+	return err
+}
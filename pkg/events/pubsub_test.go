@@ -0,0 +1,100 @@
+package events_test
+
+import (
+	"context"
+	"encoding/base64"
+	"net/http"
+
+	. "github.com/billiford/go-clouddriver/pkg/events"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"github.com/onsi/gomega/ghttp"
+	"google.golang.org/api/option"
+)
+
+var _ = Describe("GooglePubSubConsumer", func() {
+	var (
+		server   *ghttp.Server
+		consumer Consumer
+		err      error
+		messages []Message
+	)
+
+	BeforeEach(func() {
+		server = ghttp.NewServer()
+		consumer, err = NewGooglePubSubConsumer(context.Background(), "projects/test-project/subscriptions/test-subscription",
+			option.WithEndpoint(server.URL()+"/"),
+			option.WithHTTPClient(http.DefaultClient),
+			option.WithoutAuthentication())
+		Expect(err).To(BeNil())
+	})
+
+	AfterEach(func() {
+		server.Close()
+	})
+
+	Describe("#Receive", func() {
+		JustBeforeEach(func() {
+			messages, err = consumer.Receive(context.Background())
+		})
+
+		When("the server returns an error", func() {
+			BeforeEach(func() {
+				server.AppendHandlers(ghttp.RespondWith(http.StatusInternalServerError, nil))
+			})
+
+			It("returns an error", func() {
+				Expect(err).ToNot(BeNil())
+			})
+		})
+
+		When("it succeeds", func() {
+			BeforeEach(func() {
+				server.AppendHandlers(ghttp.CombineHandlers(
+					ghttp.VerifyRequest(http.MethodPost, "/v1/projects/test-project/subscriptions/test-subscription:pull"),
+					ghttp.VerifyJSON(`{"maxMessages":100}`),
+					ghttp.RespondWithJSONEncoded(http.StatusOK, map[string]interface{}{
+						"receivedMessages": []map[string]interface{}{
+							{
+								"ackId": "ack-1",
+								"message": map[string]interface{}{
+									"messageId":  "1",
+									"data":       base64.StdEncoding.EncodeToString([]byte(auditEvent)),
+									"attributes": map[string]string{"account": "test-account"},
+								},
+							},
+						},
+					}),
+				))
+			})
+
+			It("returns the decoded messages", func() {
+				Expect(err).To(BeNil())
+				Expect(messages).To(HaveLen(1))
+				Expect(messages[0].ID).To(Equal("1"))
+				Expect(messages[0].AckID).To(Equal("ack-1"))
+				Expect(messages[0].Account).To(Equal("test-account"))
+				Expect(string(messages[0].Data)).To(Equal(auditEvent))
+			})
+		})
+	})
+
+	Describe("#Ack", func() {
+		BeforeEach(func() {
+			server.AppendHandlers(ghttp.CombineHandlers(
+				ghttp.VerifyRequest(http.MethodPost, "/v1/projects/test-project/subscriptions/test-subscription:acknowledge"),
+				ghttp.VerifyJSON(`{"ackIds":["ack-1","ack-2"]}`),
+				ghttp.RespondWith(http.StatusOK, "{}"),
+			))
+		})
+
+		JustBeforeEach(func() {
+			err = consumer.Ack(context.Background(), []Message{{AckID: "ack-1"}, {AckID: "ack-2"}})
+		})
+
+		It("acknowledges the messages", func() {
+			Expect(err).To(BeNil())
+			Expect(server.ReceivedRequests()).To(HaveLen(1))
+		})
+	})
+})
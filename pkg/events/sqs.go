@@ -0,0 +1,237 @@
+package events
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	sqsAPIVersion = "2012-11-05"
+	// Max number of messages SQS allows receiving at once.
+	sqsMaxMessages = 10
+	// Long poll for up to 20 seconds, the max SQS allows.
+	sqsWaitTimeSeconds = 20
+)
+
+// NewSQSConsumer returns a consumer that receives messages from an AWS SQS queue,
+// for example "https://sqs.us-east-1.amazonaws.com/123456789012/my-queue".
+// Requests are signed with the credentials in the environment variables
+// AWS_ACCESS_KEY_ID, AWS_SECRET_ACCESS_KEY and (optionally) AWS_SESSION_TOKEN.
+func NewSQSConsumer(queueURL, region string) Consumer {
+	return &sqsConsumer{
+		queueURL:     queueURL,
+		region:       region,
+		accessKeyID:  os.Getenv("AWS_ACCESS_KEY_ID"),
+		secretKey:    os.Getenv("AWS_SECRET_ACCESS_KEY"),
+		sessionToken: os.Getenv("AWS_SESSION_TOKEN"),
+		// Allow the long poll to finish before timing out.
+		httpClient: &http.Client{Timeout: (sqsWaitTimeSeconds + 10) * time.Second},
+	}
+}
+
+type sqsConsumer struct {
+	queueURL     string
+	region       string
+	accessKeyID  string
+	secretKey    string
+	sessionToken string
+	httpClient   *http.Client
+}
+
+type sqsReceiveMessageResponse struct {
+	Messages []struct {
+		MessageID         string `xml:"MessageId"`
+		ReceiptHandle     string `xml:"ReceiptHandle"`
+		Body              string `xml:"Body"`
+		MessageAttributes []struct {
+			Name  string `xml:"Name"`
+			Value struct {
+				StringValue string `xml:"StringValue"`
+			} `xml:"Value"`
+		} `xml:"MessageAttribute"`
+	} `xml:"ReceiveMessageResult>Message"`
+}
+
+func (c *sqsConsumer) Receive(ctx context.Context) ([]Message, error) {
+	form := url.Values{}
+	form.Set("Action", "ReceiveMessage")
+	form.Set("MaxNumberOfMessages", strconv.Itoa(sqsMaxMessages))
+	form.Set("WaitTimeSeconds", strconv.Itoa(sqsWaitTimeSeconds))
+	form.Set("MessageAttributeName.1", AttributeAccount)
+
+	b, err := c.do(ctx, form)
+	if err != nil {
+		return nil, err
+	}
+
+	rmr := sqsReceiveMessageResponse{}
+
+	err = xml.Unmarshal(b, &rmr)
+	if err != nil {
+		return nil, err
+	}
+
+	messages := []Message{}
+
+	for _, m := range rmr.Messages {
+		message := Message{
+			ID:    m.MessageID,
+			AckID: m.ReceiptHandle,
+			Data:  []byte(m.Body),
+		}
+
+		for _, attribute := range m.MessageAttributes {
+			if attribute.Name == AttributeAccount {
+				message.Account = attribute.Value.StringValue
+			}
+		}
+
+		messages = append(messages, message)
+	}
+
+	return messages, nil
+}
+
+// Ack deletes the messages from the queue.
+func (c *sqsConsumer) Ack(ctx context.Context, messages []Message) error {
+	for i := 0; i < len(messages); i += sqsMaxMessages {
+		form := url.Values{}
+		form.Set("Action", "DeleteMessageBatch")
+
+		for j, m := range messages[i:min(i+sqsMaxMessages, len(messages))] {
+			prefix := fmt.Sprintf("DeleteMessageBatchRequestEntry.%d.", j+1)
+			form.Set(prefix+"Id", strconv.Itoa(j))
+			form.Set(prefix+"ReceiptHandle", m.AckID)
+		}
+
+		_, err := c.do(ctx, form)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func min(a, b int) int {
+	if a < b {
+		return a
+	}
+
+	return b
+}
+
+// do makes a signed request to the SQS query API and returns the response body.
+func (c *sqsConsumer) do(ctx context.Context, form url.Values) ([]byte, error) {
+	form.Set("Version", sqsAPIVersion)
+	body := form.Encode()
+
+	req, err := http.NewRequest(http.MethodPost, c.queueURL, strings.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
+
+	req = req.WithContext(ctx)
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded; charset=utf-8")
+
+	if c.accessKeyID == "" || c.secretKey == "" {
+		return nil, errors.New("AWS_ACCESS_KEY_ID and AWS_SECRET_ACCESS_KEY must be set to use SQS")
+	}
+
+	c.sign(req, body, time.Now().UTC())
+
+	res, err := c.httpClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer res.Body.Close()
+
+	b, err := ioutil.ReadAll(res.Body)
+	if err != nil {
+		return nil, err
+	}
+
+	if res.StatusCode < 200 || res.StatusCode > 399 {
+		return nil, fmt.Errorf("error calling SQS: %s: %s", res.Status, string(b))
+	}
+
+	return b, nil
+}
+
+// sign adds AWS Signature Version 4 headers to the request.
+//
+// See https://docs.aws.amazon.com/general/latest/gr/sigv4_signing.html.
+func (c *sqsConsumer) sign(req *http.Request, body string, t time.Time) {
+	amzDate := t.Format("20060102T150405Z")
+	date := t.Format("20060102")
+	scope := fmt.Sprintf("%s/%s/sqs/aws4_request", date, c.region)
+
+	req.Header.Set("X-Amz-Date", amzDate)
+
+	if c.sessionToken != "" {
+		req.Header.Set("X-Amz-Security-Token", c.sessionToken)
+	}
+
+	signedHeaders := "content-type;host;x-amz-date"
+	canonicalHeaders := fmt.Sprintf("content-type:%s\nhost:%s\nx-amz-date:%s\n",
+		req.Header.Get("Content-Type"), req.URL.Host, amzDate)
+
+	if c.sessionToken != "" {
+		signedHeaders += ";x-amz-security-token"
+		canonicalHeaders += fmt.Sprintf("x-amz-security-token:%s\n", c.sessionToken)
+	}
+
+	path := req.URL.EscapedPath()
+	if path == "" {
+		path = "/"
+	}
+
+	canonicalRequest := strings.Join([]string{
+		req.Method,
+		path,
+		req.URL.RawQuery,
+		canonicalHeaders,
+		signedHeaders,
+		hashHex(body),
+	}, "\n")
+
+	stringToSign := strings.Join([]string{
+		"AWS4-HMAC-SHA256",
+		amzDate,
+		scope,
+		hashHex(canonicalRequest),
+	}, "\n")
+
+	key := hmacSHA256([]byte("AWS4"+c.secretKey), date)
+	key = hmacSHA256(key, c.region)
+	key = hmacSHA256(key, "sqs")
+	key = hmacSHA256(key, "aws4_request")
+	signature := hex.EncodeToString(hmacSHA256(key, stringToSign))
+
+	req.Header.Set("Authorization", fmt.Sprintf("AWS4-HMAC-SHA256 Credential=%s/%s, SignedHeaders=%s, Signature=%s",
+		c.accessKeyID, scope, signedHeaders, signature))
+}
+
+func hashHex(s string) string {
+	h := sha256.Sum256([]byte(s))
+	return hex.EncodeToString(h[:])
+}
+
+func hmacSHA256(key []byte, data string) []byte {
+	h := hmac.New(sha256.New, key)
+	h.Write([]byte(data))
+
+	return h.Sum(nil)
+}
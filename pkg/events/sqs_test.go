@@ -0,0 +1,134 @@
+package events_test
+
+import (
+	"context"
+	"net/http"
+	"os"
+	"strings"
+
+	. "github.com/billiford/go-clouddriver/pkg/events"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"github.com/onsi/gomega/ghttp"
+)
+
+const sqsReceiveMessageResponse = `<ReceiveMessageResponse>
+  <ReceiveMessageResult>
+    <Message>
+      <MessageId>1</MessageId>
+      <ReceiptHandle>receipt-1</ReceiptHandle>
+      <Body>{"verb":"create","objectRef":{"resource":"namespaces"}}</Body>
+      <MessageAttribute>
+        <Name>account</Name>
+        <Value>
+          <StringValue>test-account</StringValue>
+          <DataType>String</DataType>
+        </Value>
+      </MessageAttribute>
+    </Message>
+  </ReceiveMessageResult>
+</ReceiveMessageResponse>`
+
+var _ = Describe("SQSConsumer", func() {
+	var (
+		server   *ghttp.Server
+		consumer Consumer
+		err      error
+		messages []Message
+	)
+
+	BeforeEach(func() {
+		server = ghttp.NewServer()
+		os.Setenv("AWS_ACCESS_KEY_ID", "test-access-key-id")
+		os.Setenv("AWS_SECRET_ACCESS_KEY", "test-secret-access-key")
+	})
+
+	AfterEach(func() {
+		server.Close()
+		os.Unsetenv("AWS_ACCESS_KEY_ID")
+		os.Unsetenv("AWS_SECRET_ACCESS_KEY")
+	})
+
+	JustBeforeEach(func() {
+		consumer = NewSQSConsumer(server.URL()+"/123456789012/test-queue", "us-east-1")
+	})
+
+	Describe("#Receive", func() {
+		JustBeforeEach(func() {
+			messages, err = consumer.Receive(context.Background())
+		})
+
+		When("credentials are not set", func() {
+			BeforeEach(func() {
+				os.Unsetenv("AWS_ACCESS_KEY_ID")
+			})
+
+			It("returns an error", func() {
+				Expect(err).ToNot(BeNil())
+				Expect(err.Error()).To(Equal("AWS_ACCESS_KEY_ID and AWS_SECRET_ACCESS_KEY must be set to use SQS"))
+			})
+		})
+
+		When("the server returns an error", func() {
+			BeforeEach(func() {
+				server.AppendHandlers(ghttp.RespondWith(http.StatusForbidden, "denied"))
+			})
+
+			It("returns an error", func() {
+				Expect(err).ToNot(BeNil())
+				Expect(err.Error()).To(Equal("error calling SQS: 403 Forbidden: denied"))
+			})
+		})
+
+		When("it succeeds", func() {
+			BeforeEach(func() {
+				server.AppendHandlers(ghttp.CombineHandlers(
+					ghttp.VerifyRequest(http.MethodPost, "/123456789012/test-queue"),
+					func(w http.ResponseWriter, r *http.Request) {
+						Expect(r.Header.Get("Authorization")).To(HavePrefix("AWS4-HMAC-SHA256 Credential=test-access-key-id/"))
+						Expect(r.Header.Get("Authorization")).To(ContainSubstring("/us-east-1/sqs/aws4_request"))
+						Expect(r.Header.Get("X-Amz-Date")).ToNot(BeEmpty())
+						Expect(r.ParseForm()).To(Succeed())
+						Expect(r.PostForm.Get("Action")).To(Equal("ReceiveMessage"))
+						Expect(r.PostForm.Get("MessageAttributeName.1")).To(Equal("account"))
+					},
+					ghttp.RespondWith(http.StatusOK, sqsReceiveMessageResponse),
+				))
+			})
+
+			It("returns the messages", func() {
+				Expect(err).To(BeNil())
+				Expect(messages).To(HaveLen(1))
+				Expect(messages[0].ID).To(Equal("1"))
+				Expect(messages[0].AckID).To(Equal("receipt-1"))
+				Expect(messages[0].Account).To(Equal("test-account"))
+				Expect(string(messages[0].Data)).To(Equal(`{"verb":"create","objectRef":{"resource":"namespaces"}}`))
+			})
+		})
+	})
+
+	Describe("#Ack", func() {
+		BeforeEach(func() {
+			server.AppendHandlers(ghttp.CombineHandlers(
+				ghttp.VerifyRequest(http.MethodPost, "/123456789012/test-queue"),
+				func(w http.ResponseWriter, r *http.Request) {
+					Expect(r.ParseForm()).To(Succeed())
+					Expect(r.PostForm.Get("Action")).To(Equal("DeleteMessageBatch"))
+					Expect(r.PostForm.Get("DeleteMessageBatchRequestEntry.1.ReceiptHandle")).To(Equal("receipt-1"))
+					Expect(r.PostForm.Get("DeleteMessageBatchRequestEntry.2.ReceiptHandle")).To(Equal("receipt-2"))
+				},
+				ghttp.RespondWith(http.StatusOK, "<DeleteMessageBatchResponse/>"),
+			))
+		})
+
+		JustBeforeEach(func() {
+			err = consumer.Ack(context.Background(), []Message{{AckID: "receipt-1"}, {AckID: "receipt-2"}})
+		})
+
+		It("deletes the messages", func() {
+			Expect(err).To(BeNil())
+			Expect(server.ReceivedRequests()).To(HaveLen(1))
+			Expect(strings.Count(server.ReceivedRequests()[0].URL.Path, "test-queue")).To(Equal(1))
+		})
+	})
+})
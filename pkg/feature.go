@@ -0,0 +1,12 @@
+package clouddriver
+
+// Feature is a toggle of a feature, such as a stage Deck shows, set by
+// admins while clouddriver runs. Features are disabled until toggled.
+type Feature struct {
+	Name    string `json:"name" gorm:"primary_key"`
+	Enabled bool   `json:"enabled"`
+}
+
+func (Feature) TableName() string {
+	return "features"
+}
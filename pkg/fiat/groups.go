@@ -0,0 +1,262 @@
+package fiat
+
+import (
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/billiford/go-clouddriver/pkg/sql"
+	"github.com/gin-gonic/gin"
+)
+
+const (
+	GroupResolverInstanceKey    = `FiatGroupResolver`
+	GroupPermissionsInstanceKey = `FiatGroupPermissions`
+
+	identityKey         = `FiatIdentity`
+	headerSpinnakerUser = `X-Spinnaker-User`
+	// The groups of accounts and applications are listed again at least
+	// this often, like Fiat caches permissions.
+	groupPermissionsTTL = 20 * time.Second
+)
+
+// Authorizations of accounts and applications.
+var authorizations = []string{"READ", "WRITE", "EXECUTE"}
+
+// Identity is the user of a request and their groups.
+type Identity struct {
+	User   string
+	Groups []string
+}
+
+// GroupResolver resolves the user of a request and their groups, for
+// installs without Fiat.
+//
+//go:generate counterfeiter . GroupResolver
+type GroupResolver interface {
+	Resolve(*http.Request) (Identity, error)
+}
+
+// Permissions are the groups of each authorization to a resource, such as
+// {"READ": ["group1"]}.
+type Permissions map[string][]string
+
+// AuthorizeGroups returns what a user in groups may access of accounts and
+// applications, by name, like Fiat does: resources without groups can be
+// accessed by anyone, and otherwise users have the authorizations they are
+// in a group of.
+func AuthorizeGroups(user string, groups []string, accounts, applications map[string]Permissions) Response {
+	r := Response{
+		Name:         user,
+		Accounts:     []Account{},
+		Applications: []Application{},
+		Roles:        []Role{},
+	}
+
+	for _, group := range groups {
+		r.Roles = append(r.Roles, Role{Name: group, Source: "EXTERNAL"})
+	}
+
+	for name, p := range accounts {
+		r.Accounts = append(r.Accounts, Account{
+			Name:           name,
+			Authorizations: p.authorizations(groups),
+		})
+	}
+
+	for name, p := range applications {
+		r.Applications = append(r.Applications, Application{
+			Name:           name,
+			Authorizations: p.authorizations(groups),
+		})
+	}
+
+	return r
+}
+
+func (p Permissions) authorizations(groups []string) []string {
+	restricted := false
+
+	for _, g := range p {
+		if len(g) > 0 {
+			restricted = true
+			break
+		}
+	}
+
+	if !restricted {
+		return append([]string{}, authorizations...)
+	}
+
+	a := []string{}
+
+	for _, authorization := range authorizations {
+		if inGroup(p[authorization], groups) {
+			a = append(a, authorization)
+		}
+	}
+
+	return a
+}
+
+func inGroup(groups, userGroups []string) bool {
+	for _, g := range groups {
+		for _, ug := range userGroups {
+			if strings.EqualFold(g, ug) {
+				return true
+			}
+		}
+	}
+
+	return false
+}
+
+// Authorize returns what the user of a request may access, from Fiat or, if
+// a group resolver is set, from the groups it resolves and the groups of
+// accounts and applications. With a group resolver the user is the one it
+// resolves, not the one given.
+func Authorize(c *gin.Context, user string) (Response, error) {
+	gr := GroupResolverInstance(c)
+	if gr == nil {
+		return Instance(c).Authorize(user)
+	}
+
+	id, err := identify(c, gr)
+	if err != nil {
+		return Response{}, err
+	}
+
+	gp := GroupPermissionsInstance(c)
+	if gp == nil {
+		gp = NewGroupPermissions()
+	}
+
+	accounts, applications, err := gp.get(sql.ReadOnlyInstance(c))
+	if err != nil {
+		return Response{}, err
+	}
+
+	return AuthorizeGroups(id.User, id.Groups, accounts, applications), nil
+}
+
+// User returns the user of a request. With a group resolver every request
+// must have a valid token, and the user is the one of its token rather than
+// the X-Spinnaker-User header, which clients can set to anyone. Otherwise it
+// is the header, if the request has one.
+func User(c *gin.Context) (string, error) {
+	gr := GroupResolverInstance(c)
+	if gr == nil {
+		return c.GetHeader(headerSpinnakerUser), nil
+	}
+
+	id, err := identify(c, gr)
+	if err != nil {
+		return "", err
+	}
+
+	return id.User, nil
+}
+
+// identify resolves the identity of a request once, so its token is only
+// verified by the first middleware that needs it.
+func identify(c *gin.Context, gr GroupResolver) (Identity, error) {
+	if v, ok := c.Get(identityKey); ok {
+		return v.(Identity), nil
+	}
+
+	id, err := gr.Resolve(c.Request)
+	if err != nil {
+		return Identity{}, err
+	}
+
+	c.Set(identityKey, id)
+
+	return id, nil
+}
+
+// GroupPermissions caches the groups of accounts and applications users are
+// authorized from with a group resolver, so requests do not list them. They
+// are listed again when the provider version changes, such as when the
+// permissions of an account do, and otherwise after groupPermissionsTTL.
+type GroupPermissions struct {
+	mux          sync.Mutex
+	version      int64
+	fetchedAt    time.Time
+	accounts     map[string]Permissions
+	applications map[string]Permissions
+}
+
+// NewGroupPermissions returns an empty GroupPermissions.
+func NewGroupPermissions() *GroupPermissions {
+	return &GroupPermissions{}
+}
+
+func (g *GroupPermissions) get(sc sql.Client) (map[string]Permissions, map[string]Permissions, error) {
+	version, err := sc.GetKubernetesProviderVersion()
+	if err != nil {
+		return nil, nil, err
+	}
+
+	g.mux.Lock()
+	if g.accounts != nil && g.version == version && time.Since(g.fetchedAt) < groupPermissionsTTL {
+		accounts, applications := g.accounts, g.applications
+		g.mux.Unlock()
+
+		return accounts, applications, nil
+	}
+	g.mux.Unlock()
+
+	// List outside the lock, so requests are not held up by one another.
+	providers, err := sc.ListKubernetesProvidersAndPermissions()
+	if err != nil {
+		return nil, nil, err
+	}
+
+	accounts := map[string]Permissions{}
+	for _, p := range providers {
+		accounts[p.Name] = Permissions{
+			"READ":    p.Permissions.Read,
+			"WRITE":   p.Permissions.Write,
+			"EXECUTE": p.Permissions.Execute,
+		}
+	}
+
+	apps, err := sc.ListApplications()
+	if err != nil {
+		return nil, nil, err
+	}
+
+	applications := map[string]Permissions{}
+	for _, a := range apps {
+		applications[a.Name] = Permissions{
+			"READ":  a.Permissions.READ,
+			"WRITE": a.Permissions.WRITE,
+		}
+	}
+
+	g.mux.Lock()
+	g.version, g.fetchedAt = version, time.Now()
+	g.accounts, g.applications = accounts, applications
+	g.mux.Unlock()
+
+	return accounts, applications, nil
+}
+
+// GroupResolverInstance returns the group resolver of the request, or nil if
+// users' groups are read from Fiat.
+func GroupResolverInstance(c *gin.Context) GroupResolver {
+	v, _ := c.Get(GroupResolverInstanceKey)
+	gr, _ := v.(GroupResolver)
+
+	return gr
+}
+
+// GroupPermissionsInstance returns the cached groups of accounts and
+// applications of the request, or nil if they are not cached.
+func GroupPermissionsInstance(c *gin.Context) *GroupPermissions {
+	v, _ := c.Get(GroupPermissionsInstanceKey)
+	gp, _ := v.(*GroupPermissions)
+
+	return gp
+}
@@ -0,0 +1,228 @@
+package fiat_test
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+
+	clouddriver "github.com/billiford/go-clouddriver/pkg"
+	. "github.com/billiford/go-clouddriver/pkg/fiat"
+	"github.com/billiford/go-clouddriver/pkg/fiat/fiatfakes"
+	"github.com/billiford/go-clouddriver/pkg/kubernetes"
+	"github.com/billiford/go-clouddriver/pkg/sql"
+	"github.com/billiford/go-clouddriver/pkg/sql/sqlfakes"
+	"github.com/gin-gonic/gin"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Groups", func() {
+	Describe("#AuthorizeGroups", func() {
+		var r Response
+
+		BeforeEach(func() {
+			r = AuthorizeGroups("test-user", []string{"Developers"}, map[string]Permissions{
+				"open-account": {},
+				"dev-account": {
+					"READ":  {"developers", "admins"},
+					"WRITE": {"admins"},
+				},
+				"prod-account": {
+					"READ": {"admins"},
+				},
+			}, map[string]Permissions{
+				"test-app": {
+					"READ":  {"developers"},
+					"WRITE": {"developers"},
+				},
+			})
+		})
+
+		It("authorizes the groups of the user like Fiat", func() {
+			Expect(r.Name).To(Equal("test-user"))
+			Expect(r.Roles).To(Equal([]Role{{Name: "Developers", Source: "EXTERNAL"}}))
+			Expect(r.Accounts).To(ConsistOf(
+				Account{Name: "open-account", Authorizations: []string{"READ", "WRITE", "EXECUTE"}},
+				Account{Name: "dev-account", Authorizations: []string{"READ"}},
+				Account{Name: "prod-account", Authorizations: []string{}},
+			))
+			Expect(r.Applications).To(Equal([]Application{
+				{Name: "test-app", Authorizations: []string{"READ", "WRITE"}},
+			}))
+			Expect(r.Admin).To(BeFalse())
+		})
+	})
+
+	Describe("#Authorize", func() {
+		var (
+			c                 *gin.Context
+			fakeFiatClient    *fiatfakes.FakeClient
+			fakeGroupResolver *fiatfakes.FakeGroupResolver
+			fakeSQLClient     *sqlfakes.FakeClient
+			r                 Response
+			err               error
+		)
+
+		BeforeEach(func() {
+			gin.SetMode(gin.ReleaseMode)
+			c, _ = gin.CreateTestContext(httptest.NewRecorder())
+			c.Request, _ = http.NewRequest(http.MethodGet, "/", nil)
+			fakeFiatClient = &fiatfakes.FakeClient{}
+			fakeFiatClient.AuthorizeReturns(Response{Name: "from-fiat"}, nil)
+			c.Set(ClientInstanceKey, fakeFiatClient)
+			fakeGroupResolver = &fiatfakes.FakeGroupResolver{}
+			fakeGroupResolver.ResolveReturns(Identity{User: "token-user", Groups: []string{"developers"}}, nil)
+			c.Set(GroupResolverInstanceKey, fakeGroupResolver)
+			fakeSQLClient = &sqlfakes.FakeClient{}
+			fakeSQLClient.ListKubernetesProvidersAndPermissionsReturns([]kubernetes.Provider{
+				{
+					Name: "test-account",
+					Permissions: kubernetes.ProviderPermissions{
+						Read:    []string{"developers"},
+						Execute: []string{"deployers"},
+					},
+				},
+			}, nil)
+			fakeSQLClient.ListApplicationsReturns([]clouddriver.Application{
+				{
+					Name:        "test-app",
+					Permissions: clouddriver.Permissions{READ: []string{"developers"}},
+				},
+			}, nil)
+			c.Set(sql.ReadOnlyClientInstanceKey, fakeSQLClient)
+		})
+
+		JustBeforeEach(func() {
+			r, err = Authorize(c, "test-user")
+		})
+
+		When("there is no group resolver", func() {
+			BeforeEach(func() {
+				c.Set(GroupResolverInstanceKey, nil)
+			})
+
+			It("asks Fiat", func() {
+				Expect(err).To(BeNil())
+				Expect(r.Name).To(Equal("from-fiat"))
+				Expect(fakeFiatClient.AuthorizeArgsForCall(0)).To(Equal("test-user"))
+			})
+		})
+
+		When("the groups cannot be resolved", func() {
+			BeforeEach(func() {
+				fakeGroupResolver.ResolveReturns(Identity{}, errors.New("no OIDC token found"))
+			})
+
+			It("returns an error", func() {
+				Expect(err).To(MatchError("no OIDC token found"))
+			})
+		})
+
+		When("listing accounts returns an error", func() {
+			BeforeEach(func() {
+				fakeSQLClient.ListKubernetesProvidersAndPermissionsReturns(nil, errors.New("error listing providers"))
+			})
+
+			It("returns an error", func() {
+				Expect(err).To(MatchError("error listing providers"))
+			})
+		})
+
+		When("the groups of accounts and applications are cached", func() {
+			var gp *GroupPermissions
+
+			BeforeEach(func() {
+				gp = NewGroupPermissions()
+				c.Set(GroupPermissionsInstanceKey, gp)
+
+				_, err = Authorize(c, "test-user")
+				Expect(err).To(BeNil())
+			})
+
+			It("does not list them again", func() {
+				Expect(err).To(BeNil())
+				Expect(fakeSQLClient.ListKubernetesProvidersAndPermissionsCallCount()).To(Equal(1))
+				Expect(fakeSQLClient.ListApplicationsCallCount()).To(Equal(1))
+				Expect(r.Accounts).To(Equal([]Account{{Name: "test-account", Authorizations: []string{"READ"}}}))
+			})
+
+			When("the provider version changes", func() {
+				BeforeEach(func() {
+					fakeSQLClient.GetKubernetesProviderVersionReturns(2, nil)
+				})
+
+				It("lists them again", func() {
+					Expect(err).To(BeNil())
+					Expect(fakeSQLClient.ListKubernetesProvidersAndPermissionsCallCount()).To(Equal(2))
+					Expect(fakeSQLClient.ListApplicationsCallCount()).To(Equal(2))
+				})
+			})
+		})
+
+		It("authorizes the groups of the user of the token", func() {
+			Expect(err).To(BeNil())
+			Expect(fakeFiatClient.AuthorizeCallCount()).To(BeZero())
+			Expect(fakeGroupResolver.ResolveArgsForCall(0)).To(BeIdenticalTo(c.Request))
+			Expect(r.Name).To(Equal("token-user"))
+			Expect(r.Accounts).To(Equal([]Account{{Name: "test-account", Authorizations: []string{"READ"}}}))
+			Expect(r.Applications).To(Equal([]Application{{Name: "test-app", Authorizations: []string{"READ"}}}))
+		})
+	})
+
+	Describe("#User", func() {
+		var (
+			c                 *gin.Context
+			fakeGroupResolver *fiatfakes.FakeGroupResolver
+			user              string
+			err               error
+		)
+
+		BeforeEach(func() {
+			gin.SetMode(gin.ReleaseMode)
+			c, _ = gin.CreateTestContext(httptest.NewRecorder())
+			c.Request, _ = http.NewRequest(http.MethodGet, "/", nil)
+			c.Request.Header.Set("X-Spinnaker-User", "header-user")
+			fakeGroupResolver = &fiatfakes.FakeGroupResolver{}
+			fakeGroupResolver.ResolveReturns(Identity{User: "token-user"}, nil)
+			c.Set(GroupResolverInstanceKey, fakeGroupResolver)
+		})
+
+		JustBeforeEach(func() {
+			user, err = User(c)
+		})
+
+		When("there is no group resolver", func() {
+			BeforeEach(func() {
+				c.Set(GroupResolverInstanceKey, nil)
+			})
+
+			It("returns the user of the header", func() {
+				Expect(err).To(BeNil())
+				Expect(user).To(Equal("header-user"))
+			})
+		})
+
+		When("the request has no valid token", func() {
+			BeforeEach(func() {
+				fakeGroupResolver.ResolveReturns(Identity{}, errors.New("no OIDC token found"))
+			})
+
+			It("returns an error", func() {
+				Expect(err).To(MatchError("no OIDC token found"))
+			})
+		})
+
+		It("returns the user of the token, not the header", func() {
+			Expect(err).To(BeNil())
+			Expect(user).To(Equal("token-user"))
+		})
+
+		When("the user is read again", func() {
+			It("does not resolve the token again", func() {
+				_, err = User(c)
+				Expect(err).To(BeNil())
+				Expect(fakeGroupResolver.ResolveCallCount()).To(Equal(1))
+			})
+		})
+	})
+})
@@ -0,0 +1,333 @@
+package fiat
+
+import (
+	"crypto"
+	"crypto/rsa"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"math/big"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	defaultOIDCHeader = "Authorization"
+	defaultOIDCClaim  = "groups"
+	// Keys are fetched again for a token signed with an unknown key at most
+	// this often, so bad tokens do not flood the identity provider.
+	jwksRefreshInterval = time.Minute
+)
+
+var (
+	errNoToken = errors.New("no OIDC token found")
+	errNoUser  = errors.New("OIDC token has no user claim")
+)
+
+// OIDCConfig configures reading the groups of users from the claims of the
+// OIDC ID or access token forwarded with their requests.
+type OIDCConfig struct {
+	// JWKSURL serves the keys tokens are signed with, such as
+	// https://accounts.example.com/.well-known/jwks.json.
+	JWKSURL string
+	// Header is the header of the token, with or without "Bearer ".
+	// Defaults to Authorization.
+	Header string
+	// Claim lists the groups of the user, as an array or a string separated
+	// by commas or spaces. Defaults to groups.
+	Claim string
+	// UserClaim is the user of the token. Defaults to email, or sub for
+	// tokens without one.
+	UserClaim string
+	// Issuer and Audience, if set, must be the iss and one of the aud of
+	// tokens.
+	Issuer   string
+	Audience string
+}
+
+// NewOIDCGroupResolver returns a GroupResolver that reads the user and groups
+// from the claims of RS256 signed tokens.
+func NewOIDCGroupResolver(config OIDCConfig) GroupResolver {
+	if config.Header == "" {
+		config.Header = defaultOIDCHeader
+	}
+
+	if config.Claim == "" {
+		config.Claim = defaultOIDCClaim
+	}
+
+	return &oidcGroupResolver{
+		config: config,
+		client: &http.Client{Timeout: 10 * time.Second},
+		keys:   map[string]*rsa.PublicKey{},
+	}
+}
+
+type oidcGroupResolver struct {
+	config OIDCConfig
+	client *http.Client
+
+	mux       sync.Mutex
+	keys      map[string]*rsa.PublicKey
+	fetchedAt time.Time
+	// fetching is closed when the keys being fetched, if any, are set.
+	fetching chan struct{}
+}
+
+type jwtHeader struct {
+	Alg string `json:"alg"`
+	Kid string `json:"kid"`
+}
+
+// Resolve verifies the token of a request and returns the user and groups
+// of its claims.
+func (o *oidcGroupResolver) Resolve(r *http.Request) (Identity, error) {
+	token := strings.TrimSpace(r.Header.Get(o.config.Header))
+	if len(token) > 7 && strings.EqualFold(token[:7], "bearer ") {
+		token = strings.TrimSpace(token[7:])
+	}
+
+	if token == "" {
+		return Identity{}, errNoToken
+	}
+
+	parts := strings.Split(token, ".")
+	if len(parts) != 3 {
+		return Identity{}, errors.New("OIDC token is not a JWT")
+	}
+
+	h := jwtHeader{}
+
+	err := decodeSegment(parts[0], &h)
+	if err != nil {
+		return Identity{}, fmt.Errorf("error decoding OIDC token header: %w", err)
+	}
+
+	if h.Alg != "RS256" {
+		return Identity{}, fmt.Errorf("OIDC token algorithm %q is not supported, must be RS256", h.Alg)
+	}
+
+	key, err := o.key(h.Kid)
+	if err != nil {
+		return Identity{}, err
+	}
+
+	sig, err := base64.RawURLEncoding.DecodeString(parts[2])
+	if err != nil {
+		return Identity{}, fmt.Errorf("error decoding OIDC token signature: %w", err)
+	}
+
+	digest := sha256.Sum256([]byte(parts[0] + "." + parts[1]))
+
+	err = rsa.VerifyPKCS1v15(key, crypto.SHA256, digest[:], sig)
+	if err != nil {
+		return Identity{}, errors.New("OIDC token signature is invalid")
+	}
+
+	claims := map[string]interface{}{}
+
+	err = decodeSegment(parts[1], &claims)
+	if err != nil {
+		return Identity{}, fmt.Errorf("error decoding OIDC token claims: %w", err)
+	}
+
+	err = o.validate(claims, time.Now())
+	if err != nil {
+		return Identity{}, err
+	}
+
+	user, err := o.user(claims)
+	if err != nil {
+		return Identity{}, err
+	}
+
+	return Identity{User: user, Groups: groupsClaim(claims[o.config.Claim])}, nil
+}
+
+// user returns the user claim of a token.
+func (o *oidcGroupResolver) user(claims map[string]interface{}) (string, error) {
+	names := []string{o.config.UserClaim}
+	if o.config.UserClaim == "" {
+		names = []string{"email", "sub"}
+	}
+
+	for _, name := range names {
+		if user, ok := claims[name].(string); ok && user != "" {
+			return user, nil
+		}
+	}
+
+	return "", errNoUser
+}
+
+// validate returns an error if a token has expired or is not of the issuer
+// and audience.
+func (o *oidcGroupResolver) validate(claims map[string]interface{}, now time.Time) error {
+	exp, ok := claims["exp"].(float64)
+	if !ok || now.After(time.Unix(int64(exp), 0)) {
+		return errors.New("OIDC token has expired")
+	}
+
+	if o.config.Issuer != "" && claims["iss"] != o.config.Issuer {
+		return fmt.Errorf("OIDC token is not issued by %s", o.config.Issuer)
+	}
+
+	if o.config.Audience != "" && !contains(groupsClaim(claims["aud"]), o.config.Audience) {
+		return fmt.Errorf("OIDC token is not for audience %s", o.config.Audience)
+	}
+
+	return nil
+}
+
+// key returns the key of an ID, fetching the keys again if it is unknown.
+// The keys are fetched without holding the lock, so requests with known keys
+// are not held up, and requests for an unknown key wait for the one fetch.
+func (o *oidcGroupResolver) key(kid string) (*rsa.PublicKey, error) {
+	o.mux.Lock()
+
+	if key, ok := o.keys[kid]; ok {
+		o.mux.Unlock()
+		return key, nil
+	}
+
+	fetching, fetch := o.fetching, false
+	if fetching == nil && time.Since(o.fetchedAt) > jwksRefreshInterval {
+		fetching, fetch = make(chan struct{}), true
+		o.fetching = fetching
+	}
+
+	o.mux.Unlock()
+
+	if fetch {
+		keys, err := o.fetchKeys()
+
+		o.mux.Lock()
+		if err == nil {
+			o.keys = keys
+			o.fetchedAt = time.Now()
+		}
+		o.fetching = nil
+		o.mux.Unlock()
+
+		close(fetching)
+
+		if err != nil {
+			return nil, err
+		}
+	} else if fetching != nil {
+		<-fetching
+	}
+
+	o.mux.Lock()
+	key, ok := o.keys[kid]
+	o.mux.Unlock()
+
+	if ok {
+		return key, nil
+	}
+
+	return nil, fmt.Errorf("OIDC token is signed with unknown key %q", kid)
+}
+
+type jwks struct {
+	Keys []struct {
+		Kty string `json:"kty"`
+		Kid string `json:"kid"`
+		N   string `json:"n"`
+		E   string `json:"e"`
+	} `json:"keys"`
+}
+
+func (o *oidcGroupResolver) fetchKeys() (map[string]*rsa.PublicKey, error) {
+	res, err := o.client.Get(o.config.JWKSURL)
+	if err != nil {
+		return nil, fmt.Errorf("error fetching OIDC keys: %w", err)
+	}
+	defer res.Body.Close()
+
+	if res.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("error fetching OIDC keys: %s", res.Status)
+	}
+
+	b, err := ioutil.ReadAll(res.Body)
+	if err != nil {
+		return nil, fmt.Errorf("error fetching OIDC keys: %w", err)
+	}
+
+	set := jwks{}
+
+	err = json.Unmarshal(b, &set)
+	if err != nil {
+		return nil, fmt.Errorf("error decoding OIDC keys: %w", err)
+	}
+
+	keys := map[string]*rsa.PublicKey{}
+
+	for _, k := range set.Keys {
+		if k.Kty != "RSA" {
+			continue
+		}
+
+		n, err := base64.RawURLEncoding.DecodeString(k.N)
+		if err != nil {
+			continue
+		}
+
+		e, err := base64.RawURLEncoding.DecodeString(k.E)
+		if err != nil {
+			continue
+		}
+
+		keys[k.Kid] = &rsa.PublicKey{
+			N: new(big.Int).SetBytes(n),
+			E: int(new(big.Int).SetBytes(e).Int64()),
+		}
+	}
+
+	return keys, nil
+}
+
+func decodeSegment(s string, v interface{}) error {
+	b, err := base64.RawURLEncoding.DecodeString(s)
+	if err != nil {
+		return err
+	}
+
+	return json.Unmarshal(b, v)
+}
+
+// groupsClaim returns the groups of a claim, an array or a string separated
+// by commas or spaces.
+func groupsClaim(v interface{}) []string {
+	groups := []string{}
+
+	switch c := v.(type) {
+	case string:
+		groups = append(groups, strings.FieldsFunc(c, func(r rune) bool {
+			return r == ',' || r == ' '
+		})...)
+	case []interface{}:
+		for _, g := range c {
+			if s, ok := g.(string); ok {
+				groups = append(groups, s)
+			}
+		}
+	}
+
+	return groups
+}
+
+func contains(s []string, e string) bool {
+	for _, a := range s {
+		if a == e {
+			return true
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,251 @@
+package fiat_test
+
+import (
+	"crypto"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"math/big"
+	"net/http"
+	"strings"
+	"time"
+
+	. "github.com/billiford/go-clouddriver/pkg/fiat"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"github.com/onsi/gomega/ghttp"
+)
+
+var _ = Describe("OIDC", func() {
+	var (
+		fakeServer *ghttp.Server
+		key        *rsa.PrivateKey
+		gr         GroupResolver
+		req        *http.Request
+		header     map[string]interface{}
+		claims     map[string]interface{}
+		id         Identity
+		err        error
+	)
+
+	encode := func(v interface{}) string {
+		b, _ := json.Marshal(v)
+		return base64.RawURLEncoding.EncodeToString(b)
+	}
+
+	sign := func(k *rsa.PrivateKey) string {
+		s := encode(header) + "." + encode(claims)
+		digest := sha256.Sum256([]byte(s))
+		sig, _ := rsa.SignPKCS1v15(rand.Reader, k, crypto.SHA256, digest[:])
+
+		return s + "." + base64.RawURLEncoding.EncodeToString(sig)
+	}
+
+	BeforeEach(func() {
+		key, err = rsa.GenerateKey(rand.Reader, 2048)
+		Expect(err).To(BeNil())
+
+		fakeServer = ghttp.NewServer()
+		fakeServer.AppendHandlers(ghttp.CombineHandlers(
+			ghttp.VerifyRequest(http.MethodGet, "/keys"),
+			ghttp.RespondWithJSONEncoded(http.StatusOK, map[string]interface{}{
+				"keys": []map[string]string{
+					{
+						"kty": "RSA",
+						"kid": "test-kid",
+						"n":   base64.RawURLEncoding.EncodeToString(key.N.Bytes()),
+						"e":   base64.RawURLEncoding.EncodeToString(big.NewInt(int64(key.E)).Bytes()),
+					},
+				},
+			}),
+		))
+
+		gr = NewOIDCGroupResolver(OIDCConfig{
+			JWKSURL:  fakeServer.URL() + "/keys",
+			Issuer:   "https://accounts.example.com",
+			Audience: "spinnaker",
+		})
+		header = map[string]interface{}{"alg": "RS256", "kid": "test-kid"}
+		claims = map[string]interface{}{
+			"iss":    "https://accounts.example.com",
+			"aud":    []string{"spinnaker", "other"},
+			"exp":    time.Now().Add(time.Hour).Unix(),
+			"sub":    "test-sub",
+			"email":  "test-user@example.com",
+			"groups": []string{"developers", "admins"},
+		}
+		req, _ = http.NewRequest(http.MethodGet, "/", nil)
+	})
+
+	AfterEach(func() {
+		fakeServer.Close()
+	})
+
+	JustBeforeEach(func() {
+		id, err = gr.Resolve(req)
+	})
+
+	When("the request has no token", func() {
+		It("returns an error", func() {
+			Expect(err).To(MatchError("no OIDC token found"))
+		})
+	})
+
+	When("the token is valid", func() {
+		BeforeEach(func() {
+			req.Header.Set("Authorization", "Bearer "+sign(key))
+		})
+
+		It("returns the user and groups of the token", func() {
+			Expect(err).To(BeNil())
+			Expect(id.User).To(Equal("test-user@example.com"))
+			Expect(id.Groups).To(Equal([]string{"developers", "admins"}))
+		})
+
+		When("the token has no email", func() {
+			BeforeEach(func() {
+				delete(claims, "email")
+				req.Header.Set("Authorization", "Bearer "+sign(key))
+			})
+
+			It("returns the subject as the user", func() {
+				Expect(err).To(BeNil())
+				Expect(id.User).To(Equal("test-sub"))
+			})
+		})
+
+		When("the token has no user", func() {
+			BeforeEach(func() {
+				delete(claims, "email")
+				delete(claims, "sub")
+				req.Header.Set("Authorization", "Bearer "+sign(key))
+			})
+
+			It("returns an error", func() {
+				Expect(err).To(MatchError("OIDC token has no user claim"))
+			})
+		})
+
+		When("another token is verified", func() {
+			It("does not fetch the keys again", func() {
+				_, err = gr.Resolve(req)
+				Expect(err).To(BeNil())
+				Expect(fakeServer.ReceivedRequests()).To(HaveLen(1))
+			})
+		})
+	})
+
+	When("the groups claim is a string", func() {
+		BeforeEach(func() {
+			claims["groups"] = "developers, admins"
+			req.Header.Set("Authorization", sign(key))
+		})
+
+		It("splits the groups", func() {
+			Expect(err).To(BeNil())
+			Expect(id.Groups).To(Equal([]string{"developers", "admins"}))
+		})
+	})
+
+	When("the token is not a JWT", func() {
+		BeforeEach(func() {
+			req.Header.Set("Authorization", "Bearer test-token")
+		})
+
+		It("returns an error", func() {
+			Expect(err).To(MatchError("OIDC token is not a JWT"))
+		})
+	})
+
+	When("the token is not signed with RS256", func() {
+		BeforeEach(func() {
+			header["alg"] = "none"
+			req.Header.Set("Authorization", "Bearer "+sign(key))
+		})
+
+		It("returns an error", func() {
+			Expect(err).To(MatchError(`OIDC token algorithm "none" is not supported, must be RS256`))
+		})
+	})
+
+	When("the token is signed by another key", func() {
+		BeforeEach(func() {
+			other, _ := rsa.GenerateKey(rand.Reader, 2048)
+			req.Header.Set("Authorization", "Bearer "+sign(other))
+		})
+
+		It("returns an error", func() {
+			Expect(err).To(MatchError("OIDC token signature is invalid"))
+		})
+	})
+
+	When("the claims of a signed token are changed", func() {
+		BeforeEach(func() {
+			token := sign(key)
+			parts := strings.Split(token, ".")
+			claims["groups"] = []string{"admins"}
+			req.Header.Set("Authorization", "Bearer "+parts[0]+"."+encode(claims)+"."+parts[2])
+		})
+
+		It("returns an error", func() {
+			Expect(err).To(MatchError("OIDC token signature is invalid"))
+		})
+	})
+
+	When("the token is signed with an unknown key", func() {
+		BeforeEach(func() {
+			header["kid"] = "unknown-kid"
+			req.Header.Set("Authorization", "Bearer "+sign(key))
+		})
+
+		It("returns an error", func() {
+			Expect(err).To(MatchError(`OIDC token is signed with unknown key "unknown-kid"`))
+		})
+	})
+
+	When("the token has expired", func() {
+		BeforeEach(func() {
+			claims["exp"] = time.Now().Add(-time.Minute).Unix()
+			req.Header.Set("Authorization", "Bearer "+sign(key))
+		})
+
+		It("returns an error", func() {
+			Expect(err).To(MatchError("OIDC token has expired"))
+		})
+	})
+
+	When("the token is of another issuer", func() {
+		BeforeEach(func() {
+			claims["iss"] = "https://other.example.com"
+			req.Header.Set("Authorization", "Bearer "+sign(key))
+		})
+
+		It("returns an error", func() {
+			Expect(err).To(MatchError("OIDC token is not issued by https://accounts.example.com"))
+		})
+	})
+
+	When("the token is for another audience", func() {
+		BeforeEach(func() {
+			claims["aud"] = "other"
+			req.Header.Set("Authorization", "Bearer "+sign(key))
+		})
+
+		It("returns an error", func() {
+			Expect(err).To(MatchError("OIDC token is not for audience spinnaker"))
+		})
+	})
+
+	When("the keys cannot be fetched", func() {
+		BeforeEach(func() {
+			fakeServer.SetHandler(0, ghttp.RespondWith(http.StatusInternalServerError, nil))
+			req.Header.Set("Authorization", "Bearer "+sign(key))
+		})
+
+		It("returns an error", func() {
+			Expect(err).To(MatchError("error fetching OIDC keys: 500 Internal Server Error"))
+		})
+	})
+})
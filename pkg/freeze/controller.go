@@ -0,0 +1,238 @@
+// Package freeze reads change freeze windows, during which operations
+// against accounts and namespaces are rejected, such as over a weekend or
+// a holiday.
+package freeze
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+const (
+	ControllerInstanceKey = "FreezeController"
+	// HeaderOverride overrides freezes that allow it. Its value is the
+	// reason for the override, which is logged.
+	HeaderOverride = "X-Spinnaker-Freeze-Override"
+)
+
+var (
+	defaultConfigPath = "/opt/spinnaker/kubernetes/freezes.json"
+)
+
+//go:generate counterfeiter . Controller
+type Controller interface {
+	ListFreezes(time.Time) []Status
+	ActiveFreeze(string, string, time.Time) (Status, bool)
+}
+
+// Config lists the freeze windows.
+//
+//	{
+//	  "freezes": [
+//	    {
+//	      "name": "weekend",
+//	      "message": "No deploys to prod over the weekend.",
+//	      "schedule": "0 17 * * FRI",
+//	      "duration": "63h",
+//	      "timeZone": "America/Chicago",
+//	      "accounts": ["prod-account"],
+//	      "allowOverride": true
+//	    }
+//	  ]
+//	}
+type Config struct {
+	Freezes []Freeze `json:"freezes"`
+}
+
+// Freeze is a window starting at each time its cron schedule matches and
+// lasting for its duration. It applies to all accounts and namespaces
+// unless they are listed.
+type Freeze struct {
+	Name          string   `json:"name"`
+	Message       string   `json:"message,omitempty"`
+	Schedule      string   `json:"schedule"`
+	Duration      string   `json:"duration"`
+	TimeZone      string   `json:"timeZone,omitempty"`
+	Accounts      []string `json:"accounts,omitempty"`
+	Namespaces    []string `json:"namespaces,omitempty"`
+	AllowOverride bool     `json:"allowOverride,omitempty"`
+
+	schedule *Schedule
+	duration time.Duration
+}
+
+// Status is a freeze along with its current window if it is active, or
+// else its next window.
+type Status struct {
+	Freeze
+	Active bool       `json:"active"`
+	Start  *time.Time `json:"start,omitempty"`
+	End    *time.Time `json:"end,omitempty"`
+}
+
+// Applies returns true if the freeze applies to a namespace of an account.
+func (f Freeze) Applies(account, namespace string) bool {
+	return matches(f.Accounts, account) && matches(f.Namespaces, namespace)
+}
+
+func matches(values []string, s string) bool {
+	if len(values) == 0 {
+		return true
+	}
+
+	for _, v := range values {
+		if v == s {
+			return true
+		}
+	}
+
+	return false
+}
+
+// status returns the window of the freeze at t.
+func (f Freeze) status(t time.Time) Status {
+	s := Status{Freeze: f}
+
+	// A window started at or before t is active if it has not ended yet.
+	start := f.schedule.Next(t.Add(-f.duration).Add(time.Nanosecond))
+	if start.IsZero() {
+		return s
+	}
+
+	if !start.After(t) {
+		s.Active = true
+
+		// Windows may overlap, the freeze lasts until the last one ends.
+		for {
+			next := f.schedule.Next(start.Add(time.Nanosecond))
+			if next.IsZero() || next.After(t) {
+				break
+			}
+
+			start = next
+		}
+	}
+
+	end := start.Add(f.duration)
+	s.Start = &start
+	s.End = &end
+
+	return s
+}
+
+// NewDefaultController reads freezes from /opt/spinnaker/kubernetes/freezes.json.
+// Freezes are optional, so if the file does not exist nothing is frozen.
+func NewDefaultController() (Controller, error) {
+	if _, err := os.Stat(defaultConfigPath); os.IsNotExist(err) {
+		return &controller{}, nil
+	}
+
+	return NewController(defaultConfigPath)
+}
+
+// NewController reads freezes from a JSON file.
+func NewController(path string) (Controller, error) {
+	b, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	config := Config{}
+
+	err = json.Unmarshal(b, &config)
+	if err != nil {
+		return nil, err
+	}
+
+	return NewControllerWithConfig(config)
+}
+
+// NewControllerWithConfig returns a controller for the freezes of config.
+func NewControllerWithConfig(config Config) (Controller, error) {
+	c := &controller{}
+
+	for _, f := range config.Freezes {
+		if f.Name == "" {
+			return nil, fmt.Errorf("no \"name\" found in freeze with schedule %q", f.Schedule)
+		}
+
+		loc := time.UTC
+
+		if f.TimeZone != "" {
+			var err error
+
+			loc, err = time.LoadLocation(f.TimeZone)
+			if err != nil {
+				return nil, fmt.Errorf("error loading time zone of freeze %s: %w", f.Name, err)
+			}
+		}
+
+		schedule, err := ParseSchedule(f.Schedule, loc)
+		if err != nil {
+			return nil, fmt.Errorf("error parsing schedule of freeze %s: %w", f.Name, err)
+		}
+
+		duration, err := time.ParseDuration(f.Duration)
+		if err != nil || duration <= 0 {
+			return nil, fmt.Errorf("invalid duration %q of freeze %s", f.Duration, f.Name)
+		}
+
+		f.schedule = schedule
+		f.duration = duration
+		c.freezes = append(c.freezes, f)
+	}
+
+	return c, nil
+}
+
+type controller struct {
+	freezes []Freeze
+}
+
+// ListFreezes returns the status of all freezes at t.
+func (c *controller) ListFreezes(t time.Time) []Status {
+	statuses := []Status{}
+
+	for _, f := range c.freezes {
+		statuses = append(statuses, f.status(t))
+	}
+
+	return statuses
+}
+
+// ActiveFreeze returns an active freeze of a namespace of an account at t,
+// preferring one that cannot be overridden.
+func (c *controller) ActiveFreeze(account, namespace string, t time.Time) (Status, bool) {
+	active := Status{}
+	found := false
+
+	for _, f := range c.freezes {
+		if !f.Applies(account, namespace) {
+			continue
+		}
+
+		s := f.status(t)
+		if !s.Active {
+			continue
+		}
+
+		if !s.AllowOverride {
+			return s, true
+		}
+
+		if !found {
+			active, found = s, true
+		}
+	}
+
+	return active, found
+}
+
+func ControllerInstance(c *gin.Context) Controller {
+	return c.MustGet(ControllerInstanceKey).(Controller)
+}
@@ -0,0 +1,235 @@
+package freeze_test
+
+import (
+	"time"
+
+	. "github.com/billiford/go-clouddriver/pkg/freeze"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Controller", func() {
+	var (
+		c   Controller
+		err error
+	)
+
+	Describe("#NewController", func() {
+		When("the file does not exist", func() {
+			BeforeEach(func() {
+				c, err = NewController("test/missing.json")
+			})
+
+			It("returns an error", func() {
+				Expect(err).ToNot(BeNil())
+			})
+		})
+
+		When("it succeeds", func() {
+			BeforeEach(func() {
+				c, err = NewController("test/freezes.json")
+			})
+
+			It("reads the freezes", func() {
+				Expect(err).To(BeNil())
+				statuses := c.ListFreezes(time.Now())
+				Expect(statuses).To(HaveLen(2))
+				Expect(statuses[0].Name).To(Equal("weekend"))
+				Expect(statuses[1].Name).To(Equal("holidays"))
+			})
+		})
+	})
+
+	Describe("#NewControllerWithConfig", func() {
+		var config Config
+
+		BeforeEach(func() {
+			config = Config{
+				Freezes: []Freeze{
+					{
+						Name:     "weekend",
+						Schedule: "0 17 * * FRI",
+						Duration: "63h",
+					},
+				},
+			}
+		})
+
+		JustBeforeEach(func() {
+			c, err = NewControllerWithConfig(config)
+		})
+
+		When("a freeze has no name", func() {
+			BeforeEach(func() {
+				config.Freezes[0].Name = ""
+			})
+
+			It("returns an error", func() {
+				Expect(err).ToNot(BeNil())
+				Expect(err.Error()).To(Equal(`no "name" found in freeze with schedule "0 17 * * FRI"`))
+			})
+		})
+
+		When("the schedule is invalid", func() {
+			BeforeEach(func() {
+				config.Freezes[0].Schedule = "0 17 * *"
+			})
+
+			It("returns an error", func() {
+				Expect(err).ToNot(BeNil())
+				Expect(err.Error()).To(Equal(`error parsing schedule of freeze weekend: invalid schedule "0 17 * *": expected 5 fields, found 4`))
+			})
+		})
+
+		When("the duration is invalid", func() {
+			BeforeEach(func() {
+				config.Freezes[0].Duration = "-1h"
+			})
+
+			It("returns an error", func() {
+				Expect(err).ToNot(BeNil())
+				Expect(err.Error()).To(Equal(`invalid duration "-1h" of freeze weekend`))
+			})
+		})
+
+		When("the time zone is unknown", func() {
+			BeforeEach(func() {
+				config.Freezes[0].TimeZone = "Mars/Olympus_Mons"
+			})
+
+			It("returns an error", func() {
+				Expect(err).ToNot(BeNil())
+			})
+		})
+
+		When("windows overlap", func() {
+			BeforeEach(func() {
+				config.Freezes[0].Schedule = "0 * * * *"
+				config.Freezes[0].Duration = "90m"
+			})
+
+			It("lasts until the last window ends", func() {
+				Expect(err).To(BeNil())
+				statuses := c.ListFreezes(time.Date(2026, time.October, 14, 10, 15, 0, 0, time.UTC))
+				Expect(statuses[0].Active).To(BeTrue())
+				Expect(*statuses[0].Start).To(Equal(time.Date(2026, time.October, 14, 10, 0, 0, 0, time.UTC)))
+				Expect(*statuses[0].End).To(Equal(time.Date(2026, time.October, 14, 11, 30, 0, 0, time.UTC)))
+			})
+		})
+	})
+
+	Describe("#ListFreezes", func() {
+		var (
+			t        time.Time
+			statuses []Status
+		)
+
+		BeforeEach(func() {
+			c, err = NewController("test/freezes.json")
+			Expect(err).To(BeNil())
+			// A Wednesday.
+			t = time.Date(2026, time.October, 14, 12, 0, 0, 0, time.UTC)
+		})
+
+		JustBeforeEach(func() {
+			statuses = c.ListFreezes(t)
+		})
+
+		When("a freeze is not active", func() {
+			It("returns its next window", func() {
+				Expect(statuses[0].Active).To(BeFalse())
+				Expect(*statuses[0].Start).To(Equal(time.Date(2026, time.October, 16, 17, 0, 0, 0, time.UTC)))
+				Expect(*statuses[0].End).To(Equal(time.Date(2026, time.October, 19, 8, 0, 0, 0, time.UTC)))
+			})
+		})
+
+		When("a freeze is active", func() {
+			BeforeEach(func() {
+				t = time.Date(2026, time.October, 17, 10, 0, 0, 0, time.UTC)
+			})
+
+			It("returns its current window", func() {
+				Expect(statuses[0].Active).To(BeTrue())
+				Expect(*statuses[0].Start).To(Equal(time.Date(2026, time.October, 16, 17, 0, 0, 0, time.UTC)))
+				Expect(*statuses[0].End).To(Equal(time.Date(2026, time.October, 19, 8, 0, 0, 0, time.UTC)))
+				Expect(statuses[1].Active).To(BeFalse())
+			})
+		})
+
+		When("a window has just ended", func() {
+			BeforeEach(func() {
+				t = time.Date(2026, time.October, 19, 8, 0, 0, 0, time.UTC)
+			})
+
+			It("is not active", func() {
+				Expect(statuses[0].Active).To(BeFalse())
+				Expect(*statuses[0].Start).To(Equal(time.Date(2026, time.October, 23, 17, 0, 0, 0, time.UTC)))
+			})
+		})
+	})
+
+	Describe("#ActiveFreeze", func() {
+		var (
+			account   string
+			namespace string
+			t         time.Time
+			status    Status
+			ok        bool
+		)
+
+		BeforeEach(func() {
+			c, err = NewController("test/freezes.json")
+			Expect(err).To(BeNil())
+			account = "prod-account"
+			namespace = "default"
+			// A Saturday.
+			t = time.Date(2026, time.October, 17, 10, 0, 0, 0, time.UTC)
+		})
+
+		JustBeforeEach(func() {
+			status, ok = c.ActiveFreeze(account, namespace, t)
+		})
+
+		When("the account is frozen", func() {
+			It("returns the freeze", func() {
+				Expect(ok).To(BeTrue())
+				Expect(status.Name).To(Equal("weekend"))
+				Expect(status.Message).To(Equal("No deploys to prod over the weekend."))
+			})
+		})
+
+		When("the account is not listed", func() {
+			BeforeEach(func() {
+				account = "test-account"
+			})
+
+			It("returns false", func() {
+				Expect(ok).To(BeFalse())
+			})
+		})
+
+		When("no freeze is active", func() {
+			BeforeEach(func() {
+				t = time.Date(2026, time.October, 14, 12, 0, 0, 0, time.UTC)
+			})
+
+			It("returns false", func() {
+				Expect(ok).To(BeFalse())
+			})
+		})
+
+		When("several freezes are active", func() {
+			BeforeEach(func() {
+				namespace = "payments"
+				// A Saturday during the holidays.
+				t = time.Date(2026, time.December, 26, 10, 0, 0, 0, time.UTC)
+			})
+
+			It("prefers the one that cannot be overridden", func() {
+				Expect(ok).To(BeTrue())
+				Expect(status.Name).To(Equal("holidays"))
+				Expect(status.AllowOverride).To(BeFalse())
+			})
+		})
+	})
+})
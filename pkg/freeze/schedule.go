@@ -0,0 +1,188 @@
+package freeze
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Schedules are searched this far ahead before giving up on finding a match,
+// such as for February 30th.
+const maxScheduleYears = 5
+
+var (
+	monthNames = map[string]int{
+		"jan": 1, "feb": 2, "mar": 3, "apr": 4, "may": 5, "jun": 6,
+		"jul": 7, "aug": 8, "sep": 9, "oct": 10, "nov": 11, "dec": 12,
+	}
+	dayNames = map[string]int{
+		"sun": 0, "mon": 1, "tue": 2, "wed": 3, "thu": 4, "fri": 5, "sat": 6,
+	}
+)
+
+// Schedule is a cron expression with the five standard fields: minute, hour,
+// day of month, month and day of week. Fields are lists of values, ranges
+// and steps, such as "1-5" or "*/15", and months and days of week may be
+// named, such as "JAN" or "MON-FRI". Like cron, when both the day of month
+// and the day of week are restricted a day matching either one matches.
+type Schedule struct {
+	minute, hour, dom, month, dow uint64
+	domStar, dowStar              bool
+	loc                           *time.Location
+}
+
+// ParseSchedule parses a cron expression evaluated in loc.
+func ParseSchedule(expr string, loc *time.Location) (*Schedule, error) {
+	fields := strings.Fields(expr)
+	if len(fields) != 5 {
+		return nil, fmt.Errorf("invalid schedule %q: expected 5 fields, found %d", expr, len(fields))
+	}
+
+	s := &Schedule{
+		domStar: fields[2] == "*" || fields[2] == "?",
+		dowStar: fields[4] == "*" || fields[4] == "?",
+		loc:     loc,
+	}
+
+	var err error
+
+	for _, f := range []struct {
+		bits     *uint64
+		field    string
+		min, max int
+		names    map[string]int
+	}{
+		{&s.minute, fields[0], 0, 59, nil},
+		{&s.hour, fields[1], 0, 23, nil},
+		{&s.dom, fields[2], 1, 31, nil},
+		{&s.month, fields[3], 1, 12, monthNames},
+		{&s.dow, fields[4], 0, 7, dayNames},
+	} {
+		*f.bits, err = parseField(f.field, f.min, f.max, f.names)
+		if err != nil {
+			return nil, fmt.Errorf("invalid schedule %q: %w", expr, err)
+		}
+	}
+
+	// Both 0 and 7 are Sunday.
+	if s.dow&(1<<7) != 0 {
+		s.dow |= 1
+	}
+
+	return s, nil
+}
+
+func parseField(field string, min, max int, names map[string]int) (uint64, error) {
+	var bits uint64
+
+	for _, part := range strings.Split(field, ",") {
+		step := 1
+
+		if i := strings.Index(part, "/"); i >= 0 {
+			var err error
+
+			step, err = strconv.Atoi(part[i+1:])
+			if err != nil || step <= 0 {
+				return 0, fmt.Errorf("invalid step in %q", part)
+			}
+
+			part = part[:i]
+		}
+
+		start, end := min, max
+
+		if part != "*" && part != "?" {
+			bounds := strings.SplitN(part, "-", 2)
+
+			var err error
+
+			start, err = parseValue(bounds[0], names)
+			if err != nil {
+				return 0, err
+			}
+
+			end = start
+
+			if len(bounds) == 2 {
+				end, err = parseValue(bounds[1], names)
+				if err != nil {
+					return 0, err
+				}
+			} else if step > 1 {
+				// A step without a range, such as 5/15, runs to the max.
+				end = max
+			}
+		}
+
+		if start < min || end > max || start > end {
+			return 0, fmt.Errorf("%q is out of range %d-%d", part, min, max)
+		}
+
+		for v := start; v <= end; v += step {
+			bits |= 1 << uint(v)
+		}
+	}
+
+	return bits, nil
+}
+
+func parseValue(s string, names map[string]int) (int, error) {
+	if v, ok := names[strings.ToLower(s)]; ok {
+		return v, nil
+	}
+
+	v, err := strconv.Atoi(s)
+	if err != nil {
+		return 0, fmt.Errorf("invalid value %q", s)
+	}
+
+	return v, nil
+}
+
+// Next returns the first time at or after t that the schedule matches,
+// or the zero time if it does not match in the next five years.
+func (s *Schedule) Next(t time.Time) time.Time {
+	t = t.In(s.loc)
+
+	// Round up to the minute.
+	if m := t.Truncate(time.Minute); m.Before(t) {
+		t = m.Add(time.Minute)
+	}
+
+	limit := t.Year() + maxScheduleYears
+
+	for t.Year() <= limit {
+		y, mo, d := t.Date()
+
+		switch {
+		case !has(s.month, int(mo)):
+			t = time.Date(y, mo+1, 1, 0, 0, 0, 0, s.loc)
+		case !s.dayMatches(t):
+			t = time.Date(y, mo, d+1, 0, 0, 0, 0, s.loc)
+		case !has(s.hour, t.Hour()):
+			t = time.Date(y, mo, d, t.Hour()+1, 0, 0, 0, s.loc)
+		case !has(s.minute, t.Minute()):
+			t = t.Add(time.Minute)
+		default:
+			return t
+		}
+	}
+
+	return time.Time{}
+}
+
+func (s *Schedule) dayMatches(t time.Time) bool {
+	dom := has(s.dom, t.Day())
+	dow := has(s.dow, int(t.Weekday()))
+
+	if s.domStar || s.dowStar {
+		return dom && dow
+	}
+
+	return dom || dow
+}
+
+func has(bits uint64, v int) bool {
+	return bits&(1<<uint(v)) != 0
+}
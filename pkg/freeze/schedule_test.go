@@ -0,0 +1,157 @@
+package freeze_test
+
+import (
+	"time"
+
+	. "github.com/billiford/go-clouddriver/pkg/freeze"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Schedule", func() {
+	var (
+		expr string
+		loc  *time.Location
+		s    *Schedule
+		err  error
+		from time.Time
+	)
+
+	BeforeEach(func() {
+		loc = time.UTC
+		// A Wednesday.
+		from = time.Date(2026, time.October, 14, 10, 7, 30, 0, time.UTC)
+	})
+
+	JustBeforeEach(func() {
+		s, err = ParseSchedule(expr, loc)
+	})
+
+	Describe("#ParseSchedule", func() {
+		When("the expression does not have five fields", func() {
+			BeforeEach(func() {
+				expr = "0 17 * *"
+			})
+
+			It("returns an error", func() {
+				Expect(err).ToNot(BeNil())
+				Expect(err.Error()).To(Equal(`invalid schedule "0 17 * *": expected 5 fields, found 4`))
+			})
+		})
+
+		When("a value is out of range", func() {
+			BeforeEach(func() {
+				expr = "60 * * * *"
+			})
+
+			It("returns an error", func() {
+				Expect(err).ToNot(BeNil())
+				Expect(err.Error()).To(Equal(`invalid schedule "60 * * * *": "60" is out of range 0-59`))
+			})
+		})
+
+		When("a step is invalid", func() {
+			BeforeEach(func() {
+				expr = "*/0 * * * *"
+			})
+
+			It("returns an error", func() {
+				Expect(err).ToNot(BeNil())
+				Expect(err.Error()).To(Equal(`invalid schedule "*/0 * * * *": invalid step in "*/0"`))
+			})
+		})
+
+		When("a name is unknown", func() {
+			BeforeEach(func() {
+				expr = "0 0 * * FUN"
+			})
+
+			It("returns an error", func() {
+				Expect(err).ToNot(BeNil())
+				Expect(err.Error()).To(Equal(`invalid schedule "0 0 * * FUN": invalid value "FUN"`))
+			})
+		})
+	})
+
+	Describe("#Next", func() {
+		var next time.Time
+
+		JustBeforeEach(func() {
+			Expect(err).To(BeNil())
+			next = s.Next(from)
+		})
+
+		When("the schedule has a step", func() {
+			BeforeEach(func() {
+				expr = "*/15 * * * *"
+			})
+
+			It("rounds up to the next match", func() {
+				Expect(next).To(Equal(time.Date(2026, time.October, 14, 10, 15, 0, 0, time.UTC)))
+			})
+		})
+
+		When("the time matches", func() {
+			BeforeEach(func() {
+				expr = "7 10 * * *"
+				from = time.Date(2026, time.October, 14, 10, 7, 0, 0, time.UTC)
+			})
+
+			It("returns the time", func() {
+				Expect(next).To(Equal(from))
+			})
+		})
+
+		When("the schedule names a range of days", func() {
+			BeforeEach(func() {
+				expr = "0 9 * * MON-FRI"
+				from = time.Date(2026, time.October, 17, 0, 0, 0, 0, time.UTC)
+			})
+
+			It("skips the weekend", func() {
+				Expect(next).To(Equal(time.Date(2026, time.October, 19, 9, 0, 0, 0, time.UTC)))
+			})
+		})
+
+		When("both the day of month and the day of week are restricted", func() {
+			BeforeEach(func() {
+				expr = "0 0 1 * MON"
+			})
+
+			It("matches either", func() {
+				Expect(next).To(Equal(time.Date(2026, time.October, 19, 0, 0, 0, 0, time.UTC)))
+			})
+		})
+
+		When("the day of week is 7", func() {
+			BeforeEach(func() {
+				expr = "0 0 * * 7"
+			})
+
+			It("matches sunday", func() {
+				Expect(next).To(Equal(time.Date(2026, time.October, 18, 0, 0, 0, 0, time.UTC)))
+			})
+		})
+
+		When("the schedule has a location", func() {
+			BeforeEach(func() {
+				expr = "0 9 * * *"
+				loc = time.FixedZone("CDT", -5*60*60)
+			})
+
+			It("matches in the location", func() {
+				Expect(next.Equal(time.Date(2026, time.October, 14, 14, 0, 0, 0, time.UTC))).To(BeTrue())
+			})
+		})
+
+		When("the schedule never matches", func() {
+			BeforeEach(func() {
+				expr = "0 0 30 FEB *"
+			})
+
+			It("returns the zero time", func() {
+				Expect(next.IsZero()).To(BeTrue())
+			})
+		})
+	})
+})
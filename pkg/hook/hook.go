@@ -0,0 +1,328 @@
+// Package hook calls webhooks before and after operations are applied and
+// once the resources they deploy are stable, so platform teams can validate,
+// mutate and audit operations without forking clouddriver. Accounts can also
+// mutate rendered manifests and decide the stability of bespoke kinds with
+// webhooks.
+package hook
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"log"
+	"net/http"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+const (
+	InstanceKey = `HookRunner`
+
+	StagePreApply      = `preApply`
+	StagePostApply     = `postApply`
+	StagePostStability = `postStability`
+)
+
+var (
+	defaultConfigPath = "/opt/spinnaker/kubernetes/hooks.json"
+	// Hooks are only called when they respond within this long.
+	defaultTimeout = 10 * time.Second
+
+	hookCalls = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "clouddriver_hook_calls_total",
+		Help: "Number of operation hooks called by stage and result.",
+	}, []string{"stage", "result"})
+)
+
+func init() {
+	prometheus.MustRegister(hookCalls)
+}
+
+// Event is the context of an operation posted to its hooks.
+type Event struct {
+	Stage       string `json:"stage"`
+	Operation   string `json:"operation"`
+	Account     string `json:"account,omitempty"`
+	Application string `json:"application,omitempty"`
+	User        string `json:"user,omitempty"`
+	TaskID      string `json:"taskId"`
+	// Manifests the operation applies, as mutated by pre-apply hooks.
+	Manifests []map[string]interface{} `json:"manifests,omitempty"`
+	// Error is the reason the operation failed, posted to post-apply hooks.
+	Error string `json:"error,omitempty"`
+	// Phase is the outcome of the rollout posted to post-stability hooks,
+	// one of STABLE, FAILED or TIMED_OUT.
+	Phase string `json:"phase,omitempty"`
+}
+
+// Response is the response of a pre-apply hook. Operations are rejected
+// with the message unless allowed, and the manifests replace the
+// operation's when set.
+type Response struct {
+	Allowed   bool                     `json:"allowed"`
+	Message   string                   `json:"message,omitempty"`
+	Manifests []map[string]interface{} `json:"manifests,omitempty"`
+}
+
+// RejectedError is returned by PreApply when a hook does not allow an operation.
+type RejectedError struct {
+	Hook    string
+	Message string
+}
+
+func (e *RejectedError) Error() string {
+	msg := fmt.Sprintf("operation rejected by hook %s", e.Hook)
+	if e.Message != "" {
+		msg += ": " + e.Message
+	}
+
+	return msg
+}
+
+// Runner calls the hooks an operation matches.
+//
+//go:generate counterfeiter . Runner
+type Runner interface {
+	// PreApply calls the pre-apply hooks an event matches in order, each
+	// posted the manifests returned by the one before, and returns the
+	// manifests to apply. It returns a *RejectedError if a hook does not
+	// allow the operation, or an error if a hook cannot be called.
+	PreApply(context.Context, Event) ([]map[string]interface{}, error)
+	// PostApply calls the post-apply hooks an event matches in the background.
+	PostApply(Event)
+	// PostStability calls the post-stability hooks an event matches in the background.
+	PostStability(Event)
+	// Matches returns true if any hook of a stage matches an event.
+	Matches(string, Event) bool
+}
+
+// Config lists the hooks to call at each stage of an operation.
+//
+//	{
+//	  "hooks": [
+//	    {
+//	      "name": "require-team-label",
+//	      "stage": "preApply",
+//	      "url": "https://hooks.example.com/validate",
+//	      "accounts": ["prod-account"],
+//	      "operations": ["deployManifest"]
+//	    },
+//	    {
+//	      "name": "audit",
+//	      "stage": "postStability",
+//	      "url": "https://hooks.example.com/audit"
+//	    }
+//	  ]
+//	}
+type Config struct {
+	Hooks []Hook `json:"hooks"`
+}
+
+// Hook is a webhook called at a stage of the operations of the listed
+// accounts, applications and operations, or of all of them when none are listed.
+type Hook struct {
+	Name         string   `json:"name"`
+	Stage        string   `json:"stage"`
+	URL          string   `json:"url"`
+	Accounts     []string `json:"accounts,omitempty"`
+	Applications []string `json:"applications,omitempty"`
+	Operations   []string `json:"operations,omitempty"`
+}
+
+// Matches returns true if the hook is called for an event.
+func (h Hook) Matches(stage string, e Event) bool {
+	return h.Stage == stage &&
+		matches(h.Accounts, e.Account) &&
+		matches(h.Applications, e.Application) &&
+		matches(h.Operations, e.Operation)
+}
+
+func matches(values []string, s string) bool {
+	if len(values) == 0 {
+		return true
+	}
+
+	for _, v := range values {
+		if strings.EqualFold(v, s) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// NewDefaultRunner reads the hooks from /opt/spinnaker/kubernetes/hooks.json.
+// Hooks are optional, so if the file does not exist it returns nil.
+func NewDefaultRunner() (Runner, error) {
+	if _, err := os.Stat(defaultConfigPath); os.IsNotExist(err) {
+		return nil, nil
+	}
+
+	return NewRunner(defaultConfigPath)
+}
+
+// NewRunner reads the hooks from a JSON file.
+func NewRunner(path string) (Runner, error) {
+	b, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	config := Config{}
+
+	err = json.Unmarshal(b, &config)
+	if err != nil {
+		return nil, err
+	}
+
+	return NewRunnerWithConfig(config)
+}
+
+// NewRunnerWithConfig returns a runner for the hooks of config.
+func NewRunnerWithConfig(config Config) (Runner, error) {
+	for _, h := range config.Hooks {
+		if h.Name == "" {
+			return nil, errors.New("no \"name\" found in hook")
+		}
+
+		if h.Stage != StagePreApply && h.Stage != StagePostApply && h.Stage != StagePostStability {
+			return nil, fmt.Errorf("unknown stage %q of hook %s, must be %s, %s or %s",
+				h.Stage, h.Name, StagePreApply, StagePostApply, StagePostStability)
+		}
+
+		if h.URL == "" {
+			return nil, fmt.Errorf("no \"url\" found in hook %s", h.Name)
+		}
+	}
+
+	return &runner{
+		config:     config,
+		httpClient: &http.Client{Timeout: defaultTimeout},
+	}, nil
+}
+
+type runner struct {
+	config     Config
+	httpClient *http.Client
+}
+
+func (r *runner) PreApply(ctx context.Context, e Event) ([]map[string]interface{}, error) {
+	e.Stage = StagePreApply
+
+	for _, h := range r.config.Hooks {
+		if !h.Matches(StagePreApply, e) {
+			continue
+		}
+
+		res := Response{}
+
+		err := r.post(ctx, h, e, &res)
+		if err != nil {
+			return nil, fmt.Errorf("error calling hook %s: %w", h.Name, err)
+		}
+
+		if !res.Allowed {
+			hookCalls.WithLabelValues(StagePreApply, "rejected").Inc()
+			return nil, &RejectedError{Hook: h.Name, Message: res.Message}
+		}
+
+		if res.Manifests != nil {
+			e.Manifests = res.Manifests
+		}
+	}
+
+	return e.Manifests, nil
+}
+
+func (r *runner) PostApply(e Event) {
+	e.Stage = StagePostApply
+	r.notify(e)
+}
+
+func (r *runner) PostStability(e Event) {
+	e.Stage = StagePostStability
+	r.notify(e)
+}
+
+// notify posts the event to each hook of its stage it matches in the
+// background, so slow hooks do not hold up operations. Errors are logged.
+func (r *runner) notify(e Event) {
+	for _, h := range r.config.Hooks {
+		if !h.Matches(e.Stage, e) {
+			continue
+		}
+
+		go func(h Hook) {
+			err := r.post(context.Background(), h, e, nil)
+			if err != nil {
+				log.Println("[HOOK] error calling", e.Stage, "hook", h.Name, "of task", e.TaskID+":", err.Error())
+			}
+		}(h)
+	}
+}
+
+func (r *runner) Matches(stage string, e Event) bool {
+	for _, h := range r.config.Hooks {
+		if h.Matches(stage, e) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// post posts an event to a hook, decoding its response into v if not nil.
+func (r *runner) post(ctx context.Context, h Hook, e Event, v interface{}) error {
+	err := r.do(ctx, h, e, v)
+	if err != nil {
+		hookCalls.WithLabelValues(e.Stage, "error").Inc()
+		return err
+	}
+
+	hookCalls.WithLabelValues(e.Stage, "called").Inc()
+
+	return nil
+}
+
+func (r *runner) do(ctx context.Context, h Hook, e Event, v interface{}) error {
+	b, err := json.Marshal(e)
+	if err != nil {
+		return err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, h.URL, bytes.NewReader(b))
+	if err != nil {
+		return err
+	}
+
+	req.Header.Set("Content-Type", "application/json")
+
+	res, err := r.httpClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer res.Body.Close()
+
+	if res.StatusCode < http.StatusOK || res.StatusCode >= http.StatusMultipleChoices {
+		return fmt.Errorf("hook returned status %d", res.StatusCode)
+	}
+
+	if v == nil {
+		return nil
+	}
+
+	return json.NewDecoder(res.Body).Decode(v)
+}
+
+// Instance returns the Runner, or nil if no hooks are configured.
+func Instance(c *gin.Context) Runner {
+	r, _ := c.MustGet(InstanceKey).(Runner)
+	return r
+}
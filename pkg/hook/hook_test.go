@@ -0,0 +1,313 @@
+package hook_test
+
+import (
+	"context"
+	"io/ioutil"
+	"log"
+	"net/http"
+
+	. "github.com/billiford/go-clouddriver/pkg/hook"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"github.com/onsi/gomega/ghttp"
+)
+
+var _ = Describe("Hook", func() {
+	var (
+		r     Runner
+		err   error
+		event Event
+	)
+
+	BeforeEach(func() {
+		log.SetOutput(ioutil.Discard)
+		event = Event{
+			Operation:   "deployManifest",
+			Account:     "prod-account",
+			Application: "my-app",
+			User:        "me@example.com",
+			TaskID:      "test-task-id",
+			Manifests: []map[string]interface{}{
+				{"kind": "Deployment"},
+			},
+		}
+	})
+
+	Describe("#NewRunner", func() {
+		When("the file does not exist", func() {
+			BeforeEach(func() {
+				r, err = NewRunner("test/missing.json")
+			})
+
+			It("returns an error", func() {
+				Expect(err).ToNot(BeNil())
+			})
+		})
+
+		When("it succeeds", func() {
+			BeforeEach(func() {
+				r, err = NewRunner("test/hooks.json")
+			})
+
+			It("returns a runner", func() {
+				Expect(err).To(BeNil())
+				Expect(r.Matches(StagePreApply, event)).To(BeTrue())
+				Expect(r.Matches(StagePostApply, event)).To(BeFalse())
+			})
+		})
+	})
+
+	Describe("#NewRunnerWithConfig", func() {
+		var config Config
+
+		BeforeEach(func() {
+			config = Config{Hooks: []Hook{{Name: "audit", Stage: StagePostApply, URL: "https://hooks.example.com/audit"}}}
+		})
+
+		JustBeforeEach(func() {
+			r, err = NewRunnerWithConfig(config)
+		})
+
+		When("a hook has no name", func() {
+			BeforeEach(func() {
+				config.Hooks[0].Name = ""
+			})
+
+			It("returns an error", func() {
+				Expect(err).ToNot(BeNil())
+				Expect(err.Error()).To(Equal(`no "name" found in hook`))
+			})
+		})
+
+		When("a stage is unknown", func() {
+			BeforeEach(func() {
+				config.Hooks[0].Stage = "preDelete"
+			})
+
+			It("returns an error", func() {
+				Expect(err).ToNot(BeNil())
+				Expect(err.Error()).To(Equal(`unknown stage "preDelete" of hook audit, must be preApply, postApply or postStability`))
+			})
+		})
+
+		When("a hook has no URL", func() {
+			BeforeEach(func() {
+				config.Hooks[0].URL = ""
+			})
+
+			It("returns an error", func() {
+				Expect(err).ToNot(BeNil())
+				Expect(err.Error()).To(Equal(`no "url" found in hook audit`))
+			})
+		})
+	})
+
+	Describe("#Matches", func() {
+		var hook Hook
+
+		BeforeEach(func() {
+			hook = Hook{Stage: StagePreApply}
+		})
+
+		It("matches all operations of its stage by default", func() {
+			Expect(hook.Matches(StagePreApply, event)).To(BeTrue())
+			Expect(hook.Matches(StagePostApply, event)).To(BeFalse())
+		})
+
+		It("matches the listed accounts, applications and operations ignoring case", func() {
+			hook.Accounts = []string{"Prod-Account"}
+			hook.Operations = []string{"scaleManifest"}
+			Expect(hook.Matches(StagePreApply, event)).To(BeFalse())
+			hook.Operations = []string{"deployManifest"}
+			Expect(hook.Matches(StagePreApply, event)).To(BeTrue())
+			hook.Applications = []string{"other-app"}
+			Expect(hook.Matches(StagePreApply, event)).To(BeFalse())
+		})
+	})
+
+	Describe("#PreApply", func() {
+		var (
+			server    *ghttp.Server
+			manifests []map[string]interface{}
+		)
+
+		BeforeEach(func() {
+			server = ghttp.NewServer()
+			r, err = NewRunnerWithConfig(Config{
+				Hooks: []Hook{
+					{
+						Name:  "add-team-label",
+						Stage: StagePreApply,
+						URL:   server.URL() + "/mutate",
+					},
+					{
+						Name:  "require-team-label",
+						Stage: StagePreApply,
+						URL:   server.URL() + "/validate",
+					},
+					{
+						Name:  "audit",
+						Stage: StagePostApply,
+						URL:   server.URL() + "/audit",
+					},
+				},
+			})
+			Expect(err).To(BeNil())
+			server.AppendHandlers(ghttp.CombineHandlers(
+				ghttp.VerifyRequest(http.MethodPost, "/mutate"),
+				ghttp.VerifyContentType("application/json"),
+				ghttp.VerifyJSON(payloadPreApply),
+				ghttp.RespondWith(http.StatusOK, `{"allowed":true,"manifests":[{"kind":"Deployment","metadata":{"labels":{"team":"platform"}}}]}`),
+			))
+		})
+
+		AfterEach(func() {
+			server.Close()
+		})
+
+		JustBeforeEach(func() {
+			manifests, err = r.PreApply(context.Background(), event)
+		})
+
+		When("the hooks allow the operation", func() {
+			BeforeEach(func() {
+				server.AppendHandlers(ghttp.CombineHandlers(
+					ghttp.VerifyRequest(http.MethodPost, "/validate"),
+					ghttp.VerifyJSON(`{"stage":"preApply","operation":"deployManifest","account":"prod-account",`+
+						`"application":"my-app","user":"me@example.com","taskId":"test-task-id",`+
+						`"manifests":[{"kind":"Deployment","metadata":{"labels":{"team":"platform"}}}]}`),
+					ghttp.RespondWith(http.StatusOK, `{"allowed":true}`),
+				))
+			})
+
+			It("returns the manifests mutated by each hook in order", func() {
+				Expect(err).To(BeNil())
+				Expect(server.ReceivedRequests()).To(HaveLen(2))
+				Expect(manifests).To(Equal([]map[string]interface{}{
+					{
+						"kind": "Deployment",
+						"metadata": map[string]interface{}{
+							"labels": map[string]interface{}{
+								"team": "platform",
+							},
+						},
+					},
+				}))
+			})
+		})
+
+		When("a hook rejects the operation", func() {
+			BeforeEach(func() {
+				server.AppendHandlers(ghttp.RespondWith(http.StatusOK, `{"allowed":false,"message":"missing label team"}`))
+			})
+
+			It("returns a rejected error", func() {
+				Expect(err).ToNot(BeNil())
+				Expect(err).To(BeAssignableToTypeOf(&RejectedError{}))
+				Expect(err.Error()).To(Equal("operation rejected by hook require-team-label: missing label team"))
+			})
+		})
+
+		When("a hook returns an error status", func() {
+			BeforeEach(func() {
+				server.AppendHandlers(ghttp.RespondWith(http.StatusInternalServerError, ""))
+			})
+
+			It("returns an error", func() {
+				Expect(err).ToNot(BeNil())
+				Expect(err.Error()).To(Equal("error calling hook require-team-label: hook returned status 500"))
+			})
+		})
+	})
+
+	Describe("#PostApply", func() {
+		var server *ghttp.Server
+
+		BeforeEach(func() {
+			server = ghttp.NewServer()
+			r, err = NewRunnerWithConfig(Config{
+				Hooks: []Hook{
+					{
+						Name:  "audit",
+						Stage: StagePostApply,
+						URL:   server.URL() + "/audit",
+					},
+					{
+						Name:       "scale-audit",
+						Stage:      StagePostApply,
+						URL:        server.URL() + "/scale-audit",
+						Operations: []string{"scaleManifest"},
+					},
+				},
+			})
+			Expect(err).To(BeNil())
+			event.Error = "error deploying manifest"
+			server.AppendHandlers(ghttp.CombineHandlers(
+				ghttp.VerifyRequest(http.MethodPost, "/audit"),
+				ghttp.VerifyJSON(`{"stage":"postApply","operation":"deployManifest","account":"prod-account",`+
+					`"application":"my-app","user":"me@example.com","taskId":"test-task-id",`+
+					`"manifests":[{"kind":"Deployment"}],"error":"error deploying manifest"}`),
+				ghttp.RespondWith(http.StatusOK, ""),
+			))
+		})
+
+		AfterEach(func() {
+			server.Close()
+		})
+
+		It("posts to the hooks it matches in the background", func() {
+			r.PostApply(event)
+			Eventually(server.ReceivedRequests).Should(HaveLen(1))
+			Consistently(server.ReceivedRequests).Should(HaveLen(1))
+		})
+	})
+
+	Describe("#PostStability", func() {
+		var server *ghttp.Server
+
+		BeforeEach(func() {
+			server = ghttp.NewServer()
+			r, err = NewRunnerWithConfig(Config{
+				Hooks: []Hook{
+					{
+						Name:  "audit",
+						Stage: StagePostStability,
+						URL:   server.URL() + "/audit",
+					},
+				},
+			})
+			Expect(err).To(BeNil())
+			event.Manifests = nil
+			event.Phase = "STABLE"
+			server.AppendHandlers(ghttp.CombineHandlers(
+				ghttp.VerifyRequest(http.MethodPost, "/audit"),
+				ghttp.VerifyJSON(`{"stage":"postStability","operation":"deployManifest","account":"prod-account",`+
+					`"application":"my-app","user":"me@example.com","taskId":"test-task-id","phase":"STABLE"}`),
+				ghttp.RespondWith(http.StatusOK, ""),
+			))
+		})
+
+		AfterEach(func() {
+			server.Close()
+		})
+
+		It("posts the phase of the rollout", func() {
+			r.PostStability(event)
+			Eventually(server.ReceivedRequests).Should(HaveLen(1))
+		})
+	})
+})
+
+const payloadPreApply = `{
+  "stage": "preApply",
+  "operation": "deployManifest",
+  "account": "prod-account",
+  "application": "my-app",
+  "user": "me@example.com",
+  "taskId": "test-task-id",
+  "manifests": [
+    {
+      "kind": "Deployment"
+    }
+  ]
+}`
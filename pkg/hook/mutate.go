@@ -0,0 +1,220 @@
+package hook
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"log"
+	"net/http"
+	"os"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+const (
+	FailurePolicyFail   = `fail`
+	FailurePolicyIgnore = `ignore`
+)
+
+var (
+	defaultMutationConfigPath = "/opt/spinnaker/kubernetes/mutations.json"
+
+	mutationCalls = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "clouddriver_mutation_webhook_calls_total",
+		Help: "Number of manifest mutation webhooks called by webhook and result.",
+	}, []string{"webhook", "result"})
+)
+
+func init() {
+	prometheus.MustRegister(mutationCalls)
+}
+
+// MutationRequest is posted to the mutation webhooks of an account with
+// the manifests of a deploy once they are rendered. Webhooks respond with
+// the manifests to deploy instead, or the same request to leave them
+// unchanged.
+type MutationRequest struct {
+	Account     string                   `json:"account"`
+	Application string                   `json:"application,omitempty"`
+	TaskID      string                   `json:"taskId"`
+	Manifests   []map[string]interface{} `json:"manifests"`
+}
+
+// Mutator calls the mutation webhooks of an account.
+//
+//go:generate counterfeiter . Mutator
+type Mutator interface {
+	// Mutate posts the manifests to each mutation webhook of the account in
+	// order, each posted the manifests returned by the one before, and
+	// returns the manifests to deploy.
+	Mutate(context.Context, MutationRequest) ([]map[string]interface{}, error)
+}
+
+// MutationConfig lists the mutation webhooks of each account. Webhooks
+// fail the deploy when they cannot be called within the timeout or respond
+// with an error status, unless their failure policy is "ignore".
+//
+//	{
+//	  "accounts": {
+//	    "prod-account": [
+//	      {
+//	        "name": "inject-proxy",
+//	        "url": "https://mutate.example.com/proxy",
+//	        "timeoutSeconds": 5,
+//	        "failurePolicy": "ignore"
+//	      },
+//	      {
+//	        "name": "rewrite-registry",
+//	        "url": "https://mutate.example.com/registry"
+//	      }
+//	    ]
+//	  }
+//	}
+type MutationConfig struct {
+	Accounts map[string][]MutationWebhook `json:"accounts"`
+}
+
+// MutationWebhook is a webhook that mutates the manifests deployed to an account.
+type MutationWebhook struct {
+	Name string `json:"name"`
+	URL  string `json:"url"`
+	// TimeoutSeconds defaults to 10.
+	TimeoutSeconds int `json:"timeoutSeconds,omitempty"`
+	// FailurePolicy is "fail" or "ignore", defaulting to "fail".
+	FailurePolicy string `json:"failurePolicy,omitempty"`
+}
+
+func (w MutationWebhook) timeout() time.Duration {
+	if w.TimeoutSeconds > 0 {
+		return time.Duration(w.TimeoutSeconds) * time.Second
+	}
+
+	return defaultTimeout
+}
+
+// NewDefaultMutator reads the mutation webhooks from /opt/spinnaker/kubernetes/mutations.json.
+// Mutation webhooks are optional, so if the file does not exist it returns nil.
+func NewDefaultMutator() (Mutator, error) {
+	if _, err := os.Stat(defaultMutationConfigPath); os.IsNotExist(err) {
+		return nil, nil
+	}
+
+	return NewMutator(defaultMutationConfigPath)
+}
+
+// NewMutator reads the mutation webhooks from a JSON file.
+func NewMutator(path string) (Mutator, error) {
+	b, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	config := MutationConfig{}
+
+	err = json.Unmarshal(b, &config)
+	if err != nil {
+		return nil, err
+	}
+
+	return NewMutatorWithConfig(config)
+}
+
+// NewMutatorWithConfig returns a mutator for the webhooks of config.
+func NewMutatorWithConfig(config MutationConfig) (Mutator, error) {
+	for account, webhooks := range config.Accounts {
+		for _, w := range webhooks {
+			if w.Name == "" {
+				return nil, fmt.Errorf("no \"name\" found in mutation webhook of account %s", account)
+			}
+
+			if w.URL == "" {
+				return nil, fmt.Errorf("no \"url\" found in mutation webhook %s of account %s", w.Name, account)
+			}
+
+			if w.FailurePolicy != "" && w.FailurePolicy != FailurePolicyFail && w.FailurePolicy != FailurePolicyIgnore {
+				return nil, fmt.Errorf("unknown failure policy %q of mutation webhook %s of account %s, must be %s or %s",
+					w.FailurePolicy, w.Name, account, FailurePolicyFail, FailurePolicyIgnore)
+			}
+		}
+	}
+
+	return &mutator{
+		config:     config,
+		httpClient: &http.Client{},
+	}, nil
+}
+
+type mutator struct {
+	config     MutationConfig
+	httpClient *http.Client
+}
+
+func (m *mutator) Mutate(ctx context.Context, mr MutationRequest) ([]map[string]interface{}, error) {
+	for _, w := range m.config.Accounts[mr.Account] {
+		manifests, err := m.post(ctx, w, mr)
+		if err != nil {
+			if w.FailurePolicy == FailurePolicyIgnore {
+				mutationCalls.WithLabelValues(w.Name, "ignored").Inc()
+				log.Println("[MUTATE] ignoring error calling mutation webhook", w.Name,
+					"of account", mr.Account, "for task", mr.TaskID+":", err.Error())
+
+				continue
+			}
+
+			mutationCalls.WithLabelValues(w.Name, "error").Inc()
+
+			return nil, fmt.Errorf("error calling mutation webhook %s: %w", w.Name, err)
+		}
+
+		mutationCalls.WithLabelValues(w.Name, "mutated").Inc()
+
+		mr.Manifests = manifests
+	}
+
+	return mr.Manifests, nil
+}
+
+// post posts the manifests to a webhook, returning the manifests it responds with.
+func (m *mutator) post(ctx context.Context, w MutationWebhook, mr MutationRequest) ([]map[string]interface{}, error) {
+	ctx, cancel := context.WithTimeout(ctx, w.timeout())
+	defer cancel()
+
+	b, err := json.Marshal(mr)
+	if err != nil {
+		return nil, err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, w.URL, bytes.NewReader(b))
+	if err != nil {
+		return nil, err
+	}
+
+	req.Header.Set("Content-Type", "application/json")
+
+	res, err := m.httpClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer res.Body.Close()
+
+	if res.StatusCode < http.StatusOK || res.StatusCode >= http.StatusMultipleChoices {
+		return nil, fmt.Errorf("webhook returned status %d", res.StatusCode)
+	}
+
+	r := MutationRequest{}
+
+	err = json.NewDecoder(res.Body).Decode(&r)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(r.Manifests) == 0 {
+		return nil, errors.New("webhook returned no manifests")
+	}
+
+	return r.Manifests, nil
+}
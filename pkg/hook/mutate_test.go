@@ -0,0 +1,232 @@
+package hook_test
+
+import (
+	"context"
+	"io/ioutil"
+	"log"
+	"net/http"
+	"time"
+
+	. "github.com/billiford/go-clouddriver/pkg/hook"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"github.com/onsi/gomega/ghttp"
+)
+
+var _ = Describe("Mutate", func() {
+	var (
+		m         Mutator
+		err       error
+		mr        MutationRequest
+		manifests []map[string]interface{}
+	)
+
+	BeforeEach(func() {
+		log.SetOutput(ioutil.Discard)
+		mr = MutationRequest{
+			Account:     "prod-account",
+			Application: "my-app",
+			TaskID:      "test-task-id",
+			Manifests: []map[string]interface{}{
+				{"kind": "Deployment"},
+			},
+		}
+	})
+
+	Describe("#NewMutator", func() {
+		When("the file does not exist", func() {
+			BeforeEach(func() {
+				m, err = NewMutator("test/missing.json")
+			})
+
+			It("returns an error", func() {
+				Expect(err).ToNot(BeNil())
+			})
+		})
+
+		When("it succeeds", func() {
+			BeforeEach(func() {
+				m, err = NewMutator("test/mutations.json")
+			})
+
+			It("returns a mutator", func() {
+				Expect(err).To(BeNil())
+				Expect(m).ToNot(BeNil())
+			})
+		})
+	})
+
+	Describe("#NewMutatorWithConfig", func() {
+		var config MutationConfig
+
+		BeforeEach(func() {
+			config = MutationConfig{
+				Accounts: map[string][]MutationWebhook{
+					"prod-account": {
+						{
+							Name: "inject-proxy",
+							URL:  "https://mutate.example.com/proxy",
+						},
+					},
+				},
+			}
+		})
+
+		JustBeforeEach(func() {
+			m, err = NewMutatorWithConfig(config)
+		})
+
+		When("a webhook has no name", func() {
+			BeforeEach(func() {
+				config.Accounts["prod-account"][0].Name = ""
+			})
+
+			It("returns an error", func() {
+				Expect(err).ToNot(BeNil())
+				Expect(err.Error()).To(Equal(`no "name" found in mutation webhook of account prod-account`))
+			})
+		})
+
+		When("a webhook has no URL", func() {
+			BeforeEach(func() {
+				config.Accounts["prod-account"][0].URL = ""
+			})
+
+			It("returns an error", func() {
+				Expect(err).ToNot(BeNil())
+				Expect(err.Error()).To(Equal(`no "url" found in mutation webhook inject-proxy of account prod-account`))
+			})
+		})
+
+		When("a failure policy is unknown", func() {
+			BeforeEach(func() {
+				config.Accounts["prod-account"][0].FailurePolicy = "retry"
+			})
+
+			It("returns an error", func() {
+				Expect(err).ToNot(BeNil())
+				Expect(err.Error()).To(Equal(`unknown failure policy "retry" of mutation webhook inject-proxy of account prod-account, must be fail or ignore`))
+			})
+		})
+	})
+
+	Describe("#Mutate", func() {
+		var (
+			server  *ghttp.Server
+			webhook MutationWebhook
+		)
+
+		BeforeEach(func() {
+			server = ghttp.NewServer()
+			webhook = MutationWebhook{
+				Name: "rewrite-registry",
+				URL:  server.URL() + "/registry",
+			}
+			server.AppendHandlers(ghttp.CombineHandlers(
+				ghttp.VerifyRequest(http.MethodPost, "/proxy"),
+				ghttp.VerifyContentType("application/json"),
+				ghttp.VerifyJSON(`{"account":"prod-account","application":"my-app","taskId":"test-task-id","manifests":[{"kind":"Deployment"}]}`),
+				ghttp.RespondWith(http.StatusOK, `{"manifests":[{"kind":"Deployment","spec":{"proxy":true}}]}`),
+			))
+		})
+
+		AfterEach(func() {
+			server.Close()
+		})
+
+		JustBeforeEach(func() {
+			m, err = NewMutatorWithConfig(MutationConfig{
+				Accounts: map[string][]MutationWebhook{
+					"prod-account": {
+						{
+							Name: "inject-proxy",
+							URL:  server.URL() + "/proxy",
+						},
+						webhook,
+					},
+				},
+			})
+			Expect(err).To(BeNil())
+			manifests, err = m.Mutate(context.Background(), mr)
+		})
+
+		When("the account has no mutation webhooks", func() {
+			BeforeEach(func() {
+				mr.Account = "test-account"
+			})
+
+			It("returns the manifests", func() {
+				Expect(err).To(BeNil())
+				Expect(server.ReceivedRequests()).To(BeEmpty())
+				Expect(manifests).To(Equal(mr.Manifests))
+			})
+		})
+
+		When("the webhooks respond", func() {
+			BeforeEach(func() {
+				server.AppendHandlers(ghttp.CombineHandlers(
+					ghttp.VerifyRequest(http.MethodPost, "/registry"),
+					ghttp.VerifyJSON(`{"account":"prod-account","application":"my-app","taskId":"test-task-id","manifests":[{"kind":"Deployment","spec":{"proxy":true}}]}`),
+					ghttp.RespondWith(http.StatusOK, `{"manifests":[{"kind":"Deployment","spec":{"proxy":true,"registry":"mirror.example.com"}}]}`),
+				))
+			})
+
+			It("returns the manifests mutated by each webhook in order", func() {
+				Expect(err).To(BeNil())
+				Expect(manifests).To(Equal([]map[string]interface{}{
+					{
+						"kind": "Deployment",
+						"spec": map[string]interface{}{
+							"proxy":    true,
+							"registry": "mirror.example.com",
+						},
+					},
+				}))
+			})
+		})
+
+		When("a webhook returns no manifests", func() {
+			BeforeEach(func() {
+				server.AppendHandlers(ghttp.RespondWith(http.StatusOK, `{}`))
+			})
+
+			It("returns an error", func() {
+				Expect(err).ToNot(BeNil())
+				Expect(err.Error()).To(Equal("error calling mutation webhook rewrite-registry: webhook returned no manifests"))
+			})
+		})
+
+		When("a webhook times out", func() {
+			BeforeEach(func() {
+				webhook.TimeoutSeconds = 1
+				server.AppendHandlers(func(http.ResponseWriter, *http.Request) {
+					time.Sleep(2 * time.Second)
+				})
+			})
+
+			It("returns an error", func() {
+				Expect(err).ToNot(BeNil())
+				Expect(err.Error()).To(ContainSubstring("error calling mutation webhook rewrite-registry"))
+				Expect(err.Error()).To(ContainSubstring("context deadline exceeded"))
+			})
+
+			When("its failure policy is ignore", func() {
+				BeforeEach(func() {
+					webhook.FailurePolicy = FailurePolicyIgnore
+				})
+
+				It("returns the manifests of the webhooks before it", func() {
+					Expect(err).To(BeNil())
+					Expect(manifests).To(Equal([]map[string]interface{}{
+						{
+							"kind": "Deployment",
+							"spec": map[string]interface{}{
+								"proxy": true,
+							},
+						},
+					}))
+				})
+			})
+		})
+	})
+})
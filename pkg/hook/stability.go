@@ -0,0 +1,254 @@
+package hook
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"log"
+	"net/http"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/billiford/go-clouddriver/pkg/kubernetes/manifest"
+	"github.com/gin-gonic/gin"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+const (
+	StabilityCheckerInstanceKey = `StabilityChecker`
+
+	StabilityStable   = `stable`
+	StabilityUnstable = `unstable`
+	StabilityFailed   = `failed`
+)
+
+var (
+	defaultStabilityConfigPath = "/opt/spinnaker/kubernetes/stability.json"
+
+	stabilityCalls = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "clouddriver_stability_webhook_calls_total",
+		Help: "Number of stability webhooks called by webhook and result.",
+	}, []string{"webhook", "result"})
+)
+
+func init() {
+	prometheus.MustRegister(stabilityCalls)
+}
+
+// StabilityRequest is posted to a stability webhook with the live manifest
+// of a resource.
+type StabilityRequest struct {
+	Account  string                 `json:"account"`
+	Manifest map[string]interface{} `json:"manifest"`
+}
+
+// StabilityResponse is the response of a stability webhook, where status is
+// one of stable, unstable or failed.
+type StabilityResponse struct {
+	Status  string `json:"status"`
+	Message string `json:"message,omitempty"`
+}
+
+// StabilityChecker delegates the stability of resources to the stability
+// webhooks of their account and kind.
+//
+//go:generate counterfeiter . StabilityChecker
+type StabilityChecker interface {
+	// Status returns the status of a live manifest as decided by the
+	// stability webhook of the account and the manifest's kind, or false
+	// if there is none.
+	Status(context.Context, string, map[string]interface{}) (manifest.Status, bool)
+}
+
+// StabilityConfig lists the stability webhooks of each account.
+//
+//	{
+//	  "accounts": {
+//	    "prod-account": [
+//	      {
+//	        "name": "argo-rollouts",
+//	        "kinds": ["Rollout"],
+//	        "url": "https://stability.example.com/rollouts",
+//	        "timeoutSeconds": 5
+//	      }
+//	    ]
+//	  }
+//	}
+type StabilityConfig struct {
+	Accounts map[string][]StabilityWebhook `json:"accounts"`
+}
+
+// StabilityWebhook decides the stability of the resources of the listed kinds.
+type StabilityWebhook struct {
+	Name  string   `json:"name"`
+	Kinds []string `json:"kinds"`
+	URL   string   `json:"url"`
+	// TimeoutSeconds defaults to 10.
+	TimeoutSeconds int `json:"timeoutSeconds,omitempty"`
+}
+
+func (w StabilityWebhook) timeout() time.Duration {
+	if w.TimeoutSeconds > 0 {
+		return time.Duration(w.TimeoutSeconds) * time.Second
+	}
+
+	return defaultTimeout
+}
+
+// NewDefaultStabilityChecker reads the stability webhooks from /opt/spinnaker/kubernetes/stability.json.
+// Stability webhooks are optional, so if the file does not exist it returns nil.
+func NewDefaultStabilityChecker() (StabilityChecker, error) {
+	if _, err := os.Stat(defaultStabilityConfigPath); os.IsNotExist(err) {
+		return nil, nil
+	}
+
+	return NewStabilityChecker(defaultStabilityConfigPath)
+}
+
+// NewStabilityChecker reads the stability webhooks from a JSON file.
+func NewStabilityChecker(path string) (StabilityChecker, error) {
+	b, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	config := StabilityConfig{}
+
+	err = json.Unmarshal(b, &config)
+	if err != nil {
+		return nil, err
+	}
+
+	return NewStabilityCheckerWithConfig(config)
+}
+
+// NewStabilityCheckerWithConfig returns a stability checker for the webhooks of config.
+func NewStabilityCheckerWithConfig(config StabilityConfig) (StabilityChecker, error) {
+	for account, webhooks := range config.Accounts {
+		for _, w := range webhooks {
+			if w.Name == "" {
+				return nil, fmt.Errorf("no \"name\" found in stability webhook of account %s", account)
+			}
+
+			if len(w.Kinds) == 0 {
+				return nil, fmt.Errorf("no \"kinds\" found in stability webhook %s of account %s", w.Name, account)
+			}
+
+			if w.URL == "" {
+				return nil, fmt.Errorf("no \"url\" found in stability webhook %s of account %s", w.Name, account)
+			}
+		}
+	}
+
+	return &stabilityChecker{
+		config:     config,
+		httpClient: &http.Client{},
+	}, nil
+}
+
+type stabilityChecker struct {
+	config     StabilityConfig
+	httpClient *http.Client
+}
+
+// Status posts the manifest to the first stability webhook of the account
+// listing its kind. Resources are unstable while their webhook cannot be
+// called, so deploys wait for it rather than passing or failing.
+func (sc *stabilityChecker) Status(ctx context.Context, account string, m map[string]interface{}) (manifest.Status, bool) {
+	kind, _ := m["kind"].(string)
+
+	for _, w := range sc.config.Accounts[account] {
+		if kind == "" || !matches(w.Kinds, kind) {
+			continue
+		}
+
+		sr, err := sc.post(ctx, w, StabilityRequest{Account: account, Manifest: m})
+		if err != nil {
+			stabilityCalls.WithLabelValues(w.Name, "error").Inc()
+			log.Println("[STABILITY] error calling stability webhook", w.Name, "of account", account+":", err.Error())
+
+			return unstable(fmt.Sprintf("Error calling stability webhook %s: %s", w.Name, err.Error())), true
+		}
+
+		stabilityCalls.WithLabelValues(w.Name, sr.Status).Inc()
+
+		switch sr.Status {
+		case StabilityStable:
+			s := manifest.DefaultStatus
+			s.Stable.Message = sr.Message
+
+			return s, true
+		case StabilityFailed:
+			s := unstable(sr.Message)
+			s.Failed.State = true
+			s.Failed.Message = sr.Message
+
+			return s, true
+		default:
+			return unstable(sr.Message), true
+		}
+	}
+
+	return manifest.Status{}, false
+}
+
+func unstable(message string) manifest.Status {
+	s := manifest.DefaultStatus
+	s.Stable.State = false
+	s.Stable.Message = message
+	s.Available.State = false
+	s.Available.Message = message
+
+	return s
+}
+
+func (sc *stabilityChecker) post(ctx context.Context, w StabilityWebhook, sr StabilityRequest) (StabilityResponse, error) {
+	res := StabilityResponse{}
+
+	ctx, cancel := context.WithTimeout(ctx, w.timeout())
+	defer cancel()
+
+	b, err := json.Marshal(sr)
+	if err != nil {
+		return res, err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, w.URL, bytes.NewReader(b))
+	if err != nil {
+		return res, err
+	}
+
+	req.Header.Set("Content-Type", "application/json")
+
+	r, err := sc.httpClient.Do(req)
+	if err != nil {
+		return res, err
+	}
+	defer r.Body.Close()
+
+	if r.StatusCode < http.StatusOK || r.StatusCode >= http.StatusMultipleChoices {
+		return res, fmt.Errorf("webhook returned status %d", r.StatusCode)
+	}
+
+	err = json.NewDecoder(r.Body).Decode(&res)
+	if err != nil {
+		return res, err
+	}
+
+	res.Status = strings.ToLower(res.Status)
+	if res.Status != StabilityStable && res.Status != StabilityUnstable && res.Status != StabilityFailed {
+		return res, fmt.Errorf("webhook returned unknown status %q", res.Status)
+	}
+
+	return res, nil
+}
+
+// StabilityCheckerInstance returns the StabilityChecker, or nil if no
+// stability webhooks are configured.
+func StabilityCheckerInstance(c *gin.Context) StabilityChecker {
+	sc, _ := c.MustGet(StabilityCheckerInstanceKey).(StabilityChecker)
+	return sc
+}
@@ -0,0 +1,233 @@
+package hook_test
+
+import (
+	"context"
+	"io/ioutil"
+	"log"
+	"net/http"
+
+	. "github.com/billiford/go-clouddriver/pkg/hook"
+	"github.com/billiford/go-clouddriver/pkg/kubernetes/manifest"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"github.com/onsi/gomega/ghttp"
+)
+
+var _ = Describe("Stability", func() {
+	var (
+		sc  StabilityChecker
+		err error
+	)
+
+	BeforeEach(func() {
+		log.SetOutput(ioutil.Discard)
+	})
+
+	Describe("#NewStabilityChecker", func() {
+		When("the file does not exist", func() {
+			BeforeEach(func() {
+				sc, err = NewStabilityChecker("test/missing.json")
+			})
+
+			It("returns an error", func() {
+				Expect(err).ToNot(BeNil())
+			})
+		})
+
+		When("it succeeds", func() {
+			BeforeEach(func() {
+				sc, err = NewStabilityChecker("test/stability.json")
+			})
+
+			It("returns a stability checker", func() {
+				Expect(err).To(BeNil())
+				Expect(sc).ToNot(BeNil())
+			})
+		})
+	})
+
+	Describe("#NewStabilityCheckerWithConfig", func() {
+		var config StabilityConfig
+
+		BeforeEach(func() {
+			config = StabilityConfig{
+				Accounts: map[string][]StabilityWebhook{
+					"prod-account": {
+						{
+							Name:  "argo-rollouts",
+							Kinds: []string{"Rollout"},
+							URL:   "https://stability.example.com/rollouts",
+						},
+					},
+				},
+			}
+		})
+
+		JustBeforeEach(func() {
+			sc, err = NewStabilityCheckerWithConfig(config)
+		})
+
+		When("a webhook has no name", func() {
+			BeforeEach(func() {
+				config.Accounts["prod-account"][0].Name = ""
+			})
+
+			It("returns an error", func() {
+				Expect(err).ToNot(BeNil())
+				Expect(err.Error()).To(Equal(`no "name" found in stability webhook of account prod-account`))
+			})
+		})
+
+		When("a webhook has no kinds", func() {
+			BeforeEach(func() {
+				config.Accounts["prod-account"][0].Kinds = nil
+			})
+
+			It("returns an error", func() {
+				Expect(err).ToNot(BeNil())
+				Expect(err.Error()).To(Equal(`no "kinds" found in stability webhook argo-rollouts of account prod-account`))
+			})
+		})
+
+		When("a webhook has no URL", func() {
+			BeforeEach(func() {
+				config.Accounts["prod-account"][0].URL = ""
+			})
+
+			It("returns an error", func() {
+				Expect(err).ToNot(BeNil())
+				Expect(err.Error()).To(Equal(`no "url" found in stability webhook argo-rollouts of account prod-account`))
+			})
+		})
+	})
+
+	Describe("#Status", func() {
+		var (
+			server  *ghttp.Server
+			account string
+			m       map[string]interface{}
+			status  manifest.Status
+			ok      bool
+		)
+
+		BeforeEach(func() {
+			server = ghttp.NewServer()
+			sc, err = NewStabilityCheckerWithConfig(StabilityConfig{
+				Accounts: map[string][]StabilityWebhook{
+					"prod-account": {
+						{
+							Name:  "argo-rollouts",
+							Kinds: []string{"rollout"},
+							URL:   server.URL() + "/rollouts",
+						},
+					},
+				},
+			})
+			Expect(err).To(BeNil())
+			account = "prod-account"
+			m = map[string]interface{}{
+				"kind": "Rollout",
+				"metadata": map[string]interface{}{
+					"name": "my-rollout",
+				},
+			}
+		})
+
+		AfterEach(func() {
+			server.Close()
+		})
+
+		JustBeforeEach(func() {
+			status, ok = sc.Status(context.Background(), account, m)
+		})
+
+		When("the account has no webhook for the kind", func() {
+			BeforeEach(func() {
+				m["kind"] = "Deployment"
+			})
+
+			It("returns false", func() {
+				Expect(ok).To(BeFalse())
+				Expect(server.ReceivedRequests()).To(BeEmpty())
+			})
+		})
+
+		When("the account has no webhooks", func() {
+			BeforeEach(func() {
+				account = "test-account"
+			})
+
+			It("returns false", func() {
+				Expect(ok).To(BeFalse())
+			})
+		})
+
+		When("the webhook returns stable", func() {
+			BeforeEach(func() {
+				server.AppendHandlers(ghttp.CombineHandlers(
+					ghttp.VerifyRequest(http.MethodPost, "/rollouts"),
+					ghttp.VerifyContentType("application/json"),
+					ghttp.VerifyJSON(`{"account":"prod-account","manifest":{"kind":"Rollout","metadata":{"name":"my-rollout"}}}`),
+					ghttp.RespondWith(http.StatusOK, `{"status":"stable"}`),
+				))
+			})
+
+			It("returns a stable status", func() {
+				Expect(ok).To(BeTrue())
+				Expect(status).To(Equal(manifest.DefaultStatus))
+			})
+		})
+
+		When("the webhook returns unstable", func() {
+			BeforeEach(func() {
+				server.AppendHandlers(ghttp.RespondWith(http.StatusOK, `{"status":"Unstable","message":"Waiting for canary analysis"}`))
+			})
+
+			It("returns an unstable status", func() {
+				Expect(ok).To(BeTrue())
+				Expect(status.Stable.State).To(BeFalse())
+				Expect(status.Stable.Message).To(Equal("Waiting for canary analysis"))
+				Expect(status.Available.State).To(BeFalse())
+				Expect(status.Failed.State).To(BeFalse())
+			})
+		})
+
+		When("the webhook returns failed", func() {
+			BeforeEach(func() {
+				server.AppendHandlers(ghttp.RespondWith(http.StatusOK, `{"status":"failed","message":"Canary analysis failed"}`))
+			})
+
+			It("returns a failed status", func() {
+				Expect(ok).To(BeTrue())
+				Expect(status.Stable.State).To(BeFalse())
+				Expect(status.Failed.State).To(BeTrue())
+				Expect(status.Failed.Message).To(Equal("Canary analysis failed"))
+			})
+		})
+
+		When("the webhook returns an unknown status", func() {
+			BeforeEach(func() {
+				server.AppendHandlers(ghttp.RespondWith(http.StatusOK, `{"status":"ready"}`))
+			})
+
+			It("returns an unstable status with the error", func() {
+				Expect(ok).To(BeTrue())
+				Expect(status.Stable.State).To(BeFalse())
+				Expect(status.Stable.Message).To(Equal(`Error calling stability webhook argo-rollouts: webhook returned unknown status "ready"`))
+			})
+		})
+
+		When("the webhook returns an error status", func() {
+			BeforeEach(func() {
+				server.AppendHandlers(ghttp.RespondWith(http.StatusServiceUnavailable, ""))
+			})
+
+			It("returns an unstable status with the error", func() {
+				Expect(ok).To(BeTrue())
+				Expect(status.Stable.State).To(BeFalse())
+				Expect(status.Failed.State).To(BeFalse())
+				Expect(status.Stable.Message).To(Equal("Error calling stability webhook argo-rollouts: webhook returned status 503"))
+			})
+		})
+	})
+})
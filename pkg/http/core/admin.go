@@ -0,0 +1,401 @@
+package core
+
+import (
+	"bytes"
+	"encoding/base64"
+	"fmt"
+	"io/ioutil"
+	"log"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+
+	clouddriver "github.com/billiford/go-clouddriver/pkg"
+	"github.com/billiford/go-clouddriver/pkg/arcade"
+	"github.com/billiford/go-clouddriver/pkg/kubernetes"
+	"github.com/billiford/go-clouddriver/pkg/queue"
+	"github.com/billiford/go-clouddriver/pkg/routine"
+	"github.com/billiford/go-clouddriver/pkg/sql"
+	jsonpatch "github.com/evanphx/json-patch"
+	"github.com/gin-gonic/gin"
+	"github.com/jinzhu/gorm"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/rest"
+)
+
+// Unreachable clusters should not hold up listing the health of the others.
+const accountHealthTimeout = 5 * time.Second
+
+// AdminAccount is an account with the health of its cluster.
+type AdminAccount struct {
+	Name               string `json:"name"`
+	Host               string `json:"host"`
+	WriteMode          string `json:"writeMode,omitempty"`
+	Maintenance        bool   `json:"maintenance"`
+	MaintenanceMessage string `json:"maintenanceMessage,omitempty"`
+	Healthy            bool   `json:"healthy"`
+	Error              string `json:"error,omitempty"`
+	LatencyMillis      int64  `json:"latencyMillis"`
+}
+
+// AdminQueue is the status of the operation queue.
+type AdminQueue struct {
+	Enabled  bool                  `json:"enabled"`
+	Accounts []queue.AccountStatus `json:"accounts"`
+}
+
+// AdminPermission is whether an account may perform a verb on a resource.
+type AdminPermission struct {
+	Verb      string `json:"verb"`
+	Group     string `json:"group"`
+	Resource  string `json:"resource"`
+	Namespace string `json:"namespace,omitempty"`
+	Allowed   bool   `json:"allowed"`
+	Error     string `json:"error,omitempty"`
+}
+
+// probedPermissions are the permissions Clouddriver needs to cache, deploy
+// and operate on the resources of an account.
+var probedPermissions = []AdminPermission{
+	{Verb: "list", Resource: "namespaces"},
+	{Verb: "list", Resource: "pods"},
+	{Verb: "get", Resource: "pods/log"},
+	{Verb: "list", Resource: "events"},
+	{Verb: "create", Resource: "configmaps"},
+	{Verb: "create", Resource: "secrets"},
+	{Verb: "create", Resource: "services"},
+	{Verb: "list", Group: "apps", Resource: "deployments"},
+	{Verb: "create", Group: "apps", Resource: "deployments"},
+	{Verb: "patch", Group: "apps", Resource: "deployments"},
+	{Verb: "delete", Group: "apps", Resource: "deployments"},
+	{Verb: "list", Group: "apps", Resource: "replicasets"},
+	{Verb: "patch", Group: "apps", Resource: "statefulsets"},
+	{Verb: "create", Group: "batch", Resource: "jobs"},
+	{Verb: "create", Group: "networking.k8s.io", Resource: "ingresses"},
+}
+
+// AdminGoroutines are the goroutines of this instance, to find leaks.
+type AdminGoroutines struct {
+	Total    int             `json:"total"`
+	Routines map[string]int  `json:"routines"`
+	Stacks   []routine.Stack `json:"stacks"`
+}
+
+// Stacks shared by fewer goroutines than this are left out of
+// /admin/goroutines unless ?min= is given.
+const defaultGoroutineStackMin = 10
+
+// ToggleFeatureRequest enables or disables a feature.
+type ToggleFeatureRequest struct {
+	Enabled bool `json:"enabled"`
+}
+
+// ListAdminAccounts returns every account with the health of its cluster,
+// checked by listing a namespace from each cluster at once. A cluster is
+// healthy if it accepts the account's credentials, even if the account
+// may not list namespaces.
+func ListAdminAccounts(c *gin.Context) {
+	sc := sql.ReadOnlyInstance(c)
+	ac := arcade.Instance(c)
+	kc := kubernetes.ControllerInstance(c)
+
+	providers, err := sc.ListKubernetesProviders()
+	if err != nil {
+		clouddriver.WriteError(c, http.StatusInternalServerError, err)
+		return
+	}
+
+	accounts := make([]AdminAccount, len(providers))
+	wg := &sync.WaitGroup{}
+
+	for i, provider := range providers {
+		wg.Add(1)
+
+		go func(i int, provider kubernetes.Provider) {
+			defer wg.Done()
+
+			a := AdminAccount{
+				Name:               provider.Name,
+				Host:               provider.Host,
+				WriteMode:          provider.WriteMode,
+				Maintenance:        provider.Maintenance,
+				MaintenanceMessage: provider.MaintenanceMessage,
+			}
+
+			start := time.Now()
+
+			err := checkAccountHealth(provider, ac, kc)
+			if err != nil {
+				a.Error = err.Error()
+			} else {
+				a.Healthy = true
+			}
+
+			a.LatencyMillis = time.Since(start).Milliseconds()
+			accounts[i] = a
+		}(i, provider)
+	}
+
+	wg.Wait()
+
+	c.JSON(http.StatusOK, accounts)
+}
+
+func checkAccountHealth(provider kubernetes.Provider, ac arcade.Client, kc kubernetes.Controller) error {
+	client, err := adminClient(provider, ac, kc, accountHealthTimeout)
+	if err != nil {
+		return err
+	}
+
+	_, err = client.ListMetadataByGVR(schema.GroupVersionResource{Version: "v1", Resource: "namespaces"},
+		metav1.ListOptions{TimeoutSeconds: &listNamespacesTimeout, Limit: 1})
+	if err != nil && !k8serrors.IsForbidden(err) {
+		return err
+	}
+
+	return nil
+}
+
+// RefreshAdminAccountCache drops everything cached for an account: its
+// namespaces, permissions and API discovery. They are read again from the
+// cluster and database on the next request.
+func RefreshAdminAccountCache(c *gin.Context) {
+	ac := arcade.Instance(c)
+	kc := kubernetes.ControllerInstance(c)
+	nc := kubernetes.NamespaceCacheInstance(c)
+	pc := kubernetes.PermissionsCacheInstance(c)
+	provider := kubernetes.ProviderInstance(c)
+
+	client, err := adminClient(provider, ac, kc, 0)
+	if err != nil {
+		clouddriver.WriteError(c, http.StatusInternalServerError, err)
+		return
+	}
+
+	nc.Delete(provider.Name)
+	pc.Delete(provider.Name)
+	client.InvalidateDiscovery()
+
+	log.Println("[ADMIN] refreshed caches of account", provider.Name, "by", requestUser(c))
+
+	c.JSON(http.StatusNoContent, nil)
+}
+
+// ProbeAdminAccountPermissions asks the cluster of an account which of the
+// permissions Clouddriver needs its credentials have, in the namespace of
+// the "namespace" query parameter or else in all namespaces.
+func ProbeAdminAccountPermissions(c *gin.Context) {
+	ac := arcade.Instance(c)
+	kc := kubernetes.ControllerInstance(c)
+	provider := kubernetes.ProviderInstance(c)
+	namespace := c.Query("namespace")
+
+	client, err := adminClient(provider, ac, kc, accountHealthTimeout)
+	if err != nil {
+		clouddriver.WriteError(c, http.StatusInternalServerError, err)
+		return
+	}
+
+	permissions := make([]AdminPermission, len(probedPermissions))
+
+	for i, p := range probedPermissions {
+		p.Namespace = namespace
+
+		allowed, err := client.CanI(p.Verb, p.Group, p.Resource, p.Namespace)
+		if err != nil {
+			p.Error = err.Error()
+		}
+
+		p.Allowed = allowed
+		permissions[i] = p
+	}
+
+	c.JSON(http.StatusOK, permissions)
+}
+
+func adminClient(provider kubernetes.Provider, ac arcade.Client,
+	kc kubernetes.Controller, timeout time.Duration) (kubernetes.Client, error) {
+	cd, err := base64.StdEncoding.DecodeString(provider.CAData)
+	if err != nil {
+		return nil, fmt.Errorf("error decoding provider ca data: %w", err)
+	}
+
+	token, err := ac.Token()
+	if err != nil {
+		return nil, fmt.Errorf("error getting arcade token: %w", err)
+	}
+
+	config := &rest.Config{
+		Host:        provider.Host,
+		BearerToken: token,
+		Timeout:     timeout,
+		TLSClientConfig: rest.TLSClientConfig{
+			CAData: cd,
+		},
+	}
+
+	err = kc.MintToken(provider, config)
+	if err != nil {
+		return nil, fmt.Errorf("error minting token: %w", err)
+	}
+
+	client, err := kc.NewClient(config)
+	if err != nil {
+		return nil, fmt.Errorf("error creating dynamic account: %w", err)
+	}
+
+	return client, nil
+}
+
+// GetAdminQueue returns the tokens and waiting operations of each account
+// in the operation queue.
+func GetAdminQueue(c *gin.Context) {
+	q := queue.Instance(c)
+	if q == nil {
+		c.JSON(http.StatusOK, AdminQueue{Accounts: []queue.AccountStatus{}})
+		return
+	}
+
+	c.JSON(http.StatusOK, AdminQueue{Enabled: true, Accounts: q.Status()})
+}
+
+// GetAdminGoroutines returns the number of goroutines of this instance, the
+// background routines running by name and the stacks shared by at least
+// ?min= goroutines, the most shared first. A stack that keeps growing across
+// calls is likely leaked.
+func GetAdminGoroutines(c *gin.Context) {
+	min := defaultGoroutineStackMin
+
+	if m := c.Query("min"); m != "" {
+		var err error
+
+		min, err = strconv.Atoi(m)
+		if err != nil || min < 1 {
+			clouddriver.WriteError(c, http.StatusBadRequest, fmt.Errorf("invalid min %q, must be a positive number", m))
+			return
+		}
+	}
+
+	total, stacks, err := routine.Stacks()
+	if err != nil {
+		clouddriver.WriteError(c, http.StatusInternalServerError, err)
+		return
+	}
+
+	ag := AdminGoroutines{
+		Total:    total,
+		Routines: routine.Running(),
+		Stacks:   []routine.Stack{},
+	}
+
+	for _, s := range stacks {
+		if s.Count >= min {
+			ag.Stacks = append(ag.Stacks, s)
+		}
+	}
+
+	c.JSON(http.StatusOK, ag)
+}
+
+// ToggleAdminStage enables or disables a stage listed by /features/stages.
+// Toggles are stored in the database, so they apply to every instance.
+// Only supported stages may be enabled.
+func ToggleAdminStage(c *gin.Context) {
+	sc := sql.Instance(c)
+	name := c.Param("name")
+	tfr := ToggleFeatureRequest{}
+
+	err := c.ShouldBindJSON(&tfr)
+	if err != nil {
+		clouddriver.WriteError(c, http.StatusBadRequest, err)
+		return
+	}
+
+	if !knownStage(name) {
+		clouddriver.WriteError(c, http.StatusNotFound, fmt.Errorf("stage %s not found", name))
+		return
+	}
+
+	if tfr.Enabled && !supportedStages[name] {
+		clouddriver.WriteError(c, http.StatusUnprocessableEntity,
+			fmt.Errorf("stage %s is not supported by the Kubernetes provider", name))
+		return
+	}
+
+	f := clouddriver.Feature{Name: name, Enabled: tfr.Enabled}
+
+	err = sc.SetFeature(f)
+	if err != nil {
+		clouddriver.WriteError(c, http.StatusInternalServerError, err)
+		return
+	}
+
+	log.Println("[ADMIN] set stage", name, "enabled to", tfr.Enabled, "by", requestUser(c))
+
+	c.JSON(http.StatusOK, Stage{Name: name, Enabled: tfr.Enabled})
+}
+
+// ReplayAdminTask runs the operations of a failed task again under a new
+// task ID. The request body may be a JSON patch (RFC 6902) applied to the
+// payload of the task first, such as to fix a field that caused it to fail.
+// The operations are run as if Orca had sent them again, so maintenance,
+// freezes, hooks and locks apply, and the response is that of /kubernetes/ops.
+func ReplayAdminTask(c *gin.Context) {
+	sc := sql.Instance(c)
+	id := c.Param("id")
+
+	fo, err := sc.GetFailedOperation(id)
+	if err != nil {
+		if err == gorm.ErrRecordNotFound {
+			clouddriver.WriteError(c, http.StatusNotFound, fmt.Errorf("failed task %s not found", id))
+			return
+		}
+
+		clouddriver.WriteError(c, http.StatusInternalServerError, err)
+
+		return
+	}
+
+	b, err := c.GetRawData()
+	if err != nil {
+		clouddriver.WriteError(c, http.StatusBadRequest, err)
+		return
+	}
+
+	payload := []byte(fo.Payload)
+
+	if len(bytes.TrimSpace(b)) > 0 {
+		patch, err := jsonpatch.DecodePatch(b)
+		if err != nil {
+			clouddriver.WriteError(c, http.StatusBadRequest, fmt.Errorf("error decoding patch: %w", err))
+			return
+		}
+
+		payload, err = patch.Apply(payload)
+		if err != nil {
+			clouddriver.WriteError(c, http.StatusBadRequest, fmt.Errorf("error applying patch: %w", err))
+			return
+		}
+	}
+
+	log.Println("[ADMIN] replayed failed task", id, "by", requestUser(c))
+
+	c.Request.Body = ioutil.NopCloser(bytes.NewReader(payload))
+	c.Request.ContentLength = int64(len(payload))
+	c.Request.Header.Set("X-Spinnaker-Application", fo.Application)
+
+	CreateKubernetesOperation(c)
+}
+
+func knownStage(name string) bool {
+	for _, stage := range stages {
+		if stage == name {
+			return true
+		}
+	}
+
+	return false
+}
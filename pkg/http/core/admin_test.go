@@ -0,0 +1,549 @@
+package core_test
+
+import (
+	"encoding/json"
+	"errors"
+	"io/ioutil"
+	"net/http"
+
+	clouddriver "github.com/billiford/go-clouddriver/pkg"
+	"github.com/billiford/go-clouddriver/pkg/fiat"
+	"github.com/billiford/go-clouddriver/pkg/http/core"
+	"github.com/billiford/go-clouddriver/pkg/kubernetes"
+	"github.com/billiford/go-clouddriver/pkg/queue"
+	"github.com/jinzhu/gorm"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+var _ = Describe("Admin", func() {
+	// createAdminRequest creates a request made by a Fiat admin.
+	createAdminRequest := func(method string) {
+		createRequest(method)
+		req.Header.Set("X-Spinnaker-User", "test-admin")
+		fakeFiatClient.AuthorizeReturns(fiat.Response{Name: "test-admin", Admin: true}, nil)
+	}
+
+	Describe("admin auth", func() {
+		BeforeEach(func() {
+			setup()
+			uri = svr.URL + "/admin/queue"
+			createAdminRequest(http.MethodGet)
+		})
+
+		AfterEach(func() {
+			teardown()
+		})
+
+		JustBeforeEach(func() {
+			doRequest()
+		})
+
+		When("the user is not an admin", func() {
+			BeforeEach(func() {
+				fakeFiatClient.AuthorizeReturns(fiat.Response{Name: "test-admin"}, nil)
+			})
+
+			It("returns status forbidden", func() {
+				Expect(res.StatusCode).To(Equal(http.StatusForbidden))
+				ce := getClouddriverError()
+				Expect(ce.Message).To(Equal("user test-admin is not an admin"))
+			})
+		})
+
+		When("there is no user", func() {
+			BeforeEach(func() {
+				req.Header.Del("X-Spinnaker-User")
+			})
+
+			It("returns status unauthorized", func() {
+				Expect(res.StatusCode).To(Equal(http.StatusUnauthorized))
+			})
+		})
+	})
+
+	Describe("#ListAdminAccounts", func() {
+		var accounts []core.AdminAccount
+
+		BeforeEach(func() {
+			setup()
+			uri = svr.URL + "/admin/accounts"
+			createAdminRequest(http.MethodGet)
+			fakeSQLClient.ListKubernetesProvidersReturns([]kubernetes.Provider{
+				{
+					Name:        "test-account",
+					Host:        "https://test-host",
+					Maintenance: true,
+				},
+			}, nil)
+			accounts = nil
+		})
+
+		AfterEach(func() {
+			teardown()
+		})
+
+		JustBeforeEach(func() {
+			doRequest()
+
+			if res.StatusCode == http.StatusOK {
+				b, _ := ioutil.ReadAll(res.Body)
+				Expect(json.Unmarshal(b, &accounts)).To(Succeed())
+			}
+		})
+
+		When("listing providers fails", func() {
+			BeforeEach(func() {
+				fakeSQLClient.ListKubernetesProvidersReturns(nil, errors.New("error listing providers"))
+			})
+
+			It("returns status internal server error", func() {
+				Expect(res.StatusCode).To(Equal(http.StatusInternalServerError))
+			})
+		})
+
+		When("the cluster cannot be reached", func() {
+			BeforeEach(func() {
+				fakeKubeClient.ListMetadataByGVRReturns(nil, errors.New("dial tcp: i/o timeout"))
+			})
+
+			It("returns the account as unhealthy", func() {
+				Expect(res.StatusCode).To(Equal(http.StatusOK))
+				Expect(accounts).To(HaveLen(1))
+				Expect(accounts[0].Healthy).To(BeFalse())
+				Expect(accounts[0].Error).To(Equal("dial tcp: i/o timeout"))
+			})
+		})
+
+		When("the account may not list namespaces", func() {
+			BeforeEach(func() {
+				fakeKubeClient.ListMetadataByGVRReturns(nil, k8serrors.NewForbidden(schema.GroupResource{Resource: "namespaces"}, "", errors.New("forbidden")))
+			})
+
+			It("returns the account as healthy", func() {
+				Expect(res.StatusCode).To(Equal(http.StatusOK))
+				Expect(accounts[0].Healthy).To(BeTrue())
+			})
+		})
+
+		When("it succeeds", func() {
+			It("returns the accounts with their health", func() {
+				Expect(res.StatusCode).To(Equal(http.StatusOK))
+				Expect(accounts).To(HaveLen(1))
+				Expect(accounts[0].Name).To(Equal("test-account"))
+				Expect(accounts[0].Host).To(Equal("https://test-host"))
+				Expect(accounts[0].Maintenance).To(BeTrue())
+				Expect(accounts[0].Healthy).To(BeTrue())
+				Expect(accounts[0].Error).To(BeEmpty())
+				config := fakeKubeController.NewClientArgsForCall(0)
+				Expect(config.Timeout.Seconds()).To(Equal(float64(5)))
+			})
+		})
+	})
+
+	Describe("#RefreshAdminAccountCache", func() {
+		BeforeEach(func() {
+			setup()
+			uri = svr.URL + "/admin/accounts/test-account/cache/refresh"
+			createAdminRequest(http.MethodPost)
+		})
+
+		AfterEach(func() {
+			teardown()
+		})
+
+		JustBeforeEach(func() {
+			doRequest()
+		})
+
+		When("creating the client fails", func() {
+			BeforeEach(func() {
+				fakeKubeController.NewClientReturns(nil, errors.New("error creating client"))
+			})
+
+			It("returns status internal server error", func() {
+				Expect(res.StatusCode).To(Equal(http.StatusInternalServerError))
+				Expect(fakeKubeNamespaceCache.DeleteCallCount()).To(BeZero())
+			})
+		})
+
+		When("it succeeds", func() {
+			It("drops the caches of the account", func() {
+				Expect(res.StatusCode).To(Equal(http.StatusNoContent))
+				Expect(fakeKubeNamespaceCache.DeleteCallCount()).To(Equal(1))
+				Expect(fakeKubeNamespaceCache.DeleteArgsForCall(0)).To(Equal("test-account"))
+				Expect(fakeKubePermissionsCache.DeleteCallCount()).To(Equal(1))
+				Expect(fakeKubePermissionsCache.DeleteArgsForCall(0)).To(Equal("test-account"))
+				Expect(fakeKubeClient.InvalidateDiscoveryCallCount()).To(Equal(1))
+			})
+		})
+	})
+
+	Describe("#ProbeAdminAccountPermissions", func() {
+		var permissions []core.AdminPermission
+
+		BeforeEach(func() {
+			setup()
+			uri = svr.URL + "/admin/accounts/test-account/permissions?namespace=test-namespace"
+			createAdminRequest(http.MethodGet)
+			fakeKubeClient.CanIStub = func(verb, group, resource, namespace string) (bool, error) {
+				return verb != "delete", nil
+			}
+			permissions = nil
+		})
+
+		AfterEach(func() {
+			teardown()
+		})
+
+		JustBeforeEach(func() {
+			doRequest()
+
+			if res.StatusCode == http.StatusOK {
+				b, _ := ioutil.ReadAll(res.Body)
+				Expect(json.Unmarshal(b, &permissions)).To(Succeed())
+			}
+		})
+
+		When("creating the client fails", func() {
+			BeforeEach(func() {
+				fakeKubeController.NewClientReturns(nil, errors.New("error creating client"))
+			})
+
+			It("returns status internal server error", func() {
+				Expect(res.StatusCode).To(Equal(http.StatusInternalServerError))
+			})
+		})
+
+		When("reviewing a permission fails", func() {
+			BeforeEach(func() {
+				fakeKubeClient.CanIReturns(false, errors.New("error reviewing access"))
+			})
+
+			It("returns the error of each permission", func() {
+				Expect(res.StatusCode).To(Equal(http.StatusOK))
+				Expect(permissions).ToNot(BeEmpty())
+				Expect(permissions[0].Allowed).To(BeFalse())
+				Expect(permissions[0].Error).To(Equal("error reviewing access"))
+			})
+		})
+
+		When("it succeeds", func() {
+			It("returns whether each permission is allowed", func() {
+				Expect(res.StatusCode).To(Equal(http.StatusOK))
+				Expect(permissions).To(ContainElement(core.AdminPermission{
+					Verb:      "list",
+					Resource:  "namespaces",
+					Namespace: "test-namespace",
+					Allowed:   true,
+				}))
+				Expect(permissions).To(ContainElement(core.AdminPermission{
+					Verb:      "delete",
+					Group:     "apps",
+					Resource:  "deployments",
+					Namespace: "test-namespace",
+					Allowed:   false,
+				}))
+				verb, group, resource, namespace := fakeKubeClient.CanIArgsForCall(0)
+				Expect(verb).To(Equal("list"))
+				Expect(group).To(BeEmpty())
+				Expect(resource).To(Equal("namespaces"))
+				Expect(namespace).To(Equal("test-namespace"))
+			})
+		})
+	})
+
+	Describe("#GetAdminGoroutines", func() {
+		var ag core.AdminGoroutines
+
+		BeforeEach(func() {
+			setup()
+			uri = svr.URL + "/admin/goroutines?min=1"
+			createAdminRequest(http.MethodGet)
+			ag = core.AdminGoroutines{}
+		})
+
+		AfterEach(func() {
+			teardown()
+		})
+
+		JustBeforeEach(func() {
+			doRequest()
+
+			if res.StatusCode == http.StatusOK {
+				b, _ := ioutil.ReadAll(res.Body)
+				Expect(json.Unmarshal(b, &ag)).To(Succeed())
+			}
+		})
+
+		When("min is not a positive number", func() {
+			BeforeEach(func() {
+				uri = svr.URL + "/admin/goroutines?min=0"
+				createAdminRequest(http.MethodGet)
+			})
+
+			It("returns status bad request", func() {
+				Expect(res.StatusCode).To(Equal(http.StatusBadRequest))
+				ce := getClouddriverError()
+				Expect(ce.Message).To(Equal(`invalid min "0", must be a positive number`))
+			})
+		})
+
+		It("returns the goroutines of the instance by stack", func() {
+			Expect(res.StatusCode).To(Equal(http.StatusOK))
+			Expect(ag.Total).To(BeNumerically(">", 0))
+			Expect(ag.Routines).ToNot(BeNil())
+			Expect(ag.Stacks).ToNot(BeEmpty())
+
+			total := 0
+			for _, s := range ag.Stacks {
+				Expect(s.Functions).ToNot(BeEmpty())
+				total += s.Count
+			}
+
+			Expect(total).To(Equal(ag.Total))
+		})
+	})
+
+	Describe("#GetAdminQueue", func() {
+		BeforeEach(func() {
+			setup()
+			uri = svr.URL + "/admin/queue"
+			createAdminRequest(http.MethodGet)
+			fakeQueue.StatusReturns([]queue.AccountStatus{
+				{
+					Account: "test-account",
+					Tokens:  0.5,
+					Waiting: map[string]int{"background": 0, "deploy": 2, "rollback": 1},
+				},
+			})
+		})
+
+		AfterEach(func() {
+			teardown()
+		})
+
+		JustBeforeEach(func() {
+			doRequest()
+		})
+
+		It("returns the status of each account", func() {
+			Expect(res.StatusCode).To(Equal(http.StatusOK))
+			validateResponse(`{
+				"enabled": true,
+				"accounts": [
+					{
+						"account": "test-account",
+						"tokens": 0.5,
+						"waiting": {
+							"background": 0,
+							"deploy": 2,
+							"rollback": 1
+						}
+					}
+				]
+			}`)
+		})
+	})
+
+	Describe("#ReplayAdminTask", func() {
+		BeforeEach(func() {
+			setup()
+			uri = svr.URL + "/admin/tasks/test-task-id/replay"
+			createAdminRequest(http.MethodPost)
+			fakeSQLClient.GetFailedOperationReturns(clouddriver.FailedOperation{
+				TaskID:      "test-task-id",
+				Application: "test-app",
+				Payload:     payloadRequestKubernetesOpsDeployManifest,
+			}, nil)
+		})
+
+		AfterEach(func() {
+			teardown()
+		})
+
+		JustBeforeEach(func() {
+			doRequest()
+		})
+
+		When("the task is not found", func() {
+			BeforeEach(func() {
+				fakeSQLClient.GetFailedOperationReturns(clouddriver.FailedOperation{}, gorm.ErrRecordNotFound)
+			})
+
+			It("returns status not found", func() {
+				Expect(res.StatusCode).To(Equal(http.StatusNotFound))
+				ce := getClouddriverError()
+				Expect(ce.Message).To(Equal("failed task test-task-id not found"))
+			})
+		})
+
+		When("the patch does not apply", func() {
+			BeforeEach(func() {
+				body.Write([]byte(`[{"op": "replace", "path": "/5/deployManifest", "value": {}}]`))
+				createAdminRequest(http.MethodPost)
+			})
+
+			It("returns status bad request", func() {
+				Expect(res.StatusCode).To(Equal(http.StatusBadRequest))
+				ce := getClouddriverError()
+				Expect(ce.Message).To(HavePrefix("error applying patch: "))
+				Expect(fakeKubeActionHandler.NewDeployManifestActionCallCount()).To(BeZero())
+			})
+		})
+
+		When("the replay fails", func() {
+			BeforeEach(func() {
+				fakeAction.RunReturns(errors.New("error deploying manifest"))
+			})
+
+			It("stores the payload under the new task ID", func() {
+				Expect(res.StatusCode).To(Equal(http.StatusInternalServerError))
+				Expect(fakeSQLClient.CreateFailedOperationCallCount()).To(Equal(1))
+				fo := fakeSQLClient.CreateFailedOperationArgsForCall(0)
+				Expect(fo.TaskID).ToNot(Equal("test-task-id"))
+				Expect(fo.User).To(Equal("test-admin"))
+			})
+		})
+
+		When("it succeeds", func() {
+			It("runs the operations under a new task ID", func() {
+				Expect(res.StatusCode).To(Equal(http.StatusOK))
+				Expect(fakeKubeActionHandler.NewDeployManifestActionCallCount()).To(Equal(1))
+				config := fakeKubeActionHandler.NewDeployManifestActionArgsForCall(0)
+				Expect(config.ID).ToNot(Equal("test-task-id"))
+				Expect(config.Application).To(Equal("test-app"))
+				Expect(config.Operation.DeployManifest.NamespaceOverride).To(Equal("default"))
+			})
+		})
+
+		When("it succeeds with a patch", func() {
+			BeforeEach(func() {
+				body.Write([]byte(`[{"op": "replace", "path": "/0/deployManifest/namespaceOverride", "value": "fixed"}]`))
+				createAdminRequest(http.MethodPost)
+			})
+
+			It("runs the patched operations", func() {
+				Expect(res.StatusCode).To(Equal(http.StatusOK))
+				config := fakeKubeActionHandler.NewDeployManifestActionArgsForCall(0)
+				Expect(config.Operation.DeployManifest.NamespaceOverride).To(Equal("fixed"))
+			})
+		})
+	})
+
+	Describe("#ToggleAdminStage", func() {
+		BeforeEach(func() {
+			setup()
+			uri = svr.URL + "/admin/features/stages/runJob"
+			body.Write([]byte(`{"enabled":true}`))
+			createAdminRequest(http.MethodPut)
+		})
+
+		AfterEach(func() {
+			teardown()
+		})
+
+		JustBeforeEach(func() {
+			doRequest()
+		})
+
+		When("the stage is unknown", func() {
+			BeforeEach(func() {
+				uri = svr.URL + "/admin/features/stages/unknownStage"
+				createAdminRequest(http.MethodPut)
+			})
+
+			It("returns status not found", func() {
+				Expect(res.StatusCode).To(Equal(http.StatusNotFound))
+				ce := getClouddriverError()
+				Expect(ce.Message).To(Equal("stage unknownStage not found"))
+			})
+		})
+
+		When("enabling an unsupported stage", func() {
+			BeforeEach(func() {
+				uri = svr.URL + "/admin/features/stages/pauseRolloutManifest"
+				createAdminRequest(http.MethodPut)
+			})
+
+			It("returns status unprocessable entity", func() {
+				Expect(res.StatusCode).To(Equal(http.StatusUnprocessableEntity))
+				ce := getClouddriverError()
+				Expect(ce.Message).To(Equal("stage pauseRolloutManifest is not supported by the Kubernetes provider"))
+				Expect(fakeSQLClient.SetFeatureCallCount()).To(BeZero())
+			})
+		})
+
+		When("setting the feature fails", func() {
+			BeforeEach(func() {
+				fakeSQLClient.SetFeatureReturns(errors.New("error setting feature"))
+			})
+
+			It("returns status internal server error", func() {
+				Expect(res.StatusCode).To(Equal(http.StatusInternalServerError))
+			})
+		})
+
+		When("it succeeds", func() {
+			It("stores the toggle", func() {
+				Expect(res.StatusCode).To(Equal(http.StatusOK))
+				Expect(fakeSQLClient.SetFeatureArgsForCall(0)).To(Equal(clouddriver.Feature{Name: "runJob", Enabled: true}))
+				validateResponse(`{"name": "runJob", "enabled": true}`)
+			})
+		})
+	})
+})
+
+var _ = Describe("Features", func() {
+	Describe("#ListStages", func() {
+		var stages core.Stages
+
+		BeforeEach(func() {
+			setup()
+			uri = svr.URL + "/features/stages"
+			createRequest(http.MethodGet)
+			fakeSQLClient.ListFeaturesReturns([]clouddriver.Feature{
+				{Name: "runJob", Enabled: false},
+				{Name: "pauseRolloutManifest", Enabled: true},
+			}, nil)
+			stages = nil
+		})
+
+		AfterEach(func() {
+			teardown()
+		})
+
+		JustBeforeEach(func() {
+			doRequest()
+
+			if res.StatusCode == http.StatusOK {
+				b, _ := ioutil.ReadAll(res.Body)
+				Expect(json.Unmarshal(b, &stages)).To(Succeed())
+			}
+		})
+
+		When("listing features fails", func() {
+			BeforeEach(func() {
+				fakeSQLClient.ListFeaturesReturns(nil, errors.New("db down"))
+			})
+
+			It("returns status internal server error", func() {
+				Expect(res.StatusCode).To(Equal(http.StatusInternalServerError))
+				ce := getClouddriverError()
+				Expect(ce.Message).To(Equal("error listing features: db down"))
+			})
+		})
+
+		When("it succeeds", func() {
+			It("enables supported stages that are not toggled off", func() {
+				Expect(res.StatusCode).To(Equal(http.StatusOK))
+				Expect(stages).To(ContainElement(core.Stage{Name: "deployManifest", Enabled: true}))
+				Expect(stages).To(ContainElement(core.Stage{Name: "runJob", Enabled: false}))
+				Expect(stages).To(ContainElement(core.Stage{Name: "pauseRolloutManifest", Enabled: false}))
+				Expect(stages).To(ContainElement(core.Stage{Name: "destroyServerGroup", Enabled: false}))
+			})
+		})
+	})
+})
@@ -35,7 +35,10 @@ type Application struct {
 }
 
 type ApplicationAttributes struct {
-	Name string `json:"name"`
+	Name        string                   `json:"name"`
+	Email       string                   `json:"email,omitempty"`
+	Description string                   `json:"description,omitempty"`
+	Permissions *clouddriver.Permissions `json:"permissions,omitempty"`
 }
 
 const KeyAllApplications = `AllApplications`
@@ -49,9 +52,27 @@ func ListApplications(c *gin.Context) {
 		return
 	}
 
-	response := Applications{}
+	// Applications created with the createApplication operation are listed
+	// with their metadata, even if nothing has been deployed for them yet.
+	as, err := sc.ListApplications()
+	if err != nil {
+		clouddriver.WriteError(c, http.StatusInternalServerError, err)
+		return
+	}
+
+	stored := map[string]clouddriver.Application{}
 	apps := uniqueSpinnakerApps(rs)
 
+	for _, a := range as {
+		stored[a.Name] = a
+
+		if !contains(apps, a.Name) {
+			apps = append(apps, a.Name)
+		}
+	}
+
+	response := Applications{}
+
 	for _, app := range apps {
 		application := Application{
 			Attributes: ApplicationAttributes{
@@ -61,9 +82,20 @@ func ListApplications(c *gin.Context) {
 			Name:         app,
 		}
 
+		if a, ok := stored[app]; ok {
+			permissions := a.Permissions
+			application.Attributes.Email = a.Email
+			application.Attributes.Description = a.Description
+			application.Attributes.Permissions = &permissions
+		}
+
 		response = append(response, application)
 	}
 
+	sort.Slice(response, func(i, j int) bool {
+		return response[i].Name < response[j].Name
+	})
+
 	c.Set(KeyAllApplications, response)
 }
 
@@ -102,6 +134,10 @@ func clusterNamesForSpinnakerApp(application string, rs []kubernetes.Resource) m
 		}
 	}
 
+	for account := range clusterNames {
+		sort.Strings(clusterNames[account])
+	}
+
 	return clusterNames
 }
 
@@ -188,7 +224,7 @@ func ListServerGroupManagers(c *gin.Context) {
 	}
 
 	sort.Slice(response, func(i, j int) bool {
-		return response[i].Name < response[j].Name
+		return response[i].listKey().less(response[j].listKey())
 	})
 
 	c.JSON(http.StatusOK, response)
@@ -228,14 +264,14 @@ func listServerGroupManagers(c *gin.Context, wg *sync.WaitGroup, sgms chan Serve
 		},
 	}
 
-	client, err := kc.NewClient(config)
+	client, err := kubernetes.NewProviderClient(kc, provider, config)
 	if err != nil {
 		log.Println("error creating dynamic client for account", account)
 		return
 	}
 
 	lo := metav1.ListOptions{
-		LabelSelector:  kubernetes.LabelKubernetesName + "=" + application,
+		LabelSelector:  kubernetes.ManagedApplicationLabelSelector(application),
 		TimeoutSeconds: &listTimeout,
 	}
 
@@ -321,6 +357,10 @@ func buildServerGroups(replicaSets *unstructured.UnstructuredList,
 		}
 	}
 
+	sort.Slice(sgs, func(i, j int) bool {
+		return sgs[i].listKey().less(sgs[j].listKey())
+	})
+
 	return sgs
 }
 
@@ -393,7 +433,7 @@ func ListLoadBalancers(c *gin.Context) {
 	}
 
 	sort.Slice(response, func(i, j int) bool {
-		return response[i].Name < response[j].Name
+		return response[i].listKey().less(response[j].listKey())
 	})
 
 	c.JSON(http.StatusOK, response)
@@ -438,7 +478,7 @@ func listLoadBalancers(c *gin.Context, wg *sync.WaitGroup, lbs chan LoadBalancer
 		},
 	}
 
-	client, err := kc.NewClient(config)
+	client, err := kubernetes.NewProviderClient(kc, provider, config)
 	if err != nil {
 		log.Println("error creating dynamic client for account", account)
 		return
@@ -447,7 +487,7 @@ func listLoadBalancers(c *gin.Context, wg *sync.WaitGroup, lbs chan LoadBalancer
 	// Label selector for all that we are listing in the cluster. We
 	// only want to list resources that have a label referencing the requested application.
 	lo := metav1.ListOptions{
-		LabelSelector:  kubernetes.LabelKubernetesName + "=" + application,
+		LabelSelector:  kubernetes.ManagedApplicationLabelSelector(application),
 		TimeoutSeconds: &listTimeout,
 	}
 
@@ -526,6 +566,10 @@ func ListClusters(c *gin.Context) {
 		}
 	}
 
+	for account := range response {
+		sort.Strings(response[account])
+	}
+
 	c.JSON(http.StatusOK, response)
 }
 
@@ -653,7 +697,7 @@ func ListServerGroups(c *gin.Context) {
 	}
 
 	sort.Slice(response, func(i, j int) bool {
-		return response[i].Name < response[j].Name
+		return response[i].listKey().less(response[j].listKey())
 	})
 
 	c.JSON(http.StatusOK, response)
@@ -699,14 +743,14 @@ func listServerGroups(c *gin.Context, wg *sync.WaitGroup, sgs chan ServerGroup,
 		},
 	}
 
-	client, err := kc.NewClient(config)
+	client, err := kubernetes.NewProviderClient(kc, provider, config)
 	if err != nil {
 		log.Println("error creating dynamic client for account", account)
 		return
 	}
 
 	lo := metav1.ListOptions{
-		LabelSelector:  kubernetes.LabelKubernetesName + "=" + application,
+		LabelSelector:  kubernetes.ApplicationLabelSelector(application),
 		TimeoutSeconds: &listTimeout,
 	}
 
@@ -716,6 +760,9 @@ func listServerGroups(c *gin.Context, wg *sync.WaitGroup, sgs chan ServerGroup,
 		return
 	}
 
+	// Pods are not always labeled as managed by Spinnaker, but the server groups are.
+	lo.LabelSelector = kubernetes.ManagedApplicationLabelSelector(application)
+
 	for _, resource := range resources {
 		results, err := client.ListResource(resource, lo)
 		if err != nil {
@@ -779,7 +826,8 @@ func newServerGroup(result unstructured.Unstructured,
 		Instances:     instances,
 		IsDisabled:    false,
 		DisplayName:   result.GetName(),
-		LoadBalancers: nil,
+		LoadBalancers: []interface{}{},
+		Manifest:      result.Object,
 		Moniker: ServerGroupMoniker{
 			App:      app,
 			Cluster:  cluster,
@@ -788,7 +836,7 @@ func newServerGroup(result unstructured.Unstructured,
 		Name:                fmt.Sprintf("%s %s", result.GetKind(), result.GetName()),
 		Namespace:           result.GetNamespace(),
 		Region:              result.GetNamespace(),
-		SecurityGroups:      nil,
+		SecurityGroups:      []interface{}{},
 		ServerGroupManagers: serverGroupManagers,
 		Type:                "kubernetes",
 		Labels:              result.GetLabels(),
@@ -920,12 +968,16 @@ func buildInstances(pods *unstructured.UnstructuredList,
 			}
 		}
 	}
+
+	sort.Slice(instances, func(i, j int) bool {
+		return instances[i].listKey().less(instances[j].listKey())
+	})
+
 	return instances
 }
 
 // /applications/:application/serverGroups/:account/:location/:name
 func GetServerGroup(c *gin.Context) {
-	sc := sql.Instance(c)
 	kc := kubernetes.ControllerInstance(c)
 	ac := arcade.Instance(c)
 	account := c.Param("account")
@@ -935,11 +987,7 @@ func GetServerGroup(c *gin.Context) {
 	kind := nameArray[0]
 	name := nameArray[1]
 
-	provider, err := sc.GetKubernetesProvider(account)
-	if err != nil {
-		clouddriver.WriteError(c, http.StatusInternalServerError, err)
-		return
-	}
+	provider := kubernetes.ProviderInstance(c)
 
 	cd, err := base64.StdEncoding.DecodeString(provider.CAData)
 	if err != nil {
@@ -961,14 +1009,19 @@ func GetServerGroup(c *gin.Context) {
 		},
 	}
 
-	client, err := kc.NewClient(config)
+	client, err := kubernetes.NewProviderClient(kc, provider, config)
 	if err != nil {
 		clouddriver.WriteError(c, http.StatusInternalServerError, err)
 		return
 	}
 
+	if liveManifestCalls(c) {
+		client.InvalidateDiscovery()
+	}
+
 	lo := metav1.ListOptions{
-		LabelSelector:  kubernetes.LabelKubernetesName + "=" + application,
+		LabelSelector:  kubernetes.ApplicationLabelSelector(application),
+		FieldSelector:  "metadata.namespace=" + location,
 		TimeoutSeconds: &listTimeout,
 	}
 
@@ -1055,6 +1108,10 @@ func GetServerGroup(c *gin.Context) {
 		}
 	}
 
+	sort.Slice(instances, func(i, j int) bool {
+		return instances[i].listKey().less(instances[j].listKey())
+	})
+
 	annotations := result.GetAnnotations()
 	cluster := annotations["moniker.spinnaker.io/cluster"]
 	app := annotations["moniker.spinnaker.io/application"]
@@ -1132,7 +1189,6 @@ type JobCompletionDetails struct {
 }
 
 func GetJob(c *gin.Context) {
-	sc := sql.Instance(c)
 	kc := kubernetes.ControllerInstance(c)
 	ac := arcade.Instance(c)
 	account := c.Param("account")
@@ -1142,11 +1198,7 @@ func GetJob(c *gin.Context) {
 	kind := nameArray[0]
 	name := nameArray[1]
 
-	provider, err := sc.GetKubernetesProvider(account)
-	if err != nil {
-		clouddriver.WriteError(c, http.StatusInternalServerError, err)
-		return
-	}
+	provider := kubernetes.ProviderInstance(c)
 
 	cd, err := base64.StdEncoding.DecodeString(provider.CAData)
 	if err != nil {
@@ -1168,7 +1220,7 @@ func GetJob(c *gin.Context) {
 		},
 	}
 
-	client, err := kc.NewClient(config)
+	client, err := kubernetes.NewProviderClient(kc, provider, config)
 	if err != nil {
 		clouddriver.WriteError(c, http.StatusInternalServerError, err)
 		return
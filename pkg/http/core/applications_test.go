@@ -8,6 +8,8 @@ import (
 	"log"
 	"net/http"
 
+	clouddriver "github.com/billiford/go-clouddriver/pkg"
+	"github.com/billiford/go-clouddriver/pkg/fiat"
 	"github.com/billiford/go-clouddriver/pkg/kubernetes"
 	. "github.com/onsi/ginkgo"
 	. "github.com/onsi/gomega"
@@ -83,6 +85,42 @@ var _ = Describe("Application", func() {
 			})
 		})
 
+		When("listing applications returns an error", func() {
+			BeforeEach(func() {
+				fakeSQLClient.ListApplicationsReturns(nil, errors.New("error listing applications"))
+			})
+
+			It("returns an error", func() {
+				Expect(res.StatusCode).To(Equal(http.StatusInternalServerError))
+				ce := getClouddriverError()
+				Expect(ce.Message).To(Equal("error listing applications"))
+			})
+		})
+
+		When("applications have been created", func() {
+			BeforeEach(func() {
+				fakeSQLClient.ListApplicationsReturns([]clouddriver.Application{
+					{
+						Name:        "test-spinnaker-app1",
+						Email:       "test@example.com",
+						Description: "test description",
+						Permissions: clouddriver.Permissions{
+							READ:  []string{"test-group1"},
+							WRITE: []string{"test-group2"},
+						},
+					},
+					{
+						Name: "test-spinnaker-app3",
+					},
+				}, nil)
+			})
+
+			It("lists them with their metadata", func() {
+				Expect(res.StatusCode).To(Equal(http.StatusOK))
+				validateResponse(payloadApplicationsWithMetadata)
+			})
+		})
+
 		When("it succeeds", func() {
 			It("succeeds", func() {
 				Expect(res.StatusCode).To(Equal(http.StatusOK))
@@ -712,6 +750,21 @@ var _ = Describe("Application", func() {
 			})
 		})
 
+		When("listing resources", func() {
+			It("only lists resources of the application deployed by spinnaker", func() {
+				Expect(res.StatusCode).To(Equal(http.StatusOK))
+				Expect(fakeKubeClient.ListResourceCallCount()).ToNot(BeZero())
+				for i := 0; i < fakeKubeClient.ListResourceCallCount(); i++ {
+					resource, lo := fakeKubeClient.ListResourceArgsForCall(i)
+					if resource == "pods" {
+						Expect(lo.LabelSelector).To(Equal("app.kubernetes.io/name=test-application"))
+					} else {
+						Expect(lo.LabelSelector).To(Equal("app.kubernetes.io/managed-by=spinnaker,app.kubernetes.io/name=test-application"))
+					}
+				}
+			})
+		})
+
 		When("it succeeds", func() {
 			It("succeeds", func() {
 				Expect(res.StatusCode).To(Equal(http.StatusOK))
@@ -832,6 +885,36 @@ var _ = Describe("Application", func() {
 			})
 		})
 
+		When("the user does not have read access to the account", func() {
+			BeforeEach(func() {
+				req.Header.Set("X-Spinnaker-User", "test-user")
+				fakeFiatClient.AuthorizeReturns(fiat.Response{
+					Accounts: []fiat.Account{
+						{
+							Name:           "test-account",
+							Authorizations: []string{},
+						},
+					},
+				}, nil)
+				fakeSQLClient.ListPermissionsByAccountNamesReturns(map[string]kubernetes.ProviderPermissions{
+					"test-account": {
+						Read:  []string{"test-read-group"},
+						Write: []string{"test-write-group"},
+					},
+				}, nil)
+			})
+
+			It("returns status forbidden with the required groups", func() {
+				Expect(res.StatusCode).To(Equal(http.StatusForbidden))
+				ce := getClouddriverError()
+				Expect(ce.Error).To(Equal("Forbidden"))
+				Expect(ce.Message).To(Equal("Access denied to account test-account - required authorization: READ"))
+				Expect(ce.RequiredAuthorization).To(Equal("READ"))
+				Expect(ce.RequiredGroups).To(Equal([]string{"test-read-group"}))
+				Expect(fakeSQLClient.GetKubernetesProviderCallCount()).To(BeZero())
+			})
+		})
+
 		When("decoding the ca data returns an error", func() {
 			BeforeEach(func() {
 				fakeSQLClient.GetKubernetesProviderReturns(kubernetes.Provider{
@@ -904,6 +987,28 @@ var _ = Describe("Application", func() {
 			})
 		})
 
+		When("liveManifestCalls is true", func() {
+			BeforeEach(func() {
+				uri = svr.URL + "/applications/test-application/serverGroups/test-account/test-namespace/replicaSet test-rs1?liveManifestCalls=true"
+				createRequest(http.MethodGet)
+			})
+
+			It("invalidates cached discovery", func() {
+				Expect(res.StatusCode).To(Equal(http.StatusOK))
+				Expect(fakeKubeClient.InvalidateDiscoveryCallCount()).To(Equal(1))
+			})
+		})
+
+		When("listing pods", func() {
+			It("only lists pods of the application in the server group's namespace", func() {
+				Expect(res.StatusCode).To(Equal(http.StatusOK))
+				Expect(fakeKubeClient.ListResourceCallCount()).To(Equal(1))
+				_, lo := fakeKubeClient.ListResourceArgsForCall(0)
+				Expect(lo.LabelSelector).To(Equal("app.kubernetes.io/name=test-application"))
+				Expect(lo.FieldSelector).To(Equal("metadata.namespace=test-namespace"))
+			})
+		})
+
 		When("it succeeds", func() {
 			It("succeeds", func() {
 				Expect(res.StatusCode).To(Equal(http.StatusOK))
@@ -1,12 +1,7 @@
 package core
 
 import (
-	"bytes"
-	"context"
-	"encoding/base64"
-	"encoding/json"
-	"fmt"
-	"io/ioutil"
+	"errors"
 	"net/http"
 	"sort"
 	"strings"
@@ -112,108 +107,28 @@ func GetArtifact(c *gin.Context) {
 		return
 	}
 
-	b := []byte{}
-
-	switch a.Type {
-	case artifact.TypeHTTPFile:
-		hc, err := cc.HTTPClientForAccountName(a.ArtifactAccount)
-		if err != nil {
-			clouddriver.WriteError(c, http.StatusBadRequest, err)
-			return
-		}
-
-		resp, err := hc.Get(a.Reference)
-		if err != nil {
-			clouddriver.WriteError(c, http.StatusInternalServerError, err)
-			return
-		}
-		defer resp.Body.Close()
-
-		b, err = ioutil.ReadAll(resp.Body)
-		if err != nil {
-			clouddriver.WriteError(c, http.StatusInternalServerError, err)
-			return
-		}
-
-	case artifact.TypeHelmChart:
-		hc, err := cc.HelmClientForAccountName(a.ArtifactAccount)
-		if err != nil {
-			clouddriver.WriteError(c, http.StatusBadRequest, err)
-			return
-		}
-
-		b, err = hc.GetChart(a.Name, a.Version)
-		if err != nil {
-			clouddriver.WriteError(c, http.StatusInternalServerError, err)
-			return
-		}
-
-	case artifact.TypeEmbeddedBase64:
-		// TODO when a base64 encoded helm templated manifest makes its way here, it sometimes starts
-		// with "WARNING":"This chart is deprecated" - we should either handle that here by removing
-		// the prefix, or handle it in the deploy manifest operation.
-		b, err = base64.StdEncoding.DecodeString(a.Reference)
-		if err != nil {
-			clouddriver.WriteError(c, http.StatusInternalServerError, err)
-			return
-		}
-
-	case artifact.TypeGithubFile:
-		gc, err := cc.GitClientForAccountName(a.ArtifactAccount)
-		if err != nil {
+	b, err := artifact.Fetch(cc, artifact.Artifact{
+		Type:            a.Type,
+		Name:            a.Name,
+		Version:         a.Version,
+		Reference:       a.Reference,
+		ArtifactAccount: a.ArtifactAccount,
+	})
+	if err != nil {
+		var (
+			iae artifact.InvalidArtifactError
+			ute artifact.UnsupportedTypeError
+		)
+
+		switch {
+		case errors.As(err, &ute):
+			clouddriver.WriteError(c, http.StatusNotImplemented, err)
+		case errors.As(err, &iae):
 			clouddriver.WriteError(c, http.StatusBadRequest, err)
-			return
-		}
-
-		if !strings.HasPrefix(a.Reference, gc.BaseURL.String()) {
-			clouddriver.WriteError(c, http.StatusBadRequest, fmt.Errorf("content URL %s should have base URL %s",
-				a.Reference, gc.BaseURL.String()))
-			return
-		}
-
-		urlStr := strings.TrimPrefix(a.Reference, gc.BaseURL.String())
-
-		req, err := gc.NewRequest(http.MethodGet, urlStr, nil)
-		if err != nil {
-			clouddriver.WriteError(c, http.StatusInternalServerError, err)
-			return
-		}
-
-		branch := "master"
-		if a.Version != "" {
-			branch = a.Version
-		}
-
-		q := req.URL.Query()
-		q.Set("ref", branch)
-		req.URL.RawQuery = q.Encode()
-
-		var buf bytes.Buffer
-
-		_, err = gc.Do(context.TODO(), req, &buf)
-		if err != nil {
+		default:
 			clouddriver.WriteError(c, http.StatusInternalServerError, err)
-			return
-		}
-
-		var response struct {
-			Content  string `json:"content"`
-			Encoding string `json:"encoding"`
-		}
-		json.Unmarshal(buf.Bytes(), &response)
-
-		if strings.EqualFold(response.Encoding, "base64") {
-			b, err = base64.StdEncoding.DecodeString(response.Content)
-			if err != nil {
-				clouddriver.WriteError(c, http.StatusInternalServerError, err)
-				return
-			}
-		} else {
-			b = []byte(response.Content)
 		}
 
-	default:
-		clouddriver.WriteError(c, http.StatusNotImplemented, fmt.Errorf("getting artifact of type %s not implemented", a.Type))
 		return
 	}
 
@@ -0,0 +1,165 @@
+package core
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"strings"
+	"time"
+
+	clouddriver "github.com/billiford/go-clouddriver/pkg"
+	"github.com/billiford/go-clouddriver/pkg/arcade"
+	"github.com/billiford/go-clouddriver/pkg/kubernetes"
+	"github.com/billiford/go-clouddriver/pkg/ondemand"
+	"github.com/billiford/go-clouddriver/pkg/routine"
+	"github.com/billiford/go-clouddriver/pkg/sql"
+	"github.com/gin-gonic/gin"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+)
+
+const (
+	cacheRefreshTimeout       = 10 * time.Second
+	cacheRefreshRetryInterval = 5 * time.Second
+	// Pending refreshes are given up on after this long, so Orca does not
+	// wait on them until its own timeout.
+	cacheRefreshMaxPending = 5 * time.Minute
+)
+
+// ManifestCacheRefreshRequest is the on-demand refresh of a manifest Orca
+// posts after an operation changes it. ManifestName is the kind and name,
+// such as "deployment my-app".
+type ManifestCacheRefreshRequest struct {
+	Account      string `json:"account"`
+	Location     string `json:"location"`
+	ManifestName string `json:"manifestName"`
+}
+
+// RefreshManifestCache refreshes what is cached of a manifest, the resource
+// types its cluster serves and, for namespaces, the namespaces of its
+// account, and reads it from the cluster. Manifests are otherwise always
+// read live, so a refresh is processed once the manifest is read, or is
+// found to be deleted. It responds with 200 OK if it was, or else with
+// 202 Accepted, retrying in the background until the refresh, listed by
+// ListPendingManifestCacheRefreshes, is processed.
+func RefreshManifestCache(c *gin.Context) {
+	od := ondemand.Instance(c)
+	req := ManifestCacheRefreshRequest{}
+
+	err := c.ShouldBindJSON(&req)
+	if err != nil {
+		clouddriver.WriteError(c, http.StatusBadRequest, err)
+		return
+	}
+
+	a := strings.Fields(req.ManifestName)
+	if req.Account == "" || len(a) != 2 {
+		clouddriver.WriteError(c, http.StatusBadRequest,
+			errors.New("account and manifestName, such as \"deployment my-app\", are required"))
+		return
+	}
+
+	r := od.Request(ondemand.Details{
+		Account:  req.Account,
+		Location: req.Location,
+		Name:     req.ManifestName,
+	}, time.Now())
+
+	mr := &manifestRefresher{
+		sc:   sql.Instance(c).WithContext(context.Background()),
+		kc:   kubernetes.ControllerInstance(c).WithContext(context.Background()),
+		ac:   arcade.Instance(c),
+		nc:   kubernetes.NamespaceCacheInstance(c),
+		req:  req,
+		kind: a[0],
+		name: a[1],
+	}
+
+	err = mr.refresh()
+	if err == nil {
+		od.Process(r, time.Now(), nil)
+		c.Status(http.StatusOK)
+
+		return
+	}
+
+	log.Println("[CACHE] refresh of", req.ManifestName, "in", req.Location, "of account", req.Account,
+		"is pending:", err.Error())
+
+	routine.Go(context.Background(), "cache-refresh", func(context.Context) {
+		od.Process(r, time.Now(), mr.retry())
+	})
+
+	c.Status(http.StatusAccepted)
+}
+
+// ListPendingManifestCacheRefreshes lists the on-demand refreshes of
+// manifests requested in the last ten minutes, as Orca polls the
+// pendingOnDemandRequests of a cache. A refresh is pending until its
+// processedCount is above zero.
+func ListPendingManifestCacheRefreshes(c *gin.Context) {
+	c.JSON(http.StatusOK, ondemand.Instance(c).List(time.Now()))
+}
+
+type manifestRefresher struct {
+	sc   sql.Client
+	kc   kubernetes.Controller
+	ac   arcade.Client
+	nc   kubernetes.NamespaceCache
+	req  ManifestCacheRefreshRequest
+	kind string
+	name string
+}
+
+// refresh refreshes the caches of a manifest and reads it from its cluster.
+// A manifest that is not found has been deleted, which it is refreshed to.
+func (mr *manifestRefresher) refresh() error {
+	provider, err := mr.sc.GetKubernetesProvider(mr.req.Account)
+	if err != nil {
+		return fmt.Errorf("error getting account %s: %w", mr.req.Account, err)
+	}
+
+	client, err := adminClient(provider, mr.ac, mr.kc, cacheRefreshTimeout)
+	if err != nil {
+		return err
+	}
+
+	client.InvalidateDiscovery()
+
+	if strings.EqualFold(mr.kind, "namespace") {
+		mr.nc.Delete(provider.Name)
+	}
+
+	_, err = client.Get(mr.kind, mr.name, mr.req.Location)
+	if err != nil && !k8serrors.IsNotFound(err) {
+		return err
+	}
+
+	return nil
+}
+
+// retry refreshes a manifest until it succeeds or has been pending too
+// long, returning the last error then.
+func (mr *manifestRefresher) retry() error {
+	timer := time.NewTimer(cacheRefreshMaxPending)
+	defer timer.Stop()
+
+	ticker := time.NewTicker(cacheRefreshRetryInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-timer.C:
+			err := fmt.Errorf("gave up refreshing %s after %s", mr.req.ManifestName, cacheRefreshMaxPending)
+			log.Println("[CACHE]", err.Error())
+
+			return err
+		case <-ticker.C:
+		}
+
+		if err := mr.refresh(); err == nil {
+			return nil
+		}
+	}
+}
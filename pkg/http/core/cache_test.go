@@ -0,0 +1,164 @@
+package core_test
+
+import (
+	"encoding/json"
+	"errors"
+	"io/ioutil"
+	"net/http"
+
+	"github.com/billiford/go-clouddriver/pkg/kubernetes"
+	"github.com/billiford/go-clouddriver/pkg/ondemand"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+var _ = Describe("Cache", func() {
+	Describe("#RefreshManifestCache", func() {
+		BeforeEach(func() {
+			setup()
+			uri = svr.URL + "/cache/kubernetes/manifest"
+			body.Write([]byte(`{"account":"test-account","location":"default","manifestName":"deployment test-deployment"}`))
+			createRequest(http.MethodPost)
+		})
+
+		AfterEach(func() {
+			teardown()
+		})
+
+		JustBeforeEach(func() {
+			doRequest()
+		})
+
+		When("the request is not JSON", func() {
+			BeforeEach(func() {
+				body.Reset()
+				body.Write([]byte(";[]---"))
+				createRequest(http.MethodPost)
+			})
+
+			It("returns status bad request", func() {
+				Expect(res.StatusCode).To(Equal(http.StatusBadRequest))
+			})
+		})
+
+		When("the manifest name has no kind", func() {
+			BeforeEach(func() {
+				body.Reset()
+				body.Write([]byte(`{"account":"test-account","manifestName":"test-deployment"}`))
+				createRequest(http.MethodPost)
+			})
+
+			It("returns status bad request", func() {
+				Expect(res.StatusCode).To(Equal(http.StatusBadRequest))
+				ce := getClouddriverError()
+				Expect(ce.Message).To(Equal("account and manifestName, such as \"deployment my-app\", are required"))
+			})
+		})
+
+		When("getting the account fails", func() {
+			BeforeEach(func() {
+				fakeSQLClient.GetKubernetesProviderReturns(kubernetes.Provider{}, errors.New("error getting provider"))
+			})
+
+			It("returns status accepted", func() {
+				Expect(res.StatusCode).To(Equal(http.StatusAccepted))
+				Expect(fakeKubeClient.GetCallCount()).To(BeZero())
+			})
+		})
+
+		When("the manifest is deleted", func() {
+			BeforeEach(func() {
+				fakeKubeClient.GetReturns(nil, k8serrors.NewNotFound(schema.GroupResource{Resource: "deployments"}, "test-deployment"))
+			})
+
+			It("returns status ok", func() {
+				Expect(res.StatusCode).To(Equal(http.StatusOK))
+			})
+		})
+
+		When("the manifest is a namespace", func() {
+			BeforeEach(func() {
+				body.Reset()
+				body.Write([]byte(`{"account":"test-account","manifestName":"namespace test-namespace"}`))
+				createRequest(http.MethodPost)
+			})
+
+			It("refreshes the namespaces of the account", func() {
+				Expect(res.StatusCode).To(Equal(http.StatusOK))
+				Expect(fakeKubeNamespaceCache.DeleteCallCount()).To(Equal(1))
+			})
+		})
+
+		When("it succeeds", func() {
+			It("refreshes and reads the manifest", func() {
+				Expect(res.StatusCode).To(Equal(http.StatusOK))
+				Expect(fakeKubeClient.InvalidateDiscoveryCallCount()).To(Equal(1))
+				Expect(fakeKubeClient.GetCallCount()).To(Equal(1))
+				kind, name, namespace := fakeKubeClient.GetArgsForCall(0)
+				Expect(kind).To(Equal("deployment"))
+				Expect(name).To(Equal("test-deployment"))
+				Expect(namespace).To(Equal("default"))
+				Expect(fakeKubeNamespaceCache.DeleteCallCount()).To(BeZero())
+			})
+		})
+	})
+
+	Describe("#ListPendingManifestCacheRefreshes", func() {
+		var refreshes []ondemand.Refresh
+
+		BeforeEach(func() {
+			setup()
+			uri = svr.URL + "/cache/kubernetes/manifest"
+			fakeSQLClient.GetKubernetesProviderReturns(kubernetes.Provider{}, errors.New("error getting provider"))
+		})
+
+		AfterEach(func() {
+			teardown()
+		})
+
+		JustBeforeEach(func() {
+			body.Write([]byte(`{"account":"test-account","location":"default","manifestName":"deployment test-deployment"}`))
+			createRequest(http.MethodPost)
+			doRequest()
+			res.Body.Close()
+
+			createRequest(http.MethodGet)
+			doRequest()
+
+			refreshes = nil
+			b, _ := ioutil.ReadAll(res.Body)
+			_ = json.Unmarshal(b, &refreshes)
+		})
+
+		When("a refresh is pending", func() {
+			It("lists it", func() {
+				Expect(res.StatusCode).To(Equal(http.StatusOK))
+				Expect(refreshes).To(HaveLen(1))
+				Expect(refreshes[0].ID).To(Equal("test-account:default:deployment test-deployment"))
+				Expect(refreshes[0].Details).To(Equal(ondemand.Details{
+					Account:  "test-account",
+					Location: "default",
+					Name:     "deployment test-deployment",
+				}))
+				Expect(refreshes[0].CacheTime).ToNot(BeZero())
+				Expect(refreshes[0].Pending()).To(BeTrue())
+			})
+		})
+
+		When("a refresh is processed", func() {
+			BeforeEach(func() {
+				fakeSQLClient.GetKubernetesProviderReturns(kubernetes.Provider{Name: "test-account"}, nil)
+			})
+
+			It("lists it processed", func() {
+				Expect(res.StatusCode).To(Equal(http.StatusOK))
+				Expect(refreshes).To(HaveLen(1))
+				Expect(refreshes[0].Pending()).To(BeFalse())
+				Expect(refreshes[0].ProcessedCount).To(Equal(1))
+				Expect(refreshes[0].ProcessedTime).ToNot(BeZero())
+			})
+		})
+	})
+})
@@ -0,0 +1,46 @@
+package core
+
+import (
+	"net/http"
+
+	"github.com/billiford/go-clouddriver/pkg/artifact"
+	"github.com/billiford/go-clouddriver/pkg/http/core/kubernetes"
+	"github.com/gin-gonic/gin"
+)
+
+// Features of accounts by provider. Add to these as features are added
+// instead of bumping a version, so callers can check for what they need.
+var accountFeatures = map[string][]string{
+	"kubernetes": {
+		// Accounts are created and deleted through /v1/kubernetes/providers.
+		"dynamicAccounts",
+		"namespaces",
+		"readPermissions",
+		"writePermissions",
+		"spinnakerKindMap",
+		// Caches are invalidated by audit webhooks and pub/sub events.
+		"cacheInvalidation",
+	},
+	"dockerRegistry": {
+		"repositories",
+		"tags",
+		"webhooks",
+	},
+}
+
+type Capabilities struct {
+	Operations      []string            `json:"operations"`
+	ArtifactTypes   []artifact.Type     `json:"artifactTypes"`
+	AccountFeatures map[string][]string `json:"accountFeatures"`
+}
+
+// GetCapabilities lists the operations, artifact types and account
+// features of this build, so that callers can check for support
+// of a feature instead of failing at runtime.
+func GetCapabilities(c *gin.Context) {
+	c.JSON(http.StatusOK, Capabilities{
+		Operations:      kubernetes.SupportedOperations,
+		ArtifactTypes:   artifact.FetchableTypes,
+		AccountFeatures: accountFeatures,
+	})
+}
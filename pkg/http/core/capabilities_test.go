@@ -0,0 +1,31 @@
+package core_test
+
+import (
+	"net/http"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Capabilities", func() {
+	Describe("#GetCapabilities", func() {
+		BeforeEach(func() {
+			setup()
+			uri = svr.URL + "/capabilities"
+			createRequest(http.MethodGet)
+		})
+
+		AfterEach(func() {
+			teardown()
+		})
+
+		JustBeforeEach(func() {
+			doRequest()
+		})
+
+		It("lists the capabilities of the build", func() {
+			Expect(res.StatusCode).To(Equal(http.StatusOK))
+			validateResponse(payloadCapabilities)
+		})
+	})
+})
@@ -0,0 +1,280 @@
+package core_test
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"log"
+	"net/http"
+	"path/filepath"
+
+	"github.com/billiford/go-clouddriver/pkg/kubernetes"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+// The contract tests compare the shape of our responses to responses
+// recorded from OSS clouddriver in testdata/contract. Fixtures are trimmed
+// to the fields Deck and Orca read, values are ignored.
+//
+// In a fixture, null matches any value, an empty object or array matches
+// any object or array and an object with the single key "*" matches a map
+// whose values all match the value of "*".
+var _ = Describe("Contract", func() {
+	BeforeEach(func() {
+		setup()
+		log.SetOutput(ioutil.Discard)
+	})
+
+	AfterEach(func() {
+		teardown()
+	})
+
+	JustBeforeEach(func() {
+		createRequest(http.MethodGet)
+		doRequest()
+		Expect(err).To(BeNil())
+		Expect(res.StatusCode).To(Equal(http.StatusOK))
+	})
+
+	Describe("GET /credentials", func() {
+		BeforeEach(func() {
+			uri = svr.URL + "/credentials"
+			fakeSQLClient.ListKubernetesProvidersReturns([]kubernetes.Provider{
+				{
+					Name: "spin-cluster-1",
+				},
+			}, nil)
+			fakeSQLClient.ListPermissionsByAccountNamesReturns(map[string]kubernetes.ProviderPermissions{
+				"spin-cluster-1": {
+					Read:  []string{"spinnaker-readers"},
+					Write: []string{"spinnaker-writers"},
+				},
+			}, nil)
+		})
+
+		It("matches the OSS contract", func() {
+			expectContract("credentials.json")
+		})
+	})
+
+	Describe("GET /manifests/:account/:location/:kind", func() {
+		BeforeEach(func() {
+			uri = svr.URL + "/manifests/spin-cluster-1/default/deployment nginx"
+			fakeKubeClient.GetReturns(&unstructured.Unstructured{
+				Object: map[string]interface{}{
+					"apiVersion": "apps/v1",
+					"kind":       "Deployment",
+					"metadata": map[string]interface{}{
+						"name":      "nginx",
+						"namespace": "default",
+						"labels": map[string]interface{}{
+							kubernetes.LabelKubernetesName: "nginx",
+						},
+					},
+				},
+			}, nil)
+		})
+
+		It("matches the OSS contract", func() {
+			expectContract("manifest.json")
+		})
+	})
+
+	Describe("GET /applications", func() {
+		BeforeEach(func() {
+			uri = svr.URL + "/applications"
+			fakeSQLClient.ListKubernetesResourcesByFieldsReturns([]kubernetes.Resource{
+				{
+					AccountName:  "spin-cluster-1",
+					Kind:         "deployment",
+					Name:         "nginx",
+					SpinnakerApp: "nginx",
+				},
+			}, nil)
+		})
+
+		It("matches the OSS contract", func() {
+			expectContract("applications.json")
+		})
+	})
+
+	Describe("GET /applications/:application/serverGroups", func() {
+		BeforeEach(func() {
+			uri = svr.URL + "/applications/nginx/serverGroups"
+			fakeSQLClient.ListKubernetesAccountsBySpinnakerAppReturns([]string{
+				"spin-cluster-1",
+			}, nil)
+			fakeKubeClient.ListResourceReturnsOnCall(0, &unstructured.UnstructuredList{
+				Items: []unstructured.Unstructured{
+					{
+						Object: map[string]interface{}{
+							"kind":       "Pod",
+							"apiVersion": "v1",
+							"metadata": map[string]interface{}{
+								"name":              "nginx-6d4cf56db6-8kqzt",
+								"namespace":         "default",
+								"creationTimestamp": "2020-10-13T10:06:17Z",
+								"ownerReferences": []interface{}{
+									map[string]interface{}{
+										"name": "nginx-6d4cf56db6",
+									},
+								},
+								"uid": "0f1d9f2e-3c2b-4f0a-9a57-5f6f8c2d1b3e",
+							},
+							"status": map[string]interface{}{
+								"phase": "Running",
+							},
+						},
+					},
+				},
+			}, nil)
+			fakeKubeClient.ListResourceReturnsOnCall(1, &unstructured.UnstructuredList{
+				Items: []unstructured.Unstructured{
+					{
+						Object: map[string]interface{}{
+							"kind":       "ReplicaSet",
+							"apiVersion": "apps/v1",
+							"metadata": map[string]interface{}{
+								"name":              "nginx-6d4cf56db6",
+								"namespace":         "default",
+								"creationTimestamp": "2020-10-13T10:06:17Z",
+								"labels": map[string]interface{}{
+									kubernetes.LabelKubernetesName: "nginx",
+								},
+								"annotations": map[string]interface{}{
+									"artifact.spinnaker.io/name":        "nginx",
+									"artifact.spinnaker.io/type":        "kubernetes/deployment",
+									"artifact.spinnaker.io/location":    "default",
+									"moniker.spinnaker.io/application":  "nginx",
+									"moniker.spinnaker.io/cluster":      "deployment nginx",
+									"deployment.kubernetes.io/revision": "1",
+								},
+							},
+							"spec": map[string]interface{}{
+								"replicas": int64(1),
+								"template": map[string]interface{}{
+									"spec": map[string]interface{}{
+										"containers": []interface{}{
+											map[string]interface{}{
+												"image": "nginx:1.19",
+											},
+										},
+									},
+								},
+							},
+							"status": map[string]interface{}{
+								"replicas":      int64(1),
+								"readyReplicas": int64(1),
+							},
+						},
+					},
+				},
+			}, nil)
+			fakeKubeClient.ListResourceReturnsOnCall(2, &unstructured.UnstructuredList{}, nil)
+			fakeKubeClient.ListResourceReturnsOnCall(3, &unstructured.UnstructuredList{}, nil)
+		})
+
+		It("matches the OSS contract", func() {
+			expectContract("server_groups.json")
+		})
+	})
+})
+
+// expectContract fails if the response body drifts from the fixture.
+func expectContract(fixture string) {
+	b, err := ioutil.ReadFile(filepath.Join("testdata", "contract", fixture))
+	Expect(err).To(BeNil())
+
+	var expected, actual interface{}
+
+	err = json.Unmarshal(b, &expected)
+	Expect(err).To(BeNil())
+
+	b, err = ioutil.ReadAll(res.Body)
+	Expect(err).To(BeNil())
+
+	err = json.Unmarshal(b, &actual)
+	Expect(err).To(BeNil())
+
+	Expect(contractDrift("$", expected, actual)).To(BeEmpty(), "response drifted from "+fixture)
+}
+
+// contractDrift returns a description of each place actual differs
+// in shape from expected.
+func contractDrift(path string, expected, actual interface{}) []string {
+	if expected == nil {
+		return nil
+	}
+
+	switch e := expected.(type) {
+	case map[string]interface{}:
+		a, ok := actual.(map[string]interface{})
+		if !ok {
+			return []string{fmt.Sprintf("%s: expected an object, got %s", path, jsonType(actual))}
+		}
+
+		drift := []string{}
+
+		if v, ok := e["*"]; ok && len(e) == 1 {
+			for key, value := range a {
+				drift = append(drift, contractDrift(path+"."+key, v, value)...)
+			}
+
+			return drift
+		}
+
+		for key, value := range e {
+			if _, ok := a[key]; !ok {
+				drift = append(drift, fmt.Sprintf("%s.%s: missing", path, key))
+				continue
+			}
+
+			drift = append(drift, contractDrift(path+"."+key, value, a[key])...)
+		}
+
+		return drift
+	case []interface{}:
+		a, ok := actual.([]interface{})
+		if !ok {
+			return []string{fmt.Sprintf("%s: expected an array, got %s", path, jsonType(actual))}
+		}
+
+		drift := []string{}
+
+		if len(e) > 0 {
+			for i, value := range a {
+				drift = append(drift, contractDrift(fmt.Sprintf("%s[%d]", path, i), e[0], value)...)
+			}
+		}
+
+		return drift
+	default:
+		if jsonType(expected) != jsonType(actual) {
+			return []string{fmt.Sprintf("%s: expected %s, got %s", path, jsonType(expected), jsonType(actual))}
+		}
+
+		return nil
+	}
+}
+
+func jsonType(v interface{}) string {
+	switch v.(type) {
+	case nil:
+		return "null"
+	case bool:
+		return "boolean"
+	case float64:
+		return "number"
+	case string:
+		return "string"
+	case []interface{}:
+		return "array"
+	case map[string]interface{}:
+		return "object"
+	default:
+		return fmt.Sprintf("%T", v)
+	}
+}
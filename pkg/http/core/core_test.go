@@ -12,15 +12,25 @@ import (
 	"github.com/billiford/go-clouddriver/pkg/arcade/arcadefakes"
 	"github.com/billiford/go-clouddriver/pkg/artifact"
 	"github.com/billiford/go-clouddriver/pkg/artifact/artifactfakes"
+	"github.com/billiford/go-clouddriver/pkg/docker/dockerfakes"
 	"github.com/billiford/go-clouddriver/pkg/fiat/fiatfakes"
+	"github.com/billiford/go-clouddriver/pkg/freeze/freezefakes"
 	"github.com/billiford/go-clouddriver/pkg/helm/helmfakes"
+	"github.com/billiford/go-clouddriver/pkg/hook/hookfakes"
 	kubefakes "github.com/billiford/go-clouddriver/pkg/http/core/kubernetes/kubernetesfakes"
 	"github.com/billiford/go-clouddriver/pkg/kubernetes"
 	"github.com/billiford/go-clouddriver/pkg/kubernetes/kubernetesfakes"
+	"github.com/billiford/go-clouddriver/pkg/lock/lockfakes"
+	"github.com/billiford/go-clouddriver/pkg/notify/notifyfakes"
+	"github.com/billiford/go-clouddriver/pkg/project/projectfakes"
+	"github.com/billiford/go-clouddriver/pkg/queue/queuefakes"
+	"github.com/billiford/go-clouddriver/pkg/recorder/recorderfakes"
 	"github.com/billiford/go-clouddriver/pkg/server"
 	"github.com/billiford/go-clouddriver/pkg/sql/sqlfakes"
+	"github.com/billiford/go-clouddriver/pkg/webhook"
 	"github.com/gin-gonic/gin"
 	"github.com/google/go-github/v32/github"
+	"github.com/jinzhu/gorm"
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 
 	// . "github.com/onsi/ginkgo"
@@ -37,14 +47,26 @@ var (
 	res                               *http.Response
 	fakeArcadeClient                  *arcadefakes.FakeClient
 	fakeArtifactCredentialsController *artifactfakes.FakeCredentialsController
+	fakeDockerClient                  *dockerfakes.FakeClient
+	fakeDockerCredentialsController   *dockerfakes.FakeCredentialsController
 	fakeFiatClient                    *fiatfakes.FakeClient
+	fakeFreezeController              *freezefakes.FakeController
 	fakeGithubClient                  *github.Client
 	fakeHelmClient                    *helmfakes.FakeClient
 	fakeSQLClient                     *sqlfakes.FakeClient
 	fakeKubeClient                    *kubernetesfakes.FakeClient
 	fakeKubeController                *kubernetesfakes.FakeController
 	fakeKubeActionHandler             *kubefakes.FakeActionHandler
+	fakeKubeNamespaceCache            *kubernetesfakes.FakeNamespaceCache
+	fakeKubePermissionsCache          *kubernetesfakes.FakePermissionsCache
+	fakeProjectController             *projectfakes.FakeController
 	fakeAction                        *kubefakes.FakeAction
+	fakeRecorder                      *recorderfakes.FakeRecorder
+	fakeQueue                         *queuefakes.FakeQueue
+	fakeLocker                        *lockfakes.FakeLocker
+	fakeNotifier                      *notifyfakes.FakeNotifier
+	fakeHookRunner                    *hookfakes.FakeRunner
+	fakeStabilityChecker              *hookfakes.FakeStabilityChecker
 	fakeGithubServer                  *ghttp.Server
 	fakeFileServer                    *ghttp.Server
 )
@@ -52,6 +74,7 @@ var (
 func setup() {
 	// Setup fakes.
 	fakeSQLClient = &sqlfakes.FakeClient{}
+	fakeSQLClient.WithContextReturns(fakeSQLClient)
 	fakeSQLClient.GetKubernetesProviderReturns(kubernetes.Provider{
 		Name:   "test-account",
 		Host:   "http://localhost",
@@ -62,6 +85,7 @@ func setup() {
 			AccountName: "test-account-name",
 		},
 	}, nil)
+	fakeSQLClient.GetMigrationReportReturns(clouddriver.MigrationReport{}, gorm.ErrRecordNotFound)
 
 	fakeKubeClient = &kubernetesfakes.FakeClient{}
 	fakeKubeClient.GetReturns(&unstructured.Unstructured{Object: map[string]interface{}{}}, nil)
@@ -112,12 +136,16 @@ func setup() {
 
 	fakeKubeController = &kubernetesfakes.FakeController{}
 	fakeKubeController.NewClientReturns(fakeKubeClient, nil)
+	fakeKubeController.WithContextReturns(fakeKubeController)
 
 	fakeAction = &kubefakes.FakeAction{}
 
 	fakeKubeActionHandler = &kubefakes.FakeActionHandler{}
 	fakeKubeActionHandler.NewCleanupArtifactsActionReturns(fakeAction)
+	fakeKubeActionHandler.NewCreateApplicationActionReturns(fakeAction)
+	fakeKubeActionHandler.NewDeleteApplicationActionReturns(fakeAction)
 	fakeKubeActionHandler.NewDeleteManifestActionReturns(fakeAction)
+	fakeKubeActionHandler.NewMigrateApplicationActionReturns(fakeAction)
 	fakeKubeActionHandler.NewDeployManifestActionReturns(fakeAction)
 	fakeKubeActionHandler.NewPatchManifestActionReturns(fakeAction)
 	fakeKubeActionHandler.NewScaleManifestActionReturns(fakeAction)
@@ -125,6 +153,26 @@ func setup() {
 	fakeKubeActionHandler.NewRollbackActionReturns(fakeAction)
 	fakeKubeActionHandler.NewRunJobActionReturns(fakeAction)
 
+	fakeKubeNamespaceCache = &kubernetesfakes.FakeNamespaceCache{}
+	fakeKubePermissionsCache = &kubernetesfakes.FakePermissionsCache{}
+
+	fakeProjectController = &projectfakes.FakeController{}
+
+	fakeFreezeController = &freezefakes.FakeController{}
+
+	fakeRecorder = &recorderfakes.FakeRecorder{}
+
+	fakeQueue = &queuefakes.FakeQueue{}
+
+	fakeLocker = &lockfakes.FakeLocker{}
+	fakeLocker.LockReturns(func() {}, nil)
+
+	fakeNotifier = &notifyfakes.FakeNotifier{}
+
+	fakeHookRunner = &hookfakes.FakeRunner{}
+
+	fakeStabilityChecker = &hookfakes.FakeStabilityChecker{}
+
 	fakeArcadeClient = &arcadefakes.FakeClient{}
 
 	fakeFiatClient = &fiatfakes.FakeClient{}
@@ -156,6 +204,13 @@ func setup() {
 	fakeArtifactCredentialsController.GitClientForAccountNameReturns(fakeGithubClient, nil)
 	fakeArtifactCredentialsController.HTTPClientForAccountNameReturns(http.DefaultClient, nil)
 
+	fakeDockerClient = &dockerfakes.FakeClient{}
+	fakeDockerClient.CatalogReturns([]string{"library/nginx", "library/redis"}, nil)
+	fakeDockerClient.TagsReturns([]string{"1.18", "1.19", "latest"}, nil)
+
+	fakeDockerCredentialsController = &dockerfakes.FakeCredentialsController{}
+	fakeDockerCredentialsController.ClientForAccountNameReturns(fakeDockerClient, nil)
+
 	// Disable debug logging.
 	gin.SetMode(gin.ReleaseMode)
 
@@ -167,10 +222,25 @@ func setup() {
 	c := &server.Config{
 		ArcadeClient:                  fakeArcadeClient,
 		ArtifactCredentialsController: fakeArtifactCredentialsController,
+		DockerCredentialsController:   fakeDockerCredentialsController,
 		FiatClient:                    fakeFiatClient,
+		FreezeController:              fakeFreezeController,
 		SQLClient:                     fakeSQLClient,
 		KubeController:                fakeKubeController,
 		KubeActionHandler:             fakeKubeActionHandler,
+		KubeNamespaceCache:            fakeKubeNamespaceCache,
+		KubePermissionsCache:          fakeKubePermissionsCache,
+		ProjectController:             fakeProjectController,
+		Recorder:                      fakeRecorder,
+		Notifier:                      fakeNotifier,
+		HookRunner:                    fakeHookRunner,
+		StabilityChecker:              fakeStabilityChecker,
+		Queue:                         fakeQueue,
+		Locker:                        fakeLocker,
+		WebhookSecrets: webhook.Secrets{
+			"docker-registry": "test-webhook-secret",
+			"test-account":    "test-webhook-secret",
+		},
 	}
 
 	// Create server.
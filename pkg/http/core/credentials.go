@@ -2,27 +2,42 @@ package core
 
 import (
 	"encoding/base64"
+	"errors"
+	"fmt"
 	"log"
 	"net/http"
+	"sort"
+	"strconv"
 	"strings"
 	"sync"
 
 	clouddriver "github.com/billiford/go-clouddriver/pkg"
 	"github.com/billiford/go-clouddriver/pkg/arcade"
+	"github.com/billiford/go-clouddriver/pkg/docker"
 	"github.com/billiford/go-clouddriver/pkg/kubernetes"
 	"github.com/billiford/go-clouddriver/pkg/sql"
 	"github.com/gin-gonic/gin"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime/schema"
 	"k8s.io/client-go/rest"
 )
 
-var listNamespacesTimeout = int64(5)
+// HeaderAccountVersion is the provider version /credentials was listed at,
+// for pollers to pass as ?since= next time.
+const HeaderAccountVersion = `X-Clouddriver-Account-Version`
+
+var (
+	listNamespacesTimeout = int64(5)
+	// Clusters can have thousands of namespaces, so list them a page at a time.
+	listNamespacesPageSize = int64(500)
+)
 
 // I'm not sure why spinnaker needs this, but without it several necessary Spinnaker manifest stages are missing
 // (I suppose this is *why* Spinnaker needs it!).
 //
-// Also, All accounts with this have the same kind map, so we're hardcoding it for now.
+// This is the default kind map of every account. Accounts can map other kinds, such as
+// the rollout of Argo Rollouts, through the kindMap of the providers API.
 var spinnakerKindMap = map[string]string{
 	"apiService":                     "unclassified",
 	"clusterRole":                    "unclassified",
@@ -59,38 +74,107 @@ var spinnakerKindMap = map[string]string{
 }
 
 // List credentials for providers.
+//
+// With ?since= set to the provider version of an earlier response, only the
+// accounts changed after it are listed, and accounts deleted after it are
+// listed as deleted.
 func ListCredentials(c *gin.Context) {
 	expand := c.Query("expand")
-	sc := sql.Instance(c)
+	sc := sql.ReadOnlyInstance(c)
 	ac := arcade.Instance(c)
 	kc := kubernetes.ControllerInstance(c)
+	dc := docker.CredentialsControllerInstance(c)
+	pc := kubernetes.PermissionsCacheInstance(c)
+	nc := kubernetes.NamespaceCacheInstance(c)
 	credentials := []clouddriver.Credential{}
+	delta := c.Query("since") != ""
+
+	var since int64
+
+	if delta {
+		var err error
+
+		since, err = strconv.ParseInt(c.Query("since"), 10, 64)
+		if err != nil || since < 0 {
+			clouddriver.WriteError(c, http.StatusBadRequest,
+				fmt.Errorf("invalid since %q, must be an account version", c.Query("since")))
+			return
+		}
+	}
+
+	// Get the version before listing providers, so a change made while they
+	// are listed is listed again next time instead of missed.
+	version, err := sc.GetKubernetesProviderVersion()
+	if err != nil {
+		clouddriver.WriteError(c, http.StatusInternalServerError, err)
+		return
+	}
+
+	c.Header(HeaderAccountVersion, strconv.FormatInt(version, 10))
+
+	var providers []kubernetes.Provider
+	if delta {
+		providers, err = sc.ListKubernetesProvidersChangedSince(since)
+	} else {
+		providers, err = sc.ListKubernetesProviders()
+	}
 
-	providers, err := sc.ListKubernetesProvidersAndPermissions()
 	if err != nil {
 		clouddriver.WriteError(c, http.StatusInternalServerError, err)
 		return
 	}
 
+	// Sort ascending by name.
+	sort.Slice(providers, func(i, j int) bool {
+		return providers[i].Name < providers[j].Name
+	})
+
+	accounts := []string{}
+	for _, provider := range providers {
+		accounts = append(accounts, provider.Name)
+	}
+
+	permissions, err := cachedPermissions(accounts, sc, pc)
+	if err != nil {
+		clouddriver.WriteError(c, http.StatusInternalServerError, err)
+		return
+	}
+
+	var kindMaps map[string]map[string]string
+
+	if expand == "true" {
+		kindMaps, err = sc.ListKindMappingsByAccountNames(accounts...)
+		if err != nil {
+			clouddriver.WriteError(c, http.StatusInternalServerError, err)
+			return
+		}
+	}
+
 	for _, provider := range providers {
 		sca := clouddriver.Credential{
-			AccountType:   provider.Name,
-			CloudProvider: "kubernetes",
-			Environment:   provider.Name,
-			Name:          provider.Name,
+			AccountType:                 provider.AccountTypeName(),
+			CacheThreads:                provider.CacheThreadCount(),
+			ChallengeDestructiveActions: provider.ChallengeDestructiveActions,
+			CloudProvider:               "kubernetes",
+			Environment:                 provider.EnvironmentName(),
+			Maintenance:                 provider.Maintenance,
+			MaintenanceMessage:          provider.MaintenanceMessage,
+			Name:                        provider.Name,
 			Permissions: clouddriver.Permissions{
-				READ:  provider.Permissions.Read,
-				WRITE: provider.Permissions.Write,
+				READ:    permissions[provider.Name].Read,
+				WRITE:   permissions[provider.Name].Write,
+				EXECUTE: permissions[provider.Name].Execute,
 			},
-			PrimaryAccount:          false,
+			PrimaryAccount:          provider.PrimaryAccount,
 			ProviderVersion:         "v2",
+			ReadOnly:                provider.ReadOnly,
 			RequiredGroupMembership: []interface{}{},
 			Skin:                    "v2",
 			Type:                    "kubernetes",
 		}
 
 		if expand == "true" {
-			sca.SpinnakerKindMap = spinnakerKindMap
+			sca.SpinnakerKindMap = kindMapFor(kindMaps[provider.Name])
 		}
 		credentials = append(credentials, sca)
 	}
@@ -99,7 +183,9 @@ func ListCredentials(c *gin.Context) {
 	//
 	// Gate is polling the endpoint `/credentials?expand=true` once every
 	// thirty seconds. Each gate instance is doing this, making the requests to get
-	// all provider's namespaces a multiple of how many gate instances there are.
+	// all provider's namespaces a multiple of how many gate instances there are,
+	// so namespaces are served from the namespace cache, which is refreshed in
+	// the background, and only listed from clusters on a miss.
 	if expand == "true" {
 		wg := &sync.WaitGroup{}
 		accountNamespacesCh := make(chan AccountNamespaces, len(providers))
@@ -107,7 +193,7 @@ func ListCredentials(c *gin.Context) {
 
 		// Get all namespaces of allowed accounts asynchronously.
 		for _, provider := range providers {
-			go listNamespaces(provider, wg, accountNamespacesCh, ac, kc)
+			go listNamespaces(provider, wg, accountNamespacesCh, ac, kc, nc)
 		}
 
 		wg.Wait()
@@ -124,112 +210,299 @@ func ListCredentials(c *gin.Context) {
 		}
 	}
 
-	c.JSON(http.StatusOK, credentials)
-}
+	if delta {
+		deleted, err := sc.ListKubernetesProvidersDeletedSince(since)
+		if err != nil {
+			clouddriver.WriteError(c, http.StatusInternalServerError, err)
+			return
+		}
+
+		sort.Strings(deleted)
+
+		for _, name := range deleted {
+			credentials = append(credentials, clouddriver.Credential{
+				AccountType:             name,
+				CloudProvider:           "kubernetes",
+				Deleted:                 true,
+				Environment:             name,
+				Name:                    name,
+				ProviderVersion:         "v2",
+				RequiredGroupMembership: []interface{}{},
+				Skin:                    "v2",
+				Type:                    "kubernetes",
+			})
+		}
+
+		// Docker registry accounts are configured when clouddriver starts
+		// and have no version, so are only listed in full.
+		c.JSON(http.StatusOK, credentials)
 
-type AccountNamespaces struct {
-	Name       string
-	Namespaces []string
+		return
+	}
+
+	// Docker registry accounts are needed by Deck's image pickers and docker triggers.
+	registries := dc.ListCredentials()
+	sort.Slice(registries, func(i, j int) bool {
+		return registries[i].Name < registries[j].Name
+	})
+
+	for _, dockerCredentials := range registries {
+		sca := clouddriver.Credential{
+			AccountType:             dockerCredentials.Name,
+			CloudProvider:           "dockerRegistry",
+			Environment:             dockerCredentials.Name,
+			Name:                    dockerCredentials.Name,
+			ProviderVersion:         "v1",
+			Registry:                dockerCredentials.Address,
+			RequiredGroupMembership: []interface{}{},
+			Skin:                    "v1",
+			Type:                    "dockerRegistry",
+		}
+		credentials = append(credentials, sca)
+	}
+
+	c.JSON(http.StatusOK, credentials)
 }
 
 func listNamespaces(provider kubernetes.Provider,
 	wg *sync.WaitGroup,
 	accountNamespacesCh chan AccountNamespaces,
 	ac arcade.Client,
-	kc kubernetes.Controller) {
+	kc kubernetes.Controller,
+	nc kubernetes.NamespaceCache) {
 	defer wg.Done()
 
-	cd, err := base64.StdEncoding.DecodeString(provider.CAData)
-	if err != nil {
-		log.Println("/credentials error decoding provider ca data:", err.Error())
+	if namespaces, ok := nc.Get(provider.Name); ok {
+		accountNamespacesCh <- AccountNamespaces{
+			Name:       provider.Name,
+			Namespaces: namespaces,
+		}
+
 		return
 	}
 
-	token, err := ac.Token()
+	namespaces, err := namespacesForProvider(provider, ac, kc, func(namespaces []string) {
+		nc.SetForProvider(provider, namespaces)
+	})
 	if err != nil {
-		log.Println("error getting arcade token:", err.Error())
+		log.Println("/credentials", err.Error())
 		return
 	}
 
-	config := &rest.Config{
-		Host:        provider.Host,
-		BearerToken: token,
-		TLSClientConfig: rest.TLSClientConfig{
-			CAData: cd,
-		},
+	an := AccountNamespaces{
+		Name:       provider.Name,
+		Namespaces: namespaces,
 	}
 
-	client, err := kc.NewClient(config)
-	if err != nil {
-		log.Println("/credentials error creating dynamic account:", err.Error())
-		return
+	accountNamespacesCh <- an
+}
+
+// cachedPermissions returns the permissions of the given accounts. Permissions
+// of accounts that are not cached are listed in one batch and then cached.
+func cachedPermissions(accounts []string, sc sql.Client,
+	pc kubernetes.PermissionsCache) (map[string]kubernetes.ProviderPermissions, error) {
+	permissions := map[string]kubernetes.ProviderPermissions{}
+	uncached := []string{}
+
+	for _, account := range accounts {
+		if p, ok := pc.Get(account); ok {
+			permissions[account] = p
+		} else {
+			uncached = append(uncached, account)
+		}
 	}
 
-	gvr := schema.GroupVersionResource{
-		Group:    "",
-		Version:  "v1",
-		Resource: "namespaces",
+	if len(uncached) == 0 {
+		return permissions, nil
 	}
-	// timeout listing namespaces to 5 seconds
-	result, err := client.ListByGVR(gvr, metav1.ListOptions{
-		TimeoutSeconds: &listNamespacesTimeout,
-	})
+
+	listed, err := sc.ListPermissionsByAccountNames(uncached...)
 	if err != nil {
-		log.Println("/credentials error listing using kubernetes account:", err.Error())
-		return
+		return nil, err
 	}
 
-	namespaces := []string{}
-	for _, ns := range result.Items {
-		namespaces = append(namespaces, ns.GetName())
+	for _, account := range uncached {
+		p := listed[account]
+		pc.Set(account, p)
+		permissions[account] = p
 	}
-	an := AccountNamespaces{
-		Name:       provider.Name,
-		Namespaces: namespaces,
+
+	return permissions, nil
+}
+
+// kindMapFor returns the default kind map with the kinds an account maps itself.
+func kindMapFor(custom map[string]string) map[string]string {
+	if len(custom) == 0 {
+		return spinnakerKindMap
 	}
 
-	accountNamespacesCh <- an
+	kindMap := make(map[string]string, len(spinnakerKindMap)+len(custom))
+
+	for kind, category := range spinnakerKindMap {
+		kindMap[kind] = category
+	}
+
+	for kind, category := range custom {
+		kindMap[kind] = category
+	}
+
+	return kindMap
 }
 
 func GetAccountCredentials(c *gin.Context) {
-	sc := sql.Instance(c)
-	account := c.Param("account")
+	sc := sql.ReadOnlyInstance(c)
+	pc := kubernetes.PermissionsCacheInstance(c)
 
-	provider, err := sc.GetKubernetesProvider(account)
-	if err != nil {
-		clouddriver.WriteError(c, http.StatusInternalServerError, err)
-		return
-	}
+	provider := kubernetes.ProviderInstance(c)
 
-	readGroups, err := sc.ListReadGroupsByAccountName(provider.Name)
+	permissions, err := cachedPermissions([]string{provider.Name}, sc, pc)
 	if err != nil {
 		clouddriver.WriteError(c, http.StatusInternalServerError, err)
 		return
 	}
 
-	writeGroups, err := sc.ListWriteGroupsByAccountName(provider.Name)
+	kindMaps, err := sc.ListKindMappingsByAccountNames(provider.Name)
 	if err != nil {
 		clouddriver.WriteError(c, http.StatusInternalServerError, err)
 		return
 	}
 
 	credentials := clouddriver.Credential{
-		AccountType:                 provider.Name,
-		ChallengeDestructiveActions: false,
+		AccountType:                 provider.AccountTypeName(),
+		CacheThreads:                provider.CacheThreadCount(),
+		ChallengeDestructiveActions: provider.ChallengeDestructiveActions,
 		CloudProvider:               "kubernetes",
-		Environment:                 provider.Name,
+		Environment:                 provider.EnvironmentName(),
+		Maintenance:                 provider.Maintenance,
+		MaintenanceMessage:          provider.MaintenanceMessage,
 		Name:                        provider.Name,
 		Permissions: clouddriver.Permissions{
-			READ:  readGroups,
-			WRITE: writeGroups,
+			READ:    permissions[provider.Name].Read,
+			WRITE:   permissions[provider.Name].Write,
+			EXECUTE: permissions[provider.Name].Execute,
 		},
-		PrimaryAccount:          false,
+		PrimaryAccount:          provider.PrimaryAccount,
 		ProviderVersion:         "v2",
+		ReadOnly:                provider.ReadOnly,
 		RequiredGroupMembership: []interface{}{},
 		Skin:                    "v2",
-		SpinnakerKindMap:        spinnakerKindMap,
+		SpinnakerKindMap:        kindMapFor(kindMaps[provider.Name]),
 		Type:                    "kubernetes",
 	}
 
 	c.JSON(http.StatusOK, credentials)
 }
+
+// RotateCredentialsRequest holds the new credentials of an account. Fields
+// left empty keep their current value.
+type RotateCredentialsRequest struct {
+	CAData      string `json:"caData"`
+	BearerToken string `json:"bearerToken"`
+}
+
+var errNoCredentialsToRotate = errors.New("no caData or bearerToken to rotate")
+
+// RotateAccountCredentials validates new credentials of an account against
+// its cluster before replacing the stored credentials, so a bad rotation is
+// rejected instead of failing every request against the account.
+func RotateAccountCredentials(c *gin.Context) {
+	sc := sql.Instance(c)
+	ac := arcade.Instance(c)
+	kc := kubernetes.ControllerInstance(c)
+	nc := kubernetes.NamespaceCacheInstance(c)
+	provider := kubernetes.ProviderInstance(c)
+	rcr := RotateCredentialsRequest{}
+
+	err := c.ShouldBindJSON(&rcr)
+	if err != nil {
+		clouddriver.WriteError(c, http.StatusBadRequest, err)
+		return
+	}
+
+	if rcr.CAData == "" && rcr.BearerToken == "" {
+		clouddriver.WriteError(c, http.StatusBadRequest, errNoCredentialsToRotate)
+		return
+	}
+
+	if provider.ClusterCredential != "" {
+		clouddriver.WriteError(c, http.StatusBadRequest,
+			fmt.Errorf("account %s uses cluster credential %s, rotate it at /v1/kubernetes/clusterCredentials/%s",
+				provider.Name, provider.ClusterCredential, provider.ClusterCredential))
+		return
+	}
+
+	if provider.HasKubeconfig() {
+		clouddriver.WriteError(c, http.StatusBadRequest,
+			fmt.Errorf("account %s uses a kubeconfig, rotate the credentials in it", provider.Name))
+		return
+	}
+
+	if provider.Fake != nil {
+		clouddriver.WriteError(c, http.StatusBadRequest,
+			fmt.Errorf("account %s is fake and has no credentials to rotate", provider.Name))
+		return
+	}
+
+	if rcr.CAData != "" {
+		provider.CAData = rcr.CAData
+	}
+
+	if rcr.BearerToken != "" {
+		provider.BearerToken = rcr.BearerToken
+	}
+
+	cd, err := base64.StdEncoding.DecodeString(provider.CAData)
+	if err != nil {
+		clouddriver.WriteError(c, http.StatusBadRequest, fmt.Errorf("error decoding ca data: %w", err))
+		return
+	}
+
+	token := provider.BearerToken
+	if token == "" {
+		token, err = ac.Token()
+		if err != nil {
+			clouddriver.WriteError(c, http.StatusInternalServerError, err)
+			return
+		}
+	}
+
+	config := &rest.Config{
+		Host:        provider.Host,
+		BearerToken: token,
+		TLSClientConfig: rest.TLSClientConfig{
+			CAData: cd,
+		},
+	}
+
+	client, err := kc.NewClient(config)
+	if err != nil {
+		clouddriver.WriteError(c, http.StatusInternalServerError, err)
+		return
+	}
+
+	// Listing a namespace checks the cluster trusts the token and the CA
+	// verifies the cluster. Accounts may not be allowed to list namespaces,
+	// which still proves the token authenticates.
+	_, err = client.ListMetadataByGVR(schema.GroupVersionResource{Version: "v1", Resource: "namespaces"},
+		metav1.ListOptions{TimeoutSeconds: &listNamespacesTimeout, Limit: 1})
+	if err != nil && !k8serrors.IsForbidden(err) {
+		clouddriver.WriteError(c, http.StatusUnprocessableEntity,
+			fmt.Errorf("error validating credentials of account %s: %w", provider.Name, err))
+		return
+	}
+
+	err = sc.RotateKubernetesProviderCredentials(provider.Name, provider.CAData, provider.BearerToken)
+	if err != nil {
+		clouddriver.WriteError(c, http.StatusInternalServerError, err)
+		return
+	}
+
+	// Clients are created per request from the stored credentials, so only
+	// what was cached using the old credentials needs invalidating.
+	nc.Delete(provider.Name)
+	client.InvalidateDiscovery()
+
+	log.Println("[CREDENTIALS] rotated credentials of account", provider.Name, "by", requestUser(c))
+
+	c.JSON(http.StatusNoContent, nil)
+}
@@ -3,15 +3,21 @@ package core_test
 import (
 	// . "github.com/billiford/go-clouddriver/pkg/http/v0"
 
+	"encoding/json"
 	"errors"
 	"io/ioutil"
 	"log"
 	"net/http"
 
+	clouddriver "github.com/billiford/go-clouddriver/pkg"
+	"github.com/billiford/go-clouddriver/pkg/docker"
 	"github.com/billiford/go-clouddriver/pkg/kubernetes"
+	"github.com/jinzhu/gorm"
 	. "github.com/onsi/ginkgo"
 	. "github.com/onsi/gomega"
-	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
 )
 
 var _ = Describe("Credential", func() {
@@ -21,34 +27,28 @@ var _ = Describe("Credential", func() {
 				setup()
 				uri = svr.URL + "/credentials"
 				createRequest(http.MethodGet)
-				fakeSQLClient.ListKubernetesProvidersAndPermissionsReturns([]kubernetes.Provider{
+				fakeSQLClient.ListKubernetesProvidersReturns([]kubernetes.Provider{
 					{
 						Name:        "provider1",
 						Host:        "host1",
 						CAData:      "dGVzdAo=",
 						BearerToken: "some.bearer.token",
-						Permissions: kubernetes.ProviderPermissions{
-							Read: []string{
-								"gg_test",
-							},
-							Write: []string{
-								"gg_test",
-							},
-						},
 					},
 					{
 						Name:        "provider2",
 						Host:        "host2",
 						CAData:      "dGVzdAo=",
 						BearerToken: "some.bearer.token2",
-						Permissions: kubernetes.ProviderPermissions{
-							Read: []string{
-								"gg_test2",
-							},
-							Write: []string{
-								"gg_test2",
-							},
-						},
+					},
+				}, nil)
+				fakeSQLClient.ListPermissionsByAccountNamesReturns(map[string]kubernetes.ProviderPermissions{
+					"provider1": {
+						Read:  []string{"gg_test"},
+						Write: []string{"gg_test"},
+					},
+					"provider2": {
+						Read:  []string{"gg_test2"},
+						Write: []string{"gg_test2"},
 					},
 				}, nil)
 			})
@@ -63,7 +63,7 @@ var _ = Describe("Credential", func() {
 
 			When("listing providers returns an error", func() {
 				BeforeEach(func() {
-					fakeSQLClient.ListKubernetesProvidersAndPermissionsReturns(nil, errors.New("error listing providers"))
+					fakeSQLClient.ListKubernetesProvidersReturns(nil, errors.New("error listing providers"))
 				})
 
 				It("returns an error", func() {
@@ -75,63 +75,290 @@ var _ = Describe("Credential", func() {
 				})
 			})
 
+			When("listing permissions returns an error", func() {
+				BeforeEach(func() {
+					fakeSQLClient.ListPermissionsByAccountNamesReturns(nil, errors.New("error listing permissions"))
+				})
+
+				It("returns an error", func() {
+					Expect(res.StatusCode).To(Equal(http.StatusInternalServerError))
+					ce := getClouddriverError()
+					Expect(ce.Error).To(Equal("Internal Server Error"))
+					Expect(ce.Message).To(Equal("error listing permissions"))
+					Expect(ce.Status).To(Equal(http.StatusInternalServerError))
+				})
+			})
+
+			When("permissions are cached", func() {
+				BeforeEach(func() {
+					fakeKubePermissionsCache.GetStub = func(account string) (kubernetes.ProviderPermissions, bool) {
+						if account == "provider1" {
+							return kubernetes.ProviderPermissions{
+								Read:  []string{"gg_test"},
+								Write: []string{"gg_test"},
+							}, true
+						}
+
+						return kubernetes.ProviderPermissions{}, false
+					}
+				})
+
+				It("only lists permissions of uncached accounts", func() {
+					Expect(res.StatusCode).To(Equal(http.StatusOK))
+					Expect(fakeSQLClient.ListPermissionsByAccountNamesCallCount()).To(Equal(1))
+					Expect(fakeSQLClient.ListPermissionsByAccountNamesArgsForCall(0)).To(Equal([]string{"provider2"}))
+					Expect(fakeKubePermissionsCache.SetCallCount()).To(Equal(1))
+					validateResponse(payloadCredentials)
+				})
+			})
+
+			When("an account is in maintenance", func() {
+				BeforeEach(func() {
+					fakeSQLClient.ListKubernetesProvidersReturns([]kubernetes.Provider{
+						{
+							Name:               "provider1",
+							Maintenance:        true,
+							MaintenanceMessage: "upgrading to 1.19",
+						},
+					}, nil)
+				})
+
+				It("lists the account as in maintenance", func() {
+					Expect(res.StatusCode).To(Equal(http.StatusOK))
+					credentials := []clouddriver.Credential{}
+					Expect(json.NewDecoder(res.Body).Decode(&credentials)).To(Succeed())
+					Expect(credentials).To(HaveLen(1))
+					Expect(credentials[0].Maintenance).To(BeTrue())
+					Expect(credentials[0].MaintenanceMessage).To(Equal("upgrading to 1.19"))
+				})
+			})
+
+			When("an account is read-only", func() {
+				BeforeEach(func() {
+					fakeSQLClient.ListKubernetesProvidersReturns([]kubernetes.Provider{
+						{
+							Name:     "provider1",
+							ReadOnly: true,
+						},
+					}, nil)
+				})
+
+				It("lists the account as read-only", func() {
+					Expect(res.StatusCode).To(Equal(http.StatusOK))
+					credentials := []clouddriver.Credential{}
+					Expect(json.NewDecoder(res.Body).Decode(&credentials)).To(Succeed())
+					Expect(credentials).To(HaveLen(1))
+					Expect(credentials[0].ReadOnly).To(BeTrue())
+				})
+			})
+
+			When("an account has an environment and account type", func() {
+				BeforeEach(func() {
+					fakeSQLClient.ListKubernetesProvidersReturns([]kubernetes.Provider{
+						{
+							Name:        "provider1",
+							Environment: "prod",
+							AccountType: "kubernetes-prod",
+						},
+						{
+							Name: "provider2",
+						},
+					}, nil)
+				})
+
+				It("lists them, defaulting to the account name", func() {
+					Expect(res.StatusCode).To(Equal(http.StatusOK))
+					credentials := []clouddriver.Credential{}
+					Expect(json.NewDecoder(res.Body).Decode(&credentials)).To(Succeed())
+					Expect(credentials).To(HaveLen(2))
+					Expect(credentials[0].Environment).To(Equal("prod"))
+					Expect(credentials[0].AccountType).To(Equal("kubernetes-prod"))
+					Expect(credentials[1].Environment).To(Equal("provider2"))
+					Expect(credentials[1].AccountType).To(Equal("provider2"))
+				})
+			})
+
+			When("an account challenges destructive actions and is primary", func() {
+				BeforeEach(func() {
+					fakeSQLClient.ListKubernetesProvidersReturns([]kubernetes.Provider{
+						{
+							Name:                        "provider1",
+							ChallengeDestructiveActions: true,
+							PrimaryAccount:              true,
+						},
+					}, nil)
+				})
+
+				It("lists the flags", func() {
+					Expect(res.StatusCode).To(Equal(http.StatusOK))
+					credentials := []clouddriver.Credential{}
+					Expect(json.NewDecoder(res.Body).Decode(&credentials)).To(Succeed())
+					Expect(credentials).To(HaveLen(1))
+					Expect(credentials[0].ChallengeDestructiveActions).To(BeTrue())
+					Expect(credentials[0].PrimaryAccount).To(BeTrue())
+				})
+			})
+
+			When("docker registry accounts are configured", func() {
+				BeforeEach(func() {
+					fakeDockerCredentialsController.ListCredentialsReturns([]docker.Credentials{
+						{
+							Name:    "docker-registry",
+							Address: "https://index.docker.io",
+						},
+					})
+				})
+
+				It("lists the docker registry accounts", func() {
+					Expect(res.StatusCode).To(Equal(http.StatusOK))
+					validateResponse(payloadCredentialsWithDockerRegistry)
+				})
+			})
+
 			When("it succeeds", func() {
+				BeforeEach(func() {
+					fakeSQLClient.GetKubernetesProviderVersionReturns(43, nil)
+				})
+
 				It("succeeds", func() {
 					Expect(res.StatusCode).To(Equal(http.StatusOK))
+					Expect(res.Header.Get("X-Clouddriver-Account-Version")).To(Equal("43"))
+					Expect(fakeSQLClient.ListPermissionsByAccountNamesArgsForCall(0)).To(Equal([]string{"provider1", "provider2"}))
+					Expect(fakeKubePermissionsCache.SetCallCount()).To(Equal(2))
 					validateResponse(payloadCredentials)
 				})
 			})
 		})
 
+		Context("since query param is set", func() {
+			var credentials []clouddriver.Credential
+
+			BeforeEach(func() {
+				setup()
+				uri = svr.URL + "/credentials?since=41"
+				createRequest(http.MethodGet)
+				fakeSQLClient.GetKubernetesProviderVersionReturns(43, nil)
+				fakeSQLClient.ListKubernetesProvidersChangedSinceReturns([]kubernetes.Provider{
+					{
+						Name:        "provider2",
+						Maintenance: true,
+					},
+				}, nil)
+				fakeSQLClient.ListKubernetesProvidersDeletedSinceReturns([]string{"provider3"}, nil)
+				fakeDockerCredentialsController.ListCredentialsReturns([]docker.Credentials{
+					{
+						Name:    "docker-registry",
+						Address: "https://index.docker.io",
+					},
+				})
+				credentials = nil
+			})
+
+			AfterEach(func() {
+				teardown()
+			})
+
+			JustBeforeEach(func() {
+				doRequest()
+
+				if res.StatusCode == http.StatusOK {
+					Expect(json.NewDecoder(res.Body).Decode(&credentials)).To(Succeed())
+				}
+			})
+
+			When("since is not a version", func() {
+				BeforeEach(func() {
+					uri = svr.URL + "/credentials?since=yesterday"
+					createRequest(http.MethodGet)
+				})
+
+				It("returns status bad request", func() {
+					Expect(res.StatusCode).To(Equal(http.StatusBadRequest))
+					ce := getClouddriverError()
+					Expect(ce.Message).To(Equal(`invalid since "yesterday", must be an account version`))
+				})
+			})
+
+			When("getting the version returns an error", func() {
+				BeforeEach(func() {
+					fakeSQLClient.GetKubernetesProviderVersionReturns(0, errors.New("error getting version"))
+				})
+
+				It("returns status internal server error", func() {
+					Expect(res.StatusCode).To(Equal(http.StatusInternalServerError))
+					ce := getClouddriverError()
+					Expect(ce.Message).To(Equal("error getting version"))
+				})
+			})
+
+			When("listing deleted providers returns an error", func() {
+				BeforeEach(func() {
+					fakeSQLClient.ListKubernetesProvidersDeletedSinceReturns(nil, errors.New("error listing deleted providers"))
+				})
+
+				It("returns status internal server error", func() {
+					Expect(res.StatusCode).To(Equal(http.StatusInternalServerError))
+					ce := getClouddriverError()
+					Expect(ce.Message).To(Equal("error listing deleted providers"))
+				})
+			})
+
+			When("it succeeds", func() {
+				It("only lists the accounts changed or deleted since the version", func() {
+					Expect(res.StatusCode).To(Equal(http.StatusOK))
+					Expect(res.Header.Get("X-Clouddriver-Account-Version")).To(Equal("43"))
+					Expect(fakeSQLClient.ListKubernetesProvidersCallCount()).To(BeZero())
+					Expect(fakeSQLClient.ListKubernetesProvidersChangedSinceArgsForCall(0)).To(Equal(int64(41)))
+					Expect(fakeSQLClient.ListKubernetesProvidersDeletedSinceArgsForCall(0)).To(Equal(int64(41)))
+					Expect(credentials).To(HaveLen(2))
+					Expect(credentials[0].Name).To(Equal("provider2"))
+					Expect(credentials[0].Maintenance).To(BeTrue())
+					Expect(credentials[0].Deleted).To(BeFalse())
+					Expect(credentials[1].Name).To(Equal("provider3"))
+					Expect(credentials[1].Deleted).To(BeTrue())
+				})
+			})
+		})
+
 		Context("expand query param is set", func() {
 			BeforeEach(func() {
 				setup()
 				uri = svr.URL + "/credentials?expand=true"
 				createRequest(http.MethodGet)
-				fakeSQLClient.ListKubernetesProvidersAndPermissionsReturns([]kubernetes.Provider{
+				fakeSQLClient.ListKubernetesProvidersReturns([]kubernetes.Provider{
 					{
 						Name:        "provider1",
 						Host:        "host1",
 						CAData:      "dGVzdAo=",
 						BearerToken: "some.bearer.token",
-						Permissions: kubernetes.ProviderPermissions{
-							Read: []string{
-								"gg_test",
-							},
-							Write: []string{
-								"gg_test",
-							},
-						},
 					},
 					{
 						Name:        "provider2",
 						Host:        "host2",
 						CAData:      "dGVzdAo=",
 						BearerToken: "some.bearer.token2",
-						Permissions: kubernetes.ProviderPermissions{
-							Read: []string{
-								"gg_test2",
-							},
-							Write: []string{
-								"gg_test2",
-							},
-						},
 					},
 				}, nil)
-				fakeKubeClient.ListByGVRReturns(&unstructured.UnstructuredList{
-					Items: []unstructured.Unstructured{
+				fakeSQLClient.ListPermissionsByAccountNamesReturns(map[string]kubernetes.ProviderPermissions{
+					"provider1": {
+						Read:  []string{"gg_test"},
+						Write: []string{"gg_test"},
+					},
+					"provider2": {
+						Read:  []string{"gg_test2"},
+						Write: []string{"gg_test2"},
+					},
+				}, nil)
+				fakeKubeClient.ListMetadataByGVRReturns(&metav1.PartialObjectMetadataList{
+					Items: []metav1.PartialObjectMetadata{
 						{
-							Object: map[string]interface{}{
-								"metadata": map[string]interface{}{
-									"name": "namespace1",
-								},
+							ObjectMeta: metav1.ObjectMeta{
+								Name: "namespace1",
 							},
 						},
 						{
-							Object: map[string]interface{}{
-								"metadata": map[string]interface{}{
-									"name": "namespace2",
-								},
+							ObjectMeta: metav1.ObjectMeta{
+								Name: "namespace2",
 							},
 						},
 					},
@@ -149,7 +376,7 @@ var _ = Describe("Credential", func() {
 
 			When("listing providers returns an error", func() {
 				BeforeEach(func() {
-					fakeSQLClient.ListKubernetesProvidersAndPermissionsReturns(nil, errors.New("error listing providers"))
+					fakeSQLClient.ListKubernetesProvidersReturns(nil, errors.New("error listing providers"))
 				})
 
 				It("returns an error", func() {
@@ -163,34 +390,18 @@ var _ = Describe("Credential", func() {
 
 			When("decoding the ca data returns an error", func() {
 				BeforeEach(func() {
-					fakeSQLClient.ListKubernetesProvidersAndPermissionsReturns([]kubernetes.Provider{
+					fakeSQLClient.ListKubernetesProvidersReturns([]kubernetes.Provider{
 						{
 							Name:        "provider1",
 							Host:        "host1",
 							CAData:      "{}",
 							BearerToken: "some.bearer.token",
-							Permissions: kubernetes.ProviderPermissions{
-								Read: []string{
-									"gg_test",
-								},
-								Write: []string{
-									"gg_test",
-								},
-							},
 						},
 						{
 							Name:        "provider2",
 							Host:        "host2",
 							CAData:      "{}",
 							BearerToken: "some.bearer.token2",
-							Permissions: kubernetes.ProviderPermissions{
-								Read: []string{
-									"gg_test2",
-								},
-								Write: []string{
-									"gg_test2",
-								},
-							},
 						},
 					}, nil)
 				})
@@ -227,19 +438,101 @@ var _ = Describe("Credential", func() {
 
 			When("listing namespaces returns an error", func() {
 				BeforeEach(func() {
-					fakeKubeClient.ListByGVRReturns(nil, errors.New("error listing"))
+					fakeKubeClient.ListMetadataByGVRReturns(nil, errors.New("error listing"))
 				})
 
 				It("continues", func() {
 					Expect(res.StatusCode).To(Equal(http.StatusOK))
-					Expect(fakeKubeClient.ListByGVRCallCount()).To(Equal(2))
+					Expect(fakeKubeClient.ListMetadataByGVRCallCount()).To(Equal(2))
 					validateResponse(payloadCredentialsExpandTrueNoNamespaces)
 				})
 			})
 
+			When("an account skips namespace listing", func() {
+				BeforeEach(func() {
+					fakeSQLClient.ListKubernetesProvidersReturns([]kubernetes.Provider{
+						{
+							Name:                 "provider1",
+							Host:                 "host1",
+							CAData:               "dGVzdAo=",
+							BearerToken:          "some.bearer.token",
+							SkipNamespaceListing: true,
+						},
+						{
+							Name:        "provider2",
+							Host:        "host2",
+							CAData:      "dGVzdAo=",
+							BearerToken: "some.bearer.token2",
+						},
+					}, nil)
+				})
+
+				It("returns no namespaces for it without calling its cluster", func() {
+					Expect(res.StatusCode).To(Equal(http.StatusOK))
+					Expect(fakeKubeClient.ListMetadataByGVRCallCount()).To(Equal(1))
+					credentials := []clouddriver.Credential{}
+					b, _ := ioutil.ReadAll(res.Body)
+					Expect(json.Unmarshal(b, &credentials)).To(Succeed())
+					Expect(credentials[0].Name).To(Equal("provider1"))
+					Expect(credentials[0].Namespaces).To(BeEmpty())
+					Expect(credentials[0].Namespaces).ToNot(BeNil())
+					Expect(credentials[1].Namespaces).To(Equal([]string{"namespace1", "namespace2"}))
+				})
+			})
+
+			When("listing kind mappings returns an error", func() {
+				BeforeEach(func() {
+					fakeSQLClient.ListKindMappingsByAccountNamesReturns(nil, errors.New("error listing kind mappings"))
+				})
+
+				It("returns an error", func() {
+					Expect(res.StatusCode).To(Equal(http.StatusInternalServerError))
+					ce := getClouddriverError()
+					Expect(ce.Message).To(Equal("error listing kind mappings"))
+				})
+			})
+
+			When("an account maps its own kinds", func() {
+				BeforeEach(func() {
+					fakeSQLClient.ListKindMappingsByAccountNamesReturns(map[string]map[string]string{
+						"provider1": {
+							"rollout":    "serverGroups",
+							"deployment": "serverGroups",
+						},
+					}, nil)
+				})
+
+				It("merges them into the default kind map of the account", func() {
+					Expect(res.StatusCode).To(Equal(http.StatusOK))
+					Expect(fakeSQLClient.ListKindMappingsByAccountNamesArgsForCall(0)).To(Equal([]string{"provider1", "provider2"}))
+					credentials := []clouddriver.Credential{}
+					Expect(json.NewDecoder(res.Body).Decode(&credentials)).To(Succeed())
+					Expect(credentials).To(HaveLen(2))
+					Expect(credentials[0].SpinnakerKindMap["rollout"]).To(Equal("serverGroups"))
+					Expect(credentials[0].SpinnakerKindMap["deployment"]).To(Equal("serverGroups"))
+					Expect(credentials[0].SpinnakerKindMap["pod"]).To(Equal("instances"))
+					Expect(credentials[1].SpinnakerKindMap).ToNot(HaveKey("rollout"))
+					Expect(credentials[1].SpinnakerKindMap["deployment"]).To(Equal("serverGroupManagers"))
+				})
+			})
+
+			When("the namespaces are cached", func() {
+				BeforeEach(func() {
+					fakeKubeNamespaceCache.GetReturns([]string{"namespace1", "namespace2"}, true)
+				})
+
+				It("returns them without calling the clusters", func() {
+					Expect(res.StatusCode).To(Equal(http.StatusOK))
+					Expect(fakeKubeController.NewClientCallCount()).To(BeZero())
+					Expect(fakeKubeClient.ListMetadataByGVRCallCount()).To(BeZero())
+					validateResponse(payloadCredentialsExpandTrue)
+				})
+			})
+
 			When("it succeeds", func() {
-				It("succeeds", func() {
+				It("succeeds and caches the namespaces", func() {
 					Expect(res.StatusCode).To(Equal(http.StatusOK))
+					Expect(fakeKubeNamespaceCache.SetForProviderCallCount()).To(Equal(2))
 					validateResponse(payloadCredentialsExpandTrue)
 				})
 			})
@@ -305,31 +598,106 @@ var _ = Describe("Credential", func() {
 			})
 		})
 
-		When("listing read groups returns an error", func() {
+		When("the account does not exist", func() {
+			BeforeEach(func() {
+				fakeSQLClient.GetKubernetesProviderReturns(kubernetes.Provider{}, gorm.ErrRecordNotFound)
+			})
+
+			It("returns status not found", func() {
+				Expect(res.StatusCode).To(Equal(http.StatusNotFound))
+				ce := getClouddriverError()
+				Expect(ce.Message).To(Equal("account test-account not found"))
+			})
+		})
+
+		When("listing permissions returns an error", func() {
 			BeforeEach(func() {
-				fakeSQLClient.ListReadGroupsByAccountNameReturns(nil, errors.New("error listing read groups"))
+				fakeSQLClient.ListPermissionsByAccountNamesReturns(nil, errors.New("error listing permissions"))
 			})
 
 			It("returns an error", func() {
 				Expect(res.StatusCode).To(Equal(http.StatusInternalServerError))
 				ce := getClouddriverError()
 				Expect(ce.Error).To(Equal("Internal Server Error"))
-				Expect(ce.Message).To(Equal("error listing read groups"))
+				Expect(ce.Message).To(Equal("error listing permissions"))
 				Expect(ce.Status).To(Equal(http.StatusInternalServerError))
 			})
 		})
 
-		When("listing write groups returns an error", func() {
+		When("the permissions are cached", func() {
 			BeforeEach(func() {
-				fakeSQLClient.ListWriteGroupsByAccountNameReturns(nil, errors.New("error listing write groups"))
+				fakeKubePermissionsCache.GetReturns(kubernetes.ProviderPermissions{}, true)
+			})
+
+			It("does not list permissions", func() {
+				Expect(res.StatusCode).To(Equal(http.StatusOK))
+				Expect(fakeSQLClient.ListPermissionsByAccountNamesCallCount()).To(BeZero())
+			})
+		})
+
+		When("the account has execute groups", func() {
+			BeforeEach(func() {
+				fakeKubePermissionsCache.GetReturns(kubernetes.ProviderPermissions{
+					Read:    []string{"viewers"},
+					Execute: []string{"deployers"},
+				}, true)
+			})
+
+			It("lists them", func() {
+				Expect(res.StatusCode).To(Equal(http.StatusOK))
+				credentials := clouddriver.Credential{}
+				Expect(json.NewDecoder(res.Body).Decode(&credentials)).To(Succeed())
+				Expect(credentials.Permissions.READ).To(Equal([]string{"viewers"}))
+				Expect(credentials.Permissions.EXECUTE).To(Equal([]string{"deployers"}))
+			})
+		})
+
+		When("listing kind mappings returns an error", func() {
+			BeforeEach(func() {
+				fakeSQLClient.ListKindMappingsByAccountNamesReturns(nil, errors.New("error listing kind mappings"))
 			})
 
 			It("returns an error", func() {
 				Expect(res.StatusCode).To(Equal(http.StatusInternalServerError))
 				ce := getClouddriverError()
-				Expect(ce.Error).To(Equal("Internal Server Error"))
-				Expect(ce.Message).To(Equal("error listing write groups"))
-				Expect(ce.Status).To(Equal(http.StatusInternalServerError))
+				Expect(ce.Message).To(Equal("error listing kind mappings"))
+			})
+		})
+
+		When("the account maps its own kinds", func() {
+			BeforeEach(func() {
+				fakeSQLClient.GetKubernetesProviderReturns(kubernetes.Provider{Name: "test-account"}, nil)
+				fakeSQLClient.ListKindMappingsByAccountNamesReturns(map[string]map[string]string{
+					"test-account": {
+						"rollout": "serverGroups",
+					},
+				}, nil)
+			})
+
+			It("merges them into the default kind map", func() {
+				Expect(res.StatusCode).To(Equal(http.StatusOK))
+				credentials := clouddriver.Credential{}
+				Expect(json.NewDecoder(res.Body).Decode(&credentials)).To(Succeed())
+				Expect(credentials.SpinnakerKindMap["rollout"]).To(Equal("serverGroups"))
+				Expect(credentials.SpinnakerKindMap["deployment"]).To(Equal("serverGroupManagers"))
+			})
+		})
+
+		When("the account challenges destructive actions and is primary", func() {
+			BeforeEach(func() {
+				fakeSQLClient.GetKubernetesProviderReturns(kubernetes.Provider{
+					Name:                        "test-account",
+					ChallengeDestructiveActions: true,
+					PrimaryAccount:              true,
+				}, nil)
+			})
+
+			It("returns the flags", func() {
+				Expect(res.StatusCode).To(Equal(http.StatusOK))
+				credentials := clouddriver.Credential{}
+				Expect(json.NewDecoder(res.Body).Decode(&credentials)).To(Succeed())
+				Expect(credentials.ChallengeDestructiveActions).To(BeTrue())
+				Expect(credentials.PrimaryAccount).To(BeTrue())
 			})
 		})
 
@@ -340,4 +708,150 @@ var _ = Describe("Credential", func() {
 			})
 		})
 	})
+	Describe("#RotateAccountCredentials", func() {
+		BeforeEach(func() {
+			setup()
+			uri = svr.URL + "/credentials/test-account/rotate"
+			body.Write([]byte(`{"caData":"bmV3LWNh","bearerToken":"new.bearer.token"}`))
+			createRequest(http.MethodPut)
+		})
+
+		AfterEach(func() {
+			teardown()
+		})
+
+		JustBeforeEach(func() {
+			doRequest()
+		})
+
+		When("no credentials are given", func() {
+			BeforeEach(func() {
+				body.Reset()
+				body.Write([]byte(`{}`))
+				createRequest(http.MethodPut)
+			})
+
+			It("returns status bad request", func() {
+				Expect(res.StatusCode).To(Equal(http.StatusBadRequest))
+				ce := getClouddriverError()
+				Expect(ce.Message).To(Equal("no caData or bearerToken to rotate"))
+			})
+		})
+
+		When("the account uses a cluster credential", func() {
+			BeforeEach(func() {
+				fakeSQLClient.GetKubernetesProviderReturns(kubernetes.Provider{
+					ClusterCredential: "test-cluster",
+					Namespaces:        kubernetes.ProviderNamespaces{"team-a"},
+				}, nil)
+			})
+
+			It("returns status bad request", func() {
+				Expect(res.StatusCode).To(Equal(http.StatusBadRequest))
+				ce := getClouddriverError()
+				Expect(ce.Message).To(Equal("account test-account uses cluster credential test-cluster, " +
+					"rotate it at /v1/kubernetes/clusterCredentials/test-cluster"))
+				Expect(fakeSQLClient.RotateKubernetesProviderCredentialsCallCount()).To(BeZero())
+			})
+		})
+
+		When("the account uses a kubeconfig", func() {
+			BeforeEach(func() {
+				fakeSQLClient.GetKubernetesProviderReturns(kubernetes.Provider{
+					Host:           "https://test-host",
+					KubeconfigFile: "/opt/spinnaker/kubeconfigs/test-account",
+				}, nil)
+			})
+
+			It("returns status bad request", func() {
+				Expect(res.StatusCode).To(Equal(http.StatusBadRequest))
+				ce := getClouddriverError()
+				Expect(ce.Message).To(Equal("account test-account uses a kubeconfig, rotate the credentials in it"))
+				Expect(fakeSQLClient.RotateKubernetesProviderCredentialsCallCount()).To(BeZero())
+			})
+		})
+
+		When("the account is fake", func() {
+			BeforeEach(func() {
+				fakeSQLClient.GetKubernetesProviderReturns(kubernetes.Provider{
+					Host: "https://test-account.fake.invalid",
+					Fake: &kubernetes.ProviderFake{},
+				}, nil)
+			})
+
+			It("returns status bad request", func() {
+				Expect(res.StatusCode).To(Equal(http.StatusBadRequest))
+				ce := getClouddriverError()
+				Expect(ce.Message).To(Equal("account test-account is fake and has no credentials to rotate"))
+				Expect(fakeSQLClient.RotateKubernetesProviderCredentialsCallCount()).To(BeZero())
+			})
+		})
+
+		When("the ca data is not base64 encoded", func() {
+			BeforeEach(func() {
+				body.Reset()
+				body.Write([]byte(`{"caData":"{}{}"}`))
+				createRequest(http.MethodPut)
+			})
+
+			It("returns status bad request", func() {
+				Expect(res.StatusCode).To(Equal(http.StatusBadRequest))
+				ce := getClouddriverError()
+				Expect(ce.Message).To(Equal("error decoding ca data: illegal base64 data at input byte 0"))
+			})
+		})
+
+		When("the cluster rejects the credentials", func() {
+			BeforeEach(func() {
+				fakeKubeClient.ListMetadataByGVRReturns(nil, k8serrors.NewUnauthorized("Unauthorized"))
+			})
+
+			It("returns status unprocessable entity without storing them", func() {
+				Expect(res.StatusCode).To(Equal(http.StatusUnprocessableEntity))
+				ce := getClouddriverError()
+				Expect(ce.Message).To(Equal("error validating credentials of account test-account: Unauthorized"))
+				Expect(fakeSQLClient.RotateKubernetesProviderCredentialsCallCount()).To(BeZero())
+			})
+		})
+
+		When("the token may not list namespaces", func() {
+			BeforeEach(func() {
+				fakeKubeClient.ListMetadataByGVRReturns(nil, k8serrors.NewForbidden(schema.GroupResource{Resource: "namespaces"}, "", errors.New("forbidden")))
+			})
+
+			It("rotates the credentials", func() {
+				Expect(res.StatusCode).To(Equal(http.StatusNoContent))
+				Expect(fakeSQLClient.RotateKubernetesProviderCredentialsCallCount()).To(Equal(1))
+			})
+		})
+
+		When("storing the credentials returns an error", func() {
+			BeforeEach(func() {
+				fakeSQLClient.RotateKubernetesProviderCredentialsReturns(errors.New("error updating provider"))
+			})
+
+			It("returns an error", func() {
+				Expect(res.StatusCode).To(Equal(http.StatusInternalServerError))
+				ce := getClouddriverError()
+				Expect(ce.Message).To(Equal("error updating provider"))
+				Expect(fakeKubeNamespaceCache.DeleteCallCount()).To(BeZero())
+			})
+		})
+
+		When("it succeeds", func() {
+			It("validates the new credentials before swapping them", func() {
+				Expect(res.StatusCode).To(Equal(http.StatusNoContent))
+				config := fakeKubeController.NewClientArgsForCall(0)
+				Expect(config.BearerToken).To(Equal("new.bearer.token"))
+				Expect(string(config.TLSClientConfig.CAData)).To(Equal("new-ca"))
+				Expect(fakeArcadeClient.TokenCallCount()).To(BeZero())
+				name, caData, token := fakeSQLClient.RotateKubernetesProviderCredentialsArgsForCall(0)
+				Expect(name).To(Equal("test-account"))
+				Expect(caData).To(Equal("bmV3LWNh"))
+				Expect(token).To(Equal("new.bearer.token"))
+				Expect(fakeKubeNamespaceCache.DeleteArgsForCall(0)).To(Equal("test-account"))
+				Expect(fakeKubeClient.InvalidateDiscoveryCallCount()).To(Equal(1))
+			})
+		})
+	})
 })
@@ -0,0 +1,147 @@
+package core
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"expvar"
+	"fmt"
+	"net/http"
+	"net/http/pprof"
+	"runtime"
+	runtimepprof "runtime/pprof"
+	"strings"
+	"time"
+
+	clouddriver "github.com/billiford/go-clouddriver/pkg"
+	"github.com/gin-gonic/gin"
+)
+
+// ServeDebugProfile serves the pprof profile named in the path, such as
+// heap, goroutine or profile for a CPU profile, or the index of profiles.
+func ServeDebugProfile(c *gin.Context) {
+	switch name := strings.Trim(c.Param("profile"), "/"); name {
+	case "":
+		pprof.Index(c.Writer, c.Request)
+	case "cmdline":
+		pprof.Cmdline(c.Writer, c.Request)
+	case "profile":
+		pprof.Profile(c.Writer, c.Request)
+	case "symbol":
+		pprof.Symbol(c.Writer, c.Request)
+	case "trace":
+		pprof.Trace(c.Writer, c.Request)
+	default:
+		pprof.Handler(name).ServeHTTP(c.Writer, c.Request)
+	}
+}
+
+// ServeDebugVars serves the variables published with expvar, such as the
+// command line and memory stats, as JSON.
+func ServeDebugVars(c *gin.Context) {
+	expvar.Handler().ServeHTTP(c.Writer, c.Request)
+}
+
+// snapshotFile is a file of a debug snapshot.
+type snapshotFile struct {
+	name  string
+	write func(*bytes.Buffer) error
+}
+
+// snapshotFiles are the files of a debug snapshot: the goroutines with
+// their full stacks, and goroutine and heap profiles for go tool pprof.
+var snapshotFiles = []snapshotFile{
+	{
+		name: "goroutines.txt",
+		write: func(b *bytes.Buffer) error {
+			return runtimepprof.Lookup("goroutine").WriteTo(b, 2)
+		},
+	},
+	{
+		name: "goroutine.pprof",
+		write: func(b *bytes.Buffer) error {
+			return runtimepprof.Lookup("goroutine").WriteTo(b, 0)
+		},
+	},
+	{
+		name: "heap.pprof",
+		write: func(b *bytes.Buffer) error {
+			return runtimepprof.Lookup("heap").WriteTo(b, 0)
+		},
+	},
+	{
+		name: "vars.json",
+		write: func(b *bytes.Buffer) error {
+			b.WriteString("{")
+
+			first := true
+
+			expvar.Do(func(kv expvar.KeyValue) {
+				if !first {
+					b.WriteString(",")
+				}
+
+				first = false
+
+				fmt.Fprintf(b, "\n%q: %s", kv.Key, kv.Value)
+			})
+
+			b.WriteString("\n}\n")
+
+			return nil
+		},
+	},
+}
+
+// CreateDebugSnapshot takes the goroutines, a heap profile and the expvar
+// variables of this instance at once, returning them as a gzipped tarball.
+// Passing ?gc=true runs a garbage collection before the heap is profiled.
+func CreateDebugSnapshot(c *gin.Context) {
+	if c.Query("gc") == "true" {
+		runtime.GC()
+	}
+
+	now := time.Now().UTC()
+	buf := &bytes.Buffer{}
+	gw := gzip.NewWriter(buf)
+	tw := tar.NewWriter(gw)
+
+	for _, f := range snapshotFiles {
+		b := &bytes.Buffer{}
+
+		err := f.write(b)
+		if err != nil {
+			clouddriver.WriteError(c, http.StatusInternalServerError, fmt.Errorf("error writing %s: %w", f.name, err))
+			return
+		}
+
+		err = tw.WriteHeader(&tar.Header{
+			Name:    f.name,
+			Mode:    0644,
+			Size:    int64(b.Len()),
+			ModTime: now,
+		})
+		if err == nil {
+			_, err = tw.Write(b.Bytes())
+		}
+
+		if err != nil {
+			clouddriver.WriteError(c, http.StatusInternalServerError, fmt.Errorf("error archiving %s: %w", f.name, err))
+			return
+		}
+	}
+
+	if err := tw.Close(); err != nil {
+		clouddriver.WriteError(c, http.StatusInternalServerError, err)
+		return
+	}
+
+	if err := gw.Close(); err != nil {
+		clouddriver.WriteError(c, http.StatusInternalServerError, err)
+		return
+	}
+
+	c.Header("Content-Disposition",
+		fmt.Sprintf(`attachment; filename="clouddriver-snapshot-%s.tar.gz"`, now.Format("20060102T150405Z")))
+	c.Data(http.StatusOK, "application/gzip", buf.Bytes())
+}
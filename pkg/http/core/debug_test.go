@@ -0,0 +1,137 @@
+package core_test
+
+import (
+	"archive/tar"
+	"compress/gzip"
+	"encoding/json"
+	"io"
+	"io/ioutil"
+	"net/http"
+
+	"github.com/billiford/go-clouddriver/pkg/fiat"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Debug", func() {
+	// createAdminRequest creates a request made by a Fiat admin.
+	createAdminRequest := func(method string) {
+		createRequest(method)
+		req.Header.Set("X-Spinnaker-User", "test-admin")
+		fakeFiatClient.AuthorizeReturns(fiat.Response{Name: "test-admin", Admin: true}, nil)
+	}
+
+	AfterEach(func() {
+		teardown()
+	})
+
+	JustBeforeEach(func() {
+		doRequest()
+	})
+
+	Describe("#ServeDebugProfile", func() {
+		BeforeEach(func() {
+			setup()
+			uri = svr.URL + "/admin/debug/pprof/goroutine?debug=1"
+			createAdminRequest(http.MethodGet)
+		})
+
+		When("the user is not an admin", func() {
+			BeforeEach(func() {
+				createRequest(http.MethodGet)
+				req.Header.Set("X-Spinnaker-User", "test-user")
+				fakeFiatClient.AuthorizeReturns(fiat.Response{Name: "test-user"}, nil)
+			})
+
+			It("returns status forbidden", func() {
+				Expect(res.StatusCode).To(Equal(http.StatusForbidden))
+			})
+		})
+
+		When("the index is requested", func() {
+			BeforeEach(func() {
+				uri = svr.URL + "/admin/debug/pprof/"
+				createAdminRequest(http.MethodGet)
+			})
+
+			It("lists the profiles", func() {
+				Expect(res.StatusCode).To(Equal(http.StatusOK))
+				b, _ := ioutil.ReadAll(res.Body)
+				Expect(string(b)).To(ContainSubstring("goroutine"))
+				Expect(string(b)).To(ContainSubstring("heap"))
+			})
+		})
+
+		When("the profile is unknown", func() {
+			BeforeEach(func() {
+				uri = svr.URL + "/admin/debug/pprof/unknown"
+				createAdminRequest(http.MethodGet)
+			})
+
+			It("returns status not found", func() {
+				Expect(res.StatusCode).To(Equal(http.StatusNotFound))
+			})
+		})
+
+		It("serves the profile", func() {
+			Expect(res.StatusCode).To(Equal(http.StatusOK))
+			b, _ := ioutil.ReadAll(res.Body)
+			Expect(string(b)).To(HavePrefix("goroutine profile: total "))
+		})
+	})
+
+	Describe("#ServeDebugVars", func() {
+		BeforeEach(func() {
+			setup()
+			uri = svr.URL + "/admin/debug/vars"
+			createAdminRequest(http.MethodGet)
+		})
+
+		It("serves the expvar variables", func() {
+			Expect(res.StatusCode).To(Equal(http.StatusOK))
+			vars := map[string]interface{}{}
+			b, _ := ioutil.ReadAll(res.Body)
+			Expect(json.Unmarshal(b, &vars)).To(Succeed())
+			Expect(vars).To(HaveKey("memstats"))
+		})
+	})
+
+	Describe("#CreateDebugSnapshot", func() {
+		BeforeEach(func() {
+			setup()
+			uri = svr.URL + "/admin/debug/snapshots?gc=true"
+			createAdminRequest(http.MethodPost)
+		})
+
+		It("returns the goroutines, heap and variables of the instance", func() {
+			Expect(res.StatusCode).To(Equal(http.StatusOK))
+			Expect(res.Header.Get("Content-Type")).To(Equal("application/gzip"))
+			Expect(res.Header.Get("Content-Disposition")).To(MatchRegexp(`^attachment; filename="clouddriver-snapshot-\d{8}T\d{6}Z\.tar\.gz"$`))
+
+			gr, err := gzip.NewReader(res.Body)
+			Expect(err).To(BeNil())
+
+			tr := tar.NewReader(gr)
+			files := map[string][]byte{}
+
+			for {
+				h, err := tr.Next()
+				if err == io.EOF {
+					break
+				}
+
+				Expect(err).To(BeNil())
+				files[h.Name], _ = ioutil.ReadAll(tr)
+			}
+
+			Expect(files).To(HaveLen(4))
+			Expect(string(files["goroutines.txt"])).To(HavePrefix("goroutine "))
+			Expect(files["goroutine.pprof"]).ToNot(BeEmpty())
+			Expect(files["heap.pprof"]).ToNot(BeEmpty())
+
+			vars := map[string]interface{}{}
+			Expect(json.Unmarshal(files["vars.json"], &vars)).To(Succeed())
+			Expect(vars).To(HaveKey("cmdline"))
+		})
+	})
+})
@@ -0,0 +1,249 @@
+package core
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"net/http"
+	"sort"
+	"strings"
+	"time"
+
+	clouddriver "github.com/billiford/go-clouddriver/pkg"
+	"github.com/billiford/go-clouddriver/pkg/http/core/kubernetes"
+	"github.com/billiford/go-clouddriver/pkg/routine"
+	"github.com/billiford/go-clouddriver/pkg/sql"
+	"github.com/gin-gonic/gin"
+)
+
+const (
+	// KeyWatchRollouts is set to true to watch the rollout of every deploy
+	// to measure its time to stable.
+	KeyWatchRollouts = `WatchRollouts`
+	// Deploy stats are over the last 30 days, if not passed a window.
+	defaultDeployStatsWindow = 30 * 24 * time.Hour
+)
+
+// DeployStats are the deploys made since a time by application and by the
+// namespace of an account they were made to.
+type DeployStats struct {
+	Since        time.Time                `json:"since"`
+	Applications []ApplicationDeployStats `json:"applications"`
+	Namespaces   []NamespaceDeployStats   `json:"namespaces"`
+}
+
+// ApplicationDeployStats are the deploys of an application.
+type ApplicationDeployStats struct {
+	Application string `json:"application"`
+	DeployCounts
+}
+
+// NamespaceDeployStats are the deploys to a namespace of an account.
+type NamespaceDeployStats struct {
+	Account   string `json:"account"`
+	Namespace string `json:"namespace"`
+	DeployCounts
+}
+
+// DeployCounts are the number of deploys and how many of them failed,
+// either the operation or the rollout. The mean time to stable is of the
+// deploys whose rollouts were watched, and is null if there are none.
+type DeployCounts struct {
+	Deploys                 int      `json:"deploys"`
+	Failed                  int      `json:"failed"`
+	FailureRate             float64  `json:"failureRate"`
+	MeanTimeToStableSeconds *float64 `json:"meanTimeToStableSeconds"`
+}
+
+type deployTally struct {
+	deploys  int
+	failed   int
+	stable   int
+	toStable time.Duration
+}
+
+func (t *deployTally) add(d clouddriver.Deploy) {
+	t.deploys++
+
+	if d.Error != "" || d.Phase == taskPhaseFailed || d.Phase == taskPhaseTimedOut {
+		t.failed++
+	}
+
+	if d.Phase == taskPhaseStable && d.RolledOutAt != nil {
+		t.stable++
+		t.toStable += d.RolledOutAt.Sub(d.CreatedAt)
+	}
+}
+
+func (t *deployTally) counts() DeployCounts {
+	dc := DeployCounts{
+		Deploys: t.deploys,
+		Failed:  t.failed,
+	}
+
+	if t.deploys > 0 {
+		dc.FailureRate = float64(t.failed) / float64(t.deploys)
+	}
+
+	if t.stable > 0 {
+		mean := t.toStable.Seconds() / float64(t.stable)
+		dc.MeanTimeToStableSeconds = &mean
+	}
+
+	return dc
+}
+
+type namespaceKey struct {
+	account   string
+	namespace string
+}
+
+// ListDeployStats returns the number of deploys, their failure rate and
+// mean time to stable by application and by namespace over the window
+// passed in as the query param 'window', such as 168h, or the last 30 days.
+// Pass the comma separated query param 'applications' to only see those.
+func ListDeployStats(c *gin.Context) {
+	sc := sql.ReadOnlyInstance(c)
+	window := defaultDeployStatsWindow
+
+	if w := c.Query("window"); w != "" {
+		d, err := time.ParseDuration(w)
+		if err != nil || d <= 0 {
+			clouddriver.WriteError(c, http.StatusBadRequest, fmt.Errorf("invalid window %q", w))
+			return
+		}
+
+		window = d
+	}
+
+	applications := map[string]bool{}
+
+	for _, application := range strings.Split(c.Query("applications"), ",") {
+		if application != "" {
+			applications[application] = true
+		}
+	}
+
+	since := time.Now().Add(-window).UTC()
+
+	deploys, err := sc.ListDeploysCreatedSince(since)
+	if err != nil {
+		clouddriver.WriteError(c, http.StatusInternalServerError, err)
+		return
+	}
+
+	byApplication := map[string]*deployTally{}
+	byNamespace := map[namespaceKey]*deployTally{}
+	// A deploy to several namespaces counts once for its application.
+	tasks := map[string]bool{}
+
+	for _, d := range deploys {
+		if len(applications) > 0 && !applications[d.Application] {
+			continue
+		}
+
+		nk := namespaceKey{account: d.Account, namespace: d.Namespace}
+		if byNamespace[nk] == nil {
+			byNamespace[nk] = &deployTally{}
+		}
+
+		byNamespace[nk].add(d)
+
+		if tasks[d.TaskID] {
+			continue
+		}
+
+		tasks[d.TaskID] = true
+
+		if byApplication[d.Application] == nil {
+			byApplication[d.Application] = &deployTally{}
+		}
+
+		byApplication[d.Application].add(d)
+	}
+
+	stats := DeployStats{
+		Since:        since,
+		Applications: []ApplicationDeployStats{},
+		Namespaces:   []NamespaceDeployStats{},
+	}
+
+	for application, t := range byApplication {
+		stats.Applications = append(stats.Applications, ApplicationDeployStats{
+			Application:  application,
+			DeployCounts: t.counts(),
+		})
+	}
+
+	sort.Slice(stats.Applications, func(i, j int) bool {
+		return stats.Applications[i].Application < stats.Applications[j].Application
+	})
+
+	for nk, t := range byNamespace {
+		stats.Namespaces = append(stats.Namespaces, NamespaceDeployStats{
+			Account:      nk.account,
+			Namespace:    nk.namespace,
+			DeployCounts: t.counts(),
+		})
+	}
+
+	sort.Slice(stats.Namespaces, func(i, j int) bool {
+		if stats.Namespaces[i].Account != stats.Namespaces[j].Account {
+			return stats.Namespaces[i].Account < stats.Namespaces[j].Account
+		}
+
+		return stats.Namespaces[i].Namespace < stats.Namespaces[j].Namespace
+	})
+
+	c.Set(KeyStats, stats)
+}
+
+// recordDeploy stores a deployManifest operation for deploy stats, once for
+// each namespace it deployed to, with the error it failed with. If rollouts
+// are watched, the rollout of a deploy that succeeded is watched in the
+// background to record when it became stable.
+func recordDeploy(c *gin.Context, sc sql.Client, req kubernetes.Operation, taskID string, err error) {
+	application := c.GetHeader("X-Spinnaker-Application")
+	if application == "" {
+		application = req.DeployManifest.Moniker.App
+	}
+
+	now := time.Now()
+
+	for _, namespace := range req.Namespaces() {
+		d := clouddriver.Deploy{
+			TaskID:      taskID,
+			Namespace:   namespace,
+			Account:     req.Account(),
+			Application: application,
+			CreatedAt:   now,
+		}
+
+		if err != nil {
+			d.Error = err.Error()
+		}
+
+		if serr := sc.CreateDeploy(d); serr != nil {
+			log.Println("[DEPLOY STATS] error recording deploy of task", taskID+":", serr.Error())
+		}
+	}
+
+	if err != nil || !c.GetBool(KeyWatchRollouts) {
+		return
+	}
+
+	sw := newStabilityWatcher(c)
+
+	routine.Go(context.Background(), "deploy-rollout", func(context.Context) {
+		phase, err := sw.wait(req.Account(), taskID)
+		if err != nil {
+			log.Println("[DEPLOY STATS] error watching the rollout of task", taskID+":", err.Error())
+			return
+		}
+
+		err = sw.sc.SetDeployPhase(taskID, phase, time.Now())
+		if err != nil {
+			log.Println("[DEPLOY STATS] error recording the rollout of task", taskID+":", err.Error())
+		}
+	})
+}
@@ -0,0 +1,255 @@
+package core
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"math"
+	"net/http"
+	"regexp"
+	"strconv"
+	"strings"
+
+	clouddriver "github.com/billiford/go-clouddriver/pkg"
+	"github.com/billiford/go-clouddriver/pkg/artifact"
+	"github.com/billiford/go-clouddriver/pkg/docker"
+	"github.com/gin-gonic/gin"
+)
+
+// ListDockerRegistryRepositories lists the repositories of a docker registry account.
+func ListDockerRegistryRepositories(c *gin.Context) {
+	dc := docker.CredentialsControllerInstance(c)
+	account := c.Query("account")
+
+	if account == "" {
+		clouddriver.WriteError(c, http.StatusBadRequest, errors.New("query param \"account\" is required"))
+		return
+	}
+
+	client, err := dc.ClientForAccountName(account)
+	if err != nil {
+		clouddriver.WriteError(c, http.StatusBadRequest, err)
+		return
+	}
+
+	repositories, err := client.Catalog()
+	if err != nil {
+		writeDockerRegistryError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, repositories)
+}
+
+// ListDockerRegistryTags lists the tags of a repository of a docker registry account.
+//
+// Tags are sorted newest version first, or newest image first when the query
+// param 'sortBy' is set to "timestamp" or the account sets "sortTagsByDate".
+//
+// https://github.com/spinnaker/clouddriver/blob/master/clouddriver-docker/src/main/groovy/com/netflix/spinnaker/clouddriver/docker/registry/controllers/DockerRegistryImageLookupController.groovy
+func ListDockerRegistryTags(c *gin.Context) {
+	dc := docker.CredentialsControllerInstance(c)
+	account := c.Query("account")
+	repository := c.Query("repository")
+	sortBy := c.Query("sortBy")
+
+	if account == "" || repository == "" {
+		clouddriver.WriteError(c, http.StatusBadRequest, errors.New("query params \"account\" and \"repository\" are required"))
+		return
+	}
+
+	if sortBy != "" && sortBy != sortBySemver && sortBy != sortByTimestamp {
+		clouddriver.WriteError(c, http.StatusBadRequest, fmt.Errorf("invalid sortBy %s, must be one of \"%s\" or \"%s\"", sortBy, sortBySemver, sortByTimestamp))
+		return
+	}
+
+	client, err := dc.ClientForAccountName(account)
+	if err != nil {
+		clouddriver.WriteError(c, http.StatusBadRequest, err)
+		return
+	}
+
+	tags, err := client.Tags(repository)
+	if err != nil {
+		writeDockerRegistryError(c, err)
+		return
+	}
+
+	if sortBy == "" {
+		sortBy = sortBySemver
+
+		for _, credentials := range dc.ListCredentials() {
+			if credentials.Name == account && credentials.SortTagsByDate {
+				sortBy = sortByTimestamp
+			}
+		}
+	}
+
+	// Don't sort the client's cached slice in place.
+	sorted := make([]string, len(tags))
+	copy(sorted, tags)
+
+	if sortBy == sortByTimestamp {
+		err = docker.SortTagsByCreated(client, repository, sorted)
+		if err != nil {
+			writeDockerRegistryError(c, err)
+			return
+		}
+	} else {
+		docker.SortTagsBySemver(sorted)
+	}
+
+	c.JSON(http.StatusOK, sorted)
+}
+
+const (
+	sortBySemver    = "semver"
+	sortByTimestamp = "timestamp"
+	// Max number of images returned by /dockerRegistry/images/find when 'count' is not set.
+	defaultFindDockerRegistryImagesCount = 500
+)
+
+type DockerRegistryImage struct {
+	Account    string   `json:"account"`
+	Artifact   Artifact `json:"artifact"`
+	ImageID    string   `json:"imageId"`
+	Registry   string   `json:"registry"`
+	Repository string   `json:"repository"`
+	Tag        string   `json:"tag"`
+}
+
+// FindDockerRegistryImages searches docker registry accounts for images matching
+// the query param 'q', which is matched against "repository:tag" and may contain
+// wildcards ("*"). Searching can be limited to one account with the query param
+// 'account' and the number of images returned is limited by the query param 'count'.
+//
+// Repositories are filtered before listing their tags, so a query like "library/nginx:1.*"
+// only lists tags of matching repositories. Tags are sorted newest version first.
+// Repositories that fail to list (or are rate limited) are logged and skipped.
+func FindDockerRegistryImages(c *gin.Context) {
+	dc := docker.CredentialsControllerInstance(c)
+	account := c.Query("account")
+	q := strings.ToLower(c.Query("q"))
+	count := defaultFindDockerRegistryImagesCount
+
+	if c.Query("count") != "" {
+		n, err := strconv.Atoi(c.Query("count"))
+		if err != nil || n < 1 {
+			clouddriver.WriteError(c, http.StatusBadRequest, fmt.Errorf("invalid count %s", c.Query("count")))
+			return
+		}
+
+		count = n
+	}
+
+	repositoryQuery, tagQuery := q, ""
+	if i := strings.LastIndex(q, ":"); i > -1 {
+		repositoryQuery, tagQuery = q[:i], q[i+1:]
+	}
+
+	images := []DockerRegistryImage{}
+
+	for _, credentials := range dc.ListCredentials() {
+		if account != "" && credentials.Name != account {
+			continue
+		}
+
+		client, err := dc.ClientForAccountName(credentials.Name)
+		if err != nil {
+			log.Println("/dockerRegistry/images/find", err.Error())
+			continue
+		}
+
+		repositories, err := client.Catalog()
+		if err != nil {
+			log.Println("/dockerRegistry/images/find", err.Error())
+			continue
+		}
+
+		registry := strings.TrimPrefix(strings.TrimPrefix(strings.TrimSuffix(credentials.Address, "/"), "https://"), "http://")
+
+		for _, repository := range repositories {
+			if !matchesQuery(repositoryQuery, repository) {
+				continue
+			}
+
+			tags, err := client.Tags(repository)
+			if err != nil {
+				log.Println("/dockerRegistry/images/find", err.Error())
+				continue
+			}
+
+			sorted := make([]string, len(tags))
+			copy(sorted, tags)
+			docker.SortTagsBySemver(sorted)
+
+			for _, tag := range sorted {
+				if !matchesQuery(tagQuery, tag) {
+					continue
+				}
+
+				imageID := fmt.Sprintf("%s/%s:%s", registry, repository, tag)
+				images = append(images, DockerRegistryImage{
+					Account: credentials.Name,
+					Artifact: Artifact{
+						Type:      artifact.TypeDockerImage,
+						Name:      fmt.Sprintf("%s/%s", registry, repository),
+						Version:   tag,
+						Reference: imageID,
+					},
+					ImageID:    imageID,
+					Registry:   registry,
+					Repository: repository,
+					Tag:        tag,
+				})
+
+				if len(images) >= count {
+					c.JSON(http.StatusOK, images)
+					return
+				}
+			}
+		}
+	}
+
+	c.JSON(http.StatusOK, images)
+}
+
+// matchesQuery returns true if s matches the query, which may contain wildcards.
+// A query without wildcards matches any string that contains it.
+func matchesQuery(query, s string) bool {
+	if query == "" || query == "*" {
+		return true
+	}
+
+	s = strings.ToLower(s)
+
+	if !strings.Contains(query, "*") {
+		return strings.Contains(s, query)
+	}
+
+	pattern := "^" + strings.ReplaceAll(regexp.QuoteMeta(query), `\*`, ".*") + "$"
+
+	matched, _ := regexp.MatchString(pattern, s)
+
+	return matched
+}
+
+// writeDockerRegistryError writes status bad request for invalid repository names,
+// status too many requests with a Retry-After header when the registry is rate
+// limiting us, otherwise status internal server error.
+func writeDockerRegistryError(c *gin.Context, err error) {
+	if errors.Is(err, docker.ErrInvalidRepository) {
+		clouddriver.WriteError(c, http.StatusBadRequest, err)
+		return
+	}
+
+	var rle *docker.RateLimitError
+	if errors.As(err, &rle) {
+		c.Header("Retry-After", strconv.Itoa(int(math.Ceil(rle.RetryAfter.Seconds()))))
+		clouddriver.WriteError(c, http.StatusTooManyRequests, err)
+
+		return
+	}
+
+	clouddriver.WriteError(c, http.StatusInternalServerError, err)
+}
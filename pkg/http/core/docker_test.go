@@ -0,0 +1,342 @@
+package core_test
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"log"
+	"net/http"
+	"time"
+
+	"github.com/billiford/go-clouddriver/pkg/docker"
+	"github.com/billiford/go-clouddriver/pkg/http/core"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Docker", func() {
+	BeforeEach(func() {
+		setup()
+	})
+
+	AfterEach(func() {
+		teardown()
+	})
+
+	JustBeforeEach(func() {
+		doRequest()
+	})
+
+	Describe("#ListDockerRegistryRepositories", func() {
+		BeforeEach(func() {
+			uri = svr.URL + "/dockerRegistry/images/repositories?account=docker-registry"
+			createRequest(http.MethodGet)
+		})
+
+		When("the account query param is missing", func() {
+			BeforeEach(func() {
+				uri = svr.URL + "/dockerRegistry/images/repositories"
+				createRequest(http.MethodGet)
+			})
+
+			It("returns status bad request", func() {
+				Expect(res.StatusCode).To(Equal(http.StatusBadRequest))
+				ce := getClouddriverError()
+				Expect(ce.Error).To(Equal("Bad Request"))
+				Expect(ce.Message).To(Equal("query param \"account\" is required"))
+				Expect(ce.Status).To(Equal(http.StatusBadRequest))
+			})
+		})
+
+		When("getting the client returns an error", func() {
+			BeforeEach(func() {
+				fakeDockerCredentialsController.ClientForAccountNameReturns(nil, errors.New("docker registry account docker-registry not found"))
+			})
+
+			It("returns status bad request", func() {
+				Expect(res.StatusCode).To(Equal(http.StatusBadRequest))
+				ce := getClouddriverError()
+				Expect(ce.Error).To(Equal("Bad Request"))
+				Expect(ce.Message).To(Equal("docker registry account docker-registry not found"))
+				Expect(ce.Status).To(Equal(http.StatusBadRequest))
+			})
+		})
+
+		When("listing the catalog returns an error", func() {
+			BeforeEach(func() {
+				fakeDockerClient.CatalogReturns(nil, errors.New("error listing docker registry catalog"))
+			})
+
+			It("returns an error", func() {
+				Expect(res.StatusCode).To(Equal(http.StatusInternalServerError))
+				ce := getClouddriverError()
+				Expect(ce.Error).To(Equal("Internal Server Error"))
+				Expect(ce.Message).To(Equal("error listing docker registry catalog"))
+				Expect(ce.Status).To(Equal(http.StatusInternalServerError))
+			})
+		})
+
+		When("it succeeds", func() {
+			It("succeeds", func() {
+				Expect(res.StatusCode).To(Equal(http.StatusOK))
+				Expect(fakeDockerCredentialsController.ClientForAccountNameArgsForCall(0)).To(Equal("docker-registry"))
+				validateResponse(payloadDockerRegistryRepositories)
+			})
+		})
+	})
+
+	Describe("#ListDockerRegistryTags", func() {
+		BeforeEach(func() {
+			uri = svr.URL + "/dockerRegistry/images/tags?account=docker-registry&repository=library/nginx"
+			createRequest(http.MethodGet)
+		})
+
+		When("the repository query param is missing", func() {
+			BeforeEach(func() {
+				uri = svr.URL + "/dockerRegistry/images/tags?account=docker-registry"
+				createRequest(http.MethodGet)
+			})
+
+			It("returns status bad request", func() {
+				Expect(res.StatusCode).To(Equal(http.StatusBadRequest))
+				ce := getClouddriverError()
+				Expect(ce.Error).To(Equal("Bad Request"))
+				Expect(ce.Message).To(Equal("query params \"account\" and \"repository\" are required"))
+				Expect(ce.Status).To(Equal(http.StatusBadRequest))
+			})
+		})
+
+		When("getting the client returns an error", func() {
+			BeforeEach(func() {
+				fakeDockerCredentialsController.ClientForAccountNameReturns(nil, errors.New("docker registry account docker-registry not found"))
+			})
+
+			It("returns status bad request", func() {
+				Expect(res.StatusCode).To(Equal(http.StatusBadRequest))
+				ce := getClouddriverError()
+				Expect(ce.Error).To(Equal("Bad Request"))
+				Expect(ce.Message).To(Equal("docker registry account docker-registry not found"))
+				Expect(ce.Status).To(Equal(http.StatusBadRequest))
+			})
+		})
+
+		When("listing tags returns an error", func() {
+			BeforeEach(func() {
+				fakeDockerClient.TagsReturns(nil, errors.New("error listing docker registry tags"))
+			})
+
+			It("returns an error", func() {
+				Expect(res.StatusCode).To(Equal(http.StatusInternalServerError))
+				ce := getClouddriverError()
+				Expect(ce.Error).To(Equal("Internal Server Error"))
+				Expect(ce.Message).To(Equal("error listing docker registry tags"))
+				Expect(ce.Status).To(Equal(http.StatusInternalServerError))
+			})
+		})
+
+		When("the repository is not a valid repository name", func() {
+			BeforeEach(func() {
+				fakeDockerClient.TagsReturns(nil, fmt.Errorf("%w %q", docker.ErrInvalidRepository, "library/nginx/.."))
+			})
+
+			It("returns status bad request", func() {
+				Expect(res.StatusCode).To(Equal(http.StatusBadRequest))
+				ce := getClouddriverError()
+				Expect(ce.Error).To(Equal("Bad Request"))
+				Expect(ce.Message).To(Equal(`invalid docker repository name "library/nginx/.."`))
+				Expect(ce.Status).To(Equal(http.StatusBadRequest))
+			})
+		})
+
+		When("the sortBy query param is invalid", func() {
+			BeforeEach(func() {
+				uri = svr.URL + "/dockerRegistry/images/tags?account=docker-registry&repository=library/nginx&sortBy=size"
+				createRequest(http.MethodGet)
+			})
+
+			It("returns status bad request", func() {
+				Expect(res.StatusCode).To(Equal(http.StatusBadRequest))
+				ce := getClouddriverError()
+				Expect(ce.Error).To(Equal("Bad Request"))
+				Expect(ce.Message).To(Equal(`invalid sortBy size, must be one of "semver" or "timestamp"`))
+				Expect(ce.Status).To(Equal(http.StatusBadRequest))
+			})
+		})
+
+		When("the registry is rate limiting requests", func() {
+			BeforeEach(func() {
+				fakeDockerClient.TagsReturns(nil, fmt.Errorf("error listing docker registry tags: %w", &docker.RateLimitError{RetryAfter: 30 * time.Second}))
+			})
+
+			It("returns status too many requests", func() {
+				Expect(res.StatusCode).To(Equal(http.StatusTooManyRequests))
+				Expect(res.Header.Get("Retry-After")).To(Equal("30"))
+				ce := getClouddriverError()
+				Expect(ce.Error).To(Equal("Too Many Requests"))
+				Expect(ce.Message).To(Equal("error listing docker registry tags: docker registry rate limit exceeded, retry after 30s"))
+				Expect(ce.Status).To(Equal(http.StatusTooManyRequests))
+			})
+		})
+
+		When("sorting by timestamp", func() {
+			BeforeEach(func() {
+				uri = svr.URL + "/dockerRegistry/images/tags?account=docker-registry&repository=library/nginx&sortBy=timestamp"
+				createRequest(http.MethodGet)
+				fakeDockerClient.CreatedStub = createdStub
+			})
+
+			It("sorts tags newest image first", func() {
+				Expect(res.StatusCode).To(Equal(http.StatusOK))
+				Expect(fakeDockerClient.CreatedCallCount()).To(Equal(3))
+				validateResponse(payloadDockerRegistryTagsSortedByTimestamp)
+			})
+		})
+
+		When("getting an image creation date returns an error", func() {
+			BeforeEach(func() {
+				uri = svr.URL + "/dockerRegistry/images/tags?account=docker-registry&repository=library/nginx&sortBy=timestamp"
+				createRequest(http.MethodGet)
+				fakeDockerClient.CreatedReturns(time.Time{}, errors.New("error getting docker registry manifest"))
+			})
+
+			It("returns an error", func() {
+				Expect(res.StatusCode).To(Equal(http.StatusInternalServerError))
+				ce := getClouddriverError()
+				Expect(ce.Error).To(Equal("Internal Server Error"))
+				Expect(ce.Message).To(Equal("error getting docker registry manifest"))
+				Expect(ce.Status).To(Equal(http.StatusInternalServerError))
+			})
+		})
+
+		When("the account sorts tags by date", func() {
+			BeforeEach(func() {
+				fakeDockerCredentialsController.ListCredentialsReturns([]docker.Credentials{
+					{
+						Name:           "docker-registry",
+						Address:        "https://index.docker.io",
+						SortTagsByDate: true,
+					},
+				})
+				fakeDockerClient.CreatedStub = createdStub
+			})
+
+			It("sorts tags newest image first", func() {
+				Expect(res.StatusCode).To(Equal(http.StatusOK))
+				validateResponse(payloadDockerRegistryTagsSortedByTimestamp)
+			})
+		})
+
+		When("it succeeds", func() {
+			It("sorts tags newest version first", func() {
+				Expect(res.StatusCode).To(Equal(http.StatusOK))
+				Expect(fakeDockerClient.TagsArgsForCall(0)).To(Equal("library/nginx"))
+				Expect(fakeDockerClient.CreatedCallCount()).To(BeZero())
+				validateResponse(payloadDockerRegistryTags)
+			})
+		})
+	})
+
+	Describe("#FindDockerRegistryImages", func() {
+		BeforeEach(func() {
+			uri = svr.URL + "/dockerRegistry/images/find?q=nginx:1.1"
+			createRequest(http.MethodGet)
+			fakeDockerCredentialsController.ListCredentialsReturns([]docker.Credentials{
+				{
+					Name:    "docker-registry",
+					Address: "https://index.docker.io/",
+				},
+			})
+			log.SetOutput(ioutil.Discard)
+		})
+
+		When("the count query param is invalid", func() {
+			BeforeEach(func() {
+				uri = svr.URL + "/dockerRegistry/images/find?q=nginx&count=0"
+				createRequest(http.MethodGet)
+			})
+
+			It("returns status bad request", func() {
+				Expect(res.StatusCode).To(Equal(http.StatusBadRequest))
+				ce := getClouddriverError()
+				Expect(ce.Error).To(Equal("Bad Request"))
+				Expect(ce.Message).To(Equal("invalid count 0"))
+				Expect(ce.Status).To(Equal(http.StatusBadRequest))
+			})
+		})
+
+		When("the account query param does not match any account", func() {
+			BeforeEach(func() {
+				uri = svr.URL + "/dockerRegistry/images/find?q=nginx&account=other-registry"
+				createRequest(http.MethodGet)
+			})
+
+			It("returns no images", func() {
+				Expect(res.StatusCode).To(Equal(http.StatusOK))
+				Expect(fakeDockerCredentialsController.ClientForAccountNameCallCount()).To(BeZero())
+				validateResponse(`[]`)
+			})
+		})
+
+		When("listing the catalog returns an error", func() {
+			BeforeEach(func() {
+				fakeDockerClient.CatalogReturns(nil, errors.New("error listing docker registry catalog"))
+			})
+
+			It("skips the account", func() {
+				Expect(res.StatusCode).To(Equal(http.StatusOK))
+				validateResponse(`[]`)
+			})
+		})
+
+		When("the query contains wildcards", func() {
+			BeforeEach(func() {
+				uri = svr.URL + "/dockerRegistry/images/find?q=library/ngin*:1.*"
+				createRequest(http.MethodGet)
+			})
+
+			It("returns matching images", func() {
+				Expect(res.StatusCode).To(Equal(http.StatusOK))
+				validateResponse(payloadFindDockerRegistryImages)
+			})
+		})
+
+		When("the count is reached", func() {
+			BeforeEach(func() {
+				uri = svr.URL + "/dockerRegistry/images/find?q=*&count=1"
+				createRequest(http.MethodGet)
+			})
+
+			It("stops listing tags", func() {
+				Expect(res.StatusCode).To(Equal(http.StatusOK))
+				Expect(fakeDockerClient.TagsCallCount()).To(Equal(1))
+				images := []core.DockerRegistryImage{}
+				b, _ := ioutil.ReadAll(res.Body)
+				Expect(json.Unmarshal(b, &images)).To(Succeed())
+				Expect(images).To(HaveLen(1))
+				Expect(images[0].ImageID).To(Equal("index.docker.io/library/nginx:1.19"))
+			})
+		})
+
+		When("it succeeds", func() {
+			It("only lists tags of matching repositories", func() {
+				Expect(res.StatusCode).To(Equal(http.StatusOK))
+				Expect(fakeDockerClient.TagsCallCount()).To(Equal(1))
+				Expect(fakeDockerClient.TagsArgsForCall(0)).To(Equal("library/nginx"))
+				validateResponse(payloadFindDockerRegistryImages)
+			})
+		})
+	})
+})
+
+// createdStub returns image creation dates where "latest" is the newest image
+// and "1.19" was pushed before "1.18".
+func createdStub(repository, tag string) (time.Time, error) {
+	created := map[string]time.Time{
+		"1.18":   time.Date(2020, 10, 2, 0, 0, 0, 0, time.UTC),
+		"1.19":   time.Date(2020, 10, 1, 0, 0, 0, 0, time.UTC),
+		"latest": time.Date(2020, 10, 3, 0, 0, 0, 0, time.UTC),
+	}
+
+	return created[tag], nil
+}
@@ -1,8 +1,11 @@
 package core
 
 import (
+	"fmt"
 	"net/http"
 
+	clouddriver "github.com/billiford/go-clouddriver/pkg"
+	"github.com/billiford/go-clouddriver/pkg/sql"
 	"github.com/gin-gonic/gin"
 )
 
@@ -39,6 +42,19 @@ var stages = []string{
 	"destroyServerGroup",
 }
 
+// supportedStages are the stages of the Kubernetes provider that
+// /kubernetes/ops runs. Deck hides the others, which would fail at runtime.
+var supportedStages = map[string]bool{
+	"cleanupArtifacts":       true,
+	"deleteManifest":         true,
+	"deployManifest":         true,
+	"patchManifest":          true,
+	"rollingRestartManifest": true,
+	"runJob":                 true,
+	"scaleManifest":          true,
+	"undoRolloutManifest":    true,
+}
+
 // Expected response:
 //
 // [
@@ -135,12 +151,28 @@ var stages = []string{
 //     "name": "destroyServerGroup"
 //   }
 // ]
+//
+// Stages are enabled if they are supported, unless an admin disables them
+// with PUT /admin/features/stages/{name}.
 func ListStages(c *gin.Context) {
+	sc := sql.ReadOnlyInstance(c)
+
+	toggled, err := toggledFeatures(sc)
+	if err != nil {
+		clouddriver.WriteError(c, http.StatusInternalServerError, err)
+		return
+	}
+
 	response := Stages{}
 
 	for _, stage := range stages {
+		enabled, ok := toggled[stage]
+		if !ok {
+			enabled = true
+		}
+
 		s := Stage{
-			Enabled: false,
+			Enabled: supportedStages[stage] && enabled,
 			Name:    stage,
 		}
 		response = append(response, s)
@@ -148,3 +180,18 @@ func ListStages(c *gin.Context) {
 
 	c.JSON(http.StatusOK, response)
 }
+
+// toggledFeatures returns whether each toggled feature is enabled by name.
+func toggledFeatures(sc sql.Client) (map[string]bool, error) {
+	features, err := sc.ListFeatures()
+	if err != nil {
+		return nil, fmt.Errorf("error listing features: %w", err)
+	}
+
+	enabled := map[string]bool{}
+	for _, f := range features {
+		enabled[f.Name] = f.Enabled
+	}
+
+	return enabled, nil
+}
@@ -0,0 +1,33 @@
+package core
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/billiford/go-clouddriver/pkg/freeze"
+	"github.com/gin-gonic/gin"
+)
+
+// ListFreezes returns all change freezes along with their current or next
+// window, for Deck to show as banners. The 'account' and 'namespace' query
+// params only list the freezes that apply to them.
+func ListFreezes(c *gin.Context) {
+	fc := freeze.ControllerInstance(c)
+	account := c.Query("account")
+	namespace := c.Query("namespace")
+	freezes := []freeze.Status{}
+
+	for _, f := range fc.ListFreezes(time.Now()) {
+		if account != "" && len(f.Accounts) > 0 && !contains(f.Accounts, account) {
+			continue
+		}
+
+		if namespace != "" && len(f.Namespaces) > 0 && !contains(f.Namespaces, namespace) {
+			continue
+		}
+
+		freezes = append(freezes, f)
+	}
+
+	c.JSON(http.StatusOK, freezes)
+}
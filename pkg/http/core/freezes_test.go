@@ -0,0 +1,86 @@
+package core_test
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/billiford/go-clouddriver/pkg/freeze"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Freezes", func() {
+	Describe("#ListFreezes", func() {
+		BeforeEach(func() {
+			setup()
+			uri = svr.URL + "/freezes"
+			createRequest(http.MethodGet)
+
+			start := time.Date(2026, time.October, 16, 17, 0, 0, 0, time.UTC)
+			end := time.Date(2026, time.October, 19, 8, 0, 0, 0, time.UTC)
+			fakeFreezeController.ListFreezesReturns([]freeze.Status{
+				{
+					Freeze: freeze.Freeze{
+						Name:          "weekend",
+						Message:       "No deploys to prod over the weekend.",
+						Schedule:      "0 17 * * FRI",
+						Duration:      "63h",
+						Accounts:      []string{"prod-account"},
+						AllowOverride: true,
+					},
+					Active: true,
+					Start:  &start,
+					End:    &end,
+				},
+				{
+					Freeze: freeze.Freeze{
+						Name:       "holidays",
+						Schedule:   "0 0 24 DEC *",
+						Duration:   "72h",
+						Namespaces: []string{"payments"},
+					},
+				},
+			})
+		})
+
+		AfterEach(func() {
+			teardown()
+		})
+
+		JustBeforeEach(func() {
+			doRequest()
+		})
+
+		When("it succeeds", func() {
+			It("returns all freezes", func() {
+				Expect(res.StatusCode).To(Equal(http.StatusOK))
+				validateResponse(payloadFreezes)
+			})
+		})
+
+		When("filtering by account", func() {
+			BeforeEach(func() {
+				uri = svr.URL + "/freezes?account=test-account"
+				createRequest(http.MethodGet)
+			})
+
+			It("returns the freezes of the account", func() {
+				Expect(res.StatusCode).To(Equal(http.StatusOK))
+				validateResponse(payloadFreezesTestAccount)
+			})
+		})
+
+		When("filtering by namespace", func() {
+			BeforeEach(func() {
+				uri = svr.URL + "/freezes?namespace=default"
+				createRequest(http.MethodGet)
+			})
+
+			It("returns the freezes of the namespace", func() {
+				Expect(res.StatusCode).To(Equal(http.StatusOK))
+				validateResponse(payloadFreezesDefaultNamespace)
+			})
+		})
+	})
+})
@@ -0,0 +1,244 @@
+package core
+
+import (
+	"context"
+	"encoding/base64"
+	"fmt"
+	"log"
+	"strings"
+	"time"
+
+	clouddriver "github.com/billiford/go-clouddriver/pkg"
+	"github.com/billiford/go-clouddriver/pkg/arcade"
+	"github.com/billiford/go-clouddriver/pkg/hook"
+	"github.com/billiford/go-clouddriver/pkg/http/core/kubernetes"
+	kube "github.com/billiford/go-clouddriver/pkg/kubernetes"
+	"github.com/billiford/go-clouddriver/pkg/routine"
+	"github.com/billiford/go-clouddriver/pkg/sql"
+	"github.com/gin-gonic/gin"
+	"k8s.io/client-go/rest"
+)
+
+const (
+	postStabilityInterval = 5 * time.Second
+	postStabilityTimeout  = 30 * time.Minute
+)
+
+// Post-stability hooks are called for the operations that roll out resources.
+var postStabilityOperations = map[string]bool{
+	"deployManifest": true,
+	"patchManifest":  true,
+}
+
+// hookEvent returns the context of an operation posted to its hooks.
+func hookEvent(c *gin.Context, req kubernetes.Operation, taskID string) hook.Event {
+	e := hook.Event{
+		Operation:   req.Name(),
+		Account:     req.Account(),
+		Application: c.GetHeader("X-Spinnaker-Application"),
+		User:        requestUser(c),
+		TaskID:      taskID,
+		Manifests:   req.Manifests(),
+	}
+
+	if e.Application == "" && req.DeployManifest != nil {
+		e.Application = req.DeployManifest.Moniker.App
+	}
+
+	return e
+}
+
+// runPostApplyHooks calls the post-apply hooks of an operation, if hooks
+// are enabled, with the error it failed with.
+func runPostApplyHooks(c *gin.Context, hr hook.Runner, req kubernetes.Operation, taskID string, err error) {
+	if hr == nil {
+		return
+	}
+
+	e := hookEvent(c, req, taskID)
+	if err != nil {
+		e.Error = err.Error()
+	}
+
+	hr.PostApply(e)
+}
+
+// runPostStabilityHooks waits in the background for the resources an
+// operation rolled out to become stable, then calls its post-stability
+// hooks with the outcome. Operations are only watched if a hook matches.
+func runPostStabilityHooks(c *gin.Context, hr hook.Runner, req kubernetes.Operation, taskID string) {
+	if hr == nil || !postStabilityOperations[req.Name()] {
+		return
+	}
+
+	e := hookEvent(c, req, taskID)
+	if !hr.Matches(hook.StagePostStability, e) {
+		return
+	}
+
+	sw := newStabilityWatcher(c)
+	// Deploys watched for deploy stats already record the events of their rollout.
+	if req.DeployManifest != nil && c.GetBool(KeyWatchRollouts) {
+		sw.recordEvents = false
+	}
+
+	routine.Go(context.Background(), "hook-post-stability", func(context.Context) {
+		phase, err := sw.wait(e.Account, taskID)
+		if err != nil {
+			log.Println("[HOOK] error watching the rollout of task", taskID+":", err.Error())
+			e.Error = err.Error()
+		}
+
+		e.Phase = phase
+		hr.PostStability(e)
+	})
+}
+
+type stabilityWatcher struct {
+	sc        sql.Client
+	kc        kube.Controller
+	ac        arcade.Client
+	stability hook.StabilityChecker
+	// recordEvents records the progress of rollouts as events of their task.
+	recordEvents bool
+}
+
+// newStabilityWatcher returns a watcher of rollouts with the clients of a
+// request. Rollouts are watched after the request is done, so the clients
+// must not be bound to its context.
+func newStabilityWatcher(c *gin.Context) *stabilityWatcher {
+	return &stabilityWatcher{
+		sc:           sql.Instance(c).WithContext(context.Background()),
+		kc:           kube.ControllerInstance(c).WithContext(context.Background()),
+		ac:           arcade.Instance(c),
+		stability:    hook.StabilityCheckerInstance(c),
+		recordEvents: true,
+	}
+}
+
+// wait polls the resources a task deployed to an account until they are
+// all stable, one fails or the timeout passes, returning the phase.
+func (sw *stabilityWatcher) wait(account, taskID string) (string, error) {
+	resources, err := sw.sc.ListKubernetesResourcesByTaskID(taskID)
+	if err != nil {
+		return "", err
+	}
+
+	watched := []kube.Resource{}
+
+	for _, r := range resources {
+		if r.AccountName == account && !strings.EqualFold(r.TaskType, "cleanup") && !r.DryRun {
+			watched = append(watched, r)
+		}
+	}
+
+	if len(watched) == 0 {
+		return taskPhaseStable, nil
+	}
+
+	provider, err := sw.sc.GetKubernetesProvider(account)
+	if err != nil {
+		return "", err
+	}
+
+	cd, err := base64.StdEncoding.DecodeString(provider.CAData)
+	if err != nil {
+		return "", err
+	}
+
+	token, err := sw.ac.Token()
+	if err != nil {
+		return "", err
+	}
+
+	config := &rest.Config{
+		Host:        provider.Host,
+		BearerToken: token,
+		TLSClientConfig: rest.TLSClientConfig{
+			CAData: cd,
+		},
+	}
+
+	err = sw.kc.MintToken(provider, config)
+	if err != nil {
+		return "", err
+	}
+
+	client, err := sw.kc.NewClient(config)
+	if err != nil {
+		return "", err
+	}
+
+	timer := time.NewTimer(postStabilityTimeout)
+	defer timer.Stop()
+
+	ticker := time.NewTicker(postStabilityInterval)
+	defer ticker.Stop()
+
+	// The last progress recorded of each resource.
+	progress := map[string]string{}
+
+	for {
+		phase, err := sw.rolloutPhase(client, watched, taskID, progress)
+		if err != nil {
+			return "", err
+		}
+
+		if phase != taskPhaseRollingOut {
+			sw.recordEvent(taskID, clouddriver.TaskEventRolloutFinished, "rollout of account %s finished: %s", account, phase)
+			return phase, nil
+		}
+
+		select {
+		case <-timer.C:
+			sw.recordEvent(taskID, clouddriver.TaskEventRolloutFinished, "rollout of account %s finished: %s after %s",
+				account, taskPhaseTimedOut, postStabilityTimeout)
+			return taskPhaseTimedOut, nil
+		case <-ticker.C:
+		}
+	}
+}
+
+// rolloutPhase returns FAILED if any resource failed, ROLLING_OUT if any
+// is not yet stable, or else STABLE. The ready pods of each workload are
+// recorded as an event of the task whenever they change from progress.
+func (sw *stabilityWatcher) rolloutPhase(client kube.Client, resources []kube.Resource,
+	taskID string, progress map[string]string) (string, error) {
+	phase := taskPhaseStable
+
+	for _, r := range resources {
+		u, err := client.Get(r.Resource, r.Name, r.Namespace)
+		if err != nil {
+			return "", err
+		}
+
+		if ready, desired, ok := readiness(r.Kind, u.Object); ok {
+			key := fmt.Sprintf("%s %s %s", r.Namespace, r.Kind, r.Name)
+			p := fmt.Sprintf("%d/%d ready", ready, desired)
+
+			if progress[key] != p {
+				progress[key] = p
+				sw.recordEvent(taskID, clouddriver.TaskEventRolloutProgress, "waiting on rollout of %s %s in %s: %s",
+					r.Kind, r.Name, r.Namespace, p)
+			}
+		}
+
+		s := resourceStatus(context.Background(), sw.stability, r.AccountName, r.Kind, u.Object)
+
+		switch {
+		case s.Failed.State:
+			return taskPhaseFailed, nil
+		case !s.Stable.State:
+			phase = taskPhaseRollingOut
+		}
+	}
+
+	return phase, nil
+}
+
+// recordEvent records an event of a task, unless the watcher does not record events.
+func (sw *stabilityWatcher) recordEvent(taskID, eventType, format string, args ...interface{}) {
+	if sw.recordEvents {
+		kubernetes.RecordTaskEvent(sw.sc, taskID, eventType, format, args...)
+	}
+}
@@ -0,0 +1,277 @@
+package core
+
+import (
+	"bufio"
+	"context"
+	"encoding/base64"
+	"fmt"
+	"net/http"
+	"sort"
+	"strings"
+	"time"
+
+	clouddriver "github.com/billiford/go-clouddriver/pkg"
+	"github.com/billiford/go-clouddriver/pkg/arcade"
+	"github.com/billiford/go-clouddriver/pkg/kubernetes"
+	"github.com/billiford/go-clouddriver/pkg/routine"
+	"github.com/gin-gonic/gin"
+	"golang.org/x/net/websocket"
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/client-go/rest"
+)
+
+const (
+	jobLogsInterval = time.Second
+	// Log lines longer than this end the stream of a pod with an error.
+	maxJobLogLine = 1024 * 1024
+)
+
+// jobLogMessage is a message sent over the websocket of StreamJobLogs.
+// The last message has the state of the job.
+type jobLogMessage struct {
+	Pod   string `json:"pod,omitempty"`
+	Line  string `json:"line,omitempty"`
+	Error string `json:"error,omitempty"`
+	State string `json:"state,omitempty"`
+}
+
+// StreamJobLogs upgrades the request to a websocket and streams the logs of
+// a job's pods as they are written, one JSON message per line, until the
+// job finishes and every pod's logs have been sent. Pods are streamed in the
+// order they were created, so retries follow the pods they replace. Set
+// ?container= to stream a container other than the first of each pod.
+func StreamJobLogs(c *gin.Context) {
+	kc := kubernetes.ControllerInstance(c)
+	ac := arcade.Instance(c)
+	location := c.Param("location")
+	container := c.Query("container")
+
+	nameArray := strings.Split(c.Param("name"), " ")
+	if len(nameArray) != 2 {
+		clouddriver.WriteError(c, http.StatusBadRequest,
+			fmt.Errorf("invalid job %q, expected a kind and name such as \"job my-job\"", c.Param("name")))
+		return
+	}
+
+	kind := nameArray[0]
+	name := nameArray[1]
+
+	provider := kubernetes.ProviderInstance(c)
+
+	cd, err := base64.StdEncoding.DecodeString(provider.CAData)
+	if err != nil {
+		clouddriver.WriteError(c, http.StatusInternalServerError, err)
+		return
+	}
+
+	token, err := ac.Token()
+	if err != nil {
+		clouddriver.WriteError(c, http.StatusInternalServerError, err)
+		return
+	}
+
+	config := &rest.Config{
+		Host:        provider.Host,
+		BearerToken: token,
+		TLSClientConfig: rest.TLSClientConfig{
+			CAData: cd,
+		},
+	}
+
+	client, err := kubernetes.NewProviderClient(kc, provider, config)
+	if err != nil {
+		clouddriver.WriteError(c, http.StatusInternalServerError, err)
+		return
+	}
+
+	// Get the job before upgrading, so errors are returned like any other request's.
+	_, err = client.Get(kind, name, location)
+	if err != nil {
+		if errors.IsNotFound(err) {
+			clouddriver.WriteError(c, http.StatusNotFound, err)
+		} else {
+			clouddriver.WriteError(c, http.StatusInternalServerError, err)
+		}
+
+		return
+	}
+
+	s := websocket.Server{
+		// Requests are authorized like any other, regardless of their origin.
+		Handshake: func(*websocket.Config, *http.Request) error {
+			return nil
+		},
+		Handler: func(ws *websocket.Conn) {
+			defer ws.Close()
+
+			ctx, cancel := context.WithCancel(c.Request.Context())
+			defer cancel()
+
+			// Stop streaming when the client closes the websocket.
+			routine.Go(ctx, "job-logs-receive", func(context.Context) {
+				defer cancel()
+
+				var msg string
+
+				for {
+					if err := websocket.Message.Receive(ws, &msg); err != nil {
+						return
+					}
+				}
+			})
+
+			jl := &jobLogs{
+				ctx:       ctx,
+				ws:        ws,
+				client:    client,
+				kind:      kind,
+				name:      name,
+				namespace: location,
+				container: container,
+			}
+			jl.stream()
+		},
+	}
+
+	s.ServeHTTP(c.Writer, c.Request)
+}
+
+type jobLogs struct {
+	ctx       context.Context
+	ws        *websocket.Conn
+	client    kubernetes.Client
+	kind      string
+	name      string
+	namespace string
+	container string
+}
+
+// stream follows the logs of each pod of the job once it has started,
+// until the job finishes.
+func (jl *jobLogs) stream() {
+	streamed := map[string]bool{}
+
+	ticker := time.NewTicker(jobLogsInterval)
+	defer ticker.Stop()
+
+	for {
+		// Get the job before its pods, so pods started before it finished are streamed.
+		u, err := jl.client.Get(jl.kind, jl.name, jl.namespace)
+		if err != nil {
+			jl.send(jobLogMessage{Error: err.Error()})
+			return
+		}
+
+		state := jobState(u.Object)
+
+		pods, err := jl.client.ListResource("pods", metav1.ListOptions{
+			LabelSelector: "job-name=" + jl.name,
+			FieldSelector: "metadata.namespace=" + jl.namespace,
+		})
+		if err != nil {
+			jl.send(jobLogMessage{Error: err.Error()})
+			return
+		}
+
+		sort.Slice(pods.Items, func(i, j int) bool {
+			return pods.Items[i].GetCreationTimestamp().Time.Before(pods.Items[j].GetCreationTimestamp().Time)
+		})
+
+		for _, p := range pods.Items {
+			if streamed[p.GetName()] {
+				continue
+			}
+
+			// Logs are not available until the pod's containers start.
+			phase, _, _ := unstructured.NestedString(p.Object, "status", "phase")
+			if phase == string(corev1.PodPending) || phase == "" {
+				continue
+			}
+
+			streamed[p.GetName()] = true
+
+			err = jl.streamPod(p)
+			if err != nil {
+				jl.send(jobLogMessage{Pod: p.GetName(), Error: err.Error()})
+			}
+
+			if jl.ctx.Err() != nil {
+				return
+			}
+		}
+
+		// Pods still pending when the job finishes never wrote logs.
+		if state != "Running" {
+			jl.send(jobLogMessage{State: state})
+			return
+		}
+
+		select {
+		case <-jl.ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// streamPod follows the logs of a pod until its container exits.
+func (jl *jobLogs) streamPod(p unstructured.Unstructured) error {
+	container := jl.container
+	if container == "" {
+		containers, _, _ := unstructured.NestedSlice(p.Object, "spec", "containers")
+		if len(containers) > 0 {
+			if m, ok := containers[0].(map[string]interface{}); ok {
+				container, _, _ = unstructured.NestedString(m, "name")
+			}
+		}
+	}
+
+	rc, err := jl.client.StreamLogs(jl.ctx, p.GetName(), jl.namespace, corev1.PodLogOptions{
+		Container: container,
+		Follow:    true,
+	})
+	if err != nil {
+		return err
+	}
+	defer rc.Close()
+
+	scanner := bufio.NewScanner(rc)
+	scanner.Buffer(make([]byte, 64*1024), maxJobLogLine)
+
+	for scanner.Scan() {
+		if err := jl.send(jobLogMessage{Pod: p.GetName(), Line: scanner.Text()}); err != nil {
+			return err
+		}
+	}
+
+	if jl.ctx.Err() != nil {
+		return nil
+	}
+
+	return scanner.Err()
+}
+
+func (jl *jobLogs) send(m jobLogMessage) error {
+	return websocket.JSON.Send(jl.ws, m)
+}
+
+// jobState returns the state of a job. Failed jobs have no completion time,
+// so a job with a failed condition is failed even if it reads as running.
+func jobState(m map[string]interface{}) string {
+	j := kubernetes.NewJob(m)
+	state := j.State()
+
+	if state == "Running" {
+		for _, condition := range j.Object().Status.Conditions {
+			if condition.Type == batchv1.JobFailed && condition.Status == corev1.ConditionTrue {
+				return "Failed"
+			}
+		}
+	}
+
+	return state
+}
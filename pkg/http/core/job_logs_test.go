@@ -0,0 +1,241 @@
+package core_test
+
+import (
+	"context"
+	"errors"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"strings"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"golang.org/x/net/websocket"
+	corev1 "k8s.io/api/core/v1"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+var _ = Describe("JobLogs", func() {
+	Describe("#StreamJobLogs", func() {
+		var (
+			job = func(status map[string]interface{}) *unstructured.Unstructured {
+				return &unstructured.Unstructured{
+					Object: map[string]interface{}{
+						"kind":       "Job",
+						"apiVersion": "batch/v1",
+						"metadata": map[string]interface{}{
+							"name":      "test-job",
+							"namespace": "test-namespace",
+						},
+						"status": status,
+					},
+				}
+			}
+			pod = func(name, created, phase string) unstructured.Unstructured {
+				return unstructured.Unstructured{
+					Object: map[string]interface{}{
+						"kind":       "Pod",
+						"apiVersion": "v1",
+						"metadata": map[string]interface{}{
+							"name":              name,
+							"namespace":         "test-namespace",
+							"creationTimestamp": created,
+						},
+						"spec": map[string]interface{}{
+							"containers": []interface{}{
+								map[string]interface{}{
+									"name": "migrate",
+								},
+								map[string]interface{}{
+									"name": "sidecar",
+								},
+							},
+						},
+						"status": map[string]interface{}{
+							"phase": phase,
+						},
+					},
+				}
+			}
+		)
+
+		BeforeEach(func() {
+			setup()
+			uri = svr.URL + "/applications/test-application/jobs/test-account/test-namespace/job%20test-job/logs"
+			createRequest(http.MethodGet)
+			fakeKubeClient.GetReturns(job(map[string]interface{}{
+				"completionTime": "2020-02-13T14:15:03Z",
+				"succeeded":      int64(1),
+			}), nil)
+			fakeKubeClient.ListResourceReturns(&unstructured.UnstructuredList{
+				Items: []unstructured.Unstructured{
+					pod("test-job-retry", "2020-02-13T14:14:03Z", "Succeeded"),
+					pod("test-job-first", "2020-02-13T14:12:03Z", "Failed"),
+					pod("test-job-pending", "2020-02-13T14:15:03Z", "Pending"),
+				},
+			}, nil)
+			fakeKubeClient.StreamLogsStub = func(_ context.Context, name, _ string, _ corev1.PodLogOptions) (io.ReadCloser, error) {
+				return ioutil.NopCloser(strings.NewReader("starting " + name + "\ndone\n")), nil
+			}
+		})
+
+		When("the request is not upgraded", func() {
+			AfterEach(func() {
+				teardown()
+			})
+
+			JustBeforeEach(func() {
+				doRequest()
+			})
+
+			When("the name has no kind", func() {
+				BeforeEach(func() {
+					uri = svr.URL + "/applications/test-application/jobs/test-account/test-namespace/test-job/logs"
+					createRequest(http.MethodGet)
+				})
+
+				It("returns status bad request", func() {
+					Expect(res.StatusCode).To(Equal(http.StatusBadRequest))
+					ce := getClouddriverError()
+					Expect(ce.Message).To(Equal(`invalid job "test-job", expected a kind and name such as "job my-job"`))
+				})
+			})
+
+			When("the job is not found", func() {
+				BeforeEach(func() {
+					fakeKubeClient.GetReturns(nil, k8serrors.NewNotFound(schema.GroupResource{Resource: "jobs"}, "test-job"))
+				})
+
+				It("returns status not found", func() {
+					Expect(res.StatusCode).To(Equal(http.StatusNotFound))
+					Expect(fakeKubeClient.StreamLogsCallCount()).To(BeZero())
+				})
+			})
+
+			When("getting the job returns an error", func() {
+				BeforeEach(func() {
+					fakeKubeClient.GetReturns(nil, errors.New("error getting job"))
+				})
+
+				It("returns status internal server error", func() {
+					Expect(res.StatusCode).To(Equal(http.StatusInternalServerError))
+					ce := getClouddriverError()
+					Expect(ce.Message).To(Equal("error getting job"))
+				})
+			})
+		})
+
+		When("the request is upgraded", func() {
+			var messages []map[string]string
+
+			AfterEach(func() {
+				svr.Close()
+			})
+
+			JustBeforeEach(func() {
+				ws, err := websocket.Dial("ws"+strings.TrimPrefix(uri, "http"), "", svr.URL)
+				Expect(err).To(BeNil())
+				defer ws.Close()
+
+				messages = []map[string]string{}
+
+				for {
+					m := map[string]string{}
+					if err := websocket.JSON.Receive(ws, &m); err != nil {
+						break
+					}
+
+					messages = append(messages, m)
+
+					if m["state"] != "" {
+						break
+					}
+				}
+			})
+
+			When("the job succeeded", func() {
+				It("streams the logs of each started pod in the order they were created", func() {
+					Expect(messages).To(Equal([]map[string]string{
+						{"pod": "test-job-first", "line": "starting test-job-first"},
+						{"pod": "test-job-first", "line": "done"},
+						{"pod": "test-job-retry", "line": "starting test-job-retry"},
+						{"pod": "test-job-retry", "line": "done"},
+						{"state": "Succeeded"},
+					}))
+				})
+
+				It("follows the first container of each pod", func() {
+					Expect(fakeKubeClient.StreamLogsCallCount()).To(Equal(2))
+					_, name, namespace, plo := fakeKubeClient.StreamLogsArgsForCall(0)
+					Expect(name).To(Equal("test-job-first"))
+					Expect(namespace).To(Equal("test-namespace"))
+					Expect(plo.Container).To(Equal("migrate"))
+					Expect(plo.Follow).To(BeTrue())
+				})
+
+				It("lists the pods of the job", func() {
+					resource, lo := fakeKubeClient.ListResourceArgsForCall(0)
+					Expect(resource).To(Equal("pods"))
+					Expect(lo.LabelSelector).To(Equal("job-name=test-job"))
+					Expect(lo.FieldSelector).To(Equal("metadata.namespace=test-namespace"))
+				})
+			})
+
+			When("a container is requested", func() {
+				BeforeEach(func() {
+					uri += "?container=sidecar"
+				})
+
+				It("follows the container", func() {
+					_, _, _, plo := fakeKubeClient.StreamLogsArgsForCall(0)
+					Expect(plo.Container).To(Equal("sidecar"))
+				})
+			})
+
+			When("the job failed", func() {
+				BeforeEach(func() {
+					fakeKubeClient.GetReturns(job(map[string]interface{}{
+						"conditions": []interface{}{
+							map[string]interface{}{
+								"type":   "Failed",
+								"status": "True",
+							},
+						},
+					}), nil)
+				})
+
+				It("ends with the failed state", func() {
+					Expect(messages[len(messages)-1]).To(Equal(map[string]string{"state": "Failed"}))
+				})
+			})
+
+			When("streaming the logs of a pod returns an error", func() {
+				BeforeEach(func() {
+					fakeKubeClient.StreamLogsReturns(nil, errors.New("error streaming logs"))
+				})
+
+				It("sends the error and continues", func() {
+					Expect(messages).To(Equal([]map[string]string{
+						{"pod": "test-job-first", "error": "error streaming logs"},
+						{"pod": "test-job-retry", "error": "error streaming logs"},
+						{"state": "Succeeded"},
+					}))
+				})
+			})
+
+			When("listing the pods returns an error", func() {
+				BeforeEach(func() {
+					fakeKubeClient.ListResourceReturns(nil, errors.New("error listing pods"))
+				})
+
+				It("sends the error", func() {
+					Expect(messages).To(Equal([]map[string]string{
+						{"error": "error listing pods"},
+					}))
+				})
+			})
+		})
+	})
+})
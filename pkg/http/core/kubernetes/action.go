@@ -1,8 +1,17 @@
 package kubernetes
 
 import (
+	"context"
+	"time"
+
 	"github.com/billiford/go-clouddriver/pkg/arcade"
+	"github.com/billiford/go-clouddriver/pkg/artifact"
+	"github.com/billiford/go-clouddriver/pkg/hook"
 	"github.com/billiford/go-clouddriver/pkg/kubernetes"
+	"github.com/billiford/go-clouddriver/pkg/kubernetes/decrypt"
+	"github.com/billiford/go-clouddriver/pkg/kubernetes/lint"
+	"github.com/billiford/go-clouddriver/pkg/kubernetes/schedule"
+	"github.com/billiford/go-clouddriver/pkg/kubernetes/verify"
 	"github.com/billiford/go-clouddriver/pkg/sql"
 	"github.com/gin-gonic/gin"
 )
@@ -13,19 +22,36 @@ type Action interface {
 }
 
 type ActionConfig struct {
-	ArcadeClient   arcade.Client
-	KubeController kubernetes.Controller
-	SQLClient      sql.Client
-	ID             string
-	Application    string
-	Operation      Operation
+	ArcadeClient                  arcade.Client
+	ArtifactCredentialsController artifact.CredentialsController
+	KubeController                kubernetes.Controller
+	SQLClient                     sql.Client
+	ID                            string
+	Application                   string
+	Operation                     Operation
+	// Context is the context of the request of the operation, so actions
+	// stop their external calls if the request is cancelled.
+	Context context.Context
+}
+
+// ctx returns the context of the operation, or the background context if
+// there is none.
+func (ac ActionConfig) ctx() context.Context {
+	if ac.Context == nil {
+		return context.Background()
+	}
+
+	return ac.Context
 }
 
 //go:generate counterfeiter . ActionHandler
 type ActionHandler interface {
 	NewCleanupArtifactsAction(ActionConfig) Action
+	NewCreateApplicationAction(ActionConfig) Action
+	NewDeleteApplicationAction(ActionConfig) Action
 	NewDeployManifestAction(ActionConfig) Action
 	NewDeleteManifestAction(ActionConfig) Action
+	NewMigrateApplicationAction(ActionConfig) Action
 	NewRollingRestartAction(ActionConfig) Action
 	NewRollbackAction(ActionConfig) Action
 	NewRunJobAction(ActionConfig) Action
@@ -36,10 +62,54 @@ type ActionHandler interface {
 const ActionHandlerInstanceKey = `KubernetesActionHandler`
 
 func NewActionHandler() ActionHandler {
-	return &actionHandler{}
+	return NewActionHandlerWithConfig(ActionHandlerConfig{})
+}
+
+// ActionHandlerConfig configures optional steps of actions.
+type ActionHandlerConfig struct {
+	// Linter lints manifests before they are deployed, if set.
+	Linter *lint.Linter
+	// MigrateRemovedAPIVersions converts manifests using API versions the
+	// target cluster no longer serves when there is a lossless conversion,
+	// instead of failing the deploy.
+	MigrateRemovedAPIVersions bool
+	// DefaultMetadata are the labels and annotations added to deployed manifests per account.
+	DefaultMetadata kubernetes.MetadataConfig
+	// Templating defines which accounts substitute placeholders in deployed manifests.
+	Templating kubernetes.TemplateConfig
+	// Decrypters decrypt encrypted manifests, such as SOPS encrypted secrets, before they are deployed.
+	Decrypters []decrypt.Decrypter
+	// ValidateScheduling warns about deployed manifests whose pods no node can run.
+	ValidateScheduling bool
+	// CapacityCheck warns about or fails deploys whose pods exceed the schedulable capacity of the cluster.
+	CapacityCheck schedule.CapacityCheck
+	// Mutator posts rendered manifests to the mutation webhooks of their account before they are deployed, if set.
+	Mutator hook.Mutator
+	// TrafficGuards block deleting or scaling to zero the last healthy server group serving a service of guarded clusters.
+	TrafficGuards kubernetes.TrafficGuardConfig
+	// URLFetcher fetches the manifest artifacts of deploys. Defaults to one
+	// with the default size limit and cache.
+	URLFetcher *artifact.URLFetcher
+	// Verifier verifies the signatures or attestations of the images of deployed, patched
+	// and run manifests, and pins them to the verified digests, if set.
+	Verifier verify.Verifier
+	// JobTTL deletes Jobs run by runJob this long after they finish, if set.
+	JobTTL time.Duration
 }
 
-type actionHandler struct{}
+// NewActionHandlerWithConfig returns an ActionHandler whose actions run
+// the optional steps in config.
+func NewActionHandlerWithConfig(config ActionHandlerConfig) ActionHandler {
+	if config.URLFetcher == nil {
+		config.URLFetcher = artifact.NewURLFetcher(0, 0)
+	}
+
+	return &actionHandler{config: config}
+}
+
+type actionHandler struct {
+	config ActionHandlerConfig
+}
 
 func ActionHandlerInstance(c *gin.Context) ActionHandler {
 	return c.MustGet(ActionHandlerInstanceKey).(ActionHandler)
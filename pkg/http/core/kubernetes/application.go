@@ -0,0 +1,51 @@
+package kubernetes
+
+import (
+	"errors"
+
+	"github.com/billiford/go-clouddriver/pkg/sql"
+)
+
+func (ah *actionHandler) NewCreateApplicationAction(ac ActionConfig) Action {
+	return &createApplication{
+		sc: ac.SQLClient,
+		ca: ac.Operation.CreateApplication,
+	}
+}
+
+type createApplication struct {
+	sc sql.Client
+	ca *CreateApplicationRequest
+}
+
+// Run stores the application's metadata, which is listed in /applications
+// whether or not anything has been deployed for it.
+func (c *createApplication) Run() error {
+	if c.ca.Application.Name == "" {
+		return errors.New("application name is required")
+	}
+
+	return c.sc.CreateApplication(c.ca.Application)
+}
+
+func (ah *actionHandler) NewDeleteApplicationAction(ac ActionConfig) Action {
+	return &deleteApplication{
+		sc: ac.SQLClient,
+		da: ac.Operation.DeleteApplication,
+	}
+}
+
+type deleteApplication struct {
+	sc sql.Client
+	da *DeleteApplicationRequest
+}
+
+// Run deletes the application's metadata. The application is still listed
+// in /applications while it has deployed resources.
+func (d *deleteApplication) Run() error {
+	if d.da.Application.Name == "" {
+		return errors.New("application name is required")
+	}
+
+	return d.sc.DeleteApplication(d.da.Application.Name)
+}
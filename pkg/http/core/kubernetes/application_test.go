@@ -0,0 +1,110 @@
+package kubernetes_test
+
+import (
+	"errors"
+
+	clouddriver "github.com/billiford/go-clouddriver/pkg"
+	. "github.com/billiford/go-clouddriver/pkg/http/core/kubernetes"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Application", func() {
+	BeforeEach(func() {
+		setup()
+	})
+
+	Describe("#NewCreateApplicationAction", func() {
+		BeforeEach(func() {
+			actionConfig.Operation.CreateApplication = &CreateApplicationRequest{
+				Application: clouddriver.Application{
+					Name:  "test-application",
+					Email: "test@example.com",
+					Permissions: clouddriver.Permissions{
+						READ: []string{"test-group"},
+					},
+				},
+			}
+		})
+
+		JustBeforeEach(func() {
+			action = actionHandler.NewCreateApplicationAction(actionConfig)
+			err = action.Run()
+		})
+
+		When("the application has no name", func() {
+			BeforeEach(func() {
+				actionConfig.Operation.CreateApplication.Application.Name = ""
+			})
+
+			It("returns an error", func() {
+				Expect(err).ToNot(BeNil())
+				Expect(err.Error()).To(Equal("application name is required"))
+				Expect(fakeSQLClient.CreateApplicationCallCount()).To(Equal(0))
+			})
+		})
+
+		When("creating the application returns an error", func() {
+			BeforeEach(func() {
+				fakeSQLClient.CreateApplicationReturns(errors.New("error creating application"))
+			})
+
+			It("returns an error", func() {
+				Expect(err).ToNot(BeNil())
+				Expect(err.Error()).To(Equal("error creating application"))
+			})
+		})
+
+		When("it succeeds", func() {
+			It("stores the application", func() {
+				Expect(err).To(BeNil())
+				a := fakeSQLClient.CreateApplicationArgsForCall(0)
+				Expect(a.Name).To(Equal("test-application"))
+				Expect(a.Email).To(Equal("test@example.com"))
+				Expect(a.Permissions.READ).To(Equal([]string{"test-group"}))
+			})
+		})
+	})
+
+	Describe("#NewDeleteApplicationAction", func() {
+		BeforeEach(func() {
+			actionConfig.Operation.DeleteApplication = &DeleteApplicationRequest{}
+			actionConfig.Operation.DeleteApplication.Application.Name = "test-application"
+		})
+
+		JustBeforeEach(func() {
+			action = actionHandler.NewDeleteApplicationAction(actionConfig)
+			err = action.Run()
+		})
+
+		When("the application has no name", func() {
+			BeforeEach(func() {
+				actionConfig.Operation.DeleteApplication.Application.Name = ""
+			})
+
+			It("returns an error", func() {
+				Expect(err).ToNot(BeNil())
+				Expect(err.Error()).To(Equal("application name is required"))
+				Expect(fakeSQLClient.DeleteApplicationCallCount()).To(Equal(0))
+			})
+		})
+
+		When("deleting the application returns an error", func() {
+			BeforeEach(func() {
+				fakeSQLClient.DeleteApplicationReturns(errors.New("error deleting application"))
+			})
+
+			It("returns an error", func() {
+				Expect(err).ToNot(BeNil())
+				Expect(err.Error()).To(Equal("error deleting application"))
+			})
+		})
+
+		When("it succeeds", func() {
+			It("deletes the application", func() {
+				Expect(err).To(BeNil())
+				Expect(fakeSQLClient.DeleteApplicationArgsForCall(0)).To(Equal("test-application"))
+			})
+		})
+	})
+})
@@ -60,7 +60,7 @@ func (c *cleanupArtifacts) Run() error {
 			},
 		}
 
-		client, err := c.kc.NewClient(config)
+		client, err := kubernetes.NewProviderClient(c.kc, provider, config)
 		if err != nil {
 			return err
 		}
@@ -83,6 +83,7 @@ func (c *cleanupArtifacts) Run() error {
 			Kind:         u.GetKind(),
 			SpinnakerApp: c.app,
 			Cluster:      cluster(u.GetKind(), u.GetName()),
+			DryRun:       provider.DryRun(),
 		}
 
 		err = c.sc.CreateKubernetesResource(kr)
@@ -9,6 +9,7 @@ import (
 	"github.com/billiford/go-clouddriver/pkg/kubernetes"
 	"github.com/billiford/go-clouddriver/pkg/sql"
 	"github.com/google/uuid"
+	"k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/client-go/rest"
@@ -21,6 +22,8 @@ func (ah *actionHandler) NewDeleteManifestAction(ac ActionConfig) Action {
 		kc: ac.KubeController,
 		id: ac.ID,
 		dm: ac.Operation.DeleteManifest,
+
+		trafficGuards: ah.config.TrafficGuards,
 	}
 }
 
@@ -30,6 +33,8 @@ type deleteManfest struct {
 	kc kubernetes.Controller
 	id string
 	dm *DeleteManifestRequest
+
+	trafficGuards kubernetes.TrafficGuardConfig
 }
 
 func (d *deleteManfest) Run() error {
@@ -56,7 +61,7 @@ func (d *deleteManfest) Run() error {
 		},
 	}
 
-	client, err := d.kc.NewClient(config)
+	client, err := kubernetes.NewProviderClient(d.kc, provider, config)
 	if err != nil {
 		return err
 	}
@@ -92,6 +97,22 @@ func (d *deleteManfest) Run() error {
 			return err
 		}
 
+		u, err := client.Get(kind, name, d.dm.Location)
+		if err != nil && !errors.IsNotFound(err) {
+			return err
+		}
+
+		if err == nil {
+			if kubernetes.Pinned(u) && !d.dm.OverridePinned {
+				return fmt.Errorf("%s is pinned, set overridePinned to delete it", d.dm.ManifestName)
+			}
+
+			err = checkTrafficGuards(client, d.trafficGuards, d.dm.Account, kind, u, "deleting")
+			if err != nil {
+				return err
+			}
+		}
+
 		err = client.DeleteResourceByKindAndNameAndNamespace(kind, name, d.dm.Location, do)
 		if err != nil {
 			return err
@@ -109,6 +130,7 @@ func (d *deleteManfest) Run() error {
 			Kind:         kind,
 			SpinnakerApp: d.dm.App,
 			Cluster:      cluster(kind, name),
+			DryRun:       provider.DryRun(),
 		}
 
 		err = d.sc.CreateKubernetesResource(kr)
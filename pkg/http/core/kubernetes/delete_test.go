@@ -6,9 +6,10 @@ import (
 	. "github.com/onsi/ginkgo"
 	. "github.com/onsi/gomega"
 	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 	"k8s.io/apimachinery/pkg/runtime/schema"
 
-	// . "github.com/billiford/go-clouddriver/pkg/http/core/kubernetes"
+	. "github.com/billiford/go-clouddriver/pkg/http/core/kubernetes"
 	"github.com/billiford/go-clouddriver/pkg/kubernetes"
 )
 
@@ -121,6 +122,104 @@ var _ = Describe("Delete", func() {
 		})
 	})
 
+	When("the resource is pinned", func() {
+		BeforeEach(func() {
+			fakeKubeClient.GetReturns(&unstructured.Unstructured{Object: map[string]interface{}{
+				"kind": "Deployment",
+				"metadata": map[string]interface{}{
+					"name": "test-deployment",
+					"annotations": map[string]interface{}{
+						kubernetes.AnnotationSpinnakerPinned: "true",
+					},
+				},
+			}}, nil)
+		})
+
+		It("returns an error", func() {
+			Expect(err).ToNot(BeNil())
+			Expect(err.Error()).To(Equal("deployment test-deployment is pinned, set overridePinned to delete it"))
+			Expect(fakeKubeClient.DeleteResourceByKindAndNameAndNamespaceCallCount()).To(BeZero())
+		})
+
+		When("the operation overrides it", func() {
+			BeforeEach(func() {
+				actionConfig.Operation.DeleteManifest.OverridePinned = true
+			})
+
+			It("deletes it", func() {
+				Expect(err).To(BeNil())
+				Expect(fakeKubeClient.DeleteResourceByKindAndNameAndNamespaceCallCount()).To(Equal(1))
+			})
+		})
+	})
+
+	When("the cluster has traffic guards", func() {
+		var serverGroups []unstructured.Unstructured
+
+		BeforeEach(func() {
+			actionHandler = NewActionHandlerWithConfig(ActionHandlerConfig{
+				TrafficGuards: kubernetes.TrafficGuardConfig{
+					Guards: []kubernetes.TrafficGuard{
+						{Account: "test-account", Cluster: "deployment test-deployment"},
+					},
+				},
+			})
+			actionConfig.Operation.DeleteManifest.Account = "test-account"
+			target := newDeployment("test-deployment", 1)
+			serverGroups = []unstructured.Unstructured{target}
+			fakeKubeClient.GetReturns(&target, nil)
+			fakeKubeClient.ListResourceReturnsOnCall(0, &unstructured.UnstructuredList{
+				Items: []unstructured.Unstructured{
+					{Object: map[string]interface{}{
+						"kind":     "Service",
+						"metadata": map[string]interface{}{"name": "test-service"},
+						"spec": map[string]interface{}{
+							"selector": map[string]interface{}{"app": "test-app"},
+						},
+					}},
+				},
+			}, nil)
+		})
+
+		When("it is the last healthy server group serving a service", func() {
+			BeforeEach(func() {
+				fakeKubeClient.ListResourceReturnsOnCall(1, &unstructured.UnstructuredList{Items: serverGroups}, nil)
+			})
+
+			It("returns an error", func() {
+				Expect(err).ToNot(BeNil())
+				Expect(err.Error()).To(Equal("cluster deployment test-deployment has traffic guards enabled: deleting " +
+					"deployment test-deployment would leave service test-service with no healthy server group taking traffic"))
+				Expect(fakeKubeClient.DeleteResourceByKindAndNameAndNamespaceCallCount()).To(BeZero())
+				_, lo := fakeKubeClient.ListResourceArgsForCall(0)
+				Expect(lo.FieldSelector).To(Equal("metadata.namespace=test-namespace"))
+			})
+		})
+
+		When("another healthy server group serves the service", func() {
+			BeforeEach(func() {
+				serverGroups = append(serverGroups, newDeployment("other-deployment", 2))
+				fakeKubeClient.ListResourceReturnsOnCall(1, &unstructured.UnstructuredList{Items: serverGroups}, nil)
+			})
+
+			It("deletes it", func() {
+				Expect(err).To(BeNil())
+				Expect(fakeKubeClient.DeleteResourceByKindAndNameAndNamespaceCallCount()).To(Equal(1))
+			})
+		})
+	})
+
+	When("getting the resource returns an error", func() {
+		BeforeEach(func() {
+			fakeKubeClient.GetReturns(nil, errors.New("error getting resource"))
+		})
+
+		It("returns an error", func() {
+			Expect(err).ToNot(BeNil())
+			Expect(err.Error()).To(Equal("error getting resource"))
+		})
+	})
+
 	When("it succeeds", func() {
 		It("succeeds", func() {
 			Expect(err).To(BeNil())
@@ -135,3 +234,23 @@ var _ = Describe("Delete", func() {
 		})
 	})
 })
+
+func newDeployment(name string, readyReplicas int64) unstructured.Unstructured {
+	return unstructured.Unstructured{Object: map[string]interface{}{
+		"kind": "Deployment",
+		"metadata": map[string]interface{}{
+			"name":      name,
+			"namespace": "test-namespace",
+		},
+		"spec": map[string]interface{}{
+			"template": map[string]interface{}{
+				"metadata": map[string]interface{}{
+					"labels": map[string]interface{}{"app": "test-app"},
+				},
+			},
+		},
+		"status": map[string]interface{}{
+			"readyReplicas": readyReplicas,
+		},
+	}}
+}
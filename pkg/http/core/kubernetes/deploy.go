@@ -1,36 +1,74 @@
 package kubernetes
 
 import (
+	"context"
 	"encoding/base64"
 	"encoding/json"
 	"fmt"
+	"log"
 	"strings"
 	"unicode"
 
+	clouddriver "github.com/billiford/go-clouddriver/pkg"
 	"github.com/billiford/go-clouddriver/pkg/arcade"
+	"github.com/billiford/go-clouddriver/pkg/artifact"
+	"github.com/billiford/go-clouddriver/pkg/hook"
 	"github.com/billiford/go-clouddriver/pkg/kubernetes"
+	"github.com/billiford/go-clouddriver/pkg/kubernetes/decrypt"
+	"github.com/billiford/go-clouddriver/pkg/kubernetes/lint"
+	"github.com/billiford/go-clouddriver/pkg/kubernetes/schedule"
+	"github.com/billiford/go-clouddriver/pkg/kubernetes/verify"
 	"github.com/billiford/go-clouddriver/pkg/sql"
 	"github.com/google/uuid"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 	"k8s.io/apimachinery/pkg/util/rand"
 	"k8s.io/client-go/rest"
 )
 
 func (ah *actionHandler) NewDeployManifestAction(ac ActionConfig) Action {
 	return &deployManfest{
-		ac: ac.ArcadeClient,
-		sc: ac.SQLClient,
-		kc: ac.KubeController,
-		id: ac.ID,
-		dm: ac.Operation.DeployManifest,
+		ac:     ac.ArcadeClient,
+		acc:    ac.ArtifactCredentialsController,
+		sc:     ac.SQLClient,
+		kc:     ac.KubeController,
+		id:     ac.ID,
+		ctx:    ac.ctx(),
+		dm:     ac.Operation.DeployManifest,
+		linter: ah.config.Linter,
+
+		migrateAPIVersions: ah.config.MigrateRemovedAPIVersions,
+		defaultMetadata:    ah.config.DefaultMetadata,
+		templating:         ah.config.Templating,
+		decrypters:         ah.config.Decrypters,
+		validateScheduling: ah.config.ValidateScheduling,
+		capacityCheck:      ah.config.CapacityCheck,
+		mutator:            ah.config.Mutator,
+		urlFetcher:         ah.config.URLFetcher,
+		verifier:           ah.config.Verifier,
 	}
 }
 
 type deployManfest struct {
-	ac arcade.Client
-	sc sql.Client
-	kc kubernetes.Controller
-	id string
-	dm *DeployManifestRequest
+	ac     arcade.Client
+	acc    artifact.CredentialsController
+	sc     sql.Client
+	kc     kubernetes.Controller
+	id     string
+	ctx    context.Context
+	dm     *DeployManifestRequest
+	linter *lint.Linter
+
+	migrateAPIVersions bool
+	defaultMetadata    kubernetes.MetadataConfig
+	templating         kubernetes.TemplateConfig
+	decrypters         []decrypt.Decrypter
+	validateScheduling bool
+	capacityCheck      schedule.CapacityCheck
+	mutator            hook.Mutator
+	urlFetcher         *artifact.URLFetcher
+	verifier           verify.Verifier
 }
 
 func (d *deployManfest) Run() error {
@@ -57,15 +95,22 @@ func (d *deployManfest) Run() error {
 		},
 	}
 
-	client, err := d.kc.NewClient(config)
+	client, err := kubernetes.NewProviderClient(d.kc, provider, config)
+	if err != nil {
+		return err
+	}
+
+	// Fetch the manifests of the manifest artifact before the cluster is changed.
+	fromArtifact, err := d.manifestsFromArtifact()
 	if err != nil {
 		return err
 	}
 
 	manifests := []map[string]interface{}{}
+	requested := append(append([]map[string]interface{}{}, d.dm.Manifests...), fromArtifact...)
 
 	// Merge all list element items into the manifest list.
-	for _, manifest := range d.dm.Manifests {
+	for _, manifest := range requested {
 		u, err := d.kc.ToUnstructured(manifest)
 		if err != nil {
 			return err
@@ -90,40 +135,104 @@ func (d *deployManfest) Run() error {
 		}
 	}
 
-	for _, manifest := range manifests {
-		u, err := d.kc.ToUnstructured(manifest)
-		if err != nil {
-			return err
-		}
+	// Decrypt manifests first, other steps cannot read encrypted values.
+	err = d.decrypt(manifests)
+	if err != nil {
+		return err
+	}
+
+	// Add the ConfigMaps and Secrets created from artifacts.
+	fromArtifacts, err := d.manifestsFromArtifacts(client)
+	if err != nil {
+		return err
+	}
 
-		// If the kind is a job, its name is not set, and generateName is set,
-		// generate a name for the job as `apply` will throw the error
-		// `resource name may not be empty`.
-		if strings.EqualFold(u.GetKind(), "job") {
-			name := u.GetName()
-			generateName := u.GetGenerateName()
+	manifests = append(manifests, fromArtifacts...)
 
-			if name == "" && generateName != "" {
-				u.SetName(generateName + rand.String(randNameNumber))
-			}
+	// Substitute placeholders before checking the manifests, they may be anywhere in them.
+	d.substituteTemplateValues(manifests)
+
+	// Mutate the rendered manifests before checking them, so mutations are checked too.
+	manifests, err = d.mutate(manifests)
+	if err != nil {
+		return err
+	}
+
+	// Check all manifests can be deployed to the cluster's version before deploying any of them.
+	migrations, err := d.migrateRemovedAPIVersions(client, manifests)
+	if err != nil {
+		return err
+	}
+
+	// Add the account's default labels and annotations, see README.
+	err = d.addDefaultMetadata(manifests)
+	if err != nil {
+		return err
+	}
+
+	// Lint all manifests before deploying any of them.
+	warnings, err := d.lint(manifests)
+	if err != nil {
+		return err
+	}
+
+	for i, migration := range migrations {
+		if migration != "" {
+			warnings[i] = append([]string{migration}, warnings[i]...)
 		}
+	}
 
-		name := u.GetName()
+	scheduling, err := d.checkScheduling(client, manifests)
+	if err != nil {
+		return err
+	}
 
-		err = d.kc.AddSpinnakerAnnotations(u, d.dm.Moniker.App)
+	for i := range warnings {
+		warnings[i] = append(warnings[i], scheduling[i]...)
+	}
+
+	// Verify the images of all manifests before deploying any of them.
+	if d.verifier != nil {
+		err = d.verifier.Verify(d.ctx, d.dm.Account, manifests)
 		if err != nil {
 			return err
 		}
+	}
+
+	// Resources of deploys that roll back on error are only stored once all
+	// manifests are applied, as rolled back resources may no longer exist.
+	resources := []kubernetes.Resource{}
+	applied := []appliedManifest{}
+	failures := []string{}
 
-		err = d.kc.AddSpinnakerLabels(u, d.dm.Moniker.App)
+	for i, manifest := range manifests {
+		am, err := d.apply(client, manifest)
 		if err != nil {
-			return err
+			RecordTaskEvent(d.sc, d.id, clouddriver.TaskEventManifestFailed, "failed to apply manifest %d/%d: %s",
+				i+1, len(manifests), err.Error())
+
+			if !d.dm.ContinueOnError {
+				d.recordSkipped(manifests, i+1)
+
+				if !d.dm.RollbackOnError {
+					return err
+				}
+			}
+
+			failures = append(failures, fmt.Sprintf("manifest %d/%d: %s", i+1, len(manifests), err.Error()))
+
+			if !d.dm.ContinueOnError {
+				break
+			}
+
+			continue
 		}
 
-		meta, err := client.ApplyWithNamespaceOverride(u, d.dm.NamespaceOverride)
+		meta := am.meta
+
+		manifest, err := dryRunManifest(provider, am.u)
 		if err != nil {
-			return fmt.Errorf("error applying manifest (kind: %s, apiVersion: %s, name: %s): %s",
-				u.GetKind(), u.GroupVersionKind().Version, u.GetName(), err.Error())
+			return err
 		}
 
 		kr := kubernetes.Resource{
@@ -137,9 +246,32 @@ func (d *deployManfest) Run() error {
 			Version:      meta.Version,
 			Kind:         meta.Kind,
 			SpinnakerApp: d.dm.Moniker.App,
-			Cluster:      cluster(meta.Kind, name),
+			Cluster:      cluster(meta.Kind, am.name),
+			Warnings:     strings.Join(warnings[i], "\n"),
+			DryRun:       provider.DryRun(),
+			Manifest:     manifest,
+		}
+
+		if d.dm.RollbackOnError {
+			resources = append(resources, kr)
+		} else {
+			err = d.sc.CreateKubernetesResource(kr)
+			if err != nil {
+				return err
+			}
 		}
 
+		applied = append(applied, am)
+
+		RecordTaskEvent(d.sc, d.id, clouddriver.TaskEventManifestApplied, "applied manifest %d/%d: %s %s in %s",
+			i+1, len(manifests), meta.Kind, meta.Name, meta.Namespace)
+	}
+
+	if len(failures) > 0 {
+		return d.deployError(client, applied, failures, len(manifests))
+	}
+
+	for _, kr := range resources {
 		err = d.sc.CreateKubernetesResource(kr)
 		if err != nil {
 			return err
@@ -149,6 +281,380 @@ func (d *deployManfest) Run() error {
 	return nil
 }
 
+// appliedManifest is a manifest a deploy applied and, if the deploy rolls
+// back on error, the resource it replaced, nil if it created the resource.
+type appliedManifest struct {
+	u        *unstructured.Unstructured
+	name     string
+	meta     kubernetes.Metadata
+	previous *unstructured.Unstructured
+}
+
+// apply applies a manifest with the Spinnaker annotations and labels.
+func (d *deployManfest) apply(client kubernetes.Client, manifest map[string]interface{}) (appliedManifest, error) {
+	am := appliedManifest{}
+
+	u, err := d.kc.ToUnstructured(manifest)
+	if err != nil {
+		return am, err
+	}
+
+	// If the kind is a job, its name is not set, and generateName is set,
+	// generate a name for the job as `apply` will throw the error
+	// `resource name may not be empty`.
+	if strings.EqualFold(u.GetKind(), "job") {
+		name := u.GetName()
+		generateName := u.GetGenerateName()
+
+		if name == "" && generateName != "" {
+			u.SetName(generateName + rand.String(randNameNumber))
+		}
+	}
+
+	am.u = u
+	am.name = u.GetName()
+
+	err = d.kc.AddSpinnakerAnnotations(u, d.dm.Moniker.App)
+	if err != nil {
+		return am, err
+	}
+
+	err = d.kc.AddSpinnakerLabels(u, d.dm.Moniker.App)
+	if err != nil {
+		return am, err
+	}
+
+	if d.dm.RollbackOnError {
+		am.previous, err = d.previous(client, u)
+		if err != nil {
+			return am, err
+		}
+	}
+
+	am.meta, err = client.ApplyWithNamespaceOverride(u, d.dm.NamespaceOverride)
+	if err != nil {
+		return am, fmt.Errorf("error applying manifest (kind: %s, apiVersion: %s, name: %s): %s",
+			u.GetKind(), u.GroupVersionKind().Version, u.GetName(), err.Error())
+	}
+
+	return am, nil
+}
+
+// dryRunManifest returns the manifest to record for a resource deployed to an
+// account in dryRun write mode, as the task cannot get what was never created.
+// It is empty for accounts whose changes are applied. The data of secrets,
+// which may have been decrypted, is redacted.
+func dryRunManifest(provider kubernetes.Provider, u *unstructured.Unstructured) (string, error) {
+	if !provider.DryRun() || u == nil {
+		return "", nil
+	}
+
+	b, err := json.Marshal(kubernetes.RedactSecret(u).Object)
+	if err != nil {
+		return "", err
+	}
+
+	return string(b), nil
+}
+
+// decrypt decrypts each manifest with the first decrypter that supports it.
+func (d *deployManfest) decrypt(manifests []map[string]interface{}) error {
+	for i, manifest := range manifests {
+		for _, decrypter := range d.decrypters {
+			decrypted, ok, err := decrypter.Decrypt(manifest)
+			if err != nil {
+				return err
+			}
+
+			if ok {
+				manifests[i] = decrypted
+				break
+			}
+		}
+	}
+
+	return nil
+}
+
+// substituteTemplateValues replaces ${name} placeholders in the manifests if the
+// account is configured to. The account, namespace and application placeholders
+// take precedence over values from the stage context and the account's config.
+func (d *deployManfest) substituteTemplateValues(manifests []map[string]interface{}) {
+	if !d.templating.EnabledFor(d.dm.Account) {
+		return
+	}
+
+	for _, manifest := range manifests {
+		values := d.templating.ValuesFor(d.dm.Account)
+		for k, v := range d.dm.TemplateValues {
+			values[k] = v
+		}
+
+		namespace := d.dm.NamespaceOverride
+		if namespace == "" {
+			namespace, _, _ = unstructured.NestedString(manifest, "metadata", "namespace")
+		}
+
+		if namespace == "" {
+			namespace = "default"
+		}
+
+		values[kubernetes.TemplateValueAccount] = d.dm.Account
+		values[kubernetes.TemplateValueNamespace] = namespace
+		values[kubernetes.TemplateValueApplication] = d.dm.Moniker.App
+
+		kubernetes.SubstituteTemplateValues(manifest, values)
+	}
+}
+
+// migrateRemovedAPIVersions returns an error if a manifest uses an API version
+// the cluster no longer serves. If migrating API versions is enabled, manifests
+// with a lossless conversion are converted in place instead, and a note of
+// the conversion is returned for each of them.
+func (d *deployManfest) migrateRemovedAPIVersions(client kubernetes.Client,
+	manifests []map[string]interface{}) ([]string, error) {
+	migrations := make([]string, len(manifests))
+
+	sv, err := client.ServerVersion()
+	if err != nil {
+		return nil, fmt.Errorf("error getting kubernetes version: %w", err)
+	}
+
+	if sv == nil {
+		return migrations, nil
+	}
+
+	for i, manifest := range manifests {
+		u, err := d.kc.ToUnstructured(manifest)
+		if err != nil {
+			return nil, err
+		}
+
+		removed, ok := kubernetes.RemovedAPIVersionFor(u, sv)
+		if !ok {
+			continue
+		}
+
+		if !d.migrateAPIVersions {
+			return nil, fmt.Errorf("error deploying manifest (kind: %s, name: %s) to kubernetes %s: %w",
+				u.GetKind(), u.GetName(), sv.GitVersion, removed)
+		}
+
+		if err := removed.Convert(u); err != nil {
+			return nil, fmt.Errorf("error deploying manifest (kind: %s, name: %s) to kubernetes %s: %w",
+				u.GetKind(), u.GetName(), sv.GitVersion, err)
+		}
+
+		migrations[i] = fmt.Sprintf("info: converted %s %s from removed apiVersion %s to %s",
+			lowercaseFirst(u.GetKind()), u.GetName(), removed.GroupVersionKind.GroupVersion().String(), removed.Replacement)
+		manifests[i] = u.Object
+	}
+
+	return migrations, nil
+}
+
+// mutate posts the manifests to the mutation webhooks of the account, if
+// any, returning the manifests they respond with.
+func (d *deployManfest) mutate(manifests []map[string]interface{}) ([]map[string]interface{}, error) {
+	if d.mutator == nil {
+		return manifests, nil
+	}
+
+	return d.mutator.Mutate(d.ctx, hook.MutationRequest{
+		Account:     d.dm.Account,
+		Application: d.dm.Moniker.App,
+		TaskID:      d.id,
+		Manifests:   manifests,
+	})
+}
+
+// addDefaultMetadata adds the default labels and annotations of the account
+// to all manifests before any of them are deployed, so a conflict fails the
+// deploy as a whole.
+func (d *deployManfest) addDefaultMetadata(manifests []map[string]interface{}) error {
+	dm := d.defaultMetadata.ForAccount(d.dm.Account)
+	if len(dm.Labels) == 0 && len(dm.Annotations) == 0 {
+		return nil
+	}
+
+	for i, manifest := range manifests {
+		u, err := d.kc.ToUnstructured(manifest)
+		if err != nil {
+			return err
+		}
+
+		err = dm.Apply(u)
+		if err != nil {
+			return fmt.Errorf("error adding default metadata of account %s: %w", d.dm.Account, err)
+		}
+
+		manifests[i] = u.Object
+	}
+
+	return nil
+}
+
+// checkScheduling warns about manifests whose pods cannot be scheduled on any
+// node of the cluster, or that exceed the schedulable capacity of the cluster,
+// instead of them waiting to be scheduled until the deploy times out.
+func (d *deployManfest) checkScheduling(client kubernetes.Client,
+	manifests []map[string]interface{}) ([][]string, error) {
+	warnings := make([][]string, len(manifests))
+
+	if !d.validateScheduling && d.capacityCheck == schedule.CapacityCheckNone {
+		return warnings, nil
+	}
+
+	list, err := client.ListResource("nodes", metav1.ListOptions{})
+	if err != nil {
+		// Listing nodes requires a cluster role, so do not fail deploys to accounts without one.
+		log.Printf("error listing nodes of account %s, not validating scheduling: %v", d.dm.Account, err)
+		return warnings, nil
+	}
+
+	nodes, err := schedule.Nodes(list)
+	if err != nil {
+		return nil, err
+	}
+
+	capacity, err := d.capacity(client, nodes)
+	if err != nil {
+		return nil, err
+	}
+
+	failures := []string{}
+
+	for i, manifest := range manifests {
+		u, err := d.kc.ToUnstructured(manifest)
+		if err != nil {
+			return nil, err
+		}
+
+		spec, ok, err := schedule.PodSpec(u)
+		if err != nil {
+			return nil, err
+		}
+
+		if !ok {
+			continue
+		}
+
+		resource := lowercaseFirst(u.GetKind()) + " " + u.GetName()
+
+		if d.validateScheduling {
+			if reason, unschedulable := schedule.Unschedulable(spec, nodes); unschedulable {
+				warnings[i] = append(warnings[i], fmt.Sprintf("warning: %s will be unschedulable: %s", resource, reason))
+			}
+		}
+
+		if capacity == nil {
+			continue
+		}
+
+		if reason, insufficient := capacity.Insufficient(spec, d.additionalReplicas(client, u), nodes); insufficient {
+			if d.capacityCheck == schedule.CapacityCheckFail {
+				failures = append(failures, fmt.Sprintf("%s: %s", resource, reason))
+			}
+
+			warnings[i] = append(warnings[i], fmt.Sprintf("warning: %s exceeds the schedulable capacity of the cluster: %s",
+				resource, reason))
+		}
+	}
+
+	if len(failures) > 0 {
+		return nil, fmt.Errorf("manifests exceed the schedulable capacity of the cluster: %s", strings.Join(failures, "; "))
+	}
+
+	return warnings, nil
+}
+
+// capacity returns the schedulable capacity of the nodes, or nil if capacity is not checked.
+func (d *deployManfest) capacity(client kubernetes.Client, nodes []corev1.Node) (schedule.Capacity, error) {
+	if d.capacityCheck == schedule.CapacityCheckNone {
+		return nil, nil
+	}
+
+	list, err := client.ListResource("pods", metav1.ListOptions{
+		FieldSelector: "status.phase!=Succeeded,status.phase!=Failed",
+	})
+	if err != nil {
+		log.Printf("error listing pods of account %s, not checking capacity: %v", d.dm.Account, err)
+		return nil, nil
+	}
+
+	pods, err := schedule.Pods(list)
+	if err != nil {
+		return nil, err
+	}
+
+	return schedule.NewCapacity(nodes, pods), nil
+}
+
+// additionalReplicas returns how many pods deploying a manifest adds to the
+// cluster, which for a workload that already exists are only those it scales up by.
+func (d *deployManfest) additionalReplicas(client kubernetes.Client, u *unstructured.Unstructured) int64 {
+	replicas, ok := schedule.Replicas(u)
+	if !ok {
+		return 0
+	}
+
+	namespace := d.dm.NamespaceOverride
+	if namespace == "" {
+		namespace = u.GetNamespace()
+	}
+
+	if namespace == "" {
+		namespace = "default"
+	}
+
+	// Pods and jobs are replaced rather than scaled, so all their pods are added.
+	if !strings.EqualFold(u.GetKind(), "pod") && !strings.EqualFold(u.GetKind(), "job") &&
+		!strings.EqualFold(u.GetKind(), "cronJob") && u.GetName() != "" {
+		current, err := client.Get(strings.ToLower(u.GetKind()), u.GetName(), namespace)
+		if err == nil {
+			if existing, ok := schedule.Replicas(current); ok {
+				replicas -= existing
+			}
+		}
+	}
+
+	return replicas
+}
+
+// lint returns the lint warnings of each manifest, or an error if any
+// manifest has findings the linter fails on.
+func (d *deployManfest) lint(manifests []map[string]interface{}) ([][]string, error) {
+	warnings := make([][]string, len(manifests))
+
+	if d.linter == nil {
+		return warnings, nil
+	}
+
+	failures := []string{}
+
+	for i, manifest := range manifests {
+		u, err := d.kc.ToUnstructured(manifest)
+		if err != nil {
+			return nil, err
+		}
+
+		findings := d.linter.Lint(u)
+		for _, f := range findings {
+			warnings[i] = append(warnings[i], f.String())
+		}
+
+		for _, f := range d.linter.Failures(findings) {
+			failures = append(failures, f.String())
+		}
+	}
+
+	if len(failures) > 0 {
+		return nil, fmt.Errorf("manifests failed linting: %s", strings.Join(failures, "; "))
+	}
+
+	return warnings, nil
+}
+
 // Generate the cluster that a kind is a part of.
 // A Kubernetes cluster is of kind deployment, statefulSet, replicaSet, ingress, service, and daemonSet
 // so only generate a cluster for these kinds.
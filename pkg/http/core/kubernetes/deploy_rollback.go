@@ -0,0 +1,122 @@
+package kubernetes
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	clouddriver "github.com/billiford/go-clouddriver/pkg"
+	"github.com/billiford/go-clouddriver/pkg/kubernetes"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+// previous returns the resource a manifest replaces, or nil if the manifest
+// creates it.
+func (d *deployManfest) previous(client kubernetes.Client, u *unstructured.Unstructured) (*unstructured.Unstructured, error) {
+	namespace := d.dm.NamespaceOverride
+	if namespace == "" {
+		namespace = u.GetNamespace()
+	}
+
+	if namespace == "" {
+		namespace = "default"
+	}
+
+	current, err := client.Get(strings.ToLower(u.GetKind()), u.GetName(), namespace)
+	if k8serrors.IsNotFound(err) {
+		return nil, nil
+	}
+
+	if err != nil {
+		return nil, fmt.Errorf("error getting %s %s to roll back to: %w", u.GetKind(), u.GetName(), err)
+	}
+
+	return current, nil
+}
+
+// recordSkipped records the manifests from index i on as skipped.
+func (d *deployManfest) recordSkipped(manifests []map[string]interface{}, i int) {
+	for ; i < len(manifests); i++ {
+		u := unstructured.Unstructured{Object: manifests[i]}
+		RecordTaskEvent(d.sc, d.id, clouddriver.TaskEventManifestSkipped, "skipped manifest %d/%d: %s %s",
+			i+1, len(manifests), u.GetKind(), u.GetName())
+	}
+}
+
+// deployError returns the error of a deploy some manifests of failed to
+// apply, after rolling back the applied manifests if the deploy rolls back
+// on error.
+func (d *deployManfest) deployError(client kubernetes.Client, applied []appliedManifest,
+	failures []string, total int) error {
+	message := fmt.Sprintf("error deploying %d of %d manifests: %s", len(failures), total, strings.Join(failures, "; "))
+
+	if !d.dm.RollbackOnError {
+		return errors.New(message)
+	}
+
+	rolledBack, rollbackErrors := d.rollback(client, applied)
+	message += fmt.Sprintf("; rolled back %d of %d applied manifests", rolledBack, len(applied))
+
+	if len(rollbackErrors) > 0 {
+		message += ": " + strings.Join(rollbackErrors, "; ")
+	}
+
+	return errors.New(message)
+}
+
+// rollback deletes the resources a deploy created and restores the ones it
+// replaced, newest first, returning how many were rolled back and the errors
+// of the others.
+func (d *deployManfest) rollback(client kubernetes.Client, applied []appliedManifest) (int, []string) {
+	rolledBack := 0
+	errs := []string{}
+
+	for i := len(applied) - 1; i >= 0; i-- {
+		am := applied[i]
+		meta := am.meta
+
+		if am.previous == nil {
+			propagation := metav1.DeletePropagationBackground
+
+			err := client.DeleteResourceByKindAndNameAndNamespace(meta.Kind, meta.Name, meta.Namespace,
+				metav1.DeleteOptions{PropagationPolicy: &propagation})
+			if err != nil && !k8serrors.IsNotFound(err) {
+				errs = append(errs, fmt.Sprintf("error deleting %s %s: %s", meta.Kind, meta.Name, err.Error()))
+				continue
+			}
+
+			RecordTaskEvent(d.sc, d.id, clouddriver.TaskEventManifestRolledBack, "rolled back %s %s in %s: deleted",
+				meta.Kind, meta.Name, meta.Namespace)
+			rolledBack++
+
+			continue
+		}
+
+		_, err := client.ApplyWithNamespaceOverride(restorable(am.previous), "")
+		if err != nil {
+			errs = append(errs, fmt.Sprintf("error restoring %s %s: %s", meta.Kind, meta.Name, err.Error()))
+			continue
+		}
+
+		RecordTaskEvent(d.sc, d.id, clouddriver.TaskEventManifestRolledBack, "rolled back %s %s in %s: restored",
+			meta.Kind, meta.Name, meta.Namespace)
+		rolledBack++
+	}
+
+	return rolledBack, errs
+}
+
+// restorable returns a resource without the fields set by the cluster, so it
+// can be applied again.
+func restorable(u *unstructured.Unstructured) *unstructured.Unstructured {
+	r := u.DeepCopy()
+	delete(r.Object, "status")
+
+	for _, field := range []string{"resourceVersion", "uid", "creationTimestamp", "generation", "managedFields", "selfLink"} {
+		unstructured.RemoveNestedField(r.Object, "metadata", field)
+	}
+
+	return r
+}
@@ -4,11 +4,21 @@ import (
 	"errors"
 	"fmt"
 
+	clouddriver "github.com/billiford/go-clouddriver/pkg"
+	"github.com/billiford/go-clouddriver/pkg/hook/hookfakes"
 	. "github.com/billiford/go-clouddriver/pkg/http/core/kubernetes"
 	"github.com/billiford/go-clouddriver/pkg/kubernetes"
+	"github.com/billiford/go-clouddriver/pkg/kubernetes/decrypt"
+	"github.com/billiford/go-clouddriver/pkg/kubernetes/decrypt/decryptfakes"
+	"github.com/billiford/go-clouddriver/pkg/kubernetes/lint"
+	"github.com/billiford/go-clouddriver/pkg/kubernetes/schedule"
+	"github.com/billiford/go-clouddriver/pkg/kubernetes/verify/verifyfakes"
 	. "github.com/onsi/ginkgo"
 	. "github.com/onsi/gomega"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/version"
 )
 
 var _ = Describe("Deploy", func() {
@@ -220,6 +230,120 @@ var _ = Describe("Deploy", func() {
 		})
 	})
 
+	Context("a manifest of several fails to apply", func() {
+		configMap := func(name string) map[string]interface{} {
+			return map[string]interface{}{
+				"apiVersion": "v1",
+				"kind":       "ConfigMap",
+				"metadata":   map[string]interface{}{"name": name, "namespace": "test-namespace"},
+			}
+		}
+
+		eventTypes := func() []string {
+			types := []string{}
+			for i := 0; i < fakeSQLClient.CreateTaskEventCallCount(); i++ {
+				types = append(types, fakeSQLClient.CreateTaskEventArgsForCall(i).Type)
+			}
+
+			return types
+		}
+
+		BeforeEach(func() {
+			actionConfig.Operation.DeployManifest.Manifests = []map[string]interface{}{
+				configMap("first"),
+				configMap("bad"),
+				configMap("last"),
+			}
+			fakeKubeController.ToUnstructuredCalls(kubernetes.NewController().ToUnstructured)
+			fakeKubeClient.ApplyWithNamespaceOverrideStub = func(u *unstructured.Unstructured, _ string) (kubernetes.Metadata, error) {
+				if u.GetName() == "bad" {
+					return kubernetes.Metadata{}, errors.New("admission webhook denied the request")
+				}
+
+				return kubernetes.Metadata{Kind: u.GetKind(), Name: u.GetName(), Namespace: u.GetNamespace()}, nil
+			}
+		})
+
+		It("skips the manifests after it", func() {
+			Expect(err).ToNot(BeNil())
+			Expect(err.Error()).To(Equal("error applying manifest (kind: ConfigMap, apiVersion: v1, name: bad): " +
+				"admission webhook denied the request"))
+			Expect(fakeKubeClient.ApplyWithNamespaceOverrideCallCount()).To(Equal(2))
+			Expect(fakeSQLClient.CreateKubernetesResourceCallCount()).To(Equal(1))
+			Expect(eventTypes()).To(Equal([]string{
+				clouddriver.TaskEventManifestApplied,
+				clouddriver.TaskEventManifestFailed,
+				clouddriver.TaskEventManifestSkipped,
+			}))
+			Expect(fakeSQLClient.CreateTaskEventArgsForCall(2).Message).To(Equal("skipped manifest 3/3: ConfigMap last"))
+		})
+
+		When("the deploy continues on error", func() {
+			BeforeEach(func() {
+				actionConfig.Operation.DeployManifest.ContinueOnError = true
+			})
+
+			It("applies the other manifests", func() {
+				Expect(err).ToNot(BeNil())
+				Expect(err.Error()).To(Equal("error deploying 1 of 3 manifests: manifest 2/3: " +
+					"error applying manifest (kind: ConfigMap, apiVersion: v1, name: bad): admission webhook denied the request"))
+				Expect(fakeKubeClient.ApplyWithNamespaceOverrideCallCount()).To(Equal(3))
+				Expect(fakeSQLClient.CreateKubernetesResourceCallCount()).To(Equal(2))
+			})
+		})
+
+		When("the deploy rolls back on error", func() {
+			BeforeEach(func() {
+				actionConfig.Operation.DeployManifest.RollbackOnError = true
+				fakeKubeClient.GetReturns(nil, k8serrors.NewNotFound(schema.GroupResource{Resource: "configmaps"}, "first"))
+			})
+
+			It("deletes the resources it created", func() {
+				Expect(err).ToNot(BeNil())
+				Expect(err.Error()).To(Equal("error deploying 1 of 3 manifests: manifest 2/3: " +
+					"error applying manifest (kind: ConfigMap, apiVersion: v1, name: bad): admission webhook denied the request; " +
+					"rolled back 1 of 1 applied manifests"))
+				Expect(fakeKubeClient.DeleteResourceByKindAndNameAndNamespaceCallCount()).To(Equal(1))
+				kind, name, namespace, _ := fakeKubeClient.DeleteResourceByKindAndNameAndNamespaceArgsForCall(0)
+				Expect(kind).To(Equal("ConfigMap"))
+				Expect(name).To(Equal("first"))
+				Expect(namespace).To(Equal("test-namespace"))
+				Expect(fakeSQLClient.CreateKubernetesResourceCallCount()).To(BeZero())
+				Expect(eventTypes()).To(ContainElement(clouddriver.TaskEventManifestRolledBack))
+			})
+
+			When("the resource existed", func() {
+				BeforeEach(func() {
+					previous := &unstructured.Unstructured{Object: configMap("first")}
+					previous.SetResourceVersion("100")
+					previous.Object["data"] = map[string]interface{}{"key": "old"}
+					fakeKubeClient.GetReturns(previous, nil)
+				})
+
+				It("restores it", func() {
+					Expect(err).ToNot(BeNil())
+					Expect(fakeKubeClient.DeleteResourceByKindAndNameAndNamespaceCallCount()).To(BeZero())
+					Expect(fakeKubeClient.ApplyWithNamespaceOverrideCallCount()).To(Equal(3))
+					u, _ := fakeKubeClient.ApplyWithNamespaceOverrideArgsForCall(2)
+					Expect(u.GetName()).To(Equal("first"))
+					Expect(u.GetResourceVersion()).To(BeEmpty())
+					Expect(u.Object["data"]).To(Equal(map[string]interface{}{"key": "old"}))
+				})
+			})
+
+			When("all manifests apply", func() {
+				BeforeEach(func() {
+					actionConfig.Operation.DeployManifest.Manifests = []map[string]interface{}{configMap("first")}
+				})
+
+				It("stores the resources", func() {
+					Expect(err).To(BeNil())
+					Expect(fakeSQLClient.CreateKubernetesResourceCallCount()).To(Equal(1))
+				})
+			})
+		})
+	})
+
 	When("creating the resource returns an error", func() {
 		BeforeEach(func() {
 			fakeSQLClient.CreateKubernetesResourceReturns(errors.New("error creating resource"))
@@ -231,6 +355,699 @@ var _ = Describe("Deploy", func() {
 		})
 	})
 
+	When("getting the kubernetes version returns an error", func() {
+		BeforeEach(func() {
+			fakeKubeClient.ServerVersionReturns(nil, errors.New("error getting version"))
+		})
+
+		It("returns an error", func() {
+			Expect(err).ToNot(BeNil())
+			Expect(err.Error()).To(Equal("error getting kubernetes version: error getting version"))
+		})
+	})
+
+	Context("the manifest uses a removed API version", func() {
+		BeforeEach(func() {
+			fakeKubeClient.ServerVersionReturns(&version.Info{GitVersion: "v1.25.0"}, nil)
+			fakeUnstructured := unstructured.Unstructured{
+				Object: map[string]interface{}{
+					"apiVersion": "batch/v1beta1",
+					"kind":       "CronJob",
+					"metadata": map[string]interface{}{
+						"name": "test-cronjob",
+					},
+				},
+			}
+			fakeKubeController.ToUnstructuredReturns(&fakeUnstructured, nil)
+		})
+
+		When("migrating API versions is disabled", func() {
+			It("returns an error and does not apply the manifest", func() {
+				Expect(err).ToNot(BeNil())
+				Expect(err.Error()).To(Equal("error deploying manifest (kind: CronJob, name: test-cronjob) to kubernetes v1.25.0: " +
+					"apiVersion batch/v1beta1 of kind CronJob was removed in Kubernetes v1.25, use batch/v1 instead"))
+				Expect(fakeKubeClient.ApplyWithNamespaceOverrideCallCount()).To(Equal(0))
+			})
+		})
+
+		When("migrating API versions is enabled", func() {
+			BeforeEach(func() {
+				actionHandler = NewActionHandlerWithConfig(ActionHandlerConfig{MigrateRemovedAPIVersions: true})
+			})
+
+			It("deploys the converted manifest", func() {
+				Expect(err).To(BeNil())
+				u, _ := fakeKubeClient.ApplyWithNamespaceOverrideArgsForCall(0)
+				Expect(u.GetAPIVersion()).To(Equal("batch/v1"))
+				kr := fakeSQLClient.CreateKubernetesResourceArgsForCall(0)
+				Expect(kr.Warnings).To(Equal("info: converted cronJob test-cronjob from removed apiVersion batch/v1beta1 to batch/v1"))
+			})
+
+			When("there is no lossless conversion", func() {
+				BeforeEach(func() {
+					fakeUnstructured := unstructured.Unstructured{
+						Object: map[string]interface{}{
+							"apiVersion": "policy/v1beta1",
+							"kind":       "PodDisruptionBudget",
+							"metadata": map[string]interface{}{
+								"name": "test-pdb",
+							},
+						},
+					}
+					fakeKubeController.ToUnstructuredReturns(&fakeUnstructured, nil)
+				})
+
+				It("returns an error", func() {
+					Expect(err).ToNot(BeNil())
+					Expect(err.Error()).To(Equal("error deploying manifest (kind: PodDisruptionBudget, name: test-pdb) to kubernetes v1.25.0: " +
+						"apiVersion policy/v1beta1 of kind PodDisruptionBudget was removed in Kubernetes v1.25, use policy/v1 instead"))
+				})
+			})
+		})
+	})
+
+	Context("decrypting the manifests", func() {
+		var fakeDecrypter *decryptfakes.FakeDecrypter
+
+		BeforeEach(func() {
+			fakeDecrypter = &decryptfakes.FakeDecrypter{}
+			fakeDecrypter.DecryptReturns(nil, false, nil)
+			fakeKubeController.ToUnstructuredCalls(kubernetes.NewController().ToUnstructured)
+			actionHandler = NewActionHandlerWithConfig(ActionHandlerConfig{
+				Decrypters: []decrypt.Decrypter{fakeDecrypter},
+			})
+		})
+
+		When("decrypting returns an error", func() {
+			BeforeEach(func() {
+				fakeDecrypter.DecryptReturns(nil, true, errors.New("error decrypting manifest using sops"))
+			})
+
+			It("returns an error and does not apply the manifest", func() {
+				Expect(err).ToNot(BeNil())
+				Expect(err.Error()).To(Equal("error decrypting manifest using sops"))
+				Expect(fakeKubeClient.ApplyWithNamespaceOverrideCallCount()).To(Equal(0))
+			})
+		})
+
+		When("the manifest is not encrypted", func() {
+			It("deploys the manifest", func() {
+				Expect(err).To(BeNil())
+				u, _ := fakeKubeClient.ApplyWithNamespaceOverrideArgsForCall(0)
+				Expect(u.GetKind()).To(Equal("Pod"))
+			})
+		})
+
+		When("the manifest is encrypted", func() {
+			BeforeEach(func() {
+				fakeDecrypter.DecryptReturns(map[string]interface{}{
+					"apiVersion": "v1",
+					"kind":       "Secret",
+					"metadata": map[string]interface{}{
+						"name": "test-secret",
+					},
+				}, true, nil)
+			})
+
+			It("deploys the decrypted manifest", func() {
+				Expect(err).To(BeNil())
+				u, _ := fakeKubeClient.ApplyWithNamespaceOverrideArgsForCall(0)
+				Expect(u.GetKind()).To(Equal("Secret"))
+				Expect(u.GetName()).To(Equal("test-secret"))
+			})
+		})
+	})
+
+	Context("the account substitutes template values", func() {
+		BeforeEach(func() {
+			enabled := true
+			actionHandler = NewActionHandlerWithConfig(ActionHandlerConfig{
+				Templating: kubernetes.TemplateConfig{
+					Accounts: map[string]kubernetes.Templating{
+						"prod-account": {
+							Enabled: &enabled,
+							Values: map[string]string{
+								"environment": "prod",
+							},
+						},
+					},
+				},
+			})
+			actionConfig.Operation.DeployManifest.Account = "prod-account"
+			actionConfig.Operation.DeployManifest.NamespaceOverride = "test-namespace"
+			actionConfig.Operation.DeployManifest.Moniker.App = "test-app"
+			actionConfig.Operation.DeployManifest.TemplateValues = map[string]string{
+				"version":   "1.0.0",
+				"namespace": "ignored",
+			}
+			fakeUnstructured := unstructured.Unstructured{
+				Object: map[string]interface{}{
+					"kind": "ConfigMap",
+					"metadata": map[string]interface{}{
+						"name": "test-config-map",
+					},
+					"data": map[string]interface{}{
+						"config": "${account} ${namespace} ${application} ${environment} ${version} ${unknown}",
+					},
+				},
+			}
+			fakeKubeController.ToUnstructuredReturns(&fakeUnstructured, nil)
+		})
+
+		It("substitutes the placeholders", func() {
+			Expect(err).To(BeNil())
+			u, _ := fakeKubeClient.ApplyWithNamespaceOverrideArgsForCall(0)
+			data, _, _ := unstructured.NestedString(u.Object, "data", "config")
+			Expect(data).To(Equal("prod-account test-namespace test-app prod 1.0.0 ${unknown}"))
+		})
+
+		When("the account does not substitute template values", func() {
+			BeforeEach(func() {
+				actionConfig.Operation.DeployManifest.Account = "test-account"
+			})
+
+			It("leaves the placeholders", func() {
+				Expect(err).To(BeNil())
+				u, _ := fakeKubeClient.ApplyWithNamespaceOverrideArgsForCall(0)
+				data, _, _ := unstructured.NestedString(u.Object, "data", "config")
+				Expect(data).To(HavePrefix("${account}"))
+			})
+		})
+	})
+
+	Context("the account has mutation webhooks", func() {
+		var fakeMutator *hookfakes.FakeMutator
+
+		BeforeEach(func() {
+			fakeMutator = &hookfakes.FakeMutator{}
+			fakeMutator.MutateReturns([]map[string]interface{}{
+				{
+					"kind": "ConfigMap",
+					"metadata": map[string]interface{}{
+						"name": "injected-config-map",
+					},
+				},
+			}, nil)
+			actionHandler = NewActionHandlerWithConfig(ActionHandlerConfig{Mutator: fakeMutator})
+			actionConfig.Operation.DeployManifest.Account = "prod-account"
+			actionConfig.Operation.DeployManifest.Moniker.App = "test-app"
+			fakeKubeController.ToUnstructuredStub = func(m map[string]interface{}) (*unstructured.Unstructured, error) {
+				return &unstructured.Unstructured{Object: m}, nil
+			}
+		})
+
+		When("mutating returns an error", func() {
+			BeforeEach(func() {
+				fakeMutator.MutateReturns(nil, errors.New("error calling mutation webhook inject-proxy: webhook returned status 500"))
+			})
+
+			It("returns an error without applying the manifests", func() {
+				Expect(err).ToNot(BeNil())
+				Expect(err.Error()).To(Equal("error calling mutation webhook inject-proxy: webhook returned status 500"))
+				Expect(fakeKubeClient.ApplyWithNamespaceOverrideCallCount()).To(BeZero())
+			})
+		})
+
+		It("applies the mutated manifests", func() {
+			Expect(err).To(BeNil())
+			_, mr := fakeMutator.MutateArgsForCall(0)
+			Expect(mr.Account).To(Equal("prod-account"))
+			Expect(mr.Application).To(Equal("test-app"))
+			Expect(mr.TaskID).To(Equal(actionConfig.ID))
+			Expect(mr.Manifests).To(HaveLen(1))
+			Expect(fakeKubeClient.ApplyWithNamespaceOverrideCallCount()).To(Equal(1))
+			u, _ := fakeKubeClient.ApplyWithNamespaceOverrideArgsForCall(0)
+			Expect(u.GetName()).To(Equal("injected-config-map"))
+		})
+	})
+
+	Context("the account verifies images", func() {
+		var fakeVerifier *verifyfakes.FakeVerifier
+
+		BeforeEach(func() {
+			fakeVerifier = &verifyfakes.FakeVerifier{}
+			actionHandler = NewActionHandlerWithConfig(ActionHandlerConfig{Verifier: fakeVerifier})
+			actionConfig.Operation.DeployManifest.Account = "prod-account"
+		})
+
+		When("an image fails verification", func() {
+			BeforeEach(func() {
+				fakeVerifier.VerifyReturns(errors.New("error verifying image nginx:1.19: exit status 1: Error: no matching signatures"))
+			})
+
+			It("returns the verification error without applying the manifests", func() {
+				Expect(err).ToNot(BeNil())
+				Expect(err.Error()).To(Equal("error verifying image nginx:1.19: exit status 1: Error: no matching signatures"))
+				Expect(fakeKubeClient.ApplyWithNamespaceOverrideCallCount()).To(BeZero())
+			})
+		})
+
+		It("verifies the manifests of the account", func() {
+			Expect(err).To(BeNil())
+			_, account, manifests := fakeVerifier.VerifyArgsForCall(0)
+			Expect(account).To(Equal("prod-account"))
+			Expect(manifests).To(HaveLen(1))
+			Expect(fakeKubeClient.ApplyWithNamespaceOverrideCallCount()).To(Equal(1))
+		})
+	})
+
+	Context("the account has default metadata", func() {
+		BeforeEach(func() {
+			actionConfig.Operation.DeployManifest.Account = "prod-account"
+			actionHandler = NewActionHandlerWithConfig(ActionHandlerConfig{
+				DefaultMetadata: kubernetes.MetadataConfig{
+					Accounts: map[string]kubernetes.DefaultMetadata{
+						"prod-account": {
+							Labels: map[string]string{
+								"example.com/environment": "prod",
+							},
+						},
+					},
+				},
+			})
+		})
+
+		When("the manifest does not set the labels", func() {
+			It("adds them", func() {
+				Expect(err).To(BeNil())
+				u, _ := fakeKubeClient.ApplyWithNamespaceOverrideArgsForCall(0)
+				Expect(u.GetLabels()).To(HaveKeyWithValue("example.com/environment", "prod"))
+			})
+		})
+
+		When("the manifest sets a conflicting label and conflicts fail", func() {
+			BeforeEach(func() {
+				actionHandler = NewActionHandlerWithConfig(ActionHandlerConfig{
+					DefaultMetadata: kubernetes.MetadataConfig{
+						DefaultMetadata: kubernetes.DefaultMetadata{
+							Labels: map[string]string{
+								"example.com/environment": "prod",
+							},
+							OnConflict: kubernetes.ConflictFail,
+						},
+					},
+				})
+				fakeUnstructured := unstructured.Unstructured{
+					Object: map[string]interface{}{
+						"kind": "Service",
+						"metadata": map[string]interface{}{
+							"name": "test-service",
+							"labels": map[string]interface{}{
+								"example.com/environment": "dev",
+							},
+						},
+					},
+				}
+				fakeKubeController.ToUnstructuredReturns(&fakeUnstructured, nil)
+			})
+
+			It("returns an error and does not apply the manifest", func() {
+				Expect(err).ToNot(BeNil())
+				Expect(err.Error()).To(Equal(`error adding default metadata of account prod-account: ` +
+					`label example.com/environment of service test-service is "dev", the account requires "prod"`))
+				Expect(fakeKubeClient.ApplyWithNamespaceOverrideCallCount()).To(Equal(0))
+			})
+		})
+	})
+
+	Context("linting the manifests", func() {
+		BeforeEach(func() {
+			fakeUnstructured := unstructured.Unstructured{
+				Object: map[string]interface{}{
+					"apiVersion": "apps/v1",
+					"kind":       "Deployment",
+					"metadata": map[string]interface{}{
+						"name": "test-deployment",
+					},
+					"spec": map[string]interface{}{
+						"selector": map[string]interface{}{},
+						"template": map[string]interface{}{
+							"spec": map[string]interface{}{
+								"containers": []interface{}{
+									map[string]interface{}{
+										"name":  "test-container",
+										"image": "nginx:latest",
+									},
+								},
+							},
+						},
+					},
+				},
+			}
+			fakeKubeController.ToUnstructuredReturns(&fakeUnstructured, nil)
+		})
+
+		When("the linter only warns", func() {
+			BeforeEach(func() {
+				actionHandler = NewActionHandlerWithConfig(ActionHandlerConfig{Linter: &lint.Linter{}})
+			})
+
+			It("saves the warnings on the resource", func() {
+				Expect(err).To(BeNil())
+				kr := fakeSQLClient.CreateKubernetesResourceArgsForCall(0)
+				Expect(kr.Warnings).To(Equal("warning: deployment test-deployment container test-container uses image nginx:latest, pin a tag or digest (latest-tag)\n" +
+					"warning: deployment test-deployment container test-container has no resource requests (missing-resource-requests)"))
+			})
+		})
+
+		When("the linter fails on warnings", func() {
+			BeforeEach(func() {
+				actionHandler = NewActionHandlerWithConfig(ActionHandlerConfig{Linter: &lint.Linter{FailSeverity: lint.SeverityWarning}})
+			})
+
+			It("returns an error and does not apply the manifest", func() {
+				Expect(err).ToNot(BeNil())
+				Expect(err.Error()).To(HavePrefix("manifests failed linting: warning: deployment test-deployment container test-container uses image nginx:latest"))
+				Expect(fakeKubeClient.ApplyWithNamespaceOverrideCallCount()).To(Equal(0))
+			})
+		})
+
+		When("there is no linter", func() {
+			It("does not save warnings", func() {
+				Expect(err).To(BeNil())
+				kr := fakeSQLClient.CreateKubernetesResourceArgsForCall(0)
+				Expect(kr.Warnings).To(BeEmpty())
+			})
+		})
+	})
+
+	Context("validating scheduling", func() {
+		BeforeEach(func() {
+			actionHandler = NewActionHandlerWithConfig(ActionHandlerConfig{ValidateScheduling: true})
+			fakeUnstructured := unstructured.Unstructured{
+				Object: map[string]interface{}{
+					"apiVersion": "apps/v1",
+					"kind":       "Deployment",
+					"metadata": map[string]interface{}{
+						"name": "test-deployment",
+					},
+					"spec": map[string]interface{}{
+						"template": map[string]interface{}{
+							"spec": map[string]interface{}{
+								"nodeSelector": map[string]interface{}{
+									"kubernetes.io/os": "windows",
+								},
+							},
+						},
+					},
+				},
+			}
+			fakeKubeController.ToUnstructuredReturns(&fakeUnstructured, nil)
+			fakeKubeClient.ListResourceReturns(&unstructured.UnstructuredList{
+				Items: []unstructured.Unstructured{
+					{
+						Object: map[string]interface{}{
+							"metadata": map[string]interface{}{
+								"name": "test-node",
+								"labels": map[string]interface{}{
+									"kubernetes.io/os": "linux",
+								},
+							},
+						},
+					},
+				},
+			}, nil)
+		})
+
+		When("listing the nodes returns an error", func() {
+			BeforeEach(func() {
+				fakeKubeClient.ListResourceReturns(nil, errors.New("nodes is forbidden"))
+			})
+
+			It("deploys the manifest without warnings", func() {
+				Expect(err).To(BeNil())
+				Expect(fakeKubeClient.ApplyWithNamespaceOverrideCallCount()).To(Equal(1))
+				kr := fakeSQLClient.CreateKubernetesResourceArgsForCall(0)
+				Expect(kr.Warnings).To(BeEmpty())
+			})
+		})
+
+		When("no node fits the pods", func() {
+			It("saves the warning on the resource", func() {
+				Expect(err).To(BeNil())
+				resource, _ := fakeKubeClient.ListResourceArgsForCall(0)
+				Expect(resource).To(Equal("nodes"))
+				Expect(fakeKubeClient.ApplyWithNamespaceOverrideCallCount()).To(Equal(1))
+				kr := fakeSQLClient.CreateKubernetesResourceArgsForCall(0)
+				Expect(kr.Warnings).To(Equal("warning: deployment test-deployment will be unschedulable: " +
+					"0/1 nodes are available: 1 node(s) didn't match node selector"))
+			})
+		})
+
+		When("a node fits the pods", func() {
+			BeforeEach(func() {
+				fakeKubeClient.ListResourceReturns(&unstructured.UnstructuredList{
+					Items: []unstructured.Unstructured{
+						{
+							Object: map[string]interface{}{
+								"metadata": map[string]interface{}{
+									"name": "test-node",
+									"labels": map[string]interface{}{
+										"kubernetes.io/os": "windows",
+									},
+								},
+							},
+						},
+					},
+				}, nil)
+			})
+
+			It("does not save warnings", func() {
+				Expect(err).To(BeNil())
+				kr := fakeSQLClient.CreateKubernetesResourceArgsForCall(0)
+				Expect(kr.Warnings).To(BeEmpty())
+			})
+		})
+	})
+
+	Context("checking capacity", func() {
+		BeforeEach(func() {
+			actionHandler = NewActionHandlerWithConfig(ActionHandlerConfig{CapacityCheck: schedule.CapacityCheckWarn})
+			fakeUnstructured := unstructured.Unstructured{
+				Object: map[string]interface{}{
+					"apiVersion": "apps/v1",
+					"kind":       "Deployment",
+					"metadata": map[string]interface{}{
+						"name":      "test-deployment",
+						"namespace": "test-namespace",
+					},
+					"spec": map[string]interface{}{
+						"replicas": int64(4),
+						"template": map[string]interface{}{
+							"spec": map[string]interface{}{
+								"containers": []interface{}{
+									map[string]interface{}{
+										"name": "test-container",
+										"resources": map[string]interface{}{
+											"requests": map[string]interface{}{
+												"cpu": "1",
+											},
+										},
+									},
+								},
+							},
+						},
+					},
+				},
+			}
+			fakeKubeController.ToUnstructuredReturns(&fakeUnstructured, nil)
+			fakeKubeClient.ListResourceReturnsOnCall(0, &unstructured.UnstructuredList{
+				Items: []unstructured.Unstructured{
+					{
+						Object: map[string]interface{}{
+							"metadata": map[string]interface{}{
+								"name": "test-node",
+							},
+							"status": map[string]interface{}{
+								"allocatable": map[string]interface{}{
+									"cpu":  "4",
+									"pods": "110",
+								},
+							},
+						},
+					},
+				},
+			}, nil)
+			fakeKubeClient.ListResourceReturnsOnCall(1, &unstructured.UnstructuredList{
+				Items: []unstructured.Unstructured{
+					{
+						Object: map[string]interface{}{
+							"spec": map[string]interface{}{
+								"nodeName": "test-node",
+								"containers": []interface{}{
+									map[string]interface{}{
+										"name": "other-container",
+										"resources": map[string]interface{}{
+											"requests": map[string]interface{}{
+												"cpu": "2",
+											},
+										},
+									},
+								},
+							},
+						},
+					},
+				},
+			}, nil)
+			fakeKubeClient.GetReturns(nil, errors.New("not found"))
+		})
+
+		When("the capacity check warns", func() {
+			It("saves the warning on the resource", func() {
+				Expect(err).To(BeNil())
+				resource, lo := fakeKubeClient.ListResourceArgsForCall(1)
+				Expect(resource).To(Equal("pods"))
+				Expect(lo.FieldSelector).To(Equal("status.phase!=Succeeded,status.phase!=Failed"))
+				kind, name, namespace := fakeKubeClient.GetArgsForCall(0)
+				Expect(kind).To(Equal("deployment"))
+				Expect(name).To(Equal("test-deployment"))
+				Expect(namespace).To(Equal("test-namespace"))
+				kr := fakeSQLClient.CreateKubernetesResourceArgsForCall(0)
+				Expect(kr.Warnings).To(Equal("warning: deployment test-deployment exceeds the schedulable capacity of the cluster: " +
+					"room for 2 of 4 pods requesting cpu 1"))
+			})
+		})
+
+		When("the capacity check fails", func() {
+			BeforeEach(func() {
+				actionHandler = NewActionHandlerWithConfig(ActionHandlerConfig{CapacityCheck: schedule.CapacityCheckFail})
+			})
+
+			It("returns an error and does not apply the manifest", func() {
+				Expect(err).ToNot(BeNil())
+				Expect(err.Error()).To(Equal("manifests exceed the schedulable capacity of the cluster: " +
+					"deployment test-deployment: room for 2 of 4 pods requesting cpu 1"))
+				Expect(fakeKubeClient.ApplyWithNamespaceOverrideCallCount()).To(Equal(0))
+			})
+		})
+
+		When("the deployment already exists", func() {
+			BeforeEach(func() {
+				actionHandler = NewActionHandlerWithConfig(ActionHandlerConfig{CapacityCheck: schedule.CapacityCheckFail})
+				fakeKubeClient.GetReturns(&unstructured.Unstructured{
+					Object: map[string]interface{}{
+						"kind": "Deployment",
+						"spec": map[string]interface{}{
+							"replicas": int64(2),
+						},
+					},
+				}, nil)
+			})
+
+			It("only checks the pods it scales up by", func() {
+				Expect(err).To(BeNil())
+				Expect(fakeKubeClient.ApplyWithNamespaceOverrideCallCount()).To(Equal(1))
+			})
+		})
+
+		When("listing the pods returns an error", func() {
+			BeforeEach(func() {
+				actionHandler = NewActionHandlerWithConfig(ActionHandlerConfig{CapacityCheck: schedule.CapacityCheckFail})
+				fakeKubeClient.ListResourceReturnsOnCall(1, nil, errors.New("pods is forbidden"))
+			})
+
+			It("does not check capacity", func() {
+				Expect(err).To(BeNil())
+				Expect(fakeKubeClient.ApplyWithNamespaceOverrideCallCount()).To(Equal(1))
+			})
+		})
+	})
+
+	When("the account is in dryRun write mode", func() {
+		BeforeEach(func() {
+			fakeSQLClient.GetKubernetesProviderReturns(kubernetes.Provider{
+				Name:      "test-account",
+				Host:      "http://localhost",
+				WriteMode: kubernetes.WriteModeDryRun,
+			}, nil)
+		})
+
+		It("only dry-runs the changes", func() {
+			Expect(err).To(BeNil())
+			config := fakeKubeController.NewClientArgsForCall(0)
+			Expect(config.WrapTransport).ToNot(BeNil())
+		})
+
+		It("records what would have been deployed", func() {
+			kr := fakeSQLClient.CreateKubernetesResourceArgsForCall(0)
+			Expect(kr.DryRun).To(BeTrue())
+			Expect(kr.Manifest).To(ContainSubstring(`"name":"test-name"`))
+		})
+
+		When("the manifest is a secret", func() {
+			BeforeEach(func() {
+				fakeKubeController.ToUnstructuredReturns(&unstructured.Unstructured{Object: map[string]interface{}{
+					"kind":       "Secret",
+					"apiVersion": "v1",
+					"metadata": map[string]interface{}{
+						"name": "test-secret",
+					},
+					"data": map[string]interface{}{"password": "cGFzc3dvcmQ="},
+				}}, nil)
+			})
+
+			It("records it without its data", func() {
+				Expect(err).To(BeNil())
+				kr := fakeSQLClient.CreateKubernetesResourceArgsForCall(0)
+				Expect(kr.Manifest).To(ContainSubstring(`"password":"[REDACTED]"`))
+				Expect(kr.Manifest).ToNot(ContainSubstring("cGFzc3dvcmQ="))
+			})
+		})
+	})
+
+	When("the account is enforced", func() {
+		BeforeEach(func() {
+			fakeSQLClient.GetKubernetesProviderReturns(kubernetes.Provider{
+				Name:      "test-account",
+				Host:      "http://localhost",
+				WriteMode: kubernetes.WriteModeEnforced,
+			}, nil)
+		})
+
+		It("applies the changes", func() {
+			Expect(err).To(BeNil())
+			config := fakeKubeController.NewClientArgsForCall(0)
+			Expect(config.WrapTransport).To(BeNil())
+			kr := fakeSQLClient.CreateKubernetesResourceArgsForCall(0)
+			Expect(kr.DryRun).To(BeFalse())
+			Expect(kr.Manifest).To(BeEmpty())
+		})
+	})
+
+	When("the account is scoped to namespaces", func() {
+		BeforeEach(func() {
+			fakeSQLClient.GetKubernetesProviderReturns(kubernetes.Provider{
+				Name:       "test-account",
+				Host:       "http://localhost",
+				Namespaces: kubernetes.ProviderNamespaces{"default"},
+			}, nil)
+		})
+
+		When("the kind is cluster-scoped", func() {
+			BeforeEach(func() {
+				fakeKubeClient.NamespaceScopedReturns(false, nil)
+			})
+
+			It("returns an error without applying it", func() {
+				Expect(err).ToNot(BeNil())
+				Expect(err.Error()).To(Equal("error applying manifest (kind: test-kind, apiVersion: test-api-version, name: test-name): " +
+					"account test-account is scoped to namespaces and cannot change cluster-scoped kind test-kind"))
+				Expect(fakeKubeClient.ApplyWithNamespaceOverrideCallCount()).To(BeZero())
+			})
+		})
+
+		When("the kind is namespaced", func() {
+			BeforeEach(func() {
+				fakeKubeClient.NamespaceScopedReturns(true, nil)
+			})
+
+			It("applies it to a namespace of the scope", func() {
+				Expect(err).To(BeNil())
+				Expect(fakeKubeClient.ApplyWithNamespaceOverrideCallCount()).To(Equal(1))
+			})
+		})
+	})
+
 	Context("generating the cluster", func() {
 		When("the kind is deployment", func() {
 			kind := "deployment"
@@ -325,8 +1142,24 @@ var _ = Describe("Deploy", func() {
 	})
 
 	When("it succeeds", func() {
+		BeforeEach(func() {
+			fakeKubeClient.ApplyWithNamespaceOverrideReturns(kubernetes.Metadata{
+				Kind:      "Pod",
+				Name:      "test-name",
+				Namespace: "default",
+			}, nil)
+		})
+
 		It("succeeds", func() {
 			Expect(err).To(BeNil())
 		})
+
+		It("records each applied manifest as an event of the task", func() {
+			Expect(fakeSQLClient.CreateTaskEventCallCount()).To(Equal(1))
+			te := fakeSQLClient.CreateTaskEventArgsForCall(0)
+			Expect(te.TaskID).To(Equal("test-id"))
+			Expect(te.Type).To(Equal(clouddriver.TaskEventManifestApplied))
+			Expect(te.Message).To(Equal("applied manifest 1/1: Pod test-name in default"))
+		})
 	})
 })
@@ -0,0 +1,195 @@
+package kubernetes
+
+import (
+	"encoding/base64"
+	"fmt"
+	"path"
+	"reflect"
+	"regexp"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+
+	clouddriver "github.com/billiford/go-clouddriver/pkg"
+	"github.com/billiford/go-clouddriver/pkg/artifact"
+	"github.com/billiford/go-clouddriver/pkg/kubernetes"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+// manifestsFromArtifacts builds the ConfigMaps and Secrets of a deploy from the
+// contents of their artifacts.
+func (d *deployManfest) manifestsFromArtifacts(client kubernetes.Client) ([]map[string]interface{}, error) {
+	manifests := []map[string]interface{}{}
+
+	for _, fa := range d.dm.FromArtifacts {
+		m, err := d.manifestFromArtifacts(client, fa)
+		if err != nil {
+			return nil, fmt.Errorf("error creating %s %s from artifacts: %w", fa.Kind, fa.Name, err)
+		}
+
+		manifests = append(manifests, m)
+	}
+
+	return manifests, nil
+}
+
+func (d *deployManfest) manifestFromArtifacts(client kubernetes.Client,
+	fa DeployManifestRequestFromArtifacts) (map[string]interface{}, error) {
+	var kind string
+
+	switch {
+	case strings.EqualFold(fa.Kind, "configMap"):
+		kind = "ConfigMap"
+	case strings.EqualFold(fa.Kind, "secret"):
+		kind = "Secret"
+	default:
+		return nil, fmt.Errorf("kind must be ConfigMap or Secret")
+	}
+
+	if fa.Name == "" {
+		return nil, fmt.Errorf("name is required")
+	}
+
+	namespace := d.dm.NamespaceOverride
+	if namespace == "" {
+		namespace = fa.Namespace
+	}
+
+	if namespace == "" {
+		namespace = "default"
+	}
+
+	data := map[string]interface{}{}
+	binaryData := map[string]interface{}{}
+
+	for i, file := range fa.Files {
+		key := file.Key
+		if key == "" && file.Artifact.Name != "" {
+			key = path.Base(file.Artifact.Name)
+		}
+
+		if key == "" {
+			return nil, fmt.Errorf("file %d has no key", i)
+		}
+
+		if _, ok := data[key]; ok {
+			return nil, fmt.Errorf("duplicate key %s", key)
+		}
+
+		if _, ok := binaryData[key]; ok {
+			return nil, fmt.Errorf("duplicate key %s", key)
+		}
+
+		b, err := artifact.Fetch(d.acc, file.Artifact)
+		if err != nil {
+			return nil, fmt.Errorf("error fetching artifact %s: %w", file.Artifact.Name, err)
+		}
+
+		RecordTaskEvent(d.sc, d.id, clouddriver.TaskEventArtifactFetched, "fetched artifact %s (%d bytes) for %s %s",
+			file.Artifact.Name, len(b), kind, fa.Name)
+
+		switch {
+		case kind == "Secret":
+			data[key] = base64.StdEncoding.EncodeToString(b)
+		case utf8.Valid(b):
+			data[key] = string(b)
+		default:
+			binaryData[key] = base64.StdEncoding.EncodeToString(b)
+		}
+	}
+
+	u := &unstructured.Unstructured{Object: map[string]interface{}{}}
+	u.SetAPIVersion("v1")
+	u.SetKind(kind)
+	u.SetName(fa.Name)
+	u.SetNamespace(namespace)
+
+	if len(data) > 0 {
+		u.Object["data"] = data
+	}
+
+	if len(binaryData) > 0 {
+		u.Object["binaryData"] = binaryData
+	}
+
+	if kind == "Secret" {
+		t := fa.Type
+		if t == "" {
+			t = "Opaque"
+		}
+
+		u.Object["type"] = t
+	}
+
+	if fa.Versioned == nil || *fa.Versioned {
+		err := d.version(client, u)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return u.Object, nil
+}
+
+// version names a ConfigMap or Secret after its next version, such as my-config-v002,
+// per the Spinnaker convention. If the latest version has the same contents it is
+// deployed again instead, so workloads referencing it do not roll out.
+func (d *deployManfest) version(client kubernetes.Client, u *unstructured.Unstructured) error {
+	name := u.GetName()
+
+	list, err := client.ListResource(u.GetKind(), metav1.ListOptions{
+		LabelSelector: kubernetes.ManagedApplicationLabelSelector(d.dm.Moniker.App),
+		FieldSelector: "metadata.namespace=" + u.GetNamespace(),
+	})
+	if err != nil {
+		return fmt.Errorf("error listing versions: %w", err)
+	}
+
+	versionedName := regexp.MustCompile(`^` + regexp.QuoteMeta(name) + `-v(\d{3,})$`)
+	sequence := -1
+
+	var latest *unstructured.Unstructured
+
+	for i := range list.Items {
+		match := versionedName.FindStringSubmatch(list.Items[i].GetName())
+		if match == nil {
+			continue
+		}
+
+		s, _ := strconv.Atoi(match[1])
+		if s > sequence {
+			sequence = s
+			latest = &list.Items[i]
+		}
+	}
+
+	if latest == nil || !sameContents(latest, u) {
+		sequence++
+	}
+
+	version := fmt.Sprintf("v%03d", sequence)
+
+	u.SetName(name + "-" + version)
+
+	annotations := u.GetAnnotations()
+	if annotations == nil {
+		annotations = map[string]string{}
+	}
+
+	annotations[kubernetes.AnnotationSpinnakerArtifactVersion] = version
+	annotations[kubernetes.AnnotationSpinnakerMonikerSequence] = strconv.Itoa(sequence)
+	u.SetAnnotations(annotations)
+
+	return nil
+}
+
+func sameContents(a, b *unstructured.Unstructured) bool {
+	for _, field := range []string{"data", "binaryData", "type"} {
+		if !reflect.DeepEqual(a.Object[field], b.Object[field]) {
+			return false
+		}
+	}
+
+	return true
+}
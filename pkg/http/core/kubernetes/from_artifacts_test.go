@@ -0,0 +1,211 @@
+package kubernetes_test
+
+import (
+	"errors"
+
+	"github.com/billiford/go-clouddriver/pkg/artifact"
+	"github.com/billiford/go-clouddriver/pkg/artifact/artifactfakes"
+	. "github.com/billiford/go-clouddriver/pkg/http/core/kubernetes"
+	"github.com/billiford/go-clouddriver/pkg/kubernetes"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+var _ = Describe("FromArtifacts", func() {
+	var (
+		fakeArtifactCredentialsController *artifactfakes.FakeCredentialsController
+		u                                 *unstructured.Unstructured
+	)
+
+	BeforeEach(func() {
+		setup()
+		fakeArtifactCredentialsController = &artifactfakes.FakeCredentialsController{}
+		actionConfig.ArtifactCredentialsController = fakeArtifactCredentialsController
+		actionConfig.Operation.DeployManifest = &DeployManifestRequest{
+			Account:   "test-account",
+			Manifests: []map[string]interface{}{},
+			FromArtifacts: []DeployManifestRequestFromArtifacts{
+				{
+					Kind:      "configMap",
+					Name:      "test-config",
+					Namespace: "test-namespace",
+					Files: []DeployManifestRequestFile{
+						{
+							Artifact: artifact.Artifact{
+								Type:      artifact.TypeEmbeddedBase64,
+								Name:      "config/app.yaml",
+								Reference: "a2V5OiB2YWx1ZQ==",
+							},
+						},
+						{
+							Key: "logo.png",
+							Artifact: artifact.Artifact{
+								Type:      artifact.TypeEmbeddedBase64,
+								Reference: "iVBORw==",
+							},
+						},
+					},
+				},
+			},
+		}
+		actionConfig.Operation.DeployManifest.Moniker.App = "test-app"
+		fakeKubeController.ToUnstructuredCalls(kubernetes.NewController().ToUnstructured)
+		fakeKubeClient.ListResourceReturns(&unstructured.UnstructuredList{}, nil)
+	})
+
+	JustBeforeEach(func() {
+		action = actionHandler.NewDeployManifestAction(actionConfig)
+		err = action.Run()
+
+		if fakeKubeClient.ApplyWithNamespaceOverrideCallCount() > 0 {
+			u, _ = fakeKubeClient.ApplyWithNamespaceOverrideArgsForCall(0)
+		}
+	})
+
+	When("the kind is not supported", func() {
+		BeforeEach(func() {
+			actionConfig.Operation.DeployManifest.FromArtifacts[0].Kind = "Deployment"
+		})
+
+		It("returns an error", func() {
+			Expect(err).ToNot(BeNil())
+			Expect(err.Error()).To(Equal("error creating Deployment test-config from artifacts: kind must be ConfigMap or Secret"))
+		})
+	})
+
+	When("a file has no key", func() {
+		BeforeEach(func() {
+			actionConfig.Operation.DeployManifest.FromArtifacts[0].Files[1].Key = ""
+		})
+
+		It("returns an error", func() {
+			Expect(err).ToNot(BeNil())
+			Expect(err.Error()).To(Equal("error creating configMap test-config from artifacts: file 1 has no key"))
+		})
+	})
+
+	When("fetching an artifact returns an error", func() {
+		BeforeEach(func() {
+			actionConfig.Operation.DeployManifest.FromArtifacts[0].Files[0].Artifact.Type = artifact.TypeHTTPFile
+			fakeArtifactCredentialsController.HTTPClientForAccountNameReturns(nil, errors.New("error getting http client"))
+		})
+
+		It("returns an error", func() {
+			Expect(err).ToNot(BeNil())
+			Expect(err.Error()).To(Equal("error creating configMap test-config from artifacts: " +
+				"error fetching artifact config/app.yaml: error getting http client"))
+		})
+	})
+
+	When("listing the versions returns an error", func() {
+		BeforeEach(func() {
+			fakeKubeClient.ListResourceReturns(nil, errors.New("error listing"))
+		})
+
+		It("returns an error", func() {
+			Expect(err).ToNot(BeNil())
+			Expect(err.Error()).To(Equal("error creating configMap test-config from artifacts: error listing versions: error listing"))
+		})
+	})
+
+	When("the config map is not versioned", func() {
+		BeforeEach(func() {
+			versioned := false
+			actionConfig.Operation.DeployManifest.FromArtifacts[0].Versioned = &versioned
+		})
+
+		It("does not version the name", func() {
+			Expect(err).To(BeNil())
+			Expect(u.GetName()).To(Equal("test-config"))
+			Expect(fakeKubeClient.ListResourceCallCount()).To(BeZero())
+		})
+	})
+
+	When("a version with different contents exists", func() {
+		BeforeEach(func() {
+			fakeKubeClient.ListResourceReturns(&unstructured.UnstructuredList{
+				Items: []unstructured.Unstructured{
+					{
+						Object: map[string]interface{}{
+							"metadata": map[string]interface{}{"name": "test-config-v000"},
+							"data":     map[string]interface{}{"app.yaml": "key: old"},
+						},
+					},
+					{
+						Object: map[string]interface{}{
+							"metadata": map[string]interface{}{"name": "test-config-v001"},
+							"data":     map[string]interface{}{"app.yaml": "key: older"},
+						},
+					},
+					{
+						Object: map[string]interface{}{
+							"metadata": map[string]interface{}{"name": "test-config-other-v005"},
+						},
+					},
+				},
+			}, nil)
+		})
+
+		It("creates the next version", func() {
+			Expect(err).To(BeNil())
+			Expect(u.GetName()).To(Equal("test-config-v002"))
+			Expect(u.GetAnnotations()).To(HaveKeyWithValue("artifact.spinnaker.io/version", "v002"))
+			Expect(u.GetAnnotations()).To(HaveKeyWithValue("moniker.spinnaker.io/sequence", "2"))
+		})
+	})
+
+	When("the latest version has the same contents", func() {
+		BeforeEach(func() {
+			fakeKubeClient.ListResourceReturns(&unstructured.UnstructuredList{
+				Items: []unstructured.Unstructured{
+					{
+						Object: map[string]interface{}{
+							"metadata":   map[string]interface{}{"name": "test-config-v003"},
+							"data":       map[string]interface{}{"app.yaml": "key: value"},
+							"binaryData": map[string]interface{}{"logo.png": "iVBORw=="},
+						},
+					},
+				},
+			}, nil)
+		})
+
+		It("deploys the latest version again", func() {
+			Expect(err).To(BeNil())
+			Expect(u.GetName()).To(Equal("test-config-v003"))
+		})
+	})
+
+	When("the kind is secret", func() {
+		BeforeEach(func() {
+			actionConfig.Operation.DeployManifest.FromArtifacts[0].Kind = "Secret"
+		})
+
+		It("encodes the data", func() {
+			Expect(err).To(BeNil())
+			Expect(u.GetKind()).To(Equal("Secret"))
+			Expect(u.Object["type"]).To(Equal("Opaque"))
+			data, _, _ := unstructured.NestedStringMap(u.Object, "data")
+			Expect(data).To(Equal(map[string]string{
+				"app.yaml": "a2V5OiB2YWx1ZQ==",
+				"logo.png": "iVBORw==",
+			}))
+		})
+	})
+
+	When("it succeeds", func() {
+		It("creates the first version of the config map", func() {
+			Expect(err).To(BeNil())
+			Expect(u.GetKind()).To(Equal("ConfigMap"))
+			Expect(u.GetName()).To(Equal("test-config-v000"))
+			Expect(u.GetNamespace()).To(Equal("test-namespace"))
+			data, _, _ := unstructured.NestedStringMap(u.Object, "data")
+			Expect(data).To(Equal(map[string]string{"app.yaml": "key: value"}))
+			binaryData, _, _ := unstructured.NestedStringMap(u.Object, "binaryData")
+			Expect(binaryData).To(Equal(map[string]string{"logo.png": "iVBORw=="}))
+			_, lo := fakeKubeClient.ListResourceArgsForCall(0)
+			Expect(lo.LabelSelector).To(Equal("app.kubernetes.io/managed-by=spinnaker,app.kubernetes.io/name=test-app"))
+			Expect(lo.FieldSelector).To(Equal("metadata.namespace=test-namespace"))
+		})
+	})
+})
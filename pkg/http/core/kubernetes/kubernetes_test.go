@@ -127,6 +127,13 @@ func newActionConfig() ActionConfig {
 				Alias:         "alias",
 				Account:       "test-account",
 			},
+			MigrateApplication: &MigrateApplicationRequest{
+				Application:     "test-application",
+				SourceAccount:   "test-source-account",
+				SourceNamespace: "test-source-namespace",
+				Account:         "test-account",
+				Namespace:       "test-namespace",
+			},
 		},
 	}
 }
@@ -19,6 +19,28 @@ type FakeActionHandler struct {
 	newCleanupArtifactsActionReturnsOnCall map[int]struct {
 		result1 kubernetes.Action
 	}
+	NewCreateApplicationActionStub        func(kubernetes.ActionConfig) kubernetes.Action
+	newCreateApplicationActionMutex       sync.RWMutex
+	newCreateApplicationActionArgsForCall []struct {
+		arg1 kubernetes.ActionConfig
+	}
+	newCreateApplicationActionReturns struct {
+		result1 kubernetes.Action
+	}
+	newCreateApplicationActionReturnsOnCall map[int]struct {
+		result1 kubernetes.Action
+	}
+	NewDeleteApplicationActionStub        func(kubernetes.ActionConfig) kubernetes.Action
+	newDeleteApplicationActionMutex       sync.RWMutex
+	newDeleteApplicationActionArgsForCall []struct {
+		arg1 kubernetes.ActionConfig
+	}
+	newDeleteApplicationActionReturns struct {
+		result1 kubernetes.Action
+	}
+	newDeleteApplicationActionReturnsOnCall map[int]struct {
+		result1 kubernetes.Action
+	}
 	NewDeleteManifestActionStub        func(kubernetes.ActionConfig) kubernetes.Action
 	newDeleteManifestActionMutex       sync.RWMutex
 	newDeleteManifestActionArgsForCall []struct {
@@ -41,6 +63,17 @@ type FakeActionHandler struct {
 	newDeployManifestActionReturnsOnCall map[int]struct {
 		result1 kubernetes.Action
 	}
+	NewMigrateApplicationActionStub        func(kubernetes.ActionConfig) kubernetes.Action
+	newMigrateApplicationActionMutex       sync.RWMutex
+	newMigrateApplicationActionArgsForCall []struct {
+		arg1 kubernetes.ActionConfig
+	}
+	newMigrateApplicationActionReturns struct {
+		result1 kubernetes.Action
+	}
+	newMigrateApplicationActionReturnsOnCall map[int]struct {
+		result1 kubernetes.Action
+	}
 	NewPatchManifestActionStub        func(kubernetes.ActionConfig) kubernetes.Action
 	newPatchManifestActionMutex       sync.RWMutex
 	newPatchManifestActionArgsForCall []struct {
@@ -160,6 +193,126 @@ func (fake *FakeActionHandler) NewCleanupArtifactsActionReturnsOnCall(i int, res
 	}{result1}
 }
 
+func (fake *FakeActionHandler) NewCreateApplicationAction(arg1 kubernetes.ActionConfig) kubernetes.Action {
+	fake.newCreateApplicationActionMutex.Lock()
+	ret, specificReturn := fake.newCreateApplicationActionReturnsOnCall[len(fake.newCreateApplicationActionArgsForCall)]
+	fake.newCreateApplicationActionArgsForCall = append(fake.newCreateApplicationActionArgsForCall, struct {
+		arg1 kubernetes.ActionConfig
+	}{arg1})
+	fake.recordInvocation("NewCreateApplicationAction", []interface{}{arg1})
+	fake.newCreateApplicationActionMutex.Unlock()
+	if fake.NewCreateApplicationActionStub != nil {
+		return fake.NewCreateApplicationActionStub(arg1)
+	}
+	if specificReturn {
+		return ret.result1
+	}
+	fakeReturns := fake.newCreateApplicationActionReturns
+	return fakeReturns.result1
+}
+
+func (fake *FakeActionHandler) NewCreateApplicationActionCallCount() int {
+	fake.newCreateApplicationActionMutex.RLock()
+	defer fake.newCreateApplicationActionMutex.RUnlock()
+	return len(fake.newCreateApplicationActionArgsForCall)
+}
+
+func (fake *FakeActionHandler) NewCreateApplicationActionCalls(stub func(kubernetes.ActionConfig) kubernetes.Action) {
+	fake.newCreateApplicationActionMutex.Lock()
+	defer fake.newCreateApplicationActionMutex.Unlock()
+	fake.NewCreateApplicationActionStub = stub
+}
+
+func (fake *FakeActionHandler) NewCreateApplicationActionArgsForCall(i int) kubernetes.ActionConfig {
+	fake.newCreateApplicationActionMutex.RLock()
+	defer fake.newCreateApplicationActionMutex.RUnlock()
+	argsForCall := fake.newCreateApplicationActionArgsForCall[i]
+	return argsForCall.arg1
+}
+
+func (fake *FakeActionHandler) NewCreateApplicationActionReturns(result1 kubernetes.Action) {
+	fake.newCreateApplicationActionMutex.Lock()
+	defer fake.newCreateApplicationActionMutex.Unlock()
+	fake.NewCreateApplicationActionStub = nil
+	fake.newCreateApplicationActionReturns = struct {
+		result1 kubernetes.Action
+	}{result1}
+}
+
+func (fake *FakeActionHandler) NewCreateApplicationActionReturnsOnCall(i int, result1 kubernetes.Action) {
+	fake.newCreateApplicationActionMutex.Lock()
+	defer fake.newCreateApplicationActionMutex.Unlock()
+	fake.NewCreateApplicationActionStub = nil
+	if fake.newCreateApplicationActionReturnsOnCall == nil {
+		fake.newCreateApplicationActionReturnsOnCall = make(map[int]struct {
+			result1 kubernetes.Action
+		})
+	}
+	fake.newCreateApplicationActionReturnsOnCall[i] = struct {
+		result1 kubernetes.Action
+	}{result1}
+}
+
+func (fake *FakeActionHandler) NewDeleteApplicationAction(arg1 kubernetes.ActionConfig) kubernetes.Action {
+	fake.newDeleteApplicationActionMutex.Lock()
+	ret, specificReturn := fake.newDeleteApplicationActionReturnsOnCall[len(fake.newDeleteApplicationActionArgsForCall)]
+	fake.newDeleteApplicationActionArgsForCall = append(fake.newDeleteApplicationActionArgsForCall, struct {
+		arg1 kubernetes.ActionConfig
+	}{arg1})
+	fake.recordInvocation("NewDeleteApplicationAction", []interface{}{arg1})
+	fake.newDeleteApplicationActionMutex.Unlock()
+	if fake.NewDeleteApplicationActionStub != nil {
+		return fake.NewDeleteApplicationActionStub(arg1)
+	}
+	if specificReturn {
+		return ret.result1
+	}
+	fakeReturns := fake.newDeleteApplicationActionReturns
+	return fakeReturns.result1
+}
+
+func (fake *FakeActionHandler) NewDeleteApplicationActionCallCount() int {
+	fake.newDeleteApplicationActionMutex.RLock()
+	defer fake.newDeleteApplicationActionMutex.RUnlock()
+	return len(fake.newDeleteApplicationActionArgsForCall)
+}
+
+func (fake *FakeActionHandler) NewDeleteApplicationActionCalls(stub func(kubernetes.ActionConfig) kubernetes.Action) {
+	fake.newDeleteApplicationActionMutex.Lock()
+	defer fake.newDeleteApplicationActionMutex.Unlock()
+	fake.NewDeleteApplicationActionStub = stub
+}
+
+func (fake *FakeActionHandler) NewDeleteApplicationActionArgsForCall(i int) kubernetes.ActionConfig {
+	fake.newDeleteApplicationActionMutex.RLock()
+	defer fake.newDeleteApplicationActionMutex.RUnlock()
+	argsForCall := fake.newDeleteApplicationActionArgsForCall[i]
+	return argsForCall.arg1
+}
+
+func (fake *FakeActionHandler) NewDeleteApplicationActionReturns(result1 kubernetes.Action) {
+	fake.newDeleteApplicationActionMutex.Lock()
+	defer fake.newDeleteApplicationActionMutex.Unlock()
+	fake.NewDeleteApplicationActionStub = nil
+	fake.newDeleteApplicationActionReturns = struct {
+		result1 kubernetes.Action
+	}{result1}
+}
+
+func (fake *FakeActionHandler) NewDeleteApplicationActionReturnsOnCall(i int, result1 kubernetes.Action) {
+	fake.newDeleteApplicationActionMutex.Lock()
+	defer fake.newDeleteApplicationActionMutex.Unlock()
+	fake.NewDeleteApplicationActionStub = nil
+	if fake.newDeleteApplicationActionReturnsOnCall == nil {
+		fake.newDeleteApplicationActionReturnsOnCall = make(map[int]struct {
+			result1 kubernetes.Action
+		})
+	}
+	fake.newDeleteApplicationActionReturnsOnCall[i] = struct {
+		result1 kubernetes.Action
+	}{result1}
+}
+
 func (fake *FakeActionHandler) NewDeleteManifestAction(arg1 kubernetes.ActionConfig) kubernetes.Action {
 	fake.newDeleteManifestActionMutex.Lock()
 	ret, specificReturn := fake.newDeleteManifestActionReturnsOnCall[len(fake.newDeleteManifestActionArgsForCall)]
@@ -280,6 +433,66 @@ func (fake *FakeActionHandler) NewDeployManifestActionReturnsOnCall(i int, resul
 	}{result1}
 }
 
+func (fake *FakeActionHandler) NewMigrateApplicationAction(arg1 kubernetes.ActionConfig) kubernetes.Action {
+	fake.newMigrateApplicationActionMutex.Lock()
+	ret, specificReturn := fake.newMigrateApplicationActionReturnsOnCall[len(fake.newMigrateApplicationActionArgsForCall)]
+	fake.newMigrateApplicationActionArgsForCall = append(fake.newMigrateApplicationActionArgsForCall, struct {
+		arg1 kubernetes.ActionConfig
+	}{arg1})
+	fake.recordInvocation("NewMigrateApplicationAction", []interface{}{arg1})
+	fake.newMigrateApplicationActionMutex.Unlock()
+	if fake.NewMigrateApplicationActionStub != nil {
+		return fake.NewMigrateApplicationActionStub(arg1)
+	}
+	if specificReturn {
+		return ret.result1
+	}
+	fakeReturns := fake.newMigrateApplicationActionReturns
+	return fakeReturns.result1
+}
+
+func (fake *FakeActionHandler) NewMigrateApplicationActionCallCount() int {
+	fake.newMigrateApplicationActionMutex.RLock()
+	defer fake.newMigrateApplicationActionMutex.RUnlock()
+	return len(fake.newMigrateApplicationActionArgsForCall)
+}
+
+func (fake *FakeActionHandler) NewMigrateApplicationActionCalls(stub func(kubernetes.ActionConfig) kubernetes.Action) {
+	fake.newMigrateApplicationActionMutex.Lock()
+	defer fake.newMigrateApplicationActionMutex.Unlock()
+	fake.NewMigrateApplicationActionStub = stub
+}
+
+func (fake *FakeActionHandler) NewMigrateApplicationActionArgsForCall(i int) kubernetes.ActionConfig {
+	fake.newMigrateApplicationActionMutex.RLock()
+	defer fake.newMigrateApplicationActionMutex.RUnlock()
+	argsForCall := fake.newMigrateApplicationActionArgsForCall[i]
+	return argsForCall.arg1
+}
+
+func (fake *FakeActionHandler) NewMigrateApplicationActionReturns(result1 kubernetes.Action) {
+	fake.newMigrateApplicationActionMutex.Lock()
+	defer fake.newMigrateApplicationActionMutex.Unlock()
+	fake.NewMigrateApplicationActionStub = nil
+	fake.newMigrateApplicationActionReturns = struct {
+		result1 kubernetes.Action
+	}{result1}
+}
+
+func (fake *FakeActionHandler) NewMigrateApplicationActionReturnsOnCall(i int, result1 kubernetes.Action) {
+	fake.newMigrateApplicationActionMutex.Lock()
+	defer fake.newMigrateApplicationActionMutex.Unlock()
+	fake.NewMigrateApplicationActionStub = nil
+	if fake.newMigrateApplicationActionReturnsOnCall == nil {
+		fake.newMigrateApplicationActionReturnsOnCall = make(map[int]struct {
+			result1 kubernetes.Action
+		})
+	}
+	fake.newMigrateApplicationActionReturnsOnCall[i] = struct {
+		result1 kubernetes.Action
+	}{result1}
+}
+
 func (fake *FakeActionHandler) NewPatchManifestAction(arg1 kubernetes.ActionConfig) kubernetes.Action {
 	fake.newPatchManifestActionMutex.Lock()
 	ret, specificReturn := fake.newPatchManifestActionReturnsOnCall[len(fake.newPatchManifestActionArgsForCall)]
@@ -585,10 +798,16 @@ func (fake *FakeActionHandler) Invocations() map[string][][]interface{} {
 	defer fake.invocationsMutex.RUnlock()
 	fake.newCleanupArtifactsActionMutex.RLock()
 	defer fake.newCleanupArtifactsActionMutex.RUnlock()
+	fake.newCreateApplicationActionMutex.RLock()
+	defer fake.newCreateApplicationActionMutex.RUnlock()
+	fake.newDeleteApplicationActionMutex.RLock()
+	defer fake.newDeleteApplicationActionMutex.RUnlock()
 	fake.newDeleteManifestActionMutex.RLock()
 	defer fake.newDeleteManifestActionMutex.RUnlock()
 	fake.newDeployManifestActionMutex.RLock()
 	defer fake.newDeployManifestActionMutex.RUnlock()
+	fake.newMigrateApplicationActionMutex.RLock()
+	defer fake.newMigrateApplicationActionMutex.RUnlock()
 	fake.newPatchManifestActionMutex.RLock()
 	defer fake.newPatchManifestActionMutex.RUnlock()
 	fake.newRollbackActionMutex.RLock()
@@ -0,0 +1,66 @@
+package kubernetes
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+
+	clouddriver "github.com/billiford/go-clouddriver/pkg"
+	"k8s.io/apimachinery/pkg/util/yaml"
+)
+
+// manifestsFromArtifact returns the manifests of the manifest artifact of a
+// deploy, if any.
+func (d *deployManfest) manifestsFromArtifact() ([]map[string]interface{}, error) {
+	a := d.dm.ManifestArtifact
+	if a == nil {
+		return nil, nil
+	}
+
+	b, err := d.urlFetcher.Fetch(d.acc, a.Artifact, a.SHA256)
+	if err != nil {
+		return nil, fmt.Errorf("error fetching manifest artifact %s: %w", a.Reference, err)
+	}
+
+	RecordTaskEvent(d.sc, d.id, clouddriver.TaskEventArtifactFetched, "fetched manifest artifact %s (%d bytes)",
+		a.Reference, len(b))
+
+	manifests, err := decodeManifests(b)
+	if err != nil {
+		return nil, fmt.Errorf("error decoding manifest artifact %s: %w", a.Reference, err)
+	}
+
+	return manifests, nil
+}
+
+// decodeManifests decodes the YAML documents or JSON objects of b, skipping
+// empty documents.
+func decodeManifests(b []byte) ([]map[string]interface{}, error) {
+	manifests := []map[string]interface{}{}
+	decoder := yaml.NewYAMLOrJSONDecoder(bytes.NewReader(b), 4096)
+
+	for {
+		manifest := map[string]interface{}{}
+
+		err := decoder.Decode(&manifest)
+		if err == io.EOF {
+			break
+		}
+
+		if err != nil {
+			return nil, err
+		}
+
+		if len(manifest) == 0 {
+			continue
+		}
+
+		manifests = append(manifests, manifest)
+	}
+
+	if len(manifests) == 0 {
+		return nil, fmt.Errorf("no manifests found")
+	}
+
+	return manifests, nil
+}
@@ -0,0 +1,86 @@
+package kubernetes_test
+
+import (
+	"net/http"
+
+	"github.com/billiford/go-clouddriver/pkg/artifact"
+	"github.com/billiford/go-clouddriver/pkg/artifact/artifactfakes"
+	. "github.com/billiford/go-clouddriver/pkg/http/core/kubernetes"
+	"github.com/billiford/go-clouddriver/pkg/kubernetes"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"github.com/onsi/gomega/ghttp"
+)
+
+var _ = Describe("ManifestArtifact", func() {
+	var (
+		fakeServer                        *ghttp.Server
+		fakeArtifactCredentialsController *artifactfakes.FakeCredentialsController
+	)
+
+	BeforeEach(func() {
+		setup()
+		fakeServer = ghttp.NewServer()
+		fakeServer.RouteToHandler(http.MethodGet, "/manifests.yaml", ghttp.RespondWith(http.StatusOK,
+			"apiVersion: v1\nkind: ConfigMap\nmetadata:\n  name: test-config\n---\n"+
+				"apiVersion: v1\nkind: Service\nmetadata:\n  name: test-service\n"))
+		fakeArtifactCredentialsController = &artifactfakes.FakeCredentialsController{}
+		fakeArtifactCredentialsController.HTTPClientForAccountNameReturns(http.DefaultClient, nil)
+		actionConfig.ArtifactCredentialsController = fakeArtifactCredentialsController
+		actionConfig.Operation.DeployManifest = &DeployManifestRequest{
+			Account:   "test-account",
+			Manifests: []map[string]interface{}{},
+			ManifestArtifact: &DeployManifestRequestArtifact{
+				Artifact: artifact.Artifact{
+					Type:            artifact.TypeHTTPFile,
+					Reference:       fakeServer.URL() + "/manifests.yaml",
+					ArtifactAccount: "test-http-account",
+				},
+			},
+		}
+		fakeKubeController.ToUnstructuredCalls(kubernetes.NewController().ToUnstructured)
+	})
+
+	AfterEach(func() {
+		fakeServer.Close()
+	})
+
+	JustBeforeEach(func() {
+		action = actionHandler.NewDeployManifestAction(actionConfig)
+		err = action.Run()
+	})
+
+	When("the checksum does not match", func() {
+		BeforeEach(func() {
+			actionConfig.Operation.DeployManifest.ManifestArtifact.SHA256 =
+				"0000000000000000000000000000000000000000000000000000000000000000"
+		})
+
+		It("returns an error without deploying", func() {
+			Expect(err).ToNot(BeNil())
+			Expect(err.Error()).To(HavePrefix("error fetching manifest artifact " + fakeServer.URL() + "/manifests.yaml: contents of"))
+			Expect(fakeKubeClient.ApplyWithNamespaceOverrideCallCount()).To(BeZero())
+		})
+	})
+
+	When("the artifact has no manifests", func() {
+		BeforeEach(func() {
+			fakeServer.RouteToHandler(http.MethodGet, "/manifests.yaml", ghttp.RespondWith(http.StatusOK, "---\n"))
+		})
+
+		It("returns an error", func() {
+			Expect(err).ToNot(BeNil())
+			Expect(err.Error()).To(Equal("error decoding manifest artifact " + fakeServer.URL() + "/manifests.yaml: no manifests found"))
+		})
+	})
+
+	It("deploys the manifests of the artifact", func() {
+		Expect(err).To(BeNil())
+		Expect(fakeArtifactCredentialsController.HTTPClientForAccountNameArgsForCall(0)).To(Equal("test-http-account"))
+		Expect(fakeKubeClient.ApplyWithNamespaceOverrideCallCount()).To(Equal(2))
+		u, _ := fakeKubeClient.ApplyWithNamespaceOverrideArgsForCall(0)
+		Expect(u.GetKind()).To(Equal("ConfigMap"))
+		u, _ = fakeKubeClient.ApplyWithNamespaceOverrideArgsForCall(1)
+		Expect(u.GetName()).To(Equal("test-service"))
+	})
+})
@@ -0,0 +1,376 @@
+package kubernetes
+
+import (
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	clouddriver "github.com/billiford/go-clouddriver/pkg"
+	"github.com/billiford/go-clouddriver/pkg/arcade"
+	"github.com/billiford/go-clouddriver/pkg/kubernetes"
+	"github.com/billiford/go-clouddriver/pkg/kubernetes/normalize"
+	"github.com/billiford/go-clouddriver/pkg/sql"
+	"github.com/google/uuid"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/client-go/rest"
+)
+
+// Versions of versioned resources a migration copies.
+const (
+	MigrateVersionsAll    = `all`
+	MigrateVersionsLatest = `latest`
+)
+
+// How a migration copies secrets.
+const (
+	// MigrateSecretsSkip leaves secrets out, the default.
+	MigrateSecretsSkip = `skip`
+	// MigrateSecretsCopy copies secrets with their values.
+	MigrateSecretsCopy = `copy`
+	// MigrateSecretsEmpty copies secrets with their keys but empty values,
+	// to be filled in the target.
+	MigrateSecretsEmpty = `empty`
+)
+
+// DefaultMigrationKinds are the kinds a migration copies when none are
+// given. Jobs are left out, as copying a job runs it again.
+var DefaultMigrationKinds = []string{
+	"configMaps",
+	"secrets",
+	"services",
+	"ingresses",
+	"deployments",
+	"replicaSets",
+	"statefulSets",
+	"daemonSets",
+	"cronJobs",
+}
+
+func (ah *actionHandler) NewMigrateApplicationAction(ac ActionConfig) Action {
+	return &migrateApplication{
+		ac: ac.ArcadeClient,
+		sc: ac.SQLClient,
+		kc: ac.KubeController,
+		id: ac.ID,
+		ma: ac.Operation.MigrateApplication,
+	}
+}
+
+type migrateApplication struct {
+	ac arcade.Client
+	sc sql.Client
+	kc kubernetes.Controller
+	id string
+	ma *MigrateApplicationRequest
+}
+
+// Run copies the resources Spinnaker manages for an application from the
+// source account and namespace to the target, keeping their names, so
+// versioned resources such as my-app-v003 keep their version. Resources
+// owned by another resource, such as the replica sets of a deployment, are
+// left to their owner to create. Every resource is reported in the task's
+// migration report; if any failed to copy, the task fails after the others
+// are copied.
+func (m *migrateApplication) Run() error {
+	err := m.ma.validate()
+	if err != nil {
+		return err
+	}
+
+	namespace := m.ma.targetNamespace()
+
+	source, _, err := m.client(m.ma.SourceAccount)
+	if err != nil {
+		return err
+	}
+
+	target, provider, err := m.client(m.ma.Account)
+	if err != nil {
+		return err
+	}
+
+	report := clouddriver.MigrationReport{
+		TaskID:          m.id,
+		Application:     m.ma.Application,
+		SourceAccount:   m.ma.SourceAccount,
+		SourceNamespace: m.ma.SourceNamespace,
+		TargetAccount:   m.ma.Account,
+		TargetNamespace: namespace,
+		CreatedAt:       time.Now(),
+	}
+
+	kinds := m.ma.Kinds
+	if len(kinds) == 0 {
+		kinds = DefaultMigrationKinds
+	}
+
+	for _, kind := range kinds {
+		list, err := source.ListResource(kind, metav1.ListOptions{
+			LabelSelector: kubernetes.ManagedApplicationLabelSelector(m.ma.Application),
+			FieldSelector: "metadata.namespace=" + m.ma.SourceNamespace,
+		})
+		if err != nil {
+			report.Add(kind, "", clouddriver.MigrationFailed, "error listing: "+err.Error())
+			continue
+		}
+
+		for _, u := range m.versions(list.Items, &report) {
+			u := u
+			m.copy(target, provider, &u, namespace, &report)
+		}
+	}
+
+	err = m.sc.CreateMigrationReport(report)
+	if err != nil {
+		return fmt.Errorf("error storing migration report: %w", err)
+	}
+
+	if report.Failed > 0 {
+		return migrationError(report)
+	}
+
+	return nil
+}
+
+// client returns a client of an account, which dry-runs changes if the
+// account is in dryRun write mode.
+func (m *migrateApplication) client(account string) (kubernetes.Client, kubernetes.Provider, error) {
+	provider, err := m.sc.GetKubernetesProvider(account)
+	if err != nil {
+		return nil, provider, fmt.Errorf("error getting account %s: %w", account, err)
+	}
+
+	cd, err := base64.StdEncoding.DecodeString(provider.CAData)
+	if err != nil {
+		return nil, provider, err
+	}
+
+	token, err := m.ac.Token()
+	if err != nil {
+		return nil, provider, err
+	}
+
+	config := &rest.Config{
+		Host:        provider.Host,
+		BearerToken: token,
+		TLSClientConfig: rest.TLSClientConfig{
+			CAData: cd,
+		},
+	}
+
+	client, err := kubernetes.NewProviderClient(m.kc, provider, config)
+	if err != nil {
+		return nil, provider, err
+	}
+
+	return client, provider, nil
+}
+
+// versions returns the resources to copy, reporting owned resources and,
+// when only the latest versions are copied, older versions as skipped.
+func (m *migrateApplication) versions(items []unstructured.Unstructured,
+	report *clouddriver.MigrationReport) []unstructured.Unstructured {
+	latest := map[string]int{}
+
+	for _, item := range items {
+		if group, sequence, ok := versionGroup(item); ok && sequence > latest[group] {
+			latest[group] = sequence
+		}
+	}
+
+	copied := []unstructured.Unstructured{}
+
+	for _, item := range items {
+		if refs := item.GetOwnerReferences(); len(refs) > 0 {
+			report.Add(item.GetKind(), item.GetName(), clouddriver.MigrationSkipped,
+				fmt.Sprintf("owned by %s %s", refs[0].Kind, refs[0].Name))
+			continue
+		}
+
+		if m.ma.Versions == MigrateVersionsLatest {
+			if group, sequence, ok := versionGroup(item); ok && sequence < latest[group] {
+				report.Add(item.GetKind(), item.GetName(), clouddriver.MigrationSkipped, "not the latest version")
+				continue
+			}
+		}
+
+		copied = append(copied, item)
+	}
+
+	sort.SliceStable(copied, func(i, j int) bool {
+		return copied[i].GetName() < copied[j].GetName()
+	})
+
+	return copied
+}
+
+// versionGroup returns the cluster of a versioned resource and its sequence.
+func versionGroup(u unstructured.Unstructured) (string, int, bool) {
+	annotations := u.GetAnnotations()
+
+	sequence, err := strconv.Atoi(annotations[kubernetes.AnnotationSpinnakerMonikerSequence])
+	if err != nil {
+		return "", 0, false
+	}
+
+	cluster := annotations[kubernetes.AnnotationSpinnakerMonikerCluster]
+	if cluster == "" {
+		cluster = annotations[kubernetes.AnnotationSpinnakerArtifactName]
+	}
+
+	return u.GetKind() + "/" + cluster, sequence, true
+}
+
+// copy applies a resource to the target namespace and reports the outcome.
+func (m *migrateApplication) copy(target kubernetes.Client, provider kubernetes.Provider,
+	u *unstructured.Unstructured, namespace string, report *clouddriver.MigrationReport) {
+	kind := u.GetKind()
+	name := u.GetName()
+
+	if strings.EqualFold(kind, "secret") {
+		switch m.ma.Secrets {
+		case MigrateSecretsCopy:
+		case MigrateSecretsEmpty:
+			emptySecret(u)
+		default:
+			report.Add(kind, name, clouddriver.MigrationSkipped, "secrets are skipped")
+			return
+		}
+	}
+
+	if !m.ma.Overwrite {
+		_, err := target.Get(strings.ToLower(kind), name, namespace)
+		if err == nil {
+			report.Add(kind, name, clouddriver.MigrationSkipped, "already exists in the target")
+			return
+		}
+
+		if !k8serrors.IsNotFound(err) {
+			report.Add(kind, name, clouddriver.MigrationFailed, "error getting the target: "+err.Error())
+			return
+		}
+	}
+
+	prepareMigratedResource(u, namespace)
+
+	meta, err := target.Apply(u)
+	if err != nil {
+		report.Add(kind, name, clouddriver.MigrationFailed, "error applying: "+err.Error())
+		return
+	}
+
+	manifest, err := dryRunManifest(provider, u)
+	if err != nil {
+		report.Add(kind, name, clouddriver.MigrationFailed, err.Error())
+		return
+	}
+
+	kr := kubernetes.Resource{
+		AccountName:  m.ma.Account,
+		ID:           uuid.New().String(),
+		TaskID:       m.id,
+		APIGroup:     meta.Group,
+		Name:         meta.Name,
+		Namespace:    meta.Namespace,
+		Resource:     meta.Resource,
+		Version:      meta.Version,
+		Kind:         meta.Kind,
+		SpinnakerApp: m.ma.Application,
+		Cluster:      cluster(meta.Kind, name),
+		DryRun:       provider.DryRun(),
+		Manifest:     manifest,
+	}
+
+	err = m.sc.CreateKubernetesResource(kr)
+	if err != nil {
+		report.Add(kind, name, clouddriver.MigrationFailed, "error recording: "+err.Error())
+		return
+	}
+
+	report.Add(kind, name, clouddriver.MigrationCopied, "")
+}
+
+// prepareMigratedResource removes what the source cluster set on a resource
+// and moves it to namespace.
+func prepareMigratedResource(u *unstructured.Unstructured, namespace string) {
+	u.Object = normalize.Options{KeepDefaults: true}.Normalize(u).Object
+	u.SetNamespace(namespace)
+
+	annotations := u.GetAnnotations()
+	if _, ok := annotations[kubernetes.AnnotationSpinnakerArtifactLocation]; ok {
+		annotations[kubernetes.AnnotationSpinnakerArtifactLocation] = namespace
+		u.SetAnnotations(annotations)
+	}
+}
+
+// emptySecret keeps the keys of a secret and empties their values.
+func emptySecret(u *unstructured.Unstructured) {
+	data, _, _ := unstructured.NestedMap(u.Object, "data")
+	for key := range data {
+		data[key] = ""
+	}
+
+	if data != nil {
+		_ = unstructured.SetNestedMap(u.Object, data, "data")
+	}
+
+	unstructured.RemoveNestedField(u.Object, "stringData")
+}
+
+// migrationError lists the resources a migration failed to copy.
+func migrationError(report clouddriver.MigrationReport) error {
+	failures := []string{}
+
+	for _, r := range report.Resources {
+		if r.Outcome == clouddriver.MigrationFailed {
+			failures = append(failures, strings.TrimSpace(r.Kind+" "+r.Name)+": "+r.Reason)
+		}
+	}
+
+	return fmt.Errorf("%d of %d resources failed to migrate: %s",
+		report.Failed, len(report.Resources), strings.Join(failures, "; "))
+}
+
+// validate returns an error if the request is missing what to migrate or
+// has an unknown option.
+func (ma *MigrateApplicationRequest) validate() error {
+	if ma.Application == "" || ma.SourceAccount == "" || ma.SourceNamespace == "" || ma.Account == "" {
+		return errors.New("application, sourceAccount, sourceNamespace and account are required")
+	}
+
+	if ma.SourceAccount == ma.Account && ma.SourceNamespace == ma.targetNamespace() {
+		return errors.New("the source and target of a migration must differ")
+	}
+
+	switch ma.Versions {
+	case "", MigrateVersionsAll, MigrateVersionsLatest:
+	default:
+		return fmt.Errorf("unknown versions %q, must be %s or %s", ma.Versions,
+			MigrateVersionsAll, MigrateVersionsLatest)
+	}
+
+	switch ma.Secrets {
+	case "", MigrateSecretsSkip, MigrateSecretsCopy, MigrateSecretsEmpty:
+	default:
+		return fmt.Errorf("unknown secrets %q, must be %s, %s or %s", ma.Secrets,
+			MigrateSecretsSkip, MigrateSecretsCopy, MigrateSecretsEmpty)
+	}
+
+	return nil
+}
+
+// targetNamespace returns the namespace resources are copied to, the
+// source namespace if none is given.
+func (ma *MigrateApplicationRequest) targetNamespace() string {
+	if ma.Namespace != "" {
+		return ma.Namespace
+	}
+
+	return ma.SourceNamespace
+}
@@ -0,0 +1,308 @@
+package kubernetes_test
+
+import (
+	"errors"
+
+	clouddriver "github.com/billiford/go-clouddriver/pkg"
+	. "github.com/billiford/go-clouddriver/pkg/http/core/kubernetes"
+	"github.com/billiford/go-clouddriver/pkg/kubernetes"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+var _ = Describe("MigrateApplication", func() {
+	var (
+		deployments  []unstructured.Unstructured
+		replicaSets  []unstructured.Unstructured
+		secrets      []unstructured.Unstructured
+		notFound     error
+		report       clouddriver.MigrationReport
+		newResource  func(kind, name string) unstructured.Unstructured
+		reportedWith func(outcome string) []clouddriver.MigratedResource
+	)
+
+	newResource = func(kind, name string) unstructured.Unstructured {
+		u := unstructured.Unstructured{Object: map[string]interface{}{
+			"status": map[string]interface{}{"replicas": int64(1)},
+		}}
+		u.SetKind(kind)
+		u.SetName(name)
+		u.SetNamespace("test-source-namespace")
+		u.SetUID("test-uid")
+		u.SetResourceVersion("100")
+		u.SetAnnotations(map[string]string{
+			kubernetes.AnnotationSpinnakerArtifactLocation: "test-source-namespace",
+			"deployment.kubernetes.io/revision":            "3",
+		})
+
+		return u
+	}
+
+	reportedWith = func(outcome string) []clouddriver.MigratedResource {
+		rs := []clouddriver.MigratedResource{}
+
+		for _, r := range report.Resources {
+			if r.Outcome == outcome {
+				rs = append(rs, r)
+			}
+		}
+
+		return rs
+	}
+
+	BeforeEach(func() {
+		setup()
+
+		actionConfig.Operation.MigrateApplication.Kinds = []string{"deployments", "replicaSets", "secrets"}
+		deployments = []unstructured.Unstructured{newResource("Deployment", "test-app")}
+
+		rs := newResource("ReplicaSet", "test-app-5d8f7")
+		rs.SetOwnerReferences([]metav1.OwnerReference{{Kind: "Deployment", Name: "test-app"}})
+		replicaSets = []unstructured.Unstructured{rs}
+
+		secret := newResource("Secret", "test-secret")
+		secret.Object["data"] = map[string]interface{}{"password": "aHVudGVyMg=="}
+		secrets = []unstructured.Unstructured{secret}
+
+		fakeKubeClient.ListResourceStub = func(kind string, lo metav1.ListOptions) (*unstructured.UnstructuredList, error) {
+			switch kind {
+			case "deployments":
+				return &unstructured.UnstructuredList{Items: deployments}, nil
+			case "replicaSets":
+				return &unstructured.UnstructuredList{Items: replicaSets}, nil
+			case "secrets":
+				return &unstructured.UnstructuredList{Items: secrets}, nil
+			}
+
+			return &unstructured.UnstructuredList{}, nil
+		}
+		notFound = k8serrors.NewNotFound(schema.GroupResource{}, "")
+		fakeKubeClient.GetReturns(nil, notFound)
+		fakeKubeClient.ApplyReturns(kubernetes.Metadata{Name: "test-app", Namespace: "test-namespace", Kind: "Deployment"}, nil)
+		fakeSQLClient.CreateMigrationReportStub = func(mr clouddriver.MigrationReport) error {
+			report = mr
+			return nil
+		}
+		report = clouddriver.MigrationReport{}
+	})
+
+	JustBeforeEach(func() {
+		action = actionHandler.NewMigrateApplicationAction(actionConfig)
+		err = action.Run()
+	})
+
+	When("the request is missing the source account", func() {
+		BeforeEach(func() {
+			actionConfig.Operation.MigrateApplication.SourceAccount = ""
+		})
+
+		It("returns an error", func() {
+			Expect(err).To(MatchError("application, sourceAccount, sourceNamespace and account are required"))
+		})
+	})
+
+	When("the source and target are the same", func() {
+		BeforeEach(func() {
+			actionConfig.Operation.MigrateApplication.SourceAccount = "test-account"
+			actionConfig.Operation.MigrateApplication.Namespace = "test-source-namespace"
+		})
+
+		It("returns an error", func() {
+			Expect(err).To(MatchError("the source and target of a migration must differ"))
+		})
+	})
+
+	When("the secrets option is unknown", func() {
+		BeforeEach(func() {
+			actionConfig.Operation.MigrateApplication.Secrets = "encrypt"
+		})
+
+		It("returns an error", func() {
+			Expect(err).To(MatchError(`unknown secrets "encrypt", must be skip, copy or empty`))
+		})
+	})
+
+	When("getting the source account returns an error", func() {
+		BeforeEach(func() {
+			fakeSQLClient.GetKubernetesProviderReturns(kubernetes.Provider{}, errors.New("error getting provider"))
+		})
+
+		It("returns an error", func() {
+			Expect(err).To(MatchError("error getting account test-source-account: error getting provider"))
+		})
+	})
+
+	When("storing the report returns an error", func() {
+		BeforeEach(func() {
+			fakeSQLClient.CreateMigrationReportStub = nil
+			fakeSQLClient.CreateMigrationReportReturns(errors.New("db down"))
+		})
+
+		It("returns an error", func() {
+			Expect(err).To(MatchError("error storing migration report: db down"))
+		})
+	})
+
+	When("a resource already exists in the target", func() {
+		BeforeEach(func() {
+			fakeKubeClient.GetReturns(&unstructured.Unstructured{}, nil)
+		})
+
+		It("skips it", func() {
+			Expect(err).To(BeNil())
+			Expect(fakeKubeClient.ApplyCallCount()).To(BeZero())
+			Expect(reportedWith(clouddriver.MigrationSkipped)).To(ContainElement(clouddriver.MigratedResource{
+				Kind:    "Deployment",
+				Name:    "test-app",
+				Outcome: clouddriver.MigrationSkipped,
+				Reason:  "already exists in the target",
+			}))
+		})
+
+		When("overwriting is enabled", func() {
+			BeforeEach(func() {
+				actionConfig.Operation.MigrateApplication.Overwrite = true
+			})
+
+			It("copies it", func() {
+				Expect(err).To(BeNil())
+				Expect(fakeKubeClient.GetCallCount()).To(BeZero())
+				Expect(fakeKubeClient.ApplyCallCount()).To(Equal(1))
+			})
+		})
+	})
+
+	When("applying a resource returns an error", func() {
+		BeforeEach(func() {
+			fakeKubeClient.ApplyReturns(kubernetes.Metadata{}, errors.New("forbidden"))
+		})
+
+		It("reports it and returns an error", func() {
+			Expect(err).To(MatchError("1 of 3 resources failed to migrate: Deployment test-app: error applying: forbidden"))
+			Expect(report.Failed).To(Equal(1))
+			Expect(fakeSQLClient.CreateKubernetesResourceCallCount()).To(BeZero())
+		})
+	})
+
+	When("listing a kind returns an error", func() {
+		BeforeEach(func() {
+			actionConfig.Operation.MigrateApplication.Kinds = []string{"deployments", "cronJobs"}
+			fakeKubeClient.ListResourceStub = func(kind string, lo metav1.ListOptions) (*unstructured.UnstructuredList, error) {
+				if kind == "cronJobs" {
+					return nil, errors.New("the server could not find the requested resource")
+				}
+
+				return &unstructured.UnstructuredList{Items: deployments}, nil
+			}
+		})
+
+		It("copies the other kinds and returns an error", func() {
+			Expect(err).To(MatchError("1 of 2 resources failed to migrate: cronJobs: " +
+				"error listing: the server could not find the requested resource"))
+			Expect(report.Copied).To(Equal(1))
+		})
+	})
+
+	When("only the latest versions are copied", func() {
+		BeforeEach(func() {
+			actionConfig.Operation.MigrateApplication.Versions = MigrateVersionsLatest
+			actionConfig.Operation.MigrateApplication.Kinds = []string{"replicaSets"}
+
+			replicaSets = nil
+			for _, v := range []string{"001", "002"} {
+				rs := newResource("ReplicaSet", "test-app-v"+v)
+				annotations := rs.GetAnnotations()
+				annotations[kubernetes.AnnotationSpinnakerMonikerCluster] = "replicaSet test-app"
+				annotations[kubernetes.AnnotationSpinnakerMonikerSequence] = v[2:]
+				rs.SetAnnotations(annotations)
+				replicaSets = append(replicaSets, rs)
+			}
+		})
+
+		It("skips older versions", func() {
+			Expect(err).To(BeNil())
+			Expect(fakeKubeClient.ApplyCallCount()).To(Equal(1))
+			Expect(fakeKubeClient.ApplyArgsForCall(0).GetName()).To(Equal("test-app-v002"))
+			Expect(reportedWith(clouddriver.MigrationSkipped)).To(Equal([]clouddriver.MigratedResource{
+				{
+					Kind:    "ReplicaSet",
+					Name:    "test-app-v001",
+					Outcome: clouddriver.MigrationSkipped,
+					Reason:  "not the latest version",
+				},
+			}))
+		})
+	})
+
+	When("secrets are copied empty", func() {
+		BeforeEach(func() {
+			actionConfig.Operation.MigrateApplication.Secrets = MigrateSecretsEmpty
+			actionConfig.Operation.MigrateApplication.Kinds = []string{"secrets"}
+		})
+
+		It("copies their keys without values", func() {
+			Expect(err).To(BeNil())
+			Expect(fakeKubeClient.ApplyCallCount()).To(Equal(1))
+			data, _, _ := unstructured.NestedMap(fakeKubeClient.ApplyArgsForCall(0).Object, "data")
+			Expect(data).To(Equal(map[string]interface{}{"password": ""}))
+		})
+	})
+
+	When("it succeeds", func() {
+		It("lists the application's resources in the source namespace", func() {
+			kind, lo := fakeKubeClient.ListResourceArgsForCall(0)
+			Expect(kind).To(Equal("deployments"))
+			Expect(lo.LabelSelector).To(Equal(kubernetes.ManagedApplicationLabelSelector("test-application")))
+			Expect(lo.FieldSelector).To(Equal("metadata.namespace=test-source-namespace"))
+		})
+
+		It("copies the resources to the target namespace", func() {
+			Expect(err).To(BeNil())
+			Expect(fakeKubeClient.ApplyCallCount()).To(Equal(1))
+			u := fakeKubeClient.ApplyArgsForCall(0)
+			Expect(u.GetName()).To(Equal("test-app"))
+			Expect(u.GetNamespace()).To(Equal("test-namespace"))
+			Expect(u.GetUID()).To(BeEmpty())
+			Expect(u.GetResourceVersion()).To(BeEmpty())
+			Expect(u.Object).ToNot(HaveKey("status"))
+			Expect(u.GetAnnotations()).To(Equal(map[string]string{
+				kubernetes.AnnotationSpinnakerArtifactLocation: "test-namespace",
+			}))
+		})
+
+		It("records the copied resources for the task", func() {
+			Expect(fakeSQLClient.CreateKubernetesResourceCallCount()).To(Equal(1))
+			kr := fakeSQLClient.CreateKubernetesResourceArgsForCall(0)
+			Expect(kr.AccountName).To(Equal("test-account"))
+			Expect(kr.TaskID).To(Equal("test-id"))
+			Expect(kr.SpinnakerApp).To(Equal("test-application"))
+		})
+
+		It("reports every resource", func() {
+			Expect(report.TaskID).To(Equal("test-id"))
+			Expect(report.SourceAccount).To(Equal("test-source-account"))
+			Expect(report.TargetNamespace).To(Equal("test-namespace"))
+			Expect(report.Copied).To(Equal(1))
+			Expect(report.Skipped).To(Equal(2))
+			Expect(report.Failed).To(BeZero())
+			Expect(reportedWith(clouddriver.MigrationSkipped)).To(Equal([]clouddriver.MigratedResource{
+				{
+					Kind:    "ReplicaSet",
+					Name:    "test-app-5d8f7",
+					Outcome: clouddriver.MigrationSkipped,
+					Reason:  "owned by Deployment test-app",
+				},
+				{
+					Kind:    "Secret",
+					Name:    "test-secret",
+					Outcome: clouddriver.MigrationSkipped,
+					Reason:  "secrets are skipped",
+				},
+			}))
+		})
+	})
+})
@@ -1,6 +1,20 @@
 package kubernetes
 
-import "github.com/billiford/go-clouddriver/pkg/kubernetes/manifest"
+import (
+	"regexp"
+	"strings"
+
+	clouddriver "github.com/billiford/go-clouddriver/pkg"
+	"github.com/billiford/go-clouddriver/pkg/artifact"
+	"github.com/billiford/go-clouddriver/pkg/kubernetes/manifest"
+	"github.com/billiford/go-clouddriver/pkg/lock"
+	"github.com/billiford/go-clouddriver/pkg/queue"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+// Versions of a resource, such as replicaSet my-app-v002, belong to the
+// cluster of its unversioned name.
+var versionSuffix = regexp.MustCompile(`-v\d{3,}$`)
 
 type OperationsResponse struct {
 	ID          string `json:"id"`
@@ -9,6 +23,22 @@ type OperationsResponse struct {
 
 type Operations []Operation
 
+// SupportedOperations lists the operations Operation can hold,
+// by the name Orca sends them under.
+var SupportedOperations = []string{
+	"deployManifest",
+	"scaleManifest",
+	"cleanupArtifacts",
+	"deleteManifest",
+	"undoRolloutManifest",
+	"rollingRestartManifest",
+	"patchManifest",
+	"runJob",
+	"createApplication",
+	"deleteApplication",
+	"migrateApplication",
+}
+
 type Operation struct {
 	DeployManifest         *DeployManifestRequest         `json:"deployManifest"`
 	ScaleManifest          *ScaleManifestRequest          `json:"scaleManifest"`
@@ -18,6 +48,246 @@ type Operation struct {
 	RollingRestartManifest *RollingRestartManifestRequest `json:"rollingRestartManifest"`
 	PatchManifest          *PatchManifestRequest          `json:"patchManifest"`
 	RunJob                 *RunJobRequest                 `json:"runJob"`
+	CreateApplication      *CreateApplicationRequest      `json:"createApplication"`
+	DeleteApplication      *DeleteApplicationRequest      `json:"deleteApplication"`
+	MigrateApplication     *MigrateApplicationRequest     `json:"migrateApplication"`
+}
+
+// Account returns the account an operation changes, which is empty for
+// operations that do not change an account, such as createApplication.
+func (o Operation) Account() string {
+	switch {
+	case o.DeployManifest != nil:
+		return o.DeployManifest.Account
+	case o.ScaleManifest != nil:
+		return o.ScaleManifest.Account
+	case o.CleanupArtifacts != nil:
+		return o.CleanupArtifacts.Account
+	case o.DeleteManifest != nil:
+		return o.DeleteManifest.Account
+	case o.UndoRolloutManifest != nil:
+		return o.UndoRolloutManifest.Account
+	case o.RollingRestartManifest != nil:
+		return o.RollingRestartManifest.Account
+	case o.PatchManifest != nil:
+		return o.PatchManifest.Account
+	case o.RunJob != nil:
+		return o.RunJob.Account
+	case o.MigrateApplication != nil:
+		return o.MigrateApplication.Account
+	default:
+		return ""
+	}
+}
+
+// ChangedApplication returns the application whose metadata and permissions
+// an operation changes, which is only set for createApplication and
+// deleteApplication.
+func (o Operation) ChangedApplication() (string, bool) {
+	switch {
+	case o.CreateApplication != nil:
+		return o.CreateApplication.Application.Name, true
+	case o.DeleteApplication != nil:
+		return o.DeleteApplication.Application.Name, true
+	default:
+		return "", false
+	}
+}
+
+// TargetCluster returns the cluster of its account an operation changes,
+// which is empty for the primary cluster.
+func (o Operation) TargetCluster() string {
+	switch {
+	case o.DeployManifest != nil:
+		return o.DeployManifest.TargetCluster
+	case o.ScaleManifest != nil:
+		return o.ScaleManifest.TargetCluster
+	case o.CleanupArtifacts != nil:
+		return o.CleanupArtifacts.TargetCluster
+	case o.DeleteManifest != nil:
+		return o.DeleteManifest.TargetCluster
+	case o.UndoRolloutManifest != nil:
+		return o.UndoRolloutManifest.TargetCluster
+	case o.RollingRestartManifest != nil:
+		return o.RollingRestartManifest.TargetCluster
+	case o.PatchManifest != nil:
+		return o.PatchManifest.TargetCluster
+	case o.RunJob != nil:
+		return o.RunJob.TargetCluster
+	default:
+		return ""
+	}
+}
+
+// Namespaces returns the namespaces an operation changes. Manifests
+// without a namespace are deployed to "default".
+func (o Operation) Namespaces() []string {
+	switch {
+	case o.DeployManifest != nil:
+		if o.DeployManifest.NamespaceOverride != "" {
+			return []string{o.DeployManifest.NamespaceOverride}
+		}
+
+		return manifestNamespaces(o.DeployManifest.Manifests)
+	case o.ScaleManifest != nil:
+		return []string{o.ScaleManifest.Location}
+	case o.CleanupArtifacts != nil:
+		return manifestNamespaces(o.CleanupArtifacts.Manifests)
+	case o.DeleteManifest != nil:
+		return []string{o.DeleteManifest.Location}
+	case o.UndoRolloutManifest != nil:
+		return []string{o.UndoRolloutManifest.Location}
+	case o.RollingRestartManifest != nil:
+		return []string{o.RollingRestartManifest.Location}
+	case o.PatchManifest != nil:
+		return []string{o.PatchManifest.Location}
+	case o.RunJob != nil:
+		return manifestNamespaces([]map[string]interface{}{o.RunJob.Manifest})
+	case o.MigrateApplication != nil:
+		return []string{o.MigrateApplication.targetNamespace()}
+	default:
+		return nil
+	}
+}
+
+func manifestNamespaces(manifests []map[string]interface{}) []string {
+	namespaces := []string{}
+	seen := map[string]bool{}
+
+	for _, m := range manifests {
+		namespace, _, _ := unstructured.NestedString(m, "metadata", "namespace")
+		if namespace == "" {
+			namespace = "default"
+		}
+
+		if !seen[namespace] {
+			seen[namespace] = true
+			namespaces = append(namespaces, namespace)
+		}
+	}
+
+	return namespaces
+}
+
+// Name returns the name Orca sends an operation under.
+func (o Operation) Name() string {
+	switch {
+	case o.DeployManifest != nil:
+		return "deployManifest"
+	case o.ScaleManifest != nil:
+		return "scaleManifest"
+	case o.CleanupArtifacts != nil:
+		return "cleanupArtifacts"
+	case o.DeleteManifest != nil:
+		return "deleteManifest"
+	case o.UndoRolloutManifest != nil:
+		return "undoRolloutManifest"
+	case o.RollingRestartManifest != nil:
+		return "rollingRestartManifest"
+	case o.PatchManifest != nil:
+		return "patchManifest"
+	case o.RunJob != nil:
+		return "runJob"
+	case o.CreateApplication != nil:
+		return "createApplication"
+	case o.DeleteApplication != nil:
+		return "deleteApplication"
+	case o.MigrateApplication != nil:
+		return "migrateApplication"
+	default:
+		return ""
+	}
+}
+
+// Manifests returns the manifests a deploy or job applies, or nil for
+// other operations.
+func (o Operation) Manifests() []map[string]interface{} {
+	switch {
+	case o.DeployManifest != nil:
+		return o.DeployManifest.Manifests
+	case o.RunJob != nil:
+		return []map[string]interface{}{o.RunJob.Manifest}
+	default:
+		return nil
+	}
+}
+
+// SetManifests replaces the manifests a deploy or job applies. A job
+// applies the first manifest.
+func (o Operation) SetManifests(manifests []map[string]interface{}) {
+	switch {
+	case o.DeployManifest != nil:
+		o.DeployManifest.Manifests = manifests
+	case o.RunJob != nil && len(manifests) > 0:
+		o.RunJob.Manifest = manifests[0]
+	}
+}
+
+// Priority returns the class the operation is queued under, rollbacks
+// before everything else.
+func (o Operation) Priority() queue.Priority {
+	if o.UndoRolloutManifest != nil {
+		return queue.PriorityRollback
+	}
+
+	return queue.PriorityDeploy
+}
+
+// LockKeys returns the keys of the locks of the clusters an operation
+// changes, such as "my-account/default/deployment my-app". Operations
+// that change no cluster, such as runJob, return none.
+func (o Operation) LockKeys() []string {
+	keys := []string{}
+	account := o.Account()
+
+	switch {
+	case o.DeployManifest != nil:
+		for _, m := range o.DeployManifest.Manifests {
+			kind, _, _ := unstructured.NestedString(m, "kind")
+			name, _, _ := unstructured.NestedString(m, "metadata", "name")
+
+			namespace := o.DeployManifest.NamespaceOverride
+			if namespace == "" {
+				namespace, _, _ = unstructured.NestedString(m, "metadata", "namespace")
+			}
+
+			if namespace == "" {
+				namespace = "default"
+			}
+
+			if c := cluster(kind, name); c != "" {
+				keys = append(keys, lock.Key(account, namespace, c))
+			}
+		}
+	case o.ScaleManifest != nil:
+		keys = appendLockKey(keys, account, o.ScaleManifest.Location, o.ScaleManifest.ManifestName)
+	case o.DeleteManifest != nil:
+		keys = appendLockKey(keys, account, o.DeleteManifest.Location, o.DeleteManifest.ManifestName)
+	case o.UndoRolloutManifest != nil:
+		keys = appendLockKey(keys, account, o.UndoRolloutManifest.Location, o.UndoRolloutManifest.ManifestName)
+	case o.RollingRestartManifest != nil:
+		keys = appendLockKey(keys, account, o.RollingRestartManifest.Location, o.RollingRestartManifest.ManifestName)
+	case o.PatchManifest != nil:
+		keys = appendLockKey(keys, account, o.PatchManifest.Location, o.PatchManifest.ManifestName)
+	}
+
+	return keys
+}
+
+// appendLockKey appends the key of the cluster of a manifest name such as
+// "replicaSet my-app-v001".
+func appendLockKey(keys []string, account, namespace, manifestName string) []string {
+	a := strings.SplitN(manifestName, " ", 2)
+	if len(a) != 2 {
+		return keys
+	}
+
+	c := cluster(a[0], versionSuffix.ReplaceAllString(a[1], ""))
+	if c == "" {
+		return keys
+	}
+
+	return append(keys, lock.Key(account, namespace, c))
 }
 
 type DeployManifestRequest struct {
@@ -37,8 +307,52 @@ type DeployManifestRequest struct {
 	} `json:"moniker"`
 	Source                   string        `json:"source"`
 	Account                  string        `json:"account"`
+	TargetCluster            string        `json:"targetCluster,omitempty"`
 	SkipExpressionEvaluation bool          `json:"skipExpressionEvaluation"`
 	RequiredArtifacts        []interface{} `json:"requiredArtifacts"`
+	// Values of ${name} placeholders in the manifests from the stage context,
+	// if the account substitutes them.
+	TemplateValues map[string]string `json:"templateValues,omitempty"`
+	// ConfigMaps and secrets to create from the contents of artifacts.
+	FromArtifacts []DeployManifestRequestFromArtifacts `json:"fromArtifacts,omitempty"`
+	// ManifestArtifact is an http/file artifact of YAML or JSON manifests to
+	// deploy with Manifests, such as a GitHub raw link.
+	ManifestArtifact *DeployManifestRequestArtifact `json:"manifestArtifact,omitempty"`
+	// ContinueOnError applies the other manifests when one fails to apply,
+	// instead of skipping the manifests after it.
+	ContinueOnError bool `json:"continueOnError,omitempty"`
+	// RollbackOnError deletes the resources the deploy created and restores
+	// the ones it replaced when a manifest fails to apply.
+	RollbackOnError bool `json:"rollbackOnError,omitempty"`
+}
+
+// DeployManifestRequestArtifact is an artifact of manifests to deploy.
+type DeployManifestRequestArtifact struct {
+	artifact.Artifact
+	// SHA256 is the hex checksum the contents of the artifact must have, if set.
+	SHA256 string `json:"sha256,omitempty"`
+}
+
+// DeployManifestRequestFromArtifacts creates a ConfigMap or Secret with a key
+// per artifact, like `kubectl create configmap --from-file`.
+type DeployManifestRequestFromArtifacts struct {
+	// ConfigMap or Secret.
+	Kind      string `json:"kind"`
+	Name      string `json:"name"`
+	Namespace string `json:"namespace"`
+	// Type of a secret, defaults to Opaque.
+	Type string `json:"type,omitempty"`
+	// Versioned resources are named after their version, such as
+	// my-config-v001. Defaults to true, as Spinnaker versions ConfigMaps
+	// and Secrets so workloads roll out when they change.
+	Versioned *bool                       `json:"versioned,omitempty"`
+	Files     []DeployManifestRequestFile `json:"files"`
+}
+
+type DeployManifestRequestFile struct {
+	// Key of the file's contents, defaults to the base name of the artifact.
+	Key      string            `json:"key,omitempty"`
+	Artifact artifact.Artifact `json:"artifact"`
 }
 
 type PatchManifestRequest struct {
@@ -54,8 +368,9 @@ type PatchManifestRequest struct {
 	AllArtifacts  []PatchManifestRequestArtifact `json:"allArtifacts"`
 	Options       PatchManifestRequestOptions    `json:"options"`
 	// Manifests         []map[string]interface{}       `json:"manifests"`
-	Location string `json:"location"`
-	Account  string `json:"account"`
+	Location      string `json:"location"`
+	Account       string `json:"account"`
+	TargetCluster string `json:"targetCluster,omitempty"`
 	// RequiredArtifacts []interface{}                  `json:"requiredArtifacts"`
 }
 
@@ -109,11 +424,48 @@ type ScaleManifestRequest struct {
 	Location      string `json:"location"`
 	User          string `json:"user"`
 	Account       string `json:"account"`
+	TargetCluster string `json:"targetCluster,omitempty"`
+	// OverridePinned allows scaling a pinned server group to zero.
+	OverridePinned bool `json:"overridePinned,omitempty"`
+}
+
+// CreateApplicationRequest creates or updates the metadata of an application.
+type CreateApplicationRequest struct {
+	Application clouddriver.Application `json:"application"`
+}
+
+// DeleteApplicationRequest deletes the metadata of an application.
+type DeleteApplicationRequest struct {
+	Application struct {
+		Name string `json:"name"`
+	} `json:"application"`
+}
+
+// MigrateApplicationRequest copies the resources Spinnaker manages for an
+// application from one account and namespace to another, the account of the
+// operation.
+type MigrateApplicationRequest struct {
+	Application     string `json:"application"`
+	SourceAccount   string `json:"sourceAccount"`
+	SourceNamespace string `json:"sourceNamespace"`
+	Account         string `json:"account"`
+	// Namespace to copy to, defaults to the source namespace.
+	Namespace string `json:"namespace,omitempty"`
+	// Kinds to copy, defaults to DefaultMigrationKinds.
+	Kinds []string `json:"kinds,omitempty"`
+	// Versions of versioned resources to copy, all or latest. Defaults to all.
+	Versions string `json:"versions,omitempty"`
+	// How to copy secrets: skip, copy, or empty to copy their keys only.
+	// Defaults to skip.
+	Secrets string `json:"secrets,omitempty"`
+	// Overwrite resources that exist in the target, which are skipped otherwise.
+	Overwrite bool `json:"overwrite,omitempty"`
 }
 
 type CleanupArtifactsRequest struct {
-	Manifests []map[string]interface{} `json:"manifests"`
-	Account   string                   `json:"account"`
+	Manifests     []map[string]interface{} `json:"manifests"`
+	Account       string                   `json:"account"`
+	TargetCluster string                   `json:"targetCluster,omitempty"`
 }
 
 type DeleteManifestRequest struct {
@@ -126,6 +478,9 @@ type DeleteManifestRequest struct {
 	Location       string                              `json:"location"`
 	User           string                              `json:"user"`
 	Account        string                              `json:"account"`
+	TargetCluster  string                              `json:"targetCluster,omitempty"`
+	// OverridePinned allows deleting a pinned server group.
+	OverridePinned bool `json:"overridePinned,omitempty"`
 }
 
 type DeleteManifestRequestLabelSelectors struct {
@@ -149,6 +504,7 @@ type UndoRolloutManifestRequest struct {
 	Location      string `json:"location"`
 	User          string `json:"user"`
 	Account       string `json:"account"`
+	TargetCluster string `json:"targetCluster,omitempty"`
 	Revision      string `json:"revision"`
 }
 
@@ -158,10 +514,12 @@ type RollingRestartManifestRequest struct {
 	Location      string `json:"location"`
 	User          string `json:"user"`
 	Account       string `json:"account"`
+	TargetCluster string `json:"targetCluster,omitempty"`
 }
 
 type RunJobRequest struct {
 	Account       string                 `json:"account"`
+	TargetCluster string                 `json:"targetCluster,omitempty"`
 	Alias         string                 `json:"alias"`
 	Application   string                 `json:"application"`
 	CloudProvider string                 `json:"cloudProvider"`
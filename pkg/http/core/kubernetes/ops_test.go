@@ -0,0 +1,234 @@
+package kubernetes_test
+
+import (
+	. "github.com/billiford/go-clouddriver/pkg/http/core/kubernetes"
+	"github.com/billiford/go-clouddriver/pkg/queue"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Operation", func() {
+	var o Operation
+
+	Describe("#Account", func() {
+		When("the operation changes an account", func() {
+			BeforeEach(func() {
+				o = Operation{ScaleManifest: &ScaleManifestRequest{Account: "test-account"}}
+			})
+
+			It("returns the account", func() {
+				Expect(o.Account()).To(Equal("test-account"))
+			})
+		})
+
+		When("the operation does not change an account", func() {
+			BeforeEach(func() {
+				o = Operation{CreateApplication: &CreateApplicationRequest{}}
+			})
+
+			It("returns an empty string", func() {
+				Expect(o.Account()).To(BeEmpty())
+			})
+		})
+	})
+
+	Describe("#ChangedApplication", func() {
+		When("the operation creates an application", func() {
+			BeforeEach(func() {
+				o = Operation{CreateApplication: &CreateApplicationRequest{}}
+				o.CreateApplication.Application.Name = "test-application"
+			})
+
+			It("returns the application", func() {
+				application, ok := o.ChangedApplication()
+				Expect(ok).To(BeTrue())
+				Expect(application).To(Equal("test-application"))
+			})
+		})
+
+		When("the operation does not change an application", func() {
+			BeforeEach(func() {
+				o = Operation{ScaleManifest: &ScaleManifestRequest{Account: "test-account"}}
+			})
+
+			It("returns false", func() {
+				_, ok := o.ChangedApplication()
+				Expect(ok).To(BeFalse())
+			})
+		})
+	})
+
+	Describe("#Namespaces", func() {
+		When("the operation has a location", func() {
+			BeforeEach(func() {
+				o = Operation{DeleteManifest: &DeleteManifestRequest{Location: "test-namespace"}}
+			})
+
+			It("returns the location", func() {
+				Expect(o.Namespaces()).To(Equal([]string{"test-namespace"}))
+			})
+		})
+
+		When("a deploy overrides the namespace", func() {
+			BeforeEach(func() {
+				o = Operation{DeployManifest: &DeployManifestRequest{
+					NamespaceOverride: "test-namespace",
+					Manifests: []map[string]interface{}{
+						{"metadata": map[string]interface{}{"namespace": "other-namespace"}},
+					},
+				}}
+			})
+
+			It("returns the override", func() {
+				Expect(o.Namespaces()).To(Equal([]string{"test-namespace"}))
+			})
+		})
+
+		When("a deploy does not override the namespace", func() {
+			BeforeEach(func() {
+				o = Operation{DeployManifest: &DeployManifestRequest{
+					Manifests: []map[string]interface{}{
+						{"metadata": map[string]interface{}{"namespace": "test-namespace"}},
+						{"metadata": map[string]interface{}{}},
+						{"metadata": map[string]interface{}{"namespace": "test-namespace"}},
+					},
+				}}
+			})
+
+			It("returns the namespaces of the manifests once each", func() {
+				Expect(o.Namespaces()).To(Equal([]string{"test-namespace", "default"}))
+			})
+		})
+
+		When("a migration does not name the target namespace", func() {
+			BeforeEach(func() {
+				o = Operation{MigrateApplication: &MigrateApplicationRequest{SourceNamespace: "test-namespace"}}
+			})
+
+			It("returns the source namespace", func() {
+				Expect(o.Namespaces()).To(Equal([]string{"test-namespace"}))
+			})
+		})
+	})
+
+	Describe("#Name", func() {
+		It("returns the name of the operation", func() {
+			o = Operation{RollingRestartManifest: &RollingRestartManifestRequest{}}
+			Expect(o.Name()).To(Equal("rollingRestartManifest"))
+			o = Operation{}
+			Expect(o.Name()).To(BeEmpty())
+		})
+	})
+
+	Describe("#SetManifests", func() {
+		var manifests []map[string]interface{}
+
+		BeforeEach(func() {
+			manifests = []map[string]interface{}{
+				{"kind": "Job"},
+				{"kind": "ConfigMap"},
+			}
+		})
+
+		When("the operation is a deploy", func() {
+			BeforeEach(func() {
+				o = Operation{DeployManifest: &DeployManifestRequest{}}
+			})
+
+			It("replaces the manifests", func() {
+				o.SetManifests(manifests)
+				Expect(o.Manifests()).To(Equal(manifests))
+			})
+		})
+
+		When("the operation is a job", func() {
+			BeforeEach(func() {
+				o = Operation{RunJob: &RunJobRequest{}}
+			})
+
+			It("replaces the manifest with the first", func() {
+				o.SetManifests(manifests)
+				Expect(o.Manifests()).To(Equal(manifests[:1]))
+			})
+		})
+
+		When("the operation has no manifests", func() {
+			BeforeEach(func() {
+				o = Operation{ScaleManifest: &ScaleManifestRequest{}}
+			})
+
+			It("does nothing", func() {
+				o.SetManifests(manifests)
+				Expect(o.Manifests()).To(BeNil())
+			})
+		})
+	})
+
+	Describe("#Priority", func() {
+		It("queues rollbacks first", func() {
+			o = Operation{UndoRolloutManifest: &UndoRolloutManifestRequest{}}
+			Expect(o.Priority()).To(Equal(queue.PriorityRollback))
+			o = Operation{DeployManifest: &DeployManifestRequest{}}
+			Expect(o.Priority()).To(Equal(queue.PriorityDeploy))
+		})
+	})
+	Describe("#LockKeys", func() {
+		When("the operation is a deploy", func() {
+			BeforeEach(func() {
+				o = Operation{DeployManifest: &DeployManifestRequest{
+					Account: "test-account",
+					Manifests: []map[string]interface{}{
+						{
+							"kind":     "Deployment",
+							"metadata": map[string]interface{}{"name": "my-app", "namespace": "test-namespace"},
+						},
+						{
+							"kind":     "Service",
+							"metadata": map[string]interface{}{"name": "my-app"},
+						},
+						{
+							"kind":     "ConfigMap",
+							"metadata": map[string]interface{}{"name": "my-config"},
+						},
+					},
+				}}
+			})
+
+			It("returns the clusters of its manifests", func() {
+				Expect(o.LockKeys()).To(Equal([]string{
+					"test-account/test-namespace/deployment my-app",
+					"test-account/default/service my-app",
+				}))
+			})
+		})
+
+		When("the operation names a version of a resource", func() {
+			BeforeEach(func() {
+				o = Operation{ScaleManifest: &ScaleManifestRequest{
+					Account:      "test-account",
+					Location:     "test-namespace",
+					ManifestName: "replicaSet my-app-v002",
+				}}
+			})
+
+			It("returns the cluster of the unversioned name", func() {
+				Expect(o.LockKeys()).To(Equal([]string{"test-account/test-namespace/replicaSet my-app"}))
+			})
+		})
+
+		When("the operation changes no cluster", func() {
+			BeforeEach(func() {
+				o = Operation{DeleteManifest: &DeleteManifestRequest{
+					Account:      "test-account",
+					Location:     "test-namespace",
+					ManifestName: "configMap my-config",
+				}}
+			})
+
+			It("returns no keys", func() {
+				Expect(o.LockKeys()).To(BeEmpty())
+			})
+		})
+	})
+})
@@ -1,6 +1,7 @@
 package kubernetes
 
 import (
+	"context"
 	"encoding/base64"
 	"encoding/json"
 	"fmt"
@@ -8,6 +9,7 @@ import (
 
 	"github.com/billiford/go-clouddriver/pkg/arcade"
 	"github.com/billiford/go-clouddriver/pkg/kubernetes"
+	"github.com/billiford/go-clouddriver/pkg/kubernetes/verify"
 	"github.com/billiford/go-clouddriver/pkg/sql"
 	"github.com/google/uuid"
 	"k8s.io/apimachinery/pkg/types"
@@ -16,20 +18,26 @@ import (
 
 func (ah *actionHandler) NewPatchManifestAction(ac ActionConfig) Action {
 	return &patchManfest{
-		ac: ac.ArcadeClient,
-		sc: ac.SQLClient,
-		kc: ac.KubeController,
-		id: ac.ID,
-		pm: ac.Operation.PatchManifest,
+		ac:  ac.ArcadeClient,
+		sc:  ac.SQLClient,
+		kc:  ac.KubeController,
+		id:  ac.ID,
+		ctx: ac.ctx(),
+		pm:  ac.Operation.PatchManifest,
+
+		verifier: ah.config.Verifier,
 	}
 }
 
 type patchManfest struct {
-	ac arcade.Client
-	sc sql.Client
-	kc kubernetes.Controller
-	id string
-	pm *PatchManifestRequest
+	ac  arcade.Client
+	sc  sql.Client
+	kc  kubernetes.Controller
+	id  string
+	ctx context.Context
+	pm  *PatchManifestRequest
+
+	verifier verify.Verifier
 }
 
 func (p *patchManfest) Run() error {
@@ -56,12 +64,7 @@ func (p *patchManfest) Run() error {
 		},
 	}
 
-	client, err := p.kc.NewClient(config)
-	if err != nil {
-		return err
-	}
-
-	b, err := json.Marshal(p.pm.PatchBody)
+	client, err := kubernetes.NewProviderClient(p.kc, provider, config)
 	if err != nil {
 		return err
 	}
@@ -91,7 +94,22 @@ func (p *patchManfest) Run() error {
 		return fmt.Errorf("invalid merge strategy %s", p.pm.Options.MergeStrategy)
 	}
 
-	meta, _, err := client.PatchUsingStrategy(kind, name, p.pm.Location, b, strategy)
+	err = p.verify(kind)
+	if err != nil {
+		return err
+	}
+
+	b, err := json.Marshal(p.pm.PatchBody)
+	if err != nil {
+		return err
+	}
+
+	meta, patched, err := client.PatchUsingStrategy(kind, name, p.pm.Location, b, strategy)
+	if err != nil {
+		return err
+	}
+
+	manifest, err := dryRunManifest(provider, patched)
 	if err != nil {
 		return err
 	}
@@ -111,6 +129,8 @@ func (p *patchManfest) Run() error {
 		Version:      meta.Version,
 		Kind:         meta.Kind,
 		SpinnakerApp: p.pm.App,
+		DryRun:       provider.DryRun(),
+		Manifest:     manifest,
 	}
 
 	err = p.sc.CreateKubernetesResource(kr)
@@ -120,3 +140,22 @@ func (p *patchManfest) Run() error {
 
 	return nil
 }
+
+// verify verifies the images the patch body sets before patching, pinning
+// them to the verified digests. The body is verified as a manifest of the
+// kind it patches, as merge patches have the structure of the manifest.
+func (p *patchManfest) verify(kind string) error {
+	if p.verifier == nil || p.pm.PatchBody == nil {
+		return nil
+	}
+
+	// The containers of the body are shared with the manifest, so they are pinned in the body.
+	manifest := map[string]interface{}{}
+	for k, v := range p.pm.PatchBody {
+		manifest[k] = v
+	}
+
+	manifest["kind"] = kind
+
+	return p.verifier.Verify(p.ctx, p.pm.Account, []map[string]interface{}{manifest})
+}
@@ -1,11 +1,16 @@
 package kubernetes_test
 
 import (
+	"context"
+	"encoding/json"
 	"errors"
 
+	. "github.com/billiford/go-clouddriver/pkg/http/core/kubernetes"
 	"github.com/billiford/go-clouddriver/pkg/kubernetes"
+	"github.com/billiford/go-clouddriver/pkg/kubernetes/verify/verifyfakes"
 	. "github.com/onsi/ginkgo"
 	. "github.com/onsi/gomega"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 )
 
 var _ = Describe("Patch", func() {
@@ -84,6 +89,79 @@ var _ = Describe("Patch", func() {
 		})
 	})
 
+	When("the account is in dryRun write mode", func() {
+		BeforeEach(func() {
+			fakeSQLClient.GetKubernetesProviderReturns(kubernetes.Provider{
+				Name:      "test-account",
+				Host:      "http://localhost",
+				WriteMode: kubernetes.WriteModeDryRun,
+			}, nil)
+			fakeKubeClient.PatchUsingStrategyReturns(kubernetes.Metadata{}, &unstructured.Unstructured{
+				Object: map[string]interface{}{"kind": "Deployment"},
+			}, nil)
+		})
+
+		It("records the patched manifest", func() {
+			Expect(err).To(BeNil())
+			Expect(fakeKubeController.NewClientArgsForCall(0).WrapTransport).ToNot(BeNil())
+			kr := fakeSQLClient.CreateKubernetesResourceArgsForCall(0)
+			Expect(kr.DryRun).To(BeTrue())
+			Expect(kr.Manifest).To(Equal(`{"kind":"Deployment"}`))
+		})
+	})
+
+	Context("the account verifies images", func() {
+		var fakeVerifier *verifyfakes.FakeVerifier
+
+		BeforeEach(func() {
+			actionConfig.Operation.PatchManifest.Account = "test-account"
+			actionConfig.Operation.PatchManifest.PatchBody = map[string]interface{}{
+				"spec": map[string]interface{}{
+					"template": map[string]interface{}{
+						"spec": map[string]interface{}{
+							"containers": []interface{}{
+								map[string]interface{}{"name": "app", "image": "nginx:1.19"},
+							},
+						},
+					},
+				},
+			}
+			fakeVerifier = &verifyfakes.FakeVerifier{}
+			fakeVerifier.VerifyStub = func(ctx context.Context, account string, manifests []map[string]interface{}) error {
+				containers, _, _ := unstructured.NestedFieldNoCopy(manifests[0], "spec", "template", "spec", "containers")
+				containers.([]interface{})[0].(map[string]interface{})["image"] = "nginx@sha256:1e2b"
+				return nil
+			}
+			actionHandler = NewActionHandlerWithConfig(ActionHandlerConfig{Verifier: fakeVerifier})
+		})
+
+		When("an image fails verification", func() {
+			BeforeEach(func() {
+				fakeVerifier.VerifyStub = nil
+				fakeVerifier.VerifyReturns(errors.New("error verifying image nginx:1.19: exit status 1: Error: no matching signatures"))
+			})
+
+			It("returns the verification error without patching", func() {
+				Expect(err).ToNot(BeNil())
+				Expect(err.Error()).To(Equal("error verifying image nginx:1.19: exit status 1: Error: no matching signatures"))
+				Expect(fakeKubeClient.PatchUsingStrategyCallCount()).To(BeZero())
+			})
+		})
+
+		It("verifies the body as a manifest of the patched kind and patches the pinned images", func() {
+			Expect(err).To(BeNil())
+			_, account, manifests := fakeVerifier.VerifyArgsForCall(0)
+			Expect(account).To(Equal("test-account"))
+			Expect(manifests[0]["kind"]).To(Equal("deployment"))
+			_, _, _, b, _ := fakeKubeClient.PatchUsingStrategyArgsForCall(0)
+			body := map[string]interface{}{}
+			Expect(json.Unmarshal(b, &body)).To(Succeed())
+			Expect(body).ToNot(HaveKey("kind"))
+			containers, _, _ := unstructured.NestedSlice(body, "spec", "template", "spec", "containers")
+			Expect(containers[0].(map[string]interface{})["image"]).To(Equal("nginx@sha256:1e2b"))
+		})
+	})
+
 	Context("merge strategies", func() {
 		Context("strategic patch type", func() {
 			BeforeEach(func() {
@@ -56,7 +56,7 @@ func (rr *rollingRestart) Run() error {
 		},
 	}
 
-	client, err := rr.kc.NewClient(config)
+	client, err := kubernetes.NewProviderClient(rr.kc, provider, config)
 	if err != nil {
 		return err
 	}
@@ -89,7 +89,7 @@ func (r *rollback) Run() error {
 		},
 	}
 
-	client, err := r.kc.NewClient(config)
+	client, err := kubernetes.NewProviderClient(r.kc, provider, config)
 	if err != nil {
 		return err
 	}
@@ -1,12 +1,18 @@
 package kubernetes
 
 import (
+	"context"
 	"encoding/base64"
+	"strconv"
+	"strings"
+	"time"
 
 	"github.com/billiford/go-clouddriver/pkg/arcade"
 	"github.com/billiford/go-clouddriver/pkg/kubernetes"
+	"github.com/billiford/go-clouddriver/pkg/kubernetes/verify"
 	"github.com/billiford/go-clouddriver/pkg/sql"
 	"github.com/google/uuid"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 	"k8s.io/apimachinery/pkg/util/rand"
 	"k8s.io/client-go/rest"
 )
@@ -15,20 +21,28 @@ const randNameNumber = 5
 
 func (ah *actionHandler) NewRunJobAction(ac ActionConfig) Action {
 	return &runJob{
-		ac: ac.ArcadeClient,
-		sc: ac.SQLClient,
-		kc: ac.KubeController,
-		id: ac.ID,
-		rj: ac.Operation.RunJob,
+		ac:  ac.ArcadeClient,
+		sc:  ac.SQLClient,
+		kc:  ac.KubeController,
+		id:  ac.ID,
+		ctx: ac.ctx(),
+		rj:  ac.Operation.RunJob,
+
+		ttl:      ah.config.JobTTL,
+		verifier: ah.config.Verifier,
 	}
 }
 
 type runJob struct {
-	ac arcade.Client
-	sc sql.Client
-	kc kubernetes.Controller
-	id string
-	rj *RunJobRequest
+	ac  arcade.Client
+	sc  sql.Client
+	kc  kubernetes.Controller
+	id  string
+	ctx context.Context
+	rj  *RunJobRequest
+
+	ttl      time.Duration
+	verifier verify.Verifier
 }
 
 func (r *runJob) Run() error {
@@ -55,7 +69,7 @@ func (r *runJob) Run() error {
 		},
 	}
 
-	client, err := r.kc.NewClient(config)
+	client, err := kubernetes.NewProviderClient(r.kc, provider, config)
 	if err != nil {
 		return err
 	}
@@ -75,6 +89,11 @@ func (r *runJob) Run() error {
 		return err
 	}
 
+	err = r.setTTL(u)
+	if err != nil {
+		return err
+	}
+
 	name := u.GetName()
 	generateName := u.GetGenerateName()
 
@@ -82,11 +101,24 @@ func (r *runJob) Run() error {
 		u.SetName(generateName + rand.String(randNameNumber))
 	}
 
+	// Verify the images of the job before running it.
+	if r.verifier != nil {
+		err = r.verifier.Verify(r.ctx, r.rj.Account, []map[string]interface{}{u.Object})
+		if err != nil {
+			return err
+		}
+	}
+
 	meta, err := client.Apply(u)
 	if err != nil {
 		return err
 	}
 
+	manifest, err := dryRunManifest(provider, u)
+	if err != nil {
+		return err
+	}
+
 	// TODO don't hardcode kind.
 	kr := kubernetes.Resource{
 		AccountName:  r.rj.Account,
@@ -99,6 +131,8 @@ func (r *runJob) Run() error {
 		Version:      meta.Version,
 		Kind:         "job",
 		SpinnakerApp: r.rj.Application,
+		DryRun:       provider.DryRun(),
+		Manifest:     manifest,
 	}
 
 	err = r.sc.CreateKubernetesResource(kr)
@@ -108,3 +142,42 @@ func (r *runJob) Run() error {
 
 	return nil
 }
+
+// setTTL sets the ttlSecondsAfterFinished of a Job to the TTL of jobs, if
+// configured and the job does not set its own, so the cluster deletes it
+// and its pods after it finishes. The TTL is annotated too, for the reaper
+// to delete the job from clusters that do not support the field.
+func (r *runJob) setTTL(u *unstructured.Unstructured) error {
+	if r.ttl <= 0 || !strings.EqualFold(u.GetKind(), "job") {
+		return nil
+	}
+
+	var seconds int64
+
+	// Manifests decoded from JSON have float64 numbers.
+	v, _, _ := unstructured.NestedFieldNoCopy(u.Object, "spec", "ttlSecondsAfterFinished")
+
+	switch t := v.(type) {
+	case int64:
+		seconds = t
+	case float64:
+		seconds = int64(t)
+	default:
+		seconds = int64(r.ttl.Seconds())
+
+		err := unstructured.SetNestedField(u.Object, seconds, "spec", "ttlSecondsAfterFinished")
+		if err != nil {
+			return err
+		}
+	}
+
+	annotations := u.GetAnnotations()
+	if annotations == nil {
+		annotations = map[string]string{}
+	}
+
+	annotations[kubernetes.AnnotationJobTTLSecondsAfterFinished] = strconv.FormatInt(seconds, 10)
+	u.SetAnnotations(annotations)
+
+	return nil
+}
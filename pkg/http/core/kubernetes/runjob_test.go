@@ -1,9 +1,13 @@
 package kubernetes_test
 
 import (
+	"context"
 	"errors"
+	"time"
 
+	. "github.com/billiford/go-clouddriver/pkg/http/core/kubernetes"
 	"github.com/billiford/go-clouddriver/pkg/kubernetes"
+	"github.com/billiford/go-clouddriver/pkg/kubernetes/verify/verifyfakes"
 	. "github.com/onsi/ginkgo"
 	. "github.com/onsi/gomega"
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
@@ -131,6 +135,77 @@ var _ = Describe("RunJob", func() {
 		})
 	})
 
+	When("jobs have a TTL", func() {
+		var fakeUnstructured unstructured.Unstructured
+
+		BeforeEach(func() {
+			fakeUnstructured = unstructured.Unstructured{
+				Object: map[string]interface{}{
+					"kind":     "Job",
+					"metadata": map[string]interface{}{"name": "test-job"},
+				},
+			}
+			fakeKubeController.ToUnstructuredReturns(&fakeUnstructured, nil)
+			actionHandler = NewActionHandlerWithConfig(ActionHandlerConfig{JobTTL: 24 * time.Hour})
+		})
+
+		It("sets the TTL of the job", func() {
+			Expect(err).To(BeNil())
+			u := fakeKubeClient.ApplyArgsForCall(0)
+			ttl, _, _ := unstructured.NestedInt64(u.Object, "spec", "ttlSecondsAfterFinished")
+			Expect(ttl).To(Equal(int64(86400)))
+			Expect(u.GetAnnotations()[kubernetes.AnnotationJobTTLSecondsAfterFinished]).To(Equal("86400"))
+		})
+
+		When("the job sets its own TTL", func() {
+			BeforeEach(func() {
+				fakeUnstructured.Object["spec"] = map[string]interface{}{"ttlSecondsAfterFinished": float64(600)}
+			})
+
+			It("keeps it", func() {
+				Expect(err).To(BeNil())
+				u := fakeKubeClient.ApplyArgsForCall(0)
+				Expect(u.Object["spec"]).To(Equal(map[string]interface{}{"ttlSecondsAfterFinished": float64(600)}))
+				Expect(u.GetAnnotations()[kubernetes.AnnotationJobTTLSecondsAfterFinished]).To(Equal("600"))
+			})
+		})
+	})
+
+	Context("the account verifies images", func() {
+		var fakeVerifier *verifyfakes.FakeVerifier
+
+		BeforeEach(func() {
+			fakeVerifier = &verifyfakes.FakeVerifier{}
+			fakeVerifier.VerifyStub = func(ctx context.Context, account string, manifests []map[string]interface{}) error {
+				manifests[0]["spec"] = map[string]interface{}{"pinned": true}
+				return nil
+			}
+			actionHandler = NewActionHandlerWithConfig(ActionHandlerConfig{Verifier: fakeVerifier})
+		})
+
+		When("an image fails verification", func() {
+			BeforeEach(func() {
+				fakeVerifier.VerifyStub = nil
+				fakeVerifier.VerifyReturns(errors.New("error verifying image nginx:1.19: exit status 1: Error: no matching signatures"))
+			})
+
+			It("returns the verification error without running the job", func() {
+				Expect(err).ToNot(BeNil())
+				Expect(err.Error()).To(Equal("error verifying image nginx:1.19: exit status 1: Error: no matching signatures"))
+				Expect(fakeKubeClient.ApplyCallCount()).To(BeZero())
+			})
+		})
+
+		It("runs the job as verified", func() {
+			Expect(err).To(BeNil())
+			_, account, manifests := fakeVerifier.VerifyArgsForCall(0)
+			Expect(account).To(Equal("test-account"))
+			Expect(manifests).To(HaveLen(1))
+			u := fakeKubeClient.ApplyArgsForCall(0)
+			Expect(u.Object["spec"]).To(Equal(map[string]interface{}{"pinned": true}))
+		})
+	})
+
 	When("it succeeds", func() {
 		It("succeeds", func() {
 			Expect(err).To(BeNil())
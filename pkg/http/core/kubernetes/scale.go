@@ -19,6 +19,8 @@ func (ah *actionHandler) NewScaleManifestAction(ac ActionConfig) Action {
 		kc: ac.KubeController,
 		id: ac.ID,
 		sm: ac.Operation.ScaleManifest,
+
+		trafficGuards: ah.config.TrafficGuards,
 	}
 }
 
@@ -28,6 +30,8 @@ type scaleManifest struct {
 	kc kubernetes.Controller
 	id string
 	sm *ScaleManifestRequest
+
+	trafficGuards kubernetes.TrafficGuardConfig
 }
 
 func (s *scaleManifest) Run() error {
@@ -54,7 +58,7 @@ func (s *scaleManifest) Run() error {
 		},
 	}
 
-	client, err := s.kc.NewClient(config)
+	client, err := kubernetes.NewProviderClient(s.kc, provider, config)
 	if err != nil {
 		return err
 	}
@@ -78,6 +82,17 @@ func (s *scaleManifest) Run() error {
 			return err
 		}
 
+		if replicas == 0 {
+			if kubernetes.Pinned(u) && !s.sm.OverridePinned {
+				return fmt.Errorf("%s is pinned, set overridePinned to scale it to zero", s.sm.ManifestName)
+			}
+
+			err = checkTrafficGuards(client, s.trafficGuards, s.sm.Account, kind, u, "scaling to zero")
+			if err != nil {
+				return err
+			}
+		}
+
 		desiredReplicas := int32(replicas)
 		d.SetReplicas(&desiredReplicas)
 
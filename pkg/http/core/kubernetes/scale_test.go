@@ -6,6 +6,7 @@ import (
 	"github.com/billiford/go-clouddriver/pkg/kubernetes"
 	. "github.com/onsi/ginkgo"
 	. "github.com/onsi/gomega"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 )
 
 var _ = Describe("Scale", func() {
@@ -95,6 +96,38 @@ var _ = Describe("Scale", func() {
 		})
 	})
 
+	When("a pinned deployment is scaled to zero", func() {
+		BeforeEach(func() {
+			actionConfig.Operation.ScaleManifest.Replicas = "0"
+			fakeKubeClient.GetReturns(&unstructured.Unstructured{Object: map[string]interface{}{
+				"kind": "Deployment",
+				"metadata": map[string]interface{}{
+					"name": "test-deployment",
+					"annotations": map[string]interface{}{
+						kubernetes.AnnotationSpinnakerPinned: "true",
+					},
+				},
+			}}, nil)
+		})
+
+		It("returns an error", func() {
+			Expect(err).ToNot(BeNil())
+			Expect(err.Error()).To(Equal("deployment test-deployment is pinned, set overridePinned to scale it to zero"))
+			Expect(fakeKubeClient.ApplyCallCount()).To(BeZero())
+		})
+
+		When("the operation overrides it", func() {
+			BeforeEach(func() {
+				actionConfig.Operation.ScaleManifest.OverridePinned = true
+			})
+
+			It("succeeds", func() {
+				Expect(err).To(BeNil())
+				Expect(fakeKubeClient.ApplyCallCount()).To(Equal(1))
+			})
+		})
+	})
+
 	When("it succeeds", func() {
 		It("succeeds", func() {
 			Expect(err).To(BeNil())
@@ -0,0 +1,47 @@
+package kubernetes
+
+import (
+	clouddriver "github.com/billiford/go-clouddriver/pkg"
+	"github.com/billiford/go-clouddriver/pkg/kubernetes"
+	"github.com/billiford/go-clouddriver/pkg/sql"
+)
+
+// NewSimulatedSQLClient returns a client whose providers are in dryRun write
+// mode, so actions only send server-side dry-run requests to their
+// clusters, and which keeps the resources actions record instead of
+// storing them.
+func NewSimulatedSQLClient(sc sql.Client) *SimulatedSQLClient {
+	return &SimulatedSQLClient{Client: sc}
+}
+
+type SimulatedSQLClient struct {
+	sql.Client
+	resources []kubernetes.Resource
+}
+
+func (s *SimulatedSQLClient) GetKubernetesProvider(name string) (kubernetes.Provider, error) {
+	p, err := s.Client.GetKubernetesProvider(name)
+	if err != nil {
+		return p, err
+	}
+
+	p.WriteMode = kubernetes.WriteModeDryRun
+
+	return p, nil
+}
+
+func (s *SimulatedSQLClient) CreateKubernetesResource(r kubernetes.Resource) error {
+	s.resources = append(s.resources, r)
+	return nil
+}
+
+// CreateTaskEvent drops the events of actions, as simulations are not tasks.
+func (s *SimulatedSQLClient) CreateTaskEvent(clouddriver.TaskEvent) error {
+	return nil
+}
+
+// Resources returns the resources recorded by actions, in the order they
+// were recorded.
+func (s *SimulatedSQLClient) Resources() []kubernetes.Resource {
+	return s.resources
+}
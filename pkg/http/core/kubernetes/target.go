@@ -0,0 +1,33 @@
+package kubernetes
+
+import (
+	"github.com/billiford/go-clouddriver/pkg/kubernetes"
+	"github.com/billiford/go-clouddriver/pkg/sql"
+)
+
+// NewTargetedSQLClient returns a client whose provider of account has the
+// host and CA data of one of its clusters, so actions change that cluster.
+func NewTargetedSQLClient(sc sql.Client, account, cluster string) sql.Client {
+	return &targetedSQLClient{
+		Client:  sc,
+		account: account,
+		cluster: cluster,
+	}
+}
+
+type targetedSQLClient struct {
+	sql.Client
+	account string
+	cluster string
+}
+
+func (t *targetedSQLClient) GetKubernetesProvider(name string) (kubernetes.Provider, error) {
+	p, err := t.Client.GetKubernetesProvider(name)
+	if err != nil || name != t.account {
+		return p, err
+	}
+
+	p.Name = name
+
+	return p.Target(t.cluster)
+}
@@ -0,0 +1,26 @@
+package kubernetes
+
+import (
+	"fmt"
+	"log"
+	"time"
+
+	clouddriver "github.com/billiford/go-clouddriver/pkg"
+	"github.com/billiford/go-clouddriver/pkg/sql"
+)
+
+// RecordTaskEvent appends an event to the log of a task. The log is only
+// there to debug tasks, so failing to record an event is logged instead of
+// failing the task.
+func RecordTaskEvent(sc sql.Client, taskID, eventType, format string, args ...interface{}) {
+	te := clouddriver.TaskEvent{
+		TaskID:    taskID,
+		Type:      eventType,
+		Message:   fmt.Sprintf(format, args...),
+		CreatedAt: time.Now(),
+	}
+
+	if err := sc.CreateTaskEvent(te); err != nil {
+		log.Printf("error recording event of task %s: %v", taskID, err)
+	}
+}
@@ -0,0 +1,47 @@
+package kubernetes
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/billiford/go-clouddriver/pkg/kubernetes"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+// checkTrafficGuards returns an error if the cluster of a server group is
+// guarded and disabling it would leave a service it serves with no healthy
+// server group, like Spinnaker's traffic guards.
+func checkTrafficGuards(client kubernetes.Client, guards kubernetes.TrafficGuardConfig,
+	account, kind string, u *unstructured.Unstructured, action string) error {
+	c := u.GetAnnotations()[kubernetes.AnnotationSpinnakerMonikerCluster]
+	if c == "" {
+		c = cluster(kind, versionSuffix.ReplaceAllString(u.GetName(), ""))
+	}
+
+	if c == "" || !guards.Guarded(account, u.GetNamespace(), c) {
+		return nil
+	}
+
+	lo := metav1.ListOptions{
+		FieldSelector: "metadata.namespace=" + u.GetNamespace(),
+	}
+
+	services, err := client.ListResource("services", lo)
+	if err != nil {
+		return err
+	}
+
+	serverGroups, err := client.ListResource(kind, lo)
+	if err != nil {
+		return err
+	}
+
+	unserved := kubernetes.UnservedServices(*u, services.Items, serverGroups.Items)
+	if len(unserved) > 0 {
+		return fmt.Errorf("cluster %s has traffic guards enabled: %s %s %s would leave service %s with no healthy server group taking traffic",
+			c, action, lowercaseFirst(kind), u.GetName(), strings.Join(unserved, ", "))
+	}
+
+	return nil
+}
@@ -1,6 +1,7 @@
 package core
 
 import (
+	"context"
 	"encoding/base64"
 	"errors"
 	"fmt"
@@ -10,9 +11,10 @@ import (
 
 	clouddriver "github.com/billiford/go-clouddriver/pkg"
 	"github.com/billiford/go-clouddriver/pkg/arcade"
+	"github.com/billiford/go-clouddriver/pkg/hook"
 	ops "github.com/billiford/go-clouddriver/pkg/http/core/kubernetes"
 	"github.com/billiford/go-clouddriver/pkg/kubernetes"
-	"github.com/billiford/go-clouddriver/pkg/sql"
+	"github.com/billiford/go-clouddriver/pkg/kubernetes/manifest"
 	"github.com/gin-gonic/gin"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
@@ -23,8 +25,28 @@ var (
 	manifestListTimeout = int64(30)
 )
 
+// liveManifestCalls returns true if the liveManifestCalls query param is set.
+// Manifests are always read from the cluster, but the resource types the
+// cluster serves are cached, so a live call discovers them again. This is
+// needed to read resources of a CRD added by a deploy stage right before.
+func liveManifestCalls(c *gin.Context) bool {
+	return c.Query("liveManifestCalls") == "true"
+}
+
+// resourceStatus returns the status of a live manifest, as decided by the
+// stability webhook of the account and kind if there is one.
+func resourceStatus(ctx context.Context, sc hook.StabilityChecker, account, kind string,
+	m map[string]interface{}) manifest.Status {
+	if sc != nil {
+		if s, ok := sc.Status(ctx, account, m); ok {
+			return s
+		}
+	}
+
+	return kubernetes.GetStatus(kind, m)
+}
+
 func GetManifest(c *gin.Context) {
-	sc := sql.Instance(c)
 	kc := kubernetes.ControllerInstance(c)
 	ac := arcade.Instance(c)
 	account := c.Param("account")
@@ -43,11 +65,7 @@ func GetManifest(c *gin.Context) {
 		kind = a2[0]
 	}
 
-	provider, err := sc.GetKubernetesProvider(account)
-	if err != nil {
-		clouddriver.WriteError(c, http.StatusInternalServerError, err)
-		return
-	}
+	provider := kubernetes.ProviderInstance(c)
 
 	cd, err := base64.StdEncoding.DecodeString(provider.CAData)
 	if err != nil {
@@ -69,12 +87,16 @@ func GetManifest(c *gin.Context) {
 		},
 	}
 
-	client, err := kc.NewClient(config)
+	client, err := kubernetes.NewProviderClient(kc, provider, config)
 	if err != nil {
 		clouddriver.WriteError(c, http.StatusInternalServerError, err)
 		return
 	}
 
+	if liveManifestCalls(c) {
+		client.InvalidateDiscovery()
+	}
+
 	result, err := client.Get(kind, name, namespace)
 	if err != nil {
 		clouddriver.WriteError(c, http.StatusInternalServerError, err)
@@ -100,7 +122,7 @@ func GetManifest(c *gin.Context) {
 		},
 		Name: fmt.Sprintf("%s %s", kind, name),
 		// The 'default' status of a kubernetes resource.
-		Status:   kubernetes.GetStatus(kind, result.Object),
+		Status:   resourceStatus(c.Request.Context(), hook.StabilityCheckerInstance(c), account, kind, result.Object),
 		Warnings: []interface{}{},
 	}
 
@@ -108,7 +130,6 @@ func GetManifest(c *gin.Context) {
 }
 
 func GetManifestByTarget(c *gin.Context) {
-	sc := sql.Instance(c)
 	kc := kubernetes.ControllerInstance(c)
 	ac := arcade.Instance(c)
 	account := c.Param("account")
@@ -126,11 +147,7 @@ func GetManifestByTarget(c *gin.Context) {
 		kind = a2[0]
 	}
 
-	provider, err := sc.GetKubernetesProvider(account)
-	if err != nil {
-		clouddriver.WriteError(c, http.StatusInternalServerError, err)
-		return
-	}
+	provider := kubernetes.ProviderInstance(c)
 
 	cd, err := base64.StdEncoding.DecodeString(provider.CAData)
 	if err != nil {
@@ -152,12 +169,16 @@ func GetManifestByTarget(c *gin.Context) {
 		},
 	}
 
-	client, err := kc.NewClient(config)
+	client, err := kubernetes.NewProviderClient(kc, provider, config)
 	if err != nil {
 		clouddriver.WriteError(c, http.StatusInternalServerError, err)
 		return
 	}
 
+	if liveManifestCalls(c) {
+		client.InvalidateDiscovery()
+	}
+
 	gvr, err := client.GVRForKind(kind)
 	if err != nil {
 		clouddriver.WriteError(c, http.StatusInternalServerError, err)
@@ -169,7 +190,7 @@ func GetManifestByTarget(c *gin.Context) {
 			Kind:       kind,
 			APIVersion: gvr.Group + "/" + gvr.Version,
 		},
-		LabelSelector:  kubernetes.LabelKubernetesName + "=" + application,
+		LabelSelector:  kubernetes.ManagedApplicationLabelSelector(application),
 		FieldSelector:  "metadata.namespace=" + namespace,
 		TimeoutSeconds: &manifestListTimeout,
 		// Limit:          0,
@@ -228,7 +249,7 @@ func GetManifestByTarget(c *gin.Context) {
 			Cluster: cluster,
 		},
 		Name:     fmt.Sprintf("%s %s", kind, result.GetName()),
-		Status:   kubernetes.GetStatus(kind, result.Object),
+		Status:   resourceStatus(c.Request.Context(), hook.StabilityCheckerInstance(c), account, kind, result.Object),
 		Warnings: []interface{}{},
 	}
 
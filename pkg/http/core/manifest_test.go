@@ -1,10 +1,14 @@
 package core_test
 
 import (
+	"encoding/json"
 	"errors"
 	"net/http"
 
+	ops "github.com/billiford/go-clouddriver/pkg/http/core/kubernetes"
 	"github.com/billiford/go-clouddriver/pkg/kubernetes"
+	"github.com/billiford/go-clouddriver/pkg/kubernetes/manifest"
+	"github.com/jinzhu/gorm"
 	. "github.com/onsi/ginkgo"
 	. "github.com/onsi/gomega"
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
@@ -41,6 +45,21 @@ var _ = Describe("Manifest", func() {
 			})
 		})
 
+		When("the account does not exist", func() {
+			BeforeEach(func() {
+				fakeSQLClient.GetKubernetesProviderReturns(kubernetes.Provider{}, gorm.ErrRecordNotFound)
+			})
+
+			It("returns status not found", func() {
+				Expect(res.StatusCode).To(Equal(http.StatusNotFound))
+				ce := getClouddriverError()
+				Expect(ce.Error).To(Equal("Not Found"))
+				Expect(ce.Message).To(Equal("account test-account not found"))
+				Expect(ce.Status).To(Equal(http.StatusNotFound))
+				Expect(fakeKubeController.NewClientCallCount()).To(BeZero())
+			})
+		})
+
 		When("there is an error decoding the provider CA data", func() {
 			BeforeEach(func() {
 				fakeSQLClient.GetKubernetesProviderReturns(kubernetes.Provider{
@@ -103,6 +122,48 @@ var _ = Describe("Manifest", func() {
 			It("succeeds", func() {
 				Expect(res.StatusCode).To(Equal(http.StatusOK))
 			})
+
+			It("marks the manifest as read live", func() {
+				Expect(res.Header.Get("X-Clouddriver-Data-Source")).To(Equal("live"))
+				Expect(res.Header.Get("X-Clouddriver-Read-At")).ToNot(BeEmpty())
+			})
+
+			It("uses cached discovery", func() {
+				Expect(fakeKubeClient.InvalidateDiscoveryCallCount()).To(BeZero())
+			})
+		})
+
+		When("a stability webhook decides the status", func() {
+			BeforeEach(func() {
+				s := manifest.DefaultStatus
+				s.Stable.State = false
+				s.Stable.Message = "Waiting for canary analysis"
+				fakeStabilityChecker.StatusReturns(s, true)
+			})
+
+			It("returns the status of the webhook", func() {
+				Expect(res.StatusCode).To(Equal(http.StatusOK))
+				Expect(fakeStabilityChecker.StatusCallCount()).To(Equal(1))
+				_, account, _ := fakeStabilityChecker.StatusArgsForCall(0)
+				Expect(account).To(Equal("test-account"))
+				mr := ops.ManifestResponse{}
+				err := json.NewDecoder(res.Body).Decode(&mr)
+				Expect(err).To(BeNil())
+				Expect(mr.Status.Stable.State).To(BeFalse())
+				Expect(mr.Status.Stable.Message).To(Equal("Waiting for canary analysis"))
+			})
+		})
+
+		When("liveManifestCalls is true", func() {
+			BeforeEach(func() {
+				uri = svr.URL + "/manifests/test-account/test-namespace/pod test-pod?liveManifestCalls=true"
+				createRequest(http.MethodGet)
+			})
+
+			It("invalidates cached discovery", func() {
+				Expect(res.StatusCode).To(Equal(http.StatusOK))
+				Expect(fakeKubeClient.InvalidateDiscoveryCallCount()).To(Equal(1))
+			})
 		})
 	})
 
@@ -138,6 +199,21 @@ var _ = Describe("Manifest", func() {
 			})
 		})
 
+		When("the account does not exist", func() {
+			BeforeEach(func() {
+				fakeSQLClient.GetKubernetesProviderReturns(kubernetes.Provider{}, gorm.ErrRecordNotFound)
+			})
+
+			It("returns status not found", func() {
+				Expect(res.StatusCode).To(Equal(http.StatusNotFound))
+				ce := getClouddriverError()
+				Expect(ce.Error).To(Equal("Not Found"))
+				Expect(ce.Message).To(Equal("account test-account not found"))
+				Expect(ce.Status).To(Equal(http.StatusNotFound))
+				Expect(fakeKubeController.NewClientCallCount()).To(BeZero())
+			})
+		})
+
 		When("there is an error decoding the provider CA data", func() {
 			BeforeEach(func() {
 				fakeSQLClient.GetKubernetesProviderReturns(kubernetes.Provider{
@@ -0,0 +1,315 @@
+package core
+
+import (
+	"context"
+	"encoding/base64"
+	"fmt"
+	"log"
+	"net/http"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	clouddriver "github.com/billiford/go-clouddriver/pkg"
+	"github.com/billiford/go-clouddriver/pkg/arcade"
+	"github.com/billiford/go-clouddriver/pkg/kubernetes"
+	"github.com/billiford/go-clouddriver/pkg/sql"
+	"github.com/gin-gonic/gin"
+	"github.com/jinzhu/gorm"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/rest"
+)
+
+const (
+	defaultNamespaceRefreshInterval = 30 * time.Second
+)
+
+type AccountNamespaces struct {
+	Name       string   `json:"name"`
+	Namespaces []string `json:"namespaces"`
+}
+
+// ListAccountNamespaces returns the namespaces of a single account. Namespaces
+// are served from the namespace cache when possible and only listed from the
+// cluster on a cache miss.
+func ListAccountNamespaces(c *gin.Context) {
+	sc := sql.ReadOnlyInstance(c)
+	ac := arcade.Instance(c)
+	kc := kubernetes.ControllerInstance(c)
+	nc := kubernetes.NamespaceCacheInstance(c)
+	account := c.Param("account")
+
+	namespaces, err := cachedNamespaces(account, sc, ac, kc, nc)
+	if err != nil {
+		if err == gorm.ErrRecordNotFound {
+			clouddriver.WriteError(c, http.StatusNotFound, fmt.Errorf("account %s not found", account))
+			return
+		}
+
+		clouddriver.WriteError(c, http.StatusInternalServerError, err)
+
+		return
+	}
+
+	c.JSON(http.StatusOK, namespaces)
+}
+
+// ListNamespaces returns the namespaces of all accounts passed in as the comma
+// separated query param 'accounts', or of every account if none are passed in.
+// Accounts whose namespaces cannot be listed are logged and left out of the response.
+func ListNamespaces(c *gin.Context) {
+	sc := sql.ReadOnlyInstance(c)
+	ac := arcade.Instance(c)
+	kc := kubernetes.ControllerInstance(c)
+	nc := kubernetes.NamespaceCacheInstance(c)
+	accounts := []string{}
+
+	for _, account := range strings.Split(c.Query("accounts"), ",") {
+		if account = strings.TrimSpace(account); account != "" {
+			accounts = append(accounts, account)
+		}
+	}
+
+	if len(accounts) == 0 {
+		providers, err := sc.ListKubernetesProviders()
+		if err != nil {
+			clouddriver.WriteError(c, http.StatusInternalServerError, err)
+			return
+		}
+
+		for _, provider := range providers {
+			accounts = append(accounts, provider.Name)
+		}
+	}
+
+	wg := &sync.WaitGroup{}
+	accountNamespacesCh := make(chan AccountNamespaces, len(accounts))
+	wg.Add(len(accounts))
+
+	for _, account := range accounts {
+		go func(account string) {
+			defer wg.Done()
+
+			namespaces, err := cachedNamespaces(account, sc, ac, kc, nc)
+			if err != nil {
+				log.Println("/namespaces error listing namespaces for account", account+":", err.Error())
+				return
+			}
+
+			accountNamespacesCh <- AccountNamespaces{
+				Name:       account,
+				Namespaces: namespaces,
+			}
+		}(account)
+	}
+
+	wg.Wait()
+
+	close(accountNamespacesCh)
+
+	response := []AccountNamespaces{}
+	for an := range accountNamespacesCh {
+		response = append(response, an)
+	}
+
+	sort.Slice(response, func(i, j int) bool {
+		return response[i].Name < response[j].Name
+	})
+
+	c.JSON(http.StatusOK, response)
+}
+
+// cachedNamespaces returns the namespaces for an account from the cache,
+// listing and caching them on a miss.
+func cachedNamespaces(account string,
+	sc sql.Client,
+	ac arcade.Client,
+	kc kubernetes.Controller,
+	nc kubernetes.NamespaceCache) ([]string, error) {
+	if namespaces, ok := nc.Get(account); ok {
+		return namespaces, nil
+	}
+
+	provider, err := sc.GetKubernetesProvider(account)
+	if err != nil {
+		return nil, err
+	}
+
+	provider.Name = account
+
+	// Cache namespaces as each page is listed so requests made while
+	// a large cluster is still being listed do not list it again.
+	namespaces, err := namespacesForProvider(provider, ac, kc, func(namespaces []string) {
+		nc.SetForProvider(provider, namespaces)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return namespaces, nil
+}
+
+// RefreshNamespaces lists and caches the namespaces of every account on an
+// interval, thirty seconds by default, until the context is done. The interval
+// should be shorter than the TTL of the namespace cache so entries are replaced
+// before they expire and requests never wait on a cluster.
+func RefreshNamespaces(ctx context.Context,
+	sc sql.Client,
+	ac arcade.Client,
+	kc kubernetes.Controller,
+	nc kubernetes.NamespaceCache,
+	interval time.Duration) {
+	if interval <= 0 {
+		interval = defaultNamespaceRefreshInterval
+	}
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		refreshNamespaces(sc, ac, kc, nc)
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+func refreshNamespaces(sc sql.Client,
+	ac arcade.Client,
+	kc kubernetes.Controller,
+	nc kubernetes.NamespaceCache) {
+	providers, err := sc.ListKubernetesProviders()
+	if err != nil {
+		log.Println("[NAMESPACES] error listing providers:", err.Error())
+		return
+	}
+
+	wg := &sync.WaitGroup{}
+	wg.Add(len(providers))
+
+	for _, provider := range providers {
+		go func(provider kubernetes.Provider) {
+			defer wg.Done()
+
+			// Only replace the cached namespaces once all pages are listed,
+			// so a refresh never serves fewer namespaces than the last one.
+			namespaces, err := namespacesForProvider(provider, ac, kc, nil)
+			if err != nil {
+				log.Println("[NAMESPACES] error refreshing namespaces for account", provider.Name+":", err.Error())
+				return
+			}
+
+			nc.SetForProvider(provider, namespaces)
+		}(provider)
+	}
+
+	wg.Wait()
+}
+
+// namespacesForProvider lists the names of all namespaces in a provider's cluster
+// a page at a time. If onPage is not nil it is called with the namespaces listed
+// so far after each page. If a page other than the first cannot be listed, the
+// namespaces listed so far are returned. The namespaces of a provider scoped to
+// namespaces are those it is scoped to, and are not listed, as are none of a
+// provider that skips namespace listing and is not scoped. Namespaces are
+// sorted by name.
+func namespacesForProvider(provider kubernetes.Provider,
+	ac arcade.Client,
+	kc kubernetes.Controller,
+	onPage func([]string)) ([]string, error) {
+	if len(provider.Namespaces) > 0 {
+		namespaces := sortedStrings(provider.Namespaces)
+		if onPage != nil {
+			onPage(namespaces)
+		}
+
+		return namespaces, nil
+	}
+
+	if provider.SkipNamespaceListing {
+		if onPage != nil {
+			onPage([]string{})
+		}
+
+		return []string{}, nil
+	}
+
+	cd, err := base64.StdEncoding.DecodeString(provider.CAData)
+	if err != nil {
+		return nil, fmt.Errorf("error decoding provider ca data: %w", err)
+	}
+
+	token, err := ac.Token()
+	if err != nil {
+		return nil, fmt.Errorf("error getting arcade token: %w", err)
+	}
+
+	config := &rest.Config{
+		Host:        provider.Host,
+		BearerToken: token,
+		TLSClientConfig: rest.TLSClientConfig{
+			CAData: cd,
+		},
+	}
+
+	err = kc.MintToken(provider, config)
+	if err != nil {
+		return nil, fmt.Errorf("error minting token: %w", err)
+	}
+
+	client, err := kc.NewClient(config)
+	if err != nil {
+		return nil, fmt.Errorf("error creating dynamic account: %w", err)
+	}
+
+	gvr := schema.GroupVersionResource{
+		Group:    "",
+		Version:  "v1",
+		Resource: "namespaces",
+	}
+
+	namespaces := []string{}
+	cont := ""
+
+	for {
+		// Only names are needed, so only list metadata.
+		// timeout listing each page of namespaces to 5 seconds
+		result, err := client.ListMetadataByGVR(gvr, metav1.ListOptions{
+			TimeoutSeconds: &listNamespacesTimeout,
+			Limit:          listNamespacesPageSize,
+			Continue:       cont,
+		})
+		if err != nil {
+			if cont == "" {
+				return nil, fmt.Errorf("error listing using kubernetes account: %w", err)
+			}
+
+			// The continue token may have expired or the cluster may have become
+			// unavailable mid-list, return what has been listed.
+			log.Printf("error listing namespaces for account %s, returning the %d listed so far: %s\n",
+				provider.Name, len(namespaces), err.Error())
+
+			return sortedStrings(namespaces), nil
+		}
+
+		for _, ns := range result.Items {
+			namespaces = append(namespaces, ns.GetName())
+		}
+
+		if onPage != nil {
+			onPage(sortedStrings(namespaces))
+		}
+
+		cont = result.GetContinue()
+		if cont == "" {
+			break
+		}
+	}
+
+	return sortedStrings(namespaces), nil
+}
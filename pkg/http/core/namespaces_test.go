@@ -0,0 +1,415 @@
+package core_test
+
+import (
+	"context"
+	"errors"
+	"io/ioutil"
+	"log"
+	"net/http"
+	"time"
+
+	"github.com/billiford/go-clouddriver/pkg/http/core"
+	"github.com/billiford/go-clouddriver/pkg/kubernetes"
+	"github.com/jinzhu/gorm"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+var _ = Describe("Namespaces", func() {
+	BeforeEach(func() {
+		setup()
+		fakeKubeClient.ListMetadataByGVRReturns(&metav1.PartialObjectMetadataList{
+			Items: []metav1.PartialObjectMetadata{
+				{
+					ObjectMeta: metav1.ObjectMeta{
+						Name: "namespace1",
+					},
+				},
+				{
+					ObjectMeta: metav1.ObjectMeta{
+						Name: "namespace2",
+					},
+				},
+			},
+		}, nil)
+		log.SetOutput(ioutil.Discard)
+	})
+
+	AfterEach(func() {
+		teardown()
+	})
+
+	JustBeforeEach(func() {
+		doRequest()
+	})
+
+	Describe("#ListAccountNamespaces", func() {
+		BeforeEach(func() {
+			uri = svr.URL + "/credentials/test-account/namespaces"
+			createRequest(http.MethodGet)
+		})
+
+		When("the namespaces are cached", func() {
+			BeforeEach(func() {
+				fakeKubeNamespaceCache.GetReturns([]string{"namespace1", "namespace2"}, true)
+			})
+
+			It("does not call the cluster", func() {
+				Expect(res.StatusCode).To(Equal(http.StatusOK))
+				Expect(fakeSQLClient.GetKubernetesProviderCallCount()).To(BeZero())
+				Expect(fakeKubeClient.ListMetadataByGVRCallCount()).To(BeZero())
+				validateResponse(payloadAccountNamespaces)
+			})
+		})
+
+		When("the account does not exist", func() {
+			BeforeEach(func() {
+				fakeSQLClient.GetKubernetesProviderReturns(kubernetes.Provider{}, gorm.ErrRecordNotFound)
+			})
+
+			It("returns status not found", func() {
+				Expect(res.StatusCode).To(Equal(http.StatusNotFound))
+				ce := getClouddriverError()
+				Expect(ce.Error).To(Equal("Not Found"))
+				Expect(ce.Message).To(Equal("account test-account not found"))
+				Expect(ce.Status).To(Equal(http.StatusNotFound))
+			})
+		})
+
+		When("getting the provider returns an error", func() {
+			BeforeEach(func() {
+				fakeSQLClient.GetKubernetesProviderReturns(kubernetes.Provider{}, errors.New("error getting kubernetes provider"))
+			})
+
+			It("returns an error", func() {
+				Expect(res.StatusCode).To(Equal(http.StatusInternalServerError))
+				ce := getClouddriverError()
+				Expect(ce.Error).To(Equal("Internal Server Error"))
+				Expect(ce.Message).To(Equal("error getting kubernetes provider"))
+				Expect(ce.Status).To(Equal(http.StatusInternalServerError))
+			})
+		})
+
+		When("the account is scoped to namespaces", func() {
+			BeforeEach(func() {
+				fakeSQLClient.GetKubernetesProviderReturns(kubernetes.Provider{
+					Namespaces: kubernetes.ProviderNamespaces{"namespace1", "namespace2"},
+				}, nil)
+			})
+
+			It("returns the namespaces of the scope without calling the cluster", func() {
+				Expect(res.StatusCode).To(Equal(http.StatusOK))
+				Expect(fakeKubeClient.ListMetadataByGVRCallCount()).To(BeZero())
+				Expect(fakeKubeNamespaceCache.SetForProviderCallCount()).To(Equal(1))
+				validateResponse(payloadAccountNamespaces)
+			})
+		})
+
+		When("the account skips namespace listing", func() {
+			BeforeEach(func() {
+				fakeSQLClient.GetKubernetesProviderReturns(kubernetes.Provider{
+					SkipNamespaceListing: true,
+				}, nil)
+			})
+
+			It("returns no namespaces without calling the cluster", func() {
+				Expect(res.StatusCode).To(Equal(http.StatusOK))
+				Expect(fakeKubeClient.ListMetadataByGVRCallCount()).To(BeZero())
+				Expect(fakeKubeNamespaceCache.SetForProviderCallCount()).To(Equal(1))
+				validateResponse(`[]`)
+			})
+
+			When("it is scoped to namespaces", func() {
+				BeforeEach(func() {
+					fakeSQLClient.GetKubernetesProviderReturns(kubernetes.Provider{
+						Namespaces:           kubernetes.ProviderNamespaces{"namespace2", "namespace1"},
+						SkipNamespaceListing: true,
+					}, nil)
+				})
+
+				It("returns the namespaces of the scope", func() {
+					Expect(res.StatusCode).To(Equal(http.StatusOK))
+					Expect(fakeKubeClient.ListMetadataByGVRCallCount()).To(BeZero())
+					validateResponse(payloadAccountNamespaces)
+				})
+			})
+		})
+
+		When("decoding the ca data returns an error", func() {
+			BeforeEach(func() {
+				fakeSQLClient.GetKubernetesProviderReturns(kubernetes.Provider{CAData: "{}"}, nil)
+			})
+
+			It("returns an error", func() {
+				Expect(res.StatusCode).To(Equal(http.StatusInternalServerError))
+				ce := getClouddriverError()
+				Expect(ce.Error).To(Equal("Internal Server Error"))
+				Expect(ce.Message).To(Equal("error decoding provider ca data: illegal base64 data at input byte 0"))
+				Expect(ce.Status).To(Equal(http.StatusInternalServerError))
+			})
+		})
+
+		When("getting the arcade token returns an error", func() {
+			BeforeEach(func() {
+				fakeArcadeClient.TokenReturns("", errors.New("error getting token"))
+			})
+
+			It("returns an error", func() {
+				Expect(res.StatusCode).To(Equal(http.StatusInternalServerError))
+				ce := getClouddriverError()
+				Expect(ce.Error).To(Equal("Internal Server Error"))
+				Expect(ce.Message).To(Equal("error getting arcade token: error getting token"))
+				Expect(ce.Status).To(Equal(http.StatusInternalServerError))
+			})
+		})
+
+		When("creating the kube client returns an error", func() {
+			BeforeEach(func() {
+				fakeKubeController.NewClientReturns(nil, errors.New("bad config"))
+			})
+
+			It("returns an error", func() {
+				Expect(res.StatusCode).To(Equal(http.StatusInternalServerError))
+				ce := getClouddriverError()
+				Expect(ce.Error).To(Equal("Internal Server Error"))
+				Expect(ce.Message).To(Equal("error creating dynamic account: bad config"))
+				Expect(ce.Status).To(Equal(http.StatusInternalServerError))
+			})
+		})
+
+		When("listing namespaces returns an error", func() {
+			BeforeEach(func() {
+				fakeKubeClient.ListMetadataByGVRReturns(nil, errors.New("error listing"))
+			})
+
+			It("returns an error and does not cache", func() {
+				Expect(res.StatusCode).To(Equal(http.StatusInternalServerError))
+				ce := getClouddriverError()
+				Expect(ce.Error).To(Equal("Internal Server Error"))
+				Expect(ce.Message).To(Equal("error listing using kubernetes account: error listing"))
+				Expect(ce.Status).To(Equal(http.StatusInternalServerError))
+				Expect(fakeKubeNamespaceCache.SetForProviderCallCount()).To(BeZero())
+			})
+		})
+
+		When("the cluster has more than one page of namespaces", func() {
+			BeforeEach(func() {
+				fakeKubeClient.ListMetadataByGVRReturnsOnCall(0, &metav1.PartialObjectMetadataList{
+					ListMeta: metav1.ListMeta{
+						Continue: "test-continue",
+					},
+					Items: []metav1.PartialObjectMetadata{
+						{
+							ObjectMeta: metav1.ObjectMeta{
+								Name: "namespace1",
+							},
+						},
+					},
+				}, nil)
+				fakeKubeClient.ListMetadataByGVRReturnsOnCall(1, &metav1.PartialObjectMetadataList{
+					Items: []metav1.PartialObjectMetadata{
+						{
+							ObjectMeta: metav1.ObjectMeta{
+								Name: "namespace2",
+							},
+						},
+					},
+				}, nil)
+			})
+
+			It("lists every page and caches the namespaces as they are listed", func() {
+				Expect(res.StatusCode).To(Equal(http.StatusOK))
+				Expect(fakeKubeClient.ListMetadataByGVRCallCount()).To(Equal(2))
+				_, lo := fakeKubeClient.ListMetadataByGVRArgsForCall(0)
+				Expect(lo.Limit).To(Equal(int64(500)))
+				Expect(lo.Continue).To(BeEmpty())
+				_, lo = fakeKubeClient.ListMetadataByGVRArgsForCall(1)
+				Expect(lo.Limit).To(Equal(int64(500)))
+				Expect(lo.Continue).To(Equal("test-continue"))
+				Expect(fakeKubeNamespaceCache.SetForProviderCallCount()).To(Equal(2))
+				_, namespaces := fakeKubeNamespaceCache.SetForProviderArgsForCall(0)
+				Expect(namespaces).To(Equal([]string{"namespace1"}))
+				_, namespaces = fakeKubeNamespaceCache.SetForProviderArgsForCall(1)
+				Expect(namespaces).To(Equal([]string{"namespace1", "namespace2"}))
+				validateResponse(payloadAccountNamespaces)
+			})
+
+			When("listing a page after the first returns an error", func() {
+				BeforeEach(func() {
+					fakeKubeClient.ListMetadataByGVRReturnsOnCall(1, nil, errors.New("error listing"))
+				})
+
+				It("returns the namespaces listed so far", func() {
+					Expect(res.StatusCode).To(Equal(http.StatusOK))
+					Expect(fakeKubeNamespaceCache.SetForProviderCallCount()).To(Equal(1))
+					validateResponse(`["namespace1"]`)
+				})
+			})
+		})
+
+		When("the cluster does not list namespaces by name", func() {
+			BeforeEach(func() {
+				fakeKubeClient.ListMetadataByGVRReturns(&metav1.PartialObjectMetadataList{
+					Items: []metav1.PartialObjectMetadata{
+						{
+							ObjectMeta: metav1.ObjectMeta{
+								Name: "namespace2",
+							},
+						},
+						{
+							ObjectMeta: metav1.ObjectMeta{
+								Name: "namespace1",
+							},
+						},
+					},
+				}, nil)
+			})
+
+			It("sorts them by name", func() {
+				Expect(res.StatusCode).To(Equal(http.StatusOK))
+				_, namespaces := fakeKubeNamespaceCache.SetForProviderArgsForCall(0)
+				Expect(namespaces).To(Equal([]string{"namespace1", "namespace2"}))
+				validateResponse(payloadAccountNamespaces)
+			})
+		})
+
+		When("it succeeds", func() {
+			It("caches and returns the namespaces", func() {
+				Expect(res.StatusCode).To(Equal(http.StatusOK))
+				Expect(fakeKubeNamespaceCache.SetForProviderCallCount()).To(Equal(1))
+				provider, namespaces := fakeKubeNamespaceCache.SetForProviderArgsForCall(0)
+				Expect(provider.Name).To(Equal("test-account"))
+				Expect(namespaces).To(Equal([]string{"namespace1", "namespace2"}))
+				validateResponse(payloadAccountNamespaces)
+			})
+		})
+	})
+
+	Describe("#ListNamespaces", func() {
+		BeforeEach(func() {
+			uri = svr.URL + "/namespaces?accounts=provider2,provider1"
+			createRequest(http.MethodGet)
+		})
+
+		When("no accounts are passed in", func() {
+			BeforeEach(func() {
+				uri = svr.URL + "/namespaces"
+				createRequest(http.MethodGet)
+				fakeSQLClient.ListKubernetesProvidersReturns([]kubernetes.Provider{
+					{
+						Name: "provider1",
+					},
+					{
+						Name: "provider2",
+					},
+				}, nil)
+			})
+
+			It("returns the namespaces of all accounts", func() {
+				Expect(res.StatusCode).To(Equal(http.StatusOK))
+				Expect(fakeSQLClient.ListKubernetesProvidersCallCount()).To(Equal(1))
+				validateResponse(payloadNamespaces)
+			})
+		})
+
+		When("listing providers returns an error", func() {
+			BeforeEach(func() {
+				uri = svr.URL + "/namespaces"
+				createRequest(http.MethodGet)
+				fakeSQLClient.ListKubernetesProvidersReturns(nil, errors.New("error listing providers"))
+			})
+
+			It("returns an error", func() {
+				Expect(res.StatusCode).To(Equal(http.StatusInternalServerError))
+				ce := getClouddriverError()
+				Expect(ce.Error).To(Equal("Internal Server Error"))
+				Expect(ce.Message).To(Equal("error listing providers"))
+				Expect(ce.Status).To(Equal(http.StatusInternalServerError))
+			})
+		})
+
+		When("listing namespaces for an account returns an error", func() {
+			BeforeEach(func() {
+				fakeKubeClient.ListMetadataByGVRReturns(nil, errors.New("error listing"))
+			})
+
+			It("leaves the account out of the response", func() {
+				Expect(res.StatusCode).To(Equal(http.StatusOK))
+				validateResponse(`[]`)
+			})
+		})
+
+		When("it succeeds", func() {
+			It("returns the namespaces sorted by account", func() {
+				Expect(res.StatusCode).To(Equal(http.StatusOK))
+				Expect(fakeSQLClient.ListKubernetesProvidersCallCount()).To(BeZero())
+				Expect(fakeKubeNamespaceCache.SetForProviderCallCount()).To(Equal(2))
+				validateResponse(payloadNamespaces)
+			})
+		})
+	})
+})
+
+var _ = Describe("RefreshNamespaces", func() {
+	BeforeEach(func() {
+		setup()
+		fakeSQLClient.ListKubernetesProvidersReturns([]kubernetes.Provider{
+			{
+				Name:   "provider1",
+				CAData: "dGVzdAo=",
+			},
+			{
+				Name:       "provider2",
+				Namespaces: []string{"scoped"},
+			},
+		}, nil)
+		fakeKubeClient.ListMetadataByGVRReturns(&metav1.PartialObjectMetadataList{
+			Items: []metav1.PartialObjectMetadata{
+				{
+					ObjectMeta: metav1.ObjectMeta{
+						Name: "namespace1",
+					},
+				},
+			},
+		}, nil)
+		log.SetOutput(ioutil.Discard)
+	})
+
+	AfterEach(func() {
+		teardown()
+	})
+
+	JustBeforeEach(func() {
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+		core.RefreshNamespaces(ctx, fakeSQLClient, fakeArcadeClient, fakeKubeController, fakeKubeNamespaceCache, time.Minute)
+	})
+
+	When("listing namespaces for an account returns an error", func() {
+		BeforeEach(func() {
+			fakeKubeClient.ListMetadataByGVRReturns(nil, errors.New("error listing"))
+		})
+
+		It("refreshes the other accounts", func() {
+			Expect(fakeKubeNamespaceCache.SetForProviderCallCount()).To(Equal(1))
+			p, namespaces := fakeKubeNamespaceCache.SetForProviderArgsForCall(0)
+			Expect(p.Name).To(Equal("provider2"))
+			Expect(namespaces).To(Equal([]string{"scoped"}))
+		})
+	})
+
+	It("caches the namespaces of every account", func() {
+		Expect(fakeSQLClient.ListKubernetesProvidersCallCount()).To(Equal(1))
+		Expect(fakeKubeNamespaceCache.SetForProviderCallCount()).To(Equal(2))
+		cached := map[string][]string{}
+		for i := 0; i < 2; i++ {
+			p, namespaces := fakeKubeNamespaceCache.SetForProviderArgsForCall(i)
+			cached[p.Name] = namespaces
+		}
+		Expect(cached).To(Equal(map[string][]string{
+			"provider1": {"namespace1"},
+			"provider2": {"scoped"},
+		}))
+	})
+})
@@ -1,12 +1,26 @@
 package core
 
 import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
 	"net/http"
+	"time"
 
 	clouddriver "github.com/billiford/go-clouddriver/pkg"
 	"github.com/billiford/go-clouddriver/pkg/arcade"
+	"github.com/billiford/go-clouddriver/pkg/artifact"
+	"github.com/billiford/go-clouddriver/pkg/fiat"
+	"github.com/billiford/go-clouddriver/pkg/freeze"
+	"github.com/billiford/go-clouddriver/pkg/hook"
 	"github.com/billiford/go-clouddriver/pkg/http/core/kubernetes"
 	kube "github.com/billiford/go-clouddriver/pkg/kubernetes"
+	"github.com/billiford/go-clouddriver/pkg/lock"
+	"github.com/billiford/go-clouddriver/pkg/notify"
+	"github.com/billiford/go-clouddriver/pkg/queue"
+	"github.com/billiford/go-clouddriver/pkg/recorder"
+	"github.com/billiford/go-clouddriver/pkg/routine"
 	"github.com/billiford/go-clouddriver/pkg/sql"
 	"github.com/gin-gonic/gin"
 	"github.com/google/uuid"
@@ -26,12 +40,25 @@ func CreateKubernetesOperation(c *gin.Context) {
 	ko := kubernetes.Operations{}
 	taskID := uuid.New().String()
 	ac := arcade.Instance(c)
+	acc := artifact.CredentialsControllerInstance(c)
 	ah := kubernetes.ActionHandlerInstance(c)
 	kc := kube.ControllerInstance(c)
 	sc := sql.Instance(c)
+	q := queue.Instance(c)
+	fc := freeze.ControllerInstance(c)
+	n := notify.Instance(c)
+	hr := hook.Instance(c)
+	l := lock.Instance(c)
 	application := c.GetHeader("X-Spinnaker-Application")
 
-	err := c.ShouldBindJSON(&ko)
+	// Keep the payload as sent, to store it for replay if the task fails.
+	payload, err := c.GetRawData()
+	if err != nil {
+		clouddriver.WriteError(c, http.StatusBadRequest, err)
+		return
+	}
+
+	err = json.Unmarshal(payload, &ko)
 	if err != nil {
 		clouddriver.WriteError(c, http.StatusBadRequest, err)
 		return
@@ -47,81 +74,200 @@ func CreateKubernetesOperation(c *gin.Context) {
 		return
 	}
 
+	status, err := validateOperations(c, sc, fc, ko)
+	if err != nil {
+		clouddriver.WriteError(c, status, err)
+		return
+	}
+
+	// Call the pre-apply hooks of all operations before any of them run,
+	// applying the manifests they return.
+	if hr != nil {
+		for _, req := range ko {
+			manifests, err := hr.PreApply(c.Request.Context(), hookEvent(c, req, taskID))
+			if err != nil {
+				var re *hook.RejectedError
+				if errors.As(err, &re) {
+					clouddriver.WriteError(c, http.StatusUnprocessableEntity, err)
+				} else {
+					clouddriver.WriteError(c, http.StatusBadGateway, err)
+				}
+
+				return
+			}
+
+			if manifests != nil {
+				req.SetManifests(manifests)
+			}
+		}
+	}
+
+	// Hold the locks of all clusters the operations change until they are
+	// done, on behalf of the pipeline execution so its other stages are not
+	// blocked by it.
+	if l != nil {
+		keys := []string{}
+		for _, req := range ko {
+			keys = append(keys, req.LockKeys()...)
+		}
+
+		owner := c.GetHeader(recorder.HeaderSpinnakerExecutionID)
+		if owner == "" {
+			owner = taskID
+		}
+
+		unlock, err := l.Lock(c.Request.Context(), owner, keys...)
+		if err != nil {
+			clouddriver.WriteError(c, http.StatusConflict, err)
+			return
+		}
+		defer unlock()
+	}
+
 	// Loop through each request in the kubernetes operations and perform
 	// each requested action.
 	for _, req := range ko {
 		config := kubernetes.ActionConfig{
-			ArcadeClient:   ac,
-			KubeController: kc,
-			SQLClient:      sc,
-			ID:             taskID,
-			Application:    application,
-			Operation:      req,
+			ArcadeClient:                  ac,
+			ArtifactCredentialsController: acc,
+			KubeController:                kc,
+			SQLClient:                     sc,
+			ID:                            taskID,
+			Application:                   application,
+			Operation:                     req,
+			Context:                       c.Request.Context(),
+		}
+
+		if cluster := req.TargetCluster(); cluster != "" {
+			config.SQLClient = kubernetes.NewTargetedSQLClient(sc, req.Account(), cluster)
 		}
 
+		// Wait for the account to admit the operation, if rate-limited.
+		if account := req.Account(); q != nil && account != "" {
+			err = q.Wait(c.Request.Context(), account, req.Priority())
+			if err != nil {
+				clouddriver.WriteError(c, http.StatusTooManyRequests, err)
+				return
+			}
+		}
+
+		kubernetes.RecordTaskEvent(sc, taskID, clouddriver.TaskEventOperationStarted, "started %s", operationName(req))
+
 		if req.DeployManifest != nil {
-			err = ah.NewDeployManifestAction(config).Run()
+			err = runAction(req.Name(), ah.NewDeployManifestAction(config))
+			runPostApplyHooks(c, hr, req, taskID, err)
+			notifyOutcome(c, n, sc, "deployManifest", req, taskID, err)
+			recordDeploy(c, sc, req, taskID, err)
+
 			if err != nil {
-				clouddriver.WriteError(c, http.StatusInternalServerError, err)
+				failOperation(c, sc, taskID, payload, err)
 				return
 			}
 		}
 
 		if req.DeleteManifest != nil {
-			err = ah.NewDeleteManifestAction(config).Run()
+			err = runAction(req.Name(), ah.NewDeleteManifestAction(config))
+			runPostApplyHooks(c, hr, req, taskID, err)
+
 			if err != nil {
-				clouddriver.WriteError(c, http.StatusInternalServerError, err)
+				failOperation(c, sc, taskID, payload, err)
 				return
 			}
 		}
 
 		if req.ScaleManifest != nil {
-			err = ah.NewScaleManifestAction(config).Run()
+			err = runAction(req.Name(), ah.NewScaleManifestAction(config))
+			runPostApplyHooks(c, hr, req, taskID, err)
+
 			if err != nil {
-				clouddriver.WriteError(c, http.StatusInternalServerError, err)
+				failOperation(c, sc, taskID, payload, err)
 				return
 			}
 		}
 
 		if req.CleanupArtifacts != nil {
-			err = ah.NewCleanupArtifactsAction(config).Run()
+			err = runAction(req.Name(), ah.NewCleanupArtifactsAction(config))
+			runPostApplyHooks(c, hr, req, taskID, err)
+
 			if err != nil {
-				clouddriver.WriteError(c, http.StatusInternalServerError, err)
+				failOperation(c, sc, taskID, payload, err)
 				return
 			}
 		}
 
 		if req.RollingRestartManifest != nil {
-			err = ah.NewRollingRestartAction(config).Run()
+			err = runAction(req.Name(), ah.NewRollingRestartAction(config))
+			runPostApplyHooks(c, hr, req, taskID, err)
+
 			if err != nil {
-				clouddriver.WriteError(c, http.StatusInternalServerError, err)
+				failOperation(c, sc, taskID, payload, err)
 				return
 			}
 		}
 
 		if req.RunJob != nil {
-			err = ah.NewRunJobAction(config).Run()
+			err = runAction(req.Name(), ah.NewRunJobAction(config))
+			runPostApplyHooks(c, hr, req, taskID, err)
+
 			if err != nil {
-				clouddriver.WriteError(c, http.StatusInternalServerError, err)
+				failOperation(c, sc, taskID, payload, err)
 				return
 			}
 		}
 
 		if req.UndoRolloutManifest != nil {
-			err = ah.NewRollbackAction(config).Run()
+			err = runAction(req.Name(), ah.NewRollbackAction(config))
+			runPostApplyHooks(c, hr, req, taskID, err)
+			notifyOutcome(c, n, sc, "undoRolloutManifest", req, taskID, err)
+
 			if err != nil {
-				clouddriver.WriteError(c, http.StatusInternalServerError, err)
+				failOperation(c, sc, taskID, payload, err)
 				return
 			}
 		}
 
 		if req.PatchManifest != nil {
-			err = ah.NewPatchManifestAction(config).Run()
+			err = runAction(req.Name(), ah.NewPatchManifestAction(config))
+			runPostApplyHooks(c, hr, req, taskID, err)
+
+			if err != nil {
+				failOperation(c, sc, taskID, payload, err)
+				return
+			}
+		}
+
+		if req.CreateApplication != nil {
+			err = runAction(req.Name(), ah.NewCreateApplicationAction(config))
+			runPostApplyHooks(c, hr, req, taskID, err)
+
+			if err != nil {
+				failOperation(c, sc, taskID, payload, err)
+				return
+			}
+		}
+
+		if req.DeleteApplication != nil {
+			err = runAction(req.Name(), ah.NewDeleteApplicationAction(config))
+			runPostApplyHooks(c, hr, req, taskID, err)
+
+			if err != nil {
+				failOperation(c, sc, taskID, payload, err)
+				return
+			}
+		}
+
+		if req.MigrateApplication != nil {
+			err = runAction(req.Name(), ah.NewMigrateApplicationAction(config))
+			runPostApplyHooks(c, hr, req, taskID, err)
+
 			if err != nil {
-				clouddriver.WriteError(c, http.StatusInternalServerError, err)
+				failOperation(c, sc, taskID, payload, err)
 				return
 			}
 		}
+
+		kubernetes.RecordTaskEvent(sc, taskID, clouddriver.TaskEventOperationSucceeded, "%s succeeded", operationName(req))
+		runPostStabilityHooks(c, hr, req, taskID)
 	}
 
 	or := kubernetes.OperationsResponse{
@@ -130,3 +276,235 @@ func CreateKubernetesOperation(c *gin.Context) {
 	}
 	c.JSON(http.StatusOK, or)
 }
+
+// validateOperations returns an error, and the status to respond with, if
+// any of the operations of a request may not run.
+func validateOperations(c *gin.Context, sc sql.Client, fc freeze.Controller, ko kubernetes.Operations) (int, error) {
+	// Reject all operations if any account is read-only, in maintenance, has
+	// no cluster an operation targets or is not scoped to a namespace an
+	// operation changes, before any of them run. Clients of scoped accounts
+	// check each change again as it is made.
+	for _, req := range ko {
+		account := req.Account()
+		if account == "" {
+			continue
+		}
+
+		provider, err := sc.GetKubernetesProvider(account)
+		if err != nil {
+			continue
+		}
+
+		provider.Name = account
+
+		_, err = provider.Target(req.TargetCluster())
+		if err != nil {
+			return http.StatusBadRequest, err
+		}
+
+		if provider.ReadOnly {
+			return http.StatusForbidden,
+				fmt.Errorf("account %s is read-only, operation %s is not allowed", account, req.Name())
+		}
+
+		for _, namespace := range req.Namespaces() {
+			if !provider.InNamespaceScope(namespace) {
+				return http.StatusForbidden,
+					fmt.Errorf("account %s is not scoped to namespace %s", account, namespace)
+			}
+		}
+
+		if !provider.Maintenance {
+			continue
+		}
+
+		msg := fmt.Sprintf("account %s is in maintenance", account)
+		if provider.MaintenanceMessage != "" {
+			msg += ": " + provider.MaintenanceMessage
+		}
+
+		return http.StatusLocked, errors.New(msg)
+	}
+
+	// Migrations read every resource of an application from their source
+	// account, including secrets, so the user needs READ permission to it.
+	for _, req := range ko {
+		if req.MigrateApplication == nil {
+			continue
+		}
+
+		status, err := authorizeAccountRead(c, req.MigrateApplication.SourceAccount)
+		if err != nil {
+			return status, err
+		}
+	}
+
+	// Reject all operations if any namespace they change is frozen, unless
+	// the freeze allows overriding it and the override header is set.
+	override := c.GetHeader(freeze.HeaderOverride)
+	now := time.Now()
+
+	for _, req := range ko {
+		account := req.Account()
+		if account == "" {
+			continue
+		}
+
+		for _, namespace := range req.Namespaces() {
+			f, ok := fc.ActiveFreeze(account, namespace, now)
+			if !ok {
+				continue
+			}
+
+			if f.AllowOverride && override != "" {
+				log.Println("[FREEZE] freeze", f.Name, "of namespace", namespace, "of account", account,
+					"overridden by", requestUser(c)+":", override)
+				continue
+			}
+
+			return http.StatusLocked, freezeError(account, namespace, f)
+		}
+	}
+
+	return http.StatusOK, nil
+}
+
+// runAction runs the action of an operation, returning a panic in it as an
+// error so the task fails like any other and its payload is stored.
+func runAction(name string, a kubernetes.Action) error {
+	return routine.Recover(name, a.Run)
+}
+
+// requestUser returns the user of a request for logs and records, the user
+// of its token with a group resolver. Requests reaching handlers have been
+// authorized, so their user resolves.
+func requestUser(c *gin.Context) string {
+	user, _ := fiat.User(c)
+
+	return user
+}
+
+// authorizeAccountRead returns an error, and the status to respond with, if
+// the user of the request is denied READ permission to an account, such as
+// the source account of a migration or the account of a task. Like the
+// account authorization of other routes, requests without a user and
+// accounts Fiat does not list are let through.
+func authorizeAccountRead(c *gin.Context, account string) (int, error) {
+	user, err := fiat.User(c)
+	if err != nil {
+		return http.StatusUnauthorized, err
+	}
+
+	if user == "" {
+		return http.StatusOK, nil
+	}
+
+	r, err := fiat.Authorize(c, user)
+	if err != nil {
+		return http.StatusUnauthorized, err
+	}
+
+	for _, a := range r.Accounts {
+		if a.Name != account {
+			continue
+		}
+
+		for _, authorization := range a.Authorizations {
+			if authorization == "READ" {
+				return http.StatusOK, nil
+			}
+		}
+
+		return http.StatusForbidden, &clouddriver.AccessDeniedError{
+			ResourceType:          "account",
+			Resource:              account,
+			RequiredAuthorization: "READ",
+		}
+	}
+
+	return http.StatusOK, nil
+}
+
+// failOperation stores the payload of a failed task, so admins can replay it
+// with ReplayAdminTask, and responds with the error of the task.
+func failOperation(c *gin.Context, sc sql.Client, taskID string, payload []byte, err error) {
+	fo := clouddriver.FailedOperation{
+		TaskID:      taskID,
+		Application: c.GetHeader("X-Spinnaker-Application"),
+		User:        requestUser(c),
+		Payload:     string(payload),
+		Error:       err.Error(),
+		CreatedAt:   time.Now(),
+	}
+
+	if serr := sc.CreateFailedOperation(fo); serr != nil {
+		log.Println("[OPS] error storing failed task", taskID+":", serr.Error())
+	}
+
+	kubernetes.RecordTaskEvent(sc, taskID, clouddriver.TaskEventOperationFailed, "failed: %s", err.Error())
+
+	clouddriver.WriteError(c, http.StatusInternalServerError, err)
+}
+
+// operationName returns the name of an operation and the account it
+// changes, if any, for the events of its task.
+func operationName(req kubernetes.Operation) string {
+	if account := req.Account(); account != "" {
+		return req.Name() + " of account " + account
+	}
+
+	return req.Name()
+}
+
+func freezeError(account, namespace string, f freeze.Status) error {
+	msg := fmt.Sprintf("namespace %s of account %s is frozen by %s until %s", namespace, account,
+		f.Name, f.End.UTC().Format(time.RFC3339))
+	if f.Message != "" {
+		msg += ": " + f.Message
+	}
+
+	if f.AllowOverride {
+		msg += fmt.Sprintf(" (set the %s header to override)", freeze.HeaderOverride)
+	}
+
+	return errors.New(msg)
+}
+
+// notifyOutcome notifies the sinks of an operation's account and
+// application, if notifications are enabled, of whether it succeeded.
+func notifyOutcome(c *gin.Context, n notify.Notifier, sc sql.Client, operation string,
+	req kubernetes.Operation, taskID string, err error) {
+	if n == nil {
+		return
+	}
+
+	e := notify.Event{
+		Operation:   operation,
+		Account:     req.Account(),
+		Application: c.GetHeader("X-Spinnaker-Application"),
+		User:        requestUser(c),
+		TaskID:      taskID,
+	}
+
+	if e.Application == "" && req.DeployManifest != nil {
+		e.Application = req.DeployManifest.Moniker.App
+	}
+
+	switch {
+	case err != nil:
+		e.Error = err.Error()
+	case req.UndoRolloutManifest != nil:
+		e.Resources = []string{fmt.Sprintf("%s in %s to revision %s", req.UndoRolloutManifest.ManifestName,
+			req.UndoRolloutManifest.Location, req.UndoRolloutManifest.Revision)}
+	default:
+		// The task's resources are only listed to be notified of, so ignore errors.
+		resources, _ := sc.ListKubernetesResourcesByTaskID(taskID)
+		for _, r := range resources {
+			if r.AccountName == e.Account {
+				e.Resources = append(e.Resources, fmt.Sprintf("%s %s in %s", r.Kind, r.Name, r.Namespace))
+			}
+		}
+	}
+
+	n.Notify(e)
+}
@@ -6,9 +6,18 @@ import (
 	"bytes"
 	"errors"
 	"net/http"
+	"time"
 
+	clouddriver "github.com/billiford/go-clouddriver/pkg"
+	"github.com/billiford/go-clouddriver/pkg/fiat"
+	"github.com/billiford/go-clouddriver/pkg/freeze"
+	"github.com/billiford/go-clouddriver/pkg/hook"
+	"github.com/billiford/go-clouddriver/pkg/kubernetes"
+	"github.com/billiford/go-clouddriver/pkg/lock"
+	"github.com/billiford/go-clouddriver/pkg/queue"
 	. "github.com/onsi/ginkgo"
 	. "github.com/onsi/gomega"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 )
 
 var _ = Describe("Kubernetes", func() {
@@ -56,6 +65,475 @@ var _ = Describe("Kubernetes", func() {
 			})
 		})
 
+		When("the account is in maintenance", func() {
+			BeforeEach(func() {
+				fakeSQLClient.GetKubernetesProviderReturns(kubernetes.Provider{
+					Name:               "spin-cluster-account",
+					Maintenance:        true,
+					MaintenanceMessage: "upgrading to 1.19",
+				}, nil)
+			})
+
+			It("returns status locked without running any operation", func() {
+				Expect(res.StatusCode).To(Equal(http.StatusLocked))
+				ce := getClouddriverError()
+				Expect(ce.Error).To(Equal("Locked"))
+				Expect(ce.Message).To(Equal("account spin-cluster-account is in maintenance: upgrading to 1.19"))
+				Expect(fakeKubeActionHandler.NewDeployManifestActionCallCount()).To(BeZero())
+			})
+		})
+
+		When("the account is read-only", func() {
+			BeforeEach(func() {
+				fakeSQLClient.GetKubernetesProviderReturns(kubernetes.Provider{
+					Name:     "spin-cluster-account",
+					ReadOnly: true,
+				}, nil)
+			})
+
+			It("returns status forbidden without running any operation", func() {
+				Expect(res.StatusCode).To(Equal(http.StatusForbidden))
+				ce := getClouddriverError()
+				Expect(ce.Error).To(Equal("Forbidden"))
+				Expect(ce.Message).To(Equal("account spin-cluster-account is read-only, operation deployManifest is not allowed"))
+				Expect(fakeKubeActionHandler.NewDeployManifestActionCallCount()).To(BeZero())
+			})
+		})
+
+		When("the account is not scoped to a namespace the operation changes", func() {
+			BeforeEach(func() {
+				fakeSQLClient.GetKubernetesProviderReturns(kubernetes.Provider{
+					Name:       "spin-cluster-account",
+					Namespaces: kubernetes.ProviderNamespaces{"team-a"},
+				}, nil)
+			})
+
+			It("returns status forbidden without running any operation", func() {
+				Expect(res.StatusCode).To(Equal(http.StatusForbidden))
+				ce := getClouddriverError()
+				Expect(ce.Error).To(Equal("Forbidden"))
+				Expect(ce.Message).To(Equal("account spin-cluster-account is not scoped to namespace default"))
+				Expect(fakeKubeActionHandler.NewDeployManifestActionCallCount()).To(BeZero())
+			})
+		})
+
+		When("the operation targets a cluster the account does not have", func() {
+			BeforeEach(func() {
+				body = &bytes.Buffer{}
+				body.Write([]byte(`[{"deployManifest":{"account":"spin-cluster-account","targetCluster":"standby","manifests":[]}}]`))
+				createRequest(http.MethodPost)
+			})
+
+			It("returns status bad request without running any operation", func() {
+				Expect(res.StatusCode).To(Equal(http.StatusBadRequest))
+				ce := getClouddriverError()
+				Expect(ce.Message).To(Equal(`account spin-cluster-account has no cluster "standby"`))
+				Expect(fakeKubeActionHandler.NewDeployManifestActionCallCount()).To(BeZero())
+			})
+		})
+
+		When("the operation targets a cluster of the account", func() {
+			BeforeEach(func() {
+				fakeSQLClient.GetKubernetesProviderReturns(kubernetes.Provider{
+					Host: "https://primary",
+					Clusters: kubernetes.ProviderClusters{
+						{
+							Name: "standby",
+							Host: "https://standby",
+						},
+					},
+				}, nil)
+				body = &bytes.Buffer{}
+				body.Write([]byte(`[{"deployManifest":{"account":"spin-cluster-account","targetCluster":"standby","manifests":[]}}]`))
+				createRequest(http.MethodPost)
+			})
+
+			It("runs the operation against that cluster", func() {
+				Expect(res.StatusCode).To(Equal(http.StatusOK))
+				Expect(fakeKubeActionHandler.NewDeployManifestActionCallCount()).To(Equal(1))
+				config := fakeKubeActionHandler.NewDeployManifestActionArgsForCall(0)
+				provider, err := config.SQLClient.GetKubernetesProvider("spin-cluster-account")
+				Expect(err).To(BeNil())
+				Expect(provider.Host).To(Equal("https://standby"))
+				Expect(config.Context).ToNot(BeNil())
+			})
+		})
+
+		When("the account has execute groups the user is not in", func() {
+			BeforeEach(func() {
+				req.Header.Set("X-Spinnaker-User", "test-user")
+				fakeSQLClient.ListPermissionsByAccountNamesReturns(map[string]kubernetes.ProviderPermissions{
+					"spin-cluster-account": {
+						Write:   []string{"developers"},
+						Execute: []string{"deployers"},
+					},
+				}, nil)
+				fakeFiatClient.AuthorizeReturns(fiat.Response{
+					Accounts: []fiat.Account{
+						{
+							Name:           "spin-cluster-account",
+							Authorizations: []string{"READ", "WRITE"},
+						},
+					},
+				}, nil)
+			})
+
+			It("returns status forbidden without running any operation", func() {
+				Expect(res.StatusCode).To(Equal(http.StatusForbidden))
+				ce := getClouddriverError()
+				Expect(ce.Message).To(Equal("Access denied to account spin-cluster-account - required authorization: EXECUTE"))
+				Expect(ce.RequiredGroups).To(Equal([]string{"deployers"}))
+				Expect(fakeKubeActionHandler.NewDeployManifestActionCallCount()).To(BeZero())
+			})
+		})
+
+		When("the namespace has execute groups the user is in", func() {
+			BeforeEach(func() {
+				req.Header.Set("X-Spinnaker-User", "test-user")
+				fakeSQLClient.ListPermissionsByAccountNamesReturns(map[string]kubernetes.ProviderPermissions{
+					"spin-cluster-account": {
+						Namespaces: map[string]kubernetes.ProviderNamespacePermissions{
+							"default": {Execute: []string{"deployers"}},
+						},
+					},
+				}, nil)
+				fakeFiatClient.AuthorizeReturns(fiat.Response{
+					Roles: []fiat.Role{{Name: "deployers"}},
+				}, nil)
+			})
+
+			It("runs the operation", func() {
+				Expect(res.StatusCode).To(Equal(http.StatusOK))
+				Expect(fakeKubeActionHandler.NewDeployManifestActionCallCount()).To(Equal(1))
+			})
+		})
+
+		When("the user may not read the source account of a migration", func() {
+			BeforeEach(func() {
+				body = &bytes.Buffer{}
+				body.Write([]byte(`[{"migrateApplication":{"application":"test-app","sourceAccount":"old-account",` +
+					`"sourceNamespace":"default","account":"spin-cluster-account"}}]`))
+				createRequest(http.MethodPost)
+				req.Header.Set("X-Spinnaker-User", "test-user")
+				fakeFiatClient.AuthorizeReturns(fiat.Response{
+					Accounts: []fiat.Account{
+						{
+							Name:           "old-account",
+							Authorizations: []string{"WRITE"},
+						},
+					},
+				}, nil)
+			})
+
+			It("returns status forbidden without running any operation", func() {
+				Expect(res.StatusCode).To(Equal(http.StatusForbidden))
+				ce := getClouddriverError()
+				Expect(ce.Message).To(Equal("Access denied to account old-account - required authorization: READ"))
+				Expect(fakeKubeActionHandler.NewMigrateApplicationActionCallCount()).To(BeZero())
+			})
+		})
+
+		When("the operation is a migration", func() {
+			BeforeEach(func() {
+				body = &bytes.Buffer{}
+				body.Write([]byte(`[{"migrateApplication":{"application":"test-app","sourceAccount":"old-account",` +
+					`"sourceNamespace":"default","account":"spin-cluster-account"}}]`))
+				createRequest(http.MethodPost)
+			})
+
+			It("runs it", func() {
+				Expect(res.StatusCode).To(Equal(http.StatusOK))
+				Expect(fakeKubeActionHandler.NewMigrateApplicationActionCallCount()).To(Equal(1))
+			})
+		})
+
+		When("the namespace is frozen", func() {
+			var status freeze.Status
+
+			BeforeEach(func() {
+				end := time.Date(2026, time.October, 19, 8, 0, 0, 0, time.UTC)
+				status = freeze.Status{
+					Freeze: freeze.Freeze{
+						Name:    "weekend",
+						Message: "No deploys to prod over the weekend.",
+					},
+					Active: true,
+					End:    &end,
+				}
+				fakeFreezeController.ActiveFreezeReturns(status, true)
+			})
+
+			It("returns status locked without running any operation", func() {
+				Expect(res.StatusCode).To(Equal(http.StatusLocked))
+				ce := getClouddriverError()
+				Expect(ce.Error).To(Equal("Locked"))
+				Expect(ce.Message).To(Equal("namespace default of account spin-cluster-account is frozen by weekend " +
+					"until 2026-10-19T08:00:00Z: No deploys to prod over the weekend."))
+				account, namespace, _ := fakeFreezeController.ActiveFreezeArgsForCall(0)
+				Expect(account).To(Equal("spin-cluster-account"))
+				Expect(namespace).To(Equal("default"))
+				Expect(fakeKubeActionHandler.NewDeployManifestActionCallCount()).To(BeZero())
+			})
+
+			When("the freeze allows overriding it", func() {
+				BeforeEach(func() {
+					status.AllowOverride = true
+					fakeFreezeController.ActiveFreezeReturns(status, true)
+				})
+
+				It("says how to override it", func() {
+					Expect(res.StatusCode).To(Equal(http.StatusLocked))
+					ce := getClouddriverError()
+					Expect(ce.Message).To(HaveSuffix(" (set the X-Spinnaker-Freeze-Override header to override)"))
+				})
+
+				When("the override header is set", func() {
+					BeforeEach(func() {
+						req.Header.Set(freeze.HeaderOverride, "hotfix for INC-123")
+					})
+
+					It("runs the operation", func() {
+						Expect(res.StatusCode).To(Equal(http.StatusOK))
+						Expect(fakeKubeActionHandler.NewDeployManifestActionCallCount()).To(Equal(1))
+					})
+				})
+			})
+
+			When("the override header is set but the freeze does not allow it", func() {
+				BeforeEach(func() {
+					req.Header.Set(freeze.HeaderOverride, "hotfix for INC-123")
+				})
+
+				It("returns status locked", func() {
+					Expect(res.StatusCode).To(Equal(http.StatusLocked))
+				})
+			})
+		})
+
+		When("the queue does not admit the operation", func() {
+			BeforeEach(func() {
+				fakeQueue.WaitReturns(errors.New("deploy operation against account spin-cluster-account was not admitted: context deadline exceeded"))
+			})
+
+			It("returns status too many requests without running it", func() {
+				Expect(res.StatusCode).To(Equal(http.StatusTooManyRequests))
+				ce := getClouddriverError()
+				Expect(ce.Error).To(Equal("Too Many Requests"))
+				Expect(ce.Message).To(Equal("deploy operation against account spin-cluster-account was not admitted: context deadline exceeded"))
+				Expect(fakeAction.RunCallCount()).To(BeZero())
+			})
+		})
+
+		When("a cluster the operation changes is locked", func() {
+			BeforeEach(func() {
+				fakeLocker.LockReturns(nil, &lock.ConflictError{
+					Key:   "spin-cluster-account/default/deployment my-app",
+					Owner: "other-execution-id",
+				})
+			})
+
+			It("returns status conflict without running it", func() {
+				Expect(res.StatusCode).To(Equal(http.StatusConflict))
+				ce := getClouddriverError()
+				Expect(ce.Error).To(Equal("Conflict"))
+				Expect(ce.Message).To(Equal("cluster spin-cluster-account/default/deployment my-app is locked by other-execution-id"))
+				Expect(fakeAction.RunCallCount()).To(BeZero())
+			})
+		})
+
+		When("the operation is run by a pipeline execution", func() {
+			var unlocked bool
+
+			BeforeEach(func() {
+				unlocked = false
+				req.Header.Set("X-Spinnaker-Execution-Id", "test-execution-id")
+				fakeLocker.LockReturns(func() { unlocked = true }, nil)
+			})
+
+			It("locks its clusters on behalf of the execution until it is done", func() {
+				Expect(res.StatusCode).To(Equal(http.StatusOK))
+				Expect(fakeLocker.LockCallCount()).To(Equal(1))
+				_, owner, _ := fakeLocker.LockArgsForCall(0)
+				Expect(owner).To(Equal("test-execution-id"))
+				Expect(fakeAction.RunCallCount()).To(Equal(1))
+				Expect(unlocked).To(BeTrue())
+			})
+		})
+
+		When("a pre-apply hook rejects the operation", func() {
+			BeforeEach(func() {
+				fakeHookRunner.PreApplyReturns(nil, &hook.RejectedError{Hook: "require-team-label", Message: "missing label team"})
+			})
+
+			It("returns status unprocessable entity without running it", func() {
+				Expect(res.StatusCode).To(Equal(http.StatusUnprocessableEntity))
+				ce := getClouddriverError()
+				Expect(ce.Message).To(Equal("operation rejected by hook require-team-label: missing label team"))
+				Expect(fakeAction.RunCallCount()).To(BeZero())
+				Expect(fakeHookRunner.PostApplyCallCount()).To(BeZero())
+			})
+		})
+
+		When("a pre-apply hook cannot be called", func() {
+			BeforeEach(func() {
+				fakeHookRunner.PreApplyReturns(nil, errors.New("error calling hook require-team-label: hook returned status 500"))
+			})
+
+			It("returns status bad gateway without running it", func() {
+				Expect(res.StatusCode).To(Equal(http.StatusBadGateway))
+				ce := getClouddriverError()
+				Expect(ce.Message).To(Equal("error calling hook require-team-label: hook returned status 500"))
+				Expect(fakeAction.RunCallCount()).To(BeZero())
+			})
+		})
+
+		When("a pre-apply hook mutates the manifests", func() {
+			BeforeEach(func() {
+				req.Header.Set("X-Spinnaker-User", "test-user")
+				fakeHookRunner.PreApplyReturns([]map[string]interface{}{{"kind": "ConfigMap"}}, nil)
+			})
+
+			It("applies the mutated manifests", func() {
+				Expect(res.StatusCode).To(Equal(http.StatusOK))
+				_, e := fakeHookRunner.PreApplyArgsForCall(0)
+				Expect(e.Operation).To(Equal("deployManifest"))
+				Expect(e.Account).To(Equal("spin-cluster-account"))
+				Expect(e.User).To(Equal("test-user"))
+				Expect(e.Manifests).To(HaveLen(1))
+				config := fakeKubeActionHandler.NewDeployManifestActionArgsForCall(0)
+				Expect(config.Operation.DeployManifest.Manifests).To(Equal([]map[string]interface{}{{"kind": "ConfigMap"}}))
+			})
+
+			It("calls the post-apply hooks", func() {
+				Expect(fakeHookRunner.PostApplyCallCount()).To(Equal(1))
+				e := fakeHookRunner.PostApplyArgsForCall(0)
+				Expect(e.Manifests).To(Equal([]map[string]interface{}{{"kind": "ConfigMap"}}))
+				Expect(e.Error).To(BeEmpty())
+			})
+		})
+
+		When("the operation fails", func() {
+			BeforeEach(func() {
+				fakeHookRunner.MatchesReturns(true)
+				fakeAction.RunReturns(errors.New("error deploying manifest"))
+			})
+
+			It("calls the post-apply hooks with the error", func() {
+				Expect(fakeHookRunner.PostApplyCallCount()).To(Equal(1))
+				Expect(fakeHookRunner.PostApplyArgsForCall(0).Error).To(Equal("error deploying manifest"))
+				Expect(fakeHookRunner.PostStabilityCallCount()).To(BeZero())
+			})
+		})
+
+		When("a post-stability hook matches the operation", func() {
+			BeforeEach(func() {
+				fakeHookRunner.MatchesReturns(true)
+				fakeSQLClient.ListKubernetesResourcesByTaskIDReturns([]kubernetes.Resource{
+					{
+						AccountName: "spin-cluster-account",
+						Kind:        "Pod",
+						Name:        "rss-site",
+						Namespace:   "default",
+						Resource:    "pods",
+					},
+				}, nil)
+				fakeKubeClient.GetReturns(&unstructured.Unstructured{
+					Object: map[string]interface{}{
+						"kind": "Pod",
+						"status": map[string]interface{}{
+							"phase": "Running",
+						},
+					},
+				}, nil)
+			})
+
+			It("calls the post-stability hooks once the resources are stable", func() {
+				Expect(res.StatusCode).To(Equal(http.StatusOK))
+				Eventually(fakeHookRunner.PostStabilityCallCount).Should(Equal(1))
+				stage, _ := fakeHookRunner.MatchesArgsForCall(0)
+				Expect(stage).To(Equal(hook.StagePostStability))
+				e := fakeHookRunner.PostStabilityArgsForCall(0)
+				Expect(e.Phase).To(Equal("STABLE"))
+				Expect(e.Error).To(BeEmpty())
+				kind, name, namespace := fakeKubeClient.GetArgsForCall(0)
+				Expect(kind).To(Equal("pods"))
+				Expect(name).To(Equal("rss-site"))
+				Expect(namespace).To(Equal("default"))
+			})
+		})
+
+		When("undoing a rollout", func() {
+			BeforeEach(func() {
+				body = &bytes.Buffer{}
+				body.Write([]byte(payloadRequestKubernetesOpsUndoRolloutManifest))
+				createRequest(http.MethodPost)
+			})
+
+			It("waits with rollback priority", func() {
+				Expect(res.StatusCode).To(Equal(http.StatusOK))
+				Expect(fakeQueue.WaitCallCount()).To(Equal(1))
+				_, _, p := fakeQueue.WaitArgsForCall(0)
+				Expect(p).To(Equal(queue.PriorityRollback))
+			})
+		})
+
+		When("deploying a manifest", func() {
+			BeforeEach(func() {
+				req.Header.Set("X-Spinnaker-Application", "test-app")
+				req.Header.Set("X-Spinnaker-User", "test-user")
+				fakeSQLClient.ListKubernetesResourcesByTaskIDReturns([]kubernetes.Resource{
+					{
+						AccountName: "spin-cluster-account",
+						Kind:        "Deployment",
+						Name:        "test-deployment",
+						Namespace:   "default",
+					},
+					{
+						AccountName: "other-account",
+						Kind:        "Service",
+						Name:        "test-service",
+						Namespace:   "default",
+					},
+				}, nil)
+			})
+
+			It("notifies of the resources deployed to the account", func() {
+				Expect(res.StatusCode).To(Equal(http.StatusOK))
+				Expect(fakeNotifier.NotifyCallCount()).To(Equal(1))
+				e := fakeNotifier.NotifyArgsForCall(0)
+				Expect(e.Operation).To(Equal("deployManifest"))
+				Expect(e.Account).To(Equal("spin-cluster-account"))
+				Expect(e.Application).To(Equal("test-app"))
+				Expect(e.User).To(Equal("test-user"))
+				Expect(e.TaskID).ToNot(BeEmpty())
+				Expect(e.Resources).To(Equal([]string{"Deployment test-deployment in default"}))
+				Expect(e.Succeeded()).To(BeTrue())
+			})
+
+			It("records the deploy for deploy stats", func() {
+				Expect(fakeSQLClient.CreateDeployCallCount()).To(Equal(1))
+				d := fakeSQLClient.CreateDeployArgsForCall(0)
+				Expect(d.TaskID).ToNot(BeEmpty())
+				Expect(d.Account).To(Equal("spin-cluster-account"))
+				Expect(d.Namespace).To(Equal("default"))
+				Expect(d.Application).To(Equal("test-app"))
+				Expect(d.Error).To(BeEmpty())
+				Expect(d.CreatedAt).To(BeTemporally("~", time.Now(), time.Minute))
+				Expect(fakeSQLClient.SetDeployPhaseCallCount()).To(BeZero())
+			})
+
+			It("records the start and end of the operation as events of the task", func() {
+				Expect(fakeSQLClient.CreateTaskEventCallCount()).To(Equal(2))
+				te := fakeSQLClient.CreateTaskEventArgsForCall(0)
+				Expect(te.TaskID).ToNot(BeEmpty())
+				Expect(te.Type).To(Equal(clouddriver.TaskEventOperationStarted))
+				Expect(te.Message).To(Equal("started deployManifest of account spin-cluster-account"))
+				te = fakeSQLClient.CreateTaskEventArgsForCall(1)
+				Expect(te.Type).To(Equal(clouddriver.TaskEventOperationSucceeded))
+				Expect(te.Message).To(Equal("deployManifest of account spin-cluster-account succeeded"))
+			})
+		})
+
 		When("deploying a manifest returns an error", func() {
 			BeforeEach(func() {
 				fakeAction.RunReturns(errors.New("error deploying manifest"))
@@ -67,6 +545,49 @@ var _ = Describe("Kubernetes", func() {
 				Expect(ce.Error).To(Equal("Internal Server Error"))
 				Expect(ce.Message).To(Equal("error deploying manifest"))
 				Expect(ce.Status).To(Equal(http.StatusInternalServerError))
+				Expect(fakeNotifier.NotifyCallCount()).To(Equal(1))
+				Expect(fakeNotifier.NotifyArgsForCall(0).Error).To(Equal("error deploying manifest"))
+			})
+
+			It("stores the payload for replay", func() {
+				Expect(fakeSQLClient.CreateFailedOperationCallCount()).To(Equal(1))
+				fo := fakeSQLClient.CreateFailedOperationArgsForCall(0)
+				Expect(fo.TaskID).ToNot(BeEmpty())
+				Expect(fo.Payload).To(Equal(payloadRequestKubernetesOpsDeployManifest))
+				Expect(fo.Error).To(Equal("error deploying manifest"))
+			})
+
+			It("records the failed deploy", func() {
+				Expect(fakeSQLClient.CreateDeployCallCount()).To(Equal(1))
+				Expect(fakeSQLClient.CreateDeployArgsForCall(0).Error).To(Equal("error deploying manifest"))
+			})
+
+			It("records the failure as an event of the task", func() {
+				Expect(fakeSQLClient.CreateTaskEventCallCount()).To(Equal(2))
+				te := fakeSQLClient.CreateTaskEventArgsForCall(1)
+				Expect(te.Type).To(Equal(clouddriver.TaskEventOperationFailed))
+				Expect(te.Message).To(Equal("failed: error deploying manifest"))
+			})
+		})
+
+		When("deploying a manifest panics", func() {
+			BeforeEach(func() {
+				fakeAction.RunStub = func() error {
+					panic("nil map")
+				}
+			})
+
+			It("fails the task", func() {
+				Expect(res.StatusCode).To(Equal(http.StatusInternalServerError))
+				ce := getClouddriverError()
+				Expect(ce.Message).To(Equal("panic: nil map"))
+				Expect(fakeNotifier.NotifyArgsForCall(0).Error).To(Equal("panic: nil map"))
+			})
+
+			It("stores the payload for replay", func() {
+				Expect(fakeSQLClient.CreateFailedOperationCallCount()).To(Equal(1))
+				fo := fakeSQLClient.CreateFailedOperationArgsForCall(0)
+				Expect(fo.Error).To(Equal("panic: nil map"))
 			})
 		})
 
@@ -169,6 +690,10 @@ var _ = Describe("Kubernetes", func() {
 				Expect(ce.Error).To(Equal("Internal Server Error"))
 				Expect(ce.Message).To(Equal("error undoing rollout"))
 				Expect(ce.Status).To(Equal(http.StatusInternalServerError))
+				Expect(fakeNotifier.NotifyCallCount()).To(Equal(1))
+				e := fakeNotifier.NotifyArgsForCall(0)
+				Expect(e.Operation).To(Equal("undoRolloutManifest"))
+				Expect(e.Error).To(Equal("error undoing rollout"))
 			})
 		})
 
@@ -189,9 +714,72 @@ var _ = Describe("Kubernetes", func() {
 			})
 		})
 
+		When("creating an application returns an error", func() {
+			BeforeEach(func() {
+				body = &bytes.Buffer{}
+				body.Write([]byte(payloadRequestKubernetesOpsCreateApplication))
+				createRequest(http.MethodPost)
+				req.Header.Set("X-Spinnaker-User", "test-user")
+				fakeAction.RunReturns(errors.New("error creating application"))
+			})
+
+			It("returns status internal server error", func() {
+				Expect(res.StatusCode).To(Equal(http.StatusInternalServerError))
+				ce := getClouddriverError()
+				Expect(ce.Message).To(Equal("error creating application"))
+				config := fakeKubeActionHandler.NewCreateApplicationActionArgsForCall(0)
+				Expect(config.Operation.CreateApplication.Application.Email).To(Equal("test@example.com"))
+				Expect(config.Operation.CreateApplication.Application.Permissions.WRITE).To(Equal([]string{"test-group2"}))
+			})
+		})
+
+		When("the user may not write an application that exists", func() {
+			BeforeEach(func() {
+				body = &bytes.Buffer{}
+				body.Write([]byte(payloadRequestKubernetesOpsCreateApplication))
+				createRequest(http.MethodPost)
+				req.Header.Set("X-Spinnaker-User", "test-user")
+				fakeSQLClient.ListApplicationsReturns([]clouddriver.Application{{Name: "test-application"}}, nil)
+				fakeFiatClient.AuthorizeReturns(fiat.Response{
+					Applications: []fiat.Application{
+						{Name: "test-application", Authorizations: []string{"READ"}},
+					},
+				}, nil)
+			})
+
+			It("returns status forbidden without replacing its permissions", func() {
+				Expect(res.StatusCode).To(Equal(http.StatusForbidden))
+				ce := getClouddriverError()
+				Expect(ce.Message).To(Equal("Access denied to application test-application - required authorization: WRITE"))
+				Expect(fakeKubeActionHandler.NewCreateApplicationActionCallCount()).To(BeZero())
+			})
+		})
+
+		When("deleting an application returns an error", func() {
+			BeforeEach(func() {
+				body = &bytes.Buffer{}
+				body.Write([]byte(payloadRequestKubernetesOpsDeleteApplication))
+				createRequest(http.MethodPost)
+				req.Header.Set("X-Spinnaker-User", "test-user")
+				fakeAction.RunReturns(errors.New("error deleting application"))
+			})
+
+			It("returns status internal server error", func() {
+				Expect(res.StatusCode).To(Equal(http.StatusInternalServerError))
+				ce := getClouddriverError()
+				Expect(ce.Message).To(Equal("error deleting application"))
+				config := fakeKubeActionHandler.NewDeleteApplicationActionArgsForCall(0)
+				Expect(config.Operation.DeleteApplication.Application.Name).To(Equal("test-application"))
+			})
+		})
+
 		When("it succeeds", func() {
 			It("succeeds", func() {
 				Expect(res.StatusCode).To(Equal(http.StatusOK))
+				Expect(fakeQueue.WaitCallCount()).To(Equal(1))
+				_, account, p := fakeQueue.WaitArgsForCall(0)
+				Expect(account).To(Equal("spin-cluster-account"))
+				Expect(p).To(Equal(queue.PriorityDeploy))
 			})
 		})
 	})
@@ -373,6 +373,36 @@ const payloadRequestKubernetesOpsPatchManifest = `[
   }
 ]`
 
+const payloadRequestKubernetesOpsCreateApplication = `[
+  {
+    "createApplication": {
+      "application": {
+        "name": "test-application",
+        "email": "test@example.com",
+        "description": "test description",
+        "permissions": {
+          "READ": [
+            "test-group1"
+          ],
+          "WRITE": [
+            "test-group2"
+          ]
+        }
+      }
+    }
+  }
+]`
+
+const payloadRequestKubernetesOpsDeleteApplication = `[
+  {
+    "deleteApplication": {
+      "application": {
+        "name": "test-application"
+      }
+    }
+  }
+]`
+
 const payloadRequestKubernetesOpsBadManifest = `[
   {
     "deployManifest": {
@@ -415,7 +445,7 @@ const payloadRequestKubernetesOpsBadManifest = `[
 const payloadCredentials = `[
               {
                 "accountType": "provider1",
-                "cacheThreads": 0,
+                "cacheThreads": 1,
                 "challengeDestructiveActions": false,
                 "cloudProvider": "kubernetes",
                 "dockerRegistries": null,
@@ -440,7 +470,7 @@ const payloadCredentials = `[
               },
               {
                 "accountType": "provider2",
-                "cacheThreads": 0,
+                "cacheThreads": 1,
                 "challengeDestructiveActions": false,
                 "cloudProvider": "kubernetes",
                 "dockerRegistries": null,
@@ -518,9 +548,114 @@ const payloadApplications = `[
             }
           ]`
 
+const payloadApplicationsWithMetadata = `[
+            {
+              "attributes": {
+                "name": "test-spinnaker-app1",
+                "email": "test@example.com",
+                "description": "test description",
+                "permissions": {
+                  "READ": [
+                    "test-group1"
+                  ],
+                  "WRITE": [
+                    "test-group2"
+                  ]
+                }
+              },
+              "clusterNames": {
+                "test-account1": [
+                  "test-kind1 test-name1"
+                ]
+              },
+              "name": "test-spinnaker-app1"
+            },
+            {
+              "attributes": {
+                "name": "test-spinnaker-app2"
+              },
+              "clusterNames": {
+                "test-account2": [
+                  "test-kind2 test-name2"
+                ],
+                "test-account3": [
+                  "test-kind3 test-name3"
+                ]
+              },
+              "name": "test-spinnaker-app2"
+            },
+            {
+              "attributes": {
+                "name": "test-spinnaker-app3",
+                "permissions": {
+                  "READ": null,
+                  "WRITE": null
+                }
+              },
+              "clusterNames": {},
+              "name": "test-spinnaker-app3"
+            }
+          ]`
+
+const payloadProjectClusters = `[
+            {
+              "account": "test-account",
+              "stack": "prod",
+              "detail": "*",
+              "applications": [
+                {
+                  "application": "app1",
+                  "lastPush": 1581689523000,
+                  "clusters": [
+                    {
+                      "region": "ns1",
+                      "lastPush": 1581603123000,
+                      "instanceCounts": {
+                        "down": 0,
+                        "outOfService": 0,
+                        "starting": 0,
+                        "total": 2,
+                        "unknown": 0,
+                        "up": 1
+                      }
+                    },
+                    {
+                      "region": "ns2",
+                      "lastPush": 1581689523000,
+                      "instanceCounts": {
+                        "down": 0,
+                        "outOfService": 0,
+                        "starting": 0,
+                        "total": 1,
+                        "unknown": 0,
+                        "up": 1
+                      }
+                    }
+                  ],
+                  "instanceCounts": {
+                    "down": 0,
+                    "outOfService": 0,
+                    "starting": 0,
+                    "total": 3,
+                    "unknown": 0,
+                    "up": 2
+                  }
+                }
+              ],
+              "instanceCounts": {
+                "down": 0,
+                "outOfService": 0,
+                "starting": 0,
+                "total": 3,
+                "unknown": 0,
+                "up": 2
+              }
+            }
+          ]`
+
 const payloadGetAccountCredentials = `{
             "accountType": "test-account",
-            "cacheThreads": 0,
+            "cacheThreads": 1,
             "challengeDestructiveActions": false,
             "cloudProvider": "kubernetes",
             "dockerRegistries": null,
@@ -576,7 +711,7 @@ const payloadGetAccountCredentials = `{
 const payloadCredentialsExpandTrue = `[
               {
                 "accountType": "provider1",
-                "cacheThreads": 0,
+                "cacheThreads": 1,
                 "challengeDestructiveActions": false,
                 "cloudProvider": "kubernetes",
                 "dockerRegistries": null,
@@ -637,7 +772,7 @@ const payloadCredentialsExpandTrue = `[
               },
               {
                 "accountType": "provider2",
-                "cacheThreads": 0,
+                "cacheThreads": 1,
                 "challengeDestructiveActions": false,
                 "cloudProvider": "kubernetes",
                 "dockerRegistries": null,
@@ -701,7 +836,7 @@ const payloadCredentialsExpandTrue = `[
 const payloadCredentialsExpandTrueNoNamespaces = `[
               {
                 "accountType": "provider1",
-                "cacheThreads": 0,
+                "cacheThreads": 1,
                 "challengeDestructiveActions": false,
                 "cloudProvider": "kubernetes",
                 "dockerRegistries": null,
@@ -759,7 +894,7 @@ const payloadCredentialsExpandTrueNoNamespaces = `[
               },
               {
                 "accountType": "provider2",
-                "cacheThreads": 0,
+                "cacheThreads": 1,
                 "challengeDestructiveActions": false,
                 "cloudProvider": "kubernetes",
                 "dockerRegistries": null,
@@ -1252,8 +1387,44 @@ const payloadListServerGroups = `[
               },
               "kind": "",
               "labels": null,
-              "loadBalancers": null,
-              "manifest": null,
+              "loadBalancers": [],
+              "manifest": {
+                "apiVersion": "v1",
+                "kind": "DaemonSet",
+                "metadata": {
+                  "annotations": {
+                    "artifact.spinnaker.io/location": "test-namespace1",
+                    "artifact.spinnaker.io/name": "test-deployment1",
+                    "artifact.spinnaker.io/type": "kubernetes/deployment",
+                    "deployment.kubernetes.io/revision": "19",
+                    "moniker.spinnaker.io/application": "test-deployment1",
+                    "moniker.spinnaker.io/cluster": "deployment test-deployment1"
+                  },
+                  "creationTimestamp": "2020-02-13T14:12:03Z",
+                  "name": "test-ds1",
+                  "namespace": "test-namespace1"
+                },
+                "spec": {
+                  "replicas": 1,
+                  "template": {
+                    "spec": {
+                      "containers": [
+                        {
+                          "image": "test-image1"
+                        },
+                        {
+                          "image": "test-image2"
+                        }
+                      ]
+                    }
+                  }
+                },
+                "status": {
+                  "currentNumberScheduled": 1,
+                  "desiredNumberScheduled": 2,
+                  "numberReady": 1
+                }
+              },
               "moniker": {
                 "app": "test-deployment1",
                 "cluster": "deployment test-deployment1",
@@ -1263,7 +1434,7 @@ const payloadListServerGroups = `[
               "namespace": "test-namespace1",
               "providerType": "",
               "region": "test-namespace1",
-              "securityGroups": null,
+              "securityGroups": [],
               "serverGroupManagers": [
                 {
                   "account": "account1",
@@ -1344,8 +1515,43 @@ const payloadListServerGroups = `[
               },
               "kind": "",
               "labels": null,
-              "loadBalancers": null,
-              "manifest": null,
+              "loadBalancers": [],
+              "manifest": {
+                "apiVersion": "apps/v1",
+                "kind": "ReplicaSet",
+                "metadata": {
+                  "annotations": {
+                    "artifact.spinnaker.io/location": "test-namespace1",
+                    "artifact.spinnaker.io/name": "test-deployment1",
+                    "artifact.spinnaker.io/type": "kubernetes/deployment",
+                    "deployment.kubernetes.io/revision": "19",
+                    "moniker.spinnaker.io/application": "test-deployment1",
+                    "moniker.spinnaker.io/cluster": "deployment test-deployment1"
+                  },
+                  "creationTimestamp": "2020-02-13T14:12:03Z",
+                  "name": "test-rs1",
+                  "namespace": "test-namespace1"
+                },
+                "spec": {
+                  "replicas": 1,
+                  "template": {
+                    "spec": {
+                      "containers": [
+                        {
+                          "image": "test-image1"
+                        },
+                        {
+                          "image": "test-image2"
+                        }
+                      ]
+                    }
+                  }
+                },
+                "status": {
+                  "readyReplicas": 0,
+                  "replicas": 1
+                }
+              },
               "moniker": {
                 "app": "test-deployment1",
                 "cluster": "deployment test-deployment1",
@@ -1355,7 +1561,7 @@ const payloadListServerGroups = `[
               "namespace": "test-namespace1",
               "providerType": "",
               "region": "test-namespace1",
-              "securityGroups": null,
+              "securityGroups": [],
               "serverGroupManagers": [
                 {
                   "account": "account1",
@@ -1436,8 +1642,43 @@ const payloadListServerGroups = `[
               },
               "kind": "",
               "labels": null,
-              "loadBalancers": null,
-              "manifest": null,
+              "loadBalancers": [],
+              "manifest": {
+                "apiVersion": "apps/v1",
+                "kind": "StatefulSet",
+                "metadata": {
+                  "annotations": {
+                    "artifact.spinnaker.io/location": "test-namespace1",
+                    "artifact.spinnaker.io/name": "test-deployment1",
+                    "artifact.spinnaker.io/type": "kubernetes/deployment",
+                    "deployment.kubernetes.io/revision": "19",
+                    "moniker.spinnaker.io/application": "test-deployment1",
+                    "moniker.spinnaker.io/cluster": "deployment test-deployment1"
+                  },
+                  "creationTimestamp": "2020-02-13T14:12:03Z",
+                  "name": "test-rs1",
+                  "namespace": "test-namespace1"
+                },
+                "spec": {
+                  "replicas": 1,
+                  "template": {
+                    "spec": {
+                      "containers": [
+                        {
+                          "image": "test-image1"
+                        },
+                        {
+                          "image": "test-image2"
+                        }
+                      ]
+                    }
+                  }
+                },
+                "status": {
+                  "readyReplicas": 0,
+                  "replicas": 1
+                }
+              },
               "moniker": {
                 "app": "test-deployment1",
                 "cluster": "deployment test-deployment1",
@@ -1447,7 +1688,7 @@ const payloadListServerGroups = `[
               "namespace": "test-namespace1",
               "providerType": "",
               "region": "test-namespace1",
-              "securityGroups": null,
+              "securityGroups": [],
               "serverGroupManagers": [
                 {
                   "account": "account1",
@@ -3098,20 +3339,20 @@ const payloadSearch = `[
                   "type": "instances"
                 },
                 {
-                  "account": "account1",
+                  "account": "account2",
                   "group": "pod",
                   "kubernetesKind": "pod",
-                  "name": "pod test-name2",
+                  "name": "pod test-name1",
                   "namespace": "default",
                   "provider": "kubernetes",
                   "region": "default",
                   "type": "instances"
                 },
                 {
-                  "account": "account2",
+                  "account": "account1",
                   "group": "pod",
                   "kubernetesKind": "pod",
-                  "name": "pod test-name1",
+                  "name": "pod test-name2",
                   "namespace": "default",
                   "provider": "kubernetes",
                   "region": "default",
@@ -3149,20 +3390,20 @@ const payloadSearchWithPageSizeThree = `[
                   "type": "instances"
                 },
                 {
-                  "account": "account1",
+                  "account": "account2",
                   "group": "pod",
                   "kubernetesKind": "pod",
-                  "name": "pod test-name2",
+                  "name": "pod test-name1",
                   "namespace": "default",
                   "provider": "kubernetes",
                   "region": "default",
                   "type": "instances"
                 },
                 {
-                  "account": "account2",
+                  "account": "account1",
                   "group": "pod",
                   "kubernetesKind": "pod",
-                  "name": "pod test-name1",
+                  "name": "pod test-name2",
                   "namespace": "default",
                   "provider": "kubernetes",
                   "region": "default",
@@ -3172,3 +3413,380 @@ const payloadSearchWithPageSizeThree = `[
               "totalMatches": 3
             }
           ]`
+
+const payloadAccountNamespaces = `[
+            "namespace1",
+            "namespace2"
+          ]`
+
+const payloadNamespaces = `[
+            {
+              "name": "provider1",
+              "namespaces": [
+                "namespace1",
+                "namespace2"
+              ]
+            },
+            {
+              "name": "provider2",
+              "namespaces": [
+                "namespace1",
+                "namespace2"
+              ]
+            }
+          ]`
+
+const payloadCredentialsWithDockerRegistry = `[
+              {
+                "accountType": "provider1",
+                "cacheThreads": 1,
+                "challengeDestructiveActions": false,
+                "cloudProvider": "kubernetes",
+                "dockerRegistries": null,
+                "enabled": false,
+                "environment": "provider1",
+                "name": "provider1",
+                "namespaces": null,
+                "permissions": {
+                  "READ": [
+                    "gg_test"
+                  ],
+                  "WRITE": [
+                    "gg_test"
+                  ]
+                },
+                "primaryAccount": false,
+                "providerVersion": "v2",
+                "requiredGroupMembership": [],
+                "skin": "v2",
+                "spinnakerKindMap": null,
+                "type": "kubernetes"
+              },
+              {
+                "accountType": "provider2",
+                "cacheThreads": 1,
+                "challengeDestructiveActions": false,
+                "cloudProvider": "kubernetes",
+                "dockerRegistries": null,
+                "enabled": false,
+                "environment": "provider2",
+                "name": "provider2",
+                "namespaces": null,
+                "permissions": {
+                  "READ": [
+                    "gg_test2"
+                  ],
+                  "WRITE": [
+                    "gg_test2"
+                  ]
+                },
+                "primaryAccount": false,
+                "providerVersion": "v2",
+                "requiredGroupMembership": [],
+                "skin": "v2",
+                "spinnakerKindMap": null,
+                "type": "kubernetes"
+              },
+              {
+                "accountType": "docker-registry",
+                "cacheThreads": 0,
+                "challengeDestructiveActions": false,
+                "cloudProvider": "dockerRegistry",
+                "dockerRegistries": null,
+                "enabled": false,
+                "environment": "docker-registry",
+                "name": "docker-registry",
+                "namespaces": null,
+                "permissions": {
+                  "READ": null,
+                  "WRITE": null
+                },
+                "primaryAccount": false,
+                "providerVersion": "v1",
+                "registry": "https://index.docker.io",
+                "requiredGroupMembership": [],
+                "skin": "v1",
+                "spinnakerKindMap": null,
+                "type": "dockerRegistry"
+              }
+            ]`
+
+const payloadDockerRegistryRepositories = `[
+            "library/nginx",
+            "library/redis"
+          ]`
+
+const payloadDockerRegistryTags = `[
+            "1.19",
+            "1.18",
+            "latest"
+          ]`
+
+const payloadDockerRegistryTagsSortedByTimestamp = `[
+            "latest",
+            "1.18",
+            "1.19"
+          ]`
+
+const payloadFindDockerRegistryImages = `[
+            {
+              "account": "docker-registry",
+              "artifact": {
+                "type": "docker/image",
+                "customKind": false,
+                "name": "index.docker.io/library/nginx",
+                "version": "1.19",
+                "location": "",
+                "reference": "index.docker.io/library/nginx:1.19",
+                "metadata": {
+                  "id": ""
+                },
+                "artifactAccount": ""
+              },
+              "imageId": "index.docker.io/library/nginx:1.19",
+              "registry": "index.docker.io",
+              "repository": "library/nginx",
+              "tag": "1.19"
+            },
+            {
+              "account": "docker-registry",
+              "artifact": {
+                "type": "docker/image",
+                "customKind": false,
+                "name": "index.docker.io/library/nginx",
+                "version": "1.18",
+                "location": "",
+                "reference": "index.docker.io/library/nginx:1.18",
+                "metadata": {
+                  "id": ""
+                },
+                "artifactAccount": ""
+              },
+              "imageId": "index.docker.io/library/nginx:1.18",
+              "registry": "index.docker.io",
+              "repository": "library/nginx",
+              "tag": "1.18"
+            }
+          ]`
+
+const payloadRequestDockerDistributionWebhook = `{
+            "events": [
+              {
+                "action": "push",
+                "target": {
+                  "mediaType": "application/vnd.docker.distribution.manifest.v2+json",
+                  "repository": "library/nginx",
+                  "tag": "1.19"
+                }
+              },
+              {
+                "action": "pull",
+                "target": {
+                  "repository": "library/redis",
+                  "tag": "latest"
+                }
+              }
+            ]
+          }`
+
+const payloadRequestDockerHubWebhook = `{
+            "push_data": {
+              "tag": "latest"
+            },
+            "repository": {
+              "repo_name": "library/nginx"
+            }
+          }`
+
+const payloadRequestKubernetesAdmissionReview = `{
+            "apiVersion": "admission.k8s.io/v1",
+            "kind": "AdmissionReview",
+            "request": {
+              "uid": "705ab4f5-6393-11e8-b7cc-42010a800002",
+              "kind": {
+                "group": "",
+                "version": "v1",
+                "kind": "Namespace"
+              },
+              "resource": {
+                "group": "",
+                "version": "v1",
+                "resource": "namespaces"
+              },
+              "name": "test-namespace",
+              "operation": "CREATE",
+              "userInfo": {
+                "username": "admin"
+              }
+            }
+          }`
+
+const payloadRequestKubernetesAdmissionReviewDeployment = `{
+            "apiVersion": "admission.k8s.io/v1",
+            "kind": "AdmissionReview",
+            "request": {
+              "uid": "705ab4f5-6393-11e8-b7cc-42010a800002",
+              "kind": {
+                "group": "apps",
+                "version": "v1",
+                "kind": "Deployment"
+              },
+              "resource": {
+                "group": "apps",
+                "version": "v1",
+                "resource": "deployments"
+              },
+              "name": "test-deployment",
+              "namespace": "test-namespace",
+              "operation": "UPDATE",
+              "userInfo": {
+                "username": "admin"
+              }
+            }
+          }`
+
+const payloadKubernetesAdmissionReview = `{
+            "apiVersion": "admission.k8s.io/v1",
+            "kind": "AdmissionReview",
+            "response": {
+              "uid": "705ab4f5-6393-11e8-b7cc-42010a800002",
+              "allowed": true
+            }
+          }`
+
+const payloadRequestKubernetesAuditEventList = `{
+            "apiVersion": "audit.k8s.io/v1",
+            "kind": "EventList",
+            "items": [
+              {
+                "verb": "get",
+                "objectRef": {
+                  "resource": "namespaces",
+                  "name": "test-namespace"
+                }
+              },
+              {
+                "verb": "create",
+                "objectRef": {
+                  "resource": "namespaces",
+                  "name": "test-namespace"
+                }
+              },
+              {
+                "verb": "patch",
+                "objectRef": {
+                  "resource": "deployments",
+                  "namespace": "test-namespace",
+                  "name": "test-deployment",
+                  "apiGroup": "apps"
+                }
+              }
+            ]
+          }`
+
+const payloadRecordings = `[
+            {
+              "executionId": "test-execution-id",
+              "time": "0001-01-01T00:00:00Z",
+              "duration": 0,
+              "request": {
+                "method": "POST",
+                "url": "/kubernetes/ops",
+                "headers": null
+              },
+              "response": {
+                "status": 200
+              },
+              "kubernetesCalls": []
+            }
+          ]`
+
+const payloadCapabilities = `{
+            "operations": [
+              "deployManifest",
+              "scaleManifest",
+              "cleanupArtifacts",
+              "deleteManifest",
+              "undoRolloutManifest",
+              "rollingRestartManifest",
+              "patchManifest",
+              "runJob",
+              "createApplication",
+              "deleteApplication",
+              "migrateApplication"
+            ],
+            "artifactTypes": [
+              "embedded/base64",
+              "github/file",
+              "helm/chart",
+              "http/file"
+            ],
+            "accountFeatures": {
+              "kubernetes": [
+                "dynamicAccounts",
+                "namespaces",
+                "readPermissions",
+                "writePermissions",
+                "spinnakerKindMap",
+                "cacheInvalidation"
+              ],
+              "dockerRegistry": [
+                "repositories",
+                "tags",
+                "webhooks"
+              ]
+            }
+          }`
+
+const payloadFreezes = `[
+            {
+              "name": "weekend",
+              "message": "No deploys to prod over the weekend.",
+              "schedule": "0 17 * * FRI",
+              "duration": "63h",
+              "accounts": [
+                "prod-account"
+              ],
+              "allowOverride": true,
+              "active": true,
+              "start": "2026-10-16T17:00:00Z",
+              "end": "2026-10-19T08:00:00Z"
+            },
+            {
+              "name": "holidays",
+              "schedule": "0 0 24 DEC *",
+              "duration": "72h",
+              "namespaces": [
+                "payments"
+              ],
+              "active": false
+            }
+          ]`
+
+const payloadFreezesTestAccount = `[
+            {
+              "name": "holidays",
+              "schedule": "0 0 24 DEC *",
+              "duration": "72h",
+              "namespaces": [
+                "payments"
+              ],
+              "active": false
+            }
+          ]`
+
+const payloadFreezesDefaultNamespace = `[
+            {
+              "name": "weekend",
+              "message": "No deploys to prod over the weekend.",
+              "schedule": "0 17 * * FRI",
+              "duration": "63h",
+              "accounts": [
+                "prod-account"
+              ],
+              "allowOverride": true,
+              "active": true,
+              "start": "2026-10-16T17:00:00Z",
+              "end": "2026-10-19T08:00:00Z"
+            }
+          ]`
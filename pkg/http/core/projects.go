@@ -0,0 +1,177 @@
+package core
+
+import (
+	"fmt"
+	"net/http"
+	"sort"
+	"sync"
+
+	clouddriver "github.com/billiford/go-clouddriver/pkg"
+	"github.com/billiford/go-clouddriver/pkg/kubernetes"
+	"github.com/billiford/go-clouddriver/pkg/project"
+	"github.com/gin-gonic/gin"
+)
+
+type ProjectClusters []ProjectCluster
+
+// ProjectCluster is a cluster of a project definition with the server groups
+// of each application that match it.
+type ProjectCluster struct {
+	Account        string                      `json:"account"`
+	Stack          string                      `json:"stack"`
+	Detail         string                      `json:"detail"`
+	Applications   []ProjectClusterApplication `json:"applications"`
+	InstanceCounts InstanceCounts              `json:"instanceCounts"`
+}
+
+type ProjectClusterApplication struct {
+	Application    string                 `json:"application"`
+	LastPush       int64                  `json:"lastPush"`
+	Clusters       []ProjectClusterRegion `json:"clusters"`
+	InstanceCounts InstanceCounts         `json:"instanceCounts"`
+}
+
+// ProjectClusterRegion sums the server groups of an application in a region,
+// which for kubernetes is a namespace.
+type ProjectClusterRegion struct {
+	Region         string         `json:"region"`
+	LastPush       int64          `json:"lastPush"`
+	InstanceCounts InstanceCounts `json:"instanceCounts"`
+}
+
+// ListProjectClusters lists the clusters of a project, aggregating the server
+// groups of its applications across accounts.
+//
+// https://github.com/spinnaker/clouddriver/blob/master/clouddriver-web/src/main/groovy/com/netflix/spinnaker/clouddriver/controllers/ProjectController.groovy
+func ListProjectClusters(c *gin.Context) {
+	pc := project.ControllerInstance(c)
+	name := c.Param("project")
+
+	p, ok := pc.GetProject(name)
+	if !ok {
+		clouddriver.WriteError(c, http.StatusNotFound, fmt.Errorf("project %s not found", name))
+		return
+	}
+
+	// List the server groups of each application in each account of the project once.
+	type key struct {
+		account     string
+		application string
+	}
+
+	keys := map[key]bool{}
+
+	for _, cluster := range p.Config.Clusters {
+		for _, application := range projectClusterApplications(p, cluster) {
+			keys[key{account: cluster.Account, application: application}] = true
+		}
+	}
+
+	wg := &sync.WaitGroup{}
+	sgs := make(chan ServerGroup, 100000)
+
+	wg.Add(len(keys))
+
+	for k := range keys {
+		go listServerGroups(c, wg, sgs, k.account, k.application)
+	}
+
+	wg.Wait()
+
+	close(sgs)
+
+	serverGroups := []ServerGroup{}
+	for sg := range sgs {
+		serverGroups = append(serverGroups, sg)
+	}
+
+	response := ProjectClusters{}
+
+	for _, cluster := range p.Config.Clusters {
+		pcl := ProjectCluster{
+			Account:      cluster.Account,
+			Stack:        cluster.Stack,
+			Detail:       cluster.Detail,
+			Applications: []ProjectClusterApplication{},
+		}
+
+		for _, application := range projectClusterApplications(p, cluster) {
+			pca := newProjectClusterApplication(cluster, application, serverGroups)
+			if len(pca.Clusters) == 0 {
+				continue
+			}
+
+			pcl.Applications = append(pcl.Applications, pca)
+			pcl.InstanceCounts = addInstanceCounts(pcl.InstanceCounts, pca.InstanceCounts)
+		}
+
+		response = append(response, pcl)
+	}
+
+	c.JSON(http.StatusOK, response)
+}
+
+// projectClusterApplications returns the applications of a cluster of a
+// project, which default to the project's applications.
+func projectClusterApplications(p project.Project, cluster project.Cluster) []string {
+	if len(cluster.Applications) > 0 {
+		return cluster.Applications
+	}
+
+	return p.Config.Applications
+}
+
+func newProjectClusterApplication(cluster project.Cluster, application string,
+	serverGroups []ServerGroup) ProjectClusterApplication {
+	pca := ProjectClusterApplication{
+		Application: application,
+		Clusters:    []ProjectClusterRegion{},
+	}
+	regions := map[string]*ProjectClusterRegion{}
+
+	for _, sg := range serverGroups {
+		if sg.Account != cluster.Account ||
+			sg.Labels[kubernetes.LabelKubernetesName] != application ||
+			!cluster.Matches(application, sg.Moniker.Cluster) {
+			continue
+		}
+
+		region, ok := regions[sg.Region]
+		if !ok {
+			region = &ProjectClusterRegion{Region: sg.Region}
+			regions[sg.Region] = region
+		}
+
+		region.InstanceCounts = addInstanceCounts(region.InstanceCounts, sg.InstanceCounts)
+		pca.InstanceCounts = addInstanceCounts(pca.InstanceCounts, sg.InstanceCounts)
+
+		if sg.CreatedTime > region.LastPush {
+			region.LastPush = sg.CreatedTime
+		}
+
+		if sg.CreatedTime > pca.LastPush {
+			pca.LastPush = sg.CreatedTime
+		}
+	}
+
+	for _, region := range regions {
+		pca.Clusters = append(pca.Clusters, *region)
+	}
+
+	sort.Slice(pca.Clusters, func(i, j int) bool {
+		return pca.Clusters[i].Region < pca.Clusters[j].Region
+	})
+
+	return pca
+}
+
+func addInstanceCounts(a, b InstanceCounts) InstanceCounts {
+	return InstanceCounts{
+		Down:         a.Down + b.Down,
+		OutOfService: a.OutOfService + b.OutOfService,
+		Starting:     a.Starting + b.Starting,
+		Total:        a.Total + b.Total,
+		Unknown:      a.Unknown + b.Unknown,
+		Up:           a.Up + b.Up,
+	}
+}
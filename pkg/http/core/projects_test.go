@@ -0,0 +1,105 @@
+package core_test
+
+import (
+	"net/http"
+
+	"github.com/billiford/go-clouddriver/pkg/project"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+func replicaSet(name, namespace, application, cluster, created string, replicas, ready int64) unstructured.Unstructured {
+	return unstructured.Unstructured{
+		Object: map[string]interface{}{
+			"kind":       "ReplicaSet",
+			"apiVersion": "apps/v1",
+			"metadata": map[string]interface{}{
+				"name":              name,
+				"namespace":         namespace,
+				"creationTimestamp": created,
+				"labels": map[string]interface{}{
+					"app.kubernetes.io/name": application,
+				},
+				"annotations": map[string]interface{}{
+					"moniker.spinnaker.io/cluster": cluster,
+				},
+			},
+			"spec": map[string]interface{}{
+				"replicas": replicas,
+			},
+			"status": map[string]interface{}{
+				"replicas":      replicas,
+				"readyReplicas": ready,
+			},
+		},
+	}
+}
+
+var _ = Describe("Project", func() {
+	Describe("#ListProjectClusters", func() {
+		BeforeEach(func() {
+			setup()
+			uri = svr.URL + "/projects/test-project/clusters"
+			createRequest(http.MethodGet)
+			fakeProjectController.GetProjectReturns(project.Project{
+				Name: "test-project",
+				Config: project.Config{
+					Applications: []string{"app1", "app2"},
+					Clusters: []project.Cluster{
+						{
+							Account: "test-account",
+							Stack:   "prod",
+							Detail:  "*",
+						},
+					},
+				},
+			}, true)
+			fakeKubeClient.ListResourceCalls(func(resource string, lo metav1.ListOptions) (*unstructured.UnstructuredList, error) {
+				list := &unstructured.UnstructuredList{Items: []unstructured.Unstructured{}}
+				if resource != "replicaSets" {
+					return list, nil
+				}
+
+				if lo.LabelSelector == "app.kubernetes.io/managed-by=spinnaker,app.kubernetes.io/name=app1" {
+					list.Items = append(list.Items,
+						replicaSet("app1-prod-v001", "ns1", "app1", "replicaSet app1-prod", "2020-02-13T14:12:03Z", 2, 1),
+						replicaSet("app1-prod-canary-v001", "ns2", "app1", "replicaSet app1-prod-canary", "2020-02-14T14:12:03Z", 1, 1),
+						replicaSet("app1-staging-v001", "ns1", "app1", "replicaSet app1-staging", "2020-02-15T14:12:03Z", 3, 3),
+					)
+				}
+
+				return list, nil
+			})
+		})
+
+		AfterEach(func() {
+			teardown()
+		})
+
+		JustBeforeEach(func() {
+			doRequest()
+		})
+
+		When("the project does not exist", func() {
+			BeforeEach(func() {
+				fakeProjectController.GetProjectReturns(project.Project{}, false)
+			})
+
+			It("returns status not found", func() {
+				Expect(res.StatusCode).To(Equal(http.StatusNotFound))
+				ce := getClouddriverError()
+				Expect(ce.Message).To(Equal("project test-project not found"))
+			})
+		})
+
+		When("it succeeds", func() {
+			It("aggregates the matching server groups", func() {
+				Expect(res.StatusCode).To(Equal(http.StatusOK))
+				validateResponse(payloadProjectClusters)
+				Expect(fakeProjectController.GetProjectArgsForCall(0)).To(Equal("test-project"))
+			})
+		})
+	})
+})
@@ -0,0 +1,24 @@
+package core
+
+import (
+	"errors"
+	"net/http"
+
+	clouddriver "github.com/billiford/go-clouddriver/pkg"
+	"github.com/billiford/go-clouddriver/pkg/recorder"
+	"github.com/gin-gonic/gin"
+)
+
+var errRecordingDisabled = errors.New("request recording is disabled, set RECORD_REQUESTS=true to enable it")
+
+// ListRecordings returns the recorded requests of a pipeline execution
+// that are still in the recorder's buffer.
+func ListRecordings(c *gin.Context) {
+	r := recorder.Instance(c)
+	if r == nil {
+		clouddriver.WriteError(c, http.StatusNotFound, errRecordingDisabled)
+		return
+	}
+
+	c.JSON(http.StatusOK, r.List(c.Param("executionId")))
+}
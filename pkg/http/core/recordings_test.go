@@ -0,0 +1,90 @@
+package core_test
+
+import (
+	"net/http"
+
+	"github.com/billiford/go-clouddriver/pkg/fiat"
+	"github.com/billiford/go-clouddriver/pkg/recorder"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Recordings", func() {
+	Describe("#ListRecordings", func() {
+		BeforeEach(func() {
+			setup()
+			uri = svr.URL + "/admin/recordings/test-execution-id"
+			createRequest(http.MethodGet)
+			req.Header.Set("X-Spinnaker-User", "test-admin")
+			fakeFiatClient.AuthorizeReturns(fiat.Response{Name: "test-admin", Admin: true}, nil)
+			fakeRecorder.ListReturns([]*recorder.Recording{
+				{
+					ExecutionID: "test-execution-id",
+					Request: recorder.Request{
+						Method: http.MethodPost,
+						URL:    "/kubernetes/ops",
+					},
+					Response: recorder.Response{
+						Status: http.StatusOK,
+					},
+					KubernetesCalls: []recorder.KubernetesCall{},
+				},
+			})
+		})
+
+		AfterEach(func() {
+			teardown()
+		})
+
+		JustBeforeEach(func() {
+			doRequest()
+		})
+
+		It("returns the recordings of the execution", func() {
+			Expect(res.StatusCode).To(Equal(http.StatusOK))
+			Expect(fakeRecorder.ListArgsForCall(0)).To(Equal("test-execution-id"))
+			validateResponse(payloadRecordings)
+		})
+	})
+
+	Describe("recording requests", func() {
+		BeforeEach(func() {
+			setup()
+			uri = svr.URL + "/health"
+			createRequest(http.MethodGet)
+		})
+
+		AfterEach(func() {
+			teardown()
+		})
+
+		JustBeforeEach(func() {
+			doRequest()
+		})
+
+		When("the request is not made for an execution", func() {
+			It("does not record it", func() {
+				Expect(res.StatusCode).To(Equal(http.StatusOK))
+				Expect(fakeRecorder.RecordCallCount()).To(Equal(0))
+			})
+		})
+
+		When("the request is made for an execution", func() {
+			BeforeEach(func() {
+				req.Header.Set(recorder.HeaderSpinnakerExecutionID, "test-execution-id")
+			})
+
+			It("records the sanitized request and response", func() {
+				Expect(res.StatusCode).To(Equal(http.StatusOK))
+				Expect(fakeRecorder.RecordCallCount()).To(Equal(1))
+				rec := fakeRecorder.RecordArgsForCall(0)
+				Expect(rec.ExecutionID).To(Equal("test-execution-id"))
+				Expect(rec.Request.Method).To(Equal(http.MethodGet))
+				Expect(rec.Request.URL).To(Equal("/health"))
+				Expect(rec.Request.Headers["Api-Key"]).To(Equal([]string{"[REDACTED]"}))
+				Expect(rec.Response.Status).To(Equal(http.StatusOK))
+			})
+		})
+	})
+})
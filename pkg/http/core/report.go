@@ -0,0 +1,187 @@
+package core
+
+import (
+	"encoding/csv"
+	"fmt"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+
+	clouddriver "github.com/billiford/go-clouddriver/pkg"
+	"github.com/billiford/go-clouddriver/pkg/arcade"
+	"github.com/billiford/go-clouddriver/pkg/kubernetes"
+	"github.com/billiford/go-clouddriver/pkg/sql"
+	"github.com/gin-gonic/gin"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+// Listing every namespace of a large cluster takes longer than a health check.
+const accountReportTimeout = 30 * time.Second
+
+// AccountReport is a row of the account health report.
+type AccountReport struct {
+	Name              string     `json:"name"`
+	Host              string     `json:"host"`
+	Reachable         bool       `json:"reachable"`
+	Error             string     `json:"error,omitempty"`
+	KubernetesVersion string     `json:"kubernetesVersion,omitempty"`
+	Namespaces        *int       `json:"namespaces"`
+	LastDeployTime    *time.Time `json:"lastDeployTime"`
+	APICalls          int64      `json:"apiCalls"`
+	APIErrors         int64      `json:"apiErrors"`
+	APIErrorRate      float64    `json:"apiErrorRate"`
+}
+
+var accountReportHeader = []string{
+	"name",
+	"host",
+	"reachable",
+	"error",
+	"kubernetesVersion",
+	"namespaces",
+	"lastDeployTime",
+	"apiCalls",
+	"apiErrors",
+	"apiErrorRate",
+}
+
+// GetAccountReport reports on every account: whether its cluster is
+// reachable, its Kubernetes version and number of namespaces, when it was
+// last deployed to, and the calls made to its API server and how many failed
+// since this instance started. The report is JSON, or CSV with ?format=csv.
+func GetAccountReport(c *gin.Context) {
+	sc := sql.ReadOnlyInstance(c)
+	ac := arcade.Instance(c)
+	kc := kubernetes.ControllerInstance(c)
+	format := c.DefaultQuery("format", "json")
+
+	if format != "json" && format != "csv" {
+		clouddriver.WriteError(c, http.StatusBadRequest, fmt.Errorf("unknown format %q, must be json or csv", format))
+		return
+	}
+
+	providers, err := sc.ListKubernetesProviders()
+	if err != nil {
+		clouddriver.WriteError(c, http.StatusInternalServerError, err)
+		return
+	}
+
+	deployTimes, err := sc.ListKubernetesLastDeployTimes()
+	if err != nil {
+		clouddriver.WriteError(c, http.StatusInternalServerError, fmt.Errorf("error listing deploy times: %w", err))
+		return
+	}
+
+	reports := make([]AccountReport, len(providers))
+	wg := &sync.WaitGroup{}
+
+	for i, provider := range providers {
+		wg.Add(1)
+
+		go func(i int, provider kubernetes.Provider) {
+			defer wg.Done()
+
+			// Count calls before reporting, so the report's own calls are left out.
+			cc := kc.CallCount(provider.Host)
+
+			r := reportAccount(provider, ac, kc)
+			r.APICalls = cc.Calls
+			r.APIErrors = cc.Errors
+			r.APIErrorRate = cc.ErrorRate()
+
+			if t, ok := deployTimes[provider.Name]; ok {
+				r.LastDeployTime = &t
+			}
+
+			reports[i] = r
+		}(i, provider)
+	}
+
+	wg.Wait()
+
+	if format == "json" {
+		c.JSON(http.StatusOK, reports)
+		return
+	}
+
+	c.Header("Content-Disposition",
+		fmt.Sprintf(`attachment; filename="accounts-%s.csv"`, time.Now().UTC().Format("2006-01-02")))
+	c.Header("Content-Type", "text/csv")
+	c.Status(http.StatusOK)
+
+	w := csv.NewWriter(c.Writer)
+	_ = w.Write(accountReportHeader)
+
+	for _, r := range reports {
+		namespaces := ""
+		if r.Namespaces != nil {
+			namespaces = strconv.Itoa(*r.Namespaces)
+		}
+
+		lastDeployTime := ""
+		if r.LastDeployTime != nil {
+			lastDeployTime = r.LastDeployTime.UTC().Format(time.RFC3339)
+		}
+
+		_ = w.Write([]string{
+			r.Name,
+			r.Host,
+			strconv.FormatBool(r.Reachable),
+			r.Error,
+			r.KubernetesVersion,
+			namespaces,
+			lastDeployTime,
+			strconv.FormatInt(r.APICalls, 10),
+			strconv.FormatInt(r.APIErrors, 10),
+			strconv.FormatFloat(r.APIErrorRate, 'f', 4, 64),
+		})
+	}
+
+	w.Flush()
+}
+
+// reportAccount gets the version and namespaces of the cluster of an
+// account. A cluster is reachable if it returns its version; the
+// namespaces are left unset if the account may not list them.
+func reportAccount(provider kubernetes.Provider, ac arcade.Client, kc kubernetes.Controller) AccountReport {
+	r := AccountReport{
+		Name: provider.Name,
+		Host: provider.Host,
+	}
+
+	client, err := adminClient(provider, ac, kc, accountReportTimeout)
+	if err != nil {
+		r.Error = err.Error()
+		return r
+	}
+
+	info, err := client.ServerVersion()
+	if err != nil {
+		r.Error = err.Error()
+		return r
+	}
+
+	r.Reachable = true
+
+	if info != nil {
+		r.KubernetesVersion = info.GitVersion
+	}
+
+	namespaces, err := client.ListMetadataByGVR(schema.GroupVersionResource{Version: "v1", Resource: "namespaces"},
+		metav1.ListOptions{})
+	if err != nil {
+		if !k8serrors.IsForbidden(err) {
+			r.Error = err.Error()
+		}
+
+		return r
+	}
+
+	n := len(namespaces.Items)
+	r.Namespaces = &n
+
+	return r
+}
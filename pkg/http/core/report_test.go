@@ -0,0 +1,152 @@
+package core_test
+
+import (
+	"encoding/json"
+	"errors"
+	"io/ioutil"
+	"net/http"
+	"time"
+
+	"github.com/billiford/go-clouddriver/pkg/fiat"
+	"github.com/billiford/go-clouddriver/pkg/http/core"
+	"github.com/billiford/go-clouddriver/pkg/kubernetes"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/version"
+)
+
+var _ = Describe("Report", func() {
+	Describe("#GetAccountReport", func() {
+		var (
+			reports    []core.AccountReport
+			deployedAt time.Time
+		)
+
+		BeforeEach(func() {
+			setup()
+			uri = svr.URL + "/admin/reports/accounts"
+			createRequest(http.MethodGet)
+			req.Header.Set("X-Spinnaker-User", "test-admin")
+			fakeFiatClient.AuthorizeReturns(fiat.Response{Name: "test-admin", Admin: true}, nil)
+			fakeSQLClient.ListKubernetesProvidersReturns([]kubernetes.Provider{
+				{
+					Name: "test-account",
+					Host: "https://test-host",
+				},
+			}, nil)
+			deployedAt = time.Date(2020, 10, 1, 12, 0, 0, 0, time.UTC)
+			fakeSQLClient.ListKubernetesLastDeployTimesReturns(map[string]time.Time{
+				"test-account": deployedAt,
+			}, nil)
+			fakeKubeController.CallCountReturns(kubernetes.CallCount{Calls: 200, Errors: 3})
+			fakeKubeClient.ServerVersionReturns(&version.Info{GitVersion: "v1.18.9"}, nil)
+			fakeKubeClient.ListMetadataByGVRReturns(&metav1.PartialObjectMetadataList{
+				Items: []metav1.PartialObjectMetadata{{}, {}},
+			}, nil)
+			reports = nil
+		})
+
+		AfterEach(func() {
+			teardown()
+		})
+
+		JustBeforeEach(func() {
+			doRequest()
+
+			if res.StatusCode == http.StatusOK && res.Header.Get("Content-Type") != "text/csv" {
+				b, _ := ioutil.ReadAll(res.Body)
+				Expect(json.Unmarshal(b, &reports)).To(Succeed())
+			}
+		})
+
+		When("the format is unknown", func() {
+			BeforeEach(func() {
+				uri = svr.URL + "/admin/reports/accounts?format=xml"
+				createRequest(http.MethodGet)
+				req.Header.Set("X-Spinnaker-User", "test-admin")
+			})
+
+			It("returns status bad request", func() {
+				Expect(res.StatusCode).To(Equal(http.StatusBadRequest))
+				ce := getClouddriverError()
+				Expect(ce.Message).To(Equal(`unknown format "xml", must be json or csv`))
+			})
+		})
+
+		When("listing deploy times fails", func() {
+			BeforeEach(func() {
+				fakeSQLClient.ListKubernetesLastDeployTimesReturns(nil, errors.New("db down"))
+			})
+
+			It("returns status internal server error", func() {
+				Expect(res.StatusCode).To(Equal(http.StatusInternalServerError))
+				ce := getClouddriverError()
+				Expect(ce.Message).To(Equal("error listing deploy times: db down"))
+			})
+		})
+
+		When("the cluster cannot be reached", func() {
+			BeforeEach(func() {
+				fakeKubeClient.ServerVersionReturns(nil, errors.New("dial tcp: i/o timeout"))
+			})
+
+			It("reports the account as unreachable", func() {
+				Expect(res.StatusCode).To(Equal(http.StatusOK))
+				Expect(reports).To(HaveLen(1))
+				Expect(reports[0].Reachable).To(BeFalse())
+				Expect(reports[0].Error).To(Equal("dial tcp: i/o timeout"))
+				Expect(reports[0].Namespaces).To(BeNil())
+				Expect(reports[0].APICalls).To(Equal(int64(200)))
+			})
+		})
+
+		When("the account may not list namespaces", func() {
+			BeforeEach(func() {
+				fakeKubeClient.ListMetadataByGVRReturns(nil, k8serrors.NewForbidden(schema.GroupResource{Resource: "namespaces"}, "", errors.New("forbidden")))
+			})
+
+			It("leaves out the namespaces", func() {
+				Expect(res.StatusCode).To(Equal(http.StatusOK))
+				Expect(reports[0].Reachable).To(BeTrue())
+				Expect(reports[0].Error).To(BeEmpty())
+				Expect(reports[0].Namespaces).To(BeNil())
+			})
+		})
+
+		When("it succeeds", func() {
+			It("reports on each account", func() {
+				Expect(res.StatusCode).To(Equal(http.StatusOK))
+				Expect(reports).To(HaveLen(1))
+				r := reports[0]
+				Expect(r.Name).To(Equal("test-account"))
+				Expect(r.Reachable).To(BeTrue())
+				Expect(r.KubernetesVersion).To(Equal("v1.18.9"))
+				Expect(*r.Namespaces).To(Equal(2))
+				Expect(r.LastDeployTime.Equal(deployedAt)).To(BeTrue())
+				Expect(r.APICalls).To(Equal(int64(200)))
+				Expect(r.APIErrors).To(Equal(int64(3)))
+				Expect(r.APIErrorRate).To(Equal(0.015))
+				Expect(fakeKubeController.CallCountArgsForCall(0)).To(Equal("https://test-host"))
+			})
+		})
+
+		When("the format is csv", func() {
+			BeforeEach(func() {
+				uri = svr.URL + "/admin/reports/accounts?format=csv"
+				createRequest(http.MethodGet)
+				req.Header.Set("X-Spinnaker-User", "test-admin")
+			})
+
+			It("returns the report as csv", func() {
+				Expect(res.StatusCode).To(Equal(http.StatusOK))
+				Expect(res.Header.Get("Content-Disposition")).To(HavePrefix(`attachment; filename="accounts-`))
+				b, _ := ioutil.ReadAll(res.Body)
+				Expect(string(b)).To(Equal("name,host,reachable,error,kubernetesVersion,namespaces,lastDeployTime,apiCalls,apiErrors,apiErrorRate\n" +
+					"test-account,https://test-host,true,,v1.18.9,2,2020-10-01T12:00:00Z,200,3,0.0150\n"))
+			})
+		})
+	})
+})
@@ -1,15 +1,24 @@
 package core
 
 import (
+	"encoding/base64"
 	"errors"
 	"fmt"
+	"log"
 	"net/http"
+	"sort"
 	"strconv"
 	"strings"
+	"sync"
+	"unicode"
 
 	clouddriver "github.com/billiford/go-clouddriver/pkg"
+	"github.com/billiford/go-clouddriver/pkg/arcade"
+	"github.com/billiford/go-clouddriver/pkg/kubernetes"
 	"github.com/billiford/go-clouddriver/pkg/sql"
 	"github.com/gin-gonic/gin"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/rest"
 )
 
 type SearchResponse []Page
@@ -37,16 +46,28 @@ type PageResult struct {
 }
 
 // Generic search endpoint. It currently just handles searching previously deployed
-// Kubernetes resources. It queries the clouddriver DB and does NOT reach out to
-// clusters for live data.
+// Kubernetes resources.
+//
+// When the "type" query param is a Spinnaker category, such as serverGroups,
+// "q" is searched for in the names of the resources of that category, as
+// Deck's search does. Otherwise "type" is a kind and "q" the namespace its
+// resources are listed in, as Deck's manifest selector does. Only the first
+// is paginated, by the "page" query param, and only it reaches out to clusters,
+// for the server groups created by deployed server group managers.
 func Search(c *gin.Context) {
-	sc := sql.Instance(c)
+	sc := sql.ReadOnlyInstance(c)
 	pageSize, _ := strconv.Atoi(c.Query("pageSize"))
 	namespace := c.Query("q")
 	// The "type" query param is the kubernetes kind.
 	kind := c.Query("type")
 	// Get all accounts the user has access to.
 	accounts := strings.Split(c.GetHeader("X-Spinnaker-Accounts"), ",")
+	sort.Strings(accounts)
+
+	if isSearchType(kind) {
+		searchNames(c, accounts, kind, namespace, pageSize)
+		return
+	}
 
 	if kind == "" || namespace == "" {
 		clouddriver.WriteError(c, http.StatusBadRequest,
@@ -68,6 +89,9 @@ func Search(c *gin.Context) {
 		if err != nil {
 			continue
 		}
+
+		sort.Strings(names)
+
 		for _, name := range names {
 			t := "unclassified"
 			if _, ok := spinnakerKindMap[kind]; ok {
@@ -90,6 +114,12 @@ func Search(c *gin.Context) {
 		}
 	}
 
+	// Accounts and names are sorted so the same results fill the page, which
+	// is then sorted by name.
+	sort.Slice(results, func(i, j int) bool {
+		return results[i].listKey().less(results[j].listKey())
+	})
+
 	sr := SearchResponse{}
 	page := Page{
 		PageNumber:   1,
@@ -102,3 +132,281 @@ func Search(c *gin.Context) {
 
 	c.JSON(http.StatusOK, sr)
 }
+
+// isSearchType returns true if t is a category resources are searched by
+// name in.
+func isSearchType(t string) bool {
+	if t == "unclassified" {
+		return false
+	}
+
+	i := sort.SearchStrings(kubernetes.KindCategories, t)
+
+	return i < len(kubernetes.KindCategories) && kubernetes.KindCategories[i] == t
+}
+
+// searchNames responds with the page of resources of the accounts in category
+// whose names contain term. Resources are matched by the kind maps of their
+// accounts, so a kind mapped to serverGroups is found as a server group.
+func searchNames(c *gin.Context, accounts []string, category, term string, pageSize int) {
+	sc := sql.ReadOnlyInstance(c)
+
+	if term == "" {
+		clouddriver.WriteError(c, http.StatusBadRequest,
+			fmt.Errorf("must provide query param 'q' to search the names of %s", category))
+		return
+	}
+
+	page, err := strconv.Atoi(c.DefaultQuery("page", "1"))
+	if err != nil || page < 1 {
+		clouddriver.WriteError(c, http.StatusBadRequest,
+			errors.New("query param 'page' must be a positive number"))
+		return
+	}
+
+	names := []string{}
+
+	for _, account := range accounts {
+		if account != "" {
+			names = append(names, account)
+		}
+	}
+
+	rs, err := sc.ListKubernetesResourcesByAccountNames(names...)
+	if err != nil {
+		clouddriver.WriteError(c, http.StatusInternalServerError, err)
+		return
+	}
+
+	kindMaps, err := sc.ListKindMappingsByAccountNames(names...)
+	if err != nil {
+		clouddriver.WriteError(c, http.StatusInternalServerError, err)
+		return
+	}
+
+	accountKindMaps := map[string]map[string]string{}
+	results := []PageResult{}
+	// Server group managers, such as deployments, whose server groups may
+	// match the term.
+	managers := []kubernetes.Resource{}
+
+	for _, r := range rs {
+		kindMap, ok := accountKindMaps[r.AccountName]
+		if !ok {
+			kindMap = kindMapFor(kindMaps[r.AccountName])
+			accountKindMaps[r.AccountName] = kindMap
+		}
+
+		kind := lowercaseFirst(r.Kind)
+
+		switch kindMap[kind] {
+		case category:
+			if nameContains(r.Name, term) {
+				results = append(results, newSearchResult(r.AccountName, kind, r.Name, r.Namespace,
+					category, r.SpinnakerApp, r.Cluster))
+			}
+		case "serverGroupManagers":
+			// The server groups of a manager are named after it, so they can
+			// only match if its name contains the term or the term starts with
+			// its name.
+			if category == "serverGroups" &&
+				(nameContains(r.Name, term) || strings.HasPrefix(strings.ToLower(term), strings.ToLower(r.Name)+"-")) {
+				managers = append(managers, r)
+			}
+		}
+	}
+
+	results = append(results, searchManagedServerGroups(c, managers, term)...)
+	results = uniqueSearchResults(results)
+
+	sort.Slice(results, func(i, j int) bool {
+		return results[i].listKey().less(results[j].listKey())
+	})
+
+	total := len(results)
+	start := (page - 1) * pageSize
+	end := start + pageSize
+
+	if start > total {
+		start = total
+	}
+
+	if end > total {
+		end = total
+	}
+
+	c.JSON(http.StatusOK, SearchResponse{
+		{
+			PageNumber:   page,
+			PageSize:     pageSize,
+			Query:        term,
+			Results:      results[start:end],
+			TotalMatches: total,
+		},
+	})
+}
+
+// searchManagedServerGroups lists the replica sets owned by managers from
+// their clusters, concurrently across accounts, and returns those whose names
+// contain term. Accounts whose clusters cannot be reached are skipped, so
+// their server groups are missing rather than failing the search.
+func searchManagedServerGroups(c *gin.Context, managers []kubernetes.Resource, term string) []PageResult {
+	byAccount := map[string][]kubernetes.Resource{}
+	for _, m := range managers {
+		byAccount[m.AccountName] = append(byAccount[m.AccountName], m)
+	}
+
+	wg := &sync.WaitGroup{}
+	ch := make(chan PageResult, 100000)
+
+	wg.Add(len(byAccount))
+
+	for account, ms := range byAccount {
+		go searchAccountServerGroups(c, wg, ch, account, ms, term)
+	}
+
+	wg.Wait()
+
+	close(ch)
+
+	results := []PageResult{}
+	for r := range ch {
+		results = append(results, r)
+	}
+
+	return results
+}
+
+func searchAccountServerGroups(c *gin.Context, wg *sync.WaitGroup, ch chan PageResult,
+	account string, managers []kubernetes.Resource, term string) {
+	defer wg.Done()
+
+	sc := sql.ReadOnlyInstance(c)
+	kc := kubernetes.ControllerInstance(c)
+	ac := arcade.Instance(c)
+
+	provider, err := sc.GetKubernetesProvider(account)
+	if err != nil {
+		log.Println("unable to get kubernetes provider for account", account)
+		return
+	}
+
+	cd, err := base64.StdEncoding.DecodeString(provider.CAData)
+	if err != nil {
+		log.Println("error decoding ca data for account", account)
+		return
+	}
+
+	token, err := ac.Token()
+	if err != nil {
+		log.Println("error getting token", err.Error())
+		return
+	}
+
+	config := &rest.Config{
+		Host:        provider.Host,
+		BearerToken: token,
+		TLSClientConfig: rest.TLSClientConfig{
+			CAData: cd,
+		},
+	}
+
+	client, err := kubernetes.NewProviderClient(kc, provider, config)
+	if err != nil {
+		log.Println("error creating dynamic client for account", account)
+		return
+	}
+
+	// Replica sets are listed once for each application, as server groups
+	// are labeled with the application that deployed them.
+	byApplication := map[string][]kubernetes.Resource{}
+	for _, m := range managers {
+		byApplication[m.SpinnakerApp] = append(byApplication[m.SpinnakerApp], m)
+	}
+
+	for application, ms := range byApplication {
+		lo := metav1.ListOptions{
+			LabelSelector:  kubernetes.ManagedApplicationLabelSelector(application),
+			TimeoutSeconds: &listTimeout,
+		}
+
+		replicaSets, err := client.ListResource("replicaSets", lo)
+		if err != nil {
+			log.Printf("error listing replicaSets of account %s: %s\n", account, err.Error())
+			continue
+		}
+
+		for _, rs := range replicaSets.Items {
+			if !nameContains(rs.GetName(), term) {
+				continue
+			}
+
+			for _, m := range ms {
+				if rs.GetNamespace() == m.Namespace && isOwnedBy(rs.GetOwnerReferences(), m) {
+					ch <- newSearchResult(account, "replicaSet", rs.GetName(), rs.GetNamespace(),
+						"serverGroups", application, m.Cluster)
+				}
+			}
+		}
+	}
+}
+
+func isOwnedBy(refs []metav1.OwnerReference, r kubernetes.Resource) bool {
+	for _, ref := range refs {
+		if strings.EqualFold(ref.Kind, r.Kind) && ref.Name == r.Name {
+			return true
+		}
+	}
+
+	return false
+}
+
+func newSearchResult(account, kind, name, namespace, category, application, cluster string) PageResult {
+	return PageResult{
+		Account:        account,
+		Group:          kind,
+		KubernetesKind: kind,
+		Name:           fmt.Sprintf("%s %s", kind, name),
+		Namespace:      namespace,
+		Provider:       "kubernetes",
+		Region:         namespace,
+		Type:           category,
+		Application:    application,
+		Cluster:        cluster,
+	}
+}
+
+// uniqueSearchResults returns results without those found more than once,
+// such as a replica set deployed by Spinnaker that is also owned by a
+// deployment.
+func uniqueSearchResults(results []PageResult) []PageResult {
+	seen := map[string]bool{}
+	unique := []PageResult{}
+
+	for _, r := range results {
+		key := r.Account + " " + r.Namespace + " " + r.Name
+		if seen[key] {
+			continue
+		}
+
+		seen[key] = true
+
+		unique = append(unique, r)
+	}
+
+	return unique
+}
+
+func nameContains(name, term string) bool {
+	return strings.Contains(strings.ToLower(name), strings.ToLower(term))
+}
+
+// lowercaseFirst returns kind as it is keyed in kind maps, such as
+// replicaSet for ReplicaSet.
+func lowercaseFirst(kind string) string {
+	for i, v := range kind {
+		return string(unicode.ToLower(v)) + kind[i+1:]
+	}
+
+	return ""
+}
@@ -1,11 +1,16 @@
 package core_test
 
 import (
+	"encoding/json"
 	"errors"
+	"io/ioutil"
 	"net/http"
 
+	"github.com/billiford/go-clouddriver/pkg/http/core"
+	"github.com/billiford/go-clouddriver/pkg/kubernetes"
 	. "github.com/onsi/ginkgo"
 	. "github.com/onsi/gomega"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 )
 
 var _ = Describe("Search", func() {
@@ -98,4 +103,235 @@ var _ = Describe("Search", func() {
 			})
 		})
 	})
+
+	Describe("#Search by name", func() {
+		var sr core.SearchResponse
+
+		BeforeEach(func() {
+			setup()
+			uri = svr.URL + "/search?pageSize=500&q=TEST&type=serverGroups"
+			sr = nil
+
+			fakeSQLClient.ListKubernetesResourcesByAccountNamesReturns([]kubernetes.Resource{
+				{
+					AccountName:  "account1",
+					Kind:         "ReplicaSet",
+					Name:         "test-rs",
+					Namespace:    "default",
+					SpinnakerApp: "test-app",
+					Cluster:      "replicaSet test-rs",
+				},
+				{
+					AccountName:  "account1",
+					Kind:         "Deployment",
+					Name:         "test-deployment",
+					Namespace:    "default",
+					SpinnakerApp: "test-app",
+					Cluster:      "deployment test-deployment",
+				},
+				{
+					AccountName:  "account2",
+					Kind:         "Service",
+					Name:         "test-service",
+					Namespace:    "default",
+					SpinnakerApp: "test-app",
+					Cluster:      "service test-service",
+				},
+				{
+					AccountName:  "account2",
+					Kind:         "StatefulSet",
+					Name:         "other",
+					Namespace:    "default",
+					SpinnakerApp: "test-app",
+				},
+			}, nil)
+			fakeKubeClient.ListResourceReturns(&unstructured.UnstructuredList{
+				Items: []unstructured.Unstructured{
+					newSearchReplicaSet("test-deployment-abc", "default", "test-deployment"),
+					newSearchReplicaSet("test-deployment-def", "other-namespace", "test-deployment"),
+					newSearchReplicaSet("test-deployment-ghi", "default", "other-deployment"),
+				},
+			}, nil)
+		})
+
+		AfterEach(func() {
+			teardown()
+		})
+
+		JustBeforeEach(func() {
+			createRequest(http.MethodGet)
+			req.Header.Add("X-Spinnaker-Accounts", "account1,,account2")
+			doRequest()
+
+			if res.StatusCode == http.StatusOK {
+				b, _ := ioutil.ReadAll(res.Body)
+				Expect(json.Unmarshal(b, &sr)).To(Succeed())
+			}
+		})
+
+		When("q is not provided", func() {
+			BeforeEach(func() {
+				uri = svr.URL + "/search?pageSize=500&type=serverGroups"
+			})
+
+			It("returns status bad request", func() {
+				Expect(res.StatusCode).To(Equal(http.StatusBadRequest))
+				ce := getClouddriverError()
+				Expect(ce.Message).To(Equal("must provide query param 'q' to search the names of serverGroups"))
+			})
+		})
+
+		When("page is not a positive number", func() {
+			BeforeEach(func() {
+				uri = svr.URL + "/search?pageSize=500&q=test&type=serverGroups&page=0"
+			})
+
+			It("returns status bad request", func() {
+				Expect(res.StatusCode).To(Equal(http.StatusBadRequest))
+				ce := getClouddriverError()
+				Expect(ce.Message).To(Equal("query param 'page' must be a positive number"))
+			})
+		})
+
+		When("listing resources returns an error", func() {
+			BeforeEach(func() {
+				fakeSQLClient.ListKubernetesResourcesByAccountNamesReturns(nil, errors.New("error listing resources"))
+			})
+
+			It("returns status internal server error", func() {
+				Expect(res.StatusCode).To(Equal(http.StatusInternalServerError))
+				ce := getClouddriverError()
+				Expect(ce.Message).To(Equal("error listing resources"))
+			})
+		})
+
+		When("listing kind mappings returns an error", func() {
+			BeforeEach(func() {
+				fakeSQLClient.ListKindMappingsByAccountNamesReturns(nil, errors.New("error listing kind mappings"))
+			})
+
+			It("returns status internal server error", func() {
+				Expect(res.StatusCode).To(Equal(http.StatusInternalServerError))
+			})
+		})
+
+		When("listing replica sets returns an error", func() {
+			BeforeEach(func() {
+				fakeKubeClient.ListResourceReturns(nil, errors.New("error listing replica sets"))
+			})
+
+			It("returns the deployed server groups", func() {
+				Expect(res.StatusCode).To(Equal(http.StatusOK))
+				Expect(sr).To(HaveLen(1))
+				Expect(sr[0].Results).To(HaveLen(1))
+				Expect(sr[0].Results[0].Name).To(Equal("replicaSet test-rs"))
+			})
+		})
+
+		When("a kind of an account is mapped to the category", func() {
+			BeforeEach(func() {
+				fakeSQLClient.ListKindMappingsByAccountNamesReturns(map[string]map[string]string{
+					"account2": {"service": "serverGroups"},
+				}, nil)
+			})
+
+			It("finds resources of the kind", func() {
+				Expect(res.StatusCode).To(Equal(http.StatusOK))
+				Expect(sr[0].Results).To(HaveLen(3))
+				Expect(sr[0].Results[2].Account).To(Equal("account2"))
+				Expect(sr[0].Results[2].Name).To(Equal("service test-service"))
+			})
+		})
+
+		When("the results fill more than a page", func() {
+			BeforeEach(func() {
+				uri = svr.URL + "/search?pageSize=1&q=test&type=serverGroups&page=2"
+			})
+
+			It("returns the page", func() {
+				Expect(res.StatusCode).To(Equal(http.StatusOK))
+				Expect(sr[0].PageNumber).To(Equal(2))
+				Expect(sr[0].PageSize).To(Equal(1))
+				Expect(sr[0].TotalMatches).To(Equal(2))
+				Expect(sr[0].Results).To(HaveLen(1))
+				Expect(sr[0].Results[0].Name).To(Equal("replicaSet test-rs"))
+			})
+		})
+
+		When("the page is past the last one", func() {
+			BeforeEach(func() {
+				uri = svr.URL + "/search?pageSize=500&q=test&type=serverGroups&page=3"
+			})
+
+			It("returns no results", func() {
+				Expect(res.StatusCode).To(Equal(http.StatusOK))
+				Expect(sr[0].TotalMatches).To(Equal(2))
+				Expect(sr[0].Results).To(BeEmpty())
+			})
+		})
+
+		When("it succeeds", func() {
+			It("succeeds", func() {
+				Expect(res.StatusCode).To(Equal(http.StatusOK))
+				Expect(fakeSQLClient.ListKubernetesResourcesByAccountNamesArgsForCall(0)).To(Equal([]string{"account1", "account2"}))
+				Expect(fakeKubeClient.ListResourceCallCount()).To(Equal(1))
+				resource, lo := fakeKubeClient.ListResourceArgsForCall(0)
+				Expect(resource).To(Equal("replicaSets"))
+				Expect(lo.LabelSelector).To(Equal("app.kubernetes.io/managed-by=spinnaker,app.kubernetes.io/name=test-app"))
+				Expect(sr).To(Equal(core.SearchResponse{
+					{
+						PageNumber: 1,
+						PageSize:   500,
+						Query:      "TEST",
+						Results: []core.PageResult{
+							{
+								Account:        "account1",
+								Group:          "replicaSet",
+								KubernetesKind: "replicaSet",
+								Name:           "replicaSet test-deployment-abc",
+								Namespace:      "default",
+								Provider:       "kubernetes",
+								Region:         "default",
+								Type:           "serverGroups",
+								Application:    "test-app",
+								Cluster:        "deployment test-deployment",
+							},
+							{
+								Account:        "account1",
+								Group:          "replicaSet",
+								KubernetesKind: "replicaSet",
+								Name:           "replicaSet test-rs",
+								Namespace:      "default",
+								Provider:       "kubernetes",
+								Region:         "default",
+								Type:           "serverGroups",
+								Application:    "test-app",
+								Cluster:        "replicaSet test-rs",
+							},
+						},
+						TotalMatches: 2,
+					},
+				}))
+			})
+		})
+	})
 })
+
+func newSearchReplicaSet(name, namespace, owner string) unstructured.Unstructured {
+	return unstructured.Unstructured{
+		Object: map[string]interface{}{
+			"metadata": map[string]interface{}{
+				"name":      name,
+				"namespace": namespace,
+				"ownerReferences": []interface{}{
+					map[string]interface{}{
+						"apiVersion": "apps/v1",
+						"kind":       "Deployment",
+						"name":       owner,
+						"uid":        "test-uid",
+					},
+				},
+			},
+		},
+	}
+}
@@ -0,0 +1,181 @@
+package core
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+
+	clouddriver "github.com/billiford/go-clouddriver/pkg"
+	"github.com/billiford/go-clouddriver/pkg/arcade"
+	"github.com/billiford/go-clouddriver/pkg/artifact"
+	"github.com/billiford/go-clouddriver/pkg/freeze"
+	"github.com/billiford/go-clouddriver/pkg/http/core/kubernetes"
+	kube "github.com/billiford/go-clouddriver/pkg/kubernetes"
+	"github.com/billiford/go-clouddriver/pkg/sql"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+// Simulation is what the operations of a request would do.
+type Simulation struct {
+	Operations []SimulatedOperation `json:"operations"`
+}
+
+// SimulatedOperation is what an operation would do: the manifests it would
+// apply, the resources it would change and the warnings it would have. The
+// error is set if it would fail, and the operations after it are not
+// simulated.
+type SimulatedOperation struct {
+	Operation string                   `json:"operation"`
+	Account   string                   `json:"account"`
+	Manifests []map[string]interface{} `json:"manifests"`
+	Resources []string                 `json:"resources"`
+	Warnings  []string                 `json:"warnings"`
+	Error     string                   `json:"error,omitempty"`
+}
+
+// SimulateKubernetesOperation runs the operations of a request as
+// CreateKubernetesOperation would, binding artifacts, versioning and
+// checking manifests, but only sends server-side dry-run requests to
+// clusters and stores nothing. Hooks are not called, and operations are
+// neither queued nor locked. Operations that only change Spinnaker, such as
+// createApplication, cannot be simulated.
+func SimulateKubernetesOperation(c *gin.Context) {
+	ko := kubernetes.Operations{}
+	taskID := uuid.New().String()
+	ah := kubernetes.ActionHandlerInstance(c)
+	sc := sql.Instance(c)
+	fc := freeze.ControllerInstance(c)
+
+	err := c.ShouldBindJSON(&ko)
+	if err != nil {
+		clouddriver.WriteError(c, http.StatusBadRequest, err)
+		return
+	}
+
+	for _, req := range ko {
+		if req.CreateApplication != nil || req.DeleteApplication != nil || req.MigrateApplication != nil {
+			clouddriver.WriteError(c, http.StatusBadRequest,
+				fmt.Errorf("operation %s cannot be simulated", req.Name()))
+			return
+		}
+	}
+
+	status, err := validateOperations(c, sc, fc, ko)
+	if err != nil {
+		clouddriver.WriteError(c, status, err)
+		return
+	}
+
+	ssc := kubernetes.NewSimulatedSQLClient(sc)
+	simulation := Simulation{Operations: []SimulatedOperation{}}
+
+	for _, req := range ko {
+		config := kubernetes.ActionConfig{
+			ArcadeClient:                  arcade.Instance(c),
+			ArtifactCredentialsController: artifact.CredentialsControllerInstance(c),
+			KubeController:                kube.ControllerInstance(c),
+			SQLClient:                     ssc,
+			ID:                            taskID,
+			Application:                   c.GetHeader("X-Spinnaker-Application"),
+			Operation:                     req,
+			Context:                       c.Request.Context(),
+		}
+
+		if cluster := req.TargetCluster(); cluster != "" {
+			config.SQLClient = kubernetes.NewTargetedSQLClient(ssc, req.Account(), cluster)
+		}
+
+		so := SimulatedOperation{
+			Operation: req.Name(),
+			Account:   req.Account(),
+			Manifests: []map[string]interface{}{},
+			Resources: []string{},
+			Warnings:  []string{},
+		}
+
+		recorded := len(ssc.Resources())
+		err = runAction(req.Name(), simulatedAction(ah, config))
+
+		// Resources recorded by a failed action are still shown.
+		if rerr := so.addResources(ssc.Resources()[recorded:]); rerr != nil {
+			clouddriver.WriteError(c, http.StatusInternalServerError, rerr)
+			return
+		}
+
+		if err != nil {
+			so.Error = err.Error()
+		}
+
+		simulation.Operations = append(simulation.Operations, so)
+
+		if err != nil {
+			break
+		}
+	}
+
+	c.JSON(http.StatusOK, simulation)
+}
+
+// addResources adds the resources an operation recorded, with their
+// warnings and the manifests that would have been applied. The data of
+// secrets, which may have been decrypted, is redacted.
+func (so *SimulatedOperation) addResources(resources []kube.Resource) error {
+	for _, r := range resources {
+		so.Resources = append(so.Resources, fmt.Sprintf("%s %s in %s", r.Kind, r.Name, r.Namespace))
+
+		if r.Warnings != "" {
+			so.Warnings = append(so.Warnings, strings.Split(r.Warnings, "\n")...)
+		}
+
+		if r.Manifest == "" {
+			continue
+		}
+
+		manifest := map[string]interface{}{}
+
+		err := json.Unmarshal([]byte(r.Manifest), &manifest)
+		if err != nil {
+			return err
+		}
+
+		so.Manifests = append(so.Manifests, kube.RedactSecret(&unstructured.Unstructured{Object: manifest}).Object)
+	}
+
+	return nil
+}
+
+// simulatedAction returns the action of an operation that can be simulated.
+func simulatedAction(ah kubernetes.ActionHandler, config kubernetes.ActionConfig) kubernetes.Action {
+	req := config.Operation
+
+	switch {
+	case req.DeployManifest != nil:
+		return ah.NewDeployManifestAction(config)
+	case req.DeleteManifest != nil:
+		return ah.NewDeleteManifestAction(config)
+	case req.ScaleManifest != nil:
+		return ah.NewScaleManifestAction(config)
+	case req.CleanupArtifacts != nil:
+		return ah.NewCleanupArtifactsAction(config)
+	case req.RollingRestartManifest != nil:
+		return ah.NewRollingRestartAction(config)
+	case req.RunJob != nil:
+		return ah.NewRunJobAction(config)
+	case req.UndoRolloutManifest != nil:
+		return ah.NewRollbackAction(config)
+	case req.PatchManifest != nil:
+		return ah.NewPatchManifestAction(config)
+	default:
+		return noAction{}
+	}
+}
+
+// noAction is the action of an unknown operation, which does nothing.
+type noAction struct{}
+
+func (noAction) Run() error {
+	return nil
+}
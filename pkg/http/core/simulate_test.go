@@ -0,0 +1,184 @@
+package core_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"io/ioutil"
+	"net/http"
+
+	"github.com/billiford/go-clouddriver/pkg/fiat"
+	"github.com/billiford/go-clouddriver/pkg/http/core"
+	kube "github.com/billiford/go-clouddriver/pkg/http/core/kubernetes"
+	"github.com/billiford/go-clouddriver/pkg/kubernetes"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Simulate", func() {
+	Describe("#SimulateKubernetesOperation", func() {
+		var (
+			simulation core.Simulation
+			provider   kubernetes.Provider
+		)
+
+		BeforeEach(func() {
+			setup()
+			uri = svr.URL + "/kubernetes/ops/simulate"
+			body.Write([]byte(payloadRequestKubernetesOpsDeployManifest))
+			createRequest(http.MethodPost)
+			simulation = core.Simulation{}
+			provider = kubernetes.Provider{}
+			fakeKubeActionHandler.NewDeployManifestActionStub = func(config kube.ActionConfig) kube.Action {
+				provider, _ = config.SQLClient.GetKubernetesProvider("spin-cluster-account")
+				config.SQLClient.CreateKubernetesResource(kubernetes.Resource{
+					Kind:      "Pod",
+					Name:      "rss-site",
+					Namespace: "default",
+					Warnings:  "warning: no resource limits\nwarning: latest tag",
+					Manifest:  `{"kind":"Pod","metadata":{"name":"rss-site"}}`,
+				})
+
+				return fakeAction
+			}
+		})
+
+		AfterEach(func() {
+			teardown()
+		})
+
+		JustBeforeEach(func() {
+			doRequest()
+
+			if res.StatusCode == http.StatusOK {
+				b, _ := ioutil.ReadAll(res.Body)
+				Expect(json.Unmarshal(b, &simulation)).To(Succeed())
+			}
+		})
+
+		When("the request body is bad data", func() {
+			BeforeEach(func() {
+				body = &bytes.Buffer{}
+				body.Write([]byte("dasdf[]dsf;;"))
+				createRequest(http.MethodPost)
+			})
+
+			It("returns status bad request", func() {
+				Expect(res.StatusCode).To(Equal(http.StatusBadRequest))
+			})
+		})
+
+		When("an operation only changes Spinnaker", func() {
+			BeforeEach(func() {
+				body = &bytes.Buffer{}
+				body.Write([]byte(payloadRequestKubernetesOpsCreateApplication))
+				createRequest(http.MethodPost)
+				req.Header.Set("X-Spinnaker-User", "test-user")
+			})
+
+			It("returns status bad request", func() {
+				Expect(res.StatusCode).To(Equal(http.StatusBadRequest))
+				ce := getClouddriverError()
+				Expect(ce.Message).To(Equal("operation createApplication cannot be simulated"))
+				Expect(fakeKubeActionHandler.NewCreateApplicationActionCallCount()).To(BeZero())
+			})
+		})
+
+		When("the account is read-only", func() {
+			BeforeEach(func() {
+				fakeSQLClient.GetKubernetesProviderReturns(kubernetes.Provider{
+					Name:     "spin-cluster-account",
+					ReadOnly: true,
+				}, nil)
+			})
+
+			It("returns status forbidden without simulating the operation", func() {
+				Expect(res.StatusCode).To(Equal(http.StatusForbidden))
+				ce := getClouddriverError()
+				Expect(ce.Message).To(Equal("account spin-cluster-account is read-only, operation deployManifest is not allowed"))
+				Expect(fakeKubeActionHandler.NewDeployManifestActionCallCount()).To(BeZero())
+			})
+		})
+
+		When("the account has execute groups the user is not in", func() {
+			BeforeEach(func() {
+				req.Header.Set("X-Spinnaker-User", "test-user")
+				fakeSQLClient.ListPermissionsByAccountNamesReturns(map[string]kubernetes.ProviderPermissions{
+					"spin-cluster-account": {
+						Execute: []string{"deployers"},
+					},
+				}, nil)
+				fakeFiatClient.AuthorizeReturns(fiat.Response{
+					Accounts: []fiat.Account{
+						{
+							Name:           "spin-cluster-account",
+							Authorizations: []string{"READ", "WRITE"},
+						},
+					},
+				}, nil)
+			})
+
+			It("returns status forbidden without simulating the operation", func() {
+				Expect(res.StatusCode).To(Equal(http.StatusForbidden))
+				ce := getClouddriverError()
+				Expect(ce.Message).To(Equal("Access denied to account spin-cluster-account - required authorization: EXECUTE"))
+				Expect(fakeKubeActionHandler.NewDeployManifestActionCallCount()).To(BeZero())
+			})
+		})
+
+		When("the operation would apply a secret", func() {
+			BeforeEach(func() {
+				fakeKubeActionHandler.NewDeployManifestActionStub = func(config kube.ActionConfig) kube.Action {
+					config.SQLClient.CreateKubernetesResource(kubernetes.Resource{
+						Kind:      "Secret",
+						Name:      "test-secret",
+						Namespace: "default",
+						Manifest:  `{"kind":"Secret","metadata":{"name":"test-secret"},"stringData":{"password":"password"}}`,
+					})
+
+					return fakeAction
+				}
+			})
+
+			It("returns it without its data", func() {
+				Expect(res.StatusCode).To(Equal(http.StatusOK))
+				Expect(simulation.Operations[0].Manifests[0]["stringData"]).To(Equal(map[string]interface{}{"password": kubernetes.Redacted}))
+			})
+		})
+
+		When("the operation would fail", func() {
+			BeforeEach(func() {
+				fakeAction.RunReturns(errors.New("error applying manifest"))
+			})
+
+			It("returns the error", func() {
+				Expect(res.StatusCode).To(Equal(http.StatusOK))
+				Expect(simulation.Operations).To(HaveLen(1))
+				Expect(simulation.Operations[0].Error).To(Equal("error applying manifest"))
+				Expect(fakeSQLClient.CreateFailedOperationCallCount()).To(BeZero())
+			})
+		})
+
+		It("dry-runs the operation and returns the manifests it would apply", func() {
+			Expect(res.StatusCode).To(Equal(http.StatusOK))
+			Expect(provider.DryRun()).To(BeTrue())
+			Expect(simulation.Operations).To(HaveLen(1))
+			so := simulation.Operations[0]
+			Expect(so.Operation).To(Equal("deployManifest"))
+			Expect(so.Account).To(Equal("spin-cluster-account"))
+			Expect(so.Resources).To(Equal([]string{"Pod rss-site in default"}))
+			Expect(so.Warnings).To(Equal([]string{"warning: no resource limits", "warning: latest tag"}))
+			Expect(so.Manifests).To(Equal([]map[string]interface{}{
+				{
+					"kind":     "Pod",
+					"metadata": map[string]interface{}{"name": "rss-site"},
+				},
+			}))
+			Expect(so.Error).To(BeEmpty())
+			Expect(fakeSQLClient.CreateKubernetesResourceCallCount()).To(BeZero())
+			Expect(fakeSQLClient.CreateDeployCallCount()).To(BeZero())
+			Expect(fakeHookRunner.PreApplyCallCount()).To(BeZero())
+			Expect(fakeQueue.WaitCallCount()).To(BeZero())
+		})
+	})
+})
@@ -0,0 +1,62 @@
+package core
+
+import "sort"
+
+// listKey orders the items of list responses so the same items are always
+// listed in the same order: by name, then oldest first, then by account and
+// namespace to tell apart items of the same name and age.
+type listKey struct {
+	name        string
+	createdTime int64
+	account     string
+	namespace   string
+}
+
+func (k listKey) less(o listKey) bool {
+	if k.name != o.name {
+		return k.name < o.name
+	}
+
+	if k.createdTime != o.createdTime {
+		return k.createdTime < o.createdTime
+	}
+
+	if k.account != o.account {
+		return k.account < o.account
+	}
+
+	return k.namespace < o.namespace
+}
+
+func (sgm ServerGroupManager) listKey() listKey {
+	return listKey{name: sgm.Name, createdTime: sgm.CreatedTime, account: sgm.Account, namespace: sgm.Namespace}
+}
+
+func (sg ServerGroupManagerServerGroup) listKey() listKey {
+	return listKey{name: sg.Name, account: sg.Account, namespace: sg.Namespace}
+}
+
+func (lb LoadBalancer) listKey() listKey {
+	return listKey{name: lb.Name, createdTime: lb.CreatedTime, account: lb.Account, namespace: lb.Region}
+}
+
+func (sg ServerGroup) listKey() listKey {
+	return listKey{name: sg.Name, createdTime: sg.CreatedTime, account: sg.Account, namespace: sg.Namespace}
+}
+
+func (i Instance) listKey() listKey {
+	return listKey{name: i.Name, createdTime: i.CreatedTime}
+}
+
+func (r PageResult) listKey() listKey {
+	return listKey{name: r.Name, account: r.Account, namespace: r.Namespace}
+}
+
+// sortedStrings returns a sorted copy of s, leaving s as it is for those
+// sharing it, such as caches.
+func sortedStrings(s []string) []string {
+	sorted := append([]string{}, s...)
+	sort.Strings(sorted)
+
+	return sorted
+}
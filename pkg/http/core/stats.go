@@ -0,0 +1,121 @@
+package core
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	clouddriver "github.com/billiford/go-clouddriver/pkg"
+	"github.com/billiford/go-clouddriver/pkg/kubernetes"
+	"github.com/billiford/go-clouddriver/pkg/sql"
+	"github.com/gin-gonic/gin"
+)
+
+// KeyStats is set to the stats of a request, which are filtered to the
+// accounts and applications the user may read before they are returned.
+const KeyStats = `Stats`
+
+// AccountRequestStats are the requests this instance made to the API server
+// of an account, keyed by window such as "5m".
+type AccountRequestStats struct {
+	Account     string           `json:"account"`
+	Host        string           `json:"host"`
+	Total       map[string]int64 `json:"total"`
+	Requests    []RequestStats   `json:"requests"`
+	Connections ConnectionStats  `json:"connections"`
+}
+
+// ConnectionStats are how many requests this instance made to the API server
+// of an account on a new connection and on a reused one since it started.
+type ConnectionStats struct {
+	New       int64   `json:"new"`
+	Reused    int64   `json:"reused"`
+	ReuseRate float64 `json:"reuseRate"`
+}
+
+// RequestStats are the requests with a verb made to a resource, keyed by
+// window.
+type RequestStats struct {
+	Verb     string           `json:"verb"`
+	Resource string           `json:"resource"`
+	Requests map[string]int64 `json:"requests"`
+}
+
+// ListRequestStats returns the requests this instance made to the API server
+// of each account passed in as the comma separated query param 'accounts',
+// or of every account if none are passed in, by verb and resource over the
+// last minute, 5 minutes, 15 minutes and hour, and how often they reused a
+// connection.
+func ListRequestStats(c *gin.Context) {
+	sc := sql.ReadOnlyInstance(c)
+	kc := kubernetes.ControllerInstance(c)
+
+	accounts := map[string]bool{}
+
+	for _, account := range strings.Split(c.Query("accounts"), ",") {
+		if account != "" {
+			accounts[account] = true
+		}
+	}
+
+	providers, err := sc.ListKubernetesProviders()
+	if err != nil {
+		clouddriver.WriteError(c, http.StatusInternalServerError, err)
+		return
+	}
+
+	stats := []AccountRequestStats{}
+
+	for _, provider := range providers {
+		if len(accounts) > 0 && !accounts[provider.Name] {
+			continue
+		}
+
+		ars := AccountRequestStats{
+			Account:  provider.Name,
+			Host:     provider.Host,
+			Total:    map[string]int64{},
+			Requests: []RequestStats{},
+		}
+
+		for _, w := range kubernetes.RequestWindows {
+			ars.Total[windowName(w)] = 0
+		}
+
+		cc := kc.CallCount(provider.Host)
+		ars.Connections = ConnectionStats{
+			New:       cc.NewConns,
+			Reused:    cc.ReusedConns,
+			ReuseRate: cc.ReuseRate(),
+		}
+
+		for _, rc := range kc.RequestCounts(provider.Host) {
+			rs := RequestStats{
+				Verb:     rc.Verb,
+				Resource: rc.Resource,
+				Requests: map[string]int64{},
+			}
+
+			for w, count := range rc.Windows {
+				rs.Requests[windowName(w)] = count
+				ars.Total[windowName(w)] += count
+			}
+
+			ars.Requests = append(ars.Requests, rs)
+		}
+
+		stats = append(stats, ars)
+	}
+
+	c.Set(KeyStats, stats)
+}
+
+// windowName returns a window as minutes or hours, such as "15m" or "1h".
+func windowName(d time.Duration) string {
+	if d%time.Hour == 0 {
+		return fmt.Sprintf("%dh", d/time.Hour)
+	}
+
+	return fmt.Sprintf("%dm", d/time.Minute)
+}
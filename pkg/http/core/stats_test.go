@@ -0,0 +1,375 @@
+package core_test
+
+import (
+	"encoding/json"
+	"errors"
+	"io/ioutil"
+	"net/http"
+	"time"
+
+	clouddriver "github.com/billiford/go-clouddriver/pkg"
+	"github.com/billiford/go-clouddriver/pkg/fiat"
+	"github.com/billiford/go-clouddriver/pkg/http/core"
+	"github.com/billiford/go-clouddriver/pkg/kubernetes"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Stats", func() {
+	Describe("#ListRequestStats", func() {
+		var stats []core.AccountRequestStats
+
+		BeforeEach(func() {
+			setup()
+			uri = svr.URL + "/stats/requests"
+			createRequest(http.MethodGet)
+			fakeSQLClient.ListKubernetesProvidersReturns([]kubernetes.Provider{
+				{
+					Name: "test-account",
+					Host: "https://test-host",
+				},
+				{
+					Name: "other-account",
+					Host: "https://other-host",
+				},
+			}, nil)
+			fakeKubeController.RequestCountsStub = func(host string) []kubernetes.RequestCount {
+				if host != "https://test-host" {
+					return nil
+				}
+
+				return []kubernetes.RequestCount{
+					{
+						Verb:     "list",
+						Resource: "pods",
+						Windows: map[time.Duration]int64{
+							time.Minute:      2,
+							5 * time.Minute:  10,
+							15 * time.Minute: 30,
+							time.Hour:        120,
+						},
+					},
+					{
+						Verb:     "patch",
+						Resource: "deployments.apps",
+						Windows: map[time.Duration]int64{
+							time.Minute:      0,
+							5 * time.Minute:  0,
+							15 * time.Minute: 1,
+							time.Hour:        4,
+						},
+					},
+				}
+			}
+			stats = nil
+		})
+
+		AfterEach(func() {
+			teardown()
+		})
+
+		JustBeforeEach(func() {
+			doRequest()
+
+			if res.StatusCode == http.StatusOK {
+				b, _ := ioutil.ReadAll(res.Body)
+				Expect(json.Unmarshal(b, &stats)).To(Succeed())
+			}
+		})
+
+		When("listing providers fails", func() {
+			BeforeEach(func() {
+				fakeSQLClient.ListKubernetesProvidersReturns(nil, errors.New("error listing providers"))
+			})
+
+			It("returns status internal server error", func() {
+				Expect(res.StatusCode).To(Equal(http.StatusInternalServerError))
+				ce := getClouddriverError()
+				Expect(ce.Message).To(Equal("error listing providers"))
+			})
+		})
+
+		When("the accounts are filtered", func() {
+			BeforeEach(func() {
+				uri = svr.URL + "/stats/requests?accounts=other-account"
+				createRequest(http.MethodGet)
+			})
+
+			It("only returns the requests to those accounts", func() {
+				Expect(res.StatusCode).To(Equal(http.StatusOK))
+				Expect(stats).To(HaveLen(1))
+				Expect(stats[0].Account).To(Equal("other-account"))
+				Expect(stats[0].Requests).To(BeEmpty())
+				Expect(stats[0].Total).To(Equal(map[string]int64{"1m": 0, "5m": 0, "15m": 0, "1h": 0}))
+			})
+		})
+
+		When("the user may not read an account", func() {
+			BeforeEach(func() {
+				req.Header.Set("X-Spinnaker-User", "test-user")
+				fakeFiatClient.AuthorizeReturns(fiat.Response{
+					Accounts: []fiat.Account{
+						{Name: "test-account", Authorizations: []string{"READ"}},
+						{Name: "other-account", Authorizations: []string{}},
+					},
+				}, nil)
+			})
+
+			It("only returns the requests to the accounts the user may read", func() {
+				Expect(res.StatusCode).To(Equal(http.StatusOK))
+				Expect(stats).To(HaveLen(1))
+				Expect(stats[0].Account).To(Equal("test-account"))
+			})
+		})
+
+		When("Fiat does not list an account", func() {
+			BeforeEach(func() {
+				req.Header.Set("X-Spinnaker-User", "test-user")
+				fakeFiatClient.AuthorizeReturns(fiat.Response{
+					Accounts: []fiat.Account{
+						{Name: "test-account", Authorizations: []string{"READ"}},
+					},
+				}, nil)
+			})
+
+			It("does not return the requests to it", func() {
+				Expect(res.StatusCode).To(Equal(http.StatusOK))
+				Expect(stats).To(HaveLen(1))
+				Expect(stats[0].Account).To(Equal("test-account"))
+			})
+		})
+
+		When("authorizing the user fails", func() {
+			BeforeEach(func() {
+				req.Header.Set("X-Spinnaker-User", "test-user")
+				fakeFiatClient.AuthorizeReturns(fiat.Response{}, errors.New("error authorizing user"))
+			})
+
+			It("returns status unauthorized", func() {
+				Expect(res.StatusCode).To(Equal(http.StatusUnauthorized))
+				ce := getClouddriverError()
+				Expect(ce.Message).To(Equal("error authorizing user"))
+			})
+		})
+
+		It("returns the requests to each account by verb, resource and window", func() {
+			Expect(res.StatusCode).To(Equal(http.StatusOK))
+			Expect(stats).To(HaveLen(2))
+			Expect(stats[0].Account).To(Equal("test-account"))
+			Expect(stats[0].Host).To(Equal("https://test-host"))
+			Expect(stats[0].Requests).To(HaveLen(2))
+			Expect(stats[0].Requests[0].Verb).To(Equal("list"))
+			Expect(stats[0].Requests[0].Resource).To(Equal("pods"))
+			Expect(stats[0].Requests[0].Requests).To(Equal(map[string]int64{"1m": 2, "5m": 10, "15m": 30, "1h": 120}))
+			Expect(stats[0].Total).To(Equal(map[string]int64{"1m": 2, "5m": 10, "15m": 31, "1h": 124}))
+		})
+
+		When("requests reused connections", func() {
+			BeforeEach(func() {
+				fakeKubeController.CallCountReturns(kubernetes.CallCount{
+					Calls:       4,
+					NewConns:    1,
+					ReusedConns: 3,
+				})
+			})
+
+			It("returns how often they did", func() {
+				Expect(res.StatusCode).To(Equal(http.StatusOK))
+				Expect(stats[0].Connections).To(Equal(core.ConnectionStats{
+					New:       1,
+					Reused:    3,
+					ReuseRate: 0.75,
+				}))
+			})
+		})
+	})
+
+	Describe("#ListDeployStats", func() {
+		var (
+			stats core.DeployStats
+			now   time.Time
+		)
+
+		BeforeEach(func() {
+			setup()
+			uri = svr.URL + "/stats/deploys"
+			createRequest(http.MethodGet)
+			now = time.Now()
+			stable := now.Add(-9 * time.Minute)
+			stabler := now.Add(-17 * time.Minute)
+			fakeSQLClient.ListDeploysCreatedSinceReturns([]clouddriver.Deploy{
+				{
+					TaskID:      "task1",
+					Account:     "test-account",
+					Namespace:   "default",
+					Application: "app1",
+					Phase:       "STABLE",
+					RolledOutAt: &stable,
+					CreatedAt:   now.Add(-10 * time.Minute),
+				},
+				{
+					TaskID:      "task1",
+					Account:     "test-account",
+					Namespace:   "canary",
+					Application: "app1",
+					Phase:       "STABLE",
+					RolledOutAt: &stable,
+					CreatedAt:   now.Add(-10 * time.Minute),
+				},
+				{
+					TaskID:      "task2",
+					Account:     "test-account",
+					Namespace:   "default",
+					Application: "app1",
+					Phase:       "STABLE",
+					RolledOutAt: &stabler,
+					CreatedAt:   now.Add(-20 * time.Minute),
+				},
+				{
+					TaskID:      "task3",
+					Account:     "test-account",
+					Namespace:   "default",
+					Application: "app2",
+					Error:       "error deploying manifest",
+					CreatedAt:   now.Add(-30 * time.Minute),
+				},
+				{
+					TaskID:      "task4",
+					Account:     "other-account",
+					Namespace:   "default",
+					Application: "app2",
+					CreatedAt:   now.Add(-40 * time.Minute),
+				},
+			}, nil)
+			stats = core.DeployStats{}
+		})
+
+		AfterEach(func() {
+			teardown()
+		})
+
+		JustBeforeEach(func() {
+			doRequest()
+
+			if res.StatusCode == http.StatusOK {
+				b, _ := ioutil.ReadAll(res.Body)
+				Expect(json.Unmarshal(b, &stats)).To(Succeed())
+			}
+		})
+
+		When("the window is invalid", func() {
+			BeforeEach(func() {
+				uri = svr.URL + "/stats/deploys?window=forever"
+				createRequest(http.MethodGet)
+			})
+
+			It("returns status bad request", func() {
+				Expect(res.StatusCode).To(Equal(http.StatusBadRequest))
+				ce := getClouddriverError()
+				Expect(ce.Message).To(Equal(`invalid window "forever"`))
+			})
+		})
+
+		When("listing deploys fails", func() {
+			BeforeEach(func() {
+				fakeSQLClient.ListDeploysCreatedSinceReturns(nil, errors.New("error listing deploys"))
+			})
+
+			It("returns status internal server error", func() {
+				Expect(res.StatusCode).To(Equal(http.StatusInternalServerError))
+			})
+		})
+
+		When("passed a window and applications", func() {
+			BeforeEach(func() {
+				uri = svr.URL + "/stats/deploys?window=168h&applications=app2"
+				createRequest(http.MethodGet)
+			})
+
+			It("only returns the deploys of those applications in the window", func() {
+				Expect(res.StatusCode).To(Equal(http.StatusOK))
+				since := fakeSQLClient.ListDeploysCreatedSinceArgsForCall(0)
+				Expect(since).To(BeTemporally("~", time.Now().Add(-168*time.Hour), time.Minute))
+				Expect(stats.Applications).To(HaveLen(1))
+				Expect(stats.Applications[0].Application).To(Equal("app2"))
+				Expect(stats.Namespaces).To(HaveLen(2))
+			})
+		})
+
+		When("the user may not read an application or an account", func() {
+			BeforeEach(func() {
+				req.Header.Set("X-Spinnaker-User", "test-user")
+				fakeFiatClient.AuthorizeReturns(fiat.Response{
+					Accounts: []fiat.Account{
+						{Name: "test-account", Authorizations: []string{"READ"}},
+						{Name: "other-account", Authorizations: []string{}},
+					},
+					Applications: []fiat.Application{
+						{Name: "app1", Authorizations: []string{"READ"}},
+						{Name: "app2", Authorizations: []string{"WRITE"}},
+					},
+				}, nil)
+			})
+
+			It("only returns the deploys the user may read", func() {
+				Expect(res.StatusCode).To(Equal(http.StatusOK))
+				Expect(stats.Applications).To(HaveLen(1))
+				Expect(stats.Applications[0].Application).To(Equal("app1"))
+				Expect(stats.Namespaces).To(HaveLen(2))
+				for _, n := range stats.Namespaces {
+					Expect(n.Account).To(Equal("test-account"))
+				}
+			})
+		})
+
+		When("Fiat does not list an account", func() {
+			BeforeEach(func() {
+				req.Header.Set("X-Spinnaker-User", "test-user")
+				fakeFiatClient.AuthorizeReturns(fiat.Response{
+					Accounts: []fiat.Account{
+						{Name: "test-account", Authorizations: []string{"READ"}},
+					},
+					Applications: []fiat.Application{
+						{Name: "app1", Authorizations: []string{"READ"}},
+						{Name: "app2", Authorizations: []string{"READ"}},
+					},
+				}, nil)
+			})
+
+			It("does not return the deploys to its namespaces", func() {
+				Expect(res.StatusCode).To(Equal(http.StatusOK))
+				Expect(stats.Applications).To(HaveLen(2))
+				Expect(stats.Namespaces).To(HaveLen(2))
+				for _, n := range stats.Namespaces {
+					Expect(n.Account).To(Equal("test-account"))
+				}
+			})
+		})
+
+		It("returns the deploys by application and namespace", func() {
+			Expect(res.StatusCode).To(Equal(http.StatusOK))
+			since := fakeSQLClient.ListDeploysCreatedSinceArgsForCall(0)
+			Expect(since).To(BeTemporally("~", time.Now().Add(-30*24*time.Hour), time.Minute))
+
+			Expect(stats.Applications).To(HaveLen(2))
+			Expect(stats.Applications[0].Application).To(Equal("app1"))
+			Expect(stats.Applications[0].Deploys).To(Equal(2))
+			Expect(stats.Applications[0].Failed).To(Equal(0))
+			Expect(*stats.Applications[0].MeanTimeToStableSeconds).To(BeNumerically("~", 120))
+			Expect(stats.Applications[1].Application).To(Equal("app2"))
+			Expect(stats.Applications[1].Deploys).To(Equal(2))
+			Expect(stats.Applications[1].Failed).To(Equal(1))
+			Expect(stats.Applications[1].FailureRate).To(Equal(0.5))
+			Expect(stats.Applications[1].MeanTimeToStableSeconds).To(BeNil())
+
+			Expect(stats.Namespaces).To(HaveLen(3))
+			Expect(stats.Namespaces[0].Account).To(Equal("other-account"))
+			Expect(stats.Namespaces[1].Account).To(Equal("test-account"))
+			Expect(stats.Namespaces[1].Namespace).To(Equal("canary"))
+			Expect(stats.Namespaces[1].Deploys).To(Equal(1))
+			Expect(stats.Namespaces[2].Namespace).To(Equal("default"))
+			Expect(stats.Namespaces[2].Deploys).To(Equal(3))
+			Expect(stats.Namespaces[2].Failed).To(Equal(1))
+		})
+	})
+})
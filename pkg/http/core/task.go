@@ -2,17 +2,20 @@ package core
 
 import (
 	"encoding/base64"
+	"encoding/json"
 	"fmt"
 	"net/http"
 	"strings"
 
 	clouddriver "github.com/billiford/go-clouddriver/pkg"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 	"k8s.io/client-go/rest"
 
 	"github.com/billiford/go-clouddriver/pkg/arcade"
 	"github.com/billiford/go-clouddriver/pkg/kubernetes"
 	"github.com/billiford/go-clouddriver/pkg/sql"
 	"github.com/gin-gonic/gin"
+	"github.com/jinzhu/gorm"
 )
 
 // Get a task - currently only associated with kubernetes 'tasks'.
@@ -29,15 +32,45 @@ func GetTask(c *gin.Context) {
 		return
 	}
 
+	report, err := migrationReport(sc, id)
+	if err != nil {
+		clouddriver.WriteError(c, http.StatusInternalServerError, err)
+		return
+	}
+
+	fo, err := failedOperation(sc, id)
+	if err != nil {
+		clouddriver.WriteError(c, http.StatusInternalServerError, err)
+		return
+	}
+
 	// If there were no kubernetes resources associated with this task ID,
-	// return the default task.
+	// return the default task, with the report of a migration that copied
+	// nothing.
 	if len(resources) == 0 {
-		c.JSON(http.StatusOK, clouddriver.NewDefaultTask(id))
+		task := clouddriver.NewDefaultTask(id)
+
+		if report != nil {
+			task.ResultObjects = []clouddriver.TaskResultObject{{MigrationReport: report}}
+		}
+
+		if fo != nil {
+			task.Fail(fo.Error)
+		}
+
+		c.JSON(http.StatusOK, task)
+
 		return
 	}
 
 	accountName := resources[0].AccountName
 
+	status, err := authorizeAccountRead(c, accountName)
+	if err != nil {
+		clouddriver.WriteError(c, status, err)
+		return
+	}
+
 	provider, err := sc.GetKubernetesProvider(accountName)
 	if err != nil {
 		clouddriver.WriteError(c, http.StatusInternalServerError, err)
@@ -64,7 +97,7 @@ func GetTask(c *gin.Context) {
 		},
 	}
 
-	client, err := kc.NewClient(config)
+	client, err := kubernetes.NewProviderClient(kc, provider, config)
 	if err != nil {
 		clouddriver.WriteError(c, http.StatusInternalServerError, err)
 		return
@@ -77,6 +110,23 @@ func GetTask(c *gin.Context) {
 			continue
 		}
 
+		// Resources that were only dry-run do not exist, return what would
+		// have been deployed. Secrets recorded before their data was redacted
+		// are redacted here.
+		if r.DryRun && r.Manifest != "" {
+			manifest := map[string]interface{}{}
+
+			err = json.Unmarshal([]byte(r.Manifest), &manifest)
+			if err != nil {
+				clouddriver.WriteError(c, http.StatusInternalServerError, err)
+				return
+			}
+
+			manifests = append(manifests, kubernetes.RedactSecret(&unstructured.Unstructured{Object: manifest}).Object)
+
+			continue
+		}
+
 		result, err := client.Get(r.Resource, r.Name, r.Namespace)
 		if err != nil {
 			clouddriver.WriteError(c, http.StatusInternalServerError, err)
@@ -94,14 +144,103 @@ func GetTask(c *gin.Context) {
 		Manifests:                         manifests,
 		ManifestNamesByNamespace:          mnr,
 		ManifestNamesByNamespaceToRefresh: mnr,
+		Warnings:                          lintWarnings(resources),
+		MigrationReport:                   report,
 	}
 
 	task := clouddriver.NewDefaultTask(id)
 	task.ResultObjects = []clouddriver.TaskResultObject{ro}
 
+	if fo != nil {
+		task.Fail(fo.Error)
+	}
+
 	c.JSON(http.StatusOK, task)
 }
 
+// ListTaskEvents returns the events of a task in the order they happened,
+// such as each manifest it applied and the progress of its rollout. Tasks
+// without events, including unknown tasks, have an empty list. Like the
+// task, its events need READ to the account of the task.
+func ListTaskEvents(c *gin.Context) {
+	sc := sql.Instance(c)
+	id := c.Param("id")
+
+	resources, err := sc.ListKubernetesResourcesByTaskID(id)
+	if err != nil {
+		clouddriver.WriteError(c, http.StatusInternalServerError, err)
+		return
+	}
+
+	if len(resources) > 0 {
+		status, err := authorizeAccountRead(c, resources[0].AccountName)
+		if err != nil {
+			clouddriver.WriteError(c, status, err)
+			return
+		}
+	}
+
+	events, err := sc.ListTaskEvents(id)
+	if err != nil {
+		clouddriver.WriteError(c, http.StatusInternalServerError, err)
+		return
+	}
+
+	if events == nil {
+		events = []clouddriver.TaskEvent{}
+	}
+
+	c.JSON(http.StatusOK, events)
+}
+
+// migrationReport returns the report of a task if it is a migration, or nil.
+func migrationReport(sc sql.Client, id string) (*clouddriver.MigrationReport, error) {
+	report, err := sc.GetMigrationReport(id)
+	if err == gorm.ErrRecordNotFound {
+		return nil, nil
+	}
+
+	if err != nil {
+		return nil, fmt.Errorf("error getting migration report: %w", err)
+	}
+
+	return &report, nil
+}
+
+// failedOperation returns the failed operation of a task if it failed, such
+// as when an operation panicked, or nil.
+func failedOperation(sc sql.Client, id string) (*clouddriver.FailedOperation, error) {
+	fo, err := sc.GetFailedOperation(id)
+	if err == gorm.ErrRecordNotFound || (err == nil && fo.Error == "") {
+		return nil, nil
+	}
+
+	if err != nil {
+		return nil, fmt.Errorf("error getting failed operation: %w", err)
+	}
+
+	return &fo, nil
+}
+
+// lintWarnings returns the lint warnings of all resources of a task, noting
+// first if the task was only dry-run.
+func lintWarnings(resources []kubernetes.Resource) []string {
+	ws := []string{}
+
+	if len(resources) > 0 && resources[0].DryRun {
+		ws = append(ws, fmt.Sprintf("info: account %s is in dryRun write mode, no changes were made",
+			resources[0].AccountName))
+	}
+
+	for _, r := range resources {
+		if r.Warnings != "" {
+			ws = append(ws, strings.Split(r.Warnings, "\n")...)
+		}
+	}
+
+	return ws
+}
+
 func buildCreatedArtifacts(resources []kubernetes.Resource) []clouddriver.TaskCreatedArtifact {
 	cas := []clouddriver.TaskCreatedArtifact{}
 
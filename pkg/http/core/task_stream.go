@@ -0,0 +1,266 @@
+package core
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	clouddriver "github.com/billiford/go-clouddriver/pkg"
+	"github.com/billiford/go-clouddriver/pkg/arcade"
+	"github.com/billiford/go-clouddriver/pkg/hook"
+	"github.com/billiford/go-clouddriver/pkg/kubernetes"
+	"github.com/billiford/go-clouddriver/pkg/kubernetes/manifest"
+	"github.com/billiford/go-clouddriver/pkg/sql"
+	"github.com/gin-gonic/gin"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/client-go/rest"
+)
+
+const (
+	taskPhaseRollingOut = "ROLLING_OUT"
+	taskPhaseStable     = "STABLE"
+	taskPhaseFailed     = "FAILED"
+	taskPhaseTimedOut   = "TIMED_OUT"
+
+	taskStreamInterval       = time.Second
+	defaultTaskStreamTimeout = 10 * time.Minute
+	maxTaskStreamTimeout     = time.Hour
+)
+
+type taskStreamPhase struct {
+	Phase string `json:"phase"`
+}
+
+type taskStreamResource struct {
+	Kind      string `json:"kind"`
+	Name      string `json:"name"`
+	Namespace string `json:"namespace"`
+}
+
+type taskStreamStatus struct {
+	taskStreamResource
+	Status manifest.Status `json:"status"`
+}
+
+type taskStreamReadiness struct {
+	taskStreamResource
+	Ready   int64 `json:"ready"`
+	Desired int64 `json:"desired"`
+}
+
+// StreamTask streams the rollout of the resources deployed by a task as
+// server-sent events, so UIs do not have to poll GetTask. The resources are
+// polled every second until they are all stable, one fails or the timeout
+// passes (?timeout=, default 10m), and each event is only sent when it
+// changes:
+//
+//	event: phase      {"phase":"ROLLING_OUT"}, ending with STABLE, FAILED or TIMED_OUT
+//	event: status     the manifest status of a resource
+//	event: readiness  the ready and desired pods of a workload
+//	event: error      {"message":"..."} if getting a resource fails
+func StreamTask(c *gin.Context) {
+	sc := sql.Instance(c)
+	kc := kubernetes.ControllerInstance(c)
+	ac := arcade.Instance(c)
+	id := c.Param("id")
+	timeout := defaultTaskStreamTimeout
+
+	if t := c.Query("timeout"); t != "" {
+		d, err := time.ParseDuration(t)
+		if err != nil || d <= 0 {
+			clouddriver.WriteError(c, http.StatusBadRequest, fmt.Errorf("invalid timeout %q", t))
+			return
+		}
+
+		if d < maxTaskStreamTimeout {
+			timeout = d
+		} else {
+			timeout = maxTaskStreamTimeout
+		}
+	}
+
+	resources, err := sc.ListKubernetesResourcesByTaskID(id)
+	if err != nil {
+		clouddriver.WriteError(c, http.StatusBadRequest, err)
+		return
+	}
+
+	// Streams are authorized like the task itself.
+	if len(resources) > 0 {
+		status, err := authorizeAccountRead(c, resources[0].AccountName)
+		if err != nil {
+			clouddriver.WriteError(c, status, err)
+			return
+		}
+	}
+
+	// Cleaned up and dry-run resources have nothing to roll out.
+	watched := []kubernetes.Resource{}
+
+	for _, r := range resources {
+		if !strings.EqualFold(r.TaskType, "cleanup") && !r.DryRun {
+			watched = append(watched, r)
+		}
+	}
+
+	var client kubernetes.Client
+
+	if len(watched) > 0 {
+		provider, err := sc.GetKubernetesProvider(watched[0].AccountName)
+		if err != nil {
+			clouddriver.WriteError(c, http.StatusInternalServerError, err)
+			return
+		}
+
+		cd, err := base64.StdEncoding.DecodeString(provider.CAData)
+		if err != nil {
+			clouddriver.WriteError(c, http.StatusInternalServerError, err)
+			return
+		}
+
+		token, err := ac.Token()
+		if err != nil {
+			clouddriver.WriteError(c, http.StatusInternalServerError, err)
+			return
+		}
+
+		config := &rest.Config{
+			Host:        provider.Host,
+			BearerToken: token,
+			TLSClientConfig: rest.TLSClientConfig{
+				CAData: cd,
+			},
+		}
+
+		client, err = kubernetes.NewProviderClient(kc, provider, config)
+		if err != nil {
+			clouddriver.WriteError(c, http.StatusInternalServerError, err)
+			return
+		}
+	}
+
+	c.Header("Cache-Control", "no-cache")
+	// Disable buffering by nginx, which would hold back events.
+	c.Header("X-Accel-Buffering", "no")
+
+	ts := &taskStream{c: c, sent: map[string]string{}}
+	done := c.Request.Context().Done()
+
+	timer := time.NewTimer(timeout)
+	defer timer.Stop()
+
+	ticker := time.NewTicker(taskStreamInterval)
+	defer ticker.Stop()
+
+	for {
+		phase, err := ts.poll(client, watched)
+		if err != nil {
+			ts.send("error", "error", gin.H{"message": err.Error()})
+			return
+		}
+
+		ts.send("phase", "phase", taskStreamPhase{Phase: phase})
+
+		if phase != taskPhaseRollingOut {
+			return
+		}
+
+		select {
+		case <-done:
+			return
+		case <-timer.C:
+			ts.send("phase", "phase", taskStreamPhase{Phase: taskPhaseTimedOut})
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+type taskStream struct {
+	c *gin.Context
+	// The data of the last event sent by key.
+	sent map[string]string
+}
+
+// poll sends the status and readiness of each resource, returning the
+// phase of the rollout.
+func (ts *taskStream) poll(client kubernetes.Client, resources []kubernetes.Resource) (string, error) {
+	phase := taskPhaseStable
+
+	for _, r := range resources {
+		u, err := client.Get(r.Resource, r.Name, r.Namespace)
+		if err != nil {
+			return "", err
+		}
+
+		tr := taskStreamResource{
+			Kind:      r.Kind,
+			Name:      r.Name,
+			Namespace: r.Namespace,
+		}
+		key := fmt.Sprintf("%s %s %s", r.Namespace, r.Kind, r.Name)
+
+		s := resourceStatus(ts.c.Request.Context(), hook.StabilityCheckerInstance(ts.c), r.AccountName, r.Kind, u.Object)
+		ts.send("status "+key, "status", taskStreamStatus{taskStreamResource: tr, Status: s})
+
+		if ready, desired, ok := readiness(r.Kind, u.Object); ok {
+			ts.send("readiness "+key, "readiness", taskStreamReadiness{
+				taskStreamResource: tr,
+				Ready:              ready,
+				Desired:            desired,
+			})
+		}
+
+		switch {
+		case s.Failed.State:
+			phase = taskPhaseFailed
+		case !s.Stable.State && phase == taskPhaseStable:
+			phase = taskPhaseRollingOut
+		}
+	}
+
+	return phase, nil
+}
+
+// send writes an event and flushes it, unless the last event sent under
+// key had the same data.
+func (ts *taskStream) send(key, event string, data interface{}) {
+	b, err := json.Marshal(data)
+	if err != nil {
+		return
+	}
+
+	if ts.sent[key] == string(b) {
+		return
+	}
+
+	ts.sent[key] = string(b)
+	ts.c.SSEvent(event, string(b))
+	ts.c.Writer.Flush()
+}
+
+// readiness returns the ready and desired pods of a workload, or false if
+// the kind does not run pods.
+func readiness(kind string, m map[string]interface{}) (int64, int64, bool) {
+	switch strings.ToLower(kind) {
+	case "deployment", "replicaset", "statefulset":
+		desired, found, _ := unstructured.NestedInt64(m, "spec", "replicas")
+		if !found {
+			desired = 1
+		}
+
+		ready, _, _ := unstructured.NestedInt64(m, "status", "readyReplicas")
+
+		return ready, desired, true
+	case "daemonset":
+		desired, _, _ := unstructured.NestedInt64(m, "status", "desiredNumberScheduled")
+		ready, _, _ := unstructured.NestedInt64(m, "status", "numberReady")
+
+		return ready, desired, true
+	default:
+		return 0, 0, false
+	}
+}
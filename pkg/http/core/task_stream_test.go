@@ -0,0 +1,206 @@
+package core_test
+
+import (
+	"errors"
+	"io/ioutil"
+	"mime"
+	"net/http"
+
+	"github.com/billiford/go-clouddriver/pkg/fiat"
+	"github.com/billiford/go-clouddriver/pkg/kubernetes"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+var _ = Describe("TaskStream", func() {
+	Describe("#StreamTask", func() {
+		var (
+			stream     string
+			deployment = func(ready int64) *unstructured.Unstructured {
+				return &unstructured.Unstructured{
+					Object: map[string]interface{}{
+						"kind": "Deployment",
+						"metadata": map[string]interface{}{
+							"name":      "test-deployment",
+							"namespace": "test-namespace",
+						},
+						"spec": map[string]interface{}{
+							"replicas": int64(2),
+						},
+						"status": map[string]interface{}{
+							"replicas":          int64(2),
+							"updatedReplicas":   int64(2),
+							"availableReplicas": ready,
+							"readyReplicas":     ready,
+						},
+					},
+				}
+			}
+		)
+
+		BeforeEach(func() {
+			setup()
+			uri = svr.URL + "/task/task-id/stream"
+			createRequest(http.MethodGet)
+			fakeSQLClient.ListKubernetesResourcesByTaskIDReturns([]kubernetes.Resource{
+				{
+					AccountName: "test-account",
+					Kind:        "deployment",
+					Name:        "test-deployment",
+					Namespace:   "test-namespace",
+					Resource:    "deployments",
+				},
+			}, nil)
+			fakeKubeClient.GetReturns(deployment(2), nil)
+		})
+
+		AfterEach(func() {
+			teardown()
+		})
+
+		JustBeforeEach(func() {
+			doRequest()
+			b, _ := ioutil.ReadAll(res.Body)
+			stream = string(b)
+		})
+
+		When("the timeout is invalid", func() {
+			BeforeEach(func() {
+				uri = svr.URL + "/task/task-id/stream?timeout=soon"
+				createRequest(http.MethodGet)
+			})
+
+			It("returns status bad request", func() {
+				Expect(res.StatusCode).To(Equal(http.StatusBadRequest))
+				Expect(stream).To(ContainSubstring(`invalid timeout \"soon\"`))
+			})
+		})
+
+		When("listing the resources returns an error", func() {
+			BeforeEach(func() {
+				fakeSQLClient.ListKubernetesResourcesByTaskIDReturns(nil, errors.New("error listing resources"))
+			})
+
+			It("returns status bad request", func() {
+				Expect(res.StatusCode).To(Equal(http.StatusBadRequest))
+				Expect(stream).To(ContainSubstring("error listing resources"))
+			})
+		})
+
+		When("the user may not read the account of the task", func() {
+			BeforeEach(func() {
+				req.Header.Set("X-Spinnaker-User", "test-user")
+				fakeFiatClient.AuthorizeReturns(fiat.Response{
+					Accounts: []fiat.Account{
+						{Name: "test-account", Authorizations: []string{"WRITE"}},
+					},
+				}, nil)
+			})
+
+			It("returns status forbidden without streaming", func() {
+				Expect(res.StatusCode).To(Equal(http.StatusForbidden))
+				Expect(stream).To(ContainSubstring("Access denied to account test-account - required authorization: READ"))
+				Expect(fakeKubeClient.GetCallCount()).To(BeZero())
+			})
+		})
+
+		When("getting the provider returns an error", func() {
+			BeforeEach(func() {
+				fakeSQLClient.GetKubernetesProviderReturns(kubernetes.Provider{}, errors.New("error getting provider"))
+			})
+
+			It("returns status internal server error", func() {
+				Expect(res.StatusCode).To(Equal(http.StatusInternalServerError))
+				Expect(stream).To(ContainSubstring("error getting provider"))
+			})
+		})
+
+		When("the task has no resources to roll out", func() {
+			BeforeEach(func() {
+				fakeSQLClient.ListKubernetesResourcesByTaskIDReturns([]kubernetes.Resource{
+					{
+						AccountName: "test-account",
+						TaskType:    "cleanup",
+					},
+				}, nil)
+			})
+
+			It("sends the stable phase", func() {
+				Expect(res.StatusCode).To(Equal(http.StatusOK))
+				Expect(fakeKubeClient.GetCallCount()).To(BeZero())
+				Expect(stream).To(ContainSubstring(`{"phase":"STABLE"}`))
+			})
+		})
+
+		When("getting a resource returns an error", func() {
+			BeforeEach(func() {
+				fakeKubeClient.GetReturns(nil, errors.New("error getting resource"))
+			})
+
+			It("sends an error event", func() {
+				Expect(stream).To(ContainSubstring("event:error"))
+				Expect(stream).To(ContainSubstring(`{"message":"error getting resource"}`))
+				Expect(stream).ToNot(ContainSubstring("event:phase"))
+			})
+		})
+
+		When("a resource fails", func() {
+			BeforeEach(func() {
+				d := deployment(1)
+				d.Object["status"].(map[string]interface{})["conditions"] = []interface{}{
+					map[string]interface{}{
+						"type":   "Progressing",
+						"status": "False",
+						"reason": "ProgressDeadlineExceeded",
+					},
+				}
+				fakeKubeClient.GetReturns(d, nil)
+			})
+
+			It("sends the failed phase", func() {
+				Expect(stream).To(ContainSubstring(`{"phase":"FAILED"}`))
+				Expect(fakeKubeClient.GetCallCount()).To(Equal(1))
+			})
+		})
+
+		When("the rollout does not finish before the timeout", func() {
+			BeforeEach(func() {
+				uri = svr.URL + "/task/task-id/stream?timeout=10ms"
+				createRequest(http.MethodGet)
+				fakeKubeClient.GetReturns(deployment(1), nil)
+			})
+
+			It("sends the timed out phase", func() {
+				Expect(stream).To(ContainSubstring(`{"phase":"ROLLING_OUT"}`))
+				Expect(stream).To(ContainSubstring(`{"phase":"TIMED_OUT"}`))
+			})
+		})
+
+		When("the rollout finishes", func() {
+			BeforeEach(func() {
+				fakeKubeClient.GetReturnsOnCall(0, deployment(1), nil)
+				fakeKubeClient.GetReturnsOnCall(1, deployment(2), nil)
+			})
+
+			It("streams its progress", func() {
+				Expect(res.StatusCode).To(Equal(http.StatusOK))
+				mt, _, _ := mime.ParseMediaType(res.Header.Get("content-type"))
+				Expect(mt).To(Equal("text/event-stream"))
+				Expect(fakeKubeClient.GetCallCount()).To(Equal(2))
+
+				kind, name, namespace := fakeKubeClient.GetArgsForCall(0)
+				Expect(kind).To(Equal("deployments"))
+				Expect(name).To(Equal("test-deployment"))
+				Expect(namespace).To(Equal("test-namespace"))
+
+				Expect(stream).To(ContainSubstring("event:readiness"))
+				Expect(stream).To(ContainSubstring(`"ready":1,"desired":2`))
+				Expect(stream).To(ContainSubstring(`"ready":2,"desired":2`))
+				Expect(stream).To(ContainSubstring("Waiting for all replicas to be available"))
+				Expect(stream).To(ContainSubstring(`{"phase":"ROLLING_OUT"}`))
+				Expect(stream).To(ContainSubstring(`{"phase":"STABLE"}`))
+			})
+		})
+	})
+})
@@ -1,10 +1,14 @@
 package core_test
 
 import (
+	"encoding/json"
 	"errors"
 	"net/http"
 
+	clouddriver "github.com/billiford/go-clouddriver/pkg"
+	"github.com/billiford/go-clouddriver/pkg/fiat"
 	"github.com/billiford/go-clouddriver/pkg/kubernetes"
+	"github.com/billiford/go-clouddriver/pkg/version"
 	. "github.com/onsi/ginkgo"
 	. "github.com/onsi/gomega"
 )
@@ -47,6 +51,102 @@ var _ = Describe("Task", func() {
 			It("returns status internal server error", func() {
 				Expect(res.StatusCode).To(Equal(http.StatusOK))
 			})
+
+			It("includes the clouddriver version", func() {
+				task := clouddriver.Task{}
+				Expect(json.NewDecoder(res.Body).Decode(&task)).To(Succeed())
+				Expect(task.ClouddriverVersion).To(Equal(version.Version))
+			})
+		})
+
+		When("getting the migration report returns an error", func() {
+			BeforeEach(func() {
+				fakeSQLClient.GetMigrationReportReturns(clouddriver.MigrationReport{}, errors.New("db down"))
+			})
+
+			It("returns status internal server error", func() {
+				Expect(res.StatusCode).To(Equal(http.StatusInternalServerError))
+				ce := getClouddriverError()
+				Expect(ce.Message).To(Equal("error getting migration report: db down"))
+			})
+		})
+
+		When("getting the failed operation returns an error", func() {
+			BeforeEach(func() {
+				fakeSQLClient.GetFailedOperationReturns(clouddriver.FailedOperation{}, errors.New("db down"))
+			})
+
+			It("returns status internal server error", func() {
+				Expect(res.StatusCode).To(Equal(http.StatusInternalServerError))
+				ce := getClouddriverError()
+				Expect(ce.Message).To(Equal("error getting failed operation: db down"))
+			})
+		})
+
+		When("the task failed", func() {
+			BeforeEach(func() {
+				fakeSQLClient.ListKubernetesResourcesByTaskIDReturns([]kubernetes.Resource{}, nil)
+				fakeSQLClient.GetFailedOperationReturns(clouddriver.FailedOperation{
+					TaskID: "task-id",
+					Error:  "panic: nil map",
+				}, nil)
+			})
+
+			It("returns the task as failed with its error", func() {
+				Expect(res.StatusCode).To(Equal(http.StatusOK))
+				task := clouddriver.Task{}
+				Expect(json.NewDecoder(res.Body).Decode(&task)).To(Succeed())
+				Expect(task.Status.Failed).To(BeTrue())
+				Expect(task.Status.Complete).To(BeTrue())
+				Expect(task.Status.Status).To(Equal("Orchestration failed: panic: nil map"))
+			})
+		})
+
+		When("the task is a migration that copied nothing", func() {
+			BeforeEach(func() {
+				fakeSQLClient.ListKubernetesResourcesByTaskIDReturns([]kubernetes.Resource{}, nil)
+				fakeSQLClient.GetMigrationReportReturns(clouddriver.MigrationReport{
+					TaskID:      "task-id",
+					Application: "test-app",
+					Skipped:     1,
+					Resources: clouddriver.MigratedResources{
+						{
+							Kind:    "Secret",
+							Name:    "test-secret",
+							Outcome: clouddriver.MigrationSkipped,
+							Reason:  "secrets are skipped",
+						},
+					},
+				}, nil)
+			})
+
+			It("returns the migration report", func() {
+				Expect(res.StatusCode).To(Equal(http.StatusOK))
+				task := clouddriver.Task{}
+				Expect(json.NewDecoder(res.Body).Decode(&task)).To(Succeed())
+				Expect(task.ResultObjects).To(HaveLen(1))
+				Expect(task.ResultObjects[0].MigrationReport).ToNot(BeNil())
+				Expect(task.ResultObjects[0].MigrationReport.Skipped).To(Equal(1))
+				Expect(task.ResultObjects[0].MigrationReport.Resources[0].Reason).To(Equal("secrets are skipped"))
+			})
+		})
+
+		When("the task is a migration", func() {
+			BeforeEach(func() {
+				fakeSQLClient.GetMigrationReportReturns(clouddriver.MigrationReport{
+					TaskID: "task-id",
+					Copied: 1,
+				}, nil)
+			})
+
+			It("returns the migration report with the copied resources", func() {
+				Expect(res.StatusCode).To(Equal(http.StatusOK))
+				task := clouddriver.Task{}
+				Expect(json.NewDecoder(res.Body).Decode(&task)).To(Succeed())
+				Expect(task.ResultObjects).To(HaveLen(1))
+				Expect(task.ResultObjects[0].Manifests).To(HaveLen(1))
+				Expect(task.ResultObjects[0].MigrationReport.Copied).To(Equal(1))
+			})
 		})
 
 		When("creating the kube client returns an error", func() {
@@ -137,10 +237,194 @@ var _ = Describe("Task", func() {
 			})
 		})
 
+		When("the resources have lint warnings", func() {
+			BeforeEach(func() {
+				fakeSQLClient.ListKubernetesResourcesByTaskIDReturns([]kubernetes.Resource{
+					{
+						AccountName: "test-account-name",
+						Warnings:    "warning: one\nwarning: two",
+					},
+					{
+						AccountName: "test-account-name",
+					},
+				}, nil)
+			})
+
+			It("returns the warnings in the result", func() {
+				Expect(res.StatusCode).To(Equal(http.StatusOK))
+				task := clouddriver.Task{}
+				Expect(json.NewDecoder(res.Body).Decode(&task)).To(Succeed())
+				Expect(task.ResultObjects).To(HaveLen(1))
+				Expect(task.ResultObjects[0].Warnings).To(Equal([]string{"warning: one", "warning: two"}))
+			})
+		})
+
+		When("the resources were only dry-run", func() {
+			BeforeEach(func() {
+				fakeSQLClient.ListKubernetesResourcesByTaskIDReturns([]kubernetes.Resource{
+					{
+						AccountName: "test-account-name",
+						DryRun:      true,
+						Manifest:    `{"kind":"Deployment","metadata":{"name":"test-deployment"}}`,
+					},
+				}, nil)
+			})
+
+			It("returns what would have been deployed", func() {
+				Expect(res.StatusCode).To(Equal(http.StatusOK))
+				Expect(fakeKubeClient.GetCallCount()).To(BeZero())
+				task := clouddriver.Task{}
+				Expect(json.NewDecoder(res.Body).Decode(&task)).To(Succeed())
+				Expect(task.ResultObjects).To(HaveLen(1))
+				Expect(task.ResultObjects[0].Manifests).To(HaveLen(1))
+				Expect(task.ResultObjects[0].Manifests[0]["kind"]).To(Equal("Deployment"))
+				Expect(task.ResultObjects[0].Warnings).To(Equal([]string{
+					"info: account test-account-name is in dryRun write mode, no changes were made",
+				}))
+			})
+		})
+
+		When("a secret was only dry-run", func() {
+			BeforeEach(func() {
+				fakeSQLClient.ListKubernetesResourcesByTaskIDReturns([]kubernetes.Resource{
+					{
+						AccountName: "test-account-name",
+						DryRun:      true,
+						Manifest:    `{"kind":"Secret","metadata":{"name":"test-secret"},"data":{"password":"cGFzc3dvcmQ="}}`,
+					},
+				}, nil)
+			})
+
+			It("redacts its data", func() {
+				Expect(res.StatusCode).To(Equal(http.StatusOK))
+				task := clouddriver.Task{}
+				Expect(json.NewDecoder(res.Body).Decode(&task)).To(Succeed())
+				Expect(task.ResultObjects[0].Manifests[0]["data"]).To(Equal(map[string]interface{}{"password": kubernetes.Redacted}))
+			})
+		})
+
+		When("the user may not read the account of the task", func() {
+			BeforeEach(func() {
+				req.Header.Set("X-Spinnaker-User", "test-user")
+				fakeFiatClient.AuthorizeReturns(fiat.Response{
+					Accounts: []fiat.Account{
+						{Name: "test-account-name", Authorizations: []string{"WRITE"}},
+					},
+				}, nil)
+			})
+
+			It("returns status forbidden", func() {
+				Expect(res.StatusCode).To(Equal(http.StatusForbidden))
+				ce := getClouddriverError()
+				Expect(ce.Message).To(Equal("Access denied to account test-account-name - required authorization: READ"))
+				Expect(fakeKubeClient.GetCallCount()).To(BeZero())
+			})
+		})
+
 		When("it succeeds", func() {
 			It("succeeds", func() {
 				Expect(res.StatusCode).To(Equal(http.StatusOK))
 			})
 		})
 	})
+
+	Describe("#ListTaskEvents", func() {
+		var events []clouddriver.TaskEvent
+
+		BeforeEach(func() {
+			setup()
+			uri = svr.URL + "/task/task-id/events"
+			createRequest(http.MethodGet)
+			fakeSQLClient.ListTaskEventsReturns([]clouddriver.TaskEvent{
+				{
+					TaskID:  "task-id",
+					Type:    clouddriver.TaskEventManifestApplied,
+					Message: "applied manifest 1/2: Deployment test-deployment in default",
+				},
+				{
+					TaskID:  "task-id",
+					Type:    clouddriver.TaskEventRolloutProgress,
+					Message: "waiting on rollout of Deployment test-deployment in default: 3/10 ready",
+				},
+			}, nil)
+			fakeSQLClient.ListKubernetesResourcesByTaskIDReturns([]kubernetes.Resource{
+				{AccountName: "test-account-name"},
+			}, nil)
+			events = nil
+		})
+
+		AfterEach(func() {
+			teardown()
+		})
+
+		JustBeforeEach(func() {
+			doRequest()
+
+			if res.StatusCode == http.StatusOK {
+				Expect(json.NewDecoder(res.Body).Decode(&events)).To(Succeed())
+			}
+		})
+
+		When("listing the resources of the task returns an error", func() {
+			BeforeEach(func() {
+				fakeSQLClient.ListKubernetesResourcesByTaskIDReturns(nil, errors.New("error listing resources"))
+			})
+
+			It("returns status internal server error", func() {
+				Expect(res.StatusCode).To(Equal(http.StatusInternalServerError))
+				ce := getClouddriverError()
+				Expect(ce.Message).To(Equal("error listing resources"))
+			})
+		})
+
+		When("the user may not read the account of the task", func() {
+			BeforeEach(func() {
+				req.Header.Set("X-Spinnaker-User", "test-user")
+				fakeFiatClient.AuthorizeReturns(fiat.Response{
+					Accounts: []fiat.Account{
+						{Name: "test-account-name", Authorizations: []string{"WRITE"}},
+					},
+				}, nil)
+			})
+
+			It("returns status forbidden", func() {
+				Expect(res.StatusCode).To(Equal(http.StatusForbidden))
+				ce := getClouddriverError()
+				Expect(ce.Message).To(Equal("Access denied to account test-account-name - required authorization: READ"))
+				Expect(fakeSQLClient.ListTaskEventsCallCount()).To(BeZero())
+			})
+		})
+
+		When("listing the events returns an error", func() {
+			BeforeEach(func() {
+				fakeSQLClient.ListTaskEventsReturns(nil, errors.New("error listing events"))
+			})
+
+			It("returns status internal server error", func() {
+				Expect(res.StatusCode).To(Equal(http.StatusInternalServerError))
+				ce := getClouddriverError()
+				Expect(ce.Message).To(Equal("error listing events"))
+			})
+		})
+
+		When("the task has no events", func() {
+			BeforeEach(func() {
+				fakeSQLClient.ListTaskEventsReturns(nil, nil)
+			})
+
+			It("returns an empty list", func() {
+				Expect(res.StatusCode).To(Equal(http.StatusOK))
+				Expect(events).ToNot(BeNil())
+				Expect(events).To(BeEmpty())
+			})
+		})
+
+		It("returns the events of the task in order", func() {
+			Expect(res.StatusCode).To(Equal(http.StatusOK))
+			Expect(fakeSQLClient.ListTaskEventsArgsForCall(0)).To(Equal("task-id"))
+			Expect(events).To(HaveLen(2))
+			Expect(events[0].Type).To(Equal(clouddriver.TaskEventManifestApplied))
+			Expect(events[1].Message).To(Equal("waiting on rollout of Deployment test-deployment in default: 3/10 ready"))
+		})
+	})
 })
@@ -0,0 +1,23 @@
+package core
+
+import (
+	"net/http"
+
+	"github.com/billiford/go-clouddriver/pkg/http/core/kubernetes"
+	"github.com/billiford/go-clouddriver/pkg/version"
+	"github.com/gin-gonic/gin"
+)
+
+type Version struct {
+	version.Info
+	Operations []string `json:"operations"`
+}
+
+// GetVersion returns the build info and the kubernetes operations
+// this build supports.
+func GetVersion(c *gin.Context) {
+	c.JSON(http.StatusOK, Version{
+		Info:       version.Get(),
+		Operations: kubernetes.SupportedOperations,
+	})
+}
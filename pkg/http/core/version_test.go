@@ -0,0 +1,66 @@
+package core_test
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/billiford/go-clouddriver/pkg/version"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Version", func() {
+	Describe("#GetVersion", func() {
+		BeforeEach(func() {
+			setup()
+			version.Version = "1.2.3"
+			version.GitCommit = "abc123"
+			version.BuildDate = "2020-10-13T10:06:17Z"
+			uri = svr.URL + "/version"
+			createRequest(http.MethodGet)
+		})
+
+		AfterEach(func() {
+			version.Version = "dev"
+			version.GitCommit = "unknown"
+			version.BuildDate = "unknown"
+			teardown()
+		})
+
+		JustBeforeEach(func() {
+			doRequest()
+		})
+
+		It("returns the build info and supported operations", func() {
+			Expect(res.StatusCode).To(Equal(http.StatusOK))
+			v := map[string]interface{}{}
+			Expect(json.NewDecoder(res.Body).Decode(&v)).To(Succeed())
+			Expect(v["version"]).To(Equal("1.2.3"))
+			Expect(v["gitCommit"]).To(Equal("abc123"))
+			Expect(v["buildDate"]).To(Equal("2020-10-13T10:06:17Z"))
+			Expect(v["goVersion"]).ToNot(BeEmpty())
+			Expect(v["operations"]).To(ContainElement("deployManifest"))
+			Expect(v["operations"]).To(ContainElement("runJob"))
+		})
+
+		It("is served as v1", func() {
+			Expect(res.Header.Get("X-Clouddriver-API-Version")).To(Equal("v1"))
+		})
+
+		When("it is requested under /v1", func() {
+			BeforeEach(func() {
+				uri = svr.URL + "/v1/version"
+				createRequest(http.MethodGet)
+			})
+
+			It("returns the build info", func() {
+				Expect(res.StatusCode).To(Equal(http.StatusOK))
+				Expect(res.Header.Get("X-Clouddriver-API-Version")).To(Equal("v1"))
+				v := map[string]interface{}{}
+				Expect(json.NewDecoder(res.Body).Decode(&v)).To(Succeed())
+				Expect(v["version"]).To(Equal("1.2.3"))
+			})
+		})
+	})
+})
@@ -0,0 +1,131 @@
+package core
+
+import (
+	"errors"
+	"net/http"
+
+	clouddriver "github.com/billiford/go-clouddriver/pkg"
+	"github.com/billiford/go-clouddriver/pkg/docker"
+	"github.com/billiford/go-clouddriver/pkg/events"
+	"github.com/billiford/go-clouddriver/pkg/kubernetes"
+	"github.com/gin-gonic/gin"
+	admissionv1 "k8s.io/api/admission/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// DockerRegistryWebhook is the body of a registry push notification. Both the
+// docker distribution notification format
+// (https://docs.docker.com/registry/notifications/) and the Docker Hub
+// webhook format (https://docs.docker.com/docker-hub/webhooks/) are supported.
+type DockerRegistryWebhook struct {
+	// Docker distribution.
+	Events []struct {
+		Action string `json:"action"`
+		Target struct {
+			Repository string `json:"repository"`
+			Tag        string `json:"tag"`
+		} `json:"target"`
+	} `json:"events"`
+	// Docker Hub.
+	PushData struct {
+		Tag string `json:"tag"`
+	} `json:"push_data"`
+	Repository struct {
+		RepoName string `json:"repo_name"`
+	} `json:"repository"`
+}
+
+// HandleDockerRegistryWebhook invalidates the cached tags of repositories
+// that had an image pushed to them, so the new tags show up without
+// waiting for the account's cache interval.
+func HandleDockerRegistryWebhook(c *gin.Context) {
+	dc := docker.CredentialsControllerInstance(c)
+	dw := DockerRegistryWebhook{}
+
+	client, err := dc.ClientForAccountName(c.Param("account"))
+	if err != nil {
+		clouddriver.WriteError(c, http.StatusNotFound, err)
+		return
+	}
+
+	err = c.ShouldBindJSON(&dw)
+	if err != nil {
+		clouddriver.WriteError(c, http.StatusBadRequest, err)
+		return
+	}
+
+	repositories := []string{}
+
+	for _, event := range dw.Events {
+		if event.Action == "push" && event.Target.Repository != "" {
+			repositories = append(repositories, event.Target.Repository)
+		}
+	}
+
+	if dw.Repository.RepoName != "" {
+		repositories = append(repositories, dw.Repository.RepoName)
+	}
+
+	for _, repository := range repositories {
+		client.Invalidate(repository)
+	}
+
+	c.JSON(http.StatusOK, gin.H{"invalidated": repositories})
+}
+
+// HandleKubernetesAdmissionWebhook receives AdmissionReviews from a cluster's
+// ValidatingWebhookConfiguration and invalidates caches of the account for the
+// reviewed resource. The request is always allowed - this webhook is only used
+// to learn about changes, so it should be configured with failurePolicy Ignore.
+func HandleKubernetesAdmissionWebhook(c *gin.Context) {
+	nc := kubernetes.NamespaceCacheInstance(c)
+	account := c.Param("account")
+	ar := admissionv1.AdmissionReview{}
+
+	err := c.ShouldBindJSON(&ar)
+	if err != nil {
+		clouddriver.WriteError(c, http.StatusBadRequest, err)
+		return
+	}
+
+	if ar.Request == nil {
+		clouddriver.WriteError(c, http.StatusBadRequest, errors.New("admission review is missing the request"))
+		return
+	}
+
+	events.Invalidate(nc, account, ar.Request.Resource.Resource)
+
+	c.JSON(http.StatusOK, admissionv1.AdmissionReview{
+		TypeMeta: metav1.TypeMeta{
+			APIVersion: "admission.k8s.io/v1",
+			Kind:       "AdmissionReview",
+		},
+		Response: &admissionv1.AdmissionResponse{
+			UID:     ar.Request.UID,
+			Allowed: true,
+		},
+	})
+}
+
+// HandleKubernetesAuditWebhook receives audit events from a cluster's audit
+// webhook backend and invalidates caches of the account for all resources
+// that were created, updated or deleted.
+func HandleKubernetesAuditWebhook(c *gin.Context) {
+	nc := kubernetes.NamespaceCacheInstance(c)
+	account := c.Param("account")
+	el := events.AuditEventList{}
+
+	err := c.ShouldBindJSON(&el)
+	if err != nil {
+		clouddriver.WriteError(c, http.StatusBadRequest, err)
+		return
+	}
+
+	for _, event := range el.Items {
+		if event.IsChange() {
+			events.Invalidate(nc, account, event.ObjectRef.Resource)
+		}
+	}
+
+	c.Status(http.StatusOK)
+}
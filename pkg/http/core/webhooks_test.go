@@ -0,0 +1,231 @@
+package core_test
+
+import (
+	"bytes"
+	"errors"
+	"net/http"
+
+	"github.com/billiford/go-clouddriver/pkg/webhook"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Webhooks", func() {
+	var token string
+
+	BeforeEach(func() {
+		setup()
+		token = "test-webhook-secret"
+	})
+
+	AfterEach(func() {
+		teardown()
+	})
+
+	JustBeforeEach(func() {
+		if token != "" {
+			req.Header.Set("Authorization", "Bearer "+token)
+		}
+
+		doRequest()
+	})
+
+	Describe("#HandleDockerRegistryWebhook", func() {
+		BeforeEach(func() {
+			uri = svr.URL + "/webhooks/dockerRegistry/docker-registry"
+			body.Write([]byte(payloadRequestDockerDistributionWebhook))
+			createRequest(http.MethodPost)
+		})
+
+		When("the request does not send the secret of the account", func() {
+			BeforeEach(func() {
+				token = "guess"
+			})
+
+			It("returns status unauthorized", func() {
+				Expect(res.StatusCode).To(Equal(http.StatusUnauthorized))
+				ce := getClouddriverError()
+				Expect(ce.Message).To(Equal("invalid webhook token for account docker-registry"))
+				Expect(fakeDockerClient.InvalidateCallCount()).To(BeZero())
+			})
+		})
+
+		When("the account has no webhook secret", func() {
+			BeforeEach(func() {
+				uri = svr.URL + "/webhooks/dockerRegistry/other-registry"
+				body = &bytes.Buffer{}
+				body.Write([]byte(payloadRequestDockerDistributionWebhook))
+				createRequest(http.MethodPost)
+			})
+
+			It("returns status unauthorized", func() {
+				Expect(res.StatusCode).To(Equal(http.StatusUnauthorized))
+				ce := getClouddriverError()
+				Expect(ce.Message).To(Equal("account other-registry has no webhook secret"))
+			})
+		})
+
+		When("the request signs the body", func() {
+			BeforeEach(func() {
+				token = ""
+				req.Header.Set(webhook.HeaderSignature, webhook.Sign("test-webhook-secret", []byte(payloadRequestDockerDistributionWebhook)))
+			})
+
+			It("invalidates pushed repositories", func() {
+				Expect(res.StatusCode).To(Equal(http.StatusOK))
+				Expect(fakeDockerClient.InvalidateCallCount()).To(Equal(1))
+			})
+		})
+
+		When("the account does not exist", func() {
+			BeforeEach(func() {
+				fakeDockerCredentialsController.ClientForAccountNameReturns(nil, errors.New("docker registry account docker-registry not found"))
+			})
+
+			It("returns status not found", func() {
+				Expect(res.StatusCode).To(Equal(http.StatusNotFound))
+				ce := getClouddriverError()
+				Expect(ce.Error).To(Equal("Not Found"))
+				Expect(ce.Message).To(Equal("docker registry account docker-registry not found"))
+				Expect(ce.Status).To(Equal(http.StatusNotFound))
+			})
+		})
+
+		When("the body is bad", func() {
+			BeforeEach(func() {
+				body = &bytes.Buffer{}
+				body.Write([]byte("{"))
+				createRequest(http.MethodPost)
+			})
+
+			It("returns status bad request", func() {
+				Expect(res.StatusCode).To(Equal(http.StatusBadRequest))
+				ce := getClouddriverError()
+				Expect(ce.Error).To(Equal("Bad Request"))
+				Expect(ce.Message).To(Equal("unexpected EOF"))
+				Expect(ce.Status).To(Equal(http.StatusBadRequest))
+			})
+		})
+
+		When("the webhook is from Docker Hub", func() {
+			BeforeEach(func() {
+				body = &bytes.Buffer{}
+				body.Write([]byte(payloadRequestDockerHubWebhook))
+				createRequest(http.MethodPost)
+			})
+
+			It("invalidates the repository", func() {
+				Expect(res.StatusCode).To(Equal(http.StatusOK))
+				Expect(fakeDockerClient.InvalidateCallCount()).To(Equal(1))
+				Expect(fakeDockerClient.InvalidateArgsForCall(0)).To(Equal("library/nginx"))
+				validateResponse(`{"invalidated":["library/nginx"]}`)
+			})
+		})
+
+		When("it succeeds", func() {
+			It("invalidates pushed repositories", func() {
+				Expect(res.StatusCode).To(Equal(http.StatusOK))
+				Expect(fakeDockerCredentialsController.ClientForAccountNameArgsForCall(0)).To(Equal("docker-registry"))
+				Expect(fakeDockerClient.InvalidateCallCount()).To(Equal(1))
+				Expect(fakeDockerClient.InvalidateArgsForCall(0)).To(Equal("library/nginx"))
+				validateResponse(`{"invalidated":["library/nginx"]}`)
+			})
+		})
+	})
+
+	Describe("#HandleKubernetesAdmissionWebhook", func() {
+		BeforeEach(func() {
+			uri = svr.URL + "/webhooks/kubernetes/test-account/admission"
+			body.Write([]byte(payloadRequestKubernetesAdmissionReview))
+			createRequest(http.MethodPost)
+		})
+
+		When("the body is bad", func() {
+			BeforeEach(func() {
+				body = &bytes.Buffer{}
+				body.Write([]byte("{"))
+				createRequest(http.MethodPost)
+			})
+
+			It("returns status bad request", func() {
+				Expect(res.StatusCode).To(Equal(http.StatusBadRequest))
+			})
+		})
+
+		When("the admission review is missing the request", func() {
+			BeforeEach(func() {
+				body = &bytes.Buffer{}
+				body.Write([]byte(`{"apiVersion":"admission.k8s.io/v1","kind":"AdmissionReview"}`))
+				createRequest(http.MethodPost)
+			})
+
+			It("returns status bad request", func() {
+				Expect(res.StatusCode).To(Equal(http.StatusBadRequest))
+				ce := getClouddriverError()
+				Expect(ce.Message).To(Equal("admission review is missing the request"))
+			})
+		})
+
+		When("the reviewed resource is not a namespace", func() {
+			BeforeEach(func() {
+				body = &bytes.Buffer{}
+				body.Write([]byte(payloadRequestKubernetesAdmissionReviewDeployment))
+				createRequest(http.MethodPost)
+			})
+
+			It("allows the request and does not invalidate namespaces", func() {
+				Expect(res.StatusCode).To(Equal(http.StatusOK))
+				Expect(fakeKubeNamespaceCache.DeleteCallCount()).To(BeZero())
+				validateResponse(payloadKubernetesAdmissionReview)
+			})
+		})
+
+		When("it succeeds", func() {
+			It("allows the request and invalidates the account's namespaces", func() {
+				Expect(res.StatusCode).To(Equal(http.StatusOK))
+				Expect(fakeKubeNamespaceCache.DeleteCallCount()).To(Equal(1))
+				Expect(fakeKubeNamespaceCache.DeleteArgsForCall(0)).To(Equal("test-account"))
+				validateResponse(payloadKubernetesAdmissionReview)
+			})
+		})
+	})
+
+	Describe("#HandleKubernetesAuditWebhook", func() {
+		BeforeEach(func() {
+			uri = svr.URL + "/webhooks/kubernetes/test-account/audit"
+			body.Write([]byte(payloadRequestKubernetesAuditEventList))
+			createRequest(http.MethodPost)
+		})
+
+		When("the body is bad", func() {
+			BeforeEach(func() {
+				body = &bytes.Buffer{}
+				body.Write([]byte("{"))
+				createRequest(http.MethodPost)
+			})
+
+			It("returns status bad request", func() {
+				Expect(res.StatusCode).To(Equal(http.StatusBadRequest))
+			})
+		})
+
+		When("the request is not authenticated", func() {
+			BeforeEach(func() {
+				token = ""
+			})
+
+			It("returns status unauthorized", func() {
+				Expect(res.StatusCode).To(Equal(http.StatusUnauthorized))
+				Expect(fakeKubeNamespaceCache.DeleteCallCount()).To(BeZero())
+			})
+		})
+
+		When("it succeeds", func() {
+			It("invalidates the account's namespaces once per namespace change", func() {
+				Expect(res.StatusCode).To(Equal(http.StatusOK))
+				Expect(fakeKubeNamespaceCache.DeleteCallCount()).To(Equal(1))
+				Expect(fakeKubeNamespaceCache.DeleteArgsForCall(0)).To(Equal("test-account"))
+			})
+		})
+	})
+})
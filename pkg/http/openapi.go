@@ -0,0 +1,200 @@
+package http
+
+import (
+	clouddriver "github.com/billiford/go-clouddriver/pkg"
+	"github.com/billiford/go-clouddriver/pkg/freeze"
+	"github.com/billiford/go-clouddriver/pkg/http/core"
+	ops "github.com/billiford/go-clouddriver/pkg/http/core/kubernetes"
+	v1 "github.com/billiford/go-clouddriver/pkg/http/v1"
+	"github.com/billiford/go-clouddriver/pkg/kubernetes"
+	"github.com/billiford/go-clouddriver/pkg/openapi"
+	"github.com/billiford/go-clouddriver/pkg/version"
+	"github.com/gin-gonic/gin"
+)
+
+const (
+	openAPISpecPath = "/swagger.json"
+	openAPIUIPath   = "/swagger-ui"
+)
+
+// docs describe the routes clients most often call, by their paths under
+// /v1. Routes not listed here are still in the spec, with their path params
+// but no bodies.
+var docs = map[string]openapi.Doc{
+	"GET /v1/version": {
+		Summary:  "Get the build info and supported operations",
+		Response: core.Version{},
+	},
+	"GET /v1/capabilities": {
+		Summary:  "Get the optional features this clouddriver has enabled",
+		Response: core.Capabilities{},
+	},
+	"GET /v1/credentials": {
+		Summary:  "List accounts",
+		Query:    []string{"expand", "since"},
+		Response: []clouddriver.Credential{},
+	},
+	"GET /v1/credentials/:account": {
+		Summary:  "Get an account",
+		Response: clouddriver.Credential{},
+	},
+	"GET /v1/credentials/:account/namespaces": {
+		Summary:  "List the namespaces of an account",
+		Response: []string{},
+	},
+	"PUT /v1/credentials/:account/rotate": {
+		Summary: "Validate and replace the credentials of an account",
+		Request: core.RotateCredentialsRequest{},
+	},
+	"GET /v1/namespaces": {
+		Summary:  "List the namespaces of several accounts",
+		Query:    []string{"accounts"},
+		Response: []core.AccountNamespaces{},
+	},
+	"GET /v1/applications": {
+		Summary:  "List applications",
+		Response: core.Applications{},
+	},
+	"GET /v1/applications/:application/serverGroupManagers": {
+		Summary:  "List the server group managers of an application",
+		Response: core.ServerGroupManagers{},
+	},
+	"GET /v1/applications/:application/serverGroups": {
+		Summary:  "List the server groups of an application",
+		Response: core.ServerGroups{},
+	},
+	"GET /v1/applications/:application/serverGroups/:account/:location/:name": {
+		Summary:  "Get a server group",
+		Response: core.ServerGroup{},
+	},
+	"GET /v1/applications/:application/loadBalancers": {
+		Summary:  "List the load balancers of an application",
+		Response: core.LoadBalancers{},
+	},
+	"GET /v1/applications/:application/clusters": {
+		Summary:  "List the clusters of an application by account",
+		Response: core.Clusters{},
+	},
+	"GET /v1/projects/:project/clusters": {
+		Summary:  "List the clusters of a project",
+		Response: core.ProjectClusters{},
+	},
+	"GET /v1/freezes": {
+		Summary:  "List change freezes",
+		Response: []freeze.Status{},
+	},
+	"POST /v1/kubernetes/ops": {
+		Summary:  "Create a task running Kubernetes operations",
+		Request:  ops.Operations{},
+		Response: ops.OperationsResponse{},
+	},
+	"POST /v1/kubernetes/ops/simulate": {
+		Summary:  "Show the manifests operations would apply",
+		Request:  ops.Operations{},
+		Response: core.Simulation{},
+	},
+	"GET /v1/manifests/:account/:location/:kind": {
+		Summary:  "Get a manifest by its kind and name, as 'kind name'",
+		Response: ops.ManifestResponse{},
+	},
+	"GET /v1/manifests/:account/:location/:kind/cluster/:application/:cluster/dynamic/:target": {
+		Summary:  "Get the manifest of a cluster by a target such as newest",
+		Response: ops.ManifestResponse{},
+	},
+	"GET /v1/task/:id": {
+		Summary:  "Get the status and results of a task",
+		Response: clouddriver.Task{},
+	},
+	"GET /v1/task/:id/stream": {
+		Summary: "Stream the rollout of the resources of a task as server-sent events",
+		Query:   []string{"timeout"},
+	},
+	"GET /v1/task/:id/events": {
+		Summary:  "List the events of a task",
+		Response: []clouddriver.TaskEvent{},
+	},
+	"GET /v1/search": {
+		Summary:  "Search deployed resources by name and category, or by namespace and kind",
+		Query:    []string{"q", "type", "page", "pageSize"},
+		Response: core.SearchResponse{},
+	},
+	"GET /v1/dockerRegistry/images/find": {
+		Summary: "Find images of docker registry accounts",
+		Query:   []string{"account", "q", "count"},
+	},
+	"GET /v1/admin/accounts": {
+		Summary:  "List accounts with the health of their clusters",
+		Response: []core.AdminAccount{},
+	},
+	"GET /v1/admin/accounts/:account/permissions": {
+		Summary:  "Probe the permissions of an account in its cluster",
+		Query:    []string{"namespace"},
+		Response: []core.AdminPermission{},
+	},
+	"GET /v1/admin/queue": {
+		Summary:  "Get the operation queue of each account",
+		Response: core.AdminQueue{},
+	},
+	"POST /v1/kubernetes/providers": {
+		Summary:  "Create a Kubernetes account",
+		Request:  kubernetes.Provider{},
+		Response: kubernetes.Provider{},
+	},
+	"GET /v1/kubernetes/providers/:name/kindMap": {
+		Summary:  "List the kinds an account maps to categories other than their defaults",
+		Response: map[string]string{},
+	},
+	"PUT /v1/kubernetes/providers/:name/kindMap/:kind": {
+		Summary:  "Map a kind of an account to a category, such as rollout to serverGroups",
+		Request:  v1.KindMappingRequest{},
+		Response: kubernetes.KindMapping{},
+	},
+	"POST /v1/kubernetes/clusterCredentials": {
+		Summary:  "Create credentials of a cluster shared by accounts",
+		Request:  kubernetes.ClusterCredential{},
+		Response: kubernetes.ClusterCredential{},
+	},
+	"PUT /v1/kubernetes/clusterCredentials/:name": {
+		Summary:  "Replace the credentials of a cluster shared by accounts",
+		Request:  kubernetes.ClusterCredential{},
+		Response: kubernetes.ClusterCredential{},
+	},
+}
+
+// serveOpenAPI serves the spec of the routes registered on r as JSON, with a
+// Swagger UI of it for trying out the API. Unversioned routes also served
+// under /v1 are only described there.
+func serveOpenAPI(r *gin.Engine) {
+	info := openapi.Info{
+		Title:   "go-clouddriver",
+		Version: version.Version,
+	}
+
+	// Generate the spec before its own routes are added, so it does not
+	// describe them.
+	spec := openapi.Generate(info, versionedRoutes(r.Routes()), docs, clouddriver.Error{})
+
+	r.GET(openAPISpecPath, openapi.Handler(spec))
+	r.GET(openAPIUIPath, openapi.UIHandler(openAPISpecPath))
+}
+
+// versionedRoutes returns routes without the unversioned routes that are
+// also served under /v1.
+func versionedRoutes(routes gin.RoutesInfo) gin.RoutesInfo {
+	registered := map[string]bool{}
+	for _, route := range routes {
+		registered[route.Method+" "+route.Path] = true
+	}
+
+	filtered := gin.RoutesInfo{}
+
+	for _, route := range routes {
+		if registered[route.Method+" /"+clouddriver.APIVersionV1+route.Path] {
+			continue
+		}
+
+		filtered = append(filtered, route)
+	}
+
+	return filtered
+}
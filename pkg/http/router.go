@@ -1,6 +1,7 @@
 package http
 
 import (
+	clouddriver "github.com/billiford/go-clouddriver/pkg"
 	"github.com/billiford/go-clouddriver/pkg/http/core"
 	v1 "github.com/billiford/go-clouddriver/pkg/http/v1"
 	"github.com/billiford/go-clouddriver/pkg/middleware"
@@ -8,66 +9,110 @@ import (
 )
 
 // Define the API.
+//
+// Every endpoint is served under /v1. The core endpoints are also served at
+// their unversioned paths, which Gate and Orca call, as v1, so a later
+// version, such as /v2, can change the shape of a response without breaking them.
 func Initialize(r *gin.Engine) {
+	initializeCore(r.Group("", middleware.APIVersion(clouddriver.APIVersionV1)))
+
+	versioned := r.Group("/"+clouddriver.APIVersionV1, middleware.APIVersion(clouddriver.APIVersionV1))
+	initializeCore(versioned)
+	initializeV1(versioned)
+
+	// OpenAPI spec of the routes above at /swagger.json, and a UI at /swagger-ui.
+	serveOpenAPI(r)
+}
+
+// initializeCore defines the core endpoints under r.
+func initializeCore(r *gin.RouterGroup) {
 	// API endpoints without a version will go under "core".
 	{
 		api := r.Group("")
 		api.GET("/health", core.OK)
+		api.GET("/version", core.GetVersion)
+		api.GET("/capabilities", core.GetCapabilities)
 
-		// Force cache refresh.
-		api.POST("/cache/kubernetes/manifest", core.OK)
+		// Force cache refresh, and list the refreshes Orca waits on.
+		api.POST("/cache/kubernetes/manifest", core.RefreshManifestCache)
+		api.GET("/cache/kubernetes/manifest", core.ListPendingManifestCacheRefreshes)
 
 		// Credentials API controller.
 		api.GET("/credentials", core.ListCredentials)
-		api.GET("/credentials/:account", core.GetAccountCredentials)
+		api.GET("/credentials/:account", middleware.LoadAccount(), core.GetAccountCredentials)
+		api.GET("/credentials/:account/namespaces", core.ListAccountNamespaces)
+		// Validate and swap the credentials of an account.
+		api.PUT("/credentials/:account/rotate", middleware.AuthAccount("WRITE"), middleware.LoadAccount(), core.RotateAccountCredentials)
+
+		// Namespaces aggregated across accounts, optionally filtered by the 'accounts' query param.
+		api.GET("/namespaces", core.ListNamespaces)
 
 		// Applications API controller.
 		//
 		// https://github.com/spinnaker/clouddriver/blob/master/clouddriver-web/src/main/groovy/com/netflix/spinnaker/clouddriver/controllers/ApplicationsController.groovy#L38
 		// @PreAuthorize("#restricted ? @fiatPermissionEvaluator.storeWholePermission() : true") -- story created
 		// @PostFilter("#restricted ? hasPermission(filterObject.name, 'APPLICATION', 'READ') : true") -- done
-		api.GET("/applications", middleware.PostFilterAuthorizedApplications("READ"), core.ListApplications)
+		api.GET("/applications", middleware.LiveData(), middleware.PostFilterAuthorizedApplications("READ"), core.ListApplications)
 
 		// https://github.com/spinnaker/clouddriver/blob/master/clouddriver-web/src/main/groovy/com/netflix/spinnaker/clouddriver/controllers/ServerGroupManagerController.java#L39
 		// @PreAuthorize("hasPermission(#application, 'APPLICATION', 'READ')") --- done
 		// @PostFilter("hasPermission(filterObject.account, 'ACCOUNT', 'READ')") -- story created
-		api.GET("/applications/:application/serverGroupManagers", middleware.AuthApplication("READ"), core.ListServerGroupManagers)
+		api.GET("/applications/:application/serverGroupManagers", middleware.LiveData(), middleware.AuthApplication("READ"), core.ListServerGroupManagers)
 
 		// https://github.com/spinnaker/clouddriver/blob/master/clouddriver-web/src/main/groovy/com/netflix/spinnaker/clouddriver/controllers/ServerGroupController.groovy#L172
 		// @PreAuthorize("hasPermission(#application, 'APPLICATION', 'READ')") -- done
 		// @PostAuthorize("@authorizationSupport.filterForAccounts(returnObject)") -- story created
-		api.GET("/applications/:application/serverGroups", middleware.AuthApplication("READ"), core.ListServerGroups)
+		api.GET("/applications/:application/serverGroups", middleware.LiveData(), middleware.AuthApplication("READ"), middleware.Snapshot(), core.ListServerGroups)
 
 		// https://github.com/spinnaker/clouddriver/blob/master/clouddriver-web/src/main/groovy/com/netflix/spinnaker/clouddriver/controllers/ServerGroupController.groovy#L75
 		// @PreAuthorize("hasPermission(#account, 'ACCOUNT', 'READ')") -- done
 		// @PostAuthorize("hasPermission(returnObject?.moniker?.app, 'APPLICATION', 'READ')") -- create story
 		// textPayload: "Headers: map[Accept:[application/json] Accept-Encoding:[gzip] Connection:[Keep-Alive] User-Agent:[okhttp/3.14.9] X-Spinnaker-Accounts:[gke_github-replication-sandbox_us-east1_sandbox-us-east1-agent-dev,gke_github-replication-sandbox_us-east1_sandbox-us-east1-dev,gke_github-replication-sandbox_us-central1-c_prom-test] X-Spinnaker-Application:[smoketests] X-Spinnaker-User:[me@me.com]]"
-		api.GET("/applications/:application/serverGroups/:account/:location/:name", middleware.AuthAccount("READ"), core.GetServerGroup)
+		api.GET("/applications/:application/serverGroups/:account/:location/:name", middleware.LiveData(), middleware.AuthAccount("READ"), middleware.Snapshot(), middleware.LoadAccount(), core.GetServerGroup)
 
 		// https: //github.com/spinnaker/clouddriver/blob/master/clouddriver-web/src/main/groovy/com/netflix/spinnaker/clouddriver/controllers/LoadBalancerController.groovy#L42
 		// @PreAuthorize("hasPermission(#application, 'APPLICATION', 'READ')") -- done
 		// @PostAuthorize("@authorizationSupport.filterForAccounts(returnObject)") --- story created
-		api.GET("/applications/:application/loadBalancers", middleware.AuthApplication("READ"), core.ListLoadBalancers)
+		api.GET("/applications/:application/loadBalancers", middleware.LiveData(), middleware.AuthApplication("READ"), core.ListLoadBalancers)
 
 		// https://github.com/spinnaker/clouddriver/blob/master/clouddriver-web/src/main/groovy/com/netflix/spinnaker/clouddriver/controllers/ClusterController.groovy#L44
 		// @PreAuthorize("@fiatPermissionEvaluator.storeWholePermission() -- story created and hasPermission(#application, 'APPLICATION', 'READ')") -- done
 		// @PostAuthorize("@authorizationSupport.filterForAccounts(returnObject)") -- story created
-		api.GET("/applications/:application/clusters", middleware.AuthApplication("READ"), core.ListClusters)
+		api.GET("/applications/:application/clusters", middleware.LiveData(), middleware.AuthApplication("READ"), middleware.Snapshot(), core.ListClusters)
 
 		// https://github.com/spinnaker/clouddriver/blob/master/clouddriver-web/src/main/groovy/com/netflix/spinnaker/clouddriver/controllers/JobController.groovy#L35
 		// @PreAuthorize("hasPermission(#application, 'APPLICATION', 'READ') -- done and hasPermission(#account, 'ACCOUNT', 'READ')") -- done
 		// @ApiOperation(value = "Collect a JobStatus", notes = "Collects the output of the job.")
-		api.GET("/applications/:application/jobs/:account/:location/:name", middleware.AuthApplication("READ"), middleware.AuthAccount("READ"), core.GetJob)
+		api.GET("/applications/:application/jobs/:account/:location/:name", middleware.LiveData(), middleware.AuthApplication("READ"), middleware.AuthAccount("READ"), middleware.LoadAccount(), core.GetJob)
+		// Stream the logs of a job's pods over a websocket while it runs.
+		api.GET("/applications/:application/jobs/:account/:location/:name/logs", middleware.AuthApplication("READ"), middleware.AuthAccount("READ"), middleware.LoadAccount(), core.StreamJobLogs)
+
+		// https://github.com/spinnaker/clouddriver/blob/master/clouddriver-web/src/main/groovy/com/netflix/spinnaker/clouddriver/controllers/ProjectController.groovy
+		// Projects are read from /opt/spinnaker/projects/config instead of front50.
+		api.GET("/projects/:project/clusters", middleware.LiveData(), core.ListProjectClusters)
+
+		// Change freezes, for Deck to show as banners.
+		api.GET("/freezes", core.ListFreezes)
 
 		// Create a kubernetes operation - deploy/delete/scale manifest.
-		api.POST("/kubernetes/ops", core.CreateKubernetesOperation)
+		api.POST("/kubernetes/ops", middleware.AuthOperations(), core.CreateKubernetesOperation)
+		// Show the manifests operations would apply, without changing anything.
+		api.POST("/kubernetes/ops/simulate", middleware.AuthOperations(), core.SimulateKubernetesOperation)
 
 		// Manifests API controller.
-		api.GET("/manifests/:account/:location/:kind", core.GetManifest)
-		api.GET("/manifests/:account/:location/:kind/cluster/:application/:cluster/dynamic/:target", core.GetManifestByTarget)
+		//
+		// Routes with an account load its provider with LoadAccount, which
+		// returns 404 Not Found for unknown accounts. Application and manifest
+		// routes mark their responses with LiveData.
+		api.GET("/manifests/:account/:location/:kind", middleware.LiveData(), middleware.LoadAccount(), core.GetManifest)
+		api.GET("/manifests/:account/:location/:kind/cluster/:application/:cluster/dynamic/:target", middleware.LiveData(), middleware.LoadAccount(), core.GetManifestByTarget)
 
 		// Get results for a task triggered in CreateKubernetesOperation.
 		api.GET("/task/:id", core.GetTask)
+		// Stream the rollout of a task's resources as server-sent events.
+		api.GET("/task/:id/stream", core.StreamTask)
+		// List the events of a task in order.
+		api.GET("/task/:id/events", core.ListTaskEvents)
 
 		// Generic search endpoint.
 		//
@@ -87,15 +132,70 @@ func Initialize(r *gin.Engine) {
 		api.GET("/artifacts/account/:accountName/versions", core.ListHelmArtifactAccountVersions)
 		api.PUT("/artifacts/fetch/", core.GetArtifact)
 
+		// Docker registry API controller.
+		api.GET("/dockerRegistry/images/repositories", core.ListDockerRegistryRepositories)
+		api.GET("/dockerRegistry/images/tags", core.ListDockerRegistryTags)
+		api.GET("/dockerRegistry/images/find", core.FindDockerRegistryImages)
+
+		// Webhooks to invalidate caches when registries or clusters change.
+		api.POST("/webhooks/dockerRegistry/:account", middleware.AuthWebhook(), core.HandleDockerRegistryWebhook)
+		api.POST("/webhooks/kubernetes/:account/admission", middleware.AuthWebhook(), middleware.LoadAccount(), core.HandleKubernetesAdmissionWebhook)
+		api.POST("/webhooks/kubernetes/:account/audit", middleware.AuthWebhook(), middleware.LoadAccount(), core.HandleKubernetesAuditWebhook)
+
 		// Features.
 		api.GET("/features/stages", core.ListStages)
 	}
 
+	// Endpoints for an operations dashboard, only for Fiat admins.
+	{
+		api := r.Group("/admin", middleware.AuthAdmin())
+		api.GET("/accounts", core.ListAdminAccounts)
+		api.POST("/accounts/:account/cache/refresh", middleware.LoadAccount(), core.RefreshAdminAccountCache)
+		api.GET("/accounts/:account/permissions", middleware.LoadAccount(), core.ProbeAdminAccountPermissions)
+		api.GET("/queue", core.GetAdminQueue)
+		// Goroutines of this instance, to find leaks.
+		api.GET("/goroutines", core.GetAdminGoroutines)
+		// Profile this instance in production without a debug build.
+		api.GET("/debug/pprof/*profile", core.ServeDebugProfile)
+		api.POST("/debug/pprof/*profile", core.ServeDebugProfile)
+		api.GET("/debug/vars", core.ServeDebugVars)
+		api.POST("/debug/snapshots", core.CreateDebugSnapshot)
+		// Account health report for platform reviews, as JSON or CSV.
+		api.GET("/reports/accounts", core.GetAccountReport)
+		api.PUT("/features/stages/:name", core.ToggleAdminStage)
+		api.POST("/tasks/:id/replay", core.ReplayAdminTask)
+		// Requests recorded for a pipeline execution, for debugging.
+		api.GET("/recordings/:executionId", core.ListRecordings)
+	}
+
+	// Usage statistics, such as of the API servers of accounts.
+	{
+		api := r.Group("/stats")
+		api.GET("/requests", middleware.PostFilterAuthorizedStats("READ"), core.ListRequestStats)
+		// Deploys by application and namespace, for delivery reporting.
+		api.GET("/deploys", middleware.PostFilterAuthorizedStats("READ"), core.ListDeployStats)
+	}
+
+}
+
+// initializeV1 defines the endpoints only served under /v1, which r is the group of.
+func initializeV1(r *gin.RouterGroup) {
 	// New endpoint.
 	{
-		api := r.Group("/v1")
+		api := r.Group("")
 		// Providers endpoint for kubernetes.
 		api.POST("/kubernetes/providers", v1.CreateKubernetesProvider)
 		api.DELETE("/kubernetes/providers/:name", v1.DeleteKubernetesProvider)
+		// Reject operations against a provider while its cluster is upgraded.
+		api.PUT("/kubernetes/providers/:name/maintenance", v1.StartKubernetesProviderMaintenance)
+		api.DELETE("/kubernetes/providers/:name/maintenance", v1.EndKubernetesProviderMaintenance)
+		// Kinds a provider maps to categories other than their defaults, such as CRDs.
+		api.GET("/kubernetes/providers/:name/kindMap", v1.ListKubernetesProviderKindMap)
+		api.PUT("/kubernetes/providers/:name/kindMap/:kind", v1.SetKubernetesProviderKindMapping)
+		api.DELETE("/kubernetes/providers/:name/kindMap/:kind", v1.DeleteKubernetesProviderKindMapping)
+		// Credentials of a cluster shared by providers scoped to its namespaces.
+		api.POST("/kubernetes/clusterCredentials", v1.CreateKubernetesClusterCredential)
+		api.PUT("/kubernetes/clusterCredentials/:name", v1.UpdateKubernetesClusterCredential)
+		api.DELETE("/kubernetes/clusterCredentials/:name", v1.DeleteKubernetesClusterCredential)
 	}
 }
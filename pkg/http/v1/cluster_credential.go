@@ -0,0 +1,125 @@
+package v1
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/billiford/go-clouddriver/pkg/kubernetes"
+	"github.com/billiford/go-clouddriver/pkg/sql"
+	"github.com/gin-gonic/gin"
+	"github.com/jinzhu/gorm"
+)
+
+// CreateKubernetesClusterCredential creates a cluster credential that
+// providers can name instead of setting a host, CA data and tokens of their
+// own.
+func CreateKubernetesClusterCredential(c *gin.Context) {
+	sc := sql.Instance(c)
+	cc := kubernetes.ClusterCredential{}
+
+	err := c.ShouldBindJSON(&cc)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	if !cc.ValidTokenServiceAccount() {
+		c.JSON(http.StatusBadRequest, gin.H{"error": fmt.Sprintf("token service account %q must be namespace/name",
+			cc.TokenServiceAccount)})
+		return
+	}
+
+	_, err = sc.GetKubernetesClusterCredential(cc.Name)
+	if err == nil {
+		c.JSON(http.StatusConflict, gin.H{"error": "cluster credential already exists"})
+		return
+	}
+
+	err = sc.CreateKubernetesClusterCredential(cc)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusCreated, cc)
+}
+
+// UpdateKubernetesClusterCredential replaces the host, CA data and tokens of
+// a cluster credential, such as to rotate its bearer token, for every
+// provider that names it.
+func UpdateKubernetesClusterCredential(c *gin.Context) {
+	sc := sql.Instance(c)
+	cc := kubernetes.ClusterCredential{}
+
+	err := c.ShouldBindJSON(&cc)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	cc.Name = c.Param("name")
+
+	if !cc.ValidTokenServiceAccount() {
+		c.JSON(http.StatusBadRequest, gin.H{"error": fmt.Sprintf("token service account %q must be namespace/name",
+			cc.TokenServiceAccount)})
+		return
+	}
+
+	_, err = sc.GetKubernetesClusterCredential(cc.Name)
+	if err != nil {
+		if err == gorm.ErrRecordNotFound {
+			c.JSON(http.StatusNotFound, gin.H{"error": "cluster credential not found"})
+			return
+		}
+
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	err = sc.UpdateKubernetesClusterCredential(cc)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, cc)
+}
+
+// DeleteKubernetesClusterCredential deletes a cluster credential no provider
+// names.
+func DeleteKubernetesClusterCredential(c *gin.Context) {
+	sc := sql.Instance(c)
+	name := c.Param("name")
+
+	_, err := sc.GetKubernetesClusterCredential(name)
+	if err != nil {
+		if err == gorm.ErrRecordNotFound {
+			c.JSON(http.StatusNotFound, gin.H{"error": "cluster credential not found"})
+			return
+		}
+
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	names, err := sc.ListKubernetesProviderNamesByClusterCredential(name)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	if len(names) > 0 {
+		c.JSON(http.StatusConflict, gin.H{"error": fmt.Sprintf("cluster credential %s is used by accounts %s",
+			name, strings.Join(names, ", "))})
+		return
+	}
+
+	err = sc.DeleteKubernetesClusterCredential(name)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusNoContent, nil)
+}
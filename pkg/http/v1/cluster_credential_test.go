@@ -0,0 +1,184 @@
+package v1_test
+
+import (
+	"bytes"
+	"errors"
+	"net/http"
+
+	"github.com/billiford/go-clouddriver/pkg/kubernetes"
+	"github.com/jinzhu/gorm"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("ClusterCredential", func() {
+	Describe("#CreateKubernetesClusterCredential", func() {
+		BeforeEach(func() {
+			setup()
+			fakeSQLClient.GetKubernetesClusterCredentialReturns(kubernetes.ClusterCredential{}, gorm.ErrRecordNotFound)
+			uri = svr.URL + "/v1/kubernetes/clusterCredentials"
+			body.Write([]byte(payloadRequestKubernetesClusterCredential))
+			createRequest(http.MethodPost)
+		})
+
+		AfterEach(func() {
+			teardown()
+		})
+
+		JustBeforeEach(func() {
+			doRequest()
+		})
+
+		When("the request body is bad data", func() {
+			BeforeEach(func() {
+				body = &bytes.Buffer{}
+				body.Write([]byte("dasdf[]dsf;;"))
+				createRequest(http.MethodPost)
+			})
+
+			It("returns status bad request", func() {
+				Expect(res.StatusCode).To(Equal(http.StatusBadRequest))
+				validateResponse(payloadBadRequest)
+			})
+		})
+
+		When("the token service account is invalid", func() {
+			BeforeEach(func() {
+				body = &bytes.Buffer{}
+				body.Write([]byte(`{"name": "test-cluster", "tokenServiceAccount": "deployer"}`))
+				createRequest(http.MethodPost)
+			})
+
+			It("returns status bad request", func() {
+				Expect(res.StatusCode).To(Equal(http.StatusBadRequest))
+				validateResponse(payloadInvalidTokenServiceAccount)
+			})
+		})
+
+		When("the cluster credential already exists", func() {
+			BeforeEach(func() {
+				fakeSQLClient.GetKubernetesClusterCredentialReturns(kubernetes.ClusterCredential{}, nil)
+			})
+
+			It("returns status conflict", func() {
+				Expect(res.StatusCode).To(Equal(http.StatusConflict))
+				validateResponse(payloadClusterCredentialConflict)
+			})
+		})
+
+		When("creating the cluster credential returns an error", func() {
+			BeforeEach(func() {
+				fakeSQLClient.CreateKubernetesClusterCredentialReturns(errors.New("error creating cluster credential"))
+			})
+
+			It("returns status internal server error", func() {
+				Expect(res.StatusCode).To(Equal(http.StatusInternalServerError))
+				validateResponse(payloadErrorCreatingClusterCredential)
+			})
+		})
+
+		When("it succeeds", func() {
+			It("returns status created", func() {
+				Expect(res.StatusCode).To(Equal(http.StatusCreated))
+				Expect(fakeSQLClient.CreateKubernetesClusterCredentialCallCount()).To(Equal(1))
+				validateResponse(payloadKubernetesClusterCredential)
+			})
+		})
+	})
+
+	Describe("#UpdateKubernetesClusterCredential", func() {
+		BeforeEach(func() {
+			setup()
+			uri = svr.URL + "/v1/kubernetes/clusterCredentials/test-cluster"
+			body.Write([]byte(`{"host": "test-host", "caData": "test-ca-data", "bearerToken": "test-token"}`))
+			createRequest(http.MethodPut)
+		})
+
+		AfterEach(func() {
+			teardown()
+		})
+
+		JustBeforeEach(func() {
+			doRequest()
+		})
+
+		When("the record is not found", func() {
+			BeforeEach(func() {
+				fakeSQLClient.GetKubernetesClusterCredentialReturns(kubernetes.ClusterCredential{}, gorm.ErrRecordNotFound)
+			})
+
+			It("returns status not found", func() {
+				Expect(res.StatusCode).To(Equal(http.StatusNotFound))
+				validateResponse(payloadClusterCredentialNotFound)
+			})
+		})
+
+		When("updating the cluster credential returns an error", func() {
+			BeforeEach(func() {
+				fakeSQLClient.UpdateKubernetesClusterCredentialReturns(errors.New("error updating cluster credential"))
+			})
+
+			It("returns status internal server error", func() {
+				Expect(res.StatusCode).To(Equal(http.StatusInternalServerError))
+				validateResponse(payloadErrorUpdatingClusterCredential)
+			})
+		})
+
+		When("it succeeds", func() {
+			It("updates the cluster credential named in the path", func() {
+				Expect(res.StatusCode).To(Equal(http.StatusOK))
+				cc := fakeSQLClient.UpdateKubernetesClusterCredentialArgsForCall(0)
+				Expect(cc.Name).To(Equal("test-cluster"))
+				Expect(cc.BearerToken).To(Equal("test-token"))
+				validateResponse(payloadKubernetesClusterCredential)
+			})
+		})
+	})
+
+	Describe("#DeleteKubernetesClusterCredential", func() {
+		BeforeEach(func() {
+			setup()
+			uri = svr.URL + "/v1/kubernetes/clusterCredentials/test-cluster"
+			createRequest(http.MethodDelete)
+		})
+
+		AfterEach(func() {
+			teardown()
+		})
+
+		JustBeforeEach(func() {
+			doRequest()
+		})
+
+		When("the record is not found", func() {
+			BeforeEach(func() {
+				fakeSQLClient.GetKubernetesClusterCredentialReturns(kubernetes.ClusterCredential{}, gorm.ErrRecordNotFound)
+			})
+
+			It("returns status not found", func() {
+				Expect(res.StatusCode).To(Equal(http.StatusNotFound))
+				validateResponse(payloadClusterCredentialNotFound)
+			})
+		})
+
+		When("providers use the cluster credential", func() {
+			BeforeEach(func() {
+				fakeSQLClient.ListKubernetesProviderNamesByClusterCredentialReturns([]string{"team-a", "team-b"}, nil)
+			})
+
+			It("returns status conflict", func() {
+				Expect(res.StatusCode).To(Equal(http.StatusConflict))
+				Expect(fakeSQLClient.DeleteKubernetesClusterCredentialCallCount()).To(BeZero())
+				validateResponse(payloadClusterCredentialInUse)
+			})
+		})
+
+		When("it succeeds", func() {
+			It("returns status no content", func() {
+				Expect(res.StatusCode).To(Equal(http.StatusNoContent))
+				Expect(fakeSQLClient.DeleteKubernetesClusterCredentialArgsForCall(0)).To(Equal("test-cluster"))
+			})
+		})
+	})
+})
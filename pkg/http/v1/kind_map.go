@@ -0,0 +1,115 @@
+package v1
+
+import (
+	"net/http"
+
+	"github.com/billiford/go-clouddriver/pkg/kubernetes"
+	"github.com/billiford/go-clouddriver/pkg/sql"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/jinzhu/gorm"
+)
+
+// KindMappingRequest maps a kind of a provider to a category.
+type KindMappingRequest struct {
+	Category string `json:"category"`
+}
+
+// ListKubernetesProviderKindMap lists the kinds a provider maps to categories
+// other than their defaults.
+func ListKubernetesProviderKindMap(c *gin.Context) {
+	sc := sql.Instance(c)
+	name := c.Param("name")
+
+	if !providerExists(c, sc, name) {
+		return
+	}
+
+	kindMaps, err := sc.ListKindMappingsByAccountNames(name)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	kindMap := kindMaps[name]
+	if kindMap == nil {
+		kindMap = map[string]string{}
+	}
+
+	c.JSON(http.StatusOK, kindMap)
+}
+
+// SetKubernetesProviderKindMapping maps a kind of a provider to a category,
+// such as rollout to serverGroups, so Deck shows it with the stages of the category.
+func SetKubernetesProviderKindMapping(c *gin.Context) {
+	sc := sql.Instance(c)
+	name := c.Param("name")
+	kind := c.Param("kind")
+	kmr := KindMappingRequest{}
+
+	err := c.ShouldBindJSON(&kmr)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	err = kubernetes.ValidateKindMapping(kind, kmr.Category)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	if !providerExists(c, sc, name) {
+		return
+	}
+
+	km := kubernetes.KindMapping{
+		ID:          uuid.New().String(),
+		AccountName: name,
+		Kind:        kind,
+		Category:    kmr.Category,
+	}
+
+	err = sc.SetKindMapping(km)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, km)
+}
+
+// DeleteKubernetesProviderKindMapping returns a kind of a provider to its default category.
+func DeleteKubernetesProviderKindMapping(c *gin.Context) {
+	sc := sql.Instance(c)
+	name := c.Param("name")
+
+	if !providerExists(c, sc, name) {
+		return
+	}
+
+	err := sc.DeleteKindMapping(name, c.Param("kind"))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusNoContent, nil)
+}
+
+// providerExists writes an error and returns false if the provider cannot be gotten.
+func providerExists(c *gin.Context, sc sql.Client, name string) bool {
+	_, err := sc.GetKubernetesProvider(name)
+	if err != nil {
+		if err == gorm.ErrRecordNotFound {
+			c.JSON(http.StatusNotFound, gin.H{"error": "provider not found"})
+			return false
+		}
+
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+
+		return false
+	}
+
+	return true
+}
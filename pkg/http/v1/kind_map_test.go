@@ -0,0 +1,202 @@
+package v1_test
+
+import (
+	"bytes"
+	"errors"
+	"net/http"
+
+	"github.com/billiford/go-clouddriver/pkg/kubernetes"
+	"github.com/jinzhu/gorm"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("KindMap", func() {
+	Describe("#ListKubernetesProviderKindMap", func() {
+		BeforeEach(func() {
+			setup()
+			fakeSQLClient.ListKindMappingsByAccountNamesReturns(map[string]map[string]string{
+				"test-name": {
+					"rollout": "serverGroups",
+				},
+			}, nil)
+			uri = svr.URL + "/v1/kubernetes/providers/test-name/kindMap"
+			createRequest(http.MethodGet)
+		})
+
+		AfterEach(func() {
+			teardown()
+		})
+
+		JustBeforeEach(func() {
+			doRequest()
+		})
+
+		When("the provider is not found", func() {
+			BeforeEach(func() {
+				fakeSQLClient.GetKubernetesProviderReturns(kubernetes.Provider{}, gorm.ErrRecordNotFound)
+			})
+
+			It("returns status not found", func() {
+				Expect(res.StatusCode).To(Equal(http.StatusNotFound))
+				validateResponse(payloadKubernetesProviderNotFound)
+			})
+		})
+
+		When("listing the kind mappings returns an error", func() {
+			BeforeEach(func() {
+				fakeSQLClient.ListKindMappingsByAccountNamesReturns(nil, errors.New("error listing kind mappings"))
+			})
+
+			It("returns status internal server error", func() {
+				Expect(res.StatusCode).To(Equal(http.StatusInternalServerError))
+				validateResponse(payloadErrorListingKindMappings)
+			})
+		})
+
+		When("the provider maps no kinds", func() {
+			BeforeEach(func() {
+				fakeSQLClient.ListKindMappingsByAccountNamesReturns(map[string]map[string]string{}, nil)
+			})
+
+			It("returns an empty kind map", func() {
+				Expect(res.StatusCode).To(Equal(http.StatusOK))
+				validateResponse(`{}`)
+			})
+		})
+
+		When("it succeeds", func() {
+			It("returns the kind map", func() {
+				Expect(res.StatusCode).To(Equal(http.StatusOK))
+				Expect(fakeSQLClient.ListKindMappingsByAccountNamesArgsForCall(0)).To(Equal([]string{"test-name"}))
+				validateResponse(payloadKindMap)
+			})
+		})
+	})
+
+	Describe("#SetKubernetesProviderKindMapping", func() {
+		BeforeEach(func() {
+			setup()
+			uri = svr.URL + "/v1/kubernetes/providers/test-name/kindMap/rollout"
+			body.Write([]byte(`{"category": "serverGroups"}`))
+			createRequest(http.MethodPut)
+		})
+
+		AfterEach(func() {
+			teardown()
+		})
+
+		JustBeforeEach(func() {
+			doRequest()
+		})
+
+		When("the request body is bad data", func() {
+			BeforeEach(func() {
+				body = &bytes.Buffer{}
+				body.Write([]byte("dasdf[]dsf;;"))
+				createRequest(http.MethodPut)
+			})
+
+			It("returns status bad request", func() {
+				Expect(res.StatusCode).To(Equal(http.StatusBadRequest))
+				validateResponse(payloadBadRequest)
+			})
+		})
+
+		When("the category is unknown", func() {
+			BeforeEach(func() {
+				body = &bytes.Buffer{}
+				body.Write([]byte(`{"category": "rollouts"}`))
+				createRequest(http.MethodPut)
+			})
+
+			It("returns status bad request", func() {
+				Expect(res.StatusCode).To(Equal(http.StatusBadRequest))
+				validateResponse(payloadUnknownKindCategory)
+				Expect(fakeSQLClient.SetKindMappingCallCount()).To(BeZero())
+			})
+		})
+
+		When("the provider is not found", func() {
+			BeforeEach(func() {
+				fakeSQLClient.GetKubernetesProviderReturns(kubernetes.Provider{}, gorm.ErrRecordNotFound)
+			})
+
+			It("returns status not found", func() {
+				Expect(res.StatusCode).To(Equal(http.StatusNotFound))
+				validateResponse(payloadKubernetesProviderNotFound)
+			})
+		})
+
+		When("setting the kind mapping returns an error", func() {
+			BeforeEach(func() {
+				fakeSQLClient.SetKindMappingReturns(errors.New("error setting kind mapping"))
+			})
+
+			It("returns status internal server error", func() {
+				Expect(res.StatusCode).To(Equal(http.StatusInternalServerError))
+				validateResponse(payloadErrorSettingKindMapping)
+			})
+		})
+
+		When("it succeeds", func() {
+			It("sets the kind mapping", func() {
+				Expect(res.StatusCode).To(Equal(http.StatusOK))
+				km := fakeSQLClient.SetKindMappingArgsForCall(0)
+				Expect(km.ID).ToNot(BeEmpty())
+				Expect(km.AccountName).To(Equal("test-name"))
+				Expect(km.Kind).To(Equal("rollout"))
+				Expect(km.Category).To(Equal("serverGroups"))
+				validateResponse(payloadKindMapping)
+			})
+		})
+	})
+
+	Describe("#DeleteKubernetesProviderKindMapping", func() {
+		BeforeEach(func() {
+			setup()
+			uri = svr.URL + "/v1/kubernetes/providers/test-name/kindMap/rollout"
+			createRequest(http.MethodDelete)
+		})
+
+		AfterEach(func() {
+			teardown()
+		})
+
+		JustBeforeEach(func() {
+			doRequest()
+		})
+
+		When("the provider is not found", func() {
+			BeforeEach(func() {
+				fakeSQLClient.GetKubernetesProviderReturns(kubernetes.Provider{}, gorm.ErrRecordNotFound)
+			})
+
+			It("returns status not found", func() {
+				Expect(res.StatusCode).To(Equal(http.StatusNotFound))
+				validateResponse(payloadKubernetesProviderNotFound)
+			})
+		})
+
+		When("deleting the kind mapping returns an error", func() {
+			BeforeEach(func() {
+				fakeSQLClient.DeleteKindMappingReturns(errors.New("error deleting kind mapping"))
+			})
+
+			It("returns status internal server error", func() {
+				Expect(res.StatusCode).To(Equal(http.StatusInternalServerError))
+				validateResponse(payloadErrorDeletingKindMapping)
+			})
+		})
+
+		When("it succeeds", func() {
+			It("returns status no content", func() {
+				Expect(res.StatusCode).To(Equal(http.StatusNoContent))
+				account, kind := fakeSQLClient.DeleteKindMappingArgsForCall(0)
+				Expect(account).To(Equal("test-name"))
+				Expect(kind).To(Equal("rollout"))
+			})
+		})
+	})
+})
@@ -18,6 +18,22 @@ const payloadRequestKubernetesProviders = `{
 						}
           }`
 
+const payloadUnknownWriteMode = `{
+            "error": "unknown write mode \"dryrun\", must be enforced or dryRun"
+          }`
+
+const payloadInvalidTokenServiceAccount = `{
+            "error": "token service account \"deployer\" must be namespace/name"
+          }`
+
+const payloadInvalidClusters = `{
+            "error": "cluster name \"primary\" is already used"
+          }`
+
+const payloadInvalidTransport = `{
+            "error": "transport idleConnTimeout \"30\" must be a duration, such as 30s"
+          }`
+
 const payloadConflictRequest = `{
             "error": "provider already exists"
           }`
@@ -59,3 +75,120 @@ const payloadKubernetesProviderGetGenericError = `{
 const payloadKubernetesProviderDeleteGenericError = `{
             "error": "error deleting provider"
           }`
+
+const payloadRequestMaintenance = `{
+            "message": "upgrading to 1.19"
+          }`
+
+const payloadKubernetesProviderMaintenance = `{
+            "name": "test-name",
+            "maintenance": true,
+            "maintenanceMessage": "upgrading to 1.19"
+          }`
+
+const payloadKubernetesProviderMaintenanceError = `{
+            "error": "error setting maintenance"
+          }`
+
+const payloadInvalidClusterCredential = `{
+            "error": "providers with a cluster credential must not set a host, caData, bearerToken, tokenServiceAccount or clusters"
+          }`
+
+const payloadClusterCredentialNotFoundForProvider = `{
+            "error": "cluster credential test-cluster not found"
+          }`
+
+const payloadRequestKubernetesClusterCredential = `{
+            "name": "test-cluster",
+            "host": "test-host",
+            "caData": "test-ca-data",
+            "bearerToken": "test-token"
+          }`
+
+const payloadKubernetesClusterCredential = `{
+            "name": "test-cluster",
+            "host": "test-host",
+            "caData": "test-ca-data",
+            "bearerToken": "test-token"
+          }`
+
+const payloadClusterCredentialConflict = `{
+            "error": "cluster credential already exists"
+          }`
+
+const payloadErrorCreatingClusterCredential = `{
+            "error": "error creating cluster credential"
+          }`
+
+const payloadClusterCredentialNotFound = `{
+            "error": "cluster credential not found"
+          }`
+
+const payloadErrorUpdatingClusterCredential = `{
+            "error": "error updating cluster credential"
+          }`
+
+const payloadClusterCredentialInUse = `{
+            "error": "cluster credential test-cluster is used by accounts team-a, team-b"
+          }`
+
+const payloadUnknownKindCategory = `{
+            "error": "unknown category \"rollouts\" of kind rollout, must be one of configs, instances, loadBalancers, securityGroups, serverGroupManagers, serverGroups, unclassified"
+          }`
+
+const payloadKindMap = `{
+            "rollout": "serverGroups"
+          }`
+
+const payloadKindMapping = `{
+            "accountName": "test-name",
+            "kind": "rollout",
+            "category": "serverGroups"
+          }`
+
+const payloadErrorListingKindMappings = `{
+            "error": "error listing kind mappings"
+          }`
+
+const payloadErrorSettingKindMapping = `{
+            "error": "error setting kind mapping"
+          }`
+
+const payloadErrorDeletingKindMapping = `{
+            "error": "error deleting kind mapping"
+          }`
+
+const payloadKubeconfigWithCredentials = `{
+            "error": "providers with a kubeconfig must not set a host, caData, bearerToken or clusterCredential"
+          }`
+
+const payloadInvalidTokenProvider = `{
+            "error": "aws token providers must set a clusterName and region"
+          }`
+
+const payloadFakeWithHost = `{
+            "error": "fake providers must not set a host, credentials, clusters or a transport"
+          }`
+
+const payloadErrorCreatingResource = `{
+            "error": "error creating resource"
+          }`
+
+const payloadKubeconfig = `apiVersion: v1
+kind: Config
+current-context: test-context
+clusters:
+- name: test-cluster
+  cluster:
+    server: https://test-host
+contexts:
+- name: test-context
+  context:
+    cluster: test-cluster
+    user: test-user
+users:
+- name: test-user
+  user:
+    client-certificate-data: dGVzdC1jZXJ0
+    client-key-data: dGVzdC1rZXk=
+`
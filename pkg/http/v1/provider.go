@@ -1,7 +1,10 @@
 package v1
 
 import (
+	"fmt"
+	"io"
 	"net/http"
+	"sort"
 
 	clouddriver "github.com/billiford/go-clouddriver/pkg"
 	"github.com/billiford/go-clouddriver/pkg/kubernetes"
@@ -13,6 +16,7 @@ import (
 
 func CreateKubernetesProvider(c *gin.Context) {
 	sc := sql.Instance(c)
+	pc := kubernetes.PermissionsCacheInstance(c)
 	p := kubernetes.Provider{}
 
 	err := c.ShouldBindJSON(&p)
@@ -21,6 +25,103 @@ func CreateKubernetesProvider(c *gin.Context) {
 		return
 	}
 
+	if !p.ValidWriteMode() {
+		c.JSON(http.StatusBadRequest, gin.H{"error": fmt.Sprintf("unknown write mode %q, must be %s or %s",
+			p.WriteMode, kubernetes.WriteModeEnforced, kubernetes.WriteModeDryRun)})
+		return
+	}
+
+	if !p.ValidTokenServiceAccount() {
+		c.JSON(http.StatusBadRequest, gin.H{"error": fmt.Sprintf("token service account %q must be namespace/name",
+			p.TokenServiceAccount)})
+		return
+	}
+
+	err = p.ValidateClusters()
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	err = p.ValidateClusterCredential()
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	err = p.ValidateKubeconfig()
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	err = p.ValidateTokenProvider()
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	err = p.ValidateFake()
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	if p.Fake != nil {
+		p.Host = kubernetes.FakeHost(p.Name)
+	}
+
+	// The host of a provider with a kubeconfig is the server of its context,
+	// so what is kept by host, such as the discovery cache, is kept for it.
+	if p.HasKubeconfig() {
+		config, err := p.KubeconfigConfig()
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+
+		p.Host = config.Host
+	}
+
+	err = p.ValidateCaching()
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	err = p.ValidatePermissions()
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	err = p.ValidateKindMap()
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	if p.Transport != nil {
+		err = p.Transport.Validate()
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+	}
+
+	if p.ClusterCredential != "" {
+		_, err = sc.GetKubernetesClusterCredential(p.ClusterCredential)
+		if err != nil {
+			if err == gorm.ErrRecordNotFound {
+				c.JSON(http.StatusBadRequest, gin.H{"error": fmt.Sprintf("cluster credential %s not found", p.ClusterCredential)})
+				return
+			}
+
+			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			return
+		}
+	}
+
 	_, err = sc.GetKubernetesProvider(p.Name)
 	if err == nil {
 		c.JSON(http.StatusConflict, gin.H{"error": "provider already exists"})
@@ -59,11 +160,81 @@ func CreateKubernetesProvider(c *gin.Context) {
 		}
 	}
 
+	for _, group := range p.Permissions.Execute {
+		ep := clouddriver.ExecutePermission{
+			ID:           uuid.New().String(),
+			AccountName:  p.Name,
+			ExecuteGroup: group,
+		}
+		err = sc.CreateExecutePermission(ep)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			return
+		}
+	}
+
+	for namespace, np := range p.Permissions.Namespaces {
+		for _, authorization := range []string{"READ", "WRITE", "EXECUTE"} {
+			for _, group := range np.Groups(authorization) {
+				nsp := clouddriver.NamespacePermission{
+					ID:            uuid.New().String(),
+					AccountName:   p.Name,
+					Namespace:     namespace,
+					Authorization: authorization,
+					GroupName:     group,
+				}
+				err = sc.CreateNamespacePermission(nsp)
+				if err != nil {
+					c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+					return
+				}
+			}
+		}
+	}
+
+	kinds := []string{}
+	for kind := range p.KindMap {
+		kinds = append(kinds, kind)
+	}
+
+	sort.Strings(kinds)
+
+	for _, kind := range kinds {
+		km := kubernetes.KindMapping{
+			ID:          uuid.New().String(),
+			AccountName: p.Name,
+			Kind:        kind,
+			Category:    p.KindMap[kind],
+		}
+		err = sc.SetKindMapping(km)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			return
+		}
+	}
+
+	// The resources the cluster of a fake is seeded with are recorded as if
+	// they were deployed, so its applications and clusters are listed.
+	if p.Fake != nil {
+		for _, kr := range p.Fake.Resources(p.Name) {
+			kr.ID = uuid.New().String()
+
+			err = sc.CreateKubernetesResource(kr)
+			if err != nil {
+				c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+				return
+			}
+		}
+	}
+
+	pc.Delete(p.Name)
+
 	c.JSON(http.StatusCreated, p)
 }
 
 func DeleteKubernetesProvider(c *gin.Context) {
 	sc := sql.Instance(c)
+	pc := kubernetes.PermissionsCacheInstance(c)
 	name := c.Param("name")
 
 	_, err := sc.GetKubernetesProvider(name)
@@ -83,5 +254,66 @@ func DeleteKubernetesProvider(c *gin.Context) {
 		return
 	}
 
+	pc.Delete(name)
+
 	c.JSON(http.StatusNoContent, nil)
 }
+
+// MaintenanceRequest puts a provider in maintenance.
+type MaintenanceRequest struct {
+	Message string `json:"message"`
+}
+
+// StartKubernetesProviderMaintenance puts a provider in maintenance, rejecting
+// operations against it with the message given until maintenance ends.
+func StartKubernetesProviderMaintenance(c *gin.Context) {
+	setKubernetesProviderMaintenance(c, true)
+}
+
+// EndKubernetesProviderMaintenance takes a provider out of maintenance.
+func EndKubernetesProviderMaintenance(c *gin.Context) {
+	setKubernetesProviderMaintenance(c, false)
+}
+
+func setKubernetesProviderMaintenance(c *gin.Context, maintenance bool) {
+	sc := sql.Instance(c)
+	name := c.Param("name")
+	mr := MaintenanceRequest{}
+
+	if maintenance {
+		// The message is optional, so an empty body is not an error.
+		err := c.ShouldBindJSON(&mr)
+		if err != nil && err != io.EOF {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+	}
+
+	_, err := sc.GetKubernetesProvider(name)
+	if err != nil {
+		if err == gorm.ErrRecordNotFound {
+			c.JSON(http.StatusNotFound, gin.H{"error": "provider not found"})
+			return
+		}
+
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	err = sc.SetKubernetesProviderMaintenance(name, maintenance, mr.Message)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	if !maintenance {
+		c.JSON(http.StatusNoContent, nil)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"name":               name,
+		"maintenance":        true,
+		"maintenanceMessage": mr.Message,
+	})
+}
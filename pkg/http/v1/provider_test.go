@@ -2,6 +2,7 @@ package v1_test
 
 import (
 	"bytes"
+	"encoding/json"
 	"errors"
 	"net/http"
 
@@ -44,6 +45,247 @@ var _ = Describe("Provider", func() {
 			})
 		})
 
+		When("the write mode is unknown", func() {
+			BeforeEach(func() {
+				body = &bytes.Buffer{}
+				body.Write([]byte(`{"name": "test-name", "writeMode": "dryrun"}`))
+				createRequest(http.MethodPost)
+			})
+
+			It("returns status bad request", func() {
+				Expect(res.StatusCode).To(Equal(http.StatusBadRequest))
+				validateResponse(payloadUnknownWriteMode)
+			})
+		})
+
+		When("the token service account is invalid", func() {
+			BeforeEach(func() {
+				body = &bytes.Buffer{}
+				body.Write([]byte(`{"name": "test-name", "tokenServiceAccount": "deployer"}`))
+				createRequest(http.MethodPost)
+			})
+
+			It("returns status bad request", func() {
+				Expect(res.StatusCode).To(Equal(http.StatusBadRequest))
+				validateResponse(payloadInvalidTokenServiceAccount)
+			})
+		})
+
+		When("the clusters are invalid", func() {
+			BeforeEach(func() {
+				body = &bytes.Buffer{}
+				body.Write([]byte(`{"name": "test-name", "clusters": [{"name": "primary", "host": "https://standby"}]}`))
+				createRequest(http.MethodPost)
+			})
+
+			It("returns status bad request", func() {
+				Expect(res.StatusCode).To(Equal(http.StatusBadRequest))
+				validateResponse(payloadInvalidClusters)
+			})
+		})
+
+		When("the provider has a cluster credential and a host", func() {
+			BeforeEach(func() {
+				body = &bytes.Buffer{}
+				body.Write([]byte(`{"name": "test-name", "host": "test-host", "clusterCredential": "test-cluster"}`))
+				createRequest(http.MethodPost)
+			})
+
+			It("returns status bad request", func() {
+				Expect(res.StatusCode).To(Equal(http.StatusBadRequest))
+				validateResponse(payloadInvalidClusterCredential)
+			})
+		})
+
+		When("the cache threads are negative", func() {
+			BeforeEach(func() {
+				body = &bytes.Buffer{}
+				body.Write([]byte(`{"name": "test-name", "cacheThreads": -1}`))
+				createRequest(http.MethodPost)
+			})
+
+			It("returns status bad request", func() {
+				Expect(res.StatusCode).To(Equal(http.StatusBadRequest))
+				validateResponse(`{"error": "cacheThreads must not be negative"}`)
+			})
+		})
+
+		When("the permissions of a namespace have no groups", func() {
+			BeforeEach(func() {
+				body = &bytes.Buffer{}
+				body.Write([]byte(`{"name": "test-name", "permissions": {"namespaces": {"team-a": {}}}}`))
+				createRequest(http.MethodPost)
+			})
+
+			It("returns status bad request", func() {
+				Expect(res.StatusCode).To(Equal(http.StatusBadRequest))
+				validateResponse(`{"error": "namespace permissions of team-a must have groups"}`)
+			})
+		})
+
+		When("the transport is invalid", func() {
+			BeforeEach(func() {
+				body = &bytes.Buffer{}
+				body.Write([]byte(`{"name": "test-name", "transport": {"idleConnTimeout": "30"}}`))
+				createRequest(http.MethodPost)
+			})
+
+			It("returns status bad request", func() {
+				Expect(res.StatusCode).To(Equal(http.StatusBadRequest))
+				validateResponse(payloadInvalidTransport)
+			})
+		})
+
+		When("the cluster credential does not exist", func() {
+			BeforeEach(func() {
+				body = &bytes.Buffer{}
+				body.Write([]byte(`{"name": "test-name", "clusterCredential": "test-cluster", "namespaces": ["team-a"]}`))
+				createRequest(http.MethodPost)
+				fakeSQLClient.GetKubernetesClusterCredentialReturns(kubernetes.ClusterCredential{}, gorm.ErrRecordNotFound)
+			})
+
+			It("returns status bad request", func() {
+				Expect(res.StatusCode).To(Equal(http.StatusBadRequest))
+				validateResponse(payloadClusterCredentialNotFoundForProvider)
+			})
+		})
+
+		When("the provider is scoped to namespaces of a cluster credential", func() {
+			BeforeEach(func() {
+				body = &bytes.Buffer{}
+				body.Write([]byte(`{"name": "test-name", "clusterCredential": "test-cluster", "namespaces": ["team-a"]}`))
+				createRequest(http.MethodPost)
+			})
+
+			It("creates the provider", func() {
+				Expect(res.StatusCode).To(Equal(http.StatusCreated))
+				Expect(fakeSQLClient.GetKubernetesClusterCredentialArgsForCall(0)).To(Equal("test-cluster"))
+				p := fakeSQLClient.CreateKubernetesProviderArgsForCall(0)
+				Expect(p.ClusterCredential).To(Equal("test-cluster"))
+				Expect(p.Namespaces).To(Equal(kubernetes.ProviderNamespaces{"team-a"}))
+			})
+		})
+
+		When("the provider has a kubeconfig and a host", func() {
+			BeforeEach(func() {
+				body = &bytes.Buffer{}
+				body.Write([]byte(`{"name": "test-name", "host": "https://test-host", "kubeconfigFile": "/opt/spinnaker/kubeconfig"}`))
+				createRequest(http.MethodPost)
+			})
+
+			It("returns status bad request", func() {
+				Expect(res.StatusCode).To(Equal(http.StatusBadRequest))
+				validateResponse(payloadKubeconfigWithCredentials)
+			})
+		})
+
+		When("the kubeconfig cannot be loaded", func() {
+			BeforeEach(func() {
+				body = &bytes.Buffer{}
+				body.Write([]byte(`{"name": "test-name", "kubeconfigFile": "/does/not/exist"}`))
+				createRequest(http.MethodPost)
+			})
+
+			It("returns status bad request", func() {
+				Expect(res.StatusCode).To(Equal(http.StatusBadRequest))
+				Expect(fakeSQLClient.CreateKubernetesProviderCallCount()).To(BeZero())
+			})
+		})
+
+		When("the provider has a kubeconfig", func() {
+			BeforeEach(func() {
+				body = &bytes.Buffer{}
+				kubeconfig, _ := json.Marshal(payloadKubeconfig)
+				body.Write([]byte(`{"name": "test-name", "kubeconfigContents": ` + string(kubeconfig) + `}`))
+				createRequest(http.MethodPost)
+			})
+
+			It("creates the provider with the host of the kubeconfig", func() {
+				Expect(res.StatusCode).To(Equal(http.StatusCreated))
+				p := fakeSQLClient.CreateKubernetesProviderArgsForCall(0)
+				Expect(p.Host).To(Equal("https://test-host"))
+				Expect(p.KubeconfigContents).To(Equal(payloadKubeconfig))
+			})
+		})
+
+		When("the token provider is invalid", func() {
+			BeforeEach(func() {
+				body = &bytes.Buffer{}
+				body.Write([]byte(`{"name": "test-name", "host": "https://test-host", "tokenProvider": {"type": "aws", "region": "us-east-1"}}`))
+				createRequest(http.MethodPost)
+			})
+
+			It("returns status bad request", func() {
+				Expect(res.StatusCode).To(Equal(http.StatusBadRequest))
+				validateResponse(payloadInvalidTokenProvider)
+			})
+		})
+
+		When("the provider has a token provider", func() {
+			BeforeEach(func() {
+				body = &bytes.Buffer{}
+				body.Write([]byte(`{"name": "test-name", "host": "https://test-host", "tokenProvider": {"type": "aws", "clusterName": "test-cluster", "region": "us-east-1"}}`))
+				createRequest(http.MethodPost)
+			})
+
+			It("creates the provider", func() {
+				Expect(res.StatusCode).To(Equal(http.StatusCreated))
+				p := fakeSQLClient.CreateKubernetesProviderArgsForCall(0)
+				Expect(p.TokenProvider).To(Equal(&kubernetes.ProviderTokenProvider{
+					Type:        kubernetes.TokenProviderAWS,
+					ClusterName: "test-cluster",
+					Region:      "us-east-1",
+				}))
+			})
+		})
+
+		When("a fake provider has a host", func() {
+			BeforeEach(func() {
+				body = &bytes.Buffer{}
+				body.Write([]byte(`{"name": "test-name", "host": "https://test-host", "fake": {}}`))
+				createRequest(http.MethodPost)
+			})
+
+			It("returns status bad request", func() {
+				Expect(res.StatusCode).To(Equal(http.StatusBadRequest))
+				validateResponse(payloadFakeWithHost)
+			})
+		})
+
+		When("the provider is fake", func() {
+			BeforeEach(func() {
+				body = &bytes.Buffer{}
+				body.Write([]byte(`{"name": "test-name", "fake": {"applications": ["app-a"], "latency": "100ms"}}`))
+				createRequest(http.MethodPost)
+			})
+
+			It("creates the provider with the resources it is seeded with", func() {
+				Expect(res.StatusCode).To(Equal(http.StatusCreated))
+				p := fakeSQLClient.CreateKubernetesProviderArgsForCall(0)
+				Expect(p.Host).To(Equal("https://test-name.fake.invalid"))
+				Expect(p.Fake.Latency).To(Equal("100ms"))
+				Expect(fakeSQLClient.CreateKubernetesResourceCallCount()).To(Equal(2))
+				kr := fakeSQLClient.CreateKubernetesResourceArgsForCall(0)
+				Expect(kr.AccountName).To(Equal("test-name"))
+				Expect(kr.SpinnakerApp).To(Equal("app-a"))
+				Expect(kr.ID).ToNot(BeEmpty())
+			})
+		})
+
+		When("creating the seeded resources fails", func() {
+			BeforeEach(func() {
+				fakeSQLClient.CreateKubernetesResourceReturns(errors.New("error creating resource"))
+				body = &bytes.Buffer{}
+				body.Write([]byte(`{"name": "test-name", "fake": {}}`))
+				createRequest(http.MethodPost)
+			})
+
+			It("returns status internal server error", func() {
+				Expect(res.StatusCode).To(Equal(http.StatusInternalServerError))
+				validateResponse(payloadErrorCreatingResource)
+			})
+		})
+
 		When("the provider already exists", func() {
 			BeforeEach(func() {
 				fakeSQLClient.GetKubernetesProviderReturns(kubernetes.Provider{}, nil)
@@ -88,9 +330,94 @@ var _ = Describe("Provider", func() {
 			})
 		})
 
+		When("the provider has execute and namespace groups", func() {
+			BeforeEach(func() {
+				body = &bytes.Buffer{}
+				body.Write([]byte(`{"name": "test-name", "permissions": {"read": ["viewers"], "execute": ["deployers"],` +
+					`"namespaces": {"team-a": {"execute": ["team-a"]}}}}`))
+				createRequest(http.MethodPost)
+			})
+
+			It("creates the groups", func() {
+				Expect(res.StatusCode).To(Equal(http.StatusCreated))
+				Expect(fakeSQLClient.CreateExecutePermissionCallCount()).To(Equal(1))
+				Expect(fakeSQLClient.CreateExecutePermissionArgsForCall(0).ExecuteGroup).To(Equal("deployers"))
+				Expect(fakeSQLClient.CreateNamespacePermissionCallCount()).To(Equal(1))
+				np := fakeSQLClient.CreateNamespacePermissionArgsForCall(0)
+				Expect(np.AccountName).To(Equal("test-name"))
+				Expect(np.Namespace).To(Equal("team-a"))
+				Expect(np.Authorization).To(Equal("EXECUTE"))
+				Expect(np.GroupName).To(Equal("team-a"))
+			})
+		})
+
+		When("creating a namespace group returns an error", func() {
+			BeforeEach(func() {
+				body = &bytes.Buffer{}
+				body.Write([]byte(`{"name": "test-name", "permissions": {"namespaces": {"team-a": {"write": ["team-a"]}}}}`))
+				createRequest(http.MethodPost)
+				fakeSQLClient.CreateNamespacePermissionReturns(errors.New("error creating namespace permission"))
+			})
+
+			It("returns status internal server error", func() {
+				Expect(res.StatusCode).To(Equal(http.StatusInternalServerError))
+				validateResponse(`{"error": "error creating namespace permission"}`)
+			})
+		})
+
+		When("a kind is mapped to an unknown category", func() {
+			BeforeEach(func() {
+				body = &bytes.Buffer{}
+				body.Write([]byte(`{"name": "test-name", "kindMap": {"rollout": "rollouts"}}`))
+				createRequest(http.MethodPost)
+			})
+
+			It("returns status bad request", func() {
+				Expect(res.StatusCode).To(Equal(http.StatusBadRequest))
+				validateResponse(payloadUnknownKindCategory)
+			})
+		})
+
+		When("the provider has a kind map", func() {
+			BeforeEach(func() {
+				body = &bytes.Buffer{}
+				body.Write([]byte(`{"name": "test-name", "kindMap": {"rollout": "serverGroups", "analysisRun": "unclassified"}}`))
+				createRequest(http.MethodPost)
+			})
+
+			It("creates the kind mappings", func() {
+				Expect(res.StatusCode).To(Equal(http.StatusCreated))
+				Expect(fakeSQLClient.SetKindMappingCallCount()).To(Equal(2))
+				km := fakeSQLClient.SetKindMappingArgsForCall(0)
+				Expect(km.AccountName).To(Equal("test-name"))
+				Expect(km.Kind).To(Equal("analysisRun"))
+				Expect(km.Category).To(Equal("unclassified"))
+				km = fakeSQLClient.SetKindMappingArgsForCall(1)
+				Expect(km.Kind).To(Equal("rollout"))
+				Expect(km.Category).To(Equal("serverGroups"))
+			})
+		})
+
+		When("creating a kind mapping returns an error", func() {
+			BeforeEach(func() {
+				body = &bytes.Buffer{}
+				body.Write([]byte(`{"name": "test-name", "kindMap": {"rollout": "serverGroups"}}`))
+				createRequest(http.MethodPost)
+				fakeSQLClient.SetKindMappingReturns(errors.New("error setting kind mapping"))
+			})
+
+			It("returns status internal server error", func() {
+				Expect(res.StatusCode).To(Equal(http.StatusInternalServerError))
+				validateResponse(payloadErrorSettingKindMapping)
+			})
+		})
+
 		When("it succeeds", func() {
 			It("returns status created", func() {
 				Expect(res.StatusCode).To(Equal(http.StatusCreated))
+				Expect(fakeKubePermissionsCache.DeleteCallCount()).To(Equal(1))
+				Expect(fakeSQLClient.CreateExecutePermissionCallCount()).To(BeZero())
+				Expect(fakeSQLClient.SetKindMappingCallCount()).To(BeZero())
 				validateResponse(payloadKubernetesProviderCreated)
 			})
 		})
@@ -147,6 +474,146 @@ var _ = Describe("Provider", func() {
 		When("it succeeds", func() {
 			It("returns status no content", func() {
 				Expect(res.StatusCode).To(Equal(http.StatusNoContent))
+				Expect(fakeKubePermissionsCache.DeleteCallCount()).To(Equal(1))
+				Expect(fakeKubePermissionsCache.DeleteArgsForCall(0)).To(Equal("test-name"))
+			})
+		})
+	})
+
+	Describe("#StartKubernetesProviderMaintenance", func() {
+		BeforeEach(func() {
+			setup()
+			uri = svr.URL + "/v1/kubernetes/providers/test-name/maintenance"
+			body.Write([]byte(payloadRequestMaintenance))
+			createRequest(http.MethodPut)
+		})
+
+		AfterEach(func() {
+			teardown()
+		})
+
+		JustBeforeEach(func() {
+			doRequest()
+		})
+
+		When("the request body is bad data", func() {
+			BeforeEach(func() {
+				body = &bytes.Buffer{}
+				body.Write([]byte("{"))
+				createRequest(http.MethodPut)
+			})
+
+			It("returns status bad request", func() {
+				Expect(res.StatusCode).To(Equal(http.StatusBadRequest))
+				Expect(fakeSQLClient.SetKubernetesProviderMaintenanceCallCount()).To(BeZero())
+			})
+		})
+
+		When("the record is not found", func() {
+			BeforeEach(func() {
+				fakeSQLClient.GetKubernetesProviderReturns(kubernetes.Provider{}, gorm.ErrRecordNotFound)
+			})
+
+			It("returns an error", func() {
+				Expect(res.StatusCode).To(Equal(http.StatusNotFound))
+				validateResponse(payloadKubernetesProviderNotFound)
+			})
+		})
+
+		When("getting the provider returns a generic error", func() {
+			BeforeEach(func() {
+				fakeSQLClient.GetKubernetesProviderReturns(kubernetes.Provider{}, errors.New("error getting provider"))
+			})
+
+			It("returns an error", func() {
+				Expect(res.StatusCode).To(Equal(http.StatusInternalServerError))
+				validateResponse(payloadKubernetesProviderGetGenericError)
+			})
+		})
+
+		When("setting maintenance returns an error", func() {
+			BeforeEach(func() {
+				fakeSQLClient.SetKubernetesProviderMaintenanceReturns(errors.New("error setting maintenance"))
+			})
+
+			It("returns an error", func() {
+				Expect(res.StatusCode).To(Equal(http.StatusInternalServerError))
+				validateResponse(payloadKubernetesProviderMaintenanceError)
+			})
+		})
+
+		When("no message is given", func() {
+			BeforeEach(func() {
+				body = &bytes.Buffer{}
+				createRequest(http.MethodPut)
+			})
+
+			It("starts maintenance without a message", func() {
+				Expect(res.StatusCode).To(Equal(http.StatusOK))
+				name, maintenance, message := fakeSQLClient.SetKubernetesProviderMaintenanceArgsForCall(0)
+				Expect(name).To(Equal("test-name"))
+				Expect(maintenance).To(BeTrue())
+				Expect(message).To(BeEmpty())
+			})
+		})
+
+		When("it succeeds", func() {
+			It("returns status ok", func() {
+				Expect(res.StatusCode).To(Equal(http.StatusOK))
+				validateResponse(payloadKubernetesProviderMaintenance)
+				Expect(fakeSQLClient.SetKubernetesProviderMaintenanceCallCount()).To(Equal(1))
+				name, maintenance, message := fakeSQLClient.SetKubernetesProviderMaintenanceArgsForCall(0)
+				Expect(name).To(Equal("test-name"))
+				Expect(maintenance).To(BeTrue())
+				Expect(message).To(Equal("upgrading to 1.19"))
+			})
+		})
+	})
+
+	Describe("#EndKubernetesProviderMaintenance", func() {
+		BeforeEach(func() {
+			setup()
+			uri = svr.URL + "/v1/kubernetes/providers/test-name/maintenance"
+			createRequest(http.MethodDelete)
+		})
+
+		AfterEach(func() {
+			teardown()
+		})
+
+		JustBeforeEach(func() {
+			doRequest()
+		})
+
+		When("the record is not found", func() {
+			BeforeEach(func() {
+				fakeSQLClient.GetKubernetesProviderReturns(kubernetes.Provider{}, gorm.ErrRecordNotFound)
+			})
+
+			It("returns an error", func() {
+				Expect(res.StatusCode).To(Equal(http.StatusNotFound))
+				validateResponse(payloadKubernetesProviderNotFound)
+			})
+		})
+
+		When("setting maintenance returns an error", func() {
+			BeforeEach(func() {
+				fakeSQLClient.SetKubernetesProviderMaintenanceReturns(errors.New("error setting maintenance"))
+			})
+
+			It("returns an error", func() {
+				Expect(res.StatusCode).To(Equal(http.StatusInternalServerError))
+				validateResponse(payloadKubernetesProviderMaintenanceError)
+			})
+		})
+
+		When("it succeeds", func() {
+			It("returns status no content", func() {
+				Expect(res.StatusCode).To(Equal(http.StatusNoContent))
+				name, maintenance, message := fakeSQLClient.SetKubernetesProviderMaintenanceArgsForCall(0)
+				Expect(name).To(Equal("test-name"))
+				Expect(maintenance).To(BeFalse())
+				Expect(message).To(BeEmpty())
 			})
 		})
 	})
@@ -7,6 +7,7 @@ import (
 	"net/http"
 	"net/http/httptest"
 
+	"github.com/billiford/go-clouddriver/pkg/kubernetes/kubernetesfakes"
 	"github.com/billiford/go-clouddriver/pkg/server"
 	"github.com/billiford/go-clouddriver/pkg/sql/sqlfakes"
 	"github.com/gin-gonic/gin"
@@ -16,18 +17,23 @@ import (
 )
 
 var (
-	err           error
-	svr           *httptest.Server
-	uri           string
-	req           *http.Request
-	body          *bytes.Buffer
-	res           *http.Response
-	fakeSQLClient *sqlfakes.FakeClient
+	err                      error
+	svr                      *httptest.Server
+	uri                      string
+	req                      *http.Request
+	body                     *bytes.Buffer
+	res                      *http.Response
+	fakeSQLClient            *sqlfakes.FakeClient
+	fakeKubePermissionsCache *kubernetesfakes.FakePermissionsCache
 )
 
 func setup() {
 	// Setup fake SQL client.
 	fakeSQLClient = &sqlfakes.FakeClient{}
+	fakeSQLClient.WithContextReturns(fakeSQLClient)
+
+	// Setup fake permissions cache.
+	fakeKubePermissionsCache = &kubernetesfakes.FakePermissionsCache{}
 
 	// Disable debug logging.
 	gin.SetMode(gin.ReleaseMode)
@@ -38,7 +44,8 @@ func setup() {
 	r.Use(gin.Recovery())
 
 	c := &server.Config{
-		SQLClient: fakeSQLClient,
+		SQLClient:            fakeSQLClient,
+		KubePermissionsCache: fakeKubePermissionsCache,
 	}
 	// Create server.
 	server.Setup(r, c)
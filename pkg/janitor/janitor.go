@@ -0,0 +1,128 @@
+package janitor
+
+import (
+	"context"
+	"log"
+	"time"
+
+	"github.com/billiford/go-clouddriver/pkg/sql"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+const (
+	defaultInterval = time.Hour
+	// Compressed values are kept for this long after they were last stored,
+	// so values are not deleted before the rows referencing them are stored.
+	unusedBlobAge = time.Hour
+)
+
+var (
+	rowsDeleted = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "clouddriver_janitor_rows_deleted_total",
+		Help: "Number of rows deleted by the janitor by retention policy.",
+	}, []string{"policy"})
+)
+
+func init() {
+	prometheus.MustRegister(rowsDeleted)
+}
+
+// Config defines the retention policies of the janitor.
+type Config struct {
+	// How often to clean up. Defaults to an hour.
+	Interval time.Duration
+	// How long to keep the task history of resources, the reports of
+	// migrations, the deploys of deploy stats and the events of tasks. The
+	// newest record of each resource is always kept. Zero keeps history
+	// forever.
+	TaskHistoryRetention time.Duration
+	// How long to keep the payloads of failed tasks for replay. Zero keeps
+	// them forever.
+	FailedOperationRetention time.Duration
+	// How long to keep snapshots of cluster and server group reads. Zero
+	// keeps them forever.
+	CacheSnapshotRetention time.Duration
+	// Delete resources and permissions of accounts that no longer exist.
+	DeleteOrphans bool
+}
+
+// Enabled returns true if any retention policy is configured.
+func (c Config) Enabled() bool {
+	return c.TaskHistoryRetention > 0 || c.FailedOperationRetention > 0 ||
+		c.CacheSnapshotRetention > 0 || c.DeleteOrphans
+}
+
+// Run cleans up on the configured interval until the context is done.
+func Run(ctx context.Context, sc sql.Client, c Config) {
+	interval := c.Interval
+	if interval <= 0 {
+		interval = defaultInterval
+	}
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		Clean(sc, c)
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// Clean runs each configured retention policy once. Errors are logged
+// and do not stop the other policies from running.
+func Clean(sc sql.Client, c Config) {
+	if c.TaskHistoryRetention > 0 {
+		run("task_history", func() (int64, error) {
+			return sc.DeleteKubernetesResourcesCreatedBefore(time.Now().Add(-c.TaskHistoryRetention))
+		})
+		run("migration_reports", func() (int64, error) {
+			return sc.DeleteMigrationReportsCreatedBefore(time.Now().Add(-c.TaskHistoryRetention))
+		})
+		run("deploys", func() (int64, error) {
+			return sc.DeleteDeploysCreatedBefore(time.Now().Add(-c.TaskHistoryRetention))
+		})
+		run("task_events", func() (int64, error) {
+			return sc.DeleteTaskEventsCreatedBefore(time.Now().Add(-c.TaskHistoryRetention))
+		})
+	}
+
+	if c.FailedOperationRetention > 0 {
+		run("failed_operations", func() (int64, error) {
+			return sc.DeleteFailedOperationsCreatedBefore(time.Now().Add(-c.FailedOperationRetention))
+		})
+	}
+
+	if c.CacheSnapshotRetention > 0 {
+		run("cache_snapshots", func() (int64, error) {
+			return sc.DeleteCacheSnapshotsCreatedBefore(time.Now().Add(-c.CacheSnapshotRetention))
+		})
+	}
+
+	if c.DeleteOrphans {
+		run("orphaned_resources", sc.DeleteOrphanedKubernetesResources)
+		run("orphaned_permissions", sc.DeleteOrphanedPermissions)
+	}
+
+	// Compressed manifests, payloads and snapshot bodies are stored once for
+	// the rows that reference them, so are deleted once none do.
+	run("compressed_blobs", func() (int64, error) {
+		return sc.DeleteCompressedBlobsUnusedSince(time.Now().Add(-unusedBlobAge))
+	})
+}
+
+func run(policy string, f func() (int64, error)) {
+	deleted, err := f()
+	if deleted > 0 {
+		rowsDeleted.WithLabelValues(policy).Add(float64(deleted))
+		log.Println("[JANITOR] deleted", deleted, "rows for policy", policy)
+	}
+
+	if err != nil {
+		log.Println("[JANITOR] error running policy", policy+":", err.Error())
+	}
+}
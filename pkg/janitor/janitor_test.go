@@ -0,0 +1,156 @@
+package janitor_test
+
+import (
+	"context"
+	"errors"
+	"io/ioutil"
+	"log"
+	"time"
+
+	. "github.com/billiford/go-clouddriver/pkg/janitor"
+	"github.com/billiford/go-clouddriver/pkg/sql/sqlfakes"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Janitor", func() {
+	var (
+		fakeSQLClient *sqlfakes.FakeClient
+		config        Config
+	)
+
+	BeforeEach(func() {
+		fakeSQLClient = &sqlfakes.FakeClient{}
+		config = Config{
+			TaskHistoryRetention:     24 * time.Hour,
+			FailedOperationRetention: 7 * 24 * time.Hour,
+			CacheSnapshotRetention:   30 * 24 * time.Hour,
+			DeleteOrphans:            true,
+		}
+		log.SetOutput(ioutil.Discard)
+	})
+
+	Describe("#Enabled", func() {
+		When("no policies are configured", func() {
+			It("returns false", func() {
+				Expect(Config{Interval: time.Minute}.Enabled()).To(BeFalse())
+			})
+		})
+
+		When("a policy is configured", func() {
+			It("returns true", func() {
+				Expect(config.Enabled()).To(BeTrue())
+			})
+		})
+	})
+
+	Describe("#Clean", func() {
+		JustBeforeEach(func() {
+			Clean(fakeSQLClient, config)
+		})
+
+		When("task history is kept forever", func() {
+			BeforeEach(func() {
+				config.TaskHistoryRetention = 0
+			})
+
+			It("does not delete task history", func() {
+				Expect(fakeSQLClient.DeleteKubernetesResourcesCreatedBeforeCallCount()).To(BeZero())
+				Expect(fakeSQLClient.DeleteMigrationReportsCreatedBeforeCallCount()).To(BeZero())
+				Expect(fakeSQLClient.DeleteDeploysCreatedBeforeCallCount()).To(BeZero())
+				Expect(fakeSQLClient.DeleteTaskEventsCreatedBeforeCallCount()).To(BeZero())
+			})
+		})
+
+		When("failed operations are kept forever", func() {
+			BeforeEach(func() {
+				config.FailedOperationRetention = 0
+			})
+
+			It("does not delete failed operations", func() {
+				Expect(fakeSQLClient.DeleteFailedOperationsCreatedBeforeCallCount()).To(BeZero())
+			})
+		})
+
+		When("cache snapshots are kept forever", func() {
+			BeforeEach(func() {
+				config.CacheSnapshotRetention = 0
+			})
+
+			It("does not delete cache snapshots", func() {
+				Expect(fakeSQLClient.DeleteCacheSnapshotsCreatedBeforeCallCount()).To(BeZero())
+			})
+		})
+
+		When("orphans are kept", func() {
+			BeforeEach(func() {
+				config.DeleteOrphans = false
+			})
+
+			It("does not delete orphans", func() {
+				Expect(fakeSQLClient.DeleteOrphanedKubernetesResourcesCallCount()).To(BeZero())
+				Expect(fakeSQLClient.DeleteOrphanedPermissionsCallCount()).To(BeZero())
+			})
+		})
+
+		When("a policy returns an error", func() {
+			BeforeEach(func() {
+				fakeSQLClient.DeleteKubernetesResourcesCreatedBeforeReturns(0, errors.New("error deleting"))
+			})
+
+			It("runs the other policies", func() {
+				Expect(fakeSQLClient.DeleteOrphanedKubernetesResourcesCallCount()).To(Equal(1))
+				Expect(fakeSQLClient.DeleteOrphanedPermissionsCallCount()).To(Equal(1))
+			})
+		})
+
+		When("it succeeds", func() {
+			It("runs all policies", func() {
+				Expect(fakeSQLClient.DeleteKubernetesResourcesCreatedBeforeCallCount()).To(Equal(1))
+				before := fakeSQLClient.DeleteKubernetesResourcesCreatedBeforeArgsForCall(0)
+				Expect(before).To(BeTemporally("~", time.Now().Add(-24*time.Hour), time.Minute))
+				Expect(fakeSQLClient.DeleteMigrationReportsCreatedBeforeCallCount()).To(Equal(1))
+				before = fakeSQLClient.DeleteMigrationReportsCreatedBeforeArgsForCall(0)
+				Expect(before).To(BeTemporally("~", time.Now().Add(-24*time.Hour), time.Minute))
+				Expect(fakeSQLClient.DeleteDeploysCreatedBeforeCallCount()).To(Equal(1))
+				before = fakeSQLClient.DeleteDeploysCreatedBeforeArgsForCall(0)
+				Expect(before).To(BeTemporally("~", time.Now().Add(-24*time.Hour), time.Minute))
+				Expect(fakeSQLClient.DeleteTaskEventsCreatedBeforeCallCount()).To(Equal(1))
+				before = fakeSQLClient.DeleteTaskEventsCreatedBeforeArgsForCall(0)
+				Expect(before).To(BeTemporally("~", time.Now().Add(-24*time.Hour), time.Minute))
+				Expect(fakeSQLClient.DeleteFailedOperationsCreatedBeforeCallCount()).To(Equal(1))
+				before = fakeSQLClient.DeleteFailedOperationsCreatedBeforeArgsForCall(0)
+				Expect(before).To(BeTemporally("~", time.Now().Add(-7*24*time.Hour), time.Minute))
+				Expect(fakeSQLClient.DeleteCacheSnapshotsCreatedBeforeCallCount()).To(Equal(1))
+				before = fakeSQLClient.DeleteCacheSnapshotsCreatedBeforeArgsForCall(0)
+				Expect(before).To(BeTemporally("~", time.Now().Add(-30*24*time.Hour), time.Minute))
+				Expect(fakeSQLClient.DeleteOrphanedKubernetesResourcesCallCount()).To(Equal(1))
+				Expect(fakeSQLClient.DeleteOrphanedPermissionsCallCount()).To(Equal(1))
+				Expect(fakeSQLClient.DeleteCompressedBlobsUnusedSinceCallCount()).To(Equal(1))
+				before = fakeSQLClient.DeleteCompressedBlobsUnusedSinceArgsForCall(0)
+				Expect(before).To(BeTemporally("~", time.Now().Add(-time.Hour), time.Minute))
+			})
+		})
+	})
+
+	Describe("#Run", func() {
+		var ctx context.Context
+
+		BeforeEach(func() {
+			var cancel context.CancelFunc
+			ctx, cancel = context.WithCancel(context.Background())
+			fakeSQLClient.DeleteOrphanedPermissionsStub = func() (int64, error) {
+				cancel()
+				return 0, nil
+			}
+		})
+
+		JustBeforeEach(func() {
+			Run(ctx, fakeSQLClient, config)
+		})
+
+		It("cleans up until the context is done", func() {
+			Expect(fakeSQLClient.DeleteOrphanedPermissionsCallCount()).To(Equal(1))
+		})
+	})
+})
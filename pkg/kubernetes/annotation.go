@@ -2,6 +2,7 @@ package kubernetes
 
 import (
 	"fmt"
+	"strconv"
 	"strings"
 
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
@@ -11,10 +12,27 @@ const (
 	AnnotationSpinnakerArtifactLocation   = `artifact.spinnaker.io/location`
 	AnnotationSpinnakerArtifactName       = `artifact.spinnaker.io/name`
 	AnnotationSpinnakerArtifactType       = `artifact.spinnaker.io/type`
+	AnnotationSpinnakerArtifactVersion    = `artifact.spinnaker.io/version`
 	AnnotationSpinnakerMonikerApplication = `moniker.spinnaker.io/application`
 	AnnotationSpinnakerMonikerCluster     = `moniker.spinnaker.io/cluster`
+	AnnotationSpinnakerMonikerSequence    = `moniker.spinnaker.io/sequence`
+	// AnnotationSpinnakerMaxVersionHistory is how many versions of a versioned resource to keep.
+	AnnotationSpinnakerMaxVersionHistory = `strategy.spinnaker.io/max-version-history`
+	// AnnotationSpinnakerPinned marks a server group that must not be deleted
+	// or scaled to zero unless the operation overrides it.
+	AnnotationSpinnakerPinned = `strategy.spinnaker.io/pinned`
+	// AnnotationJobTTLSecondsAfterFinished is how many seconds after it
+	// finishes a Job run by runJob is deleted, for clusters that do not
+	// support ttlSecondsAfterFinished.
+	AnnotationJobTTLSecondsAfterFinished = `clouddriver.spinnaker.io/ttl-seconds-after-finished`
 )
 
+// Pinned returns true if a resource is annotated as pinned.
+func Pinned(u *unstructured.Unstructured) bool {
+	pinned, _ := strconv.ParseBool(u.GetAnnotations()[AnnotationSpinnakerPinned])
+	return pinned
+}
+
 func (c *controller) AddSpinnakerAnnotations(u *unstructured.Unstructured, application string) error {
 	var err error
 
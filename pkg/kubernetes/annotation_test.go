@@ -123,3 +123,19 @@ var _ = Describe("Annotation", func() {
 		})
 	})
 })
+
+var _ = Describe("#Pinned", func() {
+	var u *unstructured.Unstructured
+
+	BeforeEach(func() {
+		u = &unstructured.Unstructured{Object: map[string]interface{}{}}
+	})
+
+	It("returns true if the resource is annotated as pinned", func() {
+		Expect(Pinned(u)).To(BeFalse())
+		u.SetAnnotations(map[string]string{AnnotationSpinnakerPinned: "false"})
+		Expect(Pinned(u)).To(BeFalse())
+		u.SetAnnotations(map[string]string{AnnotationSpinnakerPinned: "true"})
+		Expect(Pinned(u)).To(BeTrue())
+	})
+})
@@ -0,0 +1,206 @@
+package kubernetes
+
+import (
+	"fmt"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	utilversion "k8s.io/apimachinery/pkg/util/version"
+	"k8s.io/apimachinery/pkg/version"
+)
+
+// RemovedAPIVersion is an API version of a kind that Kubernetes no longer serves.
+type RemovedAPIVersion struct {
+	GroupVersionKind schema.GroupVersionKind
+	// The minor Kubernetes version the API version was removed in, e.g. "1.22".
+	RemovedIn string
+	// The API version to use instead, empty if the kind was removed.
+	Replacement string
+	// convert converts a manifest to the replacement API version. It is nil
+	// when there is no lossless conversion.
+	convert func(*unstructured.Unstructured) error
+}
+
+var removedAPIVersions = []RemovedAPIVersion{
+	{GroupVersionKind: schema.GroupVersionKind{Group: "extensions", Version: "v1beta1", Kind: "Deployment"}, RemovedIn: "1.16", Replacement: "apps/v1", convert: convertWorkload},
+	{GroupVersionKind: schema.GroupVersionKind{Group: "extensions", Version: "v1beta1", Kind: "DaemonSet"}, RemovedIn: "1.16", Replacement: "apps/v1", convert: convertWorkload},
+	{GroupVersionKind: schema.GroupVersionKind{Group: "extensions", Version: "v1beta1", Kind: "ReplicaSet"}, RemovedIn: "1.16", Replacement: "apps/v1", convert: convertWorkload},
+	{GroupVersionKind: schema.GroupVersionKind{Group: "extensions", Version: "v1beta1", Kind: "NetworkPolicy"}, RemovedIn: "1.16", Replacement: "networking.k8s.io/v1", convert: convertAPIVersion},
+	{GroupVersionKind: schema.GroupVersionKind{Group: "extensions", Version: "v1beta1", Kind: "PodSecurityPolicy"}, RemovedIn: "1.16", Replacement: "policy/v1beta1", convert: convertAPIVersion},
+	{GroupVersionKind: schema.GroupVersionKind{Group: "apps", Version: "v1beta1", Kind: "Deployment"}, RemovedIn: "1.16", Replacement: "apps/v1", convert: convertWorkload},
+	{GroupVersionKind: schema.GroupVersionKind{Group: "apps", Version: "v1beta1", Kind: "StatefulSet"}, RemovedIn: "1.16", Replacement: "apps/v1", convert: convertWorkload},
+	{GroupVersionKind: schema.GroupVersionKind{Group: "apps", Version: "v1beta2", Kind: "Deployment"}, RemovedIn: "1.16", Replacement: "apps/v1", convert: convertWorkload},
+	{GroupVersionKind: schema.GroupVersionKind{Group: "apps", Version: "v1beta2", Kind: "DaemonSet"}, RemovedIn: "1.16", Replacement: "apps/v1", convert: convertWorkload},
+	{GroupVersionKind: schema.GroupVersionKind{Group: "apps", Version: "v1beta2", Kind: "ReplicaSet"}, RemovedIn: "1.16", Replacement: "apps/v1", convert: convertWorkload},
+	{GroupVersionKind: schema.GroupVersionKind{Group: "apps", Version: "v1beta2", Kind: "StatefulSet"}, RemovedIn: "1.16", Replacement: "apps/v1", convert: convertWorkload},
+	{GroupVersionKind: schema.GroupVersionKind{Group: "extensions", Version: "v1beta1", Kind: "Ingress"}, RemovedIn: "1.22", Replacement: "networking.k8s.io/v1", convert: convertIngress},
+	{GroupVersionKind: schema.GroupVersionKind{Group: "networking.k8s.io", Version: "v1beta1", Kind: "Ingress"}, RemovedIn: "1.22", Replacement: "networking.k8s.io/v1", convert: convertIngress},
+	{GroupVersionKind: schema.GroupVersionKind{Group: "batch", Version: "v1beta1", Kind: "CronJob"}, RemovedIn: "1.25", Replacement: "batch/v1", convert: convertAPIVersion},
+	// The meaning of an empty selector changed in policy/v1.
+	{GroupVersionKind: schema.GroupVersionKind{Group: "policy", Version: "v1beta1", Kind: "PodDisruptionBudget"}, RemovedIn: "1.25", Replacement: "policy/v1"},
+	{GroupVersionKind: schema.GroupVersionKind{Group: "policy", Version: "v1beta1", Kind: "PodSecurityPolicy"}, RemovedIn: "1.25"},
+	// Metrics are defined differently in autoscaling/v2.
+	{GroupVersionKind: schema.GroupVersionKind{Group: "autoscaling", Version: "v2beta1", Kind: "HorizontalPodAutoscaler"}, RemovedIn: "1.25", Replacement: "autoscaling/v2"},
+	{GroupVersionKind: schema.GroupVersionKind{Group: "autoscaling", Version: "v2beta2", Kind: "HorizontalPodAutoscaler"}, RemovedIn: "1.26", Replacement: "autoscaling/v2", convert: convertAPIVersion},
+}
+
+// RemovedAPIVersionFor returns the removed API version of a manifest if the
+// server version no longer serves it. The server version may be nil if it
+// is unknown, in which case API versions are never considered removed.
+func RemovedAPIVersionFor(u *unstructured.Unstructured, sv *version.Info) (RemovedAPIVersion, bool) {
+	if sv == nil {
+		return RemovedAPIVersion{}, false
+	}
+
+	v, err := utilversion.ParseGeneric(sv.GitVersion)
+	if err != nil {
+		return RemovedAPIVersion{}, false
+	}
+
+	gvk := u.GroupVersionKind()
+
+	for _, r := range removedAPIVersions {
+		if r.GroupVersionKind == gvk && v.AtLeast(utilversion.MustParseGeneric(r.RemovedIn)) {
+			return r, true
+		}
+	}
+
+	return RemovedAPIVersion{}, false
+}
+
+// Error returns why a manifest using the removed API version cannot be deployed.
+func (r RemovedAPIVersion) Error() string {
+	msg := fmt.Sprintf("apiVersion %s of kind %s was removed in Kubernetes v%s",
+		r.GroupVersionKind.GroupVersion().String(), r.GroupVersionKind.Kind, r.RemovedIn)
+	if r.Replacement == "" {
+		return msg + " and has no replacement"
+	}
+
+	return msg + ", use " + r.Replacement + " instead"
+}
+
+// Convert converts a manifest to the replacement API version. It returns
+// an error if there is no lossless conversion for the manifest.
+func (r RemovedAPIVersion) Convert(u *unstructured.Unstructured) error {
+	if r.convert == nil {
+		return r
+	}
+
+	if err := r.convert(u); err != nil {
+		return fmt.Errorf("%s: %w", r.Error(), err)
+	}
+
+	u.SetAPIVersion(r.Replacement)
+
+	return nil
+}
+
+// convertAPIVersion converts kinds whose schema is the same in both API versions.
+func convertAPIVersion(u *unstructured.Unstructured) error {
+	return nil
+}
+
+// convertWorkload converts beta workloads to apps/v1, which requires a selector.
+// The beta API versions defaulted the selector to the labels of the pod template.
+func convertWorkload(u *unstructured.Unstructured) error {
+	for _, field := range []string{"rollbackTo", "templateGeneration"} {
+		if _, ok, _ := unstructured.NestedFieldNoCopy(u.Object, "spec", field); ok {
+			return fmt.Errorf("spec.%s is not supported by apps/v1", field)
+		}
+	}
+
+	if _, ok, _ := unstructured.NestedFieldNoCopy(u.Object, "spec", "selector"); ok {
+		return nil
+	}
+
+	labels, _, _ := unstructured.NestedStringMap(u.Object, "spec", "template", "metadata", "labels")
+	if len(labels) == 0 {
+		return fmt.Errorf("spec.selector is required and the pod template has no labels to default it to")
+	}
+
+	return unstructured.SetNestedStringMap(u.Object, labels, "spec", "selector", "matchLabels")
+}
+
+// convertIngress converts beta ingresses to networking.k8s.io/v1, which renames
+// the default backend, nests backend services and requires a path type.
+func convertIngress(u *unstructured.Unstructured) error {
+	if backend, ok, _ := unstructured.NestedMap(u.Object, "spec", "backend"); ok {
+		if err := convertIngressBackend(backend); err != nil {
+			return err
+		}
+
+		unstructured.RemoveNestedField(u.Object, "spec", "backend")
+
+		if err := unstructured.SetNestedMap(u.Object, backend, "spec", "defaultBackend"); err != nil {
+			return err
+		}
+	}
+
+	rules, _, _ := unstructured.NestedSlice(u.Object, "spec", "rules")
+	for _, rule := range rules {
+		r, ok := rule.(map[string]interface{})
+		if !ok {
+			continue
+		}
+
+		paths, _, _ := unstructured.NestedSlice(r, "http", "paths")
+		for _, path := range paths {
+			p, ok := path.(map[string]interface{})
+			if !ok {
+				continue
+			}
+
+			if pathType, _, _ := unstructured.NestedString(p, "pathType"); pathType == "" {
+				p["pathType"] = "ImplementationSpecific"
+			}
+
+			if backend, ok := p["backend"].(map[string]interface{}); ok {
+				if err := convertIngressBackend(backend); err != nil {
+					return err
+				}
+			}
+		}
+
+		if len(paths) > 0 {
+			if err := unstructured.SetNestedSlice(r, paths, "http", "paths"); err != nil {
+				return err
+			}
+		}
+	}
+
+	if len(rules) > 0 {
+		return unstructured.SetNestedSlice(u.Object, rules, "spec", "rules")
+	}
+
+	return nil
+}
+
+// convertIngressBackend nests the service name and port of a beta ingress backend.
+func convertIngressBackend(backend map[string]interface{}) error {
+	name, ok := backend["serviceName"]
+	if !ok {
+		// Resource backends are the same in both API versions.
+		return nil
+	}
+
+	port := map[string]interface{}{}
+
+	switch p := backend["servicePort"].(type) {
+	case string:
+		port["name"] = p
+	case int64, float64:
+		port["number"] = p
+	default:
+		return fmt.Errorf("unsupported servicePort %v for service %v", backend["servicePort"], name)
+	}
+
+	delete(backend, "serviceName")
+	delete(backend, "servicePort")
+
+	backend["service"] = map[string]interface{}{
+		"name": name,
+		"port": port,
+	}
+
+	return nil
+}
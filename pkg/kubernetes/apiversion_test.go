@@ -0,0 +1,236 @@
+package kubernetes_test
+
+import (
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/version"
+
+	. "github.com/billiford/go-clouddriver/pkg/kubernetes"
+)
+
+var _ = Describe("APIVersion", func() {
+	var (
+		u       *unstructured.Unstructured
+		sv      *version.Info
+		removed RemovedAPIVersion
+		ok      bool
+		err     error
+	)
+
+	BeforeEach(func() {
+		sv = &version.Info{GitVersion: "v1.22.3-gke.1500"}
+		u = &unstructured.Unstructured{
+			Object: map[string]interface{}{
+				"apiVersion": "extensions/v1beta1",
+				"kind":       "Ingress",
+				"metadata": map[string]interface{}{
+					"name": "test-ingress",
+				},
+				"spec": map[string]interface{}{
+					"backend": map[string]interface{}{
+						"serviceName": "test-default-service",
+						"servicePort": int64(80),
+					},
+					"rules": []interface{}{
+						map[string]interface{}{
+							"host": "test.example.com",
+							"http": map[string]interface{}{
+								"paths": []interface{}{
+									map[string]interface{}{
+										"path": "/",
+										"backend": map[string]interface{}{
+											"serviceName": "test-service",
+											"servicePort": "http",
+										},
+									},
+								},
+							},
+						},
+					},
+				},
+			},
+		}
+	})
+
+	Describe("#RemovedAPIVersionFor", func() {
+		JustBeforeEach(func() {
+			removed, ok = RemovedAPIVersionFor(u, sv)
+		})
+
+		When("the server version is unknown", func() {
+			BeforeEach(func() {
+				sv = nil
+			})
+
+			It("returns false", func() {
+				Expect(ok).To(BeFalse())
+			})
+		})
+
+		When("the server version cannot be parsed", func() {
+			BeforeEach(func() {
+				sv = &version.Info{GitVersion: "unknown"}
+			})
+
+			It("returns false", func() {
+				Expect(ok).To(BeFalse())
+			})
+		})
+
+		When("the server still serves the API version", func() {
+			BeforeEach(func() {
+				sv = &version.Info{GitVersion: "v1.21.14"}
+			})
+
+			It("returns false", func() {
+				Expect(ok).To(BeFalse())
+			})
+		})
+
+		When("the API version is not removed", func() {
+			BeforeEach(func() {
+				u.SetAPIVersion("networking.k8s.io/v1")
+			})
+
+			It("returns false", func() {
+				Expect(ok).To(BeFalse())
+			})
+		})
+
+		When("the server no longer serves the API version", func() {
+			It("returns the removed API version", func() {
+				Expect(ok).To(BeTrue())
+				Expect(removed.RemovedIn).To(Equal("1.22"))
+				Expect(removed.Replacement).To(Equal("networking.k8s.io/v1"))
+				Expect(removed.Error()).To(Equal("apiVersion extensions/v1beta1 of kind Ingress was removed " +
+					"in Kubernetes v1.22, use networking.k8s.io/v1 instead"))
+			})
+		})
+
+		When("the kind has no replacement", func() {
+			BeforeEach(func() {
+				sv = &version.Info{GitVersion: "v1.25.0"}
+				u.SetAPIVersion("policy/v1beta1")
+				u.SetKind("PodSecurityPolicy")
+			})
+
+			It("says so", func() {
+				Expect(ok).To(BeTrue())
+				Expect(removed.Error()).To(Equal("apiVersion policy/v1beta1 of kind PodSecurityPolicy was removed " +
+					"in Kubernetes v1.25 and has no replacement"))
+			})
+		})
+	})
+
+	Describe("#Convert", func() {
+		BeforeEach(func() {
+			removed, ok = RemovedAPIVersionFor(u, sv)
+			Expect(ok).To(BeTrue())
+		})
+
+		JustBeforeEach(func() {
+			err = removed.Convert(u)
+		})
+
+		When("the kind is an ingress", func() {
+			It("converts the backends and sets the path type", func() {
+				Expect(err).To(BeNil())
+				Expect(u.GetAPIVersion()).To(Equal("networking.k8s.io/v1"))
+				_, found, _ := unstructured.NestedMap(u.Object, "spec", "backend")
+				Expect(found).To(BeFalse())
+				defaultBackend, _, _ := unstructured.NestedMap(u.Object, "spec", "defaultBackend")
+				Expect(defaultBackend).To(Equal(map[string]interface{}{
+					"service": map[string]interface{}{
+						"name": "test-default-service",
+						"port": map[string]interface{}{
+							"number": int64(80),
+						},
+					},
+				}))
+				rules, _, _ := unstructured.NestedSlice(u.Object, "spec", "rules")
+				paths, _, _ := unstructured.NestedSlice(rules[0].(map[string]interface{}), "http", "paths")
+				Expect(paths[0]).To(Equal(map[string]interface{}{
+					"path":     "/",
+					"pathType": "ImplementationSpecific",
+					"backend": map[string]interface{}{
+						"service": map[string]interface{}{
+							"name": "test-service",
+							"port": map[string]interface{}{
+								"name": "http",
+							},
+						},
+					},
+				}))
+			})
+		})
+
+		When("a workload has no selector", func() {
+			BeforeEach(func() {
+				u = &unstructured.Unstructured{
+					Object: map[string]interface{}{
+						"apiVersion": "extensions/v1beta1",
+						"kind":       "Deployment",
+						"metadata": map[string]interface{}{
+							"name": "test-deployment",
+						},
+						"spec": map[string]interface{}{
+							"template": map[string]interface{}{
+								"metadata": map[string]interface{}{
+									"labels": map[string]interface{}{
+										"app": "test",
+									},
+								},
+							},
+						},
+					},
+				}
+				removed, _ = RemovedAPIVersionFor(u, sv)
+			})
+
+			It("defaults the selector to the pod template labels", func() {
+				Expect(err).To(BeNil())
+				Expect(u.GetAPIVersion()).To(Equal("apps/v1"))
+				selector, _, _ := unstructured.NestedStringMap(u.Object, "spec", "selector", "matchLabels")
+				Expect(selector).To(Equal(map[string]string{"app": "test"}))
+			})
+		})
+
+		When("a workload sets a field removed in apps/v1", func() {
+			BeforeEach(func() {
+				u = &unstructured.Unstructured{
+					Object: map[string]interface{}{
+						"apiVersion": "extensions/v1beta1",
+						"kind":       "Deployment",
+						"spec": map[string]interface{}{
+							"rollbackTo": map[string]interface{}{},
+						},
+					},
+				}
+				removed, _ = RemovedAPIVersionFor(u, sv)
+			})
+
+			It("returns an error", func() {
+				Expect(err).ToNot(BeNil())
+				Expect(err.Error()).To(Equal("apiVersion extensions/v1beta1 of kind Deployment was removed " +
+					"in Kubernetes v1.16, use apps/v1 instead: spec.rollbackTo is not supported by apps/v1"))
+				Expect(u.GetAPIVersion()).To(Equal("extensions/v1beta1"))
+			})
+		})
+
+		When("there is no lossless conversion", func() {
+			BeforeEach(func() {
+				sv = &version.Info{GitVersion: "v1.25.0"}
+				u.SetAPIVersion("policy/v1beta1")
+				u.SetKind("PodDisruptionBudget")
+				removed, _ = RemovedAPIVersionFor(u, sv)
+			})
+
+			It("returns an error", func() {
+				Expect(err).ToNot(BeNil())
+				Expect(err.Error()).To(Equal("apiVersion policy/v1beta1 of kind PodDisruptionBudget was removed " +
+					"in Kubernetes v1.25, use policy/v1 instead"))
+			})
+		})
+	})
+})
@@ -0,0 +1,81 @@
+package kubernetes
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"time"
+)
+
+const (
+	// CacheKindNamespaces is the namespaces of an account.
+	CacheKindNamespaces = `namespaces`
+	// CacheKindDiscovery is the API resources a cluster serves, such as CRDs.
+	CacheKindDiscovery = `discovery`
+)
+
+var (
+	defaultCacheConfigPath = "/opt/spinnaker/kubernetes/cache.json"
+)
+
+// CacheConfig defines how long each cached kind is kept before it is listed
+// again, with optional overrides per account. Kinds that are not configured
+// use the default interval of the cache.
+//
+//	{
+//	  "cacheIntervalSeconds": {
+//	    "namespaces": 300,
+//	    "discovery": 3600
+//	  },
+//	  "accounts": {
+//	    "large-account": {
+//	      "namespaces": 900
+//	    }
+//	  }
+//	}
+type CacheConfig struct {
+	CacheIntervalSeconds map[string]int            `json:"cacheIntervalSeconds,omitempty"`
+	Accounts             map[string]map[string]int `json:"accounts,omitempty"`
+}
+
+// NewDefaultCacheConfig reads the cache config from /opt/spinnaker/kubernetes/cache.json.
+// The config is optional, so if the file does not exist the default intervals are used.
+func NewDefaultCacheConfig() (CacheConfig, error) {
+	if _, err := os.Stat(defaultCacheConfigPath); os.IsNotExist(err) {
+		return CacheConfig{}, nil
+	}
+
+	return NewCacheConfig(defaultCacheConfigPath)
+}
+
+// NewCacheConfig reads the cache config from a JSON file.
+func NewCacheConfig(path string) (CacheConfig, error) {
+	cc := CacheConfig{}
+
+	b, err := ioutil.ReadFile(path)
+	if err != nil {
+		return cc, err
+	}
+
+	err = json.Unmarshal(b, &cc)
+	if err != nil {
+		return cc, err
+	}
+
+	return cc, nil
+}
+
+// Interval returns how long to cache a kind for an account, or def if
+// neither the account nor the kind is configured. Pass an empty account
+// for caches that are not per account.
+func (cc CacheConfig) Interval(account, kind string, def time.Duration) time.Duration {
+	if seconds, ok := cc.Accounts[account][kind]; ok && seconds > 0 {
+		return time.Duration(seconds) * time.Second
+	}
+
+	if seconds, ok := cc.CacheIntervalSeconds[kind]; ok && seconds > 0 {
+		return time.Duration(seconds) * time.Second
+	}
+
+	return def
+}
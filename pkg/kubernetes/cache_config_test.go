@@ -0,0 +1,69 @@
+package kubernetes_test
+
+import (
+	"time"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	. "github.com/billiford/go-clouddriver/pkg/kubernetes"
+)
+
+var _ = Describe("CacheConfig", func() {
+	var (
+		cc  CacheConfig
+		err error
+	)
+
+	Describe("#NewCacheConfig", func() {
+		When("the file does not exist", func() {
+			BeforeEach(func() {
+				cc, err = NewCacheConfig("test/missing.json")
+			})
+
+			It("returns an error", func() {
+				Expect(err).ToNot(BeNil())
+			})
+		})
+
+		When("it succeeds", func() {
+			BeforeEach(func() {
+				cc, err = NewCacheConfig("test/cache.json")
+			})
+
+			It("reads the intervals", func() {
+				Expect(err).To(BeNil())
+				Expect(cc.CacheIntervalSeconds).To(HaveKeyWithValue(CacheKindNamespaces, 300))
+				Expect(cc.CacheIntervalSeconds).To(HaveKeyWithValue(CacheKindDiscovery, 3600))
+				Expect(cc.Accounts["large-account"]).To(HaveKeyWithValue(CacheKindNamespaces, 900))
+			})
+		})
+	})
+
+	Describe("#Interval", func() {
+		BeforeEach(func() {
+			cc, err = NewCacheConfig("test/cache.json")
+			Expect(err).To(BeNil())
+		})
+
+		When("the account overrides the kind", func() {
+			It("returns the account's interval", func() {
+				Expect(cc.Interval("large-account", CacheKindNamespaces, time.Minute)).To(Equal(15 * time.Minute))
+			})
+		})
+
+		When("the account does not override the kind", func() {
+			It("returns the kind's interval", func() {
+				Expect(cc.Interval("other-account", CacheKindNamespaces, time.Minute)).To(Equal(5 * time.Minute))
+				Expect(cc.Interval("", CacheKindDiscovery, time.Minute)).To(Equal(time.Hour))
+			})
+		})
+
+		When("the kind is not configured", func() {
+			It("returns the default", func() {
+				Expect(cc.Interval("large-account", "pods", time.Minute)).To(Equal(time.Minute))
+				Expect(CacheConfig{}.Interval("large-account", CacheKindNamespaces, time.Minute)).To(Equal(time.Minute))
+			})
+		})
+	})
+})
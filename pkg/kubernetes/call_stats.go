@@ -0,0 +1,155 @@
+package kubernetes
+
+import (
+	"net/http"
+	"net/http/httptrace"
+	"strconv"
+	"sync"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"k8s.io/client-go/rest"
+)
+
+var (
+	apiConnections = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "clouddriver_kubernetes_api_connections_total",
+		Help: "Number of requests to Kubernetes API servers by host and whether they reused an idle connection.",
+	}, []string{"host", "reused"})
+)
+
+func init() {
+	prometheus.MustRegister(apiConnections)
+}
+
+// CallCount is the number of calls made to an API server and how many of
+// them failed, either without a response or with a 5xx status. Calls are
+// made on a new connection or reuse an idle one; a cluster whose calls
+// rarely reuse connections may be dropping them while they are idle.
+type CallCount struct {
+	Calls       int64
+	Errors      int64
+	NewConns    int64
+	ReusedConns int64
+}
+
+// ErrorRate returns the fraction of calls that failed, or 0 if there were none.
+func (cc CallCount) ErrorRate() float64 {
+	if cc.Calls == 0 {
+		return 0
+	}
+
+	return float64(cc.Errors) / float64(cc.Calls)
+}
+
+// ReuseRate returns the fraction of connections that were reused, or 0 if
+// there were none.
+func (cc CallCount) ReuseRate() float64 {
+	conns := cc.NewConns + cc.ReusedConns
+	if conns == 0 {
+		return 0
+	}
+
+	return float64(cc.ReusedConns) / float64(conns)
+}
+
+// CallStats counts the calls made to the API server of each host since it
+// was created, and the requests by verb and resource in RequestWindows.
+type CallStats struct {
+	mux      sync.Mutex
+	counts   map[string]CallCount
+	requests *requestStats
+}
+
+// NewCallStats returns empty CallStats.
+func NewCallStats() *CallStats {
+	return &CallStats{
+		counts:   map[string]CallCount{},
+		requests: newRequestStats(),
+	}
+}
+
+// Wrap counts the calls made with config to the host of config.
+func (s *CallStats) Wrap(config *rest.Config) {
+	host := config.Host
+
+	config.Wrap(func(rt http.RoundTripper) http.RoundTripper {
+		return &countingTransport{
+			rt:    rt,
+			host:  host,
+			stats: s,
+		}
+	})
+}
+
+// Count returns the calls made to host.
+func (s *CallStats) Count(host string) CallCount {
+	s.mux.Lock()
+	defer s.mux.Unlock()
+
+	return s.counts[host]
+}
+
+// EvictIdle forgets the request counts of hosts without requests in the
+// longest of RequestWindows, returning how many hosts were forgotten. Call
+// counts are kept, as they count calls since the stats were created.
+func (s *CallStats) EvictIdle() int {
+	return s.requests.evictIdle()
+}
+
+// Requests returns the requests made to host by verb and resource in each
+// of RequestWindows, the most requested first.
+func (s *CallStats) Requests(host string) []RequestCount {
+	return s.requests.counts(host)
+}
+
+func (s *CallStats) add(host string, failed bool) {
+	s.mux.Lock()
+	defer s.mux.Unlock()
+
+	cc := s.counts[host]
+	cc.Calls++
+
+	if failed {
+		cc.Errors++
+	}
+
+	s.counts[host] = cc
+}
+
+func (s *CallStats) addConn(host string, reused bool) {
+	apiConnections.WithLabelValues(host, strconv.FormatBool(reused)).Inc()
+
+	s.mux.Lock()
+	defer s.mux.Unlock()
+
+	cc := s.counts[host]
+	if reused {
+		cc.ReusedConns++
+	} else {
+		cc.NewConns++
+	}
+
+	s.counts[host] = cc
+}
+
+type countingTransport struct {
+	rt    http.RoundTripper
+	host  string
+	stats *CallStats
+}
+
+func (t *countingTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	t.stats.requests.add(t.host, req)
+
+	trace := &httptrace.ClientTrace{
+		GotConn: func(info httptrace.GotConnInfo) {
+			t.stats.addConn(t.host, info.Reused)
+		},
+	}
+	req = req.WithContext(httptrace.WithClientTrace(req.Context(), trace))
+
+	res, err := t.rt.RoundTrip(req)
+	t.stats.add(t.host, err != nil || res.StatusCode >= http.StatusInternalServerError)
+
+	return res, err
+}
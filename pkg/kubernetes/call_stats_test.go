@@ -0,0 +1,163 @@
+package kubernetes_test
+
+import (
+	"io/ioutil"
+	"net/http"
+	"time"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"github.com/onsi/gomega/ghttp"
+	"k8s.io/client-go/rest"
+
+	. "github.com/billiford/go-clouddriver/pkg/kubernetes"
+)
+
+var _ = Describe("CallStats", func() {
+	var (
+		fakeServer *ghttp.Server
+		stats      *CallStats
+		client     *http.Client
+	)
+
+	BeforeEach(func() {
+		fakeServer = ghttp.NewServer()
+		fakeServer.AppendHandlers(
+			ghttp.RespondWith(http.StatusOK, `{}`),
+			ghttp.RespondWith(http.StatusNotFound, `{}`),
+			ghttp.RespondWith(http.StatusServiceUnavailable, `{}`),
+		)
+
+		stats = NewCallStats()
+		config := &rest.Config{Host: fakeServer.URL()}
+		stats.Wrap(config)
+
+		rt, err := rest.TransportFor(config)
+		Expect(err).To(BeNil())
+
+		client = &http.Client{Transport: rt}
+	})
+
+	AfterEach(func() {
+		fakeServer.Close()
+	})
+
+	JustBeforeEach(func() {
+		for i := 0; i < 3; i++ {
+			res, err := client.Get(fakeServer.URL() + "/api/v1/namespaces")
+			Expect(err).To(BeNil())
+			// Connections are only reused once their response is read.
+			_, _ = ioutil.ReadAll(res.Body)
+			res.Body.Close()
+		}
+	})
+
+	It("counts calls and server errors by host", func() {
+		cc := stats.Count(fakeServer.URL())
+		Expect(cc.Calls).To(Equal(int64(3)))
+		Expect(cc.Errors).To(Equal(int64(1)))
+		Expect(cc.ErrorRate()).To(BeNumerically("~", 0.333, 0.001))
+	})
+
+	It("counts new and reused connections", func() {
+		cc := stats.Count(fakeServer.URL())
+		Expect(cc.NewConns).To(Equal(int64(1)))
+		Expect(cc.ReusedConns).To(Equal(int64(2)))
+		Expect(cc.ReuseRate()).To(BeNumerically("~", 0.667, 0.001))
+	})
+
+	It("returns no calls for other hosts", func() {
+		cc := stats.Count("https://other-host")
+		Expect(cc.Calls).To(BeZero())
+		Expect(cc.ErrorRate()).To(BeZero())
+		Expect(cc.ReuseRate()).To(BeZero())
+	})
+})
+
+var _ = Describe("CallStats requests", func() {
+	var (
+		fakeServer *ghttp.Server
+		stats      *CallStats
+		client     *http.Client
+	)
+
+	BeforeEach(func() {
+		fakeServer = ghttp.NewServer()
+		fakeServer.SetAllowUnhandledRequests(true)
+		fakeServer.SetUnhandledRequestStatusCode(http.StatusOK)
+
+		stats = NewCallStats()
+		config := &rest.Config{Host: fakeServer.URL()}
+		stats.Wrap(config)
+
+		rt, err := rest.TransportFor(config)
+		Expect(err).To(BeNil())
+
+		client = &http.Client{Transport: rt}
+	})
+
+	AfterEach(func() {
+		fakeServer.Close()
+	})
+
+	JustBeforeEach(func() {
+		requests := []struct {
+			method string
+			path   string
+		}{
+			{http.MethodGet, "/api/v1/namespaces/default/pods"},
+			{http.MethodGet, "/api/v1/namespaces/default/pods"},
+			{http.MethodGet, "/api/v1/namespaces/default/pods/my-pod/log"},
+			{http.MethodGet, "/api/v1/namespaces/default"},
+			{http.MethodGet, "/apis/apps/v1/namespaces/default/deployments?watch=true"},
+			{http.MethodPatch, "/apis/apps/v1/namespaces/default/deployments/my-deployment"},
+			{http.MethodDelete, "/apis/apps/v1/namespaces/default/replicasets"},
+			{http.MethodGet, "/apis/apps/v1"},
+		}
+
+		for _, r := range requests {
+			req, err := http.NewRequest(r.method, fakeServer.URL()+r.path, nil)
+			Expect(err).To(BeNil())
+			res, err := client.Do(req)
+			Expect(err).To(BeNil())
+			res.Body.Close()
+		}
+	})
+
+	It("counts requests by verb and resource in each window, the most requested first", func() {
+		rcs := stats.Requests(fakeServer.URL())
+		Expect(rcs).To(HaveLen(7))
+		Expect(rcs[0].Verb).To(Equal("list"))
+		Expect(rcs[0].Resource).To(Equal("pods"))
+		Expect(rcs[0].Windows).To(Equal(map[time.Duration]int64{
+			time.Minute:      2,
+			5 * time.Minute:  2,
+			15 * time.Minute: 2,
+			time.Hour:        2,
+		}))
+
+		requests := []string{}
+		for _, rc := range rcs[1:] {
+			Expect(rc.Windows[time.Minute]).To(Equal(int64(1)))
+			requests = append(requests, rc.Verb+" "+rc.Resource)
+		}
+
+		Expect(requests).To(Equal([]string{
+			"patch deployments.apps",
+			"watch deployments.apps",
+			"get discovery",
+			"get namespaces",
+			"get pods/log",
+			"deletecollection replicasets.apps",
+		}))
+	})
+
+	It("returns no requests for other hosts", func() {
+		Expect(stats.Requests("https://other-host")).To(BeEmpty())
+	})
+
+	It("does not evict hosts with recent requests", func() {
+		Expect(stats.EvictIdle()).To(BeZero())
+		Expect(stats.Requests(fakeServer.URL())).To(HaveLen(7))
+	})
+})
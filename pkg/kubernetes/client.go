@@ -2,15 +2,22 @@ package kubernetes
 
 import (
 	"context"
+	"io"
 
 	"github.com/billiford/go-clouddriver/pkg/kubernetes/patcher"
+	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 	"k8s.io/apimachinery/pkg/runtime/schema"
 	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/version"
 	"k8s.io/cli-runtime/pkg/resource"
+	"k8s.io/client-go/discovery"
 	"k8s.io/client-go/dynamic"
+	clientset "k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/metadata"
 	"k8s.io/client-go/rest"
 	"k8s.io/client-go/restmapper"
 	"k8s.io/kubectl/pkg/util"
@@ -35,19 +42,27 @@ type Metadata struct {
 type Client interface {
 	Apply(*unstructured.Unstructured) (Metadata, error)
 	ApplyWithNamespaceOverride(*unstructured.Unstructured, string) (Metadata, error)
+	CanI(string, string, string, string) (bool, error)
 	DeleteResourceByKindAndNameAndNamespace(string, string, string, metav1.DeleteOptions) error
 	GVRForKind(string) (schema.GroupVersionResource, error)
 	Get(string, string, string) (*unstructured.Unstructured, error)
+	InvalidateDiscovery()
 	ListByGVR(schema.GroupVersionResource, metav1.ListOptions) (*unstructured.UnstructuredList, error)
+	ListMetadataByGVR(schema.GroupVersionResource, metav1.ListOptions) (*metav1.PartialObjectMetadataList, error)
 	ListResource(string, metav1.ListOptions) (*unstructured.UnstructuredList, error)
+	NamespaceScoped(string) (bool, error)
 	Patch(string, string, string, []byte) (Metadata, *unstructured.Unstructured, error)
 	PatchUsingStrategy(string, string, string, []byte, types.PatchType) (Metadata, *unstructured.Unstructured, error)
+	ServerVersion() (*version.Info, error)
+	StreamLogs(context.Context, string, string, corev1.PodLogOptions) (io.ReadCloser, error)
 }
 
 type client struct {
-	c      dynamic.Interface
-	config *rest.Config
-	mapper *restmapper.DeferredDiscoveryRESTMapper
+	c         dynamic.Interface
+	metadata  metadata.Interface
+	discovery discovery.ServerVersionInterface
+	config    *rest.Config
+	mapper    *restmapper.DeferredDiscoveryRESTMapper
 }
 
 // Apply a given manifest.
@@ -119,21 +134,22 @@ func (c *client) ApplyWithNamespaceOverride(u *unstructured.Unstructured, namesp
 			return metadata, err
 		}
 
-		// Then create the resource and skip the three-way merge
+		// Then create the resource and skip the three-way merge. A dry-run
+		// create does not persist the resource, so there is nothing to patch.
 		obj, err := helper.Create(info.Namespace, true, info.Object)
 		if err != nil {
 			return metadata, err
 		}
 		info.Refresh(obj, true)
-	}
+	} else {
+		_, patchedObject, err := patcher.Patch(info.Object, modified, info.Namespace, info.Name)
+		if err != nil {
+			return metadata, err
+		}
 
-	_, patchedObject, err := patcher.Patch(info.Object, modified, info.Namespace, info.Name)
-	if err != nil {
-		return metadata, err
+		info.Refresh(patchedObject, true)
 	}
 
-	info.Refresh(patchedObject, true)
-
 	metadata.Name = u.GetName()
 	metadata.Namespace = u.GetNamespace()
 	metadata.Group = gvr.Group
@@ -227,9 +243,48 @@ func (c *client) GVRForKind(kind string) (schema.GroupVersionResource, error) {
 	return c.mapper.ResourceFor(schema.GroupVersionResource{Resource: kind})
 }
 
+// InvalidateDiscovery makes the client discover the resources the cluster
+// serves again instead of reading them from the disk cache, for callers
+// that cannot miss a resource type that was just added, such as a CRD.
+func (c *client) InvalidateDiscovery() {
+	c.mapper.Reset()
+}
+
 // List all resources by their GVR and list options.
 func (c *client) ListByGVR(gvr schema.GroupVersionResource, lo metav1.ListOptions) (*unstructured.UnstructuredList, error) {
-	return c.c.Resource(gvr).List(context.TODO(), lo)
+	var (
+		list *unstructured.UnstructuredList
+		err  error
+	)
+
+	if gvk, ok := c.builtInKind(gvr); ok {
+		list, err = c.listUsingProtobuf(gvk, gvr, lo)
+	} else {
+		list, err = c.c.Resource(gvr).List(context.TODO(), lo)
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	stripManagedFields(list)
+
+	return list, nil
+}
+
+// ListMetadataByGVR lists only the metadata of resources, for callers that do not
+// need their spec or status, such as when listing the names of namespaces.
+func (c *client) ListMetadataByGVR(gvr schema.GroupVersionResource, lo metav1.ListOptions) (*metav1.PartialObjectMetadataList, error) {
+	list, err := c.metadata.Resource(gvr).List(context.TODO(), lo)
+	if err != nil {
+		return nil, err
+	}
+
+	for i := range list.Items {
+		list.Items[i].SetManagedFields(nil)
+	}
+
+	return list, nil
 }
 
 // List all resources by their kind or resource (e.g. "replicaset" or "replicasets")
@@ -238,7 +293,24 @@ func (c *client) ListResource(resource string, lo metav1.ListOptions) (*unstruct
 	if err != nil {
 		return nil, err
 	}
-	return c.c.Resource(gvr).List(context.TODO(), lo)
+
+	return c.ListByGVR(gvr, lo)
+}
+
+// NamespaceScoped returns true if resources of a kind (example: 'deployment'
+// or 'clusterrole') are namespaced, according to the REST mapping of the cluster.
+func (c *client) NamespaceScoped(kind string) (bool, error) {
+	gvk, err := c.mapper.KindFor(schema.GroupVersionResource{Resource: kind})
+	if err != nil {
+		return false, err
+	}
+
+	restMapping, err := c.mapper.RESTMapping(gvk.GroupKind(), gvk.Version)
+	if err != nil {
+		return false, err
+	}
+
+	return restMapping.Scope.Name() == meta.RESTScopeNameNamespace, nil
 }
 
 func (c *client) Patch(kind, name, namespace string, p []byte) (Metadata, *unstructured.Unstructured, error) {
@@ -292,3 +364,57 @@ func (c *client) PatchUsingStrategy(kind, name, namespace string, p []byte, stra
 
 	return metadata, u, err
 }
+
+// CanI returns true if the credentials of the client may perform a verb on
+// a resource of an API group in a namespace, or all namespaces when empty,
+// as reviewed by the cluster with a SelfSubjectAccessReview.
+func (c *client) CanI(verb, group, resource, namespace string) (bool, error) {
+	review := &unstructured.Unstructured{
+		Object: map[string]interface{}{
+			"apiVersion": "authorization.k8s.io/v1",
+			"kind":       "SelfSubjectAccessReview",
+			"spec": map[string]interface{}{
+				"resourceAttributes": map[string]interface{}{
+					"verb":      verb,
+					"group":     group,
+					"resource":  resource,
+					"namespace": namespace,
+				},
+			},
+		},
+	}
+
+	gvr := schema.GroupVersionResource{
+		Group:    "authorization.k8s.io",
+		Version:  "v1",
+		Resource: "selfsubjectaccessreviews",
+	}
+
+	result, err := c.c.Resource(gvr).Create(context.TODO(), review, metav1.CreateOptions{})
+	if err != nil {
+		return false, err
+	}
+
+	allowed, _, _ := unstructured.NestedBool(result.Object, "status", "allowed")
+
+	return allowed, nil
+}
+
+// ServerVersion returns the Kubernetes version of the cluster.
+func (c *client) ServerVersion() (*version.Info, error) {
+	return c.discovery.ServerVersion()
+}
+
+// StreamLogs streams the logs of a pod by name and namespace. Followed logs
+// are streamed until ctx is done, so the request has no timeout.
+func (c *client) StreamLogs(ctx context.Context, name, namespace string, plo corev1.PodLogOptions) (io.ReadCloser, error) {
+	config := *c.config
+	config.Timeout = 0
+
+	cs, err := clientset.NewForConfig(&config)
+	if err != nil {
+		return nil, err
+	}
+
+	return cs.CoreV1().Pods(namespace).GetLogs(name, &plo).Stream(ctx)
+}
@@ -0,0 +1,22 @@
+package kubernetes
+
+// ClusterCredential is the host, CA data and tokens of a cluster, stored once
+// for every provider that names it, such as providers scoped to different
+// namespaces of the same cluster.
+type ClusterCredential struct {
+	Name                string `json:"name" gorm:"primary_key"`
+	Host                string `json:"host"`
+	CAData              string `json:"caData" gorm:"size:2048"`
+	BearerToken         string `json:"bearerToken,omitempty" gorm:"size:2048"`
+	TokenServiceAccount string `json:"tokenServiceAccount,omitempty"`
+}
+
+func (ClusterCredential) TableName() string {
+	return "kubernetes_cluster_credentials"
+}
+
+// ValidTokenServiceAccount returns true if the cluster credential has no
+// token service account or it is given as namespace/name.
+func (cc ClusterCredential) ValidTokenServiceAccount() bool {
+	return Provider{TokenServiceAccount: cc.TokenServiceAccount}.ValidTokenServiceAccount()
+}
@@ -0,0 +1,57 @@
+package kubernetes
+
+import (
+	"context"
+	"net/http"
+
+	"k8s.io/client-go/rest"
+)
+
+// contextController is a Controller whose clients, and the requests minting
+// tokens, are bound to ctx: requests made without a context of their own are
+// cancelled when ctx is done.
+type contextController struct {
+	Controller
+	ctx context.Context
+}
+
+func (c *contextController) wrap(config *rest.Config) {
+	ctx := c.ctx
+
+	config.Wrap(func(rt http.RoundTripper) http.RoundTripper {
+		return &contextTransport{rt: rt, ctx: ctx}
+	})
+}
+
+func (c *contextController) NewClient(config *rest.Config) (Client, error) {
+	c.wrap(config)
+
+	return c.Controller.NewClient(config)
+}
+
+func (c *contextController) MintToken(p Provider, config *rest.Config) error {
+	c.wrap(config)
+
+	return c.Controller.MintToken(p, config)
+}
+
+// WithContext binds the controller c was created from to ctx instead.
+func (c *contextController) WithContext(ctx context.Context) Controller {
+	return c.Controller.WithContext(ctx)
+}
+
+// contextTransport makes requests whose context is never done, such as
+// context.TODO(), with ctx instead. Requests made with a context that can be
+// cancelled, such as log streams, keep theirs.
+type contextTransport struct {
+	rt  http.RoundTripper
+	ctx context.Context
+}
+
+func (t *contextTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	if req.Context().Done() == nil {
+		req = req.WithContext(t.ctx)
+	}
+
+	return t.rt.RoundTrip(req)
+}
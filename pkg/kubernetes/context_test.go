@@ -0,0 +1,67 @@
+package kubernetes_test
+
+import (
+	"context"
+	"net/http"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"github.com/onsi/gomega/ghttp"
+	"k8s.io/client-go/rest"
+
+	. "github.com/billiford/go-clouddriver/pkg/kubernetes"
+)
+
+var _ = Describe("Controller with context", func() {
+	var (
+		fakeServer *ghttp.Server
+		ctx        context.Context
+		cancel     context.CancelFunc
+		kc         Controller
+		err        error
+	)
+
+	BeforeEach(func() {
+		fakeServer = ghttp.NewServer()
+		fakeServer.AllowUnhandledRequests = true
+		fakeServer.UnhandledRequestStatusCode = http.StatusCreated
+		ctx, cancel = context.WithCancel(context.Background())
+		kc = NewController().WithContext(ctx)
+	})
+
+	AfterEach(func() {
+		cancel()
+		fakeServer.Close()
+	})
+
+	JustBeforeEach(func() {
+		err = kc.MintToken(Provider{
+			Name:                "test-account",
+			Host:                fakeServer.URL(),
+			TokenServiceAccount: "spinnaker/deployer",
+		}, &rest.Config{Host: fakeServer.URL()})
+	})
+
+	When("the context is done", func() {
+		BeforeEach(func() {
+			cancel()
+		})
+
+		It("cancels requests to the API server", func() {
+			Expect(err).ToNot(BeNil())
+			Expect(err.Error()).To(ContainSubstring("context canceled"))
+			Expect(fakeServer.ReceivedRequests()).To(BeEmpty())
+		})
+	})
+
+	When("the context is bound again", func() {
+		BeforeEach(func() {
+			cancel()
+			kc = kc.WithContext(context.Background())
+		})
+
+		It("binds requests to the new context only", func() {
+			Expect(fakeServer.ReceivedRequests()).To(HaveLen(1))
+		})
+	})
+})
@@ -1,15 +1,18 @@
 package kubernetes
 
 import (
+	"context"
 	"path/filepath"
 	"regexp"
 	"strings"
+	"sync"
 	"time"
 
 	"github.com/billiford/go-clouddriver/pkg/kubernetes/cached/disk"
 	"github.com/gin-gonic/gin"
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 	"k8s.io/client-go/dynamic"
+	"k8s.io/client-go/metadata"
 	"k8s.io/client-go/rest"
 	"k8s.io/client-go/restmapper"
 )
@@ -21,19 +24,79 @@ const (
 //go:generate counterfeiter . Controller
 type Controller interface {
 	NewClient(*rest.Config) (Client, error)
+	MintToken(Provider, *rest.Config) error
 	ToUnstructured(map[string]interface{}) (*unstructured.Unstructured, error)
 	AddSpinnakerAnnotations(u *unstructured.Unstructured, application string) error
 	AddSpinnakerLabels(u *unstructured.Unstructured, application string) error
+	CallCount(host string) CallCount
+	RequestCounts(host string) []RequestCount
+	EvictIdle(idle time.Duration) int
+	WithContext(context.Context) Controller
 }
 
 func NewController() Controller {
-	return &controller{}
+	return NewControllerWithCacheConfig(CacheConfig{})
 }
 
-type controller struct{}
+// NewControllerWithCacheConfig returns a Controller whose clients cache API
+// discovery for the discovery interval in cc.
+func NewControllerWithCacheConfig(cc CacheConfig) Controller {
+	return &controller{
+		discoveryTTL: cc.Interval("", CacheKindDiscovery, ttl),
+		tokens:       map[string]mintedToken{},
+		transports:   newTransports(),
+		fakes:        newFakeClusters(),
+		stats:        NewCallStats(),
+	}
+}
+
+type controller struct {
+	discoveryTTL time.Duration
+
+	mux    sync.Mutex
+	tokens map[string]mintedToken
+
+	transports *transports
+
+	fakes *fakeClusters
+
+	stats *CallStats
+}
 
 func (c *controller) NewClient(config *rest.Config) (Client, error) {
-	return newClientWithDefaultDiskCache(config)
+	c.stats.Wrap(config)
+
+	return newClientWithDefaultDiskCache(config, c.discoveryTTL)
+}
+
+// CallCount returns the calls made by clients of the controller to the API
+// server of host since the controller was created.
+func (c *controller) CallCount(host string) CallCount {
+	return c.stats.Count(host)
+}
+
+// RequestCounts returns the requests made by clients of the controller to
+// the API server of host by verb and resource in each of RequestWindows.
+func (c *controller) RequestCounts(host string) []RequestCount {
+	return c.stats.Requests(host)
+}
+
+// EvictIdle forgets the tokens minted for clusters and the transports of
+// clusters that have not been used for idle, and the request counts of hosts
+// without recent requests, so instances do not hold on to the state of
+// deleted or unused accounts for as long as they run. It returns how many
+// were forgotten.
+func (c *controller) EvictIdle(idle time.Duration) int {
+	t := time.Now().Add(-idle)
+
+	return c.evictIdleTokens(t) + c.transports.evictIdle(t) + c.stats.EvictIdle()
+}
+
+// WithContext returns a controller whose clients make requests to API
+// servers that are cancelled when ctx is done, such as when a request to
+// clouddriver times out.
+func (c *controller) WithContext(ctx context.Context) Controller {
+	return &contextController{Controller: c, ctx: ctx}
 }
 
 const (
@@ -46,7 +109,7 @@ var (
 	ttl = 10 * time.Minute
 )
 
-func newClientWithDefaultDiskCache(config *rest.Config) (Client, error) {
+func newClientWithDefaultDiskCache(config *rest.Config, ttl time.Duration) (Client, error) {
 	config.Timeout = defaultTimeout
 
 	dynamicClient, err := dynamic.NewForConfig(config)
@@ -54,6 +117,11 @@ func newClientWithDefaultDiskCache(config *rest.Config) (Client, error) {
 		return nil, err
 	}
 
+	metadataClient, err := metadata.NewForConfig(config)
+	if err != nil {
+		return nil, err
+	}
+
 	// Some code to define this take from
 	// https://github.com/kubernetes/cli-runtime/blob/master/pkg/genericclioptions/config_flags.go#L215
 	httpCacheDir := filepath.Join(cacheDir, "http")
@@ -68,9 +136,11 @@ func newClientWithDefaultDiskCache(config *rest.Config) (Client, error) {
 	mapper := restmapper.NewDeferredDiscoveryRESTMapper(cdc)
 
 	kubeClient := &client{
-		c:      dynamicClient,
-		config: config,
-		mapper: mapper,
+		c:         dynamicClient,
+		metadata:  metadataClient,
+		discovery: cdc,
+		config:    config,
+		mapper:    mapper,
 	}
 
 	return kubeClient, nil
@@ -0,0 +1,117 @@
+// Package decrypt decrypts manifests before they are deployed, so secrets can
+// flow through pipelines encrypted.
+package decrypt
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os/exec"
+	"strings"
+)
+
+const (
+	// The kind and API group of Bitnami's sealed secrets, see https://github.com/bitnami-labs/sealed-secrets.
+	sealedSecretKind  = `SealedSecret`
+	sealedSecretGroup = `bitnami.com`
+)
+
+// Decrypter decrypts manifests. Decrypt returns false if a manifest is not
+// encrypted in a way the Decrypter supports.
+//
+//go:generate counterfeiter . Decrypter
+type Decrypter interface {
+	Decrypt(map[string]interface{}) (map[string]interface{}, bool, error)
+}
+
+// NewSOPSDecrypter returns a Decrypter for manifests encrypted with SOPS using
+// the sops binary at path. The keys are read from the KMS, Vault or age key
+// configured in the environment of the binary, see https://github.com/mozilla/sops.
+func NewSOPSDecrypter(path string) Decrypter {
+	return &sopsDecrypter{path: path}
+}
+
+type sopsDecrypter struct {
+	path string
+}
+
+// Decrypt decrypts manifests with top-level sops metadata.
+//
+// The MAC of the manifest is not verified, as the order of its keys is lost
+// when the request is decoded. Each value is still authenticated by its own
+// encryption, which includes its path in the manifest.
+func (s *sopsDecrypter) Decrypt(manifest map[string]interface{}) (map[string]interface{}, bool, error) {
+	if _, ok := manifest["sops"].(map[string]interface{}); !ok {
+		return manifest, false, nil
+	}
+
+	decrypted, err := run(s.path, manifest, "--decrypt", "--ignore-mac",
+		"--input-type", "json", "--output-type", "json", "/dev/stdin")
+	if err != nil {
+		return nil, true, fmt.Errorf("error decrypting manifest using sops: %w", err)
+	}
+
+	return decrypted, true, nil
+}
+
+// NewSealedSecretsDecrypter returns a Decrypter that unseals SealedSecrets into
+// Secrets with the private key at privateKeyPath using the kubeseal binary at path,
+// for clusters that do not run the sealed secrets controller.
+func NewSealedSecretsDecrypter(path, privateKeyPath string) Decrypter {
+	return &sealedSecretsDecrypter{
+		path:           path,
+		privateKeyPath: privateKeyPath,
+	}
+}
+
+type sealedSecretsDecrypter struct {
+	path           string
+	privateKeyPath string
+}
+
+func (s *sealedSecretsDecrypter) Decrypt(manifest map[string]interface{}) (map[string]interface{}, bool, error) {
+	kind, _ := manifest["kind"].(string)
+	apiVersion, _ := manifest["apiVersion"].(string)
+
+	if kind != sealedSecretKind || !strings.HasPrefix(apiVersion, sealedSecretGroup+"/") {
+		return manifest, false, nil
+	}
+
+	decrypted, err := run(s.path, manifest, "--recovery-unseal",
+		"--recovery-private-key", s.privateKeyPath, "--format", "json")
+	if err != nil {
+		return nil, true, fmt.Errorf("error unsealing sealed secret using kubeseal: %w", err)
+	}
+
+	return decrypted, true, nil
+}
+
+// run runs a binary with the manifest as its standard input and returns the
+// manifest it writes to standard output.
+func run(path string, manifest map[string]interface{}, args ...string) (map[string]interface{}, error) {
+	b, err := json.Marshal(manifest)
+	if err != nil {
+		return nil, err
+	}
+
+	var stdout, stderr bytes.Buffer
+
+	cmd := exec.Command(path, args...)
+	cmd.Stdin = bytes.NewReader(b)
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+
+	err = cmd.Run()
+	if err != nil {
+		return nil, fmt.Errorf("%w: %s", err, strings.TrimSpace(stderr.String()))
+	}
+
+	decrypted := map[string]interface{}{}
+
+	err = json.Unmarshal(stdout.Bytes(), &decrypted)
+	if err != nil {
+		return nil, err
+	}
+
+	return decrypted, nil
+}
@@ -0,0 +1,13 @@
+package decrypt_test
+
+import (
+	"testing"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+func TestDecrypt(t *testing.T) {
+	RegisterFailHandler(Fail)
+	RunSpecs(t, "Decrypt Suite")
+}
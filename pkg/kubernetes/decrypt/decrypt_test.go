@@ -0,0 +1,134 @@
+package decrypt_test
+
+import (
+	. "github.com/billiford/go-clouddriver/pkg/kubernetes/decrypt"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Decrypt", func() {
+	var (
+		d         Decrypter
+		manifest  map[string]interface{}
+		decrypted map[string]interface{}
+		ok        bool
+		err       error
+	)
+
+	JustBeforeEach(func() {
+		decrypted, ok, err = d.Decrypt(manifest)
+	})
+
+	Describe("#NewSOPSDecrypter", func() {
+		BeforeEach(func() {
+			d = NewSOPSDecrypter("test/sops")
+			manifest = map[string]interface{}{
+				"apiVersion": "v1",
+				"kind":       "Secret",
+				"metadata": map[string]interface{}{
+					"name": "test-secret",
+				},
+				"stringData": map[string]interface{}{
+					"password": "ENC[AES256_GCM,data:abc,iv:def,tag:ghi,type:str]",
+				},
+				"sops": map[string]interface{}{
+					"mac": "ENC[AES256_GCM,data:jkl,iv:mno,tag:pqr,type:str]",
+				},
+			}
+		})
+
+		When("the manifest is not encrypted", func() {
+			BeforeEach(func() {
+				delete(manifest, "sops")
+			})
+
+			It("returns the manifest", func() {
+				Expect(err).To(BeNil())
+				Expect(ok).To(BeFalse())
+				Expect(decrypted).To(Equal(manifest))
+			})
+		})
+
+		When("sops fails", func() {
+			BeforeEach(func() {
+				d = NewSOPSDecrypter("test/fail")
+			})
+
+			It("returns an error", func() {
+				Expect(err).ToNot(BeNil())
+				Expect(ok).To(BeTrue())
+				Expect(err.Error()).To(Equal("error decrypting manifest using sops: exit status 1: failed to get the data key"))
+			})
+		})
+
+		When("the binary does not exist", func() {
+			BeforeEach(func() {
+				d = NewSOPSDecrypter("test/missing")
+			})
+
+			It("returns an error", func() {
+				Expect(err).ToNot(BeNil())
+				Expect(err.Error()).To(HavePrefix("error decrypting manifest using sops: "))
+			})
+		})
+
+		When("it succeeds", func() {
+			It("returns the decrypted manifest", func() {
+				Expect(err).To(BeNil())
+				Expect(ok).To(BeTrue())
+				Expect(decrypted).ToNot(HaveKey("sops"))
+				Expect(decrypted["stringData"]).To(Equal(map[string]interface{}{"password": "hunter2"}))
+			})
+		})
+	})
+
+	Describe("#NewSealedSecretsDecrypter", func() {
+		BeforeEach(func() {
+			d = NewSealedSecretsDecrypter("test/kubeseal", "test/key.pem")
+			manifest = map[string]interface{}{
+				"apiVersion": "bitnami.com/v1alpha1",
+				"kind":       "SealedSecret",
+				"metadata": map[string]interface{}{
+					"name": "test-sealed-secret",
+				},
+				"spec": map[string]interface{}{
+					"encryptedData": map[string]interface{}{
+						"password": "AgBy3i4OJSWK+PiTySYZZA==",
+					},
+				},
+			}
+		})
+
+		When("the manifest is not a sealed secret", func() {
+			BeforeEach(func() {
+				manifest["kind"] = "Secret"
+				manifest["apiVersion"] = "v1"
+			})
+
+			It("returns the manifest", func() {
+				Expect(err).To(BeNil())
+				Expect(ok).To(BeFalse())
+			})
+		})
+
+		When("kubeseal fails", func() {
+			BeforeEach(func() {
+				d = NewSealedSecretsDecrypter("test/fail", "test/key.pem")
+			})
+
+			It("returns an error", func() {
+				Expect(err).ToNot(BeNil())
+				Expect(err.Error()).To(Equal("error unsealing sealed secret using kubeseal: exit status 1: failed to get the data key"))
+			})
+		})
+
+		When("it succeeds", func() {
+			It("returns the secret", func() {
+				Expect(err).To(BeNil())
+				Expect(ok).To(BeTrue())
+				Expect(decrypted["kind"]).To(Equal("Secret"))
+				Expect(decrypted["data"]).To(Equal(map[string]interface{}{"password": "aHVudGVyMg=="}))
+			})
+		})
+	})
+})
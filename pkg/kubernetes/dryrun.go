@@ -0,0 +1,34 @@
+package kubernetes
+
+import (
+	"net/http"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/rest"
+)
+
+// DryRun makes every request of clients created with config that changes
+// a resource a server-side dry-run, so the API server validates the change
+// and runs admission without persisting it. Reads are left as they are.
+func DryRun(config *rest.Config) {
+	config.Wrap(func(rt http.RoundTripper) http.RoundTripper {
+		return &dryRunTransport{rt: rt}
+	})
+}
+
+type dryRunTransport struct {
+	rt http.RoundTripper
+}
+
+func (t *dryRunTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	switch req.Method {
+	case http.MethodPost, http.MethodPut, http.MethodPatch, http.MethodDelete:
+		// Requests must not be modified by a RoundTripper.
+		req = req.Clone(req.Context())
+		q := req.URL.Query()
+		q.Set("dryRun", metav1.DryRunAll)
+		req.URL.RawQuery = q.Encode()
+	}
+
+	return t.rt.RoundTrip(req)
+}
@@ -0,0 +1,60 @@
+package kubernetes_test
+
+import (
+	"net/http"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"github.com/onsi/gomega/ghttp"
+	"k8s.io/client-go/rest"
+
+	. "github.com/billiford/go-clouddriver/pkg/kubernetes"
+)
+
+var _ = Describe("DryRun", func() {
+	var (
+		fakeServer *ghttp.Server
+		method     string
+		res        *http.Response
+		err        error
+	)
+
+	BeforeEach(func() {
+		fakeServer = ghttp.NewServer()
+		fakeServer.AppendHandlers(ghttp.RespondWith(http.StatusOK, "{}"))
+		method = http.MethodPatch
+	})
+
+	AfterEach(func() {
+		fakeServer.Close()
+	})
+
+	JustBeforeEach(func() {
+		config := &rest.Config{Host: fakeServer.URL()}
+		DryRun(config)
+
+		rt := config.WrapTransport(http.DefaultTransport)
+		req, _ := http.NewRequest(method, fakeServer.URL()+"/api/v1/namespaces/default/pods/test?fieldManager=spinnaker", nil)
+		res, err = rt.RoundTrip(req)
+		Expect(err).To(BeNil())
+		res.Body.Close()
+	})
+
+	When("the request changes a resource", func() {
+		It("makes it a dry-run", func() {
+			query := fakeServer.ReceivedRequests()[0].URL.Query()
+			Expect(query.Get("dryRun")).To(Equal("All"))
+			Expect(query.Get("fieldManager")).To(Equal("spinnaker"))
+		})
+	})
+
+	When("the request reads a resource", func() {
+		BeforeEach(func() {
+			method = http.MethodGet
+		})
+
+		It("leaves it as it is", func() {
+			Expect(fakeServer.ReceivedRequests()[0].URL.Query().Get("dryRun")).To(BeEmpty())
+		})
+	})
+})
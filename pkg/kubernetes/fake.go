@@ -0,0 +1,394 @@
+package kubernetes
+
+import (
+	"database/sql/driver"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/rand"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/util/validation"
+	"k8s.io/client-go/rest"
+)
+
+// Defaults of the synthetic cluster of a fake provider.
+const (
+	defaultFakeApplication  = `demo`
+	defaultFakeServerGroups = 2
+	defaultFakeReplicas     = 2
+	fakeNamespace           = `default`
+)
+
+// ProviderFake makes a provider serve a synthetic cluster held in memory
+// instead of a real one, for load testing Gate, Deck and Orca and for demos.
+// The cluster is seeded with a deployment and a service for each
+// application, and serves operations as a cluster would, such as by creating
+// the replica sets and pods of deployments.
+type ProviderFake struct {
+	// Applications seeded in the cluster, demo if not set.
+	Applications []string `json:"applications,omitempty"`
+	// ServerGroups is how many versions of the deployment of each
+	// application are seeded, two if not set.
+	ServerGroups int `json:"serverGroups,omitempty"`
+	// Replicas of the seeded deployments, two if not set.
+	Replicas int `json:"replicas,omitempty"`
+	// Latency is added to each request to the cluster, such as 200ms.
+	Latency string `json:"latency,omitempty"`
+	// FailureRate is the fraction of requests to the cluster that fail,
+	// from 0 to 1, with FailureStatus, 500 if not set.
+	FailureRate   float64 `json:"failureRate,omitempty"`
+	FailureStatus int     `json:"failureStatus,omitempty"`
+}
+
+// Validate returns an error if a setting of the fake is out of range or an
+// application is not a valid name.
+func (pf ProviderFake) Validate() error {
+	for _, application := range pf.Applications {
+		if errs := validation.IsDNS1123Label(application); len(errs) > 0 {
+			return fmt.Errorf("fake application %q is invalid: %s", application, strings.Join(errs, ", "))
+		}
+	}
+
+	if pf.ServerGroups < 0 || pf.Replicas < 0 {
+		return errors.New("fake serverGroups and replicas must not be negative")
+	}
+
+	if pf.Latency != "" {
+		d, err := time.ParseDuration(pf.Latency)
+		if err != nil || d < 0 {
+			return fmt.Errorf("fake latency %q must be a duration, such as 200ms", pf.Latency)
+		}
+	}
+
+	if pf.FailureRate < 0 || pf.FailureRate > 1 {
+		return errors.New("fake failureRate must be from 0 to 1")
+	}
+
+	if pf.FailureStatus != 0 && (pf.FailureStatus < http.StatusBadRequest || pf.FailureStatus > 599) {
+		return fmt.Errorf("fake failureStatus %d must be an error status", pf.FailureStatus)
+	}
+
+	return nil
+}
+
+// Value implements driver.Valuer.
+func (pf ProviderFake) Value() (driver.Value, error) {
+	b, err := json.Marshal(pf)
+	if err != nil {
+		return nil, err
+	}
+
+	return string(b), nil
+}
+
+// Scan implements sql.Scanner.
+func (pf *ProviderFake) Scan(src interface{}) error {
+	var b []byte
+
+	switch v := src.(type) {
+	case nil:
+		*pf = ProviderFake{}
+		return nil
+	case string:
+		b = []byte(v)
+	case []byte:
+		b = v
+	default:
+		return fmt.Errorf("cannot scan %T into provider fake", src)
+	}
+
+	if len(b) == 0 {
+		*pf = ProviderFake{}
+		return nil
+	}
+
+	return json.Unmarshal(b, pf)
+}
+
+func (pf ProviderFake) withDefaults() ProviderFake {
+	if len(pf.Applications) == 0 {
+		pf.Applications = []string{defaultFakeApplication}
+	}
+
+	if pf.ServerGroups == 0 {
+		pf.ServerGroups = defaultFakeServerGroups
+	}
+
+	if pf.Replicas == 0 {
+		pf.Replicas = defaultFakeReplicas
+	}
+
+	if pf.FailureStatus == 0 {
+		pf.FailureStatus = http.StatusInternalServerError
+	}
+
+	return pf
+}
+
+// Resources returns the resources the cluster of the fake is seeded with,
+// which are recorded for the account as if they were deployed so the
+// applications and clusters of the account are listed.
+func (pf ProviderFake) Resources(account string) []Resource {
+	resources := []Resource{}
+
+	for _, application := range pf.withDefaults().Applications {
+		resources = append(resources, Resource{
+			AccountName:  account,
+			APIGroup:     "apps",
+			Name:         application,
+			Namespace:    fakeNamespace,
+			Resource:     "deployments",
+			Version:      "v1",
+			Kind:         "Deployment",
+			SpinnakerApp: application,
+			Cluster:      "deployment " + application,
+		}, Resource{
+			AccountName:  account,
+			Name:         application,
+			Namespace:    fakeNamespace,
+			Resource:     "services",
+			Version:      "v1",
+			Kind:         "Service",
+			SpinnakerApp: application,
+			Cluster:      "service " + application,
+		})
+	}
+
+	return resources
+}
+
+// FakeHost returns the host of the fake provider named name, which never
+// resolves, so requests for the provider cannot reach a real cluster.
+func FakeHost(name string) string {
+	return fmt.Sprintf("https://%s.fake.invalid", strings.ToLower(name))
+}
+
+// ValidateFake returns an error if the fake of the provider is invalid or
+// the provider also has credentials or settings of a real cluster.
+func (p Provider) ValidateFake() error {
+	if p.Fake == nil {
+		return nil
+	}
+
+	if p.Host != "" || p.CAData != "" || p.BearerToken != "" || p.TokenServiceAccount != "" ||
+		p.TokenProvider != nil || p.HasKubeconfig() || p.ClusterCredential != "" ||
+		len(p.Clusters) > 0 || p.Transport != nil {
+		return errors.New("fake providers must not set a host, credentials, clusters or a transport")
+	}
+
+	return p.Fake.Validate()
+}
+
+type fakeClusters struct {
+	mux      sync.Mutex
+	clusters map[string]*fakeCluster
+}
+
+func newFakeClusters() *fakeClusters {
+	return &fakeClusters{clusters: map[string]*fakeCluster{}}
+}
+
+// useFake makes clients created with config make their requests to the
+// synthetic cluster of the fake of p, if it has one. The cluster is seeded
+// the first time it is used and kept for as long as clouddriver runs, so
+// changes made by operations are seen by later requests. Instances of
+// clouddriver each have their own cluster.
+func (c *controller) useFake(p Provider, config *rest.Config) error {
+	if p.Fake == nil {
+		return nil
+	}
+
+	err := p.Fake.Validate()
+	if err != nil {
+		return err
+	}
+
+	pf := p.Fake.withDefaults()
+
+	c.fakes.mux.Lock()
+	defer c.fakes.mux.Unlock()
+
+	fc, ok := c.fakes.clusters[p.Name]
+	if !ok {
+		fc = newFakeCluster()
+
+		err = c.seedFake(fc, pf)
+		if err != nil {
+			return fmt.Errorf("error seeding fake cluster of provider %s: %w", p.Name, err)
+		}
+
+		c.fakes.clusters[p.Name] = fc
+	}
+
+	// The settings of the fake may have changed since the cluster was
+	// seeded, so they are applied to every request.
+	latency, _ := time.ParseDuration(pf.Latency)
+
+	config.Host = FakeHost(p.Name)
+	config.TLSClientConfig = rest.TLSClientConfig{}
+	config.Transport = &fakeTransport{
+		cluster:       fc,
+		latency:       latency,
+		failureRate:   pf.FailureRate,
+		failureStatus: pf.FailureStatus,
+	}
+
+	return nil
+}
+
+// seedFake creates the node, namespace and applications of the cluster of a
+// fake. The deployment of each application is changed once for each of its
+// server groups, so it has a history of replica sets to roll back to.
+func (c *controller) seedFake(fc *fakeCluster, pf ProviderFake) error {
+	fc.mux.Lock()
+	defer fc.mux.Unlock()
+
+	seeds := []*unstructured.Unstructured{
+		{Object: map[string]interface{}{
+			"apiVersion": "v1",
+			"kind":       "Node",
+			"metadata":   map[string]interface{}{"name": fakeNodeName},
+		}},
+		{Object: map[string]interface{}{
+			"apiVersion": "v1",
+			"kind":       "Namespace",
+			"metadata":   map[string]interface{}{"name": fakeNamespace},
+		}},
+	}
+
+	for _, u := range seeds {
+		_, err := fc.create(u, false)
+		if err != nil {
+			return err
+		}
+	}
+
+	for _, application := range pf.Applications {
+		selector := map[string]interface{}{"app": application}
+
+		service := &unstructured.Unstructured{Object: map[string]interface{}{
+			"apiVersion": "v1",
+			"kind":       "Service",
+			"metadata": map[string]interface{}{
+				"name":      application,
+				"namespace": fakeNamespace,
+			},
+			"spec": map[string]interface{}{
+				"selector": selector,
+				"ports": []interface{}{
+					map[string]interface{}{"port": int64(80), "targetPort": int64(8080), "protocol": "TCP"},
+				},
+			},
+		}}
+
+		deployment := &unstructured.Unstructured{Object: map[string]interface{}{
+			"apiVersion": "apps/v1",
+			"kind":       "Deployment",
+			"metadata": map[string]interface{}{
+				"name":      application,
+				"namespace": fakeNamespace,
+			},
+			"spec": map[string]interface{}{
+				"replicas": int64(pf.Replicas),
+				"selector": map[string]interface{}{"matchLabels": selector},
+				"template": map[string]interface{}{
+					"metadata": map[string]interface{}{
+						"labels": map[string]interface{}{"app": application},
+					},
+					"spec": map[string]interface{}{
+						"containers": []interface{}{
+							map[string]interface{}{
+								"name":  application,
+								"image": fakeImage(application, 1),
+								"ports": []interface{}{
+									map[string]interface{}{"containerPort": int64(8080)},
+								},
+							},
+						},
+					},
+				},
+			},
+		}}
+
+		for _, u := range []*unstructured.Unstructured{service, deployment} {
+			err := c.AddSpinnakerAnnotations(u, application)
+			if err != nil {
+				return err
+			}
+
+			err = c.AddSpinnakerLabels(u, application)
+			if err != nil {
+				return err
+			}
+		}
+
+		_, err := fc.create(service, false)
+		if err != nil {
+			return err
+		}
+
+		current, err := fc.create(deployment, false)
+		if err != nil {
+			return err
+		}
+
+		for version := 2; version <= pf.ServerGroups; version++ {
+			containers, _, _ := unstructured.NestedSlice(current.Object, "spec", "template", "spec", "containers")
+			containers[0].(map[string]interface{})["image"] = fakeImage(application, version)
+
+			err = unstructured.SetNestedSlice(current.Object, containers, "spec", "template", "spec", "containers")
+			if err != nil {
+				return err
+			}
+
+			current, err = fc.update(current, false)
+			if err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+func fakeImage(application string, version int) string {
+	return fmt.Sprintf("gcr.io/fake/%s:v%d", application, version)
+}
+
+// fakeTransport serves the requests of clients of a fake provider from its
+// cluster, after waiting for the latency of the fake and failing some of
+// them at its failure rate.
+type fakeTransport struct {
+	cluster       *fakeCluster
+	latency       time.Duration
+	failureRate   float64
+	failureStatus int
+}
+
+func (t *fakeTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	if t.latency > 0 {
+		select {
+		case <-req.Context().Done():
+			return nil, req.Context().Err()
+		case <-time.After(t.latency):
+		}
+	}
+
+	if t.failureRate > 0 && rand.Float64() < t.failureRate {
+		if req.Body != nil {
+			req.Body.Close()
+		}
+
+		return fakeError(req, apierrors.NewGenericServerResponse(t.failureStatus, req.Method, schema.GroupResource{},
+			"", "failure injected by fake provider", 0, true)), nil
+	}
+
+	return t.cluster.serve(req), nil
+}
@@ -0,0 +1,1243 @@
+package kubernetes
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"hash/fnv"
+	"io/ioutil"
+	"net/http"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	jsonpatch "github.com/evanphx/json-patch"
+	"github.com/google/uuid"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/fields"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	utilrand "k8s.io/apimachinery/pkg/util/rand"
+	"k8s.io/apimachinery/pkg/util/strategicpatch"
+	"k8s.io/apimachinery/pkg/version"
+	"k8s.io/client-go/kubernetes/scheme"
+)
+
+const (
+	fakeNodeName           = `fake-node`
+	fakeRevisionLabel      = `controller-revision-hash`
+	fakeRevisionAnnotation = `deployment.kubernetes.io/revision`
+	fakeLogLines           = 10
+)
+
+// fakeResource is a resource served by fake clusters. Each is served at a
+// single version of its group.
+type fakeResource struct {
+	groupVersion string
+	kind         string
+	name         string
+	namespaced   bool
+}
+
+var fakeResources = []fakeResource{
+	{"v1", "ConfigMap", "configmaps", true},
+	{"v1", "Event", "events", true},
+	{"v1", "Namespace", "namespaces", false},
+	{"v1", "Node", "nodes", false},
+	{"v1", "PersistentVolumeClaim", "persistentvolumeclaims", true},
+	{"v1", "Pod", "pods", true},
+	{"v1", "Secret", "secrets", true},
+	{"v1", "Service", "services", true},
+	{"v1", "ServiceAccount", "serviceaccounts", true},
+	{"apps/v1", "DaemonSet", "daemonsets", true},
+	{"apps/v1", "Deployment", "deployments", true},
+	{"apps/v1", "ReplicaSet", "replicasets", true},
+	{"apps/v1", "StatefulSet", "statefulsets", true},
+	{"authorization.k8s.io/v1", "SelfSubjectAccessReview", "selfsubjectaccessreviews", false},
+	{"autoscaling/v1", "HorizontalPodAutoscaler", "horizontalpodautoscalers", true},
+	{"batch/v1", "Job", "jobs", true},
+	{"batch/v1beta1", "CronJob", "cronjobs", true},
+	{"networking.k8s.io/v1", "Ingress", "ingresses", true},
+	{"networking.k8s.io/v1", "NetworkPolicy", "networkpolicies", true},
+	{"policy/v1beta1", "PodDisruptionBudget", "poddisruptionbudgets", true},
+	{"rbac.authorization.k8s.io/v1", "ClusterRole", "clusterroles", false},
+	{"rbac.authorization.k8s.io/v1", "ClusterRoleBinding", "clusterrolebindings", false},
+	{"rbac.authorization.k8s.io/v1", "Role", "roles", true},
+	{"rbac.authorization.k8s.io/v1", "RoleBinding", "rolebindings", true},
+}
+
+func (r fakeResource) group() string {
+	if i := strings.Index(r.groupVersion, "/"); i >= 0 {
+		return r.groupVersion[:i]
+	}
+
+	return ""
+}
+
+func (r fakeResource) groupResource() schema.GroupResource {
+	return schema.GroupResource{Group: r.group(), Resource: r.name}
+}
+
+// key returns the key of an object of the resource in fake clusters, which
+// starts with the prefix of the objects of its namespace.
+func (r fakeResource) key(namespace, name string) string {
+	return r.prefix(namespace) + name
+}
+
+// prefix returns the prefix of the keys of the objects of the resource in a
+// namespace, or in all namespaces if empty.
+func (r fakeResource) prefix(namespace string) string {
+	if !r.namespaced {
+		return r.groupResource().String() + "//"
+	}
+
+	if namespace == "" {
+		return r.groupResource().String() + "/"
+	}
+
+	return r.groupResource().String() + "/" + namespace + "/"
+}
+
+func fakeResourceFor(groupVersion, name string) (fakeResource, bool) {
+	for _, r := range fakeResources {
+		if r.groupVersion == groupVersion && r.name == name {
+			return r, true
+		}
+	}
+
+	return fakeResource{}, false
+}
+
+// fakeResourceOfKind returns the resource of a kind in the group of
+// apiVersion, at whichever version fake clusters serve it.
+func fakeResourceOfKind(apiVersion, kind string) (fakeResource, bool) {
+	gv, err := schema.ParseGroupVersion(apiVersion)
+	if err != nil {
+		return fakeResource{}, false
+	}
+
+	for _, r := range fakeResources {
+		if r.group() == gv.Group && r.kind == kind {
+			return r, true
+		}
+	}
+
+	return fakeResource{}, false
+}
+
+// fakeCluster is a synthetic Kubernetes API server holding its objects in
+// memory. It serves the resources the clients of clouddriver use, and
+// stands in for the controllers of workloads, so deployments get replica
+// sets, workloads get pods and every rollout is immediately ready.
+type fakeCluster struct {
+	mux     sync.Mutex
+	objects map[string]*unstructured.Unstructured
+	version int64
+	// pods counts the pods created, so each has its own IP.
+	pods int64
+}
+
+func newFakeCluster() *fakeCluster {
+	return &fakeCluster{objects: map[string]*unstructured.Unstructured{}}
+}
+
+var fakeVersion = version.Info{
+	Major:      "1",
+	Minor:      "19",
+	GitVersion: "v1.19.2-fake",
+	Platform:   "linux/amd64",
+}
+
+// serve serves a request made to the cluster.
+func (fc *fakeCluster) serve(req *http.Request) *http.Response {
+	var body []byte
+
+	if req.Body != nil {
+		b, err := ioutil.ReadAll(req.Body)
+		req.Body.Close()
+
+		if err != nil {
+			return fakeError(req, apierrors.NewBadRequest(err.Error()))
+		}
+
+		body = b
+	}
+
+	var groupVersion string
+
+	segments := strings.Split(strings.Trim(req.URL.Path, "/"), "/")
+
+	switch {
+	case req.URL.Path == "/version":
+		return fakeResponse(req, http.StatusOK, fakeVersion)
+	case req.URL.Path == "/api":
+		return fakeResponse(req, http.StatusOK, &metav1.APIVersions{
+			TypeMeta: metav1.TypeMeta{Kind: "APIVersions"},
+			Versions: []string{"v1"},
+		})
+	case req.URL.Path == "/apis":
+		return fakeResponse(req, http.StatusOK, fakeAPIGroups())
+	case segments[0] == "api" && len(segments) > 1:
+		groupVersion, segments = segments[1], segments[2:]
+	case segments[0] == "apis" && len(segments) > 2:
+		groupVersion, segments = segments[1]+"/"+segments[2], segments[3:]
+	default:
+		return fakeError(req, apierrors.NewNotFound(schema.GroupResource{}, req.URL.Path))
+	}
+
+	if len(segments) == 0 {
+		resources := fakeAPIResources(groupVersion)
+		if len(resources.APIResources) == 0 {
+			return fakeError(req, apierrors.NewNotFound(schema.GroupResource{}, req.URL.Path))
+		}
+
+		return fakeResponse(req, http.StatusOK, resources)
+	}
+
+	namespace := ""
+	if len(segments) > 2 && segments[0] == "namespaces" {
+		namespace, segments = segments[1], segments[2:]
+	}
+
+	r, ok := fakeResourceFor(groupVersion, segments[0])
+	if !ok || len(segments) > 3 || (namespace != "" && !r.namespaced) {
+		return fakeError(req, apierrors.NewNotFound(schema.GroupResource{Resource: segments[0]}, req.URL.Path))
+	}
+
+	name, subresource := "", ""
+
+	if len(segments) > 1 {
+		name = segments[1]
+	}
+
+	if len(segments) > 2 {
+		subresource = segments[2]
+	}
+
+	dryRun := len(req.URL.Query()["dryRun"]) > 0
+
+	fc.mux.Lock()
+	defer fc.mux.Unlock()
+
+	switch {
+	case subresource == "log" && r.kind == "Pod" && req.Method == http.MethodGet:
+		return fc.logs(req, r, namespace, name)
+	case subresource != "":
+		return fakeError(req, apierrors.NewNotFound(r.groupResource(), name+"/"+subresource))
+	case name == "" && req.Method == http.MethodGet:
+		return fc.list(req, r, namespace)
+	case name == "" && req.Method == http.MethodPost:
+		return fc.post(req, r, namespace, body, dryRun)
+	case name == "":
+		return fakeError(req, apierrors.NewMethodNotSupported(r.groupResource(), req.Method))
+	}
+
+	switch req.Method {
+	case http.MethodGet:
+		u, ok := fc.objects[r.key(namespace, name)]
+		if !ok {
+			return fakeError(req, apierrors.NewNotFound(r.groupResource(), name))
+		}
+
+		return fakeResponse(req, http.StatusOK, fakeRepresentation(req, u, "PartialObjectMetadata"))
+	case http.MethodPut:
+		return fc.put(req, r, namespace, name, body, dryRun)
+	case http.MethodPatch:
+		return fc.patch(req, r, namespace, name, body, dryRun)
+	case http.MethodDelete:
+		return fc.delete(req, r, namespace, name, body, dryRun)
+	}
+
+	return fakeError(req, apierrors.NewMethodNotSupported(r.groupResource(), req.Method))
+}
+
+func (fc *fakeCluster) list(req *http.Request, r fakeResource, namespace string) *http.Response {
+	q := req.URL.Query()
+
+	ls, err := labels.Parse(q.Get("labelSelector"))
+	if err != nil {
+		return fakeError(req, apierrors.NewBadRequest(err.Error()))
+	}
+
+	fs, err := fields.ParseSelector(q.Get("fieldSelector"))
+	if err != nil {
+		return fakeError(req, apierrors.NewBadRequest(err.Error()))
+	}
+
+	prefix := r.prefix(namespace)
+	keys := []string{}
+
+	for key := range fc.objects {
+		if strings.HasPrefix(key, prefix) {
+			keys = append(keys, key)
+		}
+	}
+
+	sort.Strings(keys)
+
+	items := []interface{}{}
+
+	for _, key := range keys {
+		u := fc.objects[key]
+		if ls.Matches(labels.Set(u.GetLabels())) && fs.Matches(fakeFields(u, fs)) {
+			items = append(items, fakeRepresentation(req, u, "PartialObjectMetadataList"))
+		}
+	}
+
+	list := map[string]interface{}{
+		"apiVersion": r.groupVersion,
+		"kind":       r.kind + "List",
+		"metadata":   map[string]interface{}{"resourceVersion": strconv.FormatInt(fc.version, 10)},
+		"items":      items,
+	}
+
+	if fakePartial(req, "PartialObjectMetadataList") {
+		list["apiVersion"] = metav1.SchemeGroupVersion.String()
+		list["kind"] = "PartialObjectMetadataList"
+	}
+
+	return fakeResponse(req, http.StatusOK, list)
+}
+
+func (fc *fakeCluster) post(req *http.Request, r fakeResource, namespace string, body []byte, dryRun bool) *http.Response {
+	u, err := fakeObject(r, body)
+	if err != nil {
+		return fakeError(req, apierrors.NewBadRequest(err.Error()))
+	}
+
+	// Access reviews are never stored, and clients of fake clusters may do
+	// anything.
+	if r.kind == "SelfSubjectAccessReview" {
+		_ = unstructured.SetNestedField(u.Object, true, "status", "allowed")
+		return fakeResponse(req, http.StatusCreated, u.Object)
+	}
+
+	if namespace != "" {
+		u.SetNamespace(namespace)
+	}
+
+	created, err := fc.create(u, dryRun)
+	if err != nil {
+		return fakeError(req, err)
+	}
+
+	return fakeResponse(req, http.StatusCreated, created.Object)
+}
+
+func (fc *fakeCluster) put(req *http.Request, r fakeResource, namespace, name string, body []byte, dryRun bool) *http.Response {
+	u, err := fakeObject(r, body)
+	if err != nil {
+		return fakeError(req, apierrors.NewBadRequest(err.Error()))
+	}
+
+	if u.GetName() != name {
+		return fakeError(req, apierrors.NewBadRequest(fmt.Sprintf("the name of the object (%s) does not match the name on the URL (%s)",
+			u.GetName(), name)))
+	}
+
+	u.SetNamespace(namespace)
+
+	updated, err := fc.update(u, dryRun)
+	if err != nil {
+		return fakeError(req, err)
+	}
+
+	return fakeResponse(req, http.StatusOK, updated.Object)
+}
+
+func (fc *fakeCluster) patch(req *http.Request, r fakeResource, namespace, name string, body []byte, dryRun bool) *http.Response {
+	current, ok := fc.objects[r.key(namespace, name)]
+	if !ok {
+		return fakeError(req, apierrors.NewNotFound(r.groupResource(), name))
+	}
+
+	original, err := current.MarshalJSON()
+	if err != nil {
+		return fakeError(req, apierrors.NewInternalError(err))
+	}
+
+	var patched []byte
+
+	switch types.PatchType(strings.TrimSpace(strings.Split(req.Header.Get("Content-Type"), ";")[0])) {
+	case types.JSONPatchType:
+		var p jsonpatch.Patch
+
+		p, err = jsonpatch.DecodePatch(body)
+		if err == nil {
+			patched, err = p.Apply(original)
+		}
+	case types.MergePatchType:
+		patched, err = jsonpatch.MergePatch(original, body)
+	case types.StrategicMergePatchType:
+		// Kinds without a Go type, such as custom resources, cannot be
+		// patched strategically and are merged.
+		typed, e := scheme.Scheme.New(current.GroupVersionKind())
+		if e != nil {
+			patched, err = jsonpatch.MergePatch(original, body)
+		} else {
+			patched, err = strategicpatch.StrategicMergePatch(original, body, typed)
+		}
+	default:
+		return fakeError(req, apierrors.NewGenericServerResponse(http.StatusUnsupportedMediaType, "patch",
+			r.groupResource(), name, "unsupported patch type "+req.Header.Get("Content-Type"), 0, false))
+	}
+
+	if err != nil {
+		return fakeError(req, apierrors.NewBadRequest(err.Error()))
+	}
+
+	u := &unstructured.Unstructured{}
+
+	err = u.UnmarshalJSON(patched)
+	if err != nil {
+		return fakeError(req, apierrors.NewBadRequest(err.Error()))
+	}
+
+	updated, err := fc.update(u, dryRun)
+	if err != nil {
+		return fakeError(req, err)
+	}
+
+	return fakeResponse(req, http.StatusOK, updated.Object)
+}
+
+func (fc *fakeCluster) delete(req *http.Request, r fakeResource, namespace, name string, body []byte, dryRun bool) *http.Response {
+	u, ok := fc.objects[r.key(namespace, name)]
+	if !ok {
+		return fakeError(req, apierrors.NewNotFound(r.groupResource(), name))
+	}
+
+	do := metav1.DeleteOptions{}
+	if len(body) > 0 {
+		_ = json.Unmarshal(body, &do)
+	}
+
+	if !dryRun {
+		orphan := do.PropagationPolicy != nil && *do.PropagationPolicy == metav1.DeletePropagationOrphan
+		fc.remove(r, u, orphan)
+	}
+
+	return fakeResponse(req, http.StatusOK, &metav1.Status{
+		TypeMeta: metav1.TypeMeta{Kind: "Status", APIVersion: "v1"},
+		Status:   metav1.StatusSuccess,
+		Details: &metav1.StatusDetails{
+			Name:  name,
+			Group: r.group(),
+			Kind:  r.name,
+			UID:   u.GetUID(),
+		},
+	})
+}
+
+func (fc *fakeCluster) logs(req *http.Request, r fakeResource, namespace, name string) *http.Response {
+	u, ok := fc.objects[r.key(namespace, name)]
+	if !ok {
+		return fakeError(req, apierrors.NewNotFound(r.groupResource(), name))
+	}
+
+	container := req.URL.Query().Get("container")
+	if container == "" {
+		containers, _, _ := unstructured.NestedSlice(u.Object, "spec", "containers")
+		if len(containers) > 0 {
+			container, _, _ = unstructured.NestedString(containers[0].(map[string]interface{}), "name")
+		}
+	}
+
+	b := &bytes.Buffer{}
+	start := u.GetCreationTimestamp().Time
+
+	for i := 1; i <= fakeLogLines; i++ {
+		fmt.Fprintf(b, "%s fake log line %d of container %s of pod %s\n",
+			start.Add(time.Duration(i)*time.Second).UTC().Format(time.RFC3339), i, container, name)
+	}
+
+	return &http.Response{
+		Status:        fmt.Sprintf("%d %s", http.StatusOK, http.StatusText(http.StatusOK)),
+		StatusCode:    http.StatusOK,
+		Proto:         "HTTP/1.1",
+		ProtoMajor:    1,
+		ProtoMinor:    1,
+		Header:        http.Header{"Content-Type": {"text/plain"}},
+		Body:          ioutil.NopCloser(b),
+		ContentLength: int64(b.Len()),
+		Request:       req,
+	}
+}
+
+// create stores a new object. Objects are created in the default namespace
+// if they do not set one, which must exist. Dry-run objects are returned as
+// they would be created without storing them.
+func (fc *fakeCluster) create(u *unstructured.Unstructured, dryRun bool) (*unstructured.Unstructured, error) {
+	r, ok := fakeResourceOfKind(u.GetAPIVersion(), u.GetKind())
+	if !ok {
+		return nil, apierrors.NewBadRequest(fmt.Sprintf("fake clusters do not serve %s %s", u.GetAPIVersion(), u.GetKind()))
+	}
+
+	u = u.DeepCopy()
+	u.SetAPIVersion(r.groupVersion)
+
+	if u.GetName() == "" && u.GetGenerateName() != "" {
+		u.SetName(u.GetGenerateName() + utilrand.String(5))
+	}
+
+	if u.GetName() == "" {
+		return nil, apierrors.NewBadRequest("name is required")
+	}
+
+	if !r.namespaced {
+		u.SetNamespace("")
+	} else {
+		if u.GetNamespace() == "" {
+			u.SetNamespace(fakeNamespace)
+		}
+
+		namespaces, _ := fakeResourceFor("v1", "namespaces")
+		if _, ok := fc.objects[namespaces.key("", u.GetNamespace())]; !ok {
+			return nil, apierrors.NewNotFound(namespaces.groupResource(), u.GetNamespace())
+		}
+	}
+
+	if _, ok := fc.objects[r.key(u.GetNamespace(), u.GetName())]; ok {
+		return nil, apierrors.NewAlreadyExists(r.groupResource(), u.GetName())
+	}
+
+	u.SetUID(types.UID(uuid.New().String()))
+	u.SetCreationTimestamp(metav1.Now())
+	u.SetGeneration(1)
+
+	if dryRun {
+		return u, nil
+	}
+
+	return fc.save(r, u)
+}
+
+// update replaces an object, keeping its status, which only the cluster
+// changes. Its generation is incremented if its spec changed.
+func (fc *fakeCluster) update(u *unstructured.Unstructured, dryRun bool) (*unstructured.Unstructured, error) {
+	r, ok := fakeResourceOfKind(u.GetAPIVersion(), u.GetKind())
+	if !ok {
+		return nil, apierrors.NewBadRequest(fmt.Sprintf("fake clusters do not serve %s %s", u.GetAPIVersion(), u.GetKind()))
+	}
+
+	current, ok := fc.objects[r.key(u.GetNamespace(), u.GetName())]
+	if !ok {
+		return nil, apierrors.NewNotFound(r.groupResource(), u.GetName())
+	}
+
+	if rv := u.GetResourceVersion(); rv != "" && rv != current.GetResourceVersion() {
+		return nil, apierrors.NewConflict(r.groupResource(), u.GetName(),
+			errors.New("the object has been modified; please apply your changes to the latest version and try again"))
+	}
+
+	u = u.DeepCopy()
+	u.SetAPIVersion(r.groupVersion)
+	u.SetNamespace(current.GetNamespace())
+	u.SetUID(current.GetUID())
+	u.SetCreationTimestamp(current.GetCreationTimestamp())
+
+	generation := current.GetGeneration()
+	if !reflect.DeepEqual(current.Object["spec"], u.Object["spec"]) {
+		generation++
+	}
+
+	u.SetGeneration(generation)
+
+	delete(u.Object, "status")
+
+	if status, ok := current.Object["status"]; ok {
+		u.Object["status"] = runtime.DeepCopyJSONValue(status)
+	}
+
+	if dryRun {
+		return u, nil
+	}
+
+	return fc.save(r, u)
+}
+
+// save brings an object to the state the cluster it is in would, and
+// stores it.
+func (fc *fakeCluster) save(r fakeResource, u *unstructured.Unstructured) (*unstructured.Unstructured, error) {
+	err := fc.reconcile(r, u)
+	if err != nil {
+		return nil, apierrors.NewInternalError(err)
+	}
+
+	fc.version++
+	u.SetResourceVersion(strconv.FormatInt(fc.version, 10))
+	fc.objects[r.key(u.GetNamespace(), u.GetName())] = u
+
+	return u.DeepCopy(), nil
+}
+
+// remove deletes an object and, as the garbage collector would unless the
+// deletion orphans them, the objects it owns. Deleting a namespace deletes
+// every object in it.
+func (fc *fakeCluster) remove(r fakeResource, u *unstructured.Unstructured, orphan bool) {
+	delete(fc.objects, r.key(u.GetNamespace(), u.GetName()))
+
+	dependents := []*unstructured.Unstructured{}
+
+	for _, o := range fc.objects {
+		if r.kind == "Namespace" && o.GetNamespace() == u.GetName() {
+			dependents = append(dependents, o)
+			continue
+		}
+
+		if orphan {
+			continue
+		}
+
+		for _, ref := range o.GetOwnerReferences() {
+			if ref.UID == u.GetUID() {
+				dependents = append(dependents, o)
+				break
+			}
+		}
+	}
+
+	for _, o := range dependents {
+		if or, ok := fakeResourceOfKind(o.GetAPIVersion(), o.GetKind()); ok {
+			fc.remove(or, o, false)
+		}
+	}
+}
+
+// owned returns the objects of a kind in the namespace of owner that owner
+// controls, sorted by name.
+func (fc *fakeCluster) owned(owner *unstructured.Unstructured, kind string) []*unstructured.Unstructured {
+	owned := []*unstructured.Unstructured{}
+
+	for _, o := range fc.objects {
+		if o.GetKind() != kind || o.GetNamespace() != owner.GetNamespace() {
+			continue
+		}
+
+		if ref := metav1.GetControllerOf(o); ref != nil && ref.UID == owner.GetUID() {
+			owned = append(owned, o)
+		}
+	}
+
+	sort.Slice(owned, func(i, j int) bool {
+		return owned[i].GetName() < owned[j].GetName()
+	})
+
+	return owned
+}
+
+// reconcile does what the controllers of a cluster would for an object
+// about to be stored, such as creating the pods of a workload and setting
+// its status.
+func (fc *fakeCluster) reconcile(r fakeResource, u *unstructured.Unstructured) error {
+	now := time.Now().UTC().Format(time.RFC3339)
+
+	switch r.kind {
+	case "Namespace":
+		return unstructured.SetNestedField(u.Object, "Active", "status", "phase")
+	case "Node":
+		return unstructured.SetNestedField(u.Object, map[string]interface{}{
+			"conditions": []interface{}{fakeCondition("Ready", "KubeletReady", "kubelet is posting ready status", now)},
+			"nodeInfo":   map[string]interface{}{"kubeletVersion": fakeVersion.GitVersion},
+		}, "status")
+	case "Service":
+		if ip, _, _ := unstructured.NestedString(u.Object, "spec", "clusterIP"); ip == "" {
+			return unstructured.SetNestedField(u.Object, fmt.Sprintf("10.96.%d.%d", fc.version/250%250, fc.version%250+1),
+				"spec", "clusterIP")
+		}
+	case "Deployment":
+		return fc.reconcileDeployment(u, now)
+	case "ReplicaSet":
+		replicas := fakeReplicas(u)
+
+		err := fc.ensurePods(u, replicas, false, nil, false)
+		if err != nil {
+			return err
+		}
+
+		return unstructured.SetNestedField(u.Object, map[string]interface{}{
+			"observedGeneration":   u.GetGeneration(),
+			"replicas":             replicas,
+			"fullyLabeledReplicas": replicas,
+			"readyReplicas":        replicas,
+			"availableReplicas":    replicas,
+		}, "status")
+	case "StatefulSet":
+		replicas := fakeReplicas(u)
+		revision := u.GetName() + "-" + fakeTemplateHash(u)
+
+		err := fc.ensurePods(u, replicas, true, map[string]string{fakeRevisionLabel: revision}, false)
+		if err != nil {
+			return err
+		}
+
+		return unstructured.SetNestedField(u.Object, map[string]interface{}{
+			"observedGeneration": u.GetGeneration(),
+			"replicas":           replicas,
+			"readyReplicas":      replicas,
+			"currentReplicas":    replicas,
+			"updatedReplicas":    replicas,
+			"currentRevision":    revision,
+			"updateRevision":     revision,
+		}, "status")
+	case "DaemonSet":
+		// Fake clusters have a single node.
+		revision := u.GetName() + "-" + fakeTemplateHash(u)
+
+		err := fc.ensurePods(u, 1, false, map[string]string{fakeRevisionLabel: revision}, false)
+		if err != nil {
+			return err
+		}
+
+		return unstructured.SetNestedField(u.Object, map[string]interface{}{
+			"observedGeneration":     u.GetGeneration(),
+			"currentNumberScheduled": int64(1),
+			"desiredNumberScheduled": int64(1),
+			"numberAvailable":        int64(1),
+			"numberMisscheduled":     int64(0),
+			"numberReady":            int64(1),
+			"updatedNumberScheduled": int64(1),
+		}, "status")
+	case "Job":
+		// Jobs complete as soon as they are created, and are not run again.
+		if _, ok := u.Object["status"]; ok {
+			return nil
+		}
+
+		err := fc.ensurePods(u, 1, false, map[string]string{
+			"job-name":       u.GetName(),
+			"controller-uid": string(u.GetUID()),
+		}, true)
+		if err != nil {
+			return err
+		}
+
+		return unstructured.SetNestedField(u.Object, map[string]interface{}{
+			"startTime":      now,
+			"completionTime": now,
+			"succeeded":      int64(1),
+			"conditions": []interface{}{map[string]interface{}{
+				"type":               "Complete",
+				"status":             "True",
+				"lastProbeTime":      now,
+				"lastTransitionTime": now,
+			}},
+		}, "status")
+	}
+
+	return nil
+}
+
+// reconcileDeployment rolls a deployment out to the replica set of its
+// template, creating one for a new template, and scales its other replica
+// sets to zero. The revision of a replica set rolled out again, such as by
+// an undo, becomes the latest.
+func (fc *fakeCluster) reconcileDeployment(d *unstructured.Unstructured, now string) error {
+	replicas := fakeReplicas(d)
+	hash := fakeTemplateHash(d)
+	name := d.GetName() + "-" + hash
+	revision := 0
+
+	var current *unstructured.Unstructured
+
+	for _, rs := range fc.owned(d, "ReplicaSet") {
+		if r := fakeRevision(rs); r > revision {
+			revision = r
+		}
+
+		if rs.GetName() == name {
+			current = rs
+			continue
+		}
+
+		if fakeReplicas(rs) != 0 {
+			rs = rs.DeepCopy()
+
+			err := unstructured.SetNestedField(rs.Object, int64(0), "spec", "replicas")
+			if err != nil {
+				return err
+			}
+
+			_, err = fc.update(rs, false)
+			if err != nil {
+				return err
+			}
+		}
+	}
+
+	annotations := map[string]string{}
+
+	for k, v := range d.GetAnnotations() {
+		if k != "kubectl.kubernetes.io/last-applied-configuration" {
+			annotations[k] = v
+		}
+	}
+
+	if current == nil || fakeRevision(current) < revision {
+		revision++
+	} else {
+		revision = fakeRevision(current)
+	}
+
+	annotations[fakeRevisionAnnotation] = strconv.Itoa(revision)
+
+	var err error
+
+	if current == nil {
+		template, _, _ := unstructured.NestedMap(d.Object, "spec", "template")
+		selector, _, _ := unstructured.NestedMap(d.Object, "spec", "selector")
+
+		podLabels, _, _ := unstructured.NestedStringMap(template, "metadata", "labels")
+		if podLabels == nil {
+			podLabels = map[string]string{}
+		}
+
+		podLabels["pod-template-hash"] = hash
+
+		spec := map[string]interface{}{"template": template}
+		if selector != nil {
+			spec["selector"] = selector
+		}
+
+		current = &unstructured.Unstructured{Object: map[string]interface{}{
+			"apiVersion": "apps/v1",
+			"kind":       "ReplicaSet",
+			"spec":       spec,
+		}}
+		current.SetName(name)
+		current.SetNamespace(d.GetNamespace())
+		current.SetLabels(podLabels)
+		current.SetOwnerReferences([]metav1.OwnerReference{fakeOwnerReference(d)})
+
+		err = unstructured.SetNestedStringMap(current.Object, podLabels, "spec", "template", "metadata", "labels")
+		if err != nil {
+			return err
+		}
+
+		err = unstructured.SetNestedField(current.Object, hash, "spec", "selector", "matchLabels", "pod-template-hash")
+		if err != nil {
+			return err
+		}
+	} else {
+		current = current.DeepCopy()
+	}
+
+	current.SetAnnotations(annotations)
+
+	err = unstructured.SetNestedField(current.Object, replicas, "spec", "replicas")
+	if err != nil {
+		return err
+	}
+
+	if current.GetUID() == "" {
+		_, err = fc.create(current, false)
+	} else {
+		_, err = fc.update(current, false)
+	}
+
+	if err != nil {
+		return err
+	}
+
+	deploymentAnnotations := d.GetAnnotations()
+	if deploymentAnnotations == nil {
+		deploymentAnnotations = map[string]string{}
+	}
+
+	deploymentAnnotations[fakeRevisionAnnotation] = strconv.Itoa(revision)
+	d.SetAnnotations(deploymentAnnotations)
+
+	return unstructured.SetNestedField(d.Object, map[string]interface{}{
+		"observedGeneration": d.GetGeneration(),
+		"replicas":           replicas,
+		"updatedReplicas":    replicas,
+		"readyReplicas":      replicas,
+		"availableReplicas":  replicas,
+		"conditions": []interface{}{
+			fakeCondition("Available", "MinimumReplicasAvailable", "Deployment has minimum availability.", now),
+			fakeCondition("Progressing", "NewReplicaSetAvailable",
+				fmt.Sprintf("ReplicaSet %q has successfully progressed.", name), now),
+		},
+	}, "status")
+}
+
+// ensurePods makes owner own replicas pods of its template, deleting pods
+// without the labels of podLabels so they are replaced. Pods are named by
+// their ordinal if ordinal is true, as those of stateful sets are.
+// Completed pods have run to completion, as those of jobs.
+func (fc *fakeCluster) ensurePods(owner *unstructured.Unstructured, replicas int64, ordinal bool,
+	podLabels map[string]string, completed bool) error {
+	pods, _ := fakeResourceFor("v1", "pods")
+	kept := []*unstructured.Unstructured{}
+
+	for _, pod := range fc.owned(owner, "Pod") {
+		stale := false
+
+		for k, v := range podLabels {
+			if pod.GetLabels()[k] != v {
+				stale = true
+			}
+		}
+
+		if stale || int64(len(kept)) >= replicas {
+			fc.remove(pods, pod, false)
+			continue
+		}
+
+		kept = append(kept, pod)
+	}
+
+	names := map[string]bool{}
+	for _, pod := range kept {
+		names[pod.GetName()] = true
+	}
+
+	for i := 0; int64(len(kept)) < replicas; i++ {
+		name := owner.GetName() + "-" + utilrand.String(5)
+		if ordinal {
+			name = fmt.Sprintf("%s-%d", owner.GetName(), i)
+		}
+
+		if names[name] {
+			continue
+		}
+
+		pod, err := fc.create(fc.newPod(owner, name, podLabels, completed), false)
+		if err != nil {
+			return err
+		}
+
+		names[name] = true
+		kept = append(kept, pod)
+	}
+
+	return nil
+}
+
+// newPod returns a pod of the template of owner scheduled to the node of the
+// cluster, with all of its containers running, or terminated if completed.
+func (fc *fakeCluster) newPod(owner *unstructured.Unstructured, name string, podLabels map[string]string,
+	completed bool) *unstructured.Unstructured {
+	now := time.Now().UTC().Format(time.RFC3339)
+	template, _, _ := unstructured.NestedMap(owner.Object, "spec", "template")
+	spec, _, _ := unstructured.NestedMap(template, "spec")
+
+	if spec == nil {
+		spec = map[string]interface{}{}
+	}
+
+	spec["nodeName"] = fakeNodeName
+
+	containers, _, _ := unstructured.NestedSlice(spec, "containers")
+	statuses := []interface{}{}
+
+	for _, c := range containers {
+		container, _ := c.(map[string]interface{})
+		image, _, _ := unstructured.NestedString(container, "image")
+		state := map[string]interface{}{"running": map[string]interface{}{"startedAt": now}}
+
+		if completed {
+			state = map[string]interface{}{"terminated": map[string]interface{}{
+				"exitCode":   int64(0),
+				"reason":     "Completed",
+				"startedAt":  now,
+				"finishedAt": now,
+			}}
+		}
+
+		statuses = append(statuses, map[string]interface{}{
+			"name":         container["name"],
+			"image":        image,
+			"imageID":      "docker-pullable://" + image,
+			"ready":        !completed,
+			"started":      !completed,
+			"restartCount": int64(0),
+			"state":        state,
+		})
+	}
+
+	phase, ready := "Running", fakeCondition("Ready", "", "", now)
+	if completed {
+		phase, ready = "Succeeded", map[string]interface{}{
+			"type":               "Ready",
+			"status":             "False",
+			"reason":             "PodCompleted",
+			"lastTransitionTime": now,
+		}
+	}
+
+	fc.pods++
+
+	pod := &unstructured.Unstructured{Object: map[string]interface{}{
+		"apiVersion": "v1",
+		"kind":       "Pod",
+		"spec":       spec,
+		"status": map[string]interface{}{
+			"phase":    phase,
+			"hostIP":   "10.0.0.1",
+			"podIP":    fmt.Sprintf("10.244.%d.%d", fc.pods/250%250, fc.pods%250+1),
+			"qosClass": "BestEffort",
+			"conditions": []interface{}{
+				fakeCondition("Initialized", "", "", now),
+				ready,
+				fakeCondition("PodScheduled", "", "", now),
+			},
+			"containerStatuses": statuses,
+			"startTime":         now,
+		},
+	}}
+
+	labels, _, _ := unstructured.NestedStringMap(template, "metadata", "labels")
+	if labels == nil {
+		labels = map[string]string{}
+	}
+
+	for k, v := range podLabels {
+		labels[k] = v
+	}
+
+	annotations, _, _ := unstructured.NestedStringMap(template, "metadata", "annotations")
+
+	pod.SetName(name)
+	pod.SetNamespace(owner.GetNamespace())
+	pod.SetLabels(labels)
+	pod.SetAnnotations(annotations)
+	pod.SetOwnerReferences([]metav1.OwnerReference{fakeOwnerReference(owner)})
+
+	return pod
+}
+
+func fakeOwnerReference(owner *unstructured.Unstructured) metav1.OwnerReference {
+	controller := true
+
+	return metav1.OwnerReference{
+		APIVersion:         owner.GetAPIVersion(),
+		Kind:               owner.GetKind(),
+		Name:               owner.GetName(),
+		UID:                owner.GetUID(),
+		Controller:         &controller,
+		BlockOwnerDeletion: &controller,
+	}
+}
+
+func fakeCondition(t, reason, message, now string) map[string]interface{} {
+	condition := map[string]interface{}{
+		"type":               t,
+		"status":             "True",
+		"lastTransitionTime": now,
+	}
+
+	if reason != "" {
+		condition["reason"] = reason
+		condition["message"] = message
+		condition["lastUpdateTime"] = now
+	}
+
+	return condition
+}
+
+// fakeReplicas returns the replicas of the spec of a workload, one if not set.
+// fakeReplicas returns the replicas of a workload, which are a float64 in
+// objects that were marshalled to JSON and back, such as when annotated.
+func fakeReplicas(u *unstructured.Unstructured) int64 {
+	replicas, _, _ := unstructured.NestedFieldNoCopy(u.Object, "spec", "replicas")
+
+	switch r := replicas.(type) {
+	case int64:
+		return r
+	case float64:
+		return int64(r)
+	default:
+		return 1
+	}
+}
+
+func fakeRevision(u *unstructured.Unstructured) int {
+	revision, _ := strconv.Atoi(u.GetAnnotations()[fakeRevisionAnnotation])
+	return revision
+}
+
+// fakeTemplateHash hashes the pod template of a workload, as its controller
+// would to name its replica sets or revisions.
+func fakeTemplateHash(u *unstructured.Unstructured) string {
+	template, _, _ := unstructured.NestedFieldNoCopy(u.Object, "spec", "template")
+	b, _ := json.Marshal(template)
+
+	h := fnv.New32a()
+	h.Write(b)
+
+	return utilrand.SafeEncodeString(strconv.FormatUint(uint64(h.Sum32()), 10))
+}
+
+// fakeObject decodes the object of a request, of the kind of r whichever
+// version it was sent as.
+func fakeObject(r fakeResource, body []byte) (*unstructured.Unstructured, error) {
+	u := &unstructured.Unstructured{}
+
+	err := u.UnmarshalJSON(body)
+	if err != nil {
+		return nil, err
+	}
+
+	if u.GetKind() != r.kind {
+		return nil, fmt.Errorf("kind %s of the object does not match the resource %s", u.GetKind(), r.name)
+	}
+
+	return u, nil
+}
+
+// fakeFields returns the fields of an object a field selector selects by,
+// such as status.phase.
+func fakeFields(u *unstructured.Unstructured, fs fields.Selector) fields.Set {
+	set := fields.Set{}
+
+	for _, r := range fs.Requirements() {
+		v, found, _ := unstructured.NestedFieldNoCopy(u.Object, strings.Split(r.Field, ".")...)
+		if found {
+			set[r.Field] = fmt.Sprint(v)
+		}
+	}
+
+	return set
+}
+
+// fakePartial returns true if a request asks for only the metadata of
+// objects, as a kind such as PartialObjectMetadataList.
+func fakePartial(req *http.Request, kind string) bool {
+	for _, accept := range strings.Split(req.Header.Get("Accept"), ",") {
+		for _, param := range strings.Split(accept, ";") {
+			if strings.TrimSpace(param) == "as="+kind {
+				return true
+			}
+		}
+	}
+
+	return false
+}
+
+// fakeRepresentation returns an object as a request asks for it, which is
+// only its metadata for requests for kind.
+func fakeRepresentation(req *http.Request, u *unstructured.Unstructured, kind string) map[string]interface{} {
+	if !fakePartial(req, kind) {
+		return u.Object
+	}
+
+	return map[string]interface{}{
+		"apiVersion": metav1.SchemeGroupVersion.String(),
+		"kind":       "PartialObjectMetadata",
+		"metadata":   u.Object["metadata"],
+	}
+}
+
+func fakeAPIGroups() *metav1.APIGroupList {
+	list := &metav1.APIGroupList{TypeMeta: metav1.TypeMeta{Kind: "APIGroupList", APIVersion: "v1"}}
+	versions := map[string]bool{}
+
+	for _, r := range fakeResources {
+		group := r.group()
+		if group == "" || versions[r.groupVersion] {
+			continue
+		}
+
+		versions[r.groupVersion] = true
+		gv := metav1.GroupVersionForDiscovery{
+			GroupVersion: r.groupVersion,
+			Version:      strings.TrimPrefix(r.groupVersion, group+"/"),
+		}
+
+		// Resources are listed by group, and the first version of a group
+		// is preferred.
+		if n := len(list.Groups); n > 0 && list.Groups[n-1].Name == group {
+			list.Groups[n-1].Versions = append(list.Groups[n-1].Versions, gv)
+			continue
+		}
+
+		list.Groups = append(list.Groups, metav1.APIGroup{
+			Name:             group,
+			Versions:         []metav1.GroupVersionForDiscovery{gv},
+			PreferredVersion: gv,
+		})
+	}
+
+	return list
+}
+
+func fakeAPIResources(groupVersion string) *metav1.APIResourceList {
+	list := &metav1.APIResourceList{
+		TypeMeta:     metav1.TypeMeta{Kind: "APIResourceList", APIVersion: "v1"},
+		GroupVersion: groupVersion,
+	}
+
+	for _, r := range fakeResources {
+		if r.groupVersion != groupVersion {
+			continue
+		}
+
+		verbs := metav1.Verbs{"create", "delete", "get", "list", "patch", "update"}
+		if r.kind == "SelfSubjectAccessReview" {
+			verbs = metav1.Verbs{"create"}
+		}
+
+		list.APIResources = append(list.APIResources, metav1.APIResource{
+			Name:         r.name,
+			SingularName: strings.ToLower(r.kind),
+			Namespaced:   r.namespaced,
+			Kind:         r.kind,
+			Verbs:        verbs,
+		})
+
+		if r.kind == "Pod" {
+			list.APIResources = append(list.APIResources, metav1.APIResource{
+				Name:       "pods/log",
+				Namespaced: true,
+				Kind:       "Pod",
+				Verbs:      metav1.Verbs{"get"},
+			})
+		}
+	}
+
+	return list
+}
+
+func fakeResponse(req *http.Request, code int, v interface{}) *http.Response {
+	b, err := json.Marshal(v)
+	if err != nil {
+		code = http.StatusInternalServerError
+		b = []byte(fmt.Sprintf(`{"kind":"Status","apiVersion":"v1","status":"Failure","message":%q,"code":500}`, err.Error()))
+	}
+
+	return &http.Response{
+		Status:        fmt.Sprintf("%d %s", code, http.StatusText(code)),
+		StatusCode:    code,
+		Proto:         "HTTP/1.1",
+		ProtoMajor:    1,
+		ProtoMinor:    1,
+		Header:        http.Header{"Content-Type": {"application/json"}},
+		Body:          ioutil.NopCloser(bytes.NewReader(b)),
+		ContentLength: int64(len(b)),
+		Request:       req,
+	}
+}
+
+// fakeError responds with the status of err, or an internal error if it is
+// not an API error.
+func fakeError(req *http.Request, err error) *http.Response {
+	var se *apierrors.StatusError
+	if !errors.As(err, &se) {
+		se = apierrors.NewInternalError(err)
+	}
+
+	status := se.ErrStatus
+	status.Kind = "Status"
+	status.APIVersion = "v1"
+
+	return fakeResponse(req, int(status.Code), &status)
+}
@@ -0,0 +1,226 @@
+package kubernetes_test
+
+import (
+	"context"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/discovery"
+	"k8s.io/client-go/dynamic"
+	"k8s.io/client-go/metadata"
+	"k8s.io/client-go/rest"
+
+	. "github.com/billiford/go-clouddriver/pkg/kubernetes"
+)
+
+var (
+	deploymentsGVR = schema.GroupVersionResource{Group: "apps", Version: "v1", Resource: "deployments"}
+	replicaSetsGVR = schema.GroupVersionResource{Group: "apps", Version: "v1", Resource: "replicasets"}
+	podsGVR        = schema.GroupVersionResource{Version: "v1", Resource: "pods"}
+)
+
+var _ = Describe("Fake", func() {
+	var (
+		kc       Controller
+		provider Provider
+		config   *rest.Config
+		client   dynamic.Interface
+		err      error
+	)
+
+	Describe("#Validate", func() {
+		It("accepts settings in range and no settings", func() {
+			Expect(ProviderFake{}.Validate()).To(Succeed())
+			Expect(ProviderFake{
+				Applications:  []string{"app-a", "app-b"},
+				ServerGroups:  3,
+				Replicas:      1,
+				Latency:       "200ms",
+				FailureRate:   0.1,
+				FailureStatus: 503,
+			}.Validate()).To(Succeed())
+		})
+
+		It("rejects settings out of range", func() {
+			Expect(ProviderFake{Applications: []string{"App_A"}}.Validate()).ToNot(Succeed())
+			Expect(ProviderFake{Replicas: -1}.Validate()).ToNot(Succeed())
+			Expect(ProviderFake{Latency: "slow"}.Validate()).To(MatchError(`fake latency "slow" must be a duration, such as 200ms`))
+			Expect(ProviderFake{FailureRate: 1.5}.Validate()).To(MatchError("fake failureRate must be from 0 to 1"))
+			Expect(ProviderFake{FailureStatus: 200}.Validate()).ToNot(Succeed())
+		})
+
+		It("rejects fake providers with a host", func() {
+			p := Provider{Host: "https://test-host", Fake: &ProviderFake{}}
+			Expect(p.ValidateFake()).To(MatchError("fake providers must not set a host, credentials, clusters or a transport"))
+		})
+	})
+
+	It("is stored as JSON", func() {
+		pf := ProviderFake{Applications: []string{"app-a"}, Latency: "1s", FailureRate: 0.5}
+		v, err := pf.Value()
+		Expect(err).To(BeNil())
+
+		scanned := ProviderFake{}
+		Expect(scanned.Scan(v)).To(Succeed())
+		Expect(scanned).To(Equal(pf))
+	})
+
+	It("lists the resources it is seeded with", func() {
+		resources := ProviderFake{}.Resources("test-account")
+		Expect(resources).To(HaveLen(2))
+		Expect(resources[0].AccountName).To(Equal("test-account"))
+		Expect(resources[0].Kind).To(Equal("Deployment"))
+		Expect(resources[0].SpinnakerApp).To(Equal("demo"))
+		Expect(resources[0].Cluster).To(Equal("deployment demo"))
+		Expect(resources[1].Kind).To(Equal("Service"))
+	})
+
+	Describe("clients of a fake provider", func() {
+		BeforeEach(func() {
+			kc = NewController()
+			provider = Provider{
+				Name: "test-account",
+				Fake: &ProviderFake{},
+			}
+			config = &rest.Config{BearerToken: "arcade-token"}
+		})
+
+		JustBeforeEach(func() {
+			err = kc.MintToken(provider, config)
+			Expect(err).To(BeNil())
+
+			client, err = dynamic.NewForConfig(config)
+			Expect(err).To(BeNil())
+		})
+
+		It("serve the synthetic cluster", func() {
+			Expect(config.Host).To(Equal("https://test-account.fake.invalid"))
+
+			dc, err := discovery.NewDiscoveryClientForConfig(config)
+			Expect(err).To(BeNil())
+			info, err := dc.ServerVersion()
+			Expect(err).To(BeNil())
+			Expect(info.GitVersion).To(Equal("v1.19.2-fake"))
+
+			_, resources, err := dc.ServerGroupsAndResources()
+			Expect(err).To(BeNil())
+			Expect(resources).ToNot(BeEmpty())
+		})
+
+		It("list the seeded applications", func() {
+			deployments, err := client.Resource(deploymentsGVR).Namespace("default").List(context.TODO(), metav1.ListOptions{
+				LabelSelector: ManagedApplicationLabelSelector("demo"),
+			})
+			Expect(err).To(BeNil())
+			Expect(deployments.Items).To(HaveLen(1))
+			Expect(deployments.Items[0].GetAnnotations()["deployment.kubernetes.io/revision"]).To(Equal("2"))
+
+			replicaSets, err := client.Resource(replicaSetsGVR).Namespace("default").List(context.TODO(), metav1.ListOptions{})
+			Expect(err).To(BeNil())
+			Expect(replicaSets.Items).To(HaveLen(2))
+
+			replicas := []int64{}
+			for _, rs := range replicaSets.Items {
+				r, _, _ := unstructured.NestedInt64(rs.Object, "spec", "replicas")
+				replicas = append(replicas, r)
+			}
+			Expect(replicas).To(ConsistOf(int64(0), int64(2)))
+
+			pods, err := client.Resource(podsGVR).List(context.TODO(), metav1.ListOptions{
+				FieldSelector: "status.phase=Running",
+			})
+			Expect(err).To(BeNil())
+			Expect(pods.Items).To(HaveLen(2))
+		})
+
+		It("list the metadata of namespaces", func() {
+			mc, err := metadata.NewForConfig(config)
+			Expect(err).To(BeNil())
+
+			namespaces, err := mc.Resource(schema.GroupVersionResource{Version: "v1", Resource: "namespaces"}).
+				List(context.TODO(), metav1.ListOptions{})
+			Expect(err).To(BeNil())
+			Expect(namespaces.Items).To(HaveLen(1))
+			Expect(namespaces.Items[0].GetName()).To(Equal("default"))
+		})
+
+		It("roll out patched deployments", func() {
+			patch := []byte(`{"spec":{"template":{"spec":{"containers":[{"name":"demo","image":"gcr.io/fake/demo:v3"}]}}}}`)
+			d, err := client.Resource(deploymentsGVR).Namespace("default").
+				Patch(context.TODO(), "demo", types.StrategicMergePatchType, patch, metav1.PatchOptions{})
+			Expect(err).To(BeNil())
+			Expect(d.GetGeneration()).To(Equal(int64(3)))
+			Expect(d.GetAnnotations()["deployment.kubernetes.io/revision"]).To(Equal("3"))
+
+			ready, _, _ := unstructured.NestedInt64(d.Object, "status", "readyReplicas")
+			Expect(ready).To(Equal(int64(2)))
+
+			replicaSets, err := client.Resource(replicaSetsGVR).Namespace("default").List(context.TODO(), metav1.ListOptions{})
+			Expect(err).To(BeNil())
+			Expect(replicaSets.Items).To(HaveLen(3))
+		})
+
+		It("delete the replica sets and pods of deleted deployments", func() {
+			err := client.Resource(deploymentsGVR).Namespace("default").Delete(context.TODO(), "demo", metav1.DeleteOptions{})
+			Expect(err).To(BeNil())
+
+			replicaSets, err := client.Resource(replicaSetsGVR).Namespace("default").List(context.TODO(), metav1.ListOptions{})
+			Expect(err).To(BeNil())
+			Expect(replicaSets.Items).To(BeEmpty())
+
+			pods, err := client.Resource(podsGVR).Namespace("default").List(context.TODO(), metav1.ListOptions{})
+			Expect(err).To(BeNil())
+			Expect(pods.Items).To(BeEmpty())
+		})
+
+		It("reject objects in namespaces that do not exist", func() {
+			u := &unstructured.Unstructured{Object: map[string]interface{}{
+				"apiVersion": "v1",
+				"kind":       "ConfigMap",
+				"metadata":   map[string]interface{}{"name": "test-config"},
+			}}
+			_, err := client.Resource(schema.GroupVersionResource{Version: "v1", Resource: "configmaps"}).
+				Namespace("missing").Create(context.TODO(), u, metav1.CreateOptions{})
+			Expect(apierrors.IsNotFound(err)).To(BeTrue())
+		})
+
+		When("another client is created for the provider", func() {
+			It("sees the changes of the first", func() {
+				err := client.Resource(deploymentsGVR).Namespace("default").Delete(context.TODO(), "demo", metav1.DeleteOptions{})
+				Expect(err).To(BeNil())
+
+				other := &rest.Config{}
+				Expect(kc.MintToken(provider, other)).To(Succeed())
+				oc, err := dynamic.NewForConfig(other)
+				Expect(err).To(BeNil())
+
+				_, err = oc.Resource(deploymentsGVR).Namespace("default").Get(context.TODO(), "demo", metav1.GetOptions{})
+				Expect(apierrors.IsNotFound(err)).To(BeTrue())
+			})
+		})
+
+		When("the fake fails every request", func() {
+			BeforeEach(func() {
+				provider.Fake.FailureRate = 1
+				provider.Fake.FailureStatus = 503
+			})
+
+			It("returns the failure status", func() {
+				_, err := client.Resource(deploymentsGVR).Namespace("default").Get(context.TODO(), "demo", metav1.GetOptions{})
+				Expect(apierrors.IsServiceUnavailable(err)).To(BeTrue())
+			})
+		})
+
+		When("the fake is invalid", func() {
+			It("returns an error", func() {
+				provider.Fake.Latency = "slow"
+				Expect(kc.MintToken(provider, &rest.Config{})).ToNot(Succeed())
+			})
+		})
+	})
+})
@@ -0,0 +1,438 @@
+package kubernetes
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log"
+	"sync"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/version"
+	"k8s.io/client-go/rest"
+)
+
+// NewProviderClient mints a token for config, the config of the primary
+// cluster of a provider, and returns a client of it. If the provider has other
+// clusters, the client reads from all of them with a copy of config for each:
+// lists are merged and gets return the resource from the first cluster that
+// has it, the primary cluster first. Everything else uses the primary cluster.
+// If the provider is scoped to namespaces, the client only reads and changes
+// resources in them. Changes of providers in dryRun write mode are server-side
+// dry-runs.
+func NewProviderClient(kc Controller, p Provider, config *rest.Config) (Client, error) {
+	client, err := newFederatedClient(kc, p, config)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(p.Namespaces) > 0 {
+		return &scopedClient{client: client, p: p}, nil
+	}
+
+	return client, nil
+}
+
+func newFederatedClient(kc Controller, p Provider, config *rest.Config) (Client, error) {
+	// Mint tokens of other clusters with the credentials config came with.
+	bootstrap := rest.CopyConfig(config)
+
+	err := kc.MintToken(p, config)
+	if err != nil {
+		return nil, err
+	}
+
+	// Tokens are minted before requests are made dry-runs, or minting them
+	// would be one.
+	if p.DryRun() {
+		DryRun(config)
+	}
+
+	client, err := kc.NewClient(config)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(p.Clusters) == 0 {
+		return client, nil
+	}
+
+	fc := &federatedClient{
+		names:   []string{PrimaryCluster},
+		clients: []Client{client},
+		threads: p.CacheThreadCount(),
+	}
+
+	for _, pc := range p.Clusters {
+		cd, err := base64.StdEncoding.DecodeString(pc.CAData)
+		if err != nil {
+			return nil, fmt.Errorf("error decoding ca data of cluster %s: %w", pc.Name, err)
+		}
+
+		member := rest.CopyConfig(bootstrap)
+		member.Host = pc.Host
+		member.CAData = cd
+
+		err = kc.MintToken(p, member)
+		if err != nil {
+			return nil, err
+		}
+
+		if p.DryRun() {
+			DryRun(member)
+		}
+
+		c, err := kc.NewClient(member)
+		if err != nil {
+			return nil, err
+		}
+
+		fc.names = append(fc.names, pc.Name)
+		fc.clients = append(fc.clients, c)
+	}
+
+	return fc, nil
+}
+
+// federatedClient reads from the clusters of a provider. The first client is
+// of the primary cluster. Lists are made of up to threads clusters at once.
+type federatedClient struct {
+	names   []string
+	clients []Client
+	threads int
+}
+
+func (f *federatedClient) primary() Client {
+	return f.clients[0]
+}
+
+func (f *federatedClient) Apply(u *unstructured.Unstructured) (Metadata, error) {
+	return f.primary().Apply(u)
+}
+
+func (f *federatedClient) ApplyWithNamespaceOverride(u *unstructured.Unstructured, namespace string) (Metadata, error) {
+	return f.primary().ApplyWithNamespaceOverride(u, namespace)
+}
+
+func (f *federatedClient) CanI(verb, group, resource, namespace string) (bool, error) {
+	return f.primary().CanI(verb, group, resource, namespace)
+}
+
+func (f *federatedClient) DeleteResourceByKindAndNameAndNamespace(kind, name, namespace string, do metav1.DeleteOptions) error {
+	return f.primary().DeleteResourceByKindAndNameAndNamespace(kind, name, namespace, do)
+}
+
+func (f *federatedClient) GVRForKind(kind string) (schema.GroupVersionResource, error) {
+	return f.primary().GVRForKind(kind)
+}
+
+// Get returns the resource from the first cluster that has it. If no cluster
+// has it, a not found error is only returned if every cluster could be read.
+func (f *federatedClient) Get(kind, name, namespace string) (*unstructured.Unstructured, error) {
+	var first error
+
+	for _, c := range f.clients {
+		u, err := c.Get(kind, name, namespace)
+		if err == nil {
+			return u, nil
+		}
+
+		if first == nil || !errors.IsNotFound(err) && errors.IsNotFound(first) {
+			first = err
+		}
+	}
+
+	return nil, first
+}
+
+func (f *federatedClient) InvalidateDiscovery() {
+	for _, c := range f.clients {
+		c.InvalidateDiscovery()
+	}
+}
+
+func (f *federatedClient) ListByGVR(gvr schema.GroupVersionResource, lo metav1.ListOptions) (*unstructured.UnstructuredList, error) {
+	return f.mergeUnstructured(lo, func(c Client, lo metav1.ListOptions) (*unstructured.UnstructuredList, error) {
+		return c.ListByGVR(gvr, lo)
+	})
+}
+
+func (f *federatedClient) ListMetadataByGVR(gvr schema.GroupVersionResource, lo metav1.ListOptions) (*metav1.PartialObjectMetadataList, error) {
+	var (
+		merged *metav1.PartialObjectMetadataList
+		first  error
+	)
+
+	seen := map[string]bool{}
+	tokens := make([]string, len(f.clients))
+
+	results, errs, err := f.listEach(lo, func(c Client, lo metav1.ListOptions) (interface{}, error) {
+		return c.ListMetadataByGVR(gvr, lo)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	for i, err := range errs {
+		if err != nil {
+			log.Println("[FEDERATION] error listing", gvr.Resource, "of cluster", f.names[i]+":", err.Error())
+
+			if first == nil {
+				first = err
+			}
+
+			continue
+		}
+
+		if results[i] == nil {
+			continue
+		}
+
+		l := results[i].(*metav1.PartialObjectMetadataList)
+		tokens[i] = l.Continue
+
+		if merged == nil {
+			merged = l.DeepCopy()
+			merged.Items = nil
+		}
+
+		for _, item := range l.Items {
+			key := item.Kind + " " + item.Namespace + " " + item.Name
+			if !seen[key] {
+				seen[key] = true
+				merged.Items = append(merged.Items, item)
+			}
+		}
+	}
+
+	if merged == nil {
+		return nil, first
+	}
+
+	merged.Continue = f.continueToken(tokens)
+	merged.RemainingItemCount = nil
+
+	return merged, nil
+}
+
+func (f *federatedClient) ListResource(resource string, lo metav1.ListOptions) (*unstructured.UnstructuredList, error) {
+	return f.mergeUnstructured(lo, func(c Client, lo metav1.ListOptions) (*unstructured.UnstructuredList, error) {
+		return c.ListResource(resource, lo)
+	})
+}
+
+// mergeUnstructured merges the lists of every cluster. A resource listed by
+// more than one cluster, such as one being migrated, is only returned from
+// the first. Clusters that fail are skipped, unless every cluster fails.
+func (f *federatedClient) mergeUnstructured(lo metav1.ListOptions,
+	list func(Client, metav1.ListOptions) (*unstructured.UnstructuredList, error)) (*unstructured.UnstructuredList, error) {
+	var (
+		merged *unstructured.UnstructuredList
+		first  error
+	)
+
+	seen := map[string]bool{}
+	tokens := make([]string, len(f.clients))
+
+	results, errs, err := f.listEach(lo, func(c Client, lo metav1.ListOptions) (interface{}, error) {
+		return list(c, lo)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	for i, err := range errs {
+		if err != nil {
+			log.Println("[FEDERATION] error listing resources of cluster", f.names[i]+":", err.Error())
+
+			if first == nil {
+				first = err
+			}
+
+			continue
+		}
+
+		if results[i] == nil {
+			continue
+		}
+
+		l := results[i].(*unstructured.UnstructuredList)
+		tokens[i] = l.GetContinue()
+
+		if merged == nil {
+			merged = &unstructured.UnstructuredList{Object: listObject(l.Object)}
+		}
+
+		for _, item := range l.Items {
+			key := item.GetKind() + " " + item.GetNamespace() + " " + item.GetName()
+			if !seen[key] {
+				seen[key] = true
+				merged.Items = append(merged.Items, item)
+			}
+		}
+	}
+
+	if merged == nil {
+		return nil, first
+	}
+
+	if token := f.continueToken(tokens); token != "" {
+		merged.SetContinue(token)
+	}
+
+	return merged, nil
+}
+
+// listObject returns a copy of the fields of a list, such as its kind and
+// resource version, without the continue token and remaining item count of
+// the cluster it was listed from.
+func listObject(object map[string]interface{}) map[string]interface{} {
+	if object == nil {
+		return nil
+	}
+
+	o := map[string]interface{}{}
+	for k, v := range object {
+		o[k] = v
+	}
+
+	if metadata, ok := object["metadata"].(map[string]interface{}); ok {
+		m := map[string]interface{}{}
+		for k, v := range metadata {
+			if k != "continue" && k != "remainingItemCount" {
+				m[k] = v
+			}
+		}
+
+		o["metadata"] = m
+	}
+
+	return o
+}
+
+// listEach calls list with each client and its list options, up to threads
+// at once, and returns what each returned in the order of the clients.
+//
+// Each cluster is paginated on its own, so a page lists up to lo.Limit items
+// of every cluster. The continue token of a federated list holds the continue
+// token of each cluster with more to list, and clusters that have been listed
+// to the end are not listed again; their results are nil.
+func (f *federatedClient) listEach(lo metav1.ListOptions,
+	list func(Client, metav1.ListOptions) (interface{}, error)) ([]interface{}, []error, error) {
+	los := make([]*metav1.ListOptions, len(f.clients))
+
+	if lo.Continue == "" {
+		for i := range los {
+			o := lo
+			los[i] = &o
+		}
+	} else {
+		tokens := map[string]string{}
+
+		b, err := base64.RawURLEncoding.DecodeString(lo.Continue)
+		if err == nil {
+			err = json.Unmarshal(b, &tokens)
+		}
+
+		if err != nil {
+			return nil, nil, errors.NewBadRequest(fmt.Sprintf("invalid continue token of federated list: %v", err))
+		}
+
+		for i, name := range f.names {
+			if token, ok := tokens[name]; ok {
+				o := lo
+				o.Continue = token
+				los[i] = &o
+			}
+		}
+	}
+
+	results := make([]interface{}, len(f.clients))
+	errs := make([]error, len(f.clients))
+	threads := make(chan struct{}, f.threads)
+	wg := &sync.WaitGroup{}
+
+	for i, c := range f.clients {
+		if los[i] == nil {
+			continue
+		}
+
+		wg.Add(1)
+
+		threads <- struct{}{}
+
+		go func(i int, c Client) {
+			defer wg.Done()
+			defer func() { <-threads }()
+
+			results[i], errs[i] = list(c, *los[i])
+		}(i, c)
+	}
+
+	wg.Wait()
+
+	return results, errs, nil
+}
+
+// continueToken returns the continue token of a federated list from the
+// continue token each cluster returned, which is empty once no cluster has
+// more to list. Clusters that failed have no token, so are not listed again.
+func (f *federatedClient) continueToken(tokens []string) string {
+	cont := map[string]string{}
+
+	for i, token := range tokens {
+		if token != "" {
+			cont[f.names[i]] = token
+		}
+	}
+
+	if len(cont) == 0 {
+		return ""
+	}
+
+	b, _ := json.Marshal(cont)
+
+	return base64.RawURLEncoding.EncodeToString(b)
+}
+
+func (f *federatedClient) NamespaceScoped(kind string) (bool, error) {
+	return f.primary().NamespaceScoped(kind)
+}
+
+func (f *federatedClient) Patch(kind, name, namespace string, p []byte) (Metadata, *unstructured.Unstructured, error) {
+	return f.primary().Patch(kind, name, namespace, p)
+}
+
+func (f *federatedClient) PatchUsingStrategy(kind, name, namespace string, p []byte,
+	strategy types.PatchType) (Metadata, *unstructured.Unstructured, error) {
+	return f.primary().PatchUsingStrategy(kind, name, namespace, p, strategy)
+}
+
+func (f *federatedClient) ServerVersion() (*version.Info, error) {
+	return f.primary().ServerVersion()
+}
+
+// StreamLogs streams the logs of a pod from the first cluster that has it.
+func (f *federatedClient) StreamLogs(ctx context.Context, name, namespace string, plo corev1.PodLogOptions) (io.ReadCloser, error) {
+	var first error
+
+	for _, c := range f.clients {
+		rc, err := c.StreamLogs(ctx, name, namespace, plo)
+		if err == nil {
+			return rc, nil
+		}
+
+		if first == nil {
+			first = err
+		}
+	}
+
+	return nil, first
+}
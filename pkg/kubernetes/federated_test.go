@@ -0,0 +1,247 @@
+package kubernetes_test
+
+import (
+	"encoding/base64"
+	"errors"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/rest"
+
+	. "github.com/billiford/go-clouddriver/pkg/kubernetes"
+	"github.com/billiford/go-clouddriver/pkg/kubernetes/kubernetesfakes"
+)
+
+var _ = Describe("NewProviderClient", func() {
+	var (
+		fakeController *kubernetesfakes.FakeController
+		primary        *kubernetesfakes.FakeClient
+		standby        *kubernetesfakes.FakeClient
+		provider       Provider
+		config         *rest.Config
+		client         Client
+		err            error
+	)
+
+	newList := func(names ...string) *unstructured.UnstructuredList {
+		l := &unstructured.UnstructuredList{}
+
+		for _, name := range names {
+			u := unstructured.Unstructured{}
+			u.SetKind("Deployment")
+			u.SetNamespace("default")
+			u.SetName(name)
+			l.Items = append(l.Items, u)
+		}
+
+		return l
+	}
+
+	BeforeEach(func() {
+		fakeController = &kubernetesfakes.FakeController{}
+		primary = &kubernetesfakes.FakeClient{}
+		standby = &kubernetesfakes.FakeClient{}
+		fakeController.NewClientReturnsOnCall(0, primary, nil)
+		fakeController.NewClientReturnsOnCall(1, standby, nil)
+		provider = Provider{
+			Name: "test-account",
+			Host: "https://primary",
+			Clusters: ProviderClusters{
+				{
+					Name:   "standby",
+					Host:   "https://standby",
+					CAData: base64.StdEncoding.EncodeToString([]byte("standby-ca")),
+				},
+			},
+		}
+		config = &rest.Config{Host: "https://primary", BearerToken: "token"}
+	})
+
+	JustBeforeEach(func() {
+		client, err = NewProviderClient(fakeController, provider, config)
+	})
+
+	When("the provider has no other clusters", func() {
+		BeforeEach(func() {
+			provider.Clusters = nil
+		})
+
+		It("returns the client of the primary cluster", func() {
+			Expect(err).To(BeNil())
+			Expect(client).To(BeIdenticalTo(primary))
+			Expect(fakeController.MintTokenCallCount()).To(Equal(1))
+		})
+	})
+
+	When("minting a token fails", func() {
+		BeforeEach(func() {
+			fakeController.MintTokenReturns(errors.New("error minting token"))
+		})
+
+		It("returns an error", func() {
+			Expect(err).To(MatchError("error minting token"))
+		})
+	})
+
+	When("the CA data of a cluster is invalid", func() {
+		BeforeEach(func() {
+			provider.Clusters[0].CAData = "{}"
+		})
+
+		It("returns an error", func() {
+			Expect(err).ToNot(BeNil())
+			Expect(err.Error()).To(HavePrefix("error decoding ca data of cluster standby"))
+		})
+	})
+
+	It("creates a client of each cluster", func() {
+		Expect(err).To(BeNil())
+		Expect(fakeController.NewClientCallCount()).To(Equal(2))
+		member := fakeController.NewClientArgsForCall(1)
+		Expect(member.Host).To(Equal("https://standby"))
+		Expect(string(member.CAData)).To(Equal("standby-ca"))
+		Expect(member.BearerToken).To(Equal("token"))
+	})
+
+	Describe("#ListResource", func() {
+		var (
+			list    *unstructured.UnstructuredList
+			listErr error
+		)
+
+		BeforeEach(func() {
+			primary.ListResourceReturns(newList("app-a", "app-b"), nil)
+			standby.ListResourceReturns(newList("app-b", "app-c"), nil)
+		})
+
+		JustBeforeEach(func() {
+			list, listErr = client.ListResource("deployments", metav1.ListOptions{})
+		})
+
+		It("merges the lists, keeping resources of the first cluster", func() {
+			Expect(listErr).To(BeNil())
+			Expect(list.Items).To(HaveLen(3))
+			Expect(list.Items[0].GetName()).To(Equal("app-a"))
+			Expect(list.Items[1].GetName()).To(Equal("app-b"))
+			Expect(list.Items[2].GetName()).To(Equal("app-c"))
+		})
+
+		When("a cluster fails", func() {
+			BeforeEach(func() {
+				standby.ListResourceReturns(nil, errors.New("dial tcp: i/o timeout"))
+			})
+
+			It("lists the other clusters", func() {
+				Expect(listErr).To(BeNil())
+				Expect(list.Items).To(HaveLen(2))
+			})
+		})
+
+		When("the clusters are listed at once", func() {
+			BeforeEach(func() {
+				provider.CacheThreads = 2
+			})
+
+			It("merges the lists in the order of the clusters", func() {
+				Expect(listErr).To(BeNil())
+				Expect(list.Items).To(HaveLen(3))
+				Expect(list.Items[0].GetName()).To(Equal("app-a"))
+				Expect(list.Items[2].GetName()).To(Equal("app-c"))
+				Expect(primary.ListResourceCallCount()).To(Equal(1))
+				Expect(standby.ListResourceCallCount()).To(Equal(1))
+			})
+		})
+
+		When("the clusters are paginated", func() {
+			BeforeEach(func() {
+				page := newList("app-a")
+				page.SetContinue("primary-token")
+				primary.ListResourceReturnsOnCall(0, page, nil)
+				primary.ListResourceReturnsOnCall(1, newList("app-b"), nil)
+				standby.ListResourceReturns(newList("app-c"), nil)
+			})
+
+			It("continues each cluster on its own", func() {
+				Expect(listErr).To(BeNil())
+				Expect(list.Items).To(HaveLen(2))
+				Expect(list.GetContinue()).ToNot(BeEmpty())
+
+				next, err := client.ListResource("deployments", metav1.ListOptions{Continue: list.GetContinue()})
+				Expect(err).To(BeNil())
+				Expect(next.Items).To(HaveLen(1))
+				Expect(next.Items[0].GetName()).To(Equal("app-b"))
+				Expect(next.GetContinue()).To(BeEmpty())
+				_, lo := primary.ListResourceArgsForCall(1)
+				Expect(lo.Continue).To(Equal("primary-token"))
+				Expect(standby.ListResourceCallCount()).To(Equal(1))
+			})
+		})
+
+		When("the continue token is invalid", func() {
+			It("returns an error", func() {
+				_, err := client.ListResource("deployments", metav1.ListOptions{Continue: "primary-token"})
+				Expect(err).ToNot(BeNil())
+				Expect(err.Error()).To(HavePrefix("invalid continue token of federated list"))
+			})
+		})
+
+		When("every cluster fails", func() {
+			BeforeEach(func() {
+				primary.ListResourceReturns(nil, errors.New("error listing primary"))
+				standby.ListResourceReturns(nil, errors.New("error listing standby"))
+			})
+
+			It("returns the first error", func() {
+				Expect(listErr).To(MatchError("error listing primary"))
+			})
+		})
+	})
+
+	Describe("#Get", func() {
+		var (
+			u      *unstructured.Unstructured
+			getErr error
+		)
+
+		notFound := k8serrors.NewNotFound(schema.GroupResource{Resource: "deployments"}, "app-c")
+
+		BeforeEach(func() {
+			primary.GetReturns(nil, notFound)
+			standby.GetReturns(&newList("app-c").Items[0], nil)
+		})
+
+		JustBeforeEach(func() {
+			u, getErr = client.Get("deployment", "app-c", "default")
+		})
+
+		It("returns the resource from the first cluster that has it", func() {
+			Expect(getErr).To(BeNil())
+			Expect(u.GetName()).To(Equal("app-c"))
+		})
+
+		When("another cluster cannot be read", func() {
+			BeforeEach(func() {
+				standby.GetReturns(nil, errors.New("dial tcp: i/o timeout"))
+			})
+
+			It("returns its error instead of not found", func() {
+				Expect(getErr).To(MatchError("dial tcp: i/o timeout"))
+			})
+		})
+	})
+
+	Describe("writes", func() {
+		JustBeforeEach(func() {
+			_, _ = client.Apply(&unstructured.Unstructured{})
+		})
+
+		It("go to the primary cluster", func() {
+			Expect(primary.ApplyCallCount()).To(Equal(1))
+			Expect(standby.ApplyCallCount()).To(BeZero())
+		})
+	})
+})
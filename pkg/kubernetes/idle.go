@@ -0,0 +1,34 @@
+package kubernetes
+
+import (
+	"context"
+	"log"
+	"time"
+)
+
+// DefaultIdleTimeout is how long the state of a cluster is kept after it was
+// last used, if not configured.
+const DefaultIdleTimeout = time.Hour
+
+// RunIdleEviction evicts the state of clusters that have not been used for
+// idle from kc every idle until the context is done.
+func RunIdleEviction(ctx context.Context, kc Controller, idle time.Duration) {
+	if idle <= 0 {
+		idle = DefaultIdleTimeout
+	}
+
+	ticker := time.NewTicker(idle)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+
+		if evicted := kc.EvictIdle(idle); evicted > 0 {
+			log.Println("[KUBERNETES] evicted", evicted, "idle tokens, transports and request counts")
+		}
+	}
+}
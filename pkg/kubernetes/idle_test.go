@@ -0,0 +1,58 @@
+package kubernetes_test
+
+import (
+	"context"
+	"io/ioutil"
+	"log"
+	"time"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	. "github.com/billiford/go-clouddriver/pkg/kubernetes"
+	"github.com/billiford/go-clouddriver/pkg/kubernetes/kubernetesfakes"
+)
+
+var _ = Describe("RunIdleEviction", func() {
+	var (
+		fakeController *kubernetesfakes.FakeController
+		cancel         context.CancelFunc
+		done           chan struct{}
+	)
+
+	BeforeEach(func() {
+		log.SetOutput(ioutil.Discard)
+
+		fakeController = &kubernetesfakes.FakeController{}
+		fakeController.EvictIdleReturns(2)
+
+		var ctx context.Context
+		ctx, cancel = context.WithCancel(context.Background())
+		done = make(chan struct{})
+
+		go func() {
+			defer close(done)
+			RunIdleEviction(ctx, fakeController, 10*time.Millisecond)
+		}()
+	})
+
+	AfterEach(func() {
+		cancel()
+		Eventually(done).Should(BeClosed())
+	})
+
+	It("evicts idle state every idle timeout", func() {
+		Eventually(fakeController.EvictIdleCallCount).Should(BeNumerically(">=", 2))
+		Expect(fakeController.EvictIdleArgsForCall(0)).To(Equal(10 * time.Millisecond))
+	})
+
+	When("the context is done", func() {
+		BeforeEach(func() {
+			cancel()
+		})
+
+		It("stops", func() {
+			Eventually(done).Should(BeClosed())
+		})
+	})
+})
@@ -0,0 +1,61 @@
+package kubernetes
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// KindCategories are the Spinnaker resource categories Deck shows kinds under.
+var KindCategories = []string{
+	"configs",
+	"instances",
+	"loadBalancers",
+	"securityGroups",
+	"serverGroupManagers",
+	"serverGroups",
+	"unclassified",
+}
+
+// KindMapping maps a kind of a provider, such as rollout for Argo Rollouts,
+// to the category Deck shows it under, such as serverGroups, overriding the
+// default kind map in /credentials.
+type KindMapping struct {
+	ID          string `json:"-" gorm:"primary_key"`
+	AccountName string `json:"accountName" gorm:"index"`
+	Kind        string `json:"kind"`
+	Category    string `json:"category"`
+}
+
+func (KindMapping) TableName() string {
+	return "provider_kind_mappings"
+}
+
+// ValidateKindMap returns an error if a kind of the provider is mapped to an
+// unknown category.
+func (p Provider) ValidateKindMap() error {
+	for kind, category := range p.KindMap {
+		err := ValidateKindMapping(kind, category)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// ValidateKindMapping returns an error if kind is empty or category is not
+// one of KindCategories.
+func ValidateKindMapping(kind, category string) error {
+	if kind == "" {
+		return fmt.Errorf("kind mapped to %s must not be empty", category)
+	}
+
+	i := sort.SearchStrings(KindCategories, category)
+	if i == len(KindCategories) || KindCategories[i] != category {
+		return fmt.Errorf("unknown category %q of kind %s, must be one of %s",
+			category, kind, strings.Join(KindCategories, ", "))
+	}
+
+	return nil
+}
@@ -0,0 +1,109 @@
+package kubernetes
+
+import (
+	"errors"
+	"fmt"
+
+	"k8s.io/client-go/rest"
+	"k8s.io/client-go/tools/clientcmd"
+	clientcmdapi "k8s.io/client-go/tools/clientcmd/api"
+)
+
+// HasKubeconfig returns true if the host and credentials of the provider are
+// those of a kubeconfig.
+func (p Provider) HasKubeconfig() bool {
+	return p.KubeconfigContents != "" || p.KubeconfigFile != ""
+}
+
+// ValidateKubeconfig returns an error if the provider has both kubeconfig
+// contents and a kubeconfig file, or a kubeconfig and credentials of its own.
+func (p Provider) ValidateKubeconfig() error {
+	if !p.HasKubeconfig() {
+		if p.KubeconfigContext != "" {
+			return errors.New("kubeconfigContext must only be set with kubeconfigContents or kubeconfigFile")
+		}
+
+		return nil
+	}
+
+	if p.KubeconfigContents != "" && p.KubeconfigFile != "" {
+		return errors.New("only one of kubeconfigContents and kubeconfigFile must be set")
+	}
+
+	if p.Host != "" || p.CAData != "" || p.BearerToken != "" || p.ClusterCredential != "" {
+		return errors.New("providers with a kubeconfig must not set a host, caData, bearerToken or clusterCredential")
+	}
+
+	return nil
+}
+
+// KubeconfigConfig returns the config of the context of the kubeconfig of
+// the provider, which is its current context unless KubeconfigContext is set.
+// Files the kubeconfig refers to, such as client certificates, are read
+// relative to KubeconfigFile.
+func (p Provider) KubeconfigConfig() (*rest.Config, error) {
+	var (
+		kubeconfig *clientcmdapi.Config
+		err        error
+	)
+
+	if p.KubeconfigFile != "" {
+		kubeconfig, err = clientcmd.LoadFromFile(p.KubeconfigFile)
+		if err == nil {
+			// Paths of certificates and keys are relative to the file.
+			err = clientcmd.ResolveLocalPaths(kubeconfig)
+		}
+	} else {
+		kubeconfig, err = clientcmd.Load([]byte(p.KubeconfigContents))
+	}
+
+	if err != nil {
+		return nil, fmt.Errorf("error loading kubeconfig: %w", err)
+	}
+
+	config, err := clientcmd.NewNonInteractiveClientConfig(*kubeconfig, p.KubeconfigContext,
+		&clientcmd.ConfigOverrides{}, nil).ClientConfig()
+	if err != nil {
+		return nil, fmt.Errorf("error loading kubeconfig: %w", err)
+	}
+
+	return config, nil
+}
+
+// useKubeconfig replaces the credentials of config with those of the
+// kubeconfig of p, such as client certificates or an exec plugin, if it has
+// one. Configs of other clusters of the provider keep their host and CA data.
+func useKubeconfig(p Provider, config *rest.Config) error {
+	if !p.HasKubeconfig() {
+		return nil
+	}
+
+	kubeconfig, err := p.KubeconfigConfig()
+	if err != nil {
+		return err
+	}
+
+	if config.Host == "" || config.Host == kubeconfig.Host {
+		config.Host = kubeconfig.Host
+		config.APIPath = kubeconfig.APIPath
+		config.CAData = kubeconfig.CAData
+		config.CAFile = kubeconfig.CAFile
+		config.ServerName = kubeconfig.ServerName
+		config.Insecure = kubeconfig.Insecure
+	}
+
+	config.CertData = kubeconfig.CertData
+	config.CertFile = kubeconfig.CertFile
+	config.KeyData = kubeconfig.KeyData
+	config.KeyFile = kubeconfig.KeyFile
+	config.BearerToken = kubeconfig.BearerToken
+	config.BearerTokenFile = kubeconfig.BearerTokenFile
+	config.Username = kubeconfig.Username
+	config.Password = kubeconfig.Password
+	config.Impersonate = kubeconfig.Impersonate
+	config.AuthProvider = kubeconfig.AuthProvider
+	config.AuthConfigPersister = kubeconfig.AuthConfigPersister
+	config.ExecProvider = kubeconfig.ExecProvider
+
+	return nil
+}
@@ -0,0 +1,303 @@
+package kubernetes_test
+
+import (
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"path/filepath"
+	"time"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"github.com/onsi/gomega/ghttp"
+	"k8s.io/client-go/rest"
+
+	. "github.com/billiford/go-clouddriver/pkg/kubernetes"
+)
+
+const kubeconfig = `apiVersion: v1
+kind: Config
+current-context: test-context
+clusters:
+- name: test-cluster
+  cluster:
+    server: %[1]s
+    certificate-authority-data: dGVzdC1jYQ==
+- name: other-cluster
+  cluster:
+    server: https://other-host
+contexts:
+- name: test-context
+  context:
+    cluster: test-cluster
+    user: test-user
+- name: exec-context
+  context:
+    cluster: other-cluster
+    user: exec-user
+- name: cert-context
+  context:
+    cluster: other-cluster
+    user: cert-user
+users:
+- name: test-user
+  user:
+    token: kubeconfig-token
+- name: exec-user
+  user:
+    exec:
+      apiVersion: client.authentication.k8s.io/v1beta1
+      command: aws
+      args: ["eks", "get-token", "--cluster-name", "test-cluster"]
+- name: cert-user
+  user:
+    client-certificate: client.crt
+    client-key: client.key
+`
+
+var _ = Describe("Kubeconfig", func() {
+	var (
+		provider Provider
+		config   *rest.Config
+		err      error
+	)
+
+	BeforeEach(func() {
+		provider = Provider{
+			Name:               "test-account",
+			KubeconfigContents: fmt.Sprintf(kubeconfig, "https://test-host"),
+		}
+	})
+
+	Describe("#ValidateKubeconfig", func() {
+		It("accepts providers with a kubeconfig or without one", func() {
+			Expect(provider.ValidateKubeconfig()).To(Succeed())
+			Expect(Provider{Host: "https://test-host", CAData: "dGVzdC1jYQ=="}.ValidateKubeconfig()).To(Succeed())
+		})
+
+		It("rejects a context without a kubeconfig", func() {
+			Expect(Provider{KubeconfigContext: "test-context"}.ValidateKubeconfig()).
+				To(MatchError("kubeconfigContext must only be set with kubeconfigContents or kubeconfigFile"))
+		})
+
+		It("rejects both kubeconfig contents and a kubeconfig file", func() {
+			provider.KubeconfigFile = "/opt/spinnaker/kubeconfig"
+			Expect(provider.ValidateKubeconfig()).To(MatchError("only one of kubeconfigContents and kubeconfigFile must be set"))
+		})
+
+		It("rejects a kubeconfig with credentials of the provider", func() {
+			provider.BearerToken = "some.bearer.token"
+			Expect(provider.ValidateKubeconfig()).To(MatchError("providers with a kubeconfig must not set a host, " +
+				"caData, bearerToken or clusterCredential"))
+		})
+	})
+
+	Describe("#KubeconfigConfig", func() {
+		JustBeforeEach(func() {
+			config, err = provider.KubeconfigConfig()
+		})
+
+		When("the kubeconfig cannot be parsed", func() {
+			BeforeEach(func() {
+				provider.KubeconfigContents = "::"
+			})
+
+			It("returns an error", func() {
+				Expect(err).ToNot(BeNil())
+				Expect(err.Error()).To(HavePrefix("error loading kubeconfig: "))
+			})
+		})
+
+		When("the context does not exist", func() {
+			BeforeEach(func() {
+				provider.KubeconfigContext = "missing-context"
+			})
+
+			It("returns an error", func() {
+				Expect(err).ToNot(BeNil())
+				Expect(err.Error()).To(ContainSubstring(`context was not found for specified context: missing-context`))
+			})
+		})
+
+		When("the context is not set", func() {
+			It("uses the current context", func() {
+				Expect(err).To(BeNil())
+				Expect(config.Host).To(Equal("https://test-host"))
+				Expect(config.CAData).To(Equal([]byte("test-ca")))
+				Expect(config.BearerToken).To(Equal("kubeconfig-token"))
+			})
+		})
+
+		When("the context uses an exec plugin", func() {
+			BeforeEach(func() {
+				provider.KubeconfigContext = "exec-context"
+			})
+
+			It("uses the plugin", func() {
+				Expect(err).To(BeNil())
+				Expect(config.Host).To(Equal("https://other-host"))
+				Expect(config.BearerToken).To(BeEmpty())
+				Expect(config.ExecProvider).ToNot(BeNil())
+				Expect(config.ExecProvider.Command).To(Equal("aws"))
+				Expect(config.ExecProvider.Args).To(Equal([]string{"eks", "get-token", "--cluster-name", "test-cluster"}))
+			})
+		})
+
+		When("the kubeconfig is a file", func() {
+			var dir string
+
+			BeforeEach(func() {
+				dir, err = ioutil.TempDir("", "kubeconfig")
+				Expect(err).To(BeNil())
+				path := filepath.Join(dir, "config")
+				Expect(ioutil.WriteFile(path, []byte(provider.KubeconfigContents), 0600)).To(Succeed())
+				Expect(ioutil.WriteFile(filepath.Join(dir, "client.crt"), []byte{}, 0600)).To(Succeed())
+				Expect(ioutil.WriteFile(filepath.Join(dir, "client.key"), []byte{}, 0600)).To(Succeed())
+				provider.KubeconfigContents = ""
+				provider.KubeconfigFile = path
+				provider.KubeconfigContext = "cert-context"
+			})
+
+			AfterEach(func() {
+				os.RemoveAll(dir)
+			})
+
+			It("reads client certificates relative to the file", func() {
+				Expect(err).To(BeNil())
+				Expect(config.CertFile).To(Equal(filepath.Join(dir, "client.crt")))
+				Expect(config.KeyFile).To(Equal(filepath.Join(dir, "client.key")))
+			})
+		})
+
+		When("the kubeconfig file does not exist", func() {
+			BeforeEach(func() {
+				provider.KubeconfigContents = ""
+				provider.KubeconfigFile = "/does/not/exist"
+			})
+
+			It("returns an error", func() {
+				Expect(err).ToNot(BeNil())
+				Expect(err.Error()).To(HavePrefix("error loading kubeconfig: "))
+			})
+		})
+	})
+
+	Describe("#MintToken", func() {
+		var kc Controller
+
+		BeforeEach(func() {
+			kc = NewController()
+			config = &rest.Config{
+				Host:        "https://test-host",
+				BearerToken: "arcade-token",
+			}
+		})
+
+		JustBeforeEach(func() {
+			err = kc.MintToken(provider, config)
+		})
+
+		When("the kubeconfig cannot be loaded", func() {
+			BeforeEach(func() {
+				provider.KubeconfigContents = ""
+				provider.KubeconfigFile = "/does/not/exist"
+			})
+
+			It("returns an error", func() {
+				Expect(err).ToNot(BeNil())
+				Expect(err.Error()).To(HavePrefix("error using kubeconfig of provider test-account: "))
+			})
+		})
+
+		When("the kubeconfig uses an exec plugin", func() {
+			BeforeEach(func() {
+				provider.KubeconfigContext = "exec-context"
+				config.Host = ""
+			})
+
+			It("replaces the token with the plugin", func() {
+				Expect(err).To(BeNil())
+				Expect(config.Host).To(Equal("https://other-host"))
+				Expect(config.BearerToken).To(BeEmpty())
+				Expect(config.ExecProvider).ToNot(BeNil())
+			})
+		})
+
+		When("the config is of another cluster of the provider", func() {
+			BeforeEach(func() {
+				config.Host = "https://standby-host"
+				config.CAData = []byte("standby-ca")
+			})
+
+			It("keeps its host and ca data", func() {
+				Expect(err).To(BeNil())
+				Expect(config.Host).To(Equal("https://standby-host"))
+				Expect(config.CAData).To(Equal([]byte("standby-ca")))
+				Expect(config.BearerToken).To(Equal("kubeconfig-token"))
+			})
+		})
+
+		When("the provider mints tokens", func() {
+			var fakeServer *ghttp.Server
+
+			BeforeEach(func() {
+				fakeServer = ghttp.NewTLSServer()
+				fakeServer.AppendHandlers(ghttp.CombineHandlers(
+					ghttp.VerifyRequest(http.MethodPost, "/api/v1/namespaces/spinnaker/serviceaccounts/deployer/token"),
+					ghttp.VerifyHeaderKV("Authorization", "Bearer kubeconfig-token"),
+					ghttp.RespondWith(http.StatusCreated, fmt.Sprintf(`{
+						"kind": "TokenRequest",
+						"apiVersion": "authentication.k8s.io/v1",
+						"status": {
+							"token": "minted-token",
+							"expirationTimestamp": "%s"
+						}
+					}`, time.Now().Add(time.Hour).UTC().Format(time.RFC3339)), jsonHeader),
+				))
+
+				// Credentials of a kubeconfig are only used with TLS, and
+				// the test server's certificate is self-signed.
+				provider.KubeconfigContents = fmt.Sprintf(`apiVersion: v1
+kind: Config
+current-context: test-context
+clusters:
+- name: test-cluster
+  cluster:
+    server: %s
+    insecure-skip-tls-verify: true
+contexts:
+- name: test-context
+  context:
+    cluster: test-cluster
+    user: test-user
+users:
+- name: test-user
+  user:
+    token: kubeconfig-token
+`, fakeServer.URL())
+				provider.TokenServiceAccount = "spinnaker/deployer"
+				config.Host = fakeServer.URL()
+			})
+
+			AfterEach(func() {
+				fakeServer.Close()
+			})
+
+			It("mints them with the credentials of the kubeconfig", func() {
+				Expect(err).To(BeNil())
+				Expect(fakeServer.ReceivedRequests()).To(HaveLen(1))
+				Expect(config.BearerToken).To(Equal("minted-token"))
+			})
+		})
+
+		When("it succeeds", func() {
+			It("replaces the credentials of the config", func() {
+				Expect(err).To(BeNil())
+				Expect(config.Host).To(Equal("https://test-host"))
+				Expect(config.CAData).To(Equal([]byte("test-ca")))
+				Expect(config.BearerToken).To(Equal("kubeconfig-token"))
+			})
+		})
+	})
+})
@@ -2,13 +2,17 @@
 package kubernetesfakes
 
 import (
+	"context"
+	"io"
 	"sync"
 
 	"github.com/billiford/go-clouddriver/pkg/kubernetes"
+	corev1 "k8s.io/api/core/v1"
 	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 	"k8s.io/apimachinery/pkg/runtime/schema"
 	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/version"
 )
 
 type FakeClient struct {
@@ -39,6 +43,22 @@ type FakeClient struct {
 		result1 kubernetes.Metadata
 		result2 error
 	}
+	CanIStub        func(string, string, string, string) (bool, error)
+	canIMutex       sync.RWMutex
+	canIArgsForCall []struct {
+		arg1 string
+		arg2 string
+		arg3 string
+		arg4 string
+	}
+	canIReturns struct {
+		result1 bool
+		result2 error
+	}
+	canIReturnsOnCall map[int]struct {
+		result1 bool
+		result2 error
+	}
 	DeleteResourceByKindAndNameAndNamespaceStub        func(string, string, string, v1.DeleteOptions) error
 	deleteResourceByKindAndNameAndNamespaceMutex       sync.RWMutex
 	deleteResourceByKindAndNameAndNamespaceArgsForCall []struct {
@@ -81,6 +101,10 @@ type FakeClient struct {
 		result1 *unstructured.Unstructured
 		result2 error
 	}
+	InvalidateDiscoveryStub        func()
+	invalidateDiscoveryMutex       sync.RWMutex
+	invalidateDiscoveryArgsForCall []struct {
+	}
 	ListByGVRStub        func(schema.GroupVersionResource, v1.ListOptions) (*unstructured.UnstructuredList, error)
 	listByGVRMutex       sync.RWMutex
 	listByGVRArgsForCall []struct {
@@ -95,6 +119,20 @@ type FakeClient struct {
 		result1 *unstructured.UnstructuredList
 		result2 error
 	}
+	ListMetadataByGVRStub        func(schema.GroupVersionResource, v1.ListOptions) (*v1.PartialObjectMetadataList, error)
+	listMetadataByGVRMutex       sync.RWMutex
+	listMetadataByGVRArgsForCall []struct {
+		arg1 schema.GroupVersionResource
+		arg2 v1.ListOptions
+	}
+	listMetadataByGVRReturns struct {
+		result1 *v1.PartialObjectMetadataList
+		result2 error
+	}
+	listMetadataByGVRReturnsOnCall map[int]struct {
+		result1 *v1.PartialObjectMetadataList
+		result2 error
+	}
 	ListResourceStub        func(string, v1.ListOptions) (*unstructured.UnstructuredList, error)
 	listResourceMutex       sync.RWMutex
 	listResourceArgsForCall []struct {
@@ -109,6 +147,19 @@ type FakeClient struct {
 		result1 *unstructured.UnstructuredList
 		result2 error
 	}
+	NamespaceScopedStub        func(string) (bool, error)
+	namespaceScopedMutex       sync.RWMutex
+	namespaceScopedArgsForCall []struct {
+		arg1 string
+	}
+	namespaceScopedReturns struct {
+		result1 bool
+		result2 error
+	}
+	namespaceScopedReturnsOnCall map[int]struct {
+		result1 bool
+		result2 error
+	}
 	PatchStub        func(string, string, string, []byte) (kubernetes.Metadata, *unstructured.Unstructured, error)
 	patchMutex       sync.RWMutex
 	patchArgsForCall []struct {
@@ -146,6 +197,34 @@ type FakeClient struct {
 		result2 *unstructured.Unstructured
 		result3 error
 	}
+	ServerVersionStub        func() (*version.Info, error)
+	serverVersionMutex       sync.RWMutex
+	serverVersionArgsForCall []struct {
+	}
+	serverVersionReturns struct {
+		result1 *version.Info
+		result2 error
+	}
+	serverVersionReturnsOnCall map[int]struct {
+		result1 *version.Info
+		result2 error
+	}
+	StreamLogsStub        func(context.Context, string, string, corev1.PodLogOptions) (io.ReadCloser, error)
+	streamLogsMutex       sync.RWMutex
+	streamLogsArgsForCall []struct {
+		arg1 context.Context
+		arg2 string
+		arg3 string
+		arg4 corev1.PodLogOptions
+	}
+	streamLogsReturns struct {
+		result1 io.ReadCloser
+		result2 error
+	}
+	streamLogsReturnsOnCall map[int]struct {
+		result1 io.ReadCloser
+		result2 error
+	}
 	invocations      map[string][][]interface{}
 	invocationsMutex sync.RWMutex
 }
@@ -277,6 +356,72 @@ func (fake *FakeClient) ApplyWithNamespaceOverrideReturnsOnCall(i int, result1 k
 	}{result1, result2}
 }
 
+func (fake *FakeClient) CanI(arg1 string, arg2 string, arg3 string, arg4 string) (bool, error) {
+	fake.canIMutex.Lock()
+	ret, specificReturn := fake.canIReturnsOnCall[len(fake.canIArgsForCall)]
+	fake.canIArgsForCall = append(fake.canIArgsForCall, struct {
+		arg1 string
+		arg2 string
+		arg3 string
+		arg4 string
+	}{arg1, arg2, arg3, arg4})
+	fake.recordInvocation("CanI", []interface{}{arg1, arg2, arg3, arg4})
+	fake.canIMutex.Unlock()
+	if fake.CanIStub != nil {
+		return fake.CanIStub(arg1, arg2, arg3, arg4)
+	}
+	if specificReturn {
+		return ret.result1, ret.result2
+	}
+	fakeReturns := fake.canIReturns
+	return fakeReturns.result1, fakeReturns.result2
+}
+
+func (fake *FakeClient) CanICallCount() int {
+	fake.canIMutex.RLock()
+	defer fake.canIMutex.RUnlock()
+	return len(fake.canIArgsForCall)
+}
+
+func (fake *FakeClient) CanICalls(stub func(string, string, string, string) (bool, error)) {
+	fake.canIMutex.Lock()
+	defer fake.canIMutex.Unlock()
+	fake.CanIStub = stub
+}
+
+func (fake *FakeClient) CanIArgsForCall(i int) (string, string, string, string) {
+	fake.canIMutex.RLock()
+	defer fake.canIMutex.RUnlock()
+	argsForCall := fake.canIArgsForCall[i]
+	return argsForCall.arg1, argsForCall.arg2, argsForCall.arg3, argsForCall.arg4
+}
+
+func (fake *FakeClient) CanIReturns(result1 bool, result2 error) {
+	fake.canIMutex.Lock()
+	defer fake.canIMutex.Unlock()
+	fake.CanIStub = nil
+	fake.canIReturns = struct {
+		result1 bool
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeClient) CanIReturnsOnCall(i int, result1 bool, result2 error) {
+	fake.canIMutex.Lock()
+	defer fake.canIMutex.Unlock()
+	fake.CanIStub = nil
+	if fake.canIReturnsOnCall == nil {
+		fake.canIReturnsOnCall = make(map[int]struct {
+			result1 bool
+			result2 error
+		})
+	}
+	fake.canIReturnsOnCall[i] = struct {
+		result1 bool
+		result2 error
+	}{result1, result2}
+}
+
 func (fake *FakeClient) DeleteResourceByKindAndNameAndNamespace(arg1 string, arg2 string, arg3 string, arg4 v1.DeleteOptions) error {
 	fake.deleteResourceByKindAndNameAndNamespaceMutex.Lock()
 	ret, specificReturn := fake.deleteResourceByKindAndNameAndNamespaceReturnsOnCall[len(fake.deleteResourceByKindAndNameAndNamespaceArgsForCall)]
@@ -468,6 +613,29 @@ func (fake *FakeClient) GetReturnsOnCall(i int, result1 *unstructured.Unstructur
 	}{result1, result2}
 }
 
+func (fake *FakeClient) InvalidateDiscovery() {
+	fake.invalidateDiscoveryMutex.Lock()
+	fake.invalidateDiscoveryArgsForCall = append(fake.invalidateDiscoveryArgsForCall, struct {
+	}{})
+	fake.recordInvocation("InvalidateDiscovery", []interface{}{})
+	fake.invalidateDiscoveryMutex.Unlock()
+	if fake.InvalidateDiscoveryStub != nil {
+		fake.InvalidateDiscoveryStub()
+	}
+}
+
+func (fake *FakeClient) InvalidateDiscoveryCallCount() int {
+	fake.invalidateDiscoveryMutex.RLock()
+	defer fake.invalidateDiscoveryMutex.RUnlock()
+	return len(fake.invalidateDiscoveryArgsForCall)
+}
+
+func (fake *FakeClient) InvalidateDiscoveryCalls(stub func()) {
+	fake.invalidateDiscoveryMutex.Lock()
+	defer fake.invalidateDiscoveryMutex.Unlock()
+	fake.InvalidateDiscoveryStub = stub
+}
+
 func (fake *FakeClient) ListByGVR(arg1 schema.GroupVersionResource, arg2 v1.ListOptions) (*unstructured.UnstructuredList, error) {
 	fake.listByGVRMutex.Lock()
 	ret, specificReturn := fake.listByGVRReturnsOnCall[len(fake.listByGVRArgsForCall)]
@@ -532,6 +700,70 @@ func (fake *FakeClient) ListByGVRReturnsOnCall(i int, result1 *unstructured.Unst
 	}{result1, result2}
 }
 
+func (fake *FakeClient) ListMetadataByGVR(arg1 schema.GroupVersionResource, arg2 v1.ListOptions) (*v1.PartialObjectMetadataList, error) {
+	fake.listMetadataByGVRMutex.Lock()
+	ret, specificReturn := fake.listMetadataByGVRReturnsOnCall[len(fake.listMetadataByGVRArgsForCall)]
+	fake.listMetadataByGVRArgsForCall = append(fake.listMetadataByGVRArgsForCall, struct {
+		arg1 schema.GroupVersionResource
+		arg2 v1.ListOptions
+	}{arg1, arg2})
+	fake.recordInvocation("ListMetadataByGVR", []interface{}{arg1, arg2})
+	fake.listMetadataByGVRMutex.Unlock()
+	if fake.ListMetadataByGVRStub != nil {
+		return fake.ListMetadataByGVRStub(arg1, arg2)
+	}
+	if specificReturn {
+		return ret.result1, ret.result2
+	}
+	fakeReturns := fake.listMetadataByGVRReturns
+	return fakeReturns.result1, fakeReturns.result2
+}
+
+func (fake *FakeClient) ListMetadataByGVRCallCount() int {
+	fake.listMetadataByGVRMutex.RLock()
+	defer fake.listMetadataByGVRMutex.RUnlock()
+	return len(fake.listMetadataByGVRArgsForCall)
+}
+
+func (fake *FakeClient) ListMetadataByGVRCalls(stub func(schema.GroupVersionResource, v1.ListOptions) (*v1.PartialObjectMetadataList, error)) {
+	fake.listMetadataByGVRMutex.Lock()
+	defer fake.listMetadataByGVRMutex.Unlock()
+	fake.ListMetadataByGVRStub = stub
+}
+
+func (fake *FakeClient) ListMetadataByGVRArgsForCall(i int) (schema.GroupVersionResource, v1.ListOptions) {
+	fake.listMetadataByGVRMutex.RLock()
+	defer fake.listMetadataByGVRMutex.RUnlock()
+	argsForCall := fake.listMetadataByGVRArgsForCall[i]
+	return argsForCall.arg1, argsForCall.arg2
+}
+
+func (fake *FakeClient) ListMetadataByGVRReturns(result1 *v1.PartialObjectMetadataList, result2 error) {
+	fake.listMetadataByGVRMutex.Lock()
+	defer fake.listMetadataByGVRMutex.Unlock()
+	fake.ListMetadataByGVRStub = nil
+	fake.listMetadataByGVRReturns = struct {
+		result1 *v1.PartialObjectMetadataList
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeClient) ListMetadataByGVRReturnsOnCall(i int, result1 *v1.PartialObjectMetadataList, result2 error) {
+	fake.listMetadataByGVRMutex.Lock()
+	defer fake.listMetadataByGVRMutex.Unlock()
+	fake.ListMetadataByGVRStub = nil
+	if fake.listMetadataByGVRReturnsOnCall == nil {
+		fake.listMetadataByGVRReturnsOnCall = make(map[int]struct {
+			result1 *v1.PartialObjectMetadataList
+			result2 error
+		})
+	}
+	fake.listMetadataByGVRReturnsOnCall[i] = struct {
+		result1 *v1.PartialObjectMetadataList
+		result2 error
+	}{result1, result2}
+}
+
 func (fake *FakeClient) ListResource(arg1 string, arg2 v1.ListOptions) (*unstructured.UnstructuredList, error) {
 	fake.listResourceMutex.Lock()
 	ret, specificReturn := fake.listResourceReturnsOnCall[len(fake.listResourceArgsForCall)]
@@ -596,6 +828,69 @@ func (fake *FakeClient) ListResourceReturnsOnCall(i int, result1 *unstructured.U
 	}{result1, result2}
 }
 
+func (fake *FakeClient) NamespaceScoped(arg1 string) (bool, error) {
+	fake.namespaceScopedMutex.Lock()
+	ret, specificReturn := fake.namespaceScopedReturnsOnCall[len(fake.namespaceScopedArgsForCall)]
+	fake.namespaceScopedArgsForCall = append(fake.namespaceScopedArgsForCall, struct {
+		arg1 string
+	}{arg1})
+	fake.recordInvocation("NamespaceScoped", []interface{}{arg1})
+	fake.namespaceScopedMutex.Unlock()
+	if fake.NamespaceScopedStub != nil {
+		return fake.NamespaceScopedStub(arg1)
+	}
+	if specificReturn {
+		return ret.result1, ret.result2
+	}
+	fakeReturns := fake.namespaceScopedReturns
+	return fakeReturns.result1, fakeReturns.result2
+}
+
+func (fake *FakeClient) NamespaceScopedCallCount() int {
+	fake.namespaceScopedMutex.RLock()
+	defer fake.namespaceScopedMutex.RUnlock()
+	return len(fake.namespaceScopedArgsForCall)
+}
+
+func (fake *FakeClient) NamespaceScopedCalls(stub func(string) (bool, error)) {
+	fake.namespaceScopedMutex.Lock()
+	defer fake.namespaceScopedMutex.Unlock()
+	fake.NamespaceScopedStub = stub
+}
+
+func (fake *FakeClient) NamespaceScopedArgsForCall(i int) string {
+	fake.namespaceScopedMutex.RLock()
+	defer fake.namespaceScopedMutex.RUnlock()
+	argsForCall := fake.namespaceScopedArgsForCall[i]
+	return argsForCall.arg1
+}
+
+func (fake *FakeClient) NamespaceScopedReturns(result1 bool, result2 error) {
+	fake.namespaceScopedMutex.Lock()
+	defer fake.namespaceScopedMutex.Unlock()
+	fake.NamespaceScopedStub = nil
+	fake.namespaceScopedReturns = struct {
+		result1 bool
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeClient) NamespaceScopedReturnsOnCall(i int, result1 bool, result2 error) {
+	fake.namespaceScopedMutex.Lock()
+	defer fake.namespaceScopedMutex.Unlock()
+	fake.NamespaceScopedStub = nil
+	if fake.namespaceScopedReturnsOnCall == nil {
+		fake.namespaceScopedReturnsOnCall = make(map[int]struct {
+			result1 bool
+			result2 error
+		})
+	}
+	fake.namespaceScopedReturnsOnCall[i] = struct {
+		result1 bool
+		result2 error
+	}{result1, result2}
+}
+
 func (fake *FakeClient) Patch(arg1 string, arg2 string, arg3 string, arg4 []byte) (kubernetes.Metadata, *unstructured.Unstructured, error) {
 	var arg4Copy []byte
 	if arg4 != nil {
@@ -745,6 +1040,127 @@ func (fake *FakeClient) PatchUsingStrategyReturnsOnCall(i int, result1 kubernete
 	}{result1, result2, result3}
 }
 
+func (fake *FakeClient) ServerVersion() (*version.Info, error) {
+	fake.serverVersionMutex.Lock()
+	ret, specificReturn := fake.serverVersionReturnsOnCall[len(fake.serverVersionArgsForCall)]
+	fake.serverVersionArgsForCall = append(fake.serverVersionArgsForCall, struct {
+	}{})
+	fake.recordInvocation("ServerVersion", []interface{}{})
+	fake.serverVersionMutex.Unlock()
+	if fake.ServerVersionStub != nil {
+		return fake.ServerVersionStub()
+	}
+	if specificReturn {
+		return ret.result1, ret.result2
+	}
+	fakeReturns := fake.serverVersionReturns
+	return fakeReturns.result1, fakeReturns.result2
+}
+
+func (fake *FakeClient) ServerVersionCallCount() int {
+	fake.serverVersionMutex.RLock()
+	defer fake.serverVersionMutex.RUnlock()
+	return len(fake.serverVersionArgsForCall)
+}
+
+func (fake *FakeClient) ServerVersionCalls(stub func() (*version.Info, error)) {
+	fake.serverVersionMutex.Lock()
+	defer fake.serverVersionMutex.Unlock()
+	fake.ServerVersionStub = stub
+}
+
+func (fake *FakeClient) ServerVersionReturns(result1 *version.Info, result2 error) {
+	fake.serverVersionMutex.Lock()
+	defer fake.serverVersionMutex.Unlock()
+	fake.ServerVersionStub = nil
+	fake.serverVersionReturns = struct {
+		result1 *version.Info
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeClient) ServerVersionReturnsOnCall(i int, result1 *version.Info, result2 error) {
+	fake.serverVersionMutex.Lock()
+	defer fake.serverVersionMutex.Unlock()
+	fake.ServerVersionStub = nil
+	if fake.serverVersionReturnsOnCall == nil {
+		fake.serverVersionReturnsOnCall = make(map[int]struct {
+			result1 *version.Info
+			result2 error
+		})
+	}
+	fake.serverVersionReturnsOnCall[i] = struct {
+		result1 *version.Info
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeClient) StreamLogs(arg1 context.Context, arg2 string, arg3 string, arg4 corev1.PodLogOptions) (io.ReadCloser, error) {
+	fake.streamLogsMutex.Lock()
+	ret, specificReturn := fake.streamLogsReturnsOnCall[len(fake.streamLogsArgsForCall)]
+	fake.streamLogsArgsForCall = append(fake.streamLogsArgsForCall, struct {
+		arg1 context.Context
+		arg2 string
+		arg3 string
+		arg4 corev1.PodLogOptions
+	}{arg1, arg2, arg3, arg4})
+	fake.recordInvocation("StreamLogs", []interface{}{arg1, arg2, arg3, arg4})
+	fake.streamLogsMutex.Unlock()
+	if fake.StreamLogsStub != nil {
+		return fake.StreamLogsStub(arg1, arg2, arg3, arg4)
+	}
+	if specificReturn {
+		return ret.result1, ret.result2
+	}
+	fakeReturns := fake.streamLogsReturns
+	return fakeReturns.result1, fakeReturns.result2
+}
+
+func (fake *FakeClient) StreamLogsCallCount() int {
+	fake.streamLogsMutex.RLock()
+	defer fake.streamLogsMutex.RUnlock()
+	return len(fake.streamLogsArgsForCall)
+}
+
+func (fake *FakeClient) StreamLogsCalls(stub func(context.Context, string, string, corev1.PodLogOptions) (io.ReadCloser, error)) {
+	fake.streamLogsMutex.Lock()
+	defer fake.streamLogsMutex.Unlock()
+	fake.StreamLogsStub = stub
+}
+
+func (fake *FakeClient) StreamLogsArgsForCall(i int) (context.Context, string, string, corev1.PodLogOptions) {
+	fake.streamLogsMutex.RLock()
+	defer fake.streamLogsMutex.RUnlock()
+	argsForCall := fake.streamLogsArgsForCall[i]
+	return argsForCall.arg1, argsForCall.arg2, argsForCall.arg3, argsForCall.arg4
+}
+
+func (fake *FakeClient) StreamLogsReturns(result1 io.ReadCloser, result2 error) {
+	fake.streamLogsMutex.Lock()
+	defer fake.streamLogsMutex.Unlock()
+	fake.StreamLogsStub = nil
+	fake.streamLogsReturns = struct {
+		result1 io.ReadCloser
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeClient) StreamLogsReturnsOnCall(i int, result1 io.ReadCloser, result2 error) {
+	fake.streamLogsMutex.Lock()
+	defer fake.streamLogsMutex.Unlock()
+	fake.StreamLogsStub = nil
+	if fake.streamLogsReturnsOnCall == nil {
+		fake.streamLogsReturnsOnCall = make(map[int]struct {
+			result1 io.ReadCloser
+			result2 error
+		})
+	}
+	fake.streamLogsReturnsOnCall[i] = struct {
+		result1 io.ReadCloser
+		result2 error
+	}{result1, result2}
+}
+
 func (fake *FakeClient) Invocations() map[string][][]interface{} {
 	fake.invocationsMutex.RLock()
 	defer fake.invocationsMutex.RUnlock()
@@ -752,20 +1168,32 @@ func (fake *FakeClient) Invocations() map[string][][]interface{} {
 	defer fake.applyMutex.RUnlock()
 	fake.applyWithNamespaceOverrideMutex.RLock()
 	defer fake.applyWithNamespaceOverrideMutex.RUnlock()
+	fake.canIMutex.RLock()
+	defer fake.canIMutex.RUnlock()
 	fake.deleteResourceByKindAndNameAndNamespaceMutex.RLock()
 	defer fake.deleteResourceByKindAndNameAndNamespaceMutex.RUnlock()
 	fake.gVRForKindMutex.RLock()
 	defer fake.gVRForKindMutex.RUnlock()
 	fake.getMutex.RLock()
 	defer fake.getMutex.RUnlock()
+	fake.invalidateDiscoveryMutex.RLock()
+	defer fake.invalidateDiscoveryMutex.RUnlock()
 	fake.listByGVRMutex.RLock()
 	defer fake.listByGVRMutex.RUnlock()
+	fake.listMetadataByGVRMutex.RLock()
+	defer fake.listMetadataByGVRMutex.RUnlock()
 	fake.listResourceMutex.RLock()
 	defer fake.listResourceMutex.RUnlock()
+	fake.namespaceScopedMutex.RLock()
+	defer fake.namespaceScopedMutex.RUnlock()
 	fake.patchMutex.RLock()
 	defer fake.patchMutex.RUnlock()
 	fake.patchUsingStrategyMutex.RLock()
 	defer fake.patchUsingStrategyMutex.RUnlock()
+	fake.serverVersionMutex.RLock()
+	defer fake.serverVersionMutex.RUnlock()
+	fake.streamLogsMutex.RLock()
+	defer fake.streamLogsMutex.RUnlock()
 	copiedInvocations := map[string][][]interface{}{}
 	for key, value := range fake.invocations {
 		copiedInvocations[key] = value
@@ -2,7 +2,9 @@
 package kubernetesfakes
 
 import (
+	"context"
 	"sync"
+	"time"
 
 	"github.com/billiford/go-clouddriver/pkg/kubernetes"
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
@@ -34,6 +36,40 @@ type FakeController struct {
 	addSpinnakerLabelsReturnsOnCall map[int]struct {
 		result1 error
 	}
+	CallCountStub        func(string) kubernetes.CallCount
+	callCountMutex       sync.RWMutex
+	callCountArgsForCall []struct {
+		arg1 string
+	}
+	callCountReturns struct {
+		result1 kubernetes.CallCount
+	}
+	callCountReturnsOnCall map[int]struct {
+		result1 kubernetes.CallCount
+	}
+	EvictIdleStub        func(time.Duration) int
+	evictIdleMutex       sync.RWMutex
+	evictIdleArgsForCall []struct {
+		arg1 time.Duration
+	}
+	evictIdleReturns struct {
+		result1 int
+	}
+	evictIdleReturnsOnCall map[int]struct {
+		result1 int
+	}
+	MintTokenStub        func(kubernetes.Provider, *rest.Config) error
+	mintTokenMutex       sync.RWMutex
+	mintTokenArgsForCall []struct {
+		arg1 kubernetes.Provider
+		arg2 *rest.Config
+	}
+	mintTokenReturns struct {
+		result1 error
+	}
+	mintTokenReturnsOnCall map[int]struct {
+		result1 error
+	}
 	NewClientStub        func(*rest.Config) (kubernetes.Client, error)
 	newClientMutex       sync.RWMutex
 	newClientArgsForCall []struct {
@@ -47,6 +83,17 @@ type FakeController struct {
 		result1 kubernetes.Client
 		result2 error
 	}
+	RequestCountsStub        func(string) []kubernetes.RequestCount
+	requestCountsMutex       sync.RWMutex
+	requestCountsArgsForCall []struct {
+		arg1 string
+	}
+	requestCountsReturns struct {
+		result1 []kubernetes.RequestCount
+	}
+	requestCountsReturnsOnCall map[int]struct {
+		result1 []kubernetes.RequestCount
+	}
 	ToUnstructuredStub        func(map[string]interface{}) (*unstructured.Unstructured, error)
 	toUnstructuredMutex       sync.RWMutex
 	toUnstructuredArgsForCall []struct {
@@ -60,6 +107,17 @@ type FakeController struct {
 		result1 *unstructured.Unstructured
 		result2 error
 	}
+	WithContextStub        func(context.Context) kubernetes.Controller
+	withContextMutex       sync.RWMutex
+	withContextArgsForCall []struct {
+		arg1 context.Context
+	}
+	withContextReturns struct {
+		result1 kubernetes.Controller
+	}
+	withContextReturnsOnCall map[int]struct {
+		result1 kubernetes.Controller
+	}
 	invocations      map[string][][]interface{}
 	invocationsMutex sync.RWMutex
 }
@@ -186,6 +244,187 @@ func (fake *FakeController) AddSpinnakerLabelsReturnsOnCall(i int, result1 error
 	}{result1}
 }
 
+func (fake *FakeController) CallCount(arg1 string) kubernetes.CallCount {
+	fake.callCountMutex.Lock()
+	ret, specificReturn := fake.callCountReturnsOnCall[len(fake.callCountArgsForCall)]
+	fake.callCountArgsForCall = append(fake.callCountArgsForCall, struct {
+		arg1 string
+	}{arg1})
+	fake.recordInvocation("CallCount", []interface{}{arg1})
+	fake.callCountMutex.Unlock()
+	if fake.CallCountStub != nil {
+		return fake.CallCountStub(arg1)
+	}
+	if specificReturn {
+		return ret.result1
+	}
+	fakeReturns := fake.callCountReturns
+	return fakeReturns.result1
+}
+
+func (fake *FakeController) CallCountCallCount() int {
+	fake.callCountMutex.RLock()
+	defer fake.callCountMutex.RUnlock()
+	return len(fake.callCountArgsForCall)
+}
+
+func (fake *FakeController) CallCountCalls(stub func(string) kubernetes.CallCount) {
+	fake.callCountMutex.Lock()
+	defer fake.callCountMutex.Unlock()
+	fake.CallCountStub = stub
+}
+
+func (fake *FakeController) CallCountArgsForCall(i int) string {
+	fake.callCountMutex.RLock()
+	defer fake.callCountMutex.RUnlock()
+	argsForCall := fake.callCountArgsForCall[i]
+	return argsForCall.arg1
+}
+
+func (fake *FakeController) CallCountReturns(result1 kubernetes.CallCount) {
+	fake.callCountMutex.Lock()
+	defer fake.callCountMutex.Unlock()
+	fake.CallCountStub = nil
+	fake.callCountReturns = struct {
+		result1 kubernetes.CallCount
+	}{result1}
+}
+
+func (fake *FakeController) CallCountReturnsOnCall(i int, result1 kubernetes.CallCount) {
+	fake.callCountMutex.Lock()
+	defer fake.callCountMutex.Unlock()
+	fake.CallCountStub = nil
+	if fake.callCountReturnsOnCall == nil {
+		fake.callCountReturnsOnCall = make(map[int]struct {
+			result1 kubernetes.CallCount
+		})
+	}
+	fake.callCountReturnsOnCall[i] = struct {
+		result1 kubernetes.CallCount
+	}{result1}
+}
+
+func (fake *FakeController) EvictIdle(arg1 time.Duration) int {
+	fake.evictIdleMutex.Lock()
+	ret, specificReturn := fake.evictIdleReturnsOnCall[len(fake.evictIdleArgsForCall)]
+	fake.evictIdleArgsForCall = append(fake.evictIdleArgsForCall, struct {
+		arg1 time.Duration
+	}{arg1})
+	fake.recordInvocation("EvictIdle", []interface{}{arg1})
+	fake.evictIdleMutex.Unlock()
+	if fake.EvictIdleStub != nil {
+		return fake.EvictIdleStub(arg1)
+	}
+	if specificReturn {
+		return ret.result1
+	}
+	fakeReturns := fake.evictIdleReturns
+	return fakeReturns.result1
+}
+
+func (fake *FakeController) EvictIdleCallCount() int {
+	fake.evictIdleMutex.RLock()
+	defer fake.evictIdleMutex.RUnlock()
+	return len(fake.evictIdleArgsForCall)
+}
+
+func (fake *FakeController) EvictIdleCalls(stub func(time.Duration) int) {
+	fake.evictIdleMutex.Lock()
+	defer fake.evictIdleMutex.Unlock()
+	fake.EvictIdleStub = stub
+}
+
+func (fake *FakeController) EvictIdleArgsForCall(i int) time.Duration {
+	fake.evictIdleMutex.RLock()
+	defer fake.evictIdleMutex.RUnlock()
+	argsForCall := fake.evictIdleArgsForCall[i]
+	return argsForCall.arg1
+}
+
+func (fake *FakeController) EvictIdleReturns(result1 int) {
+	fake.evictIdleMutex.Lock()
+	defer fake.evictIdleMutex.Unlock()
+	fake.EvictIdleStub = nil
+	fake.evictIdleReturns = struct {
+		result1 int
+	}{result1}
+}
+
+func (fake *FakeController) EvictIdleReturnsOnCall(i int, result1 int) {
+	fake.evictIdleMutex.Lock()
+	defer fake.evictIdleMutex.Unlock()
+	fake.EvictIdleStub = nil
+	if fake.evictIdleReturnsOnCall == nil {
+		fake.evictIdleReturnsOnCall = make(map[int]struct {
+			result1 int
+		})
+	}
+	fake.evictIdleReturnsOnCall[i] = struct {
+		result1 int
+	}{result1}
+}
+
+func (fake *FakeController) MintToken(arg1 kubernetes.Provider, arg2 *rest.Config) error {
+	fake.mintTokenMutex.Lock()
+	ret, specificReturn := fake.mintTokenReturnsOnCall[len(fake.mintTokenArgsForCall)]
+	fake.mintTokenArgsForCall = append(fake.mintTokenArgsForCall, struct {
+		arg1 kubernetes.Provider
+		arg2 *rest.Config
+	}{arg1, arg2})
+	fake.recordInvocation("MintToken", []interface{}{arg1, arg2})
+	fake.mintTokenMutex.Unlock()
+	if fake.MintTokenStub != nil {
+		return fake.MintTokenStub(arg1, arg2)
+	}
+	if specificReturn {
+		return ret.result1
+	}
+	fakeReturns := fake.mintTokenReturns
+	return fakeReturns.result1
+}
+
+func (fake *FakeController) MintTokenCallCount() int {
+	fake.mintTokenMutex.RLock()
+	defer fake.mintTokenMutex.RUnlock()
+	return len(fake.mintTokenArgsForCall)
+}
+
+func (fake *FakeController) MintTokenCalls(stub func(kubernetes.Provider, *rest.Config) error) {
+	fake.mintTokenMutex.Lock()
+	defer fake.mintTokenMutex.Unlock()
+	fake.MintTokenStub = stub
+}
+
+func (fake *FakeController) MintTokenArgsForCall(i int) (kubernetes.Provider, *rest.Config) {
+	fake.mintTokenMutex.RLock()
+	defer fake.mintTokenMutex.RUnlock()
+	argsForCall := fake.mintTokenArgsForCall[i]
+	return argsForCall.arg1, argsForCall.arg2
+}
+
+func (fake *FakeController) MintTokenReturns(result1 error) {
+	fake.mintTokenMutex.Lock()
+	defer fake.mintTokenMutex.Unlock()
+	fake.MintTokenStub = nil
+	fake.mintTokenReturns = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *FakeController) MintTokenReturnsOnCall(i int, result1 error) {
+	fake.mintTokenMutex.Lock()
+	defer fake.mintTokenMutex.Unlock()
+	fake.MintTokenStub = nil
+	if fake.mintTokenReturnsOnCall == nil {
+		fake.mintTokenReturnsOnCall = make(map[int]struct {
+			result1 error
+		})
+	}
+	fake.mintTokenReturnsOnCall[i] = struct {
+		result1 error
+	}{result1}
+}
+
 func (fake *FakeController) NewClient(arg1 *rest.Config) (kubernetes.Client, error) {
 	fake.newClientMutex.Lock()
 	ret, specificReturn := fake.newClientReturnsOnCall[len(fake.newClientArgsForCall)]
@@ -249,6 +488,66 @@ func (fake *FakeController) NewClientReturnsOnCall(i int, result1 kubernetes.Cli
 	}{result1, result2}
 }
 
+func (fake *FakeController) RequestCounts(arg1 string) []kubernetes.RequestCount {
+	fake.requestCountsMutex.Lock()
+	ret, specificReturn := fake.requestCountsReturnsOnCall[len(fake.requestCountsArgsForCall)]
+	fake.requestCountsArgsForCall = append(fake.requestCountsArgsForCall, struct {
+		arg1 string
+	}{arg1})
+	fake.recordInvocation("RequestCounts", []interface{}{arg1})
+	fake.requestCountsMutex.Unlock()
+	if fake.RequestCountsStub != nil {
+		return fake.RequestCountsStub(arg1)
+	}
+	if specificReturn {
+		return ret.result1
+	}
+	fakeReturns := fake.requestCountsReturns
+	return fakeReturns.result1
+}
+
+func (fake *FakeController) RequestCountsCallCount() int {
+	fake.requestCountsMutex.RLock()
+	defer fake.requestCountsMutex.RUnlock()
+	return len(fake.requestCountsArgsForCall)
+}
+
+func (fake *FakeController) RequestCountsCalls(stub func(string) []kubernetes.RequestCount) {
+	fake.requestCountsMutex.Lock()
+	defer fake.requestCountsMutex.Unlock()
+	fake.RequestCountsStub = stub
+}
+
+func (fake *FakeController) RequestCountsArgsForCall(i int) string {
+	fake.requestCountsMutex.RLock()
+	defer fake.requestCountsMutex.RUnlock()
+	argsForCall := fake.requestCountsArgsForCall[i]
+	return argsForCall.arg1
+}
+
+func (fake *FakeController) RequestCountsReturns(result1 []kubernetes.RequestCount) {
+	fake.requestCountsMutex.Lock()
+	defer fake.requestCountsMutex.Unlock()
+	fake.RequestCountsStub = nil
+	fake.requestCountsReturns = struct {
+		result1 []kubernetes.RequestCount
+	}{result1}
+}
+
+func (fake *FakeController) RequestCountsReturnsOnCall(i int, result1 []kubernetes.RequestCount) {
+	fake.requestCountsMutex.Lock()
+	defer fake.requestCountsMutex.Unlock()
+	fake.RequestCountsStub = nil
+	if fake.requestCountsReturnsOnCall == nil {
+		fake.requestCountsReturnsOnCall = make(map[int]struct {
+			result1 []kubernetes.RequestCount
+		})
+	}
+	fake.requestCountsReturnsOnCall[i] = struct {
+		result1 []kubernetes.RequestCount
+	}{result1}
+}
+
 func (fake *FakeController) ToUnstructured(arg1 map[string]interface{}) (*unstructured.Unstructured, error) {
 	fake.toUnstructuredMutex.Lock()
 	ret, specificReturn := fake.toUnstructuredReturnsOnCall[len(fake.toUnstructuredArgsForCall)]
@@ -312,6 +611,66 @@ func (fake *FakeController) ToUnstructuredReturnsOnCall(i int, result1 *unstruct
 	}{result1, result2}
 }
 
+func (fake *FakeController) WithContext(arg1 context.Context) kubernetes.Controller {
+	fake.withContextMutex.Lock()
+	ret, specificReturn := fake.withContextReturnsOnCall[len(fake.withContextArgsForCall)]
+	fake.withContextArgsForCall = append(fake.withContextArgsForCall, struct {
+		arg1 context.Context
+	}{arg1})
+	fake.recordInvocation("WithContext", []interface{}{arg1})
+	fake.withContextMutex.Unlock()
+	if fake.WithContextStub != nil {
+		return fake.WithContextStub(arg1)
+	}
+	if specificReturn {
+		return ret.result1
+	}
+	fakeReturns := fake.withContextReturns
+	return fakeReturns.result1
+}
+
+func (fake *FakeController) WithContextCallCount() int {
+	fake.withContextMutex.RLock()
+	defer fake.withContextMutex.RUnlock()
+	return len(fake.withContextArgsForCall)
+}
+
+func (fake *FakeController) WithContextCalls(stub func(context.Context) kubernetes.Controller) {
+	fake.withContextMutex.Lock()
+	defer fake.withContextMutex.Unlock()
+	fake.WithContextStub = stub
+}
+
+func (fake *FakeController) WithContextArgsForCall(i int) context.Context {
+	fake.withContextMutex.RLock()
+	defer fake.withContextMutex.RUnlock()
+	argsForCall := fake.withContextArgsForCall[i]
+	return argsForCall.arg1
+}
+
+func (fake *FakeController) WithContextReturns(result1 kubernetes.Controller) {
+	fake.withContextMutex.Lock()
+	defer fake.withContextMutex.Unlock()
+	fake.WithContextStub = nil
+	fake.withContextReturns = struct {
+		result1 kubernetes.Controller
+	}{result1}
+}
+
+func (fake *FakeController) WithContextReturnsOnCall(i int, result1 kubernetes.Controller) {
+	fake.withContextMutex.Lock()
+	defer fake.withContextMutex.Unlock()
+	fake.WithContextStub = nil
+	if fake.withContextReturnsOnCall == nil {
+		fake.withContextReturnsOnCall = make(map[int]struct {
+			result1 kubernetes.Controller
+		})
+	}
+	fake.withContextReturnsOnCall[i] = struct {
+		result1 kubernetes.Controller
+	}{result1}
+}
+
 func (fake *FakeController) Invocations() map[string][][]interface{} {
 	fake.invocationsMutex.RLock()
 	defer fake.invocationsMutex.RUnlock()
@@ -319,10 +678,20 @@ func (fake *FakeController) Invocations() map[string][][]interface{} {
 	defer fake.addSpinnakerAnnotationsMutex.RUnlock()
 	fake.addSpinnakerLabelsMutex.RLock()
 	defer fake.addSpinnakerLabelsMutex.RUnlock()
+	fake.callCountMutex.RLock()
+	defer fake.callCountMutex.RUnlock()
+	fake.evictIdleMutex.RLock()
+	defer fake.evictIdleMutex.RUnlock()
+	fake.mintTokenMutex.RLock()
+	defer fake.mintTokenMutex.RUnlock()
 	fake.newClientMutex.RLock()
 	defer fake.newClientMutex.RUnlock()
+	fake.requestCountsMutex.RLock()
+	defer fake.requestCountsMutex.RUnlock()
 	fake.toUnstructuredMutex.RLock()
 	defer fake.toUnstructuredMutex.RUnlock()
+	fake.withContextMutex.RLock()
+	defer fake.withContextMutex.RUnlock()
 	copiedInvocations := map[string][][]interface{}{}
 	for key, value := range fake.invocations {
 		copiedInvocations[key] = value
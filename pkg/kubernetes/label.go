@@ -4,6 +4,7 @@ import (
 	"strings"
 
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/labels"
 )
 
 const (
@@ -12,6 +13,27 @@ const (
 	LabelKubernetesManagedBy = `app.kubernetes.io/managed-by`
 )
 
+// ApplicationLabelSelector returns a label selector for all resources
+// labeled with an application, such as pods created from a template.
+func ApplicationLabelSelector(application string) string {
+	return labels.Set{LabelKubernetesName: application}.String()
+}
+
+// ManagedLabelSelector returns a label selector for all resources deployed by Spinnaker.
+func ManagedLabelSelector() string {
+	return labels.Set{LabelKubernetesManagedBy: spinnaker}.String()
+}
+
+// ManagedApplicationLabelSelector returns a label selector for the resources
+// of an application deployed by Spinnaker, so List calls only return those
+// instead of every resource in the cluster.
+func ManagedApplicationLabelSelector(application string) string {
+	return labels.Set{
+		LabelKubernetesManagedBy: spinnaker,
+		LabelKubernetesName:      application,
+	}.String()
+}
+
 func (c *controller) AddSpinnakerLabels(u *unstructured.Unstructured, application string) error {
 	var err error
 
@@ -234,3 +234,24 @@ var _ = Describe("Annotation", func() {
 		})
 	})
 })
+
+var _ = Describe("Label selectors", func() {
+	Describe("#ApplicationLabelSelector", func() {
+		It("selects resources of the application", func() {
+			Expect(ApplicationLabelSelector("test-application")).To(Equal("app.kubernetes.io/name=test-application"))
+		})
+	})
+
+	Describe("#ManagedLabelSelector", func() {
+		It("selects resources managed by spinnaker", func() {
+			Expect(ManagedLabelSelector()).To(Equal("app.kubernetes.io/managed-by=spinnaker"))
+		})
+	})
+
+	Describe("#ManagedApplicationLabelSelector", func() {
+		It("selects resources of the application managed by spinnaker", func() {
+			Expect(ManagedApplicationLabelSelector("test-application")).
+				To(Equal("app.kubernetes.io/managed-by=spinnaker,app.kubernetes.io/name=test-application"))
+		})
+	})
+})
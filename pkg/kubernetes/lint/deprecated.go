@@ -0,0 +1,22 @@
+package lint
+
+import "k8s.io/apimachinery/pkg/runtime/schema"
+
+// deprecatedAPIVersions maps kinds of deprecated API versions to the API version to use instead.
+var deprecatedAPIVersions = map[schema.GroupVersionKind]string{
+	{Group: "extensions", Version: "v1beta1", Kind: "Deployment"}:               "apps/v1",
+	{Group: "extensions", Version: "v1beta1", Kind: "DaemonSet"}:                "apps/v1",
+	{Group: "extensions", Version: "v1beta1", Kind: "ReplicaSet"}:               "apps/v1",
+	{Group: "extensions", Version: "v1beta1", Kind: "Ingress"}:                  "networking.k8s.io/v1",
+	{Group: "extensions", Version: "v1beta1", Kind: "NetworkPolicy"}:            "networking.k8s.io/v1",
+	{Group: "apps", Version: "v1beta1", Kind: "Deployment"}:                     "apps/v1",
+	{Group: "apps", Version: "v1beta1", Kind: "StatefulSet"}:                    "apps/v1",
+	{Group: "apps", Version: "v1beta2", Kind: "Deployment"}:                     "apps/v1",
+	{Group: "apps", Version: "v1beta2", Kind: "DaemonSet"}:                      "apps/v1",
+	{Group: "apps", Version: "v1beta2", Kind: "ReplicaSet"}:                     "apps/v1",
+	{Group: "apps", Version: "v1beta2", Kind: "StatefulSet"}:                    "apps/v1",
+	{Group: "networking.k8s.io", Version: "v1beta1", Kind: "Ingress"}:           "networking.k8s.io/v1",
+	{Group: "batch", Version: "v1beta1", Kind: "CronJob"}:                       "batch/v1",
+	{Group: "policy", Version: "v1beta1", Kind: "PodDisruptionBudget"}:          "policy/v1",
+	{Group: "autoscaling", Version: "v2beta1", Kind: "HorizontalPodAutoscaler"}: "autoscaling/v2",
+}
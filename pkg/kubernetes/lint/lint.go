@@ -0,0 +1,200 @@
+// Package lint checks manifests for common mistakes before they are deployed.
+package lint
+
+import (
+	"fmt"
+	"strings"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+// Severity of a finding. Findings at or above a linter's FailSeverity fail the deploy.
+type Severity string
+
+const (
+	SeverityInfo    Severity = `info`
+	SeverityWarning Severity = `warning`
+	SeverityError   Severity = `error`
+)
+
+var severityLevels = map[Severity]int{
+	SeverityInfo:    1,
+	SeverityWarning: 2,
+	SeverityError:   3,
+}
+
+const (
+	RuleMissingResourceRequests = `missing-resource-requests`
+	RuleLatestTag               = `latest-tag`
+	RuleDeprecatedAPIVersion    = `deprecated-api-version`
+	RuleMissingSelector         = `missing-selector`
+	RuleNonStringEnvValue       = `non-string-env-value`
+)
+
+// Finding is a problem found in a manifest.
+type Finding struct {
+	Rule     string
+	Severity Severity
+	Message  string
+}
+
+func (f Finding) String() string {
+	return fmt.Sprintf("%s: %s (%s)", f.Severity, f.Message, f.Rule)
+}
+
+// Linter lints manifests. If FailSeverity is set, Failures returns the
+// findings at or above it, otherwise findings are only warnings.
+type Linter struct {
+	FailSeverity Severity
+}
+
+// NewLinter returns a Linter that fails on findings of failSeverity and
+// above. Pass an empty severity to never fail.
+func NewLinter(failSeverity string) (*Linter, error) {
+	s := Severity(strings.ToLower(failSeverity))
+	if _, ok := severityLevels[s]; s != "" && !ok {
+		return nil, fmt.Errorf("unknown lint severity %q", failSeverity)
+	}
+
+	return &Linter{FailSeverity: s}, nil
+}
+
+// Lint returns the findings of a manifest.
+func (l *Linter) Lint(u *unstructured.Unstructured) []Finding {
+	findings := []Finding{}
+	resource := strings.ToLower(u.GetKind()) + " " + u.GetName()
+
+	if replacement, ok := deprecatedAPIVersions[u.GroupVersionKind()]; ok {
+		findings = append(findings, Finding{
+			Rule:     RuleDeprecatedAPIVersion,
+			Severity: SeverityWarning,
+			Message: fmt.Sprintf("%s uses deprecated API version %s, use %s instead",
+				resource, u.GetAPIVersion(), replacement),
+		})
+	}
+
+	if requiresSelector(u) {
+		if _, ok, _ := unstructured.NestedMap(u.Object, "spec", "selector"); !ok {
+			findings = append(findings, Finding{
+				Rule:     RuleMissingSelector,
+				Severity: SeverityError,
+				Message:  fmt.Sprintf("%s has no spec.selector", resource),
+			})
+		}
+	}
+
+	for _, container := range containers(u) {
+		name, _, _ := unstructured.NestedString(container, "name")
+		image, _, _ := unstructured.NestedString(container, "image")
+
+		if latestTag(image) {
+			findings = append(findings, Finding{
+				Rule:     RuleLatestTag,
+				Severity: SeverityWarning,
+				Message:  fmt.Sprintf("%s container %s uses image %s, pin a tag or digest", resource, name, image),
+			})
+		}
+
+		if _, ok, _ := unstructured.NestedMap(container, "resources", "requests"); !ok {
+			findings = append(findings, Finding{
+				Rule:     RuleMissingResourceRequests,
+				Severity: SeverityWarning,
+				Message:  fmt.Sprintf("%s container %s has no resource requests", resource, name),
+			})
+		}
+
+		env, _, _ := unstructured.NestedSlice(container, "env")
+		for _, e := range env {
+			m, ok := e.(map[string]interface{})
+			if !ok {
+				continue
+			}
+
+			if v, ok := m["value"]; ok && v != nil {
+				if _, ok := v.(string); !ok {
+					findings = append(findings, Finding{
+						Rule:     RuleNonStringEnvValue,
+						Severity: SeverityError,
+						Message: fmt.Sprintf("%s container %s env var %v has a non-string value %v, quote it",
+							resource, name, m["name"], v),
+					})
+				}
+			}
+		}
+	}
+
+	return findings
+}
+
+// Failures returns the findings that should fail the deploy.
+func (l *Linter) Failures(findings []Finding) []Finding {
+	failures := []Finding{}
+
+	if l.FailSeverity == "" {
+		return failures
+	}
+
+	for _, f := range findings {
+		if severityLevels[f.Severity] >= severityLevels[l.FailSeverity] {
+			failures = append(failures, f)
+		}
+	}
+
+	return failures
+}
+
+// requiresSelector returns true for workloads whose API version requires a selector.
+func requiresSelector(u *unstructured.Unstructured) bool {
+	if u.GroupVersionKind().Group != "apps" || u.GroupVersionKind().Version != "v1" {
+		return false
+	}
+
+	switch strings.ToLower(u.GetKind()) {
+	case "deployment", "replicaset", "statefulset", "daemonset":
+		return true
+	}
+
+	return false
+}
+
+// containers returns the containers and init containers of a manifest's pod spec.
+func containers(u *unstructured.Unstructured) []map[string]interface{} {
+	var path []string
+
+	switch strings.ToLower(u.GetKind()) {
+	case "pod":
+		path = []string{"spec"}
+	case "deployment", "replicaset", "statefulset", "daemonset", "job", "replicationcontroller":
+		path = []string{"spec", "template", "spec"}
+	case "cronjob":
+		path = []string{"spec", "jobTemplate", "spec", "template", "spec"}
+	default:
+		return nil
+	}
+
+	cs := []map[string]interface{}{}
+
+	for _, field := range []string{"initContainers", "containers"} {
+		list, _, _ := unstructured.NestedSlice(u.Object, append(path, field)...)
+		for _, c := range list {
+			if m, ok := c.(map[string]interface{}); ok {
+				cs = append(cs, m)
+			}
+		}
+	}
+
+	return cs
+}
+
+// latestTag returns true if an image has no tag or digest, or has the latest tag.
+func latestTag(image string) bool {
+	if image == "" || strings.Contains(image, "@") {
+		return false
+	}
+
+	// The tag is after the last colon that is not part of a registry host's port.
+	name := image[strings.LastIndex(image, "/")+1:]
+	i := strings.LastIndex(name, ":")
+
+	return i == -1 || name[i+1:] == "latest"
+}
@@ -0,0 +1,210 @@
+package lint_test
+
+import (
+	. "github.com/billiford/go-clouddriver/pkg/kubernetes/lint"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+var _ = Describe("Lint", func() {
+	var (
+		linter   *Linter
+		u        *unstructured.Unstructured
+		findings []Finding
+		err      error
+	)
+
+	BeforeEach(func() {
+		linter, err = NewLinter("")
+		Expect(err).To(BeNil())
+		u = &unstructured.Unstructured{
+			Object: map[string]interface{}{
+				"apiVersion": "apps/v1",
+				"kind":       "Deployment",
+				"metadata": map[string]interface{}{
+					"name": "test-deployment",
+				},
+				"spec": map[string]interface{}{
+					"selector": map[string]interface{}{
+						"matchLabels": map[string]interface{}{
+							"app": "test",
+						},
+					},
+					"template": map[string]interface{}{
+						"spec": map[string]interface{}{
+							"containers": []interface{}{
+								map[string]interface{}{
+									"name":  "test-container",
+									"image": "nginx:1.19",
+									"resources": map[string]interface{}{
+										"requests": map[string]interface{}{
+											"cpu": "100m",
+										},
+									},
+									"env": []interface{}{
+										map[string]interface{}{
+											"name":  "PORT",
+											"value": "8080",
+										},
+									},
+								},
+							},
+						},
+					},
+				},
+			},
+		}
+	})
+
+	JustBeforeEach(func() {
+		findings = linter.Lint(u)
+	})
+
+	rules := func() []string {
+		rs := []string{}
+		for _, f := range findings {
+			rs = append(rs, f.Rule)
+		}
+
+		return rs
+	}
+
+	containerField := func(field string, value interface{}) {
+		containers, _, _ := unstructured.NestedSlice(u.Object, "spec", "template", "spec", "containers")
+		containers[0].(map[string]interface{})[field] = value
+		_ = unstructured.SetNestedSlice(u.Object, containers, "spec", "template", "spec", "containers")
+	}
+
+	Describe("#NewLinter", func() {
+		When("the severity is unknown", func() {
+			It("returns an error", func() {
+				_, err = NewLinter("fatal")
+				Expect(err).ToNot(BeNil())
+				Expect(err.Error()).To(Equal(`unknown lint severity "fatal"`))
+			})
+		})
+	})
+
+	When("the manifest has no problems", func() {
+		It("returns no findings", func() {
+			Expect(findings).To(BeEmpty())
+		})
+	})
+
+	When("the image has no tag", func() {
+		BeforeEach(func() {
+			containerField("image", "gcr.io/test/nginx")
+		})
+
+		It("warns", func() {
+			Expect(rules()).To(Equal([]string{RuleLatestTag}))
+			Expect(findings[0].String()).To(Equal("warning: deployment test-deployment container test-container " +
+				"uses image gcr.io/test/nginx, pin a tag or digest (latest-tag)"))
+		})
+	})
+
+	When("the image uses the latest tag on a registry with a port", func() {
+		BeforeEach(func() {
+			containerField("image", "localhost:5000/nginx:latest")
+		})
+
+		It("warns", func() {
+			Expect(rules()).To(Equal([]string{RuleLatestTag}))
+		})
+	})
+
+	When("the image is pinned to a digest", func() {
+		BeforeEach(func() {
+			containerField("image", "nginx@sha256:abc")
+		})
+
+		It("does not warn", func() {
+			Expect(findings).To(BeEmpty())
+		})
+	})
+
+	When("a container has no resource requests", func() {
+		BeforeEach(func() {
+			containerField("resources", map[string]interface{}{})
+		})
+
+		It("warns", func() {
+			Expect(rules()).To(Equal([]string{RuleMissingResourceRequests}))
+		})
+	})
+
+	When("an env var value is not a string", func() {
+		BeforeEach(func() {
+			containerField("env", []interface{}{
+				map[string]interface{}{
+					"name":  "PORT",
+					"value": int64(8080),
+				},
+			})
+		})
+
+		It("returns an error finding", func() {
+			Expect(rules()).To(Equal([]string{RuleNonStringEnvValue}))
+			Expect(findings[0].Severity).To(Equal(SeverityError))
+		})
+	})
+
+	When("the selector is missing", func() {
+		BeforeEach(func() {
+			unstructured.RemoveNestedField(u.Object, "spec", "selector")
+		})
+
+		It("returns an error finding", func() {
+			Expect(rules()).To(Equal([]string{RuleMissingSelector}))
+		})
+	})
+
+	When("the API version is deprecated", func() {
+		BeforeEach(func() {
+			u.SetAPIVersion("extensions/v1beta1")
+		})
+
+		It("warns", func() {
+			Expect(rules()).To(Equal([]string{RuleDeprecatedAPIVersion}))
+			Expect(findings[0].Message).To(Equal("deployment test-deployment uses deprecated API version extensions/v1beta1, use apps/v1 instead"))
+		})
+	})
+
+	Describe("#Failures", func() {
+		BeforeEach(func() {
+			containerField("image", "nginx")
+			unstructured.RemoveNestedField(u.Object, "spec", "selector")
+		})
+
+		When("no fail severity is set", func() {
+			It("returns no failures", func() {
+				Expect(linter.Failures(findings)).To(BeEmpty())
+			})
+		})
+
+		When("the fail severity is error", func() {
+			BeforeEach(func() {
+				linter, err = NewLinter("ERROR")
+				Expect(err).To(BeNil())
+			})
+
+			It("returns error findings", func() {
+				failures := linter.Failures(findings)
+				Expect(failures).To(HaveLen(1))
+				Expect(failures[0].Rule).To(Equal(RuleMissingSelector))
+			})
+		})
+
+		When("the fail severity is warning", func() {
+			BeforeEach(func() {
+				linter, err = NewLinter("warning")
+				Expect(err).To(BeNil())
+			})
+
+			It("returns warning and error findings", func() {
+				Expect(linter.Failures(findings)).To(HaveLen(2))
+			})
+		})
+	})
+})
@@ -0,0 +1,172 @@
+package kubernetes
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"strings"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+const (
+	// ConflictManifest keeps the value a manifest sets for a default label or annotation.
+	ConflictManifest = `manifest`
+	// ConflictAccount replaces the value a manifest sets with the account's default.
+	ConflictAccount = `account`
+	// ConflictFail fails the deploy when a manifest sets a different value than the default.
+	ConflictFail = `fail`
+)
+
+var (
+	defaultMetadataConfigPath = "/opt/spinnaker/kubernetes/metadata.json"
+)
+
+// DefaultMetadata are labels and annotations added to every deployed manifest.
+// OnConflict decides what happens when a manifest already sets one of them
+// to a different value, and defaults to keeping the manifest's value.
+type DefaultMetadata struct {
+	Labels      map[string]string `json:"labels,omitempty"`
+	Annotations map[string]string `json:"annotations,omitempty"`
+	OnConflict  string            `json:"onConflict,omitempty"`
+}
+
+// MetadataConfig defines the default labels and annotations of all accounts,
+// with optional additions per account. An account's labels and annotations
+// override those of all accounts with the same key.
+//
+//	{
+//	  "labels": {
+//	    "example.com/managed-by": "spinnaker"
+//	  },
+//	  "accounts": {
+//	    "prod-account": {
+//	      "labels": {
+//	        "example.com/environment": "prod",
+//	        "example.com/cost-center": "1234"
+//	      },
+//	      "onConflict": "fail"
+//	    }
+//	  }
+//	}
+type MetadataConfig struct {
+	DefaultMetadata
+	Accounts map[string]DefaultMetadata `json:"accounts,omitempty"`
+}
+
+// NewDefaultMetadataConfig reads the metadata config from /opt/spinnaker/kubernetes/metadata.json.
+// The config is optional, so if the file does not exist no metadata is added.
+func NewDefaultMetadataConfig() (MetadataConfig, error) {
+	if _, err := os.Stat(defaultMetadataConfigPath); os.IsNotExist(err) {
+		return MetadataConfig{}, nil
+	}
+
+	return NewMetadataConfig(defaultMetadataConfigPath)
+}
+
+// NewMetadataConfig reads the metadata config from a JSON file.
+func NewMetadataConfig(path string) (MetadataConfig, error) {
+	mc := MetadataConfig{}
+
+	b, err := ioutil.ReadFile(path)
+	if err != nil {
+		return mc, err
+	}
+
+	err = json.Unmarshal(b, &mc)
+	if err != nil {
+		return mc, err
+	}
+
+	for account, dm := range mc.Accounts {
+		if err := validateOnConflict(dm.OnConflict); err != nil {
+			return mc, fmt.Errorf("account %s: %w", account, err)
+		}
+	}
+
+	return mc, validateOnConflict(mc.OnConflict)
+}
+
+func validateOnConflict(onConflict string) error {
+	switch onConflict {
+	case "", ConflictManifest, ConflictAccount, ConflictFail:
+		return nil
+	}
+
+	return fmt.Errorf("unknown onConflict %q, must be one of %s, %s or %s",
+		onConflict, ConflictManifest, ConflictAccount, ConflictFail)
+}
+
+// ForAccount returns the default metadata of an account merged with that of all accounts.
+func (mc MetadataConfig) ForAccount(account string) DefaultMetadata {
+	dm := DefaultMetadata{
+		Labels:      map[string]string{},
+		Annotations: map[string]string{},
+		OnConflict:  mc.OnConflict,
+	}
+
+	for _, m := range []DefaultMetadata{mc.DefaultMetadata, mc.Accounts[account]} {
+		for k, v := range m.Labels {
+			dm.Labels[k] = v
+		}
+
+		for k, v := range m.Annotations {
+			dm.Annotations[k] = v
+		}
+	}
+
+	if onConflict := mc.Accounts[account].OnConflict; onConflict != "" {
+		dm.OnConflict = onConflict
+	}
+
+	return dm
+}
+
+// Apply adds the default labels and annotations to a manifest.
+func (dm DefaultMetadata) Apply(u *unstructured.Unstructured) error {
+	labels, err := dm.merge("label", u, u.GetLabels(), dm.Labels)
+	if err != nil {
+		return err
+	}
+
+	annotations, err := dm.merge("annotation", u, u.GetAnnotations(), dm.Annotations)
+	if err != nil {
+		return err
+	}
+
+	if len(labels) > 0 {
+		u.SetLabels(labels)
+	}
+
+	if len(annotations) > 0 {
+		u.SetAnnotations(annotations)
+	}
+
+	return nil
+}
+
+func (dm DefaultMetadata) merge(field string, u *unstructured.Unstructured,
+	current, defaults map[string]string) (map[string]string, error) {
+	if current == nil {
+		current = map[string]string{}
+	}
+
+	for k, v := range defaults {
+		existing, ok := current[k]
+		if !ok || existing == v {
+			current[k] = v
+			continue
+		}
+
+		switch dm.OnConflict {
+		case ConflictAccount:
+			current[k] = v
+		case ConflictFail:
+			return nil, fmt.Errorf("%s %s of %s %s is %q, the account requires %q",
+				field, k, strings.ToLower(u.GetKind()), u.GetName(), existing, v)
+		}
+	}
+
+	return current, nil
+}
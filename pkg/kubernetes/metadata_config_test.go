@@ -0,0 +1,154 @@
+package kubernetes_test
+
+import (
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+
+	. "github.com/billiford/go-clouddriver/pkg/kubernetes"
+)
+
+var _ = Describe("MetadataConfig", func() {
+	var (
+		mc  MetadataConfig
+		dm  DefaultMetadata
+		u   *unstructured.Unstructured
+		err error
+	)
+
+	Describe("#NewMetadataConfig", func() {
+		When("the file does not exist", func() {
+			BeforeEach(func() {
+				mc, err = NewMetadataConfig("test/missing.json")
+			})
+
+			It("returns an error", func() {
+				Expect(err).ToNot(BeNil())
+			})
+		})
+
+		When("onConflict is unknown", func() {
+			BeforeEach(func() {
+				mc, err = NewMetadataConfig("test/metadata-invalid.json")
+			})
+
+			It("returns an error", func() {
+				Expect(err).ToNot(BeNil())
+				Expect(err.Error()).To(Equal(`unknown onConflict "ignore", must be one of manifest, account or fail`))
+			})
+		})
+
+		When("it succeeds", func() {
+			BeforeEach(func() {
+				mc, err = NewMetadataConfig("test/metadata.json")
+			})
+
+			It("reads the metadata", func() {
+				Expect(err).To(BeNil())
+				Expect(mc.Labels).To(HaveKeyWithValue("example.com/environment", "dev"))
+				Expect(mc.Annotations).To(HaveKeyWithValue("example.com/owner", "platform"))
+				Expect(mc.Accounts["prod-account"].OnConflict).To(Equal(ConflictFail))
+			})
+		})
+	})
+
+	Describe("#ForAccount", func() {
+		BeforeEach(func() {
+			mc, err = NewMetadataConfig("test/metadata.json")
+			Expect(err).To(BeNil())
+		})
+
+		When("the account is not configured", func() {
+			It("returns the metadata of all accounts", func() {
+				dm = mc.ForAccount("test-account")
+				Expect(dm.Labels).To(Equal(map[string]string{
+					"example.com/managed-by":  "spinnaker",
+					"example.com/environment": "dev",
+				}))
+				Expect(dm.OnConflict).To(BeEmpty())
+			})
+		})
+
+		When("the account is configured", func() {
+			It("merges the account's metadata", func() {
+				dm = mc.ForAccount("prod-account")
+				Expect(dm.Labels).To(Equal(map[string]string{
+					"example.com/managed-by":  "spinnaker",
+					"example.com/environment": "prod",
+					"example.com/cost-center": "1234",
+				}))
+				Expect(dm.Annotations).To(HaveKeyWithValue("example.com/owner", "platform"))
+				Expect(dm.OnConflict).To(Equal(ConflictFail))
+			})
+		})
+	})
+
+	Describe("#Apply", func() {
+		BeforeEach(func() {
+			dm = DefaultMetadata{
+				Labels: map[string]string{
+					"example.com/environment": "prod",
+				},
+				Annotations: map[string]string{
+					"example.com/owner": "platform",
+				},
+			}
+			u = &unstructured.Unstructured{
+				Object: map[string]interface{}{
+					"kind": "Deployment",
+					"metadata": map[string]interface{}{
+						"name": "test-deployment",
+						"labels": map[string]interface{}{
+							"example.com/environment": "dev",
+						},
+					},
+				},
+			}
+		})
+
+		JustBeforeEach(func() {
+			err = dm.Apply(u)
+		})
+
+		When("onConflict is not set", func() {
+			It("keeps the manifest's value", func() {
+				Expect(err).To(BeNil())
+				Expect(u.GetLabels()).To(HaveKeyWithValue("example.com/environment", "dev"))
+				Expect(u.GetAnnotations()).To(HaveKeyWithValue("example.com/owner", "platform"))
+			})
+		})
+
+		When("onConflict is account", func() {
+			BeforeEach(func() {
+				dm.OnConflict = ConflictAccount
+			})
+
+			It("sets the account's value", func() {
+				Expect(err).To(BeNil())
+				Expect(u.GetLabels()).To(HaveKeyWithValue("example.com/environment", "prod"))
+			})
+		})
+
+		When("onConflict is fail", func() {
+			BeforeEach(func() {
+				dm.OnConflict = ConflictFail
+			})
+
+			It("returns an error", func() {
+				Expect(err).ToNot(BeNil())
+				Expect(err.Error()).To(Equal(`label example.com/environment of deployment test-deployment is "dev", the account requires "prod"`))
+			})
+		})
+
+		When("the manifest sets the same value", func() {
+			BeforeEach(func() {
+				dm.OnConflict = ConflictFail
+				u.SetLabels(map[string]string{"example.com/environment": "prod"})
+			})
+
+			It("succeeds", func() {
+				Expect(err).To(BeNil())
+			})
+		})
+	})
+})
@@ -0,0 +1,111 @@
+package kubernetes
+
+import (
+	"sync"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+const (
+	NamespaceCacheInstanceKey = `KubeNamespaceCache`
+)
+
+// NamespaceCache holds a list of namespaces per account for a given TTL.
+// Listing namespaces requires a round trip to each cluster, so endpoints
+// that only need namespace names should read them from here first.
+//
+//go:generate counterfeiter . NamespaceCache
+type NamespaceCache interface {
+	Get(string) ([]string, bool)
+	Set(string, []string)
+	// SetForProvider caches the namespaces of a provider for its namespaces
+	// cache interval, if it has one.
+	SetForProvider(Provider, []string)
+	Delete(string)
+}
+
+// NewNamespaceCache returns a NamespaceCache whose entries expire after ttl.
+func NewNamespaceCache(ttl time.Duration) NamespaceCache {
+	return NewNamespaceCacheWithConfig(ttl, CacheConfig{})
+}
+
+// NewNamespaceCacheWithConfig returns a NamespaceCache whose entries expire
+// after the namespaces interval of the account in cc, or ttl if not configured.
+func NewNamespaceCacheWithConfig(ttl time.Duration, cc CacheConfig) NamespaceCache {
+	return &namespaceCache{
+		ttl:     ttl,
+		config:  cc,
+		entries: map[string]namespaceCacheEntry{},
+	}
+}
+
+type namespaceCache struct {
+	mux     sync.RWMutex
+	ttl     time.Duration
+	config  CacheConfig
+	entries map[string]namespaceCacheEntry
+}
+
+type namespaceCacheEntry struct {
+	namespaces []string
+	expiresAt  time.Time
+}
+
+// Get returns the namespaces for an account and true if the entry
+// exists and has not yet expired.
+func (nc *namespaceCache) Get(account string) ([]string, bool) {
+	nc.mux.RLock()
+	defer nc.mux.RUnlock()
+
+	entry, ok := nc.entries[account]
+	if !ok || time.Now().After(entry.expiresAt) {
+		return nil, false
+	}
+
+	namespaces := make([]string, len(entry.namespaces))
+	copy(namespaces, entry.namespaces)
+
+	return namespaces, true
+}
+
+// Set caches the namespaces for an account.
+func (nc *namespaceCache) Set(account string, namespaces []string) {
+	nc.set(account, namespaces, nc.config.Interval(account, CacheKindNamespaces, nc.ttl))
+}
+
+// SetForProvider caches the namespaces of a provider. Its namespaces
+// interval takes precedence over the cache config.
+func (nc *namespaceCache) SetForProvider(p Provider, namespaces []string) {
+	if seconds := p.CacheIntervalSeconds[CacheKindNamespaces]; seconds > 0 {
+		nc.set(p.Name, namespaces, time.Duration(seconds)*time.Second)
+		return
+	}
+
+	nc.Set(p.Name, namespaces)
+}
+
+func (nc *namespaceCache) set(account string, namespaces []string, interval time.Duration) {
+	nc.mux.Lock()
+	defer nc.mux.Unlock()
+
+	ns := make([]string, len(namespaces))
+	copy(ns, namespaces)
+
+	nc.entries[account] = namespaceCacheEntry{
+		namespaces: ns,
+		expiresAt:  time.Now().Add(interval),
+	}
+}
+
+// Delete removes an account's namespaces from the cache.
+func (nc *namespaceCache) Delete(account string) {
+	nc.mux.Lock()
+	defer nc.mux.Unlock()
+
+	delete(nc.entries, account)
+}
+
+func NamespaceCacheInstance(c *gin.Context) NamespaceCache {
+	return c.MustGet(NamespaceCacheInstanceKey).(NamespaceCache)
+}
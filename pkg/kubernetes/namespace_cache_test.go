@@ -0,0 +1,118 @@
+package kubernetes_test
+
+import (
+	"time"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	. "github.com/billiford/go-clouddriver/pkg/kubernetes"
+)
+
+var _ = Describe("NamespaceCache", func() {
+	var (
+		nc         NamespaceCache
+		ttl        time.Duration
+		namespaces []string
+		ok         bool
+	)
+
+	BeforeEach(func() {
+		ttl = time.Minute
+	})
+
+	JustBeforeEach(func() {
+		nc = NewNamespaceCache(ttl)
+	})
+
+	Describe("#Get", func() {
+		When("the account is not cached", func() {
+			It("returns false", func() {
+				namespaces, ok = nc.Get("test-account")
+				Expect(ok).To(BeFalse())
+				Expect(namespaces).To(BeNil())
+			})
+		})
+
+		When("the entry has expired", func() {
+			BeforeEach(func() {
+				ttl = time.Millisecond
+			})
+
+			It("returns false", func() {
+				nc.Set("test-account", []string{"namespace1"})
+				time.Sleep(2 * time.Millisecond)
+				namespaces, ok = nc.Get("test-account")
+				Expect(ok).To(BeFalse())
+			})
+		})
+
+		When("the account's interval is configured", func() {
+			JustBeforeEach(func() {
+				nc = NewNamespaceCacheWithConfig(ttl, CacheConfig{
+					Accounts: map[string]map[string]int{
+						"other-account": {
+							CacheKindNamespaces: 60,
+						},
+					},
+				})
+			})
+
+			BeforeEach(func() {
+				ttl = time.Millisecond
+			})
+
+			It("uses the account's interval", func() {
+				nc.Set("test-account", []string{"namespace1"})
+				nc.Set("other-account", []string{"namespace1"})
+				time.Sleep(2 * time.Millisecond)
+				_, ok = nc.Get("test-account")
+				Expect(ok).To(BeFalse())
+				_, ok = nc.Get("other-account")
+				Expect(ok).To(BeTrue())
+			})
+		})
+
+		When("the provider's interval is set", func() {
+			BeforeEach(func() {
+				ttl = time.Millisecond
+			})
+
+			It("uses the provider's interval", func() {
+				nc.SetForProvider(Provider{Name: "test-account"}, []string{"namespace1"})
+				nc.SetForProvider(Provider{
+					Name:                 "other-account",
+					CacheIntervalSeconds: ProviderCacheIntervals{CacheKindNamespaces: 60},
+				}, []string{"namespace1"})
+				time.Sleep(2 * time.Millisecond)
+				_, ok = nc.Get("test-account")
+				Expect(ok).To(BeFalse())
+				namespaces, ok = nc.Get("other-account")
+				Expect(ok).To(BeTrue())
+				Expect(namespaces).To(Equal([]string{"namespace1"}))
+			})
+		})
+
+		When("the entry has been deleted", func() {
+			It("returns false", func() {
+				nc.Set("test-account", []string{"namespace1"})
+				nc.Delete("test-account")
+				namespaces, ok = nc.Get("test-account")
+				Expect(ok).To(BeFalse())
+			})
+		})
+
+		When("the account is cached", func() {
+			It("returns a copy of the namespaces", func() {
+				nc.Set("test-account", []string{"namespace1", "namespace2"})
+				namespaces, ok = nc.Get("test-account")
+				Expect(ok).To(BeTrue())
+				Expect(namespaces).To(Equal([]string{"namespace1", "namespace2"}))
+
+				namespaces[0] = "modified"
+				namespaces, _ = nc.Get("test-account")
+				Expect(namespaces[0]).To(Equal("namespace1"))
+			})
+		})
+	})
+})
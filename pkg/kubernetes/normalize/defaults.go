@@ -0,0 +1,156 @@
+package normalize
+
+import (
+	"strings"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+// defaults are fields the API server defaults, and their default.
+type defaults map[string]interface{}
+
+// specDefaults are the defaults of the spec of each kind.
+var specDefaults = map[string]defaults{
+	"cronjob": {
+		"concurrencyPolicy":          "Allow",
+		"failedJobsHistoryLimit":     1,
+		"successfulJobsHistoryLimit": 3,
+		"suspend":                    false,
+	},
+	"daemonset": {
+		"revisionHistoryLimit": 10,
+	},
+	"deployment": {
+		"progressDeadlineSeconds": 600,
+		"revisionHistoryLimit":    10,
+	},
+	"job": {
+		"backoffLimit": 6,
+	},
+	"service": {
+		"sessionAffinity": "None",
+		"type":            "ClusterIP",
+	},
+	"statefulset": {
+		"podManagementPolicy":  "OrderedReady",
+		"revisionHistoryLimit": 10,
+	},
+}
+
+// podSpecFields are where each kind with pods has the spec of its pods.
+var podSpecFields = map[string][]string{
+	"cronjob":     {"spec", "jobTemplate", "spec", "template", "spec"},
+	"daemonset":   {"spec", "template", "spec"},
+	"deployment":  {"spec", "template", "spec"},
+	"job":         {"spec", "template", "spec"},
+	"pod":         {"spec"},
+	"replicaset":  {"spec", "template", "spec"},
+	"statefulset": {"spec", "template", "spec"},
+}
+
+var podSpecDefaults = defaults{
+	"dnsPolicy":                     "ClusterFirst",
+	"restartPolicy":                 "Always",
+	"schedulerName":                 "default-scheduler",
+	"securityContext":               map[string]interface{}{},
+	"terminationGracePeriodSeconds": 30,
+}
+
+var containerDefaults = defaults{
+	"terminationMessagePath":   "/dev/termination-log",
+	"terminationMessagePolicy": "File",
+}
+
+var portDefaults = defaults{
+	"protocol": "TCP",
+}
+
+// pruneDefaults removes the fields of u set to their default.
+func pruneDefaults(u *unstructured.Unstructured) {
+	kind := strings.ToLower(u.GetKind())
+
+	if d, ok := specDefaults[kind]; ok {
+		spec := nestedMap(u.Object, "spec")
+		d.prune(spec)
+
+		if kind == "service" {
+			pruneEach(spec["ports"], portDefaults)
+		}
+	}
+
+	fields, ok := podSpecFields[kind]
+	if !ok {
+		return
+	}
+
+	podSpec := nestedMap(u.Object, fields...)
+	podSpecDefaults.prune(podSpec)
+
+	for _, key := range []string{"containers", "initContainers"} {
+		containers, _ := podSpec[key].([]interface{})
+		for _, container := range containers {
+			if c, ok := container.(map[string]interface{}); ok {
+				containerDefaults.prune(c)
+				pruneEach(c["ports"], portDefaults)
+			}
+		}
+	}
+}
+
+// pruneEach prunes the defaults of each map in a list.
+func pruneEach(list interface{}, d defaults) {
+	l, _ := list.([]interface{})
+	for _, item := range l {
+		if m, ok := item.(map[string]interface{}); ok {
+			d.prune(m)
+		}
+	}
+}
+
+// prune removes the keys of m set to their default.
+func (d defaults) prune(m map[string]interface{}) {
+	for key, def := range d {
+		if value, ok := m[key]; ok && isDefault(value, def) {
+			delete(m, key)
+		}
+	}
+}
+
+func isDefault(value, def interface{}) bool {
+	if _, ok := def.(map[string]interface{}); ok {
+		m, ok := value.(map[string]interface{})
+		return ok && len(m) == 0
+	}
+
+	if n, ok := def.(int); ok {
+		f, ok := number(value)
+		return ok && f == float64(n)
+	}
+
+	return value == def
+}
+
+// number returns a number of a manifest decoded from JSON or YAML as a float.
+func number(v interface{}) (float64, bool) {
+	switch n := v.(type) {
+	case int:
+		return float64(n), true
+	case int32:
+		return float64(n), true
+	case int64:
+		return float64(n), true
+	case float64:
+		return n, true
+	default:
+		return 0, false
+	}
+}
+
+// nestedMap returns the map at fields of obj, without copying it so it can
+// be changed, or nil if there is none.
+func nestedMap(obj map[string]interface{}, fields ...string) map[string]interface{} {
+	v, _, _ := unstructured.NestedFieldNoCopy(obj, fields...)
+	m, _ := v.(map[string]interface{})
+
+	return m
+}
@@ -0,0 +1,154 @@
+// Package normalize normalizes manifests the way clouddriver compares them,
+// so that two manifests of a resource are equal when they only differ in
+// what the API server and its controllers set on it, such as its status,
+// managed fields and defaulted fields.
+//
+// Normalize, Marshal and Equal are a stable API for tools outside
+// clouddriver. Fields added to Options keep the zero value normalizing
+// fully.
+package normalize
+
+import (
+	"encoding/json"
+	"strings"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+// IgnoredAnnotations are set by kubectl, the API server or controllers and
+// are removed from manifests.
+var IgnoredAnnotations = []string{
+	"kubectl.kubernetes.io/last-applied-configuration",
+	"deployment.kubernetes.io/revision",
+}
+
+// serverMetadata are the metadata fields the API server sets.
+var serverMetadata = []string{
+	"uid",
+	"resourceVersion",
+	"selfLink",
+	"generation",
+	"creationTimestamp",
+	"deletionTimestamp",
+	"deletionGracePeriodSeconds",
+}
+
+// Options are what Normalize keeps. The zero value keeps none of it.
+type Options struct {
+	// KeepStatus keeps the status of a manifest.
+	KeepStatus bool
+	// KeepManagedFields keeps the fields managed by server-side apply.
+	KeepManagedFields bool
+	// KeepDefaults keeps fields set to the default the API server gives
+	// them, such as the DNS policy of pods, and fields set to null.
+	KeepDefaults bool
+}
+
+// Normalize returns a normalized copy of u with the zero Options.
+func Normalize(u *unstructured.Unstructured) *unstructured.Unstructured {
+	return Options{}.Normalize(u)
+}
+
+// Marshal returns u normalized with the zero Options as JSON. Keys are
+// sorted, so manifests that are equal marshal to the same bytes.
+func Marshal(u *unstructured.Unstructured) ([]byte, error) {
+	return Options{}.Marshal(u)
+}
+
+// Equal returns true if a and b are equal normalized with the zero Options.
+func Equal(a, b *unstructured.Unstructured) bool {
+	return Options{}.Equal(a, b)
+}
+
+// Normalize returns a normalized copy of u. It removes the metadata set by
+// the API server, such as the UID and resource version, the annotations in
+// IgnoredAnnotations and the cluster IPs of services, and whatever o does not
+// keep.
+func (o Options) Normalize(u *unstructured.Unstructured) *unstructured.Unstructured {
+	n := u.DeepCopy()
+
+	for _, field := range serverMetadata {
+		unstructured.RemoveNestedField(n.Object, "metadata", field)
+	}
+
+	if !o.KeepManagedFields {
+		unstructured.RemoveNestedField(n.Object, "metadata", "managedFields")
+	}
+
+	if !o.KeepStatus {
+		unstructured.RemoveNestedField(n.Object, "status")
+	}
+
+	annotations := n.GetAnnotations()
+	for _, a := range IgnoredAnnotations {
+		delete(annotations, a)
+	}
+
+	if len(annotations) == 0 {
+		annotations = nil
+	}
+
+	n.SetAnnotations(annotations)
+
+	// Cluster IPs are allocated by the cluster.
+	if strings.EqualFold(n.GetKind(), "service") {
+		unstructured.RemoveNestedField(n.Object, "spec", "clusterIP")
+		unstructured.RemoveNestedField(n.Object, "spec", "clusterIPs")
+	}
+
+	if !o.KeepDefaults {
+		pruneDefaults(n)
+		pruneNulls(n.Object)
+	}
+
+	return n
+}
+
+// Marshal returns u normalized with o as JSON, its keys sorted.
+func (o Options) Marshal(u *unstructured.Unstructured) ([]byte, error) {
+	return json.Marshal(o.Normalize(u).Object)
+}
+
+// Equal returns true if a and b are equal normalized with o. Numbers are
+// compared by value, so manifests decoded from YAML and JSON compare equal.
+func (o Options) Equal(a, b *unstructured.Unstructured) bool {
+	x, err := o.Marshal(a)
+	if err != nil {
+		return false
+	}
+
+	y, err := o.Marshal(b)
+	if err != nil {
+		return false
+	}
+
+	return string(x) == string(y)
+}
+
+// pruneNulls removes the keys of m, at any depth, set to null and the maps
+// it leaves empty, such as pod template metadata with only a null creation
+// timestamp.
+func pruneNulls(m map[string]interface{}) {
+	for key, value := range m {
+		switch t := value.(type) {
+		case nil:
+			delete(m, key)
+		case map[string]interface{}:
+			if len(t) == 0 {
+				continue
+			}
+
+			pruneNulls(t)
+
+			if len(t) == 0 {
+				delete(m, key)
+			}
+		case []interface{}:
+			for _, item := range t {
+				if im, ok := item.(map[string]interface{}); ok {
+					pruneNulls(im)
+				}
+			}
+		}
+	}
+}
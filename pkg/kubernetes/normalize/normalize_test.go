@@ -0,0 +1,210 @@
+package normalize_test
+
+import (
+	"github.com/billiford/go-clouddriver/pkg/kubernetes/normalize"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+var _ = Describe("Normalize", func() {
+	var (
+		u       *unstructured.Unstructured
+		applied *unstructured.Unstructured
+		opts    normalize.Options
+		n       *unstructured.Unstructured
+	)
+
+	BeforeEach(func() {
+		opts = normalize.Options{}
+		applied = &unstructured.Unstructured{
+			Object: map[string]interface{}{
+				"apiVersion": "apps/v1",
+				"kind":       "Deployment",
+				"metadata": map[string]interface{}{
+					"name":      "test-deployment",
+					"namespace": "default",
+				},
+				"spec": map[string]interface{}{
+					"replicas": int64(2),
+					"template": map[string]interface{}{
+						"spec": map[string]interface{}{
+							"containers": []interface{}{
+								map[string]interface{}{
+									"name":  "test-container",
+									"image": "nginx:1.19",
+									"ports": []interface{}{
+										map[string]interface{}{
+											"containerPort": int64(80),
+										},
+									},
+								},
+							},
+						},
+					},
+				},
+			},
+		}
+		u = &unstructured.Unstructured{
+			Object: map[string]interface{}{
+				"apiVersion": "apps/v1",
+				"kind":       "Deployment",
+				"metadata": map[string]interface{}{
+					"name":              "test-deployment",
+					"namespace":         "default",
+					"uid":               "test-uid",
+					"resourceVersion":   "100",
+					"generation":        int64(3),
+					"creationTimestamp": "2021-01-01T00:00:00Z",
+					"managedFields": []interface{}{
+						map[string]interface{}{"manager": "kubectl"},
+					},
+					"annotations": map[string]interface{}{
+						"kubectl.kubernetes.io/last-applied-configuration": "{}",
+						"deployment.kubernetes.io/revision":                "3",
+					},
+				},
+				"spec": map[string]interface{}{
+					"replicas":                int64(2),
+					"progressDeadlineSeconds": int64(600),
+					"revisionHistoryLimit":    int64(10),
+					"template": map[string]interface{}{
+						"metadata": map[string]interface{}{
+							"creationTimestamp": nil,
+						},
+						"spec": map[string]interface{}{
+							"dnsPolicy":                     "ClusterFirst",
+							"restartPolicy":                 "Always",
+							"schedulerName":                 "default-scheduler",
+							"securityContext":               map[string]interface{}{},
+							"terminationGracePeriodSeconds": int64(30),
+							"containers": []interface{}{
+								map[string]interface{}{
+									"name":                     "test-container",
+									"image":                    "nginx:1.19",
+									"terminationMessagePath":   "/dev/termination-log",
+									"terminationMessagePolicy": "File",
+									"ports": []interface{}{
+										map[string]interface{}{
+											"containerPort": int64(80),
+											"protocol":      "TCP",
+										},
+									},
+								},
+							},
+						},
+					},
+				},
+				"status": map[string]interface{}{
+					"replicas": int64(2),
+				},
+			},
+		}
+	})
+
+	JustBeforeEach(func() {
+		n = opts.Normalize(u)
+	})
+
+	It("does not change the manifest", func() {
+		Expect(string(u.GetUID())).To(Equal("test-uid"))
+		Expect(u.Object).To(HaveKey("status"))
+	})
+
+	It("removes what the API server set", func() {
+		Expect(n.GetUID()).To(BeEmpty())
+		Expect(n.GetResourceVersion()).To(BeEmpty())
+		Expect(n.Object["metadata"]).ToNot(HaveKey("generation"))
+		Expect(n.Object["metadata"]).ToNot(HaveKey("creationTimestamp"))
+		Expect(n.Object["metadata"]).ToNot(HaveKey("managedFields"))
+		Expect(n.Object["metadata"]).ToNot(HaveKey("annotations"))
+		Expect(n.Object).ToNot(HaveKey("status"))
+	})
+
+	It("equals the applied manifest", func() {
+		Expect(opts.Equal(u, applied)).To(BeTrue())
+		Expect(normalize.Equal(u, applied)).To(BeTrue())
+	})
+
+	It("marshals with sorted keys", func() {
+		b, err := normalize.Marshal(applied)
+		Expect(err).To(BeNil())
+		Expect(string(b)).To(HavePrefix(`{"apiVersion":"apps/v1","kind":"Deployment","metadata":{"name":"test-deployment","namespace":"default"},"spec":{"replicas":2,`))
+	})
+
+	When("a field is not the default", func() {
+		BeforeEach(func() {
+			_ = unstructured.SetNestedField(u.Object, int64(5), "spec", "revisionHistoryLimit")
+		})
+
+		It("keeps it", func() {
+			Expect(n.Object["spec"]).To(HaveKeyWithValue("revisionHistoryLimit", int64(5)))
+			Expect(opts.Equal(u, applied)).To(BeFalse())
+		})
+	})
+
+	When("the manifest has other annotations", func() {
+		BeforeEach(func() {
+			u.SetAnnotations(map[string]string{
+				"deployment.kubernetes.io/revision": "3",
+				"owner":                             "team-a",
+			})
+		})
+
+		It("keeps them", func() {
+			Expect(n.GetAnnotations()).To(Equal(map[string]string{"owner": "team-a"}))
+		})
+	})
+
+	When("the manifest is a service", func() {
+		BeforeEach(func() {
+			u = &unstructured.Unstructured{
+				Object: map[string]interface{}{
+					"apiVersion": "v1",
+					"kind":       "Service",
+					"metadata": map[string]interface{}{
+						"name": "test-service",
+					},
+					"spec": map[string]interface{}{
+						"clusterIP":       "10.0.0.1",
+						"clusterIPs":      []interface{}{"10.0.0.1"},
+						"sessionAffinity": "None",
+						"type":            "ClusterIP",
+						"ports": []interface{}{
+							map[string]interface{}{
+								"port":     int64(80),
+								"protocol": "TCP",
+							},
+						},
+					},
+				},
+			}
+		})
+
+		It("removes its cluster IPs and defaults", func() {
+			Expect(n.Object["spec"]).To(Equal(map[string]interface{}{
+				"ports": []interface{}{
+					map[string]interface{}{"port": int64(80)},
+				},
+			}))
+		})
+	})
+
+	When("keeping everything", func() {
+		BeforeEach(func() {
+			opts = normalize.Options{
+				KeepStatus:        true,
+				KeepManagedFields: true,
+				KeepDefaults:      true,
+			}
+		})
+
+		It("only removes what the API server set", func() {
+			Expect(n.GetUID()).To(BeEmpty())
+			Expect(n.Object).To(HaveKey("status"))
+			Expect(n.Object["metadata"]).To(HaveKey("managedFields"))
+			Expect(n.Object["spec"]).To(HaveKey("revisionHistoryLimit"))
+			Expect(opts.Equal(u, applied)).To(BeFalse())
+		})
+	})
+})
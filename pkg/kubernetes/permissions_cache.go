@@ -0,0 +1,112 @@
+package kubernetes
+
+import (
+	"sync"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+const (
+	PermissionsCacheInstanceKey = `KubePermissionsCache`
+)
+
+// PermissionsCache holds the groups per account for a given TTL.
+// Permissions are read on every call to /credentials, so they are cached
+// and removed from the cache when an account is created or deleted.
+//
+//go:generate counterfeiter . PermissionsCache
+type PermissionsCache interface {
+	Get(string) (ProviderPermissions, bool)
+	Set(string, ProviderPermissions)
+	Delete(string)
+}
+
+// NewPermissionsCache returns a PermissionsCache whose entries expire after ttl.
+func NewPermissionsCache(ttl time.Duration) PermissionsCache {
+	return &permissionsCache{
+		ttl:     ttl,
+		entries: map[string]permissionsCacheEntry{},
+	}
+}
+
+type permissionsCache struct {
+	mux     sync.RWMutex
+	ttl     time.Duration
+	entries map[string]permissionsCacheEntry
+}
+
+type permissionsCacheEntry struct {
+	permissions ProviderPermissions
+	expiresAt   time.Time
+}
+
+// Get returns the permissions for an account and true if the entry
+// exists and has not yet expired.
+func (pc *permissionsCache) Get(account string) (ProviderPermissions, bool) {
+	pc.mux.RLock()
+	defer pc.mux.RUnlock()
+
+	entry, ok := pc.entries[account]
+	if !ok || time.Now().After(entry.expiresAt) {
+		return ProviderPermissions{}, false
+	}
+
+	return copyPermissions(entry.permissions), true
+}
+
+// Set caches the permissions for an account.
+func (pc *permissionsCache) Set(account string, permissions ProviderPermissions) {
+	pc.mux.Lock()
+	defer pc.mux.Unlock()
+
+	pc.entries[account] = permissionsCacheEntry{
+		permissions: copyPermissions(permissions),
+		expiresAt:   time.Now().Add(pc.ttl),
+	}
+}
+
+// Delete removes an account's permissions from the cache.
+func (pc *permissionsCache) Delete(account string) {
+	pc.mux.Lock()
+	defer pc.mux.Unlock()
+
+	delete(pc.entries, account)
+}
+
+func copyPermissions(p ProviderPermissions) ProviderPermissions {
+	c := ProviderPermissions{
+		Read:    copyGroups(p.Read),
+		Write:   copyGroups(p.Write),
+		Execute: copyGroups(p.Execute),
+	}
+
+	if p.Namespaces != nil {
+		c.Namespaces = map[string]ProviderNamespacePermissions{}
+
+		for namespace, np := range p.Namespaces {
+			c.Namespaces[namespace] = ProviderNamespacePermissions{
+				Read:    copyGroups(np.Read),
+				Write:   copyGroups(np.Write),
+				Execute: copyGroups(np.Execute),
+			}
+		}
+	}
+
+	return c
+}
+
+func copyGroups(groups []string) []string {
+	if groups == nil {
+		return nil
+	}
+
+	c := make([]string, len(groups))
+	copy(c, groups)
+
+	return c
+}
+
+func PermissionsCacheInstance(c *gin.Context) PermissionsCache {
+	return c.MustGet(PermissionsCacheInstanceKey).(PermissionsCache)
+}
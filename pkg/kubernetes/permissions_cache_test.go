@@ -0,0 +1,94 @@
+package kubernetes_test
+
+import (
+	"time"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	. "github.com/billiford/go-clouddriver/pkg/kubernetes"
+)
+
+var _ = Describe("PermissionsCache", func() {
+	var (
+		pc          PermissionsCache
+		ttl         time.Duration
+		permissions ProviderPermissions
+		ok          bool
+	)
+
+	BeforeEach(func() {
+		ttl = time.Minute
+	})
+
+	JustBeforeEach(func() {
+		pc = NewPermissionsCache(ttl)
+	})
+
+	Describe("#Get", func() {
+		When("the account is not cached", func() {
+			It("returns false", func() {
+				permissions, ok = pc.Get("test-account")
+				Expect(ok).To(BeFalse())
+				Expect(permissions.Read).To(BeNil())
+				Expect(permissions.Write).To(BeNil())
+			})
+		})
+
+		When("the entry has expired", func() {
+			BeforeEach(func() {
+				ttl = time.Millisecond
+			})
+
+			It("returns false", func() {
+				pc.Set("test-account", ProviderPermissions{Read: []string{"group1"}})
+				time.Sleep(2 * time.Millisecond)
+				_, ok = pc.Get("test-account")
+				Expect(ok).To(BeFalse())
+			})
+		})
+
+		When("the entry has been deleted", func() {
+			It("returns false", func() {
+				pc.Set("test-account", ProviderPermissions{Read: []string{"group1"}})
+				pc.Delete("test-account")
+				_, ok = pc.Get("test-account")
+				Expect(ok).To(BeFalse())
+			})
+		})
+
+		When("the entry is cached", func() {
+			It("returns a copy of the permissions", func() {
+				pc.Set("test-account", ProviderPermissions{
+					Read:  []string{"group1"},
+					Write: []string{"group2"},
+				})
+				permissions, ok = pc.Get("test-account")
+				Expect(ok).To(BeTrue())
+				Expect(permissions.Read).To(Equal([]string{"group1"}))
+				Expect(permissions.Write).To(Equal([]string{"group2"}))
+
+				permissions.Read[0] = "changed"
+				permissions, _ = pc.Get("test-account")
+				Expect(permissions.Read).To(Equal([]string{"group1"}))
+			})
+
+			It("returns a copy of the namespace permissions", func() {
+				pc.Set("test-account", ProviderPermissions{
+					Execute: []string{"group3"},
+					Namespaces: map[string]ProviderNamespacePermissions{
+						"namespace1": {Execute: []string{"group4"}},
+					},
+				})
+				permissions, ok = pc.Get("test-account")
+				Expect(ok).To(BeTrue())
+				Expect(permissions.Execute).To(Equal([]string{"group3"}))
+				Expect(permissions.Namespaces["namespace1"].Execute).To(Equal([]string{"group4"}))
+
+				permissions.Namespaces["namespace1"].Execute[0] = "changed"
+				permissions, _ = pc.Get("test-account")
+				Expect(permissions.Namespaces["namespace1"].Execute).To(Equal([]string{"group4"}))
+			})
+		})
+	})
+})
@@ -0,0 +1,79 @@
+package kubernetes
+
+import (
+	"context"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/client-go/rest"
+)
+
+// Built-in resources are requested as protobuf, which is much cheaper than JSON
+// to encode and decode for large lists. JSON is accepted as a fallback.
+const protobufAcceptContentTypes = runtime.ContentTypeProtobuf + "," + runtime.ContentTypeJSON
+
+// builtInKind returns the kind of a resource and true if it is a built-in
+// resource that can be decoded from protobuf. Custom resources are never
+// served as protobuf, so they are listed as JSON.
+func (c *client) builtInKind(gvr schema.GroupVersionResource) (schema.GroupVersionKind, bool) {
+	gvk, err := c.mapper.KindFor(gvr)
+	if err != nil {
+		return schema.GroupVersionKind{}, false
+	}
+
+	return gvk, scheme.Scheme.Recognizes(gvk.GroupVersion().WithKind(gvk.Kind + "List"))
+}
+
+// listUsingProtobuf lists a built-in resource using protobuf and converts
+// the typed list to unstructured.
+func (c *client) listUsingProtobuf(gvk schema.GroupVersionKind,
+	gvr schema.GroupVersionResource, lo metav1.ListOptions) (*unstructured.UnstructuredList, error) {
+	restClient, err := newProtobufRestClient(*c.config, gvr.GroupVersion())
+	if err != nil {
+		return nil, err
+	}
+
+	obj, err := restClient.Get().
+		Resource(gvr.Resource).
+		VersionedParams(&lo, scheme.ParameterCodec).
+		Do(context.TODO()).
+		Get()
+	if err != nil {
+		return nil, err
+	}
+
+	content, err := runtime.DefaultUnstructuredConverter.ToUnstructured(obj)
+	if err != nil {
+		return nil, err
+	}
+
+	list := &unstructured.UnstructuredList{}
+	list.SetUnstructuredContent(content)
+
+	// Decoded typed objects do not have their kind set.
+	list.SetGroupVersionKind(gvk.GroupVersion().WithKind(gvk.Kind + "List"))
+
+	for i := range list.Items {
+		list.Items[i].SetGroupVersionKind(gvk)
+	}
+
+	return list, nil
+}
+
+func newProtobufRestClient(restConfig rest.Config, gv schema.GroupVersion) (rest.Interface, error) {
+	restConfig.GroupVersion = &gv
+	restConfig.ContentType = runtime.ContentTypeProtobuf
+	restConfig.AcceptContentTypes = protobufAcceptContentTypes
+	restConfig.NegotiatedSerializer = scheme.Codecs.WithoutConversion()
+
+	if len(gv.Group) == 0 {
+		restConfig.APIPath = "/api"
+	} else {
+		restConfig.APIPath = "/apis"
+	}
+
+	return rest.RESTClientFor(&restConfig)
+}
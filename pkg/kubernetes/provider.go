@@ -1,18 +1,464 @@
 package kubernetes
 
+import (
+	"database/sql/driver"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+// ProviderInstanceKey holds the provider of the account a request is for,
+// set by the middleware that validates the account.
+const ProviderInstanceKey = `KubeProvider`
+
+// Write modes of a provider.
+const (
+	// WriteModeEnforced applies changes to the cluster, the default.
+	WriteModeEnforced = `enforced`
+	// WriteModeDryRun only runs changes as server-side dry-run requests.
+	WriteModeDryRun = `dryRun`
+)
+
+// PrimaryCluster names the cluster at the host of a provider, which is
+// changed by operations that do not target one of its other clusters.
+const PrimaryCluster = `primary`
+
 type Provider struct {
-	Name        string              `json:"name" gorm:"primary_key"`
-	Host        string              `json:"host"`
-	CAData      string              `json:"caData" gorm:"size:2048"`
-	BearerToken string              `json:"bearerToken,omitempty" gorm:"size:2048"`
+	Name        string `json:"name" gorm:"primary_key"`
+	Host        string `json:"host"`
+	CAData      string `json:"caData" gorm:"size:2048"`
+	BearerToken string `json:"bearerToken,omitempty" gorm:"size:2048"`
+	// TokenServiceAccount is the service account, as namespace/name, whose
+	// short-lived tokens are minted to make requests to the cluster instead
+	// of using a stored token.
+	TokenServiceAccount string `json:"tokenServiceAccount,omitempty"`
+	// TokenProvider fetches short-lived tokens of the provider, such as from
+	// AWS IAM for an EKS cluster, instead of using a stored token.
+	TokenProvider *ProviderTokenProvider `json:"tokenProvider,omitempty" gorm:"type:text"`
+	// KubeconfigContents, or the kubeconfig at KubeconfigFile on the
+	// filesystem of clouddriver, hold the host and credentials of the
+	// provider instead of its host, caData and bearerToken, such as client
+	// certificates or an exec plugin. KubeconfigContext selects a context
+	// other than the current context of the kubeconfig.
+	KubeconfigContents string `json:"kubeconfigContents,omitempty" gorm:"type:text"`
+	KubeconfigFile     string `json:"kubeconfigFile,omitempty"`
+	KubeconfigContext  string `json:"kubeconfigContext,omitempty"`
+	WriteMode          string `json:"writeMode,omitempty"`
+	// Set while the account is in maintenance, when operations are rejected.
+	Maintenance        bool   `json:"maintenance,omitempty"`
+	MaintenanceMessage string `json:"maintenanceMessage,omitempty" gorm:"size:2048"`
+	// ReadOnly accounts can be read and cached, but operations that change
+	// them are rejected, such as to only observe a production cluster.
+	ReadOnly    bool                `json:"readOnly,omitempty"`
 	Permissions ProviderPermissions `json:"permissions" gorm:"-"`
+	// KindMap maps kinds to the categories Deck shows them under, such as
+	// rollout to serverGroups, and is stored as kind mappings.
+	KindMap map[string]string `json:"kindMap,omitempty" gorm:"-"`
+	// Clusters other than the primary cluster backing the account, such as
+	// a standby cluster. Reads are merged across all clusters.
+	Clusters ProviderClusters `json:"clusters,omitempty" gorm:"type:text"`
+	// ClusterCredential names the cluster credential whose host, CA data and
+	// tokens are used instead of the provider's own, shared with the other
+	// providers that name it.
+	ClusterCredential string `json:"clusterCredential,omitempty"`
+	// Namespaces scope the provider to only those namespaces of its cluster.
+	Namespaces ProviderNamespaces `json:"namespaces,omitempty" gorm:"type:text"`
+	// SkipNamespaceListing never lists the namespaces of the cluster, such
+	// as for a large multi-tenant cluster. The namespaces of the provider are
+	// then only those it is scoped to, if any.
+	SkipNamespaceListing bool `json:"skipNamespaceListing,omitempty"`
+	// Transport tunes the connections to the clusters of the provider. Nil
+	// uses the transports of client-go.
+	Transport *ProviderTransport `json:"transport,omitempty" gorm:"type:text"`
+	// Environment and AccountType are listed in /credentials, where Deck
+	// filters accounts and Fiat applies policies by environment. They
+	// default to the name of the provider.
+	Environment string `json:"environment,omitempty"`
+	AccountType string `json:"accountType,omitempty"`
+	// ChallengeDestructiveActions makes Deck ask users to type the name of
+	// the account to confirm destructive actions against it.
+	ChallengeDestructiveActions bool `json:"challengeDestructiveActions,omitempty"`
+	// PrimaryAccount makes Deck select the account by default.
+	PrimaryAccount bool `json:"primaryAccount,omitempty"`
+	// CacheThreads is how many clusters of the provider are listed at once,
+	// one if not set. It only applies to providers with member clusters;
+	// a provider of one cluster lists it with one request regardless.
+	CacheThreads int `json:"cacheThreads,omitempty"`
+	// CacheIntervalSeconds overrides how long kinds cached per account, such
+	// as namespaces, are kept for the provider.
+	CacheIntervalSeconds ProviderCacheIntervals `json:"cacheIntervalSeconds,omitempty" gorm:"type:text"`
+	// Fake makes the provider serve a synthetic cluster instead of a real
+	// one, for load tests and demos.
+	Fake *ProviderFake `json:"fake,omitempty" gorm:"type:text"`
+	// Version is the provider version of the last change to the provider.
+	Version int64 `json:"-" gorm:"index"`
+}
+
+// ProviderCluster is another cluster backing a provider.
+type ProviderCluster struct {
+	Name   string `json:"name"`
+	Host   string `json:"host"`
+	CAData string `json:"caData"`
+}
+
+// ProviderClusters are stored as JSON.
+type ProviderClusters []ProviderCluster
+
+// Value implements driver.Valuer.
+func (pcs ProviderClusters) Value() (driver.Value, error) {
+	if len(pcs) == 0 {
+		return "", nil
+	}
+
+	b, err := json.Marshal(pcs)
+	if err != nil {
+		return nil, err
+	}
+
+	return string(b), nil
+}
+
+// Scan implements sql.Scanner.
+func (pcs *ProviderClusters) Scan(src interface{}) error {
+	var b []byte
+
+	switch v := src.(type) {
+	case nil:
+		*pcs = nil
+		return nil
+	case string:
+		b = []byte(v)
+	case []byte:
+		b = v
+	default:
+		return fmt.Errorf("cannot scan %T into provider clusters", src)
+	}
+
+	if len(b) == 0 {
+		*pcs = nil
+		return nil
+	}
+
+	return json.Unmarshal(b, pcs)
+}
+
+// ProviderNamespaces are stored as JSON.
+type ProviderNamespaces []string
+
+// Value implements driver.Valuer.
+func (pns ProviderNamespaces) Value() (driver.Value, error) {
+	if len(pns) == 0 {
+		return "", nil
+	}
+
+	b, err := json.Marshal(pns)
+	if err != nil {
+		return nil, err
+	}
+
+	return string(b), nil
+}
+
+// Scan implements sql.Scanner.
+func (pns *ProviderNamespaces) Scan(src interface{}) error {
+	var b []byte
+
+	switch v := src.(type) {
+	case nil:
+		*pns = nil
+		return nil
+	case string:
+		b = []byte(v)
+	case []byte:
+		b = v
+	default:
+		return fmt.Errorf("cannot scan %T into provider namespaces", src)
+	}
+
+	if len(b) == 0 {
+		*pns = nil
+		return nil
+	}
+
+	return json.Unmarshal(b, pns)
+}
+
+// ProviderCacheIntervals are the seconds each kind is cached for, stored as
+// JSON.
+type ProviderCacheIntervals map[string]int
+
+// Value implements driver.Valuer.
+func (pcis ProviderCacheIntervals) Value() (driver.Value, error) {
+	if len(pcis) == 0 {
+		return "", nil
+	}
+
+	b, err := json.Marshal(pcis)
+	if err != nil {
+		return nil, err
+	}
+
+	return string(b), nil
+}
+
+// Scan implements sql.Scanner.
+func (pcis *ProviderCacheIntervals) Scan(src interface{}) error {
+	var b []byte
+
+	switch v := src.(type) {
+	case nil:
+		*pcis = nil
+		return nil
+	case string:
+		b = []byte(v)
+	case []byte:
+		b = v
+	default:
+		return fmt.Errorf("cannot scan %T into provider cache intervals", src)
+	}
+
+	if len(b) == 0 {
+		*pcis = nil
+		return nil
+	}
+
+	return json.Unmarshal(b, pcis)
 }
 
 type ProviderPermissions struct {
 	Read  []string `json:"read" gorm:"-"`
 	Write []string `json:"write" gorm:"-"`
+	// Execute are the groups that may run operations on the account, for
+	// accounts that only let some of the groups that can write to it deploy.
+	Execute []string `json:"execute,omitempty" gorm:"-"`
+	// Namespaces override the groups of the account in a namespace, for the
+	// authorizations they have groups for.
+	Namespaces map[string]ProviderNamespacePermissions `json:"namespaces,omitempty" gorm:"-"`
+}
+
+// ProviderNamespacePermissions are the groups of a namespace of an account.
+type ProviderNamespacePermissions struct {
+	Read    []string `json:"read,omitempty"`
+	Write   []string `json:"write,omitempty"`
+	Execute []string `json:"execute,omitempty"`
+}
+
+// Groups returns the groups of an authorization, READ, WRITE or EXECUTE.
+func (p ProviderNamespacePermissions) Groups(authorization string) []string {
+	switch authorization {
+	case "READ":
+		return p.Read
+	case "WRITE":
+		return p.Write
+	case "EXECUTE":
+		return p.Execute
+	}
+
+	return nil
+}
+
+// Groups returns the groups of an authorization, READ, WRITE or EXECUTE,
+// to the account.
+func (p ProviderPermissions) Groups(authorization string) []string {
+	return ProviderNamespacePermissions{
+		Read:    p.Read,
+		Write:   p.Write,
+		Execute: p.Execute,
+	}.Groups(authorization)
+}
+
+// NamespaceGroups returns the groups of an authorization to a namespace, and
+// true if the namespace overrides the groups of the account for it.
+func (p ProviderPermissions) NamespaceGroups(namespace, authorization string) ([]string, bool) {
+	groups := p.Namespaces[namespace].Groups(authorization)
+
+	return groups, len(groups) > 0
+}
+
+// OperationAuthorization returns the authorization operations need in a
+// namespace of the account: EXECUTE if the account or namespace has execute
+// groups, or else WRITE.
+func (p ProviderPermissions) OperationAuthorization(namespace string) string {
+	if _, ok := p.NamespaceGroups(namespace, "EXECUTE"); ok || len(p.Execute) > 0 {
+		return "EXECUTE"
+	}
+
+	return "WRITE"
+}
+
+// ValidatePermissions returns an error if a namespace of the permissions of
+// the provider is empty or has no groups.
+func (p Provider) ValidatePermissions() error {
+	for namespace, np := range p.Permissions.Namespaces {
+		if namespace == "" {
+			return errors.New("namespace permissions must be of a namespace")
+		}
+
+		if len(np.Read)+len(np.Write)+len(np.Execute) == 0 {
+			return fmt.Errorf("namespace permissions of %s must have groups", namespace)
+		}
+	}
+
+	return nil
 }
 
 func (Provider) TableName() string {
 	return "kubernetes_providers"
 }
+
+func ProviderInstance(c *gin.Context) Provider {
+	return c.MustGet(ProviderInstanceKey).(Provider)
+}
+
+// EnvironmentName returns the environment of the provider, or its name if
+// it has none.
+func (p Provider) EnvironmentName() string {
+	if p.Environment == "" {
+		return p.Name
+	}
+
+	return p.Environment
+}
+
+// AccountTypeName returns the account type of the provider, or its name if
+// it has none.
+func (p Provider) AccountTypeName() string {
+	if p.AccountType == "" {
+		return p.Name
+	}
+
+	return p.AccountType
+}
+
+// CacheThreadCount returns how many clusters of the provider are listed at
+// once, at least one. Only federated clients of multi-cluster providers use it.
+func (p Provider) CacheThreadCount() int {
+	if p.CacheThreads < 1 {
+		return 1
+	}
+
+	return p.CacheThreads
+}
+
+// ValidateCaching returns an error if the cache threads or a cache interval
+// of the provider is negative.
+func (p Provider) ValidateCaching() error {
+	if p.CacheThreads < 0 {
+		return errors.New("cacheThreads must not be negative")
+	}
+
+	for kind, seconds := range p.CacheIntervalSeconds {
+		if seconds < 0 {
+			return fmt.Errorf("cache interval of %s must not be negative", kind)
+		}
+	}
+
+	return nil
+}
+
+// DryRun returns true if changes to the provider's cluster are only dry-run.
+func (p Provider) DryRun() bool {
+	return p.WriteMode == WriteModeDryRun
+}
+
+// ValidWriteMode returns true if the provider's write mode is known.
+func (p Provider) ValidWriteMode() bool {
+	return p.WriteMode == "" || p.WriteMode == WriteModeEnforced || p.WriteMode == WriteModeDryRun
+}
+
+// ValidTokenServiceAccount returns true if the provider has no token service
+// account or it is given as namespace/name.
+func (p Provider) ValidTokenServiceAccount() bool {
+	if p.TokenServiceAccount == "" {
+		return true
+	}
+
+	a := strings.Split(p.TokenServiceAccount, "/")
+
+	return len(a) == 2 && a[0] != "" && a[1] != ""
+}
+
+// ValidateClusters returns an error if a cluster of the provider has no
+// name or host, or its name is used by another cluster.
+func (p Provider) ValidateClusters() error {
+	names := map[string]bool{PrimaryCluster: true}
+
+	for _, pc := range p.Clusters {
+		if pc.Name == "" || pc.Host == "" {
+			return errors.New("clusters must have a name and host")
+		}
+
+		if names[pc.Name] {
+			return fmt.Errorf("cluster name %q is already used", pc.Name)
+		}
+
+		names[pc.Name] = true
+	}
+
+	return nil
+}
+
+// ValidateClusterCredential returns an error if the provider names a cluster
+// credential and also has credentials of its own.
+func (p Provider) ValidateClusterCredential() error {
+	if p.ClusterCredential == "" {
+		return nil
+	}
+
+	if p.Host != "" || p.CAData != "" || p.BearerToken != "" || p.TokenServiceAccount != "" || len(p.Clusters) > 0 {
+		return errors.New("providers with a cluster credential must not set a host, caData, bearerToken, tokenServiceAccount or clusters")
+	}
+
+	return nil
+}
+
+// WithClusterCredential returns the provider with the host, CA data and
+// tokens of a cluster credential.
+func (p Provider) WithClusterCredential(cc ClusterCredential) Provider {
+	p.Host = cc.Host
+	p.CAData = cc.CAData
+	p.BearerToken = cc.BearerToken
+	p.TokenServiceAccount = cc.TokenServiceAccount
+
+	return p
+}
+
+// InNamespaceScope returns true if the provider is not scoped to namespaces
+// or is scoped to namespace.
+func (p Provider) InNamespaceScope(namespace string) bool {
+	if len(p.Namespaces) == 0 {
+		return true
+	}
+
+	for _, ns := range p.Namespaces {
+		if ns == namespace {
+			return true
+		}
+	}
+
+	return false
+}
+
+// Target returns the provider with the host and CA data of the named cluster,
+// so clients of it change that cluster. The primary cluster is targeted if
+// cluster is empty.
+func (p Provider) Target(cluster string) (Provider, error) {
+	if cluster == "" || cluster == PrimaryCluster {
+		return p, nil
+	}
+
+	for _, pc := range p.Clusters {
+		if pc.Name == cluster {
+			p.Host = pc.Host
+			p.CAData = pc.CAData
+			p.Clusters = nil
+
+			return p, nil
+		}
+	}
+
+	return p, fmt.Errorf("account %s has no cluster %q", p.Name, cluster)
+}
@@ -0,0 +1,214 @@
+package kubernetes_test
+
+import (
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	. "github.com/billiford/go-clouddriver/pkg/kubernetes"
+)
+
+var _ = Describe("Provider", func() {
+	Describe("#ValidWriteMode", func() {
+		It("accepts known write modes", func() {
+			Expect(Provider{}.ValidWriteMode()).To(BeTrue())
+			Expect(Provider{WriteMode: WriteModeEnforced}.ValidWriteMode()).To(BeTrue())
+			Expect(Provider{WriteMode: WriteModeDryRun}.ValidWriteMode()).To(BeTrue())
+		})
+
+		It("rejects unknown write modes", func() {
+			Expect(Provider{WriteMode: "dryrun"}.ValidWriteMode()).To(BeFalse())
+		})
+	})
+
+	Describe("#ValidTokenServiceAccount", func() {
+		It("accepts no service account or namespace/name", func() {
+			Expect(Provider{}.ValidTokenServiceAccount()).To(BeTrue())
+			Expect(Provider{TokenServiceAccount: "spinnaker/deployer"}.ValidTokenServiceAccount()).To(BeTrue())
+		})
+
+		It("rejects anything else", func() {
+			Expect(Provider{TokenServiceAccount: "deployer"}.ValidTokenServiceAccount()).To(BeFalse())
+			Expect(Provider{TokenServiceAccount: "spinnaker/"}.ValidTokenServiceAccount()).To(BeFalse())
+			Expect(Provider{TokenServiceAccount: "a/b/c"}.ValidTokenServiceAccount()).To(BeFalse())
+		})
+	})
+
+	Describe("#EnvironmentName and #AccountTypeName", func() {
+		It("default to the name of the provider", func() {
+			p := Provider{Name: "test-account"}
+			Expect(p.EnvironmentName()).To(Equal("test-account"))
+			Expect(p.AccountTypeName()).To(Equal("test-account"))
+
+			p.Environment = "prod"
+			p.AccountType = "kubernetes-prod"
+			Expect(p.EnvironmentName()).To(Equal("prod"))
+			Expect(p.AccountTypeName()).To(Equal("kubernetes-prod"))
+		})
+	})
+
+	Describe("#CacheThreadCount", func() {
+		It("is at least one", func() {
+			Expect(Provider{}.CacheThreadCount()).To(Equal(1))
+			Expect(Provider{CacheThreads: 4}.CacheThreadCount()).To(Equal(4))
+		})
+	})
+
+	Describe("#ValidateCaching", func() {
+		It("rejects negative threads and intervals", func() {
+			Expect(Provider{CacheThreads: 2, CacheIntervalSeconds: ProviderCacheIntervals{"namespaces": 300}}.ValidateCaching()).To(Succeed())
+			Expect(Provider{CacheThreads: -1}.ValidateCaching()).ToNot(Succeed())
+			Expect(Provider{CacheIntervalSeconds: ProviderCacheIntervals{"namespaces": -1}}.ValidateCaching()).
+				To(MatchError("cache interval of namespaces must not be negative"))
+		})
+	})
+
+	Describe("#DryRun", func() {
+		It("returns true only in dryRun write mode", func() {
+			Expect(Provider{WriteMode: WriteModeDryRun}.DryRun()).To(BeTrue())
+			Expect(Provider{}.DryRun()).To(BeFalse())
+		})
+	})
+
+	Describe("#ValidateClusters", func() {
+		It("accepts named clusters with hosts", func() {
+			p := Provider{Clusters: ProviderClusters{{Name: "standby", Host: "https://standby"}}}
+			Expect(p.ValidateClusters()).To(Succeed())
+		})
+
+		It("rejects clusters without a name or host", func() {
+			p := Provider{Clusters: ProviderClusters{{Name: "standby"}}}
+			Expect(p.ValidateClusters()).To(MatchError("clusters must have a name and host"))
+		})
+
+		It("rejects clusters named like another cluster", func() {
+			p := Provider{Clusters: ProviderClusters{{Name: "primary", Host: "https://standby"}}}
+			Expect(p.ValidateClusters()).To(MatchError(`cluster name "primary" is already used`))
+		})
+	})
+
+	Describe("#Target", func() {
+		var p Provider
+
+		BeforeEach(func() {
+			p = Provider{
+				Name:   "test-account",
+				Host:   "https://primary",
+				CAData: "primary-ca",
+				Clusters: ProviderClusters{
+					{Name: "standby", Host: "https://standby", CAData: "standby-ca"},
+				},
+			}
+		})
+
+		It("targets the primary cluster by default", func() {
+			t, err := p.Target("")
+			Expect(err).To(BeNil())
+			Expect(t.Host).To(Equal("https://primary"))
+			t, err = p.Target(PrimaryCluster)
+			Expect(err).To(BeNil())
+			Expect(t.Host).To(Equal("https://primary"))
+		})
+
+		It("targets a named cluster", func() {
+			t, err := p.Target("standby")
+			Expect(err).To(BeNil())
+			Expect(t.Host).To(Equal("https://standby"))
+			Expect(t.CAData).To(Equal("standby-ca"))
+			Expect(t.Clusters).To(BeEmpty())
+		})
+
+		It("returns an error for an unknown cluster", func() {
+			_, err := p.Target("other")
+			Expect(err).To(MatchError(`account test-account has no cluster "other"`))
+		})
+	})
+
+	Describe("#ValidateClusterCredential", func() {
+		It("allows providers without a cluster credential", func() {
+			Expect(Provider{Host: "https://host"}.ValidateClusterCredential()).To(Succeed())
+		})
+
+		It("allows providers with only a cluster credential", func() {
+			Expect(Provider{ClusterCredential: "shared"}.ValidateClusterCredential()).To(Succeed())
+		})
+
+		It("returns an error for providers with a cluster credential and their own", func() {
+			err := Provider{ClusterCredential: "shared", BearerToken: "token"}.ValidateClusterCredential()
+			Expect(err).To(MatchError("providers with a cluster credential must not set a host, caData, bearerToken, tokenServiceAccount or clusters"))
+		})
+	})
+
+	Describe("#WithClusterCredential", func() {
+		It("uses the host, CA data and tokens of the cluster credential", func() {
+			p := Provider{Name: "team-a", ClusterCredential: "shared", ReadOnly: true}.WithClusterCredential(ClusterCredential{
+				Name:                "shared",
+				Host:                "https://shared",
+				CAData:              "shared-ca",
+				BearerToken:         "shared-token",
+				TokenServiceAccount: "spinnaker/deployer",
+			})
+			Expect(p.Name).To(Equal("team-a"))
+			Expect(p.ReadOnly).To(BeTrue())
+			Expect(p.Host).To(Equal("https://shared"))
+			Expect(p.CAData).To(Equal("shared-ca"))
+			Expect(p.BearerToken).To(Equal("shared-token"))
+			Expect(p.TokenServiceAccount).To(Equal("spinnaker/deployer"))
+		})
+	})
+
+	Describe("#InNamespaceScope", func() {
+		It("is true for any namespace of providers that are not scoped", func() {
+			Expect(Provider{}.InNamespaceScope("default")).To(BeTrue())
+		})
+
+		It("is only true for the namespaces of scoped providers", func() {
+			p := Provider{Namespaces: ProviderNamespaces{"team-a"}}
+			Expect(p.InNamespaceScope("team-a")).To(BeTrue())
+			Expect(p.InNamespaceScope("team-b")).To(BeFalse())
+			Expect(p.InNamespaceScope("")).To(BeFalse())
+		})
+	})
+
+	Describe("ProviderNamespaces", func() {
+		It("is stored as JSON", func() {
+			pns := ProviderNamespaces{"team-a", "team-a-canary"}
+			v, err := pns.Value()
+			Expect(err).To(BeNil())
+
+			scanned := ProviderNamespaces{}
+			Expect(scanned.Scan(v)).To(Succeed())
+			Expect(scanned).To(Equal(pns))
+		})
+	})
+
+	Describe("ProviderCacheIntervals", func() {
+		It("is stored as JSON", func() {
+			pcis := ProviderCacheIntervals{"namespaces": 300}
+			v, err := pcis.Value()
+			Expect(err).To(BeNil())
+
+			scanned := ProviderCacheIntervals{}
+			Expect(scanned.Scan(v)).To(Succeed())
+			Expect(scanned).To(Equal(pcis))
+		})
+	})
+
+	Describe("ProviderClusters", func() {
+		It("is stored as JSON", func() {
+			pcs := ProviderClusters{{Name: "standby", Host: "https://standby"}}
+			v, err := pcs.Value()
+			Expect(err).To(BeNil())
+
+			scanned := ProviderClusters{}
+			Expect(scanned.Scan(v)).To(Succeed())
+			Expect(scanned).To(Equal(pcs))
+		})
+
+		It("scans empty values as no clusters", func() {
+			scanned := ProviderClusters{}
+			Expect(scanned.Scan([]byte(""))).To(Succeed())
+			Expect(scanned).To(BeNil())
+			Expect(scanned.Scan(nil)).To(Succeed())
+		})
+	})
+})
@@ -0,0 +1,24 @@
+package kubernetes
+
+// ProviderVersion is the version of the configuration of every provider. It
+// is incremented by each change to a provider, including its permissions, so
+// changes can be listed since a version.
+type ProviderVersion struct {
+	ID      int `gorm:"primary_key"`
+	Version int64
+}
+
+func (ProviderVersion) TableName() string {
+	return "kubernetes_provider_versions"
+}
+
+// DeletedProvider is a provider deleted at a version, so a provider that was
+// deleted since a version can be told apart from one that did not change.
+type DeletedProvider struct {
+	Name    string `gorm:"primary_key"`
+	Version int64  `gorm:"index"`
+}
+
+func (DeletedProvider) TableName() string {
+	return "kubernetes_deleted_providers"
+}
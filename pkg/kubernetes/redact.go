@@ -0,0 +1,49 @@
+package kubernetes
+
+import (
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+// Redacted replaces the values of the data of secrets that are returned or
+// stored by clouddriver rather than read from a cluster.
+const Redacted = "[REDACTED]"
+
+// RedactSecret returns a copy of the manifest of a secret with the values of
+// its data and string data redacted, keeping their keys, and without the last
+// applied configuration annotation, which holds them too. Manifests of other
+// kinds are returned as they are.
+func RedactSecret(u *unstructured.Unstructured) *unstructured.Unstructured {
+	if u == nil || !strings.EqualFold(u.GetKind(), "secret") {
+		return u
+	}
+
+	r := u.DeepCopy()
+
+	for _, field := range []string{"data", "stringData"} {
+		v, ok := r.Object[field]
+		if !ok {
+			continue
+		}
+
+		data, ok := v.(map[string]interface{})
+		if !ok {
+			r.Object[field] = Redacted
+			continue
+		}
+
+		for key := range data {
+			data[key] = Redacted
+		}
+	}
+
+	annotations := r.GetAnnotations()
+	if _, ok := annotations[corev1.LastAppliedConfigAnnotation]; ok {
+		delete(annotations, corev1.LastAppliedConfigAnnotation)
+		r.SetAnnotations(annotations)
+	}
+
+	return r
+}
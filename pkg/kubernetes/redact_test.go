@@ -0,0 +1,52 @@
+package kubernetes_test
+
+import (
+	. "github.com/billiford/go-clouddriver/pkg/kubernetes"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+var _ = Describe("Redact", func() {
+	var u *unstructured.Unstructured
+
+	Describe("#RedactSecret", func() {
+		When("the manifest is a secret", func() {
+			BeforeEach(func() {
+				u = &unstructured.Unstructured{Object: map[string]interface{}{
+					"kind": "Secret",
+					"metadata": map[string]interface{}{
+						"name": "test-secret",
+						"annotations": map[string]interface{}{
+							"kubectl.kubernetes.io/last-applied-configuration": `{"data":{"password":"cGFzc3dvcmQ="}}`,
+							"test-annotation": "test-value",
+						},
+					},
+					"data":       map[string]interface{}{"password": "cGFzc3dvcmQ="},
+					"stringData": map[string]interface{}{"token": "token"},
+				}}
+			})
+
+			It("redacts the values of its data", func() {
+				r := RedactSecret(u)
+				Expect(r.Object["data"]).To(Equal(map[string]interface{}{"password": Redacted}))
+				Expect(r.Object["stringData"]).To(Equal(map[string]interface{}{"token": Redacted}))
+				Expect(r.GetAnnotations()).To(Equal(map[string]string{"test-annotation": "test-value"}))
+				Expect(u.Object["data"]).To(Equal(map[string]interface{}{"password": "cGFzc3dvcmQ="}), "the manifest is not changed")
+			})
+		})
+
+		When("the manifest is not a secret", func() {
+			BeforeEach(func() {
+				u = &unstructured.Unstructured{Object: map[string]interface{}{
+					"kind": "ConfigMap",
+					"data": map[string]interface{}{"key": "value"},
+				}}
+			})
+
+			It("returns it as it is", func() {
+				Expect(RedactSecret(u)).To(BeIdenticalTo(u))
+			})
+		})
+	})
+})
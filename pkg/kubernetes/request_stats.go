@@ -0,0 +1,255 @@
+package kubernetes
+
+import (
+	"net/http"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// RequestWindows are the sliding windows requests to an API server are
+// counted over, to the minute.
+var RequestWindows = []time.Duration{
+	time.Minute,
+	5 * time.Minute,
+	15 * time.Minute,
+	time.Hour,
+}
+
+// Requests are counted in a bucket per minute of the longest window.
+const requestBuckets = 60
+
+var (
+	apiRequests = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "clouddriver_kubernetes_api_requests_total",
+		Help: "Number of requests made to Kubernetes API servers by host, verb and resource.",
+	}, []string{"host", "verb", "resource"})
+)
+
+func init() {
+	prometheus.MustRegister(apiRequests)
+}
+
+// RequestCount is the number of requests with a verb, such as list, made to
+// a resource of an API server, such as deployments.apps, in each of
+// RequestWindows.
+type RequestCount struct {
+	Verb     string
+	Resource string
+	Windows  map[time.Duration]int64
+}
+
+type requestKey struct {
+	verb     string
+	resource string
+}
+
+// requestCounter counts requests in a ring of buckets, each the count of a
+// minute since the epoch.
+type requestCounter struct {
+	minutes [requestBuckets]int64
+	counts  [requestBuckets]int64
+}
+
+func (rc *requestCounter) add(minute int64) {
+	i := minute % requestBuckets
+	if rc.minutes[i] != minute {
+		rc.minutes[i] = minute
+		rc.counts[i] = 0
+	}
+
+	rc.counts[i]++
+}
+
+// sum returns the requests counted in the n minutes up to and including minute.
+func (rc *requestCounter) sum(minute, n int64) int64 {
+	var total int64
+
+	for m := minute - n + 1; m <= minute; m++ {
+		i := m % requestBuckets
+		if rc.minutes[i] == m {
+			total += rc.counts[i]
+		}
+	}
+
+	return total
+}
+
+type requestStats struct {
+	mux      sync.Mutex
+	now      func() time.Time
+	counters map[string]map[requestKey]*requestCounter
+}
+
+func newRequestStats() *requestStats {
+	return &requestStats{
+		now:      time.Now,
+		counters: map[string]map[requestKey]*requestCounter{},
+	}
+}
+
+func (s *requestStats) add(host string, req *http.Request) {
+	verb, resource := requestVerbAndResource(req)
+	apiRequests.WithLabelValues(host, verb, resource).Inc()
+
+	minute := s.now().Unix() / 60
+	key := requestKey{verb: verb, resource: resource}
+
+	s.mux.Lock()
+	defer s.mux.Unlock()
+
+	counters, ok := s.counters[host]
+	if !ok {
+		counters = map[requestKey]*requestCounter{}
+		s.counters[host] = counters
+	}
+
+	rc, ok := counters[key]
+	if !ok {
+		rc = &requestCounter{}
+		counters[key] = rc
+	}
+
+	rc.add(minute)
+}
+
+// counts returns the requests made to host in the longest window by verb
+// and resource, the most requested first. Verbs and resources without
+// requests in the window are forgotten.
+func (s *requestStats) counts(host string) []RequestCount {
+	minute := s.now().Unix() / 60
+	rcs := []RequestCount{}
+
+	s.mux.Lock()
+	defer s.mux.Unlock()
+
+	for key, rc := range s.counters[host] {
+		if rc.sum(minute, requestBuckets) == 0 {
+			delete(s.counters[host], key)
+			continue
+		}
+
+		windows := map[time.Duration]int64{}
+		for _, w := range RequestWindows {
+			windows[w] = rc.sum(minute, int64(w/time.Minute))
+		}
+
+		rcs = append(rcs, RequestCount{
+			Verb:     key.verb,
+			Resource: key.resource,
+			Windows:  windows,
+		})
+	}
+
+	longest := RequestWindows[len(RequestWindows)-1]
+
+	sort.Slice(rcs, func(i, j int) bool {
+		if rcs[i].Windows[longest] != rcs[j].Windows[longest] {
+			return rcs[i].Windows[longest] > rcs[j].Windows[longest]
+		}
+
+		if rcs[i].Resource != rcs[j].Resource {
+			return rcs[i].Resource < rcs[j].Resource
+		}
+
+		return rcs[i].Verb < rcs[j].Verb
+	})
+
+	return rcs
+}
+
+// evictIdle forgets the hosts without requests in the longest window,
+// returning how many were forgotten.
+func (s *requestStats) evictIdle() int {
+	minute := s.now().Unix() / 60
+	evicted := 0
+
+	s.mux.Lock()
+	defer s.mux.Unlock()
+
+	for host, counters := range s.counters {
+		for key, rc := range counters {
+			if rc.sum(minute, requestBuckets) == 0 {
+				delete(counters, key)
+			}
+		}
+
+		if len(counters) == 0 {
+			delete(s.counters, host)
+			evicted++
+		}
+	}
+
+	return evicted
+}
+
+// namespaceSubresources are subresources of namespaces, which would
+// otherwise be taken for the resources of a namespace.
+var namespaceSubresources = map[string]bool{
+	"finalize": true,
+	"status":   true,
+}
+
+// requestVerbAndResource returns the verb of a request to an API server, as
+// RBAC names it, and the resource requested, qualified by its group and
+// subresource such as deployments.apps or pods/log. Requests that are not
+// for a resource, such as API discovery, are for the resource discovery.
+func requestVerbAndResource(req *http.Request) (string, string) {
+	parts := strings.Split(strings.Trim(req.URL.Path, "/"), "/")
+	group := ""
+
+	switch {
+	case len(parts) > 2 && parts[0] == "api":
+		parts = parts[2:]
+	case len(parts) > 3 && parts[0] == "apis":
+		group = parts[1]
+		parts = parts[3:]
+	default:
+		return "get", "discovery"
+	}
+
+	if parts[0] == "namespaces" && len(parts) > 2 && !namespaceSubresources[parts[2]] {
+		parts = parts[2:]
+	}
+
+	resource := parts[0]
+	if group != "" {
+		resource += "." + group
+	}
+
+	if len(parts) > 2 {
+		resource += "/" + parts[2]
+	}
+
+	named := len(parts) > 1
+
+	switch req.Method {
+	case http.MethodGet, http.MethodHead:
+		if req.URL.Query().Get("watch") == "true" {
+			return "watch", resource
+		}
+
+		if named {
+			return "get", resource
+		}
+
+		return "list", resource
+	case http.MethodPost:
+		return "create", resource
+	case http.MethodPut:
+		return "update", resource
+	case http.MethodPatch:
+		return "patch", resource
+	case http.MethodDelete:
+		if named {
+			return "delete", resource
+		}
+
+		return "deletecollection", resource
+	default:
+		return strings.ToLower(req.Method), resource
+	}
+}
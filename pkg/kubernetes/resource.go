@@ -1,5 +1,7 @@
 package kubernetes
 
+import "time"
+
 type Resource struct {
 	AccountName  string `json:"accountName"`
 	ID           string `json:"id" gorm:"primary_key"`
@@ -13,6 +15,17 @@ type Resource struct {
 	Kind         string `json:"kind"`
 	SpinnakerApp string `json:"spinnakerApp"`
 	Cluster      string `json:"-"`
+	// Lint warnings of the deployed manifest, separated by newlines.
+	Warnings string `json:"-" gorm:"type:text"`
+	// Set when the resource was only dry-run against an account in dryRun
+	// write mode, with the manifest that would have been deployed. Manifests
+	// are stored compressed by the hash in ManifestHash, and only rows stored
+	// before then hold them in Manifest.
+	DryRun       bool   `json:"-"`
+	Manifest     string `json:"-" gorm:"type:text"`
+	ManifestHash string `json:"-" gorm:"index"`
+	// Set when the resource is created, used to clean up old task history.
+	CreatedAt time.Time `json:"-"`
 }
 
 func (Resource) TableName() string {
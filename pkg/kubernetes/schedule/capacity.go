@@ -0,0 +1,225 @@
+package schedule
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+)
+
+// CapacityCheck is what to do when deployed workloads exceed the schedulable
+// capacity of a cluster.
+type CapacityCheck string
+
+const (
+	CapacityCheckNone CapacityCheck = ``
+	CapacityCheckWarn CapacityCheck = `warn`
+	CapacityCheckFail CapacityCheck = `fail`
+)
+
+// NewCapacityCheck returns the capacity check named s, which is empty when
+// capacity is not checked.
+func NewCapacityCheck(s string) (CapacityCheck, error) {
+	cc := CapacityCheck(strings.ToLower(s))
+
+	switch cc {
+	case CapacityCheckNone, CapacityCheckWarn, CapacityCheckFail:
+		return cc, nil
+	default:
+		return cc, fmt.Errorf("unknown capacity check %q, must be warn or fail", s)
+	}
+}
+
+// Pods converts a list of pods.
+func Pods(list *unstructured.UnstructuredList) ([]corev1.Pod, error) {
+	pods := make([]corev1.Pod, len(list.Items))
+
+	for i, item := range list.Items {
+		err := runtime.DefaultUnstructuredConverter.FromUnstructured(item.Object, &pods[i])
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return pods, nil
+}
+
+// Capacity is the schedulable capacity of each node by name, its allocatable
+// resources minus the requests of the pods running on it.
+type Capacity map[string]corev1.ResourceList
+
+// NewCapacity returns the schedulable capacity of nodes given the pods of the cluster.
+// Pods that have completed do not count against capacity.
+func NewCapacity(nodes []corev1.Node, pods []corev1.Pod) Capacity {
+	c := Capacity{}
+
+	for _, node := range nodes {
+		c[node.Name] = node.Status.Allocatable.DeepCopy()
+	}
+
+	for _, pod := range pods {
+		free, ok := c[pod.Spec.NodeName]
+		if !ok || pod.Status.Phase == corev1.PodSucceeded || pod.Status.Phase == corev1.PodFailed {
+			continue
+		}
+
+		for name, q := range Requests(pod.Spec) {
+			if f, ok := free[name]; ok {
+				f.Sub(q)
+				free[name] = f
+			}
+		}
+	}
+
+	return c
+}
+
+// Requests returns the resources a pod of a spec requests from its node, the
+// larger of the sum of its containers' requests and of each init container's
+// requests, as the scheduler computes them. A pod also takes one of a node's pods.
+func Requests(spec corev1.PodSpec) corev1.ResourceList {
+	requests := corev1.ResourceList{}
+
+	for _, container := range spec.Containers {
+		for name, q := range container.Resources.Requests {
+			r := requests[name]
+			r.Add(q)
+			requests[name] = r
+		}
+	}
+
+	for _, container := range spec.InitContainers {
+		for name, q := range container.Resources.Requests {
+			if r, ok := requests[name]; !ok || q.Cmp(r) > 0 {
+				requests[name] = q.DeepCopy()
+			}
+		}
+	}
+
+	for name, q := range spec.Overhead {
+		r := requests[name]
+		r.Add(q)
+		requests[name] = r
+	}
+
+	requests[corev1.ResourcePods] = *resource.NewQuantity(1, resource.DecimalSI)
+
+	return requests
+}
+
+// Replicas returns the number of pods a manifest runs, or false if it does not
+// run a fixed number of pods, such as DaemonSets which run a pod on each node.
+func Replicas(u *unstructured.Unstructured) (int64, bool) {
+	var path []string
+
+	switch strings.ToLower(u.GetKind()) {
+	case "pod":
+		return 1, true
+	case "deployment", "replicaset", "statefulset", "replicationcontroller":
+		path = []string{"spec", "replicas"}
+	case "job":
+		path = []string{"spec", "parallelism"}
+	case "cronjob":
+		path = []string{"spec", "jobTemplate", "spec", "parallelism"}
+	default:
+		return 0, false
+	}
+
+	replicas, ok, err := unstructured.NestedInt64(u.Object, path...)
+	if err != nil {
+		return 0, false
+	}
+
+	if !ok {
+		return 1, true
+	}
+
+	return replicas, true
+}
+
+// Insufficient returns why the schedulable capacity of the nodes pods of a spec
+// can be scheduled on does not fit a number of them, such as "room for 2 of 5 pods
+// requesting cpu 500m, memory 1Gi". It returns false if they fit, or if no node
+// can run them, which Unschedulable reports.
+func (c Capacity) Insufficient(spec corev1.PodSpec, replicas int64, nodes []corev1.Node) (string, bool) {
+	if replicas <= 0 {
+		return "", false
+	}
+
+	requests := Requests(spec)
+	fits := false
+
+	var room int64
+
+	for i := range nodes {
+		if fit(spec, &nodes[i]) != "" {
+			continue
+		}
+
+		fits = true
+		room += c.room(nodes[i].Name, requests)
+
+		if room >= replicas {
+			return "", false
+		}
+	}
+
+	if !fits {
+		return "", false
+	}
+
+	return fmt.Sprintf("room for %d of %d pods requesting %s", room, replicas, describe(requests)), true
+}
+
+// room returns how many pods with requests fit the schedulable capacity of a node.
+func (c Capacity) room(node string, requests corev1.ResourceList) int64 {
+	free := c[node]
+	room := int64(-1)
+
+	for name, q := range requests {
+		if q.IsZero() {
+			continue
+		}
+
+		f, ok := free[name]
+		if !ok || f.Sign() <= 0 {
+			return 0
+		}
+
+		n := f.MilliValue() / q.MilliValue()
+		if room < 0 || n < room {
+			room = n
+		}
+	}
+
+	if room < 0 {
+		return 0
+	}
+
+	return room
+}
+
+// describe lists the resources of a pod's requests other than the pod itself.
+func describe(requests corev1.ResourceList) string {
+	rs := []string{}
+
+	for name, q := range requests {
+		if name == corev1.ResourcePods {
+			continue
+		}
+
+		rs = append(rs, fmt.Sprintf("%s %s", name, q.String()))
+	}
+
+	if len(rs) == 0 {
+		return "no resources"
+	}
+
+	sort.Strings(rs)
+
+	return strings.Join(rs, ", ")
+}
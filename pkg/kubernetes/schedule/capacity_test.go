@@ -0,0 +1,209 @@
+package schedule_test
+
+import (
+	. "github.com/billiford/go-clouddriver/pkg/kubernetes/schedule"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+func requests(cpu, memory string) corev1.ResourceRequirements {
+	return corev1.ResourceRequirements{
+		Requests: corev1.ResourceList{
+			corev1.ResourceCPU:    resource.MustParse(cpu),
+			corev1.ResourceMemory: resource.MustParse(memory),
+		},
+	}
+}
+
+var _ = Describe("Capacity", func() {
+	var (
+		nodes []corev1.Node
+		pods  []corev1.Pod
+		spec  corev1.PodSpec
+	)
+
+	BeforeEach(func() {
+		nodes = []corev1.Node{}
+
+		for _, name := range []string{"node-1", "node-2"} {
+			nodes = append(nodes, corev1.Node{
+				ObjectMeta: metav1.ObjectMeta{
+					Name: name,
+				},
+				Status: corev1.NodeStatus{
+					Allocatable: corev1.ResourceList{
+						corev1.ResourceCPU:    resource.MustParse("2"),
+						corev1.ResourceMemory: resource.MustParse("4Gi"),
+						corev1.ResourcePods:   resource.MustParse("110"),
+					},
+				},
+			})
+		}
+
+		pods = []corev1.Pod{
+			{
+				Spec: corev1.PodSpec{
+					NodeName: "node-1",
+					Containers: []corev1.Container{
+						{Resources: requests("1500m", "1Gi")},
+					},
+				},
+				Status: corev1.PodStatus{
+					Phase: corev1.PodRunning,
+				},
+			},
+			{
+				Spec: corev1.PodSpec{
+					NodeName: "node-2",
+					Containers: []corev1.Container{
+						{Resources: requests("2", "4Gi")},
+					},
+				},
+				Status: corev1.PodStatus{
+					Phase: corev1.PodSucceeded,
+				},
+			},
+		}
+
+		spec = corev1.PodSpec{
+			Containers: []corev1.Container{
+				{Resources: requests("500m", "1Gi")},
+			},
+		}
+	})
+
+	Describe("#NewCapacityCheck", func() {
+		It("parses the capacity checks", func() {
+			cc, err := NewCapacityCheck("")
+			Expect(err).To(BeNil())
+			Expect(cc).To(Equal(CapacityCheckNone))
+
+			cc, err = NewCapacityCheck("Fail")
+			Expect(err).To(BeNil())
+			Expect(cc).To(Equal(CapacityCheckFail))
+
+			_, err = NewCapacityCheck("error")
+			Expect(err).ToNot(BeNil())
+			Expect(err.Error()).To(Equal(`unknown capacity check "error", must be warn or fail`))
+		})
+	})
+
+	Describe("#Requests", func() {
+		It("returns the larger of the containers and each init container", func() {
+			spec.Containers = append(spec.Containers, corev1.Container{Resources: requests("250m", "1Gi")})
+			spec.InitContainers = []corev1.Container{
+				{Resources: requests("1", "512Mi")},
+			}
+
+			r := Requests(spec)
+			Expect(r.Cpu().String()).To(Equal("1"))
+			Expect(r.Memory().String()).To(Equal("2Gi"))
+			Expect(r.Pods().Value()).To(Equal(int64(1)))
+		})
+	})
+
+	Describe("#Replicas", func() {
+		It("returns the number of pods of a manifest", func() {
+			u := &unstructured.Unstructured{
+				Object: map[string]interface{}{
+					"kind": "Deployment",
+					"spec": map[string]interface{}{
+						"replicas": int64(3),
+					},
+				},
+			}
+			replicas, ok := Replicas(u)
+			Expect(ok).To(BeTrue())
+			Expect(replicas).To(Equal(int64(3)))
+
+			u.Object["spec"] = map[string]interface{}{}
+			replicas, ok = Replicas(u)
+			Expect(ok).To(BeTrue())
+			Expect(replicas).To(Equal(int64(1)))
+
+			u.SetKind("DaemonSet")
+			_, ok = Replicas(u)
+			Expect(ok).To(BeFalse())
+		})
+	})
+
+	Describe("#Insufficient", func() {
+		var (
+			replicas     int64
+			reason       string
+			insufficient bool
+		)
+
+		BeforeEach(func() {
+			replicas = 5
+		})
+
+		JustBeforeEach(func() {
+			reason, insufficient = NewCapacity(nodes, pods).Insufficient(spec, replicas, nodes)
+		})
+
+		When("the pods fit", func() {
+			It("returns false", func() {
+				Expect(insufficient).To(BeFalse())
+			})
+		})
+
+		When("the pods do not fit", func() {
+			BeforeEach(func() {
+				replicas = 6
+			})
+
+			It("returns how many fit", func() {
+				Expect(insufficient).To(BeTrue())
+				Expect(reason).To(Equal("room for 5 of 6 pods requesting cpu 500m, memory 1Gi"))
+			})
+		})
+
+		When("no node can run the pods", func() {
+			BeforeEach(func() {
+				spec.NodeSelector = map[string]string{"kubernetes.io/arch": "arm64"}
+			})
+
+			It("returns false", func() {
+				Expect(insufficient).To(BeFalse())
+			})
+		})
+
+		When("there are no pods to add", func() {
+			BeforeEach(func() {
+				replicas = 0
+			})
+
+			It("returns false", func() {
+				Expect(insufficient).To(BeFalse())
+			})
+		})
+	})
+
+	Describe("#Pods", func() {
+		It("converts the pods", func() {
+			list := &unstructured.UnstructuredList{
+				Items: []unstructured.Unstructured{
+					{
+						Object: map[string]interface{}{
+							"metadata": map[string]interface{}{
+								"name": "test-pod",
+							},
+							"spec": map[string]interface{}{
+								"nodeName": "node-1",
+							},
+						},
+					},
+				},
+			}
+			pods, err := Pods(list)
+			Expect(err).To(BeNil())
+			Expect(pods).To(HaveLen(1))
+			Expect(pods[0].Spec.NodeName).To(Equal("node-1"))
+		})
+	})
+})
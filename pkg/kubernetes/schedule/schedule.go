@@ -0,0 +1,189 @@
+// Package schedule checks if the pods of manifests can be scheduled on
+// the nodes of a cluster before they are deployed.
+package schedule
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/selection"
+)
+
+const (
+	reasonUnschedulable = `node(s) were unschedulable`
+	reasonNodeSelector  = `node(s) didn't match node selector`
+	reasonNodeAffinity  = `node(s) didn't match node affinity`
+	reasonTaints        = `node(s) had taints that the pod didn't tolerate`
+)
+
+// Nodes converts a list of nodes.
+func Nodes(list *unstructured.UnstructuredList) ([]corev1.Node, error) {
+	nodes := make([]corev1.Node, len(list.Items))
+
+	for i, item := range list.Items {
+		err := runtime.DefaultUnstructuredConverter.FromUnstructured(item.Object, &nodes[i])
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return nodes, nil
+}
+
+// PodSpec returns the pod spec of a manifest's pods, or false if the manifest
+// does not create pods.
+func PodSpec(u *unstructured.Unstructured) (corev1.PodSpec, bool, error) {
+	spec := corev1.PodSpec{}
+
+	path, ok := PodSpecPath(u.GetKind())
+	if !ok {
+		return spec, false, nil
+	}
+
+	m, ok, err := unstructured.NestedMap(u.Object, path...)
+	if err != nil || !ok {
+		return spec, false, err
+	}
+
+	err = runtime.DefaultUnstructuredConverter.FromUnstructured(m, &spec)
+	if err != nil {
+		return spec, false, err
+	}
+
+	return spec, true, nil
+}
+
+// PodSpecPath returns the path of the pod spec in manifests of a kind, or
+// false if the kind does not create pods.
+func PodSpecPath(kind string) ([]string, bool) {
+	switch strings.ToLower(kind) {
+	case "pod":
+		return []string{"spec"}, true
+	case "deployment", "replicaset", "statefulset", "daemonset", "job", "replicationcontroller":
+		return []string{"spec", "template", "spec"}, true
+	case "cronjob":
+		return []string{"spec", "jobTemplate", "spec", "template", "spec"}, true
+	default:
+		return nil, false
+	}
+}
+
+// Unschedulable returns why pods of a spec cannot be scheduled on any of the nodes,
+// in the format of the scheduler's events, such as "0/3 nodes are available:
+// 3 node(s) didn't match node selector". It returns false if a node fits.
+func Unschedulable(spec corev1.PodSpec, nodes []corev1.Node) (string, bool) {
+	if len(nodes) == 0 {
+		return "", false
+	}
+
+	reasons := map[string]int{}
+
+	for i := range nodes {
+		reason := fit(spec, &nodes[i])
+		if reason == "" {
+			return "", false
+		}
+
+		reasons[reason]++
+	}
+
+	rs := []string{}
+	for reason, count := range reasons {
+		rs = append(rs, fmt.Sprintf("%d %s", count, reason))
+	}
+
+	sort.Strings(rs)
+
+	return fmt.Sprintf("0/%d nodes are available: %s", len(nodes), strings.Join(rs, ", ")), true
+}
+
+// fit returns why the pods of a spec do not fit a node, or an empty string if they do.
+func fit(spec corev1.PodSpec, node *corev1.Node) string {
+	if node.Spec.Unschedulable && !tolerates(spec.Tolerations, &corev1.Taint{
+		Key:    corev1.TaintNodeUnschedulable,
+		Effect: corev1.TaintEffectNoSchedule,
+	}) {
+		return reasonUnschedulable
+	}
+
+	for k, v := range spec.NodeSelector {
+		if node.Labels[k] != v {
+			return reasonNodeSelector
+		}
+	}
+
+	if spec.Affinity != nil && spec.Affinity.NodeAffinity != nil &&
+		spec.Affinity.NodeAffinity.RequiredDuringSchedulingIgnoredDuringExecution != nil &&
+		!matchesNodeSelector(spec.Affinity.NodeAffinity.RequiredDuringSchedulingIgnoredDuringExecution, node) {
+		return reasonNodeAffinity
+	}
+
+	for i := range node.Spec.Taints {
+		taint := &node.Spec.Taints[i]
+		if taint.Effect == corev1.TaintEffectPreferNoSchedule {
+			continue
+		}
+
+		if !tolerates(spec.Tolerations, taint) {
+			return reasonTaints
+		}
+	}
+
+	return ""
+}
+
+func tolerates(tolerations []corev1.Toleration, taint *corev1.Taint) bool {
+	for i := range tolerations {
+		if tolerations[i].ToleratesTaint(taint) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// matchesNodeSelector returns true if a node matches any of the terms of a node selector.
+func matchesNodeSelector(ns *corev1.NodeSelector, node *corev1.Node) bool {
+	for _, term := range ns.NodeSelectorTerms {
+		if len(term.MatchExpressions) == 0 && len(term.MatchFields) == 0 {
+			continue
+		}
+
+		if matches(term.MatchExpressions, node.Labels) &&
+			matches(term.MatchFields, map[string]string{"metadata.name": node.Name}) {
+			return true
+		}
+	}
+
+	return false
+}
+
+var operators = map[corev1.NodeSelectorOperator]selection.Operator{
+	corev1.NodeSelectorOpIn:           selection.In,
+	corev1.NodeSelectorOpNotIn:        selection.NotIn,
+	corev1.NodeSelectorOpExists:       selection.Exists,
+	corev1.NodeSelectorOpDoesNotExist: selection.DoesNotExist,
+	corev1.NodeSelectorOpGt:           selection.GreaterThan,
+	corev1.NodeSelectorOpLt:           selection.LessThan,
+}
+
+func matches(requirements []corev1.NodeSelectorRequirement, set map[string]string) bool {
+	for _, r := range requirements {
+		op, ok := operators[r.Operator]
+		if !ok {
+			return false
+		}
+
+		req, err := labels.NewRequirement(r.Key, op, r.Values)
+		if err != nil || !req.Matches(labels.Set(set)) {
+			return false
+		}
+	}
+
+	return true
+}
@@ -0,0 +1,269 @@
+package schedule_test
+
+import (
+	. "github.com/billiford/go-clouddriver/pkg/kubernetes/schedule"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+var _ = Describe("Schedule", func() {
+	var (
+		nodes         []corev1.Node
+		spec          corev1.PodSpec
+		reason        string
+		unschedulable bool
+	)
+
+	BeforeEach(func() {
+		nodes = []corev1.Node{
+			{
+				ObjectMeta: metav1.ObjectMeta{
+					Name: "linux-node",
+					Labels: map[string]string{
+						"kubernetes.io/os":   "linux",
+						"kubernetes.io/arch": "amd64",
+					},
+				},
+			},
+			{
+				ObjectMeta: metav1.ObjectMeta{
+					Name: "windows-node",
+					Labels: map[string]string{
+						"kubernetes.io/os":   "windows",
+						"kubernetes.io/arch": "amd64",
+					},
+				},
+				Spec: corev1.NodeSpec{
+					Taints: []corev1.Taint{
+						{
+							Key:    "os",
+							Value:  "windows",
+							Effect: corev1.TaintEffectNoSchedule,
+						},
+					},
+				},
+			},
+		}
+		spec = corev1.PodSpec{}
+	})
+
+	Describe("#Unschedulable", func() {
+		JustBeforeEach(func() {
+			reason, unschedulable = Unschedulable(spec, nodes)
+		})
+
+		When("there are no nodes", func() {
+			BeforeEach(func() {
+				nodes = nil
+			})
+
+			It("returns false", func() {
+				Expect(unschedulable).To(BeFalse())
+			})
+		})
+
+		When("a node fits", func() {
+			It("returns false", func() {
+				Expect(unschedulable).To(BeFalse())
+			})
+		})
+
+		When("no node matches the node selector", func() {
+			BeforeEach(func() {
+				spec.NodeSelector = map[string]string{"kubernetes.io/arch": "arm64"}
+			})
+
+			It("returns the reason", func() {
+				Expect(unschedulable).To(BeTrue())
+				Expect(reason).To(Equal("0/2 nodes are available: 2 node(s) didn't match node selector"))
+			})
+		})
+
+		When("the pod selects tainted nodes it does not tolerate", func() {
+			BeforeEach(func() {
+				spec.NodeSelector = map[string]string{"kubernetes.io/os": "windows"}
+			})
+
+			It("returns the reasons", func() {
+				Expect(unschedulable).To(BeTrue())
+				Expect(reason).To(Equal("0/2 nodes are available: 1 node(s) didn't match node selector, " +
+					"1 node(s) had taints that the pod didn't tolerate"))
+			})
+		})
+
+		When("the pod tolerates the taint", func() {
+			BeforeEach(func() {
+				spec.NodeSelector = map[string]string{"kubernetes.io/os": "windows"}
+				spec.Tolerations = []corev1.Toleration{
+					{
+						Key:      "os",
+						Operator: corev1.TolerationOpEqual,
+						Value:    "windows",
+						Effect:   corev1.TaintEffectNoSchedule,
+					},
+				}
+			})
+
+			It("returns false", func() {
+				Expect(unschedulable).To(BeFalse())
+			})
+		})
+
+		When("the linux node is cordoned", func() {
+			BeforeEach(func() {
+				nodes[0].Spec.Unschedulable = true
+			})
+
+			It("returns the reasons", func() {
+				Expect(unschedulable).To(BeTrue())
+				Expect(reason).To(Equal("0/2 nodes are available: 1 node(s) had taints that the pod didn't tolerate, " +
+					"1 node(s) were unschedulable"))
+			})
+		})
+
+		When("no node matches the required node affinity", func() {
+			BeforeEach(func() {
+				spec.Affinity = &corev1.Affinity{
+					NodeAffinity: &corev1.NodeAffinity{
+						RequiredDuringSchedulingIgnoredDuringExecution: &corev1.NodeSelector{
+							NodeSelectorTerms: []corev1.NodeSelectorTerm{
+								{
+									MatchExpressions: []corev1.NodeSelectorRequirement{
+										{
+											Key:      "kubernetes.io/arch",
+											Operator: corev1.NodeSelectorOpIn,
+											Values:   []string{"arm64", "ppc64le"},
+										},
+									},
+								},
+							},
+						},
+					},
+				}
+			})
+
+			It("returns the reasons", func() {
+				Expect(unschedulable).To(BeTrue())
+				Expect(reason).To(Equal("0/2 nodes are available: 2 node(s) didn't match node affinity"))
+			})
+		})
+
+		When("a node matches one of the terms of the node affinity", func() {
+			BeforeEach(func() {
+				spec.Affinity = &corev1.Affinity{
+					NodeAffinity: &corev1.NodeAffinity{
+						RequiredDuringSchedulingIgnoredDuringExecution: &corev1.NodeSelector{
+							NodeSelectorTerms: []corev1.NodeSelectorTerm{
+								{
+									MatchExpressions: []corev1.NodeSelectorRequirement{
+										{
+											Key:      "kubernetes.io/arch",
+											Operator: corev1.NodeSelectorOpIn,
+											Values:   []string{"arm64"},
+										},
+									},
+								},
+								{
+									MatchFields: []corev1.NodeSelectorRequirement{
+										{
+											Key:      "metadata.name",
+											Operator: corev1.NodeSelectorOpIn,
+											Values:   []string{"linux-node"},
+										},
+									},
+								},
+							},
+						},
+					},
+				}
+			})
+
+			It("returns false", func() {
+				Expect(unschedulable).To(BeFalse())
+			})
+		})
+	})
+
+	Describe("#PodSpec", func() {
+		var (
+			u   *unstructured.Unstructured
+			ok  bool
+			err error
+		)
+
+		JustBeforeEach(func() {
+			spec, ok, err = PodSpec(u)
+		})
+
+		When("the kind does not create pods", func() {
+			BeforeEach(func() {
+				u = &unstructured.Unstructured{
+					Object: map[string]interface{}{
+						"kind": "Service",
+					},
+				}
+			})
+
+			It("returns false", func() {
+				Expect(err).To(BeNil())
+				Expect(ok).To(BeFalse())
+			})
+		})
+
+		When("the kind is a cron job", func() {
+			BeforeEach(func() {
+				u = &unstructured.Unstructured{
+					Object: map[string]interface{}{
+						"kind": "CronJob",
+						"spec": map[string]interface{}{
+							"jobTemplate": map[string]interface{}{
+								"spec": map[string]interface{}{
+									"template": map[string]interface{}{
+										"spec": map[string]interface{}{
+											"nodeSelector": map[string]interface{}{
+												"kubernetes.io/os": "windows",
+											},
+										},
+									},
+								},
+							},
+						},
+					},
+				}
+			})
+
+			It("returns the pod spec", func() {
+				Expect(err).To(BeNil())
+				Expect(ok).To(BeTrue())
+				Expect(spec.NodeSelector).To(Equal(map[string]string{"kubernetes.io/os": "windows"}))
+			})
+		})
+	})
+
+	Describe("#Nodes", func() {
+		It("converts the nodes", func() {
+			list := &unstructured.UnstructuredList{
+				Items: []unstructured.Unstructured{
+					{
+						Object: map[string]interface{}{
+							"metadata": map[string]interface{}{
+								"name": "test-node",
+							},
+							"spec": map[string]interface{}{
+								"unschedulable": true,
+							},
+						},
+					},
+				},
+			}
+			nodes, err := Nodes(list)
+			Expect(err).To(BeNil())
+			Expect(nodes).To(HaveLen(1))
+			Expect(nodes[0].Name).To(Equal("test-node"))
+			Expect(nodes[0].Spec.Unschedulable).To(BeTrue())
+		})
+	})
+})
@@ -0,0 +1,223 @@
+package kubernetes
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/version"
+)
+
+// scopedClient only reads and changes resources in the namespaces a provider
+// is scoped to, and the namespaces themselves. Other resources, including
+// cluster-scoped resources, are left out of lists and are not found, and
+// only namespaced kinds can be changed, even in a namespace of the scope.
+type scopedClient struct {
+	client Client
+	p      Provider
+}
+
+func (s *scopedClient) namespaceErr(namespace string) error {
+	return fmt.Errorf("account %s is not scoped to namespace %s", s.p.Name, namespace)
+}
+
+// namespaced returns an error if resources of kind are not namespaced
+// according to the REST mapping of the cluster, as changing them changes
+// them for every namespace.
+func (s *scopedClient) namespaced(kind string) error {
+	namespaced, err := s.client.NamespaceScoped(strings.ToLower(kind))
+	if err != nil {
+		return err
+	}
+
+	if !namespaced {
+		return fmt.Errorf("account %s is scoped to namespaces and cannot change cluster-scoped kind %s", s.p.Name, kind)
+	}
+
+	return nil
+}
+
+// inScope returns true if a resource is in a namespace of the scope, or is
+// one of its namespaces.
+func (s *scopedClient) inScope(namespaces bool, name, namespace string) bool {
+	if namespaces {
+		return s.p.InNamespaceScope(name)
+	}
+
+	return namespace != "" && s.p.InNamespaceScope(namespace)
+}
+
+// applyNamespace returns the namespace u is applied to, "default" if it has
+// none, as for deploy operations.
+func applyNamespace(u *unstructured.Unstructured) string {
+	if u.GetNamespace() == "" {
+		return "default"
+	}
+
+	return u.GetNamespace()
+}
+
+func (s *scopedClient) Apply(u *unstructured.Unstructured) (Metadata, error) {
+	if err := s.namespaced(u.GetKind()); err != nil {
+		return Metadata{}, err
+	}
+
+	if namespace := applyNamespace(u); !s.p.InNamespaceScope(namespace) {
+		return Metadata{}, s.namespaceErr(namespace)
+	}
+
+	return s.client.Apply(u)
+}
+
+func (s *scopedClient) ApplyWithNamespaceOverride(u *unstructured.Unstructured, namespace string) (Metadata, error) {
+	if err := s.namespaced(u.GetKind()); err != nil {
+		return Metadata{}, err
+	}
+
+	if namespace == "" {
+		namespace = applyNamespace(u)
+	}
+
+	if !s.p.InNamespaceScope(namespace) {
+		return Metadata{}, s.namespaceErr(namespace)
+	}
+
+	return s.client.ApplyWithNamespaceOverride(u, namespace)
+}
+
+func (s *scopedClient) CanI(verb, group, resource, namespace string) (bool, error) {
+	if !s.p.InNamespaceScope(namespace) {
+		return false, nil
+	}
+
+	return s.client.CanI(verb, group, resource, namespace)
+}
+
+func (s *scopedClient) DeleteResourceByKindAndNameAndNamespace(kind, name, namespace string, do metav1.DeleteOptions) error {
+	if err := s.namespaced(kind); err != nil {
+		return err
+	}
+
+	if !s.p.InNamespaceScope(namespace) {
+		return s.namespaceErr(namespace)
+	}
+
+	return s.client.DeleteResourceByKindAndNameAndNamespace(kind, name, namespace, do)
+}
+
+func (s *scopedClient) GVRForKind(kind string) (schema.GroupVersionResource, error) {
+	return s.client.GVRForKind(kind)
+}
+
+func (s *scopedClient) Get(kind, name, namespace string) (*unstructured.Unstructured, error) {
+	u, err := s.client.Get(kind, name, namespace)
+	if err != nil {
+		return nil, err
+	}
+
+	if !s.inScope(u.GetKind() == "Namespace", u.GetName(), u.GetNamespace()) {
+		return nil, errors.NewNotFound(schema.GroupResource{Resource: kind}, name)
+	}
+
+	return u, nil
+}
+
+func (s *scopedClient) InvalidateDiscovery() {
+	s.client.InvalidateDiscovery()
+}
+
+func (s *scopedClient) ListByGVR(gvr schema.GroupVersionResource, lo metav1.ListOptions) (*unstructured.UnstructuredList, error) {
+	l, err := s.client.ListByGVR(gvr, lo)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.filterUnstructured(l), nil
+}
+
+func (s *scopedClient) ListMetadataByGVR(gvr schema.GroupVersionResource, lo metav1.ListOptions) (*metav1.PartialObjectMetadataList, error) {
+	l, err := s.client.ListMetadataByGVR(gvr, lo)
+	if err != nil {
+		return nil, err
+	}
+
+	scoped := l.DeepCopy()
+	scoped.Items = nil
+
+	for _, item := range l.Items {
+		if s.inScope(gvr.Resource == "namespaces", item.Name, item.Namespace) {
+			scoped.Items = append(scoped.Items, item)
+		}
+	}
+
+	return scoped, nil
+}
+
+func (s *scopedClient) ListResource(resource string, lo metav1.ListOptions) (*unstructured.UnstructuredList, error) {
+	l, err := s.client.ListResource(resource, lo)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.filterUnstructured(l), nil
+}
+
+func (s *scopedClient) filterUnstructured(l *unstructured.UnstructuredList) *unstructured.UnstructuredList {
+	scoped := &unstructured.UnstructuredList{Object: l.Object}
+
+	for _, item := range l.Items {
+		if s.inScope(item.GetKind() == "Namespace", item.GetName(), item.GetNamespace()) {
+			scoped.Items = append(scoped.Items, item)
+		}
+	}
+
+	return scoped
+}
+
+func (s *scopedClient) NamespaceScoped(kind string) (bool, error) {
+	return s.client.NamespaceScoped(kind)
+}
+
+func (s *scopedClient) Patch(kind, name, namespace string, p []byte) (Metadata, *unstructured.Unstructured, error) {
+	if err := s.namespaced(kind); err != nil {
+		return Metadata{}, nil, err
+	}
+
+	if !s.p.InNamespaceScope(namespace) {
+		return Metadata{}, nil, s.namespaceErr(namespace)
+	}
+
+	return s.client.Patch(kind, name, namespace, p)
+}
+
+func (s *scopedClient) PatchUsingStrategy(kind, name, namespace string, p []byte,
+	strategy types.PatchType) (Metadata, *unstructured.Unstructured, error) {
+	if err := s.namespaced(kind); err != nil {
+		return Metadata{}, nil, err
+	}
+
+	if !s.p.InNamespaceScope(namespace) {
+		return Metadata{}, nil, s.namespaceErr(namespace)
+	}
+
+	return s.client.PatchUsingStrategy(kind, name, namespace, p, strategy)
+}
+
+func (s *scopedClient) ServerVersion() (*version.Info, error) {
+	return s.client.ServerVersion()
+}
+
+func (s *scopedClient) StreamLogs(ctx context.Context, name, namespace string, plo corev1.PodLogOptions) (io.ReadCloser, error) {
+	if !s.p.InNamespaceScope(namespace) {
+		return nil, errors.NewNotFound(schema.GroupResource{Resource: "pods"}, name)
+	}
+
+	return s.client.StreamLogs(ctx, name, namespace, plo)
+}
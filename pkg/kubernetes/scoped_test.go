@@ -0,0 +1,239 @@
+package kubernetes_test
+
+import (
+	"errors"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/rest"
+
+	. "github.com/billiford/go-clouddriver/pkg/kubernetes"
+	"github.com/billiford/go-clouddriver/pkg/kubernetes/kubernetesfakes"
+)
+
+var _ = Describe("Namespace scoped clients", func() {
+	var (
+		fakeController *kubernetesfakes.FakeController
+		fakeClient     *kubernetesfakes.FakeClient
+		provider       Provider
+		client         Client
+		err            error
+	)
+
+	newUnstructured := func(kind, namespace, name string) unstructured.Unstructured {
+		u := unstructured.Unstructured{}
+		u.SetKind(kind)
+		u.SetNamespace(namespace)
+		u.SetName(name)
+
+		return u
+	}
+
+	BeforeEach(func() {
+		fakeController = &kubernetesfakes.FakeController{}
+		fakeClient = &kubernetesfakes.FakeClient{}
+		fakeClient.NamespaceScopedReturns(true, nil)
+		fakeController.NewClientReturns(fakeClient, nil)
+		provider = Provider{
+			Name:       "team-a",
+			Host:       "https://shared",
+			Namespaces: ProviderNamespaces{"team-a", "team-a-canary"},
+		}
+	})
+
+	JustBeforeEach(func() {
+		client, err = NewProviderClient(fakeController, provider, &rest.Config{Host: provider.Host})
+		Expect(err).To(BeNil())
+	})
+
+	When("the provider is not scoped", func() {
+		BeforeEach(func() {
+			provider.Namespaces = nil
+		})
+
+		It("returns the client of the cluster", func() {
+			Expect(client).To(BeIdenticalTo(fakeClient))
+		})
+	})
+
+	Describe("#ListResource", func() {
+		var l *unstructured.UnstructuredList
+
+		BeforeEach(func() {
+			fakeClient.ListResourceReturns(&unstructured.UnstructuredList{
+				Items: []unstructured.Unstructured{
+					newUnstructured("Deployment", "team-a", "in-scope"),
+					newUnstructured("Deployment", "team-b", "out-of-scope"),
+					newUnstructured("ClusterRole", "", "cluster-scoped"),
+					newUnstructured("Namespace", "", "team-a-canary"),
+					newUnstructured("Namespace", "", "team-b"),
+				},
+			}, nil)
+		})
+
+		JustBeforeEach(func() {
+			l, err = client.ListResource("deployments", metav1.ListOptions{})
+		})
+
+		It("only lists resources in the scope and its namespaces", func() {
+			Expect(err).To(BeNil())
+			names := []string{}
+			for _, item := range l.Items {
+				names = append(names, item.GetName())
+			}
+			Expect(names).To(Equal([]string{"in-scope", "team-a-canary"}))
+		})
+	})
+
+	Describe("#ListMetadataByGVR", func() {
+		var l *metav1.PartialObjectMetadataList
+
+		BeforeEach(func() {
+			fakeClient.ListMetadataByGVRReturns(&metav1.PartialObjectMetadataList{
+				Items: []metav1.PartialObjectMetadata{
+					{ObjectMeta: metav1.ObjectMeta{Name: "team-a"}},
+					{ObjectMeta: metav1.ObjectMeta{Name: "team-b"}},
+				},
+			}, nil)
+		})
+
+		JustBeforeEach(func() {
+			l, err = client.ListMetadataByGVR(schema.GroupVersionResource{Version: "v1", Resource: "namespaces"}, metav1.ListOptions{})
+		})
+
+		It("only lists the namespaces of the scope", func() {
+			Expect(err).To(BeNil())
+			Expect(l.Items).To(HaveLen(1))
+			Expect(l.Items[0].Name).To(Equal("team-a"))
+		})
+	})
+
+	Describe("#Get", func() {
+		BeforeEach(func() {
+			u := newUnstructured("Deployment", "team-b", "out-of-scope")
+			fakeClient.GetReturns(&u, nil)
+		})
+
+		JustBeforeEach(func() {
+			_, err = client.Get("deployment", "out-of-scope", "team-b")
+		})
+
+		It("does not find resources outside the scope", func() {
+			Expect(k8serrors.IsNotFound(err)).To(BeTrue())
+		})
+	})
+
+	Describe("#Apply", func() {
+		var u unstructured.Unstructured
+
+		BeforeEach(func() {
+			u = newUnstructured("Deployment", "", "test-deployment")
+		})
+
+		JustBeforeEach(func() {
+			_, err = client.Apply(&u)
+		})
+
+		When("the manifest has no namespace", func() {
+			It("returns an error as it would be applied to default", func() {
+				Expect(err).ToNot(BeNil())
+				Expect(err.Error()).To(Equal("account team-a is not scoped to namespace default"))
+				Expect(fakeClient.ApplyCallCount()).To(BeZero())
+			})
+		})
+
+		When("the manifest is in the scope", func() {
+			BeforeEach(func() {
+				u.SetNamespace("team-a")
+			})
+
+			It("applies it", func() {
+				Expect(err).To(BeNil())
+				Expect(fakeClient.ApplyCallCount()).To(Equal(1))
+				Expect(fakeClient.NamespaceScopedArgsForCall(0)).To(Equal("deployment"))
+			})
+		})
+
+		When("the kind is cluster-scoped", func() {
+			BeforeEach(func() {
+				u = newUnstructured("ClusterRoleBinding", "team-a", "test-binding")
+				fakeClient.NamespaceScopedReturns(false, nil)
+			})
+
+			It("returns an error even with a namespace in the scope", func() {
+				Expect(err).ToNot(BeNil())
+				Expect(err.Error()).To(Equal("account team-a is scoped to namespaces and cannot change cluster-scoped kind ClusterRoleBinding"))
+				Expect(fakeClient.ApplyCallCount()).To(BeZero())
+			})
+		})
+
+		When("the scope of the kind cannot be found", func() {
+			BeforeEach(func() {
+				u.SetNamespace("team-a")
+				fakeClient.NamespaceScopedReturns(false, errors.New("no matches for kind"))
+			})
+
+			It("returns an error", func() {
+				Expect(err).ToNot(BeNil())
+				Expect(err.Error()).To(Equal("no matches for kind"))
+				Expect(fakeClient.ApplyCallCount()).To(BeZero())
+			})
+		})
+	})
+
+	Describe("#DeleteResourceByKindAndNameAndNamespace", func() {
+		var kind, namespace string
+
+		BeforeEach(func() {
+			kind = "deployment"
+			namespace = "team-b"
+		})
+
+		JustBeforeEach(func() {
+			err = client.DeleteResourceByKindAndNameAndNamespace(kind, "test", namespace, metav1.DeleteOptions{})
+		})
+
+		It("returns an error", func() {
+			Expect(err).ToNot(BeNil())
+			Expect(err.Error()).To(Equal("account team-a is not scoped to namespace team-b"))
+			Expect(fakeClient.DeleteResourceByKindAndNameAndNamespaceCallCount()).To(BeZero())
+		})
+
+		When("the kind is cluster-scoped", func() {
+			BeforeEach(func() {
+				kind = "clusterrole"
+				namespace = "team-a"
+				fakeClient.NamespaceScopedReturns(false, nil)
+			})
+
+			It("returns an error even with a namespace in the scope", func() {
+				Expect(err).ToNot(BeNil())
+				Expect(err.Error()).To(Equal("account team-a is scoped to namespaces and cannot change cluster-scoped kind clusterrole"))
+				Expect(fakeClient.DeleteResourceByKindAndNameAndNamespaceCallCount()).To(BeZero())
+			})
+		})
+	})
+
+	Describe("#PatchUsingStrategy", func() {
+		JustBeforeEach(func() {
+			_, _, err = client.PatchUsingStrategy("namespace", "team-a", "team-a", []byte("{}"), types.MergePatchType)
+		})
+
+		When("the kind is cluster-scoped", func() {
+			BeforeEach(func() {
+				fakeClient.NamespaceScopedReturns(false, nil)
+			})
+
+			It("returns an error", func() {
+				Expect(err).ToNot(BeNil())
+				Expect(err.Error()).To(Equal("account team-a is scoped to namespaces and cannot change cluster-scoped kind namespace"))
+				Expect(fakeClient.PatchUsingStrategyCallCount()).To(BeZero())
+			})
+		})
+	})
+})
@@ -0,0 +1,134 @@
+package kubernetes
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"regexp"
+)
+
+const (
+	TemplateValueAccount     = `account`
+	TemplateValueNamespace   = `namespace`
+	TemplateValueApplication = `application`
+)
+
+var (
+	defaultTemplateConfigPath = "/opt/spinnaker/kubernetes/templating.json"
+	// templatePlaceholder matches ${name} placeholders and $${name} escapes.
+	templatePlaceholder = regexp.MustCompile(`\$?\$\{([A-Za-z0-9_.-]+)\}`)
+)
+
+// Templating enables substituting ${name} placeholders in deployed manifests,
+// with values in addition to the account, namespace and application.
+type Templating struct {
+	Enabled *bool             `json:"enabled,omitempty"`
+	Values  map[string]string `json:"values,omitempty"`
+}
+
+// TemplateConfig defines which accounts substitute placeholders in deployed
+// manifests and the values they substitute. Templating is disabled by default,
+// as placeholders such as ${HOME} in container commands are common.
+//
+//	{
+//	  "enabled": false,
+//	  "values": {
+//	    "registry": "gcr.io/example"
+//	  },
+//	  "accounts": {
+//	    "prod-account": {
+//	      "enabled": true,
+//	      "values": {
+//	        "environment": "prod"
+//	      }
+//	    }
+//	  }
+//	}
+type TemplateConfig struct {
+	Templating
+	Accounts map[string]Templating `json:"accounts,omitempty"`
+}
+
+// NewDefaultTemplateConfig reads the template config from /opt/spinnaker/kubernetes/templating.json.
+// The config is optional, so if the file does not exist templating is disabled.
+func NewDefaultTemplateConfig() (TemplateConfig, error) {
+	if _, err := os.Stat(defaultTemplateConfigPath); os.IsNotExist(err) {
+		return TemplateConfig{}, nil
+	}
+
+	return NewTemplateConfig(defaultTemplateConfigPath)
+}
+
+// NewTemplateConfig reads the template config from a JSON file.
+func NewTemplateConfig(path string) (TemplateConfig, error) {
+	tc := TemplateConfig{}
+
+	b, err := ioutil.ReadFile(path)
+	if err != nil {
+		return tc, err
+	}
+
+	err = json.Unmarshal(b, &tc)
+	if err != nil {
+		return tc, err
+	}
+
+	return tc, nil
+}
+
+// EnabledFor returns true if an account substitutes placeholders.
+func (tc TemplateConfig) EnabledFor(account string) bool {
+	if enabled := tc.Accounts[account].Enabled; enabled != nil {
+		return *enabled
+	}
+
+	return tc.Templating.Enabled != nil && *tc.Templating.Enabled
+}
+
+// ValuesFor returns the values of an account merged with those of all accounts.
+func (tc TemplateConfig) ValuesFor(account string) map[string]string {
+	values := map[string]string{}
+
+	for _, t := range []Templating{tc.Templating, tc.Accounts[account]} {
+		for k, v := range t.Values {
+			values[k] = v
+		}
+	}
+
+	return values
+}
+
+// SubstituteTemplateValues replaces ${name} placeholders in the string values
+// of a manifest with their values. Placeholders without a value are left as is,
+// and $${name} is replaced with a literal ${name}.
+func SubstituteTemplateValues(manifest map[string]interface{}, values map[string]string) {
+	for k, v := range manifest {
+		manifest[k] = substitute(v, values)
+	}
+}
+
+func substitute(v interface{}, values map[string]string) interface{} {
+	switch t := v.(type) {
+	case string:
+		return templatePlaceholder.ReplaceAllStringFunc(t, func(placeholder string) string {
+			if placeholder[1] == '$' {
+				return placeholder[1:]
+			}
+
+			name := templatePlaceholder.FindStringSubmatch(placeholder)[1]
+			if value, ok := values[name]; ok {
+				return value
+			}
+
+			return placeholder
+		})
+	case map[string]interface{}:
+		SubstituteTemplateValues(t, values)
+	case []interface{}:
+		for i := range t {
+			t[i] = substitute(t[i], values)
+		}
+	}
+
+	return v
+}
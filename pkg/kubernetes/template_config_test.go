@@ -0,0 +1,124 @@
+package kubernetes_test
+
+import (
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	. "github.com/billiford/go-clouddriver/pkg/kubernetes"
+)
+
+var _ = Describe("TemplateConfig", func() {
+	var (
+		tc  TemplateConfig
+		err error
+	)
+
+	Describe("#NewTemplateConfig", func() {
+		When("the file does not exist", func() {
+			BeforeEach(func() {
+				tc, err = NewTemplateConfig("test/missing.json")
+			})
+
+			It("returns an error", func() {
+				Expect(err).ToNot(BeNil())
+			})
+		})
+
+		When("it succeeds", func() {
+			BeforeEach(func() {
+				tc, err = NewTemplateConfig("test/templating.json")
+			})
+
+			It("reads the config", func() {
+				Expect(err).To(BeNil())
+				Expect(tc.Values).To(HaveKeyWithValue("registry", "gcr.io/example"))
+				Expect(tc.Accounts).To(HaveKey("prod-account"))
+			})
+		})
+	})
+
+	Describe("#EnabledFor", func() {
+		BeforeEach(func() {
+			tc, err = NewTemplateConfig("test/templating.json")
+			Expect(err).To(BeNil())
+		})
+
+		When("the account enables templating", func() {
+			It("returns true", func() {
+				Expect(tc.EnabledFor("prod-account")).To(BeTrue())
+			})
+		})
+
+		When("the account is not configured and templating is disabled", func() {
+			It("returns false", func() {
+				Expect(tc.EnabledFor("test-account")).To(BeFalse())
+			})
+		})
+
+		When("templating is enabled for all accounts", func() {
+			BeforeEach(func() {
+				enabled := true
+				tc.Enabled = &enabled
+			})
+
+			It("returns true unless the account disables it", func() {
+				Expect(tc.EnabledFor("test-account")).To(BeTrue())
+				Expect(tc.EnabledFor("legacy-account")).To(BeFalse())
+			})
+		})
+	})
+
+	Describe("#ValuesFor", func() {
+		BeforeEach(func() {
+			tc, err = NewTemplateConfig("test/templating.json")
+			Expect(err).To(BeNil())
+		})
+
+		It("merges the account's values", func() {
+			Expect(tc.ValuesFor("prod-account")).To(Equal(map[string]string{
+				"registry":    "gcr.io/example",
+				"environment": "prod",
+			}))
+		})
+	})
+
+	Describe("#SubstituteTemplateValues", func() {
+		var manifest map[string]interface{}
+
+		BeforeEach(func() {
+			manifest = map[string]interface{}{
+				"kind": "Deployment",
+				"metadata": map[string]interface{}{
+					"name": "${application}-${environment}",
+				},
+				"spec": map[string]interface{}{
+					"replicas": int64(2),
+					"template": map[string]interface{}{
+						"spec": map[string]interface{}{
+							"containers": []interface{}{
+								map[string]interface{}{
+									"image":   "${registry}/app:1.0.0",
+									"command": []interface{}{"sh", "-c", "echo ${HOME} $${application}"},
+								},
+							},
+						},
+					},
+				},
+			}
+			SubstituteTemplateValues(manifest, map[string]string{
+				"application": "test-app",
+				"environment": "prod",
+				"registry":    "gcr.io/example",
+			})
+		})
+
+		It("substitutes the placeholders with values", func() {
+			Expect(manifest["metadata"]).To(HaveKeyWithValue("name", "test-app-prod"))
+			containers := manifest["spec"].(map[string]interface{})["template"].(map[string]interface{})["spec"].(map[string]interface{})["containers"].([]interface{})
+			container := containers[0].(map[string]interface{})
+			Expect(container["image"]).To(Equal("gcr.io/example/app:1.0.0"))
+			Expect(container["command"]).To(Equal([]interface{}{"sh", "-c", "echo ${HOME} ${application}"}))
+			Expect(manifest["spec"]).To(HaveKeyWithValue("replicas", int64(2)))
+		})
+	})
+})
@@ -0,0 +1,149 @@
+package kubernetes
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	authenticationv1 "k8s.io/api/authentication/v1"
+	clientset "k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/rest"
+)
+
+// Minted tokens are requested to expire after an hour, and are minted again
+// once most of their lifetime has passed so requests never use an expired one.
+var (
+	tokenExpirationSeconds = int64(3600)
+	tokenRefreshFraction   = 0.8
+)
+
+type mintedToken struct {
+	token   string
+	refresh time.Time
+	used    time.Time
+}
+
+// MintToken replaces the bearer token of config with a short-lived token of
+// the provider's token service account, minted by the TokenRequest API
+// using the token of config as the bootstrap credential. Tokens are cached
+// per cluster and service account until they are due to be minted again.
+// Configs of providers without a token service account keep their token.
+//
+// As every client of a provider is created with a config passed to
+// MintToken, it also gives config the credentials of the kubeconfig, the
+// bearer token or the token of the token provider of the provider, and its
+// transport, if it has them. The bearer token is the one stored with the
+// provider or its cluster credential, such as a rotated one. Tokens are then
+// minted with those credentials. Configs of fake
+// providers are only pointed at their synthetic cluster.
+func (c *controller) MintToken(p Provider, config *rest.Config) error {
+	if p.Fake != nil {
+		return c.useFake(p, config)
+	}
+
+	err := useKubeconfig(p, config)
+	if err != nil {
+		return fmt.Errorf("error using kubeconfig of provider %s: %w", p.Name, err)
+	}
+
+	if p.BearerToken != "" {
+		config.BearerToken = p.BearerToken
+		config.BearerTokenFile = ""
+	}
+
+	err = c.fetchToken(p, config)
+	if err != nil {
+		return err
+	}
+
+	err = c.transports.configure(p.Transport, config)
+	if err != nil {
+		return fmt.Errorf("error configuring transport: %w", err)
+	}
+
+	if p.TokenServiceAccount == "" {
+		return nil
+	}
+
+	if !p.ValidTokenServiceAccount() {
+		return fmt.Errorf("token service account %q must be namespace/name", p.TokenServiceAccount)
+	}
+
+	a := strings.Split(p.TokenServiceAccount, "/")
+	namespace, name := a[0], a[1]
+	key := config.Host + " " + p.TokenServiceAccount
+
+	now := time.Now()
+
+	c.mux.Lock()
+	t, ok := c.tokens[key]
+	if ok && now.Before(t.refresh) {
+		t.used = now
+		c.tokens[key] = t
+	}
+	c.mux.Unlock()
+
+	if ok && now.Before(t.refresh) {
+		config.BearerToken = t.token
+		return nil
+	}
+
+	cs, err := clientset.NewForConfig(config)
+	if err != nil {
+		return err
+	}
+
+	tr := &authenticationv1.TokenRequest{
+		Spec: authenticationv1.TokenRequestSpec{
+			ExpirationSeconds: &tokenExpirationSeconds,
+		},
+	}
+	result := &authenticationv1.TokenRequest{}
+
+	err = cs.CoreV1().RESTClient().Post().
+		Namespace(namespace).
+		Resource("serviceaccounts").
+		Name(name).
+		SubResource("token").
+		Body(tr).
+		Do(context.TODO()).
+		Into(result)
+	if err != nil {
+		return fmt.Errorf("error minting token of service account %s: %w", p.TokenServiceAccount, err)
+	}
+
+	now = time.Now()
+	lifetime := result.Status.ExpirationTimestamp.Sub(now)
+	t = mintedToken{
+		token:   result.Status.Token,
+		refresh: now.Add(time.Duration(float64(lifetime) * tokenRefreshFraction)),
+		used:    now,
+	}
+
+	c.mux.Lock()
+	c.tokens[key] = t
+	c.mux.Unlock()
+
+	config.BearerToken = t.token
+
+	return nil
+}
+
+// evictIdleTokens forgets the minted tokens last used before t, such as
+// those of deleted accounts, returning how many were forgotten.
+func (c *controller) evictIdleTokens(t time.Time) int {
+	c.mux.Lock()
+	defer c.mux.Unlock()
+
+	evicted := 0
+
+	for key, mt := range c.tokens {
+		if mt.used.Before(t) {
+			delete(c.tokens, key)
+			evicted++
+		}
+	}
+
+	return evicted
+}
@@ -0,0 +1,400 @@
+package kubernetes
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"database/sql/driver"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+	"time"
+
+	"golang.org/x/oauth2/google"
+	"k8s.io/client-go/rest"
+)
+
+// Types of token providers.
+const (
+	// TokenProviderAWS signs tokens of EKS clusters, as aws-iam-authenticator does.
+	TokenProviderAWS = `aws`
+	// TokenProviderGoogle gets access tokens of Google application default
+	// credentials, as the gcloud auth plugin of GKE clusters does.
+	TokenProviderGoogle = `google`
+	// TokenProviderHTTP gets tokens from an external service, such as arcade.
+	TokenProviderHTTP = `http`
+)
+
+const (
+	awsTokenPrefix = `k8s-aws-v1.`
+	// EKS accepts tokens for 15 minutes after they are signed. As
+	// aws-iam-authenticator, they are expired a minute early.
+	awsTokenLifetime = 14 * time.Minute
+	// Tokens of token services that do not say when they expire are fetched
+	// again after a minute.
+	defaultTokenLifetime = time.Minute
+	tokenFetchTimeout    = 30 * time.Second
+)
+
+var defaultGoogleScopes = []string{
+	"https://www.googleapis.com/auth/cloud-platform",
+	"https://www.googleapis.com/auth/userinfo.email",
+}
+
+// ProviderTokenProvider fetches the bearer tokens of a provider from a cloud
+// or an external service instead of using a stored token, such as for EKS
+// and GKE clusters whose tokens expire.
+type ProviderTokenProvider struct {
+	// Type is aws, google or http.
+	Type string `json:"type"`
+	// ClusterName is the name of the EKS cluster of aws token providers, and
+	// Region its region.
+	ClusterName string `json:"clusterName,omitempty"`
+	Region      string `json:"region,omitempty"`
+	// Scopes of the tokens of google token providers, the cloud-platform and
+	// userinfo.email scopes if not set.
+	Scopes []string `json:"scopes,omitempty"`
+	// URL of the service of http token providers, see httpTokenProvider.
+	URL string `json:"url,omitempty"`
+}
+
+// Validate returns an error if the type of the token provider is unknown or
+// a setting it needs is missing.
+func (ptp ProviderTokenProvider) Validate() error {
+	switch ptp.Type {
+	case TokenProviderAWS:
+		if ptp.ClusterName == "" || ptp.Region == "" {
+			return errors.New("aws token providers must set a clusterName and region")
+		}
+	case TokenProviderGoogle:
+	case TokenProviderHTTP:
+		u, err := url.Parse(ptp.URL)
+		if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+			return fmt.Errorf("url %q of http token provider must be an http or https URL", ptp.URL)
+		}
+	default:
+		return fmt.Errorf("unknown token provider type %q, must be %s, %s or %s",
+			ptp.Type, TokenProviderAWS, TokenProviderGoogle, TokenProviderHTTP)
+	}
+
+	return nil
+}
+
+// Value implements driver.Valuer.
+func (ptp ProviderTokenProvider) Value() (driver.Value, error) {
+	b, err := json.Marshal(ptp)
+	if err != nil {
+		return nil, err
+	}
+
+	return string(b), nil
+}
+
+// Scan implements sql.Scanner.
+func (ptp *ProviderTokenProvider) Scan(src interface{}) error {
+	var b []byte
+
+	switch v := src.(type) {
+	case nil:
+		*ptp = ProviderTokenProvider{}
+		return nil
+	case string:
+		b = []byte(v)
+	case []byte:
+		b = v
+	default:
+		return fmt.Errorf("cannot scan %T into provider token provider", src)
+	}
+
+	if len(b) == 0 {
+		*ptp = ProviderTokenProvider{}
+		return nil
+	}
+
+	return json.Unmarshal(b, ptp)
+}
+
+// ValidateTokenProvider returns an error if the token provider of the
+// provider is invalid, or the provider also has a kubeconfig, whose
+// credentials are used instead.
+func (p Provider) ValidateTokenProvider() error {
+	if p.TokenProvider == nil {
+		return nil
+	}
+
+	if p.HasKubeconfig() {
+		return errors.New("providers with a kubeconfig must not set a tokenProvider")
+	}
+
+	return p.TokenProvider.Validate()
+}
+
+// TokenProvider fetches short-lived tokens.
+type TokenProvider interface {
+	// Token returns a token and when it expires.
+	Token(ctx context.Context) (string, time.Time, error)
+}
+
+// NewTokenProvider returns the TokenProvider of the tokens of account.
+// Credentials of aws token providers are read from the environment
+// variables AWS_ACCESS_KEY_ID, AWS_SECRET_ACCESS_KEY and (optionally)
+// AWS_SESSION_TOKEN.
+func NewTokenProvider(ptp ProviderTokenProvider, account string) (TokenProvider, error) {
+	err := ptp.Validate()
+	if err != nil {
+		return nil, err
+	}
+
+	switch ptp.Type {
+	case TokenProviderAWS:
+		return &awsTokenProvider{
+			clusterName:  ptp.ClusterName,
+			region:       ptp.Region,
+			accessKeyID:  os.Getenv("AWS_ACCESS_KEY_ID"),
+			secretKey:    os.Getenv("AWS_SECRET_ACCESS_KEY"),
+			sessionToken: os.Getenv("AWS_SESSION_TOKEN"),
+		}, nil
+	case TokenProviderGoogle:
+		scopes := ptp.Scopes
+		if len(scopes) == 0 {
+			scopes = defaultGoogleScopes
+		}
+
+		return &googleTokenProvider{scopes: scopes}, nil
+	}
+
+	return &httpTokenProvider{
+		url:     ptp.URL,
+		account: account,
+	}, nil
+}
+
+// awsTokenProvider signs a presigned STS GetCallerIdentity request naming
+// an EKS cluster, which EKS makes to authenticate the caller.
+//
+// See https://github.com/kubernetes-sigs/aws-iam-authenticator#how-does-it-work.
+type awsTokenProvider struct {
+	clusterName  string
+	region       string
+	accessKeyID  string
+	secretKey    string
+	sessionToken string
+}
+
+func (a *awsTokenProvider) Token(ctx context.Context) (string, time.Time, error) {
+	if a.accessKeyID == "" || a.secretKey == "" {
+		return "", time.Time{}, errors.New("AWS_ACCESS_KEY_ID and AWS_SECRET_ACCESS_KEY must be set to use aws token providers")
+	}
+
+	t := time.Now().UTC()
+	amzDate := t.Format("20060102T150405Z")
+	date := t.Format("20060102")
+	scope := fmt.Sprintf("%s/%s/sts/aws4_request", date, a.region)
+	host := fmt.Sprintf("sts.%s.amazonaws.com", a.region)
+	signedHeaders := "host;x-k8s-aws-id"
+
+	q := url.Values{}
+	q.Set("Action", "GetCallerIdentity")
+	q.Set("Version", "2011-06-15")
+	q.Set("X-Amz-Algorithm", "AWS4-HMAC-SHA256")
+	q.Set("X-Amz-Credential", a.accessKeyID+"/"+scope)
+	q.Set("X-Amz-Date", amzDate)
+	q.Set("X-Amz-Expires", "60")
+	q.Set("X-Amz-SignedHeaders", signedHeaders)
+
+	if a.sessionToken != "" {
+		q.Set("X-Amz-Security-Token", a.sessionToken)
+	}
+
+	// Signature Version 4 escapes spaces as %20.
+	query := strings.ReplaceAll(q.Encode(), "+", "%20")
+
+	canonicalRequest := strings.Join([]string{
+		http.MethodGet,
+		"/",
+		query,
+		fmt.Sprintf("host:%s\nx-k8s-aws-id:%s\n", host, a.clusterName),
+		signedHeaders,
+		hashHex(""),
+	}, "\n")
+
+	stringToSign := strings.Join([]string{
+		"AWS4-HMAC-SHA256",
+		amzDate,
+		scope,
+		hashHex(canonicalRequest),
+	}, "\n")
+
+	key := hmacSHA256([]byte("AWS4"+a.secretKey), date)
+	key = hmacSHA256(key, a.region)
+	key = hmacSHA256(key, "sts")
+	key = hmacSHA256(key, "aws4_request")
+	signature := hex.EncodeToString(hmacSHA256(key, stringToSign))
+
+	presigned := fmt.Sprintf("https://%s/?%s&X-Amz-Signature=%s", host, query, signature)
+
+	return awsTokenPrefix + base64.RawURLEncoding.EncodeToString([]byte(presigned)), t.Add(awsTokenLifetime), nil
+}
+
+func hashHex(s string) string {
+	h := sha256.Sum256([]byte(s))
+	return hex.EncodeToString(h[:])
+}
+
+func hmacSHA256(key []byte, data string) []byte {
+	h := hmac.New(sha256.New, key)
+	h.Write([]byte(data))
+
+	return h.Sum(nil)
+}
+
+// googleTokenProvider gets access tokens of the application default credentials.
+type googleTokenProvider struct {
+	scopes []string
+}
+
+func (g *googleTokenProvider) Token(ctx context.Context) (string, time.Time, error) {
+	ts, err := google.DefaultTokenSource(ctx, g.scopes...)
+	if err != nil {
+		return "", time.Time{}, err
+	}
+
+	t, err := ts.Token()
+	if err != nil {
+		return "", time.Time{}, err
+	}
+
+	return t.AccessToken, t.Expiry, nil
+}
+
+// httpTokenProvider gets tokens of an account from a token service, passed
+// the account as the query param 'account', which must respond with the
+// token and, optionally, when it expires:
+//
+//	{"token": "some.bearer.token", "expiresAt": "2020-10-14T12:00:00Z"}
+type httpTokenProvider struct {
+	url     string
+	account string
+}
+
+func (h *httpTokenProvider) Token(ctx context.Context) (string, time.Time, error) {
+	u, err := url.Parse(h.url)
+	if err != nil {
+		return "", time.Time{}, err
+	}
+
+	q := u.Query()
+	q.Set("account", h.account)
+	u.RawQuery = q.Encode()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, u.String(), nil)
+	if err != nil {
+		return "", time.Time{}, err
+	}
+
+	req.Header.Set("Accept", "application/json")
+
+	res, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return "", time.Time{}, err
+	}
+	defer res.Body.Close()
+
+	b, err := ioutil.ReadAll(res.Body)
+	if err != nil {
+		return "", time.Time{}, err
+	}
+
+	if res.StatusCode < 200 || res.StatusCode > 399 {
+		return "", time.Time{}, fmt.Errorf("error getting token: %s", res.Status)
+	}
+
+	var response struct {
+		Token     string    `json:"token"`
+		ExpiresAt time.Time `json:"expiresAt"`
+	}
+
+	err = json.Unmarshal(b, &response)
+	if err != nil {
+		return "", time.Time{}, err
+	}
+
+	if response.Token == "" {
+		return "", time.Time{}, errors.New("error getting token: token service responded without one")
+	}
+
+	if response.ExpiresAt.IsZero() {
+		response.ExpiresAt = time.Now().Add(defaultTokenLifetime)
+	}
+
+	return response.Token, response.ExpiresAt, nil
+}
+
+// fetchToken replaces the bearer token of config with a token of the token
+// provider of p, if it has one. Tokens are cached per account and token
+// provider until most of their lifetime has passed, so requests never use
+// an expired one.
+func (c *controller) fetchToken(p Provider, config *rest.Config) error {
+	if p.TokenProvider == nil {
+		return nil
+	}
+
+	b, err := json.Marshal(p.TokenProvider)
+	if err != nil {
+		return err
+	}
+
+	key := "account " + p.Name + " " + string(b)
+	now := time.Now()
+
+	c.mux.Lock()
+	t, ok := c.tokens[key]
+	if ok && now.Before(t.refresh) {
+		t.used = now
+		c.tokens[key] = t
+	}
+	c.mux.Unlock()
+
+	if ok && now.Before(t.refresh) {
+		config.BearerToken = t.token
+		config.BearerTokenFile = ""
+
+		return nil
+	}
+
+	tp, err := NewTokenProvider(*p.TokenProvider, p.Name)
+	if err != nil {
+		return err
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), tokenFetchTimeout)
+	defer cancel()
+
+	token, expiry, err := tp.Token(ctx)
+	if err != nil {
+		return fmt.Errorf("error fetching token of %s token provider: %w", p.TokenProvider.Type, err)
+	}
+
+	now = time.Now()
+	t = mintedToken{
+		token:   token,
+		refresh: now.Add(time.Duration(float64(expiry.Sub(now)) * tokenRefreshFraction)),
+		used:    now,
+	}
+
+	c.mux.Lock()
+	c.tokens[key] = t
+	c.mux.Unlock()
+
+	config.BearerToken = token
+	config.BearerTokenFile = ""
+
+	return nil
+}
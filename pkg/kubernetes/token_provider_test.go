@@ -0,0 +1,245 @@
+package kubernetes_test
+
+import (
+	"context"
+	"encoding/base64"
+	"fmt"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+	"time"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"github.com/onsi/gomega/ghttp"
+	"k8s.io/client-go/rest"
+
+	. "github.com/billiford/go-clouddriver/pkg/kubernetes"
+)
+
+var _ = Describe("TokenProvider", func() {
+	var (
+		fakeServer *ghttp.Server
+		err        error
+	)
+
+	BeforeEach(func() {
+		fakeServer = ghttp.NewServer()
+	})
+
+	AfterEach(func() {
+		fakeServer.Close()
+	})
+
+	Describe("#Validate", func() {
+		It("accepts token providers with their settings", func() {
+			Expect(ProviderTokenProvider{Type: TokenProviderAWS, ClusterName: "test-cluster", Region: "us-east-1"}.Validate()).To(Succeed())
+			Expect(ProviderTokenProvider{Type: TokenProviderGoogle}.Validate()).To(Succeed())
+			Expect(ProviderTokenProvider{Type: TokenProviderHTTP, URL: "https://arcade/tokens"}.Validate()).To(Succeed())
+		})
+
+		It("rejects unknown types and missing settings", func() {
+			Expect(ProviderTokenProvider{Type: "azure"}.Validate()).
+				To(MatchError(`unknown token provider type "azure", must be aws, google or http`))
+			Expect(ProviderTokenProvider{Type: TokenProviderAWS, ClusterName: "test-cluster"}.Validate()).ToNot(Succeed())
+			Expect(ProviderTokenProvider{Type: TokenProviderHTTP, URL: "arcade/tokens"}.Validate()).ToNot(Succeed())
+		})
+
+		It("rejects a token provider of a provider with a kubeconfig", func() {
+			p := Provider{
+				KubeconfigFile: "/opt/spinnaker/kubeconfig",
+				TokenProvider:  &ProviderTokenProvider{Type: TokenProviderGoogle},
+			}
+			Expect(p.ValidateTokenProvider()).To(MatchError("providers with a kubeconfig must not set a tokenProvider"))
+		})
+	})
+
+	It("is stored as JSON", func() {
+		ptp := ProviderTokenProvider{Type: TokenProviderGoogle, Scopes: []string{"test-scope"}}
+		v, err := ptp.Value()
+		Expect(err).To(BeNil())
+
+		scanned := ProviderTokenProvider{}
+		Expect(scanned.Scan(v)).To(Succeed())
+		Expect(scanned).To(Equal(ptp))
+	})
+
+	Describe("aws token providers", func() {
+		var (
+			token  string
+			expiry time.Time
+		)
+
+		BeforeEach(func() {
+			os.Setenv("AWS_ACCESS_KEY_ID", "test-access-key-id")
+			os.Setenv("AWS_SECRET_ACCESS_KEY", "test-secret-access-key")
+			os.Setenv("AWS_SESSION_TOKEN", "test-session-token")
+		})
+
+		AfterEach(func() {
+			os.Unsetenv("AWS_ACCESS_KEY_ID")
+			os.Unsetenv("AWS_SECRET_ACCESS_KEY")
+			os.Unsetenv("AWS_SESSION_TOKEN")
+		})
+
+		JustBeforeEach(func() {
+			var tp TokenProvider
+			tp, err = NewTokenProvider(ProviderTokenProvider{
+				Type:        TokenProviderAWS,
+				ClusterName: "test-cluster",
+				Region:      "us-west-2",
+			}, "test-account")
+			Expect(err).To(BeNil())
+			token, expiry, err = tp.Token(context.Background())
+		})
+
+		When("there are no credentials", func() {
+			BeforeEach(func() {
+				os.Unsetenv("AWS_SECRET_ACCESS_KEY")
+			})
+
+			It("returns an error", func() {
+				Expect(err).ToNot(BeNil())
+			})
+		})
+
+		It("returns a presigned STS request naming the cluster", func() {
+			Expect(err).To(BeNil())
+			Expect(token).To(HavePrefix("k8s-aws-v1."))
+			Expect(expiry).To(BeTemporally("~", time.Now().Add(14*time.Minute), time.Minute))
+
+			b, err := base64.RawURLEncoding.DecodeString(strings.TrimPrefix(token, "k8s-aws-v1."))
+			Expect(err).To(BeNil())
+			u, err := url.Parse(string(b))
+			Expect(err).To(BeNil())
+			Expect(u.Host).To(Equal("sts.us-west-2.amazonaws.com"))
+
+			q := u.Query()
+			Expect(q.Get("Action")).To(Equal("GetCallerIdentity"))
+			Expect(q.Get("X-Amz-Credential")).To(HavePrefix("test-access-key-id/"))
+			Expect(q.Get("X-Amz-Credential")).To(HaveSuffix("/us-west-2/sts/aws4_request"))
+			Expect(q.Get("X-Amz-SignedHeaders")).To(Equal("host;x-k8s-aws-id"))
+			Expect(q.Get("X-Amz-Security-Token")).To(Equal("test-session-token"))
+			Expect(q.Get("X-Amz-Signature")).To(HaveLen(64))
+		})
+	})
+
+	Describe("#MintToken of a provider with a http token provider", func() {
+		var (
+			kc       Controller
+			provider Provider
+			config   *rest.Config
+			expiry   time.Time
+		)
+
+		BeforeEach(func() {
+			expiry = time.Now().Add(time.Hour)
+			kc = NewController()
+			provider = Provider{
+				Name: "test-account",
+				Host: "https://test-host",
+				TokenProvider: &ProviderTokenProvider{
+					Type: TokenProviderHTTP,
+					URL:  fakeServer.URL() + "/tokens",
+				},
+			}
+			config = &rest.Config{
+				Host:        "https://test-host",
+				BearerToken: "bootstrap-token",
+			}
+		})
+
+		JustBeforeEach(func() {
+			fakeServer.AppendHandlers(ghttp.CombineHandlers(
+				ghttp.VerifyRequest(http.MethodGet, "/tokens", "account=test-account"),
+				ghttp.RespondWith(http.StatusOK, fmt.Sprintf(`{"token": "fetched-token", "expiresAt": "%s"}`,
+					expiry.UTC().Format(time.RFC3339Nano))),
+			))
+
+			err = kc.MintToken(provider, config)
+		})
+
+		When("the token service fails", func() {
+			BeforeEach(func() {
+				fakeServer.AppendHandlers(ghttp.RespondWith(http.StatusInternalServerError, nil))
+			})
+
+			It("returns an error", func() {
+				Expect(err).ToNot(BeNil())
+				Expect(err.Error()).To(Equal("error fetching token of http token provider: error getting token: 500 Internal Server Error"))
+			})
+		})
+
+		When("it succeeds", func() {
+			It("swaps the bearer token for the fetched token", func() {
+				Expect(err).To(BeNil())
+				Expect(config.BearerToken).To(Equal("fetched-token"))
+			})
+		})
+
+		When("the token was already fetched", func() {
+			JustBeforeEach(func() {
+				config = &rest.Config{Host: "https://test-host", BearerToken: "bootstrap-token"}
+				err = kc.MintToken(provider, config)
+			})
+
+			It("reuses the fetched token", func() {
+				Expect(err).To(BeNil())
+				Expect(config.BearerToken).To(Equal("fetched-token"))
+				Expect(fakeServer.ReceivedRequests()).To(HaveLen(1))
+			})
+		})
+
+		When("the fetched token is about to expire", func() {
+			BeforeEach(func() {
+				expiry = time.Now()
+			})
+
+			JustBeforeEach(func() {
+				fakeServer.AppendHandlers(ghttp.RespondWith(http.StatusOK, `{"token": "refreshed-token"}`))
+				err = kc.MintToken(provider, config)
+			})
+
+			It("fetches a new token", func() {
+				Expect(err).To(BeNil())
+				Expect(config.BearerToken).To(Equal("refreshed-token"))
+				Expect(fakeServer.ReceivedRequests()).To(HaveLen(2))
+			})
+		})
+
+		When("the provider mints tokens", func() {
+			var clusterServer *ghttp.Server
+
+			BeforeEach(func() {
+				clusterServer = ghttp.NewServer()
+				clusterServer.AppendHandlers(ghttp.CombineHandlers(
+					ghttp.VerifyRequest(http.MethodPost, "/api/v1/namespaces/spinnaker/serviceaccounts/deployer/token"),
+					ghttp.VerifyHeaderKV("Authorization", "Bearer fetched-token"),
+					ghttp.RespondWith(http.StatusCreated, fmt.Sprintf(`{
+						"kind": "TokenRequest",
+						"apiVersion": "authentication.k8s.io/v1",
+						"status": {
+							"token": "minted-token",
+							"expirationTimestamp": "%s"
+						}
+					}`, time.Now().Add(time.Hour).UTC().Format(time.RFC3339)), jsonHeader),
+				))
+
+				provider.Host = clusterServer.URL()
+				provider.TokenServiceAccount = "spinnaker/deployer"
+				config.Host = clusterServer.URL()
+			})
+
+			AfterEach(func() {
+				clusterServer.Close()
+			})
+
+			It("mints them with the fetched token", func() {
+				Expect(err).To(BeNil())
+				Expect(clusterServer.ReceivedRequests()).To(HaveLen(1))
+				Expect(config.BearerToken).To(Equal("minted-token"))
+			})
+		})
+	})
+})
@@ -0,0 +1,192 @@
+package kubernetes_test
+
+import (
+	"fmt"
+	"net/http"
+	"time"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"github.com/onsi/gomega/ghttp"
+	"k8s.io/client-go/rest"
+
+	. "github.com/billiford/go-clouddriver/pkg/kubernetes"
+)
+
+// jsonHeader is the content type of API server responses, which client-go
+// decodes them by.
+var jsonHeader = http.Header{"Content-Type": []string{"application/json"}}
+
+var _ = Describe("MintToken", func() {
+	var (
+		fakeServer *ghttp.Server
+		kc         Controller
+		provider   Provider
+		config     *rest.Config
+		err        error
+	)
+
+	BeforeEach(func() {
+		fakeServer = ghttp.NewServer()
+		fakeServer.AppendHandlers(ghttp.CombineHandlers(
+			ghttp.VerifyRequest(http.MethodPost, "/api/v1/namespaces/spinnaker/serviceaccounts/deployer/token"),
+			ghttp.VerifyHeaderKV("Authorization", "Bearer bootstrap-token"),
+			ghttp.RespondWith(http.StatusCreated, fmt.Sprintf(`{
+				"kind": "TokenRequest",
+				"apiVersion": "authentication.k8s.io/v1",
+				"status": {
+					"token": "minted-token",
+					"expirationTimestamp": "%s"
+				}
+			}`, time.Now().Add(time.Hour).UTC().Format(time.RFC3339)), jsonHeader),
+		))
+
+		kc = NewController()
+		provider = Provider{
+			Name:                "test-account",
+			Host:                fakeServer.URL(),
+			TokenServiceAccount: "spinnaker/deployer",
+		}
+		config = &rest.Config{
+			Host:        fakeServer.URL(),
+			BearerToken: "bootstrap-token",
+		}
+	})
+
+	AfterEach(func() {
+		fakeServer.Close()
+	})
+
+	JustBeforeEach(func() {
+		err = kc.MintToken(provider, config)
+	})
+
+	When("the provider has no token service account", func() {
+		BeforeEach(func() {
+			provider.TokenServiceAccount = ""
+		})
+
+		It("leaves the config as it is", func() {
+			Expect(err).To(BeNil())
+			Expect(config.BearerToken).To(Equal("bootstrap-token"))
+			Expect(fakeServer.ReceivedRequests()).To(HaveLen(0))
+		})
+	})
+
+	When("the provider has a bearer token", func() {
+		BeforeEach(func() {
+			provider.BearerToken = "stored-token"
+			provider.TokenServiceAccount = ""
+		})
+
+		It("uses it", func() {
+			Expect(err).To(BeNil())
+			Expect(config.BearerToken).To(Equal("stored-token"))
+		})
+	})
+
+	When("the provider has a bearer token and a token service account", func() {
+		BeforeEach(func() {
+			provider.BearerToken = "stored-token"
+			fakeServer.SetHandler(0, ghttp.CombineHandlers(
+				ghttp.VerifyHeaderKV("Authorization", "Bearer stored-token"),
+				ghttp.RespondWith(http.StatusCreated, fmt.Sprintf(`{
+					"kind": "TokenRequest",
+					"apiVersion": "authentication.k8s.io/v1",
+					"status": {
+						"token": "minted-token",
+						"expirationTimestamp": "%s"
+					}
+				}`, time.Now().Add(time.Hour).UTC().Format(time.RFC3339)), jsonHeader),
+			))
+		})
+
+		It("mints the token with it", func() {
+			Expect(err).To(BeNil())
+			Expect(config.BearerToken).To(Equal("minted-token"))
+			Expect(fakeServer.ReceivedRequests()).To(HaveLen(1))
+		})
+	})
+
+	When("the token service account is invalid", func() {
+		BeforeEach(func() {
+			provider.TokenServiceAccount = "deployer"
+		})
+
+		It("returns an error", func() {
+			Expect(err).ToNot(BeNil())
+			Expect(err.Error()).To(Equal(`token service account "deployer" must be namespace/name`))
+		})
+	})
+
+	When("the token request fails", func() {
+		BeforeEach(func() {
+			fakeServer.SetHandler(0, ghttp.RespondWith(http.StatusForbidden, "{}"))
+		})
+
+		It("returns an error", func() {
+			Expect(err).ToNot(BeNil())
+			Expect(err.Error()).To(HavePrefix("error minting token of service account spinnaker/deployer"))
+			Expect(config.BearerToken).To(Equal("bootstrap-token"))
+		})
+	})
+
+	When("it succeeds", func() {
+		It("swaps the bearer token for the minted token", func() {
+			Expect(err).To(BeNil())
+			Expect(config.BearerToken).To(Equal("minted-token"))
+			Expect(fakeServer.ReceivedRequests()).To(HaveLen(1))
+		})
+	})
+
+	When("the token was already minted", func() {
+		JustBeforeEach(func() {
+			config = &rest.Config{
+				Host:        fakeServer.URL(),
+				BearerToken: "bootstrap-token",
+			}
+			err = kc.MintToken(provider, config)
+		})
+
+		It("reuses the minted token", func() {
+			Expect(err).To(BeNil())
+			Expect(config.BearerToken).To(Equal("minted-token"))
+			Expect(fakeServer.ReceivedRequests()).To(HaveLen(1))
+		})
+	})
+
+	When("the minted token was evicted as idle", func() {
+		BeforeEach(func() {
+			fakeServer.AppendHandlers(ghttp.RespondWith(http.StatusCreated, fmt.Sprintf(`{
+				"kind": "TokenRequest",
+				"apiVersion": "authentication.k8s.io/v1",
+				"status": {
+					"token": "minted-token-2",
+					"expirationTimestamp": "%s"
+				}
+			}`, time.Now().Add(time.Hour).UTC().Format(time.RFC3339)), jsonHeader))
+		})
+
+		JustBeforeEach(func() {
+			Expect(kc.EvictIdle(0)).To(Equal(1))
+
+			config = &rest.Config{
+				Host:        fakeServer.URL(),
+				BearerToken: "bootstrap-token",
+			}
+			err = kc.MintToken(provider, config)
+		})
+
+		It("mints a new token", func() {
+			Expect(err).To(BeNil())
+			Expect(config.BearerToken).To(Equal("minted-token-2"))
+			Expect(fakeServer.ReceivedRequests()).To(HaveLen(2))
+		})
+	})
+
+	When("the minted token is not idle", func() {
+		It("is not evicted", func() {
+			Expect(kc.EvictIdle(time.Hour)).To(BeZero())
+		})
+	})
+})
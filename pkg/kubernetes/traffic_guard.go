@@ -0,0 +1,143 @@
+package kubernetes
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"strings"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+var defaultTrafficGuardConfigPath = "/opt/spinnaker/kubernetes/traffic-guards.json"
+
+// TrafficGuard guards the clusters of an account from losing the last
+// healthy server group serving their services. An empty location guards
+// every namespace, and an empty cluster every cluster.
+type TrafficGuard struct {
+	Account  string `json:"account"`
+	Location string `json:"location,omitempty"`
+	// Cluster is the moniker of the cluster, such as "replicaSet my-app".
+	Cluster string `json:"cluster,omitempty"`
+}
+
+// TrafficGuardConfig lists the traffic guards, like the traffic guards of
+// a Spinnaker application.
+//
+//	{
+//	  "guards": [
+//	    {
+//	      "account": "prod-account",
+//	      "location": "my-namespace",
+//	      "cluster": "replicaSet my-app"
+//	    }
+//	  ]
+//	}
+type TrafficGuardConfig struct {
+	Guards []TrafficGuard `json:"guards"`
+}
+
+// NewDefaultTrafficGuardConfig reads the traffic guards from /opt/spinnaker/kubernetes/traffic-guards.json.
+// The config is optional, so if the file does not exist no clusters are guarded.
+func NewDefaultTrafficGuardConfig() (TrafficGuardConfig, error) {
+	if _, err := os.Stat(defaultTrafficGuardConfigPath); os.IsNotExist(err) {
+		return TrafficGuardConfig{}, nil
+	}
+
+	return NewTrafficGuardConfig(defaultTrafficGuardConfigPath)
+}
+
+// NewTrafficGuardConfig reads the traffic guards from a JSON file.
+func NewTrafficGuardConfig(path string) (TrafficGuardConfig, error) {
+	tgc := TrafficGuardConfig{}
+
+	b, err := ioutil.ReadFile(path)
+	if err != nil {
+		return tgc, err
+	}
+
+	err = json.Unmarshal(b, &tgc)
+	if err != nil {
+		return tgc, err
+	}
+
+	return tgc, nil
+}
+
+// Guarded returns true if a cluster in a namespace of an account is guarded.
+// Clusters are matched ignoring case, as Spinnaker has annotated them both
+// as "replicaSet my-app" and "replicaset my-app".
+func (tgc TrafficGuardConfig) Guarded(account, namespace, cluster string) bool {
+	for _, g := range tgc.Guards {
+		if g.Account != account {
+			continue
+		}
+
+		if g.Location != "" && g.Location != namespace {
+			continue
+		}
+
+		if g.Cluster != "" && !strings.EqualFold(g.Cluster, cluster) {
+			continue
+		}
+
+		return true
+	}
+
+	return false
+}
+
+// UnservedServices returns the names of the services selecting the pods of
+// target that no other healthy server group would serve if target were
+// disabled. A server group is healthy while it has ready replicas.
+func UnservedServices(target unstructured.Unstructured, services, serverGroups []unstructured.Unstructured) []string {
+	unserved := []string{}
+	labels := templateLabels(target)
+
+	for _, service := range services {
+		selector, _, _ := unstructured.NestedStringMap(service.Object, "spec", "selector")
+		if !selects(selector, labels) {
+			continue
+		}
+
+		served := false
+
+		for _, sg := range serverGroups {
+			if sg.GetKind() == target.GetKind() && sg.GetName() == target.GetName() {
+				continue
+			}
+
+			ready, _, _ := unstructured.NestedInt64(sg.Object, "status", "readyReplicas")
+			if ready > 0 && selects(selector, templateLabels(sg)) {
+				served = true
+				break
+			}
+		}
+
+		if !served {
+			unserved = append(unserved, service.GetName())
+		}
+	}
+
+	return unserved
+}
+
+func templateLabels(u unstructured.Unstructured) map[string]string {
+	labels, _, _ := unstructured.NestedStringMap(u.Object, "spec", "template", "metadata", "labels")
+	return labels
+}
+
+// selects returns true if a non-empty selector matches labels.
+func selects(selector, labels map[string]string) bool {
+	if len(selector) == 0 {
+		return false
+	}
+
+	for k, v := range selector {
+		if labels[k] != v {
+			return false
+		}
+	}
+
+	return true
+}
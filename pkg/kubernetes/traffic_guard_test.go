@@ -0,0 +1,131 @@
+package kubernetes_test
+
+import (
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+
+	. "github.com/billiford/go-clouddriver/pkg/kubernetes"
+)
+
+var _ = Describe("TrafficGuard", func() {
+	var (
+		tgc TrafficGuardConfig
+		err error
+	)
+
+	Describe("#NewTrafficGuardConfig", func() {
+		When("the file does not exist", func() {
+			BeforeEach(func() {
+				tgc, err = NewTrafficGuardConfig("test/missing.json")
+			})
+
+			It("returns an error", func() {
+				Expect(err).ToNot(BeNil())
+			})
+		})
+
+		When("it succeeds", func() {
+			BeforeEach(func() {
+				tgc, err = NewTrafficGuardConfig("test/traffic-guards.json")
+			})
+
+			It("returns the guards", func() {
+				Expect(err).To(BeNil())
+				Expect(tgc.Guards).To(HaveLen(2))
+			})
+		})
+	})
+
+	Describe("#Guarded", func() {
+		BeforeEach(func() {
+			tgc, err = NewTrafficGuardConfig("test/traffic-guards.json")
+			Expect(err).To(BeNil())
+		})
+
+		It("matches the account, location and cluster", func() {
+			Expect(tgc.Guarded("prod-account", "my-namespace", "replicaset my-app")).To(BeTrue())
+			Expect(tgc.Guarded("prod-account", "other-namespace", "replicaSet my-app")).To(BeFalse())
+			Expect(tgc.Guarded("prod-account", "my-namespace", "replicaSet other-app")).To(BeFalse())
+			Expect(tgc.Guarded("dev-account", "my-namespace", "replicaSet my-app")).To(BeFalse())
+		})
+
+		It("guards every cluster of an account without a location or cluster", func() {
+			Expect(tgc.Guarded("critical-account", "any-namespace", "deployment any-app")).To(BeTrue())
+		})
+	})
+
+	Describe("#UnservedServices", func() {
+		var (
+			target       unstructured.Unstructured
+			services     []unstructured.Unstructured
+			serverGroups []unstructured.Unstructured
+			unserved     []string
+		)
+
+		BeforeEach(func() {
+			target = newServerGroup("my-app-v002", 2)
+			services = []unstructured.Unstructured{
+				newService("my-app", map[string]interface{}{"app": "my-app"}),
+				newService("other-app", map[string]interface{}{"app": "other-app"}),
+			}
+			serverGroups = []unstructured.Unstructured{target, newServerGroup("my-app-v001", 0)}
+		})
+
+		JustBeforeEach(func() {
+			unserved = UnservedServices(target, services, serverGroups)
+		})
+
+		When("the target is the only healthy server group of a service", func() {
+			It("returns the service", func() {
+				Expect(unserved).To(Equal([]string{"my-app"}))
+			})
+		})
+
+		When("another healthy server group serves the service", func() {
+			BeforeEach(func() {
+				serverGroups = append(serverGroups, newServerGroup("my-app-v003", 1))
+			})
+
+			It("returns no services", func() {
+				Expect(unserved).To(BeEmpty())
+			})
+		})
+	})
+})
+
+func newServerGroup(name string, readyReplicas int64) unstructured.Unstructured {
+	return unstructured.Unstructured{Object: map[string]interface{}{
+		"kind": "ReplicaSet",
+		"metadata": map[string]interface{}{
+			"name":      name,
+			"namespace": "my-namespace",
+		},
+		"spec": map[string]interface{}{
+			"template": map[string]interface{}{
+				"metadata": map[string]interface{}{
+					"labels": map[string]interface{}{
+						"app":     "my-app",
+						"version": name,
+					},
+				},
+			},
+		},
+		"status": map[string]interface{}{
+			"readyReplicas": readyReplicas,
+		},
+	}}
+}
+
+func newService(name string, selector map[string]interface{}) unstructured.Unstructured {
+	return unstructured.Unstructured{Object: map[string]interface{}{
+		"kind": "Service",
+		"metadata": map[string]interface{}{
+			"name":      name,
+			"namespace": "my-namespace",
+		},
+		"spec": map[string]interface{}{
+			"selector": selector,
+		},
+	}}
+}
@@ -0,0 +1,241 @@
+package kubernetes
+
+import (
+	"crypto/sha256"
+	"crypto/tls"
+	"database/sql/driver"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"sync"
+	"time"
+
+	utilnet "k8s.io/apimachinery/pkg/util/net"
+	"k8s.io/client-go/rest"
+)
+
+// Defaults of the connections to a cluster of a provider with a transport,
+// the same as client-go's.
+const (
+	defaultMaxIdleConnsPerHost = 25
+	defaultIdleConnTimeout     = 90 * time.Second
+	defaultTLSHandshakeTimeout = 10 * time.Second
+	defaultDialTimeout         = 30 * time.Second
+	defaultKeepAlive           = 30 * time.Second
+)
+
+// ProviderTransport tunes the connections made to the clusters of a
+// provider, such as for a cluster behind a load balancer that drops idle
+// connections. Durations are such as "30s", and fields left empty keep the
+// defaults of client-go.
+type ProviderTransport struct {
+	// MaxIdleConnsPerHost is how many idle connections are kept open to
+	// each cluster.
+	MaxIdleConnsPerHost int `json:"maxIdleConnsPerHost,omitempty"`
+	// IdleConnTimeout closes connections idle for longer. Set it below the
+	// idle timeout of a load balancer in front of the cluster, so requests
+	// are not made on connections it dropped.
+	IdleConnTimeout string `json:"idleConnTimeout,omitempty"`
+	// TLSHandshakeTimeout is how long to wait for a TLS handshake.
+	TLSHandshakeTimeout string `json:"tlsHandshakeTimeout,omitempty"`
+	// KeepAlive is the interval of TCP keep-alives on idle connections.
+	KeepAlive string `json:"keepAlive,omitempty"`
+	// DisableHTTP2 only makes HTTP/1.1 requests to the cluster.
+	DisableHTTP2 bool `json:"disableHTTP2,omitempty"`
+}
+
+// Validate returns an error if a setting of the transport is negative or a
+// duration cannot be parsed.
+func (pt ProviderTransport) Validate() error {
+	if pt.MaxIdleConnsPerHost < 0 {
+		return errors.New("transport maxIdleConnsPerHost must not be negative")
+	}
+
+	durations := map[string]string{
+		"idleConnTimeout":     pt.IdleConnTimeout,
+		"tlsHandshakeTimeout": pt.TLSHandshakeTimeout,
+		"keepAlive":           pt.KeepAlive,
+	}
+
+	for name, s := range durations {
+		if _, err := parseTransportDuration(s, 0); err != nil {
+			return fmt.Errorf("transport %s %q must be a duration, such as 30s", name, s)
+		}
+	}
+
+	return nil
+}
+
+// Value implements driver.Valuer.
+func (pt ProviderTransport) Value() (driver.Value, error) {
+	b, err := json.Marshal(pt)
+	if err != nil {
+		return nil, err
+	}
+
+	return string(b), nil
+}
+
+// Scan implements sql.Scanner.
+func (pt *ProviderTransport) Scan(src interface{}) error {
+	var b []byte
+
+	switch v := src.(type) {
+	case nil:
+		*pt = ProviderTransport{}
+		return nil
+	case string:
+		b = []byte(v)
+	case []byte:
+		b = v
+	default:
+		return fmt.Errorf("cannot scan %T into provider transport", src)
+	}
+
+	if len(b) == 0 {
+		*pt = ProviderTransport{}
+		return nil
+	}
+
+	return json.Unmarshal(b, pt)
+}
+
+func parseTransportDuration(s string, def time.Duration) (time.Duration, error) {
+	if s == "" {
+		return def, nil
+	}
+
+	d, err := time.ParseDuration(s)
+	if err != nil || d < 0 {
+		return 0, fmt.Errorf("invalid duration %q", s)
+	}
+
+	return d, nil
+}
+
+// transports are the transports of providers with a ProviderTransport, one
+// for each cluster and transport, so connections are reused across the
+// clients of a provider like client-go reuses its own transports.
+type transports struct {
+	mux        sync.Mutex
+	transports map[string]*cachedTransport
+}
+
+type cachedTransport struct {
+	t    *http.Transport
+	used time.Time
+}
+
+func newTransports() *transports {
+	return &transports{transports: map[string]*cachedTransport{}}
+}
+
+// configure makes clients created with config use a transport tuned by pt.
+// Configs are left unchanged if pt is nil or config already has a
+// transport, so they can be configured more than once.
+func (ts *transports) configure(pt *ProviderTransport, config *rest.Config) error {
+	if pt == nil || config.Transport != nil {
+		return nil
+	}
+
+	b, err := json.Marshal(pt)
+	if err != nil {
+		return err
+	}
+
+	// Transports hold TLS config, so a cluster's transport is not shared with
+	// one that has other CA data or TLS settings.
+	tlsKey := fmt.Sprintf("%x", sha256.Sum256([]byte(fmt.Sprintf("%#v", config.TLSClientConfig))))
+	key := config.Host + " " + tlsKey + " " + string(b)
+	now := time.Now()
+
+	ts.mux.Lock()
+	defer ts.mux.Unlock()
+
+	ct, ok := ts.transports[key]
+	if !ok {
+		t, err := newTransport(*pt, config)
+		if err != nil {
+			return err
+		}
+
+		ct = &cachedTransport{t: t}
+		ts.transports[key] = ct
+	}
+
+	ct.used = now
+	config.Transport = ct.t
+	// client-go does not allow TLS options with a transport, as the
+	// transport has them.
+	config.TLSClientConfig = rest.TLSClientConfig{}
+
+	return nil
+}
+
+func newTransport(pt ProviderTransport, config *rest.Config) (*http.Transport, error) {
+	idleConnTimeout, err := parseTransportDuration(pt.IdleConnTimeout, defaultIdleConnTimeout)
+	if err != nil {
+		return nil, err
+	}
+
+	tlsHandshakeTimeout, err := parseTransportDuration(pt.TLSHandshakeTimeout, defaultTLSHandshakeTimeout)
+	if err != nil {
+		return nil, err
+	}
+
+	keepAlive, err := parseTransportDuration(pt.KeepAlive, defaultKeepAlive)
+	if err != nil {
+		return nil, err
+	}
+
+	maxIdleConnsPerHost := pt.MaxIdleConnsPerHost
+	if maxIdleConnsPerHost == 0 {
+		maxIdleConnsPerHost = defaultMaxIdleConnsPerHost
+	}
+
+	tlsConfig, err := rest.TLSConfigFor(config)
+	if err != nil {
+		return nil, err
+	}
+
+	t := &http.Transport{
+		Proxy:               http.ProxyFromEnvironment,
+		TLSClientConfig:     tlsConfig,
+		TLSHandshakeTimeout: tlsHandshakeTimeout,
+		MaxIdleConnsPerHost: maxIdleConnsPerHost,
+		IdleConnTimeout:     idleConnTimeout,
+		DialContext: (&net.Dialer{
+			Timeout:   defaultDialTimeout,
+			KeepAlive: keepAlive,
+		}).DialContext,
+	}
+
+	if pt.DisableHTTP2 {
+		// A non-nil TLSNextProto keeps the transport from upgrading to HTTP/2.
+		t.TLSNextProto = map[string]func(string, *tls.Conn) http.RoundTripper{}
+		return utilnet.SetOldTransportDefaults(t), nil
+	}
+
+	return utilnet.SetTransportDefaults(t), nil
+}
+
+// evictIdle closes and forgets the transports last used before t, returning
+// how many were forgotten.
+func (ts *transports) evictIdle(t time.Time) int {
+	ts.mux.Lock()
+	defer ts.mux.Unlock()
+
+	evicted := 0
+
+	for key, ct := range ts.transports {
+		if ct.used.Before(t) {
+			ct.t.CloseIdleConnections()
+			delete(ts.transports, key)
+			evicted++
+		}
+	}
+
+	return evicted
+}
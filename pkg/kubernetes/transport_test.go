@@ -0,0 +1,150 @@
+package kubernetes_test
+
+import (
+	"net/http"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"github.com/onsi/gomega/ghttp"
+	"k8s.io/client-go/rest"
+
+	. "github.com/billiford/go-clouddriver/pkg/kubernetes"
+)
+
+var _ = Describe("ProviderTransport", func() {
+	Describe("#Validate", func() {
+		It("accepts durations and no settings", func() {
+			Expect(ProviderTransport{}.Validate()).To(Succeed())
+			Expect(ProviderTransport{
+				MaxIdleConnsPerHost: 10,
+				IdleConnTimeout:     "30s",
+				TLSHandshakeTimeout: "5s",
+				KeepAlive:           "15s",
+				DisableHTTP2:        true,
+			}.Validate()).To(Succeed())
+		})
+
+		It("rejects negative settings and bad durations", func() {
+			Expect(ProviderTransport{MaxIdleConnsPerHost: -1}.Validate()).ToNot(Succeed())
+			Expect(ProviderTransport{IdleConnTimeout: "thirty seconds"}.Validate()).
+				To(MatchError(`transport idleConnTimeout "thirty seconds" must be a duration, such as 30s`))
+			Expect(ProviderTransport{KeepAlive: "-1s"}.Validate()).ToNot(Succeed())
+		})
+	})
+
+	It("is stored as JSON", func() {
+		pt := ProviderTransport{MaxIdleConnsPerHost: 10, IdleConnTimeout: "30s"}
+		v, err := pt.Value()
+		Expect(err).To(BeNil())
+
+		scanned := ProviderTransport{}
+		Expect(scanned.Scan(v)).To(Succeed())
+		Expect(scanned).To(Equal(pt))
+	})
+
+	Describe("clients of a provider with a transport", func() {
+		var (
+			fakeServer *ghttp.Server
+			kc         Controller
+			provider   Provider
+			config     *rest.Config
+			err        error
+		)
+
+		BeforeEach(func() {
+			fakeServer = ghttp.NewServer()
+			fakeServer.SetAllowUnhandledRequests(true)
+			fakeServer.SetUnhandledRequestStatusCode(http.StatusOK)
+
+			kc = NewController()
+			provider = Provider{
+				Name: "test-account",
+				Host: fakeServer.URL(),
+				Transport: &ProviderTransport{
+					IdleConnTimeout: "30s",
+					DisableHTTP2:    true,
+				},
+			}
+			config = &rest.Config{
+				Host:        fakeServer.URL(),
+				BearerToken: "test-token",
+				TLSClientConfig: rest.TLSClientConfig{
+					ServerName: "test-server",
+				},
+			}
+		})
+
+		AfterEach(func() {
+			fakeServer.Close()
+		})
+
+		JustBeforeEach(func() {
+			err = kc.MintToken(provider, config)
+		})
+
+		It("use the transport", func() {
+			Expect(err).To(BeNil())
+			Expect(config.TLSClientConfig).To(Equal(rest.TLSClientConfig{}))
+
+			t, ok := config.Transport.(*http.Transport)
+			Expect(ok).To(BeTrue())
+			Expect(t.IdleConnTimeout.String()).To(Equal("30s"))
+			Expect(t.TLSClientConfig.ServerName).To(Equal("test-server"))
+			Expect(t.TLSNextProto).To(BeEmpty())
+			Expect(t.TLSNextProto).ToNot(BeNil())
+
+			rt, err := rest.TransportFor(config)
+			Expect(err).To(BeNil())
+
+			res, err := (&http.Client{Transport: rt}).Get(fakeServer.URL() + "/api/v1/namespaces")
+			Expect(err).To(BeNil())
+			res.Body.Close()
+			Expect(fakeServer.ReceivedRequests()[0].Header.Get("Authorization")).To(Equal("Bearer test-token"))
+		})
+
+		When("another client is created for the cluster", func() {
+			var other *rest.Config
+
+			JustBeforeEach(func() {
+				other = &rest.Config{
+					Host: fakeServer.URL(),
+					TLSClientConfig: rest.TLSClientConfig{
+						ServerName: "test-server",
+					},
+				}
+				Expect(kc.MintToken(provider, other)).To(Succeed())
+			})
+
+			It("shares the transport", func() {
+				Expect(other.Transport).To(BeIdenticalTo(config.Transport))
+			})
+
+			It("evicts the transport when idle", func() {
+				Expect(kc.EvictIdle(0)).To(Equal(1))
+			})
+		})
+
+		When("the provider has no transport", func() {
+			BeforeEach(func() {
+				provider.Transport = nil
+			})
+
+			It("leaves the config as it is", func() {
+				Expect(err).To(BeNil())
+				Expect(config.Transport).To(BeNil())
+				Expect(config.TLSClientConfig.ServerName).To(Equal("test-server"))
+			})
+		})
+
+		When("a duration of the transport is invalid", func() {
+			BeforeEach(func() {
+				provider.Transport.TLSHandshakeTimeout = "soon"
+			})
+
+			It("returns an error", func() {
+				Expect(err).ToNot(BeNil())
+				Expect(err.Error()).To(HavePrefix("error configuring transport"))
+			})
+		})
+	})
+})
@@ -30,6 +30,14 @@ func (c *controller) ToUnstructured(manifest map[string]interface{}) (*unstructu
 	}, nil
 }
 
+// stripManagedFields removes the managed fields of listed objects. They are only
+// used by server-side apply and can make up half of an object's size.
+func stripManagedFields(list *unstructured.UnstructuredList) {
+	for i := range list.Items {
+		unstructured.RemoveNestedField(list.Items[i].Object, "metadata", "managedFields")
+	}
+}
+
 func SetDefaultNamespaceIfScopedAndNoneSet(u *unstructured.Unstructured, helper *resource.Helper) {
 	namespace := u.GetNamespace()
 	if helper.NamespaceScoped && namespace == "" {
@@ -0,0 +1,355 @@
+// Package verify verifies the cosign signatures or in-toto attestations of the
+// container images of manifests before they are deployed, so accounts can
+// only run images built by trusted pipelines.
+package verify
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"os/exec"
+	"strings"
+
+	"github.com/billiford/go-clouddriver/pkg/kubernetes/schedule"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+const (
+	defaultConfigPath = `/opt/spinnaker/kubernetes/image-verification.json`
+)
+
+// Policy is how the images of an account are verified.
+type Policy struct {
+	// Enabled verifies the images of manifests deployed to the account.
+	Enabled *bool `json:"enabled,omitempty"`
+	// Key is the path, URL or KMS URI of the public key images are signed
+	// with. If empty, images are verified keyless, against the identity
+	// and OIDC issuer of their certificate.
+	Key                   string `json:"key,omitempty"`
+	CertificateIdentity   string `json:"certificateIdentity,omitempty"`
+	CertificateOIDCIssuer string `json:"certificateOIDCIssuer,omitempty"`
+	// AttestationType, if set, verifies in-toto attestations of the type,
+	// such as slsaprovenance, instead of signatures.
+	AttestationType string `json:"attestationType,omitempty"`
+	// SkipImages are prefixes of images not verified, such as
+	// "registry.k8s.io/".
+	SkipImages []string `json:"skipImages,omitempty"`
+}
+
+// Config is the default policy and the policies of accounts, which replace
+// the default.
+type Config struct {
+	Policy
+	Accounts map[string]Policy `json:"accounts,omitempty"`
+}
+
+// NewDefaultConfig reads the config from /opt/spinnaker/kubernetes/image-verification.json.
+// The config is optional, so if the file does not exist images are not verified.
+func NewDefaultConfig() (Config, error) {
+	if _, err := os.Stat(defaultConfigPath); os.IsNotExist(err) {
+		return Config{}, nil
+	}
+
+	return NewConfig(defaultConfigPath)
+}
+
+// NewConfig reads the config from a JSON file.
+func NewConfig(path string) (Config, error) {
+	c := Config{}
+
+	b, err := ioutil.ReadFile(path)
+	if err != nil {
+		return c, err
+	}
+
+	err = json.Unmarshal(b, &c)
+	if err != nil {
+		return c, err
+	}
+
+	return c, nil
+}
+
+// PolicyFor returns the policy of an account, or false if its images are
+// not verified.
+func (c Config) PolicyFor(account string) (Policy, bool) {
+	p, ok := c.Accounts[account]
+	if !ok {
+		p = c.Policy
+	}
+
+	return p, p.Enabled != nil && *p.Enabled
+}
+
+// Enabled returns true if the images of any account are verified.
+func (c Config) Enabled() bool {
+	if _, ok := c.PolicyFor(""); ok {
+		return true
+	}
+
+	for account := range c.Accounts {
+		if _, ok := c.PolicyFor(account); ok {
+			return true
+		}
+	}
+
+	return false
+}
+
+// Verifier verifies the images of manifests deployed to an account,
+// returning an error for the first image that fails verification. Verified
+// images are pinned to the digest they were verified at, replacing their tag,
+// so the cluster runs what was verified even if the tag is pushed again.
+//
+//go:generate counterfeiter . Verifier
+type Verifier interface {
+	Verify(ctx context.Context, account string, manifests []map[string]interface{}) error
+}
+
+// NewCosignVerifier returns a Verifier of the policies of config using the
+// cosign binary at path, see https://github.com/sigstore/cosign.
+func NewCosignVerifier(path string, config Config) Verifier {
+	return &cosignVerifier{
+		path:   path,
+		config: config,
+	}
+}
+
+type cosignVerifier struct {
+	path   string
+	config Config
+}
+
+func (v *cosignVerifier) Verify(ctx context.Context, account string, manifests []map[string]interface{}) error {
+	p, ok := v.config.PolicyFor(account)
+	if !ok {
+		return nil
+	}
+
+	images, err := Images(manifests)
+	if err != nil {
+		return err
+	}
+
+	pinned := map[string]string{}
+
+	for _, image := range images {
+		if skip(p, image) {
+			continue
+		}
+
+		digest, err := v.verify(ctx, p, image)
+		if err != nil {
+			return fmt.Errorf("error verifying image %s: %w", image, err)
+		}
+
+		pinned[image] = pin(image, digest)
+	}
+
+	setImages(manifests, pinned)
+
+	return nil
+}
+
+func skip(p Policy, image string) bool {
+	for _, prefix := range p.SkipImages {
+		if strings.HasPrefix(image, prefix) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// verify verifies an image with cosign, returning the digest it verified.
+func (v *cosignVerifier) verify(ctx context.Context, p Policy, image string) (string, error) {
+	args := []string{"verify"}
+	if p.AttestationType != "" {
+		args = []string{"verify-attestation", "--type", p.AttestationType}
+	}
+
+	if p.Key != "" {
+		args = append(args, "--key", p.Key)
+	} else {
+		args = append(args, "--certificate-identity", p.CertificateIdentity,
+			"--certificate-oidc-issuer", p.CertificateOIDCIssuer)
+	}
+
+	args = append(args, "--output", "json", image)
+
+	var stdout, stderr bytes.Buffer
+
+	cmd := exec.CommandContext(ctx, v.path, args...)
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+
+	err := cmd.Run()
+	if err != nil {
+		return "", fmt.Errorf("%w: %s", err, strings.TrimSpace(stderr.String()))
+	}
+
+	return verifiedDigest(stdout.Bytes())
+}
+
+// verifiedDigest returns the digest of the first verified signature or attestation
+// in the JSON output of cosign. Signatures are output as an array of simple
+// signing payloads, and attestations as one DSSE envelope per line whose
+// payload is an in-toto statement about the image.
+func verifiedDigest(output []byte) (string, error) {
+	d := json.NewDecoder(bytes.NewReader(output))
+
+	for d.More() {
+		var raw json.RawMessage
+
+		err := d.Decode(&raw)
+		if err != nil {
+			return "", fmt.Errorf("error decoding output of cosign: %w", err)
+		}
+
+		if bytes.HasPrefix(bytes.TrimSpace(raw), []byte("[")) {
+			signatures := []struct {
+				Critical struct {
+					Image struct {
+						DockerManifestDigest string `json:"docker-manifest-digest"`
+					} `json:"image"`
+				} `json:"critical"`
+			}{}
+
+			err = json.Unmarshal(raw, &signatures)
+			if err != nil {
+				return "", fmt.Errorf("error decoding output of cosign: %w", err)
+			}
+
+			for _, s := range signatures {
+				if s.Critical.Image.DockerManifestDigest != "" {
+					return s.Critical.Image.DockerManifestDigest, nil
+				}
+			}
+
+			continue
+		}
+
+		envelope := struct {
+			Payload string `json:"payload"`
+		}{}
+
+		err = json.Unmarshal(raw, &envelope)
+		if err != nil {
+			return "", fmt.Errorf("error decoding output of cosign: %w", err)
+		}
+
+		payload, err := base64.StdEncoding.DecodeString(envelope.Payload)
+		if err != nil {
+			return "", fmt.Errorf("error decoding attestation: %w", err)
+		}
+
+		statement := struct {
+			Subject []struct {
+				Digest map[string]string `json:"digest"`
+			} `json:"subject"`
+		}{}
+
+		err = json.Unmarshal(payload, &statement)
+		if err != nil {
+			return "", fmt.Errorf("error decoding attestation: %w", err)
+		}
+
+		for _, subject := range statement.Subject {
+			if sha, ok := subject.Digest["sha256"]; ok {
+				return "sha256:" + sha, nil
+			}
+		}
+	}
+
+	return "", errors.New("cosign did not output the verified digest")
+}
+
+// pin returns an image by digest, such as
+// "registry.example.com/app@sha256:...", replacing its tag. Images already
+// referenced by digest are returned unchanged, as that is the digest cosign
+// verified.
+func pin(image, digest string) string {
+	if strings.Contains(image, "@") {
+		return image
+	}
+
+	if i := strings.LastIndex(image, ":"); i > strings.LastIndex(image, "/") {
+		image = image[:i]
+	}
+
+	return image + "@" + digest
+}
+
+// setImages replaces the images of the containers of the pods of manifests
+// with their pinned images.
+func setImages(manifests []map[string]interface{}, pinned map[string]string) {
+	for _, manifest := range manifests {
+		path, ok := schedule.PodSpecPath((&unstructured.Unstructured{Object: manifest}).GetKind())
+		if !ok {
+			continue
+		}
+
+		for _, field := range []string{"initContainers", "containers", "ephemeralContainers"} {
+			containers, _, _ := unstructured.NestedFieldNoCopy(manifest, append(path, field)...)
+			cs, _ := containers.([]interface{})
+
+			for _, c := range cs {
+				container, ok := c.(map[string]interface{})
+				if !ok {
+					continue
+				}
+
+				image, _ := container["image"].(string)
+				if p, ok := pinned[image]; ok {
+					container["image"] = p
+				}
+			}
+		}
+	}
+}
+
+// Images returns the container, init container and ephemeral container
+// images of the pods of manifests, without duplicates, in order.
+func Images(manifests []map[string]interface{}) ([]string, error) {
+	images := []string{}
+	seen := map[string]bool{}
+
+	for _, manifest := range manifests {
+		spec, ok, err := schedule.PodSpec(&unstructured.Unstructured{Object: manifest})
+		if err != nil {
+			return nil, err
+		}
+
+		if !ok {
+			continue
+		}
+
+		names := []string{}
+
+		for _, c := range spec.InitContainers {
+			names = append(names, c.Image)
+		}
+
+		for _, c := range spec.Containers {
+			names = append(names, c.Image)
+		}
+
+		for _, c := range spec.EphemeralContainers {
+			names = append(names, c.Image)
+		}
+
+		for _, name := range names {
+			if name != "" && !seen[name] {
+				seen[name] = true
+				images = append(images, name)
+			}
+		}
+	}
+
+	return images, nil
+}
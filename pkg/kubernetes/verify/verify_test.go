@@ -0,0 +1,178 @@
+package verify_test
+
+import (
+	"context"
+
+	. "github.com/billiford/go-clouddriver/pkg/kubernetes/verify"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+var _ = Describe("Verify", func() {
+	var (
+		enabled   = true
+		disabled  = false
+		config    Config
+		v         Verifier
+		manifests []map[string]interface{}
+		err       error
+	)
+
+	const digest = "sha256:4f53cda18c2baa0c0354bb5f9a3ecbe5ed12ab4d8e11ba873c2f11161202b945"
+
+	image := func(manifest map[string]interface{}) string {
+		containers, _, _ := unstructured.NestedSlice(manifest, "spec", "template", "spec", "containers")
+		return containers[0].(map[string]interface{})["image"].(string)
+	}
+
+	deployment := func(images ...string) map[string]interface{} {
+		containers := []interface{}{}
+		for _, image := range images {
+			containers = append(containers, map[string]interface{}{"name": "app", "image": image})
+		}
+
+		return map[string]interface{}{
+			"apiVersion": "apps/v1",
+			"kind":       "Deployment",
+			"metadata":   map[string]interface{}{"name": "test-deployment"},
+			"spec": map[string]interface{}{
+				"template": map[string]interface{}{
+					"spec": map[string]interface{}{"containers": containers},
+				},
+			},
+		}
+	}
+
+	BeforeEach(func() {
+		config = Config{
+			Policy: Policy{
+				Enabled: &enabled,
+				Key:     "cosign.pub",
+			},
+			Accounts: map[string]Policy{
+				"dev-account": {Enabled: &disabled},
+			},
+		}
+		manifests = []map[string]interface{}{
+			deployment("registry.example.com/signed-app:1.0.0"),
+			{
+				"apiVersion": "v1",
+				"kind":       "Service",
+				"metadata":   map[string]interface{}{"name": "test-service"},
+			},
+		}
+	})
+
+	JustBeforeEach(func() {
+		v = NewCosignVerifier("test/cosign", config)
+		err = v.Verify(context.Background(), "test-account", manifests)
+	})
+
+	When("the images are signed", func() {
+		It("pins the images to the verified digest", func() {
+			Expect(err).To(BeNil())
+			Expect(image(manifests[0])).To(Equal("registry.example.com/signed-app@" + digest))
+		})
+
+		When("an image is referenced by digest", func() {
+			BeforeEach(func() {
+				manifests[0] = deployment("registry.example.com/signed-app@sha256:1e2b")
+			})
+
+			It("keeps the digest", func() {
+				Expect(err).To(BeNil())
+				Expect(image(manifests[0])).To(Equal("registry.example.com/signed-app@sha256:1e2b"))
+			})
+		})
+
+		When("attestations are verified", func() {
+			BeforeEach(func() {
+				config.AttestationType = "slsaprovenance"
+			})
+
+			It("pins the images to the digest of the attestation's subject", func() {
+				Expect(err).To(BeNil())
+				Expect(image(manifests[0])).To(Equal("registry.example.com/signed-app@" + digest))
+			})
+		})
+	})
+
+	When("an image is not signed", func() {
+		BeforeEach(func() {
+			manifests = append(manifests, deployment("registry.example.com/unsigned-app:1.0.0"))
+		})
+
+		It("returns the verification error", func() {
+			Expect(err).ToNot(BeNil())
+			Expect(err.Error()).To(Equal("error verifying image registry.example.com/unsigned-app:1.0.0: " +
+				"exit status 1: Error: no matching signatures: registry.example.com/unsigned-app:1.0.0"))
+		})
+
+		When("the image is skipped", func() {
+			BeforeEach(func() {
+				config.SkipImages = []string{"registry.example.com/unsigned-"}
+			})
+
+			It("succeeds without pinning the skipped image", func() {
+				Expect(err).To(BeNil())
+				Expect(image(manifests[2])).To(Equal("registry.example.com/unsigned-app:1.0.0"))
+			})
+		})
+
+		When("the account does not verify images", func() {
+			BeforeEach(func() {
+				config.Accounts["test-account"] = Policy{Enabled: &disabled}
+			})
+
+			It("succeeds without pinning the images", func() {
+				Expect(err).To(BeNil())
+				Expect(image(manifests[0])).To(Equal("registry.example.com/signed-app:1.0.0"))
+			})
+		})
+	})
+
+	When("attestations are verified keyless", func() {
+		BeforeEach(func() {
+			config.Key = ""
+			config.CertificateIdentity = "https://github.com/example/app/.github/workflows/release.yaml@refs/heads/main"
+			config.CertificateOIDCIssuer = "https://token.actions.githubusercontent.com"
+			config.AttestationType = "slsaprovenance"
+		})
+
+		It("passes the policy to cosign", func() {
+			// The manifests were pinned by the verification of the test.
+			err = NewCosignVerifier("test/args", config).Verify(context.Background(), "test-account",
+				[]map[string]interface{}{deployment("registry.example.com/signed-app:1.0.0")})
+			Expect(err).ToNot(BeNil())
+			Expect(err.Error()).To(Equal("error verifying image registry.example.com/signed-app:1.0.0: exit status 1: " +
+				"verify-attestation --type slsaprovenance " +
+				"--certificate-identity https://github.com/example/app/.github/workflows/release.yaml@refs/heads/main " +
+				"--certificate-oidc-issuer https://token.actions.githubusercontent.com " +
+				"--output json registry.example.com/signed-app:1.0.0"))
+		})
+	})
+
+	Describe("#Images", func() {
+		It("returns the images of the pods once", func() {
+			images, err := Images([]map[string]interface{}{
+				deployment("nginx:1.19", "envoy:1.16"),
+				deployment("nginx:1.19"),
+			})
+			Expect(err).To(BeNil())
+			Expect(images).To(Equal([]string{"nginx:1.19", "envoy:1.16"}))
+		})
+	})
+
+	Describe("#PolicyFor", func() {
+		It("returns the policy of the account or the default", func() {
+			_, ok := config.PolicyFor("dev-account")
+			Expect(ok).To(BeFalse())
+			p, ok := config.PolicyFor("test-account")
+			Expect(ok).To(BeTrue())
+			Expect(p.Key).To(Equal("cosign.pub"))
+			Expect(config.Enabled()).To(BeTrue())
+			Expect(Config{}.Enabled()).To(BeFalse())
+		})
+	})
+})
@@ -0,0 +1,111 @@
+package loadtest_test
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/billiford/go-clouddriver/pkg/client"
+	ops "github.com/billiford/go-clouddriver/pkg/http/core/kubernetes"
+	"github.com/billiford/go-clouddriver/pkg/kubernetes"
+	"github.com/billiford/go-clouddriver/pkg/loadtest"
+)
+
+// Run the benchmarks with `make bench`. Allocations are those of the whole
+// process, so they include the client's.
+
+func newBenchmarkServer(b *testing.B, accounts int) (*loadtest.Server, client.Client) {
+	s, err := loadtest.NewServer(accounts, kubernetes.ProviderFake{})
+	if err != nil {
+		b.Fatal(err)
+	}
+
+	return s, client.New(client.Config{URL: s.URL, MaxRetries: -1})
+}
+
+func BenchmarkListCredentials(b *testing.B) {
+	for _, accounts := range []int{1, 10, 100} {
+		b.Run(fmt.Sprintf("accounts=%d", accounts), func(b *testing.B) {
+			s, cc := newBenchmarkServer(b, accounts)
+			defer s.Close()
+
+			ctx := context.Background()
+
+			// Fill the namespace and permissions caches, as they are once
+			// clouddriver has been polled.
+			_, err := cc.ListCredentials(ctx, true)
+			if err != nil {
+				b.Fatal(err)
+			}
+
+			b.ReportAllocs()
+			b.ResetTimer()
+
+			for i := 0; i < b.N; i++ {
+				_, err := cc.ListCredentials(ctx, true)
+				if err != nil {
+					b.Fatal(err)
+				}
+			}
+		})
+	}
+}
+
+// BenchmarkListCredentialsParallel polls /credentials?expand=true from as
+// many Gate instances at once as GOMAXPROCS, by default.
+func BenchmarkListCredentialsParallel(b *testing.B) {
+	s, cc := newBenchmarkServer(b, 100)
+	defer s.Close()
+
+	b.ReportAllocs()
+	b.ResetTimer()
+
+	b.RunParallel(func(pb *testing.PB) {
+		for pb.Next() {
+			_, err := cc.ListCredentials(context.Background(), true)
+			if err != nil {
+				b.Error(err)
+				return
+			}
+		}
+	})
+}
+
+func BenchmarkDeploy(b *testing.B) {
+	s, cc := newBenchmarkServer(b, 1)
+	defer s.Close()
+
+	ctx := context.Background()
+
+	b.ReportAllocs()
+	b.ResetTimer()
+
+	for i := 0; i < b.N; i++ {
+		dm := ops.DeployManifestRequest{
+			Account:       s.Accounts[0],
+			CloudProvider: "kubernetes",
+			Manifests: []map[string]interface{}{
+				{
+					"apiVersion": "v1",
+					"kind":       "ConfigMap",
+					"metadata": map[string]interface{}{
+						"name":      "benchmark",
+						"namespace": "default",
+					},
+					"data": map[string]interface{}{"revision": fmt.Sprint(i)},
+				},
+			},
+		}
+		dm.Moniker.App = "benchmark"
+
+		or, err := cc.Deploy(ctx, dm)
+		if err != nil {
+			b.Fatal(err)
+		}
+
+		_, err = cc.GetTask(ctx, or.ID)
+		if err != nil {
+			b.Fatal(err)
+		}
+	}
+}
@@ -0,0 +1,123 @@
+package loadtest
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"os"
+	"sort"
+)
+
+// ReadHistory reads the reports of earlier load tests from the file at path,
+// one JSON report a line, oldest first. A file that does not exist has no
+// reports.
+func ReadHistory(path string) ([]Report, error) {
+	f, err := os.Open(path)
+	if os.IsNotExist(err) {
+		return []Report{}, nil
+	}
+
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	reports := []Report{}
+	scanner := bufio.NewScanner(f)
+	scanner.Buffer(make([]byte, 64*1024), 1024*1024)
+
+	for line := 1; scanner.Scan(); line++ {
+		if len(scanner.Bytes()) == 0 {
+			continue
+		}
+
+		r := Report{}
+
+		err = json.Unmarshal(scanner.Bytes(), &r)
+		if err != nil {
+			return nil, fmt.Errorf("error reading report on line %d of %s: %w", line, path, err)
+		}
+
+		reports = append(reports, r)
+	}
+
+	return reports, scanner.Err()
+}
+
+// AppendHistory appends r to the reports of the file at path, creating it
+// if needed.
+func AppendHistory(path string, r Report) error {
+	b, err := json.Marshal(r)
+	if err != nil {
+		return err
+	}
+
+	f, err := os.OpenFile(path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644)
+	if err != nil {
+		return err
+	}
+
+	_, err = f.Write(append(b, '\n'))
+	if err != nil {
+		f.Close()
+		return err
+	}
+
+	return f.Close()
+}
+
+// LastComparable returns the last of reports of a load test with the same
+// load as c, as latencies under different loads cannot be compared.
+func LastComparable(reports []Report, c Config) (Report, bool) {
+	for i := len(reports) - 1; i >= 0; i-- {
+		rc := reports[i].Config
+		if rc.Pollers == c.Pollers && rc.PollInterval == c.PollInterval &&
+			rc.Deployers == c.Deployers && rc.Duration == c.Duration &&
+			len(rc.Accounts) == len(c.Accounts) {
+			return reports[i], true
+		}
+	}
+
+	return Report{}, false
+}
+
+// Compare returns the regressions of current from baseline: operations whose
+// mean or p99 latency rose by more than tolerance, such as 0.2 for 20%, or
+// whose error rate rose by more than a percentage point. Operations only in
+// one of the reports are not compared.
+func Compare(baseline, current Report, tolerance float64) []string {
+	operations := []string{}
+	for operation := range baseline.Operations {
+		operations = append(operations, operation)
+	}
+
+	sort.Strings(operations)
+
+	regressions := []string{}
+
+	for _, operation := range operations {
+		b := baseline.Operations[operation]
+
+		c, ok := current.Operations[operation]
+		if !ok || b.Requests == 0 || c.Requests == 0 {
+			continue
+		}
+
+		if b.MeanMs > 0 && c.MeanMs > b.MeanMs*(1+tolerance) {
+			regressions = append(regressions, fmt.Sprintf("%s: mean latency rose from %.1fms to %.1fms",
+				operation, b.MeanMs, c.MeanMs))
+		}
+
+		if b.P99Ms > 0 && c.P99Ms > b.P99Ms*(1+tolerance) {
+			regressions = append(regressions, fmt.Sprintf("%s: p99 latency rose from %.1fms to %.1fms",
+				operation, b.P99Ms, c.P99Ms))
+		}
+
+		if c.ErrorRate() > b.ErrorRate()+0.01 {
+			regressions = append(regressions, fmt.Sprintf("%s: error rate rose from %.1f%% to %.1f%%",
+				operation, b.ErrorRate()*100, c.ErrorRate()*100))
+		}
+	}
+
+	return regressions
+}
@@ -0,0 +1,330 @@
+// Package loadtest generates the load Spinnaker puts on clouddriver, Gate
+// instances polling accounts and pipelines deploying manifests, and measures
+// the latency of the requests it makes, so performance changes of the
+// credentials and cache paths can be measured and compared over time.
+package loadtest
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/billiford/go-clouddriver/pkg/client"
+	ops "github.com/billiford/go-clouddriver/pkg/http/core/kubernetes"
+	"github.com/billiford/go-clouddriver/pkg/version"
+)
+
+// Operations of a load test, as they are keyed in reports.
+const (
+	OperationListCredentials = "GET /credentials?expand=true"
+	OperationDeploy          = "POST /kubernetes/ops"
+	OperationGetTask         = "GET /task/:id"
+)
+
+// deployRetryWait is how long deployers wait after a deploy fails.
+var deployRetryWait = time.Second
+
+// Config configures the load of a load test.
+type Config struct {
+	// Pollers are the Gate instances polling /credentials?expand=true,
+	// every PollInterval, 30s if not set, as Gate does.
+	Pollers      int           `json:"pollers"`
+	PollInterval time.Duration `json:"pollInterval"`
+	// Deployers are the pipelines deploying at once. Each deploys a
+	// deployment of its own to one of Accounts, gets its task and deploys
+	// it again with a new image, until the load test ends.
+	Deployers int `json:"deployers"`
+	// Accounts deployed to, such as fake accounts.
+	Accounts []string `json:"accounts"`
+	// Application of the deployed manifests, loadtest if not set.
+	Application string `json:"application"`
+	// Duration of the load test.
+	Duration time.Duration `json:"duration"`
+}
+
+// Report is the outcome of a load test.
+type Report struct {
+	// Version of clouddriver the load test was built with.
+	Version    string           `json:"version"`
+	StartedAt  time.Time        `json:"startedAt"`
+	Config     Config           `json:"config"`
+	Operations map[string]Stats `json:"operations"`
+}
+
+// Stats are the latencies of the requests of an operation, in milliseconds.
+type Stats struct {
+	Requests int     `json:"requests"`
+	Errors   int     `json:"errors"`
+	PerSec   float64 `json:"perSec"`
+	MeanMs   float64 `json:"meanMs"`
+	P50Ms    float64 `json:"p50Ms"`
+	P90Ms    float64 `json:"p90Ms"`
+	P99Ms    float64 `json:"p99Ms"`
+	MaxMs    float64 `json:"maxMs"`
+}
+
+// ErrorRate returns the fraction of requests that failed.
+func (s Stats) ErrorRate() float64 {
+	if s.Requests == 0 {
+		return 0
+	}
+
+	return float64(s.Errors) / float64(s.Requests)
+}
+
+// NewStats returns the stats of the latencies of the requests of an
+// operation made over d, failed of which failed.
+func NewStats(latencies []time.Duration, failed int, d time.Duration) Stats {
+	s := Stats{
+		Requests: len(latencies),
+		Errors:   failed,
+	}
+
+	if len(latencies) == 0 {
+		return s
+	}
+
+	sorted := append([]time.Duration{}, latencies...)
+	sort.Slice(sorted, func(i, j int) bool {
+		return sorted[i] < sorted[j]
+	})
+
+	var total time.Duration
+	for _, l := range sorted {
+		total += l
+	}
+
+	if d > 0 {
+		s.PerSec = float64(len(sorted)) / d.Seconds()
+	}
+
+	s.MeanMs = ms(total / time.Duration(len(sorted)))
+	s.P50Ms = ms(percentile(sorted, 0.5))
+	s.P90Ms = ms(percentile(sorted, 0.9))
+	s.P99Ms = ms(percentile(sorted, 0.99))
+	s.MaxMs = ms(sorted[len(sorted)-1])
+
+	return s
+}
+
+// percentile returns the latency p of sorted latencies are at or below.
+func percentile(sorted []time.Duration, p float64) time.Duration {
+	i := int(math.Ceil(p*float64(len(sorted)))) - 1
+	if i < 0 {
+		i = 0
+	}
+
+	return sorted[i]
+}
+
+func ms(d time.Duration) float64 {
+	return float64(d) / float64(time.Millisecond)
+}
+
+// recorder records the latencies and errors of the requests of a load test
+// by operation.
+type recorder struct {
+	mux       sync.Mutex
+	latencies map[string][]time.Duration
+	errors    map[string]int
+}
+
+func newRecorder() *recorder {
+	return &recorder{
+		latencies: map[string][]time.Duration{},
+		errors:    map[string]int{},
+	}
+}
+
+func (r *recorder) record(operation string, latency time.Duration, err error) {
+	r.mux.Lock()
+	defer r.mux.Unlock()
+
+	r.latencies[operation] = append(r.latencies[operation], latency)
+
+	if err != nil {
+		r.errors[operation]++
+	}
+}
+
+func (r *recorder) report(c Config, startedAt time.Time, d time.Duration) Report {
+	r.mux.Lock()
+	defer r.mux.Unlock()
+
+	report := Report{
+		Version:    version.Version,
+		StartedAt:  startedAt,
+		Config:     c,
+		Operations: map[string]Stats{},
+	}
+
+	for operation, latencies := range r.latencies {
+		report.Operations[operation] = NewStats(latencies, r.errors[operation], d)
+	}
+
+	return report
+}
+
+// Run runs a load test against the clouddriver of cc until the Duration of
+// c has passed or ctx is done. Requests still in flight when the load test
+// ends are not recorded.
+func Run(ctx context.Context, cc client.Client, c Config) (Report, error) {
+	if c.Pollers < 0 || c.Deployers < 0 {
+		return Report{}, errors.New("pollers and deployers must not be negative")
+	}
+
+	if c.Deployers > 0 && len(c.Accounts) == 0 {
+		return Report{}, errors.New("deployers need accounts to deploy to")
+	}
+
+	if c.PollInterval <= 0 {
+		c.PollInterval = 30 * time.Second
+	}
+
+	if c.Application == "" {
+		c.Application = "loadtest"
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, c.Duration)
+	defer cancel()
+
+	r := newRecorder()
+	wg := &sync.WaitGroup{}
+	startedAt := time.Now()
+
+	wg.Add(c.Pollers + c.Deployers)
+
+	for i := 0; i < c.Pollers; i++ {
+		// Gate instances start at different times, so their polls are
+		// spread over the interval.
+		offset := c.PollInterval * time.Duration(i) / time.Duration(c.Pollers)
+		go poll(ctx, wg, r, cc, offset, c.PollInterval)
+	}
+
+	for i := 0; i < c.Deployers; i++ {
+		go deploy(ctx, wg, r, cc, c.Accounts[i%len(c.Accounts)], c.Application, i)
+	}
+
+	wg.Wait()
+
+	return r.report(c, startedAt, time.Since(startedAt)), nil
+}
+
+func poll(ctx context.Context, wg *sync.WaitGroup, r *recorder, cc client.Client,
+	offset, interval time.Duration) {
+	defer wg.Done()
+
+	if !wait(ctx, offset) {
+		return
+	}
+
+	for {
+		start := time.Now()
+		_, err := cc.ListCredentials(ctx, true)
+
+		if ctx.Err() != nil {
+			return
+		}
+
+		r.record(OperationListCredentials, time.Since(start), err)
+
+		if !wait(ctx, interval) {
+			return
+		}
+	}
+}
+
+func deploy(ctx context.Context, wg *sync.WaitGroup, r *recorder, cc client.Client,
+	account, application string, deployer int) {
+	defer wg.Done()
+
+	name := fmt.Sprintf("%s-%d", application, deployer)
+
+	for revision := 1; ; revision++ {
+		dm := ops.DeployManifestRequest{
+			Account:       account,
+			CloudProvider: "kubernetes",
+			Manifests:     []map[string]interface{}{deployment(name, application, revision)},
+		}
+		dm.Moniker.App = application
+
+		start := time.Now()
+		or, err := cc.Deploy(ctx, dm)
+
+		if ctx.Err() != nil {
+			return
+		}
+
+		r.record(OperationDeploy, time.Since(start), err)
+
+		if err != nil {
+			// Wait before deploying again, as Orca would, rather than
+			// failing as fast as clouddriver responds.
+			if !wait(ctx, deployRetryWait) {
+				return
+			}
+
+			continue
+		}
+
+		start = time.Now()
+		task, err := cc.GetTask(ctx, or.ID)
+
+		if ctx.Err() != nil {
+			return
+		}
+
+		if err == nil && task.Status.Failed {
+			err = fmt.Errorf("task %s failed", or.ID)
+		}
+
+		r.record(OperationGetTask, time.Since(start), err)
+	}
+}
+
+// wait returns false if ctx is done before d has passed.
+func wait(ctx context.Context, d time.Duration) bool {
+	t := time.NewTimer(d)
+	defer t.Stop()
+
+	select {
+	case <-ctx.Done():
+		return false
+	case <-t.C:
+		return true
+	}
+}
+
+// deployment returns the manifest of the deployment name, with an image
+// of revision so each deploy rolls out a new replica set.
+func deployment(name, application string, revision int) map[string]interface{} {
+	labels := map[string]interface{}{"app": name}
+
+	return map[string]interface{}{
+		"apiVersion": "apps/v1",
+		"kind":       "Deployment",
+		"metadata": map[string]interface{}{
+			"name":      name,
+			"namespace": "default",
+		},
+		"spec": map[string]interface{}{
+			"replicas": int64(1),
+			"selector": map[string]interface{}{"matchLabels": labels},
+			"template": map[string]interface{}{
+				"metadata": map[string]interface{}{"labels": labels},
+				"spec": map[string]interface{}{
+					"containers": []interface{}{
+						map[string]interface{}{
+							"name":  application,
+							"image": fmt.Sprintf("gcr.io/fake/%s:v%d", application, revision),
+						},
+					},
+				},
+			},
+		},
+	}
+}
@@ -0,0 +1,282 @@
+package loadtest_test
+
+import (
+	"context"
+	"errors"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"time"
+
+	clouddriver "github.com/billiford/go-clouddriver/pkg"
+	"github.com/billiford/go-clouddriver/pkg/client"
+	"github.com/billiford/go-clouddriver/pkg/client/clientfakes"
+	ops "github.com/billiford/go-clouddriver/pkg/http/core/kubernetes"
+	"github.com/billiford/go-clouddriver/pkg/kubernetes"
+	. "github.com/billiford/go-clouddriver/pkg/loadtest"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Loadtest", func() {
+	Describe("#NewStats", func() {
+		It("returns the percentiles of the latencies", func() {
+			latencies := []time.Duration{}
+			for i := 100; i > 0; i-- {
+				latencies = append(latencies, time.Duration(i)*time.Millisecond)
+			}
+
+			s := NewStats(latencies, 5, 10*time.Second)
+			Expect(s.Requests).To(Equal(100))
+			Expect(s.Errors).To(Equal(5))
+			Expect(s.ErrorRate()).To(Equal(0.05))
+			Expect(s.PerSec).To(Equal(10.0))
+			Expect(s.MeanMs).To(Equal(50.5))
+			Expect(s.P50Ms).To(Equal(50.0))
+			Expect(s.P90Ms).To(Equal(90.0))
+			Expect(s.P99Ms).To(Equal(99.0))
+			Expect(s.MaxMs).To(Equal(100.0))
+			Expect(latencies[0]).To(Equal(100*time.Millisecond), "latencies are not sorted in place")
+		})
+
+		It("returns no latencies without requests", func() {
+			s := NewStats(nil, 0, time.Second)
+			Expect(s).To(Equal(Stats{}))
+			Expect(s.ErrorRate()).To(BeZero())
+		})
+	})
+
+	Describe("#Run", func() {
+		var (
+			fakeClient *clientfakes.FakeClient
+			config     Config
+			report     Report
+			err        error
+		)
+
+		BeforeEach(func() {
+			fakeClient = &clientfakes.FakeClient{}
+			// Deploy as slowly as a cluster would, so deployers do not
+			// record every call of a tight loop.
+			fakeClient.DeployCalls(func(context.Context, ops.DeployManifestRequest) (ops.OperationsResponse, error) {
+				time.Sleep(5 * time.Millisecond)
+				return ops.OperationsResponse{ID: "test-task-id"}, nil
+			})
+			fakeClient.GetTaskReturns(clouddriver.Task{Status: clouddriver.TaskStatus{Completed: true}}, nil)
+			config = Config{
+				Pollers:      2,
+				PollInterval: 10 * time.Millisecond,
+				Deployers:    2,
+				Accounts:     []string{"account1", "account2"},
+				Duration:     100 * time.Millisecond,
+			}
+		})
+
+		JustBeforeEach(func() {
+			report, err = Run(context.Background(), fakeClient, config)
+		})
+
+		When("deployers have no accounts", func() {
+			BeforeEach(func() {
+				config.Accounts = nil
+			})
+
+			It("returns an error", func() {
+				Expect(err).ToNot(BeNil())
+				Expect(err.Error()).To(Equal("deployers need accounts to deploy to"))
+			})
+		})
+
+		When("pollers are negative", func() {
+			BeforeEach(func() {
+				config.Pollers = -1
+			})
+
+			It("returns an error", func() {
+				Expect(err).ToNot(BeNil())
+				Expect(err.Error()).To(Equal("pollers and deployers must not be negative"))
+			})
+		})
+
+		When("deploys fail", func() {
+			BeforeEach(func() {
+				fakeClient.DeployReturns(ops.OperationsResponse{}, errors.New("error deploying"))
+			})
+
+			It("records the errors", func() {
+				Expect(err).To(BeNil())
+				s := report.Operations[OperationDeploy]
+				Expect(s.Requests).To(BeNumerically(">", 0))
+				Expect(s.Errors).To(Equal(s.Requests))
+				Expect(report.Operations).ToNot(HaveKey(OperationGetTask))
+			})
+		})
+
+		When("tasks fail", func() {
+			BeforeEach(func() {
+				fakeClient.GetTaskReturns(clouddriver.Task{Status: clouddriver.TaskStatus{Failed: true}}, nil)
+			})
+
+			It("records the errors", func() {
+				Expect(err).To(BeNil())
+				s := report.Operations[OperationGetTask]
+				Expect(s.Requests).To(BeNumerically(">", 0))
+				Expect(s.Errors).To(Equal(s.Requests))
+			})
+		})
+
+		When("it succeeds", func() {
+			It("polls and deploys until the load test ends", func() {
+				Expect(err).To(BeNil())
+				Expect(report.Config.Application).To(Equal("loadtest"))
+				Expect(report.Operations).To(HaveKey(OperationListCredentials))
+				Expect(report.Operations[OperationListCredentials].Errors).To(BeZero())
+				Expect(report.Operations[OperationDeploy].Requests).To(BeNumerically(">", 0))
+				Expect(report.Operations[OperationGetTask].Requests).To(BeNumerically(">", 0))
+
+				_, expand := fakeClient.ListCredentialsArgsForCall(0)
+				Expect(expand).To(BeTrue())
+
+				accounts := map[string]bool{}
+				for i := 0; i < fakeClient.DeployCallCount(); i++ {
+					_, dm := fakeClient.DeployArgsForCall(i)
+					Expect(dm.Moniker.App).To(Equal("loadtest"))
+					Expect(dm.Manifests).To(HaveLen(1))
+					accounts[dm.Account] = true
+				}
+				Expect(accounts).To(HaveLen(2))
+
+				_, id := fakeClient.GetTaskArgsForCall(0)
+				Expect(id).To(Equal("test-task-id"))
+			})
+		})
+	})
+
+	Describe("#Compare", func() {
+		var baseline, current Report
+
+		BeforeEach(func() {
+			baseline = Report{Operations: map[string]Stats{
+				OperationListCredentials: {Requests: 100, MeanMs: 10, P99Ms: 50},
+				OperationDeploy:          {Requests: 100, MeanMs: 100, P99Ms: 500},
+			}}
+			current = Report{Operations: map[string]Stats{
+				OperationListCredentials: {Requests: 100, MeanMs: 11, P99Ms: 55},
+				OperationDeploy:          {Requests: 100, MeanMs: 100, P99Ms: 500},
+			}}
+		})
+
+		It("returns no regressions within the tolerance", func() {
+			Expect(Compare(baseline, current, 0.2)).To(BeEmpty())
+		})
+
+		It("returns latencies that rose by more than the tolerance", func() {
+			current.Operations[OperationListCredentials] = Stats{Requests: 100, MeanMs: 13, P99Ms: 80}
+			Expect(Compare(baseline, current, 0.2)).To(Equal([]string{
+				"GET /credentials?expand=true: mean latency rose from 10.0ms to 13.0ms",
+				"GET /credentials?expand=true: p99 latency rose from 50.0ms to 80.0ms",
+			}))
+		})
+
+		It("returns error rates that rose", func() {
+			current.Operations[OperationDeploy] = Stats{Requests: 100, Errors: 5, MeanMs: 100, P99Ms: 500}
+			Expect(Compare(baseline, current, 0.2)).To(Equal([]string{
+				"POST /kubernetes/ops: error rate rose from 0.0% to 5.0%",
+			}))
+		})
+
+		It("does not compare operations without requests", func() {
+			current.Operations[OperationDeploy] = Stats{}
+			delete(current.Operations, OperationListCredentials)
+			Expect(Compare(baseline, current, 0.2)).To(BeEmpty())
+		})
+	})
+
+	Describe("history", func() {
+		var (
+			dir  string
+			path string
+		)
+
+		BeforeEach(func() {
+			var err error
+			dir, err = ioutil.TempDir("", "loadtest")
+			Expect(err).To(BeNil())
+			path = filepath.Join(dir, "history.jsonl")
+		})
+
+		AfterEach(func() {
+			os.RemoveAll(dir)
+		})
+
+		It("reads no reports from a file that does not exist", func() {
+			reports, err := ReadHistory(path)
+			Expect(err).To(BeNil())
+			Expect(reports).To(BeEmpty())
+		})
+
+		It("reads the reports appended to it", func() {
+			first := Report{Version: "1.0.0", Config: Config{Pollers: 1}, Operations: map[string]Stats{
+				OperationDeploy: {Requests: 1, MeanMs: 100},
+			}}
+			second := Report{Version: "1.1.0", Config: Config{Pollers: 2}}
+
+			Expect(AppendHistory(path, first)).To(Succeed())
+			Expect(AppendHistory(path, second)).To(Succeed())
+
+			reports, err := ReadHistory(path)
+			Expect(err).To(BeNil())
+			Expect(reports).To(HaveLen(2))
+			Expect(reports[0].Operations[OperationDeploy].MeanMs).To(Equal(100.0))
+			Expect(reports[1].Version).To(Equal("1.1.0"))
+
+			baseline, ok := LastComparable(reports, Config{Pollers: 1})
+			Expect(ok).To(BeTrue())
+			Expect(baseline.Version).To(Equal("1.0.0"))
+
+			_, ok = LastComparable(reports, Config{Pollers: 3})
+			Expect(ok).To(BeFalse())
+		})
+
+		It("returns an error for a line that is not a report", func() {
+			Expect(ioutil.WriteFile(path, []byte("{}\nnot json\n"), 0644)).To(Succeed())
+
+			_, err := ReadHistory(path)
+			Expect(err).ToNot(BeNil())
+			Expect(err.Error()).To(HavePrefix("error reading report on line 2 of " + path))
+		})
+	})
+
+	Describe("#NewServer", func() {
+		var s *Server
+
+		BeforeEach(func() {
+			var err error
+			s, err = NewServer(2, kubernetes.ProviderFake{Applications: []string{"app-a"}})
+			Expect(err).To(BeNil())
+		})
+
+		AfterEach(func() {
+			s.Close()
+		})
+
+		It("serves fake accounts that can be deployed to", func() {
+			Expect(s.Accounts).To(Equal([]string{"load-0", "load-1"}))
+
+			report, err := Run(context.Background(), client.New(client.Config{URL: s.URL, MaxRetries: -1}), Config{
+				Pollers:      1,
+				PollInterval: 50 * time.Millisecond,
+				Deployers:    2,
+				Accounts:     s.Accounts,
+				Duration:     time.Second,
+			})
+			Expect(err).To(BeNil())
+
+			for _, operation := range []string{OperationListCredentials, OperationDeploy, OperationGetTask} {
+				Expect(report.Operations).To(HaveKey(operation))
+				Expect(report.Operations[operation].Requests).To(BeNumerically(">", 0), operation)
+				Expect(report.Operations[operation].Errors).To(BeZero(), operation)
+			}
+		})
+	})
+})
@@ -0,0 +1,169 @@
+package loadtest
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/billiford/go-clouddriver/pkg/arcade/arcadefakes"
+	"github.com/billiford/go-clouddriver/pkg/artifact"
+	"github.com/billiford/go-clouddriver/pkg/docker"
+	"github.com/billiford/go-clouddriver/pkg/fiat/fiatfakes"
+	"github.com/billiford/go-clouddriver/pkg/freeze"
+	kube "github.com/billiford/go-clouddriver/pkg/http/core/kubernetes"
+	"github.com/billiford/go-clouddriver/pkg/kubernetes"
+	"github.com/billiford/go-clouddriver/pkg/server"
+	"github.com/billiford/go-clouddriver/pkg/sql"
+	"github.com/gin-gonic/gin"
+	"github.com/jinzhu/gorm"
+)
+
+// Server runs clouddriver in this process against a SQLite database in a
+// temporary directory, with fake accounts, so load tests are reproducible
+// without a database or clusters. Close removes the database.
+type Server struct {
+	*httptest.Server
+
+	// Accounts are the names of the fake accounts of the server.
+	Accounts []string
+
+	db  *gorm.DB
+	dir string
+}
+
+// NewServer returns a server with accounts fake accounts named load-0,
+// load-1 and so on, each with the cluster described by fake.
+func NewServer(accounts int, fake kubernetes.ProviderFake) (*Server, error) {
+	dir, err := ioutil.TempDir("", "clouddriver-load")
+	if err != nil {
+		return nil, err
+	}
+
+	s := &Server{dir: dir}
+
+	err = s.start()
+	if err != nil {
+		s.Close()
+		return nil, err
+	}
+
+	for i := 0; i < accounts; i++ {
+		name := fmt.Sprintf("load-%d", i)
+
+		err = s.createAccount(name, fake)
+		if err != nil {
+			s.Close()
+			return nil, err
+		}
+
+		s.Accounts = append(s.Accounts, name)
+	}
+
+	return s, nil
+}
+
+func (s *Server) start() error {
+	var err error
+
+	s.db, err = sql.Connect("sqlite3", filepath.Join(s.dir, "clouddriver.db"))
+	if err != nil {
+		return err
+	}
+
+	// SQLite has one writer at a time, so a single connection keeps
+	// concurrent deploys from failing on a locked database.
+	sql.Configure(s.db, sql.Config{MaxOpenConns: 1})
+
+	// No docker registry or artifact accounts are configured.
+	config := filepath.Join(s.dir, "config")
+
+	err = os.Mkdir(config, 0700)
+	if err != nil {
+		return err
+	}
+
+	dc, err := docker.NewCredentialsController(config)
+	if err != nil {
+		return err
+	}
+
+	acc, err := artifact.NewCredentialsController(config)
+	if err != nil {
+		return err
+	}
+
+	// Nothing is frozen.
+	fc, err := freeze.NewControllerWithConfig(freeze.Config{})
+	if err != nil {
+		return err
+	}
+
+	ac := &arcadefakes.FakeClient{}
+	ac.TokenReturns("load-test", nil)
+
+	gin.SetMode(gin.ReleaseMode)
+
+	r := gin.New()
+	r.Use(gin.Recovery())
+
+	server.Setup(r, &server.Config{
+		ArcadeClient:                  ac,
+		ArtifactCredentialsController: acc,
+		DockerCredentialsController:   dc,
+		FiatClient:                    &fiatfakes.FakeClient{},
+		FreezeController:              fc,
+		SQLClient:                     sql.NewClient(s.db),
+		KubeController:                kubernetes.NewController(),
+		KubeActionHandler:             kube.NewActionHandler(),
+		KubeNamespaceCache:            kubernetes.NewNamespaceCache(time.Minute),
+		KubePermissionsCache:          kubernetes.NewPermissionsCache(time.Minute),
+	})
+
+	s.Server = httptest.NewServer(r)
+
+	return nil
+}
+
+// createAccount creates a fake account through the API, so its seeded
+// resources are recorded as they are for accounts created by users.
+func (s *Server) createAccount(name string, fake kubernetes.ProviderFake) error {
+	b, err := json.Marshal(map[string]interface{}{
+		"name": name,
+		"fake": fake,
+	})
+	if err != nil {
+		return err
+	}
+
+	res, err := http.Post(s.URL+"/v1/kubernetes/providers", "application/json", bytes.NewBuffer(b))
+	if err != nil {
+		return err
+	}
+	defer res.Body.Close()
+
+	if res.StatusCode != http.StatusCreated {
+		body, _ := ioutil.ReadAll(res.Body)
+		return fmt.Errorf("error creating account %s: %d %s", name, res.StatusCode, body)
+	}
+
+	return nil
+}
+
+// Close shuts down the server and removes its database.
+func (s *Server) Close() {
+	if s.Server != nil {
+		s.Server.Close()
+	}
+
+	if s.db != nil {
+		s.db.Close()
+	}
+
+	os.RemoveAll(s.dir)
+}
@@ -0,0 +1,346 @@
+// Package lock provides advisory locks of Spinnaker clusters, held while
+// operations change them, so two pipelines do not roll the same cluster at
+// the same time.
+package lock
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+const (
+	InstanceKey = `Locker`
+
+	// PolicyWait queues conflicting operations until the lock is released.
+	PolicyWait = `wait`
+	// PolicyFail fails conflicting operations right away.
+	PolicyFail = `fail`
+
+	defaultTTL          = time.Minute
+	defaultPollInterval = time.Second
+)
+
+var (
+	conflicts = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "clouddriver_cluster_lock_conflicts_total",
+		Help: "Number of operations that found a cluster locked by policy.",
+	}, []string{"policy"})
+)
+
+func init() {
+	prometheus.MustRegister(conflicts)
+}
+
+// Key returns the key of the lock of a cluster, such as "deployment my-app",
+// in a namespace of an account.
+func Key(account, namespace, cluster string) string {
+	return fmt.Sprintf("%s/%s/%s", account, namespace, cluster)
+}
+
+// Config configures how conflicting operations are handled.
+type Config struct {
+	// Policy is PolicyWait or PolicyFail. Defaults to PolicyWait.
+	Policy string
+	// MaxWait is how long an operation waits for a lock before failing.
+	// Operations wait as long as their request when zero.
+	MaxWait time.Duration
+	// Store holds the locks, so every instance of clouddriver sharing it
+	// sees them. Locks are only held within this instance when nil.
+	Store Store
+	// TTL is how long locks are kept in the Store without being renewed,
+	// such as those of an instance that stopped. Held locks are renewed
+	// every third of it. Defaults to a minute.
+	TTL time.Duration
+	// PollInterval is how often a lock held in the Store is tried again
+	// while waiting for it. Defaults to a second.
+	PollInterval time.Duration
+}
+
+// ClusterLock is a lock held in a Store. Owners hold it once per Lock call
+// until each is released, so locks are reentrant across instances.
+type ClusterLock struct {
+	Key       string `gorm:"column:lock_key;primary_key"`
+	Owner     string
+	Holds     int
+	ExpiresAt time.Time `gorm:"index"`
+}
+
+func (ClusterLock) TableName() string {
+	return "cluster_locks"
+}
+
+// Store holds the locks of owners, such as in SQL.
+type Store interface {
+	// AcquireClusterLocks takes the locks of all keys for an owner at once
+	// until the expiry, or none of them if any key is locked by another
+	// owner, returning that key and its owner. Expired locks are taken
+	// from their owners.
+	AcquireClusterLocks(owner string, keys []string, expiry time.Time) (string, string, error)
+	// RenewClusterLocks extends the locks of keys an owner holds.
+	RenewClusterLocks(owner string, keys []string, expiry time.Time) error
+	// ReleaseClusterLocks releases one hold of the owner on each key.
+	ReleaseClusterLocks(owner string, keys []string) error
+}
+
+// ConflictError is returned when a lock is held by another owner.
+type ConflictError struct {
+	Key   string
+	Owner string
+	// Err is the reason waiting for the lock stopped, if it was waited for.
+	Err error
+}
+
+func (e *ConflictError) Error() string {
+	msg := fmt.Sprintf("cluster %s is locked by %s", e.Key, e.Owner)
+	if e.Err != nil {
+		msg += ": " + e.Err.Error()
+	}
+
+	return msg
+}
+
+func (e *ConflictError) Unwrap() error {
+	return e.Err
+}
+
+// Locker holds the locks of clusters on behalf of owners, such as
+// pipeline executions.
+//
+//go:generate counterfeiter . Locker
+type Locker interface {
+	// Lock acquires the locks of all keys for an owner at once, returning
+	// a func that releases them. Locks are reentrant, so an owner is never
+	// blocked by the locks it already holds.
+	Lock(context.Context, string, ...string) (func(), error)
+}
+
+// New returns a Locker that handles conflicts by the policy of config.
+func New(config Config) (Locker, error) {
+	if config.Policy == "" {
+		config.Policy = PolicyWait
+	}
+
+	if config.Policy != PolicyWait && config.Policy != PolicyFail {
+		return nil, fmt.Errorf("unknown cluster lock policy %q, must be %s or %s",
+			config.Policy, PolicyWait, PolicyFail)
+	}
+
+	if config.Store != nil {
+		if config.TTL <= 0 {
+			config.TTL = defaultTTL
+		}
+
+		if config.PollInterval <= 0 {
+			config.PollInterval = defaultPollInterval
+		}
+
+		return &storeLocker{config: config}, nil
+	}
+
+	return &locker{
+		config: config,
+		locks:  map[string]*lock{},
+	}, nil
+}
+
+type locker struct {
+	mux    sync.Mutex
+	config Config
+	locks  map[string]*lock
+}
+
+type lock struct {
+	owner string
+	count int
+	// Closed when the lock is released by its owner.
+	released chan struct{}
+}
+
+func (l *locker) Lock(ctx context.Context, owner string, keys ...string) (func(), error) {
+	keys = unique(keys)
+
+	if l.config.MaxWait > 0 {
+		var cancel context.CancelFunc
+
+		ctx, cancel = context.WithTimeout(ctx, l.config.MaxWait)
+		defer cancel()
+	}
+
+	for {
+		l.mux.Lock()
+
+		key, held := l.conflict(owner, keys)
+		if held == nil {
+			l.acquire(owner, keys)
+			l.mux.Unlock()
+
+			var once sync.Once
+
+			return func() {
+				once.Do(func() {
+					l.release(keys)
+				})
+			}, nil
+		}
+
+		l.mux.Unlock()
+		conflicts.WithLabelValues(l.config.Policy).Inc()
+
+		if l.config.Policy == PolicyFail {
+			return nil, &ConflictError{Key: key, Owner: held.owner}
+		}
+
+		select {
+		case <-held.released:
+		case <-ctx.Done():
+			return nil, &ConflictError{Key: key, Owner: held.owner, Err: ctx.Err()}
+		}
+	}
+}
+
+// conflict returns the first key locked by another owner. It must be
+// called with the mutex held.
+func (l *locker) conflict(owner string, keys []string) (string, *lock) {
+	for _, key := range keys {
+		if lk, ok := l.locks[key]; ok && lk.owner != owner {
+			return key, lk
+		}
+	}
+
+	return "", nil
+}
+
+// acquire must be called with the mutex held.
+func (l *locker) acquire(owner string, keys []string) {
+	for _, key := range keys {
+		lk, ok := l.locks[key]
+		if !ok {
+			lk = &lock{owner: owner, released: make(chan struct{})}
+			l.locks[key] = lk
+		}
+
+		lk.count++
+	}
+}
+
+func (l *locker) release(keys []string) {
+	l.mux.Lock()
+	defer l.mux.Unlock()
+
+	for _, key := range keys {
+		lk, ok := l.locks[key]
+		if !ok {
+			continue
+		}
+
+		lk.count--
+		if lk.count == 0 {
+			delete(l.locks, key)
+			close(lk.released)
+		}
+	}
+}
+
+// storeLocker holds locks in a Store. As other instances release locks
+// without notice, locks held by other owners are tried again on an interval.
+type storeLocker struct {
+	config Config
+}
+
+func (l *storeLocker) Lock(ctx context.Context, owner string, keys ...string) (func(), error) {
+	keys = unique(keys)
+
+	if l.config.MaxWait > 0 {
+		var cancel context.CancelFunc
+
+		ctx, cancel = context.WithTimeout(ctx, l.config.MaxWait)
+		defer cancel()
+	}
+
+	for {
+		key, holder, err := l.config.Store.AcquireClusterLocks(owner, keys, time.Now().Add(l.config.TTL))
+		if err != nil {
+			return nil, fmt.Errorf("error locking clusters: %w", err)
+		}
+
+		if key == "" {
+			return l.hold(owner, keys), nil
+		}
+
+		conflicts.WithLabelValues(l.config.Policy).Inc()
+
+		if l.config.Policy == PolicyFail {
+			return nil, &ConflictError{Key: key, Owner: holder}
+		}
+
+		select {
+		case <-time.After(l.config.PollInterval):
+		case <-ctx.Done():
+			return nil, &ConflictError{Key: key, Owner: holder, Err: ctx.Err()}
+		}
+	}
+}
+
+// hold renews the locks of keys until the returned func releases them.
+func (l *storeLocker) hold(owner string, keys []string) func() {
+	done := make(chan struct{})
+
+	go func() {
+		ticker := time.NewTicker(l.config.TTL / 3)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-done:
+				return
+			case <-ticker.C:
+				err := l.config.Store.RenewClusterLocks(owner, keys, time.Now().Add(l.config.TTL))
+				if err != nil {
+					log.Println("[LOCK] error renewing locks of", owner+":", err.Error())
+				}
+			}
+		}
+	}()
+
+	var once sync.Once
+
+	return func() {
+		once.Do(func() {
+			close(done)
+
+			err := l.config.Store.ReleaseClusterLocks(owner, keys)
+			if err != nil {
+				log.Println("[LOCK] error releasing locks of", owner+":", err.Error())
+			}
+		})
+	}
+}
+
+func unique(keys []string) []string {
+	seen := map[string]bool{}
+	u := []string{}
+
+	for _, key := range keys {
+		if !seen[key] {
+			seen[key] = true
+			u = append(u, key)
+		}
+	}
+
+	sort.Strings(u)
+
+	return u
+}
+
+// Instance returns the Locker, or nil if clusters are not locked.
+func Instance(c *gin.Context) Locker {
+	l, _ := c.MustGet(InstanceKey).(Locker)
+	return l
+}
@@ -0,0 +1,191 @@
+package lock_test
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	. "github.com/billiford/go-clouddriver/pkg/lock"
+	"github.com/billiford/go-clouddriver/pkg/sql"
+	"github.com/jinzhu/gorm"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Lock", func() {
+	var (
+		config Config
+		l      Locker
+		ctx    context.Context
+		err    error
+	)
+
+	BeforeEach(func() {
+		config = Config{}
+		ctx = context.Background()
+	})
+
+	JustBeforeEach(func() {
+		l, err = New(config)
+	})
+
+	Describe("#New", func() {
+		When("the policy is unknown", func() {
+			BeforeEach(func() {
+				config.Policy = "queue"
+			})
+
+			It("returns an error", func() {
+				Expect(err).ToNot(BeNil())
+				Expect(err.Error()).To(Equal(`unknown cluster lock policy "queue", must be wait or fail`))
+			})
+		})
+	})
+
+	Describe("#Key", func() {
+		It("joins the account, namespace and cluster", func() {
+			Expect(Key("test-account", "default", "deployment my-app")).To(Equal("test-account/default/deployment my-app"))
+		})
+	})
+
+	Describe("#Lock", func() {
+		var unlock func()
+
+		JustBeforeEach(func() {
+			Expect(err).To(BeNil())
+			unlock, err = l.Lock(ctx, "execution-1", "a", "b")
+			Expect(err).To(BeNil())
+		})
+
+		It("is reentrant for its owner", func() {
+			again, err := l.Lock(ctx, "execution-1", "b", "c")
+			Expect(err).To(BeNil())
+			again()
+			unlock()
+			unlock()
+		})
+
+		When("the policy is fail", func() {
+			BeforeEach(func() {
+				config.Policy = PolicyFail
+			})
+
+			It("fails an operation of another owner", func() {
+				_, err := l.Lock(ctx, "execution-2", "c", "b")
+				Expect(err).ToNot(BeNil())
+				Expect(err.Error()).To(Equal("cluster b is locked by execution-1"))
+
+				var ce *ConflictError
+				Expect(errors.As(err, &ce)).To(BeTrue())
+			})
+
+			It("takes no locks when any is held", func() {
+				_, err := l.Lock(ctx, "execution-2", "c", "b")
+				Expect(err).ToNot(BeNil())
+
+				other, err := l.Lock(ctx, "execution-3", "c")
+				Expect(err).To(BeNil())
+				other()
+			})
+
+			It("admits other owners once unlocked", func() {
+				unlock()
+				other, err := l.Lock(ctx, "execution-2", "a")
+				Expect(err).To(BeNil())
+				other()
+			})
+		})
+
+		When("the policy is wait", func() {
+			BeforeEach(func() {
+				config.MaxWait = 50 * time.Millisecond
+			})
+
+			It("waits for the lock to be released", func() {
+				go func() {
+					time.Sleep(10 * time.Millisecond)
+					unlock()
+				}()
+
+				other, err := l.Lock(ctx, "execution-2", "b")
+				Expect(err).To(BeNil())
+				other()
+			})
+
+			It("fails after waiting the max wait", func() {
+				start := time.Now()
+				_, err := l.Lock(ctx, "execution-2", "b")
+				Expect(err).ToNot(BeNil())
+				Expect(err.Error()).To(Equal("cluster b is locked by execution-1: context deadline exceeded"))
+				Expect(errors.Is(err, context.DeadlineExceeded)).To(BeTrue())
+				Expect(time.Since(start)).To(BeNumerically(">=", 50*time.Millisecond))
+			})
+		})
+
+		Context("in a store", func() {
+			var db *gorm.DB
+
+			BeforeEach(func() {
+				driver, connection, err := sql.ParseDSN("sqlite::memory:")
+				Expect(err).To(BeNil())
+				db, err = sql.Connect(driver, connection)
+				Expect(err).To(BeNil())
+				config.Store = sql.NewClient(db)
+				config.TTL = 30 * time.Millisecond
+				config.PollInterval = 5 * time.Millisecond
+				config.MaxWait = 50 * time.Millisecond
+			})
+
+			AfterEach(func() {
+				unlock()
+				db.Close()
+			})
+
+			It("is reentrant for its owner", func() {
+				again, err := l.Lock(ctx, "execution-1", "b", "c")
+				Expect(err).To(BeNil())
+				again()
+
+				_, err = l.Lock(ctx, "execution-2", "b")
+				Expect(err).ToNot(BeNil())
+				Expect(err.Error()).To(Equal("cluster b is locked by execution-1: context deadline exceeded"))
+				other, err := l.Lock(ctx, "execution-2", "c")
+				Expect(err).To(BeNil())
+				other()
+			})
+
+			It("renews the locks it holds", func() {
+				time.Sleep(2 * config.TTL)
+				_, err := l.Lock(ctx, "execution-2", "a")
+				Expect(err).ToNot(BeNil())
+			})
+
+			It("waits for the lock to be released", func() {
+				go func() {
+					time.Sleep(10 * time.Millisecond)
+					unlock()
+				}()
+
+				other, err := l.Lock(ctx, "execution-2", "b")
+				Expect(err).To(BeNil())
+				other()
+			})
+
+			When("the policy is fail", func() {
+				BeforeEach(func() {
+					config.Policy = PolicyFail
+				})
+
+				It("takes no locks when any is held", func() {
+					_, err := l.Lock(ctx, "execution-2", "c", "b")
+					Expect(err).ToNot(BeNil())
+					Expect(err.Error()).To(Equal("cluster b is locked by execution-1"))
+
+					other, err := l.Lock(ctx, "execution-3", "c")
+					Expect(err).To(BeNil())
+					other()
+				})
+			})
+		})
+	})
+})
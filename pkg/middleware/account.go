@@ -0,0 +1,58 @@
+package middleware
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+
+	clouddriver "github.com/billiford/go-clouddriver/pkg"
+	"github.com/billiford/go-clouddriver/pkg/kubernetes"
+	"github.com/billiford/go-clouddriver/pkg/sql"
+	"github.com/gin-gonic/gin"
+	"github.com/jinzhu/gorm"
+)
+
+const headerSpinnakerAccount = `X-Spinnaker-Account`
+
+var errNoAccountProvided = errors.New("no account provided")
+
+// LoadAccount validates the account of a request and loads its provider
+// into the context, see kubernetes.ProviderInstance. The account is read
+// from the "account" path param or the X-Spinnaker-Account header.
+// Unknown accounts are rejected with 404 Not Found.
+func LoadAccount() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		account := c.Param("account")
+		if account == "" {
+			account = c.GetHeader(headerSpinnakerAccount)
+		}
+
+		if account == "" {
+			clouddriver.WriteError(c, http.StatusBadRequest, errNoAccountProvided)
+			c.Abort()
+
+			return
+		}
+
+		sc := sql.Instance(c)
+
+		provider, err := sc.GetKubernetesProvider(account)
+		if err != nil {
+			if err == gorm.ErrRecordNotFound {
+				clouddriver.WriteError(c, http.StatusNotFound, fmt.Errorf("account %s not found", account))
+			} else {
+				clouddriver.WriteError(c, http.StatusInternalServerError, err)
+			}
+
+			c.Abort()
+
+			return
+		}
+
+		// Getting the provider does not select its name.
+		provider.Name = account
+
+		c.Set(kubernetes.ProviderInstanceKey, provider)
+		c.Next()
+	}
+}
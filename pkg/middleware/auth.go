@@ -1,12 +1,20 @@
 package middleware
 
 import (
+	"bytes"
+	"encoding/json"
+	"errors"
 	"fmt"
+	"io/ioutil"
 	"net/http"
+	"strings"
 
 	clouddriver "github.com/billiford/go-clouddriver/pkg"
 	"github.com/billiford/go-clouddriver/pkg/fiat"
 	"github.com/billiford/go-clouddriver/pkg/http/core"
+	kube "github.com/billiford/go-clouddriver/pkg/http/core/kubernetes"
+	"github.com/billiford/go-clouddriver/pkg/kubernetes"
+	"github.com/billiford/go-clouddriver/pkg/sql"
 	"github.com/gin-gonic/gin"
 )
 
@@ -15,12 +23,21 @@ const (
 	headerSpinnakerApplication = `X-Spinnaker-Application`
 )
 
+var errNoUser = errors.New("no " + headerSpinnakerUser + " header found")
+
 //authApplication takes a list of permissions
 //authAccount takes a list of accounts
 
 func AuthApplication(permissions ...string) gin.HandlerFunc {
 	return func(c *gin.Context) {
-		user := c.GetHeader(headerSpinnakerUser)
+		user, err := fiat.User(c)
+		if err != nil {
+			clouddriver.WriteError(c, http.StatusUnauthorized, err)
+			c.Abort()
+
+			return
+		}
+
 		app := c.GetHeader(headerSpinnakerApplication)
 
 		if user == "" || app == "" {
@@ -28,10 +45,10 @@ func AuthApplication(permissions ...string) gin.HandlerFunc {
 			return
 		}
 
-		fiatClient := fiat.Instance(c)
-		authResp, err := fiatClient.Authorize(user)
+		authResp, err := fiat.Authorize(c, user)
 		if err != nil {
 			clouddriver.WriteError(c, http.StatusUnauthorized, err)
+			c.Abort()
 			return
 		}
 
@@ -41,7 +58,12 @@ func AuthApplication(permissions ...string) gin.HandlerFunc {
 				for _, p := range permissions {
 					found := find(auth.Authorizations, p)
 					if !found {
-						clouddriver.WriteError(c, http.StatusForbidden, fmt.Errorf("Access denied to application %s - required authorization: %s", app, p))
+						clouddriver.WriteError(c, http.StatusForbidden, &clouddriver.AccessDeniedError{
+							ResourceType:          "application",
+							Resource:              app,
+							RequiredAuthorization: p,
+						})
+						c.Abort()
 						return
 					}
 				}
@@ -51,36 +73,312 @@ func AuthApplication(permissions ...string) gin.HandlerFunc {
 	}
 }
 
+// AuthAccount checks the user has the given authorizations to the account of
+// the request. Routes with a location are checked against the groups of the
+// namespace instead, for the authorizations it overrides.
 func AuthAccount(permissions ...string) gin.HandlerFunc {
 	return func(c *gin.Context) {
-		user := c.GetHeader(headerSpinnakerUser)
+		user, err := fiat.User(c)
+		if err != nil {
+			clouddriver.WriteError(c, http.StatusUnauthorized, err)
+			c.Abort()
+
+			return
+		}
+
 		account := c.Param("account")
-		fiatClient := fiat.Instance(c)
+		namespace := c.Param("location")
 
 		if user == "" || account == "" {
 			c.Next()
 			return
 		}
 
-		authResp, err := fiatClient.Authorize(user)
+		authResp, err := fiat.Authorize(c, user)
 		if err != nil {
 			clouddriver.WriteError(c, http.StatusUnauthorized, err)
+			c.Abort()
 			return
 		}
 
-		accountsAuth := authResp.Accounts
+		for _, p := range permissions {
+			status, err := authorizeNamespace(c, authResp, account, namespace, p)
+			if err != nil {
+				clouddriver.WriteError(c, status, err)
+				c.Abort()
+				return
+			}
+		}
+		c.Next()
+	}
+}
+
+// AuthOperations checks the user may run the operations of the request on
+// the namespaces they change. Operations need EXECUTE to accounts and
+// namespaces with execute groups and WRITE to namespaces with write groups.
+// Operations that change the permissions of an application need a user with
+// WRITE to it, if it exists. Other operations are authorized by Orca before
+// it sends them.
+func AuthOperations() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		user, err := fiat.User(c)
+		if err != nil {
+			clouddriver.WriteError(c, http.StatusUnauthorized, err)
+			c.Abort()
+
+			return
+		}
+
+		if c.Request.Body == nil {
+			c.Next()
+			return
+		}
+
+		b, err := ioutil.ReadAll(c.Request.Body)
+		if err != nil {
+			clouddriver.WriteError(c, http.StatusBadRequest, err)
+			c.Abort()
+
+			return
+		}
+
+		c.Request.Body = ioutil.NopCloser(bytes.NewReader(b))
+
+		// Requests that are not operations are rejected by the handler.
+		ko := kube.Operations{}
+		if json.Unmarshal(b, &ko) != nil {
+			c.Next()
+			return
+		}
+
+		if user == "" {
+			for _, req := range ko {
+				if _, ok := req.ChangedApplication(); ok {
+					clouddriver.WriteError(c, http.StatusUnauthorized, errNoUser)
+					c.Abort()
+
+					return
+				}
+			}
+
+			c.Next()
+
+			return
+		}
+
+		var authResp *fiat.Response
+
+		for _, req := range ko {
+			if application, ok := req.ChangedApplication(); ok {
+				if authResp == nil {
+					r, err := fiat.Authorize(c, user)
+					if err != nil {
+						clouddriver.WriteError(c, http.StatusUnauthorized, err)
+						c.Abort()
 
-		for _, auth := range accountsAuth {
-			if auth.Name == account {
-				for _, p := range permissions {
-					found := find(auth.Authorizations, p)
-					if !found {
-						clouddriver.WriteError(c, http.StatusForbidden, fmt.Errorf("Access denied to account %s - required authorization: %s", account, p))
 						return
 					}
+
+					authResp = &r
+				}
+
+				status, err := authorizeApplication(c, *authResp, application)
+				if err != nil {
+					clouddriver.WriteError(c, status, err)
+					c.Abort()
+
+					return
 				}
+
+				continue
+			}
+
+			account := req.Account()
+			if account == "" {
+				continue
+			}
+
+			permissions, err := accountPermissions(c, account)
+			if err != nil {
+				clouddriver.WriteError(c, http.StatusInternalServerError, err)
+				c.Abort()
+
+				return
+			}
+
+			namespaces := req.Namespaces()
+			if len(namespaces) == 0 {
+				namespaces = []string{""}
+			}
+
+			for _, namespace := range namespaces {
+				p := permissions.OperationAuthorization(namespace)
+				if _, ok := permissions.NamespaceGroups(namespace, p); !ok && p == "WRITE" {
+					continue
+				}
+
+				if authResp == nil {
+					r, err := fiat.Authorize(c, user)
+					if err != nil {
+						clouddriver.WriteError(c, http.StatusUnauthorized, err)
+						c.Abort()
+
+						return
+					}
+
+					authResp = &r
+				}
+
+				status, err := authorizeNamespace(c, *authResp, account, namespace, p)
+				if err != nil {
+					clouddriver.WriteError(c, status, err)
+					c.Abort()
+
+					return
+				}
+			}
+		}
+
+		c.Next()
+	}
+}
+
+// authorizeApplication returns an error, and the status to respond with, if a
+// user may not create or delete an application. Creating an application that
+// exists replaces its permissions, from which users without Fiat are
+// authorized, so only admins and users with WRITE to an existing application
+// may change or delete it. Unlike other routes, an existing application Fiat
+// does not list is denied.
+func authorizeApplication(c *gin.Context, r fiat.Response, application string) (int, error) {
+	if r.Admin {
+		return http.StatusOK, nil
+	}
+
+	apps, err := sql.Instance(c).ListApplications()
+	if err != nil {
+		return http.StatusInternalServerError, err
+	}
+
+	exists := false
+
+	for _, a := range apps {
+		if a.Name == application {
+			exists = true
+			break
+		}
+	}
+
+	if !exists {
+		return http.StatusOK, nil
+	}
+
+	for _, auth := range r.Applications {
+		if auth.Name == application && find(auth.Authorizations, "WRITE") {
+			return http.StatusOK, nil
+		}
+	}
+
+	return http.StatusForbidden, &clouddriver.AccessDeniedError{
+		ResourceType:          "application",
+		Resource:              application,
+		RequiredAuthorization: "WRITE",
+	}
+}
+
+// authorizeNamespace returns an error, and the status to respond with, if a
+// user does not have an authorization to a namespace of an account. Users
+// need one of the groups of a namespace that overrides the authorization,
+// which Fiat does not know of, and otherwise the authorization to the
+// account. Like other routes, accounts Fiat does not list are let through.
+func authorizeNamespace(c *gin.Context, r fiat.Response, account, namespace, authorization string) (int, error) {
+	if namespace != "" {
+		permissions, err := accountPermissions(c, account)
+		if err != nil {
+			return http.StatusInternalServerError, err
+		}
+
+		if groups, ok := permissions.NamespaceGroups(namespace, authorization); ok {
+			if r.Admin || hasRole(r, groups) {
+				return http.StatusOK, nil
+			}
+
+			return http.StatusForbidden, &clouddriver.AccessDeniedError{
+				ResourceType:          "namespace",
+				Resource:              account + "/" + namespace,
+				RequiredAuthorization: authorization,
+				RequiredGroups:        groups,
+			}
+		}
+	}
+
+	for _, auth := range r.Accounts {
+		if auth.Name != account {
+			continue
+		}
+
+		if find(auth.Authorizations, authorization) {
+			return http.StatusOK, nil
+		}
+
+		return http.StatusForbidden, &clouddriver.AccessDeniedError{
+			ResourceType:          "account",
+			Resource:              account,
+			RequiredAuthorization: authorization,
+			RequiredGroups:        requiredGroups(c, account, authorization),
+		}
+	}
+
+	return http.StatusOK, nil
+}
+
+// hasRole returns true if the user of a Fiat response has one of the groups.
+func hasRole(r fiat.Response, groups []string) bool {
+	for _, role := range r.Roles {
+		for _, group := range groups {
+			if strings.EqualFold(role.Name, group) {
+				return true
 			}
 		}
+	}
+
+	return false
+}
+
+// AuthAdmin only lets Fiat admins through. Unlike the other auth
+// middlewares requests without a user are rejected, as admin endpoints
+// change clouddriver itself.
+func AuthAdmin() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		user, err := fiat.User(c)
+		if err != nil {
+			clouddriver.WriteError(c, http.StatusUnauthorized, err)
+			c.Abort()
+
+			return
+		}
+
+		if user == "" {
+			clouddriver.WriteError(c, http.StatusUnauthorized, errNoUser)
+			c.Abort()
+
+			return
+		}
+
+		authResp, err := fiat.Authorize(c, user)
+		if err != nil {
+			clouddriver.WriteError(c, http.StatusUnauthorized, err)
+			c.Abort()
+
+			return
+		}
+
+		if !authResp.Admin {
+			clouddriver.WriteError(c, http.StatusForbidden, fmt.Errorf("user %s is not an admin", user))
+			c.Abort()
+
+			return
+		}
+
 		c.Next()
 	}
 }
@@ -96,14 +394,18 @@ func PostFilterAuthorizedApplications(permissions ...string) gin.HandlerFunc {
 		allApps := core.Applications{}
 		allApps = c.MustGet(core.KeyAllApplications).(core.Applications)
 
-		user := c.GetHeader(headerSpinnakerUser)
+		user, err := fiat.User(c)
+		if err != nil {
+			clouddriver.WriteError(c, http.StatusUnauthorized, err)
+			return
+		}
+
 		if user == "" {
 			c.JSON(http.StatusOK, allApps)
 			return
 		}
 
-		fiatClient := fiat.Instance(c)
-		authResp, err := fiatClient.Authorize(user)
+		authResp, err := fiat.Authorize(c, user)
 		if err != nil {
 			clouddriver.WriteError(c, http.StatusUnauthorized, err)
 			return
@@ -120,6 +422,170 @@ func PostFilterAuthorizedApplications(permissions ...string) gin.HandlerFunc {
 	}
 }
 
+// PostFilterAuthorizedStats filters the stats of a request to the accounts,
+// namespaces and applications the user has the permissions to. Like
+// PostFilterAuthorizedApplications, only what Fiat lists for the user, or
+// namespaces whose groups the user has, are kept.
+func PostFilterAuthorizedStats(permissions ...string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		c.Next()
+
+		if len(c.Errors) > 0 {
+			return
+		}
+
+		stats := c.MustGet(core.KeyStats)
+
+		user, err := fiat.User(c)
+		if err != nil {
+			clouddriver.WriteError(c, http.StatusUnauthorized, err)
+			return
+		}
+
+		if user == "" {
+			c.JSON(http.StatusOK, stats)
+			return
+		}
+
+		authResp, err := fiat.Authorize(c, user)
+		if err != nil {
+			clouddriver.WriteError(c, http.StatusUnauthorized, err)
+			return
+		}
+
+		authorized := func(account, namespace string) (bool, error) {
+			for _, p := range permissions {
+				ok, err := authorizedNamespace(c, authResp, account, namespace, p)
+				if err != nil || !ok {
+					return false, err
+				}
+			}
+
+			return true, nil
+		}
+
+		switch s := stats.(type) {
+		case []core.AccountRequestStats:
+			filtered := []core.AccountRequestStats{}
+
+			for _, ars := range s {
+				ok, err := authorized(ars.Account, "")
+				if err != nil {
+					clouddriver.WriteError(c, http.StatusInternalServerError, err)
+					return
+				}
+
+				if ok {
+					filtered = append(filtered, ars)
+				}
+			}
+
+			stats = filtered
+		case core.DeployStats:
+			applications := []core.ApplicationDeployStats{}
+
+			for _, ads := range s.Applications {
+				if authorizedApplication(authResp, ads.Application, permissions...) {
+					applications = append(applications, ads)
+				}
+			}
+
+			namespaces := []core.NamespaceDeployStats{}
+
+			for _, nds := range s.Namespaces {
+				ok, err := authorized(nds.Account, nds.Namespace)
+				if err != nil {
+					clouddriver.WriteError(c, http.StatusInternalServerError, err)
+					return
+				}
+
+				if ok {
+					namespaces = append(namespaces, nds)
+				}
+			}
+
+			s.Applications = applications
+			s.Namespaces = namespaces
+			stats = s
+		}
+
+		c.JSON(http.StatusOK, stats)
+	}
+}
+
+// authorizedNamespace returns true if the groups of a namespace of an
+// account give the user the authorization or, for namespaces without groups
+// and an empty namespace, Fiat lists the account with it. Unlike
+// authorizeNamespace, accounts Fiat does not list are not authorized, as
+// stats only list what users may see.
+func authorizedNamespace(c *gin.Context, r fiat.Response, account, namespace, authorization string) (bool, error) {
+	if namespace != "" {
+		permissions, err := accountPermissions(c, account)
+		if err != nil {
+			return false, err
+		}
+
+		if groups, ok := permissions.NamespaceGroups(namespace, authorization); ok {
+			return r.Admin || hasRole(r, groups), nil
+		}
+	}
+
+	for _, auth := range r.Accounts {
+		if auth.Name == account {
+			return find(auth.Authorizations, authorization), nil
+		}
+	}
+
+	return false, nil
+}
+
+// authorizedApplication returns true if Fiat lists the application with all
+// of the permissions for the user.
+func authorizedApplication(r fiat.Response, application string, permissions ...string) bool {
+	for _, auth := range r.Applications {
+		if auth.Name != application {
+			continue
+		}
+
+		for _, p := range permissions {
+			if !find(auth.Authorizations, p) {
+				return false
+			}
+		}
+
+		return true
+	}
+
+	return false
+}
+
+// requiredGroups returns the groups that have the given authorization
+// to an account, so users know which group to request access to.
+// Groups are best effort - if they cannot be listed none are returned.
+func requiredGroups(c *gin.Context, account, authorization string) []string {
+	permissions, err := accountPermissions(c, account)
+	if err != nil {
+		return nil
+	}
+
+	return permissions.Groups(authorization)
+}
+
+// accountPermissions returns the groups of an account. They are read from
+// the database rather than the permissions cache, as the cache of an
+// instance is only cleared when the account changes through that instance,
+// and authorizing from stale groups would let removed users through.
+func accountPermissions(c *gin.Context, account string) (kubernetes.ProviderPermissions, error) {
+	sc := sql.ReadOnlyInstance(c)
+
+	listed, err := sc.ListPermissionsByAccountNames(account)
+	if err != nil {
+		return kubernetes.ProviderPermissions{}, err
+	}
+
+	return listed[account], nil
+}
+
 func find(slice []string, val string) bool {
 	for _, item := range slice {
 		if item == val {
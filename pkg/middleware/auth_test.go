@@ -1,15 +1,23 @@
 package middleware_test
 
 import (
+	"encoding/json"
 	"errors"
+	"io/ioutil"
 	"net/http"
 	"net/http/httptest"
+	"strings"
 
+	clouddriver "github.com/billiford/go-clouddriver/pkg"
 	"github.com/billiford/go-clouddriver/pkg/fiat"
 	"github.com/billiford/go-clouddriver/pkg/fiat/fiatfakes"
 	"github.com/billiford/go-clouddriver/pkg/http/core"
+	"github.com/billiford/go-clouddriver/pkg/kubernetes"
+	"github.com/billiford/go-clouddriver/pkg/kubernetes/kubernetesfakes"
 	"github.com/billiford/go-clouddriver/pkg/middleware"
 	. "github.com/billiford/go-clouddriver/pkg/middleware"
+	"github.com/billiford/go-clouddriver/pkg/sql"
+	"github.com/billiford/go-clouddriver/pkg/sql/sqlfakes"
 	"github.com/gin-gonic/gin"
 	. "github.com/onsi/ginkgo"
 	. "github.com/onsi/gomega"
@@ -19,6 +27,8 @@ var (
 	c                                                       *gin.Context
 	hf                                                      gin.HandlerFunc
 	fakeFiatClient                                          *fiatfakes.FakeClient
+	fakeSQLClient                                           *sqlfakes.FakeClient
+	fakePermissionsCache                                    *kubernetesfakes.FakePermissionsCache
 	r                                                       *http.Request
 	err                                                     error
 	testUser, testApplication, testAccount, authorizeErrMsg string
@@ -32,6 +42,10 @@ var _ = Describe("Auth", func() {
 		c, _ = gin.CreateTestContext(httptest.NewRecorder())
 		fakeFiatClient = &fiatfakes.FakeClient{}
 		c.Set(fiat.ClientInstanceKey, fakeFiatClient)
+		fakeSQLClient = &sqlfakes.FakeClient{}
+		c.Set(sql.ReadOnlyClientInstanceKey, fakeSQLClient)
+		fakePermissionsCache = &kubernetesfakes.FakePermissionsCache{}
+		c.Set(kubernetes.PermissionsCacheInstanceKey, fakePermissionsCache)
 		r, err = http.NewRequest(http.MethodGet, "", nil)
 		Expect(err).To(BeNil())
 		c.Request = r
@@ -73,6 +87,43 @@ var _ = Describe("Auth", func() {
 			})
 		})
 
+		When("a group resolver is set", func() {
+			var fakeGroupResolver *fiatfakes.FakeGroupResolver
+
+			BeforeEach(func() {
+				fakeGroupResolver = &fiatfakes.FakeGroupResolver{}
+				fakeGroupResolver.ResolveReturns(fiat.Identity{}, errors.New("no OIDC token found"))
+				c.Set(fiat.GroupResolverInstanceKey, fakeGroupResolver)
+			})
+
+			When("the request has no valid token", func() {
+				It("returns status Unauthorized", func() {
+					Expect(c.IsAborted()).To(BeTrue())
+					Expect(c.Writer.Status()).To(Equal(http.StatusUnauthorized))
+					Expect(c.Errors[0].Error()).To(Equal("no OIDC token found"))
+				})
+			})
+
+			When("the user header is empty", func() {
+				BeforeEach(func() {
+					r.Header.Del("X-Spinnaker-User")
+					fakeGroupResolver.ResolveReturns(fiat.Identity{User: "token-user", Groups: []string{"team-b"}}, nil)
+					fakeSQLClient.ListApplicationsReturns([]clouddriver.Application{
+						{
+							Name:        testApplication,
+							Permissions: clouddriver.Permissions{READ: []string{"team-a"}},
+						},
+					}, nil)
+				})
+
+				It("authorizes the user of the token", func() {
+					Expect(c.Writer.Status()).To(Equal(http.StatusForbidden))
+					Expect(c.Errors[0].Error()).To(Equal("Access denied to application test-application - required authorization: READ"))
+					Expect(fakeFiatClient.AuthorizeCallCount()).To(BeZero())
+				})
+			})
+		})
+
 		When("fiatClient.Authorize returns an error", func() {
 			BeforeEach(func() {
 				fakeFiatClient.AuthorizeReturns(fiat.Response{}, errors.New("fake error"))
@@ -142,6 +193,19 @@ var _ = Describe("Auth", func() {
 				Expect(c.Writer.Status()).To(Equal(http.StatusOK))
 				Expect(fakeFiatClient.AuthorizeCallCount()).To(BeZero())
 			})
+
+			When("a group resolver is set and the request has no valid token", func() {
+				BeforeEach(func() {
+					fakeGroupResolver := &fiatfakes.FakeGroupResolver{}
+					fakeGroupResolver.ResolveReturns(fiat.Identity{}, errors.New("no OIDC token found"))
+					c.Set(fiat.GroupResolverInstanceKey, fakeGroupResolver)
+				})
+
+				It("returns status Unauthorized", func() {
+					Expect(c.IsAborted()).To(BeTrue())
+					Expect(c.Writer.Status()).To(Equal(http.StatusUnauthorized))
+				})
+			})
 		})
 
 		When("account is missing from path params", func() {
@@ -190,6 +254,44 @@ var _ = Describe("Auth", func() {
 			It("returns status Forbidden", func() {
 				Expect(c.Writer.Status()).To(Equal(http.StatusForbidden))
 				Expect(c.Errors[0].Error()).To(Equal("Access denied to account test-account - required authorization: READ"))
+				Expect(c.IsAborted()).To(BeTrue())
+			})
+
+			When("the account has groups", func() {
+				BeforeEach(func() {
+					fakePermissionsCache.GetReturns(kubernetes.ProviderPermissions{
+						Read: []string{"removed-group"},
+					}, true)
+					fakeSQLClient.ListPermissionsByAccountNamesReturns(map[string]kubernetes.ProviderPermissions{
+						testAccount: {
+							Read:  []string{"read-group"},
+							Write: []string{"write-group"},
+						},
+					}, nil)
+				})
+
+				It("returns the groups of the database with the required authorization, not the cached ones", func() {
+					Expect(fakeSQLClient.ListPermissionsByAccountNamesCallCount()).To(Equal(1))
+					Expect(fakeSQLClient.ListPermissionsByAccountNamesArgsForCall(0)).To(Equal([]string{testAccount}))
+					Expect(fakePermissionsCache.GetCallCount()).To(BeZero())
+					var ade *clouddriver.AccessDeniedError
+					Expect(errors.As(c.Errors[0].Err, &ade)).To(BeTrue())
+					Expect(ade.RequiredAuthorization).To(Equal("READ"))
+					Expect(ade.RequiredGroups).To(Equal([]string{"read-group"}))
+				})
+			})
+
+			When("listing the account's permissions returns an error", func() {
+				BeforeEach(func() {
+					fakeSQLClient.ListPermissionsByAccountNamesReturns(nil, errors.New("error listing permissions"))
+				})
+
+				It("returns status Forbidden without groups", func() {
+					Expect(c.Writer.Status()).To(Equal(http.StatusForbidden))
+					var ade *clouddriver.AccessDeniedError
+					Expect(errors.As(c.Errors[0].Err, &ade)).To(BeTrue())
+					Expect(ade.RequiredGroups).To(BeNil())
+				})
 			})
 		})
 
@@ -211,6 +313,345 @@ var _ = Describe("Auth", func() {
 		})
 	})
 
+	Describe("#AuthAccount with a location", func() {
+		BeforeEach(func() {
+			hf = AuthAccount("READ")
+			c.Params = append(c.Params,
+				gin.Param{Key: "account", Value: testAccount},
+				gin.Param{Key: "location", Value: "team-a"},
+			)
+			fakeFiatClient.AuthorizeReturns(fiat.Response{
+				Name:  testUser,
+				Roles: []fiat.Role{{Name: "team-b"}},
+				Accounts: []fiat.Account{
+					{
+						Name:           testAccount,
+						Authorizations: []string{"READ"},
+					},
+				},
+			}, nil)
+		})
+
+		JustBeforeEach(func() {
+			hf(c)
+		})
+
+		When("the namespace does not override the authorization", func() {
+			BeforeEach(func() {
+				fakeSQLClient.ListPermissionsByAccountNamesReturns(map[string]kubernetes.ProviderPermissions{
+					testAccount: {
+						Namespaces: map[string]kubernetes.ProviderNamespacePermissions{
+							"team-a": {Write: []string{"team-a"}},
+						},
+					},
+				}, nil)
+			})
+
+			It("checks the authorization to the account", func() {
+				Expect(c.Writer.Status()).To(Equal(http.StatusOK))
+				Expect(c.IsAborted()).To(BeFalse())
+			})
+		})
+
+		When("the user is not in a group of the namespace", func() {
+			BeforeEach(func() {
+				fakeSQLClient.ListPermissionsByAccountNamesReturns(map[string]kubernetes.ProviderPermissions{
+					testAccount: {
+						Namespaces: map[string]kubernetes.ProviderNamespacePermissions{
+							"team-a": {Read: []string{"team-a"}},
+						},
+					},
+				}, nil)
+			})
+
+			It("returns status Forbidden with the groups of the namespace", func() {
+				Expect(c.Writer.Status()).To(Equal(http.StatusForbidden))
+				Expect(c.Errors[0].Error()).To(Equal("Access denied to namespace test-account/team-a - required authorization: READ"))
+				var ade *clouddriver.AccessDeniedError
+				Expect(errors.As(c.Errors[0].Err, &ade)).To(BeTrue())
+				Expect(ade.RequiredGroups).To(Equal([]string{"team-a"}))
+				Expect(c.IsAborted()).To(BeTrue())
+			})
+		})
+
+		When("the user is in a group of the namespace", func() {
+			BeforeEach(func() {
+				fakeSQLClient.ListPermissionsByAccountNamesReturns(map[string]kubernetes.ProviderPermissions{
+					testAccount: {
+						Namespaces: map[string]kubernetes.ProviderNamespacePermissions{
+							"team-a": {Read: []string{"Team-B"}},
+						},
+					},
+				}, nil)
+			})
+
+			It("returns status OK", func() {
+				Expect(c.Writer.Status()).To(Equal(http.StatusOK))
+				Expect(c.IsAborted()).To(BeFalse())
+			})
+		})
+
+		When("listing the account's permissions returns an error", func() {
+			BeforeEach(func() {
+				fakeSQLClient.ListPermissionsByAccountNamesReturns(nil, errors.New("error listing permissions"))
+			})
+
+			It("returns status internal server error", func() {
+				Expect(c.Writer.Status()).To(Equal(http.StatusInternalServerError))
+				Expect(c.IsAborted()).To(BeTrue())
+			})
+		})
+	})
+
+	Describe("#AuthOperations", func() {
+		var called bool
+
+		BeforeEach(func() {
+			called = false
+			hf = AuthOperations()
+			r, err = http.NewRequest(http.MethodPost, "",
+				strings.NewReader(`[{"scaleManifest":{"account":"test-account","location":"team-a"}}]`))
+			Expect(err).To(BeNil())
+			r.Header.Set("X-Spinnaker-User", testUser)
+			c.Request = r
+			fakeFiatClient.AuthorizeReturns(fiat.Response{
+				Name:  testUser,
+				Roles: []fiat.Role{{Name: "team-a"}},
+				Accounts: []fiat.Account{
+					{
+						Name:           testAccount,
+						Authorizations: []string{"READ", "WRITE"},
+					},
+				},
+			}, nil)
+		})
+
+		JustBeforeEach(func() {
+			hf(c)
+			called = !c.IsAborted()
+		})
+
+		When("the account has no execute or namespace groups", func() {
+			It("leaves the operations to Orca", func() {
+				Expect(called).To(BeTrue())
+				Expect(fakeFiatClient.AuthorizeCallCount()).To(BeZero())
+			})
+
+			It("keeps the body of the request", func() {
+				b, _ := ioutil.ReadAll(c.Request.Body)
+				Expect(string(b)).To(ContainSubstring("scaleManifest"))
+			})
+		})
+
+		When("the account has execute groups the user does not have", func() {
+			BeforeEach(func() {
+				fakeSQLClient.ListPermissionsByAccountNamesReturns(map[string]kubernetes.ProviderPermissions{
+					testAccount: {
+						Execute: []string{"deployers"},
+					},
+				}, nil)
+			})
+
+			It("returns status Forbidden", func() {
+				Expect(called).To(BeFalse())
+				Expect(c.Writer.Status()).To(Equal(http.StatusForbidden))
+				Expect(c.Errors[0].Error()).To(Equal("Access denied to account test-account - required authorization: EXECUTE"))
+			})
+		})
+
+		When("the namespace has execute groups the user is in", func() {
+			BeforeEach(func() {
+				fakeSQLClient.ListPermissionsByAccountNamesReturns(map[string]kubernetes.ProviderPermissions{
+					testAccount: {
+						Execute: []string{"deployers"},
+						Namespaces: map[string]kubernetes.ProviderNamespacePermissions{
+							"team-a": {Execute: []string{"team-a"}},
+						},
+					},
+				}, nil)
+			})
+
+			It("calls c.Next", func() {
+				Expect(called).To(BeTrue())
+			})
+		})
+
+		When("the namespace has write groups the user is not in", func() {
+			BeforeEach(func() {
+				fakeSQLClient.ListPermissionsByAccountNamesReturns(map[string]kubernetes.ProviderPermissions{
+					testAccount: {
+						Namespaces: map[string]kubernetes.ProviderNamespacePermissions{
+							"team-a": {Write: []string{"team-b"}},
+						},
+					},
+				}, nil)
+			})
+
+			It("returns status Forbidden", func() {
+				Expect(called).To(BeFalse())
+				Expect(c.Errors[0].Error()).To(Equal("Access denied to namespace test-account/team-a - required authorization: WRITE"))
+			})
+		})
+
+		When("the request is not operations", func() {
+			BeforeEach(func() {
+				c.Request.Body = ioutil.NopCloser(strings.NewReader("{}"))
+			})
+
+			It("calls c.Next", func() {
+				Expect(called).To(BeTrue())
+				Expect(fakeSQLClient.ListPermissionsByAccountNamesCallCount()).To(BeZero())
+			})
+		})
+
+		When("a group resolver is set and the request has no valid token", func() {
+			BeforeEach(func() {
+				fakeGroupResolver := &fiatfakes.FakeGroupResolver{}
+				fakeGroupResolver.ResolveReturns(fiat.Identity{}, errors.New("no OIDC token found"))
+				c.Set(fiat.GroupResolverInstanceKey, fakeGroupResolver)
+			})
+
+			It("returns status Unauthorized", func() {
+				Expect(called).To(BeFalse())
+				Expect(c.Writer.Status()).To(Equal(http.StatusUnauthorized))
+				Expect(fakeSQLClient.ListPermissionsByAccountNamesCallCount()).To(BeZero())
+			})
+		})
+
+		Context("an operation changes an application", func() {
+			BeforeEach(func() {
+				c.Set(sql.ClientInstanceKey, fakeSQLClient)
+				c.Request.Body = ioutil.NopCloser(strings.NewReader(
+					`[{"createApplication":{"application":{"name":"test-application","permissions":{"WRITE":["team-a"]}}}}]`))
+			})
+
+			When("user is empty", func() {
+				BeforeEach(func() {
+					r.Header.Del("X-Spinnaker-User")
+				})
+
+				It("returns status Unauthorized", func() {
+					Expect(called).To(BeFalse())
+					Expect(c.Writer.Status()).To(Equal(http.StatusUnauthorized))
+				})
+			})
+
+			When("the application does not exist", func() {
+				It("calls c.Next", func() {
+					Expect(called).To(BeTrue())
+				})
+			})
+
+			When("listing applications returns an error", func() {
+				BeforeEach(func() {
+					fakeSQLClient.ListApplicationsReturns(nil, errors.New("error listing applications"))
+				})
+
+				It("returns status Internal Server Error", func() {
+					Expect(called).To(BeFalse())
+					Expect(c.Writer.Status()).To(Equal(http.StatusInternalServerError))
+				})
+			})
+
+			When("the application exists", func() {
+				BeforeEach(func() {
+					fakeSQLClient.ListApplicationsReturns([]clouddriver.Application{{Name: testApplication}}, nil)
+				})
+
+				When("the user may not write it", func() {
+					It("returns status Forbidden", func() {
+						Expect(called).To(BeFalse())
+						Expect(c.Writer.Status()).To(Equal(http.StatusForbidden))
+						Expect(c.Errors[0].Error()).To(Equal("Access denied to application test-application - required authorization: WRITE"))
+					})
+				})
+
+				When("the user may write it", func() {
+					BeforeEach(func() {
+						fakeFiatClient.AuthorizeReturns(fiat.Response{
+							Name: testUser,
+							Applications: []fiat.Application{
+								{Name: testApplication, Authorizations: []string{"READ", "WRITE"}},
+							},
+						}, nil)
+					})
+
+					It("calls c.Next", func() {
+						Expect(called).To(BeTrue())
+					})
+				})
+
+				When("the user is an admin", func() {
+					BeforeEach(func() {
+						fakeFiatClient.AuthorizeReturns(fiat.Response{Name: testUser, Admin: true}, nil)
+					})
+
+					It("calls c.Next", func() {
+						Expect(called).To(BeTrue())
+						Expect(fakeSQLClient.ListApplicationsCallCount()).To(BeZero())
+					})
+				})
+			})
+		})
+	})
+
+	Describe("#AuthAdmin", func() {
+		BeforeEach(func() {
+			hf = AuthAdmin()
+		})
+
+		JustBeforeEach(func() {
+			hf(c)
+		})
+
+		When("user is empty", func() {
+			BeforeEach(func() {
+				r.Header.Del("X-Spinnaker-User")
+			})
+
+			It("returns status unauthorized", func() {
+				Expect(c.Writer.Status()).To(Equal(http.StatusUnauthorized))
+				Expect(c.Errors[0].Error()).To(Equal("no X-Spinnaker-User header found"))
+				Expect(c.IsAborted()).To(BeTrue())
+				Expect(fakeFiatClient.AuthorizeCallCount()).To(BeZero())
+			})
+		})
+
+		When("fiatClient.Authorize returns an error", func() {
+			BeforeEach(func() {
+				fakeFiatClient.AuthorizeReturns(fiat.Response{}, errors.New("fake error"))
+			})
+
+			It("returns status unauthorized", func() {
+				Expect(c.Writer.Status()).To(Equal(http.StatusUnauthorized))
+				Expect(c.Errors[0].Error()).To(Equal("fake error"))
+			})
+		})
+
+		When("the user is not an admin", func() {
+			BeforeEach(func() {
+				fakeFiatClient.AuthorizeReturns(fiat.Response{Name: testUser}, nil)
+			})
+
+			It("returns status forbidden", func() {
+				Expect(c.Writer.Status()).To(Equal(http.StatusForbidden))
+				Expect(c.Errors[0].Error()).To(Equal("user test-user is not an admin"))
+				Expect(c.IsAborted()).To(BeTrue())
+			})
+		})
+
+		When("the user is an admin", func() {
+			BeforeEach(func() {
+				fakeFiatClient.AuthorizeReturns(fiat.Response{Name: testUser, Admin: true}, nil)
+			})
+
+			It("returns status OK", func() {
+				Expect(c.Writer.Status()).To(Equal(http.StatusOK))
+				Expect(c.IsAborted()).To(BeFalse())
+			})
+		})
+	})
+
 	Describe("#FilterAuthorizedApplications", func() {
 		BeforeEach(func() {
 			hf = PostFilterAuthorizedApplications("READ")
@@ -269,6 +710,90 @@ var _ = Describe("Auth", func() {
 		})
 	})
 
+	Describe("#PostFilterAuthorizedStats", func() {
+		var (
+			w     *httptest.ResponseRecorder
+			stats core.DeployStats
+		)
+
+		BeforeEach(func() {
+			w = httptest.NewRecorder()
+			c, _ = gin.CreateTestContext(w)
+			c.Set(fiat.ClientInstanceKey, fakeFiatClient)
+			c.Set(sql.ReadOnlyClientInstanceKey, fakeSQLClient)
+			c.Request = r
+			c.Set(core.KeyStats, core.DeployStats{
+				Applications: []core.ApplicationDeployStats{
+					{Application: "test-app1"},
+					{Application: "test-app2"},
+				},
+				Namespaces: []core.NamespaceDeployStats{
+					{Account: testAccount, Namespace: "default"},
+					{Account: testAccount, Namespace: "team-a"},
+					{Account: "other-account", Namespace: "default"},
+				},
+			})
+			fakeFiatClient.AuthorizeReturns(fiat.Response{
+				Accounts: []fiat.Account{
+					{Name: testAccount, Authorizations: []string{"READ"}},
+				},
+				Applications: []fiat.Application{
+					{Name: "test-app1", Authorizations: []string{"READ"}},
+				},
+			}, nil)
+			hf = PostFilterAuthorizedStats("READ")
+			stats = core.DeployStats{}
+		})
+
+		JustBeforeEach(func() {
+			hf(c)
+
+			if w.Code == http.StatusOK {
+				Expect(json.Unmarshal(w.Body.Bytes(), &stats)).To(Succeed())
+			}
+		})
+
+		When("the user is missing from the header", func() {
+			BeforeEach(func() {
+				r.Header.Del("X-Spinnaker-User")
+			})
+
+			It("returns the stats without filtering", func() {
+				Expect(w.Code).To(Equal(http.StatusOK))
+				Expect(stats.Applications).To(HaveLen(2))
+				Expect(stats.Namespaces).To(HaveLen(3))
+			})
+		})
+
+		When("the groups of a namespace do not include the user", func() {
+			BeforeEach(func() {
+				fakeSQLClient.ListPermissionsByAccountNamesReturns(map[string]kubernetes.ProviderPermissions{
+					testAccount: {
+						Namespaces: map[string]kubernetes.ProviderNamespacePermissions{
+							"team-a": {Read: []string{"team-a"}},
+						},
+					},
+				}, nil)
+			})
+
+			It("does not return the stats of the namespace", func() {
+				Expect(w.Code).To(Equal(http.StatusOK))
+				Expect(stats.Namespaces).To(Equal([]core.NamespaceDeployStats{
+					{Account: testAccount, Namespace: "default"},
+				}))
+			})
+		})
+
+		It("only returns the stats of the applications and accounts Fiat lists the user may read", func() {
+			Expect(w.Code).To(Equal(http.StatusOK))
+			Expect(stats.Applications).To(Equal([]core.ApplicationDeployStats{{Application: "test-app1"}}))
+			Expect(stats.Namespaces).To(Equal([]core.NamespaceDeployStats{
+				{Account: testAccount, Namespace: "default"},
+				{Account: testAccount, Namespace: "team-a"},
+			}))
+		})
+	})
+
 	Describe("#FilterAuthorizedApps", func() {
 		BeforeEach(func() {
 			allApps = []core.Application{}
@@ -0,0 +1,60 @@
+package middleware
+
+import (
+	"context"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// DefaultRequestTimeout is how long handlers have to respond, if not
+// configured.
+const DefaultRequestTimeout = 5 * time.Minute
+
+var (
+	timedOutRequests = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "clouddriver_requests_timed_out_total",
+		Help: "Number of requests whose deadline passed before they were handled by route.",
+	}, []string{"route"})
+)
+
+func init() {
+	prometheus.MustRegister(timedOutRequests)
+}
+
+// streamingRoutes respond for as long as the client listens, so they are
+// not given a deadline.
+var streamingRoutes = map[string]bool{
+	"/applications/:application/jobs/:account/:location/:name/logs": true,
+	"/task/:id/stream": true,
+}
+
+// Deadline sets a deadline of timeout on the context of requests, so the
+// SQL queries and Kubernetes API requests made while handling them are
+// cancelled instead of holding on to connections when a dependency hangs.
+// It must be used before the middleware setting the SQL client and kube
+// controller, which bind them to the context of the request.
+func Deadline(timeout time.Duration) gin.HandlerFunc {
+	if timeout <= 0 {
+		timeout = DefaultRequestTimeout
+	}
+
+	return func(c *gin.Context) {
+		if streamingRoutes[unversioned(c.FullPath())] {
+			c.Next()
+			return
+		}
+
+		ctx, cancel := context.WithTimeout(c.Request.Context(), timeout)
+		defer cancel()
+
+		c.Request = c.Request.WithContext(ctx)
+
+		c.Next()
+
+		if ctx.Err() == context.DeadlineExceeded {
+			timedOutRequests.WithLabelValues(c.FullPath()).Inc()
+		}
+	}
+}
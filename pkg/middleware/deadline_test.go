@@ -0,0 +1,108 @@
+package middleware_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"time"
+
+	clouddriver "github.com/billiford/go-clouddriver/pkg"
+	. "github.com/billiford/go-clouddriver/pkg/middleware"
+	"github.com/gin-gonic/gin"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Deadline", func() {
+	var (
+		e        *gin.Engine
+		recorder *httptest.ResponseRecorder
+		req      *http.Request
+		timeout  time.Duration
+		deadline time.Time
+		ok       bool
+	)
+
+	BeforeEach(func() {
+		gin.SetMode(gin.ReleaseMode)
+		e = gin.New()
+		recorder = httptest.NewRecorder()
+		req, _ = http.NewRequest(http.MethodGet, "/credentials", nil)
+		timeout = 0
+		deadline, ok = time.Time{}, false
+	})
+
+	JustBeforeEach(func() {
+		e.Use(Deadline(timeout))
+		e.Use(HandleError())
+		e.GET("/credentials", func(c *gin.Context) {
+			deadline, ok = c.Request.Context().Deadline()
+
+			if c.Query("wait") == "true" {
+				<-c.Request.Context().Done()
+				clouddriver.WriteError(c, http.StatusInternalServerError, c.Request.Context().Err())
+
+				return
+			}
+
+			c.Status(http.StatusOK)
+		})
+		e.GET("/task/:id/stream", func(c *gin.Context) {
+			deadline, ok = c.Request.Context().Deadline()
+			c.Status(http.StatusOK)
+		})
+		e.GET("/v1/task/:id/stream", func(c *gin.Context) {
+			deadline, ok = c.Request.Context().Deadline()
+			c.Status(http.StatusOK)
+		})
+
+		e.ServeHTTP(recorder, req)
+	})
+
+	When("the timeout is not set", func() {
+		It("sets the default deadline", func() {
+			Expect(ok).To(BeTrue())
+			Expect(time.Until(deadline)).To(BeNumerically("~", DefaultRequestTimeout, time.Minute))
+			Expect(recorder.Code).To(Equal(http.StatusOK))
+		})
+	})
+
+	When("the deadline passes", func() {
+		BeforeEach(func() {
+			timeout = 10 * time.Millisecond
+			req, _ = http.NewRequest(http.MethodGet, "/credentials?wait=true", nil)
+		})
+
+		It("responds with status gateway timeout", func() {
+			Expect(ok).To(BeTrue())
+			Expect(recorder.Code).To(Equal(http.StatusGatewayTimeout))
+
+			ce := clouddriver.Error{}
+			Expect(json.Unmarshal(recorder.Body.Bytes(), &ce)).To(Succeed())
+			Expect(ce.Error).To(Equal("Gateway Timeout"))
+			Expect(ce.Message).To(Equal("context deadline exceeded"))
+		})
+	})
+
+	When("the route streams", func() {
+		BeforeEach(func() {
+			req, _ = http.NewRequest(http.MethodGet, "/task/test-task-id/stream", nil)
+		})
+
+		It("sets no deadline", func() {
+			Expect(ok).To(BeFalse())
+			Expect(recorder.Code).To(Equal(http.StatusOK))
+		})
+	})
+
+	When("the route streams under a version", func() {
+		BeforeEach(func() {
+			req, _ = http.NewRequest(http.MethodGet, "/v1/task/test-task-id/stream", nil)
+		})
+
+		It("sets no deadline", func() {
+			Expect(ok).To(BeFalse())
+			Expect(recorder.Code).To(Equal(http.StatusOK))
+		})
+	})
+})
@@ -1,6 +1,8 @@
 package middleware
 
 import (
+	"context"
+	"errors"
 	"net/http"
 
 	clouddriver "github.com/billiford/go-clouddriver/pkg"
@@ -15,12 +17,25 @@ func HandleError() gin.HandlerFunc {
 		err := c.Errors.ByType(gin.ErrorTypePublic).Last()
 		if err != nil {
 			statusCode := c.Writer.Status()
+			// Requests failing as their deadline passed timed out waiting
+			// on a dependency, such as an API server.
+			if c.Request.Context().Err() == context.DeadlineExceeded {
+				statusCode = http.StatusGatewayTimeout
+			}
+
 			ce := clouddriver.NewError(
 				http.StatusText(statusCode),
 				err.Error(),
 				statusCode,
 			)
-			c.JSON(c.Writer.Status(), ce)
+
+			var ade *clouddriver.AccessDeniedError
+			if errors.As(err.Err, &ade) {
+				ce.RequiredAuthorization = ade.RequiredAuthorization
+				ce.RequiredGroups = ade.RequiredGroups
+			}
+
+			c.JSON(statusCode, ce)
 		}
 	}
 }
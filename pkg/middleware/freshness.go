@@ -0,0 +1,30 @@
+package middleware
+
+import (
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+const (
+	// HeaderDataSource is "live" for responses read from their source
+	// during the request, "cache" for responses read from a cache, or
+	// "snapshot" for responses read from a snapshot of a past response.
+	HeaderDataSource = `X-Clouddriver-Data-Source`
+	// HeaderReadAt is the time the data of a response was read from its source.
+	HeaderReadAt = `X-Clouddriver-Read-At`
+
+	dataSourceLive     = `live`
+	dataSourceSnapshot = `snapshot`
+)
+
+// LiveData marks responses of endpoints that read from their source, such as
+// the Kubernetes API, on every request, so clients can tell how old the data
+// they are seeing is.
+func LiveData() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		c.Header(HeaderDataSource, dataSourceLive)
+		c.Header(HeaderReadAt, time.Now().UTC().Format(time.RFC3339))
+		c.Next()
+	}
+}
@@ -0,0 +1,38 @@
+package middleware_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"time"
+
+	. "github.com/billiford/go-clouddriver/pkg/middleware"
+	"github.com/gin-gonic/gin"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Freshness", func() {
+	var w *httptest.ResponseRecorder
+
+	BeforeEach(func() {
+		gin.SetMode(gin.ReleaseMode)
+		w = httptest.NewRecorder()
+		c, _ = gin.CreateTestContext(w)
+		c.Request, _ = http.NewRequest(http.MethodGet, "", nil)
+	})
+
+	Describe("#LiveData", func() {
+		JustBeforeEach(func() {
+			LiveData()(c)
+			c.Status(http.StatusOK)
+			c.Writer.WriteHeaderNow()
+		})
+
+		It("marks the response as live", func() {
+			Expect(w.Header().Get(HeaderDataSource)).To(Equal("live"))
+			readAt, err := time.Parse(time.RFC3339, w.Header().Get(HeaderReadAt))
+			Expect(err).To(BeNil())
+			Expect(readAt).To(BeTemporally("~", time.Now(), 2*time.Second))
+		})
+	})
+})
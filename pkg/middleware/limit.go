@@ -0,0 +1,202 @@
+package middleware
+
+import (
+	"fmt"
+	"math"
+	"net"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	clouddriver "github.com/billiford/go-clouddriver/pkg"
+	"github.com/gin-gonic/gin"
+	"github.com/prometheus/client_golang/prometheus"
+	"golang.org/x/time/rate"
+)
+
+// Limiters of clients idle for this long are dropped.
+const clientLimiterIdleTTL = 10 * time.Minute
+
+var (
+	rejectedRequests = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "clouddriver_requests_rejected_total",
+		Help: "Number of requests rejected by source IP, body size, client rate limits or load shedding by reason.",
+	}, []string{"reason"})
+)
+
+func init() {
+	prometheus.MustRegister(rejectedRequests)
+}
+
+// ParseCIDRs parses a comma separated list of CIDRs, such as
+// "10.0.0.0/8,192.168.1.10". IPs without a prefix length match only themselves.
+func ParseCIDRs(s string) ([]*net.IPNet, error) {
+	nets := []*net.IPNet{}
+
+	for _, cidr := range strings.Split(s, ",") {
+		cidr = strings.TrimSpace(cidr)
+		if cidr == "" {
+			continue
+		}
+
+		if !strings.Contains(cidr, "/") {
+			ip := net.ParseIP(cidr)
+			if ip == nil {
+				return nil, fmt.Errorf("invalid IP %q", cidr)
+			}
+
+			bits := 8 * net.IPv6len
+			if ip.To4() != nil {
+				bits = 8 * net.IPv4len
+			}
+
+			cidr = fmt.Sprintf("%s/%d", cidr, bits)
+		}
+
+		_, n, err := net.ParseCIDR(cidr)
+		if err != nil {
+			return nil, fmt.Errorf("invalid CIDR %q: %w", cidr, err)
+		}
+
+		nets = append(nets, n)
+	}
+
+	return nets, nil
+}
+
+// AllowIPs rejects requests from source IPs outside of nets with 403 Forbidden.
+// The source IP is the remote address of the connection; X-Forwarded-For is
+// ignored, as any caller could set it.
+func AllowIPs(nets []*net.IPNet) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if exemptPaths[unversioned(c.Request.URL.Path)] {
+			c.Next()
+			return
+		}
+
+		ip := remoteIP(c.Request)
+
+		for _, n := range nets {
+			if ip != nil && n.Contains(ip) {
+				c.Next()
+				return
+			}
+		}
+
+		rejectedRequests.WithLabelValues("ip").Inc()
+		clouddriver.WriteError(c, http.StatusForbidden, fmt.Errorf("source IP %s is not allowed", ip))
+		c.Abort()
+	}
+}
+
+func remoteIP(r *http.Request) net.IP {
+	host, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		host = r.RemoteAddr
+	}
+
+	return net.ParseIP(host)
+}
+
+// LimitRequestBody rejects requests with bodies larger than max bytes with
+// 413 Request Entity Too Large. Bodies without a content length are cut off
+// at max bytes, failing the handler reading them.
+func LimitRequestBody(max int64) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if c.Request.ContentLength > max {
+			rejectedRequests.WithLabelValues("body_size").Inc()
+			clouddriver.WriteError(c, http.StatusRequestEntityTooLarge,
+				fmt.Errorf("request body of %d bytes exceeds the limit of %d bytes", c.Request.ContentLength, max))
+			c.Abort()
+
+			return
+		}
+
+		if c.Request.Body != nil {
+			c.Request.Body = http.MaxBytesReader(c.Writer, c.Request.Body, max)
+		}
+
+		c.Next()
+	}
+}
+
+// RateLimitClients rejects requests of clients over r requests per second,
+// with bursts of up to burst requests, with 429 Too Many Requests. Clients
+// are told by their X-Spinnaker-User header, or else their source IP, so
+// users behind the same Gate are limited separately.
+func RateLimitClients(r float64, burst int) gin.HandlerFunc {
+	if burst <= 0 {
+		burst = int(math.Max(1, math.Ceil(r)))
+	}
+
+	cl := &clientLimiters{
+		limit:    rate.Limit(r),
+		burst:    burst,
+		limiters: map[string]*clientLimiter{},
+		swept:    time.Now(),
+	}
+
+	return func(c *gin.Context) {
+		if exemptPaths[unversioned(c.Request.URL.Path)] {
+			c.Next()
+			return
+		}
+
+		client := c.GetHeader(headerSpinnakerUser)
+		if client == "" {
+			client = remoteIP(c.Request).String()
+		}
+
+		if !cl.allow(client, time.Now()) {
+			rejectedRequests.WithLabelValues("rate_limit").Inc()
+			c.Header("Retry-After", strconv.Itoa(int(math.Ceil(1/r))))
+			clouddriver.WriteError(c, http.StatusTooManyRequests, fmt.Errorf("client %s exceeded the rate limit", client))
+			c.Abort()
+
+			return
+		}
+
+		c.Next()
+	}
+}
+
+type clientLimiters struct {
+	mux      sync.Mutex
+	limit    rate.Limit
+	burst    int
+	limiters map[string]*clientLimiter
+	swept    time.Time
+}
+
+type clientLimiter struct {
+	limiter *rate.Limiter
+	seen    time.Time
+}
+
+func (cl *clientLimiters) allow(client string, now time.Time) bool {
+	cl.mux.Lock()
+	defer cl.mux.Unlock()
+
+	// Drop the limiters of idle clients so they do not pile up.
+	if now.Sub(cl.swept) > clientLimiterIdleTTL {
+		for k, l := range cl.limiters {
+			if now.Sub(l.seen) > clientLimiterIdleTTL {
+				delete(cl.limiters, k)
+			}
+		}
+
+		cl.swept = now
+	}
+
+	l, ok := cl.limiters[client]
+	if !ok {
+		l = &clientLimiter{limiter: rate.NewLimiter(cl.limit, cl.burst)}
+		cl.limiters[client] = l
+	}
+
+	l.seen = now
+
+	return l.limiter.AllowN(now, 1)
+}
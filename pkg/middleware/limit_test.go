@@ -0,0 +1,199 @@
+package middleware_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+
+	. "github.com/billiford/go-clouddriver/pkg/middleware"
+	"github.com/gin-gonic/gin"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Limit", func() {
+	var (
+		e        *gin.Engine
+		recorder *httptest.ResponseRecorder
+		req      *http.Request
+		// Served before req, if set.
+		prior *http.Request
+	)
+
+	BeforeEach(func() {
+		gin.SetMode(gin.ReleaseMode)
+		e = gin.New()
+		e.Use(HandleError())
+		recorder = httptest.NewRecorder()
+		req, _ = http.NewRequest(http.MethodGet, "/credentials", nil)
+		req.RemoteAddr = "10.1.2.3:54321"
+		prior = nil
+	})
+
+	JustBeforeEach(func() {
+		e.GET("/credentials", func(c *gin.Context) { c.Status(http.StatusOK) })
+		e.GET("/health", func(c *gin.Context) { c.Status(http.StatusOK) })
+		e.POST("/kubernetes/ops", func(c *gin.Context) {
+			m := map[string]interface{}{}
+
+			err := c.ShouldBindJSON(&m)
+			if err != nil {
+				c.Status(http.StatusBadRequest)
+				return
+			}
+
+			c.Status(http.StatusOK)
+		})
+
+		if prior != nil {
+			e.ServeHTTP(httptest.NewRecorder(), prior)
+		}
+
+		e.ServeHTTP(recorder, req)
+	})
+
+	Describe("#ParseCIDRs", func() {
+		It("parses CIDRs and IPs", func() {
+			nets, err := ParseCIDRs("10.0.0.0/8, 192.168.1.10,,::1")
+			Expect(err).To(BeNil())
+			Expect(nets).To(HaveLen(3))
+			Expect(nets[0].String()).To(Equal("10.0.0.0/8"))
+			Expect(nets[1].String()).To(Equal("192.168.1.10/32"))
+			Expect(nets[2].String()).To(Equal("::1/128"))
+		})
+
+		It("returns an error for an invalid IP", func() {
+			_, err := ParseCIDRs("10.0.0.0/8,not-an-ip")
+			Expect(err).ToNot(BeNil())
+			Expect(err.Error()).To(Equal(`invalid IP "not-an-ip"`))
+		})
+
+		It("returns an error for an invalid CIDR", func() {
+			_, err := ParseCIDRs("10.0.0.0/99")
+			Expect(err).ToNot(BeNil())
+			Expect(err.Error()).To(HavePrefix(`invalid CIDR "10.0.0.0/99"`))
+		})
+	})
+
+	Describe("#AllowIPs", func() {
+		BeforeEach(func() {
+			nets, err := ParseCIDRs("10.0.0.0/8")
+			Expect(err).To(BeNil())
+			e.Use(AllowIPs(nets))
+		})
+
+		When("the source IP is allowed", func() {
+			It("calls the handler", func() {
+				Expect(recorder.Code).To(Equal(http.StatusOK))
+			})
+		})
+
+		When("the source IP is not allowed", func() {
+			BeforeEach(func() {
+				req.RemoteAddr = "192.168.1.10:54321"
+				req.Header.Set("X-Forwarded-For", "10.1.2.3")
+			})
+
+			It("returns status forbidden", func() {
+				Expect(recorder.Code).To(Equal(http.StatusForbidden))
+				Expect(recorder.Body.String()).To(ContainSubstring("source IP 192.168.1.10 is not allowed"))
+			})
+		})
+
+		When("the path is exempt", func() {
+			BeforeEach(func() {
+				req, _ = http.NewRequest(http.MethodGet, "/health", nil)
+				req.RemoteAddr = "192.168.1.10:54321"
+			})
+
+			It("calls the handler", func() {
+				Expect(recorder.Code).To(Equal(http.StatusOK))
+			})
+		})
+	})
+
+	Describe("#LimitRequestBody", func() {
+		BeforeEach(func() {
+			e.Use(LimitRequestBody(16))
+			req, _ = http.NewRequest(http.MethodPost, "/kubernetes/ops", strings.NewReader(`{"a":"b"}`))
+		})
+
+		When("the body is within the limit", func() {
+			It("calls the handler", func() {
+				Expect(recorder.Code).To(Equal(http.StatusOK))
+			})
+		})
+
+		When("the content length exceeds the limit", func() {
+			BeforeEach(func() {
+				req, _ = http.NewRequest(http.MethodPost, "/kubernetes/ops", strings.NewReader(`{"a":"bbbbbbbbbbbbbbbb"}`))
+			})
+
+			It("returns status request entity too large", func() {
+				Expect(recorder.Code).To(Equal(http.StatusRequestEntityTooLarge))
+				Expect(recorder.Body.String()).To(ContainSubstring("request body of 24 bytes exceeds the limit of 16 bytes"))
+			})
+		})
+
+		When("the body has no content length and exceeds the limit", func() {
+			BeforeEach(func() {
+				req, _ = http.NewRequest(http.MethodPost, "/kubernetes/ops", strings.NewReader(`{"a":"bbbbbbbbbbbbbbbb"}`))
+				req.ContentLength = -1
+			})
+
+			It("fails reading the body", func() {
+				Expect(recorder.Code).To(Equal(http.StatusBadRequest))
+			})
+		})
+	})
+
+	Describe("#RateLimitClients", func() {
+		BeforeEach(func() {
+			e.Use(RateLimitClients(0.5, 1))
+		})
+
+		When("the client is within the rate limit", func() {
+			It("calls the handler", func() {
+				Expect(recorder.Code).To(Equal(http.StatusOK))
+			})
+		})
+
+		When("the client exceeds the rate limit", func() {
+			BeforeEach(func() {
+				prior, _ = http.NewRequest(http.MethodGet, "/credentials", nil)
+				prior.RemoteAddr = "10.1.2.3:54321"
+			})
+
+			It("returns status too many requests", func() {
+				Expect(recorder.Code).To(Equal(http.StatusTooManyRequests))
+				Expect(recorder.Header().Get("Retry-After")).To(Equal("2"))
+				Expect(recorder.Body.String()).To(ContainSubstring("client 10.1.2.3 exceeded the rate limit"))
+			})
+		})
+
+		When("another user makes a request", func() {
+			BeforeEach(func() {
+				prior, _ = http.NewRequest(http.MethodGet, "/credentials", nil)
+				prior.RemoteAddr = "10.1.2.3:54321"
+				req.Header.Set("X-Spinnaker-User", "someone-else@example.com")
+			})
+
+			It("limits them separately", func() {
+				Expect(recorder.Code).To(Equal(http.StatusOK))
+			})
+		})
+
+		When("the path is exempt", func() {
+			BeforeEach(func() {
+				prior, _ = http.NewRequest(http.MethodGet, "/credentials", nil)
+				prior.RemoteAddr = "10.1.2.3:54321"
+				req, _ = http.NewRequest(http.MethodGet, "/health", nil)
+				req.RemoteAddr = "10.1.2.3:54321"
+			})
+
+			It("calls the handler", func() {
+				Expect(recorder.Code).To(Equal(http.StatusOK))
+			})
+		})
+	})
+})
@@ -3,10 +3,22 @@ package middleware
 import (
 	"github.com/billiford/go-clouddriver/pkg/arcade"
 	"github.com/billiford/go-clouddriver/pkg/artifact"
+	"github.com/billiford/go-clouddriver/pkg/docker"
 	"github.com/billiford/go-clouddriver/pkg/fiat"
+	"github.com/billiford/go-clouddriver/pkg/freeze"
+	"github.com/billiford/go-clouddriver/pkg/hook"
+	"github.com/billiford/go-clouddriver/pkg/http/core"
 	kube "github.com/billiford/go-clouddriver/pkg/http/core/kubernetes"
 	"github.com/billiford/go-clouddriver/pkg/kubernetes"
+	"github.com/billiford/go-clouddriver/pkg/lock"
+	"github.com/billiford/go-clouddriver/pkg/notify"
+	"github.com/billiford/go-clouddriver/pkg/ondemand"
+	"github.com/billiford/go-clouddriver/pkg/project"
+	"github.com/billiford/go-clouddriver/pkg/queue"
+	"github.com/billiford/go-clouddriver/pkg/recorder"
+	"github.com/billiford/go-clouddriver/pkg/snapshot"
 	"github.com/billiford/go-clouddriver/pkg/sql"
+	"github.com/billiford/go-clouddriver/pkg/webhook"
 	"github.com/gin-gonic/gin"
 )
 
@@ -19,7 +31,15 @@ func SetArcadeClient(a arcade.Client) gin.HandlerFunc {
 
 func SetSQLClient(r sql.Client) gin.HandlerFunc {
 	return func(c *gin.Context) {
-		c.Set(sql.ClientInstanceKey, r)
+		// Cancel queries when the request is done.
+		c.Set(sql.ClientInstanceKey, r.WithContext(c.Request.Context()))
+		c.Next()
+	}
+}
+
+func SetSQLReadOnlyClient(r sql.Client) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		c.Set(sql.ReadOnlyClientInstanceKey, r.WithContext(c.Request.Context()))
 		c.Next()
 	}
 }
@@ -31,9 +51,28 @@ func SetFiatClient(f fiat.Client) gin.HandlerFunc {
 	}
 }
 
+func SetGroupResolver(gr fiat.GroupResolver) gin.HandlerFunc {
+	gp := fiat.NewGroupPermissions()
+
+	return func(c *gin.Context) {
+		if gr != nil {
+			c.Set(fiat.GroupResolverInstanceKey, gr)
+			c.Set(fiat.GroupPermissionsInstanceKey, gp)
+		}
+
+		c.Next()
+	}
+}
+
 func SetKubeController(k kubernetes.Controller) gin.HandlerFunc {
 	return func(c *gin.Context) {
-		c.Set(kubernetes.ControllerInstanceKey, k)
+		ck := k
+		if ck != nil {
+			// Cancel requests to API servers when the request is done.
+			ck = k.WithContext(c.Request.Context())
+		}
+
+		c.Set(kubernetes.ControllerInstanceKey, ck)
 		c.Next()
 	}
 }
@@ -51,3 +90,108 @@ func SetKubeActionHandler(k kube.ActionHandler) gin.HandlerFunc {
 		c.Next()
 	}
 }
+
+func SetKubeNamespaceCache(n kubernetes.NamespaceCache) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		c.Set(kubernetes.NamespaceCacheInstanceKey, n)
+		c.Next()
+	}
+}
+
+func SetKubePermissionsCache(p kubernetes.PermissionsCache) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		c.Set(kubernetes.PermissionsCacheInstanceKey, p)
+		c.Next()
+	}
+}
+
+func SetDockerCredentialsController(d docker.CredentialsController) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		c.Set(docker.CredentialsControllerInstanceKey, d)
+		c.Next()
+	}
+}
+
+func SetFreezeController(f freeze.Controller) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		c.Set(freeze.ControllerInstanceKey, f)
+		c.Next()
+	}
+}
+
+func SetProjectController(p project.Controller) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		c.Set(project.ControllerInstanceKey, p)
+		c.Next()
+	}
+}
+
+func SetRecorder(r recorder.Recorder) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		c.Set(recorder.InstanceKey, r)
+		c.Next()
+	}
+}
+
+func SetNotifier(n notify.Notifier) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		c.Set(notify.InstanceKey, n)
+		c.Next()
+	}
+}
+
+func SetHookRunner(r hook.Runner) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		c.Set(hook.InstanceKey, r)
+		c.Next()
+	}
+}
+
+func SetStabilityChecker(sc hook.StabilityChecker) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		c.Set(hook.StabilityCheckerInstanceKey, sc)
+		c.Next()
+	}
+}
+
+func SetQueue(q queue.Queue) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		c.Set(queue.InstanceKey, q)
+		c.Next()
+	}
+}
+
+func SetLocker(l lock.Locker) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		c.Set(lock.InstanceKey, l)
+		c.Next()
+	}
+}
+
+func SetOnDemandRefreshes(r ondemand.Refreshes) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		c.Set(ondemand.InstanceKey, r)
+		c.Next()
+	}
+}
+
+func SetSnapshotter(s snapshot.Snapshotter) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		c.Set(snapshot.InstanceKey, s)
+		c.Next()
+	}
+}
+
+func SetWebhookSecrets(s webhook.Secrets) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		c.Set(webhook.InstanceKey, s)
+		c.Next()
+	}
+}
+
+func SetWatchRollouts(w bool) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		c.Set(core.KeyWatchRollouts, w)
+		c.Next()
+	}
+}
@@ -0,0 +1,72 @@
+package middleware
+
+import (
+	"bytes"
+	"io/ioutil"
+	"time"
+
+	"github.com/billiford/go-clouddriver/pkg/kubernetes"
+	"github.com/billiford/go-clouddriver/pkg/recorder"
+	"github.com/gin-gonic/gin"
+)
+
+// bodyWriter keeps a copy of the response body.
+type bodyWriter struct {
+	gin.ResponseWriter
+	body *bytes.Buffer
+}
+
+func (w bodyWriter) Write(b []byte) (int, error) {
+	w.body.Write(b)
+	return w.ResponseWriter.Write(b)
+}
+
+func (w bodyWriter) WriteString(s string) (int, error) {
+	w.body.WriteString(s)
+	return w.ResponseWriter.WriteString(s)
+}
+
+// RecordRequest records sanitized requests and responses along with the
+// Kubernetes calls made to serve them. Only requests made on behalf of a
+// pipeline execution are recorded.
+func RecordRequest() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		r := recorder.Instance(c)
+		executionID := c.GetHeader(recorder.HeaderSpinnakerExecutionID)
+
+		if r == nil || executionID == "" {
+			c.Next()
+			return
+		}
+
+		b, _ := ioutil.ReadAll(c.Request.Body)
+		c.Request.Body = ioutil.NopCloser(bytes.NewReader(b))
+
+		rec := &recorder.Recording{
+			ExecutionID: executionID,
+			Time:        time.Now().UTC(),
+			Request: recorder.Request{
+				Method:  c.Request.Method,
+				URL:     c.Request.URL.String(),
+				Headers: recorder.SanitizeHeaders(c.Request.Header),
+				Body:    recorder.SanitizeBody(b),
+			},
+			KubernetesCalls: []recorder.KubernetesCall{},
+		}
+
+		w := bodyWriter{ResponseWriter: c.Writer, body: &bytes.Buffer{}}
+		c.Writer = w
+
+		c.Set(kubernetes.ControllerInstanceKey, recorder.NewController(kubernetes.ControllerInstance(c), rec))
+
+		c.Next()
+
+		rec.Duration = time.Since(rec.Time)
+		rec.Response = recorder.Response{
+			Status: c.Writer.Status(),
+			Body:   recorder.SanitizeBody(w.body.Bytes()),
+		}
+
+		r.Record(rec)
+	}
+}
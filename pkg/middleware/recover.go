@@ -0,0 +1,48 @@
+package middleware
+
+import (
+	"net/http"
+
+	clouddriver "github.com/billiford/go-clouddriver/pkg"
+	"github.com/billiford/go-clouddriver/pkg/routine"
+	"github.com/gin-gonic/gin"
+)
+
+// Recovery recovers from panics in handlers, responding with 500 Internal
+// Server Error and the panic as a clouddriver error. Panics are logged with
+// their stack and counted by route. Panics aborting the response on purpose
+// with http.ErrAbortHandler are left to the server.
+func Recovery() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		defer func() {
+			v := recover()
+			if v == nil {
+				return
+			}
+
+			if v == http.ErrAbortHandler {
+				panic(v)
+			}
+
+			where := c.FullPath()
+			if where == "" {
+				where = "unknown route"
+			}
+
+			pe := routine.Recovered(where, v)
+
+			if c.Writer.Written() {
+				c.Abort()
+				return
+			}
+
+			c.AbortWithStatusJSON(http.StatusInternalServerError, clouddriver.NewError(
+				http.StatusText(http.StatusInternalServerError),
+				pe.Error(),
+				http.StatusInternalServerError,
+			))
+		}()
+
+		c.Next()
+	}
+}
@@ -0,0 +1,80 @@
+package middleware_test
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"log"
+	"net/http"
+	"net/http/httptest"
+
+	clouddriver "github.com/billiford/go-clouddriver/pkg"
+	. "github.com/billiford/go-clouddriver/pkg/middleware"
+	"github.com/gin-gonic/gin"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Recovery", func() {
+	var (
+		e        *gin.Engine
+		recorder *httptest.ResponseRecorder
+		req      *http.Request
+	)
+
+	BeforeEach(func() {
+		log.SetOutput(ioutil.Discard)
+		gin.SetMode(gin.ReleaseMode)
+		e = gin.New()
+		e.Use(Recovery())
+		recorder = httptest.NewRecorder()
+		req, _ = http.NewRequest(http.MethodGet, "/applications/test-app", nil)
+	})
+
+	JustBeforeEach(func() {
+		e.ServeHTTP(recorder, req)
+	})
+
+	When("the handler panics", func() {
+		BeforeEach(func() {
+			e.GET("/applications/:application", func(c *gin.Context) {
+				var m map[string]string
+				m["application"] = c.Param("application")
+			})
+		})
+
+		It("responds with the panic as an error", func() {
+			Expect(recorder.Code).To(Equal(http.StatusInternalServerError))
+
+			ce := clouddriver.Error{}
+			Expect(json.Unmarshal(recorder.Body.Bytes(), &ce)).To(Succeed())
+			Expect(ce.Error).To(Equal("Internal Server Error"))
+			Expect(ce.Message).To(Equal("panic: assignment to entry in nil map"))
+		})
+	})
+
+	When("the handler already responded", func() {
+		BeforeEach(func() {
+			e.GET("/applications/:application", func(c *gin.Context) {
+				c.String(http.StatusOK, "partial")
+				panic("after responding")
+			})
+		})
+
+		It("leaves the response as it is", func() {
+			Expect(recorder.Code).To(Equal(http.StatusOK))
+			Expect(recorder.Body.String()).To(Equal("partial"))
+		})
+	})
+
+	When("the handler does not panic", func() {
+		BeforeEach(func() {
+			e.GET("/applications/:application", func(c *gin.Context) {
+				c.Status(http.StatusNoContent)
+			})
+		})
+
+		It("does nothing", func() {
+			Expect(recorder.Code).To(Equal(http.StatusNoContent))
+		})
+	})
+})
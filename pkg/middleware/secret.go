@@ -0,0 +1,72 @@
+package middleware
+
+import (
+	"bytes"
+	"crypto/subtle"
+	"errors"
+	"io/ioutil"
+	"net/http"
+
+	clouddriver "github.com/billiford/go-clouddriver/pkg"
+	"github.com/billiford/go-clouddriver/pkg/webhook"
+	"github.com/gin-gonic/gin"
+)
+
+// HeaderSharedSecret holds the secret shared with the callers of the API,
+// such as Gate. It is prefixed X-Spinnaker so gRPC calls forward it too.
+const HeaderSharedSecret = `X-Spinnaker-Shared-Secret`
+
+var errInvalidSharedSecret = errors.New("missing or invalid " + HeaderSharedSecret + " header")
+
+// Health checks are made by the kubelet, so they are exempt from the checks
+// of callers, which it could not pass.
+var exemptPaths = map[string]bool{
+	"/health": true,
+}
+
+// RequireSharedSecret rejects requests that do not send secret in the
+// X-Spinnaker-Shared-Secret header with 401 Unauthorized.
+func RequireSharedSecret(secret string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if exemptPaths[unversioned(c.Request.URL.Path)] {
+			c.Next()
+			return
+		}
+
+		given := c.GetHeader(HeaderSharedSecret)
+		if subtle.ConstantTimeCompare([]byte(given), []byte(secret)) != 1 {
+			clouddriver.WriteError(c, http.StatusUnauthorized, errInvalidSharedSecret)
+			c.Abort()
+
+			return
+		}
+
+		c.Next()
+	}
+}
+
+// AuthWebhook rejects webhooks that are not authenticated with the secret of
+// their account with 401 Unauthorized, see webhook.Secrets.
+func AuthWebhook() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		b, err := ioutil.ReadAll(c.Request.Body)
+		if err != nil {
+			clouddriver.WriteError(c, http.StatusBadRequest, err)
+			c.Abort()
+
+			return
+		}
+
+		c.Request.Body = ioutil.NopCloser(bytes.NewReader(b))
+
+		err = webhook.Instance(c).Authenticate(c.Param("account"), c.Request, b)
+		if err != nil {
+			clouddriver.WriteError(c, http.StatusUnauthorized, err)
+			c.Abort()
+
+			return
+		}
+
+		c.Next()
+	}
+}
@@ -0,0 +1,73 @@
+package middleware_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+
+	. "github.com/billiford/go-clouddriver/pkg/middleware"
+	"github.com/gin-gonic/gin"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Secret", func() {
+	Describe("#RequireSharedSecret", func() {
+		var (
+			recorder *httptest.ResponseRecorder
+			req      *http.Request
+		)
+
+		BeforeEach(func() {
+			gin.SetMode(gin.ReleaseMode)
+			recorder = httptest.NewRecorder()
+			req, _ = http.NewRequest(http.MethodGet, "/credentials", nil)
+			req.Header.Set(HeaderSharedSecret, "test-secret")
+		})
+
+		JustBeforeEach(func() {
+			e := gin.New()
+			e.Use(HandleError())
+			e.Use(RequireSharedSecret("test-secret"))
+			e.GET("/credentials", func(c *gin.Context) { c.Status(http.StatusOK) })
+			e.GET("/health", func(c *gin.Context) { c.Status(http.StatusOK) })
+			e.ServeHTTP(recorder, req)
+		})
+
+		When("the secret is missing", func() {
+			BeforeEach(func() {
+				req.Header.Del(HeaderSharedSecret)
+			})
+
+			It("returns status unauthorized", func() {
+				Expect(recorder.Code).To(Equal(http.StatusUnauthorized))
+				Expect(recorder.Body.String()).To(ContainSubstring("missing or invalid X-Spinnaker-Shared-Secret header"))
+			})
+		})
+
+		When("the secret is wrong", func() {
+			BeforeEach(func() {
+				req.Header.Set(HeaderSharedSecret, "wrong-secret")
+			})
+
+			It("returns status unauthorized", func() {
+				Expect(recorder.Code).To(Equal(http.StatusUnauthorized))
+			})
+		})
+
+		When("the path is exempt", func() {
+			BeforeEach(func() {
+				req, _ = http.NewRequest(http.MethodGet, "/health", nil)
+			})
+
+			It("calls the handler", func() {
+				Expect(recorder.Code).To(Equal(http.StatusOK))
+			})
+		})
+
+		When("the secret matches", func() {
+			It("calls the handler", func() {
+				Expect(recorder.Code).To(Equal(http.StatusOK))
+			})
+		})
+	})
+})
@@ -0,0 +1,108 @@
+package middleware
+
+import (
+	"fmt"
+	"net/http"
+	"runtime"
+	"strconv"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	clouddriver "github.com/billiford/go-clouddriver/pkg"
+	"github.com/gin-gonic/gin"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+const (
+	// Shed operations are retried after this long.
+	shedRetryAfter = 10 * time.Second
+	// The heap is read at most this often, as reading it stops the world.
+	heapSampleInterval = time.Second
+)
+
+var (
+	inFlightOperations = prometheus.NewGauge(prometheus.GaugeOpts{
+		Name: "clouddriver_operations_in_flight",
+		Help: "Number of operations being handled, running or waiting to be admitted.",
+	})
+)
+
+func init() {
+	prometheus.MustRegister(inFlightOperations)
+}
+
+// operationRoutes start or simulate operations.
+var operationRoutes = map[string]bool{
+	"/kubernetes/ops":          true,
+	"/kubernetes/ops/simulate": true,
+}
+
+// ShedOperations rejects new operations with 429 Too Many Requests and a
+// Retry-After header while maxInFlight operations are already being handled,
+// or while the heap is larger than maxHeap bytes, so operations in flight
+// are not starved of memory or connections. Either is not limited when zero.
+// Other requests are never rejected.
+func ShedOperations(maxInFlight int, maxHeap uint64) gin.HandlerFunc {
+	var inFlight int64
+
+	hs := &heapSampler{}
+
+	return func(c *gin.Context) {
+		if c.Request.Method != http.MethodPost || !operationRoutes[unversioned(c.FullPath())] {
+			c.Next()
+			return
+		}
+
+		var err error
+
+		n := atomic.AddInt64(&inFlight, 1)
+		defer atomic.AddInt64(&inFlight, -1)
+
+		if maxInFlight > 0 && n > int64(maxInFlight) {
+			rejectedRequests.WithLabelValues("operations").Inc()
+			err = fmt.Errorf("%d operations are already in flight, the limit is %d", n-1, maxInFlight)
+		} else if maxHeap > 0 {
+			if heap := hs.heap(time.Now()); heap > maxHeap {
+				rejectedRequests.WithLabelValues("memory").Inc()
+				err = fmt.Errorf("heap of %d bytes exceeds the limit of %d bytes", heap, maxHeap)
+			}
+		}
+
+		if err != nil {
+			c.Header("Retry-After", strconv.Itoa(int(shedRetryAfter.Seconds())))
+			clouddriver.WriteError(c, http.StatusTooManyRequests, err)
+			c.Abort()
+
+			return
+		}
+
+		inFlightOperations.Inc()
+		defer inFlightOperations.Dec()
+
+		c.Next()
+	}
+}
+
+// heapSampler returns the size of the heap as last read, reading it again
+// once it is older than heapSampleInterval.
+type heapSampler struct {
+	mux  sync.Mutex
+	read time.Time
+	size uint64
+}
+
+func (hs *heapSampler) heap(now time.Time) uint64 {
+	hs.mux.Lock()
+	defer hs.mux.Unlock()
+
+	if now.Sub(hs.read) >= heapSampleInterval {
+		ms := runtime.MemStats{}
+		runtime.ReadMemStats(&ms)
+
+		hs.size = ms.HeapAlloc
+		hs.read = now
+	}
+
+	return hs.size
+}
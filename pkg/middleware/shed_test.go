@@ -0,0 +1,118 @@
+package middleware_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+
+	. "github.com/billiford/go-clouddriver/pkg/middleware"
+	"github.com/gin-gonic/gin"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("ShedOperations", func() {
+	var (
+		e           *gin.Engine
+		recorder    *httptest.ResponseRecorder
+		req         *http.Request
+		maxInFlight int
+		maxHeap     uint64
+		// Requests served while req is in flight, by path.
+		during   map[string]*httptest.ResponseRecorder
+		released chan struct{}
+	)
+
+	BeforeEach(func() {
+		gin.SetMode(gin.ReleaseMode)
+		recorder = httptest.NewRecorder()
+		req, _ = http.NewRequest(http.MethodPost, "/kubernetes/ops", nil)
+		maxInFlight = 0
+		maxHeap = 0
+		during = map[string]*httptest.ResponseRecorder{}
+		released = make(chan struct{})
+	})
+
+	JustBeforeEach(func() {
+		e = gin.New()
+		e.Use(HandleError())
+		e.Use(ShedOperations(maxInFlight, maxHeap))
+
+		inFlight := make(chan struct{})
+		e.POST("/kubernetes/ops", func(c *gin.Context) {
+			if c.Query("wait") == "true" {
+				close(inFlight)
+				<-released
+			}
+
+			c.Status(http.StatusOK)
+		})
+		e.GET("/credentials", func(c *gin.Context) { c.Status(http.StatusOK) })
+
+		if len(during) == 0 {
+			e.ServeHTTP(recorder, req)
+			return
+		}
+
+		done := make(chan struct{})
+		go func() {
+			defer close(done)
+			e.ServeHTTP(recorder, req)
+		}()
+		<-inFlight
+
+		for path, r := range during {
+			method := http.MethodPost
+			if path == "/credentials" {
+				method = http.MethodGet
+			}
+
+			dr, _ := http.NewRequest(method, path, nil)
+			e.ServeHTTP(r, dr)
+		}
+
+		close(released)
+		<-done
+	})
+
+	When("too many operations are in flight", func() {
+		BeforeEach(func() {
+			maxInFlight = 1
+			req, _ = http.NewRequest(http.MethodPost, "/kubernetes/ops?wait=true", nil)
+			during["/kubernetes/ops"] = httptest.NewRecorder()
+			during["/credentials"] = httptest.NewRecorder()
+		})
+
+		It("sheds new operations", func() {
+			Expect(recorder.Code).To(Equal(http.StatusOK))
+			Expect(during["/kubernetes/ops"].Code).To(Equal(http.StatusTooManyRequests))
+			Expect(during["/kubernetes/ops"].Header().Get("Retry-After")).To(Equal("10"))
+			Expect(during["/kubernetes/ops"].Body.String()).To(ContainSubstring("1 operations are already in flight, the limit is 1"))
+		})
+
+		It("does not shed other requests", func() {
+			Expect(during["/credentials"].Code).To(Equal(http.StatusOK))
+		})
+	})
+
+	When("the heap is too large", func() {
+		BeforeEach(func() {
+			maxHeap = 1
+		})
+
+		It("sheds operations", func() {
+			Expect(recorder.Code).To(Equal(http.StatusTooManyRequests))
+			Expect(recorder.Body.String()).To(ContainSubstring("exceeds the limit of 1 bytes"))
+		})
+	})
+
+	When("operations are under the limits", func() {
+		BeforeEach(func() {
+			maxInFlight = 1
+			maxHeap = 1 << 40
+		})
+
+		It("handles them", func() {
+			Expect(recorder.Code).To(Equal(http.StatusOK))
+		})
+	})
+})
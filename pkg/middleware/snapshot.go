@@ -0,0 +1,79 @@
+package middleware
+
+import (
+	"bytes"
+	"fmt"
+	"log"
+	"net/http"
+	"time"
+
+	clouddriver "github.com/billiford/go-clouddriver/pkg"
+	"github.com/billiford/go-clouddriver/pkg/snapshot"
+	"github.com/billiford/go-clouddriver/pkg/sql"
+	"github.com/gin-gonic/gin"
+	"github.com/jinzhu/gorm"
+)
+
+// Snapshot answers reads passed the query param 'asOf' with the latest
+// snapshot of their response taken at or before that time, so the state of
+// an application can be seen as it was during an incident. Other reads are
+// served live and their responses snapshotted when due.
+func Snapshot() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		path := c.Request.URL.Path
+
+		if asOf := c.Query("asOf"); asOf != "" {
+			t, err := snapshot.ParseAsOf(asOf)
+			if err != nil {
+				clouddriver.WriteError(c, http.StatusBadRequest, err)
+				c.Abort()
+
+				return
+			}
+
+			s, err := sql.ReadOnlyInstance(c).GetCacheSnapshot(path, t)
+			if err != nil {
+				if err == gorm.ErrRecordNotFound {
+					clouddriver.WriteError(c, http.StatusNotFound,
+						fmt.Errorf("no snapshot of %s as of %s", path, t.UTC().Format(time.RFC3339)))
+				} else {
+					clouddriver.WriteError(c, http.StatusInternalServerError, err)
+				}
+
+				c.Abort()
+
+				return
+			}
+
+			c.Header(HeaderDataSource, dataSourceSnapshot)
+			c.Header(HeaderReadAt, s.CreatedAt.UTC().Format(time.RFC3339))
+			c.Data(http.StatusOK, "application/json; charset=utf-8", []byte(s.Body))
+			c.Abort()
+
+			return
+		}
+
+		sr := snapshot.Instance(c)
+		if sr == nil {
+			c.Next()
+			return
+		}
+
+		w := bodyWriter{ResponseWriter: c.Writer, body: &bytes.Buffer{}}
+		c.Writer = w
+
+		c.Next()
+
+		if c.Writer.Status() != http.StatusOK || !sr.Due(path, time.Now()) {
+			return
+		}
+
+		err := sql.Instance(c).CreateCacheSnapshot(snapshot.Snapshot{
+			Path: path,
+			Body: w.body.String(),
+		})
+		if err != nil {
+			log.Println("[MIDDLEWARE] error creating cache snapshot of", path+":", err.Error())
+		}
+	}
+}
@@ -0,0 +1,167 @@
+package middleware_test
+
+import (
+	"encoding/json"
+	"errors"
+	"io/ioutil"
+	"log"
+	"net/http"
+	"net/http/httptest"
+	"time"
+
+	clouddriver "github.com/billiford/go-clouddriver/pkg"
+	. "github.com/billiford/go-clouddriver/pkg/middleware"
+	"github.com/billiford/go-clouddriver/pkg/snapshot"
+	"github.com/billiford/go-clouddriver/pkg/snapshot/snapshotfakes"
+	"github.com/billiford/go-clouddriver/pkg/sql"
+	"github.com/billiford/go-clouddriver/pkg/sql/sqlfakes"
+	"github.com/gin-gonic/gin"
+	"github.com/jinzhu/gorm"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Snapshot", func() {
+	var (
+		e               *gin.Engine
+		recorder        *httptest.ResponseRecorder
+		req             *http.Request
+		fakeSQLClient   *sqlfakes.FakeClient
+		fakeSnapshotter *snapshotfakes.FakeSnapshotter
+		snapshotter     snapshot.Snapshotter
+		handled         bool
+	)
+
+	const path = "/applications/test-app/serverGroups"
+
+	BeforeEach(func() {
+		gin.SetMode(gin.ReleaseMode)
+		recorder = httptest.NewRecorder()
+		req, _ = http.NewRequest(http.MethodGet, path, nil)
+		fakeSQLClient = &sqlfakes.FakeClient{}
+		fakeSnapshotter = &snapshotfakes.FakeSnapshotter{}
+		fakeSnapshotter.DueReturns(true)
+		snapshotter = fakeSnapshotter
+		handled = false
+		log.SetOutput(ioutil.Discard)
+	})
+
+	JustBeforeEach(func() {
+		e = gin.New()
+		e.Use(func(c *gin.Context) {
+			c.Set(sql.ClientInstanceKey, fakeSQLClient)
+			c.Set(sql.ReadOnlyClientInstanceKey, fakeSQLClient)
+			c.Next()
+		})
+		e.Use(SetSnapshotter(snapshotter))
+		e.Use(HandleError())
+		e.GET(path, Snapshot(), func(c *gin.Context) {
+			handled = true
+			c.JSON(http.StatusOK, []string{"live"})
+		})
+		e.ServeHTTP(recorder, req)
+	})
+
+	When("reading as of a past time", func() {
+		var createdAt time.Time
+
+		BeforeEach(func() {
+			createdAt = time.Date(2020, 2, 13, 14, 10, 0, 0, time.UTC)
+			req, _ = http.NewRequest(http.MethodGet, path+"?asOf=1581603123000", nil)
+			fakeSQLClient.GetCacheSnapshotReturns(snapshot.Snapshot{
+				Path:      path,
+				Body:      `["snapshot"]`,
+				CreatedAt: createdAt,
+			}, nil)
+		})
+
+		When("the time is invalid", func() {
+			BeforeEach(func() {
+				req, _ = http.NewRequest(http.MethodGet, path+"?asOf=yesterday", nil)
+			})
+
+			It("returns status bad request", func() {
+				Expect(recorder.Code).To(Equal(http.StatusBadRequest))
+				Expect(handled).To(BeFalse())
+			})
+		})
+
+		When("there is no snapshot", func() {
+			BeforeEach(func() {
+				fakeSQLClient.GetCacheSnapshotReturns(snapshot.Snapshot{}, gorm.ErrRecordNotFound)
+			})
+
+			It("returns status not found", func() {
+				Expect(recorder.Code).To(Equal(http.StatusNotFound))
+				ce := clouddriver.Error{}
+				Expect(json.Unmarshal(recorder.Body.Bytes(), &ce)).To(Succeed())
+				Expect(ce.Message).To(Equal("no snapshot of " + path + " as of 2020-02-13T14:12:03Z"))
+			})
+		})
+
+		When("getting the snapshot fails", func() {
+			BeforeEach(func() {
+				fakeSQLClient.GetCacheSnapshotReturns(snapshot.Snapshot{}, errors.New("error getting snapshot"))
+			})
+
+			It("returns status internal server error", func() {
+				Expect(recorder.Code).To(Equal(http.StatusInternalServerError))
+			})
+		})
+
+		It("serves the latest snapshot taken by then", func() {
+			Expect(recorder.Code).To(Equal(http.StatusOK))
+			Expect(recorder.Body.String()).To(Equal(`["snapshot"]`))
+			Expect(recorder.Header().Get(HeaderDataSource)).To(Equal("snapshot"))
+			Expect(recorder.Header().Get(HeaderReadAt)).To(Equal("2020-02-13T14:10:00Z"))
+			Expect(handled).To(BeFalse())
+			p, asOf := fakeSQLClient.GetCacheSnapshotArgsForCall(0)
+			Expect(p).To(Equal(path))
+			Expect(asOf.UTC()).To(Equal(time.Date(2020, 2, 13, 14, 12, 3, 0, time.UTC)))
+		})
+	})
+
+	When("snapshots are disabled", func() {
+		BeforeEach(func() {
+			snapshotter = nil
+		})
+
+		It("serves the live response", func() {
+			Expect(recorder.Code).To(Equal(http.StatusOK))
+			Expect(handled).To(BeTrue())
+			Expect(fakeSQLClient.CreateCacheSnapshotCallCount()).To(BeZero())
+		})
+	})
+
+	When("a snapshot is not due", func() {
+		BeforeEach(func() {
+			fakeSnapshotter.DueReturns(false)
+		})
+
+		It("does not take one", func() {
+			Expect(recorder.Code).To(Equal(http.StatusOK))
+			Expect(fakeSQLClient.CreateCacheSnapshotCallCount()).To(BeZero())
+		})
+	})
+
+	When("taking the snapshot fails", func() {
+		BeforeEach(func() {
+			fakeSQLClient.CreateCacheSnapshotReturns(errors.New("error creating snapshot"))
+		})
+
+		It("still serves the live response", func() {
+			Expect(recorder.Code).To(Equal(http.StatusOK))
+			Expect(recorder.Body.String()).To(Equal(`["live"]`))
+		})
+	})
+
+	It("serves the live response and snapshots it", func() {
+		Expect(recorder.Code).To(Equal(http.StatusOK))
+		Expect(recorder.Body.String()).To(Equal(`["live"]`))
+		Expect(fakeSnapshotter.DueCallCount()).To(Equal(1))
+		Expect(fakeSQLClient.CreateCacheSnapshotCallCount()).To(Equal(1))
+		s := fakeSQLClient.CreateCacheSnapshotArgsForCall(0)
+		Expect(s.Path).To(Equal(path))
+		Expect(s.Body).To(Equal(`["live"]`))
+	})
+})
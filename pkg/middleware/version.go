@@ -0,0 +1,35 @@
+package middleware
+
+import (
+	"strings"
+
+	clouddriver "github.com/billiford/go-clouddriver/pkg"
+	"github.com/gin-gonic/gin"
+)
+
+// HeaderAPIVersion is the version of the API that served a response.
+const HeaderAPIVersion = `X-Clouddriver-API-Version`
+
+// APIVersion marks requests as served by version of the API, for handlers to
+// read with clouddriver.APIVersion and clients to read in the X-Clouddriver-API-Version header.
+func APIVersion(version string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		c.Set(clouddriver.APIVersionInstanceKey, version)
+		c.Header(HeaderAPIVersion, version)
+		c.Next()
+	}
+}
+
+// unversioned returns a path without the version of the API it starts with,
+// such as /credentials for /v1/credentials, so middleware matching the
+// unversioned paths of routes also matches them in every version.
+func unversioned(path string) string {
+	for _, version := range clouddriver.APIVersions {
+		trimmed := strings.TrimPrefix(path, "/"+version)
+		if trimmed != path && (trimmed == "" || trimmed[0] == '/') {
+			return trimmed
+		}
+	}
+
+	return path
+}
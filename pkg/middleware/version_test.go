@@ -0,0 +1,64 @@
+package middleware_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+
+	clouddriver "github.com/billiford/go-clouddriver/pkg"
+	. "github.com/billiford/go-clouddriver/pkg/middleware"
+	"github.com/gin-gonic/gin"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Version", func() {
+	var (
+		e        *gin.Engine
+		recorder *httptest.ResponseRecorder
+		version  string
+	)
+
+	BeforeEach(func() {
+		gin.SetMode(gin.ReleaseMode)
+		e = gin.New()
+		recorder = httptest.NewRecorder()
+		version = ""
+	})
+
+	Describe("#APIVersion", func() {
+		JustBeforeEach(func() {
+			e.GET("/credentials", func(c *gin.Context) {
+				version = clouddriver.APIVersion(c)
+				c.Status(http.StatusOK)
+			})
+			e.GET("/v2/credentials", APIVersion("v2"), func(c *gin.Context) {
+				version = clouddriver.APIVersion(c)
+				c.Status(http.StatusOK)
+			})
+		})
+
+		When("the route is not versioned", func() {
+			JustBeforeEach(func() {
+				req, _ := http.NewRequest(http.MethodGet, "/credentials", nil)
+				e.ServeHTTP(recorder, req)
+			})
+
+			It("is served as v1", func() {
+				Expect(version).To(Equal(clouddriver.APIVersionV1))
+				Expect(recorder.Header().Get(HeaderAPIVersion)).To(BeEmpty())
+			})
+		})
+
+		When("the route is versioned", func() {
+			JustBeforeEach(func() {
+				req, _ := http.NewRequest(http.MethodGet, "/v2/credentials", nil)
+				e.ServeHTTP(recorder, req)
+			})
+
+			It("marks the request with the version", func() {
+				Expect(version).To(Equal("v2"))
+				Expect(recorder.Header().Get(HeaderAPIVersion)).To(Equal("v2"))
+			})
+		})
+	})
+})
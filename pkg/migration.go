@@ -0,0 +1,101 @@
+package clouddriver
+
+import (
+	"database/sql/driver"
+	"encoding/json"
+	"fmt"
+	"time"
+)
+
+// Outcomes of a resource of a migration.
+const (
+	MigrationCopied  = `copied`
+	MigrationSkipped = `skipped`
+	MigrationFailed  = `failed`
+)
+
+// MigrationReport is the outcome of a migrateApplication task: each resource
+// of the application in the source account and namespace, and whether it was
+// copied to the target.
+type MigrationReport struct {
+	TaskID          string            `json:"-" gorm:"primary_key"`
+	Application     string            `json:"application"`
+	SourceAccount   string            `json:"sourceAccount"`
+	SourceNamespace string            `json:"sourceNamespace"`
+	TargetAccount   string            `json:"targetAccount"`
+	TargetNamespace string            `json:"targetNamespace"`
+	Copied          int               `json:"copied"`
+	Skipped         int               `json:"skipped"`
+	Failed          int               `json:"failed"`
+	Resources       MigratedResources `json:"resources" gorm:"type:text"`
+	CreatedAt       time.Time         `json:"createdAt"`
+}
+
+func (MigrationReport) TableName() string {
+	return "migration_reports"
+}
+
+// Add adds the outcome of a resource to the report.
+func (mr *MigrationReport) Add(kind, name, outcome, reason string) {
+	mr.Resources = append(mr.Resources, MigratedResource{
+		Kind:    kind,
+		Name:    name,
+		Outcome: outcome,
+		Reason:  reason,
+	})
+
+	switch outcome {
+	case MigrationCopied:
+		mr.Copied++
+	case MigrationSkipped:
+		mr.Skipped++
+	case MigrationFailed:
+		mr.Failed++
+	}
+}
+
+// MigratedResource is the outcome of a resource of a migration, with why it
+// was skipped or failed.
+type MigratedResource struct {
+	Kind    string `json:"kind"`
+	Name    string `json:"name"`
+	Outcome string `json:"outcome"`
+	Reason  string `json:"reason,omitempty"`
+}
+
+// MigratedResources are stored as JSON.
+type MigratedResources []MigratedResource
+
+// Value implements driver.Valuer.
+func (mrs MigratedResources) Value() (driver.Value, error) {
+	b, err := json.Marshal(mrs)
+	if err != nil {
+		return nil, err
+	}
+
+	return string(b), nil
+}
+
+// Scan implements sql.Scanner.
+func (mrs *MigratedResources) Scan(src interface{}) error {
+	var b []byte
+
+	switch v := src.(type) {
+	case nil:
+		*mrs = nil
+		return nil
+	case string:
+		b = []byte(v)
+	case []byte:
+		b = v
+	default:
+		return fmt.Errorf("cannot scan %T into migrated resources", src)
+	}
+
+	if len(b) == 0 {
+		*mrs = nil
+		return nil
+	}
+
+	return json.Unmarshal(b, mrs)
+}
@@ -0,0 +1,133 @@
+package notify
+
+import (
+	"fmt"
+	"strings"
+)
+
+const (
+	colorSucceeded = "2EB886"
+	colorFailed    = "A30200"
+)
+
+// title summarizes an event, such as "deployManifest of my-app succeeded
+// in prod-account".
+func title(e Event) string {
+	outcome := WhenFailed
+	if e.Succeeded() {
+		outcome = WhenSucceeded
+	}
+
+	if e.Application == "" {
+		return fmt.Sprintf("%s %s in %s", e.Operation, outcome, e.Account)
+	}
+
+	return fmt.Sprintf("%s of %s %s in %s", e.Operation, e.Application, outcome, e.Account)
+}
+
+// text lists the resources changed by an event or the reason it failed,
+// along with who ran it.
+func text(e Event) string {
+	lines := []string{}
+
+	if e.Succeeded() {
+		for _, r := range e.Resources {
+			lines = append(lines, "• "+r)
+		}
+	} else {
+		lines = append(lines, e.Error)
+	}
+
+	if e.User != "" {
+		lines = append(lines, "Run by "+e.User)
+	}
+
+	return strings.Join(lines, "\n")
+}
+
+func color(e Event) string {
+	if e.Succeeded() {
+		return colorSucceeded
+	}
+
+	return colorFailed
+}
+
+type slackPayload struct {
+	Attachments []slackAttachment `json:"attachments"`
+}
+
+type slackAttachment struct {
+	Color     string `json:"color"`
+	Fallback  string `json:"fallback"`
+	Title     string `json:"title"`
+	TitleLink string `json:"title_link,omitempty"`
+	Text      string `json:"text,omitempty"`
+}
+
+// slackMessage returns an incoming webhook message with an attachment
+// colored by the outcome of the event.
+func slackMessage(e Event, link string) slackPayload {
+	return slackPayload{
+		Attachments: []slackAttachment{
+			{
+				Color:     "#" + color(e),
+				Fallback:  title(e),
+				Title:     title(e),
+				TitleLink: link,
+				Text:      text(e),
+			},
+		},
+	}
+}
+
+type teamsPayload struct {
+	Type            string        `json:"@type"`
+	Context         string        `json:"@context"`
+	ThemeColor      string        `json:"themeColor"`
+	Summary         string        `json:"summary"`
+	Title           string        `json:"title"`
+	Text            string        `json:"text,omitempty"`
+	PotentialAction []teamsAction `json:"potentialAction,omitempty"`
+}
+
+type teamsAction struct {
+	Type    string        `json:"@type"`
+	Name    string        `json:"name"`
+	Targets []teamsTarget `json:"targets"`
+}
+
+type teamsTarget struct {
+	OS  string `json:"os"`
+	URI string `json:"uri"`
+}
+
+// teamsMessage returns an incoming webhook MessageCard, which renders
+// its text as markdown, so lines are separated by blank lines.
+func teamsMessage(e Event, link string) teamsPayload {
+	tp := teamsPayload{
+		Type:       "MessageCard",
+		Context:    "https://schema.org/extensions",
+		ThemeColor: color(e),
+		Summary:    title(e),
+		Title:      title(e),
+		Text:       strings.ReplaceAll(text(e), "\n", "\n\n"),
+	}
+
+	if link != "" {
+		tp.PotentialAction = []teamsAction{
+			{
+				Type: "OpenUri",
+				Name: "View in Deck",
+				Targets: []teamsTarget{
+					{
+						OS:  "default",
+						URI: link,
+					},
+				},
+			},
+		}
+	}
+
+	return tp
+}
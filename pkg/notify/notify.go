@@ -0,0 +1,261 @@
+// Package notify posts summaries of operation outcomes to Slack and
+// Microsoft Teams incoming webhooks, for accounts whose notifications
+// are not sent by Echo.
+package notify
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"log"
+	"net/http"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/billiford/go-clouddriver/pkg/routine"
+	"github.com/gin-gonic/gin"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+const (
+	InstanceKey = `Notifier`
+
+	SinkTypeSlack = `slack`
+	SinkTypeTeams = `teams`
+
+	WhenSucceeded = `succeeded`
+	WhenFailed    = `failed`
+)
+
+var (
+	defaultConfigPath = "/opt/spinnaker/kubernetes/notifications.json"
+	// Deploys and rollbacks are notified when a sink lists no operations.
+	defaultOperations = []string{"deployManifest", "undoRolloutManifest"}
+
+	notificationsSent = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "clouddriver_notifications_total",
+		Help: "Number of notifications posted by sink type and result.",
+	}, []string{"type", "result"})
+)
+
+func init() {
+	prometheus.MustRegister(notificationsSent)
+}
+
+// Event is the outcome of an operation.
+type Event struct {
+	Operation   string
+	Account     string
+	Application string
+	User        string
+	TaskID      string
+	// Resources changed by the operation, such as "deployment my-app".
+	Resources []string
+	// Error is the reason the operation failed, or empty if it succeeded.
+	Error string
+}
+
+// Succeeded returns true if the operation succeeded.
+func (e Event) Succeeded() bool {
+	return e.Error == ""
+}
+
+// Notifier notifies the sinks an event matches.
+//
+//go:generate counterfeiter . Notifier
+type Notifier interface {
+	Notify(Event)
+}
+
+// Config lists the sinks to notify, along with Deck's URL to link to the
+// application of an operation.
+//
+//	{
+//	  "deckBaseUrl": "https://spinnaker.example.com",
+//	  "sinks": [
+//	    {
+//	      "type": "slack",
+//	      "webhookUrl": "https://hooks.slack.com/services/T000/B000/XXXX",
+//	      "accounts": ["prod-account"],
+//	      "when": ["failed"]
+//	    },
+//	    {
+//	      "type": "teams",
+//	      "webhookUrl": "https://example.webhook.office.com/webhookb2/...",
+//	      "applications": ["my-app"]
+//	    }
+//	  ]
+//	}
+type Config struct {
+	DeckBaseURL string `json:"deckBaseUrl,omitempty"`
+	Sinks       []Sink `json:"sinks"`
+}
+
+// Sink is an incoming webhook notified of the operations of the listed
+// accounts and applications, or of all of them when none are listed.
+// Sinks are notified of deploys and rollbacks that succeeded or failed
+// unless other operations or outcomes are listed.
+type Sink struct {
+	Type         string   `json:"type"`
+	WebhookURL   string   `json:"webhookUrl"`
+	Accounts     []string `json:"accounts,omitempty"`
+	Applications []string `json:"applications,omitempty"`
+	Operations   []string `json:"operations,omitempty"`
+	When         []string `json:"when,omitempty"`
+}
+
+// Matches returns true if the sink is notified of an event.
+func (s Sink) Matches(e Event) bool {
+	operations := s.Operations
+	if len(operations) == 0 {
+		operations = defaultOperations
+	}
+
+	when := WhenFailed
+	if e.Succeeded() {
+		when = WhenSucceeded
+	}
+
+	return matches(s.Accounts, e.Account) &&
+		matches(s.Applications, e.Application) &&
+		matches(operations, e.Operation) &&
+		matches(s.When, when)
+}
+
+func matches(values []string, s string) bool {
+	if len(values) == 0 {
+		return true
+	}
+
+	for _, v := range values {
+		if strings.EqualFold(v, s) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// NewDefaultNotifier reads the notifier's config from /opt/spinnaker/kubernetes/notifications.json.
+// Notifications are optional, so if the file does not exist it returns nil.
+func NewDefaultNotifier() (Notifier, error) {
+	if _, err := os.Stat(defaultConfigPath); os.IsNotExist(err) {
+		return nil, nil
+	}
+
+	return NewNotifier(defaultConfigPath)
+}
+
+// NewNotifier reads the notifier's config from a JSON file.
+func NewNotifier(path string) (Notifier, error) {
+	b, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	config := Config{}
+
+	err = json.Unmarshal(b, &config)
+	if err != nil {
+		return nil, err
+	}
+
+	return NewNotifierWithConfig(config)
+}
+
+// NewNotifierWithConfig returns a notifier for the sinks of config.
+func NewNotifierWithConfig(config Config) (Notifier, error) {
+	for _, s := range config.Sinks {
+		if s.Type != SinkTypeSlack && s.Type != SinkTypeTeams {
+			return nil, fmt.Errorf("unknown notification sink type %q, must be %s or %s",
+				s.Type, SinkTypeSlack, SinkTypeTeams)
+		}
+
+		if s.WebhookURL == "" {
+			return nil, fmt.Errorf("no \"webhookUrl\" found in %s notification sink", s.Type)
+		}
+	}
+
+	return &notifier{
+		config:     config,
+		httpClient: &http.Client{Timeout: 10 * time.Second},
+	}, nil
+}
+
+type notifier struct {
+	config     Config
+	httpClient *http.Client
+}
+
+// Notify posts the event to each sink it matches in the background,
+// so slow webhooks do not hold up operations. Errors are logged.
+func (n *notifier) Notify(e Event) {
+	for _, s := range n.config.Sinks {
+		if !s.Matches(e) {
+			continue
+		}
+
+		s := s
+
+		routine.Go(context.Background(), "notify", func(context.Context) {
+			n.post(s, e)
+		})
+	}
+}
+
+func (n *notifier) post(s Sink, e Event) {
+	var payload interface{}
+
+	link := n.link(e)
+
+	switch s.Type {
+	case SinkTypeSlack:
+		payload = slackMessage(e, link)
+	case SinkTypeTeams:
+		payload = teamsMessage(e, link)
+	}
+
+	b, err := json.Marshal(payload)
+	if err != nil {
+		n.logError(s, e, err)
+		return
+	}
+
+	res, err := n.httpClient.Post(s.WebhookURL, "application/json", bytes.NewReader(b))
+	if err != nil {
+		n.logError(s, e, err)
+		return
+	}
+	defer res.Body.Close()
+
+	if res.StatusCode < http.StatusOK || res.StatusCode >= http.StatusMultipleChoices {
+		n.logError(s, e, fmt.Errorf("webhook returned status %d", res.StatusCode))
+		return
+	}
+
+	notificationsSent.WithLabelValues(s.Type, "sent").Inc()
+}
+
+func (n *notifier) logError(s Sink, e Event, err error) {
+	notificationsSent.WithLabelValues(s.Type, "error").Inc()
+	log.Println("[NOTIFY] error posting", s.Type, "notification of task", e.TaskID+":", err.Error())
+}
+
+// link returns the URL of the application's clusters in Deck, or an empty
+// string if Deck's URL or the application is unknown.
+func (n *notifier) link(e Event) string {
+	if n.config.DeckBaseURL == "" || e.Application == "" {
+		return ""
+	}
+
+	return strings.TrimSuffix(n.config.DeckBaseURL, "/") + "/#/applications/" + e.Application + "/clusters"
+}
+
+// Instance returns the Notifier, or nil if notifications are disabled.
+func Instance(c *gin.Context) Notifier {
+	n, _ := c.MustGet(InstanceKey).(Notifier)
+	return n
+}
@@ -0,0 +1,219 @@
+package notify_test
+
+import (
+	"io/ioutil"
+	"log"
+	"net/http"
+
+	. "github.com/billiford/go-clouddriver/pkg/notify"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"github.com/onsi/gomega/ghttp"
+)
+
+var _ = Describe("Notify", func() {
+	var (
+		n     Notifier
+		err   error
+		event Event
+	)
+
+	BeforeEach(func() {
+		log.SetOutput(ioutil.Discard)
+		event = Event{
+			Operation:   "deployManifest",
+			Account:     "prod-account",
+			Application: "my-app",
+			User:        "me@example.com",
+			TaskID:      "test-task-id",
+			Resources:   []string{"Deployment my-app in default"},
+		}
+	})
+
+	Describe("#NewNotifier", func() {
+		When("the file does not exist", func() {
+			BeforeEach(func() {
+				n, err = NewNotifier("test/missing.json")
+			})
+
+			It("returns an error", func() {
+				Expect(err).ToNot(BeNil())
+			})
+		})
+
+		When("it succeeds", func() {
+			BeforeEach(func() {
+				n, err = NewNotifier("test/notifications.json")
+			})
+
+			It("returns a notifier", func() {
+				Expect(err).To(BeNil())
+				Expect(n).ToNot(BeNil())
+			})
+		})
+	})
+
+	Describe("#NewNotifierWithConfig", func() {
+		var config Config
+
+		BeforeEach(func() {
+			config = Config{Sinks: []Sink{{Type: SinkTypeSlack, WebhookURL: "https://hooks.slack.com/services/T000/B000/XXXX"}}}
+		})
+
+		JustBeforeEach(func() {
+			n, err = NewNotifierWithConfig(config)
+		})
+
+		When("a sink type is unknown", func() {
+			BeforeEach(func() {
+				config.Sinks[0].Type = "email"
+			})
+
+			It("returns an error", func() {
+				Expect(err).ToNot(BeNil())
+				Expect(err.Error()).To(Equal(`unknown notification sink type "email", must be slack or teams`))
+			})
+		})
+
+		When("a sink has no webhook URL", func() {
+			BeforeEach(func() {
+				config.Sinks[0].WebhookURL = ""
+			})
+
+			It("returns an error", func() {
+				Expect(err).ToNot(BeNil())
+				Expect(err.Error()).To(Equal(`no "webhookUrl" found in slack notification sink`))
+			})
+		})
+	})
+
+	Describe("#Matches", func() {
+		var sink Sink
+
+		BeforeEach(func() {
+			sink = Sink{Type: SinkTypeSlack}
+		})
+
+		It("matches deploys and rollbacks by default", func() {
+			Expect(sink.Matches(event)).To(BeTrue())
+			event.Operation = "undoRolloutManifest"
+			Expect(sink.Matches(event)).To(BeTrue())
+			event.Operation = "scaleManifest"
+			Expect(sink.Matches(event)).To(BeFalse())
+		})
+
+		It("matches the listed accounts and applications ignoring case", func() {
+			sink.Accounts = []string{"Prod-Account"}
+			sink.Applications = []string{"other-app"}
+			Expect(sink.Matches(event)).To(BeFalse())
+			sink.Applications = []string{"my-app"}
+			Expect(sink.Matches(event)).To(BeTrue())
+		})
+
+		It("matches the listed outcomes", func() {
+			sink.When = []string{WhenFailed}
+			Expect(sink.Matches(event)).To(BeFalse())
+			event.Error = "error deploying manifest"
+			Expect(sink.Matches(event)).To(BeTrue())
+		})
+	})
+
+	Describe("#Notify", func() {
+		var (
+			slackServer *ghttp.Server
+			teamsServer *ghttp.Server
+		)
+
+		BeforeEach(func() {
+			slackServer = ghttp.NewServer()
+			teamsServer = ghttp.NewServer()
+			n, err = NewNotifierWithConfig(Config{
+				DeckBaseURL: "https://spinnaker.example.com/",
+				Sinks: []Sink{
+					{
+						Type:       SinkTypeSlack,
+						WebhookURL: slackServer.URL() + "/slack",
+					},
+					{
+						Type:       SinkTypeTeams,
+						WebhookURL: teamsServer.URL() + "/teams",
+						When:       []string{WhenFailed},
+					},
+				},
+			})
+			Expect(err).To(BeNil())
+		})
+
+		AfterEach(func() {
+			slackServer.Close()
+			teamsServer.Close()
+		})
+
+		When("the operation succeeds", func() {
+			BeforeEach(func() {
+				slackServer.AppendHandlers(ghttp.CombineHandlers(
+					ghttp.VerifyRequest(http.MethodPost, "/slack"),
+					ghttp.VerifyContentType("application/json"),
+					ghttp.VerifyJSON(payloadSlackSucceeded),
+					ghttp.RespondWith(http.StatusOK, "ok"),
+				))
+			})
+
+			It("posts to the sinks it matches", func() {
+				n.Notify(event)
+				Eventually(slackServer.ReceivedRequests).Should(HaveLen(1))
+				Consistently(teamsServer.ReceivedRequests).Should(BeEmpty())
+			})
+		})
+
+		When("the operation fails", func() {
+			BeforeEach(func() {
+				event.Error = "error deploying manifest"
+				slackServer.AllowUnhandledRequests = true
+				teamsServer.AppendHandlers(ghttp.CombineHandlers(
+					ghttp.VerifyRequest(http.MethodPost, "/teams"),
+					ghttp.VerifyJSON(payloadTeamsFailed),
+					ghttp.RespondWith(http.StatusOK, "1"),
+				))
+			})
+
+			It("posts a message card to teams", func() {
+				n.Notify(event)
+				Eventually(teamsServer.ReceivedRequests).Should(HaveLen(1))
+			})
+		})
+	})
+})
+
+const payloadSlackSucceeded = `{
+  "attachments": [
+    {
+      "color": "#2EB886",
+      "fallback": "deployManifest of my-app succeeded in prod-account",
+      "title": "deployManifest of my-app succeeded in prod-account",
+      "title_link": "https://spinnaker.example.com/#/applications/my-app/clusters",
+      "text": "• Deployment my-app in default\nRun by me@example.com"
+    }
+  ]
+}`
+
+const payloadTeamsFailed = `{
+  "@type": "MessageCard",
+  "@context": "https://schema.org/extensions",
+  "themeColor": "A30200",
+  "summary": "deployManifest of my-app failed in prod-account",
+  "title": "deployManifest of my-app failed in prod-account",
+  "text": "error deploying manifest\n\nRun by me@example.com",
+  "potentialAction": [
+    {
+      "@type": "OpenUri",
+      "name": "View in Deck",
+      "targets": [
+        {
+          "os": "default",
+          "uri": "https://spinnaker.example.com/#/applications/my-app/clusters"
+        }
+      ]
+    }
+  ]
+}`
@@ -0,0 +1,151 @@
+// Package ondemand keeps the on-demand cache refreshes Orca requests after it
+// changes a manifest, so it can wait for the refreshes that were pending when
+// it requested them.
+package ondemand
+
+import (
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+const (
+	InstanceKey = `OnDemandRefreshes`
+	// DefaultRetention is how long refreshes are listed after they were
+	// requested, if not configured.
+	DefaultRetention = 10 * time.Minute
+)
+
+// Details are the manifest a refresh is of, as Orca matches them to the
+// manifests it refreshes. Name is the kind and name, such as
+// "deployment my-app".
+type Details struct {
+	Account  string `json:"account"`
+	Location string `json:"location"`
+	Name     string `json:"name"`
+}
+
+// Refresh is an on-demand refresh of a manifest, listed as Orca expects the
+// pendingOnDemandRequests of a cache. Times are in milliseconds since the
+// epoch. A refresh is pending until its ProcessedCount is above zero.
+type Refresh struct {
+	ID             string  `json:"id"`
+	Details        Details `json:"details"`
+	CacheTime      int64   `json:"cacheTime"`
+	ProcessedCount int     `json:"processedCount"`
+	ProcessedTime  int64   `json:"processedTime"`
+	// Error is why the refresh was given up on, if it was.
+	Error string `json:"error,omitempty"`
+}
+
+// Pending returns true if the refresh has not been processed.
+func (r Refresh) Pending() bool {
+	return r.ProcessedCount == 0
+}
+
+// Refreshes keeps the latest on-demand refresh of each manifest.
+//
+//go:generate counterfeiter . Refreshes
+type Refreshes interface {
+	// Request records a pending refresh of a manifest requested at now,
+	// replacing any earlier refresh of it.
+	Request(Details, time.Time) Refresh
+	// Process marks a refresh processed at now, with the error it was given
+	// up on if any. Refreshes replaced by a later request are left pending.
+	Process(Refresh, time.Time, error)
+	// List returns the refreshes requested within the retention of now,
+	// oldest first.
+	List(time.Time) []Refresh
+}
+
+// New returns Refreshes that are listed for retention after they are
+// requested, or DefaultRetention if zero.
+func New(retention time.Duration) Refreshes {
+	if retention <= 0 {
+		retention = DefaultRetention
+	}
+
+	return &refreshes{
+		retention: retention,
+		refreshes: map[string]Refresh{},
+	}
+}
+
+type refreshes struct {
+	mux       sync.Mutex
+	retention time.Duration
+	refreshes map[string]Refresh
+}
+
+func (rs *refreshes) Request(d Details, now time.Time) Refresh {
+	r := Refresh{
+		ID:        fmt.Sprintf("%s:%s:%s", d.Account, d.Location, d.Name),
+		Details:   d,
+		CacheTime: millis(now),
+	}
+
+	rs.mux.Lock()
+	defer rs.mux.Unlock()
+
+	rs.refreshes[r.ID] = r
+
+	return r
+}
+
+func (rs *refreshes) Process(r Refresh, now time.Time, err error) {
+	rs.mux.Lock()
+	defer rs.mux.Unlock()
+
+	latest, ok := rs.refreshes[r.ID]
+	if !ok || latest.CacheTime != r.CacheTime {
+		return
+	}
+
+	latest.ProcessedCount++
+	latest.ProcessedTime = millis(now)
+
+	if err != nil {
+		latest.Error = err.Error()
+	}
+
+	rs.refreshes[r.ID] = latest
+}
+
+func (rs *refreshes) List(now time.Time) []Refresh {
+	oldest := millis(now.Add(-rs.retention))
+	l := []Refresh{}
+
+	rs.mux.Lock()
+	defer rs.mux.Unlock()
+
+	for id, r := range rs.refreshes {
+		if r.CacheTime < oldest {
+			delete(rs.refreshes, id)
+			continue
+		}
+
+		l = append(l, r)
+	}
+
+	sort.Slice(l, func(i, j int) bool {
+		if l[i].CacheTime != l[j].CacheTime {
+			return l[i].CacheTime < l[j].CacheTime
+		}
+
+		return l[i].ID < l[j].ID
+	})
+
+	return l
+}
+
+func millis(t time.Time) int64 {
+	return t.UnixNano() / int64(time.Millisecond)
+}
+
+// Instance returns the Refreshes of the request.
+func Instance(c *gin.Context) Refreshes {
+	return c.MustGet(InstanceKey).(Refreshes)
+}
@@ -0,0 +1,85 @@
+package ondemand_test
+
+import (
+	"errors"
+	"time"
+
+	. "github.com/billiford/go-clouddriver/pkg/ondemand"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Refreshes", func() {
+	var (
+		refreshes Refreshes
+		details   Details
+		now       time.Time
+	)
+
+	BeforeEach(func() {
+		refreshes = New(time.Hour)
+		details = Details{
+			Account:  "test-account",
+			Location: "test-namespace",
+			Name:     "deployment test-deployment",
+		}
+		now = time.Unix(1600000000, 0)
+	})
+
+	It("lists requested refreshes as pending", func() {
+		r := refreshes.Request(details, now)
+		Expect(r.ID).To(Equal("test-account:test-namespace:deployment test-deployment"))
+		Expect(r.Pending()).To(BeTrue())
+
+		l := refreshes.List(now)
+		Expect(l).To(HaveLen(1))
+		Expect(l[0].Details).To(Equal(details))
+		Expect(l[0].CacheTime).To(Equal(int64(1600000000000)))
+		Expect(l[0].ProcessedCount).To(BeZero())
+	})
+
+	It("marks processed refreshes", func() {
+		r := refreshes.Request(details, now)
+		refreshes.Process(r, now.Add(time.Second), nil)
+
+		l := refreshes.List(now)
+		Expect(l).To(HaveLen(1))
+		Expect(l[0].Pending()).To(BeFalse())
+		Expect(l[0].ProcessedCount).To(Equal(1))
+		Expect(l[0].ProcessedTime).To(Equal(int64(1600000001000)))
+		Expect(l[0].Error).To(BeEmpty())
+	})
+
+	It("keeps the error a refresh was given up on", func() {
+		r := refreshes.Request(details, now)
+		refreshes.Process(r, now, errors.New("connection refused"))
+
+		l := refreshes.List(now)
+		Expect(l[0].Pending()).To(BeFalse())
+		Expect(l[0].Error).To(Equal("connection refused"))
+	})
+
+	When("a manifest is refreshed again", func() {
+		It("leaves the later refresh pending when the earlier one is processed", func() {
+			earlier := refreshes.Request(details, now)
+			refreshes.Request(details, now.Add(time.Second))
+			refreshes.Process(earlier, now.Add(2*time.Second), nil)
+
+			l := refreshes.List(now)
+			Expect(l).To(HaveLen(1))
+			Expect(l[0].CacheTime).To(Equal(int64(1600000001000)))
+			Expect(l[0].Pending()).To(BeTrue())
+		})
+	})
+
+	It("forgets refreshes requested before the retention", func() {
+		refreshes.Request(details, now)
+		other := details
+		other.Name = "service test-service"
+		refreshes.Request(other, now.Add(30*time.Minute))
+
+		l := refreshes.List(now.Add(time.Hour + time.Minute))
+		Expect(l).To(HaveLen(1))
+		Expect(l[0].Details.Name).To(Equal("service test-service"))
+	})
+})
@@ -0,0 +1,46 @@
+package openapi
+
+import (
+	"fmt"
+	"html"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+const (
+	// Swagger UI is loaded from a CDN, so the UI needs no assets in this binary.
+	swaggerUIURL = "https://unpkg.com/swagger-ui-dist@3"
+	uiTemplate   = `<!DOCTYPE html>
+<html>
+<head>
+  <meta charset="utf-8">
+  <title>go-clouddriver API</title>
+  <link rel="stylesheet" href="%[1]s/swagger-ui.css">
+</head>
+<body>
+  <div id="swagger-ui"></div>
+  <script src="%[1]s/swagger-ui-bundle.js"></script>
+  <script>
+    window.ui = SwaggerUIBundle({url: "%[2]s", dom_id: "#swagger-ui"});
+  </script>
+</body>
+</html>
+`
+)
+
+// Handler serves spec as JSON.
+func Handler(spec Spec) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		c.JSON(http.StatusOK, spec)
+	}
+}
+
+// UIHandler serves a Swagger UI of the spec served at specPath.
+func UIHandler(specPath string) gin.HandlerFunc {
+	page := fmt.Sprintf(uiTemplate, swaggerUIURL, html.EscapeString(specPath))
+
+	return func(c *gin.Context) {
+		c.Data(http.StatusOK, "text/html; charset=utf-8", []byte(page))
+	}
+}
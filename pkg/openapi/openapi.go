@@ -0,0 +1,379 @@
+// Package openapi generates an OpenAPI 3 spec of the gin routes of the API,
+// describing the bodies of documented routes by reflecting on their Go types.
+package openapi
+
+import (
+	"encoding"
+	"encoding/json"
+	"net/http"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+const (
+	openAPIVersion  = "3.0.3"
+	contentTypeJSON = "application/json"
+)
+
+// Doc documents a route, by "METHOD /path" as registered with gin.
+type Doc struct {
+	Summary string
+	// Query are the names of the query params of the route.
+	Query []string
+	// Request and Response are values of the types of the request and
+	// response bodies, such as core.Version{}. Nil if the route has none.
+	Request  interface{}
+	Response interface{}
+}
+
+// Spec is an OpenAPI 3 document.
+type Spec struct {
+	OpenAPI    string              `json:"openapi"`
+	Info       Info                `json:"info"`
+	Paths      map[string]PathItem `json:"paths"`
+	Components Components          `json:"components"`
+}
+
+type Info struct {
+	Title   string `json:"title"`
+	Version string `json:"version"`
+}
+
+// PathItem holds the operations of a path by lowercase method.
+type PathItem map[string]*Operation
+
+type Operation struct {
+	OperationID string              `json:"operationId"`
+	Summary     string              `json:"summary,omitempty"`
+	Tags        []string            `json:"tags,omitempty"`
+	Parameters  []Parameter         `json:"parameters,omitempty"`
+	RequestBody *RequestBody        `json:"requestBody,omitempty"`
+	Responses   map[string]Response `json:"responses"`
+}
+
+type Parameter struct {
+	Name     string  `json:"name"`
+	In       string  `json:"in"`
+	Required bool    `json:"required,omitempty"`
+	Schema   *Schema `json:"schema"`
+}
+
+type RequestBody struct {
+	Required bool                 `json:"required"`
+	Content  map[string]MediaType `json:"content"`
+}
+
+type Response struct {
+	Description string               `json:"description"`
+	Content     map[string]MediaType `json:"content,omitempty"`
+}
+
+type MediaType struct {
+	Schema *Schema `json:"schema"`
+}
+
+type Components struct {
+	Schemas map[string]*Schema `json:"schemas"`
+}
+
+// Schema is a JSON schema, as used by OpenAPI 3.0.
+type Schema struct {
+	Ref                  string             `json:"$ref,omitempty"`
+	Type                 string             `json:"type,omitempty"`
+	Format               string             `json:"format,omitempty"`
+	Items                *Schema            `json:"items,omitempty"`
+	Properties           map[string]*Schema `json:"properties,omitempty"`
+	AdditionalProperties *Schema            `json:"additionalProperties,omitempty"`
+}
+
+// Generate returns the spec of routes. Every route is listed with its path
+// params; the summary, query params and bodies of routes come from docs.
+// Error responses are described by errorResponse.
+func Generate(info Info, routes gin.RoutesInfo, docs map[string]Doc, errorResponse interface{}) Spec {
+	g := &generator{
+		schemas: map[string]*Schema{},
+		names:   map[reflect.Type]string{},
+	}
+	spec := Spec{
+		OpenAPI: openAPIVersion,
+		Info:    info,
+		Paths:   map[string]PathItem{},
+	}
+
+	sort.Slice(routes, func(i, j int) bool {
+		if routes[i].Path == routes[j].Path {
+			return routes[i].Method < routes[j].Method
+		}
+
+		return routes[i].Path < routes[j].Path
+	})
+
+	errorSchema := g.schema(reflect.TypeOf(errorResponse))
+	operationIDs := map[string]bool{}
+
+	for _, route := range routes {
+		path, params := pathParams(route.Path)
+		doc := docs[route.Method+" "+route.Path]
+
+		op := &Operation{
+			OperationID: handlerName(route.Handler),
+			Summary:     doc.Summary,
+			Parameters:  params,
+			Responses: map[string]Response{
+				"default": {
+					Description: "Error",
+					Content:     map[string]MediaType{contentTypeJSON: {Schema: errorSchema}},
+				},
+			},
+		}
+
+		// Handlers serving several routes, such as for GET and POST, keep
+		// operation IDs unique by their method.
+		if operationIDs[op.OperationID] {
+			id := op.OperationID + strings.Title(strings.ToLower(route.Method))
+			op.OperationID = id
+
+			for n := 2; operationIDs[op.OperationID]; n++ {
+				op.OperationID = id + strconv.Itoa(n)
+			}
+		}
+
+		operationIDs[op.OperationID] = true
+
+		if tag := tag(route.Path); tag != "" {
+			op.Tags = []string{tag}
+		}
+
+		for _, name := range doc.Query {
+			op.Parameters = append(op.Parameters, Parameter{
+				Name:   name,
+				In:     "query",
+				Schema: &Schema{Type: "string"},
+			})
+		}
+
+		if doc.Request != nil {
+			op.RequestBody = &RequestBody{
+				Required: true,
+				Content:  map[string]MediaType{contentTypeJSON: {Schema: g.schema(reflect.TypeOf(doc.Request))}},
+			}
+		}
+
+		ok := Response{Description: http.StatusText(http.StatusOK)}
+		if doc.Response != nil {
+			ok.Content = map[string]MediaType{contentTypeJSON: {Schema: g.schema(reflect.TypeOf(doc.Response))}}
+		}
+
+		op.Responses["200"] = ok
+
+		if spec.Paths[path] == nil {
+			spec.Paths[path] = PathItem{}
+		}
+
+		spec.Paths[path][strings.ToLower(route.Method)] = op
+	}
+
+	spec.Components.Schemas = g.schemas
+
+	return spec
+}
+
+// pathParams converts the :name and *name params of a gin path to {name},
+// returning them as required path params.
+func pathParams(path string) (string, []Parameter) {
+	segments := strings.Split(path, "/")
+	params := []Parameter{}
+
+	for i, segment := range segments {
+		if strings.HasPrefix(segment, ":") || strings.HasPrefix(segment, "*") {
+			name := segment[1:]
+			segments[i] = "{" + name + "}"
+			params = append(params, Parameter{
+				Name:     name,
+				In:       "path",
+				Required: true,
+				Schema:   &Schema{Type: "string"},
+			})
+		}
+	}
+
+	return strings.Join(segments, "/"), params
+}
+
+// handlerName returns the name of a handler function without its package,
+// such as ListCredentials for github.com/billiford/go-clouddriver/pkg/http/core.ListCredentials.
+func handlerName(handler string) string {
+	name := handler[strings.LastIndex(handler, "/")+1:]
+	name = name[strings.Index(name, ".")+1:]
+
+	return strings.TrimSuffix(strings.ReplaceAll(name, ".", ""), "-fm")
+}
+
+// tag groups routes by the first segment of their path, or the first two
+// of versioned routes, such as v1/kubernetes.
+func tag(path string) string {
+	segments := strings.Split(strings.TrimPrefix(path, "/"), "/")
+	if segments[0] == "v1" && len(segments) > 1 {
+		return segments[0] + "/" + segments[1]
+	}
+
+	return segments[0]
+}
+
+var (
+	timeType          = reflect.TypeOf(time.Time{})
+	rawMessageType    = reflect.TypeOf(json.RawMessage{})
+	jsonMarshalerType = reflect.TypeOf((*json.Marshaler)(nil)).Elem()
+	textMarshalerType = reflect.TypeOf((*encoding.TextMarshaler)(nil)).Elem()
+)
+
+type generator struct {
+	schemas map[string]*Schema
+	names   map[reflect.Type]string
+}
+
+// schema returns the schema of t. Named structs are added to the components
+// of the spec once and referenced.
+func (g *generator) schema(t reflect.Type) *Schema {
+	for t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+
+	switch {
+	case t == timeType || isTimeWrapper(t):
+		return &Schema{Type: "string", Format: "date-time"}
+	case t == rawMessageType:
+		return &Schema{}
+	case t.Kind() == reflect.Struct && implements(t, textMarshalerType):
+		return &Schema{Type: "string"}
+	case t.Kind() == reflect.Struct && implements(t, jsonMarshalerType):
+		// Marshaled to a shape reflection cannot tell.
+		return &Schema{}
+	}
+
+	switch t.Kind() {
+	case reflect.Bool:
+		return &Schema{Type: "boolean"}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Uint8, reflect.Uint16, reflect.Uint32:
+		return &Schema{Type: "integer", Format: "int32"}
+	case reflect.Int64, reflect.Uint, reflect.Uint64:
+		return &Schema{Type: "integer", Format: "int64"}
+	case reflect.Float32:
+		return &Schema{Type: "number", Format: "float"}
+	case reflect.Float64:
+		return &Schema{Type: "number", Format: "double"}
+	case reflect.String:
+		return &Schema{Type: "string"}
+	case reflect.Slice, reflect.Array:
+		if t.Elem().Kind() == reflect.Uint8 {
+			return &Schema{Type: "string", Format: "byte"}
+		}
+
+		return &Schema{Type: "array", Items: g.schema(t.Elem())}
+	case reflect.Map:
+		return &Schema{Type: "object", AdditionalProperties: g.schema(t.Elem())}
+	case reflect.Struct:
+		if t.Name() == "" {
+			return g.object(t)
+		}
+
+		name, ok := g.names[t]
+		if !ok {
+			name = g.name(t)
+			// Reserve the name first so recursive types reference themselves.
+			g.names[t] = name
+			g.schemas[name] = &Schema{}
+			*g.schemas[name] = *g.object(t)
+		}
+
+		return &Schema{Ref: "#/components/schemas/" + name}
+	}
+
+	// Interfaces can hold any value.
+	return &Schema{}
+}
+
+// object returns the schema of a struct, with the properties of embedded
+// structs promoted as encoding/json does.
+func (g *generator) object(t reflect.Type) *Schema {
+	s := &Schema{Type: "object", Properties: map[string]*Schema{}}
+
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		tag := f.Tag.Get("json")
+
+		if tag == "-" || (f.PkgPath != "" && !f.Anonymous) {
+			continue
+		}
+
+		name := strings.Split(tag, ",")[0]
+
+		if f.Anonymous && name == "" {
+			ft := f.Type
+			for ft.Kind() == reflect.Ptr {
+				ft = ft.Elem()
+			}
+
+			if ft.Kind() == reflect.Struct {
+				for k, v := range g.object(ft).Properties {
+					if _, ok := s.Properties[k]; !ok {
+						s.Properties[k] = v
+					}
+				}
+
+				continue
+			}
+		}
+
+		if f.PkgPath != "" {
+			continue
+		}
+
+		if name == "" {
+			name = f.Name
+		}
+
+		s.Properties[name] = g.schema(f.Type)
+	}
+
+	return s
+}
+
+// name names the schema of a type by its package and name, such as
+// clouddriver.Credential. Types of packages with the same name, such as
+// pkg/kubernetes and pkg/http/core/kubernetes, are told apart by the
+// packages they are in.
+func (g *generator) name(t reflect.Type) string {
+	segments := strings.Split(t.PkgPath(), "/")
+
+	for i := len(segments) - 1; i >= 0; i-- {
+		pkg := strings.Join(segments[i:], ".")
+		// The root package of the module is named clouddriver.
+		if pkg == "pkg" {
+			pkg = "clouddriver"
+		}
+
+		name := pkg + "." + t.Name()
+		if _, taken := g.schemas[name]; !taken {
+			return name
+		}
+	}
+
+	return strings.Join(segments, ".") + "." + t.Name()
+}
+
+// isTimeWrapper returns true for structs that only wrap a time.Time and
+// marshal to it, such as metav1.Time.
+func isTimeWrapper(t reflect.Type) bool {
+	return t.Kind() == reflect.Struct && t.NumField() == 1 && t.Field(0).Type == timeType &&
+		implements(t, jsonMarshalerType)
+}
+
+func implements(t, iface reflect.Type) bool {
+	return t.Implements(iface) || reflect.PtrTo(t).Implements(iface)
+}
@@ -0,0 +1,143 @@
+package openapi_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"time"
+
+	. "github.com/billiford/go-clouddriver/pkg/openapi"
+	"github.com/gin-gonic/gin"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+type testError struct {
+	Message string `json:"message"`
+}
+
+type testMetadata struct {
+	Labels map[string]string `json:"labels,omitempty"`
+}
+
+type testItem struct {
+	testMetadata
+	Name       string                 `json:"name"`
+	Replicas   int32                  `json:"replicas"`
+	Created    time.Time              `json:"created"`
+	Updated    metav1.Time            `json:"updated"`
+	Manifest   map[string]interface{} `json:"manifest"`
+	Children   []testItem             `json:"children"`
+	Secret     string                 `json:"-"`
+	Untagged   bool
+	unexported string
+}
+
+func ListItems(c *gin.Context) {}
+
+func CreateItem(c *gin.Context) {}
+
+func ServeProfile(c *gin.Context) {}
+
+var _ = Describe("OpenAPI", func() {
+	var (
+		r    *gin.Engine
+		spec Spec
+	)
+
+	BeforeEach(func() {
+		gin.SetMode(gin.ReleaseMode)
+		r = gin.New()
+		r.GET("/items", ListItems)
+		r.POST("/items/:name", CreateItem)
+		r.GET("/debug/*profile", ServeProfile)
+		r.POST("/debug/*profile", ServeProfile)
+	})
+
+	JustBeforeEach(func() {
+		spec = Generate(Info{Title: "test", Version: "1.0.0"}, r.Routes(), map[string]Doc{
+			"GET /items": {
+				Summary:  "List items",
+				Query:    []string{"expand"},
+				Response: []testItem{},
+			},
+			"POST /items/:name": {
+				Request:  &testItem{},
+				Response: testItem{},
+			},
+		}, testError{})
+	})
+
+	It("lists every route with its path params", func() {
+		Expect(spec.OpenAPI).To(Equal("3.0.3"))
+		Expect(spec.Paths).To(HaveLen(3))
+
+		op := spec.Paths["/items/{name}"]["post"]
+		Expect(op).ToNot(BeNil())
+		Expect(op.OperationID).To(Equal("CreateItem"))
+		Expect(op.Tags).To(Equal([]string{"items"}))
+		Expect(op.Parameters).To(Equal([]Parameter{
+			{Name: "name", In: "path", Required: true, Schema: &Schema{Type: "string"}},
+		}))
+
+		Expect(spec.Paths["/debug/{profile}"]["get"].OperationID).To(Equal("ServeProfile"))
+		Expect(spec.Paths["/debug/{profile}"]["post"].OperationID).To(Equal("ServeProfilePost"))
+		Expect(spec.Paths["/debug/{profile}"]["get"].Responses["200"].Content).To(BeNil())
+	})
+
+	It("describes the documented query params and bodies", func() {
+		op := spec.Paths["/items"]["get"]
+		Expect(op.Summary).To(Equal("List items"))
+		Expect(op.Parameters).To(Equal([]Parameter{
+			{Name: "expand", In: "query", Schema: &Schema{Type: "string"}},
+		}))
+		Expect(op.Responses["200"].Content["application/json"].Schema).To(Equal(&Schema{
+			Type:  "array",
+			Items: &Schema{Ref: "#/components/schemas/openapi_test.testItem"},
+		}))
+		Expect(op.Responses["default"].Content["application/json"].Schema.Ref).To(Equal("#/components/schemas/openapi_test.testError"))
+
+		op = spec.Paths["/items/{name}"]["post"]
+		Expect(op.RequestBody.Required).To(BeTrue())
+		Expect(op.RequestBody.Content["application/json"].Schema.Ref).To(Equal("#/components/schemas/openapi_test.testItem"))
+	})
+
+	It("generates the schemas of the types by their JSON fields", func() {
+		Expect(spec.Components.Schemas).To(HaveLen(2))
+		Expect(spec.Components.Schemas["openapi_test.testItem"]).To(Equal(&Schema{
+			Type: "object",
+			Properties: map[string]*Schema{
+				"labels":   {Type: "object", AdditionalProperties: &Schema{Type: "string"}},
+				"name":     {Type: "string"},
+				"replicas": {Type: "integer", Format: "int32"},
+				"created":  {Type: "string", Format: "date-time"},
+				"updated":  {Type: "string", Format: "date-time"},
+				"manifest": {Type: "object", AdditionalProperties: &Schema{}},
+				"children": {Type: "array", Items: &Schema{Ref: "#/components/schemas/openapi_test.testItem"}},
+				"Untagged": {Type: "boolean"},
+			},
+		}))
+	})
+
+	Describe("#Handler", func() {
+		It("serves the spec and its UI", func() {
+			r.GET("/swagger.json", Handler(spec))
+			r.GET("/swagger-ui", UIHandler("/swagger.json"))
+
+			w := httptest.NewRecorder()
+			r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/swagger.json", nil))
+			Expect(w.Code).To(Equal(http.StatusOK))
+			served := Spec{}
+			Expect(json.Unmarshal(w.Body.Bytes(), &served)).To(Succeed())
+			Expect(served.Info.Title).To(Equal("test"))
+			Expect(served.Paths).To(HaveKey("/items"))
+
+			w = httptest.NewRecorder()
+			r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/swagger-ui", nil))
+			Expect(w.Code).To(Equal(http.StatusOK))
+			Expect(w.Header().Get("Content-Type")).To(Equal("text/html; charset=utf-8"))
+			Expect(w.Body.String()).To(ContainSubstring(`url: "/swagger.json"`))
+		})
+	})
+})
@@ -0,0 +1,20 @@
+package clouddriver
+
+import "time"
+
+// FailedOperation is the payload of a task whose operations failed, kept so
+// admins can replay it under a new task ID. Payloads are stored compressed by
+// the hash in PayloadHash.
+type FailedOperation struct {
+	TaskID      string    `json:"taskId" gorm:"primary_key"`
+	Application string    `json:"application"`
+	User        string    `json:"user"`
+	Payload     string    `json:"payload" gorm:"type:text"`
+	PayloadHash string    `json:"-" gorm:"index"`
+	Error       string    `json:"error" gorm:"type:text"`
+	CreatedAt   time.Time `json:"createdAt"`
+}
+
+func (FailedOperation) TableName() string {
+	return "failed_operations"
+}
@@ -1,8 +1,9 @@
 package clouddriver
 
 type Permissions struct {
-	READ  []string `json:"READ"`
-	WRITE []string `json:"WRITE"`
+	READ    []string `json:"READ"`
+	WRITE   []string `json:"WRITE"`
+	EXECUTE []string `json:"EXECUTE,omitempty"`
 }
 
 type ReadPermission struct {
@@ -24,3 +25,28 @@ type WritePermission struct {
 func (WritePermission) TableName() string {
 	return "provider_write_permissions"
 }
+
+// ExecutePermission is a group that may run operations on an account.
+type ExecutePermission struct {
+	ID           string `json:"-" gorm:"primary_key"`
+	AccountName  string `json:"accountName"`
+	ExecuteGroup string `json:"executeGroup"`
+}
+
+func (ExecutePermission) TableName() string {
+	return "provider_execute_permissions"
+}
+
+// NamespacePermission is a group with an authorization, READ, WRITE or
+// EXECUTE, to a namespace of an account.
+type NamespacePermission struct {
+	ID            string `json:"-" gorm:"primary_key"`
+	AccountName   string `json:"accountName"`
+	Namespace     string `json:"namespace"`
+	Authorization string `json:"authorization"`
+	GroupName     string `json:"groupName"`
+}
+
+func (NamespacePermission) TableName() string {
+	return "provider_namespace_permissions"
+}
@@ -0,0 +1,169 @@
+// Package project reads the definitions of Spinnaker projects, which group
+// clusters of applications across accounts for the Projects dashboard in Deck.
+package project
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+const (
+	ControllerInstanceKey = "ProjectController"
+)
+
+//go:generate counterfeiter . Controller
+type Controller interface {
+	GetProject(string) (Project, bool)
+}
+
+// Project is a project definition, in the format front50 stores them:
+//
+//	{
+//	  "name": "my-project",
+//	  "email": "team@example.com",
+//	  "config": {
+//	    "applications": ["app1", "app2"],
+//	    "clusters": [
+//	      {
+//	        "account": "prod-account",
+//	        "stack": "prod",
+//	        "detail": "*"
+//	      }
+//	    ]
+//	  }
+//	}
+type Project struct {
+	Name   string `json:"name"`
+	Email  string `json:"email,omitempty"`
+	Config Config `json:"config"`
+}
+
+type Config struct {
+	Applications []string  `json:"applications"`
+	Clusters     []Cluster `json:"clusters"`
+}
+
+// Cluster selects the clusters of applications in an account by their stack
+// and detail, where "*" matches any stack or detail. Applications default to
+// those of the project.
+type Cluster struct {
+	Account      string   `json:"account"`
+	Stack        string   `json:"stack"`
+	Detail       string   `json:"detail"`
+	Applications []string `json:"applications,omitempty"`
+}
+
+// Matches returns true if the cluster of an application, such as
+// "deployment my-app-prod-canary", has the stack and detail of c.
+// The cluster's name is parsed as application-stack-detail.
+func (c Cluster) Matches(application, cluster string) bool {
+	// Kubernetes clusters are prefixed with their kind.
+	if i := strings.LastIndex(cluster, " "); i >= 0 {
+		cluster = cluster[i+1:]
+	}
+
+	if cluster != application && !strings.HasPrefix(cluster, application+"-") {
+		return false
+	}
+
+	parts := strings.SplitN(strings.TrimPrefix(cluster, application+"-"), "-", 2)
+	if cluster == application {
+		parts = []string{""}
+	}
+
+	stack, detail := parts[0], ""
+	if len(parts) == 2 {
+		detail = parts[1]
+	}
+
+	return matches(c.Stack, stack) && matches(c.Detail, detail)
+}
+
+func matches(pattern, s string) bool {
+	return pattern == "*" || pattern == s
+}
+
+var (
+	defaultConfigDir = "/opt/spinnaker/projects/config"
+)
+
+// NewDefaultController reads project definitions from /opt/spinnaker/projects/config.
+// Projects are optional, so if the directory does not exist no projects are defined.
+func NewDefaultController() (Controller, error) {
+	if _, err := os.Stat(defaultConfigDir); os.IsNotExist(err) {
+		return &controller{projects: map[string]Project{}}, nil
+	}
+
+	return NewController(defaultConfigDir)
+}
+
+// NewController reads project definitions from the JSON files in dir, one project per file.
+func NewController(dir string) (Controller, error) {
+	c := controller{projects: map[string]Project{}}
+
+	files, err := ioutil.ReadDir(dir)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, f := range files {
+		if f.IsDir() {
+			continue
+		}
+
+		path := filepath.Join(dir, f.Name())
+
+		// Handle symlinks for ConfigMaps.
+		ln, err := filepath.EvalSymlinks(path)
+		if err == nil {
+			path = ln
+		}
+
+		b, err := ioutil.ReadFile(path)
+		if err != nil {
+			// Symlinks to dirs when using kubernetes ConfigMaps, see the artifact credentials controller.
+			continue
+		}
+
+		p := Project{}
+
+		err = json.Unmarshal(b, &p)
+		if err != nil {
+			return nil, err
+		}
+
+		if p.Name == "" {
+			return nil, fmt.Errorf("no \"name\" found in project config file %s", path)
+		}
+
+		name := strings.ToLower(p.Name)
+		if _, ok := c.projects[name]; ok {
+			return nil, fmt.Errorf("duplicate project listed: %s", p.Name)
+		}
+
+		c.projects[name] = p
+	}
+
+	return &c, nil
+}
+
+type controller struct {
+	// Projects by lowercase name, as Deck does not preserve their case.
+	projects map[string]Project
+}
+
+// GetProject returns a project by name, ignoring case.
+func (c *controller) GetProject(name string) (Project, bool) {
+	p, ok := c.projects[strings.ToLower(name)]
+	return p, ok
+}
+
+func ControllerInstance(c *gin.Context) Controller {
+	return c.MustGet(ControllerInstanceKey).(Controller)
+}
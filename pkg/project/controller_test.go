@@ -0,0 +1,73 @@
+package project_test
+
+import (
+	. "github.com/billiford/go-clouddriver/pkg/project"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Controller", func() {
+	var (
+		c   Controller
+		err error
+		dir string
+	)
+
+	BeforeEach(func() {
+		dir = "test"
+	})
+
+	Describe("#NewController", func() {
+		JustBeforeEach(func() {
+			c, err = NewController(dir)
+		})
+
+		When("the directory does not exist", func() {
+			BeforeEach(func() {
+				dir = "i-dont-exist"
+			})
+
+			It("returns an error", func() {
+				Expect(err).ToNot(BeNil())
+			})
+		})
+
+		When("it succeeds", func() {
+			It("gets projects ignoring case", func() {
+				Expect(err).To(BeNil())
+				p, ok := c.GetProject("my-project")
+				Expect(ok).To(BeTrue())
+				Expect(p.Name).To(Equal("My-Project"))
+				Expect(p.Config.Applications).To(Equal([]string{"app1", "app2"}))
+				Expect(p.Config.Clusters).To(Equal([]Cluster{
+					{
+						Account: "prod-account",
+						Stack:   "prod",
+						Detail:  "*",
+					},
+				}))
+				_, ok = c.GetProject("other-project")
+				Expect(ok).To(BeFalse())
+			})
+		})
+	})
+
+	Describe("#Matches", func() {
+		It("matches clusters by stack and detail", func() {
+			cluster := Cluster{Stack: "prod", Detail: "*"}
+			Expect(cluster.Matches("app1", "deployment app1-prod")).To(BeTrue())
+			Expect(cluster.Matches("app1", "deployment app1-prod-canary")).To(BeTrue())
+			Expect(cluster.Matches("app1", "deployment app1-staging")).To(BeFalse())
+			Expect(cluster.Matches("app1", "deployment app1")).To(BeFalse())
+			Expect(cluster.Matches("app1", "deployment app10-prod")).To(BeFalse())
+
+			cluster = Cluster{Stack: "", Detail: ""}
+			Expect(cluster.Matches("app1", "statefulSet app1")).To(BeTrue())
+			Expect(cluster.Matches("app1", "statefulSet app1-prod")).To(BeFalse())
+
+			cluster = Cluster{Stack: "*", Detail: "canary"}
+			Expect(cluster.Matches("app1", "app1-prod-canary")).To(BeTrue())
+			Expect(cluster.Matches("app1", "app1-prod")).To(BeFalse())
+		})
+	})
+})
@@ -0,0 +1,313 @@
+package queue
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+const (
+	InstanceKey = `Queue`
+)
+
+// Priority is the class of an operation. Waiting operations of a higher
+// priority are admitted before those of a lower priority against the same
+// account, so urgent rollbacks are not stuck behind bulk deploys.
+type Priority int
+
+const (
+	// PriorityBackground is for bulk work nobody is waiting on, such as the reaper.
+	PriorityBackground Priority = iota
+	// PriorityDeploy is for operations run by pipelines.
+	PriorityDeploy
+	// PriorityRollback is for rollbacks, which are usually run during incidents.
+	PriorityRollback
+
+	numPriorities = 3
+)
+
+var (
+	waiting = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Name: "clouddriver_operation_queue_waiting",
+		Help: "Number of operations waiting to be admitted by priority.",
+	}, []string{"priority"})
+)
+
+func init() {
+	prometheus.MustRegister(waiting)
+}
+
+func (p Priority) String() string {
+	switch p {
+	case PriorityBackground:
+		return "background"
+	case PriorityDeploy:
+		return "deploy"
+	case PriorityRollback:
+		return "rollback"
+	default:
+		return fmt.Sprintf("Priority(%d)", int(p))
+	}
+}
+
+// Config is the token bucket of each account.
+type Config struct {
+	// Rate is the number of operations admitted per second for each account.
+	Rate float64
+	// Burst is the number of operations admitted at once for an idle
+	// account. Defaults to 1.
+	Burst int
+	// MaxWait is how long an operation waits to be admitted before failing.
+	// Operations wait as long as their request when zero.
+	MaxWait time.Duration
+}
+
+// Enabled returns true if operations are rate-limited.
+func (c Config) Enabled() bool {
+	return c.Rate > 0
+}
+
+// Queue admits operations against accounts at a configured rate.
+//
+//go:generate counterfeiter . Queue
+type Queue interface {
+	Wait(context.Context, string, Priority) error
+	// Status returns the status of the bucket of each account, sorted by account.
+	Status() []AccountStatus
+}
+
+// AccountStatus is the state of the bucket of an account.
+type AccountStatus struct {
+	Account string `json:"account"`
+	// Tokens is the number of operations that would be admitted right away.
+	Tokens float64 `json:"tokens"`
+	// Waiting is the number of operations waiting to be admitted by priority.
+	Waiting map[string]int `json:"waiting"`
+}
+
+// New returns a Queue that admits operations against each account at the
+// rate and burst of config.
+func New(config Config) Queue {
+	if config.Burst <= 0 {
+		config.Burst = 1
+	}
+
+	return &queue{
+		config:  config,
+		buckets: map[string]*bucket{},
+	}
+}
+
+type queue struct {
+	mux     sync.Mutex
+	config  Config
+	buckets map[string]*bucket
+}
+
+type bucket struct {
+	tokens float64
+	last   time.Time
+	// Waiters of each priority, oldest first.
+	waiters    [numPriorities][]*waiter
+	dispatched bool
+}
+
+type waiter struct {
+	admitted chan struct{}
+}
+
+// Wait blocks until an operation of priority p against account is admitted,
+// returning an error if ctx is done or the operation waits longer than
+// the max wait first.
+func (q *queue) Wait(ctx context.Context, account string, p Priority) error {
+	if p < 0 || p >= numPriorities {
+		return fmt.Errorf("unknown operation priority %s", p)
+	}
+
+	q.mux.Lock()
+
+	b := q.bucket(account)
+	b.refill(time.Now(), q.config)
+
+	// Operations of a lower priority never hold back one of a higher priority.
+	if b.tokens >= 1 && !b.waiting(p) {
+		b.tokens--
+		q.mux.Unlock()
+
+		return nil
+	}
+
+	w := &waiter{admitted: make(chan struct{})}
+	b.waiters[p] = append(b.waiters[p], w)
+	waiting.WithLabelValues(p.String()).Inc()
+	q.schedule(account, b)
+	q.mux.Unlock()
+
+	if q.config.MaxWait > 0 {
+		var cancel context.CancelFunc
+
+		ctx, cancel = context.WithTimeout(ctx, q.config.MaxWait)
+		defer cancel()
+	}
+
+	select {
+	case <-w.admitted:
+		return nil
+	case <-ctx.Done():
+	}
+
+	q.mux.Lock()
+	defer q.mux.Unlock()
+
+	// The operation may have been admitted while the lock was released.
+	select {
+	case <-w.admitted:
+		return nil
+	default:
+	}
+
+	b.remove(p, w)
+	waiting.WithLabelValues(p.String()).Dec()
+
+	return fmt.Errorf("%s operation against account %s was not admitted: %w", p, account, ctx.Err())
+}
+
+func (q *queue) Status() []AccountStatus {
+	q.mux.Lock()
+	defer q.mux.Unlock()
+
+	now := time.Now()
+	statuses := []AccountStatus{}
+
+	for account, b := range q.buckets {
+		b.refill(now, q.config)
+
+		s := AccountStatus{
+			Account: account,
+			Tokens:  b.tokens,
+			Waiting: map[string]int{},
+		}
+
+		for p := Priority(0); p < numPriorities; p++ {
+			s.Waiting[p.String()] = len(b.waiters[p])
+		}
+
+		statuses = append(statuses, s)
+	}
+
+	sort.Slice(statuses, func(i, j int) bool {
+		return statuses[i].Account < statuses[j].Account
+	})
+
+	return statuses
+}
+
+// bucket returns the bucket of an account, which starts full. It must be
+// called with the lock held.
+func (q *queue) bucket(account string) *bucket {
+	b, ok := q.buckets[account]
+	if !ok {
+		b = &bucket{
+			tokens: float64(q.config.Burst),
+			last:   time.Now(),
+		}
+		q.buckets[account] = b
+	}
+
+	return b
+}
+
+// schedule admits the waiters of a bucket as tokens are added to it. It
+// must be called with the lock held.
+func (q *queue) schedule(account string, b *bucket) {
+	if b.dispatched {
+		return
+	}
+
+	b.dispatched = true
+	d := time.Duration((1 - b.tokens) / q.config.Rate * float64(time.Second))
+
+	time.AfterFunc(d, func() {
+		q.dispatch(account, b)
+	})
+}
+
+// dispatch admits as many waiters as there are tokens, highest priority first.
+func (q *queue) dispatch(account string, b *bucket) {
+	q.mux.Lock()
+	defer q.mux.Unlock()
+
+	b.dispatched = false
+	b.refill(time.Now(), q.config)
+
+	for b.tokens >= 1 {
+		p, w := b.next()
+		if w == nil {
+			break
+		}
+
+		b.tokens--
+		b.remove(p, w)
+		waiting.WithLabelValues(p.String()).Dec()
+		close(w.admitted)
+	}
+
+	if p, _ := b.next(); p >= 0 {
+		q.schedule(account, b)
+	}
+}
+
+// refill adds the tokens earned since the bucket was last refilled.
+func (b *bucket) refill(now time.Time, config Config) {
+	b.tokens += now.Sub(b.last).Seconds() * config.Rate
+	if b.tokens > float64(config.Burst) {
+		b.tokens = float64(config.Burst)
+	}
+
+	b.last = now
+}
+
+// waiting returns true if an operation of at least priority p is waiting.
+func (b *bucket) waiting(p Priority) bool {
+	for i := p; i < numPriorities; i++ {
+		if len(b.waiters[i]) > 0 {
+			return true
+		}
+	}
+
+	return false
+}
+
+// next returns the oldest waiter of the highest priority, or a priority
+// of -1 if none are waiting.
+func (b *bucket) next() (Priority, *waiter) {
+	for p := Priority(numPriorities - 1); p >= 0; p-- {
+		if len(b.waiters[p]) > 0 {
+			return p, b.waiters[p][0]
+		}
+	}
+
+	return -1, nil
+}
+
+func (b *bucket) remove(p Priority, w *waiter) {
+	waiters := b.waiters[p]
+
+	for i := range waiters {
+		if waiters[i] == w {
+			b.waiters[p] = append(waiters[:i:i], waiters[i+1:]...)
+			return
+		}
+	}
+}
+
+// Instance returns the Queue, or nil if operations are not rate-limited.
+func Instance(c *gin.Context) Queue {
+	q, _ := c.MustGet(InstanceKey).(Queue)
+	return q
+}
@@ -0,0 +1,159 @@
+package queue_test
+
+import (
+	"context"
+	"time"
+
+	. "github.com/billiford/go-clouddriver/pkg/queue"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Queue", func() {
+	var (
+		config Config
+		q      Queue
+		ctx    context.Context
+		err    error
+	)
+
+	BeforeEach(func() {
+		config = Config{
+			Rate:  20,
+			Burst: 2,
+		}
+		ctx = context.Background()
+	})
+
+	JustBeforeEach(func() {
+		q = New(config)
+	})
+
+	Describe("#Enabled", func() {
+		It("returns true when a rate is configured", func() {
+			Expect(config.Enabled()).To(BeTrue())
+			Expect(Config{}.Enabled()).To(BeFalse())
+		})
+	})
+
+	Describe("#String", func() {
+		It("names the priority", func() {
+			Expect(PriorityRollback.String()).To(Equal("rollback"))
+			Expect(Priority(7).String()).To(Equal("Priority(7)"))
+		})
+	})
+
+	Describe("#Wait", func() {
+		It("admits a burst of operations at once", func() {
+			start := time.Now()
+			Expect(q.Wait(ctx, "test-account", PriorityDeploy)).To(Succeed())
+			Expect(q.Wait(ctx, "test-account", PriorityDeploy)).To(Succeed())
+			Expect(time.Since(start)).To(BeNumerically("<", 50*time.Millisecond))
+		})
+
+		It("rate-limits each account separately", func() {
+			Expect(q.Wait(ctx, "test-account", PriorityDeploy)).To(Succeed())
+			Expect(q.Wait(ctx, "test-account", PriorityDeploy)).To(Succeed())
+			Expect(q.Wait(ctx, "other-account", PriorityDeploy)).To(Succeed())
+
+			start := time.Now()
+			Expect(q.Wait(ctx, "test-account", PriorityDeploy)).To(Succeed())
+			Expect(time.Since(start)).To(BeNumerically(">=", 40*time.Millisecond))
+		})
+
+		It("admits waiting operations of a higher priority first", func() {
+			Expect(q.Wait(ctx, "test-account", PriorityDeploy)).To(Succeed())
+			Expect(q.Wait(ctx, "test-account", PriorityDeploy)).To(Succeed())
+
+			admitted := make(chan Priority, 3)
+			wait := func(p Priority) {
+				defer GinkgoRecover()
+				Expect(q.Wait(ctx, "test-account", p)).To(Succeed())
+				admitted <- p
+			}
+
+			go wait(PriorityBackground)
+			time.Sleep(5 * time.Millisecond)
+			go wait(PriorityDeploy)
+			time.Sleep(5 * time.Millisecond)
+			go wait(PriorityRollback)
+
+			Eventually(admitted).Should(Receive(Equal(PriorityRollback)))
+			Eventually(admitted).Should(Receive(Equal(PriorityDeploy)))
+			Eventually(admitted).Should(Receive(Equal(PriorityBackground)))
+		})
+
+		When("the priority is unknown", func() {
+			It("returns an error", func() {
+				err = q.Wait(ctx, "test-account", Priority(7))
+				Expect(err).ToNot(BeNil())
+				Expect(err.Error()).To(Equal("unknown operation priority Priority(7)"))
+			})
+		})
+
+		When("the operation waits longer than the max wait", func() {
+			BeforeEach(func() {
+				config.Rate = 1
+				config.Burst = 1
+				config.MaxWait = 10 * time.Millisecond
+			})
+
+			It("returns an error", func() {
+				Expect(q.Wait(ctx, "test-account", PriorityDeploy)).To(Succeed())
+				err = q.Wait(ctx, "test-account", PriorityDeploy)
+				Expect(err).ToNot(BeNil())
+				Expect(err.Error()).To(Equal("deploy operation against account test-account was not admitted: context deadline exceeded"))
+			})
+		})
+
+		When("the context is canceled", func() {
+			BeforeEach(func() {
+				config.Rate = 1
+				config.Burst = 1
+			})
+
+			It("returns an error", func() {
+				Expect(q.Wait(ctx, "test-account", PriorityDeploy)).To(Succeed())
+
+				var cancel context.CancelFunc
+				ctx, cancel = context.WithCancel(ctx)
+				cancel()
+
+				err = q.Wait(ctx, "test-account", PriorityRollback)
+				Expect(err).ToNot(BeNil())
+				Expect(err.Error()).To(Equal("rollback operation against account test-account was not admitted: context canceled"))
+			})
+		})
+	})
+
+	Describe("#Status", func() {
+		BeforeEach(func() {
+			config.Rate = 0.001
+			config.Burst = 1
+		})
+
+		It("returns the bucket of each account", func() {
+			Expect(q.Status()).To(BeEmpty())
+
+			Expect(q.Wait(ctx, "test-account", PriorityDeploy)).To(Succeed())
+			Expect(q.Wait(ctx, "other-account", PriorityDeploy)).To(Succeed())
+
+			ctx, cancel := context.WithCancel(ctx)
+			defer cancel()
+
+			go q.Wait(ctx, "test-account", PriorityRollback)
+
+			Eventually(func() int {
+				return q.Status()[1].Waiting["rollback"]
+			}).Should(Equal(1))
+
+			statuses := q.Status()
+			Expect(statuses).To(HaveLen(2))
+			Expect(statuses[0].Account).To(Equal("other-account"))
+			Expect(statuses[0].Tokens).To(BeNumerically("<", 1))
+			Expect(statuses[0].Waiting).To(Equal(map[string]int{"background": 0, "deploy": 0, "rollback": 0}))
+			Expect(statuses[1].Account).To(Equal("test-account"))
+			Expect(statuses[1].Waiting["deploy"]).To(Equal(0))
+		})
+	})
+})
@@ -0,0 +1,441 @@
+package reaper
+
+import (
+	"context"
+	"encoding/base64"
+	"fmt"
+	"log"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/billiford/go-clouddriver/pkg/arcade"
+	"github.com/billiford/go-clouddriver/pkg/kubernetes"
+	"github.com/billiford/go-clouddriver/pkg/queue"
+	"github.com/billiford/go-clouddriver/pkg/sql"
+	"github.com/prometheus/client_golang/prometheus"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/client-go/rest"
+)
+
+// Policy is what the reaper does with the orphaned resources it finds.
+type Policy string
+
+const (
+	PolicyNone   Policy = ``
+	PolicyReport Policy = `report`
+	PolicyDelete Policy = `delete`
+
+	ReasonOrphanedApplication = `orphaned_application`
+	ReasonVersionHistory      = `version_history`
+	ReasonFinishedJob         = `finished_job`
+
+	defaultInterval = time.Hour
+)
+
+var (
+	// DefaultKinds are the kinds the reaper lists when none are configured.
+	DefaultKinds = []string{
+		"deployments",
+		"replicaSets",
+		"statefulSets",
+		"daemonSets",
+		"services",
+		"ingresses",
+		"configMaps",
+		"secrets",
+		"jobs",
+		"cronJobs",
+	}
+
+	resourcesReaped = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "clouddriver_reaper_resources_total",
+		Help: "Number of orphaned resources found by the reaper by reason and policy.",
+	}, []string{"reason", "policy"})
+
+	listTimeout = int64(30)
+)
+
+func init() {
+	prometheus.MustRegister(resourcesReaped)
+}
+
+// NewPolicy returns the policy named s, which is empty when the reaper is disabled.
+func NewPolicy(s string) (Policy, error) {
+	p := Policy(strings.ToLower(s))
+
+	switch p {
+	case PolicyNone, PolicyReport, PolicyDelete:
+		return p, nil
+	default:
+		return p, fmt.Errorf("unknown reaper policy %q, must be report or delete", s)
+	}
+}
+
+// Config defines what the reaper looks for and what it does with it.
+type Config struct {
+	// How often to reap. Defaults to an hour.
+	Interval time.Duration
+	// Report or delete the resources found.
+	Policy Policy
+	// Find resources of applications deleted by the deleteApplication
+	// operation. Applications that were never created are not orphaned.
+	OrphanedApplications bool
+	// How many versions of a versioned resource to keep when it does not
+	// set the strategy.spinnaker.io/max-version-history annotation. Zero
+	// disables the version history check.
+	MaxVersionHistory int
+	// Find Jobs run by runJob that finished longer ago than their TTL, for
+	// clusters that do not delete them by their ttlSecondsAfterFinished.
+	// The kinds must include jobs.
+	FinishedJobs bool
+	// The kinds to list. Defaults to DefaultKinds.
+	Kinds []string
+	// Queue deletes behind other operations against the account, if set.
+	Queue queue.Queue
+}
+
+// Enabled returns true if a policy and at least one check are configured.
+func (c Config) Enabled() bool {
+	return c.Policy != PolicyNone && (c.OrphanedApplications || c.MaxVersionHistory > 0 || c.FinishedJobs)
+}
+
+// Finding is an orphaned resource found by the reaper.
+type Finding struct {
+	Account   string
+	Kind      string
+	Name      string
+	Namespace string
+	Reason    string
+	Message   string
+}
+
+// Run reaps on the configured interval until the context is done.
+func Run(ctx context.Context, sc sql.Client, ac arcade.Client, kc kubernetes.Controller, c Config) {
+	interval := c.Interval
+	if interval <= 0 {
+		interval = defaultInterval
+	}
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		Reap(sc, ac, kc, c)
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// Reap finds the orphaned resources of each account once and reports or
+// deletes them per policy. Errors are logged and do not stop the other
+// accounts or kinds from being reaped.
+func Reap(sc sql.Client, ac arcade.Client, kc kubernetes.Controller, c Config) []Finding {
+	findings := []Finding{}
+
+	var deleted map[string]bool
+
+	if c.OrphanedApplications {
+		names, err := sc.ListDeletedApplications()
+		if err != nil {
+			log.Println("[REAPER] error listing deleted applications:", err.Error())
+			return findings
+		}
+
+		deleted = map[string]bool{}
+		for _, name := range names {
+			deleted[strings.ToLower(name)] = true
+		}
+	}
+
+	providers, err := sc.ListKubernetesProviders()
+	if err != nil {
+		log.Println("[REAPER] error listing kubernetes providers:", err.Error())
+		return findings
+	}
+
+	kinds := c.Kinds
+	if len(kinds) == 0 {
+		kinds = DefaultKinds
+	}
+
+	for _, provider := range providers {
+		client, err := newClient(ac, kc, provider)
+		if err != nil {
+			log.Println("[REAPER] error creating client for account", provider.Name+":", err.Error())
+			continue
+		}
+
+		for _, kind := range kinds {
+			list, err := client.ListResource(kind, metav1.ListOptions{
+				LabelSelector:  kubernetes.ManagedLabelSelector(),
+				TimeoutSeconds: &listTimeout,
+			})
+			if err != nil {
+				log.Println("[REAPER] error listing", kind, "for account", provider.Name+":", err.Error())
+				continue
+			}
+
+			found := Find(list.Items, deleted, c.MaxVersionHistory)
+			if c.FinishedJobs {
+				found = appendNew(found, FindFinishedJobs(list.Items, time.Now()))
+			}
+
+			for _, f := range found {
+				f.Account = provider.Name
+				reap(client, c, f)
+				findings = append(findings, f)
+			}
+		}
+	}
+
+	return findings
+}
+
+func newClient(ac arcade.Client, kc kubernetes.Controller, provider kubernetes.Provider) (kubernetes.Client, error) {
+	cd, err := base64.StdEncoding.DecodeString(provider.CAData)
+	if err != nil {
+		return nil, err
+	}
+
+	token, err := ac.Token()
+	if err != nil {
+		return nil, err
+	}
+
+	config := &rest.Config{
+		Host:        provider.Host,
+		BearerToken: token,
+		TLSClientConfig: rest.TLSClientConfig{
+			CAData: cd,
+		},
+	}
+
+	return kubernetes.NewProviderClient(kc, provider, config)
+}
+
+func reap(client kubernetes.Client, c Config, f Finding) {
+	resourcesReaped.WithLabelValues(f.Reason, string(c.Policy)).Inc()
+
+	if c.Policy != PolicyDelete {
+		log.Println("[REAPER] found", f.Kind, f.Name, "in namespace", f.Namespace,
+			"of account", f.Account+":", f.Message)
+		return
+	}
+
+	if c.Queue != nil {
+		err := c.Queue.Wait(context.Background(), f.Account, queue.PriorityBackground)
+		if err != nil {
+			log.Println("[REAPER] error deleting", f.Kind, f.Name, "in namespace", f.Namespace,
+				"of account", f.Account+":", err.Error())
+			return
+		}
+	}
+
+	propagation := metav1.DeletePropagationBackground
+
+	err := client.DeleteResourceByKindAndNameAndNamespace(f.Kind, f.Name, f.Namespace,
+		metav1.DeleteOptions{PropagationPolicy: &propagation})
+	if err != nil {
+		log.Println("[REAPER] error deleting", f.Kind, f.Name, "in namespace", f.Namespace,
+			"of account", f.Account+":", err.Error())
+		return
+	}
+
+	log.Println("[REAPER] deleted", f.Kind, f.Name, "in namespace", f.Namespace,
+		"of account", f.Account+":", f.Message)
+}
+
+// Find returns the resources of items whose application is one of the
+// deleted applications, or whose version exceeds its history limit. The
+// version history check is skipped when maxVersionHistory is zero. Resources owned by another resource are
+// left for their owner's garbage collection, and pinned resources are kept.
+func Find(items []unstructured.Unstructured, deleted map[string]bool, maxVersionHistory int) []Finding {
+	findings := []Finding{}
+	versions := map[string][]versioned{}
+
+	for _, item := range items {
+		if len(item.GetOwnerReferences()) > 0 || kubernetes.Pinned(&item) {
+			continue
+		}
+
+		app := application(item)
+
+		if app != "" && deleted[strings.ToLower(app)] {
+			findings = append(findings, newFinding(item, ReasonOrphanedApplication,
+				fmt.Sprintf("application %s was deleted", app)))
+
+			continue
+		}
+
+		if maxVersionHistory <= 0 {
+			continue
+		}
+
+		annotations := item.GetAnnotations()
+
+		sequence, err := strconv.Atoi(annotations[kubernetes.AnnotationSpinnakerMonikerSequence])
+		if err != nil {
+			continue
+		}
+
+		cluster := annotations[kubernetes.AnnotationSpinnakerMonikerCluster]
+		if cluster == "" {
+			cluster = annotations[kubernetes.AnnotationSpinnakerArtifactName]
+		}
+
+		group := item.GetKind() + "/" + item.GetNamespace() + "/" + cluster
+		versions[group] = append(versions[group], versioned{item: item, sequence: sequence})
+	}
+
+	groups := []string{}
+	for group := range versions {
+		groups = append(groups, group)
+	}
+
+	sort.Strings(groups)
+
+	for _, group := range groups {
+		vs := versions[group]
+
+		// Newest first.
+		sort.Slice(vs, func(i, j int) bool {
+			return vs[i].sequence > vs[j].sequence
+		})
+
+		limit := maxVersionHistory
+		if l, err := strconv.Atoi(vs[0].item.GetAnnotations()[kubernetes.AnnotationSpinnakerMaxVersionHistory]); err == nil {
+			limit = l
+		}
+
+		if limit <= 0 || len(vs) <= limit {
+			continue
+		}
+
+		for _, v := range vs[limit:] {
+			if scaledUp(v.item) {
+				continue
+			}
+
+			findings = append(findings, newFinding(v.item, ReasonVersionHistory,
+				fmt.Sprintf("version %d exceeds the max version history of %d", v.sequence, limit)))
+		}
+	}
+
+	return findings
+}
+
+// FindFinishedJobs returns the Jobs of items annotated with a TTL that
+// finished, succeeded or failed, longer than their TTL before now.
+func FindFinishedJobs(items []unstructured.Unstructured, now time.Time) []Finding {
+	findings := []Finding{}
+
+	for _, item := range items {
+		if !strings.EqualFold(item.GetKind(), "job") || kubernetes.Pinned(&item) {
+			continue
+		}
+
+		seconds, err := strconv.ParseInt(item.GetAnnotations()[kubernetes.AnnotationJobTTLSecondsAfterFinished], 10, 64)
+		if err != nil || seconds < 0 {
+			continue
+		}
+
+		finished, ok := finishedAt(item)
+		if !ok {
+			continue
+		}
+
+		ttl := time.Duration(seconds) * time.Second
+		if now.Sub(finished) < ttl {
+			continue
+		}
+
+		findings = append(findings, newFinding(item, ReasonFinishedJob,
+			fmt.Sprintf("job finished at %s, more than its TTL of %s ago", finished.Format(time.RFC3339), ttl)))
+	}
+
+	return findings
+}
+
+// finishedAt returns when a Job completed or failed, or false if it has not
+// finished.
+func finishedAt(u unstructured.Unstructured) (time.Time, bool) {
+	if s, ok, _ := unstructured.NestedString(u.Object, "status", "completionTime"); ok {
+		t, err := time.Parse(time.RFC3339, s)
+		return t, err == nil
+	}
+
+	conditions, _, _ := unstructured.NestedSlice(u.Object, "status", "conditions")
+	for _, c := range conditions {
+		condition, _ := c.(map[string]interface{})
+		if condition["type"] != "Failed" || condition["status"] != "True" {
+			continue
+		}
+
+		s, _ := condition["lastTransitionTime"].(string)
+		t, err := time.Parse(time.RFC3339, s)
+
+		return t, err == nil
+	}
+
+	return time.Time{}, false
+}
+
+// appendNew appends the findings of resources not already found.
+func appendNew(findings, more []Finding) []Finding {
+	for _, m := range more {
+		found := false
+
+		for _, f := range findings {
+			if f.Kind == m.Kind && f.Name == m.Name && f.Namespace == m.Namespace {
+				found = true
+				break
+			}
+		}
+
+		if !found {
+			findings = append(findings, m)
+		}
+	}
+
+	return findings
+}
+
+type versioned struct {
+	item     unstructured.Unstructured
+	sequence int
+}
+
+func newFinding(u unstructured.Unstructured, reason, message string) Finding {
+	return Finding{
+		Kind:      u.GetKind(),
+		Name:      u.GetName(),
+		Namespace: u.GetNamespace(),
+		Reason:    reason,
+		Message:   message,
+	}
+}
+
+// application returns the application of a resource from its moniker,
+// falling back to its name label.
+func application(u unstructured.Unstructured) string {
+	if app := u.GetAnnotations()[kubernetes.AnnotationSpinnakerMonikerApplication]; app != "" {
+		return app
+	}
+
+	return u.GetLabels()[kubernetes.LabelKubernetesName]
+}
+
+// scaledUp returns true if a workload still runs pods, so an old version
+// that is still serving traffic is not reaped.
+func scaledUp(u unstructured.Unstructured) bool {
+	replicas, ok, _ := unstructured.NestedInt64(u.Object, "spec", "replicas")
+
+	return ok && replicas > 0
+}
@@ -0,0 +1,443 @@
+package reaper_test
+
+import (
+	"errors"
+	"io/ioutil"
+	"log"
+	"time"
+
+	"github.com/billiford/go-clouddriver/pkg/arcade/arcadefakes"
+	"github.com/billiford/go-clouddriver/pkg/kubernetes"
+	"github.com/billiford/go-clouddriver/pkg/kubernetes/kubernetesfakes"
+	. "github.com/billiford/go-clouddriver/pkg/reaper"
+	"github.com/billiford/go-clouddriver/pkg/sql/sqlfakes"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+func newResource(kind, name string, annotations map[string]string) unstructured.Unstructured {
+	u := unstructured.Unstructured{Object: map[string]interface{}{}}
+	u.SetKind(kind)
+	u.SetName(name)
+	u.SetNamespace("default")
+	u.SetAnnotations(annotations)
+
+	return u
+}
+
+func newVersion(name, sequence string) unstructured.Unstructured {
+	return newResource("ConfigMap", name, map[string]string{
+		kubernetes.AnnotationSpinnakerMonikerApplication: "test-app",
+		kubernetes.AnnotationSpinnakerMonikerCluster:     "configMap test-config",
+		kubernetes.AnnotationSpinnakerMonikerSequence:    sequence,
+	})
+}
+
+var _ = Describe("Reaper", func() {
+	BeforeEach(func() {
+		log.SetOutput(ioutil.Discard)
+	})
+
+	Describe("#NewPolicy", func() {
+		It("returns known policies", func() {
+			p, err := NewPolicy("Delete")
+			Expect(err).To(BeNil())
+			Expect(p).To(Equal(PolicyDelete))
+		})
+
+		It("errors on unknown policies", func() {
+			_, err := NewPolicy("destroy")
+			Expect(err).ToNot(BeNil())
+			Expect(err.Error()).To(Equal(`unknown reaper policy "destroy", must be report or delete`))
+		})
+	})
+
+	Describe("#Enabled", func() {
+		When("no policy is configured", func() {
+			It("returns false", func() {
+				Expect(Config{OrphanedApplications: true}.Enabled()).To(BeFalse())
+			})
+		})
+
+		When("no check is configured", func() {
+			It("returns false", func() {
+				Expect(Config{Policy: PolicyReport, Interval: time.Minute}.Enabled()).To(BeFalse())
+			})
+		})
+
+		When("a policy and a check are configured", func() {
+			It("returns true", func() {
+				Expect(Config{Policy: PolicyReport, MaxVersionHistory: 5}.Enabled()).To(BeTrue())
+				Expect(Config{Policy: PolicyDelete, FinishedJobs: true}.Enabled()).To(BeTrue())
+			})
+		})
+	})
+
+	Describe("#Find", func() {
+		var (
+			items             []unstructured.Unstructured
+			deleted           map[string]bool
+			maxVersionHistory int
+			findings          []Finding
+		)
+
+		BeforeEach(func() {
+			items = []unstructured.Unstructured{
+				newResource("Deployment", "kept", map[string]string{
+					kubernetes.AnnotationSpinnakerMonikerApplication: "Test-App",
+				}),
+				newResource("Deployment", "orphaned", map[string]string{
+					kubernetes.AnnotationSpinnakerMonikerApplication: "deleted-app",
+				}),
+			}
+			deleted = map[string]bool{"deleted-app": true}
+			maxVersionHistory = 0
+		})
+
+		JustBeforeEach(func() {
+			findings = Find(items, deleted, maxVersionHistory)
+		})
+
+		It("finds resources of deleted applications", func() {
+			Expect(findings).To(Equal([]Finding{
+				{
+					Kind:      "Deployment",
+					Name:      "orphaned",
+					Namespace: "default",
+					Reason:    ReasonOrphanedApplication,
+					Message:   "application deleted-app was deleted",
+				},
+			}))
+		})
+
+		When("the application is only labeled", func() {
+			BeforeEach(func() {
+				u := newResource("Service", "labeled", nil)
+				u.SetLabels(map[string]string{kubernetes.LabelKubernetesName: "Deleted-App"})
+				items = []unstructured.Unstructured{u}
+			})
+
+			It("uses the label", func() {
+				Expect(findings).To(HaveLen(1))
+				Expect(findings[0].Message).To(Equal("application Deleted-App was deleted"))
+			})
+		})
+
+		When("the application was never created", func() {
+			BeforeEach(func() {
+				items = []unstructured.Unstructured{
+					newResource("Deployment", "uncreated", map[string]string{
+						kubernetes.AnnotationSpinnakerMonikerApplication: "uncreated-app",
+					}),
+				}
+			})
+
+			It("keeps it", func() {
+				Expect(findings).To(BeEmpty())
+			})
+		})
+
+		When("the resource is owned by another resource", func() {
+			BeforeEach(func() {
+				items[1].SetOwnerReferences([]metav1.OwnerReference{{Kind: "Deployment", Name: "owner"}})
+			})
+
+			It("leaves it for garbage collection", func() {
+				Expect(findings).To(BeEmpty())
+			})
+		})
+
+		When("the resource is pinned", func() {
+			BeforeEach(func() {
+				items[1].SetAnnotations(map[string]string{
+					kubernetes.AnnotationSpinnakerMonikerApplication: "deleted-app",
+					kubernetes.AnnotationSpinnakerPinned:             "true",
+				})
+			})
+
+			It("keeps it", func() {
+				Expect(findings).To(BeEmpty())
+			})
+		})
+
+		When("applications are not checked", func() {
+			BeforeEach(func() {
+				deleted = nil
+			})
+
+			It("finds nothing", func() {
+				Expect(findings).To(BeEmpty())
+			})
+		})
+
+		Context("version history", func() {
+			BeforeEach(func() {
+				items = []unstructured.Unstructured{
+					newVersion("test-config-v000", "0"),
+					newVersion("test-config-v002", "2"),
+					newVersion("test-config-v001", "1"),
+				}
+				maxVersionHistory = 2
+			})
+
+			It("finds versions past the history limit", func() {
+				Expect(findings).To(Equal([]Finding{
+					{
+						Kind:      "ConfigMap",
+						Name:      "test-config-v000",
+						Namespace: "default",
+						Reason:    ReasonVersionHistory,
+						Message:   "version 0 exceeds the max version history of 2",
+					},
+				}))
+			})
+
+			When("the resource sets its own limit", func() {
+				BeforeEach(func() {
+					items[1].SetAnnotations(map[string]string{
+						kubernetes.AnnotationSpinnakerMonikerCluster:    "configMap test-config",
+						kubernetes.AnnotationSpinnakerMonikerSequence:   "2",
+						kubernetes.AnnotationSpinnakerMaxVersionHistory: "1",
+					})
+				})
+
+				It("uses the annotation", func() {
+					Expect(findings).To(HaveLen(2))
+					Expect(findings[0].Name).To(Equal("test-config-v001"))
+					Expect(findings[1].Name).To(Equal("test-config-v000"))
+				})
+			})
+
+			When("an old version is still scaled up", func() {
+				BeforeEach(func() {
+					items[0].SetKind("ReplicaSet")
+					items[0].Object["spec"] = map[string]interface{}{"replicas": int64(1)}
+					items[1].SetKind("ReplicaSet")
+					items[2].SetKind("ReplicaSet")
+					maxVersionHistory = 1
+				})
+
+				It("keeps it", func() {
+					Expect(findings).To(HaveLen(1))
+					Expect(findings[0].Name).To(Equal("test-config-v001"))
+				})
+			})
+
+			When("the version history check is disabled", func() {
+				BeforeEach(func() {
+					maxVersionHistory = 0
+				})
+
+				It("finds nothing", func() {
+					Expect(findings).To(BeEmpty())
+				})
+			})
+		})
+	})
+
+	Describe("#FindFinishedJobs", func() {
+		var (
+			now      time.Time
+			items    []unstructured.Unstructured
+			findings []Finding
+		)
+
+		newJob := func(name string, status map[string]interface{}) unstructured.Unstructured {
+			u := newResource("Job", name, map[string]string{
+				kubernetes.AnnotationJobTTLSecondsAfterFinished: "3600",
+			})
+			u.Object["status"] = status
+
+			return u
+		}
+
+		BeforeEach(func() {
+			now = time.Date(2020, 11, 1, 12, 0, 0, 0, time.UTC)
+			items = []unstructured.Unstructured{
+				newJob("succeeded", map[string]interface{}{"completionTime": "2020-11-01T10:00:00Z"}),
+				newJob("failed", map[string]interface{}{
+					"conditions": []interface{}{
+						map[string]interface{}{
+							"type":               "Failed",
+							"status":             "True",
+							"lastTransitionTime": "2020-11-01T10:30:00Z",
+						},
+					},
+				}),
+				newJob("recent", map[string]interface{}{"completionTime": "2020-11-01T11:30:00Z"}),
+				newJob("running", map[string]interface{}{"active": int64(1)}),
+				newResource("Job", "no-ttl", nil),
+			}
+		})
+
+		JustBeforeEach(func() {
+			findings = FindFinishedJobs(items, now)
+		})
+
+		It("finds jobs finished longer than their TTL ago", func() {
+			Expect(findings).To(Equal([]Finding{
+				{
+					Kind:      "Job",
+					Name:      "succeeded",
+					Namespace: "default",
+					Reason:    ReasonFinishedJob,
+					Message:   "job finished at 2020-11-01T10:00:00Z, more than its TTL of 1h0m0s ago",
+				},
+				{
+					Kind:      "Job",
+					Name:      "failed",
+					Namespace: "default",
+					Reason:    ReasonFinishedJob,
+					Message:   "job finished at 2020-11-01T10:30:00Z, more than its TTL of 1h0m0s ago",
+				},
+			}))
+		})
+	})
+
+	Describe("#Reap", func() {
+		var (
+			fakeSQLClient      *sqlfakes.FakeClient
+			fakeArcadeClient   *arcadefakes.FakeClient
+			fakeKubeController *kubernetesfakes.FakeController
+			fakeKubeClient     *kubernetesfakes.FakeClient
+			config             Config
+			findings           []Finding
+		)
+
+		BeforeEach(func() {
+			fakeSQLClient = &sqlfakes.FakeClient{}
+			fakeSQLClient.ListDeletedApplicationsReturns([]string{"deleted-app"}, nil)
+			fakeSQLClient.ListKubernetesProvidersReturns([]kubernetes.Provider{{Name: "test-account"}}, nil)
+			fakeArcadeClient = &arcadefakes.FakeClient{}
+			fakeKubeClient = &kubernetesfakes.FakeClient{}
+			fakeKubeClient.ListResourceReturns(&unstructured.UnstructuredList{}, nil)
+			fakeKubeClient.ListResourceReturnsOnCall(0, &unstructured.UnstructuredList{
+				Items: []unstructured.Unstructured{
+					newResource("Deployment", "orphaned", map[string]string{
+						kubernetes.AnnotationSpinnakerMonikerApplication: "deleted-app",
+					}),
+				},
+			}, nil)
+			fakeKubeController = &kubernetesfakes.FakeController{}
+			fakeKubeController.NewClientReturns(fakeKubeClient, nil)
+			config = Config{
+				Policy:               PolicyReport,
+				OrphanedApplications: true,
+				Kinds:                []string{"deployments", "services"},
+			}
+		})
+
+		JustBeforeEach(func() {
+			findings = Reap(fakeSQLClient, fakeArcadeClient, fakeKubeController, config)
+		})
+
+		It("lists the configured kinds managed by spinnaker", func() {
+			Expect(fakeKubeClient.ListResourceCallCount()).To(Equal(2))
+			kind, lo := fakeKubeClient.ListResourceArgsForCall(0)
+			Expect(kind).To(Equal("deployments"))
+			Expect(lo.LabelSelector).To(Equal("app.kubernetes.io/managed-by=spinnaker"))
+		})
+
+		It("reports without deleting", func() {
+			Expect(findings).To(HaveLen(1))
+			Expect(findings[0].Account).To(Equal("test-account"))
+			Expect(fakeKubeClient.DeleteResourceByKindAndNameAndNamespaceCallCount()).To(BeZero())
+		})
+
+		When("the policy is delete", func() {
+			BeforeEach(func() {
+				config.Policy = PolicyDelete
+			})
+
+			It("deletes the resources found", func() {
+				Expect(fakeKubeClient.DeleteResourceByKindAndNameAndNamespaceCallCount()).To(Equal(1))
+				kind, name, namespace, do := fakeKubeClient.DeleteResourceByKindAndNameAndNamespaceArgsForCall(0)
+				Expect(kind).To(Equal("Deployment"))
+				Expect(name).To(Equal("orphaned"))
+				Expect(namespace).To(Equal("default"))
+				Expect(*do.PropagationPolicy).To(Equal(metav1.DeletePropagationBackground))
+			})
+		})
+
+		When("finished jobs are reaped", func() {
+			BeforeEach(func() {
+				job := newResource("Job", "finished", map[string]string{
+					kubernetes.AnnotationSpinnakerMonikerApplication: "deleted-app",
+					kubernetes.AnnotationJobTTLSecondsAfterFinished:  "0",
+				})
+				job.Object["status"] = map[string]interface{}{"completionTime": "2020-11-01T10:00:00Z"}
+				fakeKubeClient.ListResourceReturnsOnCall(1, &unstructured.UnstructuredList{
+					Items: []unstructured.Unstructured{job},
+				}, nil)
+				config.FinishedJobs = true
+				config.Kinds = []string{"deployments", "jobs"}
+			})
+
+			It("finds each resource once", func() {
+				Expect(findings).To(HaveLen(2))
+				Expect(findings[1].Name).To(Equal("finished"))
+				Expect(findings[1].Reason).To(Equal(ReasonOrphanedApplication))
+			})
+		})
+
+		When("no applications were deleted", func() {
+			BeforeEach(func() {
+				fakeSQLClient.ListDeletedApplicationsReturns([]string{}, nil)
+			})
+
+			It("finds nothing", func() {
+				Expect(findings).To(BeEmpty())
+			})
+		})
+
+		When("listing deleted applications returns an error", func() {
+			BeforeEach(func() {
+				fakeSQLClient.ListDeletedApplicationsReturns(nil, errors.New("error listing deleted applications"))
+			})
+
+			It("does not reap", func() {
+				Expect(findings).To(BeEmpty())
+				Expect(fakeKubeClient.ListResourceCallCount()).To(BeZero())
+			})
+		})
+
+		When("the account is scoped to namespaces", func() {
+			BeforeEach(func() {
+				fakeSQLClient.ListKubernetesProvidersReturns([]kubernetes.Provider{
+					{Name: "test-account", Namespaces: []string{"other"}},
+				}, nil)
+				config.Policy = PolicyDelete
+			})
+
+			It("only reaps resources in them", func() {
+				Expect(findings).To(BeEmpty())
+				Expect(fakeKubeClient.DeleteResourceByKindAndNameAndNamespaceCallCount()).To(BeZero())
+			})
+		})
+
+		When("creating a client returns an error", func() {
+			BeforeEach(func() {
+				fakeSQLClient.ListKubernetesProvidersReturns([]kubernetes.Provider{{Name: "bad-account"}, {Name: "test-account"}}, nil)
+				fakeKubeController.NewClientReturnsOnCall(0, nil, errors.New("bad config"))
+			})
+
+			It("reaps the other accounts", func() {
+				Expect(findings).To(HaveLen(1))
+				Expect(findings[0].Account).To(Equal("test-account"))
+			})
+		})
+
+		When("listing a kind returns an error", func() {
+			BeforeEach(func() {
+				fakeKubeClient.ListResourceReturnsOnCall(0, nil, errors.New("error listing"))
+			})
+
+			It("lists the other kinds", func() {
+				Expect(fakeKubeClient.ListResourceCallCount()).To(Equal(2))
+				Expect(findings).To(BeEmpty())
+			})
+		})
+	})
+})
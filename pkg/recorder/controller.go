@@ -0,0 +1,60 @@
+package recorder
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/billiford/go-clouddriver/pkg/kubernetes"
+	"k8s.io/client-go/rest"
+)
+
+// NewController returns a kubernetes.Controller whose clients add each
+// call they make to the API server to rec.
+func NewController(kc kubernetes.Controller, rec *Recording) kubernetes.Controller {
+	return &controller{
+		Controller: kc,
+		rec:        rec,
+	}
+}
+
+type controller struct {
+	kubernetes.Controller
+	rec *Recording
+}
+
+func (c *controller) NewClient(config *rest.Config) (kubernetes.Client, error) {
+	config.Wrap(func(rt http.RoundTripper) http.RoundTripper {
+		return &transport{
+			rt:  rt,
+			rec: c.rec,
+		}
+	})
+
+	return c.Controller.NewClient(config)
+}
+
+type transport struct {
+	rt  http.RoundTripper
+	rec *Recording
+}
+
+func (t *transport) RoundTrip(req *http.Request) (*http.Response, error) {
+	start := time.Now()
+	res, err := t.rt.RoundTrip(req)
+
+	kc := KubernetesCall{
+		Method:   req.Method,
+		URL:      req.URL.String(),
+		Duration: time.Since(start),
+	}
+
+	if err != nil {
+		kc.Error = err.Error()
+	} else {
+		kc.Status = res.StatusCode
+	}
+
+	t.rec.AddKubernetesCall(kc)
+
+	return res, err
+}
@@ -0,0 +1,56 @@
+package recorder_test
+
+import (
+	"net/http"
+
+	"github.com/billiford/go-clouddriver/pkg/kubernetes/kubernetesfakes"
+	. "github.com/billiford/go-clouddriver/pkg/recorder"
+	"k8s.io/client-go/rest"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"github.com/onsi/gomega/ghttp"
+)
+
+var _ = Describe("Controller", func() {
+	var (
+		fakeController *kubernetesfakes.FakeController
+		fakeServer     *ghttp.Server
+		rec            *Recording
+		res            *http.Response
+		err            error
+	)
+
+	BeforeEach(func() {
+		fakeServer = ghttp.NewServer()
+		fakeServer.AppendHandlers(ghttp.RespondWith(http.StatusNotFound, "{}"))
+		fakeController = &kubernetesfakes.FakeController{}
+		fakeController.NewClientReturns(&kubernetesfakes.FakeClient{}, nil)
+		rec = &Recording{}
+	})
+
+	AfterEach(func() {
+		fakeServer.Close()
+	})
+
+	JustBeforeEach(func() {
+		kc := NewController(fakeController, rec)
+		_, err = kc.NewClient(&rest.Config{Host: fakeServer.URL()})
+		Expect(err).To(BeNil())
+
+		// Make a request with the transport the client would have used.
+		config := fakeController.NewClientArgsForCall(0)
+		rt := config.WrapTransport(http.DefaultTransport)
+		req, _ := http.NewRequest(http.MethodGet, fakeServer.URL()+"/api/v1/namespaces/default/pods/test", nil)
+		res, err = rt.RoundTrip(req)
+	})
+
+	It("records calls to the API server", func() {
+		Expect(err).To(BeNil())
+		res.Body.Close()
+		Expect(rec.KubernetesCalls).To(HaveLen(1))
+		Expect(rec.KubernetesCalls[0].Method).To(Equal(http.MethodGet))
+		Expect(rec.KubernetesCalls[0].URL).To(HaveSuffix("/api/v1/namespaces/default/pods/test"))
+		Expect(rec.KubernetesCalls[0].Status).To(Equal(http.StatusNotFound))
+	})
+})
@@ -0,0 +1,116 @@
+package recorder
+
+import (
+	"sync"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+const (
+	InstanceKey = `Recorder`
+	// Orca sends the ID of the pipeline execution that made the request.
+	HeaderSpinnakerExecutionID = `X-Spinnaker-Execution-Id`
+	DefaultSize                = 100
+)
+
+// Recording is a sanitized request/response pair along with the
+// Kubernetes API calls made while serving the request.
+type Recording struct {
+	ExecutionID     string           `json:"executionId"`
+	Time            time.Time        `json:"time"`
+	Duration        time.Duration    `json:"duration"`
+	Request         Request          `json:"request"`
+	Response        Response         `json:"response"`
+	KubernetesCalls []KubernetesCall `json:"kubernetesCalls"`
+
+	mux sync.Mutex
+}
+
+type Request struct {
+	Method  string              `json:"method"`
+	URL     string              `json:"url"`
+	Headers map[string][]string `json:"headers"`
+	Body    string              `json:"body,omitempty"`
+}
+
+type Response struct {
+	Status int    `json:"status"`
+	Body   string `json:"body,omitempty"`
+}
+
+// KubernetesCall is a request made to a cluster's API server. Bodies are
+// not recorded as they may hold secrets.
+type KubernetesCall struct {
+	Method   string        `json:"method"`
+	URL      string        `json:"url"`
+	Status   int           `json:"status"`
+	Duration time.Duration `json:"duration"`
+	Error    string        `json:"error,omitempty"`
+}
+
+// AddKubernetesCall appends a call to the recording. It is safe to
+// call concurrently, handlers often call clusters in parallel.
+func (r *Recording) AddKubernetesCall(kc KubernetesCall) {
+	r.mux.Lock()
+	defer r.mux.Unlock()
+
+	r.KubernetesCalls = append(r.KubernetesCalls, kc)
+}
+
+// Recorder holds the most recent recordings in a ring buffer.
+//
+//go:generate counterfeiter . Recorder
+type Recorder interface {
+	Record(*Recording)
+	List(string) []*Recording
+}
+
+// New returns a Recorder that keeps the last size recordings.
+func New(size int) Recorder {
+	if size <= 0 {
+		size = DefaultSize
+	}
+
+	return &recorder{
+		recordings: make([]*Recording, size),
+	}
+}
+
+type recorder struct {
+	mux        sync.RWMutex
+	recordings []*Recording
+	next       int
+}
+
+// Record adds a recording, overwriting the oldest one when the buffer is full.
+func (r *recorder) Record(rec *Recording) {
+	r.mux.Lock()
+	defer r.mux.Unlock()
+
+	r.recordings[r.next] = rec
+	r.next = (r.next + 1) % len(r.recordings)
+}
+
+// List returns the recordings of an execution, oldest first.
+func (r *recorder) List(executionID string) []*Recording {
+	r.mux.RLock()
+	defer r.mux.RUnlock()
+
+	recordings := []*Recording{}
+
+	for i := 0; i < len(r.recordings); i++ {
+		rec := r.recordings[(r.next+i)%len(r.recordings)]
+		if rec != nil && rec.ExecutionID == executionID {
+			recordings = append(recordings, rec)
+		}
+	}
+
+	return recordings
+}
+
+// Instance returns the Recorder, or nil if recording is disabled.
+func Instance(c *gin.Context) Recorder {
+	r, _ := c.MustGet(InstanceKey).(Recorder)
+	return r
+}
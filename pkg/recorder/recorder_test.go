@@ -0,0 +1,70 @@
+package recorder_test
+
+import (
+	. "github.com/billiford/go-clouddriver/pkg/recorder"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Recorder", func() {
+	var (
+		r          Recorder
+		recordings []*Recording
+	)
+
+	BeforeEach(func() {
+		r = New(3)
+	})
+
+	Describe("#List", func() {
+		JustBeforeEach(func() {
+			recordings = r.List("execution1")
+		})
+
+		When("nothing has been recorded", func() {
+			It("returns an empty list", func() {
+				Expect(recordings).ToNot(BeNil())
+				Expect(recordings).To(BeEmpty())
+			})
+		})
+
+		When("recordings exist for several executions", func() {
+			BeforeEach(func() {
+				r.Record(&Recording{ExecutionID: "execution1", Request: Request{URL: "/1"}})
+				r.Record(&Recording{ExecutionID: "execution2", Request: Request{URL: "/2"}})
+				r.Record(&Recording{ExecutionID: "execution1", Request: Request{URL: "/3"}})
+			})
+
+			It("returns the recordings of the execution, oldest first", func() {
+				Expect(recordings).To(HaveLen(2))
+				Expect(recordings[0].Request.URL).To(Equal("/1"))
+				Expect(recordings[1].Request.URL).To(Equal("/3"))
+			})
+		})
+
+		When("the buffer is full", func() {
+			BeforeEach(func() {
+				r.Record(&Recording{ExecutionID: "execution1", Request: Request{URL: "/1"}})
+				r.Record(&Recording{ExecutionID: "execution1", Request: Request{URL: "/2"}})
+				r.Record(&Recording{ExecutionID: "execution1", Request: Request{URL: "/3"}})
+				r.Record(&Recording{ExecutionID: "execution1", Request: Request{URL: "/4"}})
+			})
+
+			It("drops the oldest recording", func() {
+				Expect(recordings).To(HaveLen(3))
+				Expect(recordings[0].Request.URL).To(Equal("/2"))
+				Expect(recordings[2].Request.URL).To(Equal("/4"))
+			})
+		})
+	})
+
+	Describe("#AddKubernetesCall", func() {
+		It("appends the call", func() {
+			rec := &Recording{}
+			rec.AddKubernetesCall(KubernetesCall{Method: "GET", URL: "/api/v1/pods", Status: 200})
+			Expect(rec.KubernetesCalls).To(HaveLen(1))
+			Expect(rec.KubernetesCalls[0].URL).To(Equal("/api/v1/pods"))
+		})
+	})
+})
@@ -0,0 +1,95 @@
+package recorder
+
+import (
+	"encoding/json"
+	"net/http"
+	"strings"
+)
+
+const (
+	redacted = "[REDACTED]"
+	// Bodies are truncated to this many bytes.
+	maxBodySize = 64 * 1024
+)
+
+var (
+	sensitiveHeaders = map[string]bool{
+		"Api-Key":       true,
+		"Authorization": true,
+		"Cookie":        true,
+		"Set-Cookie":    true,
+		// The shared secret of middleware.RequireSharedSecret.
+		"X-Spinnaker-Shared-Secret": true,
+	}
+	// Lowercased JSON keys whose values are always redacted.
+	sensitiveKeys = map[string]bool{
+		"bearertoken": true,
+		"cadata":      true,
+		"password":    true,
+		"secret":      true,
+		"token":       true,
+	}
+)
+
+// SanitizeHeaders returns a copy of the headers with credentials redacted.
+func SanitizeHeaders(h http.Header) map[string][]string {
+	headers := map[string][]string{}
+
+	for key, values := range h {
+		if sensitiveHeaders[http.CanonicalHeaderKey(key)] {
+			headers[key] = []string{redacted}
+			continue
+		}
+
+		headers[key] = append([]string{}, values...)
+	}
+
+	return headers
+}
+
+// SanitizeBody redacts credentials and the data of Kubernetes secrets
+// from a JSON body. Bodies that are not JSON are returned unchanged.
+// Bodies are truncated to 64KiB.
+func SanitizeBody(b []byte) string {
+	var v interface{}
+
+	if err := json.Unmarshal(b, &v); err == nil {
+		b, _ = json.Marshal(sanitize(v))
+	}
+
+	if len(b) > maxBodySize {
+		return string(b[:maxBodySize]) + "...(truncated)"
+	}
+
+	return string(b)
+}
+
+func sanitize(v interface{}) interface{} {
+	switch t := v.(type) {
+	case map[string]interface{}:
+		isSecret := false
+		if kind, ok := t["kind"].(string); ok && strings.EqualFold(kind, "secret") {
+			isSecret = true
+		}
+
+		for key, value := range t {
+			k := strings.ToLower(key)
+			if sensitiveKeys[k] || (isSecret && (k == "data" || k == "stringdata")) {
+				t[key] = redacted
+				continue
+			}
+
+			t[key] = sanitize(value)
+		}
+
+		return t
+	case []interface{}:
+		for i, value := range t {
+			t[i] = sanitize(value)
+		}
+
+		return t
+	default:
+		return v
+	}
+}
@@ -0,0 +1,81 @@
+package recorder_test
+
+import (
+	"net/http"
+	"strings"
+
+	. "github.com/billiford/go-clouddriver/pkg/recorder"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Sanitize", func() {
+	Describe("#SanitizeHeaders", func() {
+		It("redacts credentials", func() {
+			h := http.Header{}
+			h.Set("API-Key", "some-key")
+			h.Set("Authorization", "Bearer some-token")
+			h.Set("X-Spinnaker-Shared-Secret", "some-secret")
+			h.Set("X-Spinnaker-User", "me@me.com")
+
+			headers := SanitizeHeaders(h)
+			Expect(headers["Api-Key"]).To(Equal([]string{"[REDACTED]"}))
+			Expect(headers["Authorization"]).To(Equal([]string{"[REDACTED]"}))
+			Expect(headers["X-Spinnaker-Shared-Secret"]).To(Equal([]string{"[REDACTED]"}))
+			Expect(headers["X-Spinnaker-User"]).To(Equal([]string{"me@me.com"}))
+		})
+	})
+
+	Describe("#SanitizeBody", func() {
+		var (
+			body   string
+			result string
+		)
+
+		JustBeforeEach(func() {
+			result = SanitizeBody([]byte(body))
+		})
+
+		When("the body holds credentials", func() {
+			BeforeEach(func() {
+				body = `{"name":"test-account","host":"https://localhost","caData":"abc","bearerToken":"def"}`
+			})
+
+			It("redacts them", func() {
+				Expect(result).To(MatchJSON(`{"name":"test-account","host":"https://localhost","caData":"[REDACTED]","bearerToken":"[REDACTED]"}`))
+			})
+		})
+
+		When("the body holds a secret manifest", func() {
+			BeforeEach(func() {
+				body = `[{"deployManifest":{"manifests":[{"kind":"Secret","metadata":{"name":"test"},"data":{"password":"abc"},"stringData":{"key":"def"}}]}}]`
+			})
+
+			It("redacts its data", func() {
+				Expect(result).To(MatchJSON(`[{"deployManifest":{"manifests":[{"kind":"Secret","metadata":{"name":"test"},"data":"[REDACTED]","stringData":"[REDACTED]"}]}}]`))
+			})
+		})
+
+		When("the body is not JSON", func() {
+			BeforeEach(func() {
+				body = "some text"
+			})
+
+			It("returns it unchanged", func() {
+				Expect(result).To(Equal("some text"))
+			})
+		})
+
+		When("the body is too large", func() {
+			BeforeEach(func() {
+				body = strings.Repeat("a", 70*1024)
+			})
+
+			It("truncates it", func() {
+				Expect(result).To(HaveLen(64*1024 + len("...(truncated)")))
+				Expect(result).To(HaveSuffix("...(truncated)"))
+			})
+		})
+	})
+})
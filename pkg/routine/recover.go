@@ -0,0 +1,54 @@
+package routine
+
+import (
+	"fmt"
+	"log"
+	"runtime/debug"
+
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+var (
+	panics = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "clouddriver_panics_total",
+		Help: "Number of panics recovered by where they happened, such as an operation, route or routine.",
+	}, []string{"where"})
+)
+
+func init() {
+	prometheus.MustRegister(panics)
+}
+
+// PanicError is a panic recovered from, with the stack of the goroutine
+// that panicked.
+type PanicError struct {
+	Value interface{}
+	Stack []byte
+}
+
+func (e *PanicError) Error() string {
+	return fmt.Sprintf("panic: %v", e.Value)
+}
+
+// Recovered logs v, a value recovered from a panic in where, with the stack
+// of the goroutine that panicked and counts it. It must be called from the
+// deferred function that recovered.
+func Recovered(where string, v interface{}) *PanicError {
+	pe := &PanicError{Value: v, Stack: debug.Stack()}
+
+	log.Printf("[PANIC] %s: %v\n%s", where, v, pe.Stack)
+	panics.WithLabelValues(where).Inc()
+
+	return pe
+}
+
+// Recover runs f, returning a PanicError instead of panicking if f panics.
+func Recover(where string, f func() error) (err error) {
+	defer func() {
+		if v := recover(); v != nil {
+			err = Recovered(where, v)
+		}
+	}()
+
+	return f()
+}
@@ -0,0 +1,213 @@
+// Package routine runs the background goroutines of clouddriver, labelling
+// them so they can be told apart in goroutine profiles and counting the
+// ones running, and reports goroutines that look leaked.
+package routine
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"runtime/pprof"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// LabelKey is the pprof label naming the routine a goroutine runs.
+const LabelKey = "routine"
+
+var (
+	running = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Name: "clouddriver_routines",
+		Help: "Number of background goroutines running by routine.",
+	}, []string{"routine"})
+)
+
+func init() {
+	prometheus.MustRegister(running)
+}
+
+var (
+	mux    sync.Mutex
+	counts = map[string]int{}
+	total  int
+	// idle is closed when the last goroutine returns. A WaitGroup cannot be
+	// used, as goroutines may start while a Wait that timed out still waits.
+	idle chan struct{}
+)
+
+// Go runs f in a new goroutine labelled with name, passing it ctx with the
+// label set. Goroutines that f starts inherit the label. If f panics the
+// panic is logged and counted, and the process keeps running.
+func Go(ctx context.Context, name string, f func(context.Context)) {
+	add(name, 1)
+
+	go func() {
+		defer add(name, -1)
+		defer func() {
+			if v := recover(); v != nil {
+				Recovered(name, v)
+			}
+		}()
+
+		pprof.Do(ctx, pprof.Labels(LabelKey, name), f)
+	}()
+}
+
+func add(name string, n int) {
+	mux.Lock()
+	defer mux.Unlock()
+
+	counts[name] += n
+	if counts[name] == 0 {
+		delete(counts, name)
+	}
+
+	if total == 0 {
+		idle = make(chan struct{})
+	}
+
+	total += n
+	if total == 0 {
+		close(idle)
+	}
+
+	running.WithLabelValues(name).Add(float64(n))
+}
+
+// Running returns the number of goroutines started by Go that are still
+// running, by name.
+func Running() map[string]int {
+	mux.Lock()
+	defer mux.Unlock()
+
+	r := make(map[string]int, len(counts))
+	for name, n := range counts {
+		r[name] = n
+	}
+
+	return r
+}
+
+// Wait waits up to timeout for the goroutines started by Go to return,
+// such as after cancelling their context, and returns false if some are
+// still running.
+func Wait(timeout time.Duration) bool {
+	deadline := time.After(timeout)
+
+	for {
+		mux.Lock()
+		done := idle
+		running := total > 0
+		mux.Unlock()
+
+		if !running {
+			return true
+		}
+
+		select {
+		case <-done:
+		case <-deadline:
+			return false
+		}
+	}
+}
+
+// Stack is a stack shared by Count goroutines with the same labels, as the
+// functions called, innermost first.
+type Stack struct {
+	Count     int               `json:"count"`
+	Labels    map[string]string `json:"labels,omitempty"`
+	Functions []string          `json:"functions"`
+}
+
+// Stacks returns the number of goroutines and their stacks, grouped by
+// stack and labels, those shared by the most goroutines first. Leaked
+// goroutines pile up on the same stack, so a stack shared by many
+// goroutines that keeps growing is likely a leak.
+func Stacks() (int, []Stack, error) {
+	buf := &bytes.Buffer{}
+
+	err := pprof.Lookup("goroutine").WriteTo(buf, 1)
+	if err != nil {
+		return 0, nil, err
+	}
+
+	total, stacks := parseProfile(buf.Bytes())
+
+	sort.SliceStable(stacks, func(i, j int) bool {
+		return stacks[i].Count > stacks[j].Count
+	})
+
+	return total, stacks, nil
+}
+
+// parseProfile parses a goroutine profile written with debug=1, such as
+//
+//	goroutine profile: total 2
+//	1 @ 0x43b0a5 0x4b1f2a
+//	# labels: {"routine":"janitor"}
+//	#	0x4b1f2a	main.main+0x1a	/go/src/main.go:10
+func parseProfile(b []byte) (int, []Stack) {
+	total := 0
+	stacks := []Stack{}
+
+	// The stack being parsed is an index, as appending to stacks may
+	// move them.
+	stack := -1
+
+	scanner := bufio.NewScanner(bytes.NewReader(b))
+	scanner.Buffer(make([]byte, 64*1024), 1024*1024)
+
+	for scanner.Scan() {
+		line := scanner.Text()
+
+		switch {
+		case strings.HasPrefix(line, "goroutine profile: total "):
+			total, _ = strconv.Atoi(strings.TrimPrefix(line, "goroutine profile: total "))
+		case strings.Contains(line, " @ "):
+			count, err := strconv.Atoi(line[:strings.Index(line, " @ ")])
+			if err != nil {
+				stack = -1
+				continue
+			}
+
+			stacks = append(stacks, Stack{Count: count, Functions: []string{}})
+			stack = len(stacks) - 1
+		case stack < 0:
+			continue
+		case strings.HasPrefix(line, "# labels: "):
+			labels := map[string]string{}
+			if json.Unmarshal([]byte(strings.TrimPrefix(line, "# labels: ")), &labels) == nil {
+				stacks[stack].Labels = labels
+			}
+		case strings.HasPrefix(line, "#\t"):
+			fields := strings.Split(line, "\t")
+			if len(fields) < 3 {
+				continue
+			}
+
+			function := fields[2]
+			if i := strings.LastIndex(function, "+0x"); i > 0 {
+				function = function[:i]
+			}
+
+			stacks[stack].Functions = append(stacks[stack].Functions, function)
+		}
+	}
+
+	// Goroutines that are exiting have no frames but runtime.goexit,
+	// which is not listed.
+	for i := range stacks {
+		if len(stacks[i].Functions) == 0 {
+			stacks[i].Functions = []string{"runtime.goexit"}
+		}
+	}
+
+	return total, stacks
+}
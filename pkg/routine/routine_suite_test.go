@@ -0,0 +1,13 @@
+package routine_test
+
+import (
+	"testing"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+func TestRoutine(t *testing.T) {
+	RegisterFailHandler(Fail)
+	RunSpecs(t, "Routine Suite")
+}
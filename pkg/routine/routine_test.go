@@ -0,0 +1,130 @@
+package routine_test
+
+import (
+	"context"
+	"time"
+
+	. "github.com/billiford/go-clouddriver/pkg/routine"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Routine", func() {
+	var (
+		ctx    context.Context
+		cancel context.CancelFunc
+	)
+
+	BeforeEach(func() {
+		ctx, cancel = context.WithCancel(context.Background())
+
+		for i := 0; i < 3; i++ {
+			Go(ctx, "test-routine", func(ctx context.Context) {
+				<-ctx.Done()
+			})
+		}
+	})
+
+	AfterEach(func() {
+		cancel()
+		Expect(Wait(time.Second)).To(BeTrue())
+	})
+
+	Describe("#Running", func() {
+		It("counts the goroutines running by name", func() {
+			Expect(Running()["test-routine"]).To(Equal(3))
+		})
+
+		When("the goroutines return", func() {
+			BeforeEach(func() {
+				cancel()
+				Expect(Wait(time.Second)).To(BeTrue())
+			})
+
+			It("no longer counts them", func() {
+				Expect(Running()).ToNot(HaveKey("test-routine"))
+			})
+		})
+	})
+
+	Describe("#Wait", func() {
+		It("returns false if goroutines are still running", func() {
+			Expect(Wait(10 * time.Millisecond)).To(BeFalse())
+		})
+	})
+
+	Describe("#Stacks", func() {
+		var (
+			total  int
+			stacks []Stack
+			err    error
+		)
+
+		JustBeforeEach(func() {
+			Eventually(func() []Stack {
+				total, stacks, err = Stacks()
+
+				labelled := []Stack{}
+				for _, s := range stacks {
+					if s.Labels[LabelKey] == "test-routine" {
+						labelled = append(labelled, s)
+					}
+				}
+
+				return labelled
+			}).Should(HaveLen(1))
+		})
+
+		It("groups the goroutines by stack and label", func() {
+			Expect(err).To(BeNil())
+			Expect(total).To(BeNumerically(">=", 3))
+
+			for _, s := range stacks {
+				if s.Labels[LabelKey] != "test-routine" {
+					continue
+				}
+
+				Expect(s.Count).To(Equal(3))
+				Expect(s.Functions).To(ContainElement("runtime/pprof.Do"))
+			}
+		})
+
+		It("lists the stacks shared by the most goroutines first", func() {
+			for i := 1; i < len(stacks); i++ {
+				Expect(stacks[i-1].Count).To(BeNumerically(">=", stacks[i].Count))
+			}
+		})
+	})
+
+	Describe("#Go", func() {
+		When("the goroutine panics", func() {
+			BeforeEach(func() {
+				Go(context.Background(), "test-panic", func(context.Context) {
+					panic("test panic")
+				})
+			})
+
+			It("recovers and stops counting it", func() {
+				Eventually(Running).ShouldNot(HaveKey("test-panic"))
+			})
+		})
+	})
+
+	Describe("#Recover", func() {
+		It("returns the panic as an error", func() {
+			err := Recover("test", func() error {
+				panic("test panic")
+			})
+			Expect(err).ToNot(BeNil())
+			Expect(err.Error()).To(Equal("panic: test panic"))
+
+			pe, ok := err.(*PanicError)
+			Expect(ok).To(BeTrue())
+			Expect(string(pe.Stack)).To(ContainSubstring("routine_test"))
+		})
+
+		It("returns the error of f", func() {
+			Expect(Recover("test", func() error { return nil })).To(BeNil())
+		})
+	})
+})
@@ -0,0 +1,143 @@
+package rpc
+
+import (
+	"github.com/golang/protobuf/proto"
+)
+
+// The messages of clouddriver.proto. Their protobuf struct tags describe
+// how they are encoded, so they must be kept in sync with the schema.
+
+type ListCredentialsRequest struct{}
+
+func (m *ListCredentialsRequest) Reset()         { *m = ListCredentialsRequest{} }
+func (m *ListCredentialsRequest) String() string { return proto.CompactTextString(m) }
+func (*ListCredentialsRequest) ProtoMessage()    {}
+
+type ListCredentialsResponse struct {
+	Credentials []*Credential `protobuf:"bytes,1,rep,name=credentials,proto3"`
+}
+
+func (m *ListCredentialsResponse) Reset()         { *m = ListCredentialsResponse{} }
+func (m *ListCredentialsResponse) String() string { return proto.CompactTextString(m) }
+func (*ListCredentialsResponse) ProtoMessage()    {}
+
+type Credential struct {
+	Name               string   `protobuf:"bytes,1,opt,name=name,proto3"`
+	CloudProvider      string   `protobuf:"bytes,2,opt,name=cloud_provider,json=cloudProvider,proto3"`
+	AccountType        string   `protobuf:"bytes,3,opt,name=account_type,json=accountType,proto3"`
+	Environment        string   `protobuf:"bytes,4,opt,name=environment,proto3"`
+	Namespaces         []string `protobuf:"bytes,5,rep,name=namespaces,proto3"`
+	ReadGroups         []string `protobuf:"bytes,6,rep,name=read_groups,json=readGroups,proto3"`
+	WriteGroups        []string `protobuf:"bytes,7,rep,name=write_groups,json=writeGroups,proto3"`
+	Maintenance        bool     `protobuf:"varint,8,opt,name=maintenance,proto3"`
+	MaintenanceMessage string   `protobuf:"bytes,9,opt,name=maintenance_message,json=maintenanceMessage,proto3"`
+}
+
+func (m *Credential) Reset()         { *m = Credential{} }
+func (m *Credential) String() string { return proto.CompactTextString(m) }
+func (*Credential) ProtoMessage()    {}
+
+type GetManifestRequest struct {
+	Account  string `protobuf:"bytes,1,opt,name=account,proto3"`
+	Location string `protobuf:"bytes,2,opt,name=location,proto3"`
+	Kind     string `protobuf:"bytes,3,opt,name=kind,proto3"`
+	Name     string `protobuf:"bytes,4,opt,name=name,proto3"`
+}
+
+func (m *GetManifestRequest) Reset()         { *m = GetManifestRequest{} }
+func (m *GetManifestRequest) String() string { return proto.CompactTextString(m) }
+func (*GetManifestRequest) ProtoMessage()    {}
+
+type WatchManifestRequest struct {
+	Account         string `protobuf:"bytes,1,opt,name=account,proto3"`
+	Location        string `protobuf:"bytes,2,opt,name=location,proto3"`
+	Kind            string `protobuf:"bytes,3,opt,name=kind,proto3"`
+	Name            string `protobuf:"bytes,4,opt,name=name,proto3"`
+	IntervalSeconds int32  `protobuf:"varint,5,opt,name=interval_seconds,json=intervalSeconds,proto3"`
+}
+
+func (m *WatchManifestRequest) Reset()         { *m = WatchManifestRequest{} }
+func (m *WatchManifestRequest) String() string { return proto.CompactTextString(m) }
+func (*WatchManifestRequest) ProtoMessage()    {}
+
+type Manifest struct {
+	Account  string          `protobuf:"bytes,1,opt,name=account,proto3"`
+	Location string          `protobuf:"bytes,2,opt,name=location,proto3"`
+	Name     string          `protobuf:"bytes,3,opt,name=name,proto3"`
+	Manifest []byte          `protobuf:"bytes,4,opt,name=manifest,proto3"`
+	Status   *ManifestStatus `protobuf:"bytes,5,opt,name=status,proto3"`
+}
+
+func (m *Manifest) Reset()         { *m = Manifest{} }
+func (m *Manifest) String() string { return proto.CompactTextString(m) }
+func (*Manifest) ProtoMessage()    {}
+
+type ManifestStatus struct {
+	Available *Condition `protobuf:"bytes,1,opt,name=available,proto3"`
+	Failed    *Condition `protobuf:"bytes,2,opt,name=failed,proto3"`
+	Paused    *Condition `protobuf:"bytes,3,opt,name=paused,proto3"`
+	Stable    *Condition `protobuf:"bytes,4,opt,name=stable,proto3"`
+}
+
+func (m *ManifestStatus) Reset()         { *m = ManifestStatus{} }
+func (m *ManifestStatus) String() string { return proto.CompactTextString(m) }
+func (*ManifestStatus) ProtoMessage()    {}
+
+type Condition struct {
+	State   bool   `protobuf:"varint,1,opt,name=state,proto3"`
+	Message string `protobuf:"bytes,2,opt,name=message,proto3"`
+}
+
+func (m *Condition) Reset()         { *m = Condition{} }
+func (m *Condition) String() string { return proto.CompactTextString(m) }
+func (*Condition) ProtoMessage()    {}
+
+type DeployRequest struct {
+	Account           string   `protobuf:"bytes,1,opt,name=account,proto3"`
+	Application       string   `protobuf:"bytes,2,opt,name=application,proto3"`
+	NamespaceOverride string   `protobuf:"bytes,3,opt,name=namespace_override,json=namespaceOverride,proto3"`
+	Manifests         [][]byte `protobuf:"bytes,4,rep,name=manifests,proto3"`
+}
+
+func (m *DeployRequest) Reset()         { *m = DeployRequest{} }
+func (m *DeployRequest) String() string { return proto.CompactTextString(m) }
+func (*DeployRequest) ProtoMessage()    {}
+
+type OperationResponse struct {
+	ID          string `protobuf:"bytes,1,opt,name=id,proto3"`
+	ResourceURI string `protobuf:"bytes,2,opt,name=resource_uri,json=resourceUri,proto3"`
+}
+
+func (m *OperationResponse) Reset()         { *m = OperationResponse{} }
+func (m *OperationResponse) String() string { return proto.CompactTextString(m) }
+func (*OperationResponse) ProtoMessage()    {}
+
+type GetTaskRequest struct {
+	ID string `protobuf:"bytes,1,opt,name=id,proto3"`
+}
+
+func (m *GetTaskRequest) Reset()         { *m = GetTaskRequest{} }
+func (m *GetTaskRequest) String() string { return proto.CompactTextString(m) }
+func (*GetTaskRequest) ProtoMessage()    {}
+
+type Task struct {
+	ID        string      `protobuf:"bytes,1,opt,name=id,proto3"`
+	Status    *TaskStatus `protobuf:"bytes,2,opt,name=status,proto3"`
+	Manifests [][]byte    `protobuf:"bytes,3,rep,name=manifests,proto3"`
+	Warnings  []string    `protobuf:"bytes,4,rep,name=warnings,proto3"`
+}
+
+func (m *Task) Reset()         { *m = Task{} }
+func (m *Task) String() string { return proto.CompactTextString(m) }
+func (*Task) ProtoMessage()    {}
+
+type TaskStatus struct {
+	Completed bool   `protobuf:"varint,1,opt,name=completed,proto3"`
+	Failed    bool   `protobuf:"varint,2,opt,name=failed,proto3"`
+	Phase     string `protobuf:"bytes,3,opt,name=phase,proto3"`
+	Status    string `protobuf:"bytes,4,opt,name=status,proto3"`
+}
+
+func (m *TaskStatus) Reset()         { *m = TaskStatus{} }
+func (m *TaskStatus) String() string { return proto.CompactTextString(m) }
+func (*TaskStatus) ProtoMessage()    {}
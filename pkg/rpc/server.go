@@ -0,0 +1,395 @@
+// Package rpc serves the core operations of the REST API over gRPC, for
+// internal platform services that want typed, streaming access to
+// clouddriver without going through Gate.
+package rpc
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	clouddriver "github.com/billiford/go-clouddriver/pkg"
+	kube "github.com/billiford/go-clouddriver/pkg/http/core/kubernetes"
+	"github.com/golang/protobuf/proto"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/grpc/status"
+)
+
+const (
+	headerSpinnakerApplication = `X-Spinnaker-Application`
+	// Metadata with this prefix is forwarded to the REST API as headers,
+	// such as x-spinnaker-user.
+	metadataPrefix       = "x-spinnaker-"
+	defaultWatchInterval = 5 * time.Second
+)
+
+// NewServer returns a Clouddriver server that serves each call with the
+// REST API of h, so calls are authorized, validated and recorded the same
+// way as the requests they correspond to.
+func NewServer(h http.Handler) ClouddriverServer {
+	return &server{handler: h}
+}
+
+type server struct {
+	handler http.Handler
+}
+
+// ListCredentials lists the accounts the caller can read.
+func (s *server) ListCredentials(ctx context.Context, _ *ListCredentialsRequest) (*ListCredentialsResponse, error) {
+	req, err := newRequest(ctx, http.MethodGet, "/credentials", nil)
+	if err != nil {
+		return nil, err
+	}
+
+	credentials := []clouddriver.Credential{}
+
+	err = s.do(req, &credentials)
+	if err != nil {
+		return nil, err
+	}
+
+	res := &ListCredentialsResponse{}
+
+	for _, c := range credentials {
+		res.Credentials = append(res.Credentials, &Credential{
+			Name:               c.Name,
+			CloudProvider:      c.CloudProvider,
+			AccountType:        c.AccountType,
+			Environment:        c.Environment,
+			Namespaces:         c.Namespaces,
+			ReadGroups:         c.Permissions.READ,
+			WriteGroups:        c.Permissions.WRITE,
+			Maintenance:        c.Maintenance,
+			MaintenanceMessage: c.MaintenanceMessage,
+		})
+	}
+
+	return res, nil
+}
+
+// GetManifest gets a manifest and its status from the cluster.
+func (s *server) GetManifest(ctx context.Context, in *GetManifestRequest) (*Manifest, error) {
+	if in.Account == "" || in.Location == "" || in.Kind == "" || in.Name == "" {
+		return nil, status.Error(codes.InvalidArgument, "account, location, kind and name are required")
+	}
+
+	path := "/manifests/" + url.PathEscape(in.Account) +
+		"/" + url.PathEscape(in.Location) +
+		"/" + url.PathEscape(in.Kind+" "+in.Name)
+
+	req, err := newRequest(ctx, http.MethodGet, path, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	mr := kube.ManifestResponse{}
+
+	err = s.do(req, &mr)
+	if err != nil {
+		return nil, err
+	}
+
+	b, err := json.Marshal(mr.Manifest)
+	if err != nil {
+		return nil, status.Error(codes.Internal, err.Error())
+	}
+
+	return &Manifest{
+		Account:  mr.Account,
+		Location: mr.Location,
+		Name:     mr.Name,
+		Manifest: b,
+		Status: &ManifestStatus{
+			Available: &Condition{State: mr.Status.Available.State, Message: mr.Status.Available.Message},
+			Failed:    &Condition{State: mr.Status.Failed.State, Message: mr.Status.Failed.Message},
+			Paused:    &Condition{State: mr.Status.Paused.State, Message: mr.Status.Paused.Message},
+			Stable:    &Condition{State: mr.Status.Stable.State, Message: mr.Status.Stable.Message},
+		},
+	}, nil
+}
+
+// WatchManifest polls a manifest, sending it when it is first read and
+// again each time it or its status changes, until the call is canceled
+// or getting the manifest fails.
+func (s *server) WatchManifest(in *WatchManifestRequest, stream WatchManifestServer) error {
+	interval := defaultWatchInterval
+	if in.IntervalSeconds > 0 {
+		interval = time.Duration(in.IntervalSeconds) * time.Second
+	}
+
+	ctx := stream.Context()
+	get := &GetManifestRequest{
+		Account:  in.Account,
+		Location: in.Location,
+		Kind:     in.Kind,
+		Name:     in.Name,
+	}
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	var last *Manifest
+
+	for {
+		m, err := s.GetManifest(ctx, get)
+		if err != nil {
+			return err
+		}
+
+		if last == nil || !proto.Equal(last, m) {
+			err = stream.Send(m)
+			if err != nil {
+				return err
+			}
+
+			last = m
+		}
+
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+		}
+	}
+}
+
+// Deploy runs a deployManifest operation, returning its task ID.
+func (s *server) Deploy(ctx context.Context, in *DeployRequest) (*OperationResponse, error) {
+	manifests := []map[string]interface{}{}
+
+	for _, b := range in.Manifests {
+		m := map[string]interface{}{}
+
+		err := json.Unmarshal(b, &m)
+		if err != nil {
+			return nil, status.Errorf(codes.InvalidArgument, "error decoding manifest: %v", err)
+		}
+
+		manifests = append(manifests, m)
+	}
+
+	dm := &kube.DeployManifestRequest{
+		Account:           in.Account,
+		CloudProvider:     "kubernetes",
+		Manifests:         manifests,
+		NamespaceOverride: in.NamespaceOverride,
+		Source:            "text",
+	}
+	dm.Moniker.App = in.Application
+
+	req, err := newRequest(ctx, http.MethodPost, "/kubernetes/ops", kube.Operations{{DeployManifest: dm}})
+	if err != nil {
+		return nil, err
+	}
+
+	// Operations are attributed to the application in the request unless
+	// the caller set one in its metadata.
+	if req.Header.Get(headerSpinnakerApplication) == "" && in.Application != "" {
+		req.Header.Set(headerSpinnakerApplication, in.Application)
+	}
+
+	or := kube.OperationsResponse{}
+
+	err = s.do(req, &or)
+	if err != nil {
+		return nil, err
+	}
+
+	return &OperationResponse{
+		ID:          or.ID,
+		ResourceURI: or.ResourceURI,
+	}, nil
+}
+
+// GetTask gets the status of an operation along with the manifests it deployed.
+func (s *server) GetTask(ctx context.Context, in *GetTaskRequest) (*Task, error) {
+	if in.ID == "" {
+		return nil, status.Error(codes.InvalidArgument, "id is required")
+	}
+
+	req, err := newRequest(ctx, http.MethodGet, "/task/"+url.PathEscape(in.ID), nil)
+	if err != nil {
+		return nil, err
+	}
+
+	t := clouddriver.Task{}
+
+	err = s.do(req, &t)
+	if err != nil {
+		return nil, err
+	}
+
+	task := &Task{
+		ID: t.ID,
+		Status: &TaskStatus{
+			Completed: t.Status.Completed,
+			Failed:    t.Status.Failed,
+			Phase:     t.Status.Phase,
+			Status:    t.Status.Status,
+		},
+	}
+
+	for _, ro := range t.ResultObjects {
+		for _, m := range ro.Manifests {
+			b, err := json.Marshal(m)
+			if err != nil {
+				return nil, status.Error(codes.Internal, err.Error())
+			}
+
+			task.Manifests = append(task.Manifests, b)
+		}
+
+		task.Warnings = append(task.Warnings, ro.Warnings...)
+	}
+
+	return task, nil
+}
+
+// newRequest returns a request of the REST API on behalf of the caller of
+// ctx, with its Spinnaker metadata as headers and body encoded as JSON.
+func newRequest(ctx context.Context, method, path string, body interface{}) (*http.Request, error) {
+	var r io.Reader = http.NoBody
+
+	if body != nil {
+		b, err := json.Marshal(body)
+		if err != nil {
+			return nil, status.Error(codes.Internal, err.Error())
+		}
+
+		r = bytes.NewReader(b)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, method, path, r)
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+
+	req.Header.Set("Content-Type", "application/json")
+
+	if md, ok := metadata.FromIncomingContext(ctx); ok {
+		for key, values := range md {
+			if !strings.HasPrefix(key, metadataPrefix) {
+				continue
+			}
+
+			for _, value := range values {
+				req.Header.Add(key, value)
+			}
+		}
+	}
+
+	if p, ok := peer.FromContext(ctx); ok && p.Addr != nil {
+		req.RemoteAddr = p.Addr.String()
+	}
+
+	return req, nil
+}
+
+// do serves a request with the REST API and decodes its response into v,
+// returning an error with the gRPC code of the response's status if it
+// did not succeed.
+func (s *server) do(req *http.Request, v interface{}) error {
+	w := &responseWriter{
+		header: http.Header{},
+		code:   http.StatusOK,
+	}
+
+	s.handler.ServeHTTP(w, req)
+
+	if w.code < http.StatusOK || w.code >= http.StatusMultipleChoices {
+		return statusError(w.code, w.body.Bytes())
+	}
+
+	err := json.Unmarshal(w.body.Bytes(), v)
+	if err != nil {
+		return status.Errorf(codes.Internal, "error decoding response of %s %s: %v", req.Method, req.URL.Path, err)
+	}
+
+	return nil
+}
+
+// statusError returns the error of a response as a gRPC status. Core
+// endpoints describe errors in a "message" and v1 endpoints in an "error".
+func statusError(code int, body []byte) error {
+	e := struct {
+		Error   string `json:"error"`
+		Message string `json:"message"`
+	}{}
+	_ = json.Unmarshal(body, &e)
+
+	message := e.Message
+	if message == "" {
+		message = e.Error
+	}
+
+	if message == "" {
+		message = http.StatusText(code)
+	}
+
+	return status.Error(Code(code), message)
+}
+
+// Code returns the gRPC code of an HTTP status.
+func Code(httpStatus int) codes.Code {
+	switch httpStatus {
+	case http.StatusBadRequest, http.StatusUnprocessableEntity:
+		return codes.InvalidArgument
+	case http.StatusUnauthorized:
+		return codes.Unauthenticated
+	case http.StatusForbidden:
+		return codes.PermissionDenied
+	case http.StatusNotFound:
+		return codes.NotFound
+	case http.StatusConflict:
+		return codes.AlreadyExists
+	case http.StatusPreconditionFailed, http.StatusLocked:
+		return codes.FailedPrecondition
+	case http.StatusTooManyRequests:
+		return codes.ResourceExhausted
+	case http.StatusNotImplemented:
+		return codes.Unimplemented
+	case http.StatusServiceUnavailable:
+		return codes.Unavailable
+	case http.StatusGatewayTimeout:
+		return codes.DeadlineExceeded
+	}
+
+	if httpStatus >= http.StatusInternalServerError {
+		return codes.Internal
+	}
+
+	return codes.Unknown
+}
+
+// responseWriter buffers the response of the REST API.
+type responseWriter struct {
+	header      http.Header
+	code        int
+	wroteHeader bool
+	body        bytes.Buffer
+}
+
+func (w *responseWriter) Header() http.Header {
+	return w.header
+}
+
+func (w *responseWriter) Write(b []byte) (int, error) {
+	w.wroteHeader = true
+	return w.body.Write(b)
+}
+
+func (w *responseWriter) WriteHeader(code int) {
+	if w.wroteHeader {
+		return
+	}
+
+	w.code = code
+	w.wroteHeader = true
+}
@@ -0,0 +1,370 @@
+package rpc_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net"
+	"net/http"
+	"time"
+
+	"github.com/billiford/go-clouddriver/pkg/freeze"
+	"github.com/billiford/go-clouddriver/pkg/kubernetes"
+	. "github.com/billiford/go-clouddriver/pkg/rpc"
+	"github.com/billiford/go-clouddriver/pkg/server/servertest"
+	"github.com/jinzhu/gorm"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+var _ = Describe("Server", func() {
+	var (
+		h       *servertest.Harness
+		handler http.Handler
+		lis     *bufconn.Listener
+		s       *grpc.Server
+		conn    *grpc.ClientConn
+		client  ClouddriverClient
+		ctx     context.Context
+		err     error
+	)
+
+	BeforeEach(func() {
+		h = servertest.New()
+		handler = h.Router
+		ctx = context.Background()
+
+		h.KubeClient.GetReturns(&unstructured.Unstructured{
+			Object: map[string]interface{}{
+				"apiVersion": "v1",
+				"kind":       "Pod",
+				"metadata": map[string]interface{}{
+					"name":      "test-pod",
+					"namespace": "test-namespace",
+				},
+			},
+		}, nil)
+	})
+
+	JustBeforeEach(func() {
+		lis = bufconn.Listen(1024 * 1024)
+		s = grpc.NewServer()
+		RegisterClouddriverServer(s, NewServer(handler))
+
+		go s.Serve(lis)
+
+		conn, err = grpc.DialContext(ctx, "bufnet",
+			grpc.WithContextDialer(func(context.Context, string) (net.Conn, error) {
+				return lis.Dial()
+			}),
+			grpc.WithInsecure())
+		Expect(err).To(BeNil())
+
+		client = NewClouddriverClient(conn)
+	})
+
+	AfterEach(func() {
+		conn.Close()
+		s.Stop()
+		h.Close()
+	})
+
+	Describe("#ListCredentials", func() {
+		var res *ListCredentialsResponse
+
+		JustBeforeEach(func() {
+			res, err = client.ListCredentials(ctx, &ListCredentialsRequest{})
+		})
+
+		When("listing providers returns an error", func() {
+			BeforeEach(func() {
+				h.SQLClient.ListKubernetesProvidersReturns(nil, errors.New("error listing providers"))
+			})
+
+			It("returns an internal error", func() {
+				Expect(status.Code(err)).To(Equal(codes.Internal))
+			})
+		})
+
+		When("it succeeds", func() {
+			BeforeEach(func() {
+				h.SQLClient.ListKubernetesProvidersReturns([]kubernetes.Provider{
+					{
+						Name: "test-account",
+					},
+				}, nil)
+			})
+
+			It("returns the credentials", func() {
+				Expect(err).To(BeNil())
+				Expect(res.Credentials).To(HaveLen(1))
+				Expect(res.Credentials[0].Name).To(Equal("test-account"))
+				Expect(res.Credentials[0].CloudProvider).To(Equal("kubernetes"))
+			})
+		})
+	})
+
+	Describe("#GetManifest", func() {
+		var (
+			req *GetManifestRequest
+			res *Manifest
+		)
+
+		BeforeEach(func() {
+			req = &GetManifestRequest{
+				Account:  "test-account",
+				Location: "test-namespace",
+				Kind:     "pod",
+				Name:     "test-pod",
+			}
+		})
+
+		JustBeforeEach(func() {
+			res, err = client.GetManifest(ctx, req)
+		})
+
+		When("the name is missing", func() {
+			BeforeEach(func() {
+				req.Name = ""
+			})
+
+			It("returns an invalid argument error", func() {
+				Expect(status.Code(err)).To(Equal(codes.InvalidArgument))
+				Expect(h.KubeClient.GetCallCount()).To(BeZero())
+			})
+		})
+
+		When("the account is not found", func() {
+			BeforeEach(func() {
+				h.SQLClient.GetKubernetesProviderReturns(kubernetes.Provider{}, gorm.ErrRecordNotFound)
+			})
+
+			It("returns a not found error with the message of the response", func() {
+				Expect(status.Code(err)).To(Equal(codes.NotFound))
+				Expect(status.Convert(err).Message()).To(Equal("account test-account not found"))
+			})
+		})
+
+		When("it succeeds", func() {
+			It("returns the manifest", func() {
+				Expect(err).To(BeNil())
+				Expect(res.Account).To(Equal("test-account"))
+				Expect(res.Location).To(Equal("test-namespace"))
+				Expect(res.Status).ToNot(BeNil())
+
+				m := map[string]interface{}{}
+				Expect(json.Unmarshal(res.Manifest, &m)).To(Succeed())
+				Expect(m["kind"]).To(Equal("Pod"))
+			})
+
+			It("gets the manifest of the kind and name", func() {
+				kind, name, namespace := h.KubeClient.GetArgsForCall(0)
+				Expect(kind).To(Equal("pod"))
+				Expect(namespace).To(Equal("test-namespace"))
+				Expect(name).To(Equal("test-pod"))
+			})
+		})
+	})
+
+	Describe("#WatchManifest", func() {
+		var (
+			cancel context.CancelFunc
+			stream WatchManifestClient
+			first  *Manifest
+		)
+
+		BeforeEach(func() {
+			ctx, cancel = context.WithTimeout(context.Background(), 10*time.Second)
+		})
+
+		JustBeforeEach(func() {
+			stream, err = client.WatchManifest(ctx, &WatchManifestRequest{
+				Account:         "test-account",
+				Location:        "test-namespace",
+				Kind:            "pod",
+				Name:            "test-pod",
+				IntervalSeconds: 1,
+			})
+			Expect(err).To(BeNil())
+
+			first, err = stream.Recv()
+		})
+
+		AfterEach(func() {
+			cancel()
+		})
+
+		When("the account is not found", func() {
+			BeforeEach(func() {
+				h.SQLClient.GetKubernetesProviderReturns(kubernetes.Provider{}, gorm.ErrRecordNotFound)
+			})
+
+			It("ends the stream with a not found error", func() {
+				Expect(status.Code(err)).To(Equal(codes.NotFound))
+			})
+		})
+
+		When("the manifest changes", func() {
+			It("sends the manifest again", func() {
+				Expect(err).To(BeNil())
+				Expect(string(first.Manifest)).ToNot(ContainSubstring("running"))
+
+				h.KubeClient.GetReturns(&unstructured.Unstructured{
+					Object: map[string]interface{}{
+						"apiVersion": "v1",
+						"kind":       "Pod",
+						"metadata": map[string]interface{}{
+							"name":      "test-pod",
+							"namespace": "test-namespace",
+						},
+						"status": map[string]interface{}{
+							"phase": "running",
+						},
+					},
+				}, nil)
+
+				next, err := stream.Recv()
+				Expect(err).To(BeNil())
+				Expect(string(next.Manifest)).To(ContainSubstring("running"))
+			})
+		})
+	})
+
+	Describe("#Deploy", func() {
+		var (
+			req *DeployRequest
+			res *OperationResponse
+		)
+
+		BeforeEach(func() {
+			req = &DeployRequest{
+				Account:     "test-account",
+				Application: "test-app",
+				Manifests: [][]byte{
+					[]byte(`{"apiVersion":"v1","kind":"Pod","metadata":{"name":"test-pod","namespace":"test-namespace"}}`),
+				},
+			}
+		})
+
+		JustBeforeEach(func() {
+			res, err = client.Deploy(ctx, req)
+		})
+
+		When("a manifest is not JSON", func() {
+			BeforeEach(func() {
+				req.Manifests = [][]byte{[]byte("kind: Pod")}
+			})
+
+			It("returns an invalid argument error", func() {
+				Expect(status.Code(err)).To(Equal(codes.InvalidArgument))
+				Expect(h.KubeActionHandler.NewDeployManifestActionCallCount()).To(BeZero())
+			})
+		})
+
+		When("the namespace is frozen", func() {
+			BeforeEach(func() {
+				end := time.Now().Add(time.Hour)
+				h.FreezeController.ActiveFreezeReturns(freeze.Status{
+					Freeze: freeze.Freeze{Name: "weekend"},
+					Active: true,
+					End:    &end,
+				}, true)
+			})
+
+			It("returns a failed precondition error", func() {
+				Expect(status.Code(err)).To(Equal(codes.FailedPrecondition))
+				Expect(status.Convert(err).Message()).To(ContainSubstring("frozen by weekend"))
+			})
+		})
+
+		When("it succeeds", func() {
+			It("runs a deployManifest operation", func() {
+				Expect(err).To(BeNil())
+				Expect(res.ID).ToNot(BeEmpty())
+				Expect(h.KubeActionHandler.NewDeployManifestActionCallCount()).To(Equal(1))
+
+				ac := h.KubeActionHandler.NewDeployManifestActionArgsForCall(0)
+				Expect(ac.ID).To(Equal(res.ID))
+				Expect(ac.Application).To(Equal("test-app"))
+				Expect(ac.Operation.DeployManifest.Account).To(Equal("test-account"))
+				Expect(ac.Operation.DeployManifest.Moniker.App).To(Equal("test-app"))
+				Expect(ac.Operation.DeployManifest.Manifests).To(HaveLen(1))
+				Expect(ac.Operation.DeployManifest.Manifests[0]["kind"]).To(Equal("Pod"))
+			})
+		})
+	})
+
+	Describe("#GetTask", func() {
+		var res *Task
+
+		JustBeforeEach(func() {
+			res, err = client.GetTask(ctx, &GetTaskRequest{ID: "test-task-id"})
+		})
+
+		When("listing resources returns an error", func() {
+			BeforeEach(func() {
+				h.SQLClient.ListKubernetesResourcesByTaskIDReturns(nil, errors.New("error listing resources"))
+			})
+
+			It("returns an invalid argument error", func() {
+				Expect(status.Code(err)).To(Equal(codes.InvalidArgument))
+				Expect(status.Convert(err).Message()).To(Equal("error listing resources"))
+			})
+		})
+
+		When("the task has no resources", func() {
+			It("returns the default task", func() {
+				Expect(err).To(BeNil())
+				Expect(res.ID).To(Equal("test-task-id"))
+				Expect(res.Status.Completed).To(BeTrue())
+				Expect(res.Status.Failed).To(BeFalse())
+				Expect(res.Manifests).To(BeEmpty())
+			})
+		})
+	})
+
+	Describe("metadata", func() {
+		var headers http.Header
+
+		BeforeEach(func() {
+			handler = http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				headers = r.Header
+				w.Write([]byte("[]"))
+			})
+			ctx = metadata.AppendToOutgoingContext(context.Background(),
+				"x-spinnaker-user", "test-user",
+				"authorization", "Bearer test-token")
+		})
+
+		JustBeforeEach(func() {
+			_, err = client.ListCredentials(ctx, &ListCredentialsRequest{})
+		})
+
+		It("forwards Spinnaker metadata as headers", func() {
+			Expect(err).To(BeNil())
+			Expect(headers.Get("X-Spinnaker-User")).To(Equal("test-user"))
+			Expect(headers.Get("Authorization")).To(BeEmpty())
+		})
+	})
+
+	Describe("#Code", func() {
+		It("maps HTTP statuses to gRPC codes", func() {
+			Expect(Code(http.StatusBadRequest)).To(Equal(codes.InvalidArgument))
+			Expect(Code(http.StatusUnauthorized)).To(Equal(codes.Unauthenticated))
+			Expect(Code(http.StatusForbidden)).To(Equal(codes.PermissionDenied))
+			Expect(Code(http.StatusNotFound)).To(Equal(codes.NotFound))
+			Expect(Code(http.StatusConflict)).To(Equal(codes.AlreadyExists))
+			Expect(Code(http.StatusLocked)).To(Equal(codes.FailedPrecondition))
+			Expect(Code(http.StatusTooManyRequests)).To(Equal(codes.ResourceExhausted))
+			Expect(Code(http.StatusServiceUnavailable)).To(Equal(codes.Unavailable))
+			Expect(Code(http.StatusBadGateway)).To(Equal(codes.Internal))
+			Expect(Code(http.StatusTeapot)).To(Equal(codes.Unknown))
+		})
+	})
+})
@@ -0,0 +1,256 @@
+package rpc
+
+import (
+	"context"
+
+	"google.golang.org/grpc"
+)
+
+const serviceName = "clouddriver.v1.Clouddriver"
+
+// ClouddriverServer is the server API of the Clouddriver service.
+type ClouddriverServer interface {
+	ListCredentials(context.Context, *ListCredentialsRequest) (*ListCredentialsResponse, error)
+	GetManifest(context.Context, *GetManifestRequest) (*Manifest, error)
+	WatchManifest(*WatchManifestRequest, WatchManifestServer) error
+	Deploy(context.Context, *DeployRequest) (*OperationResponse, error)
+	GetTask(context.Context, *GetTaskRequest) (*Task, error)
+}
+
+// WatchManifestServer sends the manifests of a WatchManifest call.
+type WatchManifestServer interface {
+	Send(*Manifest) error
+	grpc.ServerStream
+}
+
+type watchManifestServer struct {
+	grpc.ServerStream
+}
+
+func (s *watchManifestServer) Send(m *Manifest) error {
+	return s.ServerStream.SendMsg(m)
+}
+
+// RegisterClouddriverServer registers the Clouddriver service of srv with s.
+func RegisterClouddriverServer(s *grpc.Server, srv ClouddriverServer) {
+	s.RegisterService(&serviceDesc, srv)
+}
+
+var serviceDesc = grpc.ServiceDesc{
+	ServiceName: serviceName,
+	HandlerType: (*ClouddriverServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "ListCredentials",
+			Handler:    listCredentialsHandler,
+		},
+		{
+			MethodName: "GetManifest",
+			Handler:    getManifestHandler,
+		},
+		{
+			MethodName: "Deploy",
+			Handler:    deployHandler,
+		},
+		{
+			MethodName: "GetTask",
+			Handler:    getTaskHandler,
+		},
+	},
+	Streams: []grpc.StreamDesc{
+		{
+			StreamName:    "WatchManifest",
+			Handler:       watchManifestHandler,
+			ServerStreams: true,
+		},
+	},
+	Metadata: "clouddriver.proto",
+}
+
+func fullMethod(method string) string {
+	return "/" + serviceName + "/" + method
+}
+
+func listCredentialsHandler(srv interface{}, ctx context.Context, dec func(interface{}) error,
+	interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := &ListCredentialsRequest{}
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+
+	if interceptor == nil {
+		return srv.(ClouddriverServer).ListCredentials(ctx, in)
+	}
+
+	info := &grpc.UnaryServerInfo{Server: srv, FullMethod: fullMethod("ListCredentials")}
+
+	return interceptor(ctx, in, info, func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ClouddriverServer).ListCredentials(ctx, req.(*ListCredentialsRequest))
+	})
+}
+
+func getManifestHandler(srv interface{}, ctx context.Context, dec func(interface{}) error,
+	interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := &GetManifestRequest{}
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+
+	if interceptor == nil {
+		return srv.(ClouddriverServer).GetManifest(ctx, in)
+	}
+
+	info := &grpc.UnaryServerInfo{Server: srv, FullMethod: fullMethod("GetManifest")}
+
+	return interceptor(ctx, in, info, func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ClouddriverServer).GetManifest(ctx, req.(*GetManifestRequest))
+	})
+}
+
+func deployHandler(srv interface{}, ctx context.Context, dec func(interface{}) error,
+	interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := &DeployRequest{}
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+
+	if interceptor == nil {
+		return srv.(ClouddriverServer).Deploy(ctx, in)
+	}
+
+	info := &grpc.UnaryServerInfo{Server: srv, FullMethod: fullMethod("Deploy")}
+
+	return interceptor(ctx, in, info, func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ClouddriverServer).Deploy(ctx, req.(*DeployRequest))
+	})
+}
+
+func getTaskHandler(srv interface{}, ctx context.Context, dec func(interface{}) error,
+	interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := &GetTaskRequest{}
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+
+	if interceptor == nil {
+		return srv.(ClouddriverServer).GetTask(ctx, in)
+	}
+
+	info := &grpc.UnaryServerInfo{Server: srv, FullMethod: fullMethod("GetTask")}
+
+	return interceptor(ctx, in, info, func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ClouddriverServer).GetTask(ctx, req.(*GetTaskRequest))
+	})
+}
+
+func watchManifestHandler(srv interface{}, stream grpc.ServerStream) error {
+	in := &WatchManifestRequest{}
+	if err := stream.RecvMsg(in); err != nil {
+		return err
+	}
+
+	return srv.(ClouddriverServer).WatchManifest(in, &watchManifestServer{stream})
+}
+
+// ClouddriverClient is the client API of the Clouddriver service.
+type ClouddriverClient interface {
+	ListCredentials(context.Context, *ListCredentialsRequest, ...grpc.CallOption) (*ListCredentialsResponse, error)
+	GetManifest(context.Context, *GetManifestRequest, ...grpc.CallOption) (*Manifest, error)
+	WatchManifest(context.Context, *WatchManifestRequest, ...grpc.CallOption) (WatchManifestClient, error)
+	Deploy(context.Context, *DeployRequest, ...grpc.CallOption) (*OperationResponse, error)
+	GetTask(context.Context, *GetTaskRequest, ...grpc.CallOption) (*Task, error)
+}
+
+// WatchManifestClient receives the manifests of a WatchManifest call.
+type WatchManifestClient interface {
+	Recv() (*Manifest, error)
+	grpc.ClientStream
+}
+
+// NewClouddriverClient returns a client of the Clouddriver service.
+func NewClouddriverClient(cc *grpc.ClientConn) ClouddriverClient {
+	return &clouddriverClient{cc: cc}
+}
+
+type clouddriverClient struct {
+	cc *grpc.ClientConn
+}
+
+func (c *clouddriverClient) ListCredentials(ctx context.Context, in *ListCredentialsRequest,
+	opts ...grpc.CallOption) (*ListCredentialsResponse, error) {
+	out := &ListCredentialsResponse{}
+
+	err := c.cc.Invoke(ctx, fullMethod("ListCredentials"), in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+
+	return out, nil
+}
+
+func (c *clouddriverClient) GetManifest(ctx context.Context, in *GetManifestRequest,
+	opts ...grpc.CallOption) (*Manifest, error) {
+	out := &Manifest{}
+
+	err := c.cc.Invoke(ctx, fullMethod("GetManifest"), in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+
+	return out, nil
+}
+
+func (c *clouddriverClient) WatchManifest(ctx context.Context, in *WatchManifestRequest,
+	opts ...grpc.CallOption) (WatchManifestClient, error) {
+	stream, err := c.cc.NewStream(ctx, &serviceDesc.Streams[0], fullMethod("WatchManifest"), opts...)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := stream.SendMsg(in); err != nil {
+		return nil, err
+	}
+
+	if err := stream.CloseSend(); err != nil {
+		return nil, err
+	}
+
+	return &watchManifestClient{stream}, nil
+}
+
+type watchManifestClient struct {
+	grpc.ClientStream
+}
+
+func (c *watchManifestClient) Recv() (*Manifest, error) {
+	m := &Manifest{}
+	if err := c.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+
+	return m, nil
+}
+
+func (c *clouddriverClient) Deploy(ctx context.Context, in *DeployRequest,
+	opts ...grpc.CallOption) (*OperationResponse, error) {
+	out := &OperationResponse{}
+
+	err := c.cc.Invoke(ctx, fullMethod("Deploy"), in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+
+	return out, nil
+}
+
+func (c *clouddriverClient) GetTask(ctx context.Context, in *GetTaskRequest,
+	opts ...grpc.CallOption) (*Task, error) {
+	out := &Task{}
+
+	err := c.cc.Invoke(ctx, fullMethod("GetTask"), in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+
+	return out, nil
+}
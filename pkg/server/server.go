@@ -1,37 +1,168 @@
 package server
 
 import (
+	"net"
+	"time"
+
 	"github.com/billiford/go-clouddriver/pkg/arcade"
 	"github.com/billiford/go-clouddriver/pkg/artifact"
+	"github.com/billiford/go-clouddriver/pkg/docker"
 	"github.com/billiford/go-clouddriver/pkg/fiat"
+	"github.com/billiford/go-clouddriver/pkg/freeze"
+	"github.com/billiford/go-clouddriver/pkg/hook"
 	"github.com/billiford/go-clouddriver/pkg/http"
 	kube "github.com/billiford/go-clouddriver/pkg/http/core/kubernetes"
 	"github.com/billiford/go-clouddriver/pkg/kubernetes"
+	"github.com/billiford/go-clouddriver/pkg/lock"
 	"github.com/billiford/go-clouddriver/pkg/middleware"
+	"github.com/billiford/go-clouddriver/pkg/notify"
+	"github.com/billiford/go-clouddriver/pkg/ondemand"
+	"github.com/billiford/go-clouddriver/pkg/project"
+	"github.com/billiford/go-clouddriver/pkg/queue"
+	"github.com/billiford/go-clouddriver/pkg/recorder"
+	"github.com/billiford/go-clouddriver/pkg/snapshot"
 	"github.com/billiford/go-clouddriver/pkg/sql"
+	"github.com/billiford/go-clouddriver/pkg/webhook"
 	"github.com/gin-gonic/gin"
 )
 
 type Config struct {
 	ArcadeClient                  arcade.Client
 	ArtifactCredentialsController artifact.CredentialsController
+	DockerCredentialsController   docker.CredentialsController
 	SQLClient                     sql.Client
-	FiatClient                    fiat.Client
-	KubeController                kubernetes.Controller
-	KubeActionHandler             kube.ActionHandler
-	VerboseRequestLogging         bool
+	// SQLReadOnlyClient is used for read-heavy endpoints. Defaults to SQLClient.
+	SQLReadOnlyClient sql.Client
+	FiatClient        fiat.Client
+	// GroupResolver resolves the groups of users for installs without Fiat.
+	// Users are authorized by Fiat when nil.
+	GroupResolver        fiat.GroupResolver
+	FreezeController     freeze.Controller
+	KubeController       kubernetes.Controller
+	KubeActionHandler    kube.ActionHandler
+	KubeNamespaceCache   kubernetes.NamespaceCache
+	KubePermissionsCache kubernetes.PermissionsCache
+	ProjectController    project.Controller
+	// OnDemandRefreshes keeps the on-demand cache refreshes Orca requests.
+	// Defaults to refreshes listed for ondemand.DefaultRetention.
+	OnDemandRefreshes ondemand.Refreshes
+	// Notifier posts the outcomes of operations to Slack and Teams.
+	// Notifications are disabled when nil.
+	Notifier notify.Notifier
+	// HookRunner calls webhooks at each stage of operations.
+	// Hooks are disabled when nil.
+	HookRunner hook.Runner
+	// StabilityChecker delegates the stability of some kinds to webhooks.
+	// Stability is always decided by clouddriver when nil.
+	StabilityChecker hook.StabilityChecker
+	// Queue rate-limits operations against each account.
+	// Operations are not rate-limited when nil.
+	Queue queue.Queue
+	// Locker locks the clusters operations change until they are done.
+	// Clusters are not locked when nil.
+	Locker lock.Locker
+	// Snapshotter decides when the responses of cluster and server group
+	// reads are snapshotted to be read as of a past time.
+	// Snapshots are not taken when nil.
+	Snapshotter snapshot.Snapshotter
+	// WatchRollouts watches the rollout of every deploy to measure its time
+	// to stable for deploy stats. Rollouts are only watched for
+	// post-stability hooks when false.
+	WatchRollouts bool
+	// WebhookSecrets authenticate the cache invalidation webhooks of
+	// accounts. Webhooks of accounts without a secret are rejected.
+	WebhookSecrets webhook.Secrets
+	// Recorder records requests made on behalf of pipeline executions.
+	// Recording is disabled when nil.
+	Recorder recorder.Recorder
+	// SharedSecret must be sent by callers in the X-Spinnaker-Shared-Secret
+	// header. Any caller is accepted when empty.
+	SharedSecret string
+	// AllowedIPs are the networks requests are accepted from.
+	// Requests are accepted from any IP when empty.
+	AllowedIPs []*net.IPNet
+	// MaxRequestBodySize is the largest request body accepted, in bytes.
+	// Bodies are not limited when zero.
+	MaxRequestBodySize int64
+	// ClientRateLimit is the number of requests per second accepted from
+	// each client, in bursts of up to ClientRateBurst requests.
+	// Clients are not rate-limited when zero.
+	ClientRateLimit float64
+	ClientRateBurst int
+	// MaxInFlightOperations is the number of operations handled at once and
+	// MaxHeapSize the largest heap, in bytes, new operations are accepted
+	// with. Further operations are shed. Neither is limited when zero.
+	MaxInFlightOperations int
+	MaxHeapSize           uint64
+	// RequestTimeout is how long handlers have to respond, including the
+	// SQL queries and Kubernetes API requests they make. Defaults to
+	// middleware.DefaultRequestTimeout.
+	RequestTimeout        time.Duration
+	VerboseRequestLogging bool
 }
 
 // Define all middlewares to use then set up the API.
 func Setup(r *gin.Engine, c *Config) {
+	if c.SQLReadOnlyClient == nil {
+		c.SQLReadOnlyClient = c.SQLClient
+	}
+
+	if c.OnDemandRefreshes == nil {
+		c.OnDemandRefreshes = ondemand.New(ondemand.DefaultRetention)
+	}
+
+	r.Use(middleware.Deadline(c.RequestTimeout))
 	r.Use(middleware.SetArcadeClient(c.ArcadeClient))
 	r.Use(middleware.SetSQLClient(c.SQLClient))
+	r.Use(middleware.SetSQLReadOnlyClient(c.SQLReadOnlyClient))
 	r.Use(middleware.SetKubeController(c.KubeController))
 	r.Use(middleware.SetArtifactCredentialsController(c.ArtifactCredentialsController))
+	r.Use(middleware.SetDockerCredentialsController(c.DockerCredentialsController))
 	r.Use(middleware.SetKubeActionHandler(c.KubeActionHandler))
+	r.Use(middleware.SetKubeNamespaceCache(c.KubeNamespaceCache))
+	r.Use(middleware.SetKubePermissionsCache(c.KubePermissionsCache))
 	r.Use(middleware.SetFiatClient(c.FiatClient))
+	r.Use(middleware.SetGroupResolver(c.GroupResolver))
+	r.Use(middleware.SetFreezeController(c.FreezeController))
+	r.Use(middleware.SetProjectController(c.ProjectController))
+	r.Use(middleware.SetRecorder(c.Recorder))
+	r.Use(middleware.SetNotifier(c.Notifier))
+	r.Use(middleware.SetHookRunner(c.HookRunner))
+	r.Use(middleware.SetStabilityChecker(c.StabilityChecker))
+	r.Use(middleware.SetQueue(c.Queue))
+	r.Use(middleware.SetLocker(c.Locker))
+	r.Use(middleware.SetSnapshotter(c.Snapshotter))
+	r.Use(middleware.SetOnDemandRefreshes(c.OnDemandRefreshes))
+	r.Use(middleware.SetWatchRollouts(c.WatchRollouts))
+	r.Use(middleware.SetWebhookSecrets(c.WebhookSecrets))
+
+	// Record before handling errors so error responses are recorded.
+	if c.Recorder != nil {
+		r.Use(middleware.RecordRequest())
+	}
+
 	r.Use(middleware.HandleError())
 
+	if len(c.AllowedIPs) > 0 {
+		r.Use(middleware.AllowIPs(c.AllowedIPs))
+	}
+
+	if c.SharedSecret != "" {
+		r.Use(middleware.RequireSharedSecret(c.SharedSecret))
+	}
+
+	if c.ClientRateLimit > 0 {
+		r.Use(middleware.RateLimitClients(c.ClientRateLimit, c.ClientRateBurst))
+	}
+
+	if c.MaxRequestBodySize > 0 {
+		r.Use(middleware.LimitRequestBody(c.MaxRequestBodySize))
+	}
+
+	if c.MaxInFlightOperations > 0 || c.MaxHeapSize > 0 {
+		r.Use(middleware.ShedOperations(c.MaxInFlightOperations, c.MaxHeapSize))
+	}
+
 	if c.VerboseRequestLogging {
 		r.Use(middleware.LogRequest())
 	}
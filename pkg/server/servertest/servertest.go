@@ -0,0 +1,119 @@
+package servertest
+
+import (
+	"net/http/httptest"
+
+	"github.com/billiford/go-clouddriver/pkg/arcade/arcadefakes"
+	"github.com/billiford/go-clouddriver/pkg/artifact/artifactfakes"
+	"github.com/billiford/go-clouddriver/pkg/docker/dockerfakes"
+	"github.com/billiford/go-clouddriver/pkg/fiat/fiatfakes"
+	"github.com/billiford/go-clouddriver/pkg/freeze/freezefakes"
+	kubefakes "github.com/billiford/go-clouddriver/pkg/http/core/kubernetes/kubernetesfakes"
+	"github.com/billiford/go-clouddriver/pkg/kubernetes/kubernetesfakes"
+	"github.com/billiford/go-clouddriver/pkg/project/projectfakes"
+	"github.com/billiford/go-clouddriver/pkg/server"
+	"github.com/billiford/go-clouddriver/pkg/sql/sqlfakes"
+	"github.com/gin-gonic/gin"
+)
+
+// Harness runs the clouddriver API with every dependency replaced by a
+// counterfeiter fake, so handlers can be tested without a database or
+// a Kubernetes cluster.
+//
+// The fakes are wired together the way the real implementations are:
+// the SQL client and kube controller return themselves from WithContext,
+// the kube controller returns KubeClient and the action handler returns
+// Action for every operation. Anything else is left to the test to stub.
+type Harness struct {
+	Server *httptest.Server
+	Router *gin.Engine
+
+	ArcadeClient                  *arcadefakes.FakeClient
+	ArtifactCredentialsController *artifactfakes.FakeCredentialsController
+	DockerCredentialsController   *dockerfakes.FakeCredentialsController
+	FiatClient                    *fiatfakes.FakeClient
+	FreezeController              *freezefakes.FakeController
+	SQLClient                     *sqlfakes.FakeClient
+	KubeController                *kubernetesfakes.FakeController
+	KubeClient                    *kubernetesfakes.FakeClient
+	KubeActionHandler             *kubefakes.FakeActionHandler
+	KubeNamespaceCache            *kubernetesfakes.FakeNamespaceCache
+	KubePermissionsCache          *kubernetesfakes.FakePermissionsCache
+	ProjectController             *projectfakes.FakeController
+	Action                        *kubefakes.FakeAction
+}
+
+// New returns a harness with the fakes injected into a new gin router.
+// The router is served by an httptest server that must be closed
+// with Close.
+func New() *Harness {
+	h := &Harness{
+		ArcadeClient:                  &arcadefakes.FakeClient{},
+		ArtifactCredentialsController: &artifactfakes.FakeCredentialsController{},
+		DockerCredentialsController:   &dockerfakes.FakeCredentialsController{},
+		FiatClient:                    &fiatfakes.FakeClient{},
+		FreezeController:              &freezefakes.FakeController{},
+		SQLClient:                     &sqlfakes.FakeClient{},
+		KubeController:                &kubernetesfakes.FakeController{},
+		KubeClient:                    &kubernetesfakes.FakeClient{},
+		KubeActionHandler:             &kubefakes.FakeActionHandler{},
+		KubeNamespaceCache:            &kubernetesfakes.FakeNamespaceCache{},
+		KubePermissionsCache:          &kubernetesfakes.FakePermissionsCache{},
+		ProjectController:             &projectfakes.FakeController{},
+		Action:                        &kubefakes.FakeAction{},
+	}
+
+	h.SQLClient.WithContextReturns(h.SQLClient)
+	h.KubeController.NewClientReturns(h.KubeClient, nil)
+	h.KubeController.WithContextReturns(h.KubeController)
+	h.KubeActionHandler.NewCleanupArtifactsActionReturns(h.Action)
+	h.KubeActionHandler.NewCreateApplicationActionReturns(h.Action)
+	h.KubeActionHandler.NewDeleteApplicationActionReturns(h.Action)
+	h.KubeActionHandler.NewDeleteManifestActionReturns(h.Action)
+	h.KubeActionHandler.NewDeployManifestActionReturns(h.Action)
+	h.KubeActionHandler.NewPatchManifestActionReturns(h.Action)
+	h.KubeActionHandler.NewScaleManifestActionReturns(h.Action)
+	h.KubeActionHandler.NewRollingRestartActionReturns(h.Action)
+	h.KubeActionHandler.NewRollbackActionReturns(h.Action)
+	h.KubeActionHandler.NewRunJobActionReturns(h.Action)
+
+	// Disable debug logging.
+	gin.SetMode(gin.ReleaseMode)
+
+	// Create new gin instead of using gin.Default().
+	// This disables request logging which we don't want for tests.
+	h.Router = gin.New()
+	h.Router.Use(gin.Recovery())
+
+	server.Setup(h.Router, h.Config())
+	h.Server = httptest.NewServer(h.Router)
+
+	return h
+}
+
+// Config returns the server config with the harness' fakes.
+func (h *Harness) Config() *server.Config {
+	return &server.Config{
+		ArcadeClient:                  h.ArcadeClient,
+		ArtifactCredentialsController: h.ArtifactCredentialsController,
+		DockerCredentialsController:   h.DockerCredentialsController,
+		FiatClient:                    h.FiatClient,
+		FreezeController:              h.FreezeController,
+		SQLClient:                     h.SQLClient,
+		KubeController:                h.KubeController,
+		KubeActionHandler:             h.KubeActionHandler,
+		KubeNamespaceCache:            h.KubeNamespaceCache,
+		KubePermissionsCache:          h.KubePermissionsCache,
+		ProjectController:             h.ProjectController,
+	}
+}
+
+// URL returns the URL of path on the harness' server.
+func (h *Harness) URL(path string) string {
+	return h.Server.URL + path
+}
+
+// Close shuts down the harness' server.
+func (h *Harness) Close() {
+	h.Server.Close()
+}
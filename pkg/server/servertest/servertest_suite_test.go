@@ -0,0 +1,13 @@
+package servertest_test
+
+import (
+	"testing"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+func TestServertest(t *testing.T) {
+	RegisterFailHandler(Fail)
+	RunSpecs(t, "Servertest Suite")
+}
@@ -0,0 +1,65 @@
+package servertest_test
+
+import (
+	"errors"
+	"io/ioutil"
+	"net/http"
+
+	"github.com/billiford/go-clouddriver/pkg/kubernetes"
+	. "github.com/billiford/go-clouddriver/pkg/server/servertest"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Servertest", func() {
+	var (
+		h   *Harness
+		res *http.Response
+		err error
+	)
+
+	BeforeEach(func() {
+		h = New()
+	})
+
+	AfterEach(func() {
+		h.Close()
+	})
+
+	JustBeforeEach(func() {
+		res, err = http.Get(h.URL("/credentials"))
+		Expect(err).To(BeNil())
+	})
+
+	Context("when listing credentials", func() {
+		BeforeEach(func() {
+			h.SQLClient.ListKubernetesProvidersReturns([]kubernetes.Provider{
+				{
+					Name: "test-account",
+				},
+			}, nil)
+		})
+
+		It("serves the request with the fakes", func() {
+			defer res.Body.Close()
+			Expect(res.StatusCode).To(Equal(http.StatusOK))
+			Expect(h.SQLClient.WithContextCallCount()).ToNot(BeZero())
+			Expect(h.SQLClient.ListKubernetesProvidersCallCount()).To(Equal(1))
+
+			b, _ := ioutil.ReadAll(res.Body)
+			Expect(string(b)).To(ContainSubstring(`"name":"test-account"`))
+		})
+	})
+
+	Context("when a fake returns an error", func() {
+		BeforeEach(func() {
+			h.SQLClient.ListKubernetesProvidersReturns(nil, errors.New("error listing providers"))
+		})
+
+		It("returns the error", func() {
+			defer res.Body.Close()
+			Expect(res.StatusCode).To(Equal(http.StatusInternalServerError))
+		})
+	})
+})
@@ -0,0 +1,71 @@
+package server
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"io/ioutil"
+)
+
+// TLSConfig configures serving the API over TLS, optionally verifying the
+// certificates of clients such as Gate for mutual TLS.
+type TLSConfig struct {
+	CertFile string
+	KeyFile  string
+	// ClientCAFile holds the PEM encoded CAs client certificates are
+	// verified against. Client certificates are not requested when empty.
+	ClientCAFile string
+	// RequireClientCert rejects clients without a verified certificate.
+	// Otherwise only certificates clients send are verified.
+	RequireClientCert bool
+}
+
+// Enabled returns true if a certificate is configured.
+func (tc TLSConfig) Enabled() bool {
+	return tc.CertFile != "" || tc.KeyFile != ""
+}
+
+// Config returns the TLS config of the server.
+func (tc TLSConfig) Config() (*tls.Config, error) {
+	if tc.CertFile == "" || tc.KeyFile == "" {
+		return nil, errors.New("both a TLS certificate and key file must be set")
+	}
+
+	cert, err := tls.LoadX509KeyPair(tc.CertFile, tc.KeyFile)
+	if err != nil {
+		return nil, fmt.Errorf("error loading TLS certificate: %w", err)
+	}
+
+	config := &tls.Config{
+		Certificates: []tls.Certificate{cert},
+		MinVersion:   tls.VersionTLS12,
+	}
+
+	if tc.ClientCAFile == "" {
+		if tc.RequireClientCert {
+			return nil, errors.New("a client CA file must be set to require client certificates")
+		}
+
+		return config, nil
+	}
+
+	b, err := ioutil.ReadFile(tc.ClientCAFile)
+	if err != nil {
+		return nil, fmt.Errorf("error reading client CA file: %w", err)
+	}
+
+	pool := x509.NewCertPool()
+	if !pool.AppendCertsFromPEM(b) {
+		return nil, fmt.Errorf("no certificates found in client CA file %s", tc.ClientCAFile)
+	}
+
+	config.ClientCAs = pool
+	config.ClientAuth = tls.VerifyClientCertIfGiven
+
+	if tc.RequireClientCert {
+		config.ClientAuth = tls.RequireAndVerifyClientCert
+	}
+
+	return config, nil
+}
@@ -0,0 +1,104 @@
+package server_test
+
+import (
+	"crypto/tls"
+
+	. "github.com/billiford/go-clouddriver/pkg/server"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("TLS", func() {
+	var (
+		tc     TLSConfig
+		config *tls.Config
+		err    error
+	)
+
+	BeforeEach(func() {
+		tc = TLSConfig{
+			CertFile: "test/cert.pem",
+			KeyFile:  "test/key.pem",
+		}
+	})
+
+	JustBeforeEach(func() {
+		config, err = tc.Config()
+	})
+
+	When("the key file is not set", func() {
+		BeforeEach(func() {
+			tc.KeyFile = ""
+		})
+
+		It("returns an error", func() {
+			Expect(err).ToNot(BeNil())
+			Expect(err.Error()).To(Equal("both a TLS certificate and key file must be set"))
+		})
+	})
+
+	When("the certificate cannot be loaded", func() {
+		BeforeEach(func() {
+			tc.CertFile = "test/missing.pem"
+		})
+
+		It("returns an error", func() {
+			Expect(err).ToNot(BeNil())
+			Expect(err.Error()).To(HavePrefix("error loading TLS certificate"))
+		})
+	})
+
+	When("client certificates are required without a client CA", func() {
+		BeforeEach(func() {
+			tc.RequireClientCert = true
+		})
+
+		It("returns an error", func() {
+			Expect(err).ToNot(BeNil())
+			Expect(err.Error()).To(Equal("a client CA file must be set to require client certificates"))
+		})
+	})
+
+	When("the client CA file has no certificates", func() {
+		BeforeEach(func() {
+			tc.ClientCAFile = "test/key.pem"
+		})
+
+		It("returns an error", func() {
+			Expect(err).ToNot(BeNil())
+			Expect(err.Error()).To(Equal("no certificates found in client CA file test/key.pem"))
+		})
+	})
+
+	When("no client CA is set", func() {
+		It("does not request client certificates", func() {
+			Expect(err).To(BeNil())
+			Expect(config.Certificates).To(HaveLen(1))
+			Expect(config.MinVersion).To(Equal(uint16(tls.VersionTLS12)))
+			Expect(config.ClientAuth).To(Equal(tls.NoClientCert))
+		})
+	})
+
+	When("a client CA is set", func() {
+		BeforeEach(func() {
+			tc.ClientCAFile = "test/cert.pem"
+		})
+
+		It("verifies client certificates that are given", func() {
+			Expect(err).To(BeNil())
+			Expect(config.ClientCAs).ToNot(BeNil())
+			Expect(config.ClientAuth).To(Equal(tls.VerifyClientCertIfGiven))
+		})
+
+		When("client certificates are required", func() {
+			BeforeEach(func() {
+				tc.RequireClientCert = true
+			})
+
+			It("requires verified client certificates", func() {
+				Expect(err).To(BeNil())
+				Expect(config.ClientAuth).To(Equal(tls.RequireAndVerifyClientCert))
+			})
+		})
+	})
+})
@@ -0,0 +1,108 @@
+package snapshot
+
+import (
+	"fmt"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+const (
+	InstanceKey = `Snapshotter`
+	// DefaultInterval is how often the response of a read is snapshotted,
+	// if not configured.
+	DefaultInterval = 5 * time.Minute
+)
+
+// Snapshot is the response of a read, such as the server groups of an
+// application, as it was served at CreatedAt, so the read can be answered
+// as of a past time. Bodies are stored compressed by the hash in BodyHash, so
+// a body that did not change between snapshots is stored once.
+type Snapshot struct {
+	ID        uint      `gorm:"primary_key"`
+	Path      string    `gorm:"index:idx_cache_snapshots_path_created_at"`
+	Body      string    `gorm:"type:longtext"`
+	BodyHash  string    `gorm:"index"`
+	CreatedAt time.Time `gorm:"index:idx_cache_snapshots_path_created_at"`
+}
+
+func (Snapshot) TableName() string {
+	return "cache_snapshots"
+}
+
+// Snapshotter decides when the responses of reads are snapshotted.
+//
+//go:generate counterfeiter . Snapshotter
+type Snapshotter interface {
+	// Due returns true if the response of path served at now should be
+	// snapshotted, and if so expects it to be.
+	Due(path string, now time.Time) bool
+}
+
+// New returns a Snapshotter that snapshots the response of each path at
+// most once every interval.
+func New(interval time.Duration) Snapshotter {
+	if interval <= 0 {
+		interval = DefaultInterval
+	}
+
+	return &snapshotter{
+		interval: interval,
+		taken:    map[string]time.Time{},
+	}
+}
+
+type snapshotter struct {
+	mux      sync.Mutex
+	interval time.Duration
+	taken    map[string]time.Time
+	swept    time.Time
+}
+
+func (s *snapshotter) Due(path string, now time.Time) bool {
+	s.mux.Lock()
+	defer s.mux.Unlock()
+
+	// Forget paths whose snapshots are due anyway so they do not pile up.
+	if now.Sub(s.swept) > s.interval {
+		for p, t := range s.taken {
+			if now.Sub(t) >= s.interval {
+				delete(s.taken, p)
+			}
+		}
+
+		s.swept = now
+	}
+
+	if t, ok := s.taken[path]; ok && now.Sub(t) < s.interval {
+		return false
+	}
+
+	s.taken[path] = now
+
+	return true
+}
+
+// ParseAsOf parses the time a read is answered as of, either RFC3339, such
+// as 2020-02-13T14:12:03Z, or milliseconds since the epoch as Spinnaker
+// sends times.
+func ParseAsOf(s string) (time.Time, error) {
+	if ms, err := strconv.ParseInt(s, 10, 64); err == nil {
+		return time.Unix(0, ms*int64(time.Millisecond)), nil
+	}
+
+	t, err := time.Parse(time.RFC3339, s)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("invalid asOf %q, must be RFC3339 or milliseconds since the epoch", s)
+	}
+
+	return t, nil
+}
+
+// Instance returns the Snapshotter, or nil if snapshots are disabled.
+func Instance(c *gin.Context) Snapshotter {
+	s, _ := c.MustGet(InstanceKey).(Snapshotter)
+	return s
+}
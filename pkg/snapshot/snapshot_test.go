@@ -0,0 +1,50 @@
+package snapshot_test
+
+import (
+	"time"
+
+	. "github.com/billiford/go-clouddriver/pkg/snapshot"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Snapshot", func() {
+	Describe("#Due", func() {
+		var (
+			s   Snapshotter
+			now time.Time
+		)
+
+		BeforeEach(func() {
+			s = New(time.Minute)
+			now = time.Now()
+		})
+
+		It("snapshots each path at most once every interval", func() {
+			Expect(s.Due("/applications/test-app/serverGroups", now)).To(BeTrue())
+			Expect(s.Due("/applications/test-app/serverGroups", now.Add(30*time.Second))).To(BeFalse())
+			Expect(s.Due("/applications/test-app/clusters", now.Add(30*time.Second))).To(BeTrue())
+			Expect(s.Due("/applications/test-app/serverGroups", now.Add(time.Minute))).To(BeTrue())
+		})
+	})
+
+	Describe("#ParseAsOf", func() {
+		It("parses RFC3339", func() {
+			t, err := ParseAsOf("2020-02-13T14:12:03Z")
+			Expect(err).To(BeNil())
+			Expect(t.Unix()).To(Equal(int64(1581603123)))
+		})
+
+		It("parses milliseconds since the epoch", func() {
+			t, err := ParseAsOf("1581603123000")
+			Expect(err).To(BeNil())
+			Expect(t.Unix()).To(Equal(int64(1581603123)))
+		})
+
+		It("returns an error for other times", func() {
+			_, err := ParseAsOf("yesterday")
+			Expect(err).ToNot(BeNil())
+			Expect(err.Error()).To(Equal(`invalid asOf "yesterday", must be RFC3339 or milliseconds since the epoch`))
+		})
+	})
+})
@@ -0,0 +1,195 @@
+package sql
+
+import (
+	"context"
+	"database/sql"
+	"log"
+	"regexp"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+const (
+	// Max number of prepared statements to keep open. Most queries are
+	// a fixed set of shapes, but IN clauses get a statement per their
+	// number of arguments.
+	maxPreparedStatements = 256
+	// Queries that take longer than this are logged by default.
+	defaultSlowQueryThreshold = 500 * time.Millisecond
+)
+
+var (
+	tableRegexp   = regexp.MustCompile("(?i)(?:from|into|update)\\s+[\"`]?([a-z0-9_]+)")
+	queryDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Name: "clouddriver_sql_query_duration_seconds",
+		Help: "Latency of SQL queries by operation and table.",
+	}, []string{"operation", "table"})
+)
+
+func init() {
+	prometheus.MustRegister(queryDuration)
+}
+
+// conn is the connection gorm runs queries on. It records the latency
+// of each query, logs slow queries, runs queries with a context so they
+// are cancelled along with the request that made them and optionally
+// reuses prepared statements.
+//
+// Queries run inside of a transaction go straight to the transaction
+// and are not instrumented.
+type conn struct {
+	db                 *sql.DB
+	ctx                context.Context
+	stmts              *stmtCache
+	slowQueryThreshold time.Duration
+}
+
+func newConn(db *sql.DB) *conn {
+	return &conn{
+		db:                 db,
+		ctx:                context.Background(),
+		slowQueryThreshold: defaultSlowQueryThreshold,
+	}
+}
+
+// withContext returns a copy of the connection that runs queries with ctx.
+func (c *conn) withContext(ctx context.Context) *conn {
+	cc := *c
+	cc.ctx = ctx
+
+	return &cc
+}
+
+func (c *conn) Exec(query string, args ...interface{}) (sql.Result, error) {
+	defer c.observe(query, time.Now())
+
+	if stmt := c.stmt(query); stmt != nil {
+		return stmt.ExecContext(c.ctx, args...)
+	}
+
+	return c.db.ExecContext(c.ctx, query, args...)
+}
+
+func (c *conn) Prepare(query string) (*sql.Stmt, error) {
+	return c.db.PrepareContext(c.ctx, query)
+}
+
+func (c *conn) Query(query string, args ...interface{}) (*sql.Rows, error) {
+	defer c.observe(query, time.Now())
+
+	if stmt := c.stmt(query); stmt != nil {
+		return stmt.QueryContext(c.ctx, args...)
+	}
+
+	return c.db.QueryContext(c.ctx, query, args...)
+}
+
+func (c *conn) QueryRow(query string, args ...interface{}) *sql.Row {
+	defer c.observe(query, time.Now())
+
+	if stmt := c.stmt(query); stmt != nil {
+		return stmt.QueryRowContext(c.ctx, args...)
+	}
+
+	return c.db.QueryRowContext(c.ctx, query, args...)
+}
+
+func (c *conn) Begin() (*sql.Tx, error) {
+	return c.db.BeginTx(c.ctx, nil)
+}
+
+func (c *conn) BeginTx(ctx context.Context, opts *sql.TxOptions) (*sql.Tx, error) {
+	return c.db.BeginTx(ctx, opts)
+}
+
+func (c *conn) Close() error {
+	if c.stmts != nil {
+		c.stmts.close()
+	}
+
+	return c.db.Close()
+}
+
+// stmt returns a prepared statement for the query, or nil if prepared
+// statements are disabled or the query could not be prepared.
+func (c *conn) stmt(query string) *sql.Stmt {
+	if c.stmts == nil {
+		return nil
+	}
+
+	return c.stmts.get(c.db, query)
+}
+
+func (c *conn) observe(query string, start time.Time) {
+	elapsed := time.Since(start)
+	operation, table := labels(query)
+
+	queryDuration.WithLabelValues(operation, table).Observe(elapsed.Seconds())
+
+	// Only log the query, args can hold sensitive data such as CA data.
+	if c.slowQueryThreshold > 0 && elapsed >= c.slowQueryThreshold {
+		log.Printf("[CLOUDDRIVER] slow SQL query (%s): %s\n", elapsed, query)
+	}
+}
+
+// labels returns the operation (select, insert, etc) and table of a query.
+func labels(query string) (string, string) {
+	operation, table := "unknown", "unknown"
+
+	fields := strings.Fields(query)
+	if len(fields) > 0 {
+		operation = strings.ToLower(fields[0])
+	}
+
+	if m := tableRegexp.FindStringSubmatch(query); m != nil {
+		table = m[1]
+	}
+
+	return operation, table
+}
+
+// stmtCache holds prepared statements by query. Statements are prepared
+// without a context so they outlive the request that first used them.
+type stmtCache struct {
+	mux   sync.Mutex
+	stmts map[string]*sql.Stmt
+}
+
+func newStmtCache() *stmtCache {
+	return &stmtCache{stmts: map[string]*sql.Stmt{}}
+}
+
+func (sc *stmtCache) get(db *sql.DB, query string) *sql.Stmt {
+	sc.mux.Lock()
+	defer sc.mux.Unlock()
+
+	if stmt, ok := sc.stmts[query]; ok {
+		return stmt
+	}
+
+	if len(sc.stmts) >= maxPreparedStatements {
+		return nil
+	}
+
+	stmt, err := db.Prepare(query)
+	if err != nil {
+		return nil
+	}
+
+	sc.stmts[query] = stmt
+
+	return stmt
+}
+
+func (sc *stmtCache) close() {
+	sc.mux.Lock()
+	defer sc.mux.Unlock()
+
+	for query, stmt := range sc.stmts {
+		stmt.Close()
+		delete(sc.stmts, query)
+	}
+}
@@ -2,14 +2,100 @@
 package sqlfakes
 
 import (
+	"context"
 	"sync"
+	"time"
 
 	clouddriver "github.com/billiford/go-clouddriver/pkg"
 	"github.com/billiford/go-clouddriver/pkg/kubernetes"
+	"github.com/billiford/go-clouddriver/pkg/snapshot"
 	"github.com/billiford/go-clouddriver/pkg/sql"
 )
 
 type FakeClient struct {
+	AcquireClusterLocksStub        func(string, []string, time.Time) (string, string, error)
+	acquireClusterLocksMutex       sync.RWMutex
+	acquireClusterLocksArgsForCall []struct {
+		arg1 string
+		arg2 []string
+		arg3 time.Time
+	}
+	acquireClusterLocksReturns struct {
+		result1 string
+		result2 string
+		result3 error
+	}
+	acquireClusterLocksReturnsOnCall map[int]struct {
+		result1 string
+		result2 string
+		result3 error
+	}
+	CreateApplicationStub        func(clouddriver.Application) error
+	createApplicationMutex       sync.RWMutex
+	createApplicationArgsForCall []struct {
+		arg1 clouddriver.Application
+	}
+	createApplicationReturns struct {
+		result1 error
+	}
+	createApplicationReturnsOnCall map[int]struct {
+		result1 error
+	}
+	CreateCacheSnapshotStub        func(snapshot.Snapshot) error
+	createCacheSnapshotMutex       sync.RWMutex
+	createCacheSnapshotArgsForCall []struct {
+		arg1 snapshot.Snapshot
+	}
+	createCacheSnapshotReturns struct {
+		result1 error
+	}
+	createCacheSnapshotReturnsOnCall map[int]struct {
+		result1 error
+	}
+	CreateDeployStub        func(clouddriver.Deploy) error
+	createDeployMutex       sync.RWMutex
+	createDeployArgsForCall []struct {
+		arg1 clouddriver.Deploy
+	}
+	createDeployReturns struct {
+		result1 error
+	}
+	createDeployReturnsOnCall map[int]struct {
+		result1 error
+	}
+	CreateExecutePermissionStub        func(clouddriver.ExecutePermission) error
+	createExecutePermissionMutex       sync.RWMutex
+	createExecutePermissionArgsForCall []struct {
+		arg1 clouddriver.ExecutePermission
+	}
+	createExecutePermissionReturns struct {
+		result1 error
+	}
+	createExecutePermissionReturnsOnCall map[int]struct {
+		result1 error
+	}
+	CreateFailedOperationStub        func(clouddriver.FailedOperation) error
+	createFailedOperationMutex       sync.RWMutex
+	createFailedOperationArgsForCall []struct {
+		arg1 clouddriver.FailedOperation
+	}
+	createFailedOperationReturns struct {
+		result1 error
+	}
+	createFailedOperationReturnsOnCall map[int]struct {
+		result1 error
+	}
+	CreateKubernetesClusterCredentialStub        func(kubernetes.ClusterCredential) error
+	createKubernetesClusterCredentialMutex       sync.RWMutex
+	createKubernetesClusterCredentialArgsForCall []struct {
+		arg1 kubernetes.ClusterCredential
+	}
+	createKubernetesClusterCredentialReturns struct {
+		result1 error
+	}
+	createKubernetesClusterCredentialReturnsOnCall map[int]struct {
+		result1 error
+	}
 	CreateKubernetesProviderStub        func(kubernetes.Provider) error
 	createKubernetesProviderMutex       sync.RWMutex
 	createKubernetesProviderArgsForCall []struct {
@@ -32,6 +118,28 @@ type FakeClient struct {
 	createKubernetesResourceReturnsOnCall map[int]struct {
 		result1 error
 	}
+	CreateMigrationReportStub        func(clouddriver.MigrationReport) error
+	createMigrationReportMutex       sync.RWMutex
+	createMigrationReportArgsForCall []struct {
+		arg1 clouddriver.MigrationReport
+	}
+	createMigrationReportReturns struct {
+		result1 error
+	}
+	createMigrationReportReturnsOnCall map[int]struct {
+		result1 error
+	}
+	CreateNamespacePermissionStub        func(clouddriver.NamespacePermission) error
+	createNamespacePermissionMutex       sync.RWMutex
+	createNamespacePermissionArgsForCall []struct {
+		arg1 clouddriver.NamespacePermission
+	}
+	createNamespacePermissionReturns struct {
+		result1 error
+	}
+	createNamespacePermissionReturnsOnCall map[int]struct {
+		result1 error
+	}
 	CreateReadPermissionStub        func(clouddriver.ReadPermission) error
 	createReadPermissionMutex       sync.RWMutex
 	createReadPermissionArgsForCall []struct {
@@ -43,6 +151,17 @@ type FakeClient struct {
 	createReadPermissionReturnsOnCall map[int]struct {
 		result1 error
 	}
+	CreateTaskEventStub        func(clouddriver.TaskEvent) error
+	createTaskEventMutex       sync.RWMutex
+	createTaskEventArgsForCall []struct {
+		arg1 clouddriver.TaskEvent
+	}
+	createTaskEventReturns struct {
+		result1 error
+	}
+	createTaskEventReturnsOnCall map[int]struct {
+		result1 error
+	}
 	CreateWritePermissionStub        func(clouddriver.WritePermission) error
 	createWritePermissionMutex       sync.RWMutex
 	createWritePermissionArgsForCall []struct {
@@ -54,6 +173,92 @@ type FakeClient struct {
 	createWritePermissionReturnsOnCall map[int]struct {
 		result1 error
 	}
+	DeleteApplicationStub        func(string) error
+	deleteApplicationMutex       sync.RWMutex
+	deleteApplicationArgsForCall []struct {
+		arg1 string
+	}
+	deleteApplicationReturns struct {
+		result1 error
+	}
+	deleteApplicationReturnsOnCall map[int]struct {
+		result1 error
+	}
+	DeleteCacheSnapshotsCreatedBeforeStub        func(time.Time) (int64, error)
+	deleteCacheSnapshotsCreatedBeforeMutex       sync.RWMutex
+	deleteCacheSnapshotsCreatedBeforeArgsForCall []struct {
+		arg1 time.Time
+	}
+	deleteCacheSnapshotsCreatedBeforeReturns struct {
+		result1 int64
+		result2 error
+	}
+	deleteCacheSnapshotsCreatedBeforeReturnsOnCall map[int]struct {
+		result1 int64
+		result2 error
+	}
+	DeleteCompressedBlobsUnusedSinceStub        func(time.Time) (int64, error)
+	deleteCompressedBlobsUnusedSinceMutex       sync.RWMutex
+	deleteCompressedBlobsUnusedSinceArgsForCall []struct {
+		arg1 time.Time
+	}
+	deleteCompressedBlobsUnusedSinceReturns struct {
+		result1 int64
+		result2 error
+	}
+	deleteCompressedBlobsUnusedSinceReturnsOnCall map[int]struct {
+		result1 int64
+		result2 error
+	}
+	DeleteDeploysCreatedBeforeStub        func(time.Time) (int64, error)
+	deleteDeploysCreatedBeforeMutex       sync.RWMutex
+	deleteDeploysCreatedBeforeArgsForCall []struct {
+		arg1 time.Time
+	}
+	deleteDeploysCreatedBeforeReturns struct {
+		result1 int64
+		result2 error
+	}
+	deleteDeploysCreatedBeforeReturnsOnCall map[int]struct {
+		result1 int64
+		result2 error
+	}
+	DeleteFailedOperationsCreatedBeforeStub        func(time.Time) (int64, error)
+	deleteFailedOperationsCreatedBeforeMutex       sync.RWMutex
+	deleteFailedOperationsCreatedBeforeArgsForCall []struct {
+		arg1 time.Time
+	}
+	deleteFailedOperationsCreatedBeforeReturns struct {
+		result1 int64
+		result2 error
+	}
+	deleteFailedOperationsCreatedBeforeReturnsOnCall map[int]struct {
+		result1 int64
+		result2 error
+	}
+	DeleteKindMappingStub        func(string, string) error
+	deleteKindMappingMutex       sync.RWMutex
+	deleteKindMappingArgsForCall []struct {
+		arg1 string
+		arg2 string
+	}
+	deleteKindMappingReturns struct {
+		result1 error
+	}
+	deleteKindMappingReturnsOnCall map[int]struct {
+		result1 error
+	}
+	DeleteKubernetesClusterCredentialStub        func(string) error
+	deleteKubernetesClusterCredentialMutex       sync.RWMutex
+	deleteKubernetesClusterCredentialArgsForCall []struct {
+		arg1 string
+	}
+	deleteKubernetesClusterCredentialReturns struct {
+		result1 error
+	}
+	deleteKubernetesClusterCredentialReturnsOnCall map[int]struct {
+		result1 error
+	}
 	DeleteKubernetesProviderStub        func(string) error
 	deleteKubernetesProviderMutex       sync.RWMutex
 	deleteKubernetesProviderArgsForCall []struct {
@@ -65,6 +270,109 @@ type FakeClient struct {
 	deleteKubernetesProviderReturnsOnCall map[int]struct {
 		result1 error
 	}
+	DeleteKubernetesResourcesCreatedBeforeStub        func(time.Time) (int64, error)
+	deleteKubernetesResourcesCreatedBeforeMutex       sync.RWMutex
+	deleteKubernetesResourcesCreatedBeforeArgsForCall []struct {
+		arg1 time.Time
+	}
+	deleteKubernetesResourcesCreatedBeforeReturns struct {
+		result1 int64
+		result2 error
+	}
+	deleteKubernetesResourcesCreatedBeforeReturnsOnCall map[int]struct {
+		result1 int64
+		result2 error
+	}
+	DeleteMigrationReportsCreatedBeforeStub        func(time.Time) (int64, error)
+	deleteMigrationReportsCreatedBeforeMutex       sync.RWMutex
+	deleteMigrationReportsCreatedBeforeArgsForCall []struct {
+		arg1 time.Time
+	}
+	deleteMigrationReportsCreatedBeforeReturns struct {
+		result1 int64
+		result2 error
+	}
+	deleteMigrationReportsCreatedBeforeReturnsOnCall map[int]struct {
+		result1 int64
+		result2 error
+	}
+	DeleteOrphanedKubernetesResourcesStub        func() (int64, error)
+	deleteOrphanedKubernetesResourcesMutex       sync.RWMutex
+	deleteOrphanedKubernetesResourcesArgsForCall []struct {
+	}
+	deleteOrphanedKubernetesResourcesReturns struct {
+		result1 int64
+		result2 error
+	}
+	deleteOrphanedKubernetesResourcesReturnsOnCall map[int]struct {
+		result1 int64
+		result2 error
+	}
+	DeleteOrphanedPermissionsStub        func() (int64, error)
+	deleteOrphanedPermissionsMutex       sync.RWMutex
+	deleteOrphanedPermissionsArgsForCall []struct {
+	}
+	deleteOrphanedPermissionsReturns struct {
+		result1 int64
+		result2 error
+	}
+	deleteOrphanedPermissionsReturnsOnCall map[int]struct {
+		result1 int64
+		result2 error
+	}
+	DeleteTaskEventsCreatedBeforeStub        func(time.Time) (int64, error)
+	deleteTaskEventsCreatedBeforeMutex       sync.RWMutex
+	deleteTaskEventsCreatedBeforeArgsForCall []struct {
+		arg1 time.Time
+	}
+	deleteTaskEventsCreatedBeforeReturns struct {
+		result1 int64
+		result2 error
+	}
+	deleteTaskEventsCreatedBeforeReturnsOnCall map[int]struct {
+		result1 int64
+		result2 error
+	}
+	GetCacheSnapshotStub        func(string, time.Time) (snapshot.Snapshot, error)
+	getCacheSnapshotMutex       sync.RWMutex
+	getCacheSnapshotArgsForCall []struct {
+		arg1 string
+		arg2 time.Time
+	}
+	getCacheSnapshotReturns struct {
+		result1 snapshot.Snapshot
+		result2 error
+	}
+	getCacheSnapshotReturnsOnCall map[int]struct {
+		result1 snapshot.Snapshot
+		result2 error
+	}
+	GetFailedOperationStub        func(string) (clouddriver.FailedOperation, error)
+	getFailedOperationMutex       sync.RWMutex
+	getFailedOperationArgsForCall []struct {
+		arg1 string
+	}
+	getFailedOperationReturns struct {
+		result1 clouddriver.FailedOperation
+		result2 error
+	}
+	getFailedOperationReturnsOnCall map[int]struct {
+		result1 clouddriver.FailedOperation
+		result2 error
+	}
+	GetKubernetesClusterCredentialStub        func(string) (kubernetes.ClusterCredential, error)
+	getKubernetesClusterCredentialMutex       sync.RWMutex
+	getKubernetesClusterCredentialArgsForCall []struct {
+		arg1 string
+	}
+	getKubernetesClusterCredentialReturns struct {
+		result1 kubernetes.ClusterCredential
+		result2 error
+	}
+	getKubernetesClusterCredentialReturnsOnCall map[int]struct {
+		result1 kubernetes.ClusterCredential
+		result2 error
+	}
 	GetKubernetesProviderStub        func(string) (kubernetes.Provider, error)
 	getKubernetesProviderMutex       sync.RWMutex
 	getKubernetesProviderArgsForCall []struct {
@@ -78,6 +386,93 @@ type FakeClient struct {
 		result1 kubernetes.Provider
 		result2 error
 	}
+	GetKubernetesProviderVersionStub        func() (int64, error)
+	getKubernetesProviderVersionMutex       sync.RWMutex
+	getKubernetesProviderVersionArgsForCall []struct {
+	}
+	getKubernetesProviderVersionReturns struct {
+		result1 int64
+		result2 error
+	}
+	getKubernetesProviderVersionReturnsOnCall map[int]struct {
+		result1 int64
+		result2 error
+	}
+	GetMigrationReportStub        func(string) (clouddriver.MigrationReport, error)
+	getMigrationReportMutex       sync.RWMutex
+	getMigrationReportArgsForCall []struct {
+		arg1 string
+	}
+	getMigrationReportReturns struct {
+		result1 clouddriver.MigrationReport
+		result2 error
+	}
+	getMigrationReportReturnsOnCall map[int]struct {
+		result1 clouddriver.MigrationReport
+		result2 error
+	}
+	ListApplicationsStub        func() ([]clouddriver.Application, error)
+	listApplicationsMutex       sync.RWMutex
+	listApplicationsArgsForCall []struct {
+	}
+	listApplicationsReturns struct {
+		result1 []clouddriver.Application
+		result2 error
+	}
+	listApplicationsReturnsOnCall map[int]struct {
+		result1 []clouddriver.Application
+		result2 error
+	}
+	ListDeletedApplicationsStub        func() ([]string, error)
+	listDeletedApplicationsMutex       sync.RWMutex
+	listDeletedApplicationsArgsForCall []struct {
+	}
+	listDeletedApplicationsReturns struct {
+		result1 []string
+		result2 error
+	}
+	listDeletedApplicationsReturnsOnCall map[int]struct {
+		result1 []string
+		result2 error
+	}
+	ListDeploysCreatedSinceStub        func(time.Time) ([]clouddriver.Deploy, error)
+	listDeploysCreatedSinceMutex       sync.RWMutex
+	listDeploysCreatedSinceArgsForCall []struct {
+		arg1 time.Time
+	}
+	listDeploysCreatedSinceReturns struct {
+		result1 []clouddriver.Deploy
+		result2 error
+	}
+	listDeploysCreatedSinceReturnsOnCall map[int]struct {
+		result1 []clouddriver.Deploy
+		result2 error
+	}
+	ListFeaturesStub        func() ([]clouddriver.Feature, error)
+	listFeaturesMutex       sync.RWMutex
+	listFeaturesArgsForCall []struct {
+	}
+	listFeaturesReturns struct {
+		result1 []clouddriver.Feature
+		result2 error
+	}
+	listFeaturesReturnsOnCall map[int]struct {
+		result1 []clouddriver.Feature
+		result2 error
+	}
+	ListKindMappingsByAccountNamesStub        func(...string) (map[string]map[string]string, error)
+	listKindMappingsByAccountNamesMutex       sync.RWMutex
+	listKindMappingsByAccountNamesArgsForCall []struct {
+		arg1 []string
+	}
+	listKindMappingsByAccountNamesReturns struct {
+		result1 map[string]map[string]string
+		result2 error
+	}
+	listKindMappingsByAccountNamesReturnsOnCall map[int]struct {
+		result1 map[string]map[string]string
+		result2 error
+	}
 	ListKubernetesAccountsBySpinnakerAppStub        func(string) ([]string, error)
 	listKubernetesAccountsBySpinnakerAppMutex       sync.RWMutex
 	listKubernetesAccountsBySpinnakerAppArgsForCall []struct {
@@ -104,6 +499,31 @@ type FakeClient struct {
 		result1 []kubernetes.Resource
 		result2 error
 	}
+	ListKubernetesLastDeployTimesStub        func() (map[string]time.Time, error)
+	listKubernetesLastDeployTimesMutex       sync.RWMutex
+	listKubernetesLastDeployTimesArgsForCall []struct {
+	}
+	listKubernetesLastDeployTimesReturns struct {
+		result1 map[string]time.Time
+		result2 error
+	}
+	listKubernetesLastDeployTimesReturnsOnCall map[int]struct {
+		result1 map[string]time.Time
+		result2 error
+	}
+	ListKubernetesProviderNamesByClusterCredentialStub        func(string) ([]string, error)
+	listKubernetesProviderNamesByClusterCredentialMutex       sync.RWMutex
+	listKubernetesProviderNamesByClusterCredentialArgsForCall []struct {
+		arg1 string
+	}
+	listKubernetesProviderNamesByClusterCredentialReturns struct {
+		result1 []string
+		result2 error
+	}
+	listKubernetesProviderNamesByClusterCredentialReturnsOnCall map[int]struct {
+		result1 []string
+		result2 error
+	}
 	ListKubernetesProvidersStub        func() ([]kubernetes.Provider, error)
 	listKubernetesProvidersMutex       sync.RWMutex
 	listKubernetesProvidersArgsForCall []struct {
@@ -128,6 +548,32 @@ type FakeClient struct {
 		result1 []kubernetes.Provider
 		result2 error
 	}
+	ListKubernetesProvidersChangedSinceStub        func(int64) ([]kubernetes.Provider, error)
+	listKubernetesProvidersChangedSinceMutex       sync.RWMutex
+	listKubernetesProvidersChangedSinceArgsForCall []struct {
+		arg1 int64
+	}
+	listKubernetesProvidersChangedSinceReturns struct {
+		result1 []kubernetes.Provider
+		result2 error
+	}
+	listKubernetesProvidersChangedSinceReturnsOnCall map[int]struct {
+		result1 []kubernetes.Provider
+		result2 error
+	}
+	ListKubernetesProvidersDeletedSinceStub        func(int64) ([]string, error)
+	listKubernetesProvidersDeletedSinceMutex       sync.RWMutex
+	listKubernetesProvidersDeletedSinceArgsForCall []struct {
+		arg1 int64
+	}
+	listKubernetesProvidersDeletedSinceReturns struct {
+		result1 []string
+		result2 error
+	}
+	listKubernetesProvidersDeletedSinceReturnsOnCall map[int]struct {
+		result1 []string
+		result2 error
+	}
 	ListKubernetesResourceNamesByAccountNameAndKindAndNamespaceStub        func(string, string, string) ([]string, error)
 	listKubernetesResourceNamesByAccountNameAndKindAndNamespaceMutex       sync.RWMutex
 	listKubernetesResourceNamesByAccountNameAndKindAndNamespaceArgsForCall []struct {
@@ -143,6 +589,19 @@ type FakeClient struct {
 		result1 []string
 		result2 error
 	}
+	ListKubernetesResourcesByAccountNamesStub        func(...string) ([]kubernetes.Resource, error)
+	listKubernetesResourcesByAccountNamesMutex       sync.RWMutex
+	listKubernetesResourcesByAccountNamesArgsForCall []struct {
+		arg1 []string
+	}
+	listKubernetesResourcesByAccountNamesReturns struct {
+		result1 []kubernetes.Resource
+		result2 error
+	}
+	listKubernetesResourcesByAccountNamesReturnsOnCall map[int]struct {
+		result1 []kubernetes.Resource
+		result2 error
+	}
 	ListKubernetesResourcesByFieldsStub        func(...string) ([]kubernetes.Resource, error)
 	listKubernetesResourcesByFieldsMutex       sync.RWMutex
 	listKubernetesResourcesByFieldsArgsForCall []struct {
@@ -169,6 +628,19 @@ type FakeClient struct {
 		result1 []kubernetes.Resource
 		result2 error
 	}
+	ListPermissionsByAccountNamesStub        func(...string) (map[string]kubernetes.ProviderPermissions, error)
+	listPermissionsByAccountNamesMutex       sync.RWMutex
+	listPermissionsByAccountNamesArgsForCall []struct {
+		arg1 []string
+	}
+	listPermissionsByAccountNamesReturns struct {
+		result1 map[string]kubernetes.ProviderPermissions
+		result2 error
+	}
+	listPermissionsByAccountNamesReturnsOnCall map[int]struct {
+		result1 map[string]kubernetes.ProviderPermissions
+		result2 error
+	}
 	ListReadGroupsByAccountNameStub        func(string) ([]string, error)
 	listReadGroupsByAccountNameMutex       sync.RWMutex
 	listReadGroupsByAccountNameArgsForCall []struct {
@@ -182,6 +654,19 @@ type FakeClient struct {
 		result1 []string
 		result2 error
 	}
+	ListTaskEventsStub        func(string) ([]clouddriver.TaskEvent, error)
+	listTaskEventsMutex       sync.RWMutex
+	listTaskEventsArgsForCall []struct {
+		arg1 string
+	}
+	listTaskEventsReturns struct {
+		result1 []clouddriver.TaskEvent
+		result2 error
+	}
+	listTaskEventsReturnsOnCall map[int]struct {
+		result1 []clouddriver.TaskEvent
+		result2 error
+	}
 	ListWriteGroupsByAccountNameStub        func(string) ([]string, error)
 	listWriteGroupsByAccountNameMutex       sync.RWMutex
 	listWriteGroupsByAccountNameArgsForCall []struct {
@@ -195,360 +680,2003 @@ type FakeClient struct {
 		result1 []string
 		result2 error
 	}
-	invocations      map[string][][]interface{}
-	invocationsMutex sync.RWMutex
-}
-
-func (fake *FakeClient) CreateKubernetesProvider(arg1 kubernetes.Provider) error {
-	fake.createKubernetesProviderMutex.Lock()
-	ret, specificReturn := fake.createKubernetesProviderReturnsOnCall[len(fake.createKubernetesProviderArgsForCall)]
-	fake.createKubernetesProviderArgsForCall = append(fake.createKubernetesProviderArgsForCall, struct {
-		arg1 kubernetes.Provider
-	}{arg1})
-	fake.recordInvocation("CreateKubernetesProvider", []interface{}{arg1})
-	fake.createKubernetesProviderMutex.Unlock()
-	if fake.CreateKubernetesProviderStub != nil {
-		return fake.CreateKubernetesProviderStub(arg1)
+	ReleaseClusterLocksStub        func(string, []string) error
+	releaseClusterLocksMutex       sync.RWMutex
+	releaseClusterLocksArgsForCall []struct {
+		arg1 string
+		arg2 []string
 	}
-	if specificReturn {
-		return ret.result1
+	releaseClusterLocksReturns struct {
+		result1 error
 	}
-	fakeReturns := fake.createKubernetesProviderReturns
-	return fakeReturns.result1
-}
-
-func (fake *FakeClient) CreateKubernetesProviderCallCount() int {
-	fake.createKubernetesProviderMutex.RLock()
-	defer fake.createKubernetesProviderMutex.RUnlock()
-	return len(fake.createKubernetesProviderArgsForCall)
-}
-
-func (fake *FakeClient) CreateKubernetesProviderCalls(stub func(kubernetes.Provider) error) {
-	fake.createKubernetesProviderMutex.Lock()
-	defer fake.createKubernetesProviderMutex.Unlock()
-	fake.CreateKubernetesProviderStub = stub
-}
-
-func (fake *FakeClient) CreateKubernetesProviderArgsForCall(i int) kubernetes.Provider {
-	fake.createKubernetesProviderMutex.RLock()
-	defer fake.createKubernetesProviderMutex.RUnlock()
-	argsForCall := fake.createKubernetesProviderArgsForCall[i]
-	return argsForCall.arg1
+	releaseClusterLocksReturnsOnCall map[int]struct {
+		result1 error
+	}
+	RenewClusterLocksStub        func(string, []string, time.Time) error
+	renewClusterLocksMutex       sync.RWMutex
+	renewClusterLocksArgsForCall []struct {
+		arg1 string
+		arg2 []string
+		arg3 time.Time
+	}
+	renewClusterLocksReturns struct {
+		result1 error
+	}
+	renewClusterLocksReturnsOnCall map[int]struct {
+		result1 error
+	}
+	RotateKubernetesProviderCredentialsStub        func(string, string, string) error
+	rotateKubernetesProviderCredentialsMutex       sync.RWMutex
+	rotateKubernetesProviderCredentialsArgsForCall []struct {
+		arg1 string
+		arg2 string
+		arg3 string
+	}
+	rotateKubernetesProviderCredentialsReturns struct {
+		result1 error
+	}
+	rotateKubernetesProviderCredentialsReturnsOnCall map[int]struct {
+		result1 error
+	}
+	SetDeployPhaseStub        func(string, string, time.Time) error
+	setDeployPhaseMutex       sync.RWMutex
+	setDeployPhaseArgsForCall []struct {
+		arg1 string
+		arg2 string
+		arg3 time.Time
+	}
+	setDeployPhaseReturns struct {
+		result1 error
+	}
+	setDeployPhaseReturnsOnCall map[int]struct {
+		result1 error
+	}
+	SetFeatureStub        func(clouddriver.Feature) error
+	setFeatureMutex       sync.RWMutex
+	setFeatureArgsForCall []struct {
+		arg1 clouddriver.Feature
+	}
+	setFeatureReturns struct {
+		result1 error
+	}
+	setFeatureReturnsOnCall map[int]struct {
+		result1 error
+	}
+	SetKindMappingStub        func(kubernetes.KindMapping) error
+	setKindMappingMutex       sync.RWMutex
+	setKindMappingArgsForCall []struct {
+		arg1 kubernetes.KindMapping
+	}
+	setKindMappingReturns struct {
+		result1 error
+	}
+	setKindMappingReturnsOnCall map[int]struct {
+		result1 error
+	}
+	SetKubernetesProviderMaintenanceStub        func(string, bool, string) error
+	setKubernetesProviderMaintenanceMutex       sync.RWMutex
+	setKubernetesProviderMaintenanceArgsForCall []struct {
+		arg1 string
+		arg2 bool
+		arg3 string
+	}
+	setKubernetesProviderMaintenanceReturns struct {
+		result1 error
+	}
+	setKubernetesProviderMaintenanceReturnsOnCall map[int]struct {
+		result1 error
+	}
+	UpdateKubernetesClusterCredentialStub        func(kubernetes.ClusterCredential) error
+	updateKubernetesClusterCredentialMutex       sync.RWMutex
+	updateKubernetesClusterCredentialArgsForCall []struct {
+		arg1 kubernetes.ClusterCredential
+	}
+	updateKubernetesClusterCredentialReturns struct {
+		result1 error
+	}
+	updateKubernetesClusterCredentialReturnsOnCall map[int]struct {
+		result1 error
+	}
+	WithContextStub        func(context.Context) sql.Client
+	withContextMutex       sync.RWMutex
+	withContextArgsForCall []struct {
+		arg1 context.Context
+	}
+	withContextReturns struct {
+		result1 sql.Client
+	}
+	withContextReturnsOnCall map[int]struct {
+		result1 sql.Client
+	}
+	invocations      map[string][][]interface{}
+	invocationsMutex sync.RWMutex
 }
 
-func (fake *FakeClient) CreateKubernetesProviderReturns(result1 error) {
-	fake.createKubernetesProviderMutex.Lock()
-	defer fake.createKubernetesProviderMutex.Unlock()
-	fake.CreateKubernetesProviderStub = nil
-	fake.createKubernetesProviderReturns = struct {
-		result1 error
-	}{result1}
+func (fake *FakeClient) AcquireClusterLocks(arg1 string, arg2 []string, arg3 time.Time) (string, string, error) {
+	var arg2Copy []string
+	if arg2 != nil {
+		arg2Copy = make([]string, len(arg2))
+		copy(arg2Copy, arg2)
+	}
+	fake.acquireClusterLocksMutex.Lock()
+	ret, specificReturn := fake.acquireClusterLocksReturnsOnCall[len(fake.acquireClusterLocksArgsForCall)]
+	fake.acquireClusterLocksArgsForCall = append(fake.acquireClusterLocksArgsForCall, struct {
+		arg1 string
+		arg2 []string
+		arg3 time.Time
+	}{arg1, arg2Copy, arg3})
+	fake.recordInvocation("AcquireClusterLocks", []interface{}{arg1, arg2Copy, arg3})
+	fake.acquireClusterLocksMutex.Unlock()
+	if fake.AcquireClusterLocksStub != nil {
+		return fake.AcquireClusterLocksStub(arg1, arg2, arg3)
+	}
+	if specificReturn {
+		return ret.result1, ret.result2, ret.result3
+	}
+	fakeReturns := fake.acquireClusterLocksReturns
+	return fakeReturns.result1, fakeReturns.result2, fakeReturns.result3
 }
 
-func (fake *FakeClient) CreateKubernetesProviderReturnsOnCall(i int, result1 error) {
-	fake.createKubernetesProviderMutex.Lock()
-	defer fake.createKubernetesProviderMutex.Unlock()
-	fake.CreateKubernetesProviderStub = nil
-	if fake.createKubernetesProviderReturnsOnCall == nil {
-		fake.createKubernetesProviderReturnsOnCall = make(map[int]struct {
-			result1 error
+func (fake *FakeClient) AcquireClusterLocksCallCount() int {
+	fake.acquireClusterLocksMutex.RLock()
+	defer fake.acquireClusterLocksMutex.RUnlock()
+	return len(fake.acquireClusterLocksArgsForCall)
+}
+
+func (fake *FakeClient) AcquireClusterLocksCalls(stub func(string, []string, time.Time) (string, string, error)) {
+	fake.acquireClusterLocksMutex.Lock()
+	defer fake.acquireClusterLocksMutex.Unlock()
+	fake.AcquireClusterLocksStub = stub
+}
+
+func (fake *FakeClient) AcquireClusterLocksArgsForCall(i int) (string, []string, time.Time) {
+	fake.acquireClusterLocksMutex.RLock()
+	defer fake.acquireClusterLocksMutex.RUnlock()
+	argsForCall := fake.acquireClusterLocksArgsForCall[i]
+	return argsForCall.arg1, argsForCall.arg2, argsForCall.arg3
+}
+
+func (fake *FakeClient) AcquireClusterLocksReturns(result1 string, result2 string, result3 error) {
+	fake.acquireClusterLocksMutex.Lock()
+	defer fake.acquireClusterLocksMutex.Unlock()
+	fake.AcquireClusterLocksStub = nil
+	fake.acquireClusterLocksReturns = struct {
+		result1 string
+		result2 string
+		result3 error
+	}{result1, result2, result3}
+}
+
+func (fake *FakeClient) AcquireClusterLocksReturnsOnCall(i int, result1 string, result2 string, result3 error) {
+	fake.acquireClusterLocksMutex.Lock()
+	defer fake.acquireClusterLocksMutex.Unlock()
+	fake.AcquireClusterLocksStub = nil
+	if fake.acquireClusterLocksReturnsOnCall == nil {
+		fake.acquireClusterLocksReturnsOnCall = make(map[int]struct {
+			result1 string
+			result2 string
+			result3 error
 		})
 	}
-	fake.createKubernetesProviderReturnsOnCall[i] = struct {
-		result1 error
-	}{result1}
+	fake.acquireClusterLocksReturnsOnCall[i] = struct {
+		result1 string
+		result2 string
+		result3 error
+	}{result1, result2, result3}
 }
 
-func (fake *FakeClient) CreateKubernetesResource(arg1 kubernetes.Resource) error {
-	fake.createKubernetesResourceMutex.Lock()
-	ret, specificReturn := fake.createKubernetesResourceReturnsOnCall[len(fake.createKubernetesResourceArgsForCall)]
-	fake.createKubernetesResourceArgsForCall = append(fake.createKubernetesResourceArgsForCall, struct {
-		arg1 kubernetes.Resource
+func (fake *FakeClient) CreateApplication(arg1 clouddriver.Application) error {
+	fake.createApplicationMutex.Lock()
+	ret, specificReturn := fake.createApplicationReturnsOnCall[len(fake.createApplicationArgsForCall)]
+	fake.createApplicationArgsForCall = append(fake.createApplicationArgsForCall, struct {
+		arg1 clouddriver.Application
 	}{arg1})
-	fake.recordInvocation("CreateKubernetesResource", []interface{}{arg1})
-	fake.createKubernetesResourceMutex.Unlock()
-	if fake.CreateKubernetesResourceStub != nil {
-		return fake.CreateKubernetesResourceStub(arg1)
+	fake.recordInvocation("CreateApplication", []interface{}{arg1})
+	fake.createApplicationMutex.Unlock()
+	if fake.CreateApplicationStub != nil {
+		return fake.CreateApplicationStub(arg1)
 	}
 	if specificReturn {
 		return ret.result1
 	}
-	fakeReturns := fake.createKubernetesResourceReturns
+	fakeReturns := fake.createApplicationReturns
 	return fakeReturns.result1
 }
 
-func (fake *FakeClient) CreateKubernetesResourceCallCount() int {
-	fake.createKubernetesResourceMutex.RLock()
-	defer fake.createKubernetesResourceMutex.RUnlock()
-	return len(fake.createKubernetesResourceArgsForCall)
+func (fake *FakeClient) CreateApplicationCallCount() int {
+	fake.createApplicationMutex.RLock()
+	defer fake.createApplicationMutex.RUnlock()
+	return len(fake.createApplicationArgsForCall)
 }
 
-func (fake *FakeClient) CreateKubernetesResourceCalls(stub func(kubernetes.Resource) error) {
-	fake.createKubernetesResourceMutex.Lock()
-	defer fake.createKubernetesResourceMutex.Unlock()
-	fake.CreateKubernetesResourceStub = stub
+func (fake *FakeClient) CreateApplicationCalls(stub func(clouddriver.Application) error) {
+	fake.createApplicationMutex.Lock()
+	defer fake.createApplicationMutex.Unlock()
+	fake.CreateApplicationStub = stub
 }
 
-func (fake *FakeClient) CreateKubernetesResourceArgsForCall(i int) kubernetes.Resource {
-	fake.createKubernetesResourceMutex.RLock()
-	defer fake.createKubernetesResourceMutex.RUnlock()
-	argsForCall := fake.createKubernetesResourceArgsForCall[i]
+func (fake *FakeClient) CreateApplicationArgsForCall(i int) clouddriver.Application {
+	fake.createApplicationMutex.RLock()
+	defer fake.createApplicationMutex.RUnlock()
+	argsForCall := fake.createApplicationArgsForCall[i]
 	return argsForCall.arg1
 }
 
-func (fake *FakeClient) CreateKubernetesResourceReturns(result1 error) {
-	fake.createKubernetesResourceMutex.Lock()
-	defer fake.createKubernetesResourceMutex.Unlock()
-	fake.CreateKubernetesResourceStub = nil
-	fake.createKubernetesResourceReturns = struct {
+func (fake *FakeClient) CreateApplicationReturns(result1 error) {
+	fake.createApplicationMutex.Lock()
+	defer fake.createApplicationMutex.Unlock()
+	fake.CreateApplicationStub = nil
+	fake.createApplicationReturns = struct {
 		result1 error
 	}{result1}
 }
 
-func (fake *FakeClient) CreateKubernetesResourceReturnsOnCall(i int, result1 error) {
-	fake.createKubernetesResourceMutex.Lock()
-	defer fake.createKubernetesResourceMutex.Unlock()
-	fake.CreateKubernetesResourceStub = nil
-	if fake.createKubernetesResourceReturnsOnCall == nil {
-		fake.createKubernetesResourceReturnsOnCall = make(map[int]struct {
+func (fake *FakeClient) CreateApplicationReturnsOnCall(i int, result1 error) {
+	fake.createApplicationMutex.Lock()
+	defer fake.createApplicationMutex.Unlock()
+	fake.CreateApplicationStub = nil
+	if fake.createApplicationReturnsOnCall == nil {
+		fake.createApplicationReturnsOnCall = make(map[int]struct {
 			result1 error
 		})
 	}
-	fake.createKubernetesResourceReturnsOnCall[i] = struct {
+	fake.createApplicationReturnsOnCall[i] = struct {
 		result1 error
 	}{result1}
 }
 
-func (fake *FakeClient) CreateReadPermission(arg1 clouddriver.ReadPermission) error {
-	fake.createReadPermissionMutex.Lock()
-	ret, specificReturn := fake.createReadPermissionReturnsOnCall[len(fake.createReadPermissionArgsForCall)]
-	fake.createReadPermissionArgsForCall = append(fake.createReadPermissionArgsForCall, struct {
-		arg1 clouddriver.ReadPermission
+func (fake *FakeClient) CreateCacheSnapshot(arg1 snapshot.Snapshot) error {
+	fake.createCacheSnapshotMutex.Lock()
+	ret, specificReturn := fake.createCacheSnapshotReturnsOnCall[len(fake.createCacheSnapshotArgsForCall)]
+	fake.createCacheSnapshotArgsForCall = append(fake.createCacheSnapshotArgsForCall, struct {
+		arg1 snapshot.Snapshot
 	}{arg1})
-	fake.recordInvocation("CreateReadPermission", []interface{}{arg1})
-	fake.createReadPermissionMutex.Unlock()
-	if fake.CreateReadPermissionStub != nil {
-		return fake.CreateReadPermissionStub(arg1)
+	fake.recordInvocation("CreateCacheSnapshot", []interface{}{arg1})
+	fake.createCacheSnapshotMutex.Unlock()
+	if fake.CreateCacheSnapshotStub != nil {
+		return fake.CreateCacheSnapshotStub(arg1)
 	}
 	if specificReturn {
 		return ret.result1
 	}
-	fakeReturns := fake.createReadPermissionReturns
+	fakeReturns := fake.createCacheSnapshotReturns
 	return fakeReturns.result1
 }
 
-func (fake *FakeClient) CreateReadPermissionCallCount() int {
-	fake.createReadPermissionMutex.RLock()
-	defer fake.createReadPermissionMutex.RUnlock()
-	return len(fake.createReadPermissionArgsForCall)
+func (fake *FakeClient) CreateCacheSnapshotCallCount() int {
+	fake.createCacheSnapshotMutex.RLock()
+	defer fake.createCacheSnapshotMutex.RUnlock()
+	return len(fake.createCacheSnapshotArgsForCall)
 }
 
-func (fake *FakeClient) CreateReadPermissionCalls(stub func(clouddriver.ReadPermission) error) {
-	fake.createReadPermissionMutex.Lock()
-	defer fake.createReadPermissionMutex.Unlock()
-	fake.CreateReadPermissionStub = stub
+func (fake *FakeClient) CreateCacheSnapshotCalls(stub func(snapshot.Snapshot) error) {
+	fake.createCacheSnapshotMutex.Lock()
+	defer fake.createCacheSnapshotMutex.Unlock()
+	fake.CreateCacheSnapshotStub = stub
 }
 
-func (fake *FakeClient) CreateReadPermissionArgsForCall(i int) clouddriver.ReadPermission {
-	fake.createReadPermissionMutex.RLock()
-	defer fake.createReadPermissionMutex.RUnlock()
-	argsForCall := fake.createReadPermissionArgsForCall[i]
+func (fake *FakeClient) CreateCacheSnapshotArgsForCall(i int) snapshot.Snapshot {
+	fake.createCacheSnapshotMutex.RLock()
+	defer fake.createCacheSnapshotMutex.RUnlock()
+	argsForCall := fake.createCacheSnapshotArgsForCall[i]
 	return argsForCall.arg1
 }
 
-func (fake *FakeClient) CreateReadPermissionReturns(result1 error) {
-	fake.createReadPermissionMutex.Lock()
-	defer fake.createReadPermissionMutex.Unlock()
-	fake.CreateReadPermissionStub = nil
-	fake.createReadPermissionReturns = struct {
+func (fake *FakeClient) CreateCacheSnapshotReturns(result1 error) {
+	fake.createCacheSnapshotMutex.Lock()
+	defer fake.createCacheSnapshotMutex.Unlock()
+	fake.CreateCacheSnapshotStub = nil
+	fake.createCacheSnapshotReturns = struct {
 		result1 error
 	}{result1}
 }
 
-func (fake *FakeClient) CreateReadPermissionReturnsOnCall(i int, result1 error) {
-	fake.createReadPermissionMutex.Lock()
-	defer fake.createReadPermissionMutex.Unlock()
-	fake.CreateReadPermissionStub = nil
-	if fake.createReadPermissionReturnsOnCall == nil {
-		fake.createReadPermissionReturnsOnCall = make(map[int]struct {
+func (fake *FakeClient) CreateCacheSnapshotReturnsOnCall(i int, result1 error) {
+	fake.createCacheSnapshotMutex.Lock()
+	defer fake.createCacheSnapshotMutex.Unlock()
+	fake.CreateCacheSnapshotStub = nil
+	if fake.createCacheSnapshotReturnsOnCall == nil {
+		fake.createCacheSnapshotReturnsOnCall = make(map[int]struct {
 			result1 error
 		})
 	}
-	fake.createReadPermissionReturnsOnCall[i] = struct {
+	fake.createCacheSnapshotReturnsOnCall[i] = struct {
 		result1 error
 	}{result1}
 }
 
-func (fake *FakeClient) CreateWritePermission(arg1 clouddriver.WritePermission) error {
-	fake.createWritePermissionMutex.Lock()
-	ret, specificReturn := fake.createWritePermissionReturnsOnCall[len(fake.createWritePermissionArgsForCall)]
-	fake.createWritePermissionArgsForCall = append(fake.createWritePermissionArgsForCall, struct {
-		arg1 clouddriver.WritePermission
+func (fake *FakeClient) CreateDeploy(arg1 clouddriver.Deploy) error {
+	fake.createDeployMutex.Lock()
+	ret, specificReturn := fake.createDeployReturnsOnCall[len(fake.createDeployArgsForCall)]
+	fake.createDeployArgsForCall = append(fake.createDeployArgsForCall, struct {
+		arg1 clouddriver.Deploy
 	}{arg1})
-	fake.recordInvocation("CreateWritePermission", []interface{}{arg1})
-	fake.createWritePermissionMutex.Unlock()
-	if fake.CreateWritePermissionStub != nil {
-		return fake.CreateWritePermissionStub(arg1)
+	fake.recordInvocation("CreateDeploy", []interface{}{arg1})
+	fake.createDeployMutex.Unlock()
+	if fake.CreateDeployStub != nil {
+		return fake.CreateDeployStub(arg1)
 	}
 	if specificReturn {
 		return ret.result1
 	}
-	fakeReturns := fake.createWritePermissionReturns
+	fakeReturns := fake.createDeployReturns
 	return fakeReturns.result1
 }
 
-func (fake *FakeClient) CreateWritePermissionCallCount() int {
-	fake.createWritePermissionMutex.RLock()
-	defer fake.createWritePermissionMutex.RUnlock()
-	return len(fake.createWritePermissionArgsForCall)
+func (fake *FakeClient) CreateDeployCallCount() int {
+	fake.createDeployMutex.RLock()
+	defer fake.createDeployMutex.RUnlock()
+	return len(fake.createDeployArgsForCall)
 }
 
-func (fake *FakeClient) CreateWritePermissionCalls(stub func(clouddriver.WritePermission) error) {
-	fake.createWritePermissionMutex.Lock()
-	defer fake.createWritePermissionMutex.Unlock()
-	fake.CreateWritePermissionStub = stub
+func (fake *FakeClient) CreateDeployCalls(stub func(clouddriver.Deploy) error) {
+	fake.createDeployMutex.Lock()
+	defer fake.createDeployMutex.Unlock()
+	fake.CreateDeployStub = stub
 }
 
-func (fake *FakeClient) CreateWritePermissionArgsForCall(i int) clouddriver.WritePermission {
-	fake.createWritePermissionMutex.RLock()
-	defer fake.createWritePermissionMutex.RUnlock()
-	argsForCall := fake.createWritePermissionArgsForCall[i]
+func (fake *FakeClient) CreateDeployArgsForCall(i int) clouddriver.Deploy {
+	fake.createDeployMutex.RLock()
+	defer fake.createDeployMutex.RUnlock()
+	argsForCall := fake.createDeployArgsForCall[i]
 	return argsForCall.arg1
 }
 
-func (fake *FakeClient) CreateWritePermissionReturns(result1 error) {
-	fake.createWritePermissionMutex.Lock()
-	defer fake.createWritePermissionMutex.Unlock()
-	fake.CreateWritePermissionStub = nil
-	fake.createWritePermissionReturns = struct {
+func (fake *FakeClient) CreateDeployReturns(result1 error) {
+	fake.createDeployMutex.Lock()
+	defer fake.createDeployMutex.Unlock()
+	fake.CreateDeployStub = nil
+	fake.createDeployReturns = struct {
 		result1 error
 	}{result1}
 }
 
-func (fake *FakeClient) CreateWritePermissionReturnsOnCall(i int, result1 error) {
-	fake.createWritePermissionMutex.Lock()
-	defer fake.createWritePermissionMutex.Unlock()
-	fake.CreateWritePermissionStub = nil
-	if fake.createWritePermissionReturnsOnCall == nil {
-		fake.createWritePermissionReturnsOnCall = make(map[int]struct {
+func (fake *FakeClient) CreateDeployReturnsOnCall(i int, result1 error) {
+	fake.createDeployMutex.Lock()
+	defer fake.createDeployMutex.Unlock()
+	fake.CreateDeployStub = nil
+	if fake.createDeployReturnsOnCall == nil {
+		fake.createDeployReturnsOnCall = make(map[int]struct {
 			result1 error
 		})
 	}
-	fake.createWritePermissionReturnsOnCall[i] = struct {
+	fake.createDeployReturnsOnCall[i] = struct {
 		result1 error
 	}{result1}
 }
 
-func (fake *FakeClient) DeleteKubernetesProvider(arg1 string) error {
-	fake.deleteKubernetesProviderMutex.Lock()
-	ret, specificReturn := fake.deleteKubernetesProviderReturnsOnCall[len(fake.deleteKubernetesProviderArgsForCall)]
-	fake.deleteKubernetesProviderArgsForCall = append(fake.deleteKubernetesProviderArgsForCall, struct {
-		arg1 string
+func (fake *FakeClient) CreateExecutePermission(arg1 clouddriver.ExecutePermission) error {
+	fake.createExecutePermissionMutex.Lock()
+	ret, specificReturn := fake.createExecutePermissionReturnsOnCall[len(fake.createExecutePermissionArgsForCall)]
+	fake.createExecutePermissionArgsForCall = append(fake.createExecutePermissionArgsForCall, struct {
+		arg1 clouddriver.ExecutePermission
 	}{arg1})
-	fake.recordInvocation("DeleteKubernetesProvider", []interface{}{arg1})
-	fake.deleteKubernetesProviderMutex.Unlock()
-	if fake.DeleteKubernetesProviderStub != nil {
-		return fake.DeleteKubernetesProviderStub(arg1)
+	fake.recordInvocation("CreateExecutePermission", []interface{}{arg1})
+	fake.createExecutePermissionMutex.Unlock()
+	if fake.CreateExecutePermissionStub != nil {
+		return fake.CreateExecutePermissionStub(arg1)
 	}
 	if specificReturn {
 		return ret.result1
 	}
-	fakeReturns := fake.deleteKubernetesProviderReturns
+	fakeReturns := fake.createExecutePermissionReturns
 	return fakeReturns.result1
 }
 
-func (fake *FakeClient) DeleteKubernetesProviderCallCount() int {
-	fake.deleteKubernetesProviderMutex.RLock()
-	defer fake.deleteKubernetesProviderMutex.RUnlock()
-	return len(fake.deleteKubernetesProviderArgsForCall)
+func (fake *FakeClient) CreateExecutePermissionCallCount() int {
+	fake.createExecutePermissionMutex.RLock()
+	defer fake.createExecutePermissionMutex.RUnlock()
+	return len(fake.createExecutePermissionArgsForCall)
 }
 
-func (fake *FakeClient) DeleteKubernetesProviderCalls(stub func(string) error) {
-	fake.deleteKubernetesProviderMutex.Lock()
-	defer fake.deleteKubernetesProviderMutex.Unlock()
-	fake.DeleteKubernetesProviderStub = stub
+func (fake *FakeClient) CreateExecutePermissionCalls(stub func(clouddriver.ExecutePermission) error) {
+	fake.createExecutePermissionMutex.Lock()
+	defer fake.createExecutePermissionMutex.Unlock()
+	fake.CreateExecutePermissionStub = stub
 }
 
-func (fake *FakeClient) DeleteKubernetesProviderArgsForCall(i int) string {
-	fake.deleteKubernetesProviderMutex.RLock()
-	defer fake.deleteKubernetesProviderMutex.RUnlock()
-	argsForCall := fake.deleteKubernetesProviderArgsForCall[i]
+func (fake *FakeClient) CreateExecutePermissionArgsForCall(i int) clouddriver.ExecutePermission {
+	fake.createExecutePermissionMutex.RLock()
+	defer fake.createExecutePermissionMutex.RUnlock()
+	argsForCall := fake.createExecutePermissionArgsForCall[i]
 	return argsForCall.arg1
 }
 
-func (fake *FakeClient) DeleteKubernetesProviderReturns(result1 error) {
-	fake.deleteKubernetesProviderMutex.Lock()
-	defer fake.deleteKubernetesProviderMutex.Unlock()
-	fake.DeleteKubernetesProviderStub = nil
-	fake.deleteKubernetesProviderReturns = struct {
+func (fake *FakeClient) CreateExecutePermissionReturns(result1 error) {
+	fake.createExecutePermissionMutex.Lock()
+	defer fake.createExecutePermissionMutex.Unlock()
+	fake.CreateExecutePermissionStub = nil
+	fake.createExecutePermissionReturns = struct {
 		result1 error
 	}{result1}
 }
 
-func (fake *FakeClient) DeleteKubernetesProviderReturnsOnCall(i int, result1 error) {
-	fake.deleteKubernetesProviderMutex.Lock()
-	defer fake.deleteKubernetesProviderMutex.Unlock()
-	fake.DeleteKubernetesProviderStub = nil
-	if fake.deleteKubernetesProviderReturnsOnCall == nil {
-		fake.deleteKubernetesProviderReturnsOnCall = make(map[int]struct {
+func (fake *FakeClient) CreateExecutePermissionReturnsOnCall(i int, result1 error) {
+	fake.createExecutePermissionMutex.Lock()
+	defer fake.createExecutePermissionMutex.Unlock()
+	fake.CreateExecutePermissionStub = nil
+	if fake.createExecutePermissionReturnsOnCall == nil {
+		fake.createExecutePermissionReturnsOnCall = make(map[int]struct {
 			result1 error
 		})
 	}
-	fake.deleteKubernetesProviderReturnsOnCall[i] = struct {
+	fake.createExecutePermissionReturnsOnCall[i] = struct {
 		result1 error
 	}{result1}
 }
 
-func (fake *FakeClient) GetKubernetesProvider(arg1 string) (kubernetes.Provider, error) {
-	fake.getKubernetesProviderMutex.Lock()
-	ret, specificReturn := fake.getKubernetesProviderReturnsOnCall[len(fake.getKubernetesProviderArgsForCall)]
-	fake.getKubernetesProviderArgsForCall = append(fake.getKubernetesProviderArgsForCall, struct {
-		arg1 string
+func (fake *FakeClient) CreateFailedOperation(arg1 clouddriver.FailedOperation) error {
+	fake.createFailedOperationMutex.Lock()
+	ret, specificReturn := fake.createFailedOperationReturnsOnCall[len(fake.createFailedOperationArgsForCall)]
+	fake.createFailedOperationArgsForCall = append(fake.createFailedOperationArgsForCall, struct {
+		arg1 clouddriver.FailedOperation
 	}{arg1})
-	fake.recordInvocation("GetKubernetesProvider", []interface{}{arg1})
-	fake.getKubernetesProviderMutex.Unlock()
-	if fake.GetKubernetesProviderStub != nil {
-		return fake.GetKubernetesProviderStub(arg1)
+	fake.recordInvocation("CreateFailedOperation", []interface{}{arg1})
+	fake.createFailedOperationMutex.Unlock()
+	if fake.CreateFailedOperationStub != nil {
+		return fake.CreateFailedOperationStub(arg1)
 	}
 	if specificReturn {
-		return ret.result1, ret.result2
+		return ret.result1
 	}
-	fakeReturns := fake.getKubernetesProviderReturns
-	return fakeReturns.result1, fakeReturns.result2
+	fakeReturns := fake.createFailedOperationReturns
+	return fakeReturns.result1
 }
 
-func (fake *FakeClient) GetKubernetesProviderCallCount() int {
-	fake.getKubernetesProviderMutex.RLock()
-	defer fake.getKubernetesProviderMutex.RUnlock()
-	return len(fake.getKubernetesProviderArgsForCall)
+func (fake *FakeClient) CreateFailedOperationCallCount() int {
+	fake.createFailedOperationMutex.RLock()
+	defer fake.createFailedOperationMutex.RUnlock()
+	return len(fake.createFailedOperationArgsForCall)
 }
 
-func (fake *FakeClient) GetKubernetesProviderCalls(stub func(string) (kubernetes.Provider, error)) {
-	fake.getKubernetesProviderMutex.Lock()
-	defer fake.getKubernetesProviderMutex.Unlock()
-	fake.GetKubernetesProviderStub = stub
+func (fake *FakeClient) CreateFailedOperationCalls(stub func(clouddriver.FailedOperation) error) {
+	fake.createFailedOperationMutex.Lock()
+	defer fake.createFailedOperationMutex.Unlock()
+	fake.CreateFailedOperationStub = stub
 }
 
-func (fake *FakeClient) GetKubernetesProviderArgsForCall(i int) string {
-	fake.getKubernetesProviderMutex.RLock()
-	defer fake.getKubernetesProviderMutex.RUnlock()
-	argsForCall := fake.getKubernetesProviderArgsForCall[i]
+func (fake *FakeClient) CreateFailedOperationArgsForCall(i int) clouddriver.FailedOperation {
+	fake.createFailedOperationMutex.RLock()
+	defer fake.createFailedOperationMutex.RUnlock()
+	argsForCall := fake.createFailedOperationArgsForCall[i]
 	return argsForCall.arg1
 }
 
-func (fake *FakeClient) GetKubernetesProviderReturns(result1 kubernetes.Provider, result2 error) {
-	fake.getKubernetesProviderMutex.Lock()
-	defer fake.getKubernetesProviderMutex.Unlock()
-	fake.GetKubernetesProviderStub = nil
-	fake.getKubernetesProviderReturns = struct {
-		result1 kubernetes.Provider
-		result2 error
-	}{result1, result2}
+func (fake *FakeClient) CreateFailedOperationReturns(result1 error) {
+	fake.createFailedOperationMutex.Lock()
+	defer fake.createFailedOperationMutex.Unlock()
+	fake.CreateFailedOperationStub = nil
+	fake.createFailedOperationReturns = struct {
+		result1 error
+	}{result1}
 }
 
-func (fake *FakeClient) GetKubernetesProviderReturnsOnCall(i int, result1 kubernetes.Provider, result2 error) {
-	fake.getKubernetesProviderMutex.Lock()
-	defer fake.getKubernetesProviderMutex.Unlock()
+func (fake *FakeClient) CreateFailedOperationReturnsOnCall(i int, result1 error) {
+	fake.createFailedOperationMutex.Lock()
+	defer fake.createFailedOperationMutex.Unlock()
+	fake.CreateFailedOperationStub = nil
+	if fake.createFailedOperationReturnsOnCall == nil {
+		fake.createFailedOperationReturnsOnCall = make(map[int]struct {
+			result1 error
+		})
+	}
+	fake.createFailedOperationReturnsOnCall[i] = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *FakeClient) CreateKubernetesClusterCredential(arg1 kubernetes.ClusterCredential) error {
+	fake.createKubernetesClusterCredentialMutex.Lock()
+	ret, specificReturn := fake.createKubernetesClusterCredentialReturnsOnCall[len(fake.createKubernetesClusterCredentialArgsForCall)]
+	fake.createKubernetesClusterCredentialArgsForCall = append(fake.createKubernetesClusterCredentialArgsForCall, struct {
+		arg1 kubernetes.ClusterCredential
+	}{arg1})
+	fake.recordInvocation("CreateKubernetesClusterCredential", []interface{}{arg1})
+	fake.createKubernetesClusterCredentialMutex.Unlock()
+	if fake.CreateKubernetesClusterCredentialStub != nil {
+		return fake.CreateKubernetesClusterCredentialStub(arg1)
+	}
+	if specificReturn {
+		return ret.result1
+	}
+	fakeReturns := fake.createKubernetesClusterCredentialReturns
+	return fakeReturns.result1
+}
+
+func (fake *FakeClient) CreateKubernetesClusterCredentialCallCount() int {
+	fake.createKubernetesClusterCredentialMutex.RLock()
+	defer fake.createKubernetesClusterCredentialMutex.RUnlock()
+	return len(fake.createKubernetesClusterCredentialArgsForCall)
+}
+
+func (fake *FakeClient) CreateKubernetesClusterCredentialCalls(stub func(kubernetes.ClusterCredential) error) {
+	fake.createKubernetesClusterCredentialMutex.Lock()
+	defer fake.createKubernetesClusterCredentialMutex.Unlock()
+	fake.CreateKubernetesClusterCredentialStub = stub
+}
+
+func (fake *FakeClient) CreateKubernetesClusterCredentialArgsForCall(i int) kubernetes.ClusterCredential {
+	fake.createKubernetesClusterCredentialMutex.RLock()
+	defer fake.createKubernetesClusterCredentialMutex.RUnlock()
+	argsForCall := fake.createKubernetesClusterCredentialArgsForCall[i]
+	return argsForCall.arg1
+}
+
+func (fake *FakeClient) CreateKubernetesClusterCredentialReturns(result1 error) {
+	fake.createKubernetesClusterCredentialMutex.Lock()
+	defer fake.createKubernetesClusterCredentialMutex.Unlock()
+	fake.CreateKubernetesClusterCredentialStub = nil
+	fake.createKubernetesClusterCredentialReturns = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *FakeClient) CreateKubernetesClusterCredentialReturnsOnCall(i int, result1 error) {
+	fake.createKubernetesClusterCredentialMutex.Lock()
+	defer fake.createKubernetesClusterCredentialMutex.Unlock()
+	fake.CreateKubernetesClusterCredentialStub = nil
+	if fake.createKubernetesClusterCredentialReturnsOnCall == nil {
+		fake.createKubernetesClusterCredentialReturnsOnCall = make(map[int]struct {
+			result1 error
+		})
+	}
+	fake.createKubernetesClusterCredentialReturnsOnCall[i] = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *FakeClient) CreateKubernetesProvider(arg1 kubernetes.Provider) error {
+	fake.createKubernetesProviderMutex.Lock()
+	ret, specificReturn := fake.createKubernetesProviderReturnsOnCall[len(fake.createKubernetesProviderArgsForCall)]
+	fake.createKubernetesProviderArgsForCall = append(fake.createKubernetesProviderArgsForCall, struct {
+		arg1 kubernetes.Provider
+	}{arg1})
+	fake.recordInvocation("CreateKubernetesProvider", []interface{}{arg1})
+	fake.createKubernetesProviderMutex.Unlock()
+	if fake.CreateKubernetesProviderStub != nil {
+		return fake.CreateKubernetesProviderStub(arg1)
+	}
+	if specificReturn {
+		return ret.result1
+	}
+	fakeReturns := fake.createKubernetesProviderReturns
+	return fakeReturns.result1
+}
+
+func (fake *FakeClient) CreateKubernetesProviderCallCount() int {
+	fake.createKubernetesProviderMutex.RLock()
+	defer fake.createKubernetesProviderMutex.RUnlock()
+	return len(fake.createKubernetesProviderArgsForCall)
+}
+
+func (fake *FakeClient) CreateKubernetesProviderCalls(stub func(kubernetes.Provider) error) {
+	fake.createKubernetesProviderMutex.Lock()
+	defer fake.createKubernetesProviderMutex.Unlock()
+	fake.CreateKubernetesProviderStub = stub
+}
+
+func (fake *FakeClient) CreateKubernetesProviderArgsForCall(i int) kubernetes.Provider {
+	fake.createKubernetesProviderMutex.RLock()
+	defer fake.createKubernetesProviderMutex.RUnlock()
+	argsForCall := fake.createKubernetesProviderArgsForCall[i]
+	return argsForCall.arg1
+}
+
+func (fake *FakeClient) CreateKubernetesProviderReturns(result1 error) {
+	fake.createKubernetesProviderMutex.Lock()
+	defer fake.createKubernetesProviderMutex.Unlock()
+	fake.CreateKubernetesProviderStub = nil
+	fake.createKubernetesProviderReturns = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *FakeClient) CreateKubernetesProviderReturnsOnCall(i int, result1 error) {
+	fake.createKubernetesProviderMutex.Lock()
+	defer fake.createKubernetesProviderMutex.Unlock()
+	fake.CreateKubernetesProviderStub = nil
+	if fake.createKubernetesProviderReturnsOnCall == nil {
+		fake.createKubernetesProviderReturnsOnCall = make(map[int]struct {
+			result1 error
+		})
+	}
+	fake.createKubernetesProviderReturnsOnCall[i] = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *FakeClient) CreateKubernetesResource(arg1 kubernetes.Resource) error {
+	fake.createKubernetesResourceMutex.Lock()
+	ret, specificReturn := fake.createKubernetesResourceReturnsOnCall[len(fake.createKubernetesResourceArgsForCall)]
+	fake.createKubernetesResourceArgsForCall = append(fake.createKubernetesResourceArgsForCall, struct {
+		arg1 kubernetes.Resource
+	}{arg1})
+	fake.recordInvocation("CreateKubernetesResource", []interface{}{arg1})
+	fake.createKubernetesResourceMutex.Unlock()
+	if fake.CreateKubernetesResourceStub != nil {
+		return fake.CreateKubernetesResourceStub(arg1)
+	}
+	if specificReturn {
+		return ret.result1
+	}
+	fakeReturns := fake.createKubernetesResourceReturns
+	return fakeReturns.result1
+}
+
+func (fake *FakeClient) CreateKubernetesResourceCallCount() int {
+	fake.createKubernetesResourceMutex.RLock()
+	defer fake.createKubernetesResourceMutex.RUnlock()
+	return len(fake.createKubernetesResourceArgsForCall)
+}
+
+func (fake *FakeClient) CreateKubernetesResourceCalls(stub func(kubernetes.Resource) error) {
+	fake.createKubernetesResourceMutex.Lock()
+	defer fake.createKubernetesResourceMutex.Unlock()
+	fake.CreateKubernetesResourceStub = stub
+}
+
+func (fake *FakeClient) CreateKubernetesResourceArgsForCall(i int) kubernetes.Resource {
+	fake.createKubernetesResourceMutex.RLock()
+	defer fake.createKubernetesResourceMutex.RUnlock()
+	argsForCall := fake.createKubernetesResourceArgsForCall[i]
+	return argsForCall.arg1
+}
+
+func (fake *FakeClient) CreateKubernetesResourceReturns(result1 error) {
+	fake.createKubernetesResourceMutex.Lock()
+	defer fake.createKubernetesResourceMutex.Unlock()
+	fake.CreateKubernetesResourceStub = nil
+	fake.createKubernetesResourceReturns = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *FakeClient) CreateKubernetesResourceReturnsOnCall(i int, result1 error) {
+	fake.createKubernetesResourceMutex.Lock()
+	defer fake.createKubernetesResourceMutex.Unlock()
+	fake.CreateKubernetesResourceStub = nil
+	if fake.createKubernetesResourceReturnsOnCall == nil {
+		fake.createKubernetesResourceReturnsOnCall = make(map[int]struct {
+			result1 error
+		})
+	}
+	fake.createKubernetesResourceReturnsOnCall[i] = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *FakeClient) CreateMigrationReport(arg1 clouddriver.MigrationReport) error {
+	fake.createMigrationReportMutex.Lock()
+	ret, specificReturn := fake.createMigrationReportReturnsOnCall[len(fake.createMigrationReportArgsForCall)]
+	fake.createMigrationReportArgsForCall = append(fake.createMigrationReportArgsForCall, struct {
+		arg1 clouddriver.MigrationReport
+	}{arg1})
+	fake.recordInvocation("CreateMigrationReport", []interface{}{arg1})
+	fake.createMigrationReportMutex.Unlock()
+	if fake.CreateMigrationReportStub != nil {
+		return fake.CreateMigrationReportStub(arg1)
+	}
+	if specificReturn {
+		return ret.result1
+	}
+	fakeReturns := fake.createMigrationReportReturns
+	return fakeReturns.result1
+}
+
+func (fake *FakeClient) CreateMigrationReportCallCount() int {
+	fake.createMigrationReportMutex.RLock()
+	defer fake.createMigrationReportMutex.RUnlock()
+	return len(fake.createMigrationReportArgsForCall)
+}
+
+func (fake *FakeClient) CreateMigrationReportCalls(stub func(clouddriver.MigrationReport) error) {
+	fake.createMigrationReportMutex.Lock()
+	defer fake.createMigrationReportMutex.Unlock()
+	fake.CreateMigrationReportStub = stub
+}
+
+func (fake *FakeClient) CreateMigrationReportArgsForCall(i int) clouddriver.MigrationReport {
+	fake.createMigrationReportMutex.RLock()
+	defer fake.createMigrationReportMutex.RUnlock()
+	argsForCall := fake.createMigrationReportArgsForCall[i]
+	return argsForCall.arg1
+}
+
+func (fake *FakeClient) CreateMigrationReportReturns(result1 error) {
+	fake.createMigrationReportMutex.Lock()
+	defer fake.createMigrationReportMutex.Unlock()
+	fake.CreateMigrationReportStub = nil
+	fake.createMigrationReportReturns = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *FakeClient) CreateMigrationReportReturnsOnCall(i int, result1 error) {
+	fake.createMigrationReportMutex.Lock()
+	defer fake.createMigrationReportMutex.Unlock()
+	fake.CreateMigrationReportStub = nil
+	if fake.createMigrationReportReturnsOnCall == nil {
+		fake.createMigrationReportReturnsOnCall = make(map[int]struct {
+			result1 error
+		})
+	}
+	fake.createMigrationReportReturnsOnCall[i] = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *FakeClient) CreateNamespacePermission(arg1 clouddriver.NamespacePermission) error {
+	fake.createNamespacePermissionMutex.Lock()
+	ret, specificReturn := fake.createNamespacePermissionReturnsOnCall[len(fake.createNamespacePermissionArgsForCall)]
+	fake.createNamespacePermissionArgsForCall = append(fake.createNamespacePermissionArgsForCall, struct {
+		arg1 clouddriver.NamespacePermission
+	}{arg1})
+	fake.recordInvocation("CreateNamespacePermission", []interface{}{arg1})
+	fake.createNamespacePermissionMutex.Unlock()
+	if fake.CreateNamespacePermissionStub != nil {
+		return fake.CreateNamespacePermissionStub(arg1)
+	}
+	if specificReturn {
+		return ret.result1
+	}
+	fakeReturns := fake.createNamespacePermissionReturns
+	return fakeReturns.result1
+}
+
+func (fake *FakeClient) CreateNamespacePermissionCallCount() int {
+	fake.createNamespacePermissionMutex.RLock()
+	defer fake.createNamespacePermissionMutex.RUnlock()
+	return len(fake.createNamespacePermissionArgsForCall)
+}
+
+func (fake *FakeClient) CreateNamespacePermissionCalls(stub func(clouddriver.NamespacePermission) error) {
+	fake.createNamespacePermissionMutex.Lock()
+	defer fake.createNamespacePermissionMutex.Unlock()
+	fake.CreateNamespacePermissionStub = stub
+}
+
+func (fake *FakeClient) CreateNamespacePermissionArgsForCall(i int) clouddriver.NamespacePermission {
+	fake.createNamespacePermissionMutex.RLock()
+	defer fake.createNamespacePermissionMutex.RUnlock()
+	argsForCall := fake.createNamespacePermissionArgsForCall[i]
+	return argsForCall.arg1
+}
+
+func (fake *FakeClient) CreateNamespacePermissionReturns(result1 error) {
+	fake.createNamespacePermissionMutex.Lock()
+	defer fake.createNamespacePermissionMutex.Unlock()
+	fake.CreateNamespacePermissionStub = nil
+	fake.createNamespacePermissionReturns = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *FakeClient) CreateNamespacePermissionReturnsOnCall(i int, result1 error) {
+	fake.createNamespacePermissionMutex.Lock()
+	defer fake.createNamespacePermissionMutex.Unlock()
+	fake.CreateNamespacePermissionStub = nil
+	if fake.createNamespacePermissionReturnsOnCall == nil {
+		fake.createNamespacePermissionReturnsOnCall = make(map[int]struct {
+			result1 error
+		})
+	}
+	fake.createNamespacePermissionReturnsOnCall[i] = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *FakeClient) CreateReadPermission(arg1 clouddriver.ReadPermission) error {
+	fake.createReadPermissionMutex.Lock()
+	ret, specificReturn := fake.createReadPermissionReturnsOnCall[len(fake.createReadPermissionArgsForCall)]
+	fake.createReadPermissionArgsForCall = append(fake.createReadPermissionArgsForCall, struct {
+		arg1 clouddriver.ReadPermission
+	}{arg1})
+	fake.recordInvocation("CreateReadPermission", []interface{}{arg1})
+	fake.createReadPermissionMutex.Unlock()
+	if fake.CreateReadPermissionStub != nil {
+		return fake.CreateReadPermissionStub(arg1)
+	}
+	if specificReturn {
+		return ret.result1
+	}
+	fakeReturns := fake.createReadPermissionReturns
+	return fakeReturns.result1
+}
+
+func (fake *FakeClient) CreateReadPermissionCallCount() int {
+	fake.createReadPermissionMutex.RLock()
+	defer fake.createReadPermissionMutex.RUnlock()
+	return len(fake.createReadPermissionArgsForCall)
+}
+
+func (fake *FakeClient) CreateReadPermissionCalls(stub func(clouddriver.ReadPermission) error) {
+	fake.createReadPermissionMutex.Lock()
+	defer fake.createReadPermissionMutex.Unlock()
+	fake.CreateReadPermissionStub = stub
+}
+
+func (fake *FakeClient) CreateReadPermissionArgsForCall(i int) clouddriver.ReadPermission {
+	fake.createReadPermissionMutex.RLock()
+	defer fake.createReadPermissionMutex.RUnlock()
+	argsForCall := fake.createReadPermissionArgsForCall[i]
+	return argsForCall.arg1
+}
+
+func (fake *FakeClient) CreateReadPermissionReturns(result1 error) {
+	fake.createReadPermissionMutex.Lock()
+	defer fake.createReadPermissionMutex.Unlock()
+	fake.CreateReadPermissionStub = nil
+	fake.createReadPermissionReturns = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *FakeClient) CreateReadPermissionReturnsOnCall(i int, result1 error) {
+	fake.createReadPermissionMutex.Lock()
+	defer fake.createReadPermissionMutex.Unlock()
+	fake.CreateReadPermissionStub = nil
+	if fake.createReadPermissionReturnsOnCall == nil {
+		fake.createReadPermissionReturnsOnCall = make(map[int]struct {
+			result1 error
+		})
+	}
+	fake.createReadPermissionReturnsOnCall[i] = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *FakeClient) CreateTaskEvent(arg1 clouddriver.TaskEvent) error {
+	fake.createTaskEventMutex.Lock()
+	ret, specificReturn := fake.createTaskEventReturnsOnCall[len(fake.createTaskEventArgsForCall)]
+	fake.createTaskEventArgsForCall = append(fake.createTaskEventArgsForCall, struct {
+		arg1 clouddriver.TaskEvent
+	}{arg1})
+	fake.recordInvocation("CreateTaskEvent", []interface{}{arg1})
+	fake.createTaskEventMutex.Unlock()
+	if fake.CreateTaskEventStub != nil {
+		return fake.CreateTaskEventStub(arg1)
+	}
+	if specificReturn {
+		return ret.result1
+	}
+	fakeReturns := fake.createTaskEventReturns
+	return fakeReturns.result1
+}
+
+func (fake *FakeClient) CreateTaskEventCallCount() int {
+	fake.createTaskEventMutex.RLock()
+	defer fake.createTaskEventMutex.RUnlock()
+	return len(fake.createTaskEventArgsForCall)
+}
+
+func (fake *FakeClient) CreateTaskEventCalls(stub func(clouddriver.TaskEvent) error) {
+	fake.createTaskEventMutex.Lock()
+	defer fake.createTaskEventMutex.Unlock()
+	fake.CreateTaskEventStub = stub
+}
+
+func (fake *FakeClient) CreateTaskEventArgsForCall(i int) clouddriver.TaskEvent {
+	fake.createTaskEventMutex.RLock()
+	defer fake.createTaskEventMutex.RUnlock()
+	argsForCall := fake.createTaskEventArgsForCall[i]
+	return argsForCall.arg1
+}
+
+func (fake *FakeClient) CreateTaskEventReturns(result1 error) {
+	fake.createTaskEventMutex.Lock()
+	defer fake.createTaskEventMutex.Unlock()
+	fake.CreateTaskEventStub = nil
+	fake.createTaskEventReturns = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *FakeClient) CreateTaskEventReturnsOnCall(i int, result1 error) {
+	fake.createTaskEventMutex.Lock()
+	defer fake.createTaskEventMutex.Unlock()
+	fake.CreateTaskEventStub = nil
+	if fake.createTaskEventReturnsOnCall == nil {
+		fake.createTaskEventReturnsOnCall = make(map[int]struct {
+			result1 error
+		})
+	}
+	fake.createTaskEventReturnsOnCall[i] = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *FakeClient) CreateWritePermission(arg1 clouddriver.WritePermission) error {
+	fake.createWritePermissionMutex.Lock()
+	ret, specificReturn := fake.createWritePermissionReturnsOnCall[len(fake.createWritePermissionArgsForCall)]
+	fake.createWritePermissionArgsForCall = append(fake.createWritePermissionArgsForCall, struct {
+		arg1 clouddriver.WritePermission
+	}{arg1})
+	fake.recordInvocation("CreateWritePermission", []interface{}{arg1})
+	fake.createWritePermissionMutex.Unlock()
+	if fake.CreateWritePermissionStub != nil {
+		return fake.CreateWritePermissionStub(arg1)
+	}
+	if specificReturn {
+		return ret.result1
+	}
+	fakeReturns := fake.createWritePermissionReturns
+	return fakeReturns.result1
+}
+
+func (fake *FakeClient) CreateWritePermissionCallCount() int {
+	fake.createWritePermissionMutex.RLock()
+	defer fake.createWritePermissionMutex.RUnlock()
+	return len(fake.createWritePermissionArgsForCall)
+}
+
+func (fake *FakeClient) CreateWritePermissionCalls(stub func(clouddriver.WritePermission) error) {
+	fake.createWritePermissionMutex.Lock()
+	defer fake.createWritePermissionMutex.Unlock()
+	fake.CreateWritePermissionStub = stub
+}
+
+func (fake *FakeClient) CreateWritePermissionArgsForCall(i int) clouddriver.WritePermission {
+	fake.createWritePermissionMutex.RLock()
+	defer fake.createWritePermissionMutex.RUnlock()
+	argsForCall := fake.createWritePermissionArgsForCall[i]
+	return argsForCall.arg1
+}
+
+func (fake *FakeClient) CreateWritePermissionReturns(result1 error) {
+	fake.createWritePermissionMutex.Lock()
+	defer fake.createWritePermissionMutex.Unlock()
+	fake.CreateWritePermissionStub = nil
+	fake.createWritePermissionReturns = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *FakeClient) CreateWritePermissionReturnsOnCall(i int, result1 error) {
+	fake.createWritePermissionMutex.Lock()
+	defer fake.createWritePermissionMutex.Unlock()
+	fake.CreateWritePermissionStub = nil
+	if fake.createWritePermissionReturnsOnCall == nil {
+		fake.createWritePermissionReturnsOnCall = make(map[int]struct {
+			result1 error
+		})
+	}
+	fake.createWritePermissionReturnsOnCall[i] = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *FakeClient) DeleteApplication(arg1 string) error {
+	fake.deleteApplicationMutex.Lock()
+	ret, specificReturn := fake.deleteApplicationReturnsOnCall[len(fake.deleteApplicationArgsForCall)]
+	fake.deleteApplicationArgsForCall = append(fake.deleteApplicationArgsForCall, struct {
+		arg1 string
+	}{arg1})
+	fake.recordInvocation("DeleteApplication", []interface{}{arg1})
+	fake.deleteApplicationMutex.Unlock()
+	if fake.DeleteApplicationStub != nil {
+		return fake.DeleteApplicationStub(arg1)
+	}
+	if specificReturn {
+		return ret.result1
+	}
+	fakeReturns := fake.deleteApplicationReturns
+	return fakeReturns.result1
+}
+
+func (fake *FakeClient) DeleteApplicationCallCount() int {
+	fake.deleteApplicationMutex.RLock()
+	defer fake.deleteApplicationMutex.RUnlock()
+	return len(fake.deleteApplicationArgsForCall)
+}
+
+func (fake *FakeClient) DeleteApplicationCalls(stub func(string) error) {
+	fake.deleteApplicationMutex.Lock()
+	defer fake.deleteApplicationMutex.Unlock()
+	fake.DeleteApplicationStub = stub
+}
+
+func (fake *FakeClient) DeleteApplicationArgsForCall(i int) string {
+	fake.deleteApplicationMutex.RLock()
+	defer fake.deleteApplicationMutex.RUnlock()
+	argsForCall := fake.deleteApplicationArgsForCall[i]
+	return argsForCall.arg1
+}
+
+func (fake *FakeClient) DeleteApplicationReturns(result1 error) {
+	fake.deleteApplicationMutex.Lock()
+	defer fake.deleteApplicationMutex.Unlock()
+	fake.DeleteApplicationStub = nil
+	fake.deleteApplicationReturns = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *FakeClient) DeleteApplicationReturnsOnCall(i int, result1 error) {
+	fake.deleteApplicationMutex.Lock()
+	defer fake.deleteApplicationMutex.Unlock()
+	fake.DeleteApplicationStub = nil
+	if fake.deleteApplicationReturnsOnCall == nil {
+		fake.deleteApplicationReturnsOnCall = make(map[int]struct {
+			result1 error
+		})
+	}
+	fake.deleteApplicationReturnsOnCall[i] = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *FakeClient) DeleteCacheSnapshotsCreatedBefore(arg1 time.Time) (int64, error) {
+	fake.deleteCacheSnapshotsCreatedBeforeMutex.Lock()
+	ret, specificReturn := fake.deleteCacheSnapshotsCreatedBeforeReturnsOnCall[len(fake.deleteCacheSnapshotsCreatedBeforeArgsForCall)]
+	fake.deleteCacheSnapshotsCreatedBeforeArgsForCall = append(fake.deleteCacheSnapshotsCreatedBeforeArgsForCall, struct {
+		arg1 time.Time
+	}{arg1})
+	fake.recordInvocation("DeleteCacheSnapshotsCreatedBefore", []interface{}{arg1})
+	fake.deleteCacheSnapshotsCreatedBeforeMutex.Unlock()
+	if fake.DeleteCacheSnapshotsCreatedBeforeStub != nil {
+		return fake.DeleteCacheSnapshotsCreatedBeforeStub(arg1)
+	}
+	if specificReturn {
+		return ret.result1, ret.result2
+	}
+	fakeReturns := fake.deleteCacheSnapshotsCreatedBeforeReturns
+	return fakeReturns.result1, fakeReturns.result2
+}
+
+func (fake *FakeClient) DeleteCacheSnapshotsCreatedBeforeCallCount() int {
+	fake.deleteCacheSnapshotsCreatedBeforeMutex.RLock()
+	defer fake.deleteCacheSnapshotsCreatedBeforeMutex.RUnlock()
+	return len(fake.deleteCacheSnapshotsCreatedBeforeArgsForCall)
+}
+
+func (fake *FakeClient) DeleteCacheSnapshotsCreatedBeforeCalls(stub func(time.Time) (int64, error)) {
+	fake.deleteCacheSnapshotsCreatedBeforeMutex.Lock()
+	defer fake.deleteCacheSnapshotsCreatedBeforeMutex.Unlock()
+	fake.DeleteCacheSnapshotsCreatedBeforeStub = stub
+}
+
+func (fake *FakeClient) DeleteCacheSnapshotsCreatedBeforeArgsForCall(i int) time.Time {
+	fake.deleteCacheSnapshotsCreatedBeforeMutex.RLock()
+	defer fake.deleteCacheSnapshotsCreatedBeforeMutex.RUnlock()
+	argsForCall := fake.deleteCacheSnapshotsCreatedBeforeArgsForCall[i]
+	return argsForCall.arg1
+}
+
+func (fake *FakeClient) DeleteCacheSnapshotsCreatedBeforeReturns(result1 int64, result2 error) {
+	fake.deleteCacheSnapshotsCreatedBeforeMutex.Lock()
+	defer fake.deleteCacheSnapshotsCreatedBeforeMutex.Unlock()
+	fake.DeleteCacheSnapshotsCreatedBeforeStub = nil
+	fake.deleteCacheSnapshotsCreatedBeforeReturns = struct {
+		result1 int64
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeClient) DeleteCacheSnapshotsCreatedBeforeReturnsOnCall(i int, result1 int64, result2 error) {
+	fake.deleteCacheSnapshotsCreatedBeforeMutex.Lock()
+	defer fake.deleteCacheSnapshotsCreatedBeforeMutex.Unlock()
+	fake.DeleteCacheSnapshotsCreatedBeforeStub = nil
+	if fake.deleteCacheSnapshotsCreatedBeforeReturnsOnCall == nil {
+		fake.deleteCacheSnapshotsCreatedBeforeReturnsOnCall = make(map[int]struct {
+			result1 int64
+			result2 error
+		})
+	}
+	fake.deleteCacheSnapshotsCreatedBeforeReturnsOnCall[i] = struct {
+		result1 int64
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeClient) DeleteCompressedBlobsUnusedSince(arg1 time.Time) (int64, error) {
+	fake.deleteCompressedBlobsUnusedSinceMutex.Lock()
+	ret, specificReturn := fake.deleteCompressedBlobsUnusedSinceReturnsOnCall[len(fake.deleteCompressedBlobsUnusedSinceArgsForCall)]
+	fake.deleteCompressedBlobsUnusedSinceArgsForCall = append(fake.deleteCompressedBlobsUnusedSinceArgsForCall, struct {
+		arg1 time.Time
+	}{arg1})
+	fake.recordInvocation("DeleteCompressedBlobsUnusedSince", []interface{}{arg1})
+	fake.deleteCompressedBlobsUnusedSinceMutex.Unlock()
+	if fake.DeleteCompressedBlobsUnusedSinceStub != nil {
+		return fake.DeleteCompressedBlobsUnusedSinceStub(arg1)
+	}
+	if specificReturn {
+		return ret.result1, ret.result2
+	}
+	fakeReturns := fake.deleteCompressedBlobsUnusedSinceReturns
+	return fakeReturns.result1, fakeReturns.result2
+}
+
+func (fake *FakeClient) DeleteCompressedBlobsUnusedSinceCallCount() int {
+	fake.deleteCompressedBlobsUnusedSinceMutex.RLock()
+	defer fake.deleteCompressedBlobsUnusedSinceMutex.RUnlock()
+	return len(fake.deleteCompressedBlobsUnusedSinceArgsForCall)
+}
+
+func (fake *FakeClient) DeleteCompressedBlobsUnusedSinceCalls(stub func(time.Time) (int64, error)) {
+	fake.deleteCompressedBlobsUnusedSinceMutex.Lock()
+	defer fake.deleteCompressedBlobsUnusedSinceMutex.Unlock()
+	fake.DeleteCompressedBlobsUnusedSinceStub = stub
+}
+
+func (fake *FakeClient) DeleteCompressedBlobsUnusedSinceArgsForCall(i int) time.Time {
+	fake.deleteCompressedBlobsUnusedSinceMutex.RLock()
+	defer fake.deleteCompressedBlobsUnusedSinceMutex.RUnlock()
+	argsForCall := fake.deleteCompressedBlobsUnusedSinceArgsForCall[i]
+	return argsForCall.arg1
+}
+
+func (fake *FakeClient) DeleteCompressedBlobsUnusedSinceReturns(result1 int64, result2 error) {
+	fake.deleteCompressedBlobsUnusedSinceMutex.Lock()
+	defer fake.deleteCompressedBlobsUnusedSinceMutex.Unlock()
+	fake.DeleteCompressedBlobsUnusedSinceStub = nil
+	fake.deleteCompressedBlobsUnusedSinceReturns = struct {
+		result1 int64
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeClient) DeleteCompressedBlobsUnusedSinceReturnsOnCall(i int, result1 int64, result2 error) {
+	fake.deleteCompressedBlobsUnusedSinceMutex.Lock()
+	defer fake.deleteCompressedBlobsUnusedSinceMutex.Unlock()
+	fake.DeleteCompressedBlobsUnusedSinceStub = nil
+	if fake.deleteCompressedBlobsUnusedSinceReturnsOnCall == nil {
+		fake.deleteCompressedBlobsUnusedSinceReturnsOnCall = make(map[int]struct {
+			result1 int64
+			result2 error
+		})
+	}
+	fake.deleteCompressedBlobsUnusedSinceReturnsOnCall[i] = struct {
+		result1 int64
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeClient) DeleteDeploysCreatedBefore(arg1 time.Time) (int64, error) {
+	fake.deleteDeploysCreatedBeforeMutex.Lock()
+	ret, specificReturn := fake.deleteDeploysCreatedBeforeReturnsOnCall[len(fake.deleteDeploysCreatedBeforeArgsForCall)]
+	fake.deleteDeploysCreatedBeforeArgsForCall = append(fake.deleteDeploysCreatedBeforeArgsForCall, struct {
+		arg1 time.Time
+	}{arg1})
+	fake.recordInvocation("DeleteDeploysCreatedBefore", []interface{}{arg1})
+	fake.deleteDeploysCreatedBeforeMutex.Unlock()
+	if fake.DeleteDeploysCreatedBeforeStub != nil {
+		return fake.DeleteDeploysCreatedBeforeStub(arg1)
+	}
+	if specificReturn {
+		return ret.result1, ret.result2
+	}
+	fakeReturns := fake.deleteDeploysCreatedBeforeReturns
+	return fakeReturns.result1, fakeReturns.result2
+}
+
+func (fake *FakeClient) DeleteDeploysCreatedBeforeCallCount() int {
+	fake.deleteDeploysCreatedBeforeMutex.RLock()
+	defer fake.deleteDeploysCreatedBeforeMutex.RUnlock()
+	return len(fake.deleteDeploysCreatedBeforeArgsForCall)
+}
+
+func (fake *FakeClient) DeleteDeploysCreatedBeforeCalls(stub func(time.Time) (int64, error)) {
+	fake.deleteDeploysCreatedBeforeMutex.Lock()
+	defer fake.deleteDeploysCreatedBeforeMutex.Unlock()
+	fake.DeleteDeploysCreatedBeforeStub = stub
+}
+
+func (fake *FakeClient) DeleteDeploysCreatedBeforeArgsForCall(i int) time.Time {
+	fake.deleteDeploysCreatedBeforeMutex.RLock()
+	defer fake.deleteDeploysCreatedBeforeMutex.RUnlock()
+	argsForCall := fake.deleteDeploysCreatedBeforeArgsForCall[i]
+	return argsForCall.arg1
+}
+
+func (fake *FakeClient) DeleteDeploysCreatedBeforeReturns(result1 int64, result2 error) {
+	fake.deleteDeploysCreatedBeforeMutex.Lock()
+	defer fake.deleteDeploysCreatedBeforeMutex.Unlock()
+	fake.DeleteDeploysCreatedBeforeStub = nil
+	fake.deleteDeploysCreatedBeforeReturns = struct {
+		result1 int64
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeClient) DeleteDeploysCreatedBeforeReturnsOnCall(i int, result1 int64, result2 error) {
+	fake.deleteDeploysCreatedBeforeMutex.Lock()
+	defer fake.deleteDeploysCreatedBeforeMutex.Unlock()
+	fake.DeleteDeploysCreatedBeforeStub = nil
+	if fake.deleteDeploysCreatedBeforeReturnsOnCall == nil {
+		fake.deleteDeploysCreatedBeforeReturnsOnCall = make(map[int]struct {
+			result1 int64
+			result2 error
+		})
+	}
+	fake.deleteDeploysCreatedBeforeReturnsOnCall[i] = struct {
+		result1 int64
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeClient) DeleteFailedOperationsCreatedBefore(arg1 time.Time) (int64, error) {
+	fake.deleteFailedOperationsCreatedBeforeMutex.Lock()
+	ret, specificReturn := fake.deleteFailedOperationsCreatedBeforeReturnsOnCall[len(fake.deleteFailedOperationsCreatedBeforeArgsForCall)]
+	fake.deleteFailedOperationsCreatedBeforeArgsForCall = append(fake.deleteFailedOperationsCreatedBeforeArgsForCall, struct {
+		arg1 time.Time
+	}{arg1})
+	fake.recordInvocation("DeleteFailedOperationsCreatedBefore", []interface{}{arg1})
+	fake.deleteFailedOperationsCreatedBeforeMutex.Unlock()
+	if fake.DeleteFailedOperationsCreatedBeforeStub != nil {
+		return fake.DeleteFailedOperationsCreatedBeforeStub(arg1)
+	}
+	if specificReturn {
+		return ret.result1, ret.result2
+	}
+	fakeReturns := fake.deleteFailedOperationsCreatedBeforeReturns
+	return fakeReturns.result1, fakeReturns.result2
+}
+
+func (fake *FakeClient) DeleteFailedOperationsCreatedBeforeCallCount() int {
+	fake.deleteFailedOperationsCreatedBeforeMutex.RLock()
+	defer fake.deleteFailedOperationsCreatedBeforeMutex.RUnlock()
+	return len(fake.deleteFailedOperationsCreatedBeforeArgsForCall)
+}
+
+func (fake *FakeClient) DeleteFailedOperationsCreatedBeforeCalls(stub func(time.Time) (int64, error)) {
+	fake.deleteFailedOperationsCreatedBeforeMutex.Lock()
+	defer fake.deleteFailedOperationsCreatedBeforeMutex.Unlock()
+	fake.DeleteFailedOperationsCreatedBeforeStub = stub
+}
+
+func (fake *FakeClient) DeleteFailedOperationsCreatedBeforeArgsForCall(i int) time.Time {
+	fake.deleteFailedOperationsCreatedBeforeMutex.RLock()
+	defer fake.deleteFailedOperationsCreatedBeforeMutex.RUnlock()
+	argsForCall := fake.deleteFailedOperationsCreatedBeforeArgsForCall[i]
+	return argsForCall.arg1
+}
+
+func (fake *FakeClient) DeleteFailedOperationsCreatedBeforeReturns(result1 int64, result2 error) {
+	fake.deleteFailedOperationsCreatedBeforeMutex.Lock()
+	defer fake.deleteFailedOperationsCreatedBeforeMutex.Unlock()
+	fake.DeleteFailedOperationsCreatedBeforeStub = nil
+	fake.deleteFailedOperationsCreatedBeforeReturns = struct {
+		result1 int64
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeClient) DeleteFailedOperationsCreatedBeforeReturnsOnCall(i int, result1 int64, result2 error) {
+	fake.deleteFailedOperationsCreatedBeforeMutex.Lock()
+	defer fake.deleteFailedOperationsCreatedBeforeMutex.Unlock()
+	fake.DeleteFailedOperationsCreatedBeforeStub = nil
+	if fake.deleteFailedOperationsCreatedBeforeReturnsOnCall == nil {
+		fake.deleteFailedOperationsCreatedBeforeReturnsOnCall = make(map[int]struct {
+			result1 int64
+			result2 error
+		})
+	}
+	fake.deleteFailedOperationsCreatedBeforeReturnsOnCall[i] = struct {
+		result1 int64
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeClient) DeleteKindMapping(arg1 string, arg2 string) error {
+	fake.deleteKindMappingMutex.Lock()
+	ret, specificReturn := fake.deleteKindMappingReturnsOnCall[len(fake.deleteKindMappingArgsForCall)]
+	fake.deleteKindMappingArgsForCall = append(fake.deleteKindMappingArgsForCall, struct {
+		arg1 string
+		arg2 string
+	}{arg1, arg2})
+	fake.recordInvocation("DeleteKindMapping", []interface{}{arg1, arg2})
+	fake.deleteKindMappingMutex.Unlock()
+	if fake.DeleteKindMappingStub != nil {
+		return fake.DeleteKindMappingStub(arg1, arg2)
+	}
+	if specificReturn {
+		return ret.result1
+	}
+	fakeReturns := fake.deleteKindMappingReturns
+	return fakeReturns.result1
+}
+
+func (fake *FakeClient) DeleteKindMappingCallCount() int {
+	fake.deleteKindMappingMutex.RLock()
+	defer fake.deleteKindMappingMutex.RUnlock()
+	return len(fake.deleteKindMappingArgsForCall)
+}
+
+func (fake *FakeClient) DeleteKindMappingCalls(stub func(string, string) error) {
+	fake.deleteKindMappingMutex.Lock()
+	defer fake.deleteKindMappingMutex.Unlock()
+	fake.DeleteKindMappingStub = stub
+}
+
+func (fake *FakeClient) DeleteKindMappingArgsForCall(i int) (string, string) {
+	fake.deleteKindMappingMutex.RLock()
+	defer fake.deleteKindMappingMutex.RUnlock()
+	argsForCall := fake.deleteKindMappingArgsForCall[i]
+	return argsForCall.arg1, argsForCall.arg2
+}
+
+func (fake *FakeClient) DeleteKindMappingReturns(result1 error) {
+	fake.deleteKindMappingMutex.Lock()
+	defer fake.deleteKindMappingMutex.Unlock()
+	fake.DeleteKindMappingStub = nil
+	fake.deleteKindMappingReturns = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *FakeClient) DeleteKindMappingReturnsOnCall(i int, result1 error) {
+	fake.deleteKindMappingMutex.Lock()
+	defer fake.deleteKindMappingMutex.Unlock()
+	fake.DeleteKindMappingStub = nil
+	if fake.deleteKindMappingReturnsOnCall == nil {
+		fake.deleteKindMappingReturnsOnCall = make(map[int]struct {
+			result1 error
+		})
+	}
+	fake.deleteKindMappingReturnsOnCall[i] = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *FakeClient) DeleteKubernetesClusterCredential(arg1 string) error {
+	fake.deleteKubernetesClusterCredentialMutex.Lock()
+	ret, specificReturn := fake.deleteKubernetesClusterCredentialReturnsOnCall[len(fake.deleteKubernetesClusterCredentialArgsForCall)]
+	fake.deleteKubernetesClusterCredentialArgsForCall = append(fake.deleteKubernetesClusterCredentialArgsForCall, struct {
+		arg1 string
+	}{arg1})
+	fake.recordInvocation("DeleteKubernetesClusterCredential", []interface{}{arg1})
+	fake.deleteKubernetesClusterCredentialMutex.Unlock()
+	if fake.DeleteKubernetesClusterCredentialStub != nil {
+		return fake.DeleteKubernetesClusterCredentialStub(arg1)
+	}
+	if specificReturn {
+		return ret.result1
+	}
+	fakeReturns := fake.deleteKubernetesClusterCredentialReturns
+	return fakeReturns.result1
+}
+
+func (fake *FakeClient) DeleteKubernetesClusterCredentialCallCount() int {
+	fake.deleteKubernetesClusterCredentialMutex.RLock()
+	defer fake.deleteKubernetesClusterCredentialMutex.RUnlock()
+	return len(fake.deleteKubernetesClusterCredentialArgsForCall)
+}
+
+func (fake *FakeClient) DeleteKubernetesClusterCredentialCalls(stub func(string) error) {
+	fake.deleteKubernetesClusterCredentialMutex.Lock()
+	defer fake.deleteKubernetesClusterCredentialMutex.Unlock()
+	fake.DeleteKubernetesClusterCredentialStub = stub
+}
+
+func (fake *FakeClient) DeleteKubernetesClusterCredentialArgsForCall(i int) string {
+	fake.deleteKubernetesClusterCredentialMutex.RLock()
+	defer fake.deleteKubernetesClusterCredentialMutex.RUnlock()
+	argsForCall := fake.deleteKubernetesClusterCredentialArgsForCall[i]
+	return argsForCall.arg1
+}
+
+func (fake *FakeClient) DeleteKubernetesClusterCredentialReturns(result1 error) {
+	fake.deleteKubernetesClusterCredentialMutex.Lock()
+	defer fake.deleteKubernetesClusterCredentialMutex.Unlock()
+	fake.DeleteKubernetesClusterCredentialStub = nil
+	fake.deleteKubernetesClusterCredentialReturns = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *FakeClient) DeleteKubernetesClusterCredentialReturnsOnCall(i int, result1 error) {
+	fake.deleteKubernetesClusterCredentialMutex.Lock()
+	defer fake.deleteKubernetesClusterCredentialMutex.Unlock()
+	fake.DeleteKubernetesClusterCredentialStub = nil
+	if fake.deleteKubernetesClusterCredentialReturnsOnCall == nil {
+		fake.deleteKubernetesClusterCredentialReturnsOnCall = make(map[int]struct {
+			result1 error
+		})
+	}
+	fake.deleteKubernetesClusterCredentialReturnsOnCall[i] = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *FakeClient) DeleteKubernetesProvider(arg1 string) error {
+	fake.deleteKubernetesProviderMutex.Lock()
+	ret, specificReturn := fake.deleteKubernetesProviderReturnsOnCall[len(fake.deleteKubernetesProviderArgsForCall)]
+	fake.deleteKubernetesProviderArgsForCall = append(fake.deleteKubernetesProviderArgsForCall, struct {
+		arg1 string
+	}{arg1})
+	fake.recordInvocation("DeleteKubernetesProvider", []interface{}{arg1})
+	fake.deleteKubernetesProviderMutex.Unlock()
+	if fake.DeleteKubernetesProviderStub != nil {
+		return fake.DeleteKubernetesProviderStub(arg1)
+	}
+	if specificReturn {
+		return ret.result1
+	}
+	fakeReturns := fake.deleteKubernetesProviderReturns
+	return fakeReturns.result1
+}
+
+func (fake *FakeClient) DeleteKubernetesProviderCallCount() int {
+	fake.deleteKubernetesProviderMutex.RLock()
+	defer fake.deleteKubernetesProviderMutex.RUnlock()
+	return len(fake.deleteKubernetesProviderArgsForCall)
+}
+
+func (fake *FakeClient) DeleteKubernetesProviderCalls(stub func(string) error) {
+	fake.deleteKubernetesProviderMutex.Lock()
+	defer fake.deleteKubernetesProviderMutex.Unlock()
+	fake.DeleteKubernetesProviderStub = stub
+}
+
+func (fake *FakeClient) DeleteKubernetesProviderArgsForCall(i int) string {
+	fake.deleteKubernetesProviderMutex.RLock()
+	defer fake.deleteKubernetesProviderMutex.RUnlock()
+	argsForCall := fake.deleteKubernetesProviderArgsForCall[i]
+	return argsForCall.arg1
+}
+
+func (fake *FakeClient) DeleteKubernetesProviderReturns(result1 error) {
+	fake.deleteKubernetesProviderMutex.Lock()
+	defer fake.deleteKubernetesProviderMutex.Unlock()
+	fake.DeleteKubernetesProviderStub = nil
+	fake.deleteKubernetesProviderReturns = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *FakeClient) DeleteKubernetesProviderReturnsOnCall(i int, result1 error) {
+	fake.deleteKubernetesProviderMutex.Lock()
+	defer fake.deleteKubernetesProviderMutex.Unlock()
+	fake.DeleteKubernetesProviderStub = nil
+	if fake.deleteKubernetesProviderReturnsOnCall == nil {
+		fake.deleteKubernetesProviderReturnsOnCall = make(map[int]struct {
+			result1 error
+		})
+	}
+	fake.deleteKubernetesProviderReturnsOnCall[i] = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *FakeClient) DeleteKubernetesResourcesCreatedBefore(arg1 time.Time) (int64, error) {
+	fake.deleteKubernetesResourcesCreatedBeforeMutex.Lock()
+	ret, specificReturn := fake.deleteKubernetesResourcesCreatedBeforeReturnsOnCall[len(fake.deleteKubernetesResourcesCreatedBeforeArgsForCall)]
+	fake.deleteKubernetesResourcesCreatedBeforeArgsForCall = append(fake.deleteKubernetesResourcesCreatedBeforeArgsForCall, struct {
+		arg1 time.Time
+	}{arg1})
+	fake.recordInvocation("DeleteKubernetesResourcesCreatedBefore", []interface{}{arg1})
+	fake.deleteKubernetesResourcesCreatedBeforeMutex.Unlock()
+	if fake.DeleteKubernetesResourcesCreatedBeforeStub != nil {
+		return fake.DeleteKubernetesResourcesCreatedBeforeStub(arg1)
+	}
+	if specificReturn {
+		return ret.result1, ret.result2
+	}
+	fakeReturns := fake.deleteKubernetesResourcesCreatedBeforeReturns
+	return fakeReturns.result1, fakeReturns.result2
+}
+
+func (fake *FakeClient) DeleteKubernetesResourcesCreatedBeforeCallCount() int {
+	fake.deleteKubernetesResourcesCreatedBeforeMutex.RLock()
+	defer fake.deleteKubernetesResourcesCreatedBeforeMutex.RUnlock()
+	return len(fake.deleteKubernetesResourcesCreatedBeforeArgsForCall)
+}
+
+func (fake *FakeClient) DeleteKubernetesResourcesCreatedBeforeCalls(stub func(time.Time) (int64, error)) {
+	fake.deleteKubernetesResourcesCreatedBeforeMutex.Lock()
+	defer fake.deleteKubernetesResourcesCreatedBeforeMutex.Unlock()
+	fake.DeleteKubernetesResourcesCreatedBeforeStub = stub
+}
+
+func (fake *FakeClient) DeleteKubernetesResourcesCreatedBeforeArgsForCall(i int) time.Time {
+	fake.deleteKubernetesResourcesCreatedBeforeMutex.RLock()
+	defer fake.deleteKubernetesResourcesCreatedBeforeMutex.RUnlock()
+	argsForCall := fake.deleteKubernetesResourcesCreatedBeforeArgsForCall[i]
+	return argsForCall.arg1
+}
+
+func (fake *FakeClient) DeleteKubernetesResourcesCreatedBeforeReturns(result1 int64, result2 error) {
+	fake.deleteKubernetesResourcesCreatedBeforeMutex.Lock()
+	defer fake.deleteKubernetesResourcesCreatedBeforeMutex.Unlock()
+	fake.DeleteKubernetesResourcesCreatedBeforeStub = nil
+	fake.deleteKubernetesResourcesCreatedBeforeReturns = struct {
+		result1 int64
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeClient) DeleteKubernetesResourcesCreatedBeforeReturnsOnCall(i int, result1 int64, result2 error) {
+	fake.deleteKubernetesResourcesCreatedBeforeMutex.Lock()
+	defer fake.deleteKubernetesResourcesCreatedBeforeMutex.Unlock()
+	fake.DeleteKubernetesResourcesCreatedBeforeStub = nil
+	if fake.deleteKubernetesResourcesCreatedBeforeReturnsOnCall == nil {
+		fake.deleteKubernetesResourcesCreatedBeforeReturnsOnCall = make(map[int]struct {
+			result1 int64
+			result2 error
+		})
+	}
+	fake.deleteKubernetesResourcesCreatedBeforeReturnsOnCall[i] = struct {
+		result1 int64
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeClient) DeleteMigrationReportsCreatedBefore(arg1 time.Time) (int64, error) {
+	fake.deleteMigrationReportsCreatedBeforeMutex.Lock()
+	ret, specificReturn := fake.deleteMigrationReportsCreatedBeforeReturnsOnCall[len(fake.deleteMigrationReportsCreatedBeforeArgsForCall)]
+	fake.deleteMigrationReportsCreatedBeforeArgsForCall = append(fake.deleteMigrationReportsCreatedBeforeArgsForCall, struct {
+		arg1 time.Time
+	}{arg1})
+	fake.recordInvocation("DeleteMigrationReportsCreatedBefore", []interface{}{arg1})
+	fake.deleteMigrationReportsCreatedBeforeMutex.Unlock()
+	if fake.DeleteMigrationReportsCreatedBeforeStub != nil {
+		return fake.DeleteMigrationReportsCreatedBeforeStub(arg1)
+	}
+	if specificReturn {
+		return ret.result1, ret.result2
+	}
+	fakeReturns := fake.deleteMigrationReportsCreatedBeforeReturns
+	return fakeReturns.result1, fakeReturns.result2
+}
+
+func (fake *FakeClient) DeleteMigrationReportsCreatedBeforeCallCount() int {
+	fake.deleteMigrationReportsCreatedBeforeMutex.RLock()
+	defer fake.deleteMigrationReportsCreatedBeforeMutex.RUnlock()
+	return len(fake.deleteMigrationReportsCreatedBeforeArgsForCall)
+}
+
+func (fake *FakeClient) DeleteMigrationReportsCreatedBeforeCalls(stub func(time.Time) (int64, error)) {
+	fake.deleteMigrationReportsCreatedBeforeMutex.Lock()
+	defer fake.deleteMigrationReportsCreatedBeforeMutex.Unlock()
+	fake.DeleteMigrationReportsCreatedBeforeStub = stub
+}
+
+func (fake *FakeClient) DeleteMigrationReportsCreatedBeforeArgsForCall(i int) time.Time {
+	fake.deleteMigrationReportsCreatedBeforeMutex.RLock()
+	defer fake.deleteMigrationReportsCreatedBeforeMutex.RUnlock()
+	argsForCall := fake.deleteMigrationReportsCreatedBeforeArgsForCall[i]
+	return argsForCall.arg1
+}
+
+func (fake *FakeClient) DeleteMigrationReportsCreatedBeforeReturns(result1 int64, result2 error) {
+	fake.deleteMigrationReportsCreatedBeforeMutex.Lock()
+	defer fake.deleteMigrationReportsCreatedBeforeMutex.Unlock()
+	fake.DeleteMigrationReportsCreatedBeforeStub = nil
+	fake.deleteMigrationReportsCreatedBeforeReturns = struct {
+		result1 int64
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeClient) DeleteMigrationReportsCreatedBeforeReturnsOnCall(i int, result1 int64, result2 error) {
+	fake.deleteMigrationReportsCreatedBeforeMutex.Lock()
+	defer fake.deleteMigrationReportsCreatedBeforeMutex.Unlock()
+	fake.DeleteMigrationReportsCreatedBeforeStub = nil
+	if fake.deleteMigrationReportsCreatedBeforeReturnsOnCall == nil {
+		fake.deleteMigrationReportsCreatedBeforeReturnsOnCall = make(map[int]struct {
+			result1 int64
+			result2 error
+		})
+	}
+	fake.deleteMigrationReportsCreatedBeforeReturnsOnCall[i] = struct {
+		result1 int64
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeClient) DeleteOrphanedKubernetesResources() (int64, error) {
+	fake.deleteOrphanedKubernetesResourcesMutex.Lock()
+	ret, specificReturn := fake.deleteOrphanedKubernetesResourcesReturnsOnCall[len(fake.deleteOrphanedKubernetesResourcesArgsForCall)]
+	fake.deleteOrphanedKubernetesResourcesArgsForCall = append(fake.deleteOrphanedKubernetesResourcesArgsForCall, struct {
+	}{})
+	fake.recordInvocation("DeleteOrphanedKubernetesResources", []interface{}{})
+	fake.deleteOrphanedKubernetesResourcesMutex.Unlock()
+	if fake.DeleteOrphanedKubernetesResourcesStub != nil {
+		return fake.DeleteOrphanedKubernetesResourcesStub()
+	}
+	if specificReturn {
+		return ret.result1, ret.result2
+	}
+	fakeReturns := fake.deleteOrphanedKubernetesResourcesReturns
+	return fakeReturns.result1, fakeReturns.result2
+}
+
+func (fake *FakeClient) DeleteOrphanedKubernetesResourcesCallCount() int {
+	fake.deleteOrphanedKubernetesResourcesMutex.RLock()
+	defer fake.deleteOrphanedKubernetesResourcesMutex.RUnlock()
+	return len(fake.deleteOrphanedKubernetesResourcesArgsForCall)
+}
+
+func (fake *FakeClient) DeleteOrphanedKubernetesResourcesCalls(stub func() (int64, error)) {
+	fake.deleteOrphanedKubernetesResourcesMutex.Lock()
+	defer fake.deleteOrphanedKubernetesResourcesMutex.Unlock()
+	fake.DeleteOrphanedKubernetesResourcesStub = stub
+}
+
+func (fake *FakeClient) DeleteOrphanedKubernetesResourcesReturns(result1 int64, result2 error) {
+	fake.deleteOrphanedKubernetesResourcesMutex.Lock()
+	defer fake.deleteOrphanedKubernetesResourcesMutex.Unlock()
+	fake.DeleteOrphanedKubernetesResourcesStub = nil
+	fake.deleteOrphanedKubernetesResourcesReturns = struct {
+		result1 int64
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeClient) DeleteOrphanedKubernetesResourcesReturnsOnCall(i int, result1 int64, result2 error) {
+	fake.deleteOrphanedKubernetesResourcesMutex.Lock()
+	defer fake.deleteOrphanedKubernetesResourcesMutex.Unlock()
+	fake.DeleteOrphanedKubernetesResourcesStub = nil
+	if fake.deleteOrphanedKubernetesResourcesReturnsOnCall == nil {
+		fake.deleteOrphanedKubernetesResourcesReturnsOnCall = make(map[int]struct {
+			result1 int64
+			result2 error
+		})
+	}
+	fake.deleteOrphanedKubernetesResourcesReturnsOnCall[i] = struct {
+		result1 int64
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeClient) DeleteOrphanedPermissions() (int64, error) {
+	fake.deleteOrphanedPermissionsMutex.Lock()
+	ret, specificReturn := fake.deleteOrphanedPermissionsReturnsOnCall[len(fake.deleteOrphanedPermissionsArgsForCall)]
+	fake.deleteOrphanedPermissionsArgsForCall = append(fake.deleteOrphanedPermissionsArgsForCall, struct {
+	}{})
+	fake.recordInvocation("DeleteOrphanedPermissions", []interface{}{})
+	fake.deleteOrphanedPermissionsMutex.Unlock()
+	if fake.DeleteOrphanedPermissionsStub != nil {
+		return fake.DeleteOrphanedPermissionsStub()
+	}
+	if specificReturn {
+		return ret.result1, ret.result2
+	}
+	fakeReturns := fake.deleteOrphanedPermissionsReturns
+	return fakeReturns.result1, fakeReturns.result2
+}
+
+func (fake *FakeClient) DeleteOrphanedPermissionsCallCount() int {
+	fake.deleteOrphanedPermissionsMutex.RLock()
+	defer fake.deleteOrphanedPermissionsMutex.RUnlock()
+	return len(fake.deleteOrphanedPermissionsArgsForCall)
+}
+
+func (fake *FakeClient) DeleteOrphanedPermissionsCalls(stub func() (int64, error)) {
+	fake.deleteOrphanedPermissionsMutex.Lock()
+	defer fake.deleteOrphanedPermissionsMutex.Unlock()
+	fake.DeleteOrphanedPermissionsStub = stub
+}
+
+func (fake *FakeClient) DeleteOrphanedPermissionsReturns(result1 int64, result2 error) {
+	fake.deleteOrphanedPermissionsMutex.Lock()
+	defer fake.deleteOrphanedPermissionsMutex.Unlock()
+	fake.DeleteOrphanedPermissionsStub = nil
+	fake.deleteOrphanedPermissionsReturns = struct {
+		result1 int64
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeClient) DeleteOrphanedPermissionsReturnsOnCall(i int, result1 int64, result2 error) {
+	fake.deleteOrphanedPermissionsMutex.Lock()
+	defer fake.deleteOrphanedPermissionsMutex.Unlock()
+	fake.DeleteOrphanedPermissionsStub = nil
+	if fake.deleteOrphanedPermissionsReturnsOnCall == nil {
+		fake.deleteOrphanedPermissionsReturnsOnCall = make(map[int]struct {
+			result1 int64
+			result2 error
+		})
+	}
+	fake.deleteOrphanedPermissionsReturnsOnCall[i] = struct {
+		result1 int64
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeClient) DeleteTaskEventsCreatedBefore(arg1 time.Time) (int64, error) {
+	fake.deleteTaskEventsCreatedBeforeMutex.Lock()
+	ret, specificReturn := fake.deleteTaskEventsCreatedBeforeReturnsOnCall[len(fake.deleteTaskEventsCreatedBeforeArgsForCall)]
+	fake.deleteTaskEventsCreatedBeforeArgsForCall = append(fake.deleteTaskEventsCreatedBeforeArgsForCall, struct {
+		arg1 time.Time
+	}{arg1})
+	fake.recordInvocation("DeleteTaskEventsCreatedBefore", []interface{}{arg1})
+	fake.deleteTaskEventsCreatedBeforeMutex.Unlock()
+	if fake.DeleteTaskEventsCreatedBeforeStub != nil {
+		return fake.DeleteTaskEventsCreatedBeforeStub(arg1)
+	}
+	if specificReturn {
+		return ret.result1, ret.result2
+	}
+	fakeReturns := fake.deleteTaskEventsCreatedBeforeReturns
+	return fakeReturns.result1, fakeReturns.result2
+}
+
+func (fake *FakeClient) DeleteTaskEventsCreatedBeforeCallCount() int {
+	fake.deleteTaskEventsCreatedBeforeMutex.RLock()
+	defer fake.deleteTaskEventsCreatedBeforeMutex.RUnlock()
+	return len(fake.deleteTaskEventsCreatedBeforeArgsForCall)
+}
+
+func (fake *FakeClient) DeleteTaskEventsCreatedBeforeCalls(stub func(time.Time) (int64, error)) {
+	fake.deleteTaskEventsCreatedBeforeMutex.Lock()
+	defer fake.deleteTaskEventsCreatedBeforeMutex.Unlock()
+	fake.DeleteTaskEventsCreatedBeforeStub = stub
+}
+
+func (fake *FakeClient) DeleteTaskEventsCreatedBeforeArgsForCall(i int) time.Time {
+	fake.deleteTaskEventsCreatedBeforeMutex.RLock()
+	defer fake.deleteTaskEventsCreatedBeforeMutex.RUnlock()
+	argsForCall := fake.deleteTaskEventsCreatedBeforeArgsForCall[i]
+	return argsForCall.arg1
+}
+
+func (fake *FakeClient) DeleteTaskEventsCreatedBeforeReturns(result1 int64, result2 error) {
+	fake.deleteTaskEventsCreatedBeforeMutex.Lock()
+	defer fake.deleteTaskEventsCreatedBeforeMutex.Unlock()
+	fake.DeleteTaskEventsCreatedBeforeStub = nil
+	fake.deleteTaskEventsCreatedBeforeReturns = struct {
+		result1 int64
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeClient) DeleteTaskEventsCreatedBeforeReturnsOnCall(i int, result1 int64, result2 error) {
+	fake.deleteTaskEventsCreatedBeforeMutex.Lock()
+	defer fake.deleteTaskEventsCreatedBeforeMutex.Unlock()
+	fake.DeleteTaskEventsCreatedBeforeStub = nil
+	if fake.deleteTaskEventsCreatedBeforeReturnsOnCall == nil {
+		fake.deleteTaskEventsCreatedBeforeReturnsOnCall = make(map[int]struct {
+			result1 int64
+			result2 error
+		})
+	}
+	fake.deleteTaskEventsCreatedBeforeReturnsOnCall[i] = struct {
+		result1 int64
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeClient) GetCacheSnapshot(arg1 string, arg2 time.Time) (snapshot.Snapshot, error) {
+	fake.getCacheSnapshotMutex.Lock()
+	ret, specificReturn := fake.getCacheSnapshotReturnsOnCall[len(fake.getCacheSnapshotArgsForCall)]
+	fake.getCacheSnapshotArgsForCall = append(fake.getCacheSnapshotArgsForCall, struct {
+		arg1 string
+		arg2 time.Time
+	}{arg1, arg2})
+	fake.recordInvocation("GetCacheSnapshot", []interface{}{arg1, arg2})
+	fake.getCacheSnapshotMutex.Unlock()
+	if fake.GetCacheSnapshotStub != nil {
+		return fake.GetCacheSnapshotStub(arg1, arg2)
+	}
+	if specificReturn {
+		return ret.result1, ret.result2
+	}
+	fakeReturns := fake.getCacheSnapshotReturns
+	return fakeReturns.result1, fakeReturns.result2
+}
+
+func (fake *FakeClient) GetCacheSnapshotCallCount() int {
+	fake.getCacheSnapshotMutex.RLock()
+	defer fake.getCacheSnapshotMutex.RUnlock()
+	return len(fake.getCacheSnapshotArgsForCall)
+}
+
+func (fake *FakeClient) GetCacheSnapshotCalls(stub func(string, time.Time) (snapshot.Snapshot, error)) {
+	fake.getCacheSnapshotMutex.Lock()
+	defer fake.getCacheSnapshotMutex.Unlock()
+	fake.GetCacheSnapshotStub = stub
+}
+
+func (fake *FakeClient) GetCacheSnapshotArgsForCall(i int) (string, time.Time) {
+	fake.getCacheSnapshotMutex.RLock()
+	defer fake.getCacheSnapshotMutex.RUnlock()
+	argsForCall := fake.getCacheSnapshotArgsForCall[i]
+	return argsForCall.arg1, argsForCall.arg2
+}
+
+func (fake *FakeClient) GetCacheSnapshotReturns(result1 snapshot.Snapshot, result2 error) {
+	fake.getCacheSnapshotMutex.Lock()
+	defer fake.getCacheSnapshotMutex.Unlock()
+	fake.GetCacheSnapshotStub = nil
+	fake.getCacheSnapshotReturns = struct {
+		result1 snapshot.Snapshot
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeClient) GetCacheSnapshotReturnsOnCall(i int, result1 snapshot.Snapshot, result2 error) {
+	fake.getCacheSnapshotMutex.Lock()
+	defer fake.getCacheSnapshotMutex.Unlock()
+	fake.GetCacheSnapshotStub = nil
+	if fake.getCacheSnapshotReturnsOnCall == nil {
+		fake.getCacheSnapshotReturnsOnCall = make(map[int]struct {
+			result1 snapshot.Snapshot
+			result2 error
+		})
+	}
+	fake.getCacheSnapshotReturnsOnCall[i] = struct {
+		result1 snapshot.Snapshot
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeClient) GetFailedOperation(arg1 string) (clouddriver.FailedOperation, error) {
+	fake.getFailedOperationMutex.Lock()
+	ret, specificReturn := fake.getFailedOperationReturnsOnCall[len(fake.getFailedOperationArgsForCall)]
+	fake.getFailedOperationArgsForCall = append(fake.getFailedOperationArgsForCall, struct {
+		arg1 string
+	}{arg1})
+	fake.recordInvocation("GetFailedOperation", []interface{}{arg1})
+	fake.getFailedOperationMutex.Unlock()
+	if fake.GetFailedOperationStub != nil {
+		return fake.GetFailedOperationStub(arg1)
+	}
+	if specificReturn {
+		return ret.result1, ret.result2
+	}
+	fakeReturns := fake.getFailedOperationReturns
+	return fakeReturns.result1, fakeReturns.result2
+}
+
+func (fake *FakeClient) GetFailedOperationCallCount() int {
+	fake.getFailedOperationMutex.RLock()
+	defer fake.getFailedOperationMutex.RUnlock()
+	return len(fake.getFailedOperationArgsForCall)
+}
+
+func (fake *FakeClient) GetFailedOperationCalls(stub func(string) (clouddriver.FailedOperation, error)) {
+	fake.getFailedOperationMutex.Lock()
+	defer fake.getFailedOperationMutex.Unlock()
+	fake.GetFailedOperationStub = stub
+}
+
+func (fake *FakeClient) GetFailedOperationArgsForCall(i int) string {
+	fake.getFailedOperationMutex.RLock()
+	defer fake.getFailedOperationMutex.RUnlock()
+	argsForCall := fake.getFailedOperationArgsForCall[i]
+	return argsForCall.arg1
+}
+
+func (fake *FakeClient) GetFailedOperationReturns(result1 clouddriver.FailedOperation, result2 error) {
+	fake.getFailedOperationMutex.Lock()
+	defer fake.getFailedOperationMutex.Unlock()
+	fake.GetFailedOperationStub = nil
+	fake.getFailedOperationReturns = struct {
+		result1 clouddriver.FailedOperation
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeClient) GetFailedOperationReturnsOnCall(i int, result1 clouddriver.FailedOperation, result2 error) {
+	fake.getFailedOperationMutex.Lock()
+	defer fake.getFailedOperationMutex.Unlock()
+	fake.GetFailedOperationStub = nil
+	if fake.getFailedOperationReturnsOnCall == nil {
+		fake.getFailedOperationReturnsOnCall = make(map[int]struct {
+			result1 clouddriver.FailedOperation
+			result2 error
+		})
+	}
+	fake.getFailedOperationReturnsOnCall[i] = struct {
+		result1 clouddriver.FailedOperation
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeClient) GetKubernetesClusterCredential(arg1 string) (kubernetes.ClusterCredential, error) {
+	fake.getKubernetesClusterCredentialMutex.Lock()
+	ret, specificReturn := fake.getKubernetesClusterCredentialReturnsOnCall[len(fake.getKubernetesClusterCredentialArgsForCall)]
+	fake.getKubernetesClusterCredentialArgsForCall = append(fake.getKubernetesClusterCredentialArgsForCall, struct {
+		arg1 string
+	}{arg1})
+	fake.recordInvocation("GetKubernetesClusterCredential", []interface{}{arg1})
+	fake.getKubernetesClusterCredentialMutex.Unlock()
+	if fake.GetKubernetesClusterCredentialStub != nil {
+		return fake.GetKubernetesClusterCredentialStub(arg1)
+	}
+	if specificReturn {
+		return ret.result1, ret.result2
+	}
+	fakeReturns := fake.getKubernetesClusterCredentialReturns
+	return fakeReturns.result1, fakeReturns.result2
+}
+
+func (fake *FakeClient) GetKubernetesClusterCredentialCallCount() int {
+	fake.getKubernetesClusterCredentialMutex.RLock()
+	defer fake.getKubernetesClusterCredentialMutex.RUnlock()
+	return len(fake.getKubernetesClusterCredentialArgsForCall)
+}
+
+func (fake *FakeClient) GetKubernetesClusterCredentialCalls(stub func(string) (kubernetes.ClusterCredential, error)) {
+	fake.getKubernetesClusterCredentialMutex.Lock()
+	defer fake.getKubernetesClusterCredentialMutex.Unlock()
+	fake.GetKubernetesClusterCredentialStub = stub
+}
+
+func (fake *FakeClient) GetKubernetesClusterCredentialArgsForCall(i int) string {
+	fake.getKubernetesClusterCredentialMutex.RLock()
+	defer fake.getKubernetesClusterCredentialMutex.RUnlock()
+	argsForCall := fake.getKubernetesClusterCredentialArgsForCall[i]
+	return argsForCall.arg1
+}
+
+func (fake *FakeClient) GetKubernetesClusterCredentialReturns(result1 kubernetes.ClusterCredential, result2 error) {
+	fake.getKubernetesClusterCredentialMutex.Lock()
+	defer fake.getKubernetesClusterCredentialMutex.Unlock()
+	fake.GetKubernetesClusterCredentialStub = nil
+	fake.getKubernetesClusterCredentialReturns = struct {
+		result1 kubernetes.ClusterCredential
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeClient) GetKubernetesClusterCredentialReturnsOnCall(i int, result1 kubernetes.ClusterCredential, result2 error) {
+	fake.getKubernetesClusterCredentialMutex.Lock()
+	defer fake.getKubernetesClusterCredentialMutex.Unlock()
+	fake.GetKubernetesClusterCredentialStub = nil
+	if fake.getKubernetesClusterCredentialReturnsOnCall == nil {
+		fake.getKubernetesClusterCredentialReturnsOnCall = make(map[int]struct {
+			result1 kubernetes.ClusterCredential
+			result2 error
+		})
+	}
+	fake.getKubernetesClusterCredentialReturnsOnCall[i] = struct {
+		result1 kubernetes.ClusterCredential
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeClient) GetKubernetesProvider(arg1 string) (kubernetes.Provider, error) {
+	fake.getKubernetesProviderMutex.Lock()
+	ret, specificReturn := fake.getKubernetesProviderReturnsOnCall[len(fake.getKubernetesProviderArgsForCall)]
+	fake.getKubernetesProviderArgsForCall = append(fake.getKubernetesProviderArgsForCall, struct {
+		arg1 string
+	}{arg1})
+	fake.recordInvocation("GetKubernetesProvider", []interface{}{arg1})
+	fake.getKubernetesProviderMutex.Unlock()
+	if fake.GetKubernetesProviderStub != nil {
+		return fake.GetKubernetesProviderStub(arg1)
+	}
+	if specificReturn {
+		return ret.result1, ret.result2
+	}
+	fakeReturns := fake.getKubernetesProviderReturns
+	return fakeReturns.result1, fakeReturns.result2
+}
+
+func (fake *FakeClient) GetKubernetesProviderCallCount() int {
+	fake.getKubernetesProviderMutex.RLock()
+	defer fake.getKubernetesProviderMutex.RUnlock()
+	return len(fake.getKubernetesProviderArgsForCall)
+}
+
+func (fake *FakeClient) GetKubernetesProviderCalls(stub func(string) (kubernetes.Provider, error)) {
+	fake.getKubernetesProviderMutex.Lock()
+	defer fake.getKubernetesProviderMutex.Unlock()
+	fake.GetKubernetesProviderStub = stub
+}
+
+func (fake *FakeClient) GetKubernetesProviderArgsForCall(i int) string {
+	fake.getKubernetesProviderMutex.RLock()
+	defer fake.getKubernetesProviderMutex.RUnlock()
+	argsForCall := fake.getKubernetesProviderArgsForCall[i]
+	return argsForCall.arg1
+}
+
+func (fake *FakeClient) GetKubernetesProviderReturns(result1 kubernetes.Provider, result2 error) {
+	fake.getKubernetesProviderMutex.Lock()
+	defer fake.getKubernetesProviderMutex.Unlock()
+	fake.GetKubernetesProviderStub = nil
+	fake.getKubernetesProviderReturns = struct {
+		result1 kubernetes.Provider
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeClient) GetKubernetesProviderReturnsOnCall(i int, result1 kubernetes.Provider, result2 error) {
+	fake.getKubernetesProviderMutex.Lock()
+	defer fake.getKubernetesProviderMutex.Unlock()
 	fake.GetKubernetesProviderStub = nil
 	if fake.getKubernetesProviderReturnsOnCall == nil {
 		fake.getKubernetesProviderReturnsOnCall = make(map[int]struct {
@@ -556,598 +2684,2095 @@ func (fake *FakeClient) GetKubernetesProviderReturnsOnCall(i int, result1 kubern
 			result2 error
 		})
 	}
-	fake.getKubernetesProviderReturnsOnCall[i] = struct {
-		result1 kubernetes.Provider
+	fake.getKubernetesProviderReturnsOnCall[i] = struct {
+		result1 kubernetes.Provider
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeClient) GetKubernetesProviderVersion() (int64, error) {
+	fake.getKubernetesProviderVersionMutex.Lock()
+	ret, specificReturn := fake.getKubernetesProviderVersionReturnsOnCall[len(fake.getKubernetesProviderVersionArgsForCall)]
+	fake.getKubernetesProviderVersionArgsForCall = append(fake.getKubernetesProviderVersionArgsForCall, struct {
+	}{})
+	fake.recordInvocation("GetKubernetesProviderVersion", []interface{}{})
+	fake.getKubernetesProviderVersionMutex.Unlock()
+	if fake.GetKubernetesProviderVersionStub != nil {
+		return fake.GetKubernetesProviderVersionStub()
+	}
+	if specificReturn {
+		return ret.result1, ret.result2
+	}
+	fakeReturns := fake.getKubernetesProviderVersionReturns
+	return fakeReturns.result1, fakeReturns.result2
+}
+
+func (fake *FakeClient) GetKubernetesProviderVersionCallCount() int {
+	fake.getKubernetesProviderVersionMutex.RLock()
+	defer fake.getKubernetesProviderVersionMutex.RUnlock()
+	return len(fake.getKubernetesProviderVersionArgsForCall)
+}
+
+func (fake *FakeClient) GetKubernetesProviderVersionCalls(stub func() (int64, error)) {
+	fake.getKubernetesProviderVersionMutex.Lock()
+	defer fake.getKubernetesProviderVersionMutex.Unlock()
+	fake.GetKubernetesProviderVersionStub = stub
+}
+
+func (fake *FakeClient) GetKubernetesProviderVersionReturns(result1 int64, result2 error) {
+	fake.getKubernetesProviderVersionMutex.Lock()
+	defer fake.getKubernetesProviderVersionMutex.Unlock()
+	fake.GetKubernetesProviderVersionStub = nil
+	fake.getKubernetesProviderVersionReturns = struct {
+		result1 int64
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeClient) GetKubernetesProviderVersionReturnsOnCall(i int, result1 int64, result2 error) {
+	fake.getKubernetesProviderVersionMutex.Lock()
+	defer fake.getKubernetesProviderVersionMutex.Unlock()
+	fake.GetKubernetesProviderVersionStub = nil
+	if fake.getKubernetesProviderVersionReturnsOnCall == nil {
+		fake.getKubernetesProviderVersionReturnsOnCall = make(map[int]struct {
+			result1 int64
+			result2 error
+		})
+	}
+	fake.getKubernetesProviderVersionReturnsOnCall[i] = struct {
+		result1 int64
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeClient) GetMigrationReport(arg1 string) (clouddriver.MigrationReport, error) {
+	fake.getMigrationReportMutex.Lock()
+	ret, specificReturn := fake.getMigrationReportReturnsOnCall[len(fake.getMigrationReportArgsForCall)]
+	fake.getMigrationReportArgsForCall = append(fake.getMigrationReportArgsForCall, struct {
+		arg1 string
+	}{arg1})
+	fake.recordInvocation("GetMigrationReport", []interface{}{arg1})
+	fake.getMigrationReportMutex.Unlock()
+	if fake.GetMigrationReportStub != nil {
+		return fake.GetMigrationReportStub(arg1)
+	}
+	if specificReturn {
+		return ret.result1, ret.result2
+	}
+	fakeReturns := fake.getMigrationReportReturns
+	return fakeReturns.result1, fakeReturns.result2
+}
+
+func (fake *FakeClient) GetMigrationReportCallCount() int {
+	fake.getMigrationReportMutex.RLock()
+	defer fake.getMigrationReportMutex.RUnlock()
+	return len(fake.getMigrationReportArgsForCall)
+}
+
+func (fake *FakeClient) GetMigrationReportCalls(stub func(string) (clouddriver.MigrationReport, error)) {
+	fake.getMigrationReportMutex.Lock()
+	defer fake.getMigrationReportMutex.Unlock()
+	fake.GetMigrationReportStub = stub
+}
+
+func (fake *FakeClient) GetMigrationReportArgsForCall(i int) string {
+	fake.getMigrationReportMutex.RLock()
+	defer fake.getMigrationReportMutex.RUnlock()
+	argsForCall := fake.getMigrationReportArgsForCall[i]
+	return argsForCall.arg1
+}
+
+func (fake *FakeClient) GetMigrationReportReturns(result1 clouddriver.MigrationReport, result2 error) {
+	fake.getMigrationReportMutex.Lock()
+	defer fake.getMigrationReportMutex.Unlock()
+	fake.GetMigrationReportStub = nil
+	fake.getMigrationReportReturns = struct {
+		result1 clouddriver.MigrationReport
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeClient) GetMigrationReportReturnsOnCall(i int, result1 clouddriver.MigrationReport, result2 error) {
+	fake.getMigrationReportMutex.Lock()
+	defer fake.getMigrationReportMutex.Unlock()
+	fake.GetMigrationReportStub = nil
+	if fake.getMigrationReportReturnsOnCall == nil {
+		fake.getMigrationReportReturnsOnCall = make(map[int]struct {
+			result1 clouddriver.MigrationReport
+			result2 error
+		})
+	}
+	fake.getMigrationReportReturnsOnCall[i] = struct {
+		result1 clouddriver.MigrationReport
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeClient) ListApplications() ([]clouddriver.Application, error) {
+	fake.listApplicationsMutex.Lock()
+	ret, specificReturn := fake.listApplicationsReturnsOnCall[len(fake.listApplicationsArgsForCall)]
+	fake.listApplicationsArgsForCall = append(fake.listApplicationsArgsForCall, struct {
+	}{})
+	fake.recordInvocation("ListApplications", []interface{}{})
+	fake.listApplicationsMutex.Unlock()
+	if fake.ListApplicationsStub != nil {
+		return fake.ListApplicationsStub()
+	}
+	if specificReturn {
+		return ret.result1, ret.result2
+	}
+	fakeReturns := fake.listApplicationsReturns
+	return fakeReturns.result1, fakeReturns.result2
+}
+
+func (fake *FakeClient) ListApplicationsCallCount() int {
+	fake.listApplicationsMutex.RLock()
+	defer fake.listApplicationsMutex.RUnlock()
+	return len(fake.listApplicationsArgsForCall)
+}
+
+func (fake *FakeClient) ListApplicationsCalls(stub func() ([]clouddriver.Application, error)) {
+	fake.listApplicationsMutex.Lock()
+	defer fake.listApplicationsMutex.Unlock()
+	fake.ListApplicationsStub = stub
+}
+
+func (fake *FakeClient) ListApplicationsReturns(result1 []clouddriver.Application, result2 error) {
+	fake.listApplicationsMutex.Lock()
+	defer fake.listApplicationsMutex.Unlock()
+	fake.ListApplicationsStub = nil
+	fake.listApplicationsReturns = struct {
+		result1 []clouddriver.Application
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeClient) ListApplicationsReturnsOnCall(i int, result1 []clouddriver.Application, result2 error) {
+	fake.listApplicationsMutex.Lock()
+	defer fake.listApplicationsMutex.Unlock()
+	fake.ListApplicationsStub = nil
+	if fake.listApplicationsReturnsOnCall == nil {
+		fake.listApplicationsReturnsOnCall = make(map[int]struct {
+			result1 []clouddriver.Application
+			result2 error
+		})
+	}
+	fake.listApplicationsReturnsOnCall[i] = struct {
+		result1 []clouddriver.Application
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeClient) ListDeletedApplications() ([]string, error) {
+	fake.listDeletedApplicationsMutex.Lock()
+	ret, specificReturn := fake.listDeletedApplicationsReturnsOnCall[len(fake.listDeletedApplicationsArgsForCall)]
+	fake.listDeletedApplicationsArgsForCall = append(fake.listDeletedApplicationsArgsForCall, struct {
+	}{})
+	fake.recordInvocation("ListDeletedApplications", []interface{}{})
+	fake.listDeletedApplicationsMutex.Unlock()
+	if fake.ListDeletedApplicationsStub != nil {
+		return fake.ListDeletedApplicationsStub()
+	}
+	if specificReturn {
+		return ret.result1, ret.result2
+	}
+	fakeReturns := fake.listDeletedApplicationsReturns
+	return fakeReturns.result1, fakeReturns.result2
+}
+
+func (fake *FakeClient) ListDeletedApplicationsCallCount() int {
+	fake.listDeletedApplicationsMutex.RLock()
+	defer fake.listDeletedApplicationsMutex.RUnlock()
+	return len(fake.listDeletedApplicationsArgsForCall)
+}
+
+func (fake *FakeClient) ListDeletedApplicationsCalls(stub func() ([]string, error)) {
+	fake.listDeletedApplicationsMutex.Lock()
+	defer fake.listDeletedApplicationsMutex.Unlock()
+	fake.ListDeletedApplicationsStub = stub
+}
+
+func (fake *FakeClient) ListDeletedApplicationsReturns(result1 []string, result2 error) {
+	fake.listDeletedApplicationsMutex.Lock()
+	defer fake.listDeletedApplicationsMutex.Unlock()
+	fake.ListDeletedApplicationsStub = nil
+	fake.listDeletedApplicationsReturns = struct {
+		result1 []string
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeClient) ListDeletedApplicationsReturnsOnCall(i int, result1 []string, result2 error) {
+	fake.listDeletedApplicationsMutex.Lock()
+	defer fake.listDeletedApplicationsMutex.Unlock()
+	fake.ListDeletedApplicationsStub = nil
+	if fake.listDeletedApplicationsReturnsOnCall == nil {
+		fake.listDeletedApplicationsReturnsOnCall = make(map[int]struct {
+			result1 []string
+			result2 error
+		})
+	}
+	fake.listDeletedApplicationsReturnsOnCall[i] = struct {
+		result1 []string
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeClient) ListDeploysCreatedSince(arg1 time.Time) ([]clouddriver.Deploy, error) {
+	fake.listDeploysCreatedSinceMutex.Lock()
+	ret, specificReturn := fake.listDeploysCreatedSinceReturnsOnCall[len(fake.listDeploysCreatedSinceArgsForCall)]
+	fake.listDeploysCreatedSinceArgsForCall = append(fake.listDeploysCreatedSinceArgsForCall, struct {
+		arg1 time.Time
+	}{arg1})
+	fake.recordInvocation("ListDeploysCreatedSince", []interface{}{arg1})
+	fake.listDeploysCreatedSinceMutex.Unlock()
+	if fake.ListDeploysCreatedSinceStub != nil {
+		return fake.ListDeploysCreatedSinceStub(arg1)
+	}
+	if specificReturn {
+		return ret.result1, ret.result2
+	}
+	fakeReturns := fake.listDeploysCreatedSinceReturns
+	return fakeReturns.result1, fakeReturns.result2
+}
+
+func (fake *FakeClient) ListDeploysCreatedSinceCallCount() int {
+	fake.listDeploysCreatedSinceMutex.RLock()
+	defer fake.listDeploysCreatedSinceMutex.RUnlock()
+	return len(fake.listDeploysCreatedSinceArgsForCall)
+}
+
+func (fake *FakeClient) ListDeploysCreatedSinceCalls(stub func(time.Time) ([]clouddriver.Deploy, error)) {
+	fake.listDeploysCreatedSinceMutex.Lock()
+	defer fake.listDeploysCreatedSinceMutex.Unlock()
+	fake.ListDeploysCreatedSinceStub = stub
+}
+
+func (fake *FakeClient) ListDeploysCreatedSinceArgsForCall(i int) time.Time {
+	fake.listDeploysCreatedSinceMutex.RLock()
+	defer fake.listDeploysCreatedSinceMutex.RUnlock()
+	argsForCall := fake.listDeploysCreatedSinceArgsForCall[i]
+	return argsForCall.arg1
+}
+
+func (fake *FakeClient) ListDeploysCreatedSinceReturns(result1 []clouddriver.Deploy, result2 error) {
+	fake.listDeploysCreatedSinceMutex.Lock()
+	defer fake.listDeploysCreatedSinceMutex.Unlock()
+	fake.ListDeploysCreatedSinceStub = nil
+	fake.listDeploysCreatedSinceReturns = struct {
+		result1 []clouddriver.Deploy
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeClient) ListDeploysCreatedSinceReturnsOnCall(i int, result1 []clouddriver.Deploy, result2 error) {
+	fake.listDeploysCreatedSinceMutex.Lock()
+	defer fake.listDeploysCreatedSinceMutex.Unlock()
+	fake.ListDeploysCreatedSinceStub = nil
+	if fake.listDeploysCreatedSinceReturnsOnCall == nil {
+		fake.listDeploysCreatedSinceReturnsOnCall = make(map[int]struct {
+			result1 []clouddriver.Deploy
+			result2 error
+		})
+	}
+	fake.listDeploysCreatedSinceReturnsOnCall[i] = struct {
+		result1 []clouddriver.Deploy
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeClient) ListFeatures() ([]clouddriver.Feature, error) {
+	fake.listFeaturesMutex.Lock()
+	ret, specificReturn := fake.listFeaturesReturnsOnCall[len(fake.listFeaturesArgsForCall)]
+	fake.listFeaturesArgsForCall = append(fake.listFeaturesArgsForCall, struct {
+	}{})
+	fake.recordInvocation("ListFeatures", []interface{}{})
+	fake.listFeaturesMutex.Unlock()
+	if fake.ListFeaturesStub != nil {
+		return fake.ListFeaturesStub()
+	}
+	if specificReturn {
+		return ret.result1, ret.result2
+	}
+	fakeReturns := fake.listFeaturesReturns
+	return fakeReturns.result1, fakeReturns.result2
+}
+
+func (fake *FakeClient) ListFeaturesCallCount() int {
+	fake.listFeaturesMutex.RLock()
+	defer fake.listFeaturesMutex.RUnlock()
+	return len(fake.listFeaturesArgsForCall)
+}
+
+func (fake *FakeClient) ListFeaturesCalls(stub func() ([]clouddriver.Feature, error)) {
+	fake.listFeaturesMutex.Lock()
+	defer fake.listFeaturesMutex.Unlock()
+	fake.ListFeaturesStub = stub
+}
+
+func (fake *FakeClient) ListFeaturesReturns(result1 []clouddriver.Feature, result2 error) {
+	fake.listFeaturesMutex.Lock()
+	defer fake.listFeaturesMutex.Unlock()
+	fake.ListFeaturesStub = nil
+	fake.listFeaturesReturns = struct {
+		result1 []clouddriver.Feature
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeClient) ListFeaturesReturnsOnCall(i int, result1 []clouddriver.Feature, result2 error) {
+	fake.listFeaturesMutex.Lock()
+	defer fake.listFeaturesMutex.Unlock()
+	fake.ListFeaturesStub = nil
+	if fake.listFeaturesReturnsOnCall == nil {
+		fake.listFeaturesReturnsOnCall = make(map[int]struct {
+			result1 []clouddriver.Feature
+			result2 error
+		})
+	}
+	fake.listFeaturesReturnsOnCall[i] = struct {
+		result1 []clouddriver.Feature
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeClient) ListKindMappingsByAccountNames(arg1 ...string) (map[string]map[string]string, error) {
+	fake.listKindMappingsByAccountNamesMutex.Lock()
+	ret, specificReturn := fake.listKindMappingsByAccountNamesReturnsOnCall[len(fake.listKindMappingsByAccountNamesArgsForCall)]
+	fake.listKindMappingsByAccountNamesArgsForCall = append(fake.listKindMappingsByAccountNamesArgsForCall, struct {
+		arg1 []string
+	}{arg1})
+	fake.recordInvocation("ListKindMappingsByAccountNames", []interface{}{arg1})
+	fake.listKindMappingsByAccountNamesMutex.Unlock()
+	if fake.ListKindMappingsByAccountNamesStub != nil {
+		return fake.ListKindMappingsByAccountNamesStub(arg1...)
+	}
+	if specificReturn {
+		return ret.result1, ret.result2
+	}
+	fakeReturns := fake.listKindMappingsByAccountNamesReturns
+	return fakeReturns.result1, fakeReturns.result2
+}
+
+func (fake *FakeClient) ListKindMappingsByAccountNamesCallCount() int {
+	fake.listKindMappingsByAccountNamesMutex.RLock()
+	defer fake.listKindMappingsByAccountNamesMutex.RUnlock()
+	return len(fake.listKindMappingsByAccountNamesArgsForCall)
+}
+
+func (fake *FakeClient) ListKindMappingsByAccountNamesCalls(stub func(...string) (map[string]map[string]string, error)) {
+	fake.listKindMappingsByAccountNamesMutex.Lock()
+	defer fake.listKindMappingsByAccountNamesMutex.Unlock()
+	fake.ListKindMappingsByAccountNamesStub = stub
+}
+
+func (fake *FakeClient) ListKindMappingsByAccountNamesArgsForCall(i int) []string {
+	fake.listKindMappingsByAccountNamesMutex.RLock()
+	defer fake.listKindMappingsByAccountNamesMutex.RUnlock()
+	argsForCall := fake.listKindMappingsByAccountNamesArgsForCall[i]
+	return argsForCall.arg1
+}
+
+func (fake *FakeClient) ListKindMappingsByAccountNamesReturns(result1 map[string]map[string]string, result2 error) {
+	fake.listKindMappingsByAccountNamesMutex.Lock()
+	defer fake.listKindMappingsByAccountNamesMutex.Unlock()
+	fake.ListKindMappingsByAccountNamesStub = nil
+	fake.listKindMappingsByAccountNamesReturns = struct {
+		result1 map[string]map[string]string
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeClient) ListKindMappingsByAccountNamesReturnsOnCall(i int, result1 map[string]map[string]string, result2 error) {
+	fake.listKindMappingsByAccountNamesMutex.Lock()
+	defer fake.listKindMappingsByAccountNamesMutex.Unlock()
+	fake.ListKindMappingsByAccountNamesStub = nil
+	if fake.listKindMappingsByAccountNamesReturnsOnCall == nil {
+		fake.listKindMappingsByAccountNamesReturnsOnCall = make(map[int]struct {
+			result1 map[string]map[string]string
+			result2 error
+		})
+	}
+	fake.listKindMappingsByAccountNamesReturnsOnCall[i] = struct {
+		result1 map[string]map[string]string
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeClient) ListKubernetesAccountsBySpinnakerApp(arg1 string) ([]string, error) {
+	fake.listKubernetesAccountsBySpinnakerAppMutex.Lock()
+	ret, specificReturn := fake.listKubernetesAccountsBySpinnakerAppReturnsOnCall[len(fake.listKubernetesAccountsBySpinnakerAppArgsForCall)]
+	fake.listKubernetesAccountsBySpinnakerAppArgsForCall = append(fake.listKubernetesAccountsBySpinnakerAppArgsForCall, struct {
+		arg1 string
+	}{arg1})
+	fake.recordInvocation("ListKubernetesAccountsBySpinnakerApp", []interface{}{arg1})
+	fake.listKubernetesAccountsBySpinnakerAppMutex.Unlock()
+	if fake.ListKubernetesAccountsBySpinnakerAppStub != nil {
+		return fake.ListKubernetesAccountsBySpinnakerAppStub(arg1)
+	}
+	if specificReturn {
+		return ret.result1, ret.result2
+	}
+	fakeReturns := fake.listKubernetesAccountsBySpinnakerAppReturns
+	return fakeReturns.result1, fakeReturns.result2
+}
+
+func (fake *FakeClient) ListKubernetesAccountsBySpinnakerAppCallCount() int {
+	fake.listKubernetesAccountsBySpinnakerAppMutex.RLock()
+	defer fake.listKubernetesAccountsBySpinnakerAppMutex.RUnlock()
+	return len(fake.listKubernetesAccountsBySpinnakerAppArgsForCall)
+}
+
+func (fake *FakeClient) ListKubernetesAccountsBySpinnakerAppCalls(stub func(string) ([]string, error)) {
+	fake.listKubernetesAccountsBySpinnakerAppMutex.Lock()
+	defer fake.listKubernetesAccountsBySpinnakerAppMutex.Unlock()
+	fake.ListKubernetesAccountsBySpinnakerAppStub = stub
+}
+
+func (fake *FakeClient) ListKubernetesAccountsBySpinnakerAppArgsForCall(i int) string {
+	fake.listKubernetesAccountsBySpinnakerAppMutex.RLock()
+	defer fake.listKubernetesAccountsBySpinnakerAppMutex.RUnlock()
+	argsForCall := fake.listKubernetesAccountsBySpinnakerAppArgsForCall[i]
+	return argsForCall.arg1
+}
+
+func (fake *FakeClient) ListKubernetesAccountsBySpinnakerAppReturns(result1 []string, result2 error) {
+	fake.listKubernetesAccountsBySpinnakerAppMutex.Lock()
+	defer fake.listKubernetesAccountsBySpinnakerAppMutex.Unlock()
+	fake.ListKubernetesAccountsBySpinnakerAppStub = nil
+	fake.listKubernetesAccountsBySpinnakerAppReturns = struct {
+		result1 []string
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeClient) ListKubernetesAccountsBySpinnakerAppReturnsOnCall(i int, result1 []string, result2 error) {
+	fake.listKubernetesAccountsBySpinnakerAppMutex.Lock()
+	defer fake.listKubernetesAccountsBySpinnakerAppMutex.Unlock()
+	fake.ListKubernetesAccountsBySpinnakerAppStub = nil
+	if fake.listKubernetesAccountsBySpinnakerAppReturnsOnCall == nil {
+		fake.listKubernetesAccountsBySpinnakerAppReturnsOnCall = make(map[int]struct {
+			result1 []string
+			result2 error
+		})
+	}
+	fake.listKubernetesAccountsBySpinnakerAppReturnsOnCall[i] = struct {
+		result1 []string
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeClient) ListKubernetesClustersByApplication(arg1 string) ([]kubernetes.Resource, error) {
+	fake.listKubernetesClustersByApplicationMutex.Lock()
+	ret, specificReturn := fake.listKubernetesClustersByApplicationReturnsOnCall[len(fake.listKubernetesClustersByApplicationArgsForCall)]
+	fake.listKubernetesClustersByApplicationArgsForCall = append(fake.listKubernetesClustersByApplicationArgsForCall, struct {
+		arg1 string
+	}{arg1})
+	fake.recordInvocation("ListKubernetesClustersByApplication", []interface{}{arg1})
+	fake.listKubernetesClustersByApplicationMutex.Unlock()
+	if fake.ListKubernetesClustersByApplicationStub != nil {
+		return fake.ListKubernetesClustersByApplicationStub(arg1)
+	}
+	if specificReturn {
+		return ret.result1, ret.result2
+	}
+	fakeReturns := fake.listKubernetesClustersByApplicationReturns
+	return fakeReturns.result1, fakeReturns.result2
+}
+
+func (fake *FakeClient) ListKubernetesClustersByApplicationCallCount() int {
+	fake.listKubernetesClustersByApplicationMutex.RLock()
+	defer fake.listKubernetesClustersByApplicationMutex.RUnlock()
+	return len(fake.listKubernetesClustersByApplicationArgsForCall)
+}
+
+func (fake *FakeClient) ListKubernetesClustersByApplicationCalls(stub func(string) ([]kubernetes.Resource, error)) {
+	fake.listKubernetesClustersByApplicationMutex.Lock()
+	defer fake.listKubernetesClustersByApplicationMutex.Unlock()
+	fake.ListKubernetesClustersByApplicationStub = stub
+}
+
+func (fake *FakeClient) ListKubernetesClustersByApplicationArgsForCall(i int) string {
+	fake.listKubernetesClustersByApplicationMutex.RLock()
+	defer fake.listKubernetesClustersByApplicationMutex.RUnlock()
+	argsForCall := fake.listKubernetesClustersByApplicationArgsForCall[i]
+	return argsForCall.arg1
+}
+
+func (fake *FakeClient) ListKubernetesClustersByApplicationReturns(result1 []kubernetes.Resource, result2 error) {
+	fake.listKubernetesClustersByApplicationMutex.Lock()
+	defer fake.listKubernetesClustersByApplicationMutex.Unlock()
+	fake.ListKubernetesClustersByApplicationStub = nil
+	fake.listKubernetesClustersByApplicationReturns = struct {
+		result1 []kubernetes.Resource
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeClient) ListKubernetesClustersByApplicationReturnsOnCall(i int, result1 []kubernetes.Resource, result2 error) {
+	fake.listKubernetesClustersByApplicationMutex.Lock()
+	defer fake.listKubernetesClustersByApplicationMutex.Unlock()
+	fake.ListKubernetesClustersByApplicationStub = nil
+	if fake.listKubernetesClustersByApplicationReturnsOnCall == nil {
+		fake.listKubernetesClustersByApplicationReturnsOnCall = make(map[int]struct {
+			result1 []kubernetes.Resource
+			result2 error
+		})
+	}
+	fake.listKubernetesClustersByApplicationReturnsOnCall[i] = struct {
+		result1 []kubernetes.Resource
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeClient) ListKubernetesLastDeployTimes() (map[string]time.Time, error) {
+	fake.listKubernetesLastDeployTimesMutex.Lock()
+	ret, specificReturn := fake.listKubernetesLastDeployTimesReturnsOnCall[len(fake.listKubernetesLastDeployTimesArgsForCall)]
+	fake.listKubernetesLastDeployTimesArgsForCall = append(fake.listKubernetesLastDeployTimesArgsForCall, struct {
+	}{})
+	fake.recordInvocation("ListKubernetesLastDeployTimes", []interface{}{})
+	fake.listKubernetesLastDeployTimesMutex.Unlock()
+	if fake.ListKubernetesLastDeployTimesStub != nil {
+		return fake.ListKubernetesLastDeployTimesStub()
+	}
+	if specificReturn {
+		return ret.result1, ret.result2
+	}
+	fakeReturns := fake.listKubernetesLastDeployTimesReturns
+	return fakeReturns.result1, fakeReturns.result2
+}
+
+func (fake *FakeClient) ListKubernetesLastDeployTimesCallCount() int {
+	fake.listKubernetesLastDeployTimesMutex.RLock()
+	defer fake.listKubernetesLastDeployTimesMutex.RUnlock()
+	return len(fake.listKubernetesLastDeployTimesArgsForCall)
+}
+
+func (fake *FakeClient) ListKubernetesLastDeployTimesCalls(stub func() (map[string]time.Time, error)) {
+	fake.listKubernetesLastDeployTimesMutex.Lock()
+	defer fake.listKubernetesLastDeployTimesMutex.Unlock()
+	fake.ListKubernetesLastDeployTimesStub = stub
+}
+
+func (fake *FakeClient) ListKubernetesLastDeployTimesReturns(result1 map[string]time.Time, result2 error) {
+	fake.listKubernetesLastDeployTimesMutex.Lock()
+	defer fake.listKubernetesLastDeployTimesMutex.Unlock()
+	fake.ListKubernetesLastDeployTimesStub = nil
+	fake.listKubernetesLastDeployTimesReturns = struct {
+		result1 map[string]time.Time
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeClient) ListKubernetesLastDeployTimesReturnsOnCall(i int, result1 map[string]time.Time, result2 error) {
+	fake.listKubernetesLastDeployTimesMutex.Lock()
+	defer fake.listKubernetesLastDeployTimesMutex.Unlock()
+	fake.ListKubernetesLastDeployTimesStub = nil
+	if fake.listKubernetesLastDeployTimesReturnsOnCall == nil {
+		fake.listKubernetesLastDeployTimesReturnsOnCall = make(map[int]struct {
+			result1 map[string]time.Time
+			result2 error
+		})
+	}
+	fake.listKubernetesLastDeployTimesReturnsOnCall[i] = struct {
+		result1 map[string]time.Time
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeClient) ListKubernetesProviderNamesByClusterCredential(arg1 string) ([]string, error) {
+	fake.listKubernetesProviderNamesByClusterCredentialMutex.Lock()
+	ret, specificReturn := fake.listKubernetesProviderNamesByClusterCredentialReturnsOnCall[len(fake.listKubernetesProviderNamesByClusterCredentialArgsForCall)]
+	fake.listKubernetesProviderNamesByClusterCredentialArgsForCall = append(fake.listKubernetesProviderNamesByClusterCredentialArgsForCall, struct {
+		arg1 string
+	}{arg1})
+	fake.recordInvocation("ListKubernetesProviderNamesByClusterCredential", []interface{}{arg1})
+	fake.listKubernetesProviderNamesByClusterCredentialMutex.Unlock()
+	if fake.ListKubernetesProviderNamesByClusterCredentialStub != nil {
+		return fake.ListKubernetesProviderNamesByClusterCredentialStub(arg1)
+	}
+	if specificReturn {
+		return ret.result1, ret.result2
+	}
+	fakeReturns := fake.listKubernetesProviderNamesByClusterCredentialReturns
+	return fakeReturns.result1, fakeReturns.result2
+}
+
+func (fake *FakeClient) ListKubernetesProviderNamesByClusterCredentialCallCount() int {
+	fake.listKubernetesProviderNamesByClusterCredentialMutex.RLock()
+	defer fake.listKubernetesProviderNamesByClusterCredentialMutex.RUnlock()
+	return len(fake.listKubernetesProviderNamesByClusterCredentialArgsForCall)
+}
+
+func (fake *FakeClient) ListKubernetesProviderNamesByClusterCredentialCalls(stub func(string) ([]string, error)) {
+	fake.listKubernetesProviderNamesByClusterCredentialMutex.Lock()
+	defer fake.listKubernetesProviderNamesByClusterCredentialMutex.Unlock()
+	fake.ListKubernetesProviderNamesByClusterCredentialStub = stub
+}
+
+func (fake *FakeClient) ListKubernetesProviderNamesByClusterCredentialArgsForCall(i int) string {
+	fake.listKubernetesProviderNamesByClusterCredentialMutex.RLock()
+	defer fake.listKubernetesProviderNamesByClusterCredentialMutex.RUnlock()
+	argsForCall := fake.listKubernetesProviderNamesByClusterCredentialArgsForCall[i]
+	return argsForCall.arg1
+}
+
+func (fake *FakeClient) ListKubernetesProviderNamesByClusterCredentialReturns(result1 []string, result2 error) {
+	fake.listKubernetesProviderNamesByClusterCredentialMutex.Lock()
+	defer fake.listKubernetesProviderNamesByClusterCredentialMutex.Unlock()
+	fake.ListKubernetesProviderNamesByClusterCredentialStub = nil
+	fake.listKubernetesProviderNamesByClusterCredentialReturns = struct {
+		result1 []string
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeClient) ListKubernetesProviderNamesByClusterCredentialReturnsOnCall(i int, result1 []string, result2 error) {
+	fake.listKubernetesProviderNamesByClusterCredentialMutex.Lock()
+	defer fake.listKubernetesProviderNamesByClusterCredentialMutex.Unlock()
+	fake.ListKubernetesProviderNamesByClusterCredentialStub = nil
+	if fake.listKubernetesProviderNamesByClusterCredentialReturnsOnCall == nil {
+		fake.listKubernetesProviderNamesByClusterCredentialReturnsOnCall = make(map[int]struct {
+			result1 []string
+			result2 error
+		})
+	}
+	fake.listKubernetesProviderNamesByClusterCredentialReturnsOnCall[i] = struct {
+		result1 []string
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeClient) ListKubernetesProviders() ([]kubernetes.Provider, error) {
+	fake.listKubernetesProvidersMutex.Lock()
+	ret, specificReturn := fake.listKubernetesProvidersReturnsOnCall[len(fake.listKubernetesProvidersArgsForCall)]
+	fake.listKubernetesProvidersArgsForCall = append(fake.listKubernetesProvidersArgsForCall, struct {
+	}{})
+	fake.recordInvocation("ListKubernetesProviders", []interface{}{})
+	fake.listKubernetesProvidersMutex.Unlock()
+	if fake.ListKubernetesProvidersStub != nil {
+		return fake.ListKubernetesProvidersStub()
+	}
+	if specificReturn {
+		return ret.result1, ret.result2
+	}
+	fakeReturns := fake.listKubernetesProvidersReturns
+	return fakeReturns.result1, fakeReturns.result2
+}
+
+func (fake *FakeClient) ListKubernetesProvidersCallCount() int {
+	fake.listKubernetesProvidersMutex.RLock()
+	defer fake.listKubernetesProvidersMutex.RUnlock()
+	return len(fake.listKubernetesProvidersArgsForCall)
+}
+
+func (fake *FakeClient) ListKubernetesProvidersCalls(stub func() ([]kubernetes.Provider, error)) {
+	fake.listKubernetesProvidersMutex.Lock()
+	defer fake.listKubernetesProvidersMutex.Unlock()
+	fake.ListKubernetesProvidersStub = stub
+}
+
+func (fake *FakeClient) ListKubernetesProvidersReturns(result1 []kubernetes.Provider, result2 error) {
+	fake.listKubernetesProvidersMutex.Lock()
+	defer fake.listKubernetesProvidersMutex.Unlock()
+	fake.ListKubernetesProvidersStub = nil
+	fake.listKubernetesProvidersReturns = struct {
+		result1 []kubernetes.Provider
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeClient) ListKubernetesProvidersReturnsOnCall(i int, result1 []kubernetes.Provider, result2 error) {
+	fake.listKubernetesProvidersMutex.Lock()
+	defer fake.listKubernetesProvidersMutex.Unlock()
+	fake.ListKubernetesProvidersStub = nil
+	if fake.listKubernetesProvidersReturnsOnCall == nil {
+		fake.listKubernetesProvidersReturnsOnCall = make(map[int]struct {
+			result1 []kubernetes.Provider
+			result2 error
+		})
+	}
+	fake.listKubernetesProvidersReturnsOnCall[i] = struct {
+		result1 []kubernetes.Provider
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeClient) ListKubernetesProvidersAndPermissions() ([]kubernetes.Provider, error) {
+	fake.listKubernetesProvidersAndPermissionsMutex.Lock()
+	ret, specificReturn := fake.listKubernetesProvidersAndPermissionsReturnsOnCall[len(fake.listKubernetesProvidersAndPermissionsArgsForCall)]
+	fake.listKubernetesProvidersAndPermissionsArgsForCall = append(fake.listKubernetesProvidersAndPermissionsArgsForCall, struct {
+	}{})
+	fake.recordInvocation("ListKubernetesProvidersAndPermissions", []interface{}{})
+	fake.listKubernetesProvidersAndPermissionsMutex.Unlock()
+	if fake.ListKubernetesProvidersAndPermissionsStub != nil {
+		return fake.ListKubernetesProvidersAndPermissionsStub()
+	}
+	if specificReturn {
+		return ret.result1, ret.result2
+	}
+	fakeReturns := fake.listKubernetesProvidersAndPermissionsReturns
+	return fakeReturns.result1, fakeReturns.result2
+}
+
+func (fake *FakeClient) ListKubernetesProvidersAndPermissionsCallCount() int {
+	fake.listKubernetesProvidersAndPermissionsMutex.RLock()
+	defer fake.listKubernetesProvidersAndPermissionsMutex.RUnlock()
+	return len(fake.listKubernetesProvidersAndPermissionsArgsForCall)
+}
+
+func (fake *FakeClient) ListKubernetesProvidersAndPermissionsCalls(stub func() ([]kubernetes.Provider, error)) {
+	fake.listKubernetesProvidersAndPermissionsMutex.Lock()
+	defer fake.listKubernetesProvidersAndPermissionsMutex.Unlock()
+	fake.ListKubernetesProvidersAndPermissionsStub = stub
+}
+
+func (fake *FakeClient) ListKubernetesProvidersAndPermissionsReturns(result1 []kubernetes.Provider, result2 error) {
+	fake.listKubernetesProvidersAndPermissionsMutex.Lock()
+	defer fake.listKubernetesProvidersAndPermissionsMutex.Unlock()
+	fake.ListKubernetesProvidersAndPermissionsStub = nil
+	fake.listKubernetesProvidersAndPermissionsReturns = struct {
+		result1 []kubernetes.Provider
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeClient) ListKubernetesProvidersAndPermissionsReturnsOnCall(i int, result1 []kubernetes.Provider, result2 error) {
+	fake.listKubernetesProvidersAndPermissionsMutex.Lock()
+	defer fake.listKubernetesProvidersAndPermissionsMutex.Unlock()
+	fake.ListKubernetesProvidersAndPermissionsStub = nil
+	if fake.listKubernetesProvidersAndPermissionsReturnsOnCall == nil {
+		fake.listKubernetesProvidersAndPermissionsReturnsOnCall = make(map[int]struct {
+			result1 []kubernetes.Provider
+			result2 error
+		})
+	}
+	fake.listKubernetesProvidersAndPermissionsReturnsOnCall[i] = struct {
+		result1 []kubernetes.Provider
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeClient) ListKubernetesProvidersChangedSince(arg1 int64) ([]kubernetes.Provider, error) {
+	fake.listKubernetesProvidersChangedSinceMutex.Lock()
+	ret, specificReturn := fake.listKubernetesProvidersChangedSinceReturnsOnCall[len(fake.listKubernetesProvidersChangedSinceArgsForCall)]
+	fake.listKubernetesProvidersChangedSinceArgsForCall = append(fake.listKubernetesProvidersChangedSinceArgsForCall, struct {
+		arg1 int64
+	}{arg1})
+	fake.recordInvocation("ListKubernetesProvidersChangedSince", []interface{}{arg1})
+	fake.listKubernetesProvidersChangedSinceMutex.Unlock()
+	if fake.ListKubernetesProvidersChangedSinceStub != nil {
+		return fake.ListKubernetesProvidersChangedSinceStub(arg1)
+	}
+	if specificReturn {
+		return ret.result1, ret.result2
+	}
+	fakeReturns := fake.listKubernetesProvidersChangedSinceReturns
+	return fakeReturns.result1, fakeReturns.result2
+}
+
+func (fake *FakeClient) ListKubernetesProvidersChangedSinceCallCount() int {
+	fake.listKubernetesProvidersChangedSinceMutex.RLock()
+	defer fake.listKubernetesProvidersChangedSinceMutex.RUnlock()
+	return len(fake.listKubernetesProvidersChangedSinceArgsForCall)
+}
+
+func (fake *FakeClient) ListKubernetesProvidersChangedSinceCalls(stub func(int64) ([]kubernetes.Provider, error)) {
+	fake.listKubernetesProvidersChangedSinceMutex.Lock()
+	defer fake.listKubernetesProvidersChangedSinceMutex.Unlock()
+	fake.ListKubernetesProvidersChangedSinceStub = stub
+}
+
+func (fake *FakeClient) ListKubernetesProvidersChangedSinceArgsForCall(i int) int64 {
+	fake.listKubernetesProvidersChangedSinceMutex.RLock()
+	defer fake.listKubernetesProvidersChangedSinceMutex.RUnlock()
+	argsForCall := fake.listKubernetesProvidersChangedSinceArgsForCall[i]
+	return argsForCall.arg1
+}
+
+func (fake *FakeClient) ListKubernetesProvidersChangedSinceReturns(result1 []kubernetes.Provider, result2 error) {
+	fake.listKubernetesProvidersChangedSinceMutex.Lock()
+	defer fake.listKubernetesProvidersChangedSinceMutex.Unlock()
+	fake.ListKubernetesProvidersChangedSinceStub = nil
+	fake.listKubernetesProvidersChangedSinceReturns = struct {
+		result1 []kubernetes.Provider
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeClient) ListKubernetesProvidersChangedSinceReturnsOnCall(i int, result1 []kubernetes.Provider, result2 error) {
+	fake.listKubernetesProvidersChangedSinceMutex.Lock()
+	defer fake.listKubernetesProvidersChangedSinceMutex.Unlock()
+	fake.ListKubernetesProvidersChangedSinceStub = nil
+	if fake.listKubernetesProvidersChangedSinceReturnsOnCall == nil {
+		fake.listKubernetesProvidersChangedSinceReturnsOnCall = make(map[int]struct {
+			result1 []kubernetes.Provider
+			result2 error
+		})
+	}
+	fake.listKubernetesProvidersChangedSinceReturnsOnCall[i] = struct {
+		result1 []kubernetes.Provider
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeClient) ListKubernetesProvidersDeletedSince(arg1 int64) ([]string, error) {
+	fake.listKubernetesProvidersDeletedSinceMutex.Lock()
+	ret, specificReturn := fake.listKubernetesProvidersDeletedSinceReturnsOnCall[len(fake.listKubernetesProvidersDeletedSinceArgsForCall)]
+	fake.listKubernetesProvidersDeletedSinceArgsForCall = append(fake.listKubernetesProvidersDeletedSinceArgsForCall, struct {
+		arg1 int64
+	}{arg1})
+	fake.recordInvocation("ListKubernetesProvidersDeletedSince", []interface{}{arg1})
+	fake.listKubernetesProvidersDeletedSinceMutex.Unlock()
+	if fake.ListKubernetesProvidersDeletedSinceStub != nil {
+		return fake.ListKubernetesProvidersDeletedSinceStub(arg1)
+	}
+	if specificReturn {
+		return ret.result1, ret.result2
+	}
+	fakeReturns := fake.listKubernetesProvidersDeletedSinceReturns
+	return fakeReturns.result1, fakeReturns.result2
+}
+
+func (fake *FakeClient) ListKubernetesProvidersDeletedSinceCallCount() int {
+	fake.listKubernetesProvidersDeletedSinceMutex.RLock()
+	defer fake.listKubernetesProvidersDeletedSinceMutex.RUnlock()
+	return len(fake.listKubernetesProvidersDeletedSinceArgsForCall)
+}
+
+func (fake *FakeClient) ListKubernetesProvidersDeletedSinceCalls(stub func(int64) ([]string, error)) {
+	fake.listKubernetesProvidersDeletedSinceMutex.Lock()
+	defer fake.listKubernetesProvidersDeletedSinceMutex.Unlock()
+	fake.ListKubernetesProvidersDeletedSinceStub = stub
+}
+
+func (fake *FakeClient) ListKubernetesProvidersDeletedSinceArgsForCall(i int) int64 {
+	fake.listKubernetesProvidersDeletedSinceMutex.RLock()
+	defer fake.listKubernetesProvidersDeletedSinceMutex.RUnlock()
+	argsForCall := fake.listKubernetesProvidersDeletedSinceArgsForCall[i]
+	return argsForCall.arg1
+}
+
+func (fake *FakeClient) ListKubernetesProvidersDeletedSinceReturns(result1 []string, result2 error) {
+	fake.listKubernetesProvidersDeletedSinceMutex.Lock()
+	defer fake.listKubernetesProvidersDeletedSinceMutex.Unlock()
+	fake.ListKubernetesProvidersDeletedSinceStub = nil
+	fake.listKubernetesProvidersDeletedSinceReturns = struct {
+		result1 []string
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeClient) ListKubernetesProvidersDeletedSinceReturnsOnCall(i int, result1 []string, result2 error) {
+	fake.listKubernetesProvidersDeletedSinceMutex.Lock()
+	defer fake.listKubernetesProvidersDeletedSinceMutex.Unlock()
+	fake.ListKubernetesProvidersDeletedSinceStub = nil
+	if fake.listKubernetesProvidersDeletedSinceReturnsOnCall == nil {
+		fake.listKubernetesProvidersDeletedSinceReturnsOnCall = make(map[int]struct {
+			result1 []string
+			result2 error
+		})
+	}
+	fake.listKubernetesProvidersDeletedSinceReturnsOnCall[i] = struct {
+		result1 []string
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeClient) ListKubernetesResourceNamesByAccountNameAndKindAndNamespace(arg1 string, arg2 string, arg3 string) ([]string, error) {
+	fake.listKubernetesResourceNamesByAccountNameAndKindAndNamespaceMutex.Lock()
+	ret, specificReturn := fake.listKubernetesResourceNamesByAccountNameAndKindAndNamespaceReturnsOnCall[len(fake.listKubernetesResourceNamesByAccountNameAndKindAndNamespaceArgsForCall)]
+	fake.listKubernetesResourceNamesByAccountNameAndKindAndNamespaceArgsForCall = append(fake.listKubernetesResourceNamesByAccountNameAndKindAndNamespaceArgsForCall, struct {
+		arg1 string
+		arg2 string
+		arg3 string
+	}{arg1, arg2, arg3})
+	fake.recordInvocation("ListKubernetesResourceNamesByAccountNameAndKindAndNamespace", []interface{}{arg1, arg2, arg3})
+	fake.listKubernetesResourceNamesByAccountNameAndKindAndNamespaceMutex.Unlock()
+	if fake.ListKubernetesResourceNamesByAccountNameAndKindAndNamespaceStub != nil {
+		return fake.ListKubernetesResourceNamesByAccountNameAndKindAndNamespaceStub(arg1, arg2, arg3)
+	}
+	if specificReturn {
+		return ret.result1, ret.result2
+	}
+	fakeReturns := fake.listKubernetesResourceNamesByAccountNameAndKindAndNamespaceReturns
+	return fakeReturns.result1, fakeReturns.result2
+}
+
+func (fake *FakeClient) ListKubernetesResourceNamesByAccountNameAndKindAndNamespaceCallCount() int {
+	fake.listKubernetesResourceNamesByAccountNameAndKindAndNamespaceMutex.RLock()
+	defer fake.listKubernetesResourceNamesByAccountNameAndKindAndNamespaceMutex.RUnlock()
+	return len(fake.listKubernetesResourceNamesByAccountNameAndKindAndNamespaceArgsForCall)
+}
+
+func (fake *FakeClient) ListKubernetesResourceNamesByAccountNameAndKindAndNamespaceCalls(stub func(string, string, string) ([]string, error)) {
+	fake.listKubernetesResourceNamesByAccountNameAndKindAndNamespaceMutex.Lock()
+	defer fake.listKubernetesResourceNamesByAccountNameAndKindAndNamespaceMutex.Unlock()
+	fake.ListKubernetesResourceNamesByAccountNameAndKindAndNamespaceStub = stub
+}
+
+func (fake *FakeClient) ListKubernetesResourceNamesByAccountNameAndKindAndNamespaceArgsForCall(i int) (string, string, string) {
+	fake.listKubernetesResourceNamesByAccountNameAndKindAndNamespaceMutex.RLock()
+	defer fake.listKubernetesResourceNamesByAccountNameAndKindAndNamespaceMutex.RUnlock()
+	argsForCall := fake.listKubernetesResourceNamesByAccountNameAndKindAndNamespaceArgsForCall[i]
+	return argsForCall.arg1, argsForCall.arg2, argsForCall.arg3
+}
+
+func (fake *FakeClient) ListKubernetesResourceNamesByAccountNameAndKindAndNamespaceReturns(result1 []string, result2 error) {
+	fake.listKubernetesResourceNamesByAccountNameAndKindAndNamespaceMutex.Lock()
+	defer fake.listKubernetesResourceNamesByAccountNameAndKindAndNamespaceMutex.Unlock()
+	fake.ListKubernetesResourceNamesByAccountNameAndKindAndNamespaceStub = nil
+	fake.listKubernetesResourceNamesByAccountNameAndKindAndNamespaceReturns = struct {
+		result1 []string
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeClient) ListKubernetesResourceNamesByAccountNameAndKindAndNamespaceReturnsOnCall(i int, result1 []string, result2 error) {
+	fake.listKubernetesResourceNamesByAccountNameAndKindAndNamespaceMutex.Lock()
+	defer fake.listKubernetesResourceNamesByAccountNameAndKindAndNamespaceMutex.Unlock()
+	fake.ListKubernetesResourceNamesByAccountNameAndKindAndNamespaceStub = nil
+	if fake.listKubernetesResourceNamesByAccountNameAndKindAndNamespaceReturnsOnCall == nil {
+		fake.listKubernetesResourceNamesByAccountNameAndKindAndNamespaceReturnsOnCall = make(map[int]struct {
+			result1 []string
+			result2 error
+		})
+	}
+	fake.listKubernetesResourceNamesByAccountNameAndKindAndNamespaceReturnsOnCall[i] = struct {
+		result1 []string
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeClient) ListKubernetesResourcesByAccountNames(arg1 ...string) ([]kubernetes.Resource, error) {
+	fake.listKubernetesResourcesByAccountNamesMutex.Lock()
+	ret, specificReturn := fake.listKubernetesResourcesByAccountNamesReturnsOnCall[len(fake.listKubernetesResourcesByAccountNamesArgsForCall)]
+	fake.listKubernetesResourcesByAccountNamesArgsForCall = append(fake.listKubernetesResourcesByAccountNamesArgsForCall, struct {
+		arg1 []string
+	}{arg1})
+	fake.recordInvocation("ListKubernetesResourcesByAccountNames", []interface{}{arg1})
+	fake.listKubernetesResourcesByAccountNamesMutex.Unlock()
+	if fake.ListKubernetesResourcesByAccountNamesStub != nil {
+		return fake.ListKubernetesResourcesByAccountNamesStub(arg1...)
+	}
+	if specificReturn {
+		return ret.result1, ret.result2
+	}
+	fakeReturns := fake.listKubernetesResourcesByAccountNamesReturns
+	return fakeReturns.result1, fakeReturns.result2
+}
+
+func (fake *FakeClient) ListKubernetesResourcesByAccountNamesCallCount() int {
+	fake.listKubernetesResourcesByAccountNamesMutex.RLock()
+	defer fake.listKubernetesResourcesByAccountNamesMutex.RUnlock()
+	return len(fake.listKubernetesResourcesByAccountNamesArgsForCall)
+}
+
+func (fake *FakeClient) ListKubernetesResourcesByAccountNamesCalls(stub func(...string) ([]kubernetes.Resource, error)) {
+	fake.listKubernetesResourcesByAccountNamesMutex.Lock()
+	defer fake.listKubernetesResourcesByAccountNamesMutex.Unlock()
+	fake.ListKubernetesResourcesByAccountNamesStub = stub
+}
+
+func (fake *FakeClient) ListKubernetesResourcesByAccountNamesArgsForCall(i int) []string {
+	fake.listKubernetesResourcesByAccountNamesMutex.RLock()
+	defer fake.listKubernetesResourcesByAccountNamesMutex.RUnlock()
+	argsForCall := fake.listKubernetesResourcesByAccountNamesArgsForCall[i]
+	return argsForCall.arg1
+}
+
+func (fake *FakeClient) ListKubernetesResourcesByAccountNamesReturns(result1 []kubernetes.Resource, result2 error) {
+	fake.listKubernetesResourcesByAccountNamesMutex.Lock()
+	defer fake.listKubernetesResourcesByAccountNamesMutex.Unlock()
+	fake.ListKubernetesResourcesByAccountNamesStub = nil
+	fake.listKubernetesResourcesByAccountNamesReturns = struct {
+		result1 []kubernetes.Resource
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeClient) ListKubernetesResourcesByAccountNamesReturnsOnCall(i int, result1 []kubernetes.Resource, result2 error) {
+	fake.listKubernetesResourcesByAccountNamesMutex.Lock()
+	defer fake.listKubernetesResourcesByAccountNamesMutex.Unlock()
+	fake.ListKubernetesResourcesByAccountNamesStub = nil
+	if fake.listKubernetesResourcesByAccountNamesReturnsOnCall == nil {
+		fake.listKubernetesResourcesByAccountNamesReturnsOnCall = make(map[int]struct {
+			result1 []kubernetes.Resource
+			result2 error
+		})
+	}
+	fake.listKubernetesResourcesByAccountNamesReturnsOnCall[i] = struct {
+		result1 []kubernetes.Resource
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeClient) ListKubernetesResourcesByFields(arg1 ...string) ([]kubernetes.Resource, error) {
+	fake.listKubernetesResourcesByFieldsMutex.Lock()
+	ret, specificReturn := fake.listKubernetesResourcesByFieldsReturnsOnCall[len(fake.listKubernetesResourcesByFieldsArgsForCall)]
+	fake.listKubernetesResourcesByFieldsArgsForCall = append(fake.listKubernetesResourcesByFieldsArgsForCall, struct {
+		arg1 []string
+	}{arg1})
+	fake.recordInvocation("ListKubernetesResourcesByFields", []interface{}{arg1})
+	fake.listKubernetesResourcesByFieldsMutex.Unlock()
+	if fake.ListKubernetesResourcesByFieldsStub != nil {
+		return fake.ListKubernetesResourcesByFieldsStub(arg1...)
+	}
+	if specificReturn {
+		return ret.result1, ret.result2
+	}
+	fakeReturns := fake.listKubernetesResourcesByFieldsReturns
+	return fakeReturns.result1, fakeReturns.result2
+}
+
+func (fake *FakeClient) ListKubernetesResourcesByFieldsCallCount() int {
+	fake.listKubernetesResourcesByFieldsMutex.RLock()
+	defer fake.listKubernetesResourcesByFieldsMutex.RUnlock()
+	return len(fake.listKubernetesResourcesByFieldsArgsForCall)
+}
+
+func (fake *FakeClient) ListKubernetesResourcesByFieldsCalls(stub func(...string) ([]kubernetes.Resource, error)) {
+	fake.listKubernetesResourcesByFieldsMutex.Lock()
+	defer fake.listKubernetesResourcesByFieldsMutex.Unlock()
+	fake.ListKubernetesResourcesByFieldsStub = stub
+}
+
+func (fake *FakeClient) ListKubernetesResourcesByFieldsArgsForCall(i int) []string {
+	fake.listKubernetesResourcesByFieldsMutex.RLock()
+	defer fake.listKubernetesResourcesByFieldsMutex.RUnlock()
+	argsForCall := fake.listKubernetesResourcesByFieldsArgsForCall[i]
+	return argsForCall.arg1
+}
+
+func (fake *FakeClient) ListKubernetesResourcesByFieldsReturns(result1 []kubernetes.Resource, result2 error) {
+	fake.listKubernetesResourcesByFieldsMutex.Lock()
+	defer fake.listKubernetesResourcesByFieldsMutex.Unlock()
+	fake.ListKubernetesResourcesByFieldsStub = nil
+	fake.listKubernetesResourcesByFieldsReturns = struct {
+		result1 []kubernetes.Resource
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeClient) ListKubernetesResourcesByFieldsReturnsOnCall(i int, result1 []kubernetes.Resource, result2 error) {
+	fake.listKubernetesResourcesByFieldsMutex.Lock()
+	defer fake.listKubernetesResourcesByFieldsMutex.Unlock()
+	fake.ListKubernetesResourcesByFieldsStub = nil
+	if fake.listKubernetesResourcesByFieldsReturnsOnCall == nil {
+		fake.listKubernetesResourcesByFieldsReturnsOnCall = make(map[int]struct {
+			result1 []kubernetes.Resource
+			result2 error
+		})
+	}
+	fake.listKubernetesResourcesByFieldsReturnsOnCall[i] = struct {
+		result1 []kubernetes.Resource
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeClient) ListKubernetesResourcesByTaskID(arg1 string) ([]kubernetes.Resource, error) {
+	fake.listKubernetesResourcesByTaskIDMutex.Lock()
+	ret, specificReturn := fake.listKubernetesResourcesByTaskIDReturnsOnCall[len(fake.listKubernetesResourcesByTaskIDArgsForCall)]
+	fake.listKubernetesResourcesByTaskIDArgsForCall = append(fake.listKubernetesResourcesByTaskIDArgsForCall, struct {
+		arg1 string
+	}{arg1})
+	fake.recordInvocation("ListKubernetesResourcesByTaskID", []interface{}{arg1})
+	fake.listKubernetesResourcesByTaskIDMutex.Unlock()
+	if fake.ListKubernetesResourcesByTaskIDStub != nil {
+		return fake.ListKubernetesResourcesByTaskIDStub(arg1)
+	}
+	if specificReturn {
+		return ret.result1, ret.result2
+	}
+	fakeReturns := fake.listKubernetesResourcesByTaskIDReturns
+	return fakeReturns.result1, fakeReturns.result2
+}
+
+func (fake *FakeClient) ListKubernetesResourcesByTaskIDCallCount() int {
+	fake.listKubernetesResourcesByTaskIDMutex.RLock()
+	defer fake.listKubernetesResourcesByTaskIDMutex.RUnlock()
+	return len(fake.listKubernetesResourcesByTaskIDArgsForCall)
+}
+
+func (fake *FakeClient) ListKubernetesResourcesByTaskIDCalls(stub func(string) ([]kubernetes.Resource, error)) {
+	fake.listKubernetesResourcesByTaskIDMutex.Lock()
+	defer fake.listKubernetesResourcesByTaskIDMutex.Unlock()
+	fake.ListKubernetesResourcesByTaskIDStub = stub
+}
+
+func (fake *FakeClient) ListKubernetesResourcesByTaskIDArgsForCall(i int) string {
+	fake.listKubernetesResourcesByTaskIDMutex.RLock()
+	defer fake.listKubernetesResourcesByTaskIDMutex.RUnlock()
+	argsForCall := fake.listKubernetesResourcesByTaskIDArgsForCall[i]
+	return argsForCall.arg1
+}
+
+func (fake *FakeClient) ListKubernetesResourcesByTaskIDReturns(result1 []kubernetes.Resource, result2 error) {
+	fake.listKubernetesResourcesByTaskIDMutex.Lock()
+	defer fake.listKubernetesResourcesByTaskIDMutex.Unlock()
+	fake.ListKubernetesResourcesByTaskIDStub = nil
+	fake.listKubernetesResourcesByTaskIDReturns = struct {
+		result1 []kubernetes.Resource
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeClient) ListKubernetesResourcesByTaskIDReturnsOnCall(i int, result1 []kubernetes.Resource, result2 error) {
+	fake.listKubernetesResourcesByTaskIDMutex.Lock()
+	defer fake.listKubernetesResourcesByTaskIDMutex.Unlock()
+	fake.ListKubernetesResourcesByTaskIDStub = nil
+	if fake.listKubernetesResourcesByTaskIDReturnsOnCall == nil {
+		fake.listKubernetesResourcesByTaskIDReturnsOnCall = make(map[int]struct {
+			result1 []kubernetes.Resource
+			result2 error
+		})
+	}
+	fake.listKubernetesResourcesByTaskIDReturnsOnCall[i] = struct {
+		result1 []kubernetes.Resource
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeClient) ListPermissionsByAccountNames(arg1 ...string) (map[string]kubernetes.ProviderPermissions, error) {
+	fake.listPermissionsByAccountNamesMutex.Lock()
+	ret, specificReturn := fake.listPermissionsByAccountNamesReturnsOnCall[len(fake.listPermissionsByAccountNamesArgsForCall)]
+	fake.listPermissionsByAccountNamesArgsForCall = append(fake.listPermissionsByAccountNamesArgsForCall, struct {
+		arg1 []string
+	}{arg1})
+	fake.recordInvocation("ListPermissionsByAccountNames", []interface{}{arg1})
+	fake.listPermissionsByAccountNamesMutex.Unlock()
+	if fake.ListPermissionsByAccountNamesStub != nil {
+		return fake.ListPermissionsByAccountNamesStub(arg1...)
+	}
+	if specificReturn {
+		return ret.result1, ret.result2
+	}
+	fakeReturns := fake.listPermissionsByAccountNamesReturns
+	return fakeReturns.result1, fakeReturns.result2
+}
+
+func (fake *FakeClient) ListPermissionsByAccountNamesCallCount() int {
+	fake.listPermissionsByAccountNamesMutex.RLock()
+	defer fake.listPermissionsByAccountNamesMutex.RUnlock()
+	return len(fake.listPermissionsByAccountNamesArgsForCall)
+}
+
+func (fake *FakeClient) ListPermissionsByAccountNamesCalls(stub func(...string) (map[string]kubernetes.ProviderPermissions, error)) {
+	fake.listPermissionsByAccountNamesMutex.Lock()
+	defer fake.listPermissionsByAccountNamesMutex.Unlock()
+	fake.ListPermissionsByAccountNamesStub = stub
+}
+
+func (fake *FakeClient) ListPermissionsByAccountNamesArgsForCall(i int) []string {
+	fake.listPermissionsByAccountNamesMutex.RLock()
+	defer fake.listPermissionsByAccountNamesMutex.RUnlock()
+	argsForCall := fake.listPermissionsByAccountNamesArgsForCall[i]
+	return argsForCall.arg1
+}
+
+func (fake *FakeClient) ListPermissionsByAccountNamesReturns(result1 map[string]kubernetes.ProviderPermissions, result2 error) {
+	fake.listPermissionsByAccountNamesMutex.Lock()
+	defer fake.listPermissionsByAccountNamesMutex.Unlock()
+	fake.ListPermissionsByAccountNamesStub = nil
+	fake.listPermissionsByAccountNamesReturns = struct {
+		result1 map[string]kubernetes.ProviderPermissions
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeClient) ListPermissionsByAccountNamesReturnsOnCall(i int, result1 map[string]kubernetes.ProviderPermissions, result2 error) {
+	fake.listPermissionsByAccountNamesMutex.Lock()
+	defer fake.listPermissionsByAccountNamesMutex.Unlock()
+	fake.ListPermissionsByAccountNamesStub = nil
+	if fake.listPermissionsByAccountNamesReturnsOnCall == nil {
+		fake.listPermissionsByAccountNamesReturnsOnCall = make(map[int]struct {
+			result1 map[string]kubernetes.ProviderPermissions
+			result2 error
+		})
+	}
+	fake.listPermissionsByAccountNamesReturnsOnCall[i] = struct {
+		result1 map[string]kubernetes.ProviderPermissions
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeClient) ListReadGroupsByAccountName(arg1 string) ([]string, error) {
+	fake.listReadGroupsByAccountNameMutex.Lock()
+	ret, specificReturn := fake.listReadGroupsByAccountNameReturnsOnCall[len(fake.listReadGroupsByAccountNameArgsForCall)]
+	fake.listReadGroupsByAccountNameArgsForCall = append(fake.listReadGroupsByAccountNameArgsForCall, struct {
+		arg1 string
+	}{arg1})
+	fake.recordInvocation("ListReadGroupsByAccountName", []interface{}{arg1})
+	fake.listReadGroupsByAccountNameMutex.Unlock()
+	if fake.ListReadGroupsByAccountNameStub != nil {
+		return fake.ListReadGroupsByAccountNameStub(arg1)
+	}
+	if specificReturn {
+		return ret.result1, ret.result2
+	}
+	fakeReturns := fake.listReadGroupsByAccountNameReturns
+	return fakeReturns.result1, fakeReturns.result2
+}
+
+func (fake *FakeClient) ListReadGroupsByAccountNameCallCount() int {
+	fake.listReadGroupsByAccountNameMutex.RLock()
+	defer fake.listReadGroupsByAccountNameMutex.RUnlock()
+	return len(fake.listReadGroupsByAccountNameArgsForCall)
+}
+
+func (fake *FakeClient) ListReadGroupsByAccountNameCalls(stub func(string) ([]string, error)) {
+	fake.listReadGroupsByAccountNameMutex.Lock()
+	defer fake.listReadGroupsByAccountNameMutex.Unlock()
+	fake.ListReadGroupsByAccountNameStub = stub
+}
+
+func (fake *FakeClient) ListReadGroupsByAccountNameArgsForCall(i int) string {
+	fake.listReadGroupsByAccountNameMutex.RLock()
+	defer fake.listReadGroupsByAccountNameMutex.RUnlock()
+	argsForCall := fake.listReadGroupsByAccountNameArgsForCall[i]
+	return argsForCall.arg1
+}
+
+func (fake *FakeClient) ListReadGroupsByAccountNameReturns(result1 []string, result2 error) {
+	fake.listReadGroupsByAccountNameMutex.Lock()
+	defer fake.listReadGroupsByAccountNameMutex.Unlock()
+	fake.ListReadGroupsByAccountNameStub = nil
+	fake.listReadGroupsByAccountNameReturns = struct {
+		result1 []string
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeClient) ListReadGroupsByAccountNameReturnsOnCall(i int, result1 []string, result2 error) {
+	fake.listReadGroupsByAccountNameMutex.Lock()
+	defer fake.listReadGroupsByAccountNameMutex.Unlock()
+	fake.ListReadGroupsByAccountNameStub = nil
+	if fake.listReadGroupsByAccountNameReturnsOnCall == nil {
+		fake.listReadGroupsByAccountNameReturnsOnCall = make(map[int]struct {
+			result1 []string
+			result2 error
+		})
+	}
+	fake.listReadGroupsByAccountNameReturnsOnCall[i] = struct {
+		result1 []string
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeClient) ListTaskEvents(arg1 string) ([]clouddriver.TaskEvent, error) {
+	fake.listTaskEventsMutex.Lock()
+	ret, specificReturn := fake.listTaskEventsReturnsOnCall[len(fake.listTaskEventsArgsForCall)]
+	fake.listTaskEventsArgsForCall = append(fake.listTaskEventsArgsForCall, struct {
+		arg1 string
+	}{arg1})
+	fake.recordInvocation("ListTaskEvents", []interface{}{arg1})
+	fake.listTaskEventsMutex.Unlock()
+	if fake.ListTaskEventsStub != nil {
+		return fake.ListTaskEventsStub(arg1)
+	}
+	if specificReturn {
+		return ret.result1, ret.result2
+	}
+	fakeReturns := fake.listTaskEventsReturns
+	return fakeReturns.result1, fakeReturns.result2
+}
+
+func (fake *FakeClient) ListTaskEventsCallCount() int {
+	fake.listTaskEventsMutex.RLock()
+	defer fake.listTaskEventsMutex.RUnlock()
+	return len(fake.listTaskEventsArgsForCall)
+}
+
+func (fake *FakeClient) ListTaskEventsCalls(stub func(string) ([]clouddriver.TaskEvent, error)) {
+	fake.listTaskEventsMutex.Lock()
+	defer fake.listTaskEventsMutex.Unlock()
+	fake.ListTaskEventsStub = stub
+}
+
+func (fake *FakeClient) ListTaskEventsArgsForCall(i int) string {
+	fake.listTaskEventsMutex.RLock()
+	defer fake.listTaskEventsMutex.RUnlock()
+	argsForCall := fake.listTaskEventsArgsForCall[i]
+	return argsForCall.arg1
+}
+
+func (fake *FakeClient) ListTaskEventsReturns(result1 []clouddriver.TaskEvent, result2 error) {
+	fake.listTaskEventsMutex.Lock()
+	defer fake.listTaskEventsMutex.Unlock()
+	fake.ListTaskEventsStub = nil
+	fake.listTaskEventsReturns = struct {
+		result1 []clouddriver.TaskEvent
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeClient) ListTaskEventsReturnsOnCall(i int, result1 []clouddriver.TaskEvent, result2 error) {
+	fake.listTaskEventsMutex.Lock()
+	defer fake.listTaskEventsMutex.Unlock()
+	fake.ListTaskEventsStub = nil
+	if fake.listTaskEventsReturnsOnCall == nil {
+		fake.listTaskEventsReturnsOnCall = make(map[int]struct {
+			result1 []clouddriver.TaskEvent
+			result2 error
+		})
+	}
+	fake.listTaskEventsReturnsOnCall[i] = struct {
+		result1 []clouddriver.TaskEvent
 		result2 error
 	}{result1, result2}
 }
 
-func (fake *FakeClient) ListKubernetesAccountsBySpinnakerApp(arg1 string) ([]string, error) {
-	fake.listKubernetesAccountsBySpinnakerAppMutex.Lock()
-	ret, specificReturn := fake.listKubernetesAccountsBySpinnakerAppReturnsOnCall[len(fake.listKubernetesAccountsBySpinnakerAppArgsForCall)]
-	fake.listKubernetesAccountsBySpinnakerAppArgsForCall = append(fake.listKubernetesAccountsBySpinnakerAppArgsForCall, struct {
+func (fake *FakeClient) ListWriteGroupsByAccountName(arg1 string) ([]string, error) {
+	fake.listWriteGroupsByAccountNameMutex.Lock()
+	ret, specificReturn := fake.listWriteGroupsByAccountNameReturnsOnCall[len(fake.listWriteGroupsByAccountNameArgsForCall)]
+	fake.listWriteGroupsByAccountNameArgsForCall = append(fake.listWriteGroupsByAccountNameArgsForCall, struct {
 		arg1 string
 	}{arg1})
-	fake.recordInvocation("ListKubernetesAccountsBySpinnakerApp", []interface{}{arg1})
-	fake.listKubernetesAccountsBySpinnakerAppMutex.Unlock()
-	if fake.ListKubernetesAccountsBySpinnakerAppStub != nil {
-		return fake.ListKubernetesAccountsBySpinnakerAppStub(arg1)
+	fake.recordInvocation("ListWriteGroupsByAccountName", []interface{}{arg1})
+	fake.listWriteGroupsByAccountNameMutex.Unlock()
+	if fake.ListWriteGroupsByAccountNameStub != nil {
+		return fake.ListWriteGroupsByAccountNameStub(arg1)
 	}
 	if specificReturn {
 		return ret.result1, ret.result2
 	}
-	fakeReturns := fake.listKubernetesAccountsBySpinnakerAppReturns
+	fakeReturns := fake.listWriteGroupsByAccountNameReturns
 	return fakeReturns.result1, fakeReturns.result2
 }
 
-func (fake *FakeClient) ListKubernetesAccountsBySpinnakerAppCallCount() int {
-	fake.listKubernetesAccountsBySpinnakerAppMutex.RLock()
-	defer fake.listKubernetesAccountsBySpinnakerAppMutex.RUnlock()
-	return len(fake.listKubernetesAccountsBySpinnakerAppArgsForCall)
+func (fake *FakeClient) ListWriteGroupsByAccountNameCallCount() int {
+	fake.listWriteGroupsByAccountNameMutex.RLock()
+	defer fake.listWriteGroupsByAccountNameMutex.RUnlock()
+	return len(fake.listWriteGroupsByAccountNameArgsForCall)
 }
 
-func (fake *FakeClient) ListKubernetesAccountsBySpinnakerAppCalls(stub func(string) ([]string, error)) {
-	fake.listKubernetesAccountsBySpinnakerAppMutex.Lock()
-	defer fake.listKubernetesAccountsBySpinnakerAppMutex.Unlock()
-	fake.ListKubernetesAccountsBySpinnakerAppStub = stub
+func (fake *FakeClient) ListWriteGroupsByAccountNameCalls(stub func(string) ([]string, error)) {
+	fake.listWriteGroupsByAccountNameMutex.Lock()
+	defer fake.listWriteGroupsByAccountNameMutex.Unlock()
+	fake.ListWriteGroupsByAccountNameStub = stub
 }
 
-func (fake *FakeClient) ListKubernetesAccountsBySpinnakerAppArgsForCall(i int) string {
-	fake.listKubernetesAccountsBySpinnakerAppMutex.RLock()
-	defer fake.listKubernetesAccountsBySpinnakerAppMutex.RUnlock()
-	argsForCall := fake.listKubernetesAccountsBySpinnakerAppArgsForCall[i]
+func (fake *FakeClient) ListWriteGroupsByAccountNameArgsForCall(i int) string {
+	fake.listWriteGroupsByAccountNameMutex.RLock()
+	defer fake.listWriteGroupsByAccountNameMutex.RUnlock()
+	argsForCall := fake.listWriteGroupsByAccountNameArgsForCall[i]
 	return argsForCall.arg1
 }
 
-func (fake *FakeClient) ListKubernetesAccountsBySpinnakerAppReturns(result1 []string, result2 error) {
-	fake.listKubernetesAccountsBySpinnakerAppMutex.Lock()
-	defer fake.listKubernetesAccountsBySpinnakerAppMutex.Unlock()
-	fake.ListKubernetesAccountsBySpinnakerAppStub = nil
-	fake.listKubernetesAccountsBySpinnakerAppReturns = struct {
+func (fake *FakeClient) ListWriteGroupsByAccountNameReturns(result1 []string, result2 error) {
+	fake.listWriteGroupsByAccountNameMutex.Lock()
+	defer fake.listWriteGroupsByAccountNameMutex.Unlock()
+	fake.ListWriteGroupsByAccountNameStub = nil
+	fake.listWriteGroupsByAccountNameReturns = struct {
 		result1 []string
 		result2 error
 	}{result1, result2}
 }
 
-func (fake *FakeClient) ListKubernetesAccountsBySpinnakerAppReturnsOnCall(i int, result1 []string, result2 error) {
-	fake.listKubernetesAccountsBySpinnakerAppMutex.Lock()
-	defer fake.listKubernetesAccountsBySpinnakerAppMutex.Unlock()
-	fake.ListKubernetesAccountsBySpinnakerAppStub = nil
-	if fake.listKubernetesAccountsBySpinnakerAppReturnsOnCall == nil {
-		fake.listKubernetesAccountsBySpinnakerAppReturnsOnCall = make(map[int]struct {
+func (fake *FakeClient) ListWriteGroupsByAccountNameReturnsOnCall(i int, result1 []string, result2 error) {
+	fake.listWriteGroupsByAccountNameMutex.Lock()
+	defer fake.listWriteGroupsByAccountNameMutex.Unlock()
+	fake.ListWriteGroupsByAccountNameStub = nil
+	if fake.listWriteGroupsByAccountNameReturnsOnCall == nil {
+		fake.listWriteGroupsByAccountNameReturnsOnCall = make(map[int]struct {
 			result1 []string
 			result2 error
 		})
 	}
-	fake.listKubernetesAccountsBySpinnakerAppReturnsOnCall[i] = struct {
+	fake.listWriteGroupsByAccountNameReturnsOnCall[i] = struct {
 		result1 []string
 		result2 error
 	}{result1, result2}
 }
 
-func (fake *FakeClient) ListKubernetesClustersByApplication(arg1 string) ([]kubernetes.Resource, error) {
-	fake.listKubernetesClustersByApplicationMutex.Lock()
-	ret, specificReturn := fake.listKubernetesClustersByApplicationReturnsOnCall[len(fake.listKubernetesClustersByApplicationArgsForCall)]
-	fake.listKubernetesClustersByApplicationArgsForCall = append(fake.listKubernetesClustersByApplicationArgsForCall, struct {
+func (fake *FakeClient) ReleaseClusterLocks(arg1 string, arg2 []string) error {
+	var arg2Copy []string
+	if arg2 != nil {
+		arg2Copy = make([]string, len(arg2))
+		copy(arg2Copy, arg2)
+	}
+	fake.releaseClusterLocksMutex.Lock()
+	ret, specificReturn := fake.releaseClusterLocksReturnsOnCall[len(fake.releaseClusterLocksArgsForCall)]
+	fake.releaseClusterLocksArgsForCall = append(fake.releaseClusterLocksArgsForCall, struct {
 		arg1 string
-	}{arg1})
-	fake.recordInvocation("ListKubernetesClustersByApplication", []interface{}{arg1})
-	fake.listKubernetesClustersByApplicationMutex.Unlock()
-	if fake.ListKubernetesClustersByApplicationStub != nil {
-		return fake.ListKubernetesClustersByApplicationStub(arg1)
+		arg2 []string
+	}{arg1, arg2Copy})
+	fake.recordInvocation("ReleaseClusterLocks", []interface{}{arg1, arg2Copy})
+	fake.releaseClusterLocksMutex.Unlock()
+	if fake.ReleaseClusterLocksStub != nil {
+		return fake.ReleaseClusterLocksStub(arg1, arg2)
 	}
 	if specificReturn {
-		return ret.result1, ret.result2
+		return ret.result1
 	}
-	fakeReturns := fake.listKubernetesClustersByApplicationReturns
-	return fakeReturns.result1, fakeReturns.result2
+	fakeReturns := fake.releaseClusterLocksReturns
+	return fakeReturns.result1
 }
 
-func (fake *FakeClient) ListKubernetesClustersByApplicationCallCount() int {
-	fake.listKubernetesClustersByApplicationMutex.RLock()
-	defer fake.listKubernetesClustersByApplicationMutex.RUnlock()
-	return len(fake.listKubernetesClustersByApplicationArgsForCall)
+func (fake *FakeClient) ReleaseClusterLocksCallCount() int {
+	fake.releaseClusterLocksMutex.RLock()
+	defer fake.releaseClusterLocksMutex.RUnlock()
+	return len(fake.releaseClusterLocksArgsForCall)
 }
 
-func (fake *FakeClient) ListKubernetesClustersByApplicationCalls(stub func(string) ([]kubernetes.Resource, error)) {
-	fake.listKubernetesClustersByApplicationMutex.Lock()
-	defer fake.listKubernetesClustersByApplicationMutex.Unlock()
-	fake.ListKubernetesClustersByApplicationStub = stub
+func (fake *FakeClient) ReleaseClusterLocksCalls(stub func(string, []string) error) {
+	fake.releaseClusterLocksMutex.Lock()
+	defer fake.releaseClusterLocksMutex.Unlock()
+	fake.ReleaseClusterLocksStub = stub
 }
 
-func (fake *FakeClient) ListKubernetesClustersByApplicationArgsForCall(i int) string {
-	fake.listKubernetesClustersByApplicationMutex.RLock()
-	defer fake.listKubernetesClustersByApplicationMutex.RUnlock()
-	argsForCall := fake.listKubernetesClustersByApplicationArgsForCall[i]
-	return argsForCall.arg1
+func (fake *FakeClient) ReleaseClusterLocksArgsForCall(i int) (string, []string) {
+	fake.releaseClusterLocksMutex.RLock()
+	defer fake.releaseClusterLocksMutex.RUnlock()
+	argsForCall := fake.releaseClusterLocksArgsForCall[i]
+	return argsForCall.arg1, argsForCall.arg2
 }
 
-func (fake *FakeClient) ListKubernetesClustersByApplicationReturns(result1 []kubernetes.Resource, result2 error) {
-	fake.listKubernetesClustersByApplicationMutex.Lock()
-	defer fake.listKubernetesClustersByApplicationMutex.Unlock()
-	fake.ListKubernetesClustersByApplicationStub = nil
-	fake.listKubernetesClustersByApplicationReturns = struct {
-		result1 []kubernetes.Resource
-		result2 error
-	}{result1, result2}
+func (fake *FakeClient) ReleaseClusterLocksReturns(result1 error) {
+	fake.releaseClusterLocksMutex.Lock()
+	defer fake.releaseClusterLocksMutex.Unlock()
+	fake.ReleaseClusterLocksStub = nil
+	fake.releaseClusterLocksReturns = struct {
+		result1 error
+	}{result1}
 }
 
-func (fake *FakeClient) ListKubernetesClustersByApplicationReturnsOnCall(i int, result1 []kubernetes.Resource, result2 error) {
-	fake.listKubernetesClustersByApplicationMutex.Lock()
-	defer fake.listKubernetesClustersByApplicationMutex.Unlock()
-	fake.ListKubernetesClustersByApplicationStub = nil
-	if fake.listKubernetesClustersByApplicationReturnsOnCall == nil {
-		fake.listKubernetesClustersByApplicationReturnsOnCall = make(map[int]struct {
-			result1 []kubernetes.Resource
-			result2 error
+func (fake *FakeClient) ReleaseClusterLocksReturnsOnCall(i int, result1 error) {
+	fake.releaseClusterLocksMutex.Lock()
+	defer fake.releaseClusterLocksMutex.Unlock()
+	fake.ReleaseClusterLocksStub = nil
+	if fake.releaseClusterLocksReturnsOnCall == nil {
+		fake.releaseClusterLocksReturnsOnCall = make(map[int]struct {
+			result1 error
 		})
 	}
-	fake.listKubernetesClustersByApplicationReturnsOnCall[i] = struct {
-		result1 []kubernetes.Resource
-		result2 error
-	}{result1, result2}
+	fake.releaseClusterLocksReturnsOnCall[i] = struct {
+		result1 error
+	}{result1}
 }
 
-func (fake *FakeClient) ListKubernetesProviders() ([]kubernetes.Provider, error) {
-	fake.listKubernetesProvidersMutex.Lock()
-	ret, specificReturn := fake.listKubernetesProvidersReturnsOnCall[len(fake.listKubernetesProvidersArgsForCall)]
-	fake.listKubernetesProvidersArgsForCall = append(fake.listKubernetesProvidersArgsForCall, struct {
-	}{})
-	fake.recordInvocation("ListKubernetesProviders", []interface{}{})
-	fake.listKubernetesProvidersMutex.Unlock()
-	if fake.ListKubernetesProvidersStub != nil {
-		return fake.ListKubernetesProvidersStub()
+func (fake *FakeClient) RenewClusterLocks(arg1 string, arg2 []string, arg3 time.Time) error {
+	var arg2Copy []string
+	if arg2 != nil {
+		arg2Copy = make([]string, len(arg2))
+		copy(arg2Copy, arg2)
+	}
+	fake.renewClusterLocksMutex.Lock()
+	ret, specificReturn := fake.renewClusterLocksReturnsOnCall[len(fake.renewClusterLocksArgsForCall)]
+	fake.renewClusterLocksArgsForCall = append(fake.renewClusterLocksArgsForCall, struct {
+		arg1 string
+		arg2 []string
+		arg3 time.Time
+	}{arg1, arg2Copy, arg3})
+	fake.recordInvocation("RenewClusterLocks", []interface{}{arg1, arg2Copy, arg3})
+	fake.renewClusterLocksMutex.Unlock()
+	if fake.RenewClusterLocksStub != nil {
+		return fake.RenewClusterLocksStub(arg1, arg2, arg3)
 	}
 	if specificReturn {
-		return ret.result1, ret.result2
+		return ret.result1
 	}
-	fakeReturns := fake.listKubernetesProvidersReturns
-	return fakeReturns.result1, fakeReturns.result2
+	fakeReturns := fake.renewClusterLocksReturns
+	return fakeReturns.result1
 }
 
-func (fake *FakeClient) ListKubernetesProvidersCallCount() int {
-	fake.listKubernetesProvidersMutex.RLock()
-	defer fake.listKubernetesProvidersMutex.RUnlock()
-	return len(fake.listKubernetesProvidersArgsForCall)
+func (fake *FakeClient) RenewClusterLocksCallCount() int {
+	fake.renewClusterLocksMutex.RLock()
+	defer fake.renewClusterLocksMutex.RUnlock()
+	return len(fake.renewClusterLocksArgsForCall)
 }
 
-func (fake *FakeClient) ListKubernetesProvidersCalls(stub func() ([]kubernetes.Provider, error)) {
-	fake.listKubernetesProvidersMutex.Lock()
-	defer fake.listKubernetesProvidersMutex.Unlock()
-	fake.ListKubernetesProvidersStub = stub
+func (fake *FakeClient) RenewClusterLocksCalls(stub func(string, []string, time.Time) error) {
+	fake.renewClusterLocksMutex.Lock()
+	defer fake.renewClusterLocksMutex.Unlock()
+	fake.RenewClusterLocksStub = stub
 }
 
-func (fake *FakeClient) ListKubernetesProvidersReturns(result1 []kubernetes.Provider, result2 error) {
-	fake.listKubernetesProvidersMutex.Lock()
-	defer fake.listKubernetesProvidersMutex.Unlock()
-	fake.ListKubernetesProvidersStub = nil
-	fake.listKubernetesProvidersReturns = struct {
-		result1 []kubernetes.Provider
-		result2 error
-	}{result1, result2}
+func (fake *FakeClient) RenewClusterLocksArgsForCall(i int) (string, []string, time.Time) {
+	fake.renewClusterLocksMutex.RLock()
+	defer fake.renewClusterLocksMutex.RUnlock()
+	argsForCall := fake.renewClusterLocksArgsForCall[i]
+	return argsForCall.arg1, argsForCall.arg2, argsForCall.arg3
 }
 
-func (fake *FakeClient) ListKubernetesProvidersReturnsOnCall(i int, result1 []kubernetes.Provider, result2 error) {
-	fake.listKubernetesProvidersMutex.Lock()
-	defer fake.listKubernetesProvidersMutex.Unlock()
-	fake.ListKubernetesProvidersStub = nil
-	if fake.listKubernetesProvidersReturnsOnCall == nil {
-		fake.listKubernetesProvidersReturnsOnCall = make(map[int]struct {
-			result1 []kubernetes.Provider
-			result2 error
+func (fake *FakeClient) RenewClusterLocksReturns(result1 error) {
+	fake.renewClusterLocksMutex.Lock()
+	defer fake.renewClusterLocksMutex.Unlock()
+	fake.RenewClusterLocksStub = nil
+	fake.renewClusterLocksReturns = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *FakeClient) RenewClusterLocksReturnsOnCall(i int, result1 error) {
+	fake.renewClusterLocksMutex.Lock()
+	defer fake.renewClusterLocksMutex.Unlock()
+	fake.RenewClusterLocksStub = nil
+	if fake.renewClusterLocksReturnsOnCall == nil {
+		fake.renewClusterLocksReturnsOnCall = make(map[int]struct {
+			result1 error
 		})
 	}
-	fake.listKubernetesProvidersReturnsOnCall[i] = struct {
-		result1 []kubernetes.Provider
-		result2 error
-	}{result1, result2}
+	fake.renewClusterLocksReturnsOnCall[i] = struct {
+		result1 error
+	}{result1}
 }
 
-func (fake *FakeClient) ListKubernetesProvidersAndPermissions() ([]kubernetes.Provider, error) {
-	fake.listKubernetesProvidersAndPermissionsMutex.Lock()
-	ret, specificReturn := fake.listKubernetesProvidersAndPermissionsReturnsOnCall[len(fake.listKubernetesProvidersAndPermissionsArgsForCall)]
-	fake.listKubernetesProvidersAndPermissionsArgsForCall = append(fake.listKubernetesProvidersAndPermissionsArgsForCall, struct {
-	}{})
-	fake.recordInvocation("ListKubernetesProvidersAndPermissions", []interface{}{})
-	fake.listKubernetesProvidersAndPermissionsMutex.Unlock()
-	if fake.ListKubernetesProvidersAndPermissionsStub != nil {
-		return fake.ListKubernetesProvidersAndPermissionsStub()
+func (fake *FakeClient) RotateKubernetesProviderCredentials(arg1 string, arg2 string, arg3 string) error {
+	fake.rotateKubernetesProviderCredentialsMutex.Lock()
+	ret, specificReturn := fake.rotateKubernetesProviderCredentialsReturnsOnCall[len(fake.rotateKubernetesProviderCredentialsArgsForCall)]
+	fake.rotateKubernetesProviderCredentialsArgsForCall = append(fake.rotateKubernetesProviderCredentialsArgsForCall, struct {
+		arg1 string
+		arg2 string
+		arg3 string
+	}{arg1, arg2, arg3})
+	fake.recordInvocation("RotateKubernetesProviderCredentials", []interface{}{arg1, arg2, arg3})
+	fake.rotateKubernetesProviderCredentialsMutex.Unlock()
+	if fake.RotateKubernetesProviderCredentialsStub != nil {
+		return fake.RotateKubernetesProviderCredentialsStub(arg1, arg2, arg3)
 	}
 	if specificReturn {
-		return ret.result1, ret.result2
+		return ret.result1
 	}
-	fakeReturns := fake.listKubernetesProvidersAndPermissionsReturns
-	return fakeReturns.result1, fakeReturns.result2
+	fakeReturns := fake.rotateKubernetesProviderCredentialsReturns
+	return fakeReturns.result1
 }
 
-func (fake *FakeClient) ListKubernetesProvidersAndPermissionsCallCount() int {
-	fake.listKubernetesProvidersAndPermissionsMutex.RLock()
-	defer fake.listKubernetesProvidersAndPermissionsMutex.RUnlock()
-	return len(fake.listKubernetesProvidersAndPermissionsArgsForCall)
+func (fake *FakeClient) RotateKubernetesProviderCredentialsCallCount() int {
+	fake.rotateKubernetesProviderCredentialsMutex.RLock()
+	defer fake.rotateKubernetesProviderCredentialsMutex.RUnlock()
+	return len(fake.rotateKubernetesProviderCredentialsArgsForCall)
 }
 
-func (fake *FakeClient) ListKubernetesProvidersAndPermissionsCalls(stub func() ([]kubernetes.Provider, error)) {
-	fake.listKubernetesProvidersAndPermissionsMutex.Lock()
-	defer fake.listKubernetesProvidersAndPermissionsMutex.Unlock()
-	fake.ListKubernetesProvidersAndPermissionsStub = stub
+func (fake *FakeClient) RotateKubernetesProviderCredentialsCalls(stub func(string, string, string) error) {
+	fake.rotateKubernetesProviderCredentialsMutex.Lock()
+	defer fake.rotateKubernetesProviderCredentialsMutex.Unlock()
+	fake.RotateKubernetesProviderCredentialsStub = stub
 }
 
-func (fake *FakeClient) ListKubernetesProvidersAndPermissionsReturns(result1 []kubernetes.Provider, result2 error) {
-	fake.listKubernetesProvidersAndPermissionsMutex.Lock()
-	defer fake.listKubernetesProvidersAndPermissionsMutex.Unlock()
-	fake.ListKubernetesProvidersAndPermissionsStub = nil
-	fake.listKubernetesProvidersAndPermissionsReturns = struct {
-		result1 []kubernetes.Provider
-		result2 error
-	}{result1, result2}
+func (fake *FakeClient) RotateKubernetesProviderCredentialsArgsForCall(i int) (string, string, string) {
+	fake.rotateKubernetesProviderCredentialsMutex.RLock()
+	defer fake.rotateKubernetesProviderCredentialsMutex.RUnlock()
+	argsForCall := fake.rotateKubernetesProviderCredentialsArgsForCall[i]
+	return argsForCall.arg1, argsForCall.arg2, argsForCall.arg3
 }
 
-func (fake *FakeClient) ListKubernetesProvidersAndPermissionsReturnsOnCall(i int, result1 []kubernetes.Provider, result2 error) {
-	fake.listKubernetesProvidersAndPermissionsMutex.Lock()
-	defer fake.listKubernetesProvidersAndPermissionsMutex.Unlock()
-	fake.ListKubernetesProvidersAndPermissionsStub = nil
-	if fake.listKubernetesProvidersAndPermissionsReturnsOnCall == nil {
-		fake.listKubernetesProvidersAndPermissionsReturnsOnCall = make(map[int]struct {
-			result1 []kubernetes.Provider
-			result2 error
+func (fake *FakeClient) RotateKubernetesProviderCredentialsReturns(result1 error) {
+	fake.rotateKubernetesProviderCredentialsMutex.Lock()
+	defer fake.rotateKubernetesProviderCredentialsMutex.Unlock()
+	fake.RotateKubernetesProviderCredentialsStub = nil
+	fake.rotateKubernetesProviderCredentialsReturns = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *FakeClient) RotateKubernetesProviderCredentialsReturnsOnCall(i int, result1 error) {
+	fake.rotateKubernetesProviderCredentialsMutex.Lock()
+	defer fake.rotateKubernetesProviderCredentialsMutex.Unlock()
+	fake.RotateKubernetesProviderCredentialsStub = nil
+	if fake.rotateKubernetesProviderCredentialsReturnsOnCall == nil {
+		fake.rotateKubernetesProviderCredentialsReturnsOnCall = make(map[int]struct {
+			result1 error
 		})
 	}
-	fake.listKubernetesProvidersAndPermissionsReturnsOnCall[i] = struct {
-		result1 []kubernetes.Provider
-		result2 error
-	}{result1, result2}
+	fake.rotateKubernetesProviderCredentialsReturnsOnCall[i] = struct {
+		result1 error
+	}{result1}
 }
 
-func (fake *FakeClient) ListKubernetesResourceNamesByAccountNameAndKindAndNamespace(arg1 string, arg2 string, arg3 string) ([]string, error) {
-	fake.listKubernetesResourceNamesByAccountNameAndKindAndNamespaceMutex.Lock()
-	ret, specificReturn := fake.listKubernetesResourceNamesByAccountNameAndKindAndNamespaceReturnsOnCall[len(fake.listKubernetesResourceNamesByAccountNameAndKindAndNamespaceArgsForCall)]
-	fake.listKubernetesResourceNamesByAccountNameAndKindAndNamespaceArgsForCall = append(fake.listKubernetesResourceNamesByAccountNameAndKindAndNamespaceArgsForCall, struct {
+func (fake *FakeClient) SetDeployPhase(arg1 string, arg2 string, arg3 time.Time) error {
+	fake.setDeployPhaseMutex.Lock()
+	ret, specificReturn := fake.setDeployPhaseReturnsOnCall[len(fake.setDeployPhaseArgsForCall)]
+	fake.setDeployPhaseArgsForCall = append(fake.setDeployPhaseArgsForCall, struct {
 		arg1 string
 		arg2 string
-		arg3 string
+		arg3 time.Time
 	}{arg1, arg2, arg3})
-	fake.recordInvocation("ListKubernetesResourceNamesByAccountNameAndKindAndNamespace", []interface{}{arg1, arg2, arg3})
-	fake.listKubernetesResourceNamesByAccountNameAndKindAndNamespaceMutex.Unlock()
-	if fake.ListKubernetesResourceNamesByAccountNameAndKindAndNamespaceStub != nil {
-		return fake.ListKubernetesResourceNamesByAccountNameAndKindAndNamespaceStub(arg1, arg2, arg3)
+	fake.recordInvocation("SetDeployPhase", []interface{}{arg1, arg2, arg3})
+	fake.setDeployPhaseMutex.Unlock()
+	if fake.SetDeployPhaseStub != nil {
+		return fake.SetDeployPhaseStub(arg1, arg2, arg3)
+	}
+	if specificReturn {
+		return ret.result1
+	}
+	fakeReturns := fake.setDeployPhaseReturns
+	return fakeReturns.result1
+}
+
+func (fake *FakeClient) SetDeployPhaseCallCount() int {
+	fake.setDeployPhaseMutex.RLock()
+	defer fake.setDeployPhaseMutex.RUnlock()
+	return len(fake.setDeployPhaseArgsForCall)
+}
+
+func (fake *FakeClient) SetDeployPhaseCalls(stub func(string, string, time.Time) error) {
+	fake.setDeployPhaseMutex.Lock()
+	defer fake.setDeployPhaseMutex.Unlock()
+	fake.SetDeployPhaseStub = stub
+}
+
+func (fake *FakeClient) SetDeployPhaseArgsForCall(i int) (string, string, time.Time) {
+	fake.setDeployPhaseMutex.RLock()
+	defer fake.setDeployPhaseMutex.RUnlock()
+	argsForCall := fake.setDeployPhaseArgsForCall[i]
+	return argsForCall.arg1, argsForCall.arg2, argsForCall.arg3
+}
+
+func (fake *FakeClient) SetDeployPhaseReturns(result1 error) {
+	fake.setDeployPhaseMutex.Lock()
+	defer fake.setDeployPhaseMutex.Unlock()
+	fake.SetDeployPhaseStub = nil
+	fake.setDeployPhaseReturns = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *FakeClient) SetDeployPhaseReturnsOnCall(i int, result1 error) {
+	fake.setDeployPhaseMutex.Lock()
+	defer fake.setDeployPhaseMutex.Unlock()
+	fake.SetDeployPhaseStub = nil
+	if fake.setDeployPhaseReturnsOnCall == nil {
+		fake.setDeployPhaseReturnsOnCall = make(map[int]struct {
+			result1 error
+		})
+	}
+	fake.setDeployPhaseReturnsOnCall[i] = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *FakeClient) SetFeature(arg1 clouddriver.Feature) error {
+	fake.setFeatureMutex.Lock()
+	ret, specificReturn := fake.setFeatureReturnsOnCall[len(fake.setFeatureArgsForCall)]
+	fake.setFeatureArgsForCall = append(fake.setFeatureArgsForCall, struct {
+		arg1 clouddriver.Feature
+	}{arg1})
+	fake.recordInvocation("SetFeature", []interface{}{arg1})
+	fake.setFeatureMutex.Unlock()
+	if fake.SetFeatureStub != nil {
+		return fake.SetFeatureStub(arg1)
 	}
 	if specificReturn {
-		return ret.result1, ret.result2
+		return ret.result1
 	}
-	fakeReturns := fake.listKubernetesResourceNamesByAccountNameAndKindAndNamespaceReturns
-	return fakeReturns.result1, fakeReturns.result2
+	fakeReturns := fake.setFeatureReturns
+	return fakeReturns.result1
 }
 
-func (fake *FakeClient) ListKubernetesResourceNamesByAccountNameAndKindAndNamespaceCallCount() int {
-	fake.listKubernetesResourceNamesByAccountNameAndKindAndNamespaceMutex.RLock()
-	defer fake.listKubernetesResourceNamesByAccountNameAndKindAndNamespaceMutex.RUnlock()
-	return len(fake.listKubernetesResourceNamesByAccountNameAndKindAndNamespaceArgsForCall)
+func (fake *FakeClient) SetFeatureCallCount() int {
+	fake.setFeatureMutex.RLock()
+	defer fake.setFeatureMutex.RUnlock()
+	return len(fake.setFeatureArgsForCall)
 }
 
-func (fake *FakeClient) ListKubernetesResourceNamesByAccountNameAndKindAndNamespaceCalls(stub func(string, string, string) ([]string, error)) {
-	fake.listKubernetesResourceNamesByAccountNameAndKindAndNamespaceMutex.Lock()
-	defer fake.listKubernetesResourceNamesByAccountNameAndKindAndNamespaceMutex.Unlock()
-	fake.ListKubernetesResourceNamesByAccountNameAndKindAndNamespaceStub = stub
+func (fake *FakeClient) SetFeatureCalls(stub func(clouddriver.Feature) error) {
+	fake.setFeatureMutex.Lock()
+	defer fake.setFeatureMutex.Unlock()
+	fake.SetFeatureStub = stub
 }
 
-func (fake *FakeClient) ListKubernetesResourceNamesByAccountNameAndKindAndNamespaceArgsForCall(i int) (string, string, string) {
-	fake.listKubernetesResourceNamesByAccountNameAndKindAndNamespaceMutex.RLock()
-	defer fake.listKubernetesResourceNamesByAccountNameAndKindAndNamespaceMutex.RUnlock()
-	argsForCall := fake.listKubernetesResourceNamesByAccountNameAndKindAndNamespaceArgsForCall[i]
-	return argsForCall.arg1, argsForCall.arg2, argsForCall.arg3
+func (fake *FakeClient) SetFeatureArgsForCall(i int) clouddriver.Feature {
+	fake.setFeatureMutex.RLock()
+	defer fake.setFeatureMutex.RUnlock()
+	argsForCall := fake.setFeatureArgsForCall[i]
+	return argsForCall.arg1
 }
 
-func (fake *FakeClient) ListKubernetesResourceNamesByAccountNameAndKindAndNamespaceReturns(result1 []string, result2 error) {
-	fake.listKubernetesResourceNamesByAccountNameAndKindAndNamespaceMutex.Lock()
-	defer fake.listKubernetesResourceNamesByAccountNameAndKindAndNamespaceMutex.Unlock()
-	fake.ListKubernetesResourceNamesByAccountNameAndKindAndNamespaceStub = nil
-	fake.listKubernetesResourceNamesByAccountNameAndKindAndNamespaceReturns = struct {
-		result1 []string
-		result2 error
-	}{result1, result2}
+func (fake *FakeClient) SetFeatureReturns(result1 error) {
+	fake.setFeatureMutex.Lock()
+	defer fake.setFeatureMutex.Unlock()
+	fake.SetFeatureStub = nil
+	fake.setFeatureReturns = struct {
+		result1 error
+	}{result1}
 }
 
-func (fake *FakeClient) ListKubernetesResourceNamesByAccountNameAndKindAndNamespaceReturnsOnCall(i int, result1 []string, result2 error) {
-	fake.listKubernetesResourceNamesByAccountNameAndKindAndNamespaceMutex.Lock()
-	defer fake.listKubernetesResourceNamesByAccountNameAndKindAndNamespaceMutex.Unlock()
-	fake.ListKubernetesResourceNamesByAccountNameAndKindAndNamespaceStub = nil
-	if fake.listKubernetesResourceNamesByAccountNameAndKindAndNamespaceReturnsOnCall == nil {
-		fake.listKubernetesResourceNamesByAccountNameAndKindAndNamespaceReturnsOnCall = make(map[int]struct {
-			result1 []string
-			result2 error
+func (fake *FakeClient) SetFeatureReturnsOnCall(i int, result1 error) {
+	fake.setFeatureMutex.Lock()
+	defer fake.setFeatureMutex.Unlock()
+	fake.SetFeatureStub = nil
+	if fake.setFeatureReturnsOnCall == nil {
+		fake.setFeatureReturnsOnCall = make(map[int]struct {
+			result1 error
 		})
 	}
-	fake.listKubernetesResourceNamesByAccountNameAndKindAndNamespaceReturnsOnCall[i] = struct {
-		result1 []string
-		result2 error
-	}{result1, result2}
+	fake.setFeatureReturnsOnCall[i] = struct {
+		result1 error
+	}{result1}
 }
 
-func (fake *FakeClient) ListKubernetesResourcesByFields(arg1 ...string) ([]kubernetes.Resource, error) {
-	fake.listKubernetesResourcesByFieldsMutex.Lock()
-	ret, specificReturn := fake.listKubernetesResourcesByFieldsReturnsOnCall[len(fake.listKubernetesResourcesByFieldsArgsForCall)]
-	fake.listKubernetesResourcesByFieldsArgsForCall = append(fake.listKubernetesResourcesByFieldsArgsForCall, struct {
-		arg1 []string
+func (fake *FakeClient) SetKindMapping(arg1 kubernetes.KindMapping) error {
+	fake.setKindMappingMutex.Lock()
+	ret, specificReturn := fake.setKindMappingReturnsOnCall[len(fake.setKindMappingArgsForCall)]
+	fake.setKindMappingArgsForCall = append(fake.setKindMappingArgsForCall, struct {
+		arg1 kubernetes.KindMapping
 	}{arg1})
-	fake.recordInvocation("ListKubernetesResourcesByFields", []interface{}{arg1})
-	fake.listKubernetesResourcesByFieldsMutex.Unlock()
-	if fake.ListKubernetesResourcesByFieldsStub != nil {
-		return fake.ListKubernetesResourcesByFieldsStub(arg1...)
+	fake.recordInvocation("SetKindMapping", []interface{}{arg1})
+	fake.setKindMappingMutex.Unlock()
+	if fake.SetKindMappingStub != nil {
+		return fake.SetKindMappingStub(arg1)
 	}
 	if specificReturn {
-		return ret.result1, ret.result2
+		return ret.result1
 	}
-	fakeReturns := fake.listKubernetesResourcesByFieldsReturns
-	return fakeReturns.result1, fakeReturns.result2
+	fakeReturns := fake.setKindMappingReturns
+	return fakeReturns.result1
 }
 
-func (fake *FakeClient) ListKubernetesResourcesByFieldsCallCount() int {
-	fake.listKubernetesResourcesByFieldsMutex.RLock()
-	defer fake.listKubernetesResourcesByFieldsMutex.RUnlock()
-	return len(fake.listKubernetesResourcesByFieldsArgsForCall)
+func (fake *FakeClient) SetKindMappingCallCount() int {
+	fake.setKindMappingMutex.RLock()
+	defer fake.setKindMappingMutex.RUnlock()
+	return len(fake.setKindMappingArgsForCall)
 }
 
-func (fake *FakeClient) ListKubernetesResourcesByFieldsCalls(stub func(...string) ([]kubernetes.Resource, error)) {
-	fake.listKubernetesResourcesByFieldsMutex.Lock()
-	defer fake.listKubernetesResourcesByFieldsMutex.Unlock()
-	fake.ListKubernetesResourcesByFieldsStub = stub
+func (fake *FakeClient) SetKindMappingCalls(stub func(kubernetes.KindMapping) error) {
+	fake.setKindMappingMutex.Lock()
+	defer fake.setKindMappingMutex.Unlock()
+	fake.SetKindMappingStub = stub
 }
 
-func (fake *FakeClient) ListKubernetesResourcesByFieldsArgsForCall(i int) []string {
-	fake.listKubernetesResourcesByFieldsMutex.RLock()
-	defer fake.listKubernetesResourcesByFieldsMutex.RUnlock()
-	argsForCall := fake.listKubernetesResourcesByFieldsArgsForCall[i]
+func (fake *FakeClient) SetKindMappingArgsForCall(i int) kubernetes.KindMapping {
+	fake.setKindMappingMutex.RLock()
+	defer fake.setKindMappingMutex.RUnlock()
+	argsForCall := fake.setKindMappingArgsForCall[i]
 	return argsForCall.arg1
 }
 
-func (fake *FakeClient) ListKubernetesResourcesByFieldsReturns(result1 []kubernetes.Resource, result2 error) {
-	fake.listKubernetesResourcesByFieldsMutex.Lock()
-	defer fake.listKubernetesResourcesByFieldsMutex.Unlock()
-	fake.ListKubernetesResourcesByFieldsStub = nil
-	fake.listKubernetesResourcesByFieldsReturns = struct {
-		result1 []kubernetes.Resource
-		result2 error
-	}{result1, result2}
+func (fake *FakeClient) SetKindMappingReturns(result1 error) {
+	fake.setKindMappingMutex.Lock()
+	defer fake.setKindMappingMutex.Unlock()
+	fake.SetKindMappingStub = nil
+	fake.setKindMappingReturns = struct {
+		result1 error
+	}{result1}
 }
 
-func (fake *FakeClient) ListKubernetesResourcesByFieldsReturnsOnCall(i int, result1 []kubernetes.Resource, result2 error) {
-	fake.listKubernetesResourcesByFieldsMutex.Lock()
-	defer fake.listKubernetesResourcesByFieldsMutex.Unlock()
-	fake.ListKubernetesResourcesByFieldsStub = nil
-	if fake.listKubernetesResourcesByFieldsReturnsOnCall == nil {
-		fake.listKubernetesResourcesByFieldsReturnsOnCall = make(map[int]struct {
-			result1 []kubernetes.Resource
-			result2 error
+func (fake *FakeClient) SetKindMappingReturnsOnCall(i int, result1 error) {
+	fake.setKindMappingMutex.Lock()
+	defer fake.setKindMappingMutex.Unlock()
+	fake.SetKindMappingStub = nil
+	if fake.setKindMappingReturnsOnCall == nil {
+		fake.setKindMappingReturnsOnCall = make(map[int]struct {
+			result1 error
 		})
 	}
-	fake.listKubernetesResourcesByFieldsReturnsOnCall[i] = struct {
-		result1 []kubernetes.Resource
-		result2 error
-	}{result1, result2}
+	fake.setKindMappingReturnsOnCall[i] = struct {
+		result1 error
+	}{result1}
 }
 
-func (fake *FakeClient) ListKubernetesResourcesByTaskID(arg1 string) ([]kubernetes.Resource, error) {
-	fake.listKubernetesResourcesByTaskIDMutex.Lock()
-	ret, specificReturn := fake.listKubernetesResourcesByTaskIDReturnsOnCall[len(fake.listKubernetesResourcesByTaskIDArgsForCall)]
-	fake.listKubernetesResourcesByTaskIDArgsForCall = append(fake.listKubernetesResourcesByTaskIDArgsForCall, struct {
+func (fake *FakeClient) SetKubernetesProviderMaintenance(arg1 string, arg2 bool, arg3 string) error {
+	fake.setKubernetesProviderMaintenanceMutex.Lock()
+	ret, specificReturn := fake.setKubernetesProviderMaintenanceReturnsOnCall[len(fake.setKubernetesProviderMaintenanceArgsForCall)]
+	fake.setKubernetesProviderMaintenanceArgsForCall = append(fake.setKubernetesProviderMaintenanceArgsForCall, struct {
 		arg1 string
-	}{arg1})
-	fake.recordInvocation("ListKubernetesResourcesByTaskID", []interface{}{arg1})
-	fake.listKubernetesResourcesByTaskIDMutex.Unlock()
-	if fake.ListKubernetesResourcesByTaskIDStub != nil {
-		return fake.ListKubernetesResourcesByTaskIDStub(arg1)
+		arg2 bool
+		arg3 string
+	}{arg1, arg2, arg3})
+	fake.recordInvocation("SetKubernetesProviderMaintenance", []interface{}{arg1, arg2, arg3})
+	fake.setKubernetesProviderMaintenanceMutex.Unlock()
+	if fake.SetKubernetesProviderMaintenanceStub != nil {
+		return fake.SetKubernetesProviderMaintenanceStub(arg1, arg2, arg3)
 	}
 	if specificReturn {
-		return ret.result1, ret.result2
+		return ret.result1
 	}
-	fakeReturns := fake.listKubernetesResourcesByTaskIDReturns
-	return fakeReturns.result1, fakeReturns.result2
+	fakeReturns := fake.setKubernetesProviderMaintenanceReturns
+	return fakeReturns.result1
 }
 
-func (fake *FakeClient) ListKubernetesResourcesByTaskIDCallCount() int {
-	fake.listKubernetesResourcesByTaskIDMutex.RLock()
-	defer fake.listKubernetesResourcesByTaskIDMutex.RUnlock()
-	return len(fake.listKubernetesResourcesByTaskIDArgsForCall)
+func (fake *FakeClient) SetKubernetesProviderMaintenanceCallCount() int {
+	fake.setKubernetesProviderMaintenanceMutex.RLock()
+	defer fake.setKubernetesProviderMaintenanceMutex.RUnlock()
+	return len(fake.setKubernetesProviderMaintenanceArgsForCall)
 }
 
-func (fake *FakeClient) ListKubernetesResourcesByTaskIDCalls(stub func(string) ([]kubernetes.Resource, error)) {
-	fake.listKubernetesResourcesByTaskIDMutex.Lock()
-	defer fake.listKubernetesResourcesByTaskIDMutex.Unlock()
-	fake.ListKubernetesResourcesByTaskIDStub = stub
+func (fake *FakeClient) SetKubernetesProviderMaintenanceCalls(stub func(string, bool, string) error) {
+	fake.setKubernetesProviderMaintenanceMutex.Lock()
+	defer fake.setKubernetesProviderMaintenanceMutex.Unlock()
+	fake.SetKubernetesProviderMaintenanceStub = stub
 }
 
-func (fake *FakeClient) ListKubernetesResourcesByTaskIDArgsForCall(i int) string {
-	fake.listKubernetesResourcesByTaskIDMutex.RLock()
-	defer fake.listKubernetesResourcesByTaskIDMutex.RUnlock()
-	argsForCall := fake.listKubernetesResourcesByTaskIDArgsForCall[i]
-	return argsForCall.arg1
+func (fake *FakeClient) SetKubernetesProviderMaintenanceArgsForCall(i int) (string, bool, string) {
+	fake.setKubernetesProviderMaintenanceMutex.RLock()
+	defer fake.setKubernetesProviderMaintenanceMutex.RUnlock()
+	argsForCall := fake.setKubernetesProviderMaintenanceArgsForCall[i]
+	return argsForCall.arg1, argsForCall.arg2, argsForCall.arg3
 }
 
-func (fake *FakeClient) ListKubernetesResourcesByTaskIDReturns(result1 []kubernetes.Resource, result2 error) {
-	fake.listKubernetesResourcesByTaskIDMutex.Lock()
-	defer fake.listKubernetesResourcesByTaskIDMutex.Unlock()
-	fake.ListKubernetesResourcesByTaskIDStub = nil
-	fake.listKubernetesResourcesByTaskIDReturns = struct {
-		result1 []kubernetes.Resource
-		result2 error
-	}{result1, result2}
+func (fake *FakeClient) SetKubernetesProviderMaintenanceReturns(result1 error) {
+	fake.setKubernetesProviderMaintenanceMutex.Lock()
+	defer fake.setKubernetesProviderMaintenanceMutex.Unlock()
+	fake.SetKubernetesProviderMaintenanceStub = nil
+	fake.setKubernetesProviderMaintenanceReturns = struct {
+		result1 error
+	}{result1}
 }
 
-func (fake *FakeClient) ListKubernetesResourcesByTaskIDReturnsOnCall(i int, result1 []kubernetes.Resource, result2 error) {
-	fake.listKubernetesResourcesByTaskIDMutex.Lock()
-	defer fake.listKubernetesResourcesByTaskIDMutex.Unlock()
-	fake.ListKubernetesResourcesByTaskIDStub = nil
-	if fake.listKubernetesResourcesByTaskIDReturnsOnCall == nil {
-		fake.listKubernetesResourcesByTaskIDReturnsOnCall = make(map[int]struct {
-			result1 []kubernetes.Resource
-			result2 error
+func (fake *FakeClient) SetKubernetesProviderMaintenanceReturnsOnCall(i int, result1 error) {
+	fake.setKubernetesProviderMaintenanceMutex.Lock()
+	defer fake.setKubernetesProviderMaintenanceMutex.Unlock()
+	fake.SetKubernetesProviderMaintenanceStub = nil
+	if fake.setKubernetesProviderMaintenanceReturnsOnCall == nil {
+		fake.setKubernetesProviderMaintenanceReturnsOnCall = make(map[int]struct {
+			result1 error
 		})
 	}
-	fake.listKubernetesResourcesByTaskIDReturnsOnCall[i] = struct {
-		result1 []kubernetes.Resource
-		result2 error
-	}{result1, result2}
+	fake.setKubernetesProviderMaintenanceReturnsOnCall[i] = struct {
+		result1 error
+	}{result1}
 }
 
-func (fake *FakeClient) ListReadGroupsByAccountName(arg1 string) ([]string, error) {
-	fake.listReadGroupsByAccountNameMutex.Lock()
-	ret, specificReturn := fake.listReadGroupsByAccountNameReturnsOnCall[len(fake.listReadGroupsByAccountNameArgsForCall)]
-	fake.listReadGroupsByAccountNameArgsForCall = append(fake.listReadGroupsByAccountNameArgsForCall, struct {
-		arg1 string
+func (fake *FakeClient) UpdateKubernetesClusterCredential(arg1 kubernetes.ClusterCredential) error {
+	fake.updateKubernetesClusterCredentialMutex.Lock()
+	ret, specificReturn := fake.updateKubernetesClusterCredentialReturnsOnCall[len(fake.updateKubernetesClusterCredentialArgsForCall)]
+	fake.updateKubernetesClusterCredentialArgsForCall = append(fake.updateKubernetesClusterCredentialArgsForCall, struct {
+		arg1 kubernetes.ClusterCredential
 	}{arg1})
-	fake.recordInvocation("ListReadGroupsByAccountName", []interface{}{arg1})
-	fake.listReadGroupsByAccountNameMutex.Unlock()
-	if fake.ListReadGroupsByAccountNameStub != nil {
-		return fake.ListReadGroupsByAccountNameStub(arg1)
+	fake.recordInvocation("UpdateKubernetesClusterCredential", []interface{}{arg1})
+	fake.updateKubernetesClusterCredentialMutex.Unlock()
+	if fake.UpdateKubernetesClusterCredentialStub != nil {
+		return fake.UpdateKubernetesClusterCredentialStub(arg1)
 	}
 	if specificReturn {
-		return ret.result1, ret.result2
+		return ret.result1
 	}
-	fakeReturns := fake.listReadGroupsByAccountNameReturns
-	return fakeReturns.result1, fakeReturns.result2
+	fakeReturns := fake.updateKubernetesClusterCredentialReturns
+	return fakeReturns.result1
 }
 
-func (fake *FakeClient) ListReadGroupsByAccountNameCallCount() int {
-	fake.listReadGroupsByAccountNameMutex.RLock()
-	defer fake.listReadGroupsByAccountNameMutex.RUnlock()
-	return len(fake.listReadGroupsByAccountNameArgsForCall)
+func (fake *FakeClient) UpdateKubernetesClusterCredentialCallCount() int {
+	fake.updateKubernetesClusterCredentialMutex.RLock()
+	defer fake.updateKubernetesClusterCredentialMutex.RUnlock()
+	return len(fake.updateKubernetesClusterCredentialArgsForCall)
 }
 
-func (fake *FakeClient) ListReadGroupsByAccountNameCalls(stub func(string) ([]string, error)) {
-	fake.listReadGroupsByAccountNameMutex.Lock()
-	defer fake.listReadGroupsByAccountNameMutex.Unlock()
-	fake.ListReadGroupsByAccountNameStub = stub
+func (fake *FakeClient) UpdateKubernetesClusterCredentialCalls(stub func(kubernetes.ClusterCredential) error) {
+	fake.updateKubernetesClusterCredentialMutex.Lock()
+	defer fake.updateKubernetesClusterCredentialMutex.Unlock()
+	fake.UpdateKubernetesClusterCredentialStub = stub
 }
 
-func (fake *FakeClient) ListReadGroupsByAccountNameArgsForCall(i int) string {
-	fake.listReadGroupsByAccountNameMutex.RLock()
-	defer fake.listReadGroupsByAccountNameMutex.RUnlock()
-	argsForCall := fake.listReadGroupsByAccountNameArgsForCall[i]
+func (fake *FakeClient) UpdateKubernetesClusterCredentialArgsForCall(i int) kubernetes.ClusterCredential {
+	fake.updateKubernetesClusterCredentialMutex.RLock()
+	defer fake.updateKubernetesClusterCredentialMutex.RUnlock()
+	argsForCall := fake.updateKubernetesClusterCredentialArgsForCall[i]
 	return argsForCall.arg1
 }
 
-func (fake *FakeClient) ListReadGroupsByAccountNameReturns(result1 []string, result2 error) {
-	fake.listReadGroupsByAccountNameMutex.Lock()
-	defer fake.listReadGroupsByAccountNameMutex.Unlock()
-	fake.ListReadGroupsByAccountNameStub = nil
-	fake.listReadGroupsByAccountNameReturns = struct {
-		result1 []string
-		result2 error
-	}{result1, result2}
+func (fake *FakeClient) UpdateKubernetesClusterCredentialReturns(result1 error) {
+	fake.updateKubernetesClusterCredentialMutex.Lock()
+	defer fake.updateKubernetesClusterCredentialMutex.Unlock()
+	fake.UpdateKubernetesClusterCredentialStub = nil
+	fake.updateKubernetesClusterCredentialReturns = struct {
+		result1 error
+	}{result1}
 }
 
-func (fake *FakeClient) ListReadGroupsByAccountNameReturnsOnCall(i int, result1 []string, result2 error) {
-	fake.listReadGroupsByAccountNameMutex.Lock()
-	defer fake.listReadGroupsByAccountNameMutex.Unlock()
-	fake.ListReadGroupsByAccountNameStub = nil
-	if fake.listReadGroupsByAccountNameReturnsOnCall == nil {
-		fake.listReadGroupsByAccountNameReturnsOnCall = make(map[int]struct {
-			result1 []string
-			result2 error
+func (fake *FakeClient) UpdateKubernetesClusterCredentialReturnsOnCall(i int, result1 error) {
+	fake.updateKubernetesClusterCredentialMutex.Lock()
+	defer fake.updateKubernetesClusterCredentialMutex.Unlock()
+	fake.UpdateKubernetesClusterCredentialStub = nil
+	if fake.updateKubernetesClusterCredentialReturnsOnCall == nil {
+		fake.updateKubernetesClusterCredentialReturnsOnCall = make(map[int]struct {
+			result1 error
 		})
 	}
-	fake.listReadGroupsByAccountNameReturnsOnCall[i] = struct {
-		result1 []string
-		result2 error
-	}{result1, result2}
+	fake.updateKubernetesClusterCredentialReturnsOnCall[i] = struct {
+		result1 error
+	}{result1}
 }
 
-func (fake *FakeClient) ListWriteGroupsByAccountName(arg1 string) ([]string, error) {
-	fake.listWriteGroupsByAccountNameMutex.Lock()
-	ret, specificReturn := fake.listWriteGroupsByAccountNameReturnsOnCall[len(fake.listWriteGroupsByAccountNameArgsForCall)]
-	fake.listWriteGroupsByAccountNameArgsForCall = append(fake.listWriteGroupsByAccountNameArgsForCall, struct {
-		arg1 string
+func (fake *FakeClient) WithContext(arg1 context.Context) sql.Client {
+	fake.withContextMutex.Lock()
+	ret, specificReturn := fake.withContextReturnsOnCall[len(fake.withContextArgsForCall)]
+	fake.withContextArgsForCall = append(fake.withContextArgsForCall, struct {
+		arg1 context.Context
 	}{arg1})
-	fake.recordInvocation("ListWriteGroupsByAccountName", []interface{}{arg1})
-	fake.listWriteGroupsByAccountNameMutex.Unlock()
-	if fake.ListWriteGroupsByAccountNameStub != nil {
-		return fake.ListWriteGroupsByAccountNameStub(arg1)
+	fake.recordInvocation("WithContext", []interface{}{arg1})
+	fake.withContextMutex.Unlock()
+	if fake.WithContextStub != nil {
+		return fake.WithContextStub(arg1)
 	}
 	if specificReturn {
-		return ret.result1, ret.result2
+		return ret.result1
 	}
-	fakeReturns := fake.listWriteGroupsByAccountNameReturns
-	return fakeReturns.result1, fakeReturns.result2
+	fakeReturns := fake.withContextReturns
+	return fakeReturns.result1
 }
 
-func (fake *FakeClient) ListWriteGroupsByAccountNameCallCount() int {
-	fake.listWriteGroupsByAccountNameMutex.RLock()
-	defer fake.listWriteGroupsByAccountNameMutex.RUnlock()
-	return len(fake.listWriteGroupsByAccountNameArgsForCall)
+func (fake *FakeClient) WithContextCallCount() int {
+	fake.withContextMutex.RLock()
+	defer fake.withContextMutex.RUnlock()
+	return len(fake.withContextArgsForCall)
 }
 
-func (fake *FakeClient) ListWriteGroupsByAccountNameCalls(stub func(string) ([]string, error)) {
-	fake.listWriteGroupsByAccountNameMutex.Lock()
-	defer fake.listWriteGroupsByAccountNameMutex.Unlock()
-	fake.ListWriteGroupsByAccountNameStub = stub
+func (fake *FakeClient) WithContextCalls(stub func(context.Context) sql.Client) {
+	fake.withContextMutex.Lock()
+	defer fake.withContextMutex.Unlock()
+	fake.WithContextStub = stub
 }
 
-func (fake *FakeClient) ListWriteGroupsByAccountNameArgsForCall(i int) string {
-	fake.listWriteGroupsByAccountNameMutex.RLock()
-	defer fake.listWriteGroupsByAccountNameMutex.RUnlock()
-	argsForCall := fake.listWriteGroupsByAccountNameArgsForCall[i]
+func (fake *FakeClient) WithContextArgsForCall(i int) context.Context {
+	fake.withContextMutex.RLock()
+	defer fake.withContextMutex.RUnlock()
+	argsForCall := fake.withContextArgsForCall[i]
 	return argsForCall.arg1
 }
 
-func (fake *FakeClient) ListWriteGroupsByAccountNameReturns(result1 []string, result2 error) {
-	fake.listWriteGroupsByAccountNameMutex.Lock()
-	defer fake.listWriteGroupsByAccountNameMutex.Unlock()
-	fake.ListWriteGroupsByAccountNameStub = nil
-	fake.listWriteGroupsByAccountNameReturns = struct {
-		result1 []string
-		result2 error
-	}{result1, result2}
+func (fake *FakeClient) WithContextReturns(result1 sql.Client) {
+	fake.withContextMutex.Lock()
+	defer fake.withContextMutex.Unlock()
+	fake.WithContextStub = nil
+	fake.withContextReturns = struct {
+		result1 sql.Client
+	}{result1}
 }
 
-func (fake *FakeClient) ListWriteGroupsByAccountNameReturnsOnCall(i int, result1 []string, result2 error) {
-	fake.listWriteGroupsByAccountNameMutex.Lock()
-	defer fake.listWriteGroupsByAccountNameMutex.Unlock()
-	fake.ListWriteGroupsByAccountNameStub = nil
-	if fake.listWriteGroupsByAccountNameReturnsOnCall == nil {
-		fake.listWriteGroupsByAccountNameReturnsOnCall = make(map[int]struct {
-			result1 []string
-			result2 error
+func (fake *FakeClient) WithContextReturnsOnCall(i int, result1 sql.Client) {
+	fake.withContextMutex.Lock()
+	defer fake.withContextMutex.Unlock()
+	fake.WithContextStub = nil
+	if fake.withContextReturnsOnCall == nil {
+		fake.withContextReturnsOnCall = make(map[int]struct {
+			result1 sql.Client
 		})
 	}
-	fake.listWriteGroupsByAccountNameReturnsOnCall[i] = struct {
-		result1 []string
-		result2 error
-	}{result1, result2}
+	fake.withContextReturnsOnCall[i] = struct {
+		result1 sql.Client
+	}{result1}
 }
 
 func (fake *FakeClient) Invocations() map[string][][]interface{} {
 	fake.invocationsMutex.RLock()
 	defer fake.invocationsMutex.RUnlock()
+	fake.acquireClusterLocksMutex.RLock()
+	defer fake.acquireClusterLocksMutex.RUnlock()
+	fake.createApplicationMutex.RLock()
+	defer fake.createApplicationMutex.RUnlock()
+	fake.createCacheSnapshotMutex.RLock()
+	defer fake.createCacheSnapshotMutex.RUnlock()
+	fake.createDeployMutex.RLock()
+	defer fake.createDeployMutex.RUnlock()
+	fake.createExecutePermissionMutex.RLock()
+	defer fake.createExecutePermissionMutex.RUnlock()
+	fake.createFailedOperationMutex.RLock()
+	defer fake.createFailedOperationMutex.RUnlock()
+	fake.createKubernetesClusterCredentialMutex.RLock()
+	defer fake.createKubernetesClusterCredentialMutex.RUnlock()
 	fake.createKubernetesProviderMutex.RLock()
 	defer fake.createKubernetesProviderMutex.RUnlock()
 	fake.createKubernetesResourceMutex.RLock()
 	defer fake.createKubernetesResourceMutex.RUnlock()
+	fake.createMigrationReportMutex.RLock()
+	defer fake.createMigrationReportMutex.RUnlock()
+	fake.createNamespacePermissionMutex.RLock()
+	defer fake.createNamespacePermissionMutex.RUnlock()
 	fake.createReadPermissionMutex.RLock()
 	defer fake.createReadPermissionMutex.RUnlock()
+	fake.createTaskEventMutex.RLock()
+	defer fake.createTaskEventMutex.RUnlock()
 	fake.createWritePermissionMutex.RLock()
 	defer fake.createWritePermissionMutex.RUnlock()
+	fake.deleteApplicationMutex.RLock()
+	defer fake.deleteApplicationMutex.RUnlock()
+	fake.deleteCacheSnapshotsCreatedBeforeMutex.RLock()
+	defer fake.deleteCacheSnapshotsCreatedBeforeMutex.RUnlock()
+	fake.deleteCompressedBlobsUnusedSinceMutex.RLock()
+	defer fake.deleteCompressedBlobsUnusedSinceMutex.RUnlock()
+	fake.deleteDeploysCreatedBeforeMutex.RLock()
+	defer fake.deleteDeploysCreatedBeforeMutex.RUnlock()
+	fake.deleteFailedOperationsCreatedBeforeMutex.RLock()
+	defer fake.deleteFailedOperationsCreatedBeforeMutex.RUnlock()
+	fake.deleteKindMappingMutex.RLock()
+	defer fake.deleteKindMappingMutex.RUnlock()
+	fake.deleteKubernetesClusterCredentialMutex.RLock()
+	defer fake.deleteKubernetesClusterCredentialMutex.RUnlock()
 	fake.deleteKubernetesProviderMutex.RLock()
 	defer fake.deleteKubernetesProviderMutex.RUnlock()
+	fake.deleteKubernetesResourcesCreatedBeforeMutex.RLock()
+	defer fake.deleteKubernetesResourcesCreatedBeforeMutex.RUnlock()
+	fake.deleteMigrationReportsCreatedBeforeMutex.RLock()
+	defer fake.deleteMigrationReportsCreatedBeforeMutex.RUnlock()
+	fake.deleteOrphanedKubernetesResourcesMutex.RLock()
+	defer fake.deleteOrphanedKubernetesResourcesMutex.RUnlock()
+	fake.deleteOrphanedPermissionsMutex.RLock()
+	defer fake.deleteOrphanedPermissionsMutex.RUnlock()
+	fake.deleteTaskEventsCreatedBeforeMutex.RLock()
+	defer fake.deleteTaskEventsCreatedBeforeMutex.RUnlock()
+	fake.getCacheSnapshotMutex.RLock()
+	defer fake.getCacheSnapshotMutex.RUnlock()
+	fake.getFailedOperationMutex.RLock()
+	defer fake.getFailedOperationMutex.RUnlock()
+	fake.getKubernetesClusterCredentialMutex.RLock()
+	defer fake.getKubernetesClusterCredentialMutex.RUnlock()
 	fake.getKubernetesProviderMutex.RLock()
 	defer fake.getKubernetesProviderMutex.RUnlock()
+	fake.getKubernetesProviderVersionMutex.RLock()
+	defer fake.getKubernetesProviderVersionMutex.RUnlock()
+	fake.getMigrationReportMutex.RLock()
+	defer fake.getMigrationReportMutex.RUnlock()
+	fake.listApplicationsMutex.RLock()
+	defer fake.listApplicationsMutex.RUnlock()
+	fake.listDeletedApplicationsMutex.RLock()
+	defer fake.listDeletedApplicationsMutex.RUnlock()
+	fake.listDeploysCreatedSinceMutex.RLock()
+	defer fake.listDeploysCreatedSinceMutex.RUnlock()
+	fake.listFeaturesMutex.RLock()
+	defer fake.listFeaturesMutex.RUnlock()
+	fake.listKindMappingsByAccountNamesMutex.RLock()
+	defer fake.listKindMappingsByAccountNamesMutex.RUnlock()
 	fake.listKubernetesAccountsBySpinnakerAppMutex.RLock()
 	defer fake.listKubernetesAccountsBySpinnakerAppMutex.RUnlock()
 	fake.listKubernetesClustersByApplicationMutex.RLock()
 	defer fake.listKubernetesClustersByApplicationMutex.RUnlock()
+	fake.listKubernetesLastDeployTimesMutex.RLock()
+	defer fake.listKubernetesLastDeployTimesMutex.RUnlock()
+	fake.listKubernetesProviderNamesByClusterCredentialMutex.RLock()
+	defer fake.listKubernetesProviderNamesByClusterCredentialMutex.RUnlock()
 	fake.listKubernetesProvidersMutex.RLock()
 	defer fake.listKubernetesProvidersMutex.RUnlock()
 	fake.listKubernetesProvidersAndPermissionsMutex.RLock()
 	defer fake.listKubernetesProvidersAndPermissionsMutex.RUnlock()
+	fake.listKubernetesProvidersChangedSinceMutex.RLock()
+	defer fake.listKubernetesProvidersChangedSinceMutex.RUnlock()
+	fake.listKubernetesProvidersDeletedSinceMutex.RLock()
+	defer fake.listKubernetesProvidersDeletedSinceMutex.RUnlock()
 	fake.listKubernetesResourceNamesByAccountNameAndKindAndNamespaceMutex.RLock()
 	defer fake.listKubernetesResourceNamesByAccountNameAndKindAndNamespaceMutex.RUnlock()
+	fake.listKubernetesResourcesByAccountNamesMutex.RLock()
+	defer fake.listKubernetesResourcesByAccountNamesMutex.RUnlock()
 	fake.listKubernetesResourcesByFieldsMutex.RLock()
 	defer fake.listKubernetesResourcesByFieldsMutex.RUnlock()
 	fake.listKubernetesResourcesByTaskIDMutex.RLock()
 	defer fake.listKubernetesResourcesByTaskIDMutex.RUnlock()
+	fake.listPermissionsByAccountNamesMutex.RLock()
+	defer fake.listPermissionsByAccountNamesMutex.RUnlock()
 	fake.listReadGroupsByAccountNameMutex.RLock()
 	defer fake.listReadGroupsByAccountNameMutex.RUnlock()
+	fake.listTaskEventsMutex.RLock()
+	defer fake.listTaskEventsMutex.RUnlock()
 	fake.listWriteGroupsByAccountNameMutex.RLock()
 	defer fake.listWriteGroupsByAccountNameMutex.RUnlock()
+	fake.releaseClusterLocksMutex.RLock()
+	defer fake.releaseClusterLocksMutex.RUnlock()
+	fake.renewClusterLocksMutex.RLock()
+	defer fake.renewClusterLocksMutex.RUnlock()
+	fake.rotateKubernetesProviderCredentialsMutex.RLock()
+	defer fake.rotateKubernetesProviderCredentialsMutex.RUnlock()
+	fake.setDeployPhaseMutex.RLock()
+	defer fake.setDeployPhaseMutex.RUnlock()
+	fake.setFeatureMutex.RLock()
+	defer fake.setFeatureMutex.RUnlock()
+	fake.setKindMappingMutex.RLock()
+	defer fake.setKindMappingMutex.RUnlock()
+	fake.setKubernetesProviderMaintenanceMutex.RLock()
+	defer fake.setKubernetesProviderMaintenanceMutex.RUnlock()
+	fake.updateKubernetesClusterCredentialMutex.RLock()
+	defer fake.updateKubernetesClusterCredentialMutex.RUnlock()
+	fake.withContextMutex.RLock()
+	defer fake.withContextMutex.RUnlock()
 	copiedInvocations := map[string][][]interface{}{}
 	for key, value := range fake.invocations {
 		copiedInvocations[key] = value
@@ -0,0 +1,527 @@
+package sql_test
+
+import (
+	"time"
+
+	clouddriver "github.com/billiford/go-clouddriver/pkg"
+	"github.com/billiford/go-clouddriver/pkg/kubernetes"
+	"github.com/billiford/go-clouddriver/pkg/lock"
+	"github.com/billiford/go-clouddriver/pkg/snapshot"
+	. "github.com/billiford/go-clouddriver/pkg/sql"
+
+	"github.com/jinzhu/gorm"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+// These tests run against a real in-memory SQLite database
+// instead of a mock, verifying the queries themselves.
+var _ = Describe("SQLite", func() {
+	var (
+		db  *gorm.DB
+		c   Client
+		err error
+	)
+
+	BeforeEach(func() {
+		driver, connection, err := ParseDSN("sqlite::memory:")
+		Expect(err).To(BeNil())
+		db, err = Connect(driver, connection)
+		Expect(err).To(BeNil())
+		c = NewClient(db)
+
+		Expect(c.CreateKubernetesProvider(kubernetes.Provider{
+			Name:   "provider1",
+			Host:   "host1",
+			CAData: "ca-data1",
+		})).To(Succeed())
+		Expect(c.CreateReadPermission(clouddriver.ReadPermission{
+			ID:          "1",
+			AccountName: "provider1",
+			ReadGroup:   "group1",
+		})).To(Succeed())
+		Expect(c.CreateWritePermission(clouddriver.WritePermission{
+			ID:          "2",
+			AccountName: "provider1",
+			WriteGroup:  "group2",
+		})).To(Succeed())
+		Expect(c.CreateExecutePermission(clouddriver.ExecutePermission{
+			ID:           "4",
+			AccountName:  "provider1",
+			ExecuteGroup: "group3",
+		})).To(Succeed())
+		Expect(c.CreateNamespacePermission(clouddriver.NamespacePermission{
+			ID:            "5",
+			AccountName:   "provider1",
+			Namespace:     "namespace1",
+			Authorization: "EXECUTE",
+			GroupName:     "group4",
+		})).To(Succeed())
+	})
+
+	AfterEach(func() {
+		// Closing the last connection drops the database.
+		db.Close()
+	})
+
+	Describe("#ListKubernetesProvidersAndPermissions", func() {
+		It("lists providers with their permissions", func() {
+			providers, err := c.ListKubernetesProvidersAndPermissions()
+			Expect(err).To(BeNil())
+			Expect(providers).To(HaveLen(1))
+			Expect(providers[0].Name).To(Equal("provider1"))
+			Expect(providers[0].Permissions.Read).To(Equal([]string{"group1"}))
+			Expect(providers[0].Permissions.Write).To(Equal([]string{"group2"}))
+			Expect(providers[0].Permissions.Execute).To(Equal([]string{"group3"}))
+			Expect(providers[0].Permissions.Namespaces).To(Equal(map[string]kubernetes.ProviderNamespacePermissions{
+				"namespace1": {Execute: []string{"group4"}},
+			}))
+		})
+	})
+
+	Describe("#DeleteOrphanedPermissions", func() {
+		BeforeEach(func() {
+			Expect(c.CreateReadPermission(clouddriver.ReadPermission{
+				ID:          "3",
+				AccountName: "deleted-provider",
+				ReadGroup:   "group1",
+			})).To(Succeed())
+		})
+
+		It("only deletes permissions of accounts that do not exist", func() {
+			deleted, err := c.DeleteOrphanedPermissions()
+			Expect(err).To(BeNil())
+			Expect(deleted).To(Equal(int64(1)))
+			groups, err := c.ListReadGroupsByAccountName("provider1")
+			Expect(err).To(BeNil())
+			Expect(groups).To(Equal([]string{"group1"}))
+		})
+	})
+
+	Describe("#DeleteKubernetesProvider", func() {
+		It("deletes the provider and its permissions", func() {
+			Expect(c.DeleteKubernetesProvider("provider1")).To(Succeed())
+			_, err = c.GetKubernetesProvider("provider1")
+			Expect(err).To(Equal(gorm.ErrRecordNotFound))
+			groups, err := c.ListWriteGroupsByAccountName("provider1")
+			Expect(err).To(BeNil())
+			Expect(groups).To(BeEmpty())
+			permissions, err := c.ListPermissionsByAccountNames("provider1")
+			Expect(err).To(BeNil())
+			Expect(permissions["provider1"].Execute).To(BeEmpty())
+			Expect(permissions["provider1"].Namespaces).To(BeEmpty())
+		})
+	})
+
+	Describe("#CreateApplication", func() {
+		BeforeEach(func() {
+			Expect(c.CreateApplication(clouddriver.Application{
+				Name:  "app1",
+				Email: "old@example.com",
+				Permissions: clouddriver.Permissions{
+					READ: []string{"group1"},
+				},
+			})).To(Succeed())
+			Expect(c.CreateApplication(clouddriver.Application{Name: "app2"})).To(Succeed())
+		})
+
+		It("updates existing applications and replaces their permissions", func() {
+			Expect(c.CreateApplication(clouddriver.Application{
+				Name:        "app1",
+				Email:       "new@example.com",
+				Description: "description1",
+				Permissions: clouddriver.Permissions{
+					READ:  []string{"group2", "group3"},
+					WRITE: []string{"group2"},
+				},
+			})).To(Succeed())
+
+			applications, err := c.ListApplications()
+			Expect(err).To(BeNil())
+			Expect(applications).To(Equal([]clouddriver.Application{
+				{
+					Name:        "app1",
+					Email:       "new@example.com",
+					Description: "description1",
+					Permissions: clouddriver.Permissions{
+						READ:  []string{"group2", "group3"},
+						WRITE: []string{"group2"},
+					},
+				},
+				{
+					Name: "app2",
+				},
+			}))
+		})
+	})
+
+	Describe("#DeleteApplication", func() {
+		BeforeEach(func() {
+			Expect(c.CreateApplication(clouddriver.Application{
+				Name: "app1",
+				Permissions: clouddriver.Permissions{
+					READ: []string{"group1"},
+				},
+			})).To(Succeed())
+		})
+
+		It("deletes the application and its permissions", func() {
+			Expect(c.DeleteApplication("app1")).To(Succeed())
+			applications, err := c.ListApplications()
+			Expect(err).To(BeNil())
+			Expect(applications).To(BeEmpty())
+
+			var count int
+			Expect(db.Model(&clouddriver.ApplicationPermission{}).Count(&count).Error).To(BeNil())
+			Expect(count).To(Equal(0))
+		})
+
+		It("records that the application was deleted until it is created again", func() {
+			Expect(c.DeleteApplication("app1")).To(Succeed())
+			names, err := c.ListDeletedApplications()
+			Expect(err).To(BeNil())
+			Expect(names).To(Equal([]string{"app1"}))
+
+			Expect(c.CreateApplication(clouddriver.Application{Name: "app1"})).To(Succeed())
+			names, err = c.ListDeletedApplications()
+			Expect(err).To(BeNil())
+			Expect(names).To(BeEmpty())
+		})
+	})
+
+	Describe("#ListKubernetesAccountsBySpinnakerApp", func() {
+		BeforeEach(func() {
+			Expect(c.CreateKubernetesResource(kubernetes.Resource{
+				ID:           "1",
+				TaskID:       "task1",
+				AccountName:  "provider1",
+				SpinnakerApp: "app1",
+			})).To(Succeed())
+			Expect(c.CreateKubernetesResource(kubernetes.Resource{
+				ID:           "2",
+				TaskID:       "task2",
+				AccountName:  "provider2",
+				SpinnakerApp: "app1",
+				DryRun:       true,
+				Manifest:     "{}",
+			})).To(Succeed())
+		})
+
+		It("skips resources that were only dry-run", func() {
+			accounts, err := c.ListKubernetesAccountsBySpinnakerApp("app1")
+			Expect(err).To(BeNil())
+			Expect(accounts).To(Equal([]string{"provider1"}))
+		})
+
+		It("lists resources that were only dry-run by task", func() {
+			resources, err := c.ListKubernetesResourcesByTaskID("task2")
+			Expect(err).To(BeNil())
+			Expect(resources).To(HaveLen(1))
+			Expect(resources[0].DryRun).To(BeTrue())
+			Expect(resources[0].Manifest).To(Equal("{}"))
+		})
+	})
+
+	Describe("deploys", func() {
+		var now time.Time
+
+		BeforeEach(func() {
+			now = time.Now().UTC()
+			for _, d := range []clouddriver.Deploy{
+				{TaskID: "task1", Namespace: "default", Application: "app1", CreatedAt: now.Add(-time.Hour)},
+				{TaskID: "task1", Namespace: "canary", Application: "app1", CreatedAt: now.Add(-time.Hour)},
+				{TaskID: "task2", Namespace: "default", Application: "app1", CreatedAt: now.Add(-48 * time.Hour)},
+			} {
+				Expect(c.CreateDeploy(d)).To(Succeed())
+			}
+		})
+
+		It("sets the rollout phase of every deploy of a task", func() {
+			Expect(c.SetDeployPhase("task1", "STABLE", now)).To(Succeed())
+			ds, err := c.ListDeploysCreatedSince(now.Add(-24 * time.Hour))
+			Expect(err).To(BeNil())
+			Expect(ds).To(HaveLen(2))
+			for _, d := range ds {
+				Expect(d.TaskID).To(Equal("task1"))
+				Expect(d.Phase).To(Equal("STABLE"))
+				Expect(d.RolledOutAt).ToNot(BeNil())
+				Expect(*d.RolledOutAt).To(BeTemporally("~", now, time.Second))
+			}
+		})
+
+		It("deletes deploys made before a time", func() {
+			deleted, err := c.DeleteDeploysCreatedBefore(now.Add(-24 * time.Hour))
+			Expect(err).To(BeNil())
+			Expect(deleted).To(Equal(int64(1)))
+			ds, err := c.ListDeploysCreatedSince(time.Time{})
+			Expect(err).To(BeNil())
+			Expect(ds).To(HaveLen(2))
+		})
+	})
+
+	Describe("task events", func() {
+		var now time.Time
+
+		BeforeEach(func() {
+			now = time.Now().UTC()
+			for _, te := range []clouddriver.TaskEvent{
+				{TaskID: "task1", Type: clouddriver.TaskEventOperationStarted, Message: "first", CreatedAt: now.Add(-48 * time.Hour)},
+				{TaskID: "task2", Type: clouddriver.TaskEventOperationStarted, Message: "other", CreatedAt: now},
+				{TaskID: "task1", Type: clouddriver.TaskEventManifestApplied, Message: "second", CreatedAt: now},
+			} {
+				Expect(c.CreateTaskEvent(te)).To(Succeed())
+			}
+		})
+
+		It("lists the events of a task in order", func() {
+			tes, err := c.ListTaskEvents("task1")
+			Expect(err).To(BeNil())
+			Expect(tes).To(HaveLen(2))
+			Expect(tes[0].Message).To(Equal("first"))
+			Expect(tes[1].Message).To(Equal("second"))
+		})
+
+		It("deletes events that happened before a time", func() {
+			deleted, err := c.DeleteTaskEventsCreatedBefore(now.Add(-24 * time.Hour))
+			Expect(err).To(BeNil())
+			Expect(deleted).To(Equal(int64(1)))
+			tes, err := c.ListTaskEvents("task1")
+			Expect(err).To(BeNil())
+			Expect(tes).To(HaveLen(1))
+		})
+	})
+
+	Describe("#GetCacheSnapshot", func() {
+		var now time.Time
+
+		BeforeEach(func() {
+			now = time.Now().UTC()
+			for _, s := range []snapshot.Snapshot{
+				{Path: "/applications/app1/clusters", Body: `"2h"`, CreatedAt: now.Add(-2 * time.Hour)},
+				{Path: "/applications/app1/clusters", Body: `"1h"`, CreatedAt: now.Add(-time.Hour)},
+				{Path: "/applications/app2/clusters", Body: `"app2"`, CreatedAt: now.Add(-90 * time.Minute)},
+			} {
+				Expect(c.CreateCacheSnapshot(s)).To(Succeed())
+			}
+		})
+
+		It("returns the latest snapshot of the path taken at or before the time", func() {
+			s, err := c.GetCacheSnapshot("/applications/app1/clusters", now.Add(-90*time.Minute))
+			Expect(err).To(BeNil())
+			Expect(s.Body).To(Equal(`"2h"`))
+			s, err = c.GetCacheSnapshot("/applications/app1/clusters", now)
+			Expect(err).To(BeNil())
+			Expect(s.Body).To(Equal(`"1h"`))
+		})
+
+		It("does not find snapshots taken after the time", func() {
+			_, err = c.GetCacheSnapshot("/applications/app1/clusters", now.Add(-3*time.Hour))
+			Expect(err).To(Equal(gorm.ErrRecordNotFound))
+		})
+	})
+
+	Describe("compressed values", func() {
+		var manifest string
+
+		BeforeEach(func() {
+			manifest = `{"kind":"Deployment","metadata":{"name":"test-deployment"}}`
+			for _, id := range []string{"1", "2"} {
+				Expect(c.CreateKubernetesResource(kubernetes.Resource{
+					ID:       id,
+					TaskID:   "task1",
+					DryRun:   true,
+					Manifest: manifest,
+				})).To(Succeed())
+			}
+			Expect(c.CreateFailedOperation(clouddriver.FailedOperation{
+				TaskID:  "task2",
+				Payload: `[{"deployManifest":{}}]`,
+			})).To(Succeed())
+		})
+
+		It("stores them compressed once per content", func() {
+			rs, err := c.ListKubernetesResourcesByTaskID("task1")
+			Expect(err).To(BeNil())
+			Expect(rs).To(HaveLen(2))
+			Expect(rs[0].Manifest).To(Equal(manifest))
+			Expect(rs[1].Manifest).To(Equal(manifest))
+			fo, err := c.GetFailedOperation("task2")
+			Expect(err).To(BeNil())
+			Expect(fo.Payload).To(Equal(`[{"deployManifest":{}}]`))
+
+			var count int
+			Expect(db.Table("compressed_blobs").Count(&count).Error).To(BeNil())
+			Expect(count).To(Equal(2))
+			Expect(db.Table("kubernetes_resources").Where("manifest = ?", manifest).Count(&count).Error).To(BeNil())
+			Expect(count).To(Equal(0))
+		})
+
+		It("reads values stored before they were compressed", func() {
+			Expect(db.Create(&kubernetes.Resource{ID: "3", TaskID: "task3", Manifest: manifest}).Error).To(BeNil())
+			rs, err := c.ListKubernetesResourcesByTaskID("task3")
+			Expect(err).To(BeNil())
+			Expect(rs).To(HaveLen(1))
+			Expect(rs[0].Manifest).To(Equal(manifest))
+		})
+
+		It("deletes values no row references", func() {
+			_, err = c.DeleteFailedOperationsCreatedBefore(time.Now().Add(time.Minute))
+			Expect(err).To(BeNil())
+			deleted, err := c.DeleteCompressedBlobsUnusedSince(time.Now().Add(time.Minute))
+			Expect(err).To(BeNil())
+			Expect(deleted).To(Equal(int64(1)))
+			rs, err := c.ListKubernetesResourcesByTaskID("task1")
+			Expect(err).To(BeNil())
+			Expect(rs[0].Manifest).To(Equal(manifest))
+		})
+
+		It("keeps values stored since the time", func() {
+			_, err = c.DeleteFailedOperationsCreatedBefore(time.Now().Add(time.Minute))
+			Expect(err).To(BeNil())
+			deleted, err := c.DeleteCompressedBlobsUnusedSince(time.Now().Add(-time.Minute))
+			Expect(err).To(BeNil())
+			Expect(deleted).To(BeZero())
+		})
+	})
+
+	Describe("#AcquireClusterLocks", func() {
+		It("takes locks that expired from their owner", func() {
+			key, owner, err := c.AcquireClusterLocks("execution-1", []string{"a", "b"}, time.Now().Add(time.Minute))
+			Expect(err).To(BeNil())
+			Expect(key).To(BeEmpty())
+			Expect(owner).To(BeEmpty())
+
+			key, owner, err = c.AcquireClusterLocks("execution-2", []string{"c", "b"}, time.Now().Add(time.Minute))
+			Expect(err).To(BeNil())
+			Expect(key).To(Equal("b"))
+			Expect(owner).To(Equal("execution-1"))
+
+			Expect(c.RenewClusterLocks("execution-1", []string{"a", "b"}, time.Now().Add(-time.Second))).To(Succeed())
+			key, _, err = c.AcquireClusterLocks("execution-2", []string{"c", "b"}, time.Now().Add(time.Minute))
+			Expect(err).To(BeNil())
+			Expect(key).To(BeEmpty())
+
+			Expect(c.ReleaseClusterLocks("execution-2", []string{"b", "c"})).To(Succeed())
+			var count int
+			Expect(db.Model(&lock.ClusterLock{}).Count(&count).Error).To(BeNil())
+			Expect(count).To(Equal(1))
+		})
+	})
+
+	Describe("#SetKubernetesProviderMaintenance", func() {
+		It("puts the provider in and out of maintenance", func() {
+			Expect(c.SetKubernetesProviderMaintenance("provider1", true, "upgrading")).To(Succeed())
+			provider, err := c.GetKubernetesProvider("provider1")
+			Expect(err).To(BeNil())
+			Expect(provider.Maintenance).To(BeTrue())
+			Expect(provider.MaintenanceMessage).To(Equal("upgrading"))
+			Expect(provider.Host).To(Equal("host1"))
+
+			Expect(c.SetKubernetesProviderMaintenance("provider1", false, "")).To(Succeed())
+			providers, err := c.ListKubernetesProviders()
+			Expect(err).To(BeNil())
+			Expect(providers[0].Maintenance).To(BeFalse())
+			Expect(providers[0].MaintenanceMessage).To(BeEmpty())
+		})
+	})
+
+	Describe("#CreateKubernetesProvider", func() {
+		It("stores the kubeconfig of the provider", func() {
+			Expect(c.CreateKubernetesProvider(kubernetes.Provider{
+				Name:               "provider2",
+				Host:               "https://host2",
+				KubeconfigContents: "apiVersion: v1\nkind: Config\n",
+				KubeconfigContext:  "context2",
+			})).To(Succeed())
+
+			provider, err := c.GetKubernetesProvider("provider2")
+			Expect(err).To(BeNil())
+			Expect(provider.KubeconfigContents).To(Equal("apiVersion: v1\nkind: Config\n"))
+			Expect(provider.KubeconfigContext).To(Equal("context2"))
+
+			// Listed providers have no other credentials to use.
+			providers, err := c.ListKubernetesProviders()
+			Expect(err).To(BeNil())
+			Expect(providers).To(HaveLen(2))
+			kubeconfigs := map[string]string{}
+			for _, p := range providers {
+				kubeconfigs[p.Name] = p.KubeconfigContents
+			}
+			Expect(kubeconfigs).To(Equal(map[string]string{
+				"provider1": "",
+				"provider2": "apiVersion: v1\nkind: Config\n",
+			}))
+		})
+	})
+
+	Describe("#GetKubernetesProvider", func() {
+		It("selects the columns of listed providers and the bearer token", func() {
+			Expect(c.CreateKubernetesProvider(kubernetes.Provider{
+				Name:        "provider2",
+				Host:        "https://host2",
+				BearerToken: "token2",
+				Namespaces:  []string{"namespace2"},
+				Environment: "prod",
+			})).To(Succeed())
+
+			provider, err := c.GetKubernetesProvider("provider2")
+			Expect(err).To(BeNil())
+			Expect(provider.BearerToken).To(Equal("token2"))
+
+			providers, err := c.ListKubernetesProvidersChangedSince(0)
+			Expect(err).To(BeNil())
+			Expect(providers).To(HaveLen(2))
+
+			provider.BearerToken = ""
+			Expect(providers).To(ContainElement(provider))
+		})
+	})
+
+	Describe("kind mappings", func() {
+		It("sets, lists and deletes the kind mappings of providers", func() {
+			version, err := c.GetKubernetesProviderVersion()
+			Expect(err).To(BeNil())
+
+			Expect(c.SetKindMapping(kubernetes.KindMapping{
+				ID:          "6",
+				AccountName: "provider1",
+				Kind:        "rollout",
+				Category:    "serverGroupManagers",
+			})).To(Succeed())
+			Expect(c.SetKindMapping(kubernetes.KindMapping{
+				ID:          "7",
+				AccountName: "provider1",
+				Kind:        "rollout",
+				Category:    "serverGroups",
+			})).To(Succeed())
+
+			kindMaps, err := c.ListKindMappingsByAccountNames("provider1", "provider2")
+			Expect(err).To(BeNil())
+			Expect(kindMaps).To(Equal(map[string]map[string]string{
+				"provider1": {"rollout": "serverGroups"},
+			}))
+
+			changed, err := c.ListKubernetesProvidersChangedSince(version)
+			Expect(err).To(BeNil())
+			Expect(changed).To(HaveLen(1))
+
+			Expect(c.DeleteKindMapping("provider1", "rollout")).To(Succeed())
+			kindMaps, err = c.ListKindMappingsByAccountNames("provider1")
+			Expect(err).To(BeNil())
+			Expect(kindMaps).To(BeEmpty())
+		})
+
+		It("deletes them with the provider", func() {
+			Expect(c.SetKindMapping(kubernetes.KindMapping{
+				ID:          "6",
+				AccountName: "provider1",
+				Kind:        "rollout",
+				Category:    "serverGroups",
+			})).To(Succeed())
+			Expect(c.DeleteKubernetesProvider("provider1")).To(Succeed())
+			kindMaps, err := c.ListKindMappingsByAccountNames("provider1")
+			Expect(err).To(BeNil())
+			Expect(kindMaps).To(BeEmpty())
+		})
+	})
+})
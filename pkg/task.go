@@ -1,9 +1,12 @@
 package clouddriver
 
+import "github.com/billiford/go-clouddriver/pkg/version"
+
 func NewDefaultTask(id string) Task {
 	return Task{
-		ID:            id,
-		ResultObjects: []TaskResultObject{},
+		ID:                 id,
+		ClouddriverVersion: version.Version,
+		ResultObjects:      []TaskResultObject{},
 		Status: TaskStatus{
 			Complete:  true,
 			Completed: true,
@@ -15,6 +18,12 @@ func NewDefaultTask(id string) Task {
 	}
 }
 
+// Fail marks the task as failed by the error message.
+func (t *Task) Fail(message string) {
+	t.Status.Failed = true
+	t.Status.Status = "Orchestration failed: " + message
+}
+
 type Task struct {
 	ID string `json:"id"`
 	// SagaIds []interface{} `json:"sagaIds"`
@@ -28,6 +37,8 @@ type Task struct {
 	// StartTimeMsClouddriverSQL int64 `json:"startTimeMs$clouddriver_sql"`
 	ResultObjects []TaskResultObject `json:"resultObjects"`
 	Status        TaskStatus         `json:"status"`
+	// The version of clouddriver that served the task.
+	ClouddriverVersion string `json:"clouddriverVersion"`
 }
 
 type TaskStatus struct {
@@ -46,6 +57,10 @@ type TaskResultObject struct {
 	ManifestNamesByNamespace          map[string][]string      `json:"manifestNamesByNamespace"`
 	ManifestNamesByNamespaceToRefresh map[string][]string      `json:"manifestNamesByNamespaceToRefresh"`
 	Manifests                         []map[string]interface{} `json:"manifests"`
+	// Lint warnings of the deployed manifests.
+	Warnings []string `json:"warnings,omitempty"`
+	// What a migrateApplication task copied.
+	MigrationReport *MigrationReport `json:"migrationReport,omitempty"`
 }
 
 type TaskCreatedArtifact struct {
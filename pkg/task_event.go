@@ -0,0 +1,31 @@
+package clouddriver
+
+import "time"
+
+// Types of task events.
+const (
+	TaskEventOperationStarted   = `OPERATION_STARTED`
+	TaskEventOperationSucceeded = `OPERATION_SUCCEEDED`
+	TaskEventOperationFailed    = `OPERATION_FAILED`
+	TaskEventArtifactFetched    = `ARTIFACT_FETCHED`
+	TaskEventManifestApplied    = `MANIFEST_APPLIED`
+	TaskEventManifestFailed     = `MANIFEST_FAILED`
+	TaskEventManifestSkipped    = `MANIFEST_SKIPPED`
+	TaskEventManifestRolledBack = `MANIFEST_ROLLED_BACK`
+	TaskEventRolloutProgress    = `ROLLOUT_PROGRESS`
+	TaskEventRolloutFinished    = `ROLLOUT_FINISHED`
+)
+
+// TaskEvent is a step of a task, such as a manifest it applied, kept in the
+// order the steps happened to debug long deploys.
+type TaskEvent struct {
+	ID        uint      `json:"-" gorm:"primary_key"`
+	TaskID    string    `json:"-" gorm:"index"`
+	Type      string    `json:"type"`
+	Message   string    `json:"message" gorm:"type:text"`
+	CreatedAt time.Time `json:"createdAt"`
+}
+
+func (TaskEvent) TableName() string {
+	return "task_events"
+}
@@ -0,0 +1,38 @@
+package version
+
+import (
+	"fmt"
+	"runtime"
+)
+
+// Set at build time, for example
+//
+//	go build -ldflags "-X github.com/billiford/go-clouddriver/pkg/version.GitCommit=$(git rev-parse HEAD)"
+//
+// See the Makefile.
+var (
+	Version   = "dev"
+	GitCommit = "unknown"
+	BuildDate = "unknown"
+)
+
+type Info struct {
+	Version   string `json:"version"`
+	GitCommit string `json:"gitCommit"`
+	BuildDate string `json:"buildDate"`
+	GoVersion string `json:"goVersion"`
+}
+
+// Get returns the build info of the running binary.
+func Get() Info {
+	return Info{
+		Version:   Version,
+		GitCommit: GitCommit,
+		BuildDate: BuildDate,
+		GoVersion: runtime.Version(),
+	}
+}
+
+func (i Info) String() string {
+	return fmt.Sprintf("%s (commit %s, built %s, %s)", i.Version, i.GitCommit, i.BuildDate, i.GoVersion)
+}
@@ -0,0 +1,45 @@
+package version_test
+
+import (
+	"runtime"
+
+	. "github.com/billiford/go-clouddriver/pkg/version"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Version", func() {
+	var info Info
+
+	BeforeEach(func() {
+		Version = "1.2.3"
+		GitCommit = "abc123"
+		BuildDate = "2020-10-13T10:06:17Z"
+	})
+
+	AfterEach(func() {
+		Version = "dev"
+		GitCommit = "unknown"
+		BuildDate = "unknown"
+	})
+
+	JustBeforeEach(func() {
+		info = Get()
+	})
+
+	Describe("#Get", func() {
+		It("returns the build info", func() {
+			Expect(info.Version).To(Equal("1.2.3"))
+			Expect(info.GitCommit).To(Equal("abc123"))
+			Expect(info.BuildDate).To(Equal("2020-10-13T10:06:17Z"))
+			Expect(info.GoVersion).To(Equal(runtime.Version()))
+		})
+	})
+
+	Describe("#String", func() {
+		It("formats the build info", func() {
+			Expect(info.String()).To(Equal("1.2.3 (commit abc123, built 2020-10-13T10:06:17Z, " + runtime.Version() + ")"))
+		})
+	})
+})
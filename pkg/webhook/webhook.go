@@ -0,0 +1,108 @@
+// Package webhook authenticates the webhooks registries and clusters call to
+// invalidate caches, with a secret shared with each account's webhooks.
+package webhook
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+const (
+	InstanceKey = `WebhookSecrets`
+	// HeaderSignature holds the hex HMAC-SHA256 of the body keyed by the
+	// secret, prefixed "sha256=", like GitHub and Harbor sign their webhooks.
+	HeaderSignature    = `X-Hub-Signature-256`
+	defaultSecretsPath = "/opt/spinnaker/webhooks/secrets.json"
+)
+
+// Secrets are the secrets shared with the webhooks of accounts, by account
+// name.
+//
+//	{
+//	  "docker-registry": "s3cr3t",
+//	  "spin-cluster-account": "an0th3r"
+//	}
+type Secrets map[string]string
+
+// NewDefaultSecrets reads the secrets from /opt/spinnaker/webhooks/secrets.json.
+// The file is optional, but without it every webhook is rejected.
+func NewDefaultSecrets() (Secrets, error) {
+	if _, err := os.Stat(defaultSecretsPath); os.IsNotExist(err) {
+		return Secrets{}, nil
+	}
+
+	return NewSecrets(defaultSecretsPath)
+}
+
+// NewSecrets reads the secrets from a JSON file.
+func NewSecrets(path string) (Secrets, error) {
+	s := Secrets{}
+
+	b, err := ioutil.ReadFile(path)
+	if err != nil {
+		return s, err
+	}
+
+	err = json.Unmarshal(b, &s)
+	if err != nil {
+		return s, err
+	}
+
+	return s, nil
+}
+
+// Authenticate returns an error unless a webhook request of an account sends
+// the account's secret as a bearer token in the Authorization header, or
+// signs its body with the secret in the X-Hub-Signature-256 header. Accounts
+// without a secret do not accept webhooks.
+func (s Secrets) Authenticate(account string, r *http.Request, body []byte) error {
+	secret := s[account]
+	if secret == "" {
+		return fmt.Errorf("account %s has no webhook secret", account)
+	}
+
+	if auth := r.Header.Get("Authorization"); strings.HasPrefix(auth, "Bearer ") {
+		token := strings.TrimPrefix(auth, "Bearer ")
+		if subtle.ConstantTimeCompare([]byte(token), []byte(secret)) == 1 {
+			return nil
+		}
+
+		return fmt.Errorf("invalid webhook token for account %s", account)
+	}
+
+	if signature := r.Header.Get(HeaderSignature); signature != "" {
+		if hmac.Equal([]byte(signature), []byte(Sign(secret, body))) {
+			return nil
+		}
+
+		return fmt.Errorf("invalid webhook signature for account %s", account)
+	}
+
+	return fmt.Errorf("missing webhook token or %s header", HeaderSignature)
+}
+
+// Sign returns the X-Hub-Signature-256 header of a body signed with a secret.
+func Sign(secret string, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write(body)
+
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
+
+// Instance returns the secrets of webhooks, or nil if none are set.
+func Instance(c *gin.Context) Secrets {
+	s, _ := c.Get(InstanceKey)
+	secrets, _ := s.(Secrets)
+
+	return secrets
+}
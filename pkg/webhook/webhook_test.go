@@ -0,0 +1,99 @@
+package webhook_test
+
+import (
+	"net/http"
+
+	. "github.com/billiford/go-clouddriver/pkg/webhook"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Webhook", func() {
+	var (
+		secrets Secrets
+		r       *http.Request
+		body    []byte
+		account string
+		err     error
+	)
+
+	BeforeEach(func() {
+		secrets, err = NewSecrets("test/secrets.json")
+		Expect(err).To(BeNil())
+		r, _ = http.NewRequest(http.MethodPost, "/webhooks/dockerRegistry/docker-registry", nil)
+		body = []byte(`{"events":[]}`)
+		account = "docker-registry"
+	})
+
+	JustBeforeEach(func() {
+		err = secrets.Authenticate(account, r, body)
+	})
+
+	When("the account has no secret", func() {
+		BeforeEach(func() {
+			account = "other-registry"
+			r.Header.Set("Authorization", "Bearer s3cr3t")
+		})
+
+		It("returns an error", func() {
+			Expect(err).ToNot(BeNil())
+			Expect(err.Error()).To(Equal("account other-registry has no webhook secret"))
+		})
+	})
+
+	When("the request sends neither a token nor a signature", func() {
+		It("returns an error", func() {
+			Expect(err).ToNot(BeNil())
+			Expect(err.Error()).To(Equal("missing webhook token or X-Hub-Signature-256 header"))
+		})
+	})
+
+	When("the request sends the secret", func() {
+		BeforeEach(func() {
+			r.Header.Set("Authorization", "Bearer s3cr3t")
+		})
+
+		It("succeeds", func() {
+			Expect(err).To(BeNil())
+		})
+	})
+
+	When("the request sends another token", func() {
+		BeforeEach(func() {
+			r.Header.Set("Authorization", "Bearer guess")
+		})
+
+		It("returns an error", func() {
+			Expect(err).ToNot(BeNil())
+			Expect(err.Error()).To(Equal("invalid webhook token for account docker-registry"))
+		})
+	})
+
+	When("the request signs the body with the secret", func() {
+		BeforeEach(func() {
+			r.Header.Set(HeaderSignature, Sign("s3cr3t", body))
+		})
+
+		It("succeeds", func() {
+			Expect(err).To(BeNil())
+		})
+
+		When("the body was changed", func() {
+			BeforeEach(func() {
+				body = []byte(`{"events":[{}]}`)
+			})
+
+			It("returns an error", func() {
+				Expect(err).ToNot(BeNil())
+				Expect(err.Error()).To(Equal("invalid webhook signature for account docker-registry"))
+			})
+		})
+	})
+
+	Describe("#Sign", func() {
+		It("returns the hex HMAC-SHA256 of the body", func() {
+			Expect(Sign("key", []byte("The quick brown fox jumps over the lazy dog"))).
+				To(Equal("sha256=f7bc83f430538424b13298e6aa6fb143ef4d59a14946175997479dbc2d1a3cd8"))
+		})
+	})
+})
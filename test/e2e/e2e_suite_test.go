@@ -0,0 +1,142 @@
+//go:build e2e
+// +build e2e
+
+package e2e_test
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/billiford/go-clouddriver/pkg/arcade/arcadefakes"
+	"github.com/billiford/go-clouddriver/pkg/fiat/fiatfakes"
+	kube "github.com/billiford/go-clouddriver/pkg/http/core/kubernetes"
+	"github.com/billiford/go-clouddriver/pkg/kubernetes"
+	"github.com/billiford/go-clouddriver/pkg/server"
+	"github.com/billiford/go-clouddriver/pkg/sql"
+	"github.com/gin-gonic/gin"
+	"github.com/jinzhu/gorm"
+	"k8s.io/client-go/rest"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+const (
+	account     = "e2e"
+	application = "e2e"
+)
+
+var (
+	db         *gorm.DB
+	svr        *httptest.Server
+	kubeClient kubernetes.Client
+	namespace  string
+)
+
+// The e2e tests run clouddriver against a real control plane, such as
+// a kind cluster or envtest. Run them with `make e2e`, which sets up
+// a kind cluster with hack/e2e.sh.
+func TestE2E(t *testing.T) {
+	RegisterFailHandler(Fail)
+	RunSpecs(t, "E2E Suite")
+}
+
+var _ = BeforeSuite(func() {
+	host := mustGetenv("E2E_KUBE_HOST")
+	caData := mustGetenv("E2E_KUBE_CA_DATA")
+	token := mustGetenv("E2E_KUBE_TOKEN")
+
+	namespace = os.Getenv("E2E_NAMESPACE")
+	if namespace == "" {
+		namespace = "default"
+	}
+
+	driver, connection, err := sql.ParseDSN("sqlite::memory:")
+	Expect(err).To(BeNil())
+	db, err = sql.Connect(driver, connection)
+	Expect(err).To(BeNil())
+
+	sc := sql.NewClient(db)
+	err = sc.CreateKubernetesProvider(kubernetes.Provider{
+		Name:   account,
+		Host:   host,
+		CAData: caData,
+	})
+	Expect(err).To(BeNil())
+
+	// Arcade hands out the token of the service account set up for the tests.
+	ac := &arcadefakes.FakeClient{}
+	ac.TokenReturns(token, nil)
+
+	kc := kubernetes.NewController()
+
+	cd, err := base64.StdEncoding.DecodeString(caData)
+	Expect(err).To(BeNil())
+
+	kubeClient, err = kc.NewClient(&rest.Config{
+		Host:        host,
+		BearerToken: token,
+		TLSClientConfig: rest.TLSClientConfig{
+			CAData: cd,
+		},
+	})
+	Expect(err).To(BeNil())
+
+	gin.SetMode(gin.ReleaseMode)
+
+	r := gin.New()
+	r.Use(gin.Recovery())
+
+	server.Setup(r, &server.Config{
+		ArcadeClient:         ac,
+		FiatClient:           &fiatfakes.FakeClient{},
+		SQLClient:            sc,
+		KubeController:       kc,
+		KubeActionHandler:    kube.NewActionHandler(),
+		KubeNamespaceCache:   kubernetes.NewNamespaceCache(time.Minute),
+		KubePermissionsCache: kubernetes.NewPermissionsCache(time.Minute),
+	})
+	svr = httptest.NewServer(r)
+})
+
+var _ = AfterSuite(func() {
+	if svr != nil {
+		svr.Close()
+	}
+
+	if db != nil {
+		db.Close()
+	}
+})
+
+func mustGetenv(key string) string {
+	v := os.Getenv(key)
+	if v == "" {
+		Fail(key + " must be set to run the e2e tests, see hack/e2e.sh")
+	}
+
+	return v
+}
+
+// operate posts the operations to /kubernetes/ops and returns the response status code.
+func operate(ops kube.Operations) int {
+	b, err := json.Marshal(ops)
+	Expect(err).To(BeNil())
+
+	req, err := http.NewRequest(http.MethodPost, svr.URL+"/kubernetes/ops", bytes.NewBuffer(b))
+	Expect(err).To(BeNil())
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("X-Spinnaker-Application", application)
+
+	res, err := http.DefaultClient.Do(req)
+	Expect(err).To(BeNil())
+	defer res.Body.Close()
+
+	return res.StatusCode
+}
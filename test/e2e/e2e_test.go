@@ -0,0 +1,170 @@
+//go:build e2e
+// +build e2e
+
+package e2e_test
+
+import (
+	"net/http"
+	"time"
+
+	kube "github.com/billiford/go-clouddriver/pkg/http/core/kubernetes"
+	"github.com/billiford/go-clouddriver/pkg/kubernetes"
+	"k8s.io/apimachinery/pkg/api/errors"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+const (
+	deploymentName = "clouddriver-e2e"
+	timeout        = 2 * time.Minute
+	interval       = time.Second
+)
+
+// The specs build on each other, so they run in order.
+var _ = Describe("Deployments", func() {
+	It("deploys a manifest", func() {
+		status := operate(kube.Operations{
+			{
+				DeployManifest: deployManifest("nginx:1.18"),
+			},
+		})
+		Expect(status).To(Equal(http.StatusOK))
+
+		Eventually(readyReplicas, timeout, interval).Should(Equal(int64(1)))
+	})
+
+	It("deploys a new revision", func() {
+		status := operate(kube.Operations{
+			{
+				DeployManifest: deployManifest("nginx:1.19"),
+			},
+		})
+		Expect(status).To(Equal(http.StatusOK))
+
+		Eventually(image, timeout, interval).Should(Equal("nginx:1.19"))
+		Eventually(readyReplicas, timeout, interval).Should(Equal(int64(1)))
+	})
+
+	It("scales the deployment", func() {
+		status := operate(kube.Operations{
+			{
+				ScaleManifest: &kube.ScaleManifestRequest{
+					Account:      account,
+					ManifestName: "deployment " + deploymentName,
+					Location:     namespace,
+					Replicas:     "2",
+				},
+			},
+		})
+		Expect(status).To(Equal(http.StatusOK))
+
+		Eventually(readyReplicas, timeout, interval).Should(Equal(int64(2)))
+	})
+
+	It("rolls back to the first revision", func() {
+		status := operate(kube.Operations{
+			{
+				UndoRolloutManifest: &kube.UndoRolloutManifestRequest{
+					Account:      account,
+					ManifestName: "deployment " + deploymentName,
+					Location:     namespace,
+					Revision:     "1",
+				},
+			},
+		})
+		Expect(status).To(Equal(http.StatusOK))
+
+		Eventually(image, timeout, interval).Should(Equal("nginx:1.18"))
+	})
+
+	It("deletes the deployment", func() {
+		status := operate(kube.Operations{
+			{
+				DeleteManifest: &kube.DeleteManifestRequest{
+					Account:      account,
+					ManifestName: "deployment " + deploymentName,
+					Location:     namespace,
+					Options: kube.DeleteManifestRequestOptions{
+						Cascading: true,
+					},
+				},
+			},
+		})
+		Expect(status).To(Equal(http.StatusOK))
+
+		Eventually(func() bool {
+			_, err := kubeClient.Get("deployment", deploymentName, namespace)
+			return errors.IsNotFound(err)
+		}, timeout, interval).Should(BeTrue())
+	})
+})
+
+func deployManifest(image string) *kube.DeployManifestRequest {
+	dm := &kube.DeployManifestRequest{
+		Account:           account,
+		NamespaceOverride: namespace,
+		Manifests:         []map[string]interface{}{deployment(image)},
+	}
+	dm.Moniker.App = application
+
+	return dm
+}
+
+func deployment(image string) map[string]interface{} {
+	return map[string]interface{}{
+		"apiVersion": "apps/v1",
+		"kind":       "Deployment",
+		"metadata": map[string]interface{}{
+			"name": deploymentName,
+		},
+		"spec": map[string]interface{}{
+			"replicas": int64(1),
+			"selector": map[string]interface{}{
+				"matchLabels": map[string]interface{}{
+					"app": deploymentName,
+				},
+			},
+			"template": map[string]interface{}{
+				"metadata": map[string]interface{}{
+					"labels": map[string]interface{}{
+						"app": deploymentName,
+					},
+				},
+				"spec": map[string]interface{}{
+					"containers": []interface{}{
+						map[string]interface{}{
+							"name":  "nginx",
+							"image": image,
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+func readyReplicas() int64 {
+	u, err := kubeClient.Get("deployment", deploymentName, namespace)
+	if err != nil {
+		return 0
+	}
+
+	d := kubernetes.NewDeployment(u.Object).Object()
+
+	return int64(d.Status.ReadyReplicas)
+}
+
+func image() string {
+	u, err := kubeClient.Get("deployment", deploymentName, namespace)
+	if err != nil {
+		return ""
+	}
+
+	d := kubernetes.NewDeployment(u.Object).Object()
+	if len(d.Spec.Template.Spec.Containers) == 0 {
+		return ""
+	}
+
+	return d.Spec.Template.Spec.Containers[0].Image
+}